@@ -0,0 +1,128 @@
+// Command loadtest drives a mix of REST and WebSocket traffic against a
+// PolyGo instance and reports throughput and latency percentiles.
+//
+//	go run ./cmd/loadtest -mock -duration 30s
+//	go run ./cmd/loadtest -url http://localhost:8080 -mix book=5,price=5,markets=1 -ws 200
+//
+// With -mock it starts the fake upstreams of internal/mockupstream and a
+// PolyGo server in process, so runs are reproducible offline. The load
+// generator, the server and the mocks then share the machine, which the
+// numbers include.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/polygo/internal/mockupstream"
+)
+
+// options are the command line flags
+type options struct {
+	url         string
+	mock        bool
+	duration    time.Duration
+	concurrency int
+	rate        int
+	mix         string
+	ws          int
+	push        int
+	market      string
+	token       string
+	json        bool
+}
+
+func main() {
+	o := &options{}
+	flag.StringVar(&o.url, "url", envOr("POLYGO_URL", "http://localhost:8080"), "PolyGo base URL (env POLYGO_URL); ignored with -mock")
+	flag.BoolVar(&o.mock, "mock", false, "run PolyGo in process against the mock upstream")
+	flag.DurationVar(&o.duration, "duration", 30*time.Second, "how long to send traffic")
+	flag.IntVar(&o.concurrency, "concurrency", 32, "REST workers sending requests back to back")
+	flag.IntVar(&o.rate, "rate", 0, "total REST requests per second across workers (0 = as fast as they go)")
+	flag.StringVar(&o.mix, "mix", defaultMix, "weighted REST scenarios, name=weight,...")
+	flag.IntVar(&o.ws, "ws", 0, "WebSocket clients subscribed to -market")
+	flag.IntVar(&o.push, "push", 100, "upstream WebSocket messages per second with -mock")
+	flag.StringVar(&o.market, "market", mockupstream.MarketID, "market ID the scenarios and WebSocket clients use")
+	flag.StringVar(&o.token, "token", mockupstream.TokenYes, "token ID the scenarios use")
+	flag.BoolVar(&o.json, "json", false, "print the report as JSON")
+	flag.Parse()
+
+	if err := run(o); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(o *options) error {
+	mix, err := parseMix(o.mix, scenarios(o.market, o.token))
+	if err != nil {
+		return err
+	}
+	if o.concurrency < 1 && o.ws < 1 {
+		return fmt.Errorf("nothing to do: -concurrency and -ws are both 0")
+	}
+
+	var mock *mockupstream.Server
+	if o.mock {
+		srv, err := startMocked()
+		if err != nil {
+			return err
+		}
+		defer srv.stop()
+		o.url, mock = srv.url, srv.mock
+	}
+
+	// Interrupts end the run early; the report covers what was sent
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Clients connect before the clock starts, so the run measures fan-out
+	// rather than connection setup
+	subs, err := dialWS(ctx, o.url, o.market, o.ws)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.duration)
+	defer cancel()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	if mock != nil && len(subs) > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pushUpstream(ctx, mock, o.market, o.push)
+		}()
+	}
+	for _, sub := range subs {
+		wg.Add(1)
+		go func(sub *subscriber) {
+			defer wg.Done()
+			sub.read(ctx)
+		}(sub)
+	}
+	rest := runREST(ctx, o.url, mix, o.concurrency, o.rate)
+	wg.Wait()
+
+	rep := newReport(o, time.Since(start), rest, subs)
+	if o.json {
+		return rep.writeJSON(os.Stdout)
+	}
+	rep.writeTable(os.Stdout)
+	return nil
+}
+
+// envOr returns the environment variable key, or def when unset
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/polygo/internal/api"
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/mockupstream"
+)
+
+// mocked is a PolyGo server running in process against the mock upstream
+type mocked struct {
+	url    string
+	mock   *mockupstream.Server
+	server *api.Server
+	dir    string
+}
+
+// startMocked starts the mock upstream and a PolyGo server with the
+// default config pointed at it, and waits for the server to answer
+func startMocked() (*mocked, error) {
+	// The server's stores write under ./data; keep them out of the checkout
+	dir, err := os.MkdirTemp("", "polygo-loadtest-")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chdir(dir); err != nil {
+		return nil, err
+	}
+
+	port, err := freePort()
+	if err != nil {
+		return nil, err
+	}
+
+	mock := mockupstream.New()
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	cfg.Server.Host = "127.0.0.1"
+	cfg.Server.Port = port
+	cfg.Server.Listen = ""
+	cfg.Cache.PersistPath = ""
+	// The mock pushes every message to every upstream connection; with one
+	// shard and no live data connection it arrives once, as it would from
+	// the real upstream
+	cfg.Polymarket.WsShards = 1
+	cfg.Polymarket.WsLiveDataURL = ""
+	// All traffic comes from one IP; a route limit over every path replaces
+	// the per-IP limit, which would otherwise answer most requests with 429
+	cfg.RateLimit.Routes = []config.RouteRateLimit{{Prefix: "/", Limit: math.MaxInt32}}
+
+	c, err := cache.New(&cfg.Cache)
+	if err != nil {
+		mock.Close()
+		return nil, err
+	}
+	server, err := api.NewServer(cfg, c)
+	if err != nil {
+		mock.Close()
+		return nil, err
+	}
+
+	// Request logs would drown the report and cost the server more than
+	// the requests themselves
+	log.SetOutput(io.Discard)
+	go server.Start()
+
+	m := &mocked{
+		url:    "http://127.0.0.1:" + strconv.Itoa(port),
+		mock:   mock,
+		server: server,
+		dir:    dir,
+	}
+	if err := waitHealthy(m.url, 10*time.Second); err != nil {
+		m.stop()
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *mocked) stop() {
+	m.server.Shutdown()
+	m.mock.Close()
+	log.SetOutput(os.Stderr)
+	os.RemoveAll(m.dir)
+}
+
+// freePort returns a TCP port nothing listens on at the moment
+func freePort() (int, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer ln.Close()
+	return ln.Addr().(*net.TCPAddr).Port, nil
+}
+
+// waitHealthy polls /health until it answers 200
+func waitHealthy(url string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		status, _, err := fasthttp.GetTimeout(nil, url+"/health", time.Second)
+		if err == nil && status == fasthttp.StatusOK {
+			return nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return fmt.Errorf("server at %s not healthy after %s", url, timeout)
+}
+
+// pushUpstream sends rate price changes per second for market through the
+// mock's WebSocket until ctx is done. Each carries the time it was sent, so
+// subscribers can measure fan-out latency.
+func pushUpstream(ctx context.Context, mock *mockupstream.Server, market string, rate int) {
+	if rate < 1 {
+		return
+	}
+	ticker := time.NewTicker(time.Second / time.Duration(rate))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			mock.Push([]byte(`{"type":"price_change","markets":["` + market + `"],"sent_at":` + strconv.FormatInt(time.Now().UnixNano(), 10) + `}`))
+		}
+	}
+}
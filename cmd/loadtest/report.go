@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// report is the outcome of a run. Latencies are in milliseconds.
+type report struct {
+	Target      string           `json:"target"`
+	Mock        bool             `json:"mock"`
+	Seconds     float64          `json:"seconds"`
+	Concurrency int              `json:"concurrency"`
+	Rate        int              `json:"rate,omitempty"`
+	Scenarios   []scenarioReport `json:"scenarios"`
+	Total       scenarioReport   `json:"total"`
+	WS          *wsReport        `json:"ws,omitempty"`
+}
+
+type scenarioReport struct {
+	Name      string      `json:"name"`
+	Requests  int         `json:"requests"`
+	PerSecond float64     `json:"per_second"`
+	Errors    int         `json:"errors"`  // requests without a response
+	Non2xx    int         `json:"non_2xx"` // responses with another status
+	Statuses  map[int]int `json:"statuses"`
+	Latency   percentiles `json:"latency_ms"`
+}
+
+type wsReport struct {
+	Clients   int          `json:"clients"`
+	Dropped   int          `json:"dropped"`
+	Messages  int          `json:"messages"`
+	PerSecond float64      `json:"per_second"`
+	Connect   percentiles  `json:"connect_ms"`
+	Fanout    *percentiles `json:"fanout_ms,omitempty"` // only with -mock, which stamps the pushes
+}
+
+type percentiles struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+// summarize sorts latencies and returns their percentiles
+func summarize(latencies []time.Duration) percentiles {
+	if len(latencies) == 0 {
+		return percentiles{}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	at := func(q float64) float64 {
+		i := int(q*float64(len(latencies))+0.5) - 1
+		if i < 0 {
+			i = 0
+		}
+		return ms(latencies[i])
+	}
+	return percentiles{P50: at(0.5), P90: at(0.9), P99: at(0.99), Max: ms(latencies[len(latencies)-1])}
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func newReport(o *options, elapsed time.Duration, rest map[string]*results, subs []*subscriber) *report {
+	secs := elapsed.Seconds()
+	rep := &report{Target: o.url, Mock: o.mock, Seconds: secs, Concurrency: o.concurrency, Rate: o.rate}
+
+	total := newResults()
+	for _, name := range sortedNames(rest) {
+		r := rest[name]
+		rep.Scenarios = append(rep.Scenarios, scenarioSummary(name, r, secs))
+		total.merge(r)
+	}
+	rep.Total = scenarioSummary("total", total, secs)
+
+	if len(subs) > 0 {
+		ws := &wsReport{Clients: len(subs)}
+		var connects, fanout []time.Duration
+		for _, s := range subs {
+			ws.Messages += s.messages
+			if s.dropped {
+				ws.Dropped++
+			}
+			connects = append(connects, s.connect)
+			fanout = append(fanout, s.fanout...)
+		}
+		ws.PerSecond = float64(ws.Messages) / secs
+		ws.Connect = summarize(connects)
+		if len(fanout) > 0 {
+			p := summarize(fanout)
+			ws.Fanout = &p
+		}
+		rep.WS = ws
+	}
+	return rep
+}
+
+func scenarioSummary(name string, r *results, secs float64) scenarioReport {
+	s := scenarioReport{
+		Name:      name,
+		Requests:  len(r.latencies) + r.errors,
+		PerSecond: float64(len(r.latencies)+r.errors) / secs,
+		Errors:    r.errors,
+		Statuses:  r.statuses,
+		Latency:   summarize(r.latencies),
+	}
+	for status, n := range r.statuses {
+		if status < 200 || status > 299 {
+			s.Non2xx += n
+		}
+	}
+	return s
+}
+
+func sortedNames(m map[string]*results) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *report) writeJSON(out io.Writer) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+func (r *report) writeTable(out io.Writer) {
+	target := r.Target
+	if r.Mock {
+		target += " (mock upstream)"
+	}
+	fmt.Fprintf(out, "%s for %.1fs, %d workers\n\n", target, r.Seconds, r.Concurrency)
+
+	if len(r.Scenarios) > 0 {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(w, "SCENARIO\tREQUESTS\tREQ/S\tERRORS\tNON-2XX\tP50 MS\tP90 MS\tP99 MS\tMAX MS\t")
+		for _, s := range append(r.Scenarios, r.Total) {
+			fmt.Fprintf(w, "%s\t%d\t%.0f\t%d\t%d\t%.2f\t%.2f\t%.2f\t%.2f\t\n",
+				s.Name, s.Requests, s.PerSecond, s.Errors, s.Non2xx,
+				s.Latency.P50, s.Latency.P90, s.Latency.P99, s.Latency.Max)
+		}
+		w.Flush()
+	}
+
+	if r.WS != nil {
+		fmt.Fprintf(out, "\nWebSocket: %d clients, %d dropped, %d messages (%.0f/s)\n",
+			r.WS.Clients, r.WS.Dropped, r.WS.Messages, r.WS.PerSecond)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(w, "\tP50 MS\tP90 MS\tP99 MS\tMAX MS\t")
+		fmt.Fprintf(w, "connect\t%.2f\t%.2f\t%.2f\t%.2f\t\n", r.WS.Connect.P50, r.WS.Connect.P90, r.WS.Connect.P99, r.WS.Connect.Max)
+		if f := r.WS.Fanout; f != nil {
+			fmt.Fprintf(w, "fan-out\t%.2f\t%.2f\t%.2f\t%.2f\t\n", f.P50, f.P90, f.P99, f.Max)
+		}
+		w.Flush()
+	}
+}
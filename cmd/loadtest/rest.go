@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// defaultMix leans on the hot read paths: books and prices, then markets
+const defaultMix = "book=4,price=4,midpoint=2,market=2,markets=1,events=1"
+
+// scenarios maps scenario names to the paths they request
+func scenarios(market, token string) map[string]string {
+	return map[string]string{
+		"health":   "/health",
+		"markets":  "/api/v1/markets?limit=20",
+		"market":   "/api/v1/markets/" + market,
+		"events":   "/api/v1/events?limit=20",
+		"book":     "/api/v1/book/" + token,
+		"price":    "/api/v1/price/" + token + "?side=BUY",
+		"midpoint": "/api/v1/midpoint/" + token,
+		"spread":   "/api/v1/spread/" + token,
+		"history":  "/api/v1/price-history/" + token,
+	}
+}
+
+// weighted is a scenario and its share of the requests
+type weighted struct {
+	name   string
+	path   string
+	weight int
+}
+
+// parseMix reads "name=weight,..." into weighted scenarios, in the order given
+func parseMix(spec string, known map[string]string) ([]weighted, error) {
+	var mix []weighted
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, w, ok := strings.Cut(part, "=")
+		if !ok {
+			w = "1"
+		}
+		path, found := known[name]
+		if !found {
+			return nil, fmt.Errorf("unknown scenario %q in -mix (have %s)", name, strings.Join(sortedKeys(known), ", "))
+		}
+		weight, err := strconv.Atoi(w)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("bad weight %q for %s in -mix", w, name)
+		}
+		if weight > 0 {
+			mix = append(mix, weighted{name: name, path: path, weight: weight})
+		}
+	}
+	if len(mix) == 0 {
+		return nil, fmt.Errorf("-mix has no scenario with a weight above 0")
+	}
+	return mix, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// pick returns a scenario with probability proportional to its weight
+func pick(mix []weighted, rng *rand.Rand) *weighted {
+	total := 0
+	for _, s := range mix {
+		total += s.weight
+	}
+	n := rng.Intn(total)
+	for i := range mix {
+		if n < mix[i].weight {
+			return &mix[i]
+		}
+		n -= mix[i].weight
+	}
+	return &mix[len(mix)-1]
+}
+
+// results are the outcomes of one scenario's requests
+type results struct {
+	latencies []time.Duration
+	statuses  map[int]int
+	errors    int
+}
+
+func (r *results) merge(o *results) {
+	r.latencies = append(r.latencies, o.latencies...)
+	for status, n := range o.statuses {
+		r.statuses[status] += n
+	}
+	r.errors += o.errors
+}
+
+func newResults() *results {
+	return &results{statuses: make(map[int]int)}
+}
+
+// runREST sends requests from workers until ctx is done and returns the
+// results per scenario. With rate above 0 the workers share that many
+// requests per second; otherwise each sends its next request as soon as
+// the last one is answered.
+func runREST(ctx context.Context, baseURL string, mix []weighted, workers, rate int) map[string]*results {
+	merged := make(map[string]*results)
+	if workers < 1 {
+		return merged
+	}
+
+	client := &fasthttp.Client{
+		MaxConnsPerHost: workers,
+		ReadTimeout:     30 * time.Second,
+		WriteTimeout:    10 * time.Second,
+	}
+	defer client.CloseIdleConnections()
+
+	var tick <-chan time.Time
+	if rate > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			own := work(ctx, client, baseURL, mix, tick, rand.New(rand.NewSource(seed)))
+
+			mu.Lock()
+			defer mu.Unlock()
+			for name, r := range own {
+				if merged[name] == nil {
+					merged[name] = newResults()
+				}
+				merged[name].merge(r)
+			}
+		}(time.Now().UnixNano() + int64(i))
+	}
+	wg.Wait()
+	return merged
+}
+
+// work is one worker's request loop
+func work(ctx context.Context, client *fasthttp.Client, baseURL string, mix []weighted, tick <-chan time.Time, rng *rand.Rand) map[string]*results {
+	own := make(map[string]*results)
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	for {
+		if tick != nil {
+			select {
+			case <-ctx.Done():
+				return own
+			case <-tick:
+			}
+		} else if ctx.Err() != nil {
+			return own
+		}
+
+		s := pick(mix, rng)
+		r := own[s.name]
+		if r == nil {
+			r = newResults()
+			own[s.name] = r
+		}
+
+		req.SetRequestURI(baseURL + s.path)
+		start := time.Now()
+		err := client.Do(req, resp)
+		elapsed := time.Since(start)
+		if err != nil {
+			r.errors++
+			continue
+		}
+		r.latencies = append(r.latencies, elapsed)
+		r.statuses[resp.StatusCode()]++
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// subscriber is a WebSocket client of /ws/market/{id}
+type subscriber struct {
+	conn    *websocket.Conn
+	connect time.Duration
+
+	messages int
+	fanout   []time.Duration // push to receipt, for messages carrying sent_at
+	dropped  bool            // the server closed the connection before the end
+}
+
+// dialWS connects n clients to the market stream, failing on the first
+// client that cannot connect
+func dialWS(ctx context.Context, baseURL, market string, n int) ([]*subscriber, error) {
+	if n < 1 {
+		return nil, nil
+	}
+	wsURL := "ws" + strings.TrimPrefix(baseURL, "http") + "/ws/market/" + url.PathEscape(market)
+
+	subs := make([]*subscriber, 0, n)
+	for i := 0; i < n; i++ {
+		start := time.Now()
+		conn, resp, err := websocket.DefaultDialer.DialContext(ctx, wsURL, nil)
+		if err != nil {
+			for _, s := range subs {
+				s.conn.Close()
+			}
+			if resp != nil {
+				err = fmt.Errorf("%w (%s)", err, resp.Status)
+			}
+			return nil, fmt.Errorf("websocket client %d of %d: %w", i+1, n, err)
+		}
+		subs = append(subs, &subscriber{conn: conn, connect: time.Since(start)})
+	}
+	return subs, nil
+}
+
+// read counts messages until ctx is done or the server hangs up
+func (s *subscriber) read(ctx context.Context) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		s.conn.Close()
+	}()
+
+	var msg struct {
+		SentAt int64 `json:"sent_at"`
+	}
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			s.dropped = ctx.Err() == nil
+			return
+		}
+		received := time.Now()
+		s.messages++
+
+		msg.SentAt = 0
+		if json.Unmarshal(data, &msg) == nil && msg.SentAt > 0 {
+			s.fanout = append(s.fanout, received.Sub(time.Unix(0, msg.SentAt)))
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newCacheCmd(g *globals) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the response cache (requires the admin token)",
+	}
+	cmd.AddCommand(newCachePurgeCmd(g))
+	return cmd
+}
+
+func newCachePurgeCmd(g *globals) *cobra.Command {
+	var key string
+
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Drop one cache entry, or everything when --key is not given",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := withTimeout(cmd, g)
+			defer cancel()
+
+			if err := g.client().PurgeCache(ctx, key); err != nil {
+				return err
+			}
+			if key == "" {
+				key = "all entries"
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "purged %s\n", key)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&key, "key", "", "Cache key to drop, e.g. markets:<id>")
+	return cmd
+}
@@ -0,0 +1,126 @@
+// Command polygoctl is a terminal client for a running PolyGo instance.
+//
+//	polygoctl markets list --active
+//	polygoctl price <token>
+//	polygoctl book <token> --watch
+//	polygoctl order create --token <token> --side BUY --price 0.52 --size 10
+//	polygoctl cache purge
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/polygo/pkg/polygoclient"
+	"github.com/spf13/cobra"
+)
+
+// globals holds the persistent flags shared by every command
+type globals struct {
+	url         string
+	apiKey      string
+	apiSecret   string
+	passphrase  string
+	adminToken  string
+	accessToken string
+	timeout     time.Duration
+	json        bool
+}
+
+func main() {
+	// Interrupts end --watch loops and in-flight requests cleanly
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := newRootCmd().ExecuteContext(ctx); err != nil {
+		stop()
+		os.Exit(1)
+	}
+}
+
+func newRootCmd() *cobra.Command {
+	g := &globals{}
+
+	root := &cobra.Command{
+		Use:          "polygoctl",
+		Short:        "Command line client for PolyGo",
+		SilenceUsage: true,
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&g.url, "url", envOr("POLYGO_URL", "http://localhost:8080"), "PolyGo base URL (env POLYGO_URL)")
+	flags.StringVar(&g.apiKey, "api-key", os.Getenv("POLYGO_API_KEY"), "CLOB API key (env POLYGO_API_KEY)")
+	flags.StringVar(&g.apiSecret, "api-secret", os.Getenv("POLYGO_API_SECRET"), "CLOB API secret (env POLYGO_API_SECRET)")
+	flags.StringVar(&g.passphrase, "passphrase", os.Getenv("POLYGO_API_PASSPHRASE"), "CLOB API passphrase (env POLYGO_API_PASSPHRASE)")
+	flags.StringVar(&g.adminToken, "admin-token", os.Getenv("POLYGO_ADMIN_TOKEN"), "Admin token for /admin endpoints (env POLYGO_ADMIN_TOKEN)")
+	flags.StringVar(&g.accessToken, "access-token", os.Getenv("POLYGO_ACCESS_TOKEN"), "Access key or JWT when the server enforces roles (env POLYGO_ACCESS_TOKEN)")
+	flags.DurationVar(&g.timeout, "timeout", 15*time.Second, "Request timeout")
+	flags.BoolVar(&g.json, "json", false, "Print JSON instead of tables")
+
+	root.AddCommand(
+		newMarketsCmd(g),
+		newPriceCmd(g),
+		newBookCmd(g),
+		newOrderCmd(g),
+		newCacheCmd(g),
+	)
+	return root
+}
+
+// client builds an SDK client from the global flags
+func (g *globals) client() *polygoclient.Client {
+	opts := []polygoclient.Option{polygoclient.WithUserAgent("polygoctl/1.0")}
+	if g.apiKey != "" {
+		opts = append(opts, polygoclient.WithCredentials(polygoclient.Credentials{
+			APIKey:     g.apiKey,
+			Secret:     g.apiSecret,
+			Passphrase: g.passphrase,
+		}))
+	}
+	if g.adminToken != "" {
+		opts = append(opts, polygoclient.WithAdminToken(g.adminToken))
+	}
+	if g.accessToken != "" {
+		opts = append(opts, polygoclient.WithAccessToken(g.accessToken))
+	}
+	return polygoclient.New(g.url, opts...)
+}
+
+// printJSON writes v as indented JSON
+func printJSON(cmd *cobra.Command, v interface{}) error {
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// envOr returns the environment variable key, or def when unset
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// truncate shortens s to n runes for table output
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// emptyNote is printed instead of an empty table
+func emptyNote(cmd *cobra.Command, what string) {
+	fmt.Fprintf(cmd.OutOrStdout(), "no %s\n", what)
+}
+
+// withTimeout returns the command context bounded by --timeout
+func withTimeout(cmd *cobra.Command, g *globals) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(cmd.Context(), g.timeout)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/polygo/pkg/polygoclient"
+	"github.com/spf13/cobra"
+)
+
+func newMarketsCmd(g *globals) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "markets",
+		Short: "Browse markets",
+	}
+	cmd.AddCommand(newMarketsListCmd(g))
+	return cmd
+}
+
+func newMarketsListCmd(g *globals) *cobra.Command {
+	var (
+		limit  int
+		offset int
+		active bool
+		closed bool
+		slug   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List markets",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := withTimeout(cmd, g)
+			defer cancel()
+
+			q := &polygoclient.MarketsQuery{Limit: limit, Offset: offset, Slug: slug}
+			if cmd.Flags().Changed("active") {
+				q.Active = &active
+			}
+			if cmd.Flags().Changed("closed") {
+				q.Closed = &closed
+			}
+
+			markets, err := g.client().GetMarkets(ctx, q)
+			if err != nil {
+				return err
+			}
+			if g.json {
+				return printJSON(cmd, markets)
+			}
+			if len(markets) == 0 {
+				emptyNote(cmd, "markets")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tQUESTION\tVOLUME 24H\tBID\tASK\tSTATUS")
+			for _, m := range markets {
+				status := "open"
+				if m.Closed {
+					status = "closed"
+				} else if !m.Active {
+					status = "inactive"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%.0f\t%s\t%s\t%s\n",
+					m.ID, truncate(m.Question, 60), m.Volume24hr.Float(), m.BestBid, m.BestAsk, status)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 20, "Maximum markets to list")
+	cmd.Flags().IntVar(&offset, "offset", 0, "Pagination offset")
+	cmd.Flags().BoolVar(&active, "active", false, "Only active (or, with =false, inactive) markets")
+	cmd.Flags().BoolVar(&closed, "closed", false, "Only closed (or, with =false, open) markets")
+	cmd.Flags().StringVar(&slug, "slug", "", "Filter by market slug")
+	return cmd
+}
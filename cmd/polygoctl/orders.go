@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/polygo/pkg/polygoclient"
+	"github.com/spf13/cobra"
+)
+
+func newOrderCmd(g *globals) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "order",
+		Short: "Place and cancel orders (requires API credentials)",
+	}
+	cmd.AddCommand(newOrderCreateCmd(g), newOrderCancelCmd(g))
+	return cmd
+}
+
+func newOrderCreateCmd(g *globals) *cobra.Command {
+	var (
+		req  polygoclient.CreateOrderRequest
+		side string
+		typ  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Place an order",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req.Side = polygoclient.Side(strings.ToUpper(side))
+			if req.Side != polygoclient.Buy && req.Side != polygoclient.Sell {
+				return fmt.Errorf("--side must be BUY or SELL")
+			}
+			req.Type = polygoclient.OrderType(strings.ToUpper(typ))
+
+			ctx, cancel := withTimeout(cmd, g)
+			defer cancel()
+
+			resp, err := g.client().PlaceOrder(ctx, &req)
+			if err != nil {
+				return err
+			}
+			if g.json {
+				return printJSON(cmd, resp)
+			}
+			if !resp.Success && resp.ErrorMsg != "" {
+				return fmt.Errorf("order rejected: %s", resp.ErrorMsg)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", resp.OrderID, resp.Status)
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&req.TokenID, "token", "", "Token ID")
+	f.StringVar(&side, "side", "", "BUY or SELL")
+	f.StringVar(&req.Price, "price", "", "Limit price")
+	f.StringVar(&req.Size, "size", "", "Size in shares")
+	f.StringVar(&typ, "type", string(polygoclient.GTC), "GTC, FOK or GTD")
+	f.Int64Var(&req.Expiration, "expiration", 0, "Unix expiry for GTD orders")
+	f.StringVar(&req.Maker, "maker", "", "Maker wallet address")
+	for _, name := range []string{"token", "side", "price", "size"} {
+		cmd.MarkFlagRequired(name)
+	}
+	return cmd
+}
+
+func newOrderCancelCmd(g *globals) *cobra.Command {
+	return &cobra.Command{
+		Use:   "cancel <order-id>",
+		Short: "Cancel an order",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := withTimeout(cmd, g)
+			defer cancel()
+
+			resp, err := g.client().CancelOrder(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if g.json {
+				return printJSON(cmd, resp)
+			}
+			if reason, ok := resp.NotCanceled[args[0]]; ok {
+				return fmt.Errorf("not cancelled: %s", reason)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "cancelled %s\n", args[0])
+			return nil
+		},
+	}
+}
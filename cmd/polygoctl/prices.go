@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/polygo/pkg/polygoclient"
+	"github.com/spf13/cobra"
+)
+
+func newPriceCmd(g *globals) *cobra.Command {
+	return &cobra.Command{
+		Use:   "price <token>",
+		Short: "Show the midpoint price of a token",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := withTimeout(cmd, g)
+			defer cancel()
+
+			mid, err := g.client().GetMidpoint(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if g.json {
+				return printJSON(cmd, map[string]interface{}{"token_id": args[0], "mid": mid})
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%g\n", mid)
+			return nil
+		},
+	}
+}
+
+func newBookCmd(g *globals) *cobra.Command {
+	var (
+		depth    int
+		watch    bool
+		interval time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "book <token>",
+		Short: "Show the order book of a token",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := g.client()
+
+			for {
+				ctx, cancel := withTimeout(cmd, g)
+				book, err := client.GetOrderBook(ctx, args[0])
+				cancel()
+				if err != nil {
+					return err
+				}
+
+				if g.json {
+					if err := printJSON(cmd, book); err != nil {
+						return err
+					}
+				} else {
+					if watch {
+						// Clear the screen between refreshes
+						fmt.Fprint(cmd.OutOrStdout(), "\033[H\033[2J")
+					}
+					if err := printBook(cmd.OutOrStdout(), book, depth); err != nil {
+						return err
+					}
+				}
+
+				if !watch {
+					return nil
+				}
+				select {
+				case <-cmd.Context().Done():
+					return nil
+				case <-time.After(interval):
+				}
+			}
+		},
+	}
+
+	cmd.Flags().IntVar(&depth, "depth", 10, "Price levels per side")
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Refresh until interrupted")
+	cmd.Flags().DurationVar(&interval, "interval", time.Second, "Refresh interval with --watch")
+	return cmd
+}
+
+// printBook renders bids and asks side by side, best prices first
+func printBook(out io.Writer, book *polygoclient.OrderBook, depth int) error {
+	bids := bestFirst(book.Bids, depth, true)
+	asks := bestFirst(book.Asks, depth, false)
+
+	fmt.Fprintf(out, "%s  %s\n\n", book.TokenID, time.Now().Format("15:04:05"))
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "BID SIZE\tBID\tASK\tASK SIZE\t")
+	for i := 0; i < len(bids) || i < len(asks); i++ {
+		var bid, ask polygoclient.PriceLevel
+		if i < len(bids) {
+			bid = bids[i]
+		}
+		if i < len(asks) {
+			ask = asks[i]
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", bid.Size, bid.Price, ask.Price, ask.Size)
+	}
+	return w.Flush()
+}
+
+// bestFirst sorts levels best price first (highest for bids, lowest for
+// asks) and keeps at most depth of them
+func bestFirst(levels []polygoclient.PriceLevel, depth int, bids bool) []polygoclient.PriceLevel {
+	out := append([]polygoclient.PriceLevel(nil), levels...)
+	sort.SliceStable(out, func(i, j int) bool {
+		pi, _ := strconv.ParseFloat(out[i].Price, 64)
+		pj, _ := strconv.ParseFloat(out[j].Price, 64)
+		if bids {
+			return pi > pj
+		}
+		return pi < pj
+	})
+	if depth > 0 && len(out) > depth {
+		out = out[:depth]
+	}
+	return out
+}
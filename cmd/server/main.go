@@ -9,13 +9,29 @@
 // @license.name MIT
 // @license.url https://opensource.org/licenses/MIT
 
-// @host localhost:8080
 // @BasePath /
 
 // @securityDefinitions.apikey ApiKeyAuth
 // @in header
 // @name POLY-API-KEY
 
+// @securityDefinitions.apikey AdminAuth
+// @in header
+// @name Authorization
+
+// @tag.name Health
+// @tag.description Health, stats and metrics endpoints
+// @tag.name Markets
+// @tag.description Market operations
+// @tag.name Events
+// @tag.description Event operations
+// @tag.name Prices
+// @tag.description Price and order book operations
+// @tag.name Orders
+// @tag.description Order management (authenticated)
+// @tag.name User Data
+// @tag.description User data operations
+
 package main
 
 import (
@@ -29,6 +45,7 @@ import (
 	"github.com/polygo/internal/cache"
 	"github.com/polygo/internal/config"
 	_ "github.com/polygo/internal/docs"
+	"github.com/polygo/internal/secrets"
 )
 
 func main() {
@@ -38,12 +55,31 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Replace vault:// and aws-sm:// settings with the secrets they name
+	refs, err := secrets.Resolve(cfg)
+	if err != nil {
+		log.Fatalf("Failed to resolve secret: %v", err)
+	}
+	if len(refs) > 0 {
+		log.Printf("Resolved %d settings from secret stores", len(refs))
+	}
+
+	// Surface misconfiguration before anything starts
+	log.Print(cfg.Banner())
+
 	// Create cache
 	c, err := cache.New(&cfg.Cache)
 	if err != nil {
 		log.Fatalf("Failed to create cache: %v", err)
 	}
 
+	// Warm the cache from the snapshot saved at the last shutdown
+	if n, err := c.Load(); err != nil {
+		log.Printf("Failed to load cache snapshot: %v", err)
+	} else if n > 0 {
+		log.Printf("Loaded %d cache entries from %s", n, cfg.Cache.PersistPath)
+	}
+
 	// Create and start server
 	server, err := api.NewServer(cfg, c)
 	if err != nil {
@@ -54,20 +90,47 @@ func main() {
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
 
+	// Settings are read once, so a rotated secret takes a restart: drain and
+	// exit non-zero for the supervisor to start us again with the new one
+	rotated := false
+	watcher := secrets.Watch(&cfg.Secrets, refs, func(keys []string) {
+		log.Printf("Secrets rotated for %v, restarting", keys)
+		rotated = true
+		select {
+		case shutdown <- syscall.SIGTERM:
+		default: // already shutting down
+		}
+	})
+
+	done := make(chan struct{})
 	go func() {
 		<-shutdown
-		log.Println("Shutting down server...")
+		watcher.Stop()
+		log.Println("Shutting down server, draining connections...")
 		if err := server.Shutdown(); err != nil {
 			log.Printf("Error during shutdown: %v", err)
 		}
+		close(done)
 	}()
 
 	// Start server
-	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
-	log.Printf("🚀 PolyGo server starting on %s", addr)
-	log.Printf("📚 Swagger UI: http://%s/swagger/index.html", addr)
+	if cfg.Server.Listen != "" {
+		log.Printf("🚀 PolyGo server starting on %s", cfg.Server.Listen)
+	} else {
+		addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
+		log.Printf("🚀 PolyGo server starting on %s", addr)
+		log.Printf("📚 Swagger UI: http://%s/swagger/index.html", addr)
+		log.Printf("📄 OpenAPI 3: http://%s/openapi.json", addr)
+	}
 	
 	if err := server.Start(); err != nil {
 		log.Fatalf("Server error: %v", err)
 	}
+	
+	// Listen returns as soon as the listener closes; wait for the drain to finish
+	<-done
+	log.Println("Server stopped")
+	if rotated {
+		os.Exit(1)
+	}
 }
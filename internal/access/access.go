@@ -0,0 +1,177 @@
+// Package access gives callers a role (viewer, trader or admin) from the
+// PolyGo access key or JWT they present, so an instance can be opened to
+// readers without opening trading and administration to them too.
+package access
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+)
+
+// Role is what a caller may do; each role may do what the ones before it may
+type Role string
+
+// Roles, from least to most privileged
+const (
+	RoleViewer Role = "viewer" // reads
+	RoleTrader Role = "trader" // reads, orders and the caller's own webhooks, watchlists and alerts
+	RoleAdmin  Role = "admin"  // everything, including /admin
+)
+
+// rank orders the roles; unknown roles rank below viewer
+var rank = map[Role]int{RoleViewer: 1, RoleTrader: 2, RoleAdmin: 3}
+
+// Allows reports whether the role may do what required may
+func (r Role) Allows(required Role) bool {
+	return rank[r] >= rank[required]
+}
+
+// Valid reports whether the role is one of the known ones
+func (r Role) Valid() bool {
+	return rank[r] > 0
+}
+
+var (
+	// ErrInvalidConfig is wrapped by every access config error
+	ErrInvalidConfig = errors.New("invalid access config")
+	// ErrInvalidToken is returned for a key that is not configured, or a
+	// JWT that is malformed, badly signed, expired or carries no valid role
+	ErrInvalidToken = errors.New("invalid access token")
+	// ErrTokenRequired is returned for a caller with no token when there is
+	// no default role
+	ErrTokenRequired = errors.New("an access token is required")
+)
+
+// Identity is who a caller is and the role they have
+type Identity struct {
+	Name string `json:"name"` // the key's name, jwt:<sub> or admin; empty for callers with no token
+	Role Role   `json:"role"`
+}
+
+// Control resolves access tokens to identities
+type Control struct {
+	config     *config.AccessConfig
+	adminToken string
+	keys       map[string]Identity
+	jwtSecret  []byte
+	now        func() time.Time
+}
+
+// New creates the access control of the configured keys. The admin token
+// is accepted as a key with the admin role.
+func New(cfg *config.AccessConfig, adminToken string) (*Control, error) {
+	a := &Control{
+		config:     cfg,
+		adminToken: adminToken,
+		keys:       make(map[string]Identity),
+		jwtSecret:  []byte(cfg.JWTSecret),
+		now:        time.Now,
+	}
+	if !cfg.Enabled {
+		return a, nil
+	}
+
+	if cfg.DefaultRole != "" && !Role(cfg.DefaultRole).Valid() {
+		return nil, fmt.Errorf("%w: default_role must be viewer, trader, admin or empty", ErrInvalidConfig)
+	}
+	for i, k := range cfg.Keys {
+		name := k.Name
+		if name == "" {
+			name = fmt.Sprintf("key-%d", i+1)
+		}
+		if k.Key == "" {
+			return nil, fmt.Errorf("%w: key %s is empty", ErrInvalidConfig, name)
+		}
+		if !Role(k.Role).Valid() {
+			return nil, fmt.Errorf("%w: key %s: role must be viewer, trader or admin", ErrInvalidConfig, name)
+		}
+		if _, ok := a.keys[k.Key]; ok {
+			return nil, fmt.Errorf("%w: key %s is configured twice", ErrInvalidConfig, name)
+		}
+		a.keys[k.Key] = Identity{Name: name, Role: Role(k.Role)}
+	}
+	return a, nil
+}
+
+// Enabled reports whether roles are enforced
+func (a *Control) Enabled() bool {
+	return a.config.Enabled
+}
+
+// Resolve returns the identity of a bearer token: a configured key, the
+// admin token, or a JWT signed with the configured secret. An empty token
+// gets the default role.
+func (a *Control) Resolve(token string) (Identity, error) {
+	if token == "" {
+		if a.config.DefaultRole == "" {
+			return Identity{}, ErrTokenRequired
+		}
+		return Identity{Role: Role(a.config.DefaultRole)}, nil
+	}
+
+	if a.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(a.adminToken)) == 1 {
+		return Identity{Name: "admin", Role: RoleAdmin}, nil
+	}
+	if id, ok := a.keys[token]; ok {
+		return id, nil
+	}
+	if len(a.jwtSecret) > 0 && strings.Count(token, ".") == 2 {
+		return a.verifyJWT(token)
+	}
+	return Identity{}, ErrInvalidToken
+}
+
+// jwtClaims are the claims read from a JWT
+type jwtClaims struct {
+	Subject   string  `json:"sub"`
+	Role      Role    `json:"role"`
+	ExpiresAt float64 `json:"exp"`
+	NotBefore float64 `json:"nbf"`
+}
+
+// verifyJWT checks an HS256 JWT's signature and validity window and
+// returns the identity its sub and role claims name
+func (a *Control) verifyJWT(token string) (Identity, error) {
+	parts := strings.Split(token, ".")
+
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return Identity{}, ErrInvalidToken
+	}
+	var h struct {
+		Alg string `json:"alg"`
+	}
+	if sonic.Unmarshal(header, &h) != nil || h.Alg != "HS256" {
+		return Identity{}, ErrInvalidToken
+	}
+
+	mac := hmac.New(sha256.New, a.jwtSecret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, mac.Sum(nil)) {
+		return Identity{}, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Identity{}, ErrInvalidToken
+	}
+	var claims jwtClaims
+	if sonic.Unmarshal(payload, &claims) != nil || !claims.Role.Valid() {
+		return Identity{}, ErrInvalidToken
+	}
+	now := float64(a.now().Unix())
+	if (claims.ExpiresAt > 0 && now >= claims.ExpiresAt) || (claims.NotBefore > 0 && now < claims.NotBefore) {
+		return Identity{}, ErrInvalidToken
+	}
+	return Identity{Name: "jwt:" + claims.Subject, Role: claims.Role}, nil
+}
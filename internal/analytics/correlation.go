@@ -0,0 +1,111 @@
+package analytics
+
+import (
+	"math"
+	"time"
+)
+
+// minCorrelationSamples is the fewest shared price changes a pair needs
+// for its correlation to be reported
+const minCorrelationSamples = 3
+
+// PricePoint is one observation of a token's price
+type PricePoint struct {
+	Time  time.Time
+	Price float64
+}
+
+// CorrelationMatrix holds the pairwise correlations of a set of tokens.
+// Matrix[i][j] is nil where the pair shares too few price changes or one
+// of them never moved; Samples[i][j] is the number of changes compared.
+type CorrelationMatrix struct {
+	TokenIDs []string     `json:"token_ids"`
+	Matrix   [][]*float64 `json:"matrix"`
+	Samples  [][]int      `json:"samples"`
+	Missing  []string     `json:"missing,omitempty"` // tokens without recorded prices
+}
+
+// Correlate computes the Pearson correlation of the price changes of every
+// pair of tokens. Series are aligned on their timestamps, so two tokens
+// are compared only over the instants both were observed.
+func Correlate(tokenIDs []string, series map[string][]PricePoint) *CorrelationMatrix {
+	n := len(tokenIDs)
+	m := &CorrelationMatrix{
+		TokenIDs: tokenIDs,
+		Matrix:   make([][]*float64, n),
+		Samples:  make([][]int, n),
+	}
+	for i := range tokenIDs {
+		m.Matrix[i] = make([]*float64, n)
+		m.Samples[i] = make([]int, n)
+		if len(series[tokenIDs[i]]) == 0 {
+			m.Missing = append(m.Missing, tokenIDs[i])
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			x, y := changes(series[tokenIDs[i]], series[tokenIDs[j]])
+			m.Samples[i][j], m.Samples[j][i] = len(x), len(x)
+			if len(x) < minCorrelationSamples {
+				continue
+			}
+			if r, ok := pearson(x, y); ok {
+				m.Matrix[i][j], m.Matrix[j][i] = &r, &r
+			}
+		}
+	}
+	return m
+}
+
+// changes aligns two series on their shared timestamps and returns the
+// price change between consecutive shared points of each
+func changes(a, b []PricePoint) (x, y []float64) {
+	byTime := make(map[int64]float64, len(b))
+	for _, p := range b {
+		byTime[p.Time.UnixNano()] = p.Price
+	}
+
+	var prevA, prevB float64
+	first := true
+	for _, p := range a {
+		pb, ok := byTime[p.Time.UnixNano()]
+		if !ok {
+			continue
+		}
+		if !first {
+			x = append(x, p.Price-prevA)
+			y = append(y, pb-prevB)
+		}
+		prevA, prevB, first = p.Price, pb, false
+	}
+	return x, y
+}
+
+// pearson returns the correlation coefficient of x and y, or false when
+// either has no variance
+func pearson(x, y []float64) (float64, bool) {
+	n := float64(len(x))
+	var meanX, meanY float64
+	for i := range x {
+		meanX += x[i]
+		meanY += y[i]
+	}
+	meanX /= n
+	meanY /= n
+
+	var cov, varX, varY float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0, false
+	}
+
+	r := cov / math.Sqrt(varX*varY)
+	// Clamp rounding error into [-1, 1]
+	return math.Max(-1, math.Min(1, r)), true
+}
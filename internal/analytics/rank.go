@@ -0,0 +1,62 @@
+package analytics
+
+import (
+	"sort"
+
+	"github.com/polygo/internal/catalog"
+)
+
+// Ranking metrics for top markets
+const (
+	ByVolume    = "volume"
+	ByLiquidity = "liquidity"
+	BySpread    = "spread"
+	ByTrades    = "trades"
+)
+
+// RankedMarket is a catalog market annotated with its window trade count
+type RankedMarket struct {
+	*catalog.Entry
+	Trades int `json:"trades24h"`
+}
+
+// ValidMetric reports whether by is a supported ranking metric
+func ValidMetric(by string) bool {
+	switch by {
+	case ByVolume, ByLiquidity, BySpread, ByTrades:
+		return true
+	}
+	return false
+}
+
+// Rank orders markets by the given metric, best first. Spread ranks
+// tightest first and skips markets without a quoted spread.
+func Rank(entries []*catalog.Entry, trades *TradeCounter, by string) []RankedMarket {
+	out := make([]RankedMarket, 0, len(entries))
+	for _, e := range entries {
+		if by == BySpread && e.Spread.Float() <= 0 {
+			continue
+		}
+		r := RankedMarket{Entry: e}
+		if trades != nil {
+			r.Trades = trades.Count(e.ConditionID)
+		}
+		out = append(out, r)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		switch by {
+		case ByLiquidity:
+			return a.Liquidity.Float() > b.Liquidity.Float()
+		case BySpread:
+			return a.Spread.Float() < b.Spread.Float()
+		case ByTrades:
+			return a.Trades > b.Trades
+		default:
+			return a.Volume24hr.Float() > b.Volume24hr.Float()
+		}
+	})
+
+	return out
+}
@@ -0,0 +1,170 @@
+package analytics
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/polymarket"
+)
+
+// tradesPageSize is the largest page the Data API serves for /trades
+const tradesPageSize = 500
+
+// publicTrade is the subset of a Data API trade needed for counting
+type publicTrade struct {
+	ConditionID     string  `json:"conditionId"`
+	Asset           string  `json:"asset"`
+	Side            string  `json:"side"`
+	Size            float64 `json:"size"`
+	Price           float64 `json:"price"`
+	Timestamp       int64   `json:"timestamp"`
+	TransactionHash string  `json:"transactionHash"`
+}
+
+// key identifies a trade across overlapping polls
+func (t *publicTrade) key() string {
+	return t.TransactionHash + ":" + t.Asset + ":" + t.Side + ":" +
+		strconv.FormatFloat(t.Size, 'f', -1, 64) + ":" + strconv.FormatFloat(t.Price, 'f', -1, 64)
+}
+
+// TradeCounter keeps a rolling count of public trades per market
+// (condition ID), built by repeatedly polling the most recent trades
+type TradeCounter struct {
+	data   *polymarket.DataClient
+	config *config.AnalyticsConfig
+
+	mu   sync.RWMutex
+	seen map[string]time.Time // trade key -> trade time
+	byID map[string][]string  // condition ID -> trade keys
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewTradeCounter creates a new trade counter
+func NewTradeCounter(data *polymarket.DataClient, cfg *config.AnalyticsConfig) *TradeCounter {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &TradeCounter{
+		data:   data,
+		config: cfg,
+		seen:   make(map[string]time.Time),
+		byID:   make(map[string][]string),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start polls recent trades every TradeSyncInterval
+func (t *TradeCounter) Start() {
+	if t.config.TradeSyncInterval <= 0 {
+		return
+	}
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+
+		ticker := time.NewTicker(t.config.TradeSyncInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := t.Sync(); err != nil {
+				log.Printf("Trade count sync failed: %v", err)
+			}
+
+			select {
+			case <-t.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops background polling
+func (t *TradeCounter) Stop() {
+	t.cancel()
+	t.wg.Wait()
+}
+
+// Sync fetches the most recent trades and folds them into the counts
+func (t *TradeCounter) Sync() error {
+	var trades []publicTrade
+	for offset := 0; offset < t.config.TradeSampleSize; offset += tradesPageSize {
+		data, err := t.data.GetRecentTrades(tradesPageSize, offset)
+		if err != nil {
+			return err
+		}
+
+		var page []publicTrade
+		if err := sonic.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		trades = append(trades, page...)
+		if len(page) < tradesPageSize {
+			break
+		}
+	}
+
+	for i := range trades {
+		tr := &trades[i]
+		t.Add(tr.ConditionID, tr.key(), time.Unix(tr.Timestamp, 0))
+	}
+	t.expire(time.Now())
+	return nil
+}
+
+// Add records a single trade; duplicates and trades outside the window are
+// ignored
+func (t *TradeCounter) Add(conditionID, tradeKey string, at time.Time) {
+	if conditionID == "" || time.Since(at) > t.config.TradeWindow {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.seen[tradeKey]; ok {
+		return
+	}
+	t.seen[tradeKey] = at
+	t.byID[conditionID] = append(t.byID[conditionID], tradeKey)
+}
+
+// Count returns the number of trades in the window for a market
+func (t *TradeCounter) Count(conditionID string) int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.byID[conditionID])
+}
+
+// expire drops trades older than the window
+func (t *TradeCounter) expire(now time.Time) {
+	cutoff := now.Add(-t.config.TradeWindow)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for id, keys := range t.byID {
+		kept := keys[:0]
+		for _, k := range keys {
+			if t.seen[k].Before(cutoff) {
+				delete(t.seen, k)
+				continue
+			}
+			kept = append(kept, k)
+		}
+		if len(kept) == 0 {
+			delete(t.byID, id)
+		} else {
+			t.byID[id] = kept
+		}
+	}
+}
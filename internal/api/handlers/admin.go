@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/pkg/response"
+)
+
+// AdminHandler handles operator endpoints
+type AdminHandler struct {
+	config *config.Config
+	cache  *cache.Cache
+	client *polymarket.Client
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(cfg *config.Config, c *cache.Cache, client *polymarket.Client) *AdminHandler {
+	return &AdminHandler{config: cfg, cache: c, client: client}
+}
+
+// GetEffectiveConfig godoc
+// @Summary Effective configuration
+// @Description Get the redacted running configuration and warnings about risky settings
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=config.Effective}
+// @Failure 401 {object} response.Response
+// @Router /admin/config/effective [get]
+func (h *AdminHandler) GetEffectiveConfig(c *fiber.Ctx) error {
+	return response.Success(c, h.config.Effective())
+}
+
+// PurgeCache godoc
+// @Summary Purge response cache
+// @Description Drop one cache entry by key, or the whole cache when no key is given
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param key query string false "Cache key to drop"
+// @Security AdminAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /admin/cache [delete]
+func (h *AdminHandler) PurgeCache(c *fiber.Ctx) error {
+	if key := c.Query("key"); key != "" {
+		h.cache.Delete(key)
+		return response.Success(c, fiber.Map{"purged": key})
+	}
+
+	h.cache.Clear()
+	return response.Success(c, fiber.Map{"purged": "*"})
+}
+
+// GetSchemaDrift godoc
+// @Summary Upstream schema drift
+// @Description Get fields that appeared in or disappeared from upstream payloads compared to the models, per endpoint. Empty unless polymarket.validation is warn or strict.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=[]polymarket.DriftReport}
+// @Failure 401 {object} response.Response
+// @Router /admin/drift [get]
+func (h *AdminHandler) GetSchemaDrift(c *fiber.Ctx) error {
+	reports := h.client.SchemaDrift()
+	if reports == nil {
+		reports = []polymarket.DriftReport{}
+	}
+	return response.SuccessWithMeta(c, reports, &response.Meta{Total: len(reports)})
+}
+
+// UpstreamPoolRequest changes the upstream connection limit
+type UpstreamPoolRequest struct {
+	Host            string `json:"host,omitempty"` // host or host:port; empty changes the default of every host
+	MaxConnsPerHost int    `json:"max_conns_per_host"`
+}
+
+// SetUpstreamPool godoc
+// @Summary Tune upstream connections
+// @Description Change at runtime how many connections are opened to an upstream host, or to every host without a limit of its own, e.g. when Polymarket throttles concurrency. A lowered limit stops new connections being opened; those already open above it keep serving until idle past max_idle_conn_dur. Lasts until restart.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body UpstreamPoolRequest true "Limit"
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=[]polymarket.PoolStats}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /admin/upstream/pool [put]
+func (h *AdminHandler) SetUpstreamPool(c *fiber.Ctx) error {
+	var req UpstreamPoolRequest
+	if err := sonic.Unmarshal(c.Body(), &req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+	if err := h.client.SetMaxConns(req.Host, req.MaxConnsPerHost); err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+	return response.Success(c, h.client.PoolStats())
+}
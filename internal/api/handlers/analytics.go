@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/analytics"
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/recorder"
+	"github.com/polygo/internal/shape"
+	"github.com/polygo/pkg/response"
+)
+
+// maxCorrelationTokens bounds the tokens of one correlation matrix
+const maxCorrelationTokens = 20
+
+// AnalyticsHandler handles aggregated market analytics endpoints
+type AnalyticsHandler struct {
+	catalog  *catalog.Catalog
+	trades   *analytics.TradeCounter
+	recorder *recorder.Recorder
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(cat *catalog.Catalog, trades *analytics.TradeCounter, rec *recorder.Recorder) *AnalyticsHandler {
+	return &AnalyticsHandler{catalog: cat, trades: trades, recorder: rec}
+}
+
+// TopMarkets godoc
+// @Summary Top markets leaderboard
+// @Description Rank catalog markets by 24h volume, liquidity, spread tightness or 24h trade count
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Param by query string false "Ranking metric: volume, liquidity, spread or trades" default(volume)
+// @Param category query string false "Normalized category (politics, sports, crypto, ...)"
+// @Param tag query string false "Event tag slug"
+// @Param limit query int false "Limit results" default(50)
+// @Param cursor query string false "Pagination cursor (next_cursor or offset)"
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]analytics.RankedMarket}
+// @Failure 400 {object} response.Response
+// @Router /api/v1/analytics/markets/top [get]
+func (h *AnalyticsHandler) TopMarkets(c *fiber.Ctx) error {
+	by := strings.ToLower(c.Query("by", analytics.ByVolume))
+	if !analytics.ValidMetric(by) {
+		return response.BadRequest(c, "by must be volume, liquidity, spread or trades")
+	}
+
+	category := strings.ToLower(c.Query("category"))
+	tag := strings.ToLower(c.Query("tag"))
+	limit := c.QueryInt("limit", 50)
+	offset := pageOffset(cursorParam(c), 0)
+
+	var entries []*catalog.Entry
+	for _, e := range h.catalog.Markets() {
+		if category != "" && e.Category != category {
+			continue
+		}
+		if tag != "" && !hasTag(e.Tags, tag) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	ranked := analytics.Rank(entries, h.trades, by)
+	return send(c, shape.RankedMarkets, paginate(ranked, offset, limit), listMeta(c, offset, limit, len(ranked)))
+}
+
+// Correlation godoc
+// @Summary Price correlation matrix
+// @Description Pearson correlation of the price changes of several tokens over a trailing window, computed from locally recorded prices. Pairs with fewer than 3 shared changes, or a token that never moved, are null.
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Param token_ids query string true "Comma-separated token IDs (2 to 20)"
+// @Param window query string false "Trailing window (e.g. 1h, 24h)" default(24h)
+// @Success 200 {object} response.Response{data=analytics.CorrelationMatrix}
+// @Failure 400 {object} response.Response
+// @Failure 503 {object} response.Response
+// @Router /api/v1/analytics/correlation [get]
+func (h *AnalyticsHandler) Correlation(c *fiber.Ctx) error {
+	var tokenIDs []string
+	seen := make(map[string]bool)
+	for _, id := range strings.Split(c.Query("token_ids"), ",") {
+		id = strings.TrimSpace(id)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			tokenIDs = append(tokenIDs, id)
+		}
+	}
+	if len(tokenIDs) < 2 {
+		return response.BadRequest(c, "token_ids needs at least two token IDs")
+	}
+	if len(tokenIDs) > maxCorrelationTokens {
+		return response.BadRequest(c, "At most "+strconv.Itoa(maxCorrelationTokens)+" token IDs are allowed")
+	}
+
+	window, err := time.ParseDuration(c.Query("window", "24h"))
+	if err != nil || window <= 0 {
+		return response.BadRequest(c, "Invalid window, use a duration like 1h or 24h")
+	}
+	if window > h.recorder.Retention() {
+		return response.BadRequest(c, "Window exceeds recorded history of "+h.recorder.Retention().String())
+	}
+	if !h.recorder.Ready() {
+		return response.Error(c, fiber.StatusServiceUnavailable, "RECORDER_UNAVAILABLE", "No prices recorded yet", "")
+	}
+
+	since := time.Now().Add(-window)
+	series := make(map[string][]analytics.PricePoint, len(tokenIDs))
+	for _, id := range tokenIDs {
+		series[id] = h.tokenPrices(id, since)
+	}
+	return response.Success(c, analytics.Correlate(tokenIDs, series))
+}
+
+// tokenPrices returns a token's recorded closing prices since the given
+// time. The recorder tracks a market's first outcome; the other outcome of
+// a binary market is its complement.
+func (h *AnalyticsHandler) tokenPrices(tokenID string, since time.Time) []analytics.PricePoint {
+	entry, outcome, ok := h.catalog.Token(tokenID)
+	if !ok {
+		return nil
+	}
+
+	candles := h.recorder.Candles(entry.ID, since)
+	out := make([]analytics.PricePoint, len(candles))
+	for i, candle := range candles {
+		price := candle.Close
+		if outcome > 0 {
+			price = 1 - price
+		}
+		out[i] = analytics.PricePoint{Time: candle.Time, Price: price}
+	}
+	return out
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/audit"
+	"github.com/polygo/pkg/response"
+)
+
+// AuditHandler serves the audit log
+type AuditHandler struct {
+	log *audit.Log
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(log *audit.Log) *AuditHandler {
+	return &AuditHandler{log: log}
+}
+
+// GetAudit godoc
+// @Summary Search the audit log
+// @Description List recorded actions newest first: orders placed and cancelled, dead-man's switches armed and disarmed, webhooks, watchlists and alerts changed, and admin changes, each with the API key (or admin) that made it, the client IP, the HTTP status and the SHA-256 of the request body. Only the latest audit.keep entries are searched; export for older ones.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param actor query string false "API key, or admin"
+// @Param action query string false "Action, e.g. order.cancel, or family, e.g. order.*"
+// @Param since query string false "RFC3339 time, inclusive"
+// @Param until query string false "RFC3339 time, exclusive"
+// @Param limit query int false "Page size" default(100)
+// @Param cursor query string false "Cursor from the previous page"
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=[]audit.Entry}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /admin/audit [get]
+func (h *AuditHandler) GetAudit(c *fiber.Ctx) error {
+	filter, err := auditFilter(c)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+	limit := c.QueryInt("limit", 100)
+	offset := pageOffset(cursorParam(c), 0)
+
+	entries := h.log.Query(filter)
+	return response.SuccessWithMeta(c, paginate(entries, offset, limit), listMeta(c, offset, limit, len(entries)))
+}
+
+// ExportAudit godoc
+// @Summary Export the audit log
+// @Description Download every recorded action matching the filters, oldest first, as JSON lines or CSV. Reads the whole audit file, including entries too old to be searched.
+// @Tags Admin
+// @Produce application/x-ndjson,text/csv
+// @Param actor query string false "API key, or admin"
+// @Param action query string false "Action, e.g. order.cancel, or family, e.g. order.*"
+// @Param since query string false "RFC3339 time, inclusive"
+// @Param until query string false "RFC3339 time, exclusive"
+// @Param format query string false "jsonl or csv" default(jsonl)
+// @Security AdminAuth
+// @Success 200 {file} file
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /admin/audit/export [get]
+func (h *AuditHandler) ExportAudit(c *fiber.Ctx) error {
+	filter, err := auditFilter(c)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+	format := c.Query("format", audit.FormatJSONL)
+	if format != audit.FormatJSONL && format != audit.FormatCSV {
+		return response.BadRequest(c, "Invalid format, use jsonl or csv")
+	}
+
+	var buf bytes.Buffer
+	if err := h.log.Export(&buf, filter, format); err != nil {
+		return errorResponse(c, err)
+	}
+	c.Attachment("audit-" + time.Now().UTC().Format("20060102T150405Z") + "." + format)
+	return c.Send(buf.Bytes())
+}
+
+// auditFilter reads the filters shared by search and export
+func auditFilter(c *fiber.Ctx) (audit.Filter, error) {
+	filter := audit.Filter{Actor: c.Query("actor"), Action: c.Query("action")}
+	for name, t := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if s := c.Query(name); s != "" {
+			v, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return filter, errors.New(name + " must be an RFC3339 time like 2024-01-31T00:00:00Z")
+			}
+			*t = v
+		}
+	}
+	return filter, nil
+}
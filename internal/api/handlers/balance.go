@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/api/middleware"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/pkg/response"
+)
+
+// BalanceHandler reports whether the caller's account can trade
+type BalanceHandler struct {
+	balances   *polymarket.BalanceChecker
+	authConfig *config.AuthConfig
+}
+
+// NewBalanceHandler creates a new balance handler
+func NewBalanceHandler(clob *polymarket.ClobClient, authConfig *config.AuthConfig) *BalanceHandler {
+	return &BalanceHandler{balances: polymarket.NewBalanceChecker(clob), authConfig: authConfig}
+}
+
+// GetBalance godoc
+// @Summary Get balance and allowances
+// @Description Get the caller's USDC balance and exchange allowances, what their open orders reserve, and whether the account can trade, with warnings for anything that would make orders fail (missing allowances, balances below open-order requirements)
+// @Tags User Data
+// @Accept json
+// @Produce json
+// @Param signature_type query int false "Wallet signature type used for orders (0 EOA, 1 Polymarket proxy, 2 Gnosis Safe)"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Response{data=polymarket.BalanceReport}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/user/balance [get]
+func (h *BalanceHandler) GetBalance(c *fiber.Ctx) error {
+	creds := middleware.GetAuthCredentials(c)
+	if creds == nil {
+		return response.Unauthorized(c, "Authentication required")
+	}
+
+	report, err := h.balances.Report(c.Query("signature_type"), middleware.GetAuthHeaders(creds, h.authConfig))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return response.Success(c, report)
+}
@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/shape"
+	"github.com/polygo/pkg/response"
+)
+
+// CatalogHandler handles endpoints served from the local market catalog
+type CatalogHandler struct {
+	catalog  *catalog.Catalog
+	resolver *catalog.Resolver
+	gamma    *polymarket.GammaClient
+}
+
+// NewCatalogHandler creates a new catalog handler
+func NewCatalogHandler(cat *catalog.Catalog, resolver *catalog.Resolver, gamma *polymarket.GammaClient) *CatalogHandler {
+	return &CatalogHandler{catalog: cat, resolver: resolver, gamma: gamma}
+}
+
+// Screener godoc
+// @Summary Market screener
+// @Description Filter catalog markets by category, tag and text, sorted by 24h volume
+// @Tags Markets
+// @Accept json
+// @Produce json
+// @Param category query string false "Normalized category (politics, sports, crypto, ...)"
+// @Param tag query string false "Event tag slug"
+// @Param q query string false "Text search on question and event title"
+// @Param limit query int false "Limit results" default(100)
+// @Param cursor query string false "Pagination cursor (next_cursor or offset)"
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]catalog.Entry}
+// @Router /api/v1/screener [get]
+func (h *CatalogHandler) Screener(c *fiber.Ctx) error {
+	category := strings.ToLower(c.Query("category"))
+	tag := strings.ToLower(c.Query("tag"))
+	q := strings.ToLower(c.Query("q"))
+	limit := c.QueryInt("limit", 100)
+	offset := pageOffset(cursorParam(c), 0)
+
+	var matches []*catalog.Entry
+	for _, e := range h.catalog.Markets() {
+		if category != "" && e.Category != category {
+			continue
+		}
+		if tag != "" && !hasTag(e.Tags, tag) {
+			continue
+		}
+		if q != "" && !strings.Contains(strings.ToLower(e.Question+" "+e.EventTitle), q) {
+			continue
+		}
+		matches = append(matches, e)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Volume24hr.Float() > matches[j].Volume24hr.Float()
+	})
+
+	return send(c, shape.Markets, paginate(matches, offset, limit), listMeta(c, offset, limit, len(matches)))
+}
+
+// GetEnrichedMarket godoc
+// @Summary Get enriched market
+// @Description Get a market with its event, tags and normalized category
+// @Tags Markets
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Success 200 {object} response.Response{data=catalog.Entry}
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/markets/{id}/enriched [get]
+func (h *CatalogHandler) GetEnrichedMarket(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return response.BadRequest(c, "Market ID is required")
+	}
+
+	if entry, ok := h.catalog.Market(id); ok {
+		return send(c, shape.MarketOne, entry, nil)
+	}
+
+	// Not synced yet (or inactive): fetch and classify on the fly
+	data, _, err := h.gamma.GetMarket(id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if len(data) == 0 || string(data) == "null" {
+		return response.NotFound(c, "Market not found")
+	}
+
+	var market models.Market
+	if err := sonic.Unmarshal(data, &market); err != nil {
+		return errorResponse(c, err)
+	}
+
+	entry := &catalog.Entry{Market: market}
+	entry.Category = h.catalog.Classify(entry)
+	return send(c, shape.MarketOne, entry, nil)
+}
+
+// ResolveToken godoc
+// @Summary Resolve a token ID
+// @Description Get the market, outcome, question and slug behind a CLOB token ID
+// @Tags Markets
+// @Accept json
+// @Produce json
+// @Param token_id path string true "CLOB token ID"
+// @Success 200 {object} response.Response{data=catalog.TokenInfo}
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/resolve/{token_id} [get]
+func (h *CatalogHandler) ResolveToken(c *fiber.Ctx) error {
+	tokenID := c.Params("token_id")
+	if tokenID == "" {
+		return response.BadRequest(c, "Token ID is required")
+	}
+
+	info, err := h.resolver.Resolve(tokenID)
+	if errors.Is(err, catalog.ErrUnknownToken) {
+		return response.NotFound(c, "Token not found")
+	}
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	return response.Success(c, info)
+}
+
+// CategoryCount is the number of catalog markets in a category
+type CategoryCount struct {
+	Category string `json:"category"`
+	Markets  int    `json:"markets"`
+}
+
+// GetCategories godoc
+// @Summary List market categories
+// @Description List normalized categories with catalog market counts
+// @Tags Markets
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=[]CategoryCount}
+// @Router /api/v1/categories [get]
+func (h *CatalogHandler) GetCategories(c *fiber.Ctx) error {
+	counts := make(map[string]int)
+	for _, e := range h.catalog.Markets() {
+		counts[e.Category]++
+	}
+
+	out := make([]CategoryCount, 0, len(counts))
+	for cat, n := range counts {
+		out = append(out, CategoryCount{Category: cat, Markets: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Markets != out[j].Markets {
+			return out[i].Markets > out[j].Markets
+		}
+		return out[i].Category < out[j].Category
+	})
+
+	return response.Success(c, out)
+}
+
+// GetTags godoc
+// @Summary List tags
+// @Description List every event tag in the catalog with its event and market counts
+// @Tags Markets
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=[]catalog.TagCount}
+// @Router /api/v1/tags [get]
+func (h *CatalogHandler) GetTags(c *fiber.Ctx) error {
+	return response.Success(c, h.catalog.Tags())
+}
+
+// GetTagMarkets godoc
+// @Summary List markets for a tag
+// @Description List catalog markets whose event carries the tag, sorted by 24h volume
+// @Tags Markets
+// @Accept json
+// @Produce json
+// @Param slug path string true "Tag slug"
+// @Param limit query int false "Limit results" default(100)
+// @Param cursor query string false "Pagination cursor (next_cursor or offset)"
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]catalog.Entry}
+// @Failure 404 {object} response.Response
+// @Router /api/v1/tags/{slug}/markets [get]
+func (h *CatalogHandler) GetTagMarkets(c *fiber.Ctx) error {
+	slug := c.Params("slug")
+	limit := c.QueryInt("limit", 100)
+	offset := pageOffset(cursorParam(c), 0)
+
+	markets := h.catalog.MarketsByTag(slug)
+	if len(markets) == 0 {
+		return response.NotFound(c, "Tag not found")
+	}
+
+	sort.SliceStable(markets, func(i, j int) bool {
+		return markets[i].Volume24hr.Float() > markets[j].Volume24hr.Float()
+	})
+
+	return send(c, shape.Markets, paginate(markets, offset, limit), listMeta(c, offset, limit, len(markets)))
+}
+
+// GetClosingMarkets godoc
+// @Summary Markets closing soon
+// @Description List open catalog markets whose end date falls within a window, soonest first, with their current outcome prices and the seconds remaining
+// @Tags Markets
+// @Accept json
+// @Produce json
+// @Param within query string false "Window ahead (e.g. 1h, 24h, 168h)" default(24h)
+// @Param category query string false "Normalized category (politics, sports, crypto, ...)"
+// @Param tag query string false "Event tag slug"
+// @Param limit query int false "Limit results" default(100)
+// @Param cursor query string false "Pagination cursor (next_cursor or offset)"
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]catalog.ClosingMarket}
+// @Failure 400 {object} response.Response
+// @Router /api/v1/markets/closing [get]
+func (h *CatalogHandler) GetClosingMarkets(c *fiber.Ctx) error {
+	within, err := time.ParseDuration(c.Query("within", "24h"))
+	if err != nil || within <= 0 {
+		return response.BadRequest(c, "Invalid within, use a duration like 1h or 24h")
+	}
+
+	category := strings.ToLower(c.Query("category"))
+	tag := strings.ToLower(c.Query("tag"))
+	limit := c.QueryInt("limit", 100)
+	offset := pageOffset(cursorParam(c), 0)
+
+	var matches []catalog.ClosingMarket
+	for _, m := range h.catalog.Closing(time.Now(), within) {
+		if category != "" && m.Category != category {
+			continue
+		}
+		if tag != "" && !hasTag(m.Tags, tag) {
+			continue
+		}
+		matches = append(matches, m)
+	}
+
+	return send(c, shape.ClosingMarkets, paginate(matches, offset, limit), listMeta(c, offset, limit, len(matches)))
+}
+
+// GetGroupedEvents godoc
+// @Summary Active events grouped by tag
+// @Description List the catalog's active events bucketed by tag, with each bucket's event count, total volume and top events by volume, ordered by volume
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Param top query int false "Top events per tag" default(5)
+// @Param limit query int false "Limit tags" default(50)
+// @Param cursor query string false "Pagination cursor (next_cursor or offset)"
+// @Success 200 {object} response.Response{data=[]catalog.TagGroup}
+// @Router /api/v1/events/grouped [get]
+func (h *CatalogHandler) GetGroupedEvents(c *fiber.Ctx) error {
+	top := c.QueryInt("top", 5)
+	if top < 0 || top > 50 {
+		top = 50
+	}
+	limit := c.QueryInt("limit", 50)
+	offset := pageOffset(cursorParam(c), 0)
+
+	groups := h.catalog.EventsByTag(top)
+	return response.SuccessWithMeta(c, paginate(groups, offset, limit), listMeta(c, offset, limit, len(groups)))
+}
+
+// hasTag reports whether tags contains the given slug
+func hasTag(tags []models.Tag, slug string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t.Slug, slug) {
+			return true
+		}
+	}
+	return false
+}
+
+// paginate returns the [offset, offset+limit) window of items
+func paginate[T any](items []T, offset, limit int) []T {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(items) {
+		return []T{}
+	}
+	end := len(items)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return items[offset:end]
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/chain"
+	"github.com/polygo/pkg/response"
+)
+
+// ChainHandler verifies trade settlement on Polygon
+type ChainHandler struct {
+	verifier *chain.Verifier
+}
+
+// NewChainHandler creates a new chain handler
+func NewChainHandler(verifier *chain.Verifier) *ChainHandler {
+	return &ChainHandler{verifier: verifier}
+}
+
+// VerifyTransaction godoc
+// @Summary Verify a trade transaction
+// @Description Check a trade's transactionHash on Polygon: confirmation status, block number, confirmations and the USDC (ERC-20) and outcome share (ERC-1155) transfers it made. A transaction is confirmed once it succeeded and is POLYGO_CHAIN_CONFIRMATIONS blocks deep.
+// @Tags Trades
+// @Accept json
+// @Produce json
+// @Param hash path string true "Transaction hash"
+// @Success 200 {object} response.Response{data=chain.Settlement}
+// @Failure 400 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 503 {object} response.Response
+// @Router /api/v1/tx/{hash}/verify [get]
+func (h *ChainHandler) VerifyTransaction(c *fiber.Ctx) error {
+	settlement, err := h.verifier.Verify(c.Params("hash"))
+	switch {
+	case errors.Is(err, chain.ErrInvalidHash):
+		return response.BadRequest(c, "Transaction hash must be 0x followed by 64 hex characters")
+	case errors.Is(err, chain.ErrNoRPC):
+		return response.Error(c, fiber.StatusServiceUnavailable, "CHAIN_UNAVAILABLE", "No Polygon RPC endpoint is configured", "")
+	case err != nil:
+		return response.Error(c, fiber.StatusBadGateway, "RPC_ERROR", "Polygon RPC request failed", err.Error())
+	}
+
+	return response.Success(c, settlement)
+}
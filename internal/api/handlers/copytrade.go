@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/copytrade"
+	"github.com/polygo/pkg/response"
+	"github.com/polygo/pkg/validate"
+)
+
+// CopyTradeHandler controls the copy-trading follower engine
+type CopyTradeHandler struct {
+	engine *copytrade.Engine
+}
+
+// NewCopyTradeHandler creates a new copy-trading handler
+func NewCopyTradeHandler(e *copytrade.Engine) *CopyTradeHandler {
+	return &CopyTradeHandler{engine: e}
+}
+
+// Start godoc
+// @Summary Start copy trading
+// @Description Follow a target wallet and mirror its new trades as orders for the configured local account, scaled and capped by the given or configured limits
+// @Tags Copy Trading
+// @Accept json
+// @Produce json
+// @Param request body copytrade.Options true "Target wallet and limits"
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=copytrade.Status}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 503 {object} response.Response
+// @Router /api/v1/copytrade/start [post]
+func (h *CopyTradeHandler) Start(c *fiber.Ctx) error {
+	var opts copytrade.Options
+	if err := sonic.Unmarshal(c.Body(), &opts); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+	if opts.Scale < 0 || opts.MaxOrderNotional < 0 || opts.MaxExposure < 0 {
+		return response.BadRequest(c, "Scale and limits must not be negative")
+	}
+
+	err := h.engine.Start(opts)
+	switch {
+	case errors.Is(err, validate.ErrInvalidAddress):
+		return response.BadRequest(c, "A valid 0x target wallet address is required")
+	case errors.Is(err, copytrade.ErrRunning):
+		return response.Error(c, fiber.StatusConflict, "COPYTRADE_RUNNING", "Already following a wallet, stop it first", "")
+	case errors.Is(err, copytrade.ErrDisabled), errors.Is(err, copytrade.ErrNoAccount):
+		return response.Error(c, fiber.StatusServiceUnavailable, "COPYTRADE_UNAVAILABLE", err.Error(), "")
+	case err != nil:
+		return errorResponse(c, err)
+	}
+
+	return response.Success(c, h.engine.Status())
+}
+
+// Stop godoc
+// @Summary Stop copy trading
+// @Description Stop following the target wallet. Orders already placed are left as they are.
+// @Tags Copy Trading
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=copytrade.Status}
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /api/v1/copytrade/stop [post]
+func (h *CopyTradeHandler) Stop(c *fiber.Ctx) error {
+	if err := h.engine.Stop(); errors.Is(err, copytrade.ErrNotRunning) {
+		return response.Error(c, fiber.StatusConflict, "COPYTRADE_NOT_RUNNING", "Not following a wallet", "")
+	}
+	return response.Success(c, h.engine.Status())
+}
+
+// Status godoc
+// @Summary Copy trading status
+// @Description Get the current or last session: target, limits, exposure, counts and the most recent mirrored trades with their outcome
+// @Tags Copy Trading
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=copytrade.Status}
+// @Failure 401 {object} response.Response
+// @Router /api/v1/copytrade/status [get]
+func (h *CopyTradeHandler) Status(c *fiber.Ctx) error {
+	return response.Success(c, h.engine.Status())
+}
@@ -1,19 +1,59 @@
 package handlers
 
 import (
+	"strings"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/api/middleware"
+	"github.com/polygo/internal/chain"
 	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/recorder"
+	"github.com/polygo/internal/shape"
 	"github.com/polygo/pkg/response"
 )
 
 // DataHandler handles data-related endpoints (positions, trades, activity)
 type DataHandler struct {
-	data *polymarket.DataClient
+	data     *polymarket.DataClient
+	profiles *polymarket.TraderProfileService
+	recorder *recorder.Recorder
+	verifier *chain.Verifier
 }
 
 // NewDataHandler creates a new data handler
-func NewDataHandler(data *polymarket.DataClient) *DataHandler {
-	return &DataHandler{data: data}
+func NewDataHandler(data *polymarket.DataClient, rec *recorder.Recorder, verifier *chain.Verifier) *DataHandler {
+	return &DataHandler{data: data, profiles: polymarket.NewTraderProfileService(data), recorder: rec, verifier: verifier}
+}
+
+// userData returns the Data client to fetch the caller's user data with:
+// a tenant's cached copies are kept apart from other callers'
+func (h *DataHandler) userData(c *fiber.Ctx) *polymarket.DataClient {
+	if t := middleware.GetTenant(c); t != nil {
+		return h.data.Namespace(t.ID)
+	}
+	return h.data
+}
+
+// traderProfiles is userData for trader profiles
+func (h *DataHandler) traderProfiles(c *fiber.Ctx) *polymarket.TraderProfileService {
+	if t := middleware.GetTenant(c); t != nil {
+		return h.profiles.Namespace(t.ID)
+	}
+	return h.profiles
+}
+
+// verifyTrades adds each trade's on-chain "confirmed" flag when the caller
+// asks for it with ?verify=true; on failure the trades are returned as is
+func (h *DataHandler) verifyTrades(c *fiber.Ctx, data []byte) []byte {
+	if h.verifier == nil || !c.QueryBool("verify") {
+		return data
+	}
+	annotated, err := h.verifier.Annotate(data)
+	if err != nil {
+		return data
+	}
+	return annotated
 }
 
 // GetPositions godoc
@@ -23,8 +63,9 @@ func NewDataHandler(data *polymarket.DataClient) *DataHandler {
 // @Accept json
 // @Produce json
 // @Param address query string true "User wallet address"
+// @Param Cache-Control header string false "Send no-cache to bypass cached data"
 // @Param limit query int false "Limit results" default(100)
-// @Param cursor query string false "Pagination cursor"
+// @Param cursor query string false "Pagination cursor (next_cursor or offset)"
 // @Success 200 {object} response.Response{data=[]models.Position}
 // @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -36,14 +77,16 @@ func (h *DataHandler) GetPositions(c *fiber.Ctx) error {
 	}
 	
 	limit := c.QueryInt("limit", 100)
-	cursor := c.Query("cursor")
+	cursor := cursorParam(c)
 	
-	data, err := h.data.GetPositions(address, limit, cursor)
+	data, cached, err := h.userData(c).GetPositions(address, limit, cursor, noCache(c))
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.Raw(c, data)
+	meta := pageMeta(c, data, pageOffset(cursor, 0), limit)
+	cacheHeader(c, cached)
+	return relay(c, shape.Positions, data, meta)
 }
 
 // GetPositionsByMarket godoc
@@ -53,6 +96,7 @@ func (h *DataHandler) GetPositions(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param address query string true "User wallet address"
+// @Param Cache-Control header string false "Send no-cache to bypass cached data"
 // @Param market query string true "Market ID"
 // @Success 200 {object} response.Response{data=[]models.Position}
 // @Failure 400 {object} response.Response
@@ -69,12 +113,13 @@ func (h *DataHandler) GetPositionsByMarket(c *fiber.Ctx) error {
 		return response.BadRequest(c, "Market ID is required")
 	}
 	
-	data, err := h.data.GetPositionsByMarket(address, marketID)
+	data, cached, err := h.userData(c).GetPositionsByMarket(address, marketID, noCache(c))
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.Raw(c, data)
+	cacheHeader(c, cached)
+	return relay(c, shape.Positions, data, nil)
 }
 
 // GetUserTrades godoc
@@ -84,8 +129,10 @@ func (h *DataHandler) GetPositionsByMarket(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param address query string true "User wallet address"
+// @Param Cache-Control header string false "Send no-cache to bypass cached data"
 // @Param limit query int false "Limit results" default(100)
-// @Param cursor query string false "Pagination cursor"
+// @Param cursor query string false "Pagination cursor (next_cursor or offset)"
+// @Param verify query bool false "Add each trade's on-chain confirmed flag"
 // @Success 200 {object} response.Response{data=[]models.Trade}
 // @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -97,14 +144,16 @@ func (h *DataHandler) GetUserTrades(c *fiber.Ctx) error {
 	}
 	
 	limit := c.QueryInt("limit", 100)
-	cursor := c.Query("cursor")
+	cursor := cursorParam(c)
 	
-	data, err := h.data.GetTrades(address, limit, cursor)
+	data, cached, err := h.userData(c).GetTrades(address, limit, cursor, noCache(c))
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.Raw(c, data)
+	meta := pageMeta(c, data, pageOffset(cursor, 0), limit)
+	cacheHeader(c, cached)
+	return relay(c, shape.Trades, h.verifyTrades(c, data), meta)
 }
 
 // GetUserTradesByMarket godoc
@@ -114,8 +163,10 @@ func (h *DataHandler) GetUserTrades(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param address query string true "User wallet address"
+// @Param Cache-Control header string false "Send no-cache to bypass cached data"
 // @Param market query string true "Market ID"
 // @Param limit query int false "Limit results" default(100)
+// @Param verify query bool false "Add each trade's on-chain confirmed flag"
 // @Success 200 {object} response.Response{data=[]models.Trade}
 // @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -133,12 +184,13 @@ func (h *DataHandler) GetUserTradesByMarket(c *fiber.Ctx) error {
 	
 	limit := c.QueryInt("limit", 100)
 	
-	data, err := h.data.GetTradesByMarket(address, marketID, limit)
+	data, cached, err := h.userData(c).GetTradesByMarket(address, marketID, limit, noCache(c))
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.Raw(c, data)
+	cacheHeader(c, cached)
+	return relay(c, shape.Trades, h.verifyTrades(c, data), nil)
 }
 
 // GetActivity godoc
@@ -148,8 +200,9 @@ func (h *DataHandler) GetUserTradesByMarket(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param address query string true "User wallet address"
+// @Param Cache-Control header string false "Send no-cache to bypass cached data"
 // @Param limit query int false "Limit results" default(100)
-// @Param cursor query string false "Pagination cursor"
+// @Param cursor query string false "Pagination cursor (next_cursor or offset)"
 // @Success 200 {object} response.Response{data=[]models.Activity}
 // @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -161,14 +214,16 @@ func (h *DataHandler) GetActivity(c *fiber.Ctx) error {
 	}
 	
 	limit := c.QueryInt("limit", 100)
-	cursor := c.Query("cursor")
+	cursor := cursorParam(c)
 	
-	data, err := h.data.GetActivity(address, limit, cursor)
+	data, cached, err := h.userData(c).GetActivity(address, limit, cursor, noCache(c))
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.Raw(c, data)
+	meta := pageMeta(c, data, pageOffset(cursor, 0), limit)
+	cacheHeader(c, cached)
+	return relay(c, shape.Activities, data, meta)
 }
 
 // GetMarketTrades godoc
@@ -179,7 +234,8 @@ func (h *DataHandler) GetActivity(c *fiber.Ctx) error {
 // @Produce json
 // @Param market query string true "Market ID"
 // @Param limit query int false "Limit results" default(100)
-// @Param cursor query string false "Pagination cursor"
+// @Param cursor query string false "Pagination cursor (next_cursor or offset)"
+// @Param verify query bool false "Add each trade's on-chain confirmed flag"
 // @Success 200 {object} response.Response{data=[]models.Trade}
 // @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -191,14 +247,15 @@ func (h *DataHandler) GetMarketTrades(c *fiber.Ctx) error {
 	}
 	
 	limit := c.QueryInt("limit", 100)
-	cursor := c.Query("cursor")
+	cursor := cursorParam(c)
 	
 	data, err := h.data.GetMarketTrades(marketID, limit, cursor)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.Raw(c, data)
+	meta := pageMeta(c, data, pageOffset(cursor, 0), limit)
+	return relay(c, shape.Trades, h.verifyTrades(c, data), meta)
 }
 
 // GetPriceHistory godoc
@@ -223,12 +280,24 @@ func (h *DataHandler) GetPriceHistory(c *fiber.Ctx) error {
 	interval := c.Query("interval", "1d")
 	fidelity := c.QueryInt("fidelity", 0)
 	
+	if !typed(c) {
+		// Relayed as is, so long histories can be streamed
+		data, stream, err := h.data.StreamPriceHistory(tokenID, interval, fidelity)
+		if err != nil {
+			return errorResponse(c, err)
+		}
+		if stream != nil {
+			return response.Stream(c, stream, stream.Size)
+		}
+		return response.Raw(c, data)
+	}
+	
 	data, err := h.data.GetPriceHistory(tokenID, interval, fidelity)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.Raw(c, data)
+	return relay(c, shape.PriceHistory, data, nil)
 }
 
 // GetTimeseries godoc
@@ -255,31 +324,51 @@ func (h *DataHandler) GetTimeseries(c *fiber.Ctx) error {
 	
 	data, err := h.data.GetTimeseriesData(conditionID, startTs, endTs)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.Raw(c, data)
+	return relay(c, shape.Any, data, nil)
 }
 
 // GetTopMovers godoc
 // @Summary Get top moving markets
-// @Description Get markets with the highest price changes
+// @Description Get markets with the largest price changes over a trailing window, computed from locally recorded prices
 // @Tags Markets
 // @Accept json
 // @Produce json
+// @Param window query string false "Trailing window (e.g. 5m, 1h, 24h)" default(24h)
+// @Param sort query string false "Sort by abs, pct or volume" default(abs)
 // @Param limit query int false "Limit results" default(10)
-// @Success 200 {object} response.Response{data=object}
+// @Success 200 {object} response.Response{data=[]recorder.Mover}
+// @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/top-movers [get]
 func (h *DataHandler) GetTopMovers(c *fiber.Ctx) error {
 	limit := c.QueryInt("limit", 10)
 	
-	data, err := h.data.GetTopMovers(limit)
-	if err != nil {
-		return response.InternalError(c, err)
+	// Until the recorder has data, fall back to the upstream endpoint
+	if h.recorder == nil || !h.recorder.Ready() {
+		data, err := h.data.GetTopMovers(limit)
+		if err != nil {
+			return errorResponse(c, err)
+		}
+		return relay(c, shape.Any, data, nil)
+	}
+	
+	window, err := time.ParseDuration(c.Query("window", "24h"))
+	if err != nil || window <= 0 {
+		return response.BadRequest(c, "Invalid window, use a duration like 5m, 1h or 24h")
+	}
+	if window > h.recorder.Retention() {
+		return response.BadRequest(c, "Window exceeds recorded history of "+h.recorder.Retention().String())
+	}
+	
+	sortBy := c.Query("sort", recorder.SortAbsolute)
+	if !recorder.ValidSort(sortBy) {
+		return response.BadRequest(c, "Sort must be abs, pct or volume")
 	}
 	
-	return response.Raw(c, data)
+	return response.Success(c, h.recorder.Movers(window, sortBy, limit))
 }
 
 // GetLeaderboard godoc
@@ -297,8 +386,41 @@ func (h *DataHandler) GetLeaderboard(c *fiber.Ctx) error {
 	
 	data, err := h.data.GetLeaderboard(limit)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.Raw(c, data)
+	return relay(c, shape.Leaderboard, data, nil)
+}
+
+// GetTraderProfile godoc
+// @Summary Get trader profile
+// @Description Get a trader's positions, recent trades and activity with computed stats (volume, win rate on resolved positions, exposure, PnL) in one cached response
+// @Tags User Data
+// @Accept json
+// @Produce json
+// @Param address path string true "User wallet address"
+// @Param Cache-Control header string false "Send no-cache to bypass cached data"
+// @Success 200 {object} response.Response{data=polymarket.TraderProfile}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/trader/{address}/profile [get]
+func (h *DataHandler) GetTraderProfile(c *fiber.Ctx) error {
+	address := c.Params("address")
+	if address == "" {
+		return response.BadRequest(c, "Address is required")
+	}
+	
+	profile, cacheHit, err := h.traderProfiles(c).Get(address, noCache(c))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	
+	cacheHeader(c, cacheHit)
+	return send(c, shape.Profile, profile, nil)
+}
+
+// noCache reports whether the client asked to bypass cached user data
+func noCache(c *fiber.Ctx) bool {
+	cc := strings.ToLower(c.Get(fiber.HeaderCacheControl))
+	return strings.Contains(cc, "no-cache") || strings.Contains(cc, "no-store")
 }
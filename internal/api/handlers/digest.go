@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/digest"
+	"github.com/polygo/pkg/response"
+)
+
+// DigestHandler serves computed market digests
+type DigestHandler struct {
+	digests *digest.Builder
+	catalog *catalog.Catalog
+}
+
+// NewDigestHandler creates a new digest handler
+func NewDigestHandler(digests *digest.Builder, cat *catalog.Catalog) *DigestHandler {
+	return &DigestHandler{digests: digests, catalog: cat}
+}
+
+// GetDaily godoc
+// @Summary Daily movers digest
+// @Description Summary of the last day: biggest probability swings, markets created in the last 24h, markets ending within 24h and volume spikes against the 7-day daily average. Computed from the local catalog and price recorder and cached for POLYGO_DIGEST_TTL (an hour by default).
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=digest.Digest}
+// @Failure 503 {object} response.Response
+// @Router /api/v1/digest/daily [get]
+func (h *DigestHandler) GetDaily(c *fiber.Ctx) error {
+	if h.catalog.Status().LastSync.IsZero() {
+		return response.Error(c, fiber.StatusServiceUnavailable, "CATALOG_UNAVAILABLE", "Market catalog has not synced yet", "")
+	}
+
+	d, cacheHit := h.digests.Daily()
+	cacheHeader(c, cacheHit)
+	return response.Success(c, d)
+}
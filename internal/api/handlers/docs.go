@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/docs"
+	"github.com/polygo/pkg/response"
+)
+
+// DocsHandler serves the API description
+type DocsHandler struct {
+	openapi []byte
+	err     error
+}
+
+// NewDocsHandler points the generated spec at this environment's host and
+// renders the OpenAPI document once, as neither changes while running
+func NewDocsHandler(cfg *config.DocsConfig) *DocsHandler {
+	docs.Configure(cfg)
+	doc, err := docs.OpenAPI()
+	if err != nil {
+		log.Printf("OpenAPI document unavailable: %v", err)
+	}
+	return &DocsHandler{openapi: doc, err: err}
+}
+
+// OpenAPI godoc
+// @Summary OpenAPI 3 document
+// @Description The API as an OpenAPI 3.0 document for client generators, converted from the Swagger spec served at /swagger/doc.json
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} response.Response
+// @Router /openapi.json [get]
+func (h *DocsHandler) OpenAPI(c *fiber.Ctx) error {
+	if h.err != nil {
+		return response.InternalError(c, h.err)
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	return c.Send(h.openapi)
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/equity"
+	"github.com/polygo/pkg/response"
+)
+
+// EquityHandler serves the equity curves of watched wallets
+type EquityHandler struct {
+	tracker *equity.Tracker
+}
+
+// NewEquityHandler creates a new equity curve handler
+func NewEquityHandler(t *equity.Tracker) *EquityHandler {
+	return &EquityHandler{tracker: t}
+}
+
+// GetEquityCurve godoc
+// @Summary Get a wallet's equity curve
+// @Description Get the daily portfolio snapshots of a watched wallet (on a watchlist or with a position alert) with per-period returns, total return, max drawdown and an annualized Sharpe ratio. A day's return is its change in PnL over the previous day's portfolio value.
+// @Tags User Data
+// @Accept json
+// @Produce json
+// @Param address path string true "Wallet address"
+// @Param period query string false "Return period: day, week or month" default(week)
+// @Param window query string false "Only snapshots within this window, e.g. 30d (default all)"
+// @Success 200 {object} response.Response{data=equity.Curve}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/portfolio/{address}/equity-curve [get]
+func (h *EquityHandler) GetEquityCurve(c *fiber.Ctx) error {
+	var since string
+	if w := c.Query("window"); w != "" {
+		window, err := parseWindow(w)
+		if err != nil || window <= 0 {
+			return response.BadRequest(c, "Invalid window, use a duration like 720h or 30d")
+		}
+		since = time.Now().UTC().Add(-window).Format("2006-01-02")
+	}
+
+	curve, ok, err := h.tracker.Curve(c.Params("address"), since, c.Query("period", equity.PeriodWeek))
+	if err != nil {
+		return response.BadRequest(c, "Invalid period, use day, week or month")
+	}
+	if !ok {
+		return response.NotFound(c, "No snapshots of this wallet; it is snapshotted daily once watched")
+	}
+	return response.Success(c, curve)
+}
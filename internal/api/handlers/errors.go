@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/pkg/response"
+)
+
+// errorResponse answers with the typed error for an upstream failure (see
+// polymarket.ClassifyError), or a 500 for anything else
+func errorResponse(c *fiber.Ctx, err error) error {
+	e := polymarket.ClassifyError(err)
+	if e == nil {
+		return response.InternalError(c, err)
+	}
+	if e.Retryable {
+		return response.Retry(c, e.Status, e.Code, e.Message, e.Details, e.RetryAfter)
+	}
+	return response.Error(c, e.Status, e.Code, e.Message, e.Details)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/polygo/internal/models"
 	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/shape"
 	"github.com/polygo/pkg/response"
 )
 
@@ -24,19 +25,24 @@ func NewEventsHandler(gamma *polymarket.GammaClient) *EventsHandler {
 // @Accept json
 // @Produce json
 // @Param limit query int false "Limit results" default(100)
-// @Param cursor query string false "Pagination cursor"
+// @Param cursor query string false "Pagination cursor (next_cursor or offset)"
 // @Param active query bool false "Filter by active status"
 // @Param closed query bool false "Filter by closed status"
 // @Param archived query bool false "Filter by archived status"
 // @Param slug query string false "Filter by slug"
 // @Param tag query string false "Filter by tag"
+// @Param offset query int false "Pagination offset"
+// @Param all query bool false "Auto-paginate through every page"
+// @Param max query int false "Maximum items when auto-paginating" default(1000)
+// @Param normalize query bool false "Parse stringified lists and numbers and use camelCase keys"
 // @Success 200 {object} response.Response{data=[]models.Event}
 // @Failure 500 {object} response.Response
 // @Router /api/v1/events [get]
 func (h *EventsHandler) GetEvents(c *fiber.Ctx) error {
 	params := &models.EventQueryParams{
 		Limit:  c.QueryInt("limit", 100),
-		Cursor: c.Query("cursor"),
+		Cursor: cursorParam(c),
+		Offset: c.QueryInt("offset", 0),
 		Slug:   c.Query("slug"),
 		Tag:    c.Query("tag"),
 	}
@@ -55,12 +61,22 @@ func (h *EventsHandler) GetEvents(c *fiber.Ctx) error {
 		params.Archived = &archived
 	}
 	
+	if c.QueryBool("all") {
+		result, err := h.gamma.GetAllEvents(params, autoPaginateMax(c))
+		if err != nil {
+			return errorResponse(c, err)
+		}
+		meta := allItemsMeta(c, result, pageOffset(params.Cursor, params.Offset), params.Limit)
+		return send(c, shape.Events, gammaItems(c, result.Items), meta)
+	}
+	
 	data, cacheHit, err := h.gamma.GetEvents(params)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.RawWithCacheHeader(c, data, cacheHit)
+	meta := pageMeta(c, data, pageOffset(params.Cursor, params.Offset), params.Limit)
+	return sendGamma(c, shape.Events, data, cacheHit, meta)
 }
 
 // GetEvent godoc
@@ -70,6 +86,7 @@ func (h *EventsHandler) GetEvents(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param id path string true "Event ID"
+// @Param normalize query bool false "Parse stringified lists and numbers and use camelCase keys"
 // @Success 200 {object} response.Response{data=models.Event}
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -82,14 +99,14 @@ func (h *EventsHandler) GetEvent(c *fiber.Ctx) error {
 	
 	data, cacheHit, err := h.gamma.GetEvent(id)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
 	if len(data) == 0 || string(data) == "null" {
 		return response.NotFound(c, "Event not found")
 	}
 	
-	return response.RawWithCacheHeader(c, data, cacheHit)
+	return sendGamma(c, shape.EventOne, data, cacheHit, nil)
 }
 
 // GetEventBySlug godoc
@@ -99,6 +116,7 @@ func (h *EventsHandler) GetEvent(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param slug path string true "Event slug"
+// @Param normalize query bool false "Parse stringified lists and numbers and use camelCase keys"
 // @Success 200 {object} response.Response{data=models.Event}
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -111,10 +129,10 @@ func (h *EventsHandler) GetEventBySlug(c *fiber.Ctx) error {
 	
 	data, cacheHit, err := h.gamma.GetEventBySlug(slug)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.RawWithCacheHeader(c, data, cacheHit)
+	return sendGamma(c, shape.EventOne, data, cacheHit, nil)
 }
 
 // SearchEvents godoc
@@ -125,6 +143,7 @@ func (h *EventsHandler) GetEventBySlug(c *fiber.Ctx) error {
 // @Produce json
 // @Param q query string true "Search query"
 // @Param limit query int false "Limit results" default(20)
+// @Param normalize query bool false "Parse stringified lists and numbers and use camelCase keys"
 // @Success 200 {object} response.Response{data=[]models.Event}
 // @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -139,8 +158,8 @@ func (h *EventsHandler) SearchEvents(c *fiber.Ctx) error {
 	
 	data, cacheHit, err := h.gamma.SearchEvents(query, limit)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.RawWithCacheHeader(c, data, cacheHit)
+	return sendGamma(c, shape.Events, data, cacheHit, nil)
 }
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/export"
+	"github.com/polygo/pkg/response"
+)
+
+// ExportsHandler manages scheduled data export jobs
+type ExportsHandler struct {
+	scheduler *export.Scheduler
+}
+
+// NewExportsHandler creates a new export jobs handler
+func NewExportsHandler(s *export.Scheduler) *ExportsHandler {
+	return &ExportsHandler{scheduler: s}
+}
+
+// ListJobs godoc
+// @Summary List export jobs
+// @Description List scheduled export jobs with their next and last run
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=[]export.Job}
+// @Failure 401 {object} response.Response
+// @Router /admin/exports [get]
+func (h *ExportsHandler) ListJobs(c *fiber.Ctx) error {
+	return response.Success(c, h.scheduler.List())
+}
+
+// CreateJob godoc
+// @Summary Create an export job
+// @Description Schedule a periodic export of a dataset (trades for an address, recorded candles for tokens, or the full market catalog) as CSV or Parquet under the export directory of the configured storage (local disk or a bucket). The first run is due immediately unless paused.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body export.Spec true "Job"
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=export.Job}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/exports [post]
+func (h *ExportsHandler) CreateJob(c *fiber.Ctx) error {
+	var spec export.Spec
+	if err := sonic.Unmarshal(c.Body(), &spec); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	job, err := h.scheduler.Create(spec)
+	if err != nil {
+		return exportError(c, err)
+	}
+	return response.Success(c, job)
+}
+
+// GetJob godoc
+// @Summary Get an export job
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=export.Job}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/exports/{id} [get]
+func (h *ExportsHandler) GetJob(c *fiber.Ctx) error {
+	job, err := h.scheduler.Get(c.Params("id"))
+	if err != nil {
+		return exportError(c, err)
+	}
+	return response.Success(c, job)
+}
+
+// UpdateJob godoc
+// @Summary Update an export job
+// @Description Replace a job's spec. The next run is rescheduled from the last one.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body export.Spec true "Job"
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=export.Job}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/exports/{id} [put]
+func (h *ExportsHandler) UpdateJob(c *fiber.Ctx) error {
+	var spec export.Spec
+	if err := sonic.Unmarshal(c.Body(), &spec); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	job, err := h.scheduler.Update(c.Params("id"), spec)
+	if err != nil {
+		return exportError(c, err)
+	}
+	return response.Success(c, job)
+}
+
+// DeleteJob godoc
+// @Summary Delete an export job
+// @Description Remove a job and its run history. Files already exported are kept.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Security AdminAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/exports/{id} [delete]
+func (h *ExportsHandler) DeleteJob(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if err := h.scheduler.Delete(id); err != nil {
+		return exportError(c, err)
+	}
+	return response.Success(c, fiber.Map{"deleted": id})
+}
+
+// ListRuns godoc
+// @Summary List an export job's runs
+// @Description Get a job's recent runs, newest first, with row counts, sizes, where each file was written and any error
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=[]export.Run}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/exports/{id}/runs [get]
+func (h *ExportsHandler) ListRuns(c *fiber.Ctx) error {
+	runs, err := h.scheduler.Runs(c.Params("id"))
+	if err != nil {
+		return exportError(c, err)
+	}
+	return response.Success(c, runs)
+}
+
+// RunJob godoc
+// @Summary Run an export job now
+// @Description Run a job immediately, even if paused, and wait for it to finish. A failed export is still a 200; see the run's status and error.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=export.Run}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/exports/{id}/run [post]
+func (h *ExportsHandler) RunJob(c *fiber.Ctx) error {
+	run, err := h.scheduler.RunNow(c.Params("id"))
+	if err != nil {
+		return exportError(c, err)
+	}
+	return response.Success(c, run)
+}
+
+// exportError maps scheduler errors to responses
+func exportError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, export.ErrInvalidJob):
+		return response.BadRequest(c, err.Error())
+	case errors.Is(err, export.ErrNotFound):
+		return response.NotFound(c, "Export job not found")
+	case errors.Is(err, export.ErrTooManyJobs):
+		return response.Error(c, fiber.StatusConflict, "TOO_MANY_EXPORT_JOBS", "Export job limit reached", "")
+	}
+	return errorResponse(c, err)
+}
@@ -1,42 +1,67 @@
 package handlers
 
 import (
+	"bytes"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/api/middleware"
 	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/eventbus"
+	"github.com/polygo/internal/latency"
 	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/wsbuffer"
 	"github.com/polygo/pkg/response"
 )
 
 // HealthHandler handles health check endpoints
 type HealthHandler struct {
-	cache     *cache.Cache
-	wsManager *polymarket.WSManager
-	startTime time.Time
+	cache           *cache.Cache
+	wsManager       *polymarket.WSManager
+	client          *polymarket.Client
+	drainer         *middleware.Drainer
+	shedder         *middleware.Shedder
+	prober          *polymarket.HealthProber
+	latency         *latency.Recorder
+	eventBus        *eventbus.Publisher
+	wsBuffers       *wsbuffer.Pool
+	goroutineBudget int
+	startTime       time.Time
 }
 
 // NewHealthHandler creates a new health handler
-func NewHealthHandler(c *cache.Cache, ws *polymarket.WSManager) *HealthHandler {
+func NewHealthHandler(c *cache.Cache, ws *polymarket.WSManager, client *polymarket.Client, drainer *middleware.Drainer, shedder *middleware.Shedder, prober *polymarket.HealthProber, recorder *latency.Recorder, bus *eventbus.Publisher, wsBuffers *wsbuffer.Pool, goroutineBudget int) *HealthHandler {
 	return &HealthHandler{
-		cache:     c,
-		wsManager: ws,
-		startTime: time.Now(),
+		cache:           c,
+		wsManager:       ws,
+		client:          client,
+		drainer:         drainer,
+		shedder:         shedder,
+		prober:          prober,
+		latency:         recorder,
+		eventBus:        bus,
+		wsBuffers:       wsBuffers,
+		goroutineBudget: goroutineBudget,
+		startTime:       time.Now(),
 	}
 }
 
 // HealthResponse represents health check response
 type HealthResponse struct {
-	Status    string            `json:"status"`
-	Uptime    string            `json:"uptime"`
-	Timestamp int64             `json:"timestamp"`
-	Services  map[string]string `json:"services"`
+	Status       string                            `json:"status"`
+	Uptime       string                            `json:"uptime"`
+	Timestamp    int64                             `json:"timestamp"`
+	Services     map[string]string                 `json:"services"`
+	Dependencies map[string]polymarket.ProbeResult `json:"dependencies,omitempty"`
+	Feeds        map[string]int64                  `json:"feeds,omitempty"` // last upstream WS message per channel, unix ms
+	Failover     []polymarket.FailoverStatus       `json:"failover,omitempty"` // APIs with mirrors
 }
 
 // Health godoc
 // @Summary Health check
-// @Description Check if the server is running
+// @Description Check if the server is running and probe upstream API reachability
 // @Tags Health
 // @Accept json
 // @Produce json
@@ -47,17 +72,55 @@ func (h *HealthHandler) Health(c *fiber.Ctx) error {
 		"cache": "healthy",
 	}
 	
-	if h.wsManager.IsConnected() {
+	if h.wsManager.Replaying() {
+		services["websocket"] = "replaying"
+	} else if h.wsManager.IsConnected() {
 		services["websocket"] = "connected"
+		for _, shard := range h.wsManager.Shards() {
+			if !shard.Connected {
+				services["websocket"] = "degraded"
+				break
+			}
+		}
 	} else {
 		services["websocket"] = "disconnected"
 	}
 	
+	status := "healthy"
+	var deps map[string]polymarket.ProbeResult
+	if h.prober != nil {
+		deps = h.prober.Check()
+		for name, dep := range deps {
+			if dep.Healthy {
+				services[name] = "reachable"
+			} else {
+				services[name] = "unreachable"
+				status = "degraded"
+			}
+		}
+	}
+	
+	// Served by a mirror: up, but not as configured
+	failover := h.client.Failover()
+	for _, api := range failover {
+		if api.FailedOver {
+			status = "degraded"
+		}
+	}
+	
+	feeds := make(map[string]int64)
+	for channel, at := range h.wsManager.LastMessages() {
+		feeds[string(channel)] = at.UnixMilli()
+	}
+	
 	resp := HealthResponse{
-		Status:    "healthy",
-		Uptime:    time.Since(h.startTime).String(),
-		Timestamp: time.Now().UnixMilli(),
-		Services:  services,
+		Status:       status,
+		Uptime:       time.Since(h.startTime).String(),
+		Timestamp:    time.Now().UnixMilli(),
+		Services:     services,
+		Dependencies: deps,
+		Feeds:        feeds,
+		Failover:     failover,
 	}
 	
 	return response.Success(c, resp)
@@ -80,9 +143,19 @@ type ReadyResponse struct {
 // @Failure 503 {object} ReadyResponse
 // @Router /ready [get]
 func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	// Fail readiness while draining so load balancers stop routing to us
+	if h.drainer != nil && h.drainer.IsDraining() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(ReadyResponse{
+			Ready:     false,
+			Message:   "Server is shutting down",
+			Timestamp: time.Now().UnixMilli(),
+		})
+	}
+	
 	// Check if cache is working
 	testKey := "__ready_check__"
 	h.cache.Set(testKey, []byte("ok"), time.Second)
+	h.cache.Wait() // sets are buffered
 	_, found := h.cache.Get(testKey)
 	h.cache.Delete(testKey)
 	
@@ -94,6 +167,17 @@ func (h *HealthHandler) Ready(c *fiber.Ctx) error {
 		})
 	}
 	
+	// Fail once an upstream has been unreachable beyond the threshold
+	if h.prober != nil {
+		if down := h.prober.Unreachable(); len(down) > 0 {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(ReadyResponse{
+				Ready:     false,
+				Message:   "Upstream unreachable: " + strings.Join(down, ","),
+				Timestamp: time.Now().UnixMilli(),
+			})
+		}
+	}
+	
 	return response.Success(c, ReadyResponse{
 		Ready:     true,
 		Timestamp: time.Now().UnixMilli(),
@@ -105,11 +189,20 @@ type StatsResponse struct {
 	Uptime       string  `json:"uptime"`
 	GoVersion    string  `json:"go_version"`
 	NumGoroutine int     `json:"num_goroutine"`
+	GoroutineBudget     int  `json:"goroutine_budget"`      // 0 = unchecked
+	OverGoroutineBudget bool `json:"over_goroutine_budget"` // num_goroutine exceeds the budget, likely a leak
 	NumCPU       int     `json:"num_cpu"`
 	MemAlloc     uint64  `json:"mem_alloc_bytes"`
 	MemTotal     uint64  `json:"mem_total_bytes"`
 	MemSys       uint64  `json:"mem_sys_bytes"`
 	CacheHitRate float64 `json:"cache_hit_rate"`
+	CacheSizes   cache.SizeStats `json:"cache_sizes"`
+	WSShards     []polymarket.ShardStatus `json:"ws_shards"`
+	UpstreamPools []polymarket.PoolStats  `json:"upstream_pools"` // HTTP connections per upstream host
+	Routes       []latency.RouteStats     `json:"routes"` // latency percentiles per route, busiest first
+	EventBus     *eventbus.Stats          `json:"event_bus,omitempty"` // set when the event bus is enabled
+	LoadShedding middleware.ShedStats     `json:"load_shedding"`
+	WSBuffers    wsbuffer.Stats           `json:"ws_buffers"` // frames queued for WebSocket clients
 	Timestamp    int64   `json:"timestamp"`
 }
 
@@ -129,13 +222,42 @@ func (h *HealthHandler) Stats(c *fiber.Ctx) error {
 		Uptime:       time.Since(h.startTime).String(),
 		GoVersion:    runtime.Version(),
 		NumGoroutine: runtime.NumGoroutine(),
+		GoroutineBudget: h.goroutineBudget,
 		NumCPU:       runtime.NumCPU(),
 		MemAlloc:     mem.Alloc,
 		MemTotal:     mem.TotalAlloc,
 		MemSys:       mem.Sys,
 		CacheHitRate: h.cache.HitRatio(),
+		CacheSizes:   h.cache.SizeStats(),
+		WSShards:     h.wsManager.Shards(),
+		UpstreamPools: h.client.PoolStats(),
+		Routes:       h.latency.Stats(),
+		LoadShedding: h.shedder.Stats(),
+		WSBuffers:    h.wsBuffers.Stats(),
 		Timestamp:    time.Now().UnixMilli(),
 	}
+	resp.OverGoroutineBudget = h.goroutineBudget > 0 && resp.NumGoroutine > h.goroutineBudget
+	if h.eventBus != nil && h.eventBus.Enabled() {
+		stats := h.eventBus.Stats()
+		resp.EventBus = &stats
+	}
 	
 	return response.Success(c, resp)
 }
+
+// Metrics godoc
+// @Summary Prometheus metrics
+// @Description Per-route request latency and upstream wait histograms, estimated p50/p95/p99, 5xx counts and WebSocket send buffer gauges in the Prometheus text format
+// @Tags Health
+// @Produce plain
+// @Success 200 {string} string
+// @Router /metrics [get]
+func (h *HealthHandler) Metrics(c *fiber.Ctx) error {
+	var buf bytes.Buffer
+	h.latency.WritePrometheus(&buf)
+	h.wsBuffers.WritePrometheus(&buf)
+	h.wsManager.WritePrometheus(&buf)
+	
+	c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+	return c.Send(buf.Bytes())
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/leaderboard"
+	"github.com/polygo/pkg/response"
+)
+
+// LeaderboardHandler serves the recorded leaderboard history
+type LeaderboardHandler struct {
+	tracker *leaderboard.Tracker
+}
+
+// NewLeaderboardHandler creates a new leaderboard history handler
+func NewLeaderboardHandler(t *leaderboard.Tracker) *LeaderboardHandler {
+	return &LeaderboardHandler{tracker: t}
+}
+
+// GetHistory godoc
+// @Summary Get leaderboard history
+// @Description Get the traders on the latest leaderboard snapshot with their rank change and PnL trajectory over a window
+// @Tags User Data
+// @Accept json
+// @Produce json
+// @Param window query string false "Window, e.g. 24h or 7d" default(7d)
+// @Param limit query int false "Limit results" default(100)
+// @Success 200 {object} response.Response{data=[]leaderboard.TraderHistory}
+// @Failure 400 {object} response.Response
+// @Router /api/v1/leaderboard/history [get]
+func (h *LeaderboardHandler) GetHistory(c *fiber.Ctx) error {
+	window, err := h.window(c)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.Success(c, h.tracker.History(window, c.QueryInt("limit", 100)))
+}
+
+// GetTrader godoc
+// @Summary Get a trader's leaderboard history
+// @Description Get a single trader's rank and PnL trajectory over a window, including after they drop off the leaderboard
+// @Tags User Data
+// @Accept json
+// @Produce json
+// @Param address path string true "Trader wallet address"
+// @Param window query string false "Window, e.g. 24h or 7d" default(7d)
+// @Success 200 {object} response.Response{data=leaderboard.TraderHistory}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/leaderboard/trader/{address} [get]
+func (h *LeaderboardHandler) GetTrader(c *fiber.Ctx) error {
+	window, err := h.window(c)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	history, ok := h.tracker.Trader(c.Params("address"), window)
+	if !ok {
+		return response.NotFound(c, "Trader not ranked in this window")
+	}
+	return response.Success(c, history)
+}
+
+// window parses ?window, which must fit within the recorded history
+func (h *LeaderboardHandler) window(c *fiber.Ctx) (time.Duration, error) {
+	window, err := parseWindow(c.Query("window", "7d"))
+	if err != nil || window <= 0 {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "Invalid window, use a duration like 24h or 7d")
+	}
+	if window > h.tracker.Retention() {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "Window exceeds recorded history of "+h.tracker.Retention().String())
+	}
+	return window, nil
+}
+
+// parseWindow parses a Go duration, also accepting whole days ("7d")
+func parseWindow(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/websocket/v2"
+	"github.com/polygo/internal/listings"
+	"github.com/polygo/internal/wsbuffer"
+)
+
+// ListingsHandler streams newly listed markets
+type ListingsHandler struct {
+	feed    *listings.Feed
+	buffers *wsbuffer.Pool
+}
+
+// NewListingsHandler creates a new listings handler; frames waiting for
+// slow clients are held in buffers
+func NewListingsHandler(feed *listings.Feed, buffers *wsbuffer.Pool) *ListingsHandler {
+	return &ListingsHandler{feed: feed, buffers: buffers}
+}
+
+// HandleListingsWS streams markets as the catalog discovers them
+// @Summary New market listings WebSocket
+// @Description Streams a "market_listed" frame ({"type", "market"}) for each market a catalog sync finds for the first time. The same payload is delivered to webhooks subscribed to market.listed.
+// @Tags WebSocket
+// @Param tags query string false "Comma-separated tag slugs; only markets carrying one of them are sent"
+// @Param encoding query string false "Downstream encoding: json (default) or msgpack for binary frames"
+// @Router /ws/listings [get]
+func (h *ListingsHandler) HandleListingsWS(c *websocket.Conn) {
+	var tags []string
+	if q := c.Query("tags"); q != "" {
+		tags = strings.Split(q, ",")
+	}
+
+	out := h.buffers.Add(c)
+	defer out.Close()
+
+	h.feed.Subscribe(out, connEncoding(c), tags)
+	defer h.feed.Unsubscribe(out)
+
+	serveFeed(c, out)
+}
@@ -1,45 +1,69 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/catalog"
 	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/normalize"
 	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/shape"
 	"github.com/polygo/pkg/response"
 )
 
 // MarketsHandler handles market-related endpoints
 type MarketsHandler struct {
-	gamma *polymarket.GammaClient
+	gamma   *polymarket.GammaClient
+	details *polymarket.MarketDetailService
+	catalog *catalog.Catalog
 }
 
 // NewMarketsHandler creates a new markets handler
-func NewMarketsHandler(gamma *polymarket.GammaClient) *MarketsHandler {
-	return &MarketsHandler{gamma: gamma}
+func NewMarketsHandler(gamma *polymarket.GammaClient, details *polymarket.MarketDetailService, cat *catalog.Catalog) *MarketsHandler {
+	return &MarketsHandler{gamma: gamma, details: details, catalog: cat}
 }
 
 // GetMarkets godoc
 // @Summary List all markets
-// @Description Get a list of markets with optional filtering
+// @Description Get a list of markets with optional filtering. Volume, liquidity and end date filters on open markets are served from the local catalog; other queries go to Gamma.
 // @Tags Markets
 // @Accept json
 // @Produce json
 // @Param limit query int false "Limit results" default(100)
-// @Param cursor query string false "Pagination cursor"
+// @Param cursor query string false "Pagination cursor (next_cursor or offset)"
 // @Param active query bool false "Filter by active status"
 // @Param closed query bool false "Filter by closed status"
 // @Param slug query string false "Filter by slug"
 // @Param event_slug query string false "Filter by event slug"
 // @Param clob_token_id query string false "Filter by CLOB token ID"
+// @Param offset query int false "Pagination offset"
+// @Param min_volume query number false "Minimum total volume"
+// @Param min_liquidity query number false "Minimum liquidity"
+// @Param end_date_before query string false "End date before (RFC 3339 or YYYY-MM-DD)"
+// @Param end_date_after query string false "End date after (RFC 3339 or YYYY-MM-DD)"
+// @Param order query string false "Sort field: volume, volume24hr, liquidity, spread or end_date"
+// @Param ascending query bool false "Sort ascending (default descending)"
+// @Param all query bool false "Auto-paginate through every page"
+// @Param max query int false "Maximum items when auto-paginating" default(1000)
+// @Param normalize query bool false "Parse stringified lists and numbers and use camelCase keys"
 // @Success 200 {object} response.Response{data=[]models.Market}
+// @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/markets [get]
 func (h *MarketsHandler) GetMarkets(c *fiber.Ctx) error {
 	params := &models.MarketQueryParams{
 		Limit:       c.QueryInt("limit", 100),
-		Cursor:      c.Query("cursor"),
+		Cursor:      cursorParam(c),
+		Offset:      c.QueryInt("offset", 0),
 		Slug:        c.Query("slug"),
 		EventSlug:   c.Query("event_slug"),
 		ClobTokenID: c.Query("clob_token_id"),
+		Order:       c.Query("order"),
 	}
 	
 	// Handle bool pointers
@@ -51,13 +75,36 @@ func (h *MarketsHandler) GetMarkets(c *fiber.Ctx) error {
 		closed := c.QueryBool("closed")
 		params.Closed = &closed
 	}
+	if c.Query("ascending") != "" {
+		ascending := c.QueryBool("ascending")
+		params.Ascending = &ascending
+	}
+	if err := conditionalParams(c, params); err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+	
+	if h.servesLocally(params) {
+		matches := h.catalog.Filter(params)
+		offset := pageOffset(params.Cursor, params.Offset)
+		return send(c, shape.Markets, paginate(matches, offset, params.Limit), listMeta(c, offset, params.Limit, len(matches)))
+	}
+	
+	if c.QueryBool("all") {
+		result, err := h.gamma.GetAllMarkets(params, autoPaginateMax(c))
+		if err != nil {
+			return errorResponse(c, err)
+		}
+		meta := allItemsMeta(c, result, pageOffset(params.Cursor, params.Offset), params.Limit)
+		return send(c, shape.Markets, gammaItems(c, result.Items), meta)
+	}
 	
 	data, cacheHit, err := h.gamma.GetMarkets(params)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.RawWithCacheHeader(c, data, cacheHit)
+	meta := pageMeta(c, data, pageOffset(params.Cursor, params.Offset), params.Limit)
+	return sendGamma(c, shape.Markets, data, cacheHit, meta)
 }
 
 // GetMarket godoc
@@ -67,6 +114,7 @@ func (h *MarketsHandler) GetMarkets(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param id path string true "Market ID"
+// @Param normalize query bool false "Parse stringified lists and numbers and use camelCase keys"
 // @Success 200 {object} response.Response{data=models.Market}
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -79,14 +127,43 @@ func (h *MarketsHandler) GetMarket(c *fiber.Ctx) error {
 	
 	data, cacheHit, err := h.gamma.GetMarket(id)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
 	if len(data) == 0 || string(data) == "null" {
 		return response.NotFound(c, "Market not found")
 	}
 	
-	return response.RawWithCacheHeader(c, data, cacheHit)
+	return sendGamma(c, shape.MarketOne, data, cacheHit, nil)
+}
+
+// GetMarketFull godoc
+// @Summary Get full market detail
+// @Description Get the market record, per-outcome book summary (bid, ask, mid, spread, depth, last trade), 24h volume and recent trades in one cached response
+// @Tags Markets
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Success 200 {object} response.Response{data=polymarket.MarketDetail}
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/markets/{id}/full [get]
+func (h *MarketsHandler) GetMarketFull(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return response.BadRequest(c, "Market ID is required")
+	}
+	
+	detail, cacheHit, err := h.details.Get(id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if detail == nil {
+		return response.NotFound(c, "Market not found")
+	}
+	
+	cacheHeader(c, cacheHit)
+	return send(c, shape.Detail, detail, nil)
 }
 
 // GetMarketBySlug godoc
@@ -96,6 +173,7 @@ func (h *MarketsHandler) GetMarket(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param slug path string true "Market slug"
+// @Param normalize query bool false "Parse stringified lists and numbers and use camelCase keys"
 // @Success 200 {object} response.Response{data=models.Market}
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -108,10 +186,10 @@ func (h *MarketsHandler) GetMarketBySlug(c *fiber.Ctx) error {
 	
 	data, cacheHit, err := h.gamma.GetMarketBySlug(slug)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.RawWithCacheHeader(c, data, cacheHit)
+	return sendGamma(c, shape.MarketOne, data, cacheHit, nil)
 }
 
 // GetMarketByToken godoc
@@ -121,6 +199,7 @@ func (h *MarketsHandler) GetMarketBySlug(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param token_id path string true "CLOB Token ID"
+// @Param normalize query bool false "Parse stringified lists and numbers and use camelCase keys"
 // @Success 200 {object} response.Response{data=models.Market}
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -133,8 +212,109 @@ func (h *MarketsHandler) GetMarketByToken(c *fiber.Ctx) error {
 	
 	data, cacheHit, err := h.gamma.GetMarketByClobTokenID(tokenID)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.RawWithCacheHeader(c, data, cacheHit)
+	return sendGamma(c, shape.MarketOne, data, cacheHit, nil)
+}
+
+// servesLocally reports whether a market listing is answered from the
+// catalog: it must filter on volume, liquidity or end date, and ask only
+// for open markets, which are all the catalog holds
+func (h *MarketsHandler) servesLocally(params *models.MarketQueryParams) bool {
+	if !params.Conditional() || h.catalog.Status().LastSync.IsZero() {
+		return false
+	}
+	if params.Closed != nil && *params.Closed {
+		return false
+	}
+	return params.Active == nil || *params.Active
+}
+
+// conditionalParams parses the volume, liquidity and end date filters and
+// checks the sort order
+func conditionalParams(c *fiber.Ctx, params *models.MarketQueryParams) error {
+	var err error
+	if params.MinVolume, err = queryFloat(c, "min_volume"); err != nil {
+		return err
+	}
+	if params.MinLiquidity, err = queryFloat(c, "min_liquidity"); err != nil {
+		return err
+	}
+	if params.EndDateBefore, err = queryDate(c, "end_date_before"); err != nil {
+		return err
+	}
+	if params.EndDateAfter, err = queryDate(c, "end_date_after"); err != nil {
+		return err
+	}
+	if !catalog.ValidOrder(params.Order) {
+		return errors.New("order must be volume, volume24hr, liquidity, spread or end_date")
+	}
+	return nil
+}
+
+// queryFloat parses an optional non-negative number query parameter
+func queryFloat(c *fiber.Ctx, name string) (float64, error) {
+	s := c.Query(name)
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative number", name)
+	}
+	return v, nil
+}
+
+// queryDate parses an optional RFC 3339 timestamp or YYYY-MM-DD date
+func queryDate(c *fiber.Ctx, name string) (time.Time, error) {
+	s := c.Query(name)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%s must be an RFC 3339 timestamp or YYYY-MM-DD date", name)
+}
+
+// autoPaginateMax returns the item cap for auto-paginated listings
+func autoPaginateMax(c *fiber.Ctx) int {
+	max := c.QueryInt("max", 1000)
+	if max <= 0 || max > 10000 {
+		max = 10000
+	}
+	return max
+}
+
+// sendGamma relays a Gamma payload. On v1 it is normalized when
+// ?normalize=true; v2 always is.
+func sendGamma(c *fiber.Ctx, s shape.Shaper, data []byte, cacheHit bool, meta *response.Meta) error {
+	cacheHeader(c, cacheHit)
+	if c.QueryBool("normalize") && !typed(c) {
+		normalized, err := normalize.JSON(data)
+		if err != nil {
+			return errorResponse(c, err)
+		}
+		data = normalized
+	}
+	return relay(c, s, data, meta)
+}
+
+// gammaItems normalizes auto-paginated items when ?normalize=true
+func gammaItems(c *fiber.Ctx, items []json.RawMessage) []json.RawMessage {
+	if !c.QueryBool("normalize") {
+		return items
+	}
+	out := make([]json.RawMessage, len(items))
+	for i, item := range items {
+		if normalized, err := normalize.JSON(item); err == nil {
+			item = normalized
+		}
+		out[i] = item
+	}
+	return out
 }
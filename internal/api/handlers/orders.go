@@ -1,27 +1,119 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	
 	"github.com/bytedance/sonic"
 	"github.com/gofiber/fiber/v2"
 	"github.com/polygo/internal/api/middleware"
 	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/expiry"
+	"github.com/polygo/internal/fillnotify"
 	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/pairs"
 	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/shape"
+	"github.com/polygo/internal/strategies"
+	"github.com/polygo/internal/webhooks"
+	"github.com/polygo/pkg/polygoclient"
 	"github.com/polygo/pkg/response"
 )
 
 // OrdersHandler handles order-related endpoints
 type OrdersHandler struct {
 	clob       *polymarket.ClobClient
+	data       *polymarket.DataClient
 	authConfig *config.AuthConfig
+	webhooks   *webhooks.Dispatcher
+	fills      *polymarket.FillTracker
+	expiry     *expiry.Tracker
+	notifier   *fillnotify.Notifier
+	strategies *strategies.Tracker
+	pairs      *pairs.Manager
 }
 
 // NewOrdersHandler creates a new orders handler
-func NewOrdersHandler(clob *polymarket.ClobClient, authConfig *config.AuthConfig) *OrdersHandler {
+func NewOrdersHandler(clob *polymarket.ClobClient, data *polymarket.DataClient, authConfig *config.AuthConfig, dispatcher *webhooks.Dispatcher, fills *polymarket.FillTracker, expiries *expiry.Tracker, notifier *fillnotify.Notifier, tracker *strategies.Tracker, pairManager *pairs.Manager) *OrdersHandler {
 	return &OrdersHandler{
 		clob:       clob,
+		data:       data,
 		authConfig: authConfig,
+		webhooks:   dispatcher,
+		fills:      fills,
+		expiry:     expiries,
+		notifier:   notifier,
+		strategies: tracker,
+		pairs:      pairManager,
+	}
+}
+
+// orderFilled drops the wallet's cached user data and announces the fill
+func (h *OrdersHandler) orderFilled(c *fiber.Ctx, orderID, address string) {
+	if h.data != nil {
+		h.data.InvalidateUser(address)
+	}
+	if h.webhooks != nil {
+		h.webhooks.Publish("order.filled", middleware.GetRequestID(c), callerKey(c), fiber.Map{
+			"order_id": orderID,
+			"maker":    address,
+		})
+	}
+}
+
+// orderState is the subset of a CLOB order needed to detect fills
+type orderState struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+	SizeMatched string `json:"size_matched"`
+}
+
+// observeOrders feeds order states returned by the CLOB (a single order,
+// a list, or a {data: [...]} page) to the fill tracker
+func (h *OrdersHandler) observeOrders(c *fiber.Ctx, data []byte) {
+	var orders []orderState
+	if err := sonic.Unmarshal(data, &orders); err != nil {
+		var page struct {
+			Data []orderState `json:"data"`
+		}
+		if err := sonic.Unmarshal(data, &page); err == nil && len(page.Data) > 0 {
+			orders = page.Data
+		} else {
+			var order orderState
+			if err := sonic.Unmarshal(data, &order); err != nil {
+				return
+			}
+			orders = []orderState{order}
+		}
+	}
+	
+	for _, o := range orders {
+		switch strings.ToUpper(o.Status) {
+		case "MATCHED":
+			h.expiry.Forget(o.ID)
+		case "CANCELLED", "CANCELED":
+			h.expiry.Forget(o.ID)
+			h.notifier.Forget(o.ID)
+		}
+		if address, filled := h.fills.Observe(o.ID, o.Status, o.SizeMatched); filled {
+			h.orderFilled(c, o.ID, address)
+		}
+	}
+}
+
+// publish emits an order event to webhooks, tagged with the request ID
+func (h *OrdersHandler) publish(c *fiber.Ctx, eventType string, data interface{}, upstream []byte) {
+	if h.webhooks == nil {
+		return
 	}
+	
+	h.webhooks.Publish(eventType, middleware.GetRequestID(c), callerKey(c), fiber.Map{
+		"request":  data,
+		"response": json.RawMessage(upstream),
+	})
 }
 
 // getAuthHeaders extracts auth headers from context
@@ -35,7 +127,7 @@ func (h *OrdersHandler) getAuthHeaders(c *fiber.Ctx) map[string]string {
 
 // CreateOrder godoc
 // @Summary Create a new order
-// @Description Place a new order on the market
+// @Description Place a new order on the market. Instead of a price, price_mode (mid, best_bid or best_ask, optionally ±N ticks, e.g. best_bid+1tick) prices the order against the live book; the result then carries price_mode and resolved_price.
 // @Tags Orders
 // @Accept json
 // @Produce json
@@ -52,23 +144,68 @@ func (h *OrdersHandler) CreateOrder(c *fiber.Ctx) error {
 		return response.BadRequest(c, "Invalid request body")
 	}
 	
-	// Validate required fields
-	if req.TokenID == "" {
-		return response.BadRequest(c, "Token ID is required")
+	defaultOrder(&req)
+	if err := h.expiry.Validate(&req, time.Now()); err != nil {
+		return response.BadRequest(c, capitalize(err.Error()))
+	}
+	
+	authHeaders := h.getAuthHeaders(c)
+	if authHeaders == nil {
+		return response.Unauthorized(c, "Authentication required")
 	}
-	if req.Price == "" {
-		return response.BadRequest(c, "Price is required")
+	
+	data, err := h.clob.CreateOrder(&req, authHeaders)
+	if err != nil {
+		return errorResponse(c, err)
 	}
-	if req.Size == "" {
-		return response.BadRequest(c, "Size is required")
+	
+	var placed models.PlacedOrder
+	if err := sonic.Unmarshal(data, &placed); err == nil && (placed.Success == nil || !*placed.Success) && placed.ErrorMsg != "" {
+		return errorResponse(c, polymarket.ClassifyRejection(placed.ErrorMsg))
 	}
-	if req.Side != models.SideBuy && req.Side != models.SideSell {
-		return response.BadRequest(c, "Side must be BUY or SELL")
+	
+	h.publish(c, "order.created", req, data)
+	if placed.OrderID != "" {
+		h.trackPlaced(c, &req, placed)
 	}
 	
-	// Default order type
-	if req.Type == "" {
-		req.Type = models.OrderTypeGTC
+	data = withResolvedPrices(data, []models.CreateOrderRequest{req})
+	return relay(c, shape.Placed, data, nil)
+}
+
+// CreateOrders godoc
+// @Summary Create multiple orders
+// @Description Place up to 15 orders in one upstream request. The whole batch passes or fails pre-trade risk checks together.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param orders body []models.CreateOrderRequest true "Orders"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/orders/batch [post]
+func (h *OrdersHandler) CreateOrders(c *fiber.Ctx) error {
+	var reqs []models.CreateOrderRequest
+	if err := sonic.Unmarshal(c.Body(), &reqs); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+	
+	if len(reqs) == 0 {
+		return response.BadRequest(c, "At least one order is required")
+	}
+	if len(reqs) > maxBatchOrders {
+		return response.BadRequest(c, "At most "+strconv.Itoa(maxBatchOrders)+" orders are allowed per batch")
+	}
+	now := time.Now()
+	for i := range reqs {
+		defaultOrder(&reqs[i])
+		if err := h.expiry.Validate(&reqs[i], now); err != nil {
+			msg := capitalize(err.Error())
+			return response.Error(c, fiber.StatusBadRequest, "BAD_REQUEST", msg, "orders["+strconv.Itoa(i)+"]")
+		}
 	}
 	
 	authHeaders := h.getAuthHeaders(c)
@@ -76,12 +213,303 @@ func (h *OrdersHandler) CreateOrder(c *fiber.Ctx) error {
 		return response.Unauthorized(c, "Authentication required")
 	}
 	
-	data, err := h.clob.CreateOrder(&req, authHeaders)
+	data, err := h.clob.CreateOrders(reqs, authHeaders)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	
+	h.publish(c, "order.created", reqs, data)
+	
+	// Results come back in request order
+	var placed []models.PlacedOrder
+	if err := sonic.Unmarshal(data, &placed); err == nil {
+		for i, p := range placed {
+			if i < len(reqs) {
+				h.trackPlaced(c, &reqs[i], p)
+			}
+		}
+	}
+	
+	data = withResolvedPrices(data, reqs)
+	return relay(c, shape.PlacedAll, data, nil)
+}
+
+// PairRequest represents a request to place two coordinated orders
+type PairRequest struct {
+	Legs []models.CreateOrderRequest `json:"legs" validate:"len=2,dive"`
+}
+
+// CreateOrderPair godoc
+// @Summary Place an order pair
+// @Description Place two coordinated orders (e.g. buy YES@0.40 and NO@0.55, or legs in two markets) in one upstream request. If one leg is rejected the other is cancelled; a leg that already filled cannot be, and the pair is reported as broken. Both legs pass pre-trade risk checks together.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param pair body PairRequest true "The two legs"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Response{data=pairs.Pair}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/orders/pair [post]
+func (h *OrdersHandler) CreateOrderPair(c *fiber.Ctx) error {
+	var req PairRequest
+	if err := sonic.Unmarshal(c.Body(), &req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+	
+	if len(req.Legs) != 2 {
+		return response.BadRequest(c, "Exactly two legs are required")
+	}
+	now := time.Now()
+	for i := range req.Legs {
+		defaultOrder(&req.Legs[i])
+		if err := h.expiry.Validate(&req.Legs[i], now); err != nil {
+			msg := capitalize(err.Error())
+			return response.Error(c, fiber.StatusBadRequest, "BAD_REQUEST", msg, "legs["+strconv.Itoa(i)+"]")
+		}
+	}
+	
+	authHeaders := h.getAuthHeaders(c)
+	if authHeaders == nil {
+		return response.Unauthorized(c, "Authentication required")
+	}
+	
+	pair, err := h.pairs.Place(callerKey(c), [2]models.CreateOrderRequest{req.Legs[0], req.Legs[1]}, authHeaders)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	
+	for i := range pair.Legs {
+		leg := &pair.Legs[i]
+		if leg.OrderID != "" && leg.Status != pairs.LegCancelled {
+			h.trackPlaced(c, &leg.Order, models.PlacedOrder{OrderID: leg.OrderID, Status: leg.Status})
+		}
+	}
+	h.publishPair(c, "order.pair_created", pair)
+	
+	return response.Success(c, pair)
+}
+
+// GetOrderPairs godoc
+// @Summary List order pairs
+// @Description List the caller's order pairs, newest first, as last recorded
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Response{data=[]pairs.Pair}
+// @Failure 401 {object} response.Response
+// @Router /api/v1/orders/pair [get]
+func (h *OrdersHandler) GetOrderPairs(c *fiber.Ctx) error {
+	owner := callerKey(c)
+	if owner == "" {
+		return response.Unauthorized(c, "Authentication required")
+	}
+	
+	return response.Success(c, h.pairs.List(owner))
+}
+
+// GetOrderPair godoc
+// @Summary Get an order pair
+// @Description Get one of the caller's order pairs with its open legs refreshed from the CLOB
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Pair ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Response{data=pairs.Pair}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/orders/pair/{id} [get]
+func (h *OrdersHandler) GetOrderPair(c *fiber.Ctx) error {
+	authHeaders := h.getAuthHeaders(c)
+	if authHeaders == nil {
+		return response.Unauthorized(c, "Authentication required")
+	}
+	
+	pair, err := h.pairs.Refresh(callerKey(c), c.Params("id"), authHeaders)
+	if errors.Is(err, pairs.ErrNotFound) {
+		return response.NotFound(c, "Pair not found")
+	}
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	
+	return response.Success(c, pair)
+}
+
+// CancelOrderPair godoc
+// @Summary Cancel an order pair
+// @Description Cancel both legs of one of the caller's order pairs. Legs that already filled are left as they are.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Pair ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Response{data=pairs.Pair}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/orders/pair/{id} [delete]
+func (h *OrdersHandler) CancelOrderPair(c *fiber.Ctx) error {
+	authHeaders := h.getAuthHeaders(c)
+	if authHeaders == nil {
+		return response.Unauthorized(c, "Authentication required")
+	}
+	
+	pair, err := h.pairs.Cancel(callerKey(c), c.Params("id"), authHeaders)
+	if errors.Is(err, pairs.ErrNotFound) {
+		return response.NotFound(c, "Pair not found")
+	}
+	
+	for _, leg := range pair.Legs {
+		if leg.Status == pairs.LegCancelled {
+			h.expiry.Forget(leg.OrderID)
+			h.notifier.Forget(leg.OrderID)
+		}
+	}
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
+	}
+	h.publishPair(c, "order.pair_cancelled", pair)
+	
+	return response.Success(c, pair)
+}
+
+// publishPair emits a pair event to webhooks, tagged with the request ID
+func (h *OrdersHandler) publishPair(c *fiber.Ctx, eventType string, pair pairs.Pair) {
+	if h.webhooks != nil {
+		h.webhooks.Publish(eventType, middleware.GetRequestID(c), callerKey(c), pair)
+	}
+}
+
+// maxBatchOrders is the most orders the CLOB accepts in one batch
+const maxBatchOrders = 15
+
+// defaultOrder fills in optional fields; the order's validate tags are
+// checked by middleware.ValidateBody before the handler runs
+func defaultOrder(req *models.CreateOrderRequest) {
+	if req.Type == "" {
+		req.Type = models.OrderTypeGTC
+	}
+}
+
+// trackPlaced invalidates the maker's cached data on an immediate fill,
+// tracks resting orders for later fills and, for GTD orders, expiry, and
+// attributes tagged orders to their strategy
+func (h *OrdersHandler) trackPlaced(c *fiber.Ctx, req *models.CreateOrderRequest, placed models.PlacedOrder) {
+	matched := strings.EqualFold(placed.Status, "matched")
+	if req.Strategy != "" {
+		price, _ := strconv.ParseFloat(req.Price, 64)
+		size, _ := strconv.ParseFloat(req.Size, 64)
+		h.strategies.Record(strategies.Order{
+			ID:       placed.OrderID,
+			Owner:    callerKey(c),
+			Strategy: req.Strategy,
+			TokenID:  req.TokenID,
+			Side:     string(req.Side),
+			Price:    price,
+			Size:     size,
+			PlacedAt: time.Now(),
+		}, matched)
+	}
+	if req.Type == models.OrderTypeGTD && !matched {
+		h.expiry.Track(placed.OrderID, callerKey(c), req.Maker, time.Unix(req.Expiration, 0), callerCredentials(c))
+	}
+	if !matched {
+		h.notifier.Track(fillnotify.Order{
+			ID:        placed.OrderID,
+			RequestID: middleware.GetRequestID(c),
+			Owner:     callerKey(c),
+			Maker:     strings.ToLower(req.Maker),
+			Size:      req.Size,
+		}, callerCredentials(c))
+	}
+	
+	if req.Maker == "" {
+		return
+	}
+	if matched {
+		h.orderFilled(c, placed.OrderID, strings.ToLower(req.Maker))
+	} else {
+		h.fills.Track(placed.OrderID, req.Maker)
+	}
+}
+
+// withResolvedPrices adds to the CLOB's results, which come back in request
+// order, the price_mode of each order that had one and the price it
+// resolved to. data is returned as is when it does not parse.
+func withResolvedPrices(data []byte, orders []models.CreateOrderRequest) []byte {
+	var results []map[string]interface{}
+	single := sonic.Unmarshal(data, &results) != nil
+	if single {
+		var result map[string]interface{}
+		if err := sonic.Unmarshal(data, &result); err != nil {
+			return data
+		}
+		results = []map[string]interface{}{result}
+	}
+	
+	changed := false
+	for i, o := range orders {
+		if o.PriceMode != "" && i < len(results) && results[i] != nil {
+			results[i]["price_mode"] = o.PriceMode
+			results[i]["resolved_price"] = o.Price
+			changed = true
+		}
+	}
+	if !changed {
+		return data
+	}
+	
+	var v interface{} = results
+	if single {
+		v = results[0]
+	}
+	if out, err := sonic.Marshal(v); err == nil {
+		return out
+	}
+	return data
+}
+
+// callerCredentials returns the caller's L2 credentials when they include
+// the secret, which PolyGo needs to sign requests on their behalf
+func callerCredentials(c *fiber.Ctx) *polygoclient.Credentials {
+	creds := middleware.GetAuthCredentials(c)
+	if creds == nil || creds.APISecret == "" {
+		return nil
+	}
+	return &polygoclient.Credentials{APIKey: creds.APIKey, Secret: creds.APISecret, Passphrase: creds.Passphrase}
+}
+
+// capitalize turns an error message into a sentence-case API message
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// GetExpiringOrders godoc
+// @Summary List tracked GTD orders
+// @Description List the caller's GTD orders placed through PolyGo that are awaiting expiry, with when each will be cancelled. Orders placed without the API secret header are not cancelled automatically; their owner is only notified (order.expiring).
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Response{data=[]expiry.Order}
+// @Failure 401 {object} response.Response
+// @Router /api/v1/orders/expiring [get]
+func (h *OrdersHandler) GetExpiringOrders(c *fiber.Ctx) error {
+	owner := callerKey(c)
+	if owner == "" {
+		return response.Unauthorized(c, "Authentication required")
 	}
 	
-	return response.Raw(c, data)
+	return response.Success(c, h.expiry.Orders(owner))
 }
 
 // GetOrders godoc
@@ -92,6 +520,7 @@ func (h *OrdersHandler) CreateOrder(c *fiber.Ctx) error {
 // @Produce json
 // @Param market query string false "Filter by market"
 // @Param status query string false "Filter by status"
+// @Param cursor query string false "Pagination cursor"
 // @Security ApiKeyAuth
 // @Success 200 {object} response.Response{data=[]models.Order}
 // @Failure 401 {object} response.Response
@@ -110,13 +539,18 @@ func (h *OrdersHandler) GetOrders(c *fiber.Ctx) error {
 	if status := c.Query("status"); status != "" {
 		params["status"] = status
 	}
+	if cursor := cursorParam(c); cursor != "" {
+		params["next_cursor"] = cursor
+	}
 	
 	data, err := h.clob.GetOrders(params, authHeaders)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.Raw(c, data)
+	h.observeOrders(c, data)
+	
+	return relay(c, shape.Orders, data, pageMeta(c, data, 0, 0))
 }
 
 // GetOrder godoc
@@ -145,10 +579,12 @@ func (h *OrdersHandler) GetOrder(c *fiber.Ctx) error {
 	
 	data, err := h.clob.GetOrder(orderID, authHeaders)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.Raw(c, data)
+	h.observeOrders(c, data)
+	
+	return relay(c, shape.OrderOne, data, nil)
 }
 
 // GetOpenOrders godoc
@@ -173,10 +609,12 @@ func (h *OrdersHandler) GetOpenOrders(c *fiber.Ctx) error {
 	
 	data, err := h.clob.GetOpenOrders(market, authHeaders)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.Raw(c, data)
+	h.observeOrders(c, data)
+	
+	return relay(c, shape.Orders, data, nil)
 }
 
 // CancelOrder godoc
@@ -205,10 +643,14 @@ func (h *OrdersHandler) CancelOrder(c *fiber.Ctx) error {
 	
 	data, err := h.clob.CancelOrder(orderID, authHeaders)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.Raw(c, data)
+	h.expiry.Forget(orderID)
+	h.notifier.Forget(orderID)
+	h.publish(c, "order.cancelled", fiber.Map{"order_id": orderID}, data)
+	
+	return relay(c, shape.Cancelled, data, nil)
 }
 
 // CancelAllOrders godoc
@@ -237,10 +679,12 @@ func (h *OrdersHandler) CancelAllOrders(c *fiber.Ctx) error {
 	
 	data, err := h.clob.CancelAll(market, authHeaders)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.Raw(c, data)
+	h.publish(c, "order.cancelled_all", fiber.Map{"market": market}, data)
+	
+	return relay(c, shape.Cancelled, data, nil)
 }
 
 // GetTrades godoc
@@ -251,8 +695,9 @@ func (h *OrdersHandler) CancelAllOrders(c *fiber.Ctx) error {
 // @Produce json
 // @Param token_id path string true "Token ID"
 // @Param limit query int false "Limit results" default(100)
-// @Param before query string false "Cursor for pagination (before)"
-// @Param after query string false "Cursor for pagination (after)"
+// @Param cursor query string false "Pagination cursor"
+// @Param before query string false "Only trades before this time"
+// @Param after query string false "Only trades after this time"
 // @Success 200 {object} response.Response{data=[]models.Trade}
 // @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -264,20 +709,21 @@ func (h *OrdersHandler) GetTrades(c *fiber.Ctx) error {
 	}
 	
 	limit := c.QueryInt("limit", 100)
+	cursor := cursorParam(c)
 	before := c.Query("before")
 	after := c.Query("after")
 	
-	data, err := h.clob.GetTradesHistory(tokenID, limit, before, after)
+	data, err := h.clob.GetTradesHistory(tokenID, limit, cursor, before, after)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.Raw(c, data)
+	return relay(c, shape.Trades, data, pageMeta(c, data, 0, 0))
 }
 
 // BatchCancelRequest represents batch cancel request
 type BatchCancelRequest struct {
-	OrderIDs []string `json:"orderIds"`
+	OrderIDs []string `json:"orderIds" validate:"min=1,dive,required"`
 }
 
 // CancelOrders godoc
@@ -310,8 +756,14 @@ func (h *OrdersHandler) CancelOrders(c *fiber.Ctx) error {
 	
 	data, err := h.clob.CancelOrders(req.OrderIDs, authHeaders)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
+	}
+	
+	for _, id := range req.OrderIDs {
+		h.expiry.Forget(id)
+		h.notifier.Forget(id)
 	}
+	h.publish(c, "order.cancelled", req, data)
 	
-	return response.Raw(c, data)
+	return relay(c, shape.Cancelled, data, nil)
 }
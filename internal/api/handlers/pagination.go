@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/pkg/response"
+)
+
+// cursorParam returns the unified pagination cursor. List endpoints take
+// "cursor" whatever the upstream calls it; next_cursor and offset are
+// accepted as aliases.
+func cursorParam(c *fiber.Ctx) string {
+	for _, name := range []string{"cursor", "next_cursor", "offset"} {
+		if v := c.Query(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// pageOffset is the offset a request starts at: a numeric cursor, else offset
+func pageOffset(cursor string, offset int) int {
+	if n, err := strconv.Atoi(cursor); err == nil {
+		return n
+	}
+	return offset
+}
+
+// pageMeta sets the Link header for a relayed upstream page and returns
+// the page's meta for v2
+func pageMeta(c *fiber.Ctx, data []byte, offset, limit int) *response.Meta {
+	meta := &response.Meta{
+		NextCursor: polymarket.NextCursor(data, offset, limit),
+		Limit:      limit,
+	}
+	response.NextLink(c, meta.NextCursor)
+	return meta
+}
+
+// listMeta builds the meta for a page of a local listing of total items,
+// with a numeric next cursor while items remain
+func listMeta(c *fiber.Ctx, offset, limit, total int) *response.Meta {
+	meta := &response.Meta{Limit: limit, Total: total}
+	if limit > 0 && offset+limit < total {
+		meta.NextCursor = strconv.Itoa(offset + limit)
+		response.NextLink(c, meta.NextCursor)
+	}
+	return meta
+}
+
+// allItemsMeta builds the meta for an auto-paginated listing. When max cut
+// the walk short, the next cursor resumes after the last item returned.
+func allItemsMeta(c *fiber.Ctx, result *polymarket.PaginateResult, offset, limit int) *response.Meta {
+	meta := &response.Meta{
+		Limit:           limit,
+		Total:           len(result.Items),
+		CursorRefreshed: result.CursorRefreshed,
+	}
+	if len(result.Items) >= autoPaginateMax(c) {
+		meta.NextCursor = strconv.Itoa(offset + len(result.Items))
+		response.NextLink(c, meta.NextCursor)
+	}
+	return meta
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/posalert"
+	"github.com/polygo/pkg/response"
+	"github.com/polygo/pkg/validate"
+)
+
+// PositionAlertsHandler handles position alert endpoints
+type PositionAlertsHandler struct {
+	alerts *posalert.Alerts
+}
+
+// NewPositionAlertsHandler creates a new position alerts handler
+func NewPositionAlertsHandler(alerts *posalert.Alerts) *PositionAlertsHandler {
+	return &PositionAlertsHandler{alerts: alerts}
+}
+
+// PositionAlertRequest represents a request to create or update a position alert
+type PositionAlertRequest struct {
+	Address  string  `json:"address"` // ignored on update
+	Label    string  `json:"label,omitempty"`
+	MinSize  float64 `json:"min_size"`  // shares a position must change by; 0 for any change
+	MinValue float64 `json:"min_value"` // USDC, at the current price, a position must change by
+}
+
+func (r *PositionAlertRequest) thresholds() posalert.Thresholds {
+	return posalert.Thresholds{Label: r.Label, MinSize: r.MinSize, MinValue: r.MinValue}
+}
+
+// CreatePositionAlert godoc
+// @Summary Watch a wallet's positions
+// @Description Poll a wallet's positions and publish position.opened, position.increased, position.decreased and position.closed to the caller's webhooks and /ws/events when a position changes by at least min_size shares and min_value USDC
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param request body PositionAlertRequest true "Wallet and thresholds"
+// @Success 200 {object} response.Response{data=posalert.Watch}
+// @Failure 400 {object} response.Response
+// @Failure 503 {object} response.Response
+// @Router /api/v1/alerts/positions [post]
+func (h *PositionAlertsHandler) CreatePositionAlert(c *fiber.Ctx) error {
+	var req PositionAlertRequest
+	if err := sonic.Unmarshal(c.Body(), &req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	watch, err := h.alerts.Create(req.Address, req.thresholds(), callerKey(c))
+	return h.respond(c, watch, err)
+}
+
+// ListPositionAlerts godoc
+// @Summary List position alerts
+// @Description List the wallets whose positions the caller watches
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=[]posalert.Watch}
+// @Router /api/v1/alerts/positions [get]
+func (h *PositionAlertsHandler) ListPositionAlerts(c *fiber.Ctx) error {
+	return response.Success(c, h.alerts.Watches(callerKey(c)))
+}
+
+// GetPositionAlert godoc
+// @Summary Get a position alert
+// @Description Get one of the caller's position alerts
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param id path string true "Position alert ID"
+// @Success 200 {object} response.Response{data=posalert.Watch}
+// @Failure 404 {object} response.Response
+// @Router /api/v1/alerts/positions/{id} [get]
+func (h *PositionAlertsHandler) GetPositionAlert(c *fiber.Ctx) error {
+	watch, ok := h.alerts.Get(c.Params("id"), callerKey(c))
+	if !ok {
+		return response.NotFound(c, "Position alert not found")
+	}
+	return response.Success(c, watch)
+}
+
+// UpdatePositionAlert godoc
+// @Summary Update a position alert
+// @Description Replace the label and thresholds of one of the caller's position alerts; the address cannot be changed
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param id path string true "Position alert ID"
+// @Param request body PositionAlertRequest true "Label and thresholds"
+// @Success 200 {object} response.Response{data=posalert.Watch}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/alerts/positions/{id} [put]
+func (h *PositionAlertsHandler) UpdatePositionAlert(c *fiber.Ctx) error {
+	var req PositionAlertRequest
+	if err := sonic.Unmarshal(c.Body(), &req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	watch, err := h.alerts.Update(c.Params("id"), req.thresholds(), callerKey(c))
+	return h.respond(c, watch, err)
+}
+
+// DeletePositionAlert godoc
+// @Summary Delete a position alert
+// @Description Stop watching a wallet's positions for the caller
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param id path string true "Position alert ID"
+// @Success 200 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/alerts/positions/{id} [delete]
+func (h *PositionAlertsHandler) DeletePositionAlert(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if !h.alerts.Delete(id, callerKey(c)) {
+		return response.NotFound(c, "Position alert not found")
+	}
+	return response.Success(c, fiber.Map{"deleted": id})
+}
+
+// respond maps the result of a create or update to a response
+func (h *PositionAlertsHandler) respond(c *fiber.Ctx, watch posalert.Watch, err error) error {
+	switch {
+	case errors.Is(err, validate.ErrInvalidAddress):
+		return response.BadRequest(c, "A valid 0x wallet address is required")
+	case errors.Is(err, posalert.ErrInvalidThreshold):
+		return response.BadRequest(c, "min_size and min_value must not be negative")
+	case errors.Is(err, posalert.ErrNotFound):
+		return response.NotFound(c, "Position alert not found")
+	case errors.Is(err, posalert.ErrFull):
+		return response.Error(c, fiber.StatusServiceUnavailable, "POSITION_ALERTS_FULL", "Position alerts are full", "")
+	case err != nil:
+		return errorResponse(c, err)
+	}
+	return response.Success(c, watch)
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/odds"
+)
+
+// priceOptions are the ?as and ?round transforms of a price request
+type priceOptions struct {
+	format odds.Format
+	round  bool // round to the token's tick size
+}
+
+// identity reports whether prices are passed through untouched
+func (o priceOptions) identity() bool {
+	return o.format == odds.Probability && !o.round
+}
+
+// priceOptions parses ?as (probability, cents or american) and ?round
+// (tick or none, defaulting to the configured behaviour)
+func (h *PricesHandler) priceOptions(c *fiber.Ctx) (priceOptions, error) {
+	format, ok := odds.ParseFormat(c.Query("as"))
+	if !ok {
+		return priceOptions{}, errors.New("as must be probability, cents or american")
+	}
+
+	opts := priceOptions{format: format, round: h.config.RoundToTick}
+	switch c.Query("round") {
+	case "":
+	case "tick":
+		opts.round = true
+	case "none":
+		opts.round = false
+	default:
+		return priceOptions{}, errors.New("round must be tick or none")
+	}
+	return opts, nil
+}
+
+// tickSize returns a token's tick size, or 0 (no rounding) if unknown
+func (h *PricesHandler) tickSize(tokenID string) float64 {
+	data, _, err := h.clob.GetTickSize(tokenID)
+	if err != nil {
+		return 0
+	}
+	var resp struct {
+		MinimumTickSize float64 `json:"minimum_tick_size"`
+	}
+	if err := sonic.Unmarshal(data, &resp); err != nil {
+		return 0
+	}
+	return resp.MinimumTickSize
+}
+
+// priceQuoter converts the prices of one token
+type priceQuoter struct {
+	opts priceOptions
+	tick float64
+}
+
+// quoter returns the converter for a token's prices
+func (h *PricesHandler) quoter(opts priceOptions, tokenID string) *priceQuoter {
+	q := &priceQuoter{opts: opts}
+	if opts.round {
+		q.tick = h.tickSize(tokenID)
+	}
+	return q
+}
+
+// probability rounds a price to the tick size, if asked to
+func (q *priceQuoter) probability(p float64) float64 {
+	if q.opts.round {
+		return odds.RoundToTick(p, q.tick)
+	}
+	return p
+}
+
+// quote converts a price value; non-numeric values are returned unchanged
+// and prices the format cannot express become null
+func (q *priceQuoter) quote(v interface{}) interface{} {
+	p, ok := priceFloat(v)
+	if !ok {
+		return v
+	}
+	s, ok := odds.Quote(q.probability(p), q.opts.format)
+	if !ok {
+		return nil
+	}
+	return s
+}
+
+// quoteKeys converts the given keys of an object
+func (q *priceQuoter) quoteKeys(obj map[string]interface{}, keys ...string) {
+	for _, k := range keys {
+		if v, ok := obj[k]; ok {
+			obj[k] = q.quote(v)
+		}
+	}
+}
+
+// quoteLeaves converts every price in a value, such as the per-side map of
+// /prices
+func (q *priceQuoter) quoteLeaves(v interface{}) interface{} {
+	if obj, ok := v.(map[string]interface{}); ok {
+		for k, child := range obj {
+			obj[k] = q.quoteLeaves(child)
+		}
+		return obj
+	}
+	return q.quote(v)
+}
+
+// quoteBook converts the level prices of an order book and adds its implied
+// probability
+func (q *priceQuoter) quoteBook(book map[string]interface{}) {
+	var bid, ask, last float64
+	for _, side := range []string{"bids", "asks"} {
+		levels, _ := book[side].([]interface{})
+		for _, l := range levels {
+			level, ok := l.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if p, ok := priceFloat(level["price"]); ok {
+				if side == "bids" && p > bid {
+					bid = p
+				}
+				if side == "asks" && (ask == 0 || p < ask) {
+					ask = p
+				}
+			}
+			if !q.opts.identity() {
+				q.quoteKeys(level, "price")
+			}
+		}
+	}
+	if p, ok := priceFloat(book["last_trade_price"]); ok {
+		last = p
+		if !q.opts.identity() {
+			q.quoteKeys(book, "last_trade_price")
+		}
+	}
+
+	book["implied_probability"] = nil
+	if p, ok := odds.Implied(bid, ask, last); ok {
+		book["implied_probability"] = q.probability(p)
+	}
+}
+
+// priceFloat parses a price sent as a number or a numeric string
+func priceFloat(v interface{}) (float64, bool) {
+	switch p := v.(type) {
+	case float64:
+		return p, true
+	case string:
+		f, err := strconv.ParseFloat(p, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// transformPrices decodes an upstream price payload and lets fn rewrite it
+// in place
+func transformPrices(data []byte, fn func(v interface{})) ([]byte, error) {
+	var v interface{}
+	if err := sonic.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	fn(v)
+	return sonic.Marshal(v)
+}
+
+// quoteTokenMap converts a payload keyed by token ID, as returned by
+// /prices and /midpoints
+func (h *PricesHandler) quoteTokenMap(opts priceOptions) func(v interface{}) {
+	return func(v interface{}) {
+		byToken, _ := v.(map[string]interface{})
+		for tokenID, prices := range byToken {
+			byToken[tokenID] = h.quoter(opts, tokenID).quoteLeaves(prices)
+		}
+	}
+}
@@ -1,22 +1,28 @@
 package handlers
 
 import (
+	"errors"
+	"strconv"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/config"
 	"github.com/polygo/internal/models"
 	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/shape"
 	"github.com/polygo/pkg/response"
 )
 
 // PricesHandler handles price-related endpoints
 type PricesHandler struct {
-	clob *polymarket.ClobClient
+	clob   *polymarket.ClobClient
+	payout *polymarket.PayoutCalculator
+	config *config.PricesConfig
 }
 
 // NewPricesHandler creates a new prices handler
-func NewPricesHandler(clob *polymarket.ClobClient) *PricesHandler {
-	return &PricesHandler{clob: clob}
+func NewPricesHandler(clob *polymarket.ClobClient, cfg *config.PricesConfig) *PricesHandler {
+	return &PricesHandler{clob: clob, payout: polymarket.NewPayoutCalculator(clob), config: cfg}
 }
 
 // GetPrice godoc
@@ -24,9 +30,11 @@ func NewPricesHandler(clob *polymarket.ClobClient) *PricesHandler {
 // @Description Get the current price for a token
 // @Tags Prices
 // @Accept json
-// @Produce json
+// @Produce json,application/x-protobuf
 // @Param token_id path string true "Token ID"
 // @Param side query string false "Order side (BUY/SELL)" default(BUY)
+// @Param as query string false "Price format: probability (default), cents or american"
+// @Param round query string false "Round prices to the token's tick size (tick) or not (none); defaults to the server setting"
 // @Success 200 {object} response.Response{data=models.Price}
 // @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -42,12 +50,30 @@ func (h *PricesHandler) GetPrice(c *fiber.Ctx) error {
 		return response.BadRequest(c, "Side must be BUY or SELL")
 	}
 	
+	opts, err := h.priceOptions(c)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+	
 	data, cacheHit, err := h.clob.GetPrice(tokenID, side)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
+	}
+	
+	if !opts.identity() {
+		q := h.quoter(opts, tokenID)
+		data, err = transformPrices(data, func(v interface{}) {
+			if price, ok := v.(map[string]interface{}); ok {
+				q.quoteKeys(price, "price")
+			}
+		})
+		if err != nil {
+			return errorResponse(c, err)
+		}
 	}
 	
-	return response.RawWithCacheHeader(c, data, cacheHit)
+	cacheHeader(c, cacheHit)
+	return relayQuote(c, shape.TokenPrice(tokenID, string(side)), data)
 }
 
 // GetPrices godoc
@@ -55,9 +81,11 @@ func (h *PricesHandler) GetPrice(c *fiber.Ctx) error {
 // @Description Get current prices for multiple tokens at once
 // @Tags Prices
 // @Accept json
-// @Produce json
+// @Produce json,application/x-protobuf
 // @Param token_ids query string true "Comma-separated token IDs"
 // @Param side query string false "Order side (BUY/SELL)" default(BUY)
+// @Param as query string false "Price format: probability (default), cents or american"
+// @Param round query string false "Round prices to the token's tick size (tick) or not (none); defaults to the server setting"
 // @Success 200 {object} response.Response{data=[]models.Price}
 // @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -78,12 +106,24 @@ func (h *PricesHandler) GetPrices(c *fiber.Ctx) error {
 		return response.BadRequest(c, "Side must be BUY or SELL")
 	}
 	
+	opts, err := h.priceOptions(c)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+	
 	data, err := h.clob.GetPrices(tokenIDs, side)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.Raw(c, data)
+	if !opts.identity() {
+		data, err = transformPrices(data, h.quoteTokenMap(opts))
+		if err != nil {
+			return errorResponse(c, err)
+		}
+	}
+	
+	return relayQuote(c, shape.Prices, data)
 }
 
 // GetOrderBook godoc
@@ -91,8 +131,10 @@ func (h *PricesHandler) GetPrices(c *fiber.Ctx) error {
 // @Description Get the full order book for a token
 // @Tags Prices
 // @Accept json
-// @Produce json
+// @Produce json,application/x-protobuf
 // @Param token_id path string true "Token ID"
+// @Param as query string false "Price format: probability (default), cents or american"
+// @Param round query string false "Round prices to the token's tick size (tick) or not (none); defaults to the server setting"
 // @Success 200 {object} response.Response{data=models.OrderBook}
 // @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -103,12 +145,28 @@ func (h *PricesHandler) GetOrderBook(c *fiber.Ctx) error {
 		return response.BadRequest(c, "Token ID is required")
 	}
 	
+	opts, err := h.priceOptions(c)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+	
 	data, cacheHit, err := h.clob.GetOrderBook(tokenID)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
+	}
+	
+	q := h.quoter(opts, tokenID)
+	data, err = transformPrices(data, func(v interface{}) {
+		if book, ok := v.(map[string]interface{}); ok {
+			q.quoteBook(book)
+		}
+	})
+	if err != nil {
+		return errorResponse(c, err)
 	}
 	
-	return response.RawWithCacheHeader(c, data, cacheHit)
+	cacheHeader(c, cacheHit)
+	return relayQuote(c, shape.Book, data)
 }
 
 // GetOrderBooks godoc
@@ -116,8 +174,10 @@ func (h *PricesHandler) GetOrderBook(c *fiber.Ctx) error {
 // @Description Get order books for multiple tokens at once
 // @Tags Prices
 // @Accept json
-// @Produce json
+// @Produce json,application/x-protobuf
 // @Param token_ids query string true "Comma-separated token IDs"
+// @Param as query string false "Price format: probability (default), cents or american"
+// @Param round query string false "Round prices to the token's tick size (tick) or not (none); defaults to the server setting"
 // @Success 200 {object} response.Response{data=[]models.OrderBook}
 // @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -133,12 +193,30 @@ func (h *PricesHandler) GetOrderBooks(c *fiber.Ctx) error {
 		return response.BadRequest(c, "At least one token ID is required")
 	}
 	
+	opts, err := h.priceOptions(c)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+	
 	data, err := h.clob.GetOrderBooks(tokenIDs)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.Raw(c, data)
+	data, err = transformPrices(data, func(v interface{}) {
+		books, _ := v.([]interface{})
+		for _, b := range books {
+			if book, ok := b.(map[string]interface{}); ok {
+				tokenID, _ := book["asset_id"].(string)
+				h.quoter(opts, tokenID).quoteBook(book)
+			}
+		}
+	})
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	
+	return relayQuote(c, shape.Books, data)
 }
 
 // GetSpread godoc
@@ -160,10 +238,11 @@ func (h *PricesHandler) GetSpread(c *fiber.Ctx) error {
 	
 	data, cacheHit, err := h.clob.GetSpread(tokenID)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.RawWithCacheHeader(c, data, cacheHit)
+	cacheHeader(c, cacheHit)
+	return relay(c, shape.TokenSpread(tokenID), data, nil)
 }
 
 // GetMidpoint godoc
@@ -173,6 +252,8 @@ func (h *PricesHandler) GetSpread(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param token_id path string true "Token ID"
+// @Param as query string false "Price format: probability (default), cents or american"
+// @Param round query string false "Round prices to the token's tick size (tick) or not (none); defaults to the server setting"
 // @Success 200 {object} response.Response{data=object}
 // @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -183,12 +264,35 @@ func (h *PricesHandler) GetMidpoint(c *fiber.Ctx) error {
 		return response.BadRequest(c, "Token ID is required")
 	}
 	
+	opts, err := h.priceOptions(c)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+	
 	data, cacheHit, err := h.clob.GetMidpoint(tokenID)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
 	}
 	
-	return response.RawWithCacheHeader(c, data, cacheHit)
+	q := h.quoter(opts, tokenID)
+	data, err = transformPrices(data, func(v interface{}) {
+		mid, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+		if p, ok := priceFloat(mid["mid"]); ok {
+			mid["implied_probability"] = q.probability(p)
+		}
+		if !opts.identity() {
+			q.quoteKeys(mid, "mid")
+		}
+	})
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	
+	cacheHeader(c, cacheHit)
+	return relay(c, shape.TokenMidpoint(tokenID), data, nil)
 }
 
 // GetMidpoints godoc
@@ -198,6 +302,8 @@ func (h *PricesHandler) GetMidpoint(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param token_ids query string true "Comma-separated token IDs"
+// @Param as query string false "Price format: probability (default), cents or american"
+// @Param round query string false "Round prices to the token's tick size (tick) or not (none); defaults to the server setting"
 // @Success 200 {object} response.Response{data=object}
 // @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -210,12 +316,24 @@ func (h *PricesHandler) GetMidpoints(c *fiber.Ctx) error {
 	
 	tokenIDs := strings.Split(tokenIDsStr, ",")
 	
+	opts, err := h.priceOptions(c)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+	
 	data, err := h.clob.GetMidpoints(tokenIDs)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
+	}
+	
+	if !opts.identity() {
+		data, err = transformPrices(data, h.quoteTokenMap(opts))
+		if err != nil {
+			return errorResponse(c, err)
+		}
 	}
 	
-	return response.Raw(c, data)
+	return relay(c, shape.Midpoints, data, nil)
 }
 
 // GetLastTradePrice godoc
@@ -225,6 +343,8 @@ func (h *PricesHandler) GetMidpoints(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param token_id path string true "Token ID"
+// @Param as query string false "Price format: probability (default), cents or american"
+// @Param round query string false "Round prices to the token's tick size (tick) or not (none); defaults to the server setting"
 // @Success 200 {object} response.Response{data=object}
 // @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -235,10 +355,132 @@ func (h *PricesHandler) GetLastTradePrice(c *fiber.Ctx) error {
 		return response.BadRequest(c, "Token ID is required")
 	}
 	
+	opts, err := h.priceOptions(c)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+	
 	data, cacheHit, err := h.clob.GetLastTradePrice(tokenID)
 	if err != nil {
-		return response.InternalError(c, err)
+		return errorResponse(c, err)
+	}
+	
+	if !opts.identity() {
+		q := h.quoter(opts, tokenID)
+		data, err = transformPrices(data, func(v interface{}) {
+			if price, ok := v.(map[string]interface{}); ok {
+				q.quoteKeys(price, "price")
+			}
+		})
+		if err != nil {
+			return errorResponse(c, err)
+		}
+	}
+	
+	cacheHeader(c, cacheHit)
+	return relay(c, shape.TokenLastTrade(tokenID), data, nil)
+}
+
+// CalcPayout godoc
+// @Summary Calculate order payout
+// @Description Price a hypothetical order with the token's current tick size, neg-risk flag and fee rate: fee, net cost or proceeds, max payout, profit and loss, and breakeven probability
+// @Tags Prices
+// @Accept json
+// @Produce json
+// @Param token_id query string true "Token ID"
+// @Param side query string true "Order side (BUY/SELL)"
+// @Param price query number true "Limit price (0-1, on the token's tick)"
+// @Param size query number true "Size in shares"
+// @Param maker query bool false "Price as a resting (maker) order, which pays no fee" default(false)
+// @Success 200 {object} response.Response{data=polymarket.PayoutQuote}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/calc/payout [get]
+func (h *PricesHandler) CalcPayout(c *fiber.Ctx) error {
+	tokenID := c.Query("token_id")
+	if tokenID == "" {
+		return response.BadRequest(c, "token_id is required")
+	}
+	
+	side := models.Side(strings.ToUpper(c.Query("side")))
+	if side != models.SideBuy && side != models.SideSell {
+		return response.BadRequest(c, "Side must be BUY or SELL")
+	}
+	
+	price, err := strconv.ParseFloat(c.Query("price"), 64)
+	if err != nil || price <= 0 || price >= 1 {
+		return response.BadRequest(c, "price must be between 0 and 1")
+	}
+	size, err := strconv.ParseFloat(c.Query("size"), 64)
+	if err != nil || size <= 0 {
+		return response.BadRequest(c, "size must be a positive number of shares")
+	}
+	
+	quote, err := h.payout.Quote(polymarket.PayoutOrder{
+		TokenID: tokenID,
+		Side:    side,
+		Price:   price,
+		Size:    size,
+		Maker:   c.QueryBool("maker"),
+	})
+	var statusErr *polymarket.StatusError
+	switch {
+	case errors.Is(err, polymarket.ErrPriceOffTick):
+		return response.BadRequest(c, "price is not a multiple of the token's tick size")
+	case errors.As(err, &statusErr) && statusErr.StatusCode == fiber.StatusNotFound:
+		return response.NotFound(c, "Token not found")
+	case err != nil:
+		return errorResponse(c, err)
+	}
+	
+	return response.Success(c, quote)
+}
+
+// PreviewOrder godoc
+// @Summary Preview an order's fills
+// @Description Match a hypothetical order against the token's current (cached) book without placing it: the levels it would take up to its limit price, filled and remaining size, average and worst fill price, slippage from the best price, and the taker fee. GTC and GTD orders leave the remainder resting; FOK orders the book cannot fill whole fill nothing. Takes the same body as POST /orders.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param order body models.CreateOrderRequest true "Order details"
+// @Success 200 {object} response.Response{data=polymarket.OrderPreview}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/orders/preview [post]
+func (h *PricesHandler) PreviewOrder(c *fiber.Ctx) error {
+	var req models.CreateOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+	
+	price, _ := strconv.ParseFloat(req.Price, 64)
+	if price <= 0 || price >= 1 {
+		return response.BadRequest(c, "price must be between 0 and 1")
+	}
+	size, _ := strconv.ParseFloat(req.Size, 64)
+	if size <= 0 {
+		return response.BadRequest(c, "size must be a positive number of shares")
+	}
+	
+	preview, hit, err := h.payout.Preview(polymarket.PreviewOrder{
+		TokenID: req.TokenID,
+		Side:    req.Side,
+		Price:   price,
+		Size:    size,
+		Type:    req.Type,
+	})
+	var statusErr *polymarket.StatusError
+	switch {
+	case errors.Is(err, polymarket.ErrPriceOffTick):
+		return response.BadRequest(c, "price is not a multiple of the token's tick size")
+	case errors.As(err, &statusErr) && statusErr.StatusCode == fiber.StatusNotFound:
+		return response.NotFound(c, "Token not found")
+	case err != nil:
+		return errorResponse(c, err)
 	}
 	
-	return response.RawWithCacheHeader(c, data, cacheHit)
+	cacheHeader(c, hit)
+	return response.Success(c, preview)
 }
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/pb"
+	"github.com/polygo/internal/shape"
+	"github.com/polygo/pkg/response"
+	"google.golang.org/protobuf/proto"
+)
+
+// The price, book and snapshot endpoints answer in protobuf (internal/pb)
+// when the client prefers it in Accept. Errors stay JSON.
+
+// wantsProtobuf reports whether the client prefers protobuf over JSON
+func wantsProtobuf(c *fiber.Ctx) bool {
+	c.Vary(fiber.HeaderAccept)
+	return c.Accepts(fiber.MIMEApplicationJSON, pb.ContentType) == pb.ContentType
+}
+
+// relayQuote sends an upstream payload as relay does, or shaped into its
+// protobuf message when the client asks for it
+func relayQuote(c *fiber.Ctx, s shape.Shaper, data []byte) error {
+	if !wantsProtobuf(c) {
+		return relay(c, s, data, nil)
+	}
+
+	v, err := s(data)
+	if errors.Is(err, shape.ErrNotFound) {
+		return response.NotFound(c, "Not found")
+	}
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	msg, ok := pb.From(v)
+	if !ok {
+		return response.InternalError(c, errors.New("no protobuf message for this response"))
+	}
+	return sendProtobuf(c, msg)
+}
+
+// sendProtobuf encodes msg and sends it
+func sendProtobuf(c *fiber.Ctx, msg proto.Message) error {
+	body, err := proto.Marshal(msg)
+	if err != nil {
+		return response.InternalError(c, err)
+	}
+	return response.Protobuf(c, body)
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/pkg/response"
+)
+
+// RawHandler forwards requests for upstream endpoints PolyGo does not map
+// yet, limited to an allowlist of path prefixes
+type RawHandler struct {
+	client     *polymarket.Client
+	config     *config.RawProxyConfig
+	authConfig *config.AuthConfig
+}
+
+// NewRawHandler creates a new raw passthrough handler
+func NewRawHandler(client *polymarket.Client, cfg *config.RawProxyConfig, authCfg *config.AuthConfig) *RawHandler {
+	return &RawHandler{client: client, config: cfg, authConfig: authCfg}
+}
+
+// Proxy godoc
+// @Summary Raw upstream passthrough
+// @Description Forward any allowlisted path and query string to the CLOB, Gamma or Data API, passing auth headers through. Responses are not cached unless a cache TTL is configured.
+// @Tags Raw
+// @Accept json
+// @Produce json
+// @Param upstream path string true "Upstream API" Enums(clob, gamma, data)
+// @Param path path string true "Upstream path"
+// @Success 200 {object} interface{}
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/raw/{upstream}/{path} [get]
+func (h *RawHandler) Proxy(c *fiber.Ctx) error {
+	upstream := c.Params("upstream")
+	target := proxyPath(c)
+
+	url, ok := upstreamURL(h.client, upstream, target)
+	if !ok {
+		return response.NotFound(c, "Unknown upstream")
+	}
+	p, clean := cleanPath("/" + c.Params("*"))
+	if !clean || !h.allowed(upstream, p) {
+		return response.Error(c, fiber.StatusForbidden, "FORBIDDEN", "Path is not in the raw proxy allowlist", upstream+":"+p)
+	}
+	if upstream == "clob" && c.Method() != fiber.MethodGet && c.Method() != fiber.MethodDelete && isOrderPath(p) {
+		// Orders go through /orders so the pre-trade checks see them
+		return response.Error(c, fiber.StatusForbidden, "FORBIDDEN", "Orders cannot be placed through the raw proxy", upstream+":"+p)
+	}
+
+	if c.Method() == fiber.MethodGet && h.config.CacheTTL > 0 {
+		data, cached, stream, err := h.client.GetWithCacheOrStream(url, "raw:"+upstream+":"+target, h.config.CacheTTL)
+		if err != nil {
+			return relayError(c, err)
+		}
+		if stream != nil {
+			c.Set("X-Cache", "MISS")
+			return response.Stream(c, stream, stream.Size)
+		}
+		return response.RawWithCacheHeader(c, data, cached)
+	}
+
+	opts := &polymarket.RequestOptions{Headers: authHeaders(c, h.authConfig)}
+	if c.Method() == fiber.MethodGet {
+		data, stream, err := h.client.GetStream(url, opts)
+		if err != nil {
+			return relayError(c, err)
+		}
+		if stream != nil {
+			return response.Stream(c, stream, stream.Size)
+		}
+		return response.Raw(c, data)
+	}
+
+	data, err := h.client.Do(c.Method(), url, c.Body(), opts)
+	if err != nil {
+		return relayError(c, err)
+	}
+	return response.Raw(c, data)
+}
+
+// allowed reports whether an upstream path matches an allowlist entry.
+// Entries are "<upstream>:<path prefix>", with "*" for any upstream.
+func (h *RawHandler) allowed(upstream, path string) bool {
+	for _, entry := range h.config.Allowlist {
+		name, prefix, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || (name != "*" && name != upstream) {
+			continue
+		}
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanPath unescapes p and reports whether it is already in canonical form,
+// so that no "." or ".." segment (escaped or not) can climb out of an
+// allowlisted prefix once the upstream resolves it
+func cleanPath(p string) (string, bool) {
+	decoded, err := url.PathUnescape(p)
+	if err != nil || strings.Contains(decoded, "\\") {
+		return decoded, false
+	}
+	if decoded == "/" {
+		return decoded, true
+	}
+	return decoded, path.Clean(decoded) == strings.TrimSuffix(decoded, "/")
+}
+
+// isOrderPath reports whether a CLOB path places orders
+func isOrderPath(p string) bool {
+	p = strings.ToLower(strings.TrimSuffix(p, "/"))
+	return p == "/order" || p == "/orders"
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/pkg/response"
+)
+
+// ReplicaHandler lets replica PolyGo instances read Polymarket through this
+// (primary) instance. Responses are served from the primary's cache and
+// carry their remaining freshness so replicas can cache them consistently.
+// Only reads are proxied: the route takes GET and HEAD.
+type ReplicaHandler struct {
+	client      *polymarket.Client
+	cacheConfig *config.CacheConfig
+}
+
+// NewReplicaHandler creates a new replica handler
+func NewReplicaHandler(client *polymarket.Client, cacheCfg *config.CacheConfig) *ReplicaHandler {
+	return &ReplicaHandler{
+		client:      client,
+		cacheConfig: cacheCfg,
+	}
+}
+
+// Proxy forwards /replica/:upstream/* to the matching Polymarket API
+func (h *ReplicaHandler) Proxy(c *fiber.Ctx) error {
+	path := proxyPath(c)
+	url, ok := upstreamURL(h.client, c.Params("upstream"), path)
+	if !ok {
+		return response.NotFound(c, "Unknown upstream")
+	}
+
+	ttl := h.ttlFor(path)
+	data, cached, err := h.client.GetWithCache(url, "replica:"+c.Params("upstream")+":"+path, ttl)
+	if err != nil {
+		return relayError(c, err)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "max-age="+strconv.Itoa(int(ttl/time.Second)))
+	c.Set(polymarket.TTLHeader, strconv.FormatInt(ttl.Milliseconds(), 10))
+	return response.RawWithCacheHeader(c, data, cached)
+}
+
+// proxyPath returns the wildcard path of a proxy route with its query string
+func proxyPath(c *fiber.Ctx) string {
+	path := "/" + c.Params("*")
+	if q := c.Context().QueryArgs().QueryString(); len(q) > 0 {
+		path += "?" + string(q)
+	}
+	return path
+}
+
+// upstreamURL resolves path against the named Polymarket API
+func upstreamURL(client *polymarket.Client, upstream, path string) (string, bool) {
+	switch upstream {
+	case "clob":
+		return client.CLOB(path), true
+	case "gamma":
+		return client.Gamma(path), true
+	case "data":
+		return client.Data(path), true
+	}
+	return "", false
+}
+
+// authHeaders collects the caller's L2 auth headers for forwarding
+func authHeaders(c *fiber.Ctx, authConfig *config.AuthConfig) map[string]string {
+	headers := make(map[string]string)
+	for _, name := range []string{
+		authConfig.APIKeyHeader,
+		authConfig.APISecretHeader,
+		authConfig.PassphraseHeader,
+		authConfig.SignatureHeader,
+		authConfig.TimestampHeader,
+	} {
+		if v := c.Get(name); v != "" {
+			headers[name] = v
+		}
+	}
+	return headers
+}
+
+// ttlFor picks the cache TTL matching the kind of data behind path
+func (h *ReplicaHandler) ttlFor(path string) time.Duration {
+	switch {
+	case strings.HasPrefix(path, "/book"):
+		return h.cacheConfig.OrderBookTTL
+	case strings.HasPrefix(path, "/price"), strings.HasPrefix(path, "/midpoint"),
+		strings.HasPrefix(path, "/spread"), strings.HasPrefix(path, "/last-trade"):
+		return h.cacheConfig.PricesTTL
+	case strings.HasPrefix(path, "/markets"):
+		return h.cacheConfig.MarketsTTL
+	case strings.HasPrefix(path, "/events"):
+		return h.cacheConfig.EventsTTL
+	default:
+		return h.cacheConfig.DefaultTTL
+	}
+}
+
+// relayError passes upstream client errors through unchanged so replicas
+// see the same status Polymarket returned
+func relayError(c *fiber.Ctx, err error) error {
+	var statusErr *polymarket.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode < 500 {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.Status(statusErr.StatusCode).Send(statusErr.Body)
+	}
+	if errors.Is(err, polymarket.ErrUpstreamBusy) {
+		return response.Retry(c, fiber.StatusServiceUnavailable, "UPSTREAM_BUSY", "Upstream rate limit reached", err.Error(), time.Second)
+	}
+	return response.Retry(c, fiber.StatusBadGateway, "UPSTREAM_ERROR", "Upstream request failed", err.Error(), 0)
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/api/middleware"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/rewards"
+	"github.com/polygo/pkg/response"
+	"github.com/polygo/pkg/validate"
+)
+
+// RewardsHandler estimates liquidity rewards for the caller's open orders
+type RewardsHandler struct {
+	estimator  *rewards.Estimator
+	authConfig *config.AuthConfig
+}
+
+// NewRewardsHandler creates a new rewards handler
+func NewRewardsHandler(estimator *rewards.Estimator, authConfig *config.AuthConfig) *RewardsHandler {
+	return &RewardsHandler{estimator: estimator, authConfig: authConfig}
+}
+
+// GetRewards godoc
+// @Summary Estimate liquidity rewards
+// @Description Score the caller's open orders made by the address against each market's reward terms (rewardsMinSize, rewardsMaxSpread and daily rate), reporting per-order eligibility and the estimated share of each market's daily rewards given the current order book
+// @Tags User Data
+// @Accept json
+// @Produce json
+// @Param address path string true "Maker wallet address"
+// @Security ApiKeyAuth
+// @Success 200 {object} response.Response{data=rewards.Report}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/rewards/{address} [get]
+func (h *RewardsHandler) GetRewards(c *fiber.Ctx) error {
+	creds := middleware.GetAuthCredentials(c)
+	if creds == nil {
+		return response.Unauthorized(c, "Authentication required")
+	}
+
+	report, err := h.estimator.Estimate(c.Params("address"), middleware.GetAuthHeaders(creds, h.authConfig))
+	if errors.Is(err, validate.ErrInvalidAddress) {
+		return response.BadRequest(c, err.Error())
+	}
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return response.Success(c, report)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/risk"
+	"github.com/polygo/pkg/response"
+)
+
+// RiskHandler manages pre-trade risk limits
+type RiskHandler struct {
+	checker *risk.Checker
+}
+
+// NewRiskHandler creates a new risk limits handler
+func NewRiskHandler(checker *risk.Checker) *RiskHandler {
+	return &RiskHandler{checker: checker}
+}
+
+// GetLimits godoc
+// @Summary Get risk limits
+// @Description Get the default pre-trade limits and per-account overrides, keyed by API key
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=risk.Limits}
+// @Failure 401 {object} response.Response
+// @Router /admin/risk/limits [get]
+func (h *RiskHandler) GetLimits(c *fiber.Ctx) error {
+	return response.Success(c, h.checker.Limits())
+}
+
+// SetDefaultLimits godoc
+// @Summary Set default risk limits
+// @Description Replace the limits of accounts without their own. Zero disables a limit.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param limits body config.RiskLimits true "Limits"
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=config.RiskLimits}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /admin/risk/limits/default [put]
+func (h *RiskHandler) SetDefaultLimits(c *fiber.Ctx) error {
+	limits, err := parseLimits(c)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	h.checker.SetDefault(limits)
+	return response.Success(c, limits)
+}
+
+// SetAccountLimits godoc
+// @Summary Set an account's risk limits
+// @Description Replace an account's limits; they apply instead of the default ones. Zero disables a limit.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param account path string true "Account API key"
+// @Param limits body config.RiskLimits true "Limits"
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=config.RiskLimits}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /admin/risk/limits/{account} [put]
+func (h *RiskHandler) SetAccountLimits(c *fiber.Ctx) error {
+	limits, err := parseLimits(c)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	h.checker.SetAccount(c.Params("account"), limits)
+	return response.Success(c, limits)
+}
+
+// DeleteAccountLimits godoc
+// @Summary Remove an account's risk limits
+// @Description Return an account to the default limits
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param account path string true "Account API key"
+// @Security AdminAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/risk/limits/{account} [delete]
+func (h *RiskHandler) DeleteAccountLimits(c *fiber.Ctx) error {
+	account := c.Params("account")
+	if !h.checker.DeleteAccount(account) {
+		return response.NotFound(c, "Account has no limits of its own")
+	}
+	return response.Success(c, fiber.Map{"deleted": account})
+}
+
+// parseLimits reads limits from the body, rejecting negative ones
+func parseLimits(c *fiber.Ctx) (config.RiskLimits, error) {
+	var limits config.RiskLimits
+	if err := sonic.Unmarshal(c.Body(), &limits); err != nil {
+		return limits, fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if limits.MaxOrderSize < 0 || limits.MaxOpenNotional < 0 || limits.MaxOrdersPerMinute < 0 {
+		return limits, fiber.NewError(fiber.StatusBadRequest, "Limits must not be negative")
+	}
+	if limits.BannedMarkets == nil {
+		limits.BannedMarkets = []string{}
+	}
+	return limits, nil
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/api/middleware"
+	"github.com/polygo/internal/expiry"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/routing"
+	"github.com/polygo/pkg/response"
+)
+
+// RoutingHandler places orders for routed accounts
+type RoutingHandler struct {
+	router *routing.Router
+	expiry *expiry.Tracker
+}
+
+// NewRoutingHandler creates a new routing handler
+func NewRoutingHandler(router *routing.Router, expiry *expiry.Tracker) *RoutingHandler {
+	return &RoutingHandler{router: router, expiry: expiry}
+}
+
+// GetRouting godoc
+// @Summary Get order routing
+// @Description List the routes and the accounts orders are routed to, with how many orders each took and how often it was skipped at its exposure limit
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=routing.Status}
+// @Failure 401 {object} response.Response
+// @Router /admin/routing [get]
+func (h *RoutingHandler) GetRouting(c *fiber.Ctx) error {
+	return response.Success(c, h.router.Status())
+}
+
+// RouteOrder godoc
+// @Summary Place a routed order
+// @Description Place an order for one of the configured routing accounts, signed server-side. The first route matching the order's market and strategy tag picks the account, round-robin among its accounts and skipping those whose open orders plus this one would exceed max_exposure USDC. The order's maker is the account's address. The order passes the order rules, duplicate guard and risk limits of /orders for the account picked.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param order body models.CreateOrderRequest true "Order"
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=routing.Placement}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Failure 429 {object} response.Response
+// @Failure 503 {object} response.Response
+// @Router /admin/orders [post]
+func (h *RoutingHandler) RouteOrder(c *fiber.Ctx) error {
+	var req models.CreateOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	defaultOrder(&req)
+	if err := h.expiry.Validate(&req, time.Now()); err != nil {
+		return response.BadRequest(c, capitalize(err.Error()))
+	}
+
+	placement, err := h.router.Place(req)
+	if rejected, resp := middleware.PreTradeRejection(c, err, "orders"); rejected {
+		return resp
+	}
+	var lookup *routing.LookupError
+	switch {
+	case errors.Is(err, routing.ErrNoRoute):
+		return response.Error(c, fiber.StatusUnprocessableEntity, "NO_ROUTE", "No route matches the order's market and strategy", "")
+	case errors.Is(err, routing.ErrExposure):
+		return response.Error(c, fiber.StatusUnprocessableEntity, "ROUTE_EXPOSURE_LIMIT", "Every account of the route would exceed its exposure limit", "")
+	case errors.As(err, &lookup):
+		return response.Retry(c, fiber.StatusServiceUnavailable, "ROUTE_EXPOSURE_UNAVAILABLE", "Open orders could not be read to check exposure", err.Error(), 0)
+	case err != nil:
+		return errorResponse(c, err)
+	}
+	return response.Success(c, placement)
+}
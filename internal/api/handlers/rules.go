@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/rules"
+	"github.com/polygo/pkg/response"
+)
+
+// RulesHandler manages rule engine rules
+type RulesHandler struct {
+	engine *rules.Engine
+}
+
+// NewRulesHandler creates a new rules handler
+func NewRulesHandler(e *rules.Engine) *RulesHandler {
+	return &RulesHandler{engine: e}
+}
+
+// ListRules godoc
+// @Summary List rules
+// @Description List rule engine rules with how often each fired
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=[]rules.Rule}
+// @Failure 401 {object} response.Response
+// @Router /admin/rules [get]
+func (h *RulesHandler) ListRules(c *fiber.Ctx) error {
+	return response.Success(c, h.engine.List())
+}
+
+// CreateRule godoc
+// @Summary Create a rule
+// @Description Add a rule: a condition over upstream market events (e.g. volume_24h > 1e6 AND price_change_5m > 0.05) and the actions run when an event matches it (webhook, publish, log or order). A rule fires at most once per market per cooldown.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body config.RuleSpec true "Rule"
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=rules.Rule}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/rules [post]
+func (h *RulesHandler) CreateRule(c *fiber.Ctx) error {
+	var spec config.RuleSpec
+	if err := sonic.Unmarshal(c.Body(), &spec); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	rule, err := h.engine.Create(spec)
+	if err != nil {
+		return ruleError(c, err)
+	}
+	return response.Success(c, rule)
+}
+
+// GetRule godoc
+// @Summary Get a rule
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param name path string true "Rule name"
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=rules.Rule}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/rules/{name} [get]
+func (h *RulesHandler) GetRule(c *fiber.Ctx) error {
+	rule, err := h.engine.Get(c.Params("name"))
+	if err != nil {
+		return ruleError(c, err)
+	}
+	return response.Success(c, rule)
+}
+
+// UpdateRule godoc
+// @Summary Update a rule
+// @Description Replace a rule's condition, actions and cooldown. Its cooldowns restart; its counts and triggers are kept.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param name path string true "Rule name"
+// @Param request body config.RuleSpec true "Rule"
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=rules.Rule}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/rules/{name} [put]
+func (h *RulesHandler) UpdateRule(c *fiber.Ctx) error {
+	var spec config.RuleSpec
+	if err := sonic.Unmarshal(c.Body(), &spec); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	rule, err := h.engine.Update(c.Params("name"), spec)
+	if err != nil {
+		return ruleError(c, err)
+	}
+	return response.Success(c, rule)
+}
+
+// DeleteRule godoc
+// @Summary Delete a rule
+// @Description Remove a rule and its trigger history
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param name path string true "Rule name"
+// @Security AdminAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/rules/{name} [delete]
+func (h *RulesHandler) DeleteRule(c *fiber.Ctx) error {
+	name := c.Params("name")
+	if err := h.engine.Delete(name); err != nil {
+		return ruleError(c, err)
+	}
+	return response.Success(c, fiber.Map{"deleted": name})
+}
+
+// ListTriggers godoc
+// @Summary List a rule's triggers
+// @Description Get a rule's recent triggers, newest first, with the field values that matched and what each action did
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param name path string true "Rule name"
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=[]rules.Trigger}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/rules/{name}/triggers [get]
+func (h *RulesHandler) ListTriggers(c *fiber.Ctx) error {
+	triggers, err := h.engine.Triggers(c.Params("name"))
+	if err != nil {
+		return ruleError(c, err)
+	}
+	return response.Success(c, triggers)
+}
+
+// ruleError maps rule engine errors to responses
+func ruleError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, rules.ErrInvalidRule):
+		return response.BadRequest(c, err.Error())
+	case errors.Is(err, rules.ErrNotFound):
+		return response.NotFound(c, "Rule not found")
+	case errors.Is(err, rules.ErrExists):
+		return response.Error(c, fiber.StatusConflict, "RULE_EXISTS", "A rule with this name already exists", "")
+	case errors.Is(err, rules.ErrTooManyRules):
+		return response.Error(c, fiber.StatusConflict, "TOO_MANY_RULES", "Rule limit reached", "")
+	}
+	return errorResponse(c, err)
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/pb"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/pkg/response"
+)
+
+// SnapshotHandler handles the multi-token price snapshot endpoint
+type SnapshotHandler struct {
+	snapshots *polymarket.SnapshotService
+	config    *config.SnapshotConfig
+}
+
+// NewSnapshotHandler creates a new snapshot handler
+func NewSnapshotHandler(snapshots *polymarket.SnapshotService, cfg *config.SnapshotConfig) *SnapshotHandler {
+	return &SnapshotHandler{snapshots: snapshots, config: cfg}
+}
+
+// GetSnapshot godoc
+// @Summary Get multi-token price snapshot
+// @Description Get bid, ask, mid, last, spread and 24h change for several tokens in one call
+// @Tags Prices
+// @Accept json
+// @Produce json,application/x-protobuf
+// @Param token_ids query string true "Comma-separated token IDs"
+// @Success 200 {object} response.Response{data=[]polymarket.TokenSnapshot}
+// @Failure 400 {object} response.Response
+// @Router /api/v1/snapshot [get]
+func (h *SnapshotHandler) GetSnapshot(c *fiber.Ctx) error {
+	var tokenIDs []string
+	seen := make(map[string]bool)
+	for _, id := range strings.Split(c.Query("token_ids"), ",") {
+		id = strings.TrimSpace(id)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			tokenIDs = append(tokenIDs, id)
+		}
+	}
+
+	if len(tokenIDs) == 0 {
+		return response.BadRequest(c, "token_ids is required")
+	}
+	if len(tokenIDs) > h.config.MaxTokens {
+		return response.BadRequest(c, "At most "+strconv.Itoa(h.config.MaxTokens)+" token IDs are allowed")
+	}
+
+	snaps := h.snapshots.Get(tokenIDs)
+	if wantsProtobuf(c) {
+		return sendProtobuf(c, pb.Snapshots(snaps))
+	}
+	return response.Success(c, snaps)
+}
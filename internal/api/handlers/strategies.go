@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/strategies"
+	"github.com/polygo/pkg/response"
+)
+
+// StrategiesHandler serves per-strategy statistics of tagged orders
+type StrategiesHandler struct {
+	tracker *strategies.Tracker
+}
+
+// NewStrategiesHandler creates a new strategies handler
+func NewStrategiesHandler(tracker *strategies.Tracker) *StrategiesHandler {
+	return &StrategiesHandler{tracker: tracker}
+}
+
+// GetStrategies godoc
+// @Summary Get per-strategy statistics
+// @Description Report, per strategy, the caller's orders placed with a strategy tag: how many filled and how much of their size, the USDC volume filled, PnL realized at average cost, and the open positions priced at current midpoints. Fills come from the CLOB user channel (see POLYGO_FILL_NOTIFY_*); orders matched as they were placed count as filled at their limit price.
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Param strategy query string false "Only this strategy"
+// @Success 200 {object} response.Response{data=[]strategies.Stats}
+// @Router /api/v1/analytics/strategies [get]
+func (h *StrategiesHandler) GetStrategies(c *fiber.Ctx) error {
+	stats := h.tracker.Stats(callerKey(c))
+	if name := c.Query("strategy"); name != "" {
+		filtered := make([]strategies.Stats, 0, 1)
+		for _, s := range stats {
+			if s.Strategy == name {
+				filtered = append(filtered, s)
+			}
+		}
+		stats = filtered
+	}
+	return response.Success(c, stats)
+}
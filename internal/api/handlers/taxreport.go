@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/api/middleware"
+	"github.com/polygo/internal/taxreport"
+	"github.com/polygo/pkg/response"
+	"github.com/polygo/pkg/validate"
+)
+
+// TaxReportHandler serves cost-basis reports
+type TaxReportHandler struct {
+	reporter *taxreport.Reporter
+}
+
+// NewTaxReportHandler creates a new tax report handler
+func NewTaxReportHandler(r *taxreport.Reporter) *TaxReportHandler {
+	return &TaxReportHandler{reporter: r}
+}
+
+// GetTaxReport godoc
+// @Summary Get a cost-basis report
+// @Description Match a wallet's sales and redemptions against its purchases, first in first out or last in first out, and report the gains realized in a calendar year (UTC) per disposal and per market, with short- and long-term totals and the lots still held at the end of the year. Built from the wallet's Data API activity; splits and merges are counted in ignored, not matched. With format=csv the disposals are returned as a CSV attachment.
+// @Tags User Data
+// @Accept json
+// @Produce json,text/csv
+// @Param address path string true "Wallet address"
+// @Param year query int false "Calendar year (default: the current year)"
+// @Param method query string false "Lot matching: fifo or lifo" default(fifo)
+// @Param format query string false "json or csv" default(json)
+// @Success 200 {object} response.Response{data=taxreport.Report}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/reports/tax/{address} [get]
+func (h *TaxReportHandler) GetTaxReport(c *fiber.Ctx) error {
+	now := time.Now().UTC()
+	year := c.QueryInt("year", now.Year())
+	if year < 2020 || year > now.Year() {
+		return response.BadRequest(c, "Invalid year, use a year from 2020 to "+strconv.Itoa(now.Year()))
+	}
+	format := c.Query("format", "json")
+	if format != "json" && format != "csv" {
+		return response.BadRequest(c, "Invalid format, use json or csv")
+	}
+
+	reporter := h.reporter
+	if t := middleware.GetTenant(c); t != nil {
+		reporter = reporter.Namespace(t.ID)
+	}
+	report, err := reporter.Generate(c.Params("address"), year, c.Query("method", taxreport.MethodFIFO))
+	switch {
+	case errors.Is(err, validate.ErrInvalidAddress):
+		return response.BadRequest(c, "A valid 0x wallet address is required")
+	case errors.Is(err, taxreport.ErrInvalidMethod):
+		return response.BadRequest(c, "Invalid method, use fifo or lifo")
+	case err != nil:
+		return errorResponse(c, err)
+	}
+
+	if format == "json" {
+		return response.Success(c, report)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		return errorResponse(c, err)
+	}
+	c.Attachment("tax-" + report.Address + "-" + strconv.Itoa(year) + "-" + report.Method + ".csv")
+	return c.Send(buf.Bytes())
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/tenant"
+	"github.com/polygo/pkg/response"
+)
+
+// defaultUsageDays is how many days a usage report covers by default
+const defaultUsageDays = 30
+
+// TenantsHandler reports tenants and their usage
+type TenantsHandler struct {
+	registry *tenant.Registry
+}
+
+// NewTenantsHandler creates a new tenants handler
+func NewTenantsHandler(registry *tenant.Registry) *TenantsHandler {
+	return &TenantsHandler{registry: registry}
+}
+
+// ListTenants godoc
+// @Summary List tenants
+// @Description List the configured tenants with their rate limit and how many API keys belong to each (the keys are not shown)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=[]tenant.Tenant}
+// @Failure 401 {object} response.Response
+// @Router /admin/tenants [get]
+func (h *TenantsHandler) ListTenants(c *fiber.Ctx) error {
+	return response.Success(c, h.registry.List())
+}
+
+// GetUsage godoc
+// @Summary Get a tenant's usage
+// @Description Get a tenant's requests, errors, rate-limited requests and bytes per UTC day, with totals and requests per route. Covers the last 30 days unless from/to are given.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param from query string false "First day, YYYY-MM-DD (UTC)"
+// @Param to query string false "Last day, YYYY-MM-DD (UTC); default today"
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=tenant.Report}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/tenants/{id}/usage [get]
+func (h *TenantsHandler) GetUsage(c *fiber.Ctx) error {
+	to := time.Now().UTC()
+	if s := c.Query("to"); s != "" {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return response.BadRequest(c, "to must be a date like 2024-01-31")
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, 1-defaultUsageDays)
+	if s := c.Query("from"); s != "" {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return response.BadRequest(c, "from must be a date like 2024-01-01")
+		}
+		from = t
+	}
+	if from.After(to) {
+		return response.BadRequest(c, "from must not be after to")
+	}
+
+	report, err := h.registry.Usage(c.Params("id"), from, to)
+	if err != nil {
+		return response.NotFound(c, "Tenant not found")
+	}
+	return response.Success(c, report)
+}
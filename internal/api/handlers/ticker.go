@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"github.com/gofiber/websocket/v2"
+	"github.com/polygo/internal/ticker"
+	"github.com/polygo/internal/wsbuffer"
+)
+
+// TickerHandler streams the throttled headline price ticker
+type TickerHandler struct {
+	ticker  *ticker.Ticker
+	buffers *wsbuffer.Pool
+}
+
+// NewTickerHandler creates a new ticker handler; frames waiting for slow
+// clients are held in buffers
+func NewTickerHandler(t *ticker.Ticker, buffers *wsbuffer.Pool) *TickerHandler {
+	return &TickerHandler{ticker: t, buffers: buffers}
+}
+
+// HandleTickerWS streams midpoint changes for the top markets by volume
+// @Summary Headline Price Ticker WebSocket
+// @Description Compact midpoint stream for the top markets by 24h volume, throttled server-side to at most one update per token per interval. A snapshot frame is sent on connect, then batched "ticker" frames with only the tokens that changed ({"m": market ID, "t": token ID, "p": midpoint}).
+// @Tags WebSocket
+// @Param encoding query string false "Downstream encoding: json (default) or msgpack for binary frames"
+// @Router /ws/ticker [get]
+func (h *TickerHandler) HandleTickerWS(c *websocket.Conn) {
+	out := h.buffers.Add(c)
+	defer out.Close()
+
+	h.ticker.Subscribe(out, connEncoding(c))
+	defer h.ticker.Unsubscribe(out)
+
+	serveFeed(c, out)
+}
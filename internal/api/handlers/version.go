@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/api/middleware"
+	"github.com/polygo/internal/shape"
+	"github.com/polygo/pkg/response"
+)
+
+// Handlers serve /api/v1 and /api/v2 alike. v1 relays upstream payloads as
+// they are; v2 answers with the typed models in shape, inside the standard
+// envelope. Both go through relay or send so the versions cannot drift.
+
+// typed reports whether the request is answered with typed models
+func typed(c *fiber.Ctx) bool {
+	return middleware.GetAPIVersion(c) >= 2
+}
+
+// relay sends an upstream payload: raw on v1, shaped on v2 with meta and
+// the cache status from X-Cache
+func relay(c *fiber.Ctx, s shape.Shaper, data []byte, meta *response.Meta) error {
+	if !typed(c) {
+		return response.Raw(c, data)
+	}
+
+	v, err := s(data)
+	if errors.Is(err, shape.ErrNotFound) {
+		return response.NotFound(c, "Not found")
+	}
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if c.GetRespHeader("X-Cache") == "HIT" {
+		if meta == nil {
+			meta = &response.Meta{}
+		}
+		meta.CacheHit = true
+	}
+	return response.SuccessWithMeta(c, v, meta)
+}
+
+// send sends a value built from upstream data: as is on v1, and on v2
+// through the shaper of the payloads it was built from
+func send(c *fiber.Ctx, s shape.Shaper, v interface{}, meta *response.Meta) error {
+	if !typed(c) {
+		return response.SuccessWithMeta(c, v, meta)
+	}
+
+	data, err := sonic.Marshal(v)
+	if err != nil {
+		return response.InternalError(c, err)
+	}
+	return relay(c, s, data, meta)
+}
+
+// cacheHeader reports a cache hit or miss in X-Cache
+func cacheHeader(c *fiber.Ctx, hit bool) {
+	if hit {
+		c.Set("X-Cache", "HIT")
+	} else {
+		c.Set("X-Cache", "MISS")
+	}
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/polygo/internal/watchlist"
+	"github.com/polygo/internal/wsbuffer"
+	"github.com/polygo/pkg/response"
+	"github.com/polygo/pkg/validate"
+)
+
+// WatchlistHandler handles wallet and market watchlist endpoints
+type WatchlistHandler struct {
+	watchlist *watchlist.Watchlist
+	buffers   *wsbuffer.Pool
+}
+
+// NewWatchlistHandler creates a new watchlist handler; frames waiting for
+// slow /ws/watchlist clients are held in buffers
+func NewWatchlistHandler(w *watchlist.Watchlist, buffers *wsbuffer.Pool) *WatchlistHandler {
+	return &WatchlistHandler{watchlist: w, buffers: buffers}
+}
+
+// AddWalletRequest represents a request to watch a wallet
+type AddWalletRequest struct {
+	Address string `json:"address"`
+	Label   string `json:"label,omitempty"`
+}
+
+// AddWallet godoc
+// @Summary Watch a wallet
+// @Description Poll a wallet's trades and push new ones to /ws/watchlist subscribers and wallet.trade webhooks
+// @Tags Watchlist
+// @Accept json
+// @Produce json
+// @Param request body AddWalletRequest true "Wallet to watch"
+// @Success 200 {object} response.Response{data=watchlist.Wallet}
+// @Failure 400 {object} response.Response
+// @Failure 503 {object} response.Response
+// @Router /api/v1/watchlist/wallets [post]
+func (h *WatchlistHandler) AddWallet(c *fiber.Ctx) error {
+	var req AddWalletRequest
+	if err := sonic.Unmarshal(c.Body(), &req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	wallet, err := h.watchlist.Add(req.Address, req.Label, callerKey(c))
+	switch {
+	case errors.Is(err, validate.ErrInvalidAddress):
+		return response.BadRequest(c, "A valid 0x wallet address is required")
+	case errors.Is(err, watchlist.ErrFull):
+		return response.Error(c, fiber.StatusServiceUnavailable, "WATCHLIST_FULL", "Watchlist is full", "")
+	case err != nil:
+		return errorResponse(c, err)
+	}
+
+	return response.Success(c, wallet)
+}
+
+// ListWallets godoc
+// @Summary List watched wallets
+// @Description List the wallets watched by the caller
+// @Tags Watchlist
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=[]watchlist.Wallet}
+// @Router /api/v1/watchlist/wallets [get]
+func (h *WatchlistHandler) ListWallets(c *fiber.Ctx) error {
+	return response.Success(c, h.watchlist.Wallets(callerKey(c)))
+}
+
+// RemoveWallet godoc
+// @Summary Stop watching a wallet
+// @Description Remove a wallet from the caller's watchlist
+// @Tags Watchlist
+// @Accept json
+// @Produce json
+// @Param address path string true "Wallet address"
+// @Success 200 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/watchlist/wallets/{address} [delete]
+func (h *WatchlistHandler) RemoveWallet(c *fiber.Ctx) error {
+	address := c.Params("address")
+	if !h.watchlist.Remove(address, callerKey(c)) {
+		return response.NotFound(c, "Wallet not watched")
+	}
+	return response.Success(c, fiber.Map{"deleted": strings.ToLower(address)})
+}
+
+// AddMarketRequest represents a request to watch a market
+type AddMarketRequest struct {
+	MarketID string `json:"market_id"`
+}
+
+// AddMarket godoc
+// @Summary Watch a market
+// @Description Stream price, volume and resolution changes of a market on /ws/watchlist
+// @Tags Watchlist
+// @Accept json
+// @Produce json
+// @Param request body AddMarketRequest true "Market to watch"
+// @Success 200 {object} response.Response{data=watchlist.WatchedMarket}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 503 {object} response.Response
+// @Router /api/v1/watchlist/markets [post]
+func (h *WatchlistHandler) AddMarket(c *fiber.Ctx) error {
+	var req AddMarketRequest
+	if err := sonic.Unmarshal(c.Body(), &req); err != nil || req.MarketID == "" {
+		return response.BadRequest(c, "market_id is required")
+	}
+
+	market, err := h.watchlist.AddMarket(req.MarketID, callerKey(c))
+	switch {
+	case errors.Is(err, watchlist.ErrUnknownMarket):
+		return response.NotFound(c, "Market not found")
+	case errors.Is(err, watchlist.ErrFull):
+		return response.Error(c, fiber.StatusServiceUnavailable, "WATCHLIST_FULL", "Watchlist is full", "")
+	case err != nil:
+		return errorResponse(c, err)
+	}
+
+	return response.Success(c, market)
+}
+
+// ListMarkets godoc
+// @Summary List watched markets
+// @Description List the markets watched by the caller
+// @Tags Watchlist
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=[]watchlist.WatchedMarket}
+// @Router /api/v1/watchlist/markets [get]
+func (h *WatchlistHandler) ListMarkets(c *fiber.Ctx) error {
+	return response.Success(c, h.watchlist.Markets(callerKey(c)))
+}
+
+// RemoveMarket godoc
+// @Summary Stop watching a market
+// @Description Remove a market from the caller's watchlist
+// @Tags Watchlist
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Success 200 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/watchlist/markets/{id} [delete]
+func (h *WatchlistHandler) RemoveMarket(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if !h.watchlist.RemoveMarket(id, callerKey(c)) {
+		return response.NotFound(c, "Market not watched")
+	}
+	return response.Success(c, fiber.Map{"deleted": id})
+}
+
+// HandleWatchlistWS streams updates for the caller's watchlist
+// @Summary Watchlist WebSocket
+// @Description Streams the caller's watchlist (identified by the POLY-API-KEY header, if sent). Watched markets produce "market_snapshot" frames on connect and "market_update" frames listing what changed (price, volume, resolution); watched wallets produce "wallet_trade" frames with each new trade.
+// @Tags WebSocket
+// @Param wallets query string false "Comma-separated addresses whose trades to receive instead of the caller's wallets"
+// @Param encoding query string false "Downstream encoding: json (default) or msgpack for binary frames"
+// @Router /ws/watchlist [get]
+func (h *WatchlistHandler) HandleWatchlistWS(c *websocket.Conn) {
+	var wallets []string
+	if q := c.Query("wallets"); q != "" {
+		wallets = strings.Split(q, ",")
+	}
+
+	out := h.buffers.Add(c)
+	defer out.Close()
+
+	h.watchlist.Subscribe(out, connEncoding(c), connKey(c), wallets)
+	defer h.watchlist.Unsubscribe(out)
+
+	serveFeed(c, out)
+}
@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/polygo/internal/api/middleware"
+	"github.com/polygo/internal/audit"
+	"github.com/polygo/internal/deadman"
+	"github.com/polygo/internal/tenant"
+	"github.com/polygo/internal/webhooks"
+	"github.com/polygo/internal/wsbuffer"
+	"github.com/polygo/internal/wsfanout"
+	"github.com/polygo/pkg/polygoclient"
+	"github.com/polygo/pkg/response"
+)
+
+// WebhooksHandler handles webhook subscription and delivery endpoints
+type WebhooksHandler struct {
+	dispatcher *webhooks.Dispatcher
+	deadMan    *deadman.Switches
+	audit      *audit.Log
+	buffers    *wsbuffer.Pool
+}
+
+// NewWebhooksHandler creates a new webhooks handler; frames waiting for
+// slow /ws/events clients are held in buffers
+func NewWebhooksHandler(dispatcher *webhooks.Dispatcher, deadMan *deadman.Switches, auditLog *audit.Log, buffers *wsbuffer.Pool) *WebhooksHandler {
+	return &WebhooksHandler{dispatcher: dispatcher, deadMan: deadMan, audit: auditLog, buffers: buffers}
+}
+
+// CreateWebhookRequest represents a webhook subscription request
+type CreateWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Tags   []string `json:"tags,omitempty"`   // only market.listed events for markets with one of these tag slugs, and rule.triggered events of these rules
+	Secret string   `json:"secret,omitempty"` // signs deliveries; generated when empty
+}
+
+// WebhookWithSecret is a subscription with the secret its deliveries are
+// signed with, returned only when the secret is set
+type WebhookWithSecret struct {
+	webhooks.Subscription
+	Secret string `json:"secret"`
+}
+
+// CreateWebhook godoc
+// @Summary Register a webhook
+// @Description Subscribe a URL to PolyGo events (e.g. order.created, order.*, market.listed, or * for all). tags narrows market.listed to markets with one of the given tag slugs, and rule.triggered to the named rules. Deliveries carry X-PolyGo-Timestamp and X-PolyGo-Signature, v1= and the hex HMAC-SHA256 of "<timestamp>.<body>" keyed by the subscription's secret. The secret is returned here only; it is generated unless given (at least 16 characters).
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param request body CreateWebhookRequest true "Webhook subscription"
+// @Success 200 {object} response.Response{data=WebhookWithSecret}
+// @Failure 400 {object} response.Response
+// @Router /api/v1/webhooks [post]
+func (h *WebhooksHandler) CreateWebhook(c *fiber.Ctx) error {
+	var req CreateWebhookRequest
+	if err := sonic.Unmarshal(c.Body(), &req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return response.BadRequest(c, "A valid http(s) URL is required")
+	}
+	if req.Secret != "" && len(req.Secret) < webhooks.MinSecretLength {
+		return response.BadRequest(c, "secret must be at least 16 characters")
+	}
+
+	sub := h.dispatcher.Subscribe(req.URL, req.Events, req.Tags, callerKey(c), req.Secret)
+	return response.Success(c, WebhookWithSecret{Subscription: *sub, Secret: sub.Secret})
+}
+
+// RotateWebhookSecretRequest sets a webhook's new secret
+type RotateWebhookSecretRequest struct {
+	Secret string `json:"secret,omitempty"` // generated when empty
+}
+
+// RotateWebhookSecret godoc
+// @Summary Rotate a webhook's secret
+// @Description Replace the secret a webhook's deliveries are signed with. For 24 hours deliveries carry a signature with the old secret too (comma-separated in X-PolyGo-Signature), so receivers can switch over without dropping events.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param request body RotateWebhookSecretRequest false "New secret"
+// @Success 200 {object} response.Response{data=WebhookWithSecret}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/webhooks/{id}/secret [post]
+func (h *WebhooksHandler) RotateWebhookSecret(c *fiber.Ctx) error {
+	var req RotateWebhookSecretRequest
+	if len(c.Body()) > 0 {
+		if err := sonic.Unmarshal(c.Body(), &req); err != nil {
+			return response.BadRequest(c, "Invalid request body")
+		}
+	}
+	if req.Secret != "" && len(req.Secret) < webhooks.MinSecretLength {
+		return response.BadRequest(c, "secret must be at least 16 characters")
+	}
+
+	id := c.Params("id")
+	sub, ok := h.dispatcher.Subscription(id)
+	if !ok || (sub.Owner != "" && sub.Owner != callerKey(c)) {
+		return response.NotFound(c, "Webhook not found")
+	}
+
+	rotated, ok := h.dispatcher.RotateSecret(id, req.Secret)
+	if !ok {
+		return response.NotFound(c, "Webhook not found")
+	}
+	return response.Success(c, WebhookWithSecret{Subscription: rotated, Secret: rotated.Secret})
+}
+
+// ListWebhooks godoc
+// @Summary List webhooks
+// @Description List webhook subscriptions owned by the caller
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=[]webhooks.Subscription}
+// @Router /api/v1/webhooks [get]
+func (h *WebhooksHandler) ListWebhooks(c *fiber.Ctx) error {
+	return response.Success(c, h.dispatcher.Subscriptions(callerKey(c)))
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook
+// @Description Remove a webhook subscription
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/webhooks/{id} [delete]
+func (h *WebhooksHandler) DeleteWebhook(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	sub, ok := h.dispatcher.Subscription(id)
+	if !ok || (sub.Owner != "" && sub.Owner != callerKey(c)) {
+		return response.NotFound(c, "Webhook not found")
+	}
+
+	h.dispatcher.Unsubscribe(id)
+	return response.Success(c, fiber.Map{"deleted": id})
+}
+
+// GetDelivery godoc
+// @Summary Trace a webhook delivery
+// @Description Get the status, attempts and originating request ID of a webhook delivery
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Delivery ID"
+// @Success 200 {object} response.Response{data=webhooks.Delivery}
+// @Failure 404 {object} response.Response
+// @Router /api/v1/webhooks/deliveries/{id} [get]
+func (h *WebhooksHandler) GetDelivery(c *fiber.Ctx) error {
+	delivery, ok := h.dispatcher.Delivery(c.Params("id"))
+	if !ok {
+		return response.NotFound(c, "Delivery not found")
+	}
+
+	if sub, ok := h.dispatcher.Subscription(delivery.SubscriptionID); ok && sub.Owner != "" && sub.Owner != callerKey(c) {
+		return response.NotFound(c, "Delivery not found")
+	}
+
+	return response.Success(c, delivery)
+}
+
+// callerKey returns who owns the caller's webhooks, watchlists and orders:
+// their tenant when their API key belongs to one, else the API key, if any
+func callerKey(c *fiber.Ctx) string {
+	return ownerOf(c.Locals("tenant"), c.Locals("auth"))
+}
+
+// connKey is callerKey for a WebSocket connection
+func connKey(c *websocket.Conn) string {
+	return ownerOf(c.Locals("tenant"), c.Locals("auth"))
+}
+
+// connCredentials returns the connection's API credentials when they
+// include the secret, as callerCredentials does for requests
+func connCredentials(c *websocket.Conn) *polygoclient.Credentials {
+	creds, ok := c.Locals("auth").(*middleware.AuthCredentials)
+	if !ok || creds.APISecret == "" {
+		return nil
+	}
+	return &polygoclient.Credentials{APIKey: creds.APIKey, Secret: creds.APISecret, Passphrase: creds.Passphrase}
+}
+
+func ownerOf(t, auth interface{}) string {
+	if t, ok := t.(*tenant.Tenant); ok {
+		return t.Owner()
+	}
+	if creds, ok := auth.(*middleware.AuthCredentials); ok {
+		return creds.APIKey
+	}
+	return ""
+}
+
+// eventsClientMessage is a message from an events WebSocket client
+type eventsClientMessage struct {
+	Type    string  `json:"type"`
+	Timeout float64 `json:"timeout"` // arm_dead_man: seconds
+}
+
+// deadManMessage reports the state of the connection's dead-man's switch
+type deadManMessage struct {
+	Type string `json:"type"`
+	deadman.Status
+	Error string `json:"error,omitempty"`
+}
+
+// HandleEventsWS streams the caller's events (the same envelopes webhooks
+// receive) over a WebSocket, whether or not webhooks are enabled. Clients
+// can arm a dead-man's switch on the connection.
+// @Summary Events WebSocket
+// @Description Stream the caller's events, e.g. order.created or order.expiring. ?events= takes comma-separated types or families (order.*); default all. Send {"type":"arm_dead_man","timeout":10} to cancel all the API key's open orders should the connection drop or go that many seconds without {"type":"heartbeat"}; {"type":"disarm_dead_man"} turns it off. Each is answered with a dead_man message, and a triggered switch publishes order.dead_man. Arming needs the API secret header.
+// @Tags WebSocket
+// @Param events query string false "Event types, e.g. order.*"
+// @Security ApiKeyAuth
+// @Router /ws/events [get]
+func (h *WebhooksHandler) HandleEventsWS(c *websocket.Conn) {
+	enc := connEncoding(c)
+
+	filter := &webhooks.Subscription{Events: []string{"*"}}
+	if q := c.Query("events"); q != "" {
+		filter.Events = strings.Split(q, ",")
+	}
+
+	events, stop := h.dispatcher.Listen(connKey(c))
+	defer stop()
+
+	// Events and replies to the client share its queue
+	out := h.buffers.Add(c)
+	defer out.Close()
+	send := func(v interface{}) {
+		if data, err := sonic.Marshal(v); err == nil {
+			wsfanout.Send(out, enc, data)
+		}
+	}
+
+	// Fires the switch, if armed, once the connection is gone
+	session := h.deadMan.Session(connKey(c), connCredentials(c))
+	defer session.Close()
+
+	// Forwarder: exits when the client is dropped or the listener stops
+	go func() {
+		defer c.Close()
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if filter.Matches(event.Type) {
+					send(event)
+				}
+			case <-out.Done():
+				return
+			}
+		}
+	}()
+
+	// Reader: serves the dead-man's switch; reads also detect disconnects
+	for {
+		_, msg, err := c.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var clientMsg eventsClientMessage
+		if err := sonic.Unmarshal(msg, &clientMsg); err != nil {
+			continue
+		}
+
+		reply := deadManMessage{Type: "dead_man"}
+		switch clientMsg.Type {
+		case "arm_dead_man":
+			status, err := session.Arm(time.Duration(clientMsg.Timeout * float64(time.Second)))
+			if err != nil {
+				reply.Error = err.Error()
+				status = session.Status()
+			} else {
+				h.recordWS(c, "dead_man.arm", msg)
+			}
+			reply.Status = status
+		case "heartbeat", "ping":
+			reply.Status, _ = session.Heartbeat()
+		case "disarm_dead_man":
+			reply.Status = session.Disarm()
+			h.recordWS(c, "dead_man.disarm", msg)
+		default:
+			continue
+		}
+		send(reply)
+	}
+}
+
+// recordWS records an action taken over the connection in the audit log,
+// as the Audit middleware does for requests
+func (h *WebhooksHandler) recordWS(c *websocket.Conn, action string, msg []byte) {
+	creds, ok := c.Locals("auth").(*middleware.AuthCredentials)
+	if !ok || !h.audit.Enabled() {
+		return
+	}
+	h.audit.Record(audit.Entry{
+		Actor:       creds.APIKey,
+		IP:          wsLocal(c, "ws_client_ip"),
+		Action:      action,
+		Method:      "WS",
+		Path:        "/ws/events",
+		PayloadHash: audit.HashPayload(msg),
+	})
+}
@@ -2,35 +2,124 @@ package handlers
 
 import (
 	"log"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
+	"github.com/polygo/internal/api/middleware"
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/idgen"
 	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/wsbuffer"
+	"github.com/polygo/internal/wsframe"
+	"github.com/polygo/internal/wsproto"
+	"github.com/polygo/internal/wsreplay"
+	"github.com/polygo/pkg/response"
 )
 
 // WebSocketHandler handles WebSocket connections
 type WebSocketHandler struct {
 	wsManager   *polymarket.WSManager
 	clients     map[*websocket.Conn]map[string]bool // client -> subscribed markets
+	options     map[*websocket.Conn]wsClientOptions // client -> negotiated options
 	clientsMu   sync.RWMutex
 	broadcast   chan *WSBroadcast
+	done        chan struct{} // closed by Close to stop handleBroadcasts
+	closeOnce   sync.Once
+	books       *polymarket.BookDiffer
+	resolver    *catalog.Resolver
+	buffers     *wsbuffer.Pool
+	replay      *wsreplay.Buffer
+	
+	// Clients and traffic per subscribed market or token, for /admin/ws/stats
+	subscriptions map[string]*subscriptionStats // guarded by clientsMu
+}
+
+// subscriptionStats counts the clients of a market or token and what was
+// broadcast to them. Entries go once the last client leaves.
+type subscriptionStats struct {
+	clients  int // guarded by clientsMu
+	messages atomic.Uint64
+	bytes    atomic.Uint64
+}
+
+// wsClientOptions are the per-connection options negotiated by WSMiddleware
+type wsClientOptions struct {
+	encoding   wsframe.Encoding
+	bookDeltas bool // book messages are sent as deltas with sequence numbers
+	enrich     bool // messages carry the market and outcome of their tokens
+	version    wsproto.Version
+	out        *wsbuffer.Client // frames queued for the client, written in order
+	seq        *clientSeq       // numbers the frames of each subscription as they are queued
+	info       *clientInfo
+}
+
+// clientInfo identifies a connection on /admin/ws/stats
+type clientInfo struct {
+	id          string
+	path        string
+	clientIP    string
+	connectedAt time.Time
+}
+
+// clientSeq is the sub_seq of the last frame queued for each subscription
+// of a client. Every message of a subscription carries one, increasing by
+// one, so a client can tell a message was lost; the server says so itself
+// with a gap notice when it knows. Replies and notices that belong to no
+// subscription are not numbered.
+type clientSeq struct {
+	mu sync.Mutex
+	n  map[string]uint64 // subscription -> sub_seq of its last frame
+}
+
+// snapshot returns the sub_seq of the last frame of each subscription
+func (s *clientSeq) snapshot() map[string]uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]uint64, len(s.n))
+	for sub, n := range s.n {
+		out[sub] = n
+	}
+	return out
+}
+
+// reset starts a subscription's numbering over, for a resubscribe
+func (s *clientSeq) reset(sub string) {
+	s.mu.Lock()
+	delete(s.n, sub)
+	s.mu.Unlock()
 }
 
 // WSBroadcast represents a broadcast message
 type WSBroadcast struct {
-	MarketID string
-	Data     []byte
+	Keys   []string // tokens and market of the message, as clients subscribe to them
+	Data   []byte
+	IsBook bool   // Data is a full order book
+	Delta  []byte // book update for delta clients; nil when no level changed
 }
 
-// NewWebSocketHandler creates a new WebSocket handler
-func NewWebSocketHandler(wsManager *polymarket.WSManager) *WebSocketHandler {
+// NewWebSocketHandler creates a new WebSocket handler. Book deltas carry a
+// full snapshot every bookSnapshotEvery updates per token; frames waiting
+// for slow clients are held in buffers, and recent messages in replay for
+// clients reconnecting with ?since=.
+func NewWebSocketHandler(wsManager *polymarket.WSManager, resolver *catalog.Resolver, bookSnapshotEvery int, buffers *wsbuffer.Pool, replay *wsreplay.Buffer) *WebSocketHandler {
 	h := &WebSocketHandler{
 		wsManager: wsManager,
 		clients:   make(map[*websocket.Conn]map[string]bool),
+		options:   make(map[*websocket.Conn]wsClientOptions),
 		broadcast: make(chan *WSBroadcast, 1000),
+		done:      make(chan struct{}),
+		books:     polymarket.NewBookDiffer(bookSnapshotEvery),
+		resolver:  resolver,
+		buffers:   buffers,
+		replay:    replay,
+		subscriptions: make(map[string]*subscriptionStats),
 	}
 	
 	// Setup callbacks from polymarket WebSocket
@@ -50,49 +139,142 @@ func NewWebSocketHandler(wsManager *polymarket.WSManager) *WebSocketHandler {
 		},
 	)
 	
+	wsManager.ObserveGaps(h.handleGap)
+	
 	// Start broadcast handler
 	go h.handleBroadcasts()
 	
 	return h
 }
 
-// handleUpstreamMessage handles messages from Polymarket WebSocket
+// handleUpstreamMessage handles messages from Polymarket WebSocket. Each
+// message of a batched frame is broadcast on its own, to the clients
+// subscribed to one of its tokens or its market.
 func (h *WebSocketHandler) handleUpstreamMessage(channel polymarket.WSChannel, data []byte) {
-	// Parse message to get market ID
-	var msg struct {
-		Markets []string `json:"markets"`
-		Market  string   `json:"market"`
+	for _, m := range polymarket.ParseMarketFrame(data) {
+		keys := m.Keys()
+		if len(keys) == 0 {
+			continue
+		}
+		
+		// Books are diffed once here, not per client
+		delta, isBook := h.books.Apply(m.Data)
+		
+		select {
+		case h.broadcast <- &WSBroadcast{
+			Keys:   keys,
+			Data:   m.Data,
+			IsBook: isBook,
+			Delta:  delta,
+		}:
+		case <-h.done:
+			return
+		}
 	}
-	
-	if err := sonic.Unmarshal(data, &msg); err != nil {
-		return
+}
+
+// handleGap tells the clients of markets whose upstream connection dropped
+// that messages were lost. The notice is broadcast like a message of those
+// markets, so it is ordered with them and kept for replay.
+func (h *WebSocketHandler) handleGap(markets []string) {
+	select {
+	case h.broadcast <- &WSBroadcast{
+		Keys: markets,
+		Data: gapNotice("upstream_reconnect", markets),
+	}:
+	case <-h.done:
 	}
-	
-	// Broadcast to relevant clients
-	markets := msg.Markets
-	if msg.Market != "" {
-		markets = append(markets, msg.Market)
+}
+
+// gapNotice builds a gap message: updates of markets may be missing, so
+// their books should be resnapshotted
+func gapNotice(reason string, markets []string) []byte {
+	notice, _ := sonic.Marshal(map[string]interface{}{
+		"type":      "gap",
+		"event_id":  idgen.WithPrefix("evt"),
+		"reason":    reason,
+		"markets":   markets,
+		"timestamp": time.Now().UnixMilli(),
+	})
+	return notice
+}
+
+// subscription returns the subscription of a client covering any of keys,
+// or "" if none does
+func subscription(subs map[string]bool, keys []string) string {
+	if subs["*"] {
+		return "*"
 	}
-	
-	for _, marketID := range markets {
-		h.broadcast <- &WSBroadcast{
-			MarketID: marketID,
-			Data:     data,
+	for _, key := range keys {
+		if subs[key] {
+			return key
 		}
 	}
+	return ""
 }
 
-// handleBroadcasts processes broadcast messages
+// handleBroadcasts processes broadcast messages until Close
 func (h *WebSocketHandler) handleBroadcasts() {
-	for msg := range h.broadcast {
+	for {
+		var msg *WSBroadcast
+		select {
+		case msg = <-h.broadcast:
+		case <-h.done:
+			return
+		}
+		
 		h.clientsMu.RLock()
+		
+		// Numbered and kept for replay under the lock, so a client
+		// registering with ?since= gets each message either replayed or
+		// live, never both
+		data := h.replay.Add(msg.Keys, msg.Data, msg.IsBook)
+		
+		// Binary clients share one encoding of the message, and v2 clients
+		// one set of envelopes per variant
+		frame := wsframe.NewMessage(data)
+		deltaFrame := wsframe.NewMessage(msg.Delta)
+		var enrichedFrame *wsframe.Message // built on first use
+		envelopes := make(map[*wsframe.Message][]envelope)
+		
 		for conn, subs := range h.clients {
-			if subs[msg.MarketID] || subs["*"] {
-				go func(c *websocket.Conn, data []byte) {
-					if err := c.WriteMessage(websocket.TextMessage, data); err != nil {
-						log.Printf("Failed to write to WebSocket: %v", err)
+			if key := subscription(subs, msg.Keys); key != "" {
+				opts := h.options[conn]
+				f := frame
+				if msg.IsBook && opts.bookDeltas {
+					if msg.Delta == nil {
+						continue
+					}
+					f = deltaFrame
+				} else if opts.enrich {
+					if enrichedFrame == nil {
+						enrichedFrame = wsframe.NewMessage(h.resolver.Enrich(data))
+					}
+					f = enrichedFrame
+				}
+				
+				frames := []*wsframe.Message{f}
+				if opts.version == wsproto.V2 {
+					if _, ok := envelopes[f]; !ok {
+						envelopes[f] = wrap(f.JSON())
+					}
+					frames = frames[:0]
+					for _, e := range envelopes[f] {
+						if e.wantedBy(subs) {
+							frames = append(frames, e.frame)
+						}
 					}
-				}(conn, msg.Data)
+				}
+				
+				// Queued rather than written, so a slow client holds up
+				// neither the others nor this loop
+				for _, f := range frames {
+					if messageType, data, err := f.Frame(opts.encoding); err == nil && opts.sendFrame(key, messageType, data) {
+						stats := h.subscriptions[key]
+						stats.messages.Add(1)
+						stats.bytes.Add(uint64(len(data)))
+					}
+				}
 			}
 		}
 		h.clientsMu.RUnlock()
@@ -104,45 +286,320 @@ func (h *WebSocketHandler) UpgradeCheck(c *fiber.Ctx) bool {
 	return websocket.IsWebSocketUpgrade(c)
 }
 
-// HandleMarketWS handles WebSocket connections for market updates
-// @Summary Market WebSocket
-// @Description WebSocket endpoint for real-time market updates
-// @Tags WebSocket
-// @Param market_id path string true "Market ID to subscribe"
-// @Router /ws/market/{market_id} [get]
-func (h *WebSocketHandler) HandleMarketWS(c *websocket.Conn) {
-	marketID := c.Params("market_id")
+// register adds a client with its initial subscriptions and options. A
+// client reconnecting with ?since= is first sent the messages it missed.
+func (h *WebSocketHandler) register(c *websocket.Conn, subs map[string]bool) wsClientOptions {
+	opts := wsClientOptions{
+		encoding:   connEncoding(c),
+		bookDeltas: c.Locals("book_deltas") == true,
+		enrich:     c.Locals("enrich") == true,
+		version:    connVersion(c),
+		out:        h.buffers.Add(c),
+		seq:        &clientSeq{n: make(map[string]uint64)},
+		info: &clientInfo{
+			id:          idgen.WithPrefix("ws"),
+			path:        wsLocal(c, "ws_path"),
+			clientIP:    wsLocal(c, "ws_client_ip"),
+			connectedAt: time.Now(),
+		},
+	}
 	
-	// Register client
 	h.clientsMu.Lock()
-	h.clients[c] = map[string]bool{marketID: true}
+	if since, ok := c.Locals("since").(uint64); ok {
+		h.sendReplay(opts, since, subs)
+	}
+	h.clients[c] = make(map[string]bool, len(subs))
+	for key := range subs {
+		h.addSubscription(c, key)
+	}
+	h.options[c] = opts
 	h.clientsMu.Unlock()
+	return opts
+}
+
+// addSubscription subscribes a registered client to a market or token.
+// Caller holds clientsMu.
+func (h *WebSocketHandler) addSubscription(c *websocket.Conn, key string) {
+	if h.clients[c][key] {
+		return
+	}
+	h.clients[c][key] = true
+	stats, ok := h.subscriptions[key]
+	if !ok {
+		stats = &subscriptionStats{}
+		h.subscriptions[key] = stats
+	}
+	stats.clients++
+}
+
+// removeSubscription undoes addSubscription. Caller holds clientsMu.
+func (h *WebSocketHandler) removeSubscription(c *websocket.Conn, key string) {
+	if !h.clients[c][key] {
+		return
+	}
+	delete(h.clients[c], key)
+	if opts, ok := h.options[c]; ok {
+		opts.seq.reset(key)
+	}
+	if stats := h.subscriptions[key]; stats != nil {
+		if stats.clients--; stats.clients <= 0 {
+			delete(h.subscriptions, key)
+		}
+	}
+}
+
+// wsLocal returns a request detail recorded by WSMiddleware
+func wsLocal(c *websocket.Conn, key string) string {
+	s, _ := c.Locals(key).(string)
+	return s
+}
+
+// sendReplay queues the buffered messages after since for a client's
+// subscriptions, then a replayed notice saying whether any may be missing,
+// after a gap notice if so. Books are skipped for delta clients, which get
+// snapshots on subscribe.
+func (h *WebSocketHandler) sendReplay(opts wsClientOptions, since uint64, subs map[string]bool) {
+	entries, complete := h.replay.Since(since, subs)
 	
-	// Subscribe to market on upstream
-	ch, err := h.wsManager.SubscribeMarket(marketID)
+	sent := 0
+	for _, e := range entries {
+		if e.Book && opts.bookDeltas {
+			continue
+		}
+		data := e.Data
+		if opts.enrich {
+			data = h.resolver.Enrich(data)
+		}
+		if !opts.sendFollowed(subscription(subs, e.Keys), data, subs) {
+			return
+		}
+		sent++
+	}
+	
+	if !complete {
+		markets := make([]string, 0, len(subs))
+		for key := range subs {
+			markets = append(markets, key)
+		}
+		sort.Strings(markets)
+		opts.send(gapNotice("replay_incomplete", markets))
+	}
+	
+	notice, _ := sonic.Marshal(map[string]interface{}{
+		"type":      "replayed",
+		"event_id":  idgen.WithPrefix("evt"),
+		"since":     since,
+		"count":     sent,
+		"complete":  complete,
+		"timestamp": time.Now().UnixMilli(),
+	})
+	opts.send(notice)
+}
+
+// unregister removes a client and drops the frames still queued for it
+func (h *WebSocketHandler) unregister(c *websocket.Conn) {
+	h.clientsMu.Lock()
+	if opts, ok := h.options[c]; ok {
+		opts.out.Close()
+	}
+	for key := range h.clients[c] {
+		h.removeSubscription(c, key)
+	}
+	delete(h.clients, c)
+	delete(h.options, c)
+	h.clientsMu.Unlock()
+}
+
+// send queues data that belongs to no subscription, such as a reply or a
+// notice, for the client in its negotiated encoding and protocol version.
+// It reports false once the client is gone or was disconnected for falling
+// behind.
+func (opts wsClientOptions) send(data []byte) bool {
+	return opts.sendFollowed("", data, map[string]bool{"*": true})
+}
+
+// sendFollowed is send for a message of subscription sub to a client
+// following subs, which in v2 only gets the envelopes of the tokens it
+// follows
+func (opts wsClientOptions) sendFollowed(sub string, data []byte, subs map[string]bool) bool {
+	if opts.version != wsproto.V2 {
+		return opts.sendMessage(sub, wsframe.NewMessage(data))
+	}
+	for _, e := range wrap(data) {
+		if e.wantedBy(subs) && !opts.sendMessage(sub, e.frame) {
+			return false
+		}
+	}
+	return true
+}
+
+// sendMessage queues a message of subscription sub in the client's encoding
+func (opts wsClientOptions) sendMessage(sub string, m *wsframe.Message) bool {
+	messageType, payload, err := m.Frame(opts.encoding)
 	if err != nil {
-		log.Printf("Failed to subscribe to market %s: %v", marketID, err)
-		c.Close()
+		return true
+	}
+	return opts.sendFrame(sub, messageType, payload)
+}
+
+// sendFrame queues an encoded frame of subscription sub for the client,
+// stamped with sub and its next sequence number: sub_seq in v1, the
+// envelope's seq in v2. The frame is shared with other clients, so the
+// stamp is queued as a head written before it rather than on a copy.
+// Frames of no subscription, or that cannot be stamped, are queued as is
+// and not counted.
+func (opts wsClientOptions) sendFrame(sub string, messageType int, data []byte) bool {
+	if sub == "" {
+		return opts.out.Send(messageType, data)
+	}
+	key := "sub_seq"
+	if opts.version == wsproto.V2 {
+		key = "seq"
+	}
+	
+	opts.seq.mu.Lock()
+	defer opts.seq.mu.Unlock()
+	head, body, ok := wsframe.Stamp(messageType, data, sub, key, opts.seq.n[sub]+1)
+	if !ok {
+		return opts.out.Send(messageType, data)
+	}
+	opts.seq.n[sub]++
+	return opts.out.SendParts(messageType, head, body)
+}
+
+// envelope is a v2 message ready to be framed
+type envelope struct {
+	token  string
+	market string
+	frame  *wsframe.Message
+}
+
+// wrap returns the v2 envelopes of a message
+func wrap(data []byte) []envelope {
+	wrapped := wsproto.Wrap(data)
+	out := make([]envelope, len(wrapped))
+	for i, w := range wrapped {
+		out[i] = envelope{token: w.Token, market: w.Market, frame: wsframe.NewMessage(w.Data)}
+	}
+	return out
+}
+
+// wantedBy reports whether a client following subs gets the envelope:
+// clients following only some tokens of a market skip the others
+func (e envelope) wantedBy(subs map[string]bool) bool {
+	return e.token == "" || subs["*"] || subs[e.token] || subs[e.market]
+}
+
+// sendBookSnapshots gives a delta client the current books of a market or
+// token ("*" for all) so later deltas have a base to apply to
+func (h *WebSocketHandler) sendBookSnapshots(opts wsClientOptions, market string) {
+	if !opts.bookDeltas {
 		return
 	}
+	for _, data := range h.books.Snapshots(market) {
+		if !opts.sendFollowed(market, data, map[string]bool{"*": true}) {
+			return
+		}
+	}
+}
+
+// connVersion returns the protocol version negotiated by WSMiddleware
+func connVersion(c *websocket.Conn) wsproto.Version {
+	if v, ok := c.Locals("version").(wsproto.Version); ok {
+		return v
+	}
+	return wsproto.V1
+}
+
+// serveFeed serves a one-way stream whose frames a feed queues on out. It
+// returns once the client disconnects, and closes the connection should
+// the feed stop or the client be dropped first.
+func serveFeed(c *websocket.Conn, out *wsbuffer.Client) {
+	go func() {
+		<-out.Done()
+		c.Close()
+	}()
+	
+	// The stream is one-way, but reads detect disconnects
+	for {
+		if _, _, err := c.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// connEncoding returns the encoding negotiated by WSMiddleware
+func connEncoding(c *websocket.Conn) wsframe.Encoding {
+	if enc, ok := c.Locals("encoding").(wsframe.Encoding); ok {
+		return enc
+	}
+	return wsframe.JSON
+}
+
+// HandleMarketWS handles WebSocket connections for market updates
+// @Summary Market WebSocket
+// @Description WebSocket endpoint for real-time market updates. Send {"type":"subscribe","markets":[...],"assets":[...]} to follow more markets or tokens, and "unsubscribe" to stop.
+// @Tags WebSocket
+// @Param market_id path string true "Token ID (asset_id) or market condition ID to subscribe"
+// @Param encoding query string false "Downstream encoding: json (default) or msgpack for binary frames"
+// @Param books query string false "Order book format: full (default) or delta for changed levels with sequence numbers"
+// @Param enrich query bool false "Add the market, question and outcome of each token to trade and price messages"
+// @Param since query integer false "stream_seq of the last message received; messages after it still buffered are sent first, then a replayed notice"
+// @Param version query integer false "Protocol version: 1 (default) for messages as received, 2 for envelopes with type, seq, token and payload"
+// @Router /ws/market/{market_id} [get]
+func (h *WebSocketHandler) HandleMarketWS(c *websocket.Conn) {
+	h.serveSubscriptions(c, c.Params("market_id"))
+}
+
+// HandleTokenWS handles WebSocket connections for outcome token updates
+// @Summary Token WebSocket
+// @Description WebSocket endpoint for real-time updates of one outcome token: its book, price changes and trades. Send {"type":"subscribe","assets":[...]} to follow more tokens, and "unsubscribe" to stop.
+// @Tags WebSocket
+// @Param token_id path string true "Token ID (asset_id) to subscribe"
+// @Param encoding query string false "Downstream encoding: json (default) or msgpack for binary frames"
+// @Param books query string false "Order book format: full (default) or delta for changed levels with sequence numbers"
+// @Param enrich query bool false "Add the market, question and outcome of each token to trade and price messages"
+// @Param since query integer false "stream_seq of the last message received; messages after it still buffered are sent first, then a replayed notice"
+// @Param version query integer false "Protocol version: 1 (default) for messages as received, 2 for envelopes with type, seq, token and payload"
+// @Router /ws/token/{token_id} [get]
+func (h *WebSocketHandler) HandleTokenWS(c *websocket.Conn) {
+	h.serveSubscriptions(c, c.Params("token_id"))
+}
+
+// clientMessage is a request from a downstream client. Markets and assets
+// are both subscription keys, since messages are routed by token and by
+// market; assets is the name clients following tokens use.
+type clientMessage struct {
+	Type    string   `json:"type"`
+	Markets []string `json:"markets"`
+	Assets  []string `json:"assets"`
+}
+
+// keys returns the markets and tokens named by the message
+func (m *clientMessage) keys() []string {
+	return append(append([]string(nil), m.Markets...), m.Assets...)
+}
+
+// serveSubscriptions serves a client following key, and whatever markets
+// and tokens it subscribes to later, until it disconnects
+func (h *WebSocketHandler) serveSubscriptions(c *websocket.Conn, key string) {
+	// Register client
+	opts := h.register(c, map[string]bool{key: true})
+	upstream := make(map[string]chan []byte)
 	
 	// Cleanup on disconnect
 	defer func() {
-		h.wsManager.UnsubscribeMarket(marketID, ch)
-		h.clientsMu.Lock()
-		delete(h.clients, c)
-		h.clientsMu.Unlock()
+		for k, ch := range upstream {
+			h.wsManager.UnsubscribeMarket(k, ch)
+		}
+		h.unregister(c)
 		c.Close()
 	}()
 	
-	// Forward messages from upstream
-	go func() {
-		for data := range ch {
-			if err := c.WriteMessage(websocket.TextMessage, data); err != nil {
-				return
-			}
-		}
-	}()
+	if !h.follow(upstream, key) {
+		return
+	}
+	h.sendBookSnapshots(opts, key)
 	
 	// Handle incoming messages from client
 	for {
@@ -152,56 +609,94 @@ func (h *WebSocketHandler) HandleMarketWS(c *websocket.Conn) {
 			return
 		}
 		
-		// Parse client message
-		var clientMsg struct {
-			Type    string   `json:"type"`
-			Markets []string `json:"markets"`
-		}
-		
+		var clientMsg clientMessage
 		if err := sonic.Unmarshal(msg, &clientMsg); err != nil {
 			continue
 		}
 		
 		switch clientMsg.Type {
 		case "subscribe":
-			for _, m := range clientMsg.Markets {
+			for _, k := range clientMsg.keys() {
+				if !h.follow(upstream, k) {
+					continue
+				}
 				h.clientsMu.Lock()
-				h.clients[c][m] = true
+				h.addSubscription(c, k)
 				h.clientsMu.Unlock()
-				h.wsManager.SubscribeMarket(m)
+				h.sendBookSnapshots(opts, k)
 			}
 		case "unsubscribe":
-			for _, m := range clientMsg.Markets {
+			for _, k := range clientMsg.keys() {
 				h.clientsMu.Lock()
-				delete(h.clients[c], m)
+				h.removeSubscription(c, k)
 				h.clientsMu.Unlock()
+				if ch, ok := upstream[k]; ok {
+					h.wsManager.UnsubscribeMarket(k, ch)
+					delete(upstream, k)
+				}
 			}
 		case "ping":
-			pong := map[string]interface{}{
-				"type":      "pong",
-				"timestamp": time.Now().UnixMilli(),
-			}
-			data, _ := sonic.Marshal(pong)
-			c.WriteMessage(websocket.TextMessage, data)
+			sendPong(opts)
+		}
+	}
+}
+
+// follow keeps a market or token subscribed upstream for a client, once.
+// Updates reach the client through the broadcast, which also applies its
+// book and enrichment options, so the subscription's channel is drained.
+func (h *WebSocketHandler) follow(upstream map[string]chan []byte, key string) bool {
+	if _, ok := upstream[key]; ok {
+		return true
+	}
+	ch, err := h.wsManager.SubscribeMarket(key)
+	if err != nil {
+		log.Printf("Failed to subscribe to market %s: %v", key, err)
+		return false
+	}
+	upstream[key] = ch
+	go func() {
+		for range ch {
 		}
+	}()
+	return true
+}
+
+// sendPong answers a client ping
+func sendPong(opts wsClientOptions) {
+	pong := map[string]interface{}{
+		"type":      "pong",
+		"event_id":  idgen.WithPrefix("evt"),
+		"timestamp": time.Now().UnixMilli(),
 	}
+	data, _ := sonic.Marshal(pong)
+	opts.send(data)
 }
 
 // HandleAllMarketsWS handles WebSocket for all market updates
 // @Summary All Markets WebSocket
 // @Description WebSocket endpoint for all real-time market updates
 // @Tags WebSocket
+// @Param encoding query string false "Downstream encoding: json (default) or msgpack for binary frames"
+// @Param books query string false "Order book format: full (default) or delta for changed levels with sequence numbers"
+// @Param enrich query bool false "Add the market, question and outcome of each token to trade and price messages"
+// @Param since query integer false "stream_seq of the last message received; messages after it still buffered are sent first, then a replayed notice"
+// @Param version query integer false "Protocol version: 1 (default) for messages as received, 2 for envelopes with type, seq, token and payload"
 // @Router /ws/markets [get]
 func (h *WebSocketHandler) HandleAllMarketsWS(c *websocket.Conn) {
 	// Register client for all markets
-	h.clientsMu.Lock()
-	h.clients[c] = map[string]bool{"*": true}
-	h.clientsMu.Unlock()
+	opts := h.register(c, map[string]bool{"*": true})
+	h.sendBookSnapshots(opts, "*")
+	
+	// Upstream subscriptions requested by this client (e.g. a replica
+	// PolyGo asking the primary to follow specific markets); updates reach
+	// it through the "*" broadcast
+	upstream := make(map[string]chan []byte)
 	
 	defer func() {
-		h.clientsMu.Lock()
-		delete(h.clients, c)
-		h.clientsMu.Unlock()
+		for marketID, ch := range upstream {
+			h.wsManager.UnsubscribeMarket(marketID, ch)
+		}
+		h.unregister(c)
 		c.Close()
 	}()
 	
@@ -212,32 +707,214 @@ func (h *WebSocketHandler) HandleAllMarketsWS(c *websocket.Conn) {
 			return
 		}
 		
-		var clientMsg struct {
-			Type string `json:"type"`
-		}
-		
+		var clientMsg clientMessage
 		if err := sonic.Unmarshal(msg, &clientMsg); err != nil {
 			continue
 		}
 		
-		if clientMsg.Type == "ping" {
-			pong := map[string]interface{}{
-				"type":      "pong",
-				"timestamp": time.Now().UnixMilli(),
+		switch clientMsg.Type {
+		case "subscribe":
+			for _, k := range clientMsg.keys() {
+				h.follow(upstream, k)
 			}
-			data, _ := sonic.Marshal(pong)
-			c.WriteMessage(websocket.TextMessage, data)
+		case "ping":
+			sendPong(opts)
 		}
 	}
 }
 
-// WSMiddleware returns middleware for WebSocket upgrade check
+// WSMiddleware returns middleware for WebSocket upgrade check. It also
+// negotiates the downstream encoding from the encoding query parameter.
 func WSMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		if websocket.IsWebSocketUpgrade(c) {
+			enc, ok := wsframe.ParseEncoding(c.Query("encoding"))
+			if !ok {
+				return response.BadRequest(c, "encoding must be json or msgpack")
+			}
+			version, ok := wsproto.ParseVersion(c.Query("version"))
+			if !ok {
+				return response.BadRequest(c, "version must be 1 or 2")
+			}
+			books := c.Query("books", "full")
+			if books != "full" && books != "delta" {
+				return response.BadRequest(c, "books must be full or delta")
+			}
 			c.Locals("allowed", true)
+			c.Locals("encoding", enc)
+			c.Locals("version", version)
+			c.Locals("book_deltas", books == "delta")
+			c.Locals("enrich", c.QueryBool("enrich"))
+			c.Locals("ws_path", strings.Clone(c.Path()))
+			c.Locals("ws_client_ip", strings.Clone(middleware.ClientIP(c)))
+			if since := c.Query("since"); since != "" {
+				seq, err := strconv.ParseUint(since, 10, 64)
+				if err != nil {
+					return response.BadRequest(c, "since must be the stream_seq of the last message received")
+				}
+				c.Locals("since", seq)
+			}
 			return c.Next()
 		}
 		return fiber.ErrUpgradeRequired
 	}
 }
+
+// Close stops the broadcast loop. Call it once the WSManager is closed, as
+// messages arriving later are dropped.
+func (h *WebSocketHandler) Close() {
+	h.closeOnce.Do(func() { close(h.done) })
+}
+
+// Shutdown notifies every connected client that the server is going away,
+// suggesting a reconnect delay, and then sends a close frame.
+func (h *WebSocketHandler) Shutdown(reconnectAfter time.Duration) {
+	notice, _ := sonic.Marshal(map[string]interface{}{
+		"type":               "shutdown",
+		"event_id":           idgen.WithPrefix("evt"),
+		"reason":             "server restarting",
+		"reconnect_after_ms": reconnectAfter.Milliseconds(),
+		"timestamp":          time.Now().UnixMilli(),
+	})
+	closeFrame := websocket.FormatCloseMessage(websocket.CloseServiceRestart, "server restarting")
+	deadline := time.Now().Add(time.Second)
+
+	h.clientsMu.RLock()
+	defer h.clientsMu.RUnlock()
+
+	frame := wsframe.NewMessage(notice)
+	for conn := range h.clients {
+		if messageType, data, err := frame.Frame(h.options[conn].encoding); err == nil {
+			conn.WriteMessage(messageType, data)
+		}
+		conn.WriteControl(websocket.CloseMessage, closeFrame, deadline)
+	}
+}
+
+// WSStatsResponse describes the market WebSocket streams
+type WSStatsResponse struct {
+	Connections   int                   `json:"connections"`
+	Subscriptions []WSSubscriptionStats `json:"subscriptions"` // most bytes sent first
+	Clients       []WSClientStats       `json:"clients"`       // most bytes sent first
+	Buffers       wsbuffer.Stats        `json:"buffers"`
+	Upstream      WSUpstreamStats       `json:"upstream"`
+	Timestamp     int64                 `json:"timestamp"`
+}
+
+// WSSubscriptionStats is the traffic of one subscribed market or token
+type WSSubscriptionStats struct {
+	Key      string `json:"key"` // token ID, market condition ID, or * for all markets
+	Clients  int    `json:"clients"`
+	Messages uint64 `json:"messages"` // broadcast to its clients since the first subscribed
+	Bytes    uint64 `json:"bytes"`
+}
+
+// WSClientStats describes one market stream connection
+type WSClientStats struct {
+	ID            string            `json:"id"`
+	Path          string            `json:"path"`
+	ClientIP      string            `json:"client_ip"`
+	Encoding      string            `json:"encoding"`
+	ConnectedAt   int64             `json:"connected_at"` // unix ms
+	Subscriptions []string          `json:"subscriptions"`
+	SubSeq        map[string]uint64 `json:"sub_seq"` // of the last frame queued, per subscription
+	wsbuffer.ClientStats
+}
+
+// WSUpstreamStats describes the upstream market channel connections
+type WSUpstreamStats struct {
+	Connected       bool                     `json:"connected"`
+	Shards          []polymarket.ShardStatus `json:"shards"`
+	MaxLag          int64                    `json:"max_lag_ms"`
+	DroppedMessages uint64                   `json:"dropped_messages"` // not delivered to a subscriber that fell behind
+	Gaps            uint64                   `json:"gaps"`             // shard drops that lost messages of subscribed markets
+}
+
+// GetStats godoc
+// @Summary WebSocket stream statistics
+// @Description Get the market stream connections with what was sent to each, the clients and traffic of each subscribed market or token, buffer evictions and the upstream lag and drops, computed when requested
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param limit query int false "Most subscriptions and clients listed, busiest first" default(100)
+// @Security AdminAuth
+// @Success 200 {object} response.Response{data=WSStatsResponse}
+// @Failure 401 {object} response.Response
+// @Router /admin/ws/stats [get]
+func (h *WebSocketHandler) GetStats(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 100)
+	if limit <= 0 {
+		limit = 100
+	}
+	
+	stats := WSStatsResponse{
+		Subscriptions: []WSSubscriptionStats{},
+		Clients:       []WSClientStats{},
+		Buffers:       h.buffers.Stats(),
+		Timestamp:     time.Now().UnixMilli(),
+	}
+	
+	h.clientsMu.RLock()
+	stats.Connections = len(h.clients)
+	for key, s := range h.subscriptions {
+		stats.Subscriptions = append(stats.Subscriptions, WSSubscriptionStats{
+			Key:      key,
+			Clients:  s.clients,
+			Messages: s.messages.Load(),
+			Bytes:    s.bytes.Load(),
+		})
+	}
+	for conn, subs := range h.clients {
+		opts := h.options[conn]
+		client := WSClientStats{
+			ID:            opts.info.id,
+			Path:          opts.info.path,
+			ClientIP:      opts.info.clientIP,
+			Encoding:      string(opts.encoding),
+			ConnectedAt:   opts.info.connectedAt.UnixMilli(),
+			Subscriptions: make([]string, 0, len(subs)),
+			ClientStats:   opts.out.Stats(),
+		}
+		for key := range subs {
+			client.Subscriptions = append(client.Subscriptions, key)
+		}
+		sort.Strings(client.Subscriptions)
+		client.SubSeq = opts.seq.snapshot()
+		stats.Clients = append(stats.Clients, client)
+	}
+	h.clientsMu.RUnlock()
+	
+	sort.Slice(stats.Subscriptions, func(i, j int) bool {
+		a, b := stats.Subscriptions[i], stats.Subscriptions[j]
+		if a.Bytes != b.Bytes {
+			return a.Bytes > b.Bytes
+		}
+		return a.Key < b.Key
+	})
+	sort.Slice(stats.Clients, func(i, j int) bool {
+		a, b := stats.Clients[i], stats.Clients[j]
+		if a.SentBytes != b.SentBytes {
+			return a.SentBytes > b.SentBytes
+		}
+		return a.ID < b.ID
+	})
+	if len(stats.Subscriptions) > limit {
+		stats.Subscriptions = stats.Subscriptions[:limit]
+	}
+	if len(stats.Clients) > limit {
+		stats.Clients = stats.Clients[:limit]
+	}
+	
+	stats.Upstream = WSUpstreamStats{
+		Connected: h.wsManager.IsConnected(),
+		Shards:    h.wsManager.Shards(),
+	}
+	for _, shard := range stats.Upstream.Shards {
+		if shard.Lag > stats.Upstream.MaxLag {
+			stats.Upstream.MaxLag = shard.Lag
+		}
+	}
+	stats.Upstream.DroppedMessages, stats.Upstream.Gaps = h.wsManager.Dropped()
+	
+	return response.Success(c, stats)
+}
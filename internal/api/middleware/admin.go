@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/access"
+	"github.com/polygo/pkg/response"
+)
+
+// AdminAuth returns a middleware that requires "Authorization: Bearer <token>"
+// on operator endpoints, or an access key or JWT with the admin role. An
+// empty token leaves them open.
+func AdminAuth(token string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if id := GetIdentity(c); id != nil && id.Role == access.RoleAdmin {
+			c.Locals("admin", true)
+			return c.Next()
+		}
+		if token != "" {
+			given := strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				return response.Unauthorized(c, "Invalid admin token")
+			}
+		}
+
+		c.Locals("admin", true)
+		return c.Next()
+	}
+}
+
+// IsAdmin reports whether the request passed AdminAuth
+func IsAdmin(c *fiber.Ctx) bool {
+	admin, _ := c.Locals("admin").(bool)
+	return admin
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/audit"
+)
+
+// Audit returns a middleware that records the requests action names in
+// the audit log once they are answered, whatever their status: who made
+// them (the API key, else the access key's name, or admin for the admin
+// token), from where, and a hash of the body as sent. Requests without
+// credentials are not recorded. action runs after the handlers, so it can
+// read c.Route().
+func Audit(auditLog *audit.Log, action func(c *fiber.Ctx) string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !auditLog.Enabled() {
+			return c.Next()
+		}
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return c.Next()
+		}
+
+		// Hashed before middleware such as price modes rewrite the body
+		hash := audit.HashPayload(c.Body())
+
+		err := c.Next()
+
+		name := action(c)
+		if name == "" {
+			return err
+		}
+		actor := ""
+		if creds := GetAuthCredentials(c); creds != nil {
+			actor = creds.APIKey
+		} else if id := GetIdentity(c); id != nil && id.Name != "" {
+			actor = id.Name
+		} else if IsAdmin(c) {
+			actor = "admin"
+		}
+		if actor == "" {
+			return err
+		}
+
+		// The error handler has not written the status yet
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			if e, ok := err.(*fiber.Error); ok {
+				status = e.Code
+			}
+		}
+
+		auditLog.Record(audit.Entry{
+			Actor:       actor,
+			IP:          ClientIP(c),
+			Action:      name,
+			Method:      c.Method(),
+			Path:        c.Path(),
+			Status:      status,
+			RequestID:   GetRequestID(c),
+			PayloadHash: hash,
+		})
+		return err
+	}
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/polygo/internal/config"
 	"github.com/polygo/pkg/response"
@@ -25,13 +27,7 @@ type AuthConfig struct {
 // Auth returns a middleware that extracts and validates auth credentials
 func Auth(cfg *config.AuthConfig) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		creds := &AuthCredentials{
-			APIKey:     c.Get(cfg.APIKeyHeader),
-			APISecret:  c.Get(cfg.APISecretHeader),
-			Passphrase: c.Get(cfg.PassphraseHeader),
-			Signature:  c.Get(cfg.SignatureHeader),
-			Timestamp:  c.Get(cfg.TimestampHeader),
-		}
+		creds := credentials(c, cfg)
 		
 		// Check required fields for authenticated endpoints
 		if creds.APIKey == "" {
@@ -56,23 +52,27 @@ func Auth(cfg *config.AuthConfig) fiber.Handler {
 // OptionalAuth extracts auth credentials if present, but doesn't require them
 func OptionalAuth(cfg *config.AuthConfig) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		apiKey := c.Get(cfg.APIKeyHeader)
-		
-		if apiKey != "" {
-			creds := &AuthCredentials{
-				APIKey:     apiKey,
-				APISecret:  c.Get(cfg.APISecretHeader),
-				Passphrase: c.Get(cfg.PassphraseHeader),
-				Signature:  c.Get(cfg.SignatureHeader),
-				Timestamp:  c.Get(cfg.TimestampHeader),
-			}
-			c.Locals("auth", creds)
+		if c.Get(cfg.APIKeyHeader) != "" {
+			c.Locals("auth", credentials(c, cfg))
 		}
 		
 		return c.Next()
 	}
 }
 
+// credentials copies the auth headers out of the request. Fiber reuses the
+// request buffer, and the key outlives the request as the owner of
+// watchlists, alerts and webhooks.
+func credentials(c *fiber.Ctx, cfg *config.AuthConfig) *AuthCredentials {
+	return &AuthCredentials{
+		APIKey:     strings.Clone(c.Get(cfg.APIKeyHeader)),
+		APISecret:  strings.Clone(c.Get(cfg.APISecretHeader)),
+		Passphrase: strings.Clone(c.Get(cfg.PassphraseHeader)),
+		Signature:  strings.Clone(c.Get(cfg.SignatureHeader)),
+		Timestamp:  strings.Clone(c.Get(cfg.TimestampHeader)),
+	}
+}
+
 // GetAuthCredentials retrieves auth credentials from context
 func GetAuthCredentials(c *fiber.Ctx) *AuthCredentials {
 	if creds, ok := c.Locals("auth").(*AuthCredentials); ok {
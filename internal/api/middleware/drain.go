@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/pkg/response"
+)
+
+// Drainer coordinates graceful shutdown: once draining starts new requests
+// are rejected, while requests already in flight are tracked so shutdown
+// can wait for them to finish.
+type Drainer struct {
+	draining atomic.Bool
+	inflight atomic.Int64
+	// RetryAfter is advertised to clients rejected while draining
+	RetryAfter time.Duration
+}
+
+// NewDrainer creates a new drainer
+func NewDrainer(retryAfter time.Duration) *Drainer {
+	return &Drainer{RetryAfter: retryAfter}
+}
+
+// Start switches the drainer into draining mode
+func (d *Drainer) Start() {
+	d.draining.Store(true)
+}
+
+// IsDraining returns true once shutdown has started
+func (d *Drainer) IsDraining() bool {
+	return d.draining.Load()
+}
+
+// InFlight returns the number of tracked requests still running
+func (d *Drainer) InFlight() int64 {
+	return d.inflight.Load()
+}
+
+// Wait blocks until all tracked requests complete or the timeout expires.
+// It returns false if requests were still running at the deadline.
+func (d *Drainer) Wait(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for d.inflight.Load() > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		<-ticker.C
+	}
+	return true
+}
+
+// Reject returns a middleware that refuses new requests while draining
+func (d *Drainer) Reject(skip func(c *fiber.Ctx) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !d.IsDraining() || (skip != nil && skip(c)) {
+			return c.Next()
+		}
+
+		c.Set(fiber.HeaderConnection, "close")
+		return response.Retry(c, fiber.StatusServiceUnavailable,
+			"SHUTTING_DOWN",
+			"Server is shutting down",
+			"Retry the request against another instance",
+			d.RetryAfter)
+	}
+}
+
+// Track returns a middleware that counts the wrapped requests as in flight
+func (d *Drainer) Track() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		d.inflight.Add(1)
+		defer d.inflight.Add(-1)
+		return c.Next()
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/latency"
+)
+
+// Latency returns a middleware that records each request's latency in its
+// route's histograms, split into time spent waiting on upstream and the
+// rest, and logs requests slower than slow (0 disables the log)
+func Latency(recorder *latency.Recorder, slow time.Duration, skip func(c *fiber.Ctx) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if skip != nil && skip(c) {
+			return c.Next()
+		}
+
+		timing := &latency.Timing{}
+		unbind := timing.Bind()
+		start := time.Now()
+
+		err := c.Next()
+
+		total := time.Since(start)
+		unbind()
+		upstream := min(timing.Upstream(), total)
+		// The error handler has not written the status yet
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			if e, ok := err.(*fiber.Error); ok {
+				status = e.Code
+			}
+		}
+
+		route := c.Route().Path
+		recorder.Observe(c.Method(), route, total, upstream, status)
+
+		if slow > 0 && total >= slow {
+			log.Printf("SLOW REQUEST %s %s route=%s status=%d total=%v upstream=%v (%d calls) proxy=%v request_id=%s",
+				c.Method(), c.Path(), route, status, total, upstream, timing.Calls(), total-upstream, GetRequestID(c))
+		}
+		return err
+	}
+}
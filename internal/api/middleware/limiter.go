@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Rate limit algorithms
+const (
+	// FixedWindow counts requests per window starting at a key's first
+	// request. Cheapest, but allows twice the limit across a window edge.
+	FixedWindow = "fixed_window"
+	// SlidingWindow remembers each request for a window (a sliding log), so
+	// no window ever holds more than the limit. Memory grows with the limit.
+	SlidingWindow = "sliding_window"
+	// TokenBucket refills limit tokens per window up to a burst capacity,
+	// smoothing traffic while allowing short bursts.
+	TokenBucket = "token_bucket"
+)
+
+// decision is the outcome of taking one request from a key's quota
+type decision struct {
+	allowed    bool
+	remaining  int
+	reset      time.Duration // until the quota is whole again
+	retryAfter time.Duration // until a rejected request may be retried
+}
+
+// quota is the rate limit state of one key
+type quota interface {
+	take(limit int, now time.Time) decision
+	// idle reports that the quota is whole again, so forgetting it is
+	// the same as keeping it
+	idle(now time.Time) bool
+}
+
+// newQuotaFunc returns the constructor of quotas for algorithm
+func newQuotaFunc(algorithm string, window time.Duration, burst int) (func() quota, error) {
+	switch algorithm {
+	case "", FixedWindow:
+		return func() quota { return &fixedWindow{window: window} }, nil
+	case SlidingWindow:
+		return func() quota { return &slidingWindow{window: window} }, nil
+	case TokenBucket:
+		return func() quota { return &tokenBucket{window: window, burst: burst} }, nil
+	}
+	return nil, fmt.Errorf("unknown rate limit algorithm %q (want %s, %s or %s)", algorithm, FixedWindow, SlidingWindow, TokenBucket)
+}
+
+// fixedWindow counts requests until the window ends
+type fixedWindow struct {
+	mu      sync.Mutex
+	window  time.Duration
+	count   int
+	resetAt time.Time
+}
+
+func (q *fixedWindow) take(limit int, now time.Time) decision {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !now.Before(q.resetAt) {
+		q.count = 0
+		q.resetAt = now.Add(q.window)
+	}
+	reset := q.resetAt.Sub(now)
+	if q.count >= limit {
+		return decision{reset: reset, retryAfter: reset}
+	}
+	q.count++
+	return decision{allowed: true, remaining: limit - q.count, reset: reset}
+}
+
+func (q *fixedWindow) idle(now time.Time) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return !now.Before(q.resetAt)
+}
+
+// slidingWindow keeps the time of every request in the last window,
+// oldest first
+type slidingWindow struct {
+	mu     sync.Mutex
+	window time.Duration
+	hits   []time.Time
+}
+
+func (q *slidingWindow) take(limit int, now time.Time) decision {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// Forget requests that left the window
+	cutoff := now.Add(-q.window)
+	i := 0
+	for i < len(q.hits) && !q.hits[i].After(cutoff) {
+		i++
+	}
+	if i > 0 {
+		q.hits = q.hits[:copy(q.hits, q.hits[i:])]
+	}
+
+	if limit <= 0 {
+		return decision{reset: q.window, retryAfter: q.window}
+	}
+	if len(q.hits) >= limit {
+		// A slot frees when enough of the oldest requests leave the window
+		// to get under the limit, which may have been lowered since
+		return decision{
+			reset:      q.hits[len(q.hits)-1].Add(q.window).Sub(now),
+			retryAfter: q.hits[len(q.hits)-limit].Add(q.window).Sub(now),
+		}
+	}
+	q.hits = append(q.hits, now)
+	return decision{allowed: true, remaining: limit - len(q.hits), reset: q.window}
+}
+
+func (q *slidingWindow) idle(now time.Time) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.hits) == 0 || !q.hits[len(q.hits)-1].After(now.Add(-q.window))
+}
+
+// tokenBucket refills at limit tokens per window up to its capacity: burst,
+// or the limit when burst is not set. A new bucket starts full.
+type tokenBucket struct {
+	mu       sync.Mutex
+	window   time.Duration
+	burst    int
+	tokens   float64
+	capacity float64
+	rate     float64 // tokens per second
+	last     time.Time
+}
+
+func (q *tokenBucket) take(limit int, now time.Time) decision {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.capacity = float64(limit)
+	if q.burst > 0 {
+		q.capacity = float64(q.burst)
+	}
+	q.rate = float64(limit) / q.window.Seconds()
+
+	if q.last.IsZero() {
+		q.tokens = q.capacity
+	} else {
+		q.tokens = min(q.capacity, q.tokens+now.Sub(q.last).Seconds()*q.rate)
+	}
+	q.last = now
+
+	if q.rate <= 0 {
+		return decision{reset: q.window, retryAfter: q.window}
+	}
+	if q.tokens < 1 {
+		return decision{
+			reset:      q.until(q.capacity),
+			retryAfter: q.until(1),
+		}
+	}
+	q.tokens--
+	return decision{allowed: true, remaining: int(q.tokens), reset: q.until(q.capacity)}
+}
+
+// until returns how long the bucket takes to hold n tokens
+func (q *tokenBucket) until(n float64) time.Duration {
+	if q.tokens >= n {
+		return 0
+	}
+	return time.Duration((n - q.tokens) / q.rate * float64(time.Second))
+}
+
+func (q *tokenBucket) idle(now time.Time) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.rate <= 0 || q.tokens+now.Sub(q.last).Seconds()*q.rate >= q.capacity
+}
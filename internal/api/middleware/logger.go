@@ -71,13 +71,14 @@ func LoggerWithConfig(config LoggerConfig) fiber.Handler {
 			timeFormat = "2006-01-02 15:04:05"
 		}
 		
-		log.Printf("[%s] %s %s %d %v %s",
+		log.Printf("[%s] %s %s %d %v %s %s",
 			time.Now().Format(timeFormat),
 			c.Method(),
 			c.Path(),
 			status,
 			latency,
-			c.IP(),
+			ClientIP(c),
+			GetRequestID(c),
 		)
 		
 		c.Set("X-Response-Time", latency.String())
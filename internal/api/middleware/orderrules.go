@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/orderrules"
+	"github.com/polygo/pkg/response"
+)
+
+// OrderRulesCheck returns a middleware that rejects orders breaking their
+// market's tick size, price bounds or minimum size with 400, naming the
+// offending order. Must run after ValidateBody.
+func OrderRulesCheck(checker *orderrules.Checker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !checker.Enabled() {
+			return c.Next()
+		}
+
+		orders, field, ok := parseOrders(c.Body())
+		if !ok {
+			return c.Next()
+		}
+
+		var rejected *orderrules.Error
+		if !errors.As(checker.Check(orders), &rejected) {
+			return c.Next()
+		}
+		return rejectRules(c, rejected, field)
+	}
+}
+
+// rejectRules responds to an order breaking its market's rules
+func rejectRules(c *fiber.Ctx, rejected *orderrules.Error, field string) error {
+	return response.Error(c, fiber.StatusBadRequest, rejected.Code, rejected.Message, field+"["+strconv.Itoa(rejected.Order)+"]")
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/orderrules"
+	"github.com/polygo/internal/risk"
+	"github.com/polygo/internal/throttle"
+)
+
+// PreTradeRejection responds to orders a pretrade.Chain rejected as the
+// /orders routes respond to them, pointing at the offending order or
+// field. It reports false, and responds nothing, for other errors.
+func PreTradeRejection(c *fiber.Ctx, err error, field string) (bool, error) {
+	var rules *orderrules.Error
+	var throttled *throttle.Error
+	var risky *risk.Error
+	switch {
+	case errors.As(err, &rules):
+		return true, rejectRules(c, rules, field)
+	case errors.As(err, &throttled):
+		return true, rejectThrottled(c, throttled, field)
+	case errors.As(err, &risky):
+		return true, rejectRisk(c, risky, field)
+	}
+	return false, nil
+}
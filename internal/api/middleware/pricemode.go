@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/pkg/response"
+	"github.com/polygo/pkg/validate"
+)
+
+var priceModeKey = []byte(`"price_mode"`)
+
+// ResolvePriceModes returns a middleware that prices orders carrying a
+// price_mode against the live book and rewrites the body with the resolved
+// prices, so validation, order rules and risk checks see them as if the
+// caller had sent them. Must run before ValidateBody.
+func ResolvePriceModes(resolver *polymarket.PriceResolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		body := c.Body()
+		if !bytes.Contains(body, priceModeKey) {
+			return c.Next()
+		}
+
+		orders, field, ok := parseOrders(body)
+		if !ok {
+			return c.Next()
+		}
+
+		err := resolver.Resolve(orders)
+		var modeErr *polymarket.PriceModeError
+		switch {
+		case errors.As(err, &modeErr):
+			path := "price_mode"
+			if field == "legs" || bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+				path = field + "[" + strconv.Itoa(modeErr.Order) + "].price_mode"
+			}
+			if errors.Is(err, polymarket.ErrNoAnchorPrice) || errors.Is(err, polymarket.ErrPriceOutOfRange) {
+				return response.Error(c, fiber.StatusUnprocessableEntity, "PRICE_UNRESOLVABLE", "Cannot resolve price_mode: "+modeErr.Err.Error(), path)
+			}
+			return response.ValidationFailed(c, validate.Errors{{Field: path, Rule: "price_mode", Message: modeErr.Err.Error()}})
+		case err != nil:
+			return response.Retry(c, fiber.StatusServiceUnavailable, "BOOK_UNAVAILABLE", "Order book unavailable to resolve price_mode", err.Error(), 0)
+		}
+
+		var resolved interface{} = orders
+		switch {
+		case field == "legs":
+			resolved = fiber.Map{"legs": orders}
+		case !bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")):
+			resolved = orders[0]
+		}
+		data, err := sonic.Marshal(resolved)
+		if err != nil {
+			return response.InternalError(c, err)
+		}
+		c.Request().SetBody(data)
+		return c.Next()
+	}
+}
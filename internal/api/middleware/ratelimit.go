@@ -1,6 +1,11 @@
 package middleware
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,40 +19,64 @@ type RateLimitConfig struct {
 	Max int
 	// Window duration
 	Window time.Duration
+	// Algorithm: FixedWindow (default), SlidingWindow or TokenBucket
+	Algorithm string
+	// Token bucket capacity (0 = the limit)
+	Burst int
+	// Limits of their own for path prefixes, counted apart from the rest
+	Routes []RouteRateLimit
 	// Key generator function
 	KeyGenerator func(c *fiber.Ctx) string
+	// Max requests per window for a request's key outside Routes (0 = Max)
+	MaxFor func(c *fiber.Ctx) int
 	// Skip function
 	Skip func(c *fiber.Ctx) bool
 }
 
-// rateLimitEntry holds rate limit state for a key
-type rateLimitEntry struct {
-	count     int
-	resetAt   time.Time
-	mu        sync.Mutex
+// RouteRateLimit limits requests whose path starts with Prefix; the
+// longest matching prefix applies
+type RouteRateLimit struct {
+	Prefix    string
+	Algorithm string        // "" = the config's Algorithm
+	Max       int           // 0 = the config's Max
+	Window    time.Duration // 0 = the config's Window
+	Burst     int           // 0 = the config's Burst
 }
 
-// rateLimiter holds all rate limit entries
+// rateLimiter holds the quotas of all keys under one algorithm
 type rateLimiter struct {
-	entries map[string]*rateLimitEntry
-	mu      sync.RWMutex
-	config  RateLimitConfig
+	entries  map[string]quota
+	mu       sync.RWMutex
+	newQuota func() quota
+	max      int
+	policy   string // RateLimit-Policy parameters after the limit
 }
 
 // newRateLimiter creates a new rate limiter
-func newRateLimiter(config RateLimitConfig) *rateLimiter {
+func newRateLimiter(algorithm string, limit int, window time.Duration, burst int) (*rateLimiter, error) {
+	newQuota, err := newQuotaFunc(algorithm, window, burst)
+	if err != nil {
+		return nil, err
+	}
+	
+	policy := ";w=" + strconv.Itoa(ceilSeconds(window))
+	if algorithm == TokenBucket && burst > 0 {
+		policy += ";burst=" + strconv.Itoa(burst)
+	}
 	rl := &rateLimiter{
-		entries: make(map[string]*rateLimitEntry),
-		config:  config,
+		entries:  make(map[string]quota),
+		newQuota: newQuota,
+		max:      limit,
+		policy:   policy,
 	}
 	
 	// Start cleanup goroutine
 	go rl.cleanup()
 	
-	return rl
+	return rl, nil
 }
 
-// cleanup removes expired entries
+// cleanup removes quotas that are whole again
 func (r *rateLimiter) cleanup() {
 	ticker := time.NewTicker(time.Minute)
 	defer ticker.Stop()
@@ -56,7 +85,7 @@ func (r *rateLimiter) cleanup() {
 		r.mu.Lock()
 		now := time.Now()
 		for key, entry := range r.entries {
-			if now.After(entry.resetAt) {
+			if entry.idle(now) {
 				delete(r.entries, key)
 			}
 		}
@@ -64,46 +93,43 @@ func (r *rateLimiter) cleanup() {
 	}
 }
 
-// check checks if request is allowed
-func (r *rateLimiter) check(key string) (bool, int, time.Time) {
+// take takes one request from key's quota
+func (r *rateLimiter) take(key string, limit int, now time.Time) decision {
 	r.mu.RLock()
 	entry, exists := r.entries[key]
 	r.mu.RUnlock()
 	
-	now := time.Now()
-	
 	if !exists {
 		r.mu.Lock()
-		entry = &rateLimitEntry{
-			count:   1,
-			resetAt: now.Add(r.config.Window),
+		if entry, exists = r.entries[key]; !exists {
+			entry = r.newQuota()
+			r.entries[key] = entry
 		}
-		r.entries[key] = entry
 		r.mu.Unlock()
-		return true, r.config.Max - 1, entry.resetAt
 	}
 	
-	entry.mu.Lock()
-	defer entry.mu.Unlock()
-	
-	// Reset if window expired
-	if now.After(entry.resetAt) {
-		entry.count = 1
-		entry.resetAt = now.Add(r.config.Window)
-		return true, r.config.Max - 1, entry.resetAt
-	}
-	
-	// Check limit
-	if entry.count >= r.config.Max {
-		return false, 0, entry.resetAt
-	}
-	
-	entry.count++
-	return true, r.config.Max - entry.count, entry.resetAt
+	return entry.take(limit, now)
+}
+
+// routeLimiter is the limiter of a RouteRateLimit
+type routeLimiter struct {
+	prefix  string
+	limiter *rateLimiter
 }
 
-// RateLimit returns a rate limiting middleware
+// RateLimit returns a rate limiting middleware. It panics on an unknown
+// algorithm; NewRateLimit returns the error instead.
 func RateLimit(config RateLimitConfig) fiber.Handler {
+	handler, err := NewRateLimit(config)
+	if err != nil {
+		panic(err)
+	}
+	return handler
+}
+
+// NewRateLimit returns a rate limiting middleware, or an error for an
+// unknown algorithm
+func NewRateLimit(config RateLimitConfig) (fiber.Handler, error) {
 	if config.Max == 0 {
 		config.Max = 100
 	}
@@ -112,11 +138,41 @@ func RateLimit(config RateLimitConfig) fiber.Handler {
 	}
 	if config.KeyGenerator == nil {
 		config.KeyGenerator = func(c *fiber.Ctx) string {
-			return c.IP()
+			return ClientIP(c)
 		}
 	}
 	
-	limiter := newRateLimiter(config)
+	limiter, err := newRateLimiter(config.Algorithm, config.Max, config.Window, config.Burst)
+	if err != nil {
+		return nil, err
+	}
+	
+	routes := make([]routeLimiter, 0, len(config.Routes))
+	for _, route := range config.Routes {
+		if route.Prefix == "" {
+			return nil, errors.New("rate limit route needs a prefix")
+		}
+		if route.Algorithm == "" {
+			route.Algorithm = config.Algorithm
+		}
+		if route.Max == 0 {
+			route.Max = config.Max
+		}
+		if route.Window == 0 {
+			route.Window = config.Window
+		}
+		if route.Burst == 0 {
+			route.Burst = config.Burst
+		}
+		rl, err := newRateLimiter(route.Algorithm, route.Max, route.Window, route.Burst)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit route %s: %w", route.Prefix, err)
+		}
+		// Fiber routes ignore case, so /API/V1/ORDERS must not escape the limit
+		routes = append(routes, routeLimiter{prefix: strings.ToLower(route.Prefix), limiter: rl})
+	}
+	// Longest prefix first, so the most specific route matches
+	sort.SliceStable(routes, func(i, j int) bool { return len(routes[i].prefix) > len(routes[j].prefix) })
 	
 	return func(c *fiber.Ctx) error {
 		// Check skip
@@ -125,20 +181,47 @@ func RateLimit(config RateLimitConfig) fiber.Handler {
 		}
 		
 		key := config.KeyGenerator(c)
-		allowed, remaining, resetAt := limiter.check(key)
+		rl, limit := limiter, config.Max
+		matched := false
+		path := strings.ToLower(c.Path())
+		for _, route := range routes {
+			if strings.HasPrefix(path, route.prefix) {
+				rl, limit, matched = route.limiter, route.limiter.max, true
+				break
+			}
+		}
+		if !matched && config.MaxFor != nil {
+			if n := config.MaxFor(c); n > 0 {
+				limit = n
+			}
+		}
+		now := time.Now()
+		d := rl.take(key, limit, now)
 		
-		// Set headers
-		c.Set("X-RateLimit-Limit", string(rune(config.Max)))
-		c.Set("X-RateLimit-Remaining", string(rune(remaining)))
-		c.Set("X-RateLimit-Reset", resetAt.Format(time.RFC3339))
+		// Set headers: the legacy X- names, and the IETF draft RateLimit
+		// fields whose reset is in seconds
+		c.Set("X-RateLimit-Limit", strconv.Itoa(limit))
+		c.Set("X-RateLimit-Remaining", strconv.Itoa(d.remaining))
+		c.Set("X-RateLimit-Reset", now.Add(d.reset).Format(time.RFC3339))
+		c.Set("RateLimit-Limit", strconv.Itoa(limit))
+		c.Set("RateLimit-Remaining", strconv.Itoa(d.remaining))
+		c.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(d.reset)))
+		c.Set("RateLimit-Policy", strconv.Itoa(limit)+rl.policy)
 		
-		if !allowed {
-			c.Set("Retry-After", resetAt.Sub(time.Now()).String())
-			return response.TooManyRequests(c)
+		if !d.allowed {
+			return response.TooManyRequests(c, d.retryAfter)
 		}
 		
 		return c.Next()
+	}, nil
+}
+
+// ceilSeconds rounds d up to whole seconds, never below zero
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
 	}
+	return int((d + time.Second - 1) / time.Second)
 }
 
 // DefaultRateLimit returns a rate limiter with default settings
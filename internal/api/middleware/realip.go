@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/config"
+)
+
+// Proxies resolves the client IP of requests relayed by trusted reverse
+// proxies, so rate limiting and logs see the client rather than the load
+// balancer. Headers from untrusted peers are ignored: anyone can send them.
+type Proxies struct {
+	nets   []*net.IPNet
+	unix   bool // connections over a unix socket are trusted
+	header string
+}
+
+// NewProxies parses cfg.TrustedProxies
+func NewProxies(cfg *config.ServerConfig) (*Proxies, error) {
+	p := &Proxies{header: cfg.ProxyHeader}
+	if p.header == "" {
+		p.header = fiber.HeaderXForwardedFor
+	}
+	if !strings.EqualFold(p.header, fiber.HeaderXForwardedFor) && !strings.EqualFold(p.header, "X-Real-IP") {
+		return nil, fmt.Errorf("proxy_header %q must be X-Forwarded-For or X-Real-IP", cfg.ProxyHeader)
+	}
+
+	for _, entry := range cfg.TrustedProxies {
+		entry = strings.TrimSpace(entry)
+		if entry == "unix" {
+			p.unix = true
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("trusted proxy %q is not an IP, a CIDR or unix", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			p.nets = append(p.nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q is not an IP, a CIDR or unix", entry)
+		}
+		p.nets = append(p.nets, n)
+	}
+	return p, nil
+}
+
+// RealIP returns a middleware that stores the client IP for ClientIP
+func (p *Proxies) RealIP() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if len(p.nets) == 0 && !p.unix {
+			return c.Next()
+		}
+
+		_, unix := c.Context().RemoteAddr().(*net.UnixAddr)
+		peer := c.Context().RemoteIP()
+		if !(unix && p.unix) && !p.trusted(peer) {
+			return c.Next()
+		}
+
+		// Header names are not normalized, so match them case-insensitively;
+		// X-Forwarded-For may be split over several lines
+		var values []string
+		c.Request().Header.VisitAll(func(key, value []byte) {
+			if strings.EqualFold(string(key), p.header) {
+				values = append(values, string(value))
+			}
+		})
+		if ip := p.clientIP(values); ip != nil {
+			c.Locals("client_ip", ip.String())
+		}
+		return c.Next()
+	}
+}
+
+// clientIP picks the client from the header values of a trusted peer.
+// X-Real-IP is taken as is. X-Forwarded-For is walked from the right, as
+// each proxy appends the address it received the request from: the first
+// untrusted address is the client, since anything left of it could have
+// been sent by the client itself.
+func (p *Proxies) clientIP(values []string) net.IP {
+	if !strings.EqualFold(p.header, fiber.HeaderXForwardedFor) {
+		if len(values) == 0 {
+			return nil
+		}
+		return parseHop(values[0])
+	}
+
+	var hops []string
+	for _, v := range values {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+	var client net.IP
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := parseHop(hops[i])
+		if ip == nil {
+			break
+		}
+		client = ip
+		if !p.trusted(ip) {
+			break
+		}
+	}
+	return client
+}
+
+func (p *Proxies) trusted(ip net.IP) bool {
+	for _, n := range p.nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHop parses one address of a forwarding header, which some proxies
+// send with a port
+func parseHop(s string) net.IP {
+	s = strings.TrimSpace(s)
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	return net.ParseIP(strings.Trim(s, "[]"))
+}
+
+// ClientIP returns the IP of the client, past any trusted proxies
+func ClientIP(c *fiber.Ctx) string {
+	if ip, ok := c.Locals("client_ip").(string); ok {
+		return ip
+	}
+	return c.IP()
+}
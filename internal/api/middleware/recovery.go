@@ -3,18 +3,32 @@ package middleware
 import (
 	"log"
 	"runtime/debug"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/crashreport"
 	"github.com/polygo/pkg/response"
 )
 
-// Recovery returns a middleware that recovers from panics
-func Recovery() fiber.Handler {
+// Recovery returns a middleware that recovers from panics. Panics are
+// logged with the request they happened in and, when reporter has an error
+// tracker configured, reported to it; a panic repeating within the dedup
+// window is logged on one line without its stack. reporter may be nil.
+func Recovery(reporter *crashreport.Reporter) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		defer func() {
 			if r := recover(); r != nil {
-				// Log the panic and stack trace
-				log.Printf("PANIC RECOVERED: %v\n%s", r, debug.Stack())
+				stack := debug.Stack()
+				req := panicRequest(c)
+				
+				if reporter == nil {
+					log.Printf("PANIC RECOVERED: %v request_id=%s %s %s\n%s", r, req.ID, req.Method, req.Route, stack)
+				} else if report := reporter.Capture(r, stack, req); report != nil {
+					log.Printf("PANIC RECOVERED: %v request_id=%s %s %s event_id=%s occurrences=%d\n%s",
+						r, req.ID, req.Method, req.Route, report.EventID, report.Occurrences, stack)
+				} else {
+					log.Printf("PANIC RECOVERED (repeated, stack omitted): %v request_id=%s %s %s", r, req.ID, req.Method, req.Route)
+				}
 				
 				// Return 500 error
 				response.Error(c, fiber.StatusInternalServerError, 
@@ -28,6 +42,28 @@ func Recovery() fiber.Handler {
 	}
 }
 
+// panicRequest describes the request a panic happened in, without the
+// headers that carry credentials. Fiber's strings point into buffers that
+// are reused once the request ends, so everything is copied for the report
+// sent later.
+func panicRequest(c *fiber.Ctx) *crashreport.Request {
+	req := &crashreport.Request{
+		ID:        strings.Clone(GetRequestID(c)),
+		Method:    strings.Clone(c.Method()),
+		URL:       c.BaseURL() + c.OriginalURL(),
+		Route:     strings.Clone(c.Route().Path),
+		RemoteIP:  strings.Clone(ClientIP(c)),
+		UserAgent: strings.Clone(c.Get(fiber.HeaderUserAgent)),
+		Headers:   make(map[string]string),
+	}
+	c.Request().Header.VisitAll(func(key, value []byte) {
+		if name := string(key); crashreport.SafeHeader(name) {
+			req.Headers[name] = string(value)
+		}
+	})
+	return req
+}
+
 // RecoveryWithConfig returns a recovery middleware with custom handler
 type RecoveryConfig struct {
 	// EnableStackTrace enables logging stack trace
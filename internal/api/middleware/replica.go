@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"crypto/subtle"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/pkg/response"
+)
+
+// ReplicaToken returns a middleware that only admits replicas presenting the
+// shared replication token. An empty token admits no one.
+func ReplicaToken(token string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if token == "" || subtle.ConstantTimeCompare([]byte(c.Get(config.ReplicaTokenHeader)), []byte(token)) != 1 {
+			return response.Unauthorized(c, "Invalid replication token")
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/idgen"
+)
+
+// RequestIDHeader is the header carrying the correlation ID
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID returns a middleware that assigns every request a correlation
+// ID, reusing the caller's X-Request-ID when present, and echoes it back
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Cloned: the header's bytes are reused after the request, while the
+		// ID lives on in webhook deliveries and events
+		id := strings.Clone(c.Get(RequestIDHeader))
+		if id == "" || len(id) > 128 {
+			id = idgen.New()
+		}
+
+		c.Locals("request_id", id)
+		c.Set(RequestIDHeader, id)
+
+		return c.Next()
+	}
+}
+
+// GetRequestID retrieves the correlation ID from context
+func GetRequestID(c *fiber.Ctx) string {
+	if id, ok := c.Locals("request_id").(string); ok {
+		return id
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/risk"
+	"github.com/polygo/pkg/response"
+)
+
+// PreTradeCheck returns a middleware that runs the caller's risk checks on
+// the order (or JSON array of orders, or pair of legs) in the body before
+// it reaches the handler. Bodies that do not parse are left for the handler to reject.
+// Must run after Auth.
+func PreTradeCheck(checker *risk.Checker, cfg *config.AuthConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !checker.Enabled() {
+			return c.Next()
+		}
+
+		orders, field, ok := parseOrders(c.Body())
+		if !ok {
+			return c.Next()
+		}
+
+		creds := GetAuthCredentials(c)
+		if creds == nil {
+			return response.Unauthorized(c, "Authentication required")
+		}
+
+		err := checker.Check(creds.APIKey, GetAuthHeaders(creds, cfg), orders)
+		var rejected *risk.Error
+		if !errors.As(err, &rejected) {
+			return c.Next()
+		}
+		return rejectRisk(c, rejected, field)
+	}
+}
+
+// rejectRisk responds to an order over the account's risk limits
+func rejectRisk(c *fiber.Ctx, rejected *risk.Error, field string) error {
+	details := ""
+	if rejected.Order != nil {
+		details = field + "[" + strconv.Itoa(*rejected.Order) + "]"
+	}
+	switch {
+	case rejected.Code == risk.CodeOrderRate && rejected.RetryAfter > 0:
+		return response.Retry(c, fiber.StatusTooManyRequests, rejected.Code, rejected.Message, details, rejected.RetryAfter)
+	case rejected.Code == risk.CodeOrderRate:
+		return response.Error(c, fiber.StatusTooManyRequests, rejected.Code, rejected.Message, details)
+	case rejected.Code == risk.CodeCheckUnavailable:
+		return response.Retry(c, fiber.StatusServiceUnavailable, rejected.Code, rejected.Message, details, 0)
+	}
+	return response.Error(c, fiber.StatusUnprocessableEntity, rejected.Code, rejected.Message, details)
+}
+
+// parseOrders reads the orders from a single order, an array of orders or
+// a pair's legs, with the field rejections should point at
+func parseOrders(body []byte) ([]models.CreateOrderRequest, string, bool) {
+	var orders []models.CreateOrderRequest
+	if err := sonic.Unmarshal(body, &orders); err == nil {
+		return orders, "orders", true
+	}
+
+	var pair struct {
+		Legs []models.CreateOrderRequest `json:"legs"`
+	}
+	if err := sonic.Unmarshal(body, &pair); err == nil && len(pair.Legs) > 0 {
+		return pair.Legs, "legs", true
+	}
+
+	var order models.CreateOrderRequest
+	if err := sonic.Unmarshal(body, &order); err != nil {
+		return nil, "", false
+	}
+	return []models.CreateOrderRequest{order}, "orders", true
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/pkg/response"
+)
+
+// Shedder bounds the requests served at once, overall and per route class,
+// and tells when the server is loaded enough that reads missing the cache
+// should be refused so cached reads keep being served.
+type Shedder struct {
+	max        int64
+	shedAt     int64 // in-flight requests from which reads missing the cache are shed (0 = never)
+	classes    map[string]*routeClass
+	classFor   func(c *fiber.Ctx) string
+	retryAfter time.Duration
+
+	inflight atomic.Int64
+	rejected atomic.Int64
+	shed     atomic.Int64
+}
+
+// routeClass is the in-flight limit of one route class
+type routeClass struct {
+	max      int64
+	inflight atomic.Int64
+	rejected atomic.Int64
+}
+
+// ShedStats reports the shedder's state for /stats
+type ShedStats struct {
+	InFlight int64                     `json:"in_flight"`
+	Rejected int64                     `json:"rejected"` // requests refused at a limit
+	Shed     int64                     `json:"shed"`     // reads refused under load because they missed the cache
+	Classes  map[string]ClassShedStats `json:"classes,omitempty"`
+}
+
+// ClassShedStats reports one route class
+type ClassShedStats struct {
+	InFlight int64 `json:"in_flight"`
+	Max      int64 `json:"max"`
+	Rejected int64 `json:"rejected"`
+}
+
+// NewShedder creates a shedder; classFor names the class of a request,
+// which is limited when cfg.Classes has it
+func NewShedder(cfg *config.ConcurrencyConfig, classFor func(c *fiber.Ctx) string) *Shedder {
+	s := &Shedder{
+		max:        int64(cfg.MaxInFlight),
+		classes:    make(map[string]*routeClass),
+		classFor:   classFor,
+		retryAfter: cfg.RetryAfter,
+	}
+	if cfg.MaxInFlight > 0 && cfg.ShedAt > 0 {
+		s.shedAt = max(int64(float64(cfg.MaxInFlight)*cfg.ShedAt), 1)
+	}
+	for name, limit := range cfg.Classes {
+		if limit > 0 {
+			s.classes[name] = &routeClass{max: int64(limit)}
+		}
+	}
+	return s
+}
+
+// Limit returns a middleware that refuses requests beyond the in-flight
+// limits with 503 and Retry-After
+func (s *Shedder) Limit(skip func(c *fiber.Ctx) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if skip != nil && skip(c) {
+			return c.Next()
+		}
+
+		n := s.inflight.Add(1)
+		defer s.inflight.Add(-1)
+		if s.max > 0 && n > s.max {
+			return s.reject(c, "Too many requests in flight")
+		}
+
+		if class, ok := s.classes[s.classFor(c)]; ok {
+			n := class.inflight.Add(1)
+			defer class.inflight.Add(-1)
+			if n > class.max {
+				class.rejected.Add(1)
+				return s.reject(c, "Too many requests of this kind in flight")
+			}
+		}
+
+		return c.Next()
+	}
+}
+
+func (s *Shedder) reject(c *fiber.Ctx, details string) error {
+	s.rejected.Add(1)
+	return response.Retry(c, fiber.StatusServiceUnavailable, "OVERLOADED", "Server is overloaded", details, s.retryAfter)
+}
+
+// Shed reports whether a read that missed the cache should be refused
+// rather than sent upstream, counting it when so
+func (s *Shedder) Shed() bool {
+	if s.shedAt == 0 || s.inflight.Load() < s.shedAt {
+		return false
+	}
+	s.shed.Add(1)
+	return true
+}
+
+// Stats returns in-flight requests and what was refused
+func (s *Shedder) Stats() ShedStats {
+	stats := ShedStats{
+		InFlight: s.inflight.Load(),
+		Rejected: s.rejected.Load(),
+		Shed:     s.shed.Load(),
+	}
+	if len(s.classes) > 0 {
+		stats.Classes = make(map[string]ClassShedStats, len(s.classes))
+		for name, class := range s.classes {
+			stats.Classes[name] = ClassShedStats{InFlight: class.inflight.Load(), Max: class.max, Rejected: class.rejected.Load()}
+		}
+	}
+	return stats
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/tenant"
+	"github.com/polygo/pkg/response"
+)
+
+// Tenant returns a middleware that identifies the caller's tenant by API
+// key and records the request in the tenant's usage. Callers whose key
+// belongs to no tenant pass through untouched.
+func Tenant(registry *tenant.Registry, cfg *config.AuthConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		t, ok := registry.ForKey(c.Get(cfg.APIKeyHeader))
+		if !ok {
+			return c.Next()
+		}
+		c.Locals("tenant", t)
+
+		err := c.Next()
+
+		// The error handler has not written the status yet
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			if e, ok := err.(*fiber.Error); ok {
+				status = e.Code
+			}
+		}
+		// Reading a streamed body would consume it
+		var bytesOut int64
+		if c.Response().IsBodyStream() {
+			bytesOut = int64(max(c.Response().Header.ContentLength(), 0))
+		} else {
+			bytesOut = int64(len(c.Response().Body()))
+		}
+		bytesIn := int64(max(c.Request().Header.ContentLength(), 0))
+
+		registry.Record(t, c.Method(), c.Route().Path, status, bytesIn, bytesOut, time.Now())
+		return err
+	}
+}
+
+// RequireTenant rejects callers whose API key belongs to no tenant when
+// tenants are enabled, for routes whose data is kept per tenant
+func RequireTenant(registry *tenant.Registry) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if registry.Enabled() && GetTenant(c) == nil {
+			return response.Unauthorized(c, "An API key that belongs to a tenant is required")
+		}
+		return c.Next()
+	}
+}
+
+// GetTenant retrieves the caller's tenant from context, if any
+func GetTenant(c *fiber.Ctx) *tenant.Tenant {
+	if t, ok := c.Locals("tenant").(*tenant.Tenant); ok {
+		return t
+	}
+	return nil
+}
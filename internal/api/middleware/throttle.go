@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/internal/throttle"
+	"github.com/polygo/pkg/response"
+)
+
+// OrderThrottle returns a middleware that rejects orders repeating one the
+// caller just sent with 409, and orders over the caller's per-market rate
+// with 429, naming the offending order. Requests that fail further on are
+// not counted. Must run after Auth and ValidateBody.
+func OrderThrottle(guard *throttle.Guard) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !guard.Enabled() {
+			return c.Next()
+		}
+
+		orders, field, ok := parseOrders(c.Body())
+		if !ok {
+			return c.Next()
+		}
+
+		creds := GetAuthCredentials(c)
+		if creds == nil {
+			return response.Unauthorized(c, "Authentication required")
+		}
+
+		reservation, err := guard.Reserve(creds.APIKey, orders)
+		var rejected *throttle.Error
+		if !errors.As(err, &rejected) {
+			err := c.Next()
+			if err != nil || c.Response().StatusCode() >= fiber.StatusBadRequest {
+				guard.Release(reservation)
+			}
+			return err
+		}
+		return rejectThrottled(c, rejected, field)
+	}
+}
+
+// rejectThrottled responds to a duplicate order, or one over its market's rate
+func rejectThrottled(c *fiber.Ctx, rejected *throttle.Error, field string) error {
+	details := field + "[" + strconv.Itoa(rejected.Order) + "]"
+	status := fiber.StatusTooManyRequests
+	if rejected.Code == throttle.CodeDuplicate {
+		status = fiber.StatusConflict
+	}
+	if rejected.RetryAfter > 0 {
+		return response.Retry(c, status, rejected.Code, rejected.Message, details, rejected.RetryAfter)
+	}
+	return response.Error(c, status, rejected.Code, rejected.Message, details)
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/pkg/response"
+	"github.com/polygo/pkg/validate"
+)
+
+// BodyLimit returns a middleware that rejects request bodies larger than
+// limit bytes with 413, before they are parsed. The declared length is
+// checked first; chunked bodies are measured once read. A limit of 0 or
+// less disables the check.
+func BodyLimit(limit int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if limit <= 0 {
+			return c.Next()
+		}
+		if c.Request().Header.ContentLength() > limit || len(c.Body()) > limit {
+			return response.PayloadTooLarge(c, limit)
+		}
+		return c.Next()
+	}
+}
+
+// ValidateBody returns a middleware that decodes the JSON body into a T
+// and checks it against its validate tags, answering 400 with every
+// invalid field before the handler runs. When T is a slice its elements
+// are reported as field[i]; otherwise field is ignored.
+func ValidateBody[T any](field string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var body T
+		if err := sonic.Unmarshal(c.Body(), &body); err != nil {
+			return response.BadRequest(c, "Invalid request body")
+		}
+		if errs := validate.Var(&body, field); len(errs) > 0 {
+			return response.ValidationFailed(c, errs)
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import "github.com/gofiber/fiber/v2"
+
+// APIVersion returns a middleware that marks requests with the API version
+// their route group serves, so shared handlers can answer in its format
+func APIVersion(version int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("api_version", version)
+		return c.Next()
+	}
+}
+
+// GetAPIVersion retrieves the API version from context, 1 when unmarked
+func GetAPIVersion(c *fiber.Ctx) int {
+	if v, ok := c.Locals("api_version").(int); ok {
+		return v
+	}
+	return 1
+}
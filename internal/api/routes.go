@@ -1,16 +1,59 @@
 package api
 
 import (
+	"errors"
+	"log"
+	"strings"
+	
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/swagger"
 	"github.com/gofiber/websocket/v2"
 	
+	"github.com/polygo/internal/access"
+	"github.com/polygo/internal/analytics"
 	"github.com/polygo/internal/api/handlers"
 	"github.com/polygo/internal/api/middleware"
+	"github.com/polygo/internal/audit"
 	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/chain"
 	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/expiry"
+	"github.com/polygo/internal/fillnotify"
+	"github.com/polygo/internal/export"
+	"github.com/polygo/internal/latency"
+	"github.com/polygo/internal/listener"
+	"github.com/polygo/internal/listings"
+	"github.com/polygo/internal/copytrade"
+	"github.com/polygo/internal/crashreport"
+	"github.com/polygo/internal/deadman"
+	"github.com/polygo/internal/equity"
+	"github.com/polygo/internal/digest"
+	"github.com/polygo/internal/eventbus"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/orderrules"
+	"github.com/polygo/internal/pairs"
 	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/pretrade"
+	"github.com/polygo/internal/leaderboard"
+	"github.com/polygo/internal/recorder"
+	"github.com/polygo/internal/rewards"
+	"github.com/polygo/internal/risk"
+	"github.com/polygo/internal/routing"
+	"github.com/polygo/internal/rules"
+	"github.com/polygo/internal/storage"
+	"github.com/polygo/internal/strategies"
+	"github.com/polygo/internal/tape"
+	"github.com/polygo/internal/taxreport"
+	"github.com/polygo/internal/tenant"
+	"github.com/polygo/internal/throttle"
+	"github.com/polygo/internal/ticker"
+	"github.com/polygo/internal/posalert"
+	"github.com/polygo/internal/watchlist"
+	"github.com/polygo/internal/webhooks"
+	"github.com/polygo/internal/wsbuffer"
+	"github.com/polygo/internal/wsreplay"
 )
 
 // Server holds all dependencies for the API server
@@ -19,14 +62,69 @@ type Server struct {
 	config    *config.Config
 	cache     *cache.Cache
 	client    *polymarket.Client
+	failover  *polymarket.Failover
 	gamma     *polymarket.GammaClient
 	clob      *polymarket.ClobClient
 	data      *polymarket.DataClient
 	wsManager *polymarket.WSManager
+	tape      *tape.Tape
+	catalog   *catalog.Catalog
+	resolver  *catalog.Resolver
+	listings  *listings.Feed
+	recorder  *recorder.Recorder
+	leaderboard *leaderboard.Tracker
+	trades    *analytics.TradeCounter
+	ticker    *ticker.Ticker
+	webhooks  *webhooks.Dispatcher
+	tenants   *tenant.Registry
+	eventBus  *eventbus.Publisher
+	watchlist *watchlist.Watchlist
+	posAlerts *posalert.Alerts
+	equity    *equity.Tracker
+	taxReports *taxreport.Reporter
+	fills     *polymarket.FillTracker
+	copytrade *copytrade.Engine
+	risk      *risk.Checker
+	rules     *orderrules.Checker
+	throttle  *throttle.Guard
+	router    *routing.Router
+	ruleEngine *rules.Engine
+	expiry    *expiry.Tracker
+	deadMan   *deadman.Switches
+	notifier  *fillnotify.Notifier
+	strategies *strategies.Tracker
+	exports   *export.Scheduler
+	pairs     *pairs.Manager
+	verifier  *chain.Verifier
+	wsHandler *handlers.WebSocketHandler
+	drainer   *middleware.Drainer
+	proxies   *middleware.Proxies
+	rateLimit fiber.Handler
+	shedder   *middleware.Shedder
+	reporter  *crashreport.Reporter
+	latency   *latency.Recorder
+	wsBuffers *wsbuffer.Pool
+	audit     *audit.Log
+	access    *access.Control
 }
 
 // NewServer creates a new API server
 func NewServer(cfg *config.Config, c *cache.Cache) (*Server, error) {
+	// Refuse proxy settings that would otherwise fail every upstream call
+	if _, err := polymarket.NewProxyDialer(&cfg.Polymarket); err != nil {
+		return nil, err
+	}
+	
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	
+	// /replica is exempt from rate limits and access control; never open it
+	// to anyone
+	if cfg.Replication.ServeReplicas && cfg.Replication.Token == "" {
+		return nil, errors.New("serve_replicas requires a replication token")
+	}
+	
 	// Create Polymarket client
 	client := polymarket.NewClient(&cfg.Polymarket, c)
 	
@@ -38,6 +136,70 @@ func NewServer(cfg *config.Config, c *cache.Cache) (*Server, error) {
 	// Create WebSocket manager
 	wsManager := polymarket.NewWSManager(&cfg.Polymarket)
 	
+	// Keep recordings, exports and recorder snapshots on disk or in a bucket
+	store, err := storage.New(&cfg.Storage)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Record or replay upstream traffic
+	tp, err := tape.New(&cfg.Tape, store)
+	if err != nil {
+		return nil, err
+	}
+	client.SetTape(tp)
+	wsManager.SetTape(tp)
+	
+	// Move upstream APIs to their mirrors while their base URL is down
+	failover := polymarket.NewFailover(client, &cfg.Polymarket, &cfg.Health)
+	client.SetFailover(failover)
+	
+	// Publish market events to Kafka or NATS when enabled
+	bus, err := eventbus.New(wsManager, &cfg.EventBus, cfg.Server.BookSnapshotEvery)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Group API keys into tenants when PolyGo is shared
+	tenants, err := tenant.New(&cfg.Tenants)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Client IPs reported by trusted reverse proxies
+	proxies, err := middleware.NewProxies(&cfg.Server)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Rate limit with the configured algorithm and per-route limits
+	rateLimit, err := middleware.NewRateLimit(rateLimitConfig(cfg))
+	if err != nil {
+		return nil, err
+	}
+	
+	// Bound requests in flight, and refuse reads missing the cache under load
+	shedder := middleware.NewShedder(&cfg.Concurrency, routeClass)
+	client.SetShedder(shedder.Shed)
+	
+	// Report recovered panics to Sentry/Bugsnag when configured
+	reporter, err := crashreport.New(&cfg.ErrorReporting)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Roles of PolyGo access keys and JWTs
+	acl, err := access.New(&cfg.Access, cfg.Admin.Token)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Append-only record of the changes callers make
+	auditLog, err := audit.New(&cfg.Audit)
+	if err != nil {
+		return nil, err
+	}
+	
 	// Create Fiber app with optimized settings
 	app := fiber.New(fiber.Config{
 		Prefork:               cfg.Server.Prefork,
@@ -48,20 +210,84 @@ func NewServer(cfg *config.Config, c *cache.Cache) (*Server, error) {
 		IdleTimeout:           cfg.Server.IdleTimeout,
 		// Performance optimizations
 		DisableDefaultDate:         true,
-		DisableHeaderNormalizing:   true,
 		DisablePreParseMultipartForm: true,
 		StreamRequestBody:          true,
+		BodyLimit:                  cfg.Server.BodyLimit,
 	})
 	
+	cat := catalog.New(gamma, &cfg.Catalog)
+	rec := recorder.New(gamma, store, &cfg.Recorder)
+	dispatcher, err := webhooks.NewDispatcher(&cfg.Webhooks)
+	if err != nil {
+		return nil, err
+	}
+	wl := watchlist.New(data, gamma, clob, dispatcher, &cfg.Watchlist)
+	posAlerts := posalert.New(data, dispatcher, &cfg.PositionAlerts)
+	// Equity curves follow every wallet watched either way
+	watched := func() []string { return append(wl.Addresses(), posAlerts.Addresses()...) }
+	fills := polymarket.NewFillTracker()
+	notifier := fillnotify.New(&cfg.Polymarket, data, dispatcher, fills, &cfg.FillNotify)
+	tracker := strategies.New(clob, &cfg.Strategies)
+	notifier.OnFill(tracker.ObserveFill)
+	
+	resolver := catalog.NewResolver(cat, gamma)
+	feed := listings.New(dispatcher)
+	cat.OnListed(feed.Publish)
+	
+	// Pre-trade checks, for orders placed through /orders and server-side alike
+	riskChecker := risk.New(clob, resolver, &cfg.Risk)
+	orderRules := orderrules.New(clob, resolver, &cfg.OrderRules)
+	guard := throttle.New(resolver, &cfg.Throttle)
+	checks := pretrade.New(orderRules, guard, riskChecker, &cfg.Auth)
+	
 	server := &Server{
 		app:       app,
 		config:    cfg,
 		cache:     c,
 		client:    client,
+		failover:  failover,
 		gamma:     gamma,
 		clob:      clob,
 		data:      data,
 		wsManager: wsManager,
+		tape:      tp,
+		catalog:   cat,
+		resolver:  resolver,
+		listings:  feed,
+		recorder:  rec,
+		leaderboard: leaderboard.New(data, &cfg.Leaderboard),
+		trades:    analytics.NewTradeCounter(data, &cfg.Analytics),
+		ticker:    ticker.New(clob, cat, &cfg.Ticker),
+		webhooks:  dispatcher,
+		tenants:   tenants,
+		eventBus:  bus,
+		watchlist: wl,
+		posAlerts: posAlerts,
+		equity:    equity.New(data, watched, &cfg.Equity),
+		taxReports: taxreport.New(data, &cfg.TaxReport),
+		fills:     fills,
+		copytrade: copytrade.New(data, clob, fills, checks, &cfg.Auth, &cfg.CopyTrade),
+		risk:      riskChecker,
+		rules:     orderRules,
+		throttle:  guard,
+		router:    routing.New(clob, data, fills, checks, resolver, &cfg.Auth, &cfg.Routing),
+		ruleEngine: rules.New(wsManager, cat, clob, data, fills, checks, dispatcher, bus, &cfg.Auth, &cfg.Rules),
+		expiry:    expiry.New(clob, dispatcher, &cfg.Auth, &cfg.OrderExpiry),
+		deadMan:   deadman.New(clob, dispatcher, &cfg.Auth, &cfg.DeadMan),
+		notifier:  notifier,
+		strategies: tracker,
+		exports:   export.New(data, cat, rec, store, &cfg.Export),
+		pairs:     pairs.New(clob),
+		verifier:  chain.NewVerifier(chain.NewClient(&cfg.Chain), c, &cfg.Chain),
+		drainer:   middleware.NewDrainer(cfg.Server.ReconnectHint),
+		proxies:   proxies,
+		rateLimit: rateLimit,
+		shedder:   shedder,
+		reporter:  reporter,
+		latency:   latency.NewRecorder(),
+		wsBuffers: wsbuffer.New(cfg.Server.WSBufferLimit, cfg.Server.WSClientBufferLimit),
+		audit:     auditLog,
+		access:    acl,
 	}
 	
 	// Setup routes
@@ -75,13 +301,35 @@ func NewServer(cfg *config.Config, c *cache.Cache) (*Server, error) {
 func (s *Server) setupMiddleware() {
 	// CORS
 	s.app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders: "Origin,Content-Type,Accept,Authorization,POLY-API-KEY,POLY-API-SECRET,POLY-PASSPHRASE,POLY-SIGNATURE,POLY-TIMESTAMP",
+		AllowOrigins:     s.config.Server.CORSOrigins,
+		AllowCredentials: s.config.Server.CORSAllowCredentials,
+		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS",
+		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,POLY-API-KEY,POLY-API-SECRET,POLY-PASSPHRASE,POLY-SIGNATURE,POLY-TIMESTAMP",
 	}))
 	
 	// Recovery
-	s.app.Use(middleware.Recovery())
+	s.app.Use(middleware.Recovery(s.reporter))
+	
+	// Client IP behind trusted reverse proxies, for rate limiting and logs
+	s.app.Use(s.proxies.RealIP())
+	
+	// Correlation ID for logs, webhooks and upstream tracing
+	s.app.Use(middleware.RequestID())
+	
+	// Audit log of orders, dead-man's switches, webhooks and admin changes
+	s.app.Use(middleware.Audit(s.audit, auditAction))
+	
+	// Reject new requests while draining (health checks still answer)
+	s.app.Use(s.drainer.Reject(func(c *fiber.Ctx) bool {
+		path := c.Path()
+		return path == "/health" || path == "/ready"
+	}))
+	
+	// Per-route latency histograms and the slow-request log
+	s.app.Use(middleware.Latency(s.latency, s.config.Server.SlowRequestThreshold, func(c *fiber.Ctx) bool {
+		path := c.Path()
+		return path == "/health" || path == "/ready" || path == "/metrics" || websocket.IsWebSocketUpgrade(c)
+	}))
 	
 	// Logger (skip health checks)
 	s.app.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{
@@ -91,99 +339,429 @@ func (s *Server) setupMiddleware() {
 		},
 	}))
 	
-	// Rate limiting
-	s.app.Use(middleware.RateLimit(middleware.RateLimitConfig{
-		Max:    1000,
-		Window: 10 * 1000 * 1000 * 1000, // 10 seconds in nanoseconds
+	// Roles: viewers read, traders also trade, admins also administer
+	s.app.Use(middleware.Access(s.access, routeRole))
+	
+	// Tenant of the caller's API key, and its usage accounting
+	if s.tenants.Enabled() {
+		s.app.Use(middleware.Tenant(s.tenants, &s.config.Auth))
+	}
+	
+	// Rate limiting, per tenant for tenants' API keys and per IP otherwise
+	s.app.Use(s.rateLimit)
+	
+	// Concurrency limits and load shedding (streams are long-lived and not counted)
+	s.app.Use(s.shedder.Limit(func(c *fiber.Ctx) bool {
+		path := c.Path()
+		return path == "/health" || path == "/ready" || websocket.IsWebSocketUpgrade(c)
+	}))
+	
+	// Streamed bodies bypass fiber's own limit, so enforce it here too
+	s.app.Use(middleware.BodyLimit(s.config.Server.BodyLimit))
+}
+
+// rateLimitConfig limits each tenant to its own rate and other callers
+// per IP, with the configured algorithm and per-route limits
+func rateLimitConfig(cfg *config.Config) middleware.RateLimitConfig {
+	routes := make([]middleware.RouteRateLimit, 0, len(cfg.RateLimit.Routes))
+	for _, r := range cfg.RateLimit.Routes {
+		routes = append(routes, middleware.RouteRateLimit{
+			Prefix:    r.Prefix,
+			Algorithm: r.Algorithm,
+			Max:       r.Limit,
+			Window:    r.Window,
+			Burst:     r.Burst,
+		})
+	}
+	
+	return middleware.RateLimitConfig{
+		Max:       1000,
+		Window:    10 * 1000 * 1000 * 1000, // 10 seconds in nanoseconds
+		Algorithm: cfg.RateLimit.Algorithm,
+		Burst:     cfg.RateLimit.Burst,
+		Routes:    routes,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			if t := middleware.GetTenant(c); t != nil {
+				return t.Owner()
+			}
+			return middleware.ClientIP(c)
+		},
+		MaxFor: func(c *fiber.Ctx) int {
+			if t := middleware.GetTenant(c); t != nil {
+				return t.RateLimit
+			}
+			return 0
+		},
 		Skip: func(c *fiber.Ctx) bool {
-			return c.Path() == "/health" || c.Path() == "/ready"
+			// Replicas fan in traffic from many users; they are gated by token instead
+			return c.Path() == "/health" || c.Path() == "/ready" ||
+				(cfg.Replication.ServeReplicas && strings.HasPrefix(c.Path(), "/replica/"))
 		},
-	}))
+	}
+}
+
+// routeClass names the class of a request for the concurrency limits. It
+// runs before routing, so it matches the path the way Fiber routes it:
+// regardless of case.
+func routeClass(c *fiber.Ctx) string {
+	path := strings.ToLower(c.Path())
+	switch {
+	case path == "/admin/orders":
+		return "trading"
+	case strings.HasPrefix(path, "/admin"), strings.HasPrefix(path, "/api/v1/copytrade"), strings.HasPrefix(path, "/api/v2/copytrade"):
+		return "admin"
+	case c.Method() == fiber.MethodGet && strings.HasPrefix(path, "/api/"):
+		return "reads"
+	case strings.Contains(path, "/orders") || strings.Contains(path, "/raw/"):
+		return "trading"
+	}
+	return "other"
+}
+
+// routeRole names the least role a request needs when access control is
+// enabled ("" for health checks, docs and replicas, which need none);
+// operator groups require the admin role on top, see RequireRole
+func routeRole(c *fiber.Ctx) access.Role {
+	path := c.Path()
+	switch {
+	case path == "/health" || path == "/ready" || path == "/openapi.json" || strings.HasPrefix(path, "/swagger/"),
+		strings.HasPrefix(path, "/replica/"):
+		return ""
+	case c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead || c.Method() == fiber.MethodOptions,
+		strings.HasSuffix(path, "/orders/preview"):
+		return access.RoleViewer
+	}
+	return access.RoleTrader
+}
+
+// auditActions names the requests recorded in the audit log, by method and
+// route (under /api/v1 and /api/v2 alike)
+var auditActions = map[string]string{
+	"POST /orders":                       "order.create",
+	"POST /orders/batch":                 "order.create_batch",
+	"POST /orders/pair":                  "order.create_pair",
+	"DELETE /orders/pair/:id":            "order.cancel_pair",
+	"DELETE /orders/:id":                 "order.cancel",
+	"DELETE /orders/cancel-all":          "order.cancel_all",
+	"POST /orders/batch-cancel":          "order.cancel_batch",
+	"POST /raw/:upstream/*":              "raw.post",
+	"PUT /raw/:upstream/*":               "raw.put",
+	"DELETE /raw/:upstream/*":            "raw.delete",
+	"POST /webhooks":                     "webhook.create",
+	"DELETE /webhooks/:id":               "webhook.delete",
+	"POST /webhooks/:id/secret":          "webhook.rotate_secret",
+	"POST /watchlist/wallets":            "watchlist.add_wallet",
+	"DELETE /watchlist/wallets/:address": "watchlist.remove_wallet",
+	"POST /watchlist/markets":            "watchlist.add_market",
+	"DELETE /watchlist/markets/:id":      "watchlist.remove_market",
+	"POST /alerts/positions":             "position_alert.create",
+	"PUT /alerts/positions/:id":          "position_alert.update",
+	"DELETE /alerts/positions/:id":       "position_alert.delete",
+	"POST /copytrade/start":              "copytrade.start",
+	"POST /copytrade/stop":               "copytrade.stop",
+	"DELETE /admin/cache":                "cache.purge",
+	"PUT /admin/upstream/pool":           "upstream_pool.tune",
+	"PUT /admin/risk/limits/default":     "risk_limits.set_default",
+	"PUT /admin/risk/limits/:account":    "risk_limits.set",
+	"DELETE /admin/risk/limits/:account": "risk_limits.delete",
+	"POST /admin/exports":                "export.create",
+	"PUT /admin/exports/:id":             "export.update",
+	"DELETE /admin/exports/:id":          "export.delete",
+	"POST /admin/exports/:id/run":        "export.run",
+	"POST /admin/rules":                  "rule.create",
+	"PUT /admin/rules/:name":             "rule.update",
+	"DELETE /admin/rules/:name":          "rule.delete",
+	"POST /admin/orders":                 "order.route",
+}
+
+// auditAction names the action of an answered request for the audit log
+func auditAction(c *fiber.Ctx) string {
+	route := c.Route().Path
+	for _, prefix := range []string{"/api/v1", "/api/v2"} {
+		route = strings.TrimPrefix(route, prefix)
+	}
+	if len(route) > 1 {
+		route = strings.TrimSuffix(route, "/")
+	}
+	return auditActions[c.Method()+" "+route]
 }
 
 // setupRoutes configures all API routes
 func (s *Server) setupRoutes() {
 	// Create handlers
-	healthHandler := handlers.NewHealthHandler(s.cache, s.wsManager)
-	marketsHandler := handlers.NewMarketsHandler(s.gamma)
+	// A replay never contacts Polymarket, so there is nothing to probe
+	var prober *polymarket.HealthProber
+	if !s.tape.Replaying() {
+		prober = polymarket.NewHealthProber(s.client, &s.config.Health)
+	}
+	healthHandler := handlers.NewHealthHandler(s.cache, s.wsManager, s.client, s.drainer, s.shedder, prober, s.latency, s.eventBus, s.wsBuffers, s.config.Server.GoroutineBudget)
+	snapshots := polymarket.NewSnapshotService(s.clob, s.data, s.config.Snapshot.Concurrency)
+	marketsHandler := handlers.NewMarketsHandler(s.gamma, polymarket.NewMarketDetailService(s.gamma, s.data, snapshots), s.catalog)
 	eventsHandler := handlers.NewEventsHandler(s.gamma)
-	pricesHandler := handlers.NewPricesHandler(s.clob)
-	ordersHandler := handlers.NewOrdersHandler(s.clob, &s.config.Auth)
-	dataHandler := handlers.NewDataHandler(s.data)
-	wsHandler := handlers.NewWebSocketHandler(s.wsManager)
+	pricesHandler := handlers.NewPricesHandler(s.clob, &s.config.Prices)
+	snapshotHandler := handlers.NewSnapshotHandler(snapshots, &s.config.Snapshot)
+	chainHandler := handlers.NewChainHandler(s.verifier)
+	balanceHandler := handlers.NewBalanceHandler(s.clob, &s.config.Auth)
+	rewardsHandler := handlers.NewRewardsHandler(rewards.New(s.clob, s.gamma, s.catalog), &s.config.Auth)
+	ordersHandler := handlers.NewOrdersHandler(s.clob, s.data, &s.config.Auth, s.webhooks, s.fills, s.expiry, s.notifier, s.strategies, s.pairs)
+	dataHandler := handlers.NewDataHandler(s.data, s.recorder, s.verifier)
+	leaderboardHandler := handlers.NewLeaderboardHandler(s.leaderboard)
+	catalogHandler := handlers.NewCatalogHandler(s.catalog, s.resolver, s.gamma)
+	analyticsHandler := handlers.NewAnalyticsHandler(s.catalog, s.trades, s.recorder)
+	digestHandler := handlers.NewDigestHandler(digest.NewBuilder(s.catalog, s.recorder, s.cache, &s.config.Digest), s.catalog)
+	webhooksHandler := handlers.NewWebhooksHandler(s.webhooks, s.deadMan, s.audit, s.wsBuffers)
+	wsHandler := handlers.NewWebSocketHandler(s.wsManager, s.resolver, s.config.Server.BookSnapshotEvery, s.wsBuffers, wsreplay.New(s.config.Server.WSReplayWindow))
+	tickerHandler := handlers.NewTickerHandler(s.ticker, s.wsBuffers)
+	watchlistHandler := handlers.NewWatchlistHandler(s.watchlist, s.wsBuffers)
+	posAlertsHandler := handlers.NewPositionAlertsHandler(s.posAlerts)
+	equityHandler := handlers.NewEquityHandler(s.equity)
+	taxReportHandler := handlers.NewTaxReportHandler(s.taxReports)
+	strategiesHandler := handlers.NewStrategiesHandler(s.strategies)
+	listingsHandler := handlers.NewListingsHandler(s.listings, s.wsBuffers)
+	copyTradeHandler := handlers.NewCopyTradeHandler(s.copytrade)
+	adminHandler := handlers.NewAdminHandler(s.config, s.cache, s.client)
+	riskHandler := handlers.NewRiskHandler(s.risk)
+	exportsHandler := handlers.NewExportsHandler(s.exports)
+	rulesHandler := handlers.NewRulesHandler(s.ruleEngine)
+	routingHandler := handlers.NewRoutingHandler(s.router, s.expiry)
+	auditHandler := handlers.NewAuditHandler(s.audit)
+	tenantsHandler := handlers.NewTenantsHandler(s.tenants)
+	docsHandler := handlers.NewDocsHandler(&s.config.Docs)
+	s.wsHandler = wsHandler
+	jsonLimit := middleware.BodyLimit(s.config.Server.JSONBodyLimit)
 	
 	// Health endpoints
 	s.app.Get("/health", healthHandler.Health)
 	s.app.Get("/ready", healthHandler.Ready)
 	s.app.Get("/stats", healthHandler.Stats)
+	s.app.Get("/metrics", healthHandler.Metrics)
 	
-	// Swagger
+	// API docs
 	s.app.Get("/swagger/*", swagger.HandlerDefault)
+	s.app.Get("/openapi.json", docsHandler.OpenAPI)
+	
+	// Admin (operator-only)
+	admin := s.app.Group("/admin", middleware.RequireRole(s.access, access.RoleAdmin), middleware.AdminAuth(s.config.Admin.Token))
+	admin.Get("/config/effective", adminHandler.GetEffectiveConfig)
+	admin.Delete("/cache", adminHandler.PurgeCache)
+	admin.Get("/drift", adminHandler.GetSchemaDrift)
+	admin.Get("/ws/stats", wsHandler.GetStats)
+	admin.Put("/upstream/pool", jsonLimit, adminHandler.SetUpstreamPool)
+	admin.Get("/risk/limits", riskHandler.GetLimits)
+	admin.Put("/risk/limits/default", jsonLimit, riskHandler.SetDefaultLimits)
+	admin.Put("/risk/limits/:account", jsonLimit, riskHandler.SetAccountLimits)
+	admin.Delete("/risk/limits/:account", riskHandler.DeleteAccountLimits)
+	if s.config.Export.Enabled {
+		admin.Get("/exports", exportsHandler.ListJobs)
+		admin.Post("/exports", jsonLimit, exportsHandler.CreateJob)
+		admin.Get("/exports/:id", exportsHandler.GetJob)
+		admin.Put("/exports/:id", jsonLimit, exportsHandler.UpdateJob)
+		admin.Delete("/exports/:id", exportsHandler.DeleteJob)
+		admin.Get("/exports/:id/runs", exportsHandler.ListRuns)
+		admin.Post("/exports/:id/run", exportsHandler.RunJob)
+	}
+	if s.config.Tenants.Enabled {
+		admin.Get("/tenants", tenantsHandler.ListTenants)
+		admin.Get("/tenants/:id/usage", tenantsHandler.GetUsage)
+	}
+	if s.config.Rules.Enabled {
+		admin.Get("/rules", rulesHandler.ListRules)
+		admin.Post("/rules", jsonLimit, rulesHandler.CreateRule)
+		admin.Get("/rules/:name", rulesHandler.GetRule)
+		admin.Put("/rules/:name", jsonLimit, rulesHandler.UpdateRule)
+		admin.Delete("/rules/:name", rulesHandler.DeleteRule)
+		admin.Get("/rules/:name/triggers", rulesHandler.ListTriggers)
+	}
+	if s.config.Audit.Enabled {
+		admin.Get("/audit", auditHandler.GetAudit)
+		admin.Get("/audit/export", auditHandler.ExportAudit)
+	}
+	if s.config.Routing.Enabled {
+		admin.Get("/routing", routingHandler.GetRouting)
+		admin.Post("/orders", middleware.BodyLimit(s.config.Server.OrderBodyLimit), s.drainer.Track(), middleware.ResolvePriceModes(polymarket.NewPriceResolver(s.clob)), middleware.ValidateBody[models.CreateOrderRequest](""), routingHandler.RouteOrder)
+	}
 	
-	// API v1 routes
-	v1 := s.app.Group("/api/v1")
-	
-	// Markets (public)
-	markets := v1.Group("/markets")
-	markets.Get("/", marketsHandler.GetMarkets)
-	markets.Get("/:id", marketsHandler.GetMarket)
-	markets.Get("/slug/:slug", marketsHandler.GetMarketBySlug)
-	markets.Get("/token/:token_id", marketsHandler.GetMarketByToken)
-	
-	// Events (public)
-	events := v1.Group("/events")
-	events.Get("/", eventsHandler.GetEvents)
-	events.Get("/search", eventsHandler.SearchEvents)
-	events.Get("/:id", eventsHandler.GetEvent)
-	events.Get("/slug/:slug", eventsHandler.GetEventBySlug)
-	
-	// Prices (public)
-	v1.Get("/price/:token_id", pricesHandler.GetPrice)
-	v1.Get("/prices", pricesHandler.GetPrices)
-	v1.Get("/book/:token_id", pricesHandler.GetOrderBook)
-	v1.Get("/books", pricesHandler.GetOrderBooks)
-	v1.Get("/spread/:token_id", pricesHandler.GetSpread)
-	v1.Get("/midpoint/:token_id", pricesHandler.GetMidpoint)
-	v1.Get("/midpoints", pricesHandler.GetMidpoints)
-	v1.Get("/last-trade/:token_id", pricesHandler.GetLastTradePrice)
-	
-	// Trades (public)
-	v1.Get("/trades/:token_id", ordersHandler.GetTrades)
-	v1.Get("/market-trades", dataHandler.GetMarketTrades)
-	
-	// Price history (public)
-	v1.Get("/price-history/:token_id", dataHandler.GetPriceHistory)
-	v1.Get("/timeseries", dataHandler.GetTimeseries)
-	
-	// Top movers & leaderboard (public)
-	v1.Get("/top-movers", dataHandler.GetTopMovers)
-	v1.Get("/leaderboard", dataHandler.GetLeaderboard)
-	
-	// User data (public, address-based)
-	v1.Get("/positions", dataHandler.GetPositions)
-	v1.Get("/positions/market", dataHandler.GetPositionsByMarket)
-	v1.Get("/user/trades", dataHandler.GetUserTrades)
-	v1.Get("/user/trades/market", dataHandler.GetUserTradesByMarket)
-	v1.Get("/activity", dataHandler.GetActivity)
-	
-	// Orders (authenticated)
-	orders := v1.Group("/orders")
-	orders.Use(middleware.OptionalAuth(&s.config.Auth))
-	
-	orders.Get("/", ordersHandler.GetOrders)
-	orders.Get("/open", ordersHandler.GetOpenOrders)
-	orders.Get("/:id", ordersHandler.GetOrder)
-	orders.Post("/", middleware.Auth(&s.config.Auth), ordersHandler.CreateOrder)
-	orders.Delete("/:id", middleware.Auth(&s.config.Auth), ordersHandler.CancelOrder)
-	orders.Delete("/cancel-all", middleware.Auth(&s.config.Auth), ordersHandler.CancelAllOrders)
-	orders.Post("/batch-cancel", middleware.Auth(&s.config.Auth), ordersHandler.CancelOrders)
+	// The API is served twice: /api/v1 relays upstream payloads as they are,
+	// /api/v2 answers with typed models. Both share the handlers below.
+	registerAPI := func(api fiber.Router, version int) {
+		// Markets (public)
+		markets := api.Group("/markets")
+		markets.Get("/", marketsHandler.GetMarkets)
+		markets.Get("/closing", catalogHandler.GetClosingMarkets)
+		markets.Get("/:id", marketsHandler.GetMarket)
+		markets.Get("/slug/:slug", marketsHandler.GetMarketBySlug)
+		markets.Get("/token/:token_id", marketsHandler.GetMarketByToken)
+		markets.Get("/:id/enriched", catalogHandler.GetEnrichedMarket)
+		markets.Get("/:id/full", marketsHandler.GetMarketFull)
+		
+		// Catalog-backed discovery (public)
+		api.Get("/screener", catalogHandler.Screener)
+		api.Get("/categories", catalogHandler.GetCategories)
+		api.Get("/tags", catalogHandler.GetTags)
+		api.Get("/tags/:slug/markets", catalogHandler.GetTagMarkets)
+		api.Get("/resolve/:token_id", catalogHandler.ResolveToken)
+		
+		// Analytics (public, computed locally)
+		api.Get("/analytics/markets/top", analyticsHandler.TopMarkets)
+		api.Get("/analytics/correlation", analyticsHandler.Correlation)
+		api.Get("/digest/daily", digestHandler.GetDaily)
+		
+		// Events (public)
+		events := api.Group("/events")
+		events.Get("/", eventsHandler.GetEvents)
+		events.Get("/search", eventsHandler.SearchEvents)
+		events.Get("/grouped", catalogHandler.GetGroupedEvents)
+		events.Get("/:id", eventsHandler.GetEvent)
+		events.Get("/slug/:slug", eventsHandler.GetEventBySlug)
+		
+		// Prices (public)
+		api.Get("/price/:token_id", pricesHandler.GetPrice)
+		api.Get("/prices", pricesHandler.GetPrices)
+		api.Get("/book/:token_id", pricesHandler.GetOrderBook)
+		api.Get("/books", pricesHandler.GetOrderBooks)
+		api.Get("/spread/:token_id", pricesHandler.GetSpread)
+		api.Get("/midpoint/:token_id", pricesHandler.GetMidpoint)
+		api.Get("/midpoints", pricesHandler.GetMidpoints)
+		api.Get("/last-trade/:token_id", pricesHandler.GetLastTradePrice)
+		api.Get("/calc/payout", pricesHandler.CalcPayout)
+		api.Get("/snapshot", snapshotHandler.GetSnapshot)
+		
+		// Trades (public)
+		api.Get("/trades/:token_id", ordersHandler.GetTrades)
+		api.Get("/market-trades", dataHandler.GetMarketTrades)
+		api.Get("/tx/:hash/verify", chainHandler.VerifyTransaction)
+		
+		// Price history (public)
+		api.Get("/price-history/:token_id", dataHandler.GetPriceHistory)
+		api.Get("/timeseries", dataHandler.GetTimeseries)
+		
+		// Top movers & leaderboard (public)
+		api.Get("/top-movers", dataHandler.GetTopMovers)
+		api.Get("/leaderboard", dataHandler.GetLeaderboard)
+		api.Get("/leaderboard/history", leaderboardHandler.GetHistory)
+		api.Get("/leaderboard/trader/:address", leaderboardHandler.GetTrader)
+		api.Get("/portfolio/:address/equity-curve", equityHandler.GetEquityCurve)
+		
+		// User data (public, address-based)
+		api.Get("/positions", dataHandler.GetPositions)
+		api.Get("/positions/market", dataHandler.GetPositionsByMarket)
+		api.Get("/user/trades", dataHandler.GetUserTrades)
+		api.Get("/user/trades/market", dataHandler.GetUserTradesByMarket)
+		api.Get("/user/balance", middleware.Auth(&s.config.Auth), balanceHandler.GetBalance)
+		api.Get("/rewards/:address", middleware.Auth(&s.config.Auth), rewardsHandler.GetRewards)
+		api.Get("/activity", dataHandler.GetActivity)
+		api.Get("/trader/:address/profile", dataHandler.GetTraderProfile)
+		if s.config.TaxReport.Enabled {
+			api.Get("/reports/tax/:address", taxReportHandler.GetTaxReport)
+		}
+		
+		// Orders (authenticated)
+		orders := api.Group("/orders")
+		orders.Use(middleware.OptionalAuth(&s.config.Auth))
+		
+		orders.Get("/", ordersHandler.GetOrders)
+		orders.Get("/open", ordersHandler.GetOpenOrders)
+		orders.Get("/expiring", ordersHandler.GetExpiringOrders)
+		orders.Get("/pair", middleware.Auth(&s.config.Auth), ordersHandler.GetOrderPairs)
+		orders.Get("/pair/:id", middleware.Auth(&s.config.Auth), ordersHandler.GetOrderPair)
+		orders.Get("/:id", ordersHandler.GetOrder)
+		preTrade := middleware.PreTradeCheck(s.risk, &s.config.Auth)
+		orderLimit := middleware.BodyLimit(s.config.Server.OrderBodyLimit)
+		orderRules := middleware.OrderRulesCheck(s.rules)
+		priceModes := middleware.ResolvePriceModes(polymarket.NewPriceResolver(s.clob))
+		throttled := middleware.OrderThrottle(s.throttle)
+		orders.Post("/", orderLimit, middleware.Auth(&s.config.Auth), s.drainer.Track(), priceModes, middleware.ValidateBody[models.CreateOrderRequest](""), orderRules, throttled, preTrade, ordersHandler.CreateOrder)
+		orders.Post("/batch", orderLimit, middleware.Auth(&s.config.Auth), s.drainer.Track(), priceModes, middleware.ValidateBody[[]models.CreateOrderRequest]("orders"), orderRules, throttled, preTrade, ordersHandler.CreateOrders)
+		orders.Post("/pair", orderLimit, middleware.Auth(&s.config.Auth), s.drainer.Track(), priceModes, middleware.ValidateBody[handlers.PairRequest](""), orderRules, throttled, preTrade, ordersHandler.CreateOrderPair)
+		orders.Post("/preview", orderLimit, priceModes, middleware.ValidateBody[models.CreateOrderRequest](""), pricesHandler.PreviewOrder)
+		orders.Delete("/pair/:id", middleware.Auth(&s.config.Auth), s.drainer.Track(), ordersHandler.CancelOrderPair)
+		orders.Delete("/:id", middleware.Auth(&s.config.Auth), s.drainer.Track(), ordersHandler.CancelOrder)
+		orders.Delete("/cancel-all", middleware.Auth(&s.config.Auth), s.drainer.Track(), ordersHandler.CancelAllOrders)
+		orders.Post("/batch-cancel", orderLimit, middleware.Auth(&s.config.Auth), s.drainer.Track(), middleware.ValidateBody[handlers.BatchCancelRequest](""), ordersHandler.CancelOrders)
+		
+		// Raw passthrough for upstream endpoints not mapped above (v1 only)
+		if version == 1 && s.config.RawProxy.Enabled {
+			rawHandler := handlers.NewRawHandler(s.client, &s.config.RawProxy, &s.config.Auth)
+			api.Get("/raw/:upstream/*", rawHandler.Proxy)
+			api.Post("/raw/:upstream/*", s.drainer.Track(), rawHandler.Proxy)
+			api.Put("/raw/:upstream/*", s.drainer.Track(), rawHandler.Proxy)
+			api.Delete("/raw/:upstream/*", s.drainer.Track(), rawHandler.Proxy)
+		}
+		
+		// Webhooks (caller-scoped when an API key is supplied, tenant-scoped with tenants)
+		hooks := api.Group("/webhooks")
+		hooks.Use(middleware.OptionalAuth(&s.config.Auth), middleware.RequireTenant(s.tenants))
+		
+		hooks.Get("/", webhooksHandler.ListWebhooks)
+		hooks.Post("/", jsonLimit, webhooksHandler.CreateWebhook)
+		hooks.Get("/deliveries/:id", webhooksHandler.GetDelivery)
+		hooks.Delete("/:id", webhooksHandler.DeleteWebhook)
+		hooks.Post("/:id/secret", jsonLimit, webhooksHandler.RotateWebhookSecret)
+		
+		// Wallet and market watchlists (caller-scoped when an API key is supplied, tenant-scoped with tenants)
+		if s.config.Watchlist.Enabled {
+			watch := api.Group("/watchlist")
+			watch.Use(middleware.OptionalAuth(&s.config.Auth), middleware.RequireTenant(s.tenants))
+			
+			watch.Get("/wallets", watchlistHandler.ListWallets)
+			watch.Post("/wallets", jsonLimit, watchlistHandler.AddWallet)
+			watch.Delete("/wallets/:address", watchlistHandler.RemoveWallet)
+			watch.Get("/markets", watchlistHandler.ListMarkets)
+			watch.Post("/markets", jsonLimit, watchlistHandler.AddMarket)
+			watch.Delete("/markets/:id", watchlistHandler.RemoveMarket)
+		}
+		
+		// Position change alerts (caller-scoped when an API key is supplied, tenant-scoped with tenants)
+		if s.config.PositionAlerts.Enabled {
+			alerts := api.Group("/alerts/positions")
+			alerts.Use(middleware.OptionalAuth(&s.config.Auth), middleware.RequireTenant(s.tenants))
+			
+			alerts.Get("/", posAlertsHandler.ListPositionAlerts)
+			alerts.Post("/", jsonLimit, posAlertsHandler.CreatePositionAlert)
+			alerts.Get("/:id", posAlertsHandler.GetPositionAlert)
+			alerts.Put("/:id", jsonLimit, posAlertsHandler.UpdatePositionAlert)
+			alerts.Delete("/:id", posAlertsHandler.DeletePositionAlert)
+		}
+		
+		// Per-strategy statistics of tagged orders (caller-scoped when an API key is supplied, tenant-scoped with tenants)
+		if s.config.Strategies.Enabled {
+			api.Get("/analytics/strategies", middleware.OptionalAuth(&s.config.Auth), middleware.RequireTenant(s.tenants), strategiesHandler.GetStrategies)
+		}
+		
+		// Copy trading places orders with the server's account (operator-only)
+		if s.config.CopyTrade.Enabled {
+			copyTrade := api.Group("/copytrade", middleware.RequireRole(s.access, access.RoleAdmin), middleware.AdminAuth(s.config.Admin.Token))
+			
+			copyTrade.Post("/start", jsonLimit, copyTradeHandler.Start)
+			copyTrade.Post("/stop", copyTradeHandler.Stop)
+			copyTrade.Get("/status", copyTradeHandler.Status)
+		}
+	}
+	registerAPI(s.app.Group("/api/v1", middleware.APIVersion(1)), 1)
+	registerAPI(s.app.Group("/api/v2", middleware.APIVersion(2)), 2)
 	
 	// WebSocket endpoints
 	ws := s.app.Group("/ws")
 	ws.Use(handlers.WSMiddleware())
 	
 	ws.Get("/market/:market_id", websocket.New(wsHandler.HandleMarketWS))
+	ws.Get("/token/:token_id", websocket.New(wsHandler.HandleTokenWS))
 	ws.Get("/markets", websocket.New(wsHandler.HandleAllMarketsWS))
+	ws.Get("/events", middleware.Auth(&s.config.Auth), middleware.RequireTenant(s.tenants), websocket.New(webhooksHandler.HandleEventsWS))
+	ws.Get("/listings", websocket.New(listingsHandler.HandleListingsWS))
+	if s.config.Ticker.Enabled {
+		ws.Get("/ticker", websocket.New(tickerHandler.HandleTickerWS))
+	}
+	if s.config.Watchlist.Enabled {
+		ws.Get("/watchlist", middleware.OptionalAuth(&s.config.Auth), middleware.RequireTenant(s.tenants), websocket.New(watchlistHandler.HandleWatchlistWS))
+	}
+	
+	// Upstream pass-through for replica instances
+	if s.config.Replication.ServeReplicas {
+		replicaHandler := handlers.NewReplicaHandler(s.client, &s.config.Cache)
+		// Get serves HEAD too; writes never go through a primary
+		s.app.Get("/replica/:upstream/*", middleware.ReplicaToken(s.config.Replication.Token), replicaHandler.Proxy)
+	}
 }
 
 // Start starts the server
@@ -196,16 +774,102 @@ func (s *Server) Start() error {
 		}
 	}()
 	
+	// Check base and mirror URLs of upstream APIs
+	s.failover.Start()
+	
+	// Resolve and connect to the upstream APIs before requests need them
+	go func() {
+		if err := s.client.Warmup(); err != nil {
+			log.Printf("Upstream warmup: %v", err)
+		}
+	}()
+	
+	// Sync the local market catalog in the background
+	s.catalog.Start()
+	s.recorder.Start()
+	s.leaderboard.Start()
+	s.trades.Start()
+	s.ticker.Start()
+	s.watchlist.Start()
+	s.posAlerts.Start()
+	s.equity.Start()
+	s.expiry.Start()
+	s.notifier.Start()
+	s.exports.Start()
+	s.webhooks.Start()
+	s.eventBus.Start()
+	s.ruleEngine.Start()
+	s.tenants.Start()
+	s.reporter.Start()
+	
+	// Unix sockets and systemd-activated sockets replace Host:Port
+	if s.config.Server.Listen != "" {
+		ln, err := listener.Open(&s.config.Server)
+		if err != nil {
+			return err
+		}
+		return s.app.Listener(ln)
+	}
+	
 	addr := s.config.Server.Host + ":" + itoa(s.config.Server.Port)
 	return s.app.Listen(addr)
 }
 
-// Shutdown gracefully shuts down the server
+// Shutdown gracefully shuts down the server. New requests are rejected,
+// WebSocket clients are told to reconnect elsewhere, in-flight orders are
+// given DrainTimeout to finish, and open connections ShutdownTimeout to close.
 func (s *Server) Shutdown() error {
+	s.drainer.Start()
+	
+	if s.wsHandler != nil {
+		s.wsHandler.Shutdown(s.config.Server.ReconnectHint)
+	}
+	// Closes ticker and watchlist streams so their connections do not hold up shutdown
+	s.ticker.Stop()
+	s.watchlist.Stop()
+	// Stops mirroring and rule orders before in-flight orders drain
+	s.copytrade.Stop()
+	s.ruleEngine.Stop()
+	// Disarmed so closing /ws/events connections does not cancel their orders
+	s.deadMan.Stop()
+	
+	if !s.drainer.Wait(s.config.Server.DrainTimeout) {
+		log.Printf("Drain timeout exceeded with %d order requests still in flight", s.drainer.InFlight())
+	}
+	
+	err := s.app.ShutdownWithTimeout(s.config.Server.ShutdownTimeout)
+	
+	s.catalog.Stop()
+	s.listings.Stop()
+	s.recorder.Stop()
+	s.posAlerts.Stop()
+	s.equity.Stop()
+	s.leaderboard.Stop()
+	s.expiry.Stop()
+	s.notifier.Stop()
+	s.exports.Stop()
+	s.trades.Stop()
+	s.webhooks.Stop()
+	s.eventBus.Stop()
+	s.tenants.Stop()
+	s.reporter.Stop()
+	s.failover.Stop()
 	s.wsManager.Close()
+	if s.wsHandler != nil {
+		s.wsHandler.Close()
+	}
+	s.tape.Close()
 	s.client.Close()
+	s.audit.Close()
+	
+	// Saved last, once nothing fills the cache anymore
+	if n, err := s.cache.Save(); err != nil {
+		log.Printf("Failed to save cache snapshot: %v", err)
+	} else if n > 0 {
+		log.Printf("Saved %d cache entries to %s", n, s.config.Cache.PersistPath)
+	}
 	s.cache.Close()
-	return s.app.Shutdown()
+	return err
 }
 
 // GetApp returns the Fiber app (for testing)
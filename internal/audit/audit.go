@@ -0,0 +1,301 @@
+// Package audit keeps an append-only record of the changes callers make
+// through PolyGo (orders placed and cancelled, dead-man's switches armed,
+// configuration and webhooks changed) for compliance review. Entries are
+// appended to a JSON lines file and never rewritten; the latest are also
+// held in memory to be searched.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+)
+
+// Export formats
+const (
+	FormatJSONL = "jsonl"
+	FormatCSV   = "csv"
+)
+
+// ErrInvalidFormat is returned for an export format other than jsonl or csv
+var ErrInvalidFormat = errors.New("invalid export format")
+
+// Entry is one recorded action
+type Entry struct {
+	Seq         int64     `json:"seq"`
+	Time        time.Time `json:"time"`
+	Actor       string    `json:"actor"`  // API key, or admin for the admin token
+	IP          string    `json:"ip"`     // client IP
+	Action      string    `json:"action"` // e.g. order.create or webhook.delete
+	Method      string    `json:"method"` // HTTP method, or WS for WebSocket messages
+	Path        string    `json:"path"`
+	Status      int       `json:"status"` // HTTP status answered (0 for WebSocket messages)
+	RequestID   string    `json:"request_id,omitempty"`
+	PayloadHash string    `json:"payload_hash,omitempty"` // SHA-256 of the request body, hex
+}
+
+// Filter selects entries. Action matches exactly or by family (order.*).
+type Filter struct {
+	Actor  string
+	Action string
+	Since  time.Time // inclusive
+	Until  time.Time // exclusive
+}
+
+// Matches reports whether an entry passes the filter
+func (f Filter) Matches(e *Entry) bool {
+	if f.Actor != "" && e.Actor != f.Actor {
+		return false
+	}
+	if f.Action != "" {
+		if family, ok := strings.CutSuffix(f.Action, "*"); ok {
+			if !strings.HasPrefix(e.Action, family) {
+				return false
+			}
+		} else if e.Action != f.Action {
+			return false
+		}
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.Time.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Log is the audit log
+type Log struct {
+	config *config.AuditConfig
+
+	mu      sync.Mutex
+	file    *os.File
+	seq     int64
+	entries []Entry // the latest Keep, oldest first
+}
+
+// New opens the audit log, picking up the sequence where the file left it
+func New(cfg *config.AuditConfig) (*Log, error) {
+	l := &Log{config: cfg}
+	if !cfg.Enabled || cfg.Path == "" {
+		return l, nil
+	}
+
+	if err := l.load(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(cfg.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	l.file = f
+	return l, nil
+}
+
+// load reads the file's latest entries back into memory
+func (l *Log) load() error {
+	f, err := os.Open(l.config.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return scan(f, func(e *Entry) {
+		l.seq = e.Seq
+		l.keep(*e)
+	})
+}
+
+// Enabled reports whether actions are recorded
+func (l *Log) Enabled() bool {
+	return l.config.Enabled
+}
+
+// Record appends an entry, numbering and timestamping it. Failures to
+// write the file are logged; the entry is still kept in memory.
+func (l *Log) Record(e Entry) {
+	if !l.config.Enabled {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.seq++
+	e.Seq = l.seq
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	l.keep(e)
+
+	if l.file == nil {
+		return
+	}
+	line, err := sonic.Marshal(e)
+	if err == nil {
+		_, err = l.file.Write(append(line, '\n'))
+	}
+	if err != nil {
+		log.Printf("Failed to append audit entry %d to %s: %v", e.Seq, l.config.Path, err)
+	}
+}
+
+// keep holds an entry in memory, dropping the oldest past Keep. Caller
+// holds l.mu or has the log to itself.
+func (l *Log) keep(e Entry) {
+	l.entries = append(l.entries, e)
+	if n := len(l.entries) - l.config.Keep; l.config.Keep > 0 && n > 0 {
+		l.entries = append(l.entries[:0:0], l.entries[n:]...)
+	}
+}
+
+// Query returns the entries held in memory that pass the filter, newest
+// first
+func (l *Log) Query(f Filter) []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	out := []Entry{}
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		if f.Matches(&l.entries[i]) {
+			out = append(out, l.entries[i])
+		}
+	}
+	return out
+}
+
+// Export writes every entry that passes the filter, oldest first, as JSON
+// lines or CSV. With a file it reads the whole file, so entries too old
+// to be searched are exported too.
+func (l *Log) Export(w io.Writer, f Filter, format string) error {
+	if format != FormatJSONL && format != FormatCSV {
+		return ErrInvalidFormat
+	}
+
+	var cw *csv.Writer
+	if format == FormatCSV {
+		cw = csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	var werr error
+	emit := func(e *Entry) {
+		if werr != nil || !f.Matches(e) {
+			return
+		}
+		if cw != nil {
+			werr = cw.Write(e.record())
+			return
+		}
+		line, err := sonic.Marshal(e)
+		if err == nil {
+			_, err = w.Write(append(line, '\n'))
+		}
+		werr = err
+	}
+
+	if l.file != nil {
+		data, err := l.snapshot()
+		if err != nil {
+			return err
+		}
+		if err := scan(bytes.NewReader(data), emit); err != nil {
+			return err
+		}
+	} else {
+		l.mu.Lock()
+		entries := append([]Entry(nil), l.entries...)
+		l.mu.Unlock()
+		for i := range entries {
+			emit(&entries[i])
+		}
+	}
+	if werr != nil {
+		return werr
+	}
+
+	if cw != nil {
+		cw.Flush()
+		return cw.Error()
+	}
+	return nil
+}
+
+// snapshot reads the file as of now, so appends during an export are left
+// out rather than cut in half
+func (l *Log) snapshot() ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return os.ReadFile(l.config.Path)
+}
+
+// Close closes the file
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// HashPayload returns the hex SHA-256 of a request body, or "" for none
+func HashPayload(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// scan decodes JSON lines, skipping lines that are not entries (e.g. one
+// cut short by a crash)
+func scan(r io.Reader, fn func(*Entry)) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var e Entry
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 || sonic.Unmarshal(sc.Bytes(), &e) != nil {
+			continue
+		}
+		fn(&e)
+	}
+	return sc.Err()
+}
+
+// csvHeader names the columns of an export as CSV
+var csvHeader = []string{
+	"seq", "time", "actor", "ip", "action", "method", "path", "status", "request_id", "payload_hash",
+}
+
+func (e *Entry) record() []string {
+	return []string{
+		strconv.FormatInt(e.Seq, 10), e.Time.UTC().Format(time.RFC3339Nano), e.Actor, e.IP, e.Action,
+		e.Method, e.Path, strconv.Itoa(e.Status), e.RequestID, e.PayloadHash,
+	}
+}
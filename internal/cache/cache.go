@@ -1,7 +1,9 @@
 package cache
 
 import (
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bytedance/sonic"
@@ -13,7 +15,46 @@ import (
 type Cache struct {
 	store  *ristretto.Cache
 	config *config.CacheConfig
-	pool   sync.Pool // Pool for byte slices
+	codec  *codec // nil when compression is disabled
+	sizes  sizeCounters
+	
+	// Keys being refreshed by one caller while others serve them stale,
+	// with the unix nano time their lock lapses
+	refreshing sync.Map
+	
+	// Keys under PersistPrefixes, written to PersistPath by Save
+	persisted sync.Map
+}
+
+// entry is a stored value, []byte or *compressedValue, and the unix nano
+// time it turns stale (0 = never)
+type entry struct {
+	value      interface{}
+	freshUntil int64
+}
+
+// sizeCounters tracks entry size accounting
+type sizeCounters struct {
+	rejectedOversize atomic.Uint64
+	rejectedPolicy   atomic.Uint64
+	evicted          atomic.Uint64
+	evictedBytes     atomic.Uint64
+	compressed       atomic.Uint64
+	bytesSaved       atomic.Uint64
+}
+
+// SizeStats reports size-related cache metrics
+type SizeStats struct {
+	MaxEntrySize     int64  `json:"max_entry_size"`
+	Compression      string `json:"compression"`
+	RejectedOversize uint64 `json:"rejected_oversize"`
+	RejectedPolicy   uint64 `json:"rejected_policy"`
+	Evicted          uint64 `json:"evicted"`
+	EvictedBytes     uint64 `json:"evicted_bytes"`
+	Compressed       uint64 `json:"compressed"`
+	BytesSaved       uint64 `json:"bytes_saved"`
+	CostAdded        uint64 `json:"cost_added"`
+	CostEvicted      uint64 `json:"cost_evicted"`
 }
 
 // CacheEntry represents a cached entry with metadata
@@ -25,41 +66,81 @@ type CacheEntry struct {
 
 // New creates a new cache instance
 func New(cfg *config.CacheConfig) (*Cache, error) {
+	cd, err := newCodec(cfg.Compression)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cache{
+		config: cfg,
+		codec:  cd,
+	}
+
 	store, err := ristretto.NewCache(&ristretto.Config{
 		NumCounters: cfg.NumCounters,
 		MaxCost:     cfg.MaxCost,
 		BufferItems: cfg.BufferItems,
 		Metrics:     true,
+		OnEvict: func(item *ristretto.Item) {
+			c.sizes.evicted.Add(1)
+			c.sizes.evictedBytes.Add(uint64(item.Cost))
+		},
+		OnReject: func(item *ristretto.Item) {
+			c.sizes.rejectedPolicy.Add(1)
+		},
 	})
 	if err != nil {
 		return nil, err
 	}
+	c.store = store
 
-	return &Cache{
-		store:  store,
-		config: cfg,
-		pool: sync.Pool{
-			New: func() interface{} {
-				// Pre-allocate 4KB buffers
-				return make([]byte, 0, 4096)
-			},
-		},
-	}, nil
+	return c, nil
 }
 
-// Get retrieves a value from cache
+// Get retrieves a value from cache, unless it is past its TTL
 func (c *Cache) Get(key string) ([]byte, bool) {
+	data, fresh, found := c.GetStale(key)
+	return data, found && fresh
+}
+
+// GetStale retrieves a value from cache even when it is past its TTL, for
+// up to StaleFor; fresh reports whether it is still within its TTL
+func (c *Cache) GetStale(key string) (data []byte, fresh bool, found bool) {
+	data, freshUntil, found := c.lookup(key)
+	if !found {
+		return nil, false, false
+	}
+	fresh = freshUntil == 0 || time.Now().UnixNano() < freshUntil
+	return data, fresh, true
+}
+
+// lookup returns key's value, decompressed, and the unix nano time it
+// turns stale
+func (c *Cache) lookup(key string) ([]byte, int64, bool) {
 	val, found := c.store.Get(key)
 	if !found {
-		return nil, false
+		return nil, 0, false
 	}
-	
-	data, ok := val.([]byte)
+	e, ok := val.(*entry)
 	if !ok {
-		return nil, false
+		return nil, 0, false
 	}
 	
-	return data, true
+	switch v := e.value.(type) {
+	case []byte:
+		return v, e.freshUntil, true
+	case *compressedValue:
+		if c.codec == nil {
+			return nil, 0, false
+		}
+		data, err := c.codec.decompress(v)
+		if err != nil {
+			return nil, 0, false
+		}
+		return data, e.freshUntil, true
+	}
+	
+	return nil, 0, false
 }
 
 // GetJSON retrieves and unmarshals a value from cache
@@ -77,12 +158,54 @@ func (c *Cache) GetJSON(key string, dest interface{}) bool {
 }
 
 // Set stores a value in cache with TTL
+// Entries larger than MaxEntrySize are rejected, and entries above
+// CompressThreshold are compressed when compression is enabled. The TTL
+// is jittered by TTLJitter, and the entry is kept StaleFor past it.
 func (c *Cache) Set(key string, value []byte, ttl time.Duration) bool {
-	// Make a copy to avoid data races
-	data := make([]byte, len(value))
-	copy(data, value)
+	return c.put(key, value, c.jitter(ttl), false)
+}
+
+// SetNoCopy is Set without copying value, which is shared with every later
+// Get: the caller must not modify it afterwards. Values are never recycled
+// either, since bytes returned by Get may still be referenced, e.g. by
+// strings sonic decoded from them or by a response being written.
+func (c *Cache) SetNoCopy(key string, value []byte, ttl time.Duration) bool {
+	return c.put(key, value, c.jitter(ttl), true)
+}
+
+// put stores value for exactly ttl, copying it unless owned
+func (c *Cache) put(key string, value []byte, ttl time.Duration, owned bool) bool {
+	if c.config.MaxEntrySize > 0 && int64(len(value)) > c.config.MaxEntrySize {
+		c.sizes.rejectedOversize.Add(1)
+		return false
+	}
+	if c.persists(key) {
+		c.persisted.Store(key, struct{}{})
+	}
+	
+	e := &entry{}
+	keep := ttl
+	if ttl > 0 {
+		e.freshUntil = time.Now().Add(ttl).UnixNano()
+		keep += c.config.StaleFor
+	}
+	
+	if c.codec != nil && c.config.CompressThreshold > 0 && len(value) >= c.config.CompressThreshold {
+		compressed := c.codec.compress(value)
+		if len(compressed) < len(value) {
+			c.sizes.compressed.Add(1)
+			c.sizes.bytesSaved.Add(uint64(len(value) - len(compressed)))
+			e.value = &compressedValue{codec: c.codec.name, data: compressed, size: len(value)}
+			return c.store.SetWithTTL(key, e, int64(len(compressed)), keep)
+		}
+	}
 	
-	return c.store.SetWithTTL(key, data, int64(len(data)), ttl)
+	if !owned {
+		// Make a copy to avoid data races
+		value = append([]byte(nil), value...)
+	}
+	e.value = value
+	return c.store.SetWithTTL(key, e, int64(len(value)), keep)
 }
 
 // SetJSON marshals and stores a value in cache
@@ -103,11 +226,16 @@ func (c *Cache) SetWithDefaultTTL(key string, value []byte) bool {
 // Delete removes a value from cache
 func (c *Cache) Delete(key string) {
 	c.store.Del(key)
+	c.persisted.Delete(key)
 }
 
 // Clear removes all values from cache
 func (c *Cache) Clear() {
 	c.store.Clear()
+	c.persisted.Range(func(key, _ interface{}) bool {
+		c.persisted.Delete(key)
+		return true
+	})
 }
 
 // Wait waits for all pending sets to complete
@@ -118,6 +246,9 @@ func (c *Cache) Wait() {
 // Close closes the cache
 func (c *Cache) Close() {
 	c.store.Close()
+	if c.codec != nil {
+		c.codec.close()
+	}
 }
 
 // Metrics returns cache metrics
@@ -134,6 +265,28 @@ func (c *Cache) HitRatio() float64 {
 	return metrics.Ratio()
 }
 
+// SizeStats returns entry size accounting metrics
+func (c *Cache) SizeStats() SizeStats {
+	stats := SizeStats{
+		MaxEntrySize:     c.config.MaxEntrySize,
+		Compression:      CompressionNone,
+		RejectedOversize: c.sizes.rejectedOversize.Load(),
+		RejectedPolicy:   c.sizes.rejectedPolicy.Load(),
+		Evicted:          c.sizes.evicted.Load(),
+		EvictedBytes:     c.sizes.evictedBytes.Load(),
+		Compressed:       c.sizes.compressed.Load(),
+		BytesSaved:       c.sizes.bytesSaved.Load(),
+	}
+	if c.codec != nil {
+		stats.Compression = c.codec.name
+	}
+	if m := c.store.Metrics; m != nil {
+		stats.CostAdded = m.CostAdded()
+		stats.CostEvicted = m.CostEvicted()
+	}
+	return stats
+}
+
 // GetConfig returns the cache configuration (for accessing TTL values)
 func (c *Cache) GetConfig() *config.CacheConfig {
 	return c.config
@@ -148,6 +301,9 @@ const (
 	PrefixSpread    = "spread:"
 	PrefixTrades    = "trades:"
 	PrefixPositions = "positions:"
+	PrefixUserData  = "user:"
+	PrefixTx        = "tx:"
+	PrefixDigest    = "digest:"
 )
 
 // MarketKey generates a cache key for market
@@ -155,6 +311,11 @@ func MarketKey(id string) string {
 	return PrefixMarkets + id
 }
 
+// MarketDetailKey generates a cache key for a composite market detail
+func MarketDetailKey(id string) string {
+	return PrefixMarkets + "full:" + id
+}
+
 // MarketsListKey generates a cache key for markets list
 func MarketsListKey(params string) string {
 	return PrefixMarkets + "list:" + params
@@ -180,7 +341,29 @@ func OrderBookKey(tokenID string) string {
 	return PrefixOrderBook + tokenID
 }
 
+// UserDataKey generates a cache key for per-user data; generation is bumped
+// to invalidate everything cached for the address
+func UserDataKey(address string, generation uint64, params string) string {
+	return PrefixUserData + address + ":" + strconv.FormatUint(generation, 10) + ":" + params
+}
+
+// NamespacedUserDataKey is UserDataKey for a namespace's (tenant's) own copy
+// of the data
+func NamespacedUserDataKey(namespace, address string, generation uint64, params string) string {
+	return PrefixUserData + namespace + "/" + address + ":" + strconv.FormatUint(generation, 10) + ":" + params
+}
+
 // SpreadKey generates a cache key for spread
 func SpreadKey(tokenID string) string {
 	return PrefixSpread + tokenID
 }
+
+// SettlementKey generates a cache key for a transaction's settled status
+func SettlementKey(hash string) string {
+	return PrefixTx + hash
+}
+
+// DigestKey generates a cache key for a computed digest
+func DigestKey(name string) string {
+	return PrefixDigest + name
+}
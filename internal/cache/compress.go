@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Supported compression codecs for large cache entries
+const (
+	CompressionNone   = "none"
+	CompressionSnappy = "snappy"
+	CompressionZstd   = "zstd"
+)
+
+// compressedValue is stored in place of a raw []byte when an entry was
+// compressed on the way in
+type compressedValue struct {
+	codec string
+	data  []byte
+	size  int // uncompressed size
+}
+
+// codec compresses and decompresses cache payloads
+type codec struct {
+	name    string
+	zstdEnc *zstd.Encoder
+	zstdDec *zstd.Decoder
+}
+
+// newCodec creates the codec named in config; unknown names are an error
+func newCodec(name string) (*codec, error) {
+	switch name {
+	case "", CompressionNone:
+		return nil, nil
+	case CompressionSnappy:
+		return &codec{name: CompressionSnappy}, nil
+	case CompressionZstd:
+		enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest))
+		if err != nil {
+			return nil, err
+		}
+		dec, err := zstd.NewReader(nil)
+		if err != nil {
+			return nil, err
+		}
+		return &codec{name: CompressionZstd, zstdEnc: enc, zstdDec: dec}, nil
+	default:
+		return nil, fmt.Errorf("unknown cache compression %q", name)
+	}
+}
+
+// compress encodes data with the codec
+func (c *codec) compress(data []byte) []byte {
+	if c.name == CompressionZstd {
+		return c.zstdEnc.EncodeAll(data, make([]byte, 0, len(data)/4))
+	}
+	return snappy.Encode(nil, data)
+}
+
+// decompress decodes a value produced by compress
+func (c *codec) decompress(v *compressedValue) ([]byte, error) {
+	if v.codec == CompressionZstd {
+		return c.zstdDec.DecodeAll(v.data, make([]byte, 0, v.size))
+	}
+	return snappy.Decode(make([]byte, v.size), v.data)
+}
+
+// close releases codec resources
+func (c *codec) close() {
+	if c.zstdDec != nil {
+		c.zstdDec.Close()
+	}
+	if c.zstdEnc != nil {
+		c.zstdEnc.Close()
+	}
+}
@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/fsutil"
+)
+
+// snapshot is the on-disk form of the persisted entries
+type snapshot struct {
+	SavedAt time.Time       `json:"saved_at"`
+	Entries []snapshotEntry `json:"entries"`
+}
+
+type snapshotEntry struct {
+	Key        string `json:"key"`
+	Data       []byte `json:"data"`
+	FreshUntil int64  `json:"fresh_until"` // unix nano, 0 = never stale
+}
+
+// persists reports whether key is written to the snapshot
+func (c *Cache) persists(key string) bool {
+	if c.config.PersistPath == "" {
+		return false
+	}
+	for _, prefix := range c.config.PersistPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Save writes the fresh entries under PersistPrefixes to PersistPath,
+// replacing the file atomically, and returns how many were written. It
+// does nothing when PersistPath is not set.
+func (c *Cache) Save() (int, error) {
+	if c.config.PersistPath == "" {
+		return 0, nil
+	}
+
+	now := time.Now()
+	s := snapshot{SavedAt: now, Entries: []snapshotEntry{}}
+	c.persisted.Range(func(k, _ interface{}) bool {
+		key := k.(string)
+		data, freshUntil, found := c.lookup(key)
+		if !found || (freshUntil != 0 && freshUntil <= now.UnixNano()) {
+			// Evicted or expired since it was set
+			c.persisted.Delete(key)
+			return true
+		}
+		s.Entries = append(s.Entries, snapshotEntry{Key: key, Data: data, FreshUntil: freshUntil})
+		return true
+	})
+
+	data, err := sonic.Marshal(s)
+	if err != nil {
+		return 0, err
+	}
+	if err := fsutil.WriteFileAtomic(c.config.PersistPath, data, 0o600); err != nil {
+		return 0, err
+	}
+	return len(s.Entries), nil
+}
+
+// Load restores the entries saved at PersistPath that are still fresh, for
+// the rest of their TTL, and returns how many were restored. A missing
+// file is not an error.
+func (c *Cache) Load() (int, error) {
+	if c.config.PersistPath == "" {
+		return 0, nil
+	}
+
+	data, err := os.ReadFile(c.config.PersistPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var s snapshot
+	if err := sonic.Unmarshal(data, &s); err != nil {
+		return 0, err
+	}
+
+	n := 0
+	now := time.Now()
+	for _, e := range s.Entries {
+		var ttl time.Duration
+		if e.FreshUntil != 0 {
+			if ttl = time.Unix(0, e.FreshUntil).Sub(now); ttl <= 0 {
+				continue
+			}
+		}
+		if c.put(e.Key, e.Data, ttl, true) {
+			n++
+		}
+	}
+	c.store.Wait()
+	return n, nil
+}
@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"math/rand"
+	"time"
+)
+
+// minRefreshLock bounds how soon a refresh lock lapses when StaleFor is
+// short, so a slow refresher is not raced by the next caller
+const minRefreshLock = time.Second
+
+// jitter spreads ttl by up to TTLJitter either way, so keys cached at the
+// same moment do not all expire on the same boundary
+func (c *Cache) jitter(ttl time.Duration) time.Duration {
+	if ttl <= 0 || c.config.TTLJitter <= 0 {
+		return ttl
+	}
+	f := 1 + c.config.TTLJitter*(2*rand.Float64()-1)
+	return max(time.Duration(float64(ttl)*f), time.Millisecond)
+}
+
+// BeginRefresh takes key's refresh lock, returning false when another
+// caller holds it. The holder repopulates the key while other callers
+// serve the stale value; the lock lapses after StaleFor (at least a
+// second) in case the holder never calls EndRefresh.
+func (c *Cache) BeginRefresh(key string) bool {
+	now := time.Now().UnixNano()
+	until := now + int64(max(c.config.StaleFor, minRefreshLock))
+
+	held, loaded := c.refreshing.LoadOrStore(key, until)
+	if !loaded {
+		return true
+	}
+	// Take over a lapsed lock, unless someone else just did
+	return held.(int64) <= now && c.refreshing.CompareAndSwap(key, held, until)
+}
+
+// EndRefresh releases key's refresh lock
+func (c *Cache) EndRefresh(key string) {
+	c.refreshing.Delete(key)
+}
@@ -0,0 +1,271 @@
+package catalog
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+)
+
+// Entry is a market in the local catalog together with the event it
+// belongs to and its normalized category
+type Entry struct {
+	models.Market
+	EventID    string       `json:"eventId,omitempty"`
+	EventSlug  string       `json:"eventSlug,omitempty"`
+	EventTitle string       `json:"eventTitle,omitempty"`
+	Tags       []models.Tag `json:"tags,omitempty"`
+	Category   string       `json:"category"`
+	FirstSeen  time.Time    `json:"firstSeen"`
+}
+
+// Catalog is a periodically synced, in-memory registry of Gamma events and
+// markets used by endpoints that need to filter or aggregate locally
+type Catalog struct {
+	gamma      *polymarket.GammaClient
+	config     *config.CatalogConfig
+	classifier *Classifier
+
+	mu       sync.RWMutex
+	markets  map[string]*Entry
+	tokens   map[string]tokenRef // CLOB token ID -> market and outcome
+	events   map[string]*models.Event
+	lastSync time.Time
+	lastErr  error
+	onListed []func([]*Entry)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a new catalog
+func New(gamma *polymarket.GammaClient, cfg *config.CatalogConfig) *Catalog {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Catalog{
+		gamma:      gamma,
+		config:     cfg,
+		classifier: NewClassifier(cfg.Categories),
+		markets:    make(map[string]*Entry),
+		tokens:     make(map[string]tokenRef),
+		events:     make(map[string]*models.Event),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start runs an initial sync and then re-syncs every SyncInterval
+func (c *Catalog) Start() {
+	if !c.config.Enabled {
+		return
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		ticker := time.NewTicker(c.config.SyncInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := c.Sync(); err != nil {
+				log.Printf("Catalog sync failed: %v", err)
+			}
+
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the background sync
+func (c *Catalog) Stop() {
+	c.cancel()
+	c.wg.Wait()
+}
+
+// Sync fetches all active events (with their markets) from Gamma and
+// replaces the catalog contents
+func (c *Catalog) Sync() error {
+	active, closed := true, false
+	result, err := c.gamma.GetAllEvents(&models.EventQueryParams{
+		Limit:  c.config.PageSize,
+		Active: &active,
+		Closed: &closed,
+	}, c.config.MaxEvents)
+	if err != nil {
+		c.mu.Lock()
+		c.lastErr = err
+		c.mu.Unlock()
+		return err
+	}
+
+	events := make(map[string]*models.Event, len(result.Items))
+	for _, raw := range result.Items {
+		var event models.Event
+		if err := sonic.Unmarshal(raw, &event); err != nil {
+			continue
+		}
+		events[event.ID] = &event
+	}
+
+	c.replace(events, time.Now())
+	return nil
+}
+
+// Load replaces the catalog contents with the given events, as if they had
+// just been synced (used for warm starts and tests)
+func (c *Catalog) Load(events []*models.Event) {
+	byID := make(map[string]*models.Event, len(events))
+	for _, e := range events {
+		byID[e.ID] = e
+	}
+	c.replace(byID, time.Now())
+}
+
+// Classify returns the category for a market that may not be in the catalog
+func (c *Catalog) Classify(e *Entry) string {
+	return c.classifier.Classify(e)
+}
+
+// OnListed registers fn to be called, after a sync, with the markets it
+// added. The first sync only records what is already listed.
+func (c *Catalog) OnListed(fn func([]*Entry)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onListed = append(c.onListed, fn)
+}
+
+// replace swaps in a freshly synced set of events
+func (c *Catalog) replace(events map[string]*models.Event, now time.Time) {
+	c.mu.Lock()
+
+	primed := !c.lastSync.IsZero()
+	var listed []*Entry
+	markets := make(map[string]*Entry)
+	tokens := make(map[string]tokenRef)
+	for _, event := range events {
+		for _, m := range event.Markets {
+			entry := &Entry{
+				Market:     m,
+				EventID:    event.ID,
+				EventSlug:  event.Slug,
+				EventTitle: event.Title,
+				Tags:       event.Tags,
+				FirstSeen:  now,
+			}
+			if prev, ok := c.markets[m.ID]; ok {
+				entry.FirstSeen = prev.FirstSeen
+			} else if primed && markets[m.ID] == nil {
+				listed = append(listed, entry)
+			}
+			entry.Category = c.classifier.Classify(entry)
+			markets[m.ID] = entry
+			for i, tokenID := range m.ClobTokenIDs {
+				tokens[tokenID] = tokenRef{entry: entry, outcome: i}
+			}
+		}
+	}
+
+	c.events = events
+	c.markets = markets
+	c.tokens = tokens
+	c.lastSync = now
+	c.lastErr = nil
+	hooks := c.onListed
+	c.mu.Unlock()
+
+	if len(listed) == 0 {
+		return
+	}
+	sort.Slice(listed, func(i, j int) bool { return listed[i].ID < listed[j].ID })
+	for _, fn := range hooks {
+		fn(listed)
+	}
+}
+
+// Markets returns every market in the catalog, ordered by ID
+func (c *Catalog) Markets() []*Entry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	out := make([]*Entry, 0, len(c.markets))
+	for _, e := range c.markets {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// Market returns a single market by ID
+func (c *Catalog) Market(id string) (*Entry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	e, ok := c.markets[id]
+	return e, ok
+}
+
+// tokenRef locates a CLOB token: its market and outcome index
+type tokenRef struct {
+	entry   *Entry
+	outcome int
+}
+
+// Token returns the market a CLOB token belongs to and its outcome index
+func (c *Catalog) Token(tokenID string) (*Entry, int, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ref, ok := c.tokens[tokenID]
+	return ref.entry, ref.outcome, ok
+}
+
+// Events returns every event in the catalog, ordered by ID
+func (c *Catalog) Events() []*models.Event {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	out := make([]*models.Event, 0, len(c.events))
+	for _, e := range c.events {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// Status describes the last sync
+type Status struct {
+	Enabled  bool      `json:"enabled"`
+	Markets  int       `json:"markets"`
+	Events   int       `json:"events"`
+	LastSync time.Time `json:"last_sync,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Status returns the catalog sync status
+func (c *Catalog) Status() Status {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s := Status{
+		Enabled:  c.config.Enabled,
+		Markets:  len(c.markets),
+		Events:   len(c.events),
+		LastSync: c.lastSync,
+	}
+	if c.lastErr != nil {
+		s.Error = c.lastErr.Error()
+	}
+	return s
+}
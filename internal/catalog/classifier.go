@@ -0,0 +1,141 @@
+package catalog
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Normalized market categories
+const (
+	CategoryPolitics      = "politics"
+	CategorySports        = "sports"
+	CategoryCrypto        = "crypto"
+	CategoryEconomics     = "economics"
+	CategoryTech          = "tech"
+	CategoryEntertainment = "entertainment"
+	CategoryWorld         = "world"
+	CategoryOther         = "other"
+)
+
+// defaultKeywords maps each category to words that indicate it. Tag slugs
+// are matched against the same lists.
+var defaultKeywords = map[string][]string{
+	CategoryPolitics: {
+		"election", "president", "presidential", "senate", "congress", "governor",
+		"democrat", "democrats", "republican", "republicans", "gop", "trump", "biden",
+		"harris", "primary", "nominee", "parliament", "prime minister", "vote", "politics",
+	},
+	CategorySports: {
+		"nfl", "nba", "mlb", "nhl", "ufc", "soccer", "football", "basketball", "baseball",
+		"tennis", "golf", "f1", "formula 1", "super bowl", "world cup", "champions league",
+		"premier league", "match", "playoffs", "championship", "sports", "olympics",
+	},
+	CategoryCrypto: {
+		"bitcoin", "btc", "ethereum", "eth", "solana", "sol", "crypto", "token", "airdrop",
+		"memecoin", "doge", "xrp", "etf", "stablecoin", "blockchain", "defi",
+	},
+	CategoryEconomics: {
+		"fed", "interest rate", "rates", "inflation", "cpi", "gdp", "recession",
+		"unemployment", "jobs report", "treasury", "economy", "tariff", "tariffs", "s&p",
+	},
+	CategoryTech: {
+		"ai", "openai", "gpt", "apple", "google", "microsoft", "nvidia", "tesla", "spacex",
+		"launch", "iphone", "tech", "science", "nasa",
+	},
+	CategoryEntertainment: {
+		"oscar", "oscars", "grammy", "grammys", "movie", "box office", "album", "netflix",
+		"taylor swift", "emmy", "celebrity", "pop culture", "culture", "tv", "music",
+	},
+	CategoryWorld: {
+		"war", "ukraine", "russia", "israel", "gaza", "china", "taiwan", "iran", "nato",
+		"ceasefire", "un", "geopolitics", "world",
+	},
+}
+
+// Classifier assigns a normalized category to a market from its tags and text
+type Classifier struct {
+	keywords map[string][]string
+}
+
+// NewClassifier creates a classifier, merging extra keywords from config
+func NewClassifier(extra map[string][]string) *Classifier {
+	keywords := make(map[string][]string, len(defaultKeywords))
+	for cat, words := range defaultKeywords {
+		keywords[cat] = append([]string(nil), words...)
+	}
+	for cat, words := range extra {
+		cat = strings.ToLower(cat)
+		for _, w := range words {
+			keywords[cat] = append(keywords[cat], strings.ToLower(w))
+		}
+	}
+	return &Classifier{keywords: keywords}
+}
+
+// Classify returns the best matching category for a catalog entry.
+// Matching tags weigh more than matching words in the question or title.
+func (c *Classifier) Classify(e *Entry) string {
+	scores := make(map[string]int)
+
+	for _, tag := range e.Tags {
+		slug := strings.ToLower(tag.Slug)
+		label := strings.ToLower(tag.Label)
+		for cat, words := range c.keywords {
+			if slug == cat || label == cat {
+				scores[cat] += 5
+				continue
+			}
+			for _, w := range words {
+				if slug == w || label == w {
+					scores[cat] += 3
+					break
+				}
+			}
+		}
+	}
+
+	text := " " + normalizeText(e.Question+" "+e.EventTitle) + " "
+	for cat, words := range c.keywords {
+		for _, w := range words {
+			if strings.Contains(text, " "+w+" ") {
+				scores[cat]++
+			}
+		}
+	}
+
+	return bestCategory(scores)
+}
+
+// Categories returns every category the classifier can assign, sorted
+func (c *Classifier) Categories() []string {
+	out := make([]string, 0, len(c.keywords)+1)
+	for cat := range c.keywords {
+		out = append(out, cat)
+	}
+	out = append(out, CategoryOther)
+	sort.Strings(out)
+	return out
+}
+
+// bestCategory picks the highest score, breaking ties alphabetically
+func bestCategory(scores map[string]int) string {
+	best, bestScore := CategoryOther, 0
+	for cat, score := range scores {
+		if score > bestScore || (score == bestScore && score > 0 && cat < best) {
+			best, bestScore = cat, score
+		}
+	}
+	return best
+}
+
+// normalizeText lowercases s and replaces punctuation with spaces so
+// keywords match on word boundaries
+func normalizeText(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '&' {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, s)
+}
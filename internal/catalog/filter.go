@@ -0,0 +1,129 @@
+package catalog
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/polygo/internal/models"
+)
+
+// Filter returns catalog markets matching params, sorted by params.Order
+// (24h volume, highest first, when unset). Paging fields are ignored.
+func (c *Catalog) Filter(params *models.MarketQueryParams) []*Entry {
+	var out []*Entry
+	for _, e := range c.Markets() {
+		if matches(e, params) {
+			out = append(out, e)
+		}
+	}
+	sortEntries(out, params.Order, params.Ascending)
+	return out
+}
+
+// matches reports whether a catalog entry passes every filter in params
+func matches(e *Entry, p *models.MarketQueryParams) bool {
+	if p.Active != nil && e.Active != *p.Active {
+		return false
+	}
+	if p.Closed != nil && e.Closed != *p.Closed {
+		return false
+	}
+	if p.Slug != "" && e.Slug != p.Slug {
+		return false
+	}
+	if p.EventSlug != "" && e.EventSlug != p.EventSlug {
+		return false
+	}
+	if p.ClobTokenID != "" && !hasToken(e.ClobTokenIDs, p.ClobTokenID) {
+		return false
+	}
+	if p.MinVolume > 0 && e.Volume.Float() < p.MinVolume {
+		return false
+	}
+	if p.MinLiquidity > 0 && e.Liquidity.Float() < p.MinLiquidity {
+		return false
+	}
+	if !p.EndDateBefore.IsZero() && (e.EndDate.IsZero() || !e.EndDate.Before(p.EndDateBefore)) {
+		return false
+	}
+	if !p.EndDateAfter.IsZero() && !e.EndDate.After(p.EndDateAfter) {
+		return false
+	}
+	return true
+}
+
+// hasToken reports whether ids contains tokenID
+func hasToken(ids models.StringList, tokenID string) bool {
+	for _, id := range ids {
+		if id == tokenID {
+			return true
+		}
+	}
+	return false
+}
+
+// sortKeys maps the order names Gamma accepts to a catalog sort key
+var sortKeys = map[string]func(e *Entry) float64{
+	"volume":       func(e *Entry) float64 { return e.Volume.Float() },
+	"volumenum":    func(e *Entry) float64 { return e.Volume.Float() },
+	"volume24hr":   func(e *Entry) float64 { return e.Volume24hr.Float() },
+	"liquidity":    func(e *Entry) float64 { return e.Liquidity.Float() },
+	"liquiditynum": func(e *Entry) float64 { return e.Liquidity.Float() },
+	"spread":       func(e *Entry) float64 { return e.Spread.Float() },
+	"enddate":      func(e *Entry) float64 { return float64(e.EndDate.Unix()) },
+}
+
+// ValidOrder reports whether Filter can sort by order (case and
+// underscores ignored, so end_date and endDate both work)
+func ValidOrder(order string) bool {
+	_, ok := sortKeys[orderKey(order)]
+	return order == "" || ok
+}
+
+// orderKey folds an order name to its sortKeys form
+func orderKey(order string) string {
+	return strings.ToLower(strings.ReplaceAll(order, "_", ""))
+}
+
+// sortEntries sorts entries by order, descending unless ascending is true.
+// Unknown orders fall back to 24h volume.
+func sortEntries(entries []*Entry, order string, ascending *bool) {
+	key, ok := sortKeys[orderKey(order)]
+	if !ok {
+		key = sortKeys["volume24hr"]
+	}
+	asc := ascending != nil && *ascending
+	sort.SliceStable(entries, func(i, j int) bool {
+		if asc {
+			return key(entries[i]) < key(entries[j])
+		}
+		return key(entries[i]) > key(entries[j])
+	})
+}
+
+// ClosingMarket is a catalog market with the time left before its end date
+type ClosingMarket struct {
+	*Entry
+	SecondsRemaining int64 `json:"secondsRemaining"`
+}
+
+// Closing returns open markets whose end date falls within the given
+// duration of now, soonest first
+func (c *Catalog) Closing(now time.Time, within time.Duration) []ClosingMarket {
+	var out []ClosingMarket
+	for _, e := range c.Markets() {
+		if e.Closed || e.EndDate.IsZero() || !e.EndDate.After(now) {
+			continue
+		}
+		left := e.EndDate.Sub(now)
+		if left > within {
+			continue
+		}
+		out = append(out, ClosingMarket{Entry: e, SecondsRemaining: int64(left / time.Second)})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].EndDate.Before(out[j].EndDate)
+	})
+	return out
+}
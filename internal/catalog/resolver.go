@@ -0,0 +1,233 @@
+package catalog
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+)
+
+// resolverMissTTL is how long a token Gamma does not know is not looked up again
+const resolverMissTTL = time.Minute
+
+// resolverMaxCached bounds the tokens resolved outside the catalog
+const resolverMaxCached = 10000
+
+// ErrUnknownToken is returned for a token ID no market lists
+var ErrUnknownToken = errors.New("unknown token")
+
+// TokenInfo is the human-readable market behind a CLOB token ID
+type TokenInfo struct {
+	TokenID      string  `json:"token_id"`
+	Outcome      string  `json:"outcome"`
+	OutcomeIndex int     `json:"outcome_index"`
+	MarketID     string  `json:"market_id"`
+	ConditionID  string  `json:"condition_id"`
+	Question     string  `json:"question"`
+	Slug         string  `json:"slug"`
+	EventSlug    string  `json:"event_slug,omitempty"`
+	MinOrderSize float64 `json:"min_order_size,omitempty"` // shares
+	TickSize     float64 `json:"tick_size,omitempty"`      // as listed by Gamma; the CLOB's is authoritative
+}
+
+// Resolver maps CLOB token IDs to their market and outcome. Tokens of
+// catalog markets resolve from memory; others (closed markets, markets
+// listed since the last sync) are looked up on Gamma once and remembered.
+type Resolver struct {
+	catalog *Catalog
+	gamma   *polymarket.GammaClient
+
+	mu       sync.Mutex
+	cached   map[string]*TokenInfo
+	misses   map[string]time.Time
+	inflight map[string]bool
+}
+
+// NewResolver creates a new token resolver
+func NewResolver(cat *Catalog, gamma *polymarket.GammaClient) *Resolver {
+	return &Resolver{
+		catalog:  cat,
+		gamma:    gamma,
+		cached:   make(map[string]*TokenInfo),
+		misses:   make(map[string]time.Time),
+		inflight: make(map[string]bool),
+	}
+}
+
+// Lookup resolves a token from memory only, never calling upstream
+func (r *Resolver) Lookup(tokenID string) (*TokenInfo, bool) {
+	if entry, i, ok := r.catalog.Token(tokenID); ok {
+		return tokenInfo(tokenID, &entry.Market, i, entry.EventSlug), true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	info, ok := r.cached[tokenID]
+	return info, ok
+}
+
+// Resolve resolves a token, asking Gamma when it is not known locally
+func (r *Resolver) Resolve(tokenID string) (*TokenInfo, error) {
+	if info, ok := r.Lookup(tokenID); ok {
+		return info, nil
+	}
+
+	r.mu.Lock()
+	missed, ok := r.misses[tokenID]
+	r.mu.Unlock()
+	if ok && time.Since(missed) < resolverMissTTL {
+		return nil, ErrUnknownToken
+	}
+
+	data, _, err := r.gamma.GetMarketByClobTokenID(tokenID)
+	if err != nil {
+		return nil, err
+	}
+	var markets []models.Market
+	if err := sonic.Unmarshal(data, &markets); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, m := range markets {
+		for i, id := range m.ClobTokenIDs {
+			if id == tokenID {
+				info := tokenInfo(tokenID, &m, i, "")
+				if len(r.cached) >= resolverMaxCached {
+					r.cached = make(map[string]*TokenInfo)
+				}
+				r.cached[tokenID] = info
+				delete(r.misses, tokenID)
+				return info, nil
+			}
+		}
+	}
+
+	if len(r.misses) >= resolverMaxCached {
+		r.misses = make(map[string]time.Time)
+	}
+	r.misses[tokenID] = time.Now()
+	return nil, ErrUnknownToken
+}
+
+// Warm resolves a token in the background so a later Lookup finds it.
+// Concurrent calls for the same token share one upstream request.
+func (r *Resolver) Warm(tokenID string) {
+	r.mu.Lock()
+	if r.inflight[tokenID] {
+		r.mu.Unlock()
+		return
+	}
+	r.inflight[tokenID] = true
+	r.mu.Unlock()
+
+	go func() {
+		r.Resolve(tokenID)
+
+		r.mu.Lock()
+		delete(r.inflight, tokenID)
+		r.mu.Unlock()
+	}()
+}
+
+// tokenInfo describes outcome i of a market
+func tokenInfo(tokenID string, m *models.Market, i int, eventSlug string) *TokenInfo {
+	info := &TokenInfo{
+		TokenID:      tokenID,
+		OutcomeIndex: i,
+		MarketID:     m.ID,
+		ConditionID:  m.ConditionID,
+		Question:     m.Question,
+		Slug:         m.Slug,
+		EventSlug:    eventSlug,
+		MinOrderSize: m.OrderMinSize,
+		TickSize:     m.OrderPriceMinTickSize,
+	}
+	if i < len(m.Outcomes) {
+		info.Outcome = m.Outcomes[i]
+	}
+	return info
+}
+
+// Enrich adds a "market_info" object describing asset_id to a WebSocket
+// message (or each message of a batch), and an "outcome" to each entry of
+// its price_changes. Messages with nothing to resolve are returned
+// unchanged; unknown tokens are resolved in the background for later
+// messages.
+func (r *Resolver) Enrich(data []byte) []byte {
+	var msg interface{}
+	if err := sonic.Unmarshal(data, &msg); err != nil {
+		return data
+	}
+
+	changed := false
+	switch m := msg.(type) {
+	case map[string]interface{}:
+		changed = r.enrichMessage(m)
+	case []interface{}:
+		for _, item := range m {
+			if obj, ok := item.(map[string]interface{}); ok && r.enrichMessage(obj) {
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return data
+	}
+
+	out, err := sonic.Marshal(msg)
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+// enrichMessage enriches one decoded message, reporting whether it changed
+func (r *Resolver) enrichMessage(msg map[string]interface{}) bool {
+	changed := false
+	if assetID, ok := msg["asset_id"].(string); ok {
+		if info := r.lookupOrWarm(assetID); info != nil {
+			msg["market_info"] = info
+			changed = true
+		}
+	}
+
+	changes, _ := msg["price_changes"].([]interface{})
+	for _, c := range changes {
+		change, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		assetID, _ := change["asset_id"].(string)
+		info := r.lookupOrWarm(assetID)
+		if info == nil {
+			continue
+		}
+		change["outcome"] = info.Outcome
+		if _, ok := msg["market_info"]; !ok {
+			// The market of the batch, without any one token's outcome
+			market := *info
+			market.TokenID, market.Outcome, market.OutcomeIndex = "", "", 0
+			msg["market_info"] = &market
+		}
+		changed = true
+	}
+	return changed
+}
+
+// lookupOrWarm returns a token's info if known, else starts resolving it
+func (r *Resolver) lookupOrWarm(tokenID string) *TokenInfo {
+	if tokenID == "" {
+		return nil
+	}
+	if info, ok := r.Lookup(tokenID); ok {
+		return info
+	}
+	r.Warm(tokenID)
+	return nil
+}
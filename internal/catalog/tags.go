@@ -0,0 +1,165 @@
+package catalog
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/polygo/internal/models"
+)
+
+// TagCount is a tag with the number of catalog events and markets using it
+type TagCount struct {
+	Slug    string `json:"slug"`
+	Label   string `json:"label"`
+	Events  int    `json:"events"`
+	Markets int    `json:"markets"`
+}
+
+// Tags returns every tag in the catalog, most used first
+func (c *Catalog) Tags() []TagCount {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	counts := make(map[string]*TagCount)
+	for _, event := range c.events {
+		for _, tag := range event.Tags {
+			slug := strings.ToLower(tag.Slug)
+			if slug == "" {
+				continue
+			}
+			tc, ok := counts[slug]
+			if !ok {
+				label := tag.Label
+				if label == "" {
+					label = tag.Name
+				}
+				tc = &TagCount{Slug: slug, Label: label}
+				counts[slug] = tc
+			}
+			tc.Events++
+			tc.Markets += len(event.Markets)
+		}
+	}
+
+	out := make([]TagCount, 0, len(counts))
+	for _, tc := range counts {
+		out = append(out, *tc)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Markets != out[j].Markets {
+			return out[i].Markets > out[j].Markets
+		}
+		return out[i].Slug < out[j].Slug
+	})
+	return out
+}
+
+// MarketsByTag returns catalog markets whose event carries the tag slug
+func (c *Catalog) MarketsByTag(slug string) []*Entry {
+	var out []*Entry
+	for _, e := range c.Markets() {
+		for _, t := range e.Tags {
+			if strings.EqualFold(t.Slug, slug) {
+				out = append(out, e)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// EventSummary is the part of an event a landing page lists
+type EventSummary struct {
+	ID        string    `json:"id"`
+	Slug      string    `json:"slug"`
+	Title     string    `json:"title"`
+	Image     string    `json:"image,omitempty"`
+	Volume    float64   `json:"volume"`
+	Liquidity float64   `json:"liquidity"`
+	Markets   int       `json:"markets"`
+	EndDate   time.Time `json:"end_date,omitempty"`
+}
+
+// TagGroup is a tag with its active events: how many, their total volume
+// and the top ones by volume
+type TagGroup struct {
+	Slug   string         `json:"slug"`
+	Label  string         `json:"label"`
+	Events int            `json:"events"`
+	Volume float64        `json:"volume"`
+	Top    []EventSummary `json:"top"`
+}
+
+// EventsByTag buckets the catalog's active events by tag, keeping the top
+// events of each bucket by volume. Buckets are ordered by total volume.
+func (c *Catalog) EventsByTag(top int) []TagGroup {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	groups := make(map[string]*TagGroup)
+	members := make(map[string][]*models.Event)
+	for _, event := range c.events {
+		if !event.Active || event.Closed {
+			continue
+		}
+		for _, tag := range event.Tags {
+			slug := strings.ToLower(tag.Slug)
+			if slug == "" {
+				continue
+			}
+			g, ok := groups[slug]
+			if !ok {
+				label := tag.Label
+				if label == "" {
+					label = tag.Name
+				}
+				g = &TagGroup{Slug: slug, Label: label}
+				groups[slug] = g
+			}
+			g.Events++
+			g.Volume += event.Volume.Float()
+			members[slug] = append(members[slug], event)
+		}
+	}
+
+	out := make([]TagGroup, 0, len(groups))
+	for slug, g := range groups {
+		events := members[slug]
+		sort.Slice(events, func(i, j int) bool {
+			if vi, vj := events[i].Volume.Float(), events[j].Volume.Float(); vi != vj {
+				return vi > vj
+			}
+			return events[i].ID < events[j].ID
+		})
+		if top >= 0 && len(events) > top {
+			events = events[:top]
+		}
+		g.Top = make([]EventSummary, len(events))
+		for i, e := range events {
+			g.Top[i] = summarize(e)
+		}
+		out = append(out, *g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Volume != out[j].Volume {
+			return out[i].Volume > out[j].Volume
+		}
+		return out[i].Slug < out[j].Slug
+	})
+	return out
+}
+
+// summarize reduces an event to its landing page summary
+func summarize(e *models.Event) EventSummary {
+	return EventSummary{
+		ID:        e.ID,
+		Slug:      e.Slug,
+		Title:     e.Title,
+		Image:     e.Image,
+		Volume:    e.Volume.Float(),
+		Liquidity: e.Liquidity.Float(),
+		Markets:   len(e.Markets),
+		EndDate:   e.EndDate,
+	}
+}
@@ -0,0 +1,151 @@
+// Package chain reads Polygon transactions over JSON-RPC to verify that
+// trades settled on-chain
+package chain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/valyala/fasthttp"
+)
+
+// ErrNoRPC is returned when no RPC endpoint is configured
+var ErrNoRPC = errors.New("no Polygon RPC endpoint is configured")
+
+// Receipt is the subset of a transaction receipt needed for settlement
+type Receipt struct {
+	Status      string `json:"status"`
+	BlockNumber string `json:"blockNumber"`
+	Logs        []Log  `json:"logs"`
+}
+
+// Log is an event emitted by a transaction
+type Log struct {
+	Address string   `json:"address"`
+	Topics  []string `json:"topics"`
+	Data    string   `json:"data"`
+}
+
+// rpcRequest is a JSON-RPC 2.0 call
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// rpcResponse is a JSON-RPC 2.0 reply
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// rpcError is a JSON-RPC 2.0 error object
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Client is a minimal Ethereum JSON-RPC client
+type Client struct {
+	http   *fasthttp.Client
+	config *config.ChainConfig
+	nextID atomic.Uint64
+}
+
+// NewClient creates a new RPC client
+func NewClient(cfg *config.ChainConfig) *Client {
+	return &Client{
+		http: &fasthttp.Client{
+			Name:                     "PolyGo/1.0",
+			ReadTimeout:              cfg.Timeout,
+			WriteTimeout:             cfg.Timeout,
+			NoDefaultUserAgentHeader: true,
+		},
+		config: cfg,
+	}
+}
+
+// BlockNumber returns the latest block number
+func (c *Client) BlockNumber() (uint64, error) {
+	var hex string
+	if err := c.call("eth_blockNumber", nil, &hex); err != nil {
+		return 0, err
+	}
+	return parseQuantity(hex)
+}
+
+// TransactionReceipt returns a transaction's receipt, or nil if it has
+// not been mined
+func (c *Client) TransactionReceipt(hash string) (*Receipt, error) {
+	var receipt *Receipt
+	if err := c.call("eth_getTransactionReceipt", []interface{}{hash}, &receipt); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// TransactionKnown reports whether the node knows the transaction, mined
+// or pending
+func (c *Client) TransactionKnown(hash string) (bool, error) {
+	var tx map[string]interface{}
+	if err := c.call("eth_getTransactionByHash", []interface{}{hash}, &tx); err != nil {
+		return false, err
+	}
+	return tx != nil, nil
+}
+
+// call performs one JSON-RPC call, decoding its result into result
+func (c *Client) call(method string, params []interface{}, result interface{}) error {
+	if c.config.RPCURL == "" {
+		return ErrNoRPC
+	}
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	body, err := sonic.Marshal(rpcRequest{JSONRPC: "2.0", ID: c.nextID.Add(1), Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(c.config.RPCURL)
+	req.Header.SetMethod(fasthttp.MethodPost)
+	req.Header.SetContentType("application/json")
+	req.SetBody(body)
+
+	if err := c.http.DoTimeout(req, resp, c.config.Timeout); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	if status := resp.StatusCode(); status != fasthttp.StatusOK {
+		return fmt.Errorf("%s: RPC returned status %d", method, status)
+	}
+
+	var reply rpcResponse
+	if err := sonic.Unmarshal(resp.Body(), &reply); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	if reply.Error != nil {
+		return fmt.Errorf("%s: %s (code %d)", method, reply.Error.Message, reply.Error.Code)
+	}
+	if len(reply.Result) == 0 {
+		return fmt.Errorf("%s: RPC returned no result", method)
+	}
+	return sonic.Unmarshal(reply.Result, result)
+}
+
+// parseQuantity decodes a hex-encoded JSON-RPC quantity
+func parseQuantity(hex string) (uint64, error) {
+	return strconv.ParseUint(strings.TrimPrefix(hex, "0x"), 16, 64)
+}
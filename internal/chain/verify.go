@@ -0,0 +1,344 @@
+package chain
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+)
+
+// Event signatures decoded from receipt logs
+const (
+	// ERC-20 Transfer(address,address,uint256): USDC moving between parties
+	topicTransfer = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	// ERC-1155 TransferSingle(address,address,address,uint256,uint256): outcome shares
+	topicTransferSingle = "0xc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f62"
+	// ERC-1155 TransferBatch(address,address,address,uint256[],uint256[])
+	topicTransferBatch = "0x4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb"
+)
+
+// Settlement statuses
+const (
+	StatusConfirmed   = "confirmed"   // succeeded, at least Confirmations blocks deep
+	StatusUnconfirmed = "unconfirmed" // succeeded, not yet Confirmations blocks deep
+	StatusPending     = "pending"     // known to the node but not mined
+	StatusFailed      = "failed"      // mined but reverted
+	StatusNotFound    = "not_found"   // unknown to the node
+)
+
+// headTTL is how long the latest block number is reused; Polygon produces
+// a block about every two seconds
+const headTTL = 2 * time.Second
+
+// settledTTL is how long final results are cached
+const settledTTL = 24 * time.Hour
+
+// unitScale converts base units to whole units: USDC and Polymarket's
+// conditional tokens both have 6 decimals
+var unitScale = big.NewFloat(1e6)
+
+// ErrInvalidHash is returned for anything but a 0x-prefixed 32-byte hex hash
+var ErrInvalidHash = errors.New("transaction hash must be 0x followed by 64 hex characters")
+
+var hashPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+
+// api keeps numbers exact when re-encoding upstream payloads
+var api = sonic.Config{UseNumber: true}.Froze()
+
+// Transfer is a token movement decoded from a transaction's logs
+type Transfer struct {
+	Standard string  `json:"standard"` // erc20 or erc1155
+	Contract string  `json:"contract"`
+	From     string  `json:"from"`
+	To       string  `json:"to"`
+	TokenID  string  `json:"token_id,omitempty"` // erc1155 position ID
+	Amount   string  `json:"amount"`             // base units
+	Value    float64 `json:"value"`              // whole units, assuming 6 decimals
+}
+
+// Settlement is a transaction's on-chain status
+type Settlement struct {
+	Hash          string     `json:"hash"`
+	Status        string     `json:"status"`
+	Confirmed     bool       `json:"confirmed"`
+	BlockNumber   uint64     `json:"block_number,omitempty"`
+	Confirmations uint64     `json:"confirmations"`
+	Transfers     []Transfer `json:"transfers"`
+}
+
+// Verifier checks trade transactions against the chain. Results that can
+// no longer change (confirmed or failed, Confirmations deep) are cached.
+type Verifier struct {
+	client *Client
+	cache  *cache.Cache
+	config *config.ChainConfig
+
+	mu     sync.Mutex
+	head   uint64
+	headAt time.Time
+}
+
+// NewVerifier creates a new settlement verifier
+func NewVerifier(client *Client, c *cache.Cache, cfg *config.ChainConfig) *Verifier {
+	return &Verifier{client: client, cache: c, config: cfg}
+}
+
+// Verify looks up a transaction's receipt and decodes its transfers
+func (v *Verifier) Verify(hash string) (*Settlement, error) {
+	if !hashPattern.MatchString(hash) {
+		return nil, ErrInvalidHash
+	}
+	hash = strings.ToLower(hash)
+	key := cache.SettlementKey(hash)
+
+	var settled Settlement
+	if v.cache != nil && v.cache.GetJSON(key, &settled) {
+		if head, err := v.latestBlock(); err == nil && head >= settled.BlockNumber {
+			settled.Confirmations = head - settled.BlockNumber + 1
+		}
+		return &settled, nil
+	}
+
+	receipt, err := v.client.TransactionReceipt(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Settlement{Hash: hash, Transfers: []Transfer{}}
+	if receipt == nil {
+		known, err := v.client.TransactionKnown(hash)
+		if err != nil {
+			return nil, err
+		}
+		s.Status = StatusNotFound
+		if known {
+			s.Status = StatusPending
+		}
+		return s, nil
+	}
+
+	if s.BlockNumber, err = parseQuantity(receipt.BlockNumber); err != nil {
+		return nil, err
+	}
+	head, err := v.latestBlock()
+	if err != nil {
+		return nil, err
+	}
+	if head >= s.BlockNumber {
+		s.Confirmations = head - s.BlockNumber + 1
+	}
+	s.Transfers = decodeTransfers(receipt.Logs)
+
+	deep := s.Confirmations >= v.config.Confirmations
+	switch {
+	case receipt.Status == "0x0":
+		s.Status = StatusFailed
+	case deep:
+		s.Status = StatusConfirmed
+		s.Confirmed = true
+	default:
+		s.Status = StatusUnconfirmed
+	}
+
+	if deep && v.cache != nil {
+		v.cache.SetJSON(key, s, settledTTL)
+	}
+	return s, nil
+}
+
+// latestBlock returns the chain head, refreshed at most every headTTL
+func (v *Verifier) latestBlock() (uint64, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if !v.headAt.IsZero() && time.Since(v.headAt) < headTTL {
+		return v.head, nil
+	}
+	head, err := v.client.BlockNumber()
+	if err != nil {
+		return 0, err
+	}
+	v.head, v.headAt = head, time.Now()
+	return head, nil
+}
+
+// Annotate adds a "confirmed" flag to each trade in a JSON list, or
+// {data: [...]} page, of trades that carries a transaction hash. Up to
+// MaxVerify distinct hashes are looked up, Concurrency at a time; trades
+// beyond that or whose status could not be read are left unannotated.
+func (v *Verifier) Annotate(data []byte) ([]byte, error) {
+	var body interface{}
+	if err := api.Unmarshal(data, &body); err != nil {
+		return nil, err
+	}
+
+	trades, ok := body.([]interface{})
+	if page, isPage := body.(map[string]interface{}); isPage {
+		trades, ok = page["data"].([]interface{})
+	}
+	if !ok {
+		return data, nil
+	}
+
+	var hashes []string
+	seen := make(map[string]bool)
+	for _, t := range trades {
+		hash := tradeHash(t)
+		if hash != "" && !seen[hash] && len(hashes) < v.config.MaxVerify {
+			seen[hash] = true
+			hashes = append(hashes, hash)
+		}
+	}
+
+	confirmed := v.confirmed(hashes)
+	for _, t := range trades {
+		trade, _ := t.(map[string]interface{})
+		if c, ok := confirmed[tradeHash(t)]; ok {
+			trade["confirmed"] = c
+		}
+	}
+	return api.Marshal(body)
+}
+
+// confirmed verifies hashes concurrently, omitting those that failed
+func (v *Verifier) confirmed(hashes []string) map[string]bool {
+	out := make(map[string]bool, len(hashes))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(v.config.Concurrency, 1))
+	for _, hash := range hashes {
+		wg.Add(1)
+		go func(hash string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			s, err := v.Verify(hash)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			out[hash] = s.Confirmed
+			mu.Unlock()
+		}(hash)
+	}
+	wg.Wait()
+	return out
+}
+
+// tradeHash reads a trade's transaction hash (Data API or CLOB naming)
+func tradeHash(t interface{}) string {
+	trade, ok := t.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	for _, field := range []string{"transactionHash", "transaction_hash"} {
+		if hash, ok := trade[field].(string); ok && hashPattern.MatchString(hash) {
+			return hash
+		}
+	}
+	return ""
+}
+
+// decodeTransfers extracts ERC-20 and ERC-1155 transfers from logs
+func decodeTransfers(logs []Log) []Transfer {
+	transfers := []Transfer{}
+	for _, l := range logs {
+		if len(l.Topics) == 0 {
+			continue
+		}
+		data := hexBytes(l.Data)
+		contract := strings.ToLower(l.Address)
+
+		switch strings.ToLower(l.Topics[0]) {
+		case topicTransfer:
+			// ERC-721 transfers index the token ID too and are skipped
+			if len(l.Topics) != 3 || len(data) < 32 {
+				continue
+			}
+			transfers = append(transfers, newTransfer("erc20", contract, l.Topics[1], l.Topics[2], nil, word(data, 0)))
+		case topicTransferSingle:
+			if len(l.Topics) != 4 || len(data) < 64 {
+				continue
+			}
+			transfers = append(transfers, newTransfer("erc1155", contract, l.Topics[2], l.Topics[3], word(data, 0), word(data, 1)))
+		case topicTransferBatch:
+			if len(l.Topics) != 4 {
+				continue
+			}
+			ids, amounts := uintArray(data, 0), uintArray(data, 1)
+			for i := 0; i < len(ids) && i < len(amounts); i++ {
+				transfers = append(transfers, newTransfer("erc1155", contract, l.Topics[2], l.Topics[3], ids[i], amounts[i]))
+			}
+		}
+	}
+	return transfers
+}
+
+func newTransfer(standard, contract, from, to string, id, amount *big.Int) Transfer {
+	value, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), unitScale).Float64()
+	t := Transfer{
+		Standard: standard,
+		Contract: contract,
+		From:     topicAddress(from),
+		To:       topicAddress(to),
+		Amount:   amount.String(),
+		Value:    value,
+	}
+	if id != nil {
+		t.TokenID = id.String()
+	}
+	return t
+}
+
+// topicAddress reads an address from an indexed 32-byte topic
+func topicAddress(topic string) string {
+	topic = strings.ToLower(strings.TrimPrefix(topic, "0x"))
+	if len(topic) < 40 {
+		return ""
+	}
+	return "0x" + topic[len(topic)-40:]
+}
+
+// hexBytes decodes 0x-prefixed hex, returning nil when invalid
+func hexBytes(s string) []byte {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// word returns the i-th 32-byte ABI word as an unsigned integer
+func word(data []byte, i int) *big.Int {
+	if len(data) < (i+1)*32 {
+		return new(big.Int)
+	}
+	return new(big.Int).SetBytes(data[i*32 : (i+1)*32])
+}
+
+// uintArray decodes the dynamic uint256[] whose offset is the i-th word
+func uintArray(data []byte, i int) []*big.Int {
+	offset := word(data, i)
+	if !offset.IsInt64() || offset.Int64()%32 != 0 {
+		return nil
+	}
+	start := int(offset.Int64()) / 32
+	length := word(data, start)
+	if !length.IsInt64() || len(data) < (start+1+int(length.Int64()))*32 {
+		return nil
+	}
+
+	out := make([]*big.Int, length.Int64())
+	for j := range out {
+		out[j] = word(data, start+1+j)
+	}
+	return out
+}
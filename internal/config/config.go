@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -12,6 +13,46 @@ type Config struct {
 	Polymarket PolymarketConfig `mapstructure:"polymarket"`
 	Cache      CacheConfig      `mapstructure:"cache"`
 	Auth       AuthConfig       `mapstructure:"auth"`
+	RateLimit  RateLimitConfig  `mapstructure:"rate_limit"`
+	Concurrency ConcurrencyConfig `mapstructure:"concurrency"`
+	Tenants    TenantsConfig    `mapstructure:"tenants"`
+	Health     HealthConfig     `mapstructure:"health"`
+	Catalog    CatalogConfig    `mapstructure:"catalog"`
+	Webhooks   WebhooksConfig   `mapstructure:"webhooks"`
+	EventBus   EventBusConfig   `mapstructure:"eventbus"`
+	Rules      RulesConfig      `mapstructure:"rules"`
+	Routing    RoutingConfig    `mapstructure:"routing"`
+	Watchlist  WatchlistConfig  `mapstructure:"watchlist"`
+	PositionAlerts PositionAlertsConfig `mapstructure:"position_alerts"`
+	Equity     EquityConfig     `mapstructure:"equity"`
+	TaxReport  TaxReportConfig  `mapstructure:"tax_report"`
+	Strategies StrategiesConfig `mapstructure:"strategies"`
+	Recorder   RecorderConfig   `mapstructure:"recorder"`
+	Leaderboard LeaderboardConfig `mapstructure:"leaderboard"`
+	CopyTrade  CopyTradeConfig  `mapstructure:"copytrade"`
+	Risk       RiskConfig       `mapstructure:"risk"`
+	OrderExpiry OrderExpiryConfig `mapstructure:"order_expiry"`
+	FillNotify FillNotifyConfig `mapstructure:"fill_notify"`
+	DeadMan    DeadManConfig    `mapstructure:"dead_man"`
+	OrderRules OrderRulesConfig `mapstructure:"order_rules"`
+	Throttle   ThrottleConfig   `mapstructure:"throttle"`
+	Chain      ChainConfig      `mapstructure:"chain"`
+	Analytics  AnalyticsConfig  `mapstructure:"analytics"`
+	Digest     DigestConfig     `mapstructure:"digest"`
+	Export     ExportConfig     `mapstructure:"export"`
+	Snapshot   SnapshotConfig   `mapstructure:"snapshot"`
+	Prices     PricesConfig     `mapstructure:"prices"`
+	Ticker     TickerConfig     `mapstructure:"ticker"`
+	RawProxy   RawProxyConfig   `mapstructure:"raw_proxy"`
+	Replication ReplicationConfig `mapstructure:"replication"`
+	Tape       TapeConfig       `mapstructure:"tape"`
+	Storage    StorageConfig    `mapstructure:"storage"`
+	Secrets    SecretsConfig    `mapstructure:"secrets"`
+	Admin      AdminConfig      `mapstructure:"admin"`
+	Access     AccessConfig     `mapstructure:"access"`
+	Audit      AuditConfig      `mapstructure:"audit"`
+	ErrorReporting ErrorReportingConfig `mapstructure:"error_reporting"`
+	Docs       DocsConfig       `mapstructure:"docs"`
 }
 
 // ServerConfig holds server configuration
@@ -23,6 +64,48 @@ type ServerConfig struct {
 	IdleTimeout  time.Duration `mapstructure:"idle_timeout"`
 	Prefork      bool          `mapstructure:"prefork"`
 	Debug        bool          `mapstructure:"debug"`
+
+	// Listeners used instead of Host:Port when set: a comma-separated list of
+	// unix:///path/to.sock, tcp://host:port and systemd (socket activation)
+	Listen     string `mapstructure:"listen"`
+	SocketMode string `mapstructure:"socket_mode"` // octal permissions of unix sockets
+
+	// Reverse proxies trusted to report the client IP in ProxyHeader: IPs,
+	// CIDRs, or unix for connections over a unix socket. Empty trusts none.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+	ProxyHeader    string   `mapstructure:"proxy_header"` // X-Forwarded-For or X-Real-IP
+
+	// Shutdown/drain behavior
+	DrainTimeout    time.Duration `mapstructure:"drain_timeout"`    // max wait for in-flight orders
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"` // max wait for open connections
+	ReconnectHint   time.Duration `mapstructure:"reconnect_hint"`   // delay suggested to WS clients
+
+	// Requests slower than this are logged with their upstream/proxy split (0 disables)
+	SlowRequestThreshold time.Duration `mapstructure:"slow_request_threshold"`
+
+	// Order book deltas sent to WS clients that opt in with ?books=delta
+	BookSnapshotEvery int `mapstructure:"book_snapshot_every"` // full snapshot after this many deltas per token
+
+	// Bytes of frames queued for slow WS clients; a client over its own
+	// limit, or the ones furthest behind once the total is reached, are
+	// disconnected (0 = unlimited)
+	WSBufferLimit       int `mapstructure:"ws_buffer_limit"`        // all clients together
+	WSClientBufferLimit int `mapstructure:"ws_client_buffer_limit"` // one client
+	// How long market WS messages are kept for clients reconnecting with
+	// ?since= (0 disables replay and stream_seq)
+	WSReplayWindow time.Duration `mapstructure:"ws_replay_window"`
+	// Goroutines expected at most; /stats flags a count above it, which
+	// usually means a leak (0 = unchecked)
+	GoroutineBudget int `mapstructure:"goroutine_budget"`
+
+	// CORS
+	CORSOrigins          string `mapstructure:"cors_origins"`
+	CORSAllowCredentials bool   `mapstructure:"cors_allow_credentials"`
+
+	// Request body size limits in bytes
+	BodyLimit      int `mapstructure:"body_limit"`       // any request, including raw proxy writes
+	OrderBodyLimit int `mapstructure:"order_body_limit"` // order placement and cancellation
+	JSONBodyLimit  int `mapstructure:"json_body_limit"`  // other JSON endpoints (webhooks, watchlist, copy trading, admin)
 }
 
 // PolymarketConfig holds Polymarket API configuration
@@ -30,14 +113,68 @@ type PolymarketConfig struct {
 	ClobBaseURL      string        `mapstructure:"clob_base_url"`
 	GammaBaseURL     string        `mapstructure:"gamma_base_url"`
 	DataBaseURL      string        `mapstructure:"data_base_url"`
+	// Mirrors are backup base URLs of each API, in order of preference.
+	// Requests move to the first healthy one while the base URL is down
+	// and come back once it recovers.
+	ClobMirrors           []string      `mapstructure:"clob_mirrors"`
+	GammaMirrors          []string      `mapstructure:"gamma_mirrors"`
+	DataMirrors           []string      `mapstructure:"data_mirrors"`
+	FailoverCheckInterval time.Duration `mapstructure:"failover_check_interval"` // health checks of base and mirror URLs, on the health probe paths
+	FailoverThreshold     int           `mapstructure:"failover_threshold"`      // consecutive failed checks or requests marking a URL down, successful checks marking it up
 	WsClobURL        string        `mapstructure:"ws_clob_url"`
 	WsLiveDataURL    string        `mapstructure:"ws_live_data_url"`
-	MaxConnsPerHost  int           `mapstructure:"max_conns_per_host"`
+	WsUserURL        string        `mapstructure:"ws_user_url"` // CLOB user channel, for fill notifications; empty disables
+	WsShards         int           `mapstructure:"ws_shards"` // upstream CLOB WebSocket connections
+	WsPingInterval   time.Duration `mapstructure:"ws_ping_interval"` // 0 disables pings
+	WsPongTimeout    time.Duration `mapstructure:"ws_pong_timeout"`  // reconnect when a ping goes unanswered this long
+	WsStaleTimeout   time.Duration `mapstructure:"ws_stale_timeout"` // reconnect a subscribed shard silent this long; 0 disables
+	MaxConnsPerHost  int           `mapstructure:"max_conns_per_host"` // tunable at runtime under /admin/upstream/pool
+	MaxConnWait      time.Duration `mapstructure:"max_conn_wait"`      // a request's wait for a free connection at MaxConnsPerHost; 0 fails at once
 	ReadTimeout      time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout     time.Duration `mapstructure:"write_timeout"`
 	MaxIdleConnDur   time.Duration `mapstructure:"max_idle_conn_dur"`
+	DialTimeout      time.Duration `mapstructure:"dial_timeout"` // connecting to an upstream, proxy handshake included
+	// Upstream host names are resolved through a cache, and a host's IPv4
+	// and IPv6 addresses raced (happy eyeballs) when connecting
+	DNSCacheTTL        time.Duration `mapstructure:"dns_cache_ttl"`        // 0 resolves on every connection; expired entries are refreshed in the background
+	HappyEyeballsDelay time.Duration `mapstructure:"happy_eyeballs_delay"` // head start of each address over the next; 0 tries them one by one
+	WarmupConns        int           `mapstructure:"warmup_conns"`         // connections opened to each upstream API at startup; 0 disables
+	// Timeouts overrides ReadTimeout for upstream paths starting with a
+	// prefix (e.g. "/prices-history": 15s); the longest matching prefix wins
+	Timeouts         map[string]time.Duration `mapstructure:"timeouts"`
 	RetryCount       int           `mapstructure:"retry_count"`
-	RetryWaitTime    time.Duration `mapstructure:"retry_wait_time"`
+	RetryWaitTime    time.Duration `mapstructure:"retry_wait_time"`    // first backoff; doubles per retry
+	RetryMaxWait     time.Duration `mapstructure:"retry_max_wait"`     // backoff cap and longest Retry-After honored
+	RetryMethods     []string      `mapstructure:"retry_methods"`      // methods safe to retry; POST is never retried by default
+	RetryBudgetRatio float64       `mapstructure:"retry_budget_ratio"` // retries allowed per request, long-run
+	RetryBudgetBurst int           `mapstructure:"retry_budget_burst"` // retries allowed in a burst; 0 disables the budget
+
+	// Client-side rate limit per upstream host; excess requests queue
+	UpstreamRPS       float64       `mapstructure:"upstream_rps"`        // 0 disables; halved on 429 and recovered gradually
+	UpstreamBurst     int           `mapstructure:"upstream_burst"`
+	UpstreamQueueSize int           `mapstructure:"upstream_queue_size"` // requests allowed to wait for a token
+	UpstreamQueueWait time.Duration `mapstructure:"upstream_queue_wait"` // longest a request may wait for a token
+
+	// Validation checks upstream payloads against the models: off, warn
+	// (log and count drift) or strict (also fail payloads missing expected
+	// fields or with mismatched types)
+	Validation string `mapstructure:"validation"`
+
+	// ExtraHeaders are sent with every upstream HTTP and WebSocket request
+	ExtraHeaders map[string]string `mapstructure:"extra_headers"`
+	// Proxy carries upstream HTTP and WebSocket connections through an
+	// http:// or socks5:// (socks5h:// to resolve on the proxy) proxy,
+	// with user:password@ for credentials; empty connects directly
+	Proxy string `mapstructure:"proxy"`
+	// Proxies overrides Proxy per upstream host (e.g. "clob.polymarket.com"),
+	// "direct" bypassing it
+	Proxies map[string]string `mapstructure:"proxies"`
+	// HonorCacheHeaders uses upstream-provided TTLs (X-PolyGo-TTL-Ms) when caching
+	HonorCacheHeaders bool `mapstructure:"honor_cache_headers"`
+	// StreamThreshold is the body size in bytes above which responses
+	// relayed as is (raw proxy, v1 price history) are streamed to the
+	// client instead of buffered and cached; 0 always buffers
+	StreamThreshold int `mapstructure:"stream_threshold"`
 }
 
 // CacheConfig holds cache configuration
@@ -50,6 +187,21 @@ type CacheConfig struct {
 	PricesTTL      time.Duration `mapstructure:"prices_ttl"`
 	OrderBookTTL   time.Duration `mapstructure:"order_book_ttl"`
 	DefaultTTL     time.Duration `mapstructure:"default_ttl"`
+	UserDataTTL    time.Duration `mapstructure:"user_data_ttl"` // positions, user trades, activity
+	CompositeTTL   time.Duration `mapstructure:"composite_ttl"` // composite responses such as /markets/:id/full
+
+	// Size guards
+	MaxEntrySize      int64  `mapstructure:"max_entry_size"`     // entries above this are not cached (0 = unlimited)
+	Compression       string `mapstructure:"compression"`        // none, snappy or zstd
+	CompressThreshold int    `mapstructure:"compress_threshold"` // minimum payload size to compress
+
+	// Stampede protection
+	TTLJitter float64       `mapstructure:"ttl_jitter"` // TTLs vary by up to this share either way, so keys cached together expire apart (0 disables)
+	StaleFor  time.Duration `mapstructure:"stale_for"`  // entries past their TTL are served this long while one caller refreshes them (0 disables)
+
+	// Persistence across restarts
+	PersistPath     string   `mapstructure:"persist_path"`     // file the entries under PersistPrefixes are saved to on shutdown and loaded from on startup (empty disables)
+	PersistPrefixes []string `mapstructure:"persist_prefixes"` // key prefixes of long-lived entries worth keeping
 }
 
 // AuthConfig holds authentication configuration
@@ -61,6 +213,552 @@ type AuthConfig struct {
 	TimestampHeader  string `mapstructure:"timestamp_header"`
 }
 
+// HealthConfig holds upstream health probe configuration
+type HealthConfig struct {
+	ProbeInterval      time.Duration `mapstructure:"probe_interval"`      // how long probe results are cached
+	ProbeTimeout       time.Duration `mapstructure:"probe_timeout"`       // per-probe timeout
+	UnhealthyThreshold time.Duration `mapstructure:"unhealthy_threshold"` // unreachable time before /ready fails
+	ClobProbePath      string        `mapstructure:"clob_probe_path"`
+	GammaProbePath     string        `mapstructure:"gamma_probe_path"`
+	DataProbePath      string        `mapstructure:"data_probe_path"`
+}
+
+// CatalogConfig holds local market catalog sync configuration
+type CatalogConfig struct {
+	Enabled      bool                `mapstructure:"enabled"`
+	SyncInterval time.Duration       `mapstructure:"sync_interval"`
+	PageSize     int                 `mapstructure:"page_size"`
+	MaxEvents    int                 `mapstructure:"max_events"`
+	Categories   map[string][]string `mapstructure:"categories"` // extra classifier keywords per category
+}
+
+// RecorderConfig holds configuration for the local price recorder that
+// backs computed analytics such as top movers
+type RecorderConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	SampleInterval time.Duration `mapstructure:"sample_interval"` // how often prices are sampled
+	Resolution     time.Duration `mapstructure:"resolution"`      // candle width
+	Retention      time.Duration `mapstructure:"retention"`       // how much history is kept
+	MaxMarkets     int           `mapstructure:"max_markets"`     // top markets by 24h volume to record
+	Path           string        `mapstructure:"path"`            // storage key candles are saved to (empty = memory only)
+	SaveInterval   time.Duration `mapstructure:"save_interval"`   // how often candles are saved
+}
+
+// LeaderboardConfig holds configuration for leaderboard history snapshots
+type LeaderboardConfig struct {
+	Enabled   bool          `mapstructure:"enabled"`
+	Interval  time.Duration `mapstructure:"interval"`  // how often the leaderboard is snapshotted
+	Size      int           `mapstructure:"size"`      // traders kept per snapshot
+	Retention time.Duration `mapstructure:"retention"` // how long snapshots are kept
+	Path      string        `mapstructure:"path"`      // file history is saved to (empty = memory only)
+}
+
+// CopyTradeConfig holds configuration for the copy-trading follower
+// engine. Mirrored orders are placed for the local account below.
+type CopyTradeConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	Interval         time.Duration `mapstructure:"interval"`           // how often the target wallet's trades are polled
+	TradesLimit      int           `mapstructure:"trades_limit"`       // recent trades fetched per poll
+	Scale            float64       `mapstructure:"scale"`              // default size multiplier applied to the target's trades
+	MaxOrderNotional float64       `mapstructure:"max_order_notional"` // USDC per mirrored order; larger orders are scaled down
+	MaxExposure      float64       `mapstructure:"max_exposure"`       // net USDC bought per session; orders beyond it are skipped
+	Address          string        `mapstructure:"address"`            // local account's maker wallet
+	APIKey           string        `mapstructure:"api_key"`            // local account's L2 credentials
+	Secret           string        `mapstructure:"secret"`
+	Passphrase       string        `mapstructure:"passphrase"`
+}
+
+// RiskConfig holds configuration for pre-trade risk checks on order
+// placement. Accounts are API keys; an account's limits replace the
+// default ones entirely.
+type RiskConfig struct {
+	Enabled  bool                  `mapstructure:"enabled"`
+	Default  RiskLimits            `mapstructure:"default"`
+	Accounts map[string]RiskLimits `mapstructure:"accounts"`
+	Path     string                `mapstructure:"path"` // file admin API changes are saved to (empty = memory only)
+}
+
+// RiskLimits are one account's pre-trade limits. Zero disables a limit.
+type RiskLimits struct {
+	MaxOrderSize       float64  `mapstructure:"max_order_size" json:"max_order_size"`               // shares per order
+	MaxOpenNotional    float64  `mapstructure:"max_open_notional" json:"max_open_notional"`         // USDC across open orders, including new ones
+	MaxOrdersPerMinute int      `mapstructure:"max_orders_per_minute" json:"max_orders_per_minute"` // orders accepted per rolling minute
+	BannedMarkets      []string `mapstructure:"banned_markets" json:"banned_markets"`               // token IDs, market IDs, condition IDs or slugs
+}
+
+// OrderExpiryConfig holds configuration for GTD order expiration checks
+// and proactive cancellation
+type OrderExpiryConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	MinLifetime  time.Duration `mapstructure:"min_lifetime"`  // GTD expirations closer than this are rejected
+	CancelBuffer time.Duration `mapstructure:"cancel_buffer"` // tracked orders are cancelled this long before expiry
+	Interval     time.Duration `mapstructure:"interval"`      // how often tracked expirations are checked
+	MaxOrders    int           `mapstructure:"max_orders"`    // GTD orders tracked across all callers
+}
+
+// FillNotifyConfig holds configuration for fill notifications of orders
+// placed through PolyGo, read from the CLOB user channel
+type FillNotifyConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	IdleTimeout time.Duration `mapstructure:"idle_timeout"` // a caller's user channel is closed this long after its last watched order is done
+	MaxOrders   int           `mapstructure:"max_orders"`   // resting orders watched across all callers
+}
+
+// DeadManConfig holds configuration for the dead-man's switch, which
+// cancels a client's open orders when its /ws/events connection drops or
+// stops sending heartbeats
+type DeadManConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	MinTimeout time.Duration `mapstructure:"min_timeout"` // shortest heartbeat timeout a client may arm
+	MaxTimeout time.Duration `mapstructure:"max_timeout"` // longest heartbeat timeout a client may arm
+}
+
+// OrderRulesConfig holds configuration for the tick size, minimum size and
+// price bound checks run on orders before they are sent upstream
+type OrderRulesConfig struct {
+	Enabled      bool    `mapstructure:"enabled"`
+	MinSize      float64 `mapstructure:"min_size"`      // shares, for markets that publish no minimum (0 = none)
+	SizeDecimals int     `mapstructure:"size_decimals"` // decimals allowed in order sizes (negative = any)
+}
+
+// ThrottleConfig holds configuration for the guard against runaway order
+// loops: repeated identical orders and bursts of orders into one market
+type ThrottleConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	DuplicateWindow time.Duration `mapstructure:"duplicate_window"` // an order identical to one the key sent this recently is rejected (0 = allowed)
+	MarketRate      int           `mapstructure:"market_rate"`      // orders a key may send to one market per MarketWindow (0 = unlimited)
+	MarketWindow    time.Duration `mapstructure:"market_window"`    // rolling window MarketRate is counted over
+}
+
+// ChainConfig holds the Polygon JSON-RPC endpoint used to verify that
+// trades settled on-chain
+type ChainConfig struct {
+	RPCURL        string        `mapstructure:"rpc_url"`
+	Timeout       time.Duration `mapstructure:"timeout"`
+	Confirmations uint64        `mapstructure:"confirmations"` // blocks deep before a transaction counts as confirmed
+	MaxVerify     int           `mapstructure:"max_verify"`    // trades verified per response with ?verify=true
+	Concurrency   int           `mapstructure:"concurrency"`   // concurrent RPC lookups per response
+}
+
+// SnapshotConfig holds configuration for the multi-token snapshot endpoint
+type SnapshotConfig struct {
+	MaxTokens   int `mapstructure:"max_tokens"`  // token IDs accepted per request
+	Concurrency int `mapstructure:"concurrency"` // concurrent upstream fetches per request
+}
+
+// PricesConfig holds configuration for price endpoint transforms
+type PricesConfig struct {
+	RoundToTick bool `mapstructure:"round_to_tick"` // round prices to the token's tick size unless ?round=none
+}
+
+// TickerConfig holds configuration for the headline price ticker stream
+type TickerConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	Interval   time.Duration `mapstructure:"interval"`    // minimum time between updates per token
+	TopMarkets int           `mapstructure:"top_markets"` // top markets by 24h volume to stream
+}
+
+// RawProxyConfig holds configuration for the /api/v1/raw passthrough
+type RawProxyConfig struct {
+	Enabled   bool          `mapstructure:"enabled"`
+	Allowlist []string      `mapstructure:"allowlist"` // "<clob|gamma|data|*>:<path prefix>" entries; empty allows nothing
+	CacheTTL  time.Duration `mapstructure:"cache_ttl"` // GET responses are cached only when > 0
+}
+
+// AnalyticsConfig holds configuration for locally computed market analytics
+type AnalyticsConfig struct {
+	TradeSyncInterval time.Duration `mapstructure:"trade_sync_interval"` // how often recent trades are pulled
+	TradeSampleSize   int           `mapstructure:"trade_sample_size"`   // recent trades fetched per sync
+	TradeWindow       time.Duration `mapstructure:"trade_window"`        // window for trade counts
+}
+
+// DigestConfig holds configuration for the daily digest
+type DigestConfig struct {
+	TTL        time.Duration `mapstructure:"ttl"`         // how long a computed digest is served
+	Limit      int           `mapstructure:"limit"`       // entries per section
+	SpikeRatio float64       `mapstructure:"spike_ratio"` // 24h volume over the 7-day daily average that counts as a spike
+}
+
+// ExportConfig holds configuration for scheduled data export jobs
+type ExportConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	Path        string        `mapstructure:"path"`         // file jobs and their run history are saved to (empty = memory only)
+	Dir         string        `mapstructure:"dir"`          // storage directory exports are written under
+	Tick        time.Duration `mapstructure:"tick"`         // how often due jobs are checked for
+	MinInterval time.Duration `mapstructure:"min_interval"` // shortest schedule a job may have
+	MaxJobs     int           `mapstructure:"max_jobs"`
+	MaxRows     int           `mapstructure:"max_rows"` // rows written per run; larger datasets are truncated
+	History     int           `mapstructure:"history"`  // runs kept per job
+}
+
+// WebhooksConfig holds outgoing webhook delivery configuration
+type WebhooksConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Workers       int           `mapstructure:"workers"`
+	QueueSize     int           `mapstructure:"queue_size"`
+	MaxAttempts   int           `mapstructure:"max_attempts"`
+	RetryBackoff  time.Duration `mapstructure:"retry_backoff"`
+	Timeout       time.Duration `mapstructure:"timeout"`
+	MaxDeliveries int           `mapstructure:"max_deliveries"` // delivery records kept for tracing
+	ClientCert    string        `mapstructure:"client_cert"`    // PEM certificate presented to endpoints that require mTLS
+	ClientKey     string        `mapstructure:"client_key"`     // its PEM private key
+	CAFile        string        `mapstructure:"ca_file"`        // PEM CAs endpoint certificates may chain to, besides the system ones
+}
+
+// Event bus backends
+const (
+	EventBusKafka = "kafka"
+	EventBusNATS  = "nats"
+)
+
+// EventBusConfig holds configuration for publishing normalized market
+// events (trades, price changes, book deltas, resolutions) to Kafka or NATS
+type EventBusConfig struct {
+	Enabled       bool           `mapstructure:"enabled"`
+	Backend       string         `mapstructure:"backend"`  // kafka or nats
+	Servers       []string       `mapstructure:"servers"`  // host:port of Kafka bootstrap brokers or NATS servers
+	ClientID      string         `mapstructure:"client_id"`
+	User          string         `mapstructure:"user"`     // NATS only
+	Password      string         `mapstructure:"password"` // NATS only
+	Token         string         `mapstructure:"token"`    // NATS only
+	Topics        EventBusTopics `mapstructure:"topics"`
+	Markets       []string       `mapstructure:"markets"`        // markets kept subscribed upstream for the bus, as in WebSocket subscriptions
+	QueueSize     int            `mapstructure:"queue_size"`     // events waiting to be published; more are dropped
+	BatchSize     int            `mapstructure:"batch_size"`     // events per publish
+	FlushInterval time.Duration  `mapstructure:"flush_interval"` // longest an event waits for a batch to fill
+	Timeout       time.Duration  `mapstructure:"timeout"`
+}
+
+// EventBusTopics names the Kafka topic or NATS subject of each event type
+// (empty = not published)
+type EventBusTopics struct {
+	Trades       string `mapstructure:"trades"`
+	PriceChanges string `mapstructure:"price_changes"`
+	BookDeltas   string `mapstructure:"book_deltas"`
+	Resolutions  string `mapstructure:"resolutions"`
+}
+
+// RulesConfig holds configuration for the rule engine, which evaluates
+// operator-defined conditions over upstream market events and runs actions
+// (webhooks, event bus messages, log lines, orders) when they match
+type RulesConfig struct {
+	Enabled        bool                   `mapstructure:"enabled"`
+	Path           string                 `mapstructure:"path"`             // file admin API changes are saved to (empty = memory only)
+	Rules          []RuleSpec             `mapstructure:"rules"`            // initial rules; rules saved at Path replace them
+	Accounts       map[string]RuleAccount `mapstructure:"accounts"`         // named accounts order actions trade for
+	Markets        []string               `mapstructure:"markets"`          // markets kept subscribed upstream for the rules, as in WebSocket subscriptions
+	Cooldown       time.Duration          `mapstructure:"cooldown"`         // how long a rule stays quiet for a market after firing, unless the rule sets its own
+	MaxRules       int                    `mapstructure:"max_rules"`
+	MaxPriceWindow time.Duration          `mapstructure:"max_price_window"` // longest price_change_<window> a condition may read
+	QueueSize      int                    `mapstructure:"queue_size"`       // triggers waiting for their actions; more are dropped
+	History        int                    `mapstructure:"history"`          // triggers kept per rule
+}
+
+// RuleSpec is a rule: a condition over market events and the actions run
+// when an event matches it
+type RuleSpec struct {
+	Name      string       `mapstructure:"name" json:"name"`
+	Condition string       `mapstructure:"condition" json:"condition"`         // e.g. volume_24h > 1e6 AND price_change_5m > 0.05
+	Actions   []RuleAction `mapstructure:"actions" json:"actions"`
+	Cooldown  string       `mapstructure:"cooldown" json:"cooldown,omitempty"` // e.g. 10m (default: the configured cooldown)
+	Disabled  bool         `mapstructure:"disabled" json:"disabled,omitempty"`
+}
+
+// RuleAction is what a rule does when it fires
+type RuleAction struct {
+	Type      string  `mapstructure:"type" json:"type"`                       // webhook, publish, log or order
+	Topic     string  `mapstructure:"topic" json:"topic,omitempty"`           // publish: Kafka topic or NATS subject
+	Account   string  `mapstructure:"account" json:"account,omitempty"`       // order: a configured account name
+	TokenID   string  `mapstructure:"token_id" json:"token_id,omitempty"`     // order: default the event's token
+	Side      string  `mapstructure:"side" json:"side,omitempty"`             // order: BUY or SELL
+	Price     float64 `mapstructure:"price" json:"price,omitempty"`           // order: default the event's best ask (BUY) or bid (SELL), else its price
+	Size      float64 `mapstructure:"size" json:"size,omitempty"`             // order: shares
+	OrderType string  `mapstructure:"order_type" json:"order_type,omitempty"` // order: GTC (default) or FOK
+}
+
+// RuleAccount is a local account rules may place orders for
+type RuleAccount struct {
+	Address    string `mapstructure:"address"` // maker wallet
+	APIKey     string `mapstructure:"api_key"` // L2 credentials
+	Secret     string `mapstructure:"secret"`
+	Passphrase string `mapstructure:"passphrase"`
+}
+
+// RoutingConfig holds configuration for multi-account order routing:
+// orders sent to /admin/orders are placed for one of several configured
+// accounts, picked by the first route that matches
+type RoutingConfig struct {
+	Enabled  bool                      `mapstructure:"enabled"`
+	Accounts map[string]RoutingAccount `mapstructure:"accounts"` // named accounts orders may be routed to
+	Routes   []OrderRoute              `mapstructure:"routes"`   // tried in order
+}
+
+// RoutingAccount is a local account orders may be routed to
+type RoutingAccount struct {
+	Address     string  `mapstructure:"address"` // maker wallet
+	APIKey      string  `mapstructure:"api_key"` // L2 credentials
+	Secret      string  `mapstructure:"secret"`
+	Passphrase  string  `mapstructure:"passphrase"`
+	MaxExposure float64 `mapstructure:"max_exposure"` // USDC across the account's open orders, including a new one (0 = unlimited)
+}
+
+// OrderRoute sends the orders it matches to its accounts in turn
+type OrderRoute struct {
+	Market   string   `mapstructure:"market" json:"market,omitempty"`     // token ID, market ID, condition ID or slug (empty = any)
+	Strategy string   `mapstructure:"strategy" json:"strategy,omitempty"` // strategy tag (empty = any)
+	Accounts []string `mapstructure:"accounts" json:"accounts"`           // names, round-robin, skipping accounts at their exposure limit
+}
+
+// RateLimitConfig selects how the per-IP and per-tenant rate limit counts
+// requests, and gives path prefixes limits of their own
+type RateLimitConfig struct {
+	Algorithm string           `mapstructure:"algorithm"` // fixed_window, sliding_window or token_bucket
+	Burst     int              `mapstructure:"burst"`     // token bucket capacity (0 = the limit)
+	Routes    []RouteRateLimit `mapstructure:"routes"`
+}
+
+// RouteRateLimit limits requests whose path starts with Prefix, counted
+// apart from other requests; tenant limits do not apply to them
+type RouteRateLimit struct {
+	Prefix    string        `mapstructure:"prefix"`    // e.g. /api/v1/orders
+	Algorithm string        `mapstructure:"algorithm"` // empty = the global algorithm
+	Limit     int           `mapstructure:"limit"`     // requests per window (0 = the global limit)
+	Window    time.Duration `mapstructure:"window"`    // 0 = the global window
+	Burst     int           `mapstructure:"burst"`     // 0 = the global burst
+}
+
+// ConcurrencyConfig bounds the requests served at once. Route classes are
+// reads (GET /api), trading (order placement and cancellation, raw writes),
+// admin and other.
+type ConcurrencyConfig struct {
+	MaxInFlight int            `mapstructure:"max_in_flight"` // all requests (0 = unlimited)
+	Classes     map[string]int `mapstructure:"classes"`       // max in flight per route class
+	ShedAt      float64        `mapstructure:"shed_at"`       // share of max_in_flight from which reads missing the cache are refused (0 = never)
+	RetryAfter  time.Duration  `mapstructure:"retry_after"`   // suggested to refused clients
+}
+
+// TenantsConfig holds configuration for tenants: groups of API keys with
+// their own rate limit, watchlists, webhooks, cached user data and usage
+type TenantsConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	Tenants        []TenantSpec  `mapstructure:"tenants"`
+	RateLimit      int           `mapstructure:"rate_limit"`      // requests per 10s per tenant, unless the tenant sets its own
+	UsagePath      string        `mapstructure:"usage_path"`      // file usage is saved to (empty = memory only)
+	UsageRetention int           `mapstructure:"usage_retention"` // days of usage kept
+	SaveInterval   time.Duration `mapstructure:"save_interval"`
+}
+
+// TenantSpec is a tenant and the API keys that belong to it
+type TenantSpec struct {
+	ID        string   `mapstructure:"id"`
+	Name      string   `mapstructure:"name"`
+	APIKeys   []string `mapstructure:"api_keys"`   // POLY-API-KEY values
+	RateLimit int      `mapstructure:"rate_limit"` // requests per 10s (0 = the default)
+}
+
+// WatchlistConfig holds configuration for wallet and market watchlists
+type WatchlistConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	Interval       time.Duration `mapstructure:"interval"`        // how often each wallet's trades are polled
+	MaxWallets     int           `mapstructure:"max_wallets"`     // wallets watched across all callers
+	TradesLimit    int           `mapstructure:"trades_limit"`    // recent trades fetched per poll
+	MarketInterval time.Duration `mapstructure:"market_interval"` // how often watched markets are polled
+	MaxMarkets     int           `mapstructure:"max_markets"`     // markets watched across all callers
+	Path           string        `mapstructure:"path"`            // file watchlists are saved to (empty = memory only)
+}
+
+// PositionAlertsConfig holds configuration for alerts on the position
+// changes of watched wallets
+type PositionAlertsConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	Interval       time.Duration `mapstructure:"interval"`        // how often each watched wallet's positions are polled
+	MaxWatches     int           `mapstructure:"max_watches"`     // watches across all callers
+	PositionsLimit int           `mapstructure:"positions_limit"` // positions fetched per poll
+	Path           string        `mapstructure:"path"`            // file watches are saved to (empty = memory only)
+}
+
+// EquityConfig holds configuration for the daily portfolio snapshots of
+// watched wallets
+type EquityConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	At             string        `mapstructure:"at"`              // UTC time of day snapshots are taken, HH:MM
+	PositionsLimit int           `mapstructure:"positions_limit"` // positions fetched per wallet and snapshot
+	Retention      time.Duration `mapstructure:"retention"`       // how long snapshots are kept
+	Path           string        `mapstructure:"path"`            // file history is saved to (empty = memory only)
+}
+
+// TaxReportConfig holds configuration for cost-basis reports
+type TaxReportConfig struct {
+	Enabled     bool `mapstructure:"enabled"`
+	MaxActivity int  `mapstructure:"max_activity"` // activity entries fetched per report, newest first
+}
+
+// StrategiesConfig holds configuration for attributing tagged orders to
+// strategies
+type StrategiesConfig struct {
+	Enabled   bool   `mapstructure:"enabled"`
+	MaxOrders int    `mapstructure:"max_orders"` // tagged orders kept across all callers; the oldest are dropped
+	Path      string `mapstructure:"path"`       // file orders and fills are saved to (empty = memory only)
+}
+
+// AdminConfig holds configuration for the /admin endpoints
+type AdminConfig struct {
+	Token string `mapstructure:"token"` // bearer token required on /admin (empty = open)
+}
+
+// AccessConfig holds role-based access control: callers present a PolyGo
+// access key or JWT as a bearer token, and its role (viewer, trader or
+// admin) decides which routes they may call
+type AccessConfig struct {
+	Enabled     bool        `mapstructure:"enabled"`
+	Keys        []AccessKey `mapstructure:"keys"`
+	JWTSecret   string      `mapstructure:"jwt_secret"`   // HS256 secret JWTs are signed with (empty = JWTs are not accepted)
+	DefaultRole string      `mapstructure:"default_role"` // role of callers without a token (empty = they are rejected)
+}
+
+// AccessKey is a PolyGo access key and its role
+type AccessKey struct {
+	Name string `mapstructure:"name"` // who the key belongs to, as logged and audited
+	Key  string `mapstructure:"key"`
+	Role string `mapstructure:"role"` // viewer, trader or admin
+}
+
+// AuditConfig holds configuration for the audit log, an append-only record
+// of the changes callers make: orders placed and cancelled, dead-man's
+// switches armed, configuration and webhooks changed
+type AuditConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"` // JSON lines file entries are appended to (empty = memory only)
+	Keep    int    `mapstructure:"keep"` // latest entries /admin/audit searches; exports read the whole file
+}
+
+// ErrorReportingConfig holds where recovered panics are reported besides
+// the log: Sentry and/or Bugsnag, when their DSN or API key is set
+type ErrorReportingConfig struct {
+	SentryDSN     string        `mapstructure:"sentry_dsn"`
+	BugsnagAPIKey string        `mapstructure:"bugsnag_api_key"`
+	BugsnagURL    string        `mapstructure:"bugsnag_url"`  // notify endpoint (on-premise Bugsnag)
+	Environment   string        `mapstructure:"environment"`  // Sentry environment / Bugsnag release stage
+	Release       string        `mapstructure:"release"`
+	DedupWindow   time.Duration `mapstructure:"dedup_window"` // identical panics are reported once per window, with a count
+	Timeout       time.Duration `mapstructure:"timeout"`
+	QueueSize     int           `mapstructure:"queue_size"`   // reports waiting to be sent; more are dropped
+}
+
+// DocsConfig holds where the API docs (Swagger UI, /openapi.json) tell
+// clients to send requests, so each environment advertises its own URL
+type DocsConfig struct {
+	Host     string   `mapstructure:"host"`      // e.g. api.example.com (empty = the host serving the docs)
+	BasePath string   `mapstructure:"base_path"`
+	Schemes  []string `mapstructure:"schemes"`   // e.g. https (empty = the scheme serving the docs)
+}
+
+// Replication modes
+const (
+	ReplicationModePrimary = "primary"
+	ReplicationModeReplica = "replica"
+)
+
+// Upstream payload validation modes
+const (
+	ValidationOff    = "off"
+	ValidationWarn   = "warn"
+	ValidationStrict = "strict"
+)
+
+// Tape modes
+const (
+	TapeModeOff    = "off"
+	TapeModeRecord = "record"
+	TapeModeReplay = "replay"
+)
+
+// TapeConfig holds record/replay configuration. Recording writes every
+// upstream response and WebSocket frame under Dir; replaying serves them
+// back without contacting Polymarket.
+type TapeConfig struct {
+	Mode    string  `mapstructure:"mode"`     // off, record or replay
+	Dir     string  `mapstructure:"dir"`      // directory holding the recording
+	WSSpeed float64 `mapstructure:"ws_speed"` // replay speed of WebSocket frames; 0 sends them without delay
+	WSLoop  bool    `mapstructure:"ws_loop"`  // restart WebSocket replay at the end of the recording
+}
+
+// StorageConfig holds where artifacts (recorder snapshots, exports, tape
+// recordings) are kept. The local backend uses the configured paths as
+// files; s3 and gcs use them as object names under Prefix in Bucket.
+type StorageConfig struct {
+	Backend         string        `mapstructure:"backend"`  // local, s3 or gcs
+	Endpoint        string        `mapstructure:"endpoint"` // S3-compatible endpoint URL without a path; defaults to AWS for s3 and storage.googleapis.com for gcs
+	Region          string        `mapstructure:"region"`
+	Bucket          string        `mapstructure:"bucket"`
+	Prefix          string        `mapstructure:"prefix"`            // object name prefix inside the bucket
+	AccessKeyID     string        `mapstructure:"access_key_id"`     // for gcs, an HMAC key
+	SecretAccessKey string        `mapstructure:"secret_access_key"`
+	Timeout         time.Duration `mapstructure:"timeout"` // per request
+}
+
+// SecretsConfig holds where vault:// and aws-sm:// references in other
+// settings are read from: HashiCorp Vault and AWS Secrets Manager
+type SecretsConfig struct {
+	VaultAddr          string        `mapstructure:"vault_addr"`
+	VaultToken         string        `mapstructure:"vault_token"`
+	VaultNamespace     string        `mapstructure:"vault_namespace"` // Vault Enterprise namespace
+	AWSRegion          string        `mapstructure:"aws_region"`
+	AWSEndpoint        string        `mapstructure:"aws_endpoint"` // Secrets Manager endpoint (default: the region's)
+	AWSAccessKeyID     string        `mapstructure:"aws_access_key_id"`
+	AWSSecretAccessKey string        `mapstructure:"aws_secret_access_key"`
+	AWSSessionToken    string        `mapstructure:"aws_session_token"`
+	RefreshInterval    time.Duration `mapstructure:"refresh_interval"` // how often references are read again to catch rotations (0 = at startup only)
+	Timeout            time.Duration `mapstructure:"timeout"`          // per read
+}
+
+// ReplicaTokenHeader carries the shared replication secret
+const ReplicaTokenHeader = "X-PolyGo-Replica-Token"
+
+// ReplicationConfig holds primary/replica deployment configuration
+type ReplicationConfig struct {
+	Mode          string `mapstructure:"mode"`           // primary (talks to Polymarket) or replica
+	PrimaryURL    string `mapstructure:"primary_url"`    // base URL of the primary, e.g. http://polygo:8080
+	Token         string `mapstructure:"token"`          // shared secret between primary and replicas
+	ServeReplicas bool   `mapstructure:"serve_replicas"` // expose /replica endpoints on this instance
+}
+
+// IsReplica returns true when this instance sources data from a primary
+func (r *ReplicationConfig) IsReplica() bool {
+	return r.Mode == ReplicationModeReplica && r.PrimaryURL != ""
+}
+
+// ApplyTo points the upstream configuration at the primary instance
+func (r *ReplicationConfig) ApplyTo(pm *PolymarketConfig) {
+	base := strings.TrimRight(r.PrimaryURL, "/")
+	pm.ClobBaseURL = base + "/replica/clob"
+	pm.GammaBaseURL = base + "/replica/gamma"
+	pm.DataBaseURL = base + "/replica/data"
+	// Polymarket's mirrors are no stand-in for the primary
+	pm.ClobMirrors = nil
+	pm.GammaMirrors = nil
+	pm.DataMirrors = nil
+
+	wsBase := base
+	switch {
+	case strings.HasPrefix(base, "https://"):
+		wsBase = "wss://" + strings.TrimPrefix(base, "https://")
+	case strings.HasPrefix(base, "http://"):
+		wsBase = "ws://" + strings.TrimPrefix(base, "http://")
+	}
+	pm.WsClobURL = wsBase + "/ws/markets"
+	pm.WsLiveDataURL = ""
+	pm.WsUserURL = ""
+
+	pm.HonorCacheHeaders = true
+	if r.Token != "" {
+		if pm.ExtraHeaders == nil {
+			pm.ExtraHeaders = make(map[string]string)
+		}
+		pm.ExtraHeaders[ReplicaTokenHeader] = r.Token
+	}
+}
+
 // DefaultConfig returns default configuration
 func DefaultConfig() *Config {
 	return &Config{
@@ -72,19 +770,59 @@ func DefaultConfig() *Config {
 			IdleTimeout:  30 * time.Second,
 			Prefork:      false,
 			Debug:        false,
+			SocketMode:   "0660",
+			ProxyHeader:  "X-Forwarded-For",
+			DrainTimeout:    15 * time.Second,
+			ShutdownTimeout: 10 * time.Second,
+			ReconnectHint:   5 * time.Second,
+			SlowRequestThreshold: time.Second,
+			BookSnapshotEvery: 100,
+			WSBufferLimit:       256 * 1024 * 1024,
+			WSClientBufferLimit: 4 * 1024 * 1024,
+			WSReplayWindow:      30 * time.Second,
+			GoroutineBudget:     10000,
+			CORSOrigins:     "*",
+			BodyLimit:       4 * 1024 * 1024,
+			OrderBodyLimit:  64 * 1024,
+			JSONBodyLimit:   16 * 1024,
 		},
 		Polymarket: PolymarketConfig{
 			ClobBaseURL:     "https://clob.polymarket.com",
 			GammaBaseURL:    "https://gamma-api.polymarket.com",
 			DataBaseURL:     "https://data-api.polymarket.com",
+			FailoverCheckInterval: 10 * time.Second,
+			FailoverThreshold:     3,
 			WsClobURL:       "wss://ws-subscriptions-clob.polymarket.com/ws/",
 			WsLiveDataURL:   "wss://ws-live-data.polymarket.com",
+			WsUserURL:       "wss://ws-subscriptions-clob.polymarket.com/ws/user",
+			WsShards:        2,
+			WsPingInterval:  30 * time.Second,
+			WsPongTimeout:   10 * time.Second,
+			WsStaleTimeout:  60 * time.Second,
 			MaxConnsPerHost: 1000,
+			MaxConnWait:     time.Second,
 			ReadTimeout:     5 * time.Second,
 			WriteTimeout:    5 * time.Second,
 			MaxIdleConnDur:  30 * time.Second,
+			DialTimeout:     5 * time.Second,
+			DNSCacheTTL:        time.Minute,
+			HappyEyeballsDelay: 250 * time.Millisecond,
+			WarmupConns:        4,
+			Timeouts: map[string]time.Duration{
+				"/prices-history": 15 * time.Second,
+			},
 			RetryCount:      3,
 			RetryWaitTime:   100 * time.Millisecond,
+			RetryMaxWait:    2 * time.Second,
+			RetryMethods:    []string{"GET", "HEAD", "DELETE"},
+			RetryBudgetRatio: 0.1,
+			RetryBudgetBurst: 50,
+			UpstreamRPS:       100,
+			UpstreamBurst:     200,
+			UpstreamQueueSize: 1000,
+			UpstreamQueueWait: 2 * time.Second,
+			Validation:        ValidationOff,
+			StreamThreshold:   8 << 20, // 8MB
 		},
 		Cache: CacheConfig{
 			MaxCost:      1 << 30,      // 1GB
@@ -95,6 +833,15 @@ func DefaultConfig() *Config {
 			PricesTTL:    100 * time.Millisecond,
 			OrderBookTTL: 50 * time.Millisecond,
 			DefaultTTL:   5 * time.Second,
+			UserDataTTL:  2 * time.Second,
+			CompositeTTL: time.Second,
+			MaxEntrySize:      16 << 20, // 16MB
+			Compression:       "none",
+			CompressThreshold: 64 << 10, // 64KB
+			TTLJitter:         0.1,
+			StaleFor:          time.Second,
+			// Market catalog, events and token metadata
+			PersistPrefixes: []string{"markets:", "events:", "price:tick:", "price:negrisk:", "price:fee:"},
 		},
 		Auth: AuthConfig{
 			APIKeyHeader:     "POLY-API-KEY",
@@ -103,6 +850,229 @@ func DefaultConfig() *Config {
 			SignatureHeader:  "POLY-SIGNATURE",
 			TimestampHeader:  "POLY-TIMESTAMP",
 		},
+		Health: HealthConfig{
+			ProbeInterval:      10 * time.Second,
+			ProbeTimeout:       2 * time.Second,
+			UnhealthyThreshold: time.Minute,
+			ClobProbePath:      "/time",
+			GammaProbePath:     "/markets?limit=1",
+			DataProbePath:      "/",
+		},
+		Catalog: CatalogConfig{
+			Enabled:      true,
+			SyncInterval: 5 * time.Minute,
+			PageSize:     500,
+			MaxEvents:    5000,
+		},
+		Recorder: RecorderConfig{
+			Enabled:        true,
+			SampleInterval: 30 * time.Second,
+			Resolution:     time.Minute,
+			Retention:      25 * time.Hour,
+			MaxMarkets:     500,
+			SaveInterval:   5 * time.Minute,
+		},
+		Leaderboard: LeaderboardConfig{
+			Enabled:   true,
+			Interval:  time.Hour,
+			Size:      100,
+			Retention: 30 * 24 * time.Hour,
+			Path:      "./data/leaderboard.json",
+		},
+		CopyTrade: CopyTradeConfig{
+			Enabled:          false,
+			Interval:         5 * time.Second,
+			TradesLimit:      50,
+			Scale:            1,
+			MaxOrderNotional: 100,
+			MaxExposure:      1000,
+		},
+		Risk: RiskConfig{
+			Enabled: true,
+			Path:    "./data/risk.json",
+		},
+		OrderExpiry: OrderExpiryConfig{
+			Enabled:      true,
+			MinLifetime:  90 * time.Second,
+			CancelBuffer: 30 * time.Second,
+			Interval:     time.Second,
+			MaxOrders:    10000,
+		},
+		FillNotify: FillNotifyConfig{
+			Enabled:     true,
+			IdleTimeout: time.Minute,
+			MaxOrders:   10000,
+		},
+		DeadMan: DeadManConfig{
+			Enabled:    true,
+			MinTimeout: time.Second,
+			MaxTimeout: 5 * time.Minute,
+		},
+		OrderRules: OrderRulesConfig{
+			Enabled:      true,
+			SizeDecimals: 2,
+		},
+		Throttle: ThrottleConfig{
+			Enabled:         true,
+			DuplicateWindow: 2 * time.Second,
+			MarketRate:      120,
+			MarketWindow:    time.Minute,
+		},
+		Chain: ChainConfig{
+			RPCURL:        "https://polygon-rpc.com",
+			Timeout:       5 * time.Second,
+			Confirmations: 30,
+			MaxVerify:     50,
+			Concurrency:   8,
+		},
+		Snapshot: SnapshotConfig{
+			MaxTokens:   50,
+			Concurrency: 8,
+		},
+		Ticker: TickerConfig{
+			Enabled:    true,
+			Interval:   time.Second,
+			TopMarkets: 50,
+		},
+		RawProxy: RawProxyConfig{
+			Enabled: true,
+		},
+		Analytics: AnalyticsConfig{
+			TradeSyncInterval: time.Minute,
+			TradeSampleSize:   1000,
+			TradeWindow:       24 * time.Hour,
+		},
+		Digest: DigestConfig{
+			TTL:        time.Hour,
+			Limit:      10,
+			SpikeRatio: 3,
+		},
+		Export: ExportConfig{
+			Enabled:     false,
+			Path:        "./data/exports.json",
+			Dir:         "./data/exports",
+			Tick:        30 * time.Second,
+			MinInterval: 5 * time.Minute,
+			MaxJobs:     50,
+			MaxRows:     100000,
+			History:     20,
+		},
+		Webhooks: WebhooksConfig{
+			Enabled:       true,
+			Workers:       4,
+			QueueSize:     1000,
+			MaxAttempts:   5,
+			RetryBackoff:  time.Second,
+			Timeout:       5 * time.Second,
+			MaxDeliveries: 10000,
+		},
+		EventBus: EventBusConfig{
+			Enabled:  false,
+			Backend:  EventBusNATS,
+			ClientID: "polygo",
+			Topics: EventBusTopics{
+				Trades:       "polygo.trades",
+				PriceChanges: "polygo.price_changes",
+				BookDeltas:   "polygo.book_deltas",
+				Resolutions:  "polygo.resolutions",
+			},
+			QueueSize:     10000,
+			BatchSize:     500,
+			FlushInterval: 100 * time.Millisecond,
+			Timeout:       10 * time.Second,
+		},
+		Rules: RulesConfig{
+			Enabled:        false,
+			Path:           "./data/rules.json",
+			Cooldown:       5 * time.Minute,
+			MaxRules:       100,
+			MaxPriceWindow: time.Hour,
+			QueueSize:      1000,
+			History:        50,
+		},
+		RateLimit: RateLimitConfig{
+			Algorithm: "fixed_window",
+		},
+		Concurrency: ConcurrencyConfig{
+			ShedAt:     0.8,
+			RetryAfter: time.Second,
+		},
+		Tenants: TenantsConfig{
+			Enabled:        false,
+			RateLimit:      1000,
+			UsagePath:      "./data/tenant_usage.json",
+			UsageRetention: 90,
+			SaveInterval:   time.Minute,
+		},
+		Watchlist: WatchlistConfig{
+			Enabled:        true,
+			Interval:       15 * time.Second,
+			MaxWallets:     500,
+			TradesLimit:    50,
+			MarketInterval: 5 * time.Second,
+			MaxMarkets:     2000,
+			Path:           "./data/watchlists.json",
+		},
+		PositionAlerts: PositionAlertsConfig{
+			Enabled:        true,
+			Interval:       30 * time.Second,
+			MaxWatches:     500,
+			PositionsLimit: 500,
+			Path:           "./data/position_alerts.json",
+		},
+		Equity: EquityConfig{
+			Enabled:        true,
+			At:             "00:00",
+			PositionsLimit: 500,
+			Retention:      365 * 24 * time.Hour,
+			Path:           "./data/equity.json",
+		},
+		TaxReport: TaxReportConfig{
+			Enabled:     true,
+			MaxActivity: 10000,
+		},
+		Strategies: StrategiesConfig{
+			Enabled:   true,
+			MaxOrders: 20000,
+			Path:      "./data/strategies.json",
+		},
+		Replication: ReplicationConfig{
+			Mode: ReplicationModePrimary,
+		},
+		Tape: TapeConfig{
+			Mode:    TapeModeOff,
+			Dir:     "./tape",
+			WSSpeed: 1,
+			WSLoop:  true,
+		},
+		Storage: StorageConfig{
+			Backend: "local",
+			Region:  "us-east-1",
+			Timeout: 5 * time.Minute,
+		},
+		Access: AccessConfig{
+			Enabled:     false,
+			DefaultRole: "viewer",
+		},
+		Audit: AuditConfig{
+			Enabled: false,
+			Path:    "./data/audit.jsonl",
+			Keep:    10000,
+		},
+		Secrets: SecretsConfig{
+			RefreshInterval: 5 * time.Minute,
+			Timeout:         10 * time.Second,
+		},
+		ErrorReporting: ErrorReportingConfig{
+			BugsnagURL:  "https://notify.bugsnag.com",
+			Environment: "production",
+			DedupWindow: 5 * time.Minute,
+			Timeout:     5 * time.Second,
+			QueueSize:   100,
+		},
+		Docs: DocsConfig{
+			BasePath: "/",
+		},
 	}
 }
 
@@ -135,6 +1105,11 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	// Replicas source everything from the primary instead of Polymarket
+	if cfg.Replication.IsReplica() {
+		cfg.Replication.ApplyTo(&cfg.Polymarket)
+	}
+
 	return cfg, nil
 }
 
@@ -144,16 +1119,319 @@ func bindEnvVars() {
 	viper.BindEnv("server.port", "POLYGO_PORT")
 	viper.BindEnv("server.debug", "POLYGO_DEBUG")
 	viper.BindEnv("server.prefork", "POLYGO_PREFORK")
+	viper.BindEnv("server.listen", "POLYGO_LISTEN")
+	viper.BindEnv("server.socket_mode", "POLYGO_SOCKET_MODE")
+	viper.BindEnv("server.trusted_proxies", "POLYGO_TRUSTED_PROXIES")
+	viper.BindEnv("server.proxy_header", "POLYGO_PROXY_HEADER")
+	viper.BindEnv("server.drain_timeout", "POLYGO_DRAIN_TIMEOUT")
+	viper.BindEnv("server.shutdown_timeout", "POLYGO_SHUTDOWN_TIMEOUT")
+	viper.BindEnv("server.book_snapshot_every", "POLYGO_BOOK_SNAPSHOT_EVERY")
+	viper.BindEnv("server.ws_buffer_limit", "POLYGO_WS_BUFFER_LIMIT")
+	viper.BindEnv("server.ws_client_buffer_limit", "POLYGO_WS_CLIENT_BUFFER_LIMIT")
+	viper.BindEnv("server.ws_replay_window", "POLYGO_WS_REPLAY_WINDOW")
+	viper.BindEnv("server.goroutine_budget", "POLYGO_GOROUTINE_BUDGET")
+	viper.BindEnv("server.slow_request_threshold", "POLYGO_SLOW_REQUEST_THRESHOLD")
+	viper.BindEnv("server.cors_origins", "POLYGO_CORS_ORIGINS")
+	viper.BindEnv("server.cors_allow_credentials", "POLYGO_CORS_ALLOW_CREDENTIALS")
+	viper.BindEnv("server.body_limit", "POLYGO_BODY_LIMIT")
+	viper.BindEnv("server.order_body_limit", "POLYGO_ORDER_BODY_LIMIT")
+	viper.BindEnv("server.json_body_limit", "POLYGO_JSON_BODY_LIMIT")
+	viper.BindEnv("admin.token", "POLYGO_ADMIN_TOKEN")
+
+	// Role-based access control
+	viper.BindEnv("access.enabled", "POLYGO_ACCESS_ENABLED")
+	viper.BindEnv("access.jwt_secret", "POLYGO_ACCESS_JWT_SECRET")
+	viper.BindEnv("access.default_role", "POLYGO_ACCESS_DEFAULT_ROLE")
+
+	// Audit log
+	viper.BindEnv("audit.enabled", "POLYGO_AUDIT_ENABLED")
+	viper.BindEnv("audit.path", "POLYGO_AUDIT_PATH")
+	viper.BindEnv("audit.keep", "POLYGO_AUDIT_KEEP")
+
+	// Panic reporting
+	viper.BindEnv("error_reporting.sentry_dsn", "POLYGO_SENTRY_DSN")
+	viper.BindEnv("error_reporting.bugsnag_api_key", "POLYGO_BUGSNAG_API_KEY")
+	viper.BindEnv("error_reporting.environment", "POLYGO_ERROR_REPORTING_ENVIRONMENT")
+	viper.BindEnv("error_reporting.release", "POLYGO_ERROR_REPORTING_RELEASE")
+	viper.BindEnv("error_reporting.dedup_window", "POLYGO_ERROR_REPORTING_DEDUP_WINDOW")
+
+	// API docs
+	viper.BindEnv("docs.host", "POLYGO_DOCS_HOST")
+	viper.BindEnv("docs.base_path", "POLYGO_DOCS_BASE_PATH")
+	viper.BindEnv("docs.schemes", "POLYGO_DOCS_SCHEMES")
 
 	// Polymarket URLs
 	viper.BindEnv("polymarket.clob_base_url", "POLYGO_CLOB_URL")
 	viper.BindEnv("polymarket.gamma_base_url", "POLYGO_GAMMA_URL")
 	viper.BindEnv("polymarket.data_base_url", "POLYGO_DATA_URL")
+	viper.BindEnv("polymarket.clob_mirrors", "POLYGO_CLOB_MIRRORS")
+	viper.BindEnv("polymarket.gamma_mirrors", "POLYGO_GAMMA_MIRRORS")
+	viper.BindEnv("polymarket.data_mirrors", "POLYGO_DATA_MIRRORS")
+	viper.BindEnv("polymarket.failover_check_interval", "POLYGO_FAILOVER_CHECK_INTERVAL")
+	viper.BindEnv("polymarket.failover_threshold", "POLYGO_FAILOVER_THRESHOLD")
+	viper.BindEnv("polymarket.ws_shards", "POLYGO_WS_SHARDS")
+	viper.BindEnv("polymarket.retry_count", "POLYGO_RETRY_COUNT")
+	viper.BindEnv("polymarket.retry_max_wait", "POLYGO_RETRY_MAX_WAIT")
+	viper.BindEnv("polymarket.retry_methods", "POLYGO_RETRY_METHODS")
+	viper.BindEnv("polymarket.retry_budget_ratio", "POLYGO_RETRY_BUDGET_RATIO")
+	viper.BindEnv("polymarket.retry_budget_burst", "POLYGO_RETRY_BUDGET_BURST")
+	viper.BindEnv("polymarket.upstream_rps", "POLYGO_UPSTREAM_RPS")
+	viper.BindEnv("polymarket.upstream_burst", "POLYGO_UPSTREAM_BURST")
+	viper.BindEnv("polymarket.upstream_queue_size", "POLYGO_UPSTREAM_QUEUE_SIZE")
+	viper.BindEnv("polymarket.upstream_queue_wait", "POLYGO_UPSTREAM_QUEUE_WAIT")
+	viper.BindEnv("polymarket.validation", "POLYGO_VALIDATION")
+	viper.BindEnv("polymarket.stream_threshold", "POLYGO_STREAM_THRESHOLD")
+	viper.BindEnv("polymarket.ws_ping_interval", "POLYGO_WS_PING_INTERVAL")
+	viper.BindEnv("polymarket.ws_pong_timeout", "POLYGO_WS_PONG_TIMEOUT")
+	viper.BindEnv("polymarket.ws_stale_timeout", "POLYGO_WS_STALE_TIMEOUT")
+	viper.BindEnv("polymarket.ws_user_url", "POLYGO_WS_USER_URL")
+	viper.BindEnv("polymarket.proxy", "POLYGO_UPSTREAM_PROXY")
+	viper.BindEnv("polymarket.max_conns_per_host", "POLYGO_MAX_CONNS_PER_HOST")
+	viper.BindEnv("polymarket.max_conn_wait", "POLYGO_MAX_CONN_WAIT")
+	viper.BindEnv("polymarket.dial_timeout", "POLYGO_DIAL_TIMEOUT")
+	viper.BindEnv("polymarket.dns_cache_ttl", "POLYGO_DNS_CACHE_TTL")
+	viper.BindEnv("polymarket.happy_eyeballs_delay", "POLYGO_HAPPY_EYEBALLS_DELAY")
+	viper.BindEnv("polymarket.warmup_conns", "POLYGO_WARMUP_CONNS")
 
 	// Cache
 	viper.BindEnv("cache.max_cost", "POLYGO_CACHE_MAX_COST")
 	viper.BindEnv("cache.markets_ttl", "POLYGO_CACHE_MARKETS_TTL")
 	viper.BindEnv("cache.prices_ttl", "POLYGO_CACHE_PRICES_TTL")
+	viper.BindEnv("cache.user_data_ttl", "POLYGO_CACHE_USER_DATA_TTL")
+	viper.BindEnv("cache.max_entry_size", "POLYGO_CACHE_MAX_ENTRY_SIZE")
+	viper.BindEnv("cache.compression", "POLYGO_CACHE_COMPRESSION")
+	viper.BindEnv("cache.ttl_jitter", "POLYGO_CACHE_TTL_JITTER")
+	viper.BindEnv("cache.stale_for", "POLYGO_CACHE_STALE_FOR")
+	viper.BindEnv("cache.persist_path", "POLYGO_CACHE_PERSIST_PATH")
+	viper.BindEnv("cache.persist_prefixes", "POLYGO_CACHE_PERSIST_PREFIXES")
+
+	// Health
+	viper.BindEnv("health.probe_interval", "POLYGO_HEALTH_PROBE_INTERVAL")
+	viper.BindEnv("health.unhealthy_threshold", "POLYGO_HEALTH_UNHEALTHY_THRESHOLD")
+
+	// Catalog
+	viper.BindEnv("catalog.enabled", "POLYGO_CATALOG_ENABLED")
+	viper.BindEnv("catalog.sync_interval", "POLYGO_CATALOG_SYNC_INTERVAL")
+
+	// Leaderboard history
+	viper.BindEnv("leaderboard.enabled", "POLYGO_LEADERBOARD_ENABLED")
+	viper.BindEnv("leaderboard.interval", "POLYGO_LEADERBOARD_INTERVAL")
+	viper.BindEnv("leaderboard.size", "POLYGO_LEADERBOARD_SIZE")
+	viper.BindEnv("leaderboard.retention", "POLYGO_LEADERBOARD_RETENTION")
+	viper.BindEnv("leaderboard.path", "POLYGO_LEADERBOARD_PATH")
+	
+	// Copy trading
+	viper.BindEnv("copytrade.enabled", "POLYGO_COPYTRADE_ENABLED")
+	viper.BindEnv("copytrade.interval", "POLYGO_COPYTRADE_INTERVAL")
+	viper.BindEnv("copytrade.trades_limit", "POLYGO_COPYTRADE_TRADES_LIMIT")
+	viper.BindEnv("copytrade.scale", "POLYGO_COPYTRADE_SCALE")
+	viper.BindEnv("copytrade.max_order_notional", "POLYGO_COPYTRADE_MAX_ORDER_NOTIONAL")
+	viper.BindEnv("copytrade.max_exposure", "POLYGO_COPYTRADE_MAX_EXPOSURE")
+	viper.BindEnv("copytrade.address", "POLYGO_COPYTRADE_ADDRESS")
+	viper.BindEnv("copytrade.api_key", "POLYGO_COPYTRADE_API_KEY")
+	viper.BindEnv("copytrade.secret", "POLYGO_COPYTRADE_SECRET")
+	viper.BindEnv("copytrade.passphrase", "POLYGO_COPYTRADE_PASSPHRASE")
+	
+	// Risk limits
+	viper.BindEnv("risk.enabled", "POLYGO_RISK_ENABLED")
+	viper.BindEnv("risk.default.max_order_size", "POLYGO_RISK_MAX_ORDER_SIZE")
+	viper.BindEnv("risk.default.max_open_notional", "POLYGO_RISK_MAX_OPEN_NOTIONAL")
+	viper.BindEnv("risk.default.max_orders_per_minute", "POLYGO_RISK_MAX_ORDERS_PER_MINUTE")
+	viper.BindEnv("risk.default.banned_markets", "POLYGO_RISK_BANNED_MARKETS")
+	viper.BindEnv("risk.path", "POLYGO_RISK_PATH")
+	
+	// GTD order expiry
+	viper.BindEnv("order_expiry.enabled", "POLYGO_ORDER_EXPIRY_ENABLED")
+	viper.BindEnv("order_expiry.min_lifetime", "POLYGO_ORDER_EXPIRY_MIN_LIFETIME")
+	viper.BindEnv("order_expiry.cancel_buffer", "POLYGO_ORDER_EXPIRY_CANCEL_BUFFER")
+	viper.BindEnv("order_expiry.interval", "POLYGO_ORDER_EXPIRY_INTERVAL")
+	viper.BindEnv("order_expiry.max_orders", "POLYGO_ORDER_EXPIRY_MAX_ORDERS")
+	
+	// Fill notifications
+	viper.BindEnv("fill_notify.enabled", "POLYGO_FILL_NOTIFY_ENABLED")
+	viper.BindEnv("fill_notify.idle_timeout", "POLYGO_FILL_NOTIFY_IDLE_TIMEOUT")
+	viper.BindEnv("fill_notify.max_orders", "POLYGO_FILL_NOTIFY_MAX_ORDERS")
+	
+	// Dead-man's switch
+	viper.BindEnv("dead_man.enabled", "POLYGO_DEAD_MAN_ENABLED")
+	viper.BindEnv("dead_man.min_timeout", "POLYGO_DEAD_MAN_MIN_TIMEOUT")
+	viper.BindEnv("dead_man.max_timeout", "POLYGO_DEAD_MAN_MAX_TIMEOUT")
+	
+	// Order tick, size and price rules
+	viper.BindEnv("order_rules.enabled", "POLYGO_ORDER_RULES_ENABLED")
+	viper.BindEnv("order_rules.min_size", "POLYGO_ORDER_RULES_MIN_SIZE")
+	viper.BindEnv("order_rules.size_decimals", "POLYGO_ORDER_RULES_SIZE_DECIMALS")
+	
+	// Duplicate orders and per-market order rate
+	viper.BindEnv("throttle.enabled", "POLYGO_THROTTLE_ENABLED")
+	viper.BindEnv("throttle.duplicate_window", "POLYGO_THROTTLE_DUPLICATE_WINDOW")
+	viper.BindEnv("throttle.market_rate", "POLYGO_THROTTLE_MARKET_RATE")
+	viper.BindEnv("throttle.market_window", "POLYGO_THROTTLE_MARKET_WINDOW")
+	
+	// Chain
+	viper.BindEnv("chain.rpc_url", "POLYGO_CHAIN_RPC_URL")
+	viper.BindEnv("chain.timeout", "POLYGO_CHAIN_TIMEOUT")
+	viper.BindEnv("chain.confirmations", "POLYGO_CHAIN_CONFIRMATIONS")
+	viper.BindEnv("chain.max_verify", "POLYGO_CHAIN_MAX_VERIFY")
+
+	// Snapshot
+	viper.BindEnv("snapshot.max_tokens", "POLYGO_SNAPSHOT_MAX_TOKENS")
+	viper.BindEnv("snapshot.concurrency", "POLYGO_SNAPSHOT_CONCURRENCY")
+
+	// Prices
+	viper.BindEnv("prices.round_to_tick", "POLYGO_PRICES_ROUND_TO_TICK")
+
+	// Ticker
+	viper.BindEnv("ticker.enabled", "POLYGO_TICKER_ENABLED")
+	viper.BindEnv("ticker.interval", "POLYGO_TICKER_INTERVAL")
+	viper.BindEnv("ticker.top_markets", "POLYGO_TICKER_TOP_MARKETS")
+
+	// Raw proxy
+	viper.BindEnv("raw_proxy.enabled", "POLYGO_RAW_PROXY_ENABLED")
+	viper.BindEnv("raw_proxy.allowlist", "POLYGO_RAW_PROXY_ALLOWLIST")
+	viper.BindEnv("raw_proxy.cache_ttl", "POLYGO_RAW_PROXY_CACHE_TTL")
+
+	// Analytics
+	viper.BindEnv("analytics.trade_sync_interval", "POLYGO_ANALYTICS_TRADE_SYNC_INTERVAL")
+	viper.BindEnv("analytics.trade_sample_size", "POLYGO_ANALYTICS_TRADE_SAMPLE_SIZE")
+
+	// Digest
+	viper.BindEnv("digest.ttl", "POLYGO_DIGEST_TTL")
+	viper.BindEnv("digest.limit", "POLYGO_DIGEST_LIMIT")
+	viper.BindEnv("digest.spike_ratio", "POLYGO_DIGEST_SPIKE_RATIO")
+
+	// Export jobs
+	viper.BindEnv("export.enabled", "POLYGO_EXPORT_ENABLED")
+	viper.BindEnv("export.path", "POLYGO_EXPORT_PATH")
+	viper.BindEnv("export.dir", "POLYGO_EXPORT_DIR")
+	viper.BindEnv("export.tick", "POLYGO_EXPORT_TICK")
+	viper.BindEnv("export.min_interval", "POLYGO_EXPORT_MIN_INTERVAL")
+	viper.BindEnv("export.max_jobs", "POLYGO_EXPORT_MAX_JOBS")
+	viper.BindEnv("export.max_rows", "POLYGO_EXPORT_MAX_ROWS")
+	viper.BindEnv("export.history", "POLYGO_EXPORT_HISTORY")
+
+	// Storage
+	viper.BindEnv("storage.backend", "POLYGO_STORAGE_BACKEND")
+	viper.BindEnv("storage.endpoint", "POLYGO_STORAGE_ENDPOINT")
+	viper.BindEnv("storage.region", "POLYGO_STORAGE_REGION")
+	viper.BindEnv("storage.bucket", "POLYGO_STORAGE_BUCKET")
+	viper.BindEnv("storage.prefix", "POLYGO_STORAGE_PREFIX")
+	viper.BindEnv("storage.access_key_id", "POLYGO_STORAGE_ACCESS_KEY_ID")
+	viper.BindEnv("storage.secret_access_key", "POLYGO_STORAGE_SECRET_ACCESS_KEY")
+	viper.BindEnv("storage.timeout", "POLYGO_STORAGE_TIMEOUT")
+
+	// Secret references (the usual Vault and AWS variables work too)
+	viper.BindEnv("secrets.vault_addr", "POLYGO_VAULT_ADDR", "VAULT_ADDR")
+	viper.BindEnv("secrets.vault_token", "POLYGO_VAULT_TOKEN", "VAULT_TOKEN")
+	viper.BindEnv("secrets.vault_namespace", "POLYGO_VAULT_NAMESPACE", "VAULT_NAMESPACE")
+	viper.BindEnv("secrets.aws_region", "POLYGO_SECRETS_AWS_REGION", "AWS_REGION")
+	viper.BindEnv("secrets.aws_endpoint", "POLYGO_SECRETS_AWS_ENDPOINT")
+	viper.BindEnv("secrets.aws_access_key_id", "POLYGO_SECRETS_AWS_ACCESS_KEY_ID", "AWS_ACCESS_KEY_ID")
+	viper.BindEnv("secrets.aws_secret_access_key", "POLYGO_SECRETS_AWS_SECRET_ACCESS_KEY", "AWS_SECRET_ACCESS_KEY")
+	viper.BindEnv("secrets.aws_session_token", "POLYGO_SECRETS_AWS_SESSION_TOKEN", "AWS_SESSION_TOKEN")
+	viper.BindEnv("secrets.refresh_interval", "POLYGO_SECRETS_REFRESH_INTERVAL")
+	viper.BindEnv("secrets.timeout", "POLYGO_SECRETS_TIMEOUT")
+
+	// Recorder
+	viper.BindEnv("recorder.enabled", "POLYGO_RECORDER_ENABLED")
+	viper.BindEnv("recorder.sample_interval", "POLYGO_RECORDER_SAMPLE_INTERVAL")
+	viper.BindEnv("recorder.retention", "POLYGO_RECORDER_RETENTION")
+	viper.BindEnv("recorder.max_markets", "POLYGO_RECORDER_MAX_MARKETS")
+	viper.BindEnv("recorder.path", "POLYGO_RECORDER_PATH")
+	viper.BindEnv("recorder.save_interval", "POLYGO_RECORDER_SAVE_INTERVAL")
+
+	// Webhooks
+	viper.BindEnv("webhooks.enabled", "POLYGO_WEBHOOKS_ENABLED")
+	viper.BindEnv("webhooks.client_cert", "POLYGO_WEBHOOKS_CLIENT_CERT")
+	viper.BindEnv("webhooks.client_key", "POLYGO_WEBHOOKS_CLIENT_KEY")
+	viper.BindEnv("webhooks.ca_file", "POLYGO_WEBHOOKS_CA_FILE")
+
+	// Event bus
+	viper.BindEnv("eventbus.enabled", "POLYGO_EVENTBUS_ENABLED")
+	viper.BindEnv("eventbus.backend", "POLYGO_EVENTBUS_BACKEND")
+	viper.BindEnv("eventbus.servers", "POLYGO_EVENTBUS_SERVERS")
+	viper.BindEnv("eventbus.client_id", "POLYGO_EVENTBUS_CLIENT_ID")
+	viper.BindEnv("eventbus.user", "POLYGO_EVENTBUS_USER")
+	viper.BindEnv("eventbus.password", "POLYGO_EVENTBUS_PASSWORD")
+	viper.BindEnv("eventbus.token", "POLYGO_EVENTBUS_TOKEN")
+	viper.BindEnv("eventbus.topics.trades", "POLYGO_EVENTBUS_TOPIC_TRADES")
+	viper.BindEnv("eventbus.topics.price_changes", "POLYGO_EVENTBUS_TOPIC_PRICE_CHANGES")
+	viper.BindEnv("eventbus.topics.book_deltas", "POLYGO_EVENTBUS_TOPIC_BOOK_DELTAS")
+	viper.BindEnv("eventbus.topics.resolutions", "POLYGO_EVENTBUS_TOPIC_RESOLUTIONS")
+	viper.BindEnv("eventbus.markets", "POLYGO_EVENTBUS_MARKETS")
+	viper.BindEnv("eventbus.queue_size", "POLYGO_EVENTBUS_QUEUE_SIZE")
+
+	// Rate limit algorithm (per-route limits are configured in config.yaml)
+	viper.BindEnv("rate_limit.algorithm", "POLYGO_RATE_LIMIT_ALGORITHM")
+	viper.BindEnv("rate_limit.burst", "POLYGO_RATE_LIMIT_BURST")
+
+	// Concurrency limits (per-class limits are configured in config.yaml)
+	viper.BindEnv("concurrency.max_in_flight", "POLYGO_MAX_IN_FLIGHT")
+	viper.BindEnv("concurrency.shed_at", "POLYGO_SHED_AT")
+	viper.BindEnv("concurrency.retry_after", "POLYGO_SHED_RETRY_AFTER")
+
+	// Tenants (tenants and their API keys are configured in config.yaml)
+	viper.BindEnv("tenants.enabled", "POLYGO_TENANTS_ENABLED")
+	viper.BindEnv("tenants.rate_limit", "POLYGO_TENANTS_RATE_LIMIT")
+	viper.BindEnv("tenants.usage_path", "POLYGO_TENANTS_USAGE_PATH")
+	viper.BindEnv("tenants.usage_retention", "POLYGO_TENANTS_USAGE_RETENTION")
+	viper.BindEnv("tenants.save_interval", "POLYGO_TENANTS_SAVE_INTERVAL")
+
+	// Rule engine
+	viper.BindEnv("rules.enabled", "POLYGO_RULES_ENABLED")
+	viper.BindEnv("rules.path", "POLYGO_RULES_PATH")
+	viper.BindEnv("rules.markets", "POLYGO_RULES_MARKETS")
+	viper.BindEnv("rules.cooldown", "POLYGO_RULES_COOLDOWN")
+	viper.BindEnv("rules.max_rules", "POLYGO_RULES_MAX_RULES")
+	viper.BindEnv("rules.max_price_window", "POLYGO_RULES_MAX_PRICE_WINDOW")
+	viper.BindEnv("rules.queue_size", "POLYGO_RULES_QUEUE_SIZE")
+	viper.BindEnv("rules.history", "POLYGO_RULES_HISTORY")
+
+	// Order routing
+	viper.BindEnv("routing.enabled", "POLYGO_ROUTING_ENABLED")
+
+	// Watchlist
+	viper.BindEnv("watchlist.enabled", "POLYGO_WATCHLIST_ENABLED")
+	viper.BindEnv("watchlist.interval", "POLYGO_WATCHLIST_INTERVAL")
+	viper.BindEnv("watchlist.max_wallets", "POLYGO_WATCHLIST_MAX_WALLETS")
+	viper.BindEnv("watchlist.market_interval", "POLYGO_WATCHLIST_MARKET_INTERVAL")
+	viper.BindEnv("watchlist.max_markets", "POLYGO_WATCHLIST_MAX_MARKETS")
+	viper.BindEnv("watchlist.path", "POLYGO_WATCHLIST_PATH")
+	
+	// Position alerts
+	viper.BindEnv("position_alerts.enabled", "POLYGO_POSITION_ALERTS_ENABLED")
+	viper.BindEnv("position_alerts.interval", "POLYGO_POSITION_ALERTS_INTERVAL")
+	viper.BindEnv("position_alerts.max_watches", "POLYGO_POSITION_ALERTS_MAX_WATCHES")
+	viper.BindEnv("position_alerts.positions_limit", "POLYGO_POSITION_ALERTS_POSITIONS_LIMIT")
+	viper.BindEnv("position_alerts.path", "POLYGO_POSITION_ALERTS_PATH")
+	
+	// Equity curves
+	viper.BindEnv("equity.enabled", "POLYGO_EQUITY_ENABLED")
+	viper.BindEnv("equity.at", "POLYGO_EQUITY_AT")
+	viper.BindEnv("equity.positions_limit", "POLYGO_EQUITY_POSITIONS_LIMIT")
+	viper.BindEnv("equity.retention", "POLYGO_EQUITY_RETENTION")
+	viper.BindEnv("equity.path", "POLYGO_EQUITY_PATH")
+	
+	// Tax reports
+	viper.BindEnv("tax_report.enabled", "POLYGO_TAX_REPORT_ENABLED")
+	viper.BindEnv("tax_report.max_activity", "POLYGO_TAX_REPORT_MAX_ACTIVITY")
+	
+	// Strategy attribution
+	viper.BindEnv("strategies.enabled", "POLYGO_STRATEGIES_ENABLED")
+	viper.BindEnv("strategies.max_orders", "POLYGO_STRATEGIES_MAX_ORDERS")
+	viper.BindEnv("strategies.path", "POLYGO_STRATEGIES_PATH")
+
+	// Replication
+	viper.BindEnv("replication.mode", "POLYGO_REPLICATION_MODE")
+	viper.BindEnv("replication.primary_url", "POLYGO_PRIMARY_URL")
+	viper.BindEnv("replication.token", "POLYGO_REPLICATION_TOKEN")
+
+	// Record/replay
+	viper.BindEnv("tape.mode", "POLYGO_TAPE_MODE")
+	viper.BindEnv("tape.dir", "POLYGO_TAPE_DIR")
+	viper.BindEnv("tape.ws_speed", "POLYGO_TAPE_WS_SPEED")
+	viper.BindEnv("tape.ws_loop", "POLYGO_TAPE_WS_LOOP")
+	viper.BindEnv("replication.serve_replicas", "POLYGO_SERVE_REPLICAS")
 }
 
 // GetAddress returns the full address string
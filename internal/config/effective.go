@@ -0,0 +1,287 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// redacted replaces secret values in the effective configuration
+const redacted = "[REDACTED]"
+
+// minSafeCacheCost is the MaxCost below which the cache thrashes under
+// normal market traffic
+const minSafeCacheCost = 64 << 20 // 64MB
+
+// Effective is a redacted view of the running configuration together with
+// warnings about risky combinations of settings
+type Effective struct {
+	Config   Config   `json:"config"`
+	Warnings []string `json:"warnings"`
+}
+
+// Effective returns the redacted configuration and its warnings
+func (c *Config) Effective() *Effective {
+	return &Effective{
+		Config:   c.Redacted(),
+		Warnings: c.Warnings(),
+	}
+}
+
+// Redacted returns a copy of the configuration with secrets masked
+func (c *Config) Redacted() Config {
+	out := *c
+
+	if out.Replication.Token != "" {
+		out.Replication.Token = redacted
+	}
+	if out.Admin.Token != "" {
+		out.Admin.Token = redacted
+	}
+	if out.CopyTrade.Secret != "" {
+		out.CopyTrade.Secret = redacted
+	}
+	if out.CopyTrade.Passphrase != "" {
+		out.CopyTrade.Passphrase = redacted
+	}
+	if out.EventBus.Password != "" {
+		out.EventBus.Password = redacted
+	}
+	if out.EventBus.Token != "" {
+		out.EventBus.Token = redacted
+	}
+	if len(c.Rules.Accounts) > 0 {
+		out.Rules.Accounts = make(map[string]RuleAccount, len(c.Rules.Accounts))
+		for name, acct := range c.Rules.Accounts {
+			if acct.Secret != "" {
+				acct.Secret = redacted
+			}
+			if acct.Passphrase != "" {
+				acct.Passphrase = redacted
+			}
+			out.Rules.Accounts[name] = acct
+		}
+	}
+	if len(c.Routing.Accounts) > 0 {
+		out.Routing.Accounts = make(map[string]RoutingAccount, len(c.Routing.Accounts))
+		for name, acct := range c.Routing.Accounts {
+			if acct.Secret != "" {
+				acct.Secret = redacted
+			}
+			if acct.Passphrase != "" {
+				acct.Passphrase = redacted
+			}
+			out.Routing.Accounts[name] = acct
+		}
+	}
+	if len(c.Tenants.Tenants) > 0 {
+		out.Tenants.Tenants = make([]TenantSpec, len(c.Tenants.Tenants))
+		for i, t := range c.Tenants.Tenants {
+			keys := make([]string, len(t.APIKeys))
+			for k := range keys {
+				keys[k] = redacted
+			}
+			t.APIKeys = keys
+			out.Tenants.Tenants[i] = t
+		}
+	}
+	if len(c.Access.Keys) > 0 {
+		out.Access.Keys = make([]AccessKey, len(c.Access.Keys))
+		for i, k := range c.Access.Keys {
+			k.Key = redacted
+			out.Access.Keys[i] = k
+		}
+	}
+	if out.Access.JWTSecret != "" {
+		out.Access.JWTSecret = redacted
+	}
+	if out.Storage.SecretAccessKey != "" {
+		out.Storage.SecretAccessKey = redacted
+	}
+	if out.Secrets.VaultToken != "" {
+		out.Secrets.VaultToken = redacted
+	}
+	if out.Secrets.AWSSecretAccessKey != "" {
+		out.Secrets.AWSSecretAccessKey = redacted
+	}
+	if out.Secrets.AWSSessionToken != "" {
+		out.Secrets.AWSSessionToken = redacted
+	}
+	if out.ErrorReporting.SentryDSN != "" {
+		out.ErrorReporting.SentryDSN = redactURL(out.ErrorReporting.SentryDSN)
+	}
+	if out.ErrorReporting.BugsnagAPIKey != "" {
+		out.ErrorReporting.BugsnagAPIKey = redacted
+	}
+	if out.Chain.RPCURL != "" {
+		out.Chain.RPCURL = redactURL(out.Chain.RPCURL)
+	}
+	if len(c.Risk.Accounts) > 0 {
+		out.Risk.Accounts = make(map[string]RiskLimits, len(c.Risk.Accounts))
+		for k, v := range c.Risk.Accounts {
+			out.Risk.Accounts[redactKey(k)] = v
+		}
+	}
+	if out.Polymarket.Proxy != "" {
+		out.Polymarket.Proxy = redactURL(out.Polymarket.Proxy)
+	}
+	if len(c.Polymarket.Proxies) > 0 {
+		out.Polymarket.Proxies = make(map[string]string, len(c.Polymarket.Proxies))
+		for host, p := range c.Polymarket.Proxies {
+			if p != "direct" {
+				p = redactURL(p)
+			}
+			out.Polymarket.Proxies[host] = p
+		}
+	}
+	if len(c.Polymarket.ExtraHeaders) > 0 {
+		out.Polymarket.ExtraHeaders = make(map[string]string, len(c.Polymarket.ExtraHeaders))
+		for k := range c.Polymarket.ExtraHeaders {
+			out.Polymarket.ExtraHeaders[k] = redacted
+		}
+	}
+
+	return out
+}
+
+// redactKey masks an API key, keeping enough of it to tell accounts apart
+func redactKey(key string) string {
+	if len(key) <= 8 {
+		return redacted
+	}
+	return key[:4] + "..." + redacted
+}
+
+// redactURL keeps a URL's scheme and host; hosted RPC providers put the
+// API key in the path or query
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return redacted
+	}
+	if u.User == nil && (u.Path == "" || u.Path == "/") && u.RawQuery == "" {
+		return raw
+	}
+	return u.Scheme + "://" + u.Host + "/" + redacted
+}
+
+// adminOpen reports whether /admin is open to anyone: no token, and no
+// access control requiring admin keys
+func (c *Config) adminOpen() bool {
+	return c.Admin.Token == "" && !c.Access.Enabled
+}
+
+// Validate refuses setting combinations that would let anyone trade with
+// server-held accounts
+func (c *Config) Validate() error {
+	if c.Routing.Enabled && len(c.Routing.Accounts) > 0 && c.adminOpen() {
+		return errors.New("order routing with accounts requires an admin token: anyone could place orders for the routing accounts")
+	}
+	if c.CopyTrade.Enabled && c.adminOpen() {
+		return errors.New("copy trading requires an admin token: anyone could start mirroring trades with the configured account")
+	}
+	if c.Rules.Enabled && len(c.Rules.Accounts) > 0 && c.adminOpen() {
+		return errors.New("rules with accounts require an admin token: anyone could add a rule that trades with them")
+	}
+	return nil
+}
+
+// Warnings lists dangerous or likely unintended setting combinations
+func (c *Config) Warnings() []string {
+	warnings := []string{}
+	adminOpen := c.adminOpen()
+
+	if c.Server.Prefork {
+		warnings = append(warnings, "prefork is enabled but the rate limiter and cache are in-memory: limits and cached data are per process, not per server")
+	}
+	if c.Server.Prefork && c.Server.Listen != "" {
+		warnings = append(warnings, "prefork is not supported with server.listen: a single process serves every listener")
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if proxy == "0.0.0.0/0" || proxy == "::/0" {
+			warnings = append(warnings, "trusted_proxies trusts every address: any client can set its own IP and dodge the rate limit")
+			break
+		}
+	}
+	if c.Server.CORSAllowCredentials && strings.Contains(c.Server.CORSOrigins, "*") {
+		warnings = append(warnings, "CORS allows credentials with a wildcard origin: any site can make authenticated requests on behalf of users")
+	}
+	if s := c.Server; s.BodyLimit > 0 && (s.OrderBodyLimit > s.BodyLimit || s.JSONBodyLimit > s.BodyLimit) {
+		warnings = append(warnings, "order_body_limit or json_body_limit exceeds body_limit: larger bodies are rejected by body_limit first")
+	}
+	if s := c.Server; s.WSBufferLimit > 0 && s.WSClientBufferLimit > s.WSBufferLimit {
+		warnings = append(warnings, "ws_client_buffer_limit exceeds ws_buffer_limit: a single slow client can take the whole WebSocket buffer")
+	}
+	if c.Cache.MaxCost < minSafeCacheCost {
+		warnings = append(warnings, fmt.Sprintf("cache max_cost is %d bytes (< %d): expect constant evictions and upstream load", c.Cache.MaxCost, int64(minSafeCacheCost)))
+	}
+	if c.Cache.MaxEntrySize > c.Cache.MaxCost && c.Cache.MaxCost > 0 {
+		warnings = append(warnings, "cache max_entry_size exceeds max_cost: a single entry can evict the whole cache")
+	}
+	if adminOpen {
+		warnings = append(warnings, "admin token is not set: /admin endpoints are unauthenticated")
+	}
+	if c.Tenants.Enabled && len(c.Tenants.Tenants) == 0 {
+		warnings = append(warnings, "tenants are enabled but none are configured: webhooks and watchlists reject every caller")
+	}
+	if c.Access.Enabled && (c.Access.DefaultRole == "trader" || c.Access.DefaultRole == "admin") {
+		warnings = append(warnings, "access control gives callers without a token the "+c.Access.DefaultRole+" role: anyone can place orders")
+	}
+	if c.Audit.Enabled && adminOpen {
+		warnings = append(warnings, "the audit log is enabled without an admin token: admin changes are not attributable and anyone can read the log")
+	}
+	if e := c.OrderExpiry; e.Enabled && e.MinLifetime <= e.CancelBuffer {
+		warnings = append(warnings, "order expiry min_lifetime does not exceed cancel_buffer: GTD orders with the shortest accepted expiration are cancelled as soon as they are placed")
+	}
+	if c.Replication.Mode == ReplicationModeReplica && c.Replication.PrimaryURL == "" {
+		warnings = append(warnings, "replication mode is replica but primary_url is empty: talking to Polymarket directly")
+	}
+	if c.Tape.Mode == TapeModeRecord {
+		warnings = append(warnings, "recording upstream traffic to "+c.Tape.Dir+": responses include user orders and positions, keep the recording private")
+	}
+	if c.Tape.Mode == TapeModeReplay {
+		warnings = append(warnings, "replaying recorded traffic from "+c.Tape.Dir+": Polymarket is not contacted and requests not in the recording fail")
+	}
+	if c.Webhooks.Enabled && c.Webhooks.Workers <= 0 {
+		warnings = append(warnings, "webhooks are enabled with no workers: deliveries will never be sent")
+	}
+	if w := c.Watchlist; w.Enabled && w.Interval > 0 && c.Polymarket.UpstreamRPS > 0 {
+		if rps := float64(w.MaxWallets) / w.Interval.Seconds(); rps > c.Polymarket.UpstreamRPS/2 {
+			warnings = append(warnings, fmt.Sprintf("a full watchlist polls %.0f wallets/s, more than half the upstream rate limit: other Data API requests will queue", rps))
+		}
+	}
+
+	return warnings
+}
+
+// Banner renders a short human-readable summary of the effective settings
+func (c *Config) Banner() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Effective configuration:\n")
+	fmt.Fprintf(&b, "  server:      %s:%d (prefork=%t, debug=%t)\n", c.Server.Host, c.Server.Port, c.Server.Prefork, c.Server.Debug)
+	fmt.Fprintf(&b, "  cors:        origins=%q credentials=%t\n", c.Server.CORSOrigins, c.Server.CORSAllowCredentials)
+	fmt.Fprintf(&b, "  upstream:    clob=%s gamma=%s data=%s\n", c.Polymarket.ClobBaseURL, c.Polymarket.GammaBaseURL, c.Polymarket.DataBaseURL)
+	if pm := c.Polymarket; len(pm.ClobMirrors)+len(pm.GammaMirrors)+len(pm.DataMirrors) > 0 {
+		fmt.Fprintf(&b, "  mirrors:     clob=%d gamma=%d data=%d\n", len(pm.ClobMirrors), len(pm.GammaMirrors), len(pm.DataMirrors))
+	}
+	if c.Polymarket.Proxy != "" || len(c.Polymarket.Proxies) > 0 {
+		proxy := "direct"
+		if c.Polymarket.Proxy != "" {
+			proxy = redactURL(c.Polymarket.Proxy)
+		}
+		fmt.Fprintf(&b, "  proxy:       default=%s per_host=%d\n", proxy, len(c.Polymarket.Proxies))
+	}
+	fmt.Fprintf(&b, "  cache:       max_cost=%d compression=%s markets_ttl=%s prices_ttl=%s\n", c.Cache.MaxCost, c.Cache.Compression, c.Cache.MarketsTTL, c.Cache.PricesTTL)
+	fmt.Fprintf(&b, "  replication: mode=%s serve_replicas=%t\n", c.Replication.Mode, c.Replication.ServeReplicas)
+	fmt.Fprintf(&b, "  tape:        mode=%s dir=%s\n", c.Tape.Mode, c.Tape.Dir)
+	fmt.Fprintf(&b, "  storage:     backend=%s bucket=%s\n", c.Storage.Backend, c.Storage.Bucket)
+	fmt.Fprintf(&b, "  catalog:     enabled=%t  webhooks: enabled=%t\n", c.Catalog.Enabled, c.Webhooks.Enabled)
+
+	for _, w := range c.Warnings() {
+		fmt.Fprintf(&b, "  WARNING: %s\n", w)
+	}
+
+	return b.String()
+}
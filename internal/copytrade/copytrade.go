@@ -0,0 +1,423 @@
+package copytrade
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/pretrade"
+	"github.com/polygo/pkg/polygoclient"
+	"github.com/polygo/pkg/validate"
+)
+
+// maxRecent bounds the mirrored trades kept for status
+const maxRecent = 100
+
+// Mirror statuses
+const (
+	StatusPlaced  = "placed"
+	StatusFilled  = "filled"
+	StatusSkipped = "skipped"
+	StatusFailed  = "failed"
+)
+
+var (
+	// ErrDisabled is returned when copy trading is not enabled in config
+	ErrDisabled = errors.New("copy trading is disabled")
+	// ErrNoAccount is returned when no local account credentials are configured
+	ErrNoAccount = errors.New("copy trading account is not configured")
+	// ErrRunning is returned when a wallet is already being followed
+	ErrRunning = errors.New("already following a wallet")
+	// ErrNotRunning is returned when stopping with nothing followed
+	ErrNotRunning = errors.New("not following a wallet")
+)
+
+// Options configure one copy-trading session. Zero values fall back to
+// the configured defaults.
+type Options struct {
+	Target           string  `json:"target"`
+	Scale            float64 `json:"scale,omitempty"`
+	MaxOrderNotional float64 `json:"max_order_notional,omitempty"`
+	MaxExposure      float64 `json:"max_exposure,omitempty"`
+}
+
+// Mirror is one target trade and what was done about it
+type Mirror struct {
+	Time       time.Time   `json:"time"`
+	SourceTx   string      `json:"source_tx"`
+	TokenID    string      `json:"token_id"`
+	Side       models.Side `json:"side"`
+	Price      float64     `json:"price"`
+	TargetSize float64     `json:"target_size"`
+	Size       float64     `json:"size"` // shares ordered for the local account
+	OrderID    string      `json:"order_id,omitempty"`
+	Status     string      `json:"status"`
+	Reason     string      `json:"reason,omitempty"`
+}
+
+// Status is the engine's current session
+type Status struct {
+	Running    bool       `json:"running"`
+	Account    string     `json:"account"`
+	Options    *Options   `json:"options,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	LastPolled *time.Time `json:"last_polled,omitempty"`
+	LastError  string     `json:"last_error,omitempty"`
+	Exposure   float64    `json:"exposure"` // net USDC bought this session
+	Placed     int        `json:"placed"`
+	Skipped    int        `json:"skipped"`
+	Failed     int        `json:"failed"`
+	Recent     []Mirror   `json:"recent"` // newest first
+}
+
+// targetTrade is the subset of a Data API trade needed to mirror it
+type targetTrade struct {
+	Asset           string  `json:"asset"`
+	Side            string  `json:"side"`
+	Size            float64 `json:"size"`
+	Price           float64 `json:"price"`
+	TransactionHash string  `json:"transactionHash"`
+}
+
+// key identifies a trade across overlapping polls
+func (t *targetTrade) key() string {
+	return t.TransactionHash + ":" + t.Asset + ":" + t.Side + ":" +
+		strconv.FormatFloat(t.Size, 'f', -1, 64) + ":" + strconv.FormatFloat(t.Price, 'f', -1, 64)
+}
+
+// Engine follows one target wallet at a time, mirroring its new trades as
+// orders for the configured local account. Orders are signed with the
+// account's L2 credentials, pass the same pre-trade checks and are
+// tracked like orders placed through the API.
+type Engine struct {
+	data   *polymarket.DataClient
+	clob   *polymarket.ClobClient
+	fills  *polymarket.FillTracker
+	checks *pretrade.Chain
+	auth   *config.AuthConfig
+	config *config.CopyTradeConfig
+
+	mu     sync.Mutex
+	opts   *Options
+	status Status
+	seen   map[string]struct{}
+	primed bool // the first poll only records what is already there
+
+	// pollMu serializes polls so a trade is never mirrored twice
+	pollMu sync.Mutex
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a new copy-trading engine; nothing is followed until Start
+func New(data *polymarket.DataClient, clob *polymarket.ClobClient, fills *polymarket.FillTracker, checks *pretrade.Chain, auth *config.AuthConfig, cfg *config.CopyTradeConfig) *Engine {
+	return &Engine{
+		data:   data,
+		clob:   clob,
+		fills:  fills,
+		checks: checks,
+		auth:   auth,
+		config: cfg,
+		status: Status{Account: strings.ToLower(cfg.Address), Recent: []Mirror{}},
+	}
+}
+
+// Start begins following opts.Target, polling every Interval. Trades the
+// target made before Start are not mirrored.
+func (e *Engine) Start(opts Options) error {
+	if !e.config.Enabled {
+		return ErrDisabled
+	}
+	if e.config.APIKey == "" || e.config.Secret == "" || e.config.Passphrase == "" || !validate.IsAddress(e.config.Address) {
+		return ErrNoAccount
+	}
+	if err := validate.Address(opts.Target); err != nil {
+		return err
+	}
+	opts.Target = strings.ToLower(opts.Target)
+	if opts.Scale <= 0 {
+		opts.Scale = e.config.Scale
+	}
+	if opts.MaxOrderNotional <= 0 {
+		opts.MaxOrderNotional = e.config.MaxOrderNotional
+	}
+	if opts.MaxExposure <= 0 {
+		opts.MaxExposure = e.config.MaxExposure
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.opts != nil {
+		return ErrRunning
+	}
+	now := time.Now()
+	e.opts = &opts
+	e.seen = make(map[string]struct{})
+	e.primed = false
+	e.status = Status{
+		Running:   true,
+		Account:   strings.ToLower(e.config.Address),
+		Options:   &opts,
+		StartedAt: &now,
+		Recent:    []Mirror{},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+
+		ticker := time.NewTicker(e.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			// A poll racing Stop finds nothing to follow; that is not a failure
+			if err := e.Poll(); err != nil && !errors.Is(err, ErrNotRunning) {
+				log.Printf("Copy trade poll for %s failed: %v", opts.Target, err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops following the target. The last session's status is kept.
+func (e *Engine) Stop() error {
+	e.mu.Lock()
+	if e.opts == nil {
+		e.mu.Unlock()
+		return ErrNotRunning
+	}
+	e.opts = nil
+	e.status.Running = false
+	cancel := e.cancel
+	e.mu.Unlock()
+
+	cancel()
+	e.wg.Wait()
+	return nil
+}
+
+// Status returns a copy of the current or last session's status
+func (e *Engine) Status() Status {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	s := e.status
+	s.Recent = make([]Mirror, len(e.status.Recent))
+	copy(s.Recent, e.status.Recent)
+	return s
+}
+
+// Poll diffs the target's recent trades against those seen last time and
+// mirrors the new ones, oldest first
+func (e *Engine) Poll() error {
+	e.pollMu.Lock()
+	defer e.pollMu.Unlock()
+
+	e.mu.Lock()
+	if e.opts == nil {
+		e.mu.Unlock()
+		return ErrNotRunning
+	}
+	opts := *e.opts
+	e.mu.Unlock()
+
+	trades, err := e.fetch(opts.Target)
+
+	e.mu.Lock()
+	now := time.Now()
+	e.status.LastPolled = &now
+	if err != nil {
+		e.status.LastError = err.Error()
+		e.mu.Unlock()
+		return err
+	}
+	e.status.LastError = ""
+
+	seen := make(map[string]struct{}, len(trades))
+	var fresh []targetTrade
+	// Upstream lists newest first; mirror oldest first
+	for i := len(trades) - 1; i >= 0; i-- {
+		key := trades[i].key()
+		seen[key] = struct{}{}
+		if _, ok := e.seen[key]; !ok && e.primed {
+			fresh = append(fresh, trades[i])
+		}
+	}
+	e.seen = seen
+	e.primed = true
+	e.mu.Unlock()
+
+	for _, t := range fresh {
+		e.mirror(opts, t)
+	}
+	return nil
+}
+
+// fetch returns the target's recent trades, bypassing the cache
+func (e *Engine) fetch(target string) ([]targetTrade, error) {
+	data, _, err := e.data.GetTrades(target, e.config.TradesLimit, "", true)
+	if err != nil {
+		return nil, err
+	}
+	var trades []targetTrade
+	if err := sonic.Unmarshal(data, &trades); err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
+
+// mirror sizes one target trade for the local account, applies the caps
+// and the pre-trade checks and places the order
+func (e *Engine) mirror(opts Options, t targetTrade) {
+	m := Mirror{
+		Time:       time.Now(),
+		SourceTx:   t.TransactionHash,
+		TokenID:    t.Asset,
+		Side:       models.Side(strings.ToUpper(t.Side)),
+		Price:      t.Price,
+		TargetSize: t.Size,
+	}
+	if m.Side != models.SideBuy && m.Side != models.SideSell {
+		e.record(m, StatusSkipped, "unknown side "+t.Side, 0)
+		return
+	}
+	if t.Price <= 0 || t.Price >= 1 {
+		e.record(m, StatusSkipped, "price out of range", 0)
+		return
+	}
+
+	size := t.Size * opts.Scale
+	if opts.MaxOrderNotional > 0 && size*t.Price > opts.MaxOrderNotional {
+		size = opts.MaxOrderNotional / t.Price
+	}
+	// The CLOB accepts sizes to two decimals; round down so caps hold
+	m.Size = math.Floor(size*100) / 100
+	if m.Size <= 0 {
+		e.record(m, StatusSkipped, "scaled size rounds to zero", 0)
+		return
+	}
+
+	notional := m.Size * t.Price
+	delta := notional
+	if m.Side == models.SideSell {
+		delta = -notional
+	}
+	e.mu.Lock()
+	over := delta > 0 && opts.MaxExposure > 0 && e.status.Exposure+delta > opts.MaxExposure
+	e.mu.Unlock()
+	if over {
+		e.record(m, StatusSkipped, "max exposure reached", 0)
+		return
+	}
+
+	req := &models.CreateOrderRequest{
+		TokenID: t.Asset,
+		Side:    m.Side,
+		Price:   strconv.FormatFloat(t.Price, 'f', -1, 64),
+		Size:    strconv.FormatFloat(m.Size, 'f', 2, 64),
+		Type:    models.OrderTypeGTC,
+		Maker:   e.config.Address,
+	}
+	reservation, err := e.checks.Check(e.credentials(), []models.CreateOrderRequest{*req})
+	if err != nil {
+		e.record(m, StatusSkipped, err.Error(), 0)
+		return
+	}
+	headers, err := e.authHeaders(req)
+	if err != nil {
+		e.checks.Release(reservation)
+		e.record(m, StatusFailed, err.Error(), 0)
+		return
+	}
+	data, err := e.clob.CreateOrder(req, headers)
+	if err != nil {
+		e.checks.Release(reservation)
+		e.record(m, StatusFailed, err.Error(), 0)
+		return
+	}
+
+	var placed models.PlacedOrder
+	if err := sonic.Unmarshal(data, &placed); err != nil {
+		e.record(m, StatusFailed, "unreadable order response", 0)
+		return
+	}
+	if placed.Success != nil && !*placed.Success {
+		e.checks.Release(reservation)
+		e.record(m, StatusFailed, placed.ErrorMsg, 0)
+		return
+	}
+	m.OrderID = placed.OrderID
+
+	// Immediate fills invalidate now; resting orders are tracked for later
+	if strings.EqualFold(placed.Status, "matched") {
+		e.data.InvalidateUser(strings.ToLower(e.config.Address))
+		e.record(m, StatusFilled, "", delta)
+		return
+	}
+	e.fills.Track(placed.OrderID, e.config.Address)
+	e.record(m, StatusPlaced, "", delta)
+}
+
+// record appends a mirror to the status and counts it. Exposure moves by
+// delta, never below zero, for orders that were placed.
+func (e *Engine) record(m Mirror, status, reason string, delta float64) {
+	m.Status = status
+	m.Reason = reason
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch status {
+	case StatusPlaced, StatusFilled:
+		e.status.Placed++
+		e.status.Exposure = math.Max(0, e.status.Exposure+delta)
+	case StatusSkipped:
+		e.status.Skipped++
+	case StatusFailed:
+		e.status.Failed++
+		log.Printf("Copy trade order for %s failed: %s", m.TokenID, reason)
+	}
+
+	e.status.Recent = append([]Mirror{m}, e.status.Recent...)
+	if len(e.status.Recent) > maxRecent {
+		e.status.Recent = e.status.Recent[:maxRecent]
+	}
+}
+
+// authHeaders signs an order with the local account's L2 credentials
+func (e *Engine) authHeaders(req *models.CreateOrderRequest) (map[string]string, error) {
+	body, err := sonic.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return polymarket.SignedHeaders(e.auth, e.credentials(), "POST", "/order", body), nil
+}
+
+// credentials returns the local account's L2 credentials
+func (e *Engine) credentials() *polygoclient.Credentials {
+	return &polygoclient.Credentials{
+		APIKey:     e.config.APIKey,
+		Secret:     e.config.Secret,
+		Passphrase: e.config.Passphrase,
+	}
+}
@@ -0,0 +1,103 @@
+package crashreport
+
+import (
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/bugsnag/bugsnag-go/v2"
+	"github.com/bugsnag/bugsnag-go/v2/errors"
+	"github.com/polygo/internal/config"
+)
+
+// bugsnagNotifier sends reports to Bugsnag with bugsnag-go
+type bugsnagNotifier struct {
+	notifier *bugsnag.Notifier
+}
+
+func newBugsnag(cfg *config.ErrorReportingConfig, hostname string) *bugsnagNotifier {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: cfg.Timeout}).DialContext
+	transport.TLSHandshakeTimeout = cfg.Timeout
+	transport.ResponseHeaderTimeout = cfg.Timeout
+
+	return &bugsnagNotifier{notifier: bugsnag.New(bugsnag.Configuration{
+		APIKey: cfg.BugsnagAPIKey,
+		// No sessions endpoint: we report crashes, not sessions
+		Endpoints:           bugsnag.Endpoints{Notify: cfg.BugsnagURL},
+		AutoCaptureSessions: false,
+		ReleaseStage:        cfg.Environment,
+		AppVersion:          cfg.Release,
+		Hostname:            hostname,
+		ProjectPackages:     []string{inAppPrefix + "**"},
+		Transport:           transport,
+		// The Reporter logs failed deliveries itself
+		Logger: log.New(io.Discard, "", 0),
+	})}
+}
+
+func (b *bugsnagNotifier) name() string {
+	return "Bugsnag"
+}
+
+// panicError carries a report's message and frames into bugsnag-go, which
+// would otherwise take the stack of the delivery worker
+type panicError struct {
+	report *Report
+}
+
+func (e panicError) Error() string {
+	return e.report.Message
+}
+
+func (e panicError) StackFrames() []errors.StackFrame {
+	frames := make([]errors.StackFrame, len(e.report.Frames))
+	for i, f := range e.report.Frames {
+		pkg, name := splitFunction(f.Function)
+		frames[i] = errors.StackFrame{File: shortFile(f.File), LineNumber: f.Line, Name: name, Package: pkg}
+	}
+	return frames
+}
+
+func (b *bugsnagNotifier) send(r *Report) error {
+	metaData := bugsnag.MetaData{"panic": {"event_id": r.EventID, "occurrences": r.Occurrences}}
+	rawData := []interface{}{
+		bugsnag.ErrorClass{Name: r.Type},
+		bugsnag.HandledState{
+			SeverityReason:   bugsnag.SeverityReasonUnhandledPanic,
+			OriginalSeverity: bugsnag.SeverityError,
+			Unhandled:        true,
+		},
+		metaData,
+	}
+	req := r.Request
+	if req != nil {
+		rawData = append(rawData, bugsnag.Context{String: req.Method + " " + req.Route})
+		metaData.Add("request", "id", req.ID)
+	}
+	rawData = append(rawData, func(event *bugsnag.Event) {
+		event.GroupingHash = r.Fingerprint
+		if req != nil {
+			event.Request = &bugsnag.RequestJSON{
+				ClientIP:   req.RemoteIP,
+				Headers:    req.Headers,
+				HTTPMethod: req.Method,
+				URL:        req.URL,
+			}
+		}
+	})
+
+	return b.notifier.NotifySync(panicError{report: r}, true, rawData...)
+}
+
+// splitFunction splits a qualified Go function name into its package path
+// and the name within the package
+func splitFunction(function string) (string, string) {
+	slash := strings.LastIndex(function, "/") + 1
+	if dot := strings.Index(function[slash:], "."); dot >= 0 {
+		return function[:slash+dot], function[slash+dot+1:]
+	}
+	return "", function
+}
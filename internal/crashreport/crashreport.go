@@ -0,0 +1,304 @@
+// Package crashreport sends recovered panics, with the request they
+// happened in, to Sentry and/or Bugsnag. Identical panics are reported once
+// per dedup window with a count, so a hot crashing route does not flood
+// the error tracker.
+package crashreport
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/idgen"
+)
+
+// inAppPrefix marks stack frames belonging to PolyGo rather than its
+// dependencies or the runtime
+const inAppPrefix = "github.com/polygo/"
+
+// maxSeen bounds the fingerprints remembered for deduplication
+const maxSeen = 1000
+
+// Request is the API request a panic happened in
+type Request struct {
+	ID        string
+	Method    string
+	URL       string
+	Route     string
+	RemoteIP  string
+	UserAgent string
+	Headers   map[string]string // secrets already removed
+}
+
+// Frame is one call in a panic's stack
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// InApp reports whether the frame is PolyGo's own code
+func (f Frame) InApp() bool {
+	return strings.HasPrefix(f.Function, inAppPrefix)
+}
+
+// Report is a recovered panic on its way to the error trackers
+type Report struct {
+	EventID     string
+	Time        time.Time
+	Type        string // the panic value's Go type
+	Message     string
+	Frames      []Frame // innermost first, starting where panic was called
+	Fingerprint string
+	Occurrences int // times the panic happened since it was last reported
+	Request     *Request
+}
+
+// notifier delivers reports to one error tracker through its SDK
+type notifier interface {
+	name() string
+	send(r *Report) error
+}
+
+// seen tracks a fingerprint within its dedup window
+type seen struct {
+	reported   time.Time
+	suppressed int
+}
+
+// Reporter queues panic reports and delivers them in the background
+type Reporter struct {
+	config    *config.ErrorReportingConfig
+	notifiers []notifier
+	hostname  string
+
+	mu   sync.Mutex
+	seen map[string]*seen
+
+	queue  chan *Report
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a reporter for the configured trackers; it reports nothing
+// when neither a Sentry DSN nor a Bugsnag API key is set
+func New(cfg *config.ErrorReportingConfig) (*Reporter, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	hostname, _ := os.Hostname()
+
+	r := &Reporter{
+		config:   cfg,
+		hostname: hostname,
+		seen:     make(map[string]*seen),
+		queue:    make(chan *Report, max(cfg.QueueSize, 1)),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	if cfg.SentryDSN != "" {
+		s, err := newSentry(cfg, hostname)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		r.notifiers = append(r.notifiers, s)
+	}
+	if cfg.BugsnagAPIKey != "" {
+		r.notifiers = append(r.notifiers, newBugsnag(cfg, hostname))
+	}
+	return r, nil
+}
+
+// Enabled reports whether any error tracker is configured
+func (r *Reporter) Enabled() bool {
+	return len(r.notifiers) > 0
+}
+
+// Start launches the delivery worker
+func (r *Reporter) Start() {
+	if !r.Enabled() {
+		return
+	}
+	r.wg.Add(1)
+	go r.worker()
+}
+
+// Stop delivers the reports still queued, then stops the worker
+func (r *Reporter) Stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// Capture records a recovered panic. It returns the report when the panic
+// is new to the dedup window, or nil when it was only counted. The report
+// is queued for delivery if any tracker is configured; a full queue drops
+// it rather than block the request.
+func (r *Reporter) Capture(value interface{}, stack []byte, req *Request) *Report {
+	report := &Report{
+		EventID: idgen.New(), // 32 hex digits, as Sentry expects
+		Time:    time.Now().UTC(),
+		Type:    fmt.Sprintf("%T", value),
+		Message: fmt.Sprint(value),
+		Frames:  ParseStack(stack),
+		Request: req,
+	}
+	report.Fingerprint = fingerprint(report)
+
+	occurrences, ok := r.dedup(report.Fingerprint, report.Time)
+	if !ok {
+		return nil
+	}
+	report.Occurrences = occurrences
+
+	if r.Enabled() {
+		select {
+		case r.queue <- report:
+		default:
+			log.Printf("Crash report queue full, dropping report %s", report.EventID)
+		}
+	}
+	return report
+}
+
+// dedup counts an occurrence of a fingerprint, reporting whether it should
+// be sent and how many occurrences the report stands for
+func (r *Reporter) dedup(key string, now time.Time) (int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	window := r.config.DedupWindow
+	if s, ok := r.seen[key]; ok && window > 0 && now.Sub(s.reported) < window {
+		s.suppressed++
+		return 0, false
+	}
+
+	if len(r.seen) >= maxSeen {
+		for k, s := range r.seen {
+			if now.Sub(s.reported) >= window {
+				delete(r.seen, k)
+			}
+		}
+	}
+
+	occurrences := 1
+	if s, ok := r.seen[key]; ok {
+		occurrences += s.suppressed
+	}
+	r.seen[key] = &seen{reported: now}
+	return occurrences, true
+}
+
+func (r *Reporter) worker() {
+	defer r.wg.Done()
+
+	for {
+		select {
+		case report := <-r.queue:
+			r.deliver(report)
+		case <-r.ctx.Done():
+			for {
+				select {
+				case report := <-r.queue:
+					r.deliver(report)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// deliver sends a report to every configured tracker
+func (r *Reporter) deliver(report *Report) {
+	for _, n := range r.notifiers {
+		if err := n.send(report); err != nil {
+			log.Printf("Failed to send crash report %s to %s: %v", report.EventID, n.name(), err)
+		}
+	}
+}
+
+// ParseStack reads the frames of a runtime/debug.Stack trace from the call
+// to panic outward. Traces without a panic frame are returned whole.
+func ParseStack(stack []byte) []Frame {
+	var frames []Frame
+	lines := strings.Split(string(stack), "\n")
+	for i := 1; i+1 < len(lines); i++ {
+		fn := lines[i]
+		loc := lines[i+1]
+		if fn == "" || strings.HasPrefix(fn, "\t") || !strings.HasPrefix(loc, "\t") {
+			continue
+		}
+		i++
+
+		// "\t/path/to/file.go:123 +0x1d"
+		loc = strings.TrimPrefix(loc, "\t")
+		if sp := strings.LastIndexByte(loc, ' '); sp > 0 {
+			loc = loc[:sp]
+		}
+		file, line := loc, 0
+		if colon := strings.LastIndexByte(loc, ':'); colon > 0 {
+			file = loc[:colon]
+			line, _ = strconv.Atoi(loc[colon+1:])
+		}
+		// "pkg.Func(0x1, ...)" or "created by pkg.Func in goroutine 1"
+		fn = strings.TrimPrefix(fn, "created by ")
+		if paren := strings.LastIndexByte(fn, '('); paren > 0 && strings.HasSuffix(fn, ")") {
+			fn = fn[:paren]
+		}
+		if sp := strings.Index(fn, " in goroutine"); sp > 0 {
+			fn = fn[:sp]
+		}
+		frames = append(frames, Frame{Function: fn, File: file, Line: line})
+	}
+
+	// Drop the frames of debug.Stack and the recovering code
+	for i, f := range frames {
+		if f.Function == "panic" {
+			rest := frames[i+1:]
+			for len(rest) > 1 && strings.HasPrefix(rest[0].Function, "runtime.") {
+				rest = rest[1:]
+			}
+			return rest
+		}
+	}
+	return frames
+}
+
+// digits are masked in fingerprints so panics differing only in indexes
+// or IDs (e.g. "index out of range [5] with length 3") count as one
+var digits = regexp.MustCompile(`[0-9]+`)
+
+// fingerprint identifies a panic by its type, message and where it happened
+func fingerprint(r *Report) string {
+	location := ""
+	for _, f := range r.Frames {
+		location = f.Function
+		if f.InApp() {
+			break
+		}
+	}
+	return r.Type + "|" + digits.ReplaceAllString(r.Message, "N") + "|" + location
+}
+
+// sensitiveHeaders are left out of reported requests
+var sensitiveHeaders = []string{"authorization", "cookie", "secret", "passphrase", "signature", "key", "token"}
+
+// SafeHeader reports whether a request header may be sent to an error
+// tracker
+func SafeHeader(name string) bool {
+	lower := strings.ToLower(name)
+	for _, s := range sensitiveHeaders {
+		if strings.Contains(lower, s) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package crashreport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/polygo/internal/config"
+)
+
+// sentryNotifier sends reports to Sentry with sentry-go
+type sentryNotifier struct {
+	client *sentry.Client
+}
+
+// newSentry creates a Sentry client for a DSN of the form
+// https://<key>@<host>[/<path>]/<project>
+func newSentry(cfg *config.ErrorReportingConfig, hostname string) (*sentryNotifier, error) {
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		Dsn:         cfg.SentryDSN,
+		Environment: cfg.Environment,
+		Release:     cfg.Release,
+		ServerName:  hostname,
+		HTTPClient:  &http.Client{Timeout: cfg.Timeout},
+		// The Reporter's worker already delivers in the background
+		Transport: sentry.NewHTTPSyncTransport(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid Sentry DSN: %w", err)
+	}
+	return &sentryNotifier{client: client}, nil
+}
+
+func (s *sentryNotifier) name() string {
+	return "Sentry"
+}
+
+func (s *sentryNotifier) send(r *Report) error {
+	// Sentry lists frames outermost first
+	frames := make([]sentry.Frame, len(r.Frames))
+	for i, f := range r.Frames {
+		frames[len(frames)-1-i] = sentry.Frame{
+			Function: f.Function,
+			Filename: shortFile(f.File),
+			AbsPath:  f.File,
+			Lineno:   f.Line,
+			InApp:    f.InApp(),
+		}
+	}
+
+	handled := false
+	event := sentry.NewEvent()
+	event.EventID = sentry.EventID(r.EventID)
+	event.Timestamp = r.Time
+	event.Level = sentry.LevelError
+	event.Logger = "polygo.recovery"
+	event.Exception = []sentry.Exception{{
+		Type:       r.Type,
+		Value:      r.Message,
+		Mechanism:  &sentry.Mechanism{Type: "recovery", Handled: &handled},
+		Stacktrace: &sentry.Stacktrace{Frames: frames},
+	}}
+	event.Fingerprint = []string{r.Fingerprint}
+	event.Extra["occurrences"] = r.Occurrences
+	if req := r.Request; req != nil {
+		event.Request = &sentry.Request{
+			Method:  req.Method,
+			URL:     req.URL,
+			Headers: req.Headers,
+			Env:     map[string]string{"REMOTE_ADDR": req.RemoteIP},
+		}
+		event.Tags["request_id"] = req.ID
+		event.Tags["route"] = req.Route
+		event.Transaction = req.Method + " " + req.Route
+	}
+
+	if s.client.CaptureEvent(event, nil, nil) == nil {
+		return errors.New("event dropped by the Sentry client")
+	}
+	return nil
+}
+
+// shortFile trims a source path to the part after the module or GOPATH
+// root, as trackers group frames by it
+func shortFile(file string) string {
+	for _, marker := range []string{"/pkg/mod/", "/src/"} {
+		if i := strings.LastIndex(file, marker); i >= 0 {
+			return file[i+len(marker):]
+		}
+	}
+	return file
+}
@@ -0,0 +1,263 @@
+// Package deadman implements a dead-man's switch: a client arms it on its
+// /ws/events connection and, should that connection drop or go without a
+// heartbeat for the armed timeout, every open order of its API key is
+// cancelled, as exchanges do with cancel-on-disconnect.
+package deadman
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/webhooks"
+	"github.com/polygo/pkg/polygoclient"
+)
+
+// EventTriggered is the event published when a switch cancels its
+// owner's orders
+const EventTriggered = "order.dead_man"
+
+// Why a switch was triggered
+const (
+	ReasonDisconnect = "disconnect"
+	ReasonHeartbeat  = "heartbeat_timeout"
+)
+
+var (
+	// ErrDisabled is returned when arming while the switch is turned off
+	ErrDisabled = errors.New("the dead-man's switch is disabled")
+	// ErrNoSecret is returned when arming a connection made without the API
+	// secret, which cancelling needs
+	ErrNoSecret = errors.New("arming needs the API secret header on the connection")
+	// ErrTimeout is returned for a timeout outside MinTimeout and MaxTimeout
+	ErrTimeout = errors.New("timeout out of range")
+)
+
+// Status is a session's switch as reported to its client
+type Status struct {
+	Armed    bool       `json:"armed"`
+	Timeout  float64    `json:"timeout,omitempty"`  // seconds
+	Deadline *time.Time `json:"deadline,omitempty"` // when the switch fires without another heartbeat
+}
+
+// Notice is the payload of an EventTriggered event
+type Notice struct {
+	Reason       string            `json:"reason"` // disconnect or heartbeat_timeout
+	ArmedAt      time.Time         `json:"armed_at"`
+	TriggeredAt  time.Time         `json:"triggered_at"`
+	Cancelled    []string          `json:"cancelled"`
+	NotCancelled map[string]string `json:"not_cancelled,omitempty"`
+	Error        string            `json:"error,omitempty"` // why the cancellation failed
+}
+
+// Switches tracks the sessions of connected clients
+type Switches struct {
+	clob       *polymarket.ClobClient
+	dispatcher *webhooks.Dispatcher
+	auth       *config.AuthConfig
+	config     *config.DeadManConfig
+
+	mu       sync.Mutex
+	sessions map[*Session]bool
+	stopped  bool
+}
+
+// New creates a new set of switches
+func New(clob *polymarket.ClobClient, dispatcher *webhooks.Dispatcher, auth *config.AuthConfig, cfg *config.DeadManConfig) *Switches {
+	return &Switches{
+		clob:       clob,
+		dispatcher: dispatcher,
+		auth:       auth,
+		config:     cfg,
+		sessions:   make(map[*Session]bool),
+	}
+}
+
+// Session starts the switch of one connection, disarmed; creds sign the
+// cancellation
+func (s *Switches) Session(owner string, creds *polygoclient.Credentials) *Session {
+	x := &Session{switches: s, owner: owner, creds: creds}
+	s.mu.Lock()
+	s.sessions[x] = true
+	s.mu.Unlock()
+	return x
+}
+
+// Stop disarms every session without cancelling anything, so that
+// connections closed by a shutdown do not trigger their switches
+func (s *Switches) Stop() {
+	s.mu.Lock()
+	s.stopped = true
+	sessions := s.sessions
+	s.sessions = make(map[*Session]bool)
+	s.mu.Unlock()
+
+	for x := range sessions {
+		x.Disarm()
+	}
+}
+
+func (s *Switches) isStopped() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stopped
+}
+
+// Session is the switch of one connection
+type Session struct {
+	switches *Switches
+	owner    string
+	creds    *polygoclient.Credentials
+
+	mu       sync.Mutex
+	timeout  time.Duration // 0 while disarmed
+	armedAt  time.Time
+	deadline time.Time
+	timer    *time.Timer
+	gen      int // invalidates the timers of earlier arms and heartbeats
+}
+
+// Arm arms the switch, or re-arms it with a new timeout: without a
+// heartbeat within timeout, or when the connection drops, the owner's
+// open orders are cancelled
+func (x *Session) Arm(timeout time.Duration) (Status, error) {
+	cfg := x.switches.config
+	switch {
+	case !cfg.Enabled || x.switches.isStopped():
+		return Status{}, ErrDisabled
+	case x.creds == nil || x.creds.Secret == "":
+		return Status{}, ErrNoSecret
+	case timeout < cfg.MinTimeout || timeout > cfg.MaxTimeout:
+		return Status{}, fmt.Errorf("%w: use %s to %s", ErrTimeout, cfg.MinTimeout, cfg.MaxTimeout)
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.timeout == 0 {
+		x.armedAt = time.Now()
+	}
+	x.timeout = timeout
+	x.resetLocked()
+	return x.statusLocked(), nil
+}
+
+// Heartbeat postpones the switch by its timeout; it reports whether the
+// switch is armed
+func (x *Session) Heartbeat() (Status, bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.timeout == 0 {
+		return x.statusLocked(), false
+	}
+	x.resetLocked()
+	return x.statusLocked(), true
+}
+
+// Disarm disarms the switch; the connection can then close safely
+func (x *Session) Disarm() Status {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.disarmLocked()
+	return x.statusLocked()
+}
+
+// Status returns the state of the switch
+func (x *Session) Status() Status {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.statusLocked()
+}
+
+// Close ends the session as its connection drops, firing the switch if it
+// is armed
+func (x *Session) Close() {
+	s := x.switches
+	s.mu.Lock()
+	delete(s.sessions, x)
+	stopped := s.stopped
+	s.mu.Unlock()
+
+	x.mu.Lock()
+	armed := x.timeout > 0
+	armedAt := x.armedAt
+	x.disarmLocked()
+	x.mu.Unlock()
+
+	if armed && !stopped {
+		x.fire(ReasonDisconnect, armedAt)
+	}
+}
+
+// resetLocked restarts the heartbeat timer. Caller holds x.mu.
+func (x *Session) resetLocked() {
+	if x.timer != nil {
+		x.timer.Stop()
+	}
+	x.gen++
+	x.deadline = time.Now().Add(x.timeout)
+	gen, armedAt := x.gen, x.armedAt
+	x.timer = time.AfterFunc(x.timeout, func() {
+		x.mu.Lock()
+		if x.gen != gen || x.timeout == 0 {
+			x.mu.Unlock()
+			return
+		}
+		x.disarmLocked()
+		x.mu.Unlock()
+
+		x.fire(ReasonHeartbeat, armedAt)
+	})
+}
+
+// disarmLocked stops the timer. Caller holds x.mu.
+func (x *Session) disarmLocked() {
+	if x.timer != nil {
+		x.timer.Stop()
+		x.timer = nil
+	}
+	x.gen++
+	x.timeout = 0
+	x.armedAt = time.Time{}
+}
+
+func (x *Session) statusLocked() Status {
+	if x.timeout == 0 {
+		return Status{}
+	}
+	deadline := x.deadline
+	return Status{Armed: true, Timeout: x.timeout.Seconds(), Deadline: &deadline}
+}
+
+// fire cancels the owner's open orders and publishes what happened, to
+// webhooks and to the owner's connections
+func (x *Session) fire(reason string, armedAt time.Time) {
+	s := x.switches
+	notice := Notice{Reason: reason, ArmedAt: armedAt, TriggeredAt: time.Now(), Cancelled: []string{}}
+
+	headers := polymarket.SignedHeaders(s.auth, x.creds, "DELETE", "/cancel-all", nil)
+	data, err := s.clob.CancelOpenOrders(headers)
+	if err != nil {
+		notice.Error = err.Error()
+		log.Printf("Dead-man's switch failed to cancel orders (%s): %v", reason, err)
+	} else {
+		var result models.CancelResult
+		if err := sonic.Unmarshal(data, &result); err == nil {
+			if result.Canceled != nil {
+				notice.Cancelled = result.Canceled
+			}
+			if len(result.NotCanceled) > 0 {
+				notice.NotCancelled = result.NotCanceled
+			}
+		}
+	}
+
+	if s.dispatcher != nil {
+		s.dispatcher.Publish(EventTriggered, "", x.owner, notice)
+	}
+}
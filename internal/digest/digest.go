@@ -0,0 +1,157 @@
+package digest
+
+import (
+	"sort"
+	"time"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/recorder"
+)
+
+// period is the span a daily digest covers, both back and ahead
+const period = 24 * time.Hour
+
+// minSpikeVolume is the 24h volume (USDC) below which a market is too thin
+// for its volume to count as a spike
+const minSpikeVolume = 1000
+
+// Market is a catalog market as a digest lists it
+type Market struct {
+	MarketID   string    `json:"market_id"`
+	Question   string    `json:"question"`
+	Slug       string    `json:"slug"`
+	EventSlug  string    `json:"event_slug,omitempty"`
+	Category   string    `json:"category"`
+	Price      float64   `json:"price"` // first outcome
+	Volume24hr float64   `json:"volume_24hr"`
+	EndDate    time.Time `json:"end_date,omitempty"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+}
+
+// Spike is a market trading well above its recent daily volume
+type Spike struct {
+	Market
+	AvgDailyVolume float64 `json:"avg_daily_volume"` // over the last 7 days
+	Ratio          float64 `json:"ratio"`            // 24h volume over the daily average
+}
+
+// Digest summarizes the last day of market activity
+type Digest struct {
+	GeneratedAt  time.Time        `json:"generated_at"`
+	Swings       []recorder.Mover `json:"swings"`        // biggest probability moves over 24h
+	NewMarkets   []Market         `json:"new_markets"`   // created in the last 24h, busiest first
+	Resolving    []Market         `json:"resolving"`     // ending within 24h, soonest first
+	VolumeSpikes []Spike          `json:"volume_spikes"` // highest ratio first
+}
+
+// Builder computes the daily digest from the catalog and the price
+// recorder, caching it for TTL
+type Builder struct {
+	catalog  *catalog.Catalog
+	recorder *recorder.Recorder
+	cache    *cache.Cache
+	config   *config.DigestConfig
+}
+
+// NewBuilder creates a new digest builder
+func NewBuilder(cat *catalog.Catalog, rec *recorder.Recorder, c *cache.Cache, cfg *config.DigestConfig) *Builder {
+	return &Builder{catalog: cat, recorder: rec, cache: c, config: cfg}
+}
+
+// Daily returns the current daily digest. The bool reports a cache hit.
+func (b *Builder) Daily() (*Digest, bool) {
+	key := cache.DigestKey("daily")
+
+	var cached Digest
+	if b.cache.GetJSON(key, &cached) {
+		return &cached, true
+	}
+
+	d := b.Build(time.Now())
+	b.cache.SetJSON(key, d, b.config.TTL)
+	return d, false
+}
+
+// Build computes a digest as of now
+func (b *Builder) Build(now time.Time) *Digest {
+	d := &Digest{
+		GeneratedAt:  now,
+		Swings:       []recorder.Mover{},
+		NewMarkets:   []Market{},
+		Resolving:    []Market{},
+		VolumeSpikes: []Spike{},
+	}
+	if b.recorder != nil && b.recorder.Ready() {
+		d.Swings = b.recorder.Movers(period, recorder.SortAbsolute, b.config.Limit)
+	}
+
+	for _, e := range b.catalog.Markets() {
+		m := summarize(e)
+		if !e.CreatedAt.IsZero() && now.Sub(e.CreatedAt) <= period {
+			d.NewMarkets = append(d.NewMarkets, m)
+		}
+		if e.EndDate.After(now) && e.EndDate.Sub(now) <= period {
+			d.Resolving = append(d.Resolving, m)
+		}
+		if s, ok := b.spike(e, m); ok {
+			d.VolumeSpikes = append(d.VolumeSpikes, s)
+		}
+	}
+
+	sort.SliceStable(d.NewMarkets, func(i, j int) bool {
+		return d.NewMarkets[i].Volume24hr > d.NewMarkets[j].Volume24hr
+	})
+	sort.SliceStable(d.Resolving, func(i, j int) bool {
+		return d.Resolving[i].EndDate.Before(d.Resolving[j].EndDate)
+	})
+	sort.SliceStable(d.VolumeSpikes, func(i, j int) bool {
+		return d.VolumeSpikes[i].Ratio > d.VolumeSpikes[j].Ratio
+	})
+
+	d.NewMarkets = truncate(d.NewMarkets, b.config.Limit)
+	d.Resolving = truncate(d.Resolving, b.config.Limit)
+	d.VolumeSpikes = truncate(d.VolumeSpikes, b.config.Limit)
+	return d
+}
+
+// spike reports whether a market's 24h volume is at least SpikeRatio times
+// its average daily volume over the last week
+func (b *Builder) spike(e *catalog.Entry, m Market) (Spike, bool) {
+	avg := e.Volume1wk.Float() / 7
+	if avg <= 0 || m.Volume24hr < minSpikeVolume {
+		return Spike{}, false
+	}
+	ratio := m.Volume24hr / avg
+	if ratio < b.config.SpikeRatio {
+		return Spike{}, false
+	}
+	return Spike{Market: m, AvgDailyVolume: avg, Ratio: ratio}, true
+}
+
+// summarize reduces a catalog entry to its digest listing
+func summarize(e *catalog.Entry) Market {
+	m := Market{
+		MarketID:   e.ID,
+		Question:   e.Question,
+		Slug:       e.Slug,
+		EventSlug:  e.EventSlug,
+		Category:   e.Category,
+		Volume24hr: e.Volume24hr.Float(),
+		EndDate:    e.EndDate,
+		CreatedAt:  e.CreatedAt,
+	}
+	if prices := e.OutcomePrices.Floats(); len(prices) > 0 {
+		m.Price = prices[0]
+	}
+	return m
+}
+
+// truncate keeps the first limit items (all of them when limit <= 0)
+func truncate[T any](items []T, limit int) []T {
+	if limit > 0 && len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
@@ -1,255 +1,13023 @@
-// Package docs contains swagger documentation
+// Code generated by cmd/docgen. DO NOT EDIT.
+
+// Package docs holds the Swagger spec generated from the handlers'
+// annotations
 package docs
 
 import "github.com/swaggo/swag"
 
 const docTemplate = `{
-  "swagger": "2.0",
-  "info": {
-    "title": "PolyGo API",
-    "description": "High-performance Polymarket API proxy with caching and WebSocket support",
-    "version": "1.0",
-    "contact": {
-      "name": "API Support",
-      "email": "support@polygo.dev"
-    },
-    "license": {
-      "name": "MIT",
-      "url": "https://opensource.org/licenses/MIT"
-    }
-  },
-  "host": "{{.Host}}",
-  "basePath": "{{.BasePath}}",
-  "schemes": ["http", "https"],
-  "securityDefinitions": {
-    "ApiKeyAuth": {
-      "type": "apiKey",
-      "in": "header",
-      "name": "POLY-API-KEY"
-    }
-  },
-  "paths": {
-    "/health": {
-      "get": {
-        "tags": ["Health"],
-        "summary": "Health check",
-        "description": "Check if the server is running",
-        "produces": ["application/json"],
-        "responses": {
-          "200": {
-            "description": "Server is healthy"
-          }
-        }
-      }
-    },
-    "/api/v1/markets": {
-      "get": {
-        "tags": ["Markets"],
-        "summary": "List all markets",
-        "description": "Get a list of markets with optional filtering",
-        "produces": ["application/json"],
-        "parameters": [
-          {"name": "limit", "in": "query", "type": "integer", "default": 100},
-          {"name": "cursor", "in": "query", "type": "string"},
-          {"name": "active", "in": "query", "type": "boolean"},
-          {"name": "closed", "in": "query", "type": "boolean"}
-        ],
-        "responses": {
-          "200": {"description": "List of markets"}
-        }
-      }
-    },
-    "/api/v1/markets/{id}": {
-      "get": {
-        "tags": ["Markets"],
-        "summary": "Get market by ID",
-        "produces": ["application/json"],
-        "parameters": [
-          {"name": "id", "in": "path", "required": true, "type": "string"}
-        ],
-        "responses": {
-          "200": {"description": "Market details"},
-          "404": {"description": "Market not found"}
-        }
-      }
-    },
-    "/api/v1/events": {
-      "get": {
-        "tags": ["Events"],
-        "summary": "List all events",
-        "produces": ["application/json"],
-        "parameters": [
-          {"name": "limit", "in": "query", "type": "integer", "default": 100},
-          {"name": "cursor", "in": "query", "type": "string"},
-          {"name": "active", "in": "query", "type": "boolean"}
-        ],
-        "responses": {
-          "200": {"description": "List of events"}
-        }
-      }
-    },
-    "/api/v1/events/{id}": {
-      "get": {
-        "tags": ["Events"],
-        "summary": "Get event by ID",
-        "produces": ["application/json"],
-        "parameters": [
-          {"name": "id", "in": "path", "required": true, "type": "string"}
-        ],
-        "responses": {
-          "200": {"description": "Event details"},
-          "404": {"description": "Event not found"}
-        }
-      }
-    },
-    "/api/v1/price/{token_id}": {
-      "get": {
-        "tags": ["Prices"],
-        "summary": "Get current price",
-        "produces": ["application/json"],
-        "parameters": [
-          {"name": "token_id", "in": "path", "required": true, "type": "string"},
-          {"name": "side", "in": "query", "type": "string", "default": "BUY"}
-        ],
-        "responses": {
-          "200": {"description": "Current price"}
-        }
-      }
-    },
-    "/api/v1/book/{token_id}": {
-      "get": {
-        "tags": ["Prices"],
-        "summary": "Get order book",
-        "produces": ["application/json"],
-        "parameters": [
-          {"name": "token_id", "in": "path", "required": true, "type": "string"}
-        ],
-        "responses": {
-          "200": {"description": "Order book"}
-        }
-      }
-    },
-    "/api/v1/spread/{token_id}": {
-      "get": {
-        "tags": ["Prices"],
-        "summary": "Get spread",
-        "produces": ["application/json"],
-        "parameters": [
-          {"name": "token_id", "in": "path", "required": true, "type": "string"}
-        ],
-        "responses": {
-          "200": {"description": "Spread info"}
-        }
-      }
-    },
-    "/api/v1/orders": {
-      "get": {
-        "tags": ["Orders"],
-        "summary": "Get user orders",
-        "security": [{"ApiKeyAuth": []}],
-        "produces": ["application/json"],
-        "responses": {
-          "200": {"description": "List of orders"},
-          "401": {"description": "Unauthorized"}
-        }
-      },
-      "post": {
-        "tags": ["Orders"],
-        "summary": "Create order",
-        "security": [{"ApiKeyAuth": []}],
-        "consumes": ["application/json"],
-        "produces": ["application/json"],
-        "parameters": [
-          {
-            "name": "order",
-            "in": "body",
-            "required": true,
-            "schema": {
-              "type": "object",
-              "properties": {
-                "tokenID": {"type": "string"},
-                "side": {"type": "string", "enum": ["BUY", "SELL"]},
-                "price": {"type": "string"},
-                "size": {"type": "string"}
-              }
-            }
-          }
-        ],
-        "responses": {
-          "200": {"description": "Order created"},
-          "401": {"description": "Unauthorized"}
-        }
-      }
+    {{ if .Schemes }}"schemes": {{ marshal .Schemes }},{{ end }}
+    "swagger": "2.0",
+    "info": {
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
+        "termsOfService": "http://swagger.io/terms/",
+        "contact": {
+            "name": "API Support",
+            "email": "support@polygo.dev"
+        },
+        "license": {
+            "name": "MIT",
+            "url": "https://opensource.org/licenses/MIT"
+        },
+        "version": "{{.Version}}"
     },
-    "/api/v1/orders/{id}": {
-      "delete": {
-        "tags": ["Orders"],
-        "summary": "Cancel order",
-        "security": [{"ApiKeyAuth": []}],
-        "parameters": [
-          {"name": "id", "in": "path", "required": true, "type": "string"}
-        ],
-        "responses": {
-          "200": {"description": "Order cancelled"},
-          "401": {"description": "Unauthorized"}
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
+    "paths": {
+        "/admin/audit": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "List recorded actions newest first: orders placed and cancelled, dead-man's switches armed and disarmed, webhooks, watchlists and alerts changed, and admin changes, each with the API key (or admin) that made it, the client IP, the HTTP status and the SHA-256 of the request body. Only the latest audit.keep entries are searched; export for older ones.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Search the audit log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key, or admin",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action, e.g. order.cancel, or family, e.g. order.*",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 time, inclusive",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 time, exclusive",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/audit.Entry"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/audit/export": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Download every recorded action matching the filters, oldest first, as JSON lines or CSV. Reads the whole audit file, including entries too old to be searched.",
+                "produces": [
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Export the audit log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key, or admin",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action, e.g. order.cancel, or family, e.g. order.*",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 time, inclusive",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 time, exclusive",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "jsonl",
+                        "description": "jsonl or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/cache": {
+            "delete": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Drop one cache entry by key, or the whole cache when no key is given",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Purge response cache",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cache key to drop",
+                        "name": "key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/config/effective": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Get the redacted running configuration and warnings about risky settings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Effective configuration",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/config.Effective"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/drift": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Get fields that appeared in or disappeared from upstream payloads compared to the models, per endpoint. Empty unless polymarket.validation is warn or strict.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Upstream schema drift",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/polymarket.DriftReport"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/exports": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "List scheduled export jobs with their next and last run",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List export jobs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/export.Job"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Schedule a periodic export of a dataset (trades for an address, recorded candles for tokens, or the full market catalog) as CSV or Parquet under the export directory of the configured storage (local disk or a bucket). The first run is due immediately unless paused.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Create an export job",
+                "parameters": [
+                    {
+                        "description": "Job",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/export.Spec"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/export.Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/exports/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get an export job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/export.Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Replace a job's spec. The next run is rescheduled from the last one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Update an export job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Job",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/export.Spec"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/export.Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Remove a job and its run history. Files already exported are kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete an export job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/exports/{id}/run": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Run a job immediately, even if paused, and wait for it to finish. A failed export is still a 200; see the run's status and error.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Run an export job now",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/export.Run"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/exports/{id}/runs": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Get a job's recent runs, newest first, with row counts, sizes, where each file was written and any error",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List an export job's runs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/export.Run"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Place an order for one of the configured routing accounts, signed server-side. The first route matching the order's market and strategy tag picks the account, round-robin among its accounts and skipping those whose open orders plus this one would exceed max_exposure USDC. The order's maker is the account's address. The order passes the order rules, duplicate guard and risk limits of /orders for the account picked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Place a routed order",
+                "parameters": [
+                    {
+                        "description": "Order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/routing.Placement"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/risk/limits": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Get the default pre-trade limits and per-account overrides, keyed by API key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get risk limits",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/risk.Limits"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/risk/limits/default": {
+            "put": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Replace the limits of accounts without their own. Zero disables a limit.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Set default risk limits",
+                "parameters": [
+                    {
+                        "description": "Limits",
+                        "name": "limits",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/config.RiskLimits"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/config.RiskLimits"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/risk/limits/{account}": {
+            "put": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Replace an account's limits; they apply instead of the default ones. Zero disables a limit.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Set an account's risk limits",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account API key",
+                        "name": "account",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Limits",
+                        "name": "limits",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/config.RiskLimits"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/config.RiskLimits"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Return an account to the default limits",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Remove an account's risk limits",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account API key",
+                        "name": "account",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/routing": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "List the routes and the accounts orders are routed to, with how many orders each took and how often it was skipped at its exposure limit",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get order routing",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/routing.Status"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/rules": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "List rule engine rules with how often each fired",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/rules.Rule"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Add a rule: a condition over upstream market events (e.g. volume_24h \u003e 1e6 AND price_change_5m \u003e 0.05) and the actions run when an event matches it (webhook, publish, log or order). A rule fires at most once per market per cooldown.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Create a rule",
+                "parameters": [
+                    {
+                        "description": "Rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/config.RuleSpec"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/rules.Rule"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/rules/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get a rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rule name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/rules.Rule"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Replace a rule's condition, actions and cooldown. Its cooldowns restart; its counts and triggers are kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Update a rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rule name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/config.RuleSpec"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/rules.Rule"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Remove a rule and its trigger history",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete a rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rule name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/rules/{name}/triggers": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Get a rule's recent triggers, newest first, with the field values that matched and what each action did",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List a rule's triggers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rule name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/rules.Trigger"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tenants": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "List the configured tenants with their rate limit and how many API keys belong to each (the keys are not shown)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List tenants",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/tenant.Tenant"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tenants/{id}/usage": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Get a tenant's requests, errors, rate-limited requests and bytes per UTC day, with totals and requests per route. Covers the last 30 days unless from/to are given.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get a tenant's usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day, YYYY-MM-DD (UTC)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, YYYY-MM-DD (UTC); default today",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/tenant.Report"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/upstream/pool": {
+            "put": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Change at runtime how many connections are opened to an upstream host, or to every host without a limit of its own, e.g. when Polymarket throttles concurrency. A lowered limit stops new connections being opened; those already open above it keep serving until idle past max_idle_conn_dur. Lasts until restart.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Tune upstream connections",
+                "parameters": [
+                    {
+                        "description": "Limit",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpstreamPoolRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/polymarket.PoolStats"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/ws/stats": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Get the market stream connections with what was sent to each, the clients and traffic of each subscribed market or token, buffer evictions and the upstream lag and drops, computed when requested",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "WebSocket stream statistics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Most subscriptions and clients listed, busiest first",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.WSStatsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/activity": {
+            "get": {
+                "description": "Get activity log for a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Data"
+                ],
+                "summary": "Get user activity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User wallet address",
+                        "name": "address",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Send no-cache to bypass cached data",
+                        "name": "Cache-Control",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pagination cursor (next_cursor or offset)",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Activity"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/alerts/positions": {
+            "get": {
+                "description": "List the wallets whose positions the caller watches",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alerts"
+                ],
+                "summary": "List position alerts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/posalert.Watch"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Poll a wallet's positions and publish position.opened, position.increased, position.decreased and position.closed to the caller's webhooks and /ws/events when a position changes by at least min_size shares and min_value USDC",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alerts"
+                ],
+                "summary": "Watch a wallet's positions",
+                "parameters": [
+                    {
+                        "description": "Wallet and thresholds",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PositionAlertRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/posalert.Watch"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/alerts/positions/{id}": {
+            "get": {
+                "description": "Get one of the caller's position alerts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alerts"
+                ],
+                "summary": "Get a position alert",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Position alert ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/posalert.Watch"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the label and thresholds of one of the caller's position alerts; the address cannot be changed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alerts"
+                ],
+                "summary": "Update a position alert",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Position alert ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Label and thresholds",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PositionAlertRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/posalert.Watch"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Stop watching a wallet's positions for the caller",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alerts"
+                ],
+                "summary": "Delete a position alert",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Position alert ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/analytics/correlation": {
+            "get": {
+                "description": "Pearson correlation of the price changes of several tokens over a trailing window, computed from locally recorded prices. Pairs with fewer than 3 shared changes, or a token that never moved, are null.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Price correlation matrix",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated token IDs (2 to 20)",
+                        "name": "token_ids",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "24h",
+                        "description": "Trailing window (e.g. 1h, 24h)",
+                        "name": "window",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/analytics.CorrelationMatrix"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/analytics/markets/top": {
+            "get": {
+                "description": "Rank catalog markets by 24h volume, liquidity, spread tightness or 24h trade count",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Top markets leaderboard",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "volume",
+                        "description": "Ranking metric: volume, liquidity, spread or trades",
+                        "name": "by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Normalized category (politics, sports, crypto, ...)",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event tag slug",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Limit results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pagination cursor (next_cursor or offset)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/analytics.RankedMarket"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/analytics/strategies": {
+            "get": {
+                "description": "Report, per strategy, the caller's orders placed with a strategy tag: how many filled and how much of their size, the USDC volume filled, PnL realized at average cost, and the open positions priced at current midpoints. Fills come from the CLOB user channel (see POLYGO_FILL_NOTIFY_*); orders matched as they were placed count as filled at their limit price.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Get per-strategy statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only this strategy",
+                        "name": "strategy",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/strategies.Stats"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/book/{token_id}": {
+            "get": {
+                "description": "Get the full order book for a token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-protobuf"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get order book",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID",
+                        "name": "token_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price format: probability (default), cents or american",
+                        "name": "as",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Round prices to the token's tick size (tick) or not (none); defaults to the server setting",
+                        "name": "round",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.OrderBook"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/books": {
+            "get": {
+                "description": "Get order books for multiple tokens at once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-protobuf"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get order books for multiple tokens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated token IDs",
+                        "name": "token_ids",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price format: probability (default), cents or american",
+                        "name": "as",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Round prices to the token's tick size (tick) or not (none); defaults to the server setting",
+                        "name": "round",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.OrderBook"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/calc/payout": {
+            "get": {
+                "description": "Price a hypothetical order with the token's current tick size, neg-risk flag and fee rate: fee, net cost or proceeds, max payout, profit and loss, and breakeven probability",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Calculate order payout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID",
+                        "name": "token_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order side (BUY/SELL)",
+                        "name": "side",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Limit price (0-1, on the token's tick)",
+                        "name": "price",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Size in shares",
+                        "name": "size",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Price as a resting (maker) order, which pays no fee",
+                        "name": "maker",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/polymarket.PayoutQuote"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/categories": {
+            "get": {
+                "description": "List normalized categories with catalog market counts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Markets"
+                ],
+                "summary": "List market categories",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handlers.CategoryCount"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/copytrade/start": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Follow a target wallet and mirror its new trades as orders for the configured local account, scaled and capped by the given or configured limits",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Copy Trading"
+                ],
+                "summary": "Start copy trading",
+                "parameters": [
+                    {
+                        "description": "Target wallet and limits",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/copytrade.Options"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/copytrade.Status"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/copytrade/status": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Get the current or last session: target, limits, exposure, counts and the most recent mirrored trades with their outcome",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Copy Trading"
+                ],
+                "summary": "Copy trading status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/copytrade.Status"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/copytrade/stop": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAuth": []
+                    }
+                ],
+                "description": "Stop following the target wallet. Orders already placed are left as they are.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Copy Trading"
+                ],
+                "summary": "Stop copy trading",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/copytrade.Status"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/digest/daily": {
+            "get": {
+                "description": "Summary of the last day: biggest probability swings, markets created in the last 24h, markets ending within 24h and volume spikes against the 7-day daily average. Computed from the local catalog and price recorder and cached for POLYGO_DIGEST_TTL (an hour by default).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Daily movers digest",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/digest.Digest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events": {
+            "get": {
+                "description": "Get a list of events with optional filtering",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "List all events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pagination cursor (next_cursor or offset)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by active status",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by closed status",
+                        "name": "closed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by archived status",
+                        "name": "archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by slug",
+                        "name": "slug",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Auto-paginate through every page",
+                        "name": "all",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1000,
+                        "description": "Maximum items when auto-paginating",
+                        "name": "max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Parse stringified lists and numbers and use camelCase keys",
+                        "name": "normalize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Event"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/grouped": {
+            "get": {
+                "description": "List the catalog's active events bucketed by tag, with each bucket's event count, total volume and top events by volume, ordered by volume",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Active events grouped by tag",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Top events per tag",
+                        "name": "top",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Limit tags",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pagination cursor (next_cursor or offset)",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/catalog.TagGroup"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/search": {
+            "get": {
+                "description": "Search events by query string",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Search events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limit results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Parse stringified lists and numbers and use camelCase keys",
+                        "name": "normalize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Event"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/slug/{slug}": {
+            "get": {
+                "description": "Get event by its URL slug",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get event by slug",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Parse stringified lists and numbers and use camelCase keys",
+                        "name": "normalize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Event"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}": {
+            "get": {
+                "description": "Get detailed information about a specific event including its markets",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get event by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Parse stringified lists and numbers and use camelCase keys",
+                        "name": "normalize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Event"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/last-trade/{token_id}": {
+            "get": {
+                "description": "Get the last trade price for a token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get last trade price",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID",
+                        "name": "token_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price format: probability (default), cents or american",
+                        "name": "as",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Round prices to the token's tick size (tick) or not (none); defaults to the server setting",
+                        "name": "round",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/leaderboard": {
+            "get": {
+                "description": "Get the top traders leaderboard",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Data"
+                ],
+                "summary": "Get trading leaderboard",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit results",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/leaderboard/history": {
+            "get": {
+                "description": "Get the traders on the latest leaderboard snapshot with their rank change and PnL trajectory over a window",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Data"
+                ],
+                "summary": "Get leaderboard history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "7d",
+                        "description": "Window, e.g. 24h or 7d",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit results",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/leaderboard.TraderHistory"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/leaderboard/trader/{address}": {
+            "get": {
+                "description": "Get a single trader's rank and PnL trajectory over a window, including after they drop off the leaderboard",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Data"
+                ],
+                "summary": "Get a trader's leaderboard history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Trader wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "7d",
+                        "description": "Window, e.g. 24h or 7d",
+                        "name": "window",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/leaderboard.TraderHistory"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/market-trades": {
+            "get": {
+                "description": "Get trade history for a market (no auth required)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trades"
+                ],
+                "summary": "Get public market trades",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Market ID",
+                        "name": "market",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pagination cursor (next_cursor or offset)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add each trade's on-chain confirmed flag",
+                        "name": "verify",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Trade"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/markets": {
+            "get": {
+                "description": "Get a list of markets with optional filtering. Volume, liquidity and end date filters on open markets are served from the local catalog; other queries go to Gamma.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Markets"
+                ],
+                "summary": "List all markets",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pagination cursor (next_cursor or offset)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by active status",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by closed status",
+                        "name": "closed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by slug",
+                        "name": "slug",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by event slug",
+                        "name": "event_slug",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by CLOB token ID",
+                        "name": "clob_token_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum total volume",
+                        "name": "min_volume",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum liquidity",
+                        "name": "min_liquidity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date before (RFC 3339 or YYYY-MM-DD)",
+                        "name": "end_date_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date after (RFC 3339 or YYYY-MM-DD)",
+                        "name": "end_date_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field: volume, volume24hr, liquidity, spread or end_date",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sort ascending (default descending)",
+                        "name": "ascending",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Auto-paginate through every page",
+                        "name": "all",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1000,
+                        "description": "Maximum items when auto-paginating",
+                        "name": "max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Parse stringified lists and numbers and use camelCase keys",
+                        "name": "normalize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Market"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/markets/closing": {
+            "get": {
+                "description": "List open catalog markets whose end date falls within a window, soonest first, with their current outcome prices and the seconds remaining",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Markets"
+                ],
+                "summary": "Markets closing soon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "24h",
+                        "description": "Window ahead (e.g. 1h, 24h, 168h)",
+                        "name": "within",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Normalized category (politics, sports, crypto, ...)",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event tag slug",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pagination cursor (next_cursor or offset)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/catalog.ClosingMarket"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/markets/slug/{slug}": {
+            "get": {
+                "description": "Get market by its URL slug",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Markets"
+                ],
+                "summary": "Get market by slug",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Market slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Parse stringified lists and numbers and use camelCase keys",
+                        "name": "normalize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Market"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/markets/token/{token_id}": {
+            "get": {
+                "description": "Get market associated with a specific CLOB token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Markets"
+                ],
+                "summary": "Get market by CLOB token ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CLOB Token ID",
+                        "name": "token_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Parse stringified lists and numbers and use camelCase keys",
+                        "name": "normalize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Market"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/markets/{id}": {
+            "get": {
+                "description": "Get detailed information about a specific market",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Markets"
+                ],
+                "summary": "Get market by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Market ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Parse stringified lists and numbers and use camelCase keys",
+                        "name": "normalize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Market"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/markets/{id}/enriched": {
+            "get": {
+                "description": "Get a market with its event, tags and normalized category",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Markets"
+                ],
+                "summary": "Get enriched market",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Market ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/catalog.Entry"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/markets/{id}/full": {
+            "get": {
+                "description": "Get the market record, per-outcome book summary (bid, ask, mid, spread, depth, last trade), 24h volume and recent trades in one cached response",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Markets"
+                ],
+                "summary": "Get full market detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Market ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/polymarket.MarketDetail"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/midpoint/{token_id}": {
+            "get": {
+                "description": "Get the midpoint price for a token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get midpoint price",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID",
+                        "name": "token_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price format: probability (default), cents or american",
+                        "name": "as",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Round prices to the token's tick size (tick) or not (none); defaults to the server setting",
+                        "name": "round",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/midpoints": {
+            "get": {
+                "description": "Get midpoint prices for multiple tokens at once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get midpoints for multiple tokens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated token IDs",
+                        "name": "token_ids",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price format: probability (default), cents or american",
+                        "name": "as",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Round prices to the token's tick size (tick) or not (none); defaults to the server setting",
+                        "name": "round",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/orders": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get orders for the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get user orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by market",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pagination cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Order"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Place a new order on the market. Instead of a price, price_mode (mid, best_bid or best_ask, optionally ±N ticks, e.g. best_bid+1tick) prices the order against the live book; the result then carries price_mode and resolved_price.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Create a new order",
+                "parameters": [
+                    {
+                        "description": "Order details",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Order"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/orders/batch": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Place up to 15 orders in one upstream request. The whole batch passes or fails pre-trade risk checks together.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Create multiple orders",
+                "parameters": [
+                    {
+                        "description": "Orders",
+                        "name": "orders",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CreateOrderRequest"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/orders/batch-cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancel multiple orders by IDs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Cancel multiple orders",
+                "parameters": [
+                    {
+                        "description": "Order IDs to cancel",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BatchCancelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/orders/cancel-all": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancel all orders for a specific market",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Cancel all orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Market ID",
+                        "name": "market",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/orders/expiring": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the caller's GTD orders placed through PolyGo that are awaiting expiry, with when each will be cancelled. Orders placed without the API secret header are not cancelled automatically; their owner is only notified (order.expiring).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "List tracked GTD orders",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/expiry.Order"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/orders/open": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get all open orders for the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get open orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by market",
+                        "name": "market",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Order"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/orders/pair": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the caller's order pairs, newest first, as last recorded",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "List order pairs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/pairs.Pair"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Place two coordinated orders (e.g. buy YES@0.40 and NO@0.55, or legs in two markets) in one upstream request. If one leg is rejected the other is cancelled; a leg that already filled cannot be, and the pair is reported as broken. Both legs pass pre-trade risk checks together.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Place an order pair",
+                "parameters": [
+                    {
+                        "description": "The two legs",
+                        "name": "pair",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PairRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/pairs.Pair"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/orders/pair/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get one of the caller's order pairs with its open legs refreshed from the CLOB",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get an order pair",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pair ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/pairs.Pair"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancel both legs of one of the caller's order pairs. Legs that already filled are left as they are.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Cancel an order pair",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pair ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/pairs.Pair"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/orders/preview": {
+            "post": {
+                "description": "Match a hypothetical order against the token's current (cached) book without placing it: the levels it would take up to its limit price, filled and remaining size, average and worst fill price, slippage from the best price, and the taker fee. GTC and GTD orders leave the remainder resting; FOK orders the book cannot fill whole fill nothing. Takes the same body as POST /orders.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Preview an order's fills",
+                "parameters": [
+                    {
+                        "description": "Order details",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/polymarket.OrderPreview"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get details of a specific order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get order by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Order"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancel an existing order by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Cancel an order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/portfolio/{address}/equity-curve": {
+            "get": {
+                "description": "Get the daily portfolio snapshots of a watched wallet (on a watchlist or with a position alert) with per-period returns, total return, max drawdown and an annualized Sharpe ratio. A day's return is its change in PnL over the previous day's portfolio value.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Data"
+                ],
+                "summary": "Get a wallet's equity curve",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "week",
+                        "description": "Return period: day, week or month",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only snapshots within this window, e.g. 30d (default all)",
+                        "name": "window",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/equity.Curve"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/positions": {
+            "get": {
+                "description": "Get all positions for a user address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Data"
+                ],
+                "summary": "Get user positions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User wallet address",
+                        "name": "address",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Send no-cache to bypass cached data",
+                        "name": "Cache-Control",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pagination cursor (next_cursor or offset)",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Position"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/positions/market": {
+            "get": {
+                "description": "Get user positions for a specific market",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Data"
+                ],
+                "summary": "Get positions for a specific market",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User wallet address",
+                        "name": "address",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Send no-cache to bypass cached data",
+                        "name": "Cache-Control",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Market ID",
+                        "name": "market",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Position"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/price-history/{token_id}": {
+            "get": {
+                "description": "Get historical price data for a token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get price history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CLOB Token ID",
+                        "name": "token_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "1d",
+                        "description": "Time interval (1h, 1d, max)",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Data fidelity/resolution",
+                        "name": "fidelity",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/price/{token_id}": {
+            "get": {
+                "description": "Get the current price for a token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-protobuf"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get current price",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID",
+                        "name": "token_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "BUY",
+                        "description": "Order side (BUY/SELL)",
+                        "name": "side",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price format: probability (default), cents or american",
+                        "name": "as",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Round prices to the token's tick size (tick) or not (none); defaults to the server setting",
+                        "name": "round",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Price"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/prices": {
+            "get": {
+                "description": "Get current prices for multiple tokens at once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-protobuf"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get prices for multiple tokens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated token IDs",
+                        "name": "token_ids",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "BUY",
+                        "description": "Order side (BUY/SELL)",
+                        "name": "side",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price format: probability (default), cents or american",
+                        "name": "as",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Round prices to the token's tick size (tick) or not (none); defaults to the server setting",
+                        "name": "round",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Price"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/raw/{upstream}/{path}": {
+            "get": {
+                "description": "Forward any allowlisted path and query string to the CLOB, Gamma or Data API, passing auth headers through. Responses are not cached unless a cache TTL is configured.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Raw"
+                ],
+                "summary": "Raw upstream passthrough",
+                "parameters": [
+                    {
+                        "enum": [
+                            "clob",
+                            "gamma",
+                            "data"
+                        ],
+                        "type": "string",
+                        "description": "Upstream API",
+                        "name": "upstream",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upstream path",
+                        "name": "path",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reports/tax/{address}": {
+            "get": {
+                "description": "Match a wallet's sales and redemptions against its purchases, first in first out or last in first out, and report the gains realized in a calendar year (UTC) per disposal and per market, with short- and long-term totals and the lots still held at the end of the year. Built from the wallet's Data API activity; splits and merges are counted in ignored, not matched. With format=csv the disposals are returned as a CSV attachment.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "User Data"
+                ],
+                "summary": "Get a cost-basis report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Calendar year (default: the current year)",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "fifo",
+                        "description": "Lot matching: fifo or lifo",
+                        "name": "method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "json",
+                        "description": "json or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/taxreport.Report"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/resolve/{token_id}": {
+            "get": {
+                "description": "Get the market, outcome, question and slug behind a CLOB token ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Markets"
+                ],
+                "summary": "Resolve a token ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CLOB token ID",
+                        "name": "token_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/catalog.TokenInfo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/rewards/{address}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Score the caller's open orders made by the address against each market's reward terms (rewardsMinSize, rewardsMaxSpread and daily rate), reporting per-order eligibility and the estimated share of each market's daily rewards given the current order book",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Data"
+                ],
+                "summary": "Estimate liquidity rewards",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Maker wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/rewards.Report"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/screener": {
+            "get": {
+                "description": "Filter catalog markets by category, tag and text, sorted by 24h volume",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Markets"
+                ],
+                "summary": "Market screener",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Normalized category (politics, sports, crypto, ...)",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event tag slug",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text search on question and event title",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pagination cursor (next_cursor or offset)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/catalog.Entry"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/snapshot": {
+            "get": {
+                "description": "Get bid, ask, mid, last, spread and 24h change for several tokens in one call",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-protobuf"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get multi-token price snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated token IDs",
+                        "name": "token_ids",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/polymarket.TokenSnapshot"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/spread/{token_id}": {
+            "get": {
+                "description": "Get the bid-ask spread for a token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get spread",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID",
+                        "name": "token_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Spread"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tags": {
+            "get": {
+                "description": "List every event tag in the catalog with its event and market counts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Markets"
+                ],
+                "summary": "List tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/catalog.TagCount"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tags/{slug}/markets": {
+            "get": {
+                "description": "List catalog markets whose event carries the tag, sorted by 24h volume",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Markets"
+                ],
+                "summary": "List markets for a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pagination cursor (next_cursor or offset)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/catalog.Entry"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/timeseries": {
+            "get": {
+                "description": "Get timeseries data for a market",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get timeseries data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Condition ID",
+                        "name": "condition_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Start timestamp (unix)",
+                        "name": "start_ts",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "End timestamp (unix)",
+                        "name": "end_ts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/top-movers": {
+            "get": {
+                "description": "Get markets with the largest price changes over a trailing window, computed from locally recorded prices",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Markets"
+                ],
+                "summary": "Get top moving markets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "24h",
+                        "description": "Trailing window (e.g. 5m, 1h, 24h)",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "abs",
+                        "description": "Sort by abs, pct or volume",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit results",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/recorder.Mover"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/trader/{address}/profile": {
+            "get": {
+                "description": "Get a trader's positions, recent trades and activity with computed stats (volume, win rate on resolved positions, exposure, PnL) in one cached response",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Data"
+                ],
+                "summary": "Get trader profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Send no-cache to bypass cached data",
+                        "name": "Cache-Control",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/polymarket.TraderProfile"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/trades/{token_id}": {
+            "get": {
+                "description": "Get trade history for a token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trades"
+                ],
+                "summary": "Get public trades",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID",
+                        "name": "token_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pagination cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only trades before this time",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only trades after this time",
+                        "name": "after",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Trade"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tx/{hash}/verify": {
+            "get": {
+                "description": "Check a trade's transactionHash on Polygon: confirmation status, block number, confirmations and the USDC (ERC-20) and outcome share (ERC-1155) transfers it made. A transaction is confirmed once it succeeded and is POLYGO_CHAIN_CONFIRMATIONS blocks deep.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trades"
+                ],
+                "summary": "Verify a trade transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transaction hash",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/chain.Settlement"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/balance": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the caller's USDC balance and exchange allowances, what their open orders reserve, and whether the account can trade, with warnings for anything that would make orders fail (missing allowances, balances below open-order requirements)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Data"
+                ],
+                "summary": "Get balance and allowances",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet signature type used for orders (0 EOA, 1 Polymarket proxy, 2 Gnosis Safe)",
+                        "name": "signature_type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/polymarket.BalanceReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/trades": {
+            "get": {
+                "description": "Get trade history for a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Data"
+                ],
+                "summary": "Get user trades",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User wallet address",
+                        "name": "address",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Send no-cache to bypass cached data",
+                        "name": "Cache-Control",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pagination cursor (next_cursor or offset)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add each trade's on-chain confirmed flag",
+                        "name": "verify",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Trade"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/trades/market": {
+            "get": {
+                "description": "Get trade history for a user in a specific market",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Data"
+                ],
+                "summary": "Get user trades for a specific market",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User wallet address",
+                        "name": "address",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Send no-cache to bypass cached data",
+                        "name": "Cache-Control",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Market ID",
+                        "name": "market",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add each trade's on-chain confirmed flag",
+                        "name": "verify",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Trade"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlist/markets": {
+            "get": {
+                "description": "List the markets watched by the caller",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Watchlist"
+                ],
+                "summary": "List watched markets",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/watchlist.WatchedMarket"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Stream price, volume and resolution changes of a market on /ws/watchlist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Watchlist"
+                ],
+                "summary": "Watch a market",
+                "parameters": [
+                    {
+                        "description": "Market to watch",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AddMarketRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/watchlist.WatchedMarket"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlist/markets/{id}": {
+            "delete": {
+                "description": "Remove a market from the caller's watchlist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Watchlist"
+                ],
+                "summary": "Stop watching a market",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Market ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlist/wallets": {
+            "get": {
+                "description": "List the wallets watched by the caller",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Watchlist"
+                ],
+                "summary": "List watched wallets",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/watchlist.Wallet"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Poll a wallet's trades and push new ones to /ws/watchlist subscribers and wallet.trade webhooks",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Watchlist"
+                ],
+                "summary": "Watch a wallet",
+                "parameters": [
+                    {
+                        "description": "Wallet to watch",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AddWalletRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/watchlist.Wallet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlist/wallets/{address}": {
+            "delete": {
+                "description": "Remove a wallet from the caller's watchlist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Watchlist"
+                ],
+                "summary": "Stop watching a wallet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks": {
+            "get": {
+                "description": "List webhook subscriptions owned by the caller",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/webhooks.Subscription"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Subscribe a URL to PolyGo events (e.g. order.created, order.*, market.listed, or * for all). tags narrows market.listed to markets with one of the given tag slugs, and rule.triggered to the named rules. Deliveries carry X-PolyGo-Timestamp and X-PolyGo-Signature, v1= and the hex HMAC-SHA256 of \"\u003ctimestamp\u003e.\u003cbody\u003e\" keyed by the subscription's secret. The secret is returned here only; it is generated unless given (at least 16 characters).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Register a webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook subscription",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.WebhookWithSecret"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/deliveries/{id}": {
+            "get": {
+                "description": "Get the status, attempts and originating request ID of a webhook delivery",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Trace a webhook delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/webhooks.Delivery"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}": {
+            "delete": {
+                "description": "Remove a webhook subscription",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}/secret": {
+            "post": {
+                "description": "Replace the secret a webhook's deliveries are signed with. For 24 hours deliveries carry a signature with the old secret too (comma-separated in X-PolyGo-Signature), so receivers can switch over without dropping events.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Rotate a webhook's secret",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New secret",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RotateWebhookSecretRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/handlers.WebhookWithSecret"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check if the server is running and probe upstream API reachability",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "Per-route request latency and upstream wait histograms, estimated p50/p95/p99, 5xx counts and WebSocket send buffer gauges in the Prometheus text format",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Prometheus metrics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/openapi.json": {
+            "get": {
+                "description": "The API as an OpenAPI 3.0 document for client generators, converted from the Swagger spec served at /swagger/doc.json",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "OpenAPI 3 document",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/ready": {
+            "get": {
+                "description": "Check if the server is ready to accept requests",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Readiness check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReadyResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReadyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats": {
+            "get": {
+                "description": "Get server runtime statistics",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Server statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StatsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ws/events": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stream the caller's events, e.g. order.created or order.expiring. ?events= takes comma-separated types or families (order.*); default all. Send {\"type\":\"arm_dead_man\",\"timeout\":10} to cancel all the API key's open orders should the connection drop or go that many seconds without {\"type\":\"heartbeat\"}; {\"type\":\"disarm_dead_man\"} turns it off. Each is answered with a dead_man message, and a triggered switch publishes order.dead_man. Arming needs the API secret header.",
+                "tags": [
+                    "WebSocket"
+                ],
+                "summary": "Events WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event types, e.g. order.*",
+                        "name": "events",
+                        "in": "query"
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/ws/listings": {
+            "get": {
+                "description": "Streams a \"market_listed\" frame ({\"type\", \"market\"}) for each market a catalog sync finds for the first time. The same payload is delivered to webhooks subscribed to market.listed.",
+                "tags": [
+                    "WebSocket"
+                ],
+                "summary": "New market listings WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated tag slugs; only markets carrying one of them are sent",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Downstream encoding: json (default) or msgpack for binary frames",
+                        "name": "encoding",
+                        "in": "query"
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/ws/market/{market_id}": {
+            "get": {
+                "description": "WebSocket endpoint for real-time market updates. Send {\"type\":\"subscribe\",\"markets\":[...],\"assets\":[...]} to follow more markets or tokens, and \"unsubscribe\" to stop.",
+                "tags": [
+                    "WebSocket"
+                ],
+                "summary": "Market WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID (asset_id) or market condition ID to subscribe",
+                        "name": "market_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Downstream encoding: json (default) or msgpack for binary frames",
+                        "name": "encoding",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order book format: full (default) or delta for changed levels with sequence numbers",
+                        "name": "books",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add the market, question and outcome of each token to trade and price messages",
+                        "name": "enrich",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "stream_seq of the last message received; messages after it still buffered are sent first, then a replayed notice",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Protocol version: 1 (default) for messages as received, 2 for envelopes with type, seq, token and payload",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/ws/markets": {
+            "get": {
+                "description": "WebSocket endpoint for all real-time market updates",
+                "tags": [
+                    "WebSocket"
+                ],
+                "summary": "All Markets WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Downstream encoding: json (default) or msgpack for binary frames",
+                        "name": "encoding",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order book format: full (default) or delta for changed levels with sequence numbers",
+                        "name": "books",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add the market, question and outcome of each token to trade and price messages",
+                        "name": "enrich",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "stream_seq of the last message received; messages after it still buffered are sent first, then a replayed notice",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Protocol version: 1 (default) for messages as received, 2 for envelopes with type, seq, token and payload",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/ws/ticker": {
+            "get": {
+                "description": "Compact midpoint stream for the top markets by 24h volume, throttled server-side to at most one update per token per interval. A snapshot frame is sent on connect, then batched \"ticker\" frames with only the tokens that changed ({\"m\": market ID, \"t\": token ID, \"p\": midpoint}).",
+                "tags": [
+                    "WebSocket"
+                ],
+                "summary": "Headline Price Ticker WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Downstream encoding: json (default) or msgpack for binary frames",
+                        "name": "encoding",
+                        "in": "query"
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/ws/token/{token_id}": {
+            "get": {
+                "description": "WebSocket endpoint for real-time updates of one outcome token: its book, price changes and trades. Send {\"type\":\"subscribe\",\"assets\":[...]} to follow more tokens, and \"unsubscribe\" to stop.",
+                "tags": [
+                    "WebSocket"
+                ],
+                "summary": "Token WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID (asset_id) to subscribe",
+                        "name": "token_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Downstream encoding: json (default) or msgpack for binary frames",
+                        "name": "encoding",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order book format: full (default) or delta for changed levels with sequence numbers",
+                        "name": "books",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add the market, question and outcome of each token to trade and price messages",
+                        "name": "enrich",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "stream_seq of the last message received; messages after it still buffered are sent first, then a replayed notice",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Protocol version: 1 (default) for messages as received, 2 for envelopes with type, seq, token and payload",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/ws/watchlist": {
+            "get": {
+                "description": "Streams the caller's watchlist (identified by the POLY-API-KEY header, if sent). Watched markets produce \"market_snapshot\" frames on connect and \"market_update\" frames listing what changed (price, volume, resolution); watched wallets produce \"wallet_trade\" frames with each new trade.",
+                "tags": [
+                    "WebSocket"
+                ],
+                "summary": "Watchlist WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated addresses whose trades to receive instead of the caller's wallets",
+                        "name": "wallets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Downstream encoding: json (default) or msgpack for binary frames",
+                        "name": "encoding",
+                        "in": "query"
+                    }
+                ],
+                "responses": {}
+            }
         }
-      }
     },
-    "/api/v1/positions": {
-      "get": {
-        "tags": ["User Data"],
-        "summary": "Get user positions",
-        "produces": ["application/json"],
-        "parameters": [
-          {"name": "address", "in": "query", "required": true, "type": "string"},
-          {"name": "limit", "in": "query", "type": "integer", "default": 100}
-        ],
-        "responses": {
-          "200": {"description": "List of positions"}
+    "definitions": {
+        "analytics.CorrelationMatrix": {
+            "type": "object",
+            "properties": {
+                "matrix": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "number"
+                        }
+                    }
+                },
+                "missing": {
+                    "description": "tokens without recorded prices",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "samples": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        }
+                    }
+                },
+                "token_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "analytics.RankedMarket": {
+            "type": "object",
+            "properties": {
+                "acceptingOrders": {
+                    "type": "boolean"
+                },
+                "acceptingOrdersTimestamp": {
+                    "type": "string"
+                },
+                "active": {
+                    "type": "boolean"
+                },
+                "bestAsk": {
+                    "type": "string"
+                },
+                "bestBid": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "clobRewards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ClobReward"
+                    }
+                },
+                "clobTokenIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "closed": {
+                    "type": "boolean"
+                },
+                "conditionId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enableOrderBook": {
+                    "type": "boolean"
+                },
+                "endDate": {
+                    "type": "string"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "eventSlug": {
+                    "type": "string"
+                },
+                "eventTitle": {
+                    "type": "string"
+                },
+                "firstSeen": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "liquidity": {
+                    "type": "string"
+                },
+                "marketType": {
+                    "type": "string"
+                },
+                "negRisk": {
+                    "type": "boolean"
+                },
+                "negRiskMarketId": {
+                    "type": "string"
+                },
+                "negRiskRequestId": {
+                    "type": "string"
+                },
+                "orderMinSize": {
+                    "type": "number"
+                },
+                "orderPriceMinTickSize": {
+                    "type": "number"
+                },
+                "outcomePrices": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "outcomes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "question": {
+                    "type": "string"
+                },
+                "rewardsMaxSpread": {
+                    "type": "number"
+                },
+                "rewardsMinSize": {
+                    "type": "number"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "spread": {
+                    "type": "string"
+                },
+                "spreadMultiplierMax": {
+                    "type": "number"
+                },
+                "spreadMultiplierMin": {
+                    "type": "number"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                },
+                "trades24h": {
+                    "type": "integer"
+                },
+                "volume": {
+                    "type": "string"
+                },
+                "volume1wk": {
+                    "type": "string"
+                },
+                "volume24hr": {
+                    "type": "string"
+                }
+            }
+        },
+        "audit.Entry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "e.g. order.create or webhook.delete",
+                    "type": "string"
+                },
+                "actor": {
+                    "description": "API key, or admin for the admin token",
+                    "type": "string"
+                },
+                "ip": {
+                    "description": "client IP",
+                    "type": "string"
+                },
+                "method": {
+                    "description": "HTTP method, or WS for WebSocket messages",
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "payload_hash": {
+                    "description": "SHA-256 of the request body, hex",
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "seq": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "HTTP status answered (0 for WebSocket messages)",
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "cache.SizeStats": {
+            "type": "object",
+            "properties": {
+                "bytes_saved": {
+                    "type": "integer"
+                },
+                "compressed": {
+                    "type": "integer"
+                },
+                "compression": {
+                    "type": "string"
+                },
+                "cost_added": {
+                    "type": "integer"
+                },
+                "cost_evicted": {
+                    "type": "integer"
+                },
+                "evicted": {
+                    "type": "integer"
+                },
+                "evicted_bytes": {
+                    "type": "integer"
+                },
+                "max_entry_size": {
+                    "type": "integer"
+                },
+                "rejected_oversize": {
+                    "type": "integer"
+                },
+                "rejected_policy": {
+                    "type": "integer"
+                }
+            }
+        },
+        "catalog.ClosingMarket": {
+            "type": "object",
+            "properties": {
+                "acceptingOrders": {
+                    "type": "boolean"
+                },
+                "acceptingOrdersTimestamp": {
+                    "type": "string"
+                },
+                "active": {
+                    "type": "boolean"
+                },
+                "bestAsk": {
+                    "type": "string"
+                },
+                "bestBid": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "clobRewards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ClobReward"
+                    }
+                },
+                "clobTokenIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "closed": {
+                    "type": "boolean"
+                },
+                "conditionId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enableOrderBook": {
+                    "type": "boolean"
+                },
+                "endDate": {
+                    "type": "string"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "eventSlug": {
+                    "type": "string"
+                },
+                "eventTitle": {
+                    "type": "string"
+                },
+                "firstSeen": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "liquidity": {
+                    "type": "string"
+                },
+                "marketType": {
+                    "type": "string"
+                },
+                "negRisk": {
+                    "type": "boolean"
+                },
+                "negRiskMarketId": {
+                    "type": "string"
+                },
+                "negRiskRequestId": {
+                    "type": "string"
+                },
+                "orderMinSize": {
+                    "type": "number"
+                },
+                "orderPriceMinTickSize": {
+                    "type": "number"
+                },
+                "outcomePrices": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "outcomes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "question": {
+                    "type": "string"
+                },
+                "rewardsMaxSpread": {
+                    "type": "number"
+                },
+                "rewardsMinSize": {
+                    "type": "number"
+                },
+                "secondsRemaining": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "spread": {
+                    "type": "string"
+                },
+                "spreadMultiplierMax": {
+                    "type": "number"
+                },
+                "spreadMultiplierMin": {
+                    "type": "number"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                },
+                "volume": {
+                    "type": "string"
+                },
+                "volume1wk": {
+                    "type": "string"
+                },
+                "volume24hr": {
+                    "type": "string"
+                }
+            }
+        },
+        "catalog.Entry": {
+            "type": "object",
+            "properties": {
+                "acceptingOrders": {
+                    "type": "boolean"
+                },
+                "acceptingOrdersTimestamp": {
+                    "type": "string"
+                },
+                "active": {
+                    "type": "boolean"
+                },
+                "bestAsk": {
+                    "type": "string"
+                },
+                "bestBid": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "clobRewards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ClobReward"
+                    }
+                },
+                "clobTokenIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "closed": {
+                    "type": "boolean"
+                },
+                "conditionId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enableOrderBook": {
+                    "type": "boolean"
+                },
+                "endDate": {
+                    "type": "string"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "eventSlug": {
+                    "type": "string"
+                },
+                "eventTitle": {
+                    "type": "string"
+                },
+                "firstSeen": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "liquidity": {
+                    "type": "string"
+                },
+                "marketType": {
+                    "type": "string"
+                },
+                "negRisk": {
+                    "type": "boolean"
+                },
+                "negRiskMarketId": {
+                    "type": "string"
+                },
+                "negRiskRequestId": {
+                    "type": "string"
+                },
+                "orderMinSize": {
+                    "type": "number"
+                },
+                "orderPriceMinTickSize": {
+                    "type": "number"
+                },
+                "outcomePrices": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "outcomes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "question": {
+                    "type": "string"
+                },
+                "rewardsMaxSpread": {
+                    "type": "number"
+                },
+                "rewardsMinSize": {
+                    "type": "number"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "spread": {
+                    "type": "string"
+                },
+                "spreadMultiplierMax": {
+                    "type": "number"
+                },
+                "spreadMultiplierMin": {
+                    "type": "number"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                },
+                "volume": {
+                    "type": "string"
+                },
+                "volume1wk": {
+                    "type": "string"
+                },
+                "volume24hr": {
+                    "type": "string"
+                }
+            }
+        },
+        "catalog.EventSummary": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "liquidity": {
+                    "type": "number"
+                },
+                "markets": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "volume": {
+                    "type": "number"
+                }
+            }
+        },
+        "catalog.TagCount": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "integer"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "markets": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "catalog.TagGroup": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "integer"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "top": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/catalog.EventSummary"
+                    }
+                },
+                "volume": {
+                    "type": "number"
+                }
+            }
+        },
+        "catalog.TokenInfo": {
+            "type": "object",
+            "properties": {
+                "condition_id": {
+                    "type": "string"
+                },
+                "event_slug": {
+                    "type": "string"
+                },
+                "market_id": {
+                    "type": "string"
+                },
+                "min_order_size": {
+                    "description": "shares",
+                    "type": "number"
+                },
+                "outcome": {
+                    "type": "string"
+                },
+                "outcome_index": {
+                    "type": "integer"
+                },
+                "question": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "tick_size": {
+                    "description": "as listed by Gamma; the CLOB's is authoritative",
+                    "type": "number"
+                },
+                "token_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "chain.Settlement": {
+            "type": "object",
+            "properties": {
+                "block_number": {
+                    "type": "integer"
+                },
+                "confirmations": {
+                    "type": "integer"
+                },
+                "confirmed": {
+                    "type": "boolean"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "transfers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chain.Transfer"
+                    }
+                }
+            }
+        },
+        "chain.Transfer": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "base units",
+                    "type": "string"
+                },
+                "contract": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "standard": {
+                    "description": "erc20 or erc1155",
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "token_id": {
+                    "description": "erc1155 position ID",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "whole units, assuming 6 decimals",
+                    "type": "number"
+                }
+            }
+        },
+        "config.AccessConfig": {
+            "type": "object",
+            "properties": {
+                "defaultRole": {
+                    "description": "role of callers without a token (empty = they are rejected)",
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "jwtsecret": {
+                    "description": "HS256 secret JWTs are signed with (empty = JWTs are not accepted)",
+                    "type": "string"
+                },
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.AccessKey"
+                    }
+                }
+            }
+        },
+        "config.AccessKey": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "who the key belongs to, as logged and audited",
+                    "type": "string"
+                },
+                "role": {
+                    "description": "viewer, trader or admin",
+                    "type": "string"
+                }
+            }
+        },
+        "config.AdminConfig": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "description": "bearer token required on /admin (empty = open)",
+                    "type": "string"
+                }
+            }
+        },
+        "config.AnalyticsConfig": {
+            "type": "object",
+            "properties": {
+                "tradeSampleSize": {
+                    "description": "recent trades fetched per sync",
+                    "type": "integer"
+                },
+                "tradeSyncInterval": {
+                    "description": "how often recent trades are pulled",
+                    "type": "integer"
+                },
+                "tradeWindow": {
+                    "description": "window for trade counts",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.AuditConfig": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "keep": {
+                    "description": "latest entries /admin/audit searches; exports read the whole file",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "JSON lines file entries are appended to (empty = memory only)",
+                    "type": "string"
+                }
+            }
+        },
+        "config.AuthConfig": {
+            "type": "object",
+            "properties": {
+                "apikeyHeader": {
+                    "type": "string"
+                },
+                "apisecretHeader": {
+                    "type": "string"
+                },
+                "passphraseHeader": {
+                    "type": "string"
+                },
+                "signatureHeader": {
+                    "type": "string"
+                },
+                "timestampHeader": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.CacheConfig": {
+            "type": "object",
+            "properties": {
+                "bufferItems": {
+                    "type": "integer"
+                },
+                "compositeTTL": {
+                    "description": "composite responses such as /markets/:id/full",
+                    "type": "integer"
+                },
+                "compressThreshold": {
+                    "description": "minimum payload size to compress",
+                    "type": "integer"
+                },
+                "compression": {
+                    "description": "none, snappy or zstd",
+                    "type": "string"
+                },
+                "defaultTTL": {
+                    "type": "integer"
+                },
+                "eventsTTL": {
+                    "type": "integer"
+                },
+                "marketsTTL": {
+                    "type": "integer"
+                },
+                "maxCost": {
+                    "type": "integer"
+                },
+                "maxEntrySize": {
+                    "description": "Size guards",
+                    "type": "integer"
+                },
+                "numCounters": {
+                    "type": "integer"
+                },
+                "orderBookTTL": {
+                    "type": "integer"
+                },
+                "persistPath": {
+                    "description": "Persistence across restarts",
+                    "type": "string"
+                },
+                "persistPrefixes": {
+                    "description": "key prefixes of long-lived entries worth keeping",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pricesTTL": {
+                    "type": "integer"
+                },
+                "staleFor": {
+                    "description": "entries past their TTL are served this long while one caller refreshes them (0 disables)",
+                    "type": "integer"
+                },
+                "ttljitter": {
+                    "description": "Stampede protection",
+                    "type": "number"
+                },
+                "userDataTTL": {
+                    "description": "positions, user trades, activity",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.CatalogConfig": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "description": "extra classifier keywords per category",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "maxEvents": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "syncInterval": {
+                    "type": "integer"
+                }
+            }
+        },
+        "config.ChainConfig": {
+            "type": "object",
+            "properties": {
+                "concurrency": {
+                    "description": "concurrent RPC lookups per response",
+                    "type": "integer"
+                },
+                "confirmations": {
+                    "description": "blocks deep before a transaction counts as confirmed",
+                    "type": "integer"
+                },
+                "maxVerify": {
+                    "description": "trades verified per response with ?verify=true",
+                    "type": "integer"
+                },
+                "rpcurl": {
+                    "type": "string"
+                },
+                "timeout": {
+                    "type": "integer"
+                }
+            }
+        },
+        "config.ConcurrencyConfig": {
+            "type": "object",
+            "properties": {
+                "classes": {
+                    "description": "max in flight per route class",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "maxInFlight": {
+                    "description": "all requests (0 = unlimited)",
+                    "type": "integer"
+                },
+                "retryAfter": {
+                    "description": "suggested to refused clients",
+                    "type": "integer"
+                },
+                "shedAt": {
+                    "description": "share of max_in_flight from which reads missing the cache are refused (0 = never)",
+                    "type": "number"
+                }
+            }
+        },
+        "config.Config": {
+            "type": "object",
+            "properties": {
+                "access": {
+                    "$ref": "#/definitions/config.AccessConfig"
+                },
+                "admin": {
+                    "$ref": "#/definitions/config.AdminConfig"
+                },
+                "analytics": {
+                    "$ref": "#/definitions/config.AnalyticsConfig"
+                },
+                "audit": {
+                    "$ref": "#/definitions/config.AuditConfig"
+                },
+                "auth": {
+                    "$ref": "#/definitions/config.AuthConfig"
+                },
+                "cache": {
+                    "$ref": "#/definitions/config.CacheConfig"
+                },
+                "catalog": {
+                    "$ref": "#/definitions/config.CatalogConfig"
+                },
+                "chain": {
+                    "$ref": "#/definitions/config.ChainConfig"
+                },
+                "concurrency": {
+                    "$ref": "#/definitions/config.ConcurrencyConfig"
+                },
+                "copyTrade": {
+                    "$ref": "#/definitions/config.CopyTradeConfig"
+                },
+                "deadMan": {
+                    "$ref": "#/definitions/config.DeadManConfig"
+                },
+                "digest": {
+                    "$ref": "#/definitions/config.DigestConfig"
+                },
+                "docs": {
+                    "$ref": "#/definitions/config.DocsConfig"
+                },
+                "equity": {
+                    "$ref": "#/definitions/config.EquityConfig"
+                },
+                "errorReporting": {
+                    "$ref": "#/definitions/config.ErrorReportingConfig"
+                },
+                "eventBus": {
+                    "$ref": "#/definitions/config.EventBusConfig"
+                },
+                "export": {
+                    "$ref": "#/definitions/config.ExportConfig"
+                },
+                "fillNotify": {
+                    "$ref": "#/definitions/config.FillNotifyConfig"
+                },
+                "health": {
+                    "$ref": "#/definitions/config.HealthConfig"
+                },
+                "leaderboard": {
+                    "$ref": "#/definitions/config.LeaderboardConfig"
+                },
+                "orderExpiry": {
+                    "$ref": "#/definitions/config.OrderExpiryConfig"
+                },
+                "orderRules": {
+                    "$ref": "#/definitions/config.OrderRulesConfig"
+                },
+                "polymarket": {
+                    "$ref": "#/definitions/config.PolymarketConfig"
+                },
+                "positionAlerts": {
+                    "$ref": "#/definitions/config.PositionAlertsConfig"
+                },
+                "prices": {
+                    "$ref": "#/definitions/config.PricesConfig"
+                },
+                "rateLimit": {
+                    "$ref": "#/definitions/config.RateLimitConfig"
+                },
+                "rawProxy": {
+                    "$ref": "#/definitions/config.RawProxyConfig"
+                },
+                "recorder": {
+                    "$ref": "#/definitions/config.RecorderConfig"
+                },
+                "replication": {
+                    "$ref": "#/definitions/config.ReplicationConfig"
+                },
+                "risk": {
+                    "$ref": "#/definitions/config.RiskConfig"
+                },
+                "routing": {
+                    "$ref": "#/definitions/config.RoutingConfig"
+                },
+                "rules": {
+                    "$ref": "#/definitions/config.RulesConfig"
+                },
+                "secrets": {
+                    "$ref": "#/definitions/config.SecretsConfig"
+                },
+                "server": {
+                    "$ref": "#/definitions/config.ServerConfig"
+                },
+                "snapshot": {
+                    "$ref": "#/definitions/config.SnapshotConfig"
+                },
+                "storage": {
+                    "$ref": "#/definitions/config.StorageConfig"
+                },
+                "strategies": {
+                    "$ref": "#/definitions/config.StrategiesConfig"
+                },
+                "tape": {
+                    "$ref": "#/definitions/config.TapeConfig"
+                },
+                "taxReport": {
+                    "$ref": "#/definitions/config.TaxReportConfig"
+                },
+                "tenants": {
+                    "$ref": "#/definitions/config.TenantsConfig"
+                },
+                "throttle": {
+                    "$ref": "#/definitions/config.ThrottleConfig"
+                },
+                "ticker": {
+                    "$ref": "#/definitions/config.TickerConfig"
+                },
+                "watchlist": {
+                    "$ref": "#/definitions/config.WatchlistConfig"
+                },
+                "webhooks": {
+                    "$ref": "#/definitions/config.WebhooksConfig"
+                }
+            }
+        },
+        "config.CopyTradeConfig": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "local account's maker wallet",
+                    "type": "string"
+                },
+                "apikey": {
+                    "description": "local account's L2 credentials",
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "interval": {
+                    "description": "how often the target wallet's trades are polled",
+                    "type": "integer"
+                },
+                "maxExposure": {
+                    "description": "net USDC bought per session; orders beyond it are skipped",
+                    "type": "number"
+                },
+                "maxOrderNotional": {
+                    "description": "USDC per mirrored order; larger orders are scaled down",
+                    "type": "number"
+                },
+                "passphrase": {
+                    "type": "string"
+                },
+                "scale": {
+                    "description": "default size multiplier applied to the target's trades",
+                    "type": "number"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "tradesLimit": {
+                    "description": "recent trades fetched per poll",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.DeadManConfig": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "maxTimeout": {
+                    "description": "longest heartbeat timeout a client may arm",
+                    "type": "integer"
+                },
+                "minTimeout": {
+                    "description": "shortest heartbeat timeout a client may arm",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.DigestConfig": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "description": "entries per section",
+                    "type": "integer"
+                },
+                "spikeRatio": {
+                    "description": "24h volume over the 7-day daily average that counts as a spike",
+                    "type": "number"
+                },
+                "ttl": {
+                    "description": "how long a computed digest is served",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.DocsConfig": {
+            "type": "object",
+            "properties": {
+                "basePath": {
+                    "type": "string"
+                },
+                "host": {
+                    "description": "e.g. api.example.com (empty = the host serving the docs)",
+                    "type": "string"
+                },
+                "schemes": {
+                    "description": "e.g. https (empty = the scheme serving the docs)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "config.Effective": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/config.Config"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "config.EquityConfig": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "description": "UTC time of day snapshots are taken, HH:MM",
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "path": {
+                    "description": "file history is saved to (empty = memory only)",
+                    "type": "string"
+                },
+                "positionsLimit": {
+                    "description": "positions fetched per wallet and snapshot",
+                    "type": "integer"
+                },
+                "retention": {
+                    "description": "how long snapshots are kept",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.ErrorReportingConfig": {
+            "type": "object",
+            "properties": {
+                "bugsnagAPIKey": {
+                    "type": "string"
+                },
+                "bugsnagURL": {
+                    "description": "notify endpoint (on-premise Bugsnag)",
+                    "type": "string"
+                },
+                "dedupWindow": {
+                    "description": "identical panics are reported once per window, with a count",
+                    "type": "integer"
+                },
+                "environment": {
+                    "description": "Sentry environment / Bugsnag release stage",
+                    "type": "string"
+                },
+                "queueSize": {
+                    "description": "reports waiting to be sent; more are dropped",
+                    "type": "integer"
+                },
+                "release": {
+                    "type": "string"
+                },
+                "sentryDSN": {
+                    "type": "string"
+                },
+                "timeout": {
+                    "type": "integer"
+                }
+            }
+        },
+        "config.EventBusConfig": {
+            "type": "object",
+            "properties": {
+                "backend": {
+                    "description": "kafka or nats",
+                    "type": "string"
+                },
+                "batchSize": {
+                    "description": "events per publish",
+                    "type": "integer"
+                },
+                "clientID": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "flushInterval": {
+                    "description": "longest an event waits for a batch to fill",
+                    "type": "integer"
+                },
+                "markets": {
+                    "description": "markets kept subscribed upstream for the bus, as in WebSocket subscriptions",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "password": {
+                    "description": "NATS only",
+                    "type": "string"
+                },
+                "queueSize": {
+                    "description": "events waiting to be published; more are dropped",
+                    "type": "integer"
+                },
+                "servers": {
+                    "description": "host:port of Kafka bootstrap brokers or NATS servers",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timeout": {
+                    "type": "integer"
+                },
+                "token": {
+                    "description": "NATS only",
+                    "type": "string"
+                },
+                "topics": {
+                    "$ref": "#/definitions/config.EventBusTopics"
+                },
+                "user": {
+                    "description": "NATS only",
+                    "type": "string"
+                }
+            }
+        },
+        "config.EventBusTopics": {
+            "type": "object",
+            "properties": {
+                "bookDeltas": {
+                    "type": "string"
+                },
+                "priceChanges": {
+                    "type": "string"
+                },
+                "resolutions": {
+                    "type": "string"
+                },
+                "trades": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.ExportConfig": {
+            "type": "object",
+            "properties": {
+                "dir": {
+                    "description": "storage directory exports are written under",
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "history": {
+                    "description": "runs kept per job",
+                    "type": "integer"
+                },
+                "maxJobs": {
+                    "type": "integer"
+                },
+                "maxRows": {
+                    "description": "rows written per run; larger datasets are truncated",
+                    "type": "integer"
+                },
+                "minInterval": {
+                    "description": "shortest schedule a job may have",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "file jobs and their run history are saved to (empty = memory only)",
+                    "type": "string"
+                },
+                "tick": {
+                    "description": "how often due jobs are checked for",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.FillNotifyConfig": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "idleTimeout": {
+                    "description": "a caller's user channel is closed this long after its last watched order is done",
+                    "type": "integer"
+                },
+                "maxOrders": {
+                    "description": "resting orders watched across all callers",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.HealthConfig": {
+            "type": "object",
+            "properties": {
+                "clobProbePath": {
+                    "type": "string"
+                },
+                "dataProbePath": {
+                    "type": "string"
+                },
+                "gammaProbePath": {
+                    "type": "string"
+                },
+                "probeInterval": {
+                    "description": "how long probe results are cached",
+                    "type": "integer"
+                },
+                "probeTimeout": {
+                    "description": "per-probe timeout",
+                    "type": "integer"
+                },
+                "unhealthyThreshold": {
+                    "description": "unreachable time before /ready fails",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.LeaderboardConfig": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "interval": {
+                    "description": "how often the leaderboard is snapshotted",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "file history is saved to (empty = memory only)",
+                    "type": "string"
+                },
+                "retention": {
+                    "description": "how long snapshots are kept",
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "traders kept per snapshot",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.OrderExpiryConfig": {
+            "type": "object",
+            "properties": {
+                "cancelBuffer": {
+                    "description": "tracked orders are cancelled this long before expiry",
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "interval": {
+                    "description": "how often tracked expirations are checked",
+                    "type": "integer"
+                },
+                "maxOrders": {
+                    "description": "GTD orders tracked across all callers",
+                    "type": "integer"
+                },
+                "minLifetime": {
+                    "description": "GTD expirations closer than this are rejected",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.OrderRoute": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "description": "names, round-robin, skipping accounts at their exposure limit",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "market": {
+                    "description": "token ID, market ID, condition ID or slug (empty = any)",
+                    "type": "string"
+                },
+                "strategy": {
+                    "description": "strategy tag (empty = any)",
+                    "type": "string"
+                }
+            }
+        },
+        "config.OrderRulesConfig": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "minSize": {
+                    "description": "shares, for markets that publish no minimum (0 = none)",
+                    "type": "number"
+                },
+                "sizeDecimals": {
+                    "description": "decimals allowed in order sizes (negative = any)",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.PolymarketConfig": {
+            "type": "object",
+            "properties": {
+                "clobBaseURL": {
+                    "type": "string"
+                },
+                "clobMirrors": {
+                    "description": "Mirrors are backup base URLs of each API, in order of preference.\nRequests move to the first healthy one while the base URL is down\nand come back once it recovers.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dataBaseURL": {
+                    "type": "string"
+                },
+                "dataMirrors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dialTimeout": {
+                    "description": "connecting to an upstream, proxy handshake included",
+                    "type": "integer"
+                },
+                "dnscacheTTL": {
+                    "description": "Upstream host names are resolved through a cache, and a host's IPv4\nand IPv6 addresses raced (happy eyeballs) when connecting",
+                    "type": "integer"
+                },
+                "extraHeaders": {
+                    "description": "ExtraHeaders are sent with every upstream HTTP and WebSocket request",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "failoverCheckInterval": {
+                    "description": "health checks of base and mirror URLs, on the health probe paths",
+                    "type": "integer"
+                },
+                "failoverThreshold": {
+                    "description": "consecutive failed checks or requests marking a URL down, successful checks marking it up",
+                    "type": "integer"
+                },
+                "gammaBaseURL": {
+                    "type": "string"
+                },
+                "gammaMirrors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "happyEyeballsDelay": {
+                    "description": "head start of each address over the next; 0 tries them one by one",
+                    "type": "integer"
+                },
+                "honorCacheHeaders": {
+                    "description": "HonorCacheHeaders uses upstream-provided TTLs (X-PolyGo-TTL-Ms) when caching",
+                    "type": "boolean"
+                },
+                "maxConnWait": {
+                    "description": "a request's wait for a free connection at MaxConnsPerHost; 0 fails at once",
+                    "type": "integer"
+                },
+                "maxConnsPerHost": {
+                    "description": "tunable at runtime under /admin/upstream/pool",
+                    "type": "integer"
+                },
+                "maxIdleConnDur": {
+                    "type": "integer"
+                },
+                "proxies": {
+                    "description": "Proxies overrides Proxy per upstream host (e.g. \"clob.polymarket.com\"),\n\"direct\" bypassing it",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "proxy": {
+                    "description": "Proxy carries upstream HTTP and WebSocket connections through an\nhttp:// or socks5:// (socks5h:// to resolve on the proxy) proxy,\nwith user:password@ for credentials; empty connects directly",
+                    "type": "string"
+                },
+                "readTimeout": {
+                    "type": "integer"
+                },
+                "retryBudgetBurst": {
+                    "description": "retries allowed in a burst; 0 disables the budget",
+                    "type": "integer"
+                },
+                "retryBudgetRatio": {
+                    "description": "retries allowed per request, long-run",
+                    "type": "number"
+                },
+                "retryCount": {
+                    "type": "integer"
+                },
+                "retryMaxWait": {
+                    "description": "backoff cap and longest Retry-After honored",
+                    "type": "integer"
+                },
+                "retryMethods": {
+                    "description": "methods safe to retry; POST is never retried by default",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "retryWaitTime": {
+                    "description": "first backoff; doubles per retry",
+                    "type": "integer"
+                },
+                "streamThreshold": {
+                    "description": "StreamThreshold is the body size in bytes above which responses\nrelayed as is (raw proxy, v1 price history) are streamed to the\nclient instead of buffered and cached; 0 always buffers",
+                    "type": "integer"
+                },
+                "timeouts": {
+                    "description": "Timeouts overrides ReadTimeout for upstream paths starting with a\nprefix (e.g. \"/prices-history\": 15s); the longest matching prefix wins",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "upstreamBurst": {
+                    "type": "integer"
+                },
+                "upstreamQueueSize": {
+                    "description": "requests allowed to wait for a token",
+                    "type": "integer"
+                },
+                "upstreamQueueWait": {
+                    "description": "longest a request may wait for a token",
+                    "type": "integer"
+                },
+                "upstreamRPS": {
+                    "description": "Client-side rate limit per upstream host; excess requests queue",
+                    "type": "number"
+                },
+                "validation": {
+                    "description": "Validation checks upstream payloads against the models: off, warn\n(log and count drift) or strict (also fail payloads missing expected\nfields or with mismatched types)",
+                    "type": "string"
+                },
+                "warmupConns": {
+                    "description": "connections opened to each upstream API at startup; 0 disables",
+                    "type": "integer"
+                },
+                "writeTimeout": {
+                    "type": "integer"
+                },
+                "wsClobURL": {
+                    "type": "string"
+                },
+                "wsLiveDataURL": {
+                    "type": "string"
+                },
+                "wsPingInterval": {
+                    "description": "0 disables pings",
+                    "type": "integer"
+                },
+                "wsPongTimeout": {
+                    "description": "reconnect when a ping goes unanswered this long",
+                    "type": "integer"
+                },
+                "wsShards": {
+                    "description": "upstream CLOB WebSocket connections",
+                    "type": "integer"
+                },
+                "wsStaleTimeout": {
+                    "description": "reconnect a subscribed shard silent this long; 0 disables",
+                    "type": "integer"
+                },
+                "wsUserURL": {
+                    "description": "CLOB user channel, for fill notifications; empty disables",
+                    "type": "string"
+                }
+            }
+        },
+        "config.PositionAlertsConfig": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "interval": {
+                    "description": "how often each watched wallet's positions are polled",
+                    "type": "integer"
+                },
+                "maxWatches": {
+                    "description": "watches across all callers",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "file watches are saved to (empty = memory only)",
+                    "type": "string"
+                },
+                "positionsLimit": {
+                    "description": "positions fetched per poll",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.PricesConfig": {
+            "type": "object",
+            "properties": {
+                "roundToTick": {
+                    "description": "round prices to the token's tick size unless ?round=none",
+                    "type": "boolean"
+                }
+            }
+        },
+        "config.RateLimitConfig": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "description": "fixed_window, sliding_window or token_bucket",
+                    "type": "string"
+                },
+                "burst": {
+                    "description": "token bucket capacity (0 = the limit)",
+                    "type": "integer"
+                },
+                "routes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.RouteRateLimit"
+                    }
+                }
+            }
+        },
+        "config.RawProxyConfig": {
+            "type": "object",
+            "properties": {
+                "allowlist": {
+                    "description": "\"\u003cclob|gamma|data|*\u003e:\u003cpath prefix\u003e\" entries; empty allows nothing",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cacheTTL": {
+                    "description": "GET responses are cached only when \u003e 0",
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "config.RecorderConfig": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "maxMarkets": {
+                    "description": "top markets by 24h volume to record",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "storage key candles are saved to (empty = memory only)",
+                    "type": "string"
+                },
+                "resolution": {
+                    "description": "candle width",
+                    "type": "integer"
+                },
+                "retention": {
+                    "description": "how much history is kept",
+                    "type": "integer"
+                },
+                "sampleInterval": {
+                    "description": "how often prices are sampled",
+                    "type": "integer"
+                },
+                "saveInterval": {
+                    "description": "how often candles are saved",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.ReplicationConfig": {
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "description": "primary (talks to Polymarket) or replica",
+                    "type": "string"
+                },
+                "primaryURL": {
+                    "description": "base URL of the primary, e.g. http://polygo:8080",
+                    "type": "string"
+                },
+                "serveReplicas": {
+                    "description": "expose /replica endpoints on this instance",
+                    "type": "boolean"
+                },
+                "token": {
+                    "description": "shared secret between primary and replicas",
+                    "type": "string"
+                }
+            }
+        },
+        "config.RiskConfig": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/config.RiskLimits"
+                    }
+                },
+                "default": {
+                    "$ref": "#/definitions/config.RiskLimits"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "path": {
+                    "description": "file admin API changes are saved to (empty = memory only)",
+                    "type": "string"
+                }
+            }
+        },
+        "config.RiskLimits": {
+            "type": "object",
+            "properties": {
+                "banned_markets": {
+                    "description": "token IDs, market IDs, condition IDs or slugs",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_open_notional": {
+                    "description": "USDC across open orders, including new ones",
+                    "type": "number"
+                },
+                "max_order_size": {
+                    "description": "shares per order",
+                    "type": "number"
+                },
+                "max_orders_per_minute": {
+                    "description": "orders accepted per rolling minute",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.RouteRateLimit": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "description": "empty = the global algorithm",
+                    "type": "string"
+                },
+                "burst": {
+                    "description": "0 = the global burst",
+                    "type": "integer"
+                },
+                "limit": {
+                    "description": "requests per window (0 = the global limit)",
+                    "type": "integer"
+                },
+                "prefix": {
+                    "description": "e.g. /api/v1/orders",
+                    "type": "string"
+                },
+                "window": {
+                    "description": "0 = the global window",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.RoutingAccount": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "maker wallet",
+                    "type": "string"
+                },
+                "apikey": {
+                    "description": "L2 credentials",
+                    "type": "string"
+                },
+                "maxExposure": {
+                    "description": "USDC across the account's open orders, including a new one (0 = unlimited)",
+                    "type": "number"
+                },
+                "passphrase": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.RoutingConfig": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "description": "named accounts orders may be routed to",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/config.RoutingAccount"
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "routes": {
+                    "description": "tried in order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.OrderRoute"
+                    }
+                }
+            }
+        },
+        "config.RuleAccount": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "maker wallet",
+                    "type": "string"
+                },
+                "apikey": {
+                    "description": "L2 credentials",
+                    "type": "string"
+                },
+                "passphrase": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.RuleAction": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "description": "order: a configured account name",
+                    "type": "string"
+                },
+                "order_type": {
+                    "description": "order: GTC (default) or FOK",
+                    "type": "string"
+                },
+                "price": {
+                    "description": "order: default the event's best ask (BUY) or bid (SELL), else its price",
+                    "type": "number"
+                },
+                "side": {
+                    "description": "order: BUY or SELL",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "order: shares",
+                    "type": "number"
+                },
+                "token_id": {
+                    "description": "order: default the event's token",
+                    "type": "string"
+                },
+                "topic": {
+                    "description": "publish: Kafka topic or NATS subject",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "webhook, publish, log or order",
+                    "type": "string"
+                }
+            }
+        },
+        "config.RuleSpec": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.RuleAction"
+                    }
+                },
+                "condition": {
+                    "description": "e.g. volume_24h \u003e 1e6 AND price_change_5m \u003e 0.05",
+                    "type": "string"
+                },
+                "cooldown": {
+                    "description": "e.g. 10m (default: the configured cooldown)",
+                    "type": "string"
+                },
+                "disabled": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.RulesConfig": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "description": "named accounts order actions trade for",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/config.RuleAccount"
+                    }
+                },
+                "cooldown": {
+                    "description": "how long a rule stays quiet for a market after firing, unless the rule sets its own",
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "history": {
+                    "description": "triggers kept per rule",
+                    "type": "integer"
+                },
+                "markets": {
+                    "description": "markets kept subscribed upstream for the rules, as in WebSocket subscriptions",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "maxPriceWindow": {
+                    "description": "longest price_change_\u003cwindow\u003e a condition may read",
+                    "type": "integer"
+                },
+                "maxRules": {
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "file admin API changes are saved to (empty = memory only)",
+                    "type": "string"
+                },
+                "queueSize": {
+                    "description": "triggers waiting for their actions; more are dropped",
+                    "type": "integer"
+                },
+                "rules": {
+                    "description": "initial rules; rules saved at Path replace them",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.RuleSpec"
+                    }
+                }
+            }
+        },
+        "config.SecretsConfig": {
+            "type": "object",
+            "properties": {
+                "awsaccessKeyID": {
+                    "type": "string"
+                },
+                "awsendpoint": {
+                    "description": "Secrets Manager endpoint (default: the region's)",
+                    "type": "string"
+                },
+                "awsregion": {
+                    "type": "string"
+                },
+                "awssecretAccessKey": {
+                    "type": "string"
+                },
+                "awssessionToken": {
+                    "type": "string"
+                },
+                "refreshInterval": {
+                    "description": "how often references are read again to catch rotations (0 = at startup only)",
+                    "type": "integer"
+                },
+                "timeout": {
+                    "description": "per read",
+                    "type": "integer"
+                },
+                "vaultAddr": {
+                    "type": "string"
+                },
+                "vaultNamespace": {
+                    "description": "Vault Enterprise namespace",
+                    "type": "string"
+                },
+                "vaultToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "config.ServerConfig": {
+            "type": "object",
+            "properties": {
+                "bodyLimit": {
+                    "description": "Request body size limits in bytes",
+                    "type": "integer"
+                },
+                "bookSnapshotEvery": {
+                    "description": "Order book deltas sent to WS clients that opt in with ?books=delta",
+                    "type": "integer"
+                },
+                "corsallowCredentials": {
+                    "type": "boolean"
+                },
+                "corsorigins": {
+                    "description": "CORS",
+                    "type": "string"
+                },
+                "debug": {
+                    "type": "boolean"
+                },
+                "drainTimeout": {
+                    "description": "Shutdown/drain behavior",
+                    "type": "integer"
+                },
+                "goroutineBudget": {
+                    "description": "Goroutines expected at most; /stats flags a count above it, which\nusually means a leak (0 = unchecked)",
+                    "type": "integer"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "idleTimeout": {
+                    "type": "integer"
+                },
+                "jsonbodyLimit": {
+                    "description": "other JSON endpoints (webhooks, watchlist, copy trading, admin)",
+                    "type": "integer"
+                },
+                "listen": {
+                    "description": "Listeners used instead of Host:Port when set: a comma-separated list of\nunix:///path/to.sock, tcp://host:port and systemd (socket activation)",
+                    "type": "string"
+                },
+                "orderBodyLimit": {
+                    "description": "order placement and cancellation",
+                    "type": "integer"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "prefork": {
+                    "type": "boolean"
+                },
+                "proxyHeader": {
+                    "description": "X-Forwarded-For or X-Real-IP",
+                    "type": "string"
+                },
+                "readTimeout": {
+                    "type": "integer"
+                },
+                "reconnectHint": {
+                    "description": "delay suggested to WS clients",
+                    "type": "integer"
+                },
+                "shutdownTimeout": {
+                    "description": "max wait for open connections",
+                    "type": "integer"
+                },
+                "slowRequestThreshold": {
+                    "description": "Requests slower than this are logged with their upstream/proxy split (0 disables)",
+                    "type": "integer"
+                },
+                "socketMode": {
+                    "description": "octal permissions of unix sockets",
+                    "type": "string"
+                },
+                "trustedProxies": {
+                    "description": "Reverse proxies trusted to report the client IP in ProxyHeader: IPs,\nCIDRs, or unix for connections over a unix socket. Empty trusts none.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "writeTimeout": {
+                    "type": "integer"
+                },
+                "wsbufferLimit": {
+                    "description": "Bytes of frames queued for slow WS clients; a client over its own\nlimit, or the ones furthest behind once the total is reached, are\ndisconnected (0 = unlimited)",
+                    "type": "integer"
+                },
+                "wsclientBufferLimit": {
+                    "description": "one client",
+                    "type": "integer"
+                },
+                "wsreplayWindow": {
+                    "description": "How long market WS messages are kept for clients reconnecting with\n?since= (0 disables replay and stream_seq)",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.SnapshotConfig": {
+            "type": "object",
+            "properties": {
+                "concurrency": {
+                    "description": "concurrent upstream fetches per request",
+                    "type": "integer"
+                },
+                "maxTokens": {
+                    "description": "token IDs accepted per request",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.StorageConfig": {
+            "type": "object",
+            "properties": {
+                "accessKeyID": {
+                    "description": "for gcs, an HMAC key",
+                    "type": "string"
+                },
+                "backend": {
+                    "description": "local, s3 or gcs",
+                    "type": "string"
+                },
+                "bucket": {
+                    "type": "string"
+                },
+                "endpoint": {
+                    "description": "S3-compatible endpoint URL without a path; defaults to AWS for s3 and storage.googleapis.com for gcs",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "object name prefix inside the bucket",
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "secretAccessKey": {
+                    "type": "string"
+                },
+                "timeout": {
+                    "description": "per request",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.StrategiesConfig": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "maxOrders": {
+                    "description": "tagged orders kept across all callers; the oldest are dropped",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "file orders and fills are saved to (empty = memory only)",
+                    "type": "string"
+                }
+            }
+        },
+        "config.TapeConfig": {
+            "type": "object",
+            "properties": {
+                "dir": {
+                    "description": "directory holding the recording",
+                    "type": "string"
+                },
+                "mode": {
+                    "description": "off, record or replay",
+                    "type": "string"
+                },
+                "wsloop": {
+                    "description": "restart WebSocket replay at the end of the recording",
+                    "type": "boolean"
+                },
+                "wsspeed": {
+                    "description": "replay speed of WebSocket frames; 0 sends them without delay",
+                    "type": "number"
+                }
+            }
+        },
+        "config.TaxReportConfig": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "maxActivity": {
+                    "description": "activity entries fetched per report, newest first",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.TenantSpec": {
+            "type": "object",
+            "properties": {
+                "apikeys": {
+                    "description": "POLY-API-KEY values",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rateLimit": {
+                    "description": "requests per 10s (0 = the default)",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.TenantsConfig": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "rateLimit": {
+                    "description": "requests per 10s per tenant, unless the tenant sets its own",
+                    "type": "integer"
+                },
+                "saveInterval": {
+                    "type": "integer"
+                },
+                "tenants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.TenantSpec"
+                    }
+                },
+                "usagePath": {
+                    "description": "file usage is saved to (empty = memory only)",
+                    "type": "string"
+                },
+                "usageRetention": {
+                    "description": "days of usage kept",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.ThrottleConfig": {
+            "type": "object",
+            "properties": {
+                "duplicateWindow": {
+                    "description": "an order identical to one the key sent this recently is rejected (0 = allowed)",
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "marketRate": {
+                    "description": "orders a key may send to one market per MarketWindow (0 = unlimited)",
+                    "type": "integer"
+                },
+                "marketWindow": {
+                    "description": "rolling window MarketRate is counted over",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.TickerConfig": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "interval": {
+                    "description": "minimum time between updates per token",
+                    "type": "integer"
+                },
+                "topMarkets": {
+                    "description": "top markets by 24h volume to stream",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.WatchlistConfig": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "interval": {
+                    "description": "how often each wallet's trades are polled",
+                    "type": "integer"
+                },
+                "marketInterval": {
+                    "description": "how often watched markets are polled",
+                    "type": "integer"
+                },
+                "maxMarkets": {
+                    "description": "markets watched across all callers",
+                    "type": "integer"
+                },
+                "maxWallets": {
+                    "description": "wallets watched across all callers",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "file watchlists are saved to (empty = memory only)",
+                    "type": "string"
+                },
+                "tradesLimit": {
+                    "description": "recent trades fetched per poll",
+                    "type": "integer"
+                }
+            }
+        },
+        "config.WebhooksConfig": {
+            "type": "object",
+            "properties": {
+                "cafile": {
+                    "description": "PEM CAs endpoint certificates may chain to, besides the system ones",
+                    "type": "string"
+                },
+                "clientCert": {
+                    "description": "PEM certificate presented to endpoints that require mTLS",
+                    "type": "string"
+                },
+                "clientKey": {
+                    "description": "its PEM private key",
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "maxAttempts": {
+                    "type": "integer"
+                },
+                "maxDeliveries": {
+                    "description": "delivery records kept for tracing",
+                    "type": "integer"
+                },
+                "queueSize": {
+                    "type": "integer"
+                },
+                "retryBackoff": {
+                    "type": "integer"
+                },
+                "timeout": {
+                    "type": "integer"
+                },
+                "workers": {
+                    "type": "integer"
+                }
+            }
+        },
+        "copytrade.Mirror": {
+            "type": "object",
+            "properties": {
+                "order_id": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "side": {
+                    "$ref": "#/definitions/models.Side"
+                },
+                "size": {
+                    "description": "shares ordered for the local account",
+                    "type": "number"
+                },
+                "source_tx": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "target_size": {
+                    "type": "number"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "token_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "copytrade.Options": {
+            "type": "object",
+            "properties": {
+                "max_exposure": {
+                    "type": "number"
+                },
+                "max_order_notional": {
+                    "type": "number"
+                },
+                "scale": {
+                    "type": "number"
+                },
+                "target": {
+                    "type": "string"
+                }
+            }
+        },
+        "copytrade.Status": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "type": "string"
+                },
+                "exposure": {
+                    "description": "net USDC bought this session",
+                    "type": "number"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_polled": {
+                    "type": "string"
+                },
+                "options": {
+                    "$ref": "#/definitions/copytrade.Options"
+                },
+                "placed": {
+                    "type": "integer"
+                },
+                "recent": {
+                    "description": "newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/copytrade.Mirror"
+                    }
+                },
+                "running": {
+                    "type": "boolean"
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "digest.Digest": {
+            "type": "object",
+            "properties": {
+                "generated_at": {
+                    "type": "string"
+                },
+                "new_markets": {
+                    "description": "created in the last 24h, busiest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/digest.Market"
+                    }
+                },
+                "resolving": {
+                    "description": "ending within 24h, soonest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/digest.Market"
+                    }
+                },
+                "swings": {
+                    "description": "biggest probability moves over 24h",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/recorder.Mover"
+                    }
+                },
+                "volume_spikes": {
+                    "description": "highest ratio first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/digest.Spike"
+                    }
+                }
+            }
+        },
+        "digest.Market": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "event_slug": {
+                    "type": "string"
+                },
+                "market_id": {
+                    "type": "string"
+                },
+                "price": {
+                    "description": "first outcome",
+                    "type": "number"
+                },
+                "question": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "volume_24hr": {
+                    "type": "number"
+                }
+            }
+        },
+        "digest.Spike": {
+            "type": "object",
+            "properties": {
+                "avg_daily_volume": {
+                    "description": "over the last 7 days",
+                    "type": "number"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "event_slug": {
+                    "type": "string"
+                },
+                "market_id": {
+                    "type": "string"
+                },
+                "price": {
+                    "description": "first outcome",
+                    "type": "number"
+                },
+                "question": {
+                    "type": "string"
+                },
+                "ratio": {
+                    "description": "24h volume over the daily average",
+                    "type": "number"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "volume_24hr": {
+                    "type": "number"
+                }
+            }
+        },
+        "equity.Curve": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "max_drawdown": {
+                    "description": "largest fall of equity from a previous peak, as a fraction of the peak",
+                    "type": "number"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/equity.Point"
+                    }
+                },
+                "returns": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/equity.PeriodReturn"
+                    }
+                },
+                "sharpe": {
+                    "description": "annualized, zero risk-free rate; nil with fewer than two returns or no volatility",
+                    "type": "number"
+                },
+                "total_return": {
+                    "type": "number"
+                }
+            }
+        },
+        "equity.PeriodReturn": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "description": "last snapshot date in it",
+                    "type": "string"
+                },
+                "period": {
+                    "description": "first date of the period",
+                    "type": "string"
+                },
+                "return": {
+                    "type": "number"
+                },
+                "start": {
+                    "description": "first snapshot date in it",
+                    "type": "string"
+                }
+            }
+        },
+        "equity.Point": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "UTC, YYYY-MM-DD",
+                    "type": "string"
+                },
+                "equity": {
+                    "description": "growth of 1 invested at the first point",
+                    "type": "number"
+                },
+                "pnl": {
+                    "description": "unrealized plus realized PnL of those positions",
+                    "type": "number"
+                },
+                "positions": {
+                    "type": "integer"
+                },
+                "return": {
+                    "description": "since the previous point",
+                    "type": "number"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "value": {
+                    "description": "current value of every position, USDC",
+                    "type": "number"
+                }
+            }
+        },
+        "eventbus.Stats": {
+            "type": "object",
+            "properties": {
+                "dropped": {
+                    "description": "queue was full",
+                    "type": "integer"
+                },
+                "failed": {
+                    "description": "the bus rejected or was unreachable",
+                    "type": "integer"
+                },
+                "published": {
+                    "type": "integer"
+                }
+            }
+        },
+        "expiry.Order": {
+            "type": "object",
+            "properties": {
+                "auto_cancel": {
+                    "description": "false when no API secret was supplied to cancel with",
+                    "type": "boolean"
+                },
+                "cancel_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "maker": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "export.Job": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "trades: the wallet",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dataset": {
+                    "description": "trades, candles or catalog",
+                    "type": "string"
+                },
+                "format": {
+                    "description": "csv or parquet",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "interval": {
+                    "description": "e.g. 1h, 24h",
+                    "type": "string"
+                },
+                "last_run": {
+                    "$ref": "#/definitions/export.Run"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "next_run": {
+                    "description": "unset while paused",
+                    "type": "string"
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "prefix": {
+                    "description": "directory under the export directory (default: the job ID)",
+                    "type": "string"
+                },
+                "token_ids": {
+                    "description": "candles: the tokens",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "window": {
+                    "description": "candles: how far back (default: recorder retention)",
+                    "type": "string"
+                }
+            }
+        },
+        "export.Run": {
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "location": {
+                    "description": "file path or bucket URL written",
+                    "type": "string"
+                },
+                "rows": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "truncated": {
+                    "description": "more than MaxRows rows were available",
+                    "type": "boolean"
+                }
+            }
+        },
+        "export.Spec": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "trades: the wallet",
+                    "type": "string"
+                },
+                "dataset": {
+                    "description": "trades, candles or catalog",
+                    "type": "string"
+                },
+                "format": {
+                    "description": "csv or parquet",
+                    "type": "string"
+                },
+                "interval": {
+                    "description": "e.g. 1h, 24h",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "prefix": {
+                    "description": "directory under the export directory (default: the job ID)",
+                    "type": "string"
+                },
+                "token_ids": {
+                    "description": "candles: the tokens",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "window": {
+                    "description": "candles: how far back (default: recorder retention)",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.AddMarketRequest": {
+            "type": "object",
+            "properties": {
+                "market_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.AddWalletRequest": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.BatchCancelRequest": {
+            "type": "object",
+            "required": [
+                "orderIds"
+            ],
+            "properties": {
+                "orderIds": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.CategoryCount": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "markets": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.CreateWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "description": "signs deliveries; generated when empty",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "only market.listed events for markets with one of these tag slugs, and rule.triggered events of these rules",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "dependencies": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/polymarket.ProbeResult"
+                    }
+                },
+                "failover": {
+                    "description": "APIs with mirrors",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/polymarket.FailoverStatus"
+                    }
+                },
+                "feeds": {
+                    "description": "last upstream WS message per channel, unix ms",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "services": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "integer"
+                },
+                "uptime": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.PairRequest": {
+            "type": "object",
+            "properties": {
+                "legs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreateOrderRequest"
+                    }
+                }
+            }
+        },
+        "handlers.PositionAlertRequest": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "ignored on update",
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "min_size": {
+                    "description": "shares a position must change by; 0 for any change",
+                    "type": "number"
+                },
+                "min_value": {
+                    "description": "USDC, at the current price, a position must change by",
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.ReadyResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "ready": {
+                    "type": "boolean"
+                },
+                "timestamp": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.RotateWebhookSecretRequest": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "description": "generated when empty",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.StatsResponse": {
+            "type": "object",
+            "properties": {
+                "cache_hit_rate": {
+                    "type": "number"
+                },
+                "cache_sizes": {
+                    "$ref": "#/definitions/cache.SizeStats"
+                },
+                "event_bus": {
+                    "description": "set when the event bus is enabled",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/eventbus.Stats"
+                        }
+                    ]
+                },
+                "go_version": {
+                    "type": "string"
+                },
+                "goroutine_budget": {
+                    "description": "0 = unchecked",
+                    "type": "integer"
+                },
+                "load_shedding": {
+                    "$ref": "#/definitions/middleware.ShedStats"
+                },
+                "mem_alloc_bytes": {
+                    "type": "integer"
+                },
+                "mem_sys_bytes": {
+                    "type": "integer"
+                },
+                "mem_total_bytes": {
+                    "type": "integer"
+                },
+                "num_cpu": {
+                    "type": "integer"
+                },
+                "num_goroutine": {
+                    "type": "integer"
+                },
+                "over_goroutine_budget": {
+                    "description": "num_goroutine exceeds the budget, likely a leak",
+                    "type": "boolean"
+                },
+                "routes": {
+                    "description": "latency percentiles per route, busiest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/latency.RouteStats"
+                    }
+                },
+                "timestamp": {
+                    "type": "integer"
+                },
+                "upstream_pools": {
+                    "description": "HTTP connections per upstream host",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/polymarket.PoolStats"
+                    }
+                },
+                "uptime": {
+                    "type": "string"
+                },
+                "ws_buffers": {
+                    "description": "frames queued for WebSocket clients",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/wsbuffer.Stats"
+                        }
+                    ]
+                },
+                "ws_shards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/polymarket.ShardStatus"
+                    }
+                }
+            }
+        },
+        "handlers.UpstreamPoolRequest": {
+            "type": "object",
+            "properties": {
+                "host": {
+                    "description": "host or host:port; empty changes the default of every host",
+                    "type": "string"
+                },
+                "max_conns_per_host": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.WSClientStats": {
+            "type": "object",
+            "properties": {
+                "client_ip": {
+                    "type": "string"
+                },
+                "connected_at": {
+                    "description": "unix ms",
+                    "type": "integer"
+                },
+                "encoding": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "queued_bytes": {
+                    "type": "integer"
+                },
+                "sent_bytes": {
+                    "type": "integer"
+                },
+                "sent_frames": {
+                    "type": "integer"
+                },
+                "sub_seq": {
+                    "description": "of the last frame queued, per subscription",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.WSStatsResponse": {
+            "type": "object",
+            "properties": {
+                "buffers": {
+                    "$ref": "#/definitions/wsbuffer.Stats"
+                },
+                "clients": {
+                    "description": "most bytes sent first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.WSClientStats"
+                    }
+                },
+                "connections": {
+                    "type": "integer"
+                },
+                "subscriptions": {
+                    "description": "most bytes sent first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.WSSubscriptionStats"
+                    }
+                },
+                "timestamp": {
+                    "type": "integer"
+                },
+                "upstream": {
+                    "$ref": "#/definitions/handlers.WSUpstreamStats"
+                }
+            }
+        },
+        "handlers.WSSubscriptionStats": {
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "type": "integer"
+                },
+                "clients": {
+                    "type": "integer"
+                },
+                "key": {
+                    "description": "token ID, market condition ID, or * for all markets",
+                    "type": "string"
+                },
+                "messages": {
+                    "description": "broadcast to its clients since the first subscribed",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.WSUpstreamStats": {
+            "type": "object",
+            "properties": {
+                "connected": {
+                    "type": "boolean"
+                },
+                "dropped_messages": {
+                    "description": "not delivered to a subscriber that fell behind",
+                    "type": "integer"
+                },
+                "gaps": {
+                    "description": "shard drops that lost messages of subscribed markets",
+                    "type": "integer"
+                },
+                "max_lag_ms": {
+                    "type": "integer"
+                },
+                "shards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/polymarket.ShardStatus"
+                    }
+                }
+            }
+        },
+        "handlers.WebhookWithSecret": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "description": "event types, \"*\" for all",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "tagged events (market.listed, rule.triggered) must carry one of these",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "latency.Percentiles": {
+            "type": "object",
+            "properties": {
+                "max": {
+                    "type": "number"
+                },
+                "p50": {
+                    "type": "number"
+                },
+                "p95": {
+                    "type": "number"
+                },
+                "p99": {
+                    "type": "number"
+                }
+            }
+        },
+        "latency.RouteStats": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "description": "5xx responses",
+                    "type": "integer"
+                },
+                "latency_ms": {
+                    "$ref": "#/definitions/latency.Percentiles"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "route": {
+                    "type": "string"
+                },
+                "upstream_ms": {
+                    "description": "time spent waiting on Polymarket",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/latency.Percentiles"
+                        }
+                    ]
+                }
+            }
+        },
+        "leaderboard.Point": {
+            "type": "object",
+            "properties": {
+                "pnl": {
+                    "type": "number"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "volume": {
+                    "type": "number"
+                }
+            }
+        },
+        "leaderboard.TraderHistory": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pnl": {
+                    "type": "number"
+                },
+                "pnl_change": {
+                    "description": "between the trader's first and last point in the window",
+                    "type": "number"
+                },
+                "points": {
+                    "description": "oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/leaderboard.Point"
+                    }
+                },
+                "previous_rank": {
+                    "description": "in the first snapshot of the window; 0 if not yet ranked",
+                    "type": "integer"
+                },
+                "rank": {
+                    "description": "in the latest snapshot; 0 if no longer ranked",
+                    "type": "integer"
+                },
+                "rank_change": {
+                    "description": "places climbed (negative = fell); 0 unless ranked at both ends",
+                    "type": "integer"
+                }
+            }
+        },
+        "middleware.ClassShedStats": {
+            "type": "object",
+            "properties": {
+                "in_flight": {
+                    "type": "integer"
+                },
+                "max": {
+                    "type": "integer"
+                },
+                "rejected": {
+                    "type": "integer"
+                }
+            }
+        },
+        "middleware.ShedStats": {
+            "type": "object",
+            "properties": {
+                "classes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/middleware.ClassShedStats"
+                    }
+                },
+                "in_flight": {
+                    "type": "integer"
+                },
+                "rejected": {
+                    "description": "requests refused at a limit",
+                    "type": "integer"
+                },
+                "shed": {
+                    "description": "reads refused under load because they missed the cache",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Activity": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "string"
+                },
+                "asset": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "market": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "string"
+                },
+                "side": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "transactionHash": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ClobReward": {
+            "type": "object",
+            "properties": {
+                "assetAddress": {
+                    "type": "string"
+                },
+                "conditionId": {
+                    "type": "string"
+                },
+                "endDate": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "rewardsAmount": {
+                    "type": "number"
+                },
+                "rewardsDailyRate": {
+                    "description": "paid across the market's makers per day",
+                    "type": "number"
+                },
+                "startDate": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateOrderRequest": {
+            "type": "object",
+            "required": [
+                "price",
+                "side",
+                "size",
+                "tokenID"
+            ],
+            "properties": {
+                "expiration": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "maker": {
+                    "description": "wallet address; enables cached user-data invalidation on fills",
+                    "type": "string"
+                },
+                "price": {
+                    "description": "may be omitted with price_mode, which sets it",
+                    "type": "string"
+                },
+                "price_mode": {
+                    "description": "price relative to the live book, e.g. best_bid+1tick; resolved before sending",
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "side": {
+                    "enum": [
+                        "BUY",
+                        "SELL"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Side"
+                        }
+                    ]
+                },
+                "size": {
+                    "type": "string"
+                },
+                "strategy": {
+                    "description": "attributes fills to a strategy in /analytics/strategies; not sent upstream",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "tokenID": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "type": {
+                    "enum": [
+                        "GTC",
+                        "FOK",
+                        "GTD"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.OrderType"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.Event": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "archived": {
+                    "type": "boolean"
+                },
+                "closed": {
+                    "type": "boolean"
+                },
+                "coverImage": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "endDate": {
+                    "type": "string"
+                },
+                "featured": {
+                    "type": "boolean"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "liquidity": {
+                    "type": "string"
+                },
+                "liquidityClaimable": {
+                    "type": "boolean"
+                },
+                "markets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Market"
+                    }
+                },
+                "new": {
+                    "type": "boolean"
+                },
+                "restricted": {
+                    "type": "boolean"
+                },
+                "rewardsMaxSpread": {
+                    "type": "number"
+                },
+                "rewardsMinSize": {
+                    "type": "number"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "spreadMultiplierMax": {
+                    "type": "number"
+                },
+                "spreadMultiplierMin": {
+                    "type": "number"
+                },
+                "startDate": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                },
+                "ticker": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "volume": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Market": {
+            "type": "object",
+            "properties": {
+                "acceptingOrders": {
+                    "type": "boolean"
+                },
+                "acceptingOrdersTimestamp": {
+                    "type": "string"
+                },
+                "active": {
+                    "type": "boolean"
+                },
+                "bestAsk": {
+                    "type": "string"
+                },
+                "bestBid": {
+                    "type": "string"
+                },
+                "clobRewards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ClobReward"
+                    }
+                },
+                "clobTokenIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "closed": {
+                    "type": "boolean"
+                },
+                "conditionId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enableOrderBook": {
+                    "type": "boolean"
+                },
+                "endDate": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "liquidity": {
+                    "type": "string"
+                },
+                "marketType": {
+                    "type": "string"
+                },
+                "negRisk": {
+                    "type": "boolean"
+                },
+                "negRiskMarketId": {
+                    "type": "string"
+                },
+                "negRiskRequestId": {
+                    "type": "string"
+                },
+                "orderMinSize": {
+                    "type": "number"
+                },
+                "orderPriceMinTickSize": {
+                    "type": "number"
+                },
+                "outcomePrices": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "outcomes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "question": {
+                    "type": "string"
+                },
+                "rewardsMaxSpread": {
+                    "type": "number"
+                },
+                "rewardsMinSize": {
+                    "type": "number"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "spread": {
+                    "type": "string"
+                },
+                "spreadMultiplierMax": {
+                    "type": "number"
+                },
+                "spreadMultiplierMin": {
+                    "type": "number"
+                },
+                "volume": {
+                    "type": "string"
+                },
+                "volume1wk": {
+                    "type": "string"
+                },
+                "volume24hr": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Order": {
+            "type": "object",
+            "properties": {
+                "asset_id": {
+                    "type": "string"
+                },
+                "associate_trade_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expiration": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "market": {
+                    "type": "string"
+                },
+                "original_size": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "string"
+                },
+                "side": {
+                    "$ref": "#/definitions/models.Side"
+                },
+                "size_matched": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.OrderStatus"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.OrderType"
+                }
+            }
+        },
+        "models.OrderBook": {
+            "type": "object",
+            "properties": {
+                "asks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceLevel"
+                    }
+                },
+                "bids": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceLevel"
+                    }
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "integer"
+                },
+                "token_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OrderStatus": {
+            "type": "string",
+            "enum": [
+                "LIVE",
+                "MATCHED",
+                "CANCELLED"
+            ],
+            "x-enum-varnames": [
+                "OrderStatusLive",
+                "OrderStatusMatched",
+                "OrderStatusCancelled"
+            ]
+        },
+        "models.OrderType": {
+            "type": "string",
+            "enum": [
+                "GTC",
+                "FOK",
+                "GTD"
+            ],
+            "x-enum-comments": {
+                "OrderTypeFOK": "Fill Or Kill",
+                "OrderTypeGTC": "Good Till Cancelled",
+                "OrderTypeGTD": "Good Till Date"
+            },
+            "x-enum-varnames": [
+                "OrderTypeGTC",
+                "OrderTypeFOK",
+                "OrderTypeGTD"
+            ]
+        },
+        "models.Position": {
+            "type": "object",
+            "properties": {
+                "asset": {
+                    "type": "string"
+                },
+                "avgCost": {
+                    "type": "string"
+                },
+                "conditionId": {
+                    "type": "string"
+                },
+                "curVal": {
+                    "type": "string"
+                },
+                "currentPrice": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "type": "string"
+                },
+                "outcomeIndex": {
+                    "type": "integer"
+                },
+                "percentChange": {
+                    "type": "number"
+                },
+                "proxyWalletAddress": {
+                    "type": "string"
+                },
+                "realizedPnl": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "string"
+                },
+                "totalBought": {
+                    "type": "string"
+                },
+                "totalSold": {
+                    "type": "string"
+                },
+                "unrealizedPnl": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Price": {
+            "type": "object",
+            "properties": {
+                "price": {
+                    "type": "string"
+                },
+                "side": {
+                    "$ref": "#/definitions/models.Side"
+                },
+                "token_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PriceLevel": {
+            "type": "object",
+            "properties": {
+                "price": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Side": {
+            "type": "string",
+            "enum": [
+                "BUY",
+                "SELL"
+            ],
+            "x-enum-varnames": [
+                "SideBuy",
+                "SideSell"
+            ]
+        },
+        "models.Spread": {
+            "type": "object",
+            "properties": {
+                "ask": {
+                    "type": "string"
+                },
+                "bid": {
+                    "type": "string"
+                },
+                "spread_abs": {
+                    "type": "string"
+                },
+                "spread_pct": {
+                    "type": "string"
+                },
+                "token_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Tag": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Trade": {
+            "type": "object",
+            "properties": {
+                "asset_id": {
+                    "type": "string"
+                },
+                "bucket_index": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "fee": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "market": {
+                    "type": "string"
+                },
+                "match_time": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "string"
+                },
+                "side": {
+                    "$ref": "#/definitions/models.Side"
+                },
+                "size": {
+                    "type": "string"
+                },
+                "taker_order_id": {
+                    "type": "string"
+                },
+                "trader_side": {
+                    "type": "string"
+                },
+                "transaction_hash": {
+                    "type": "string"
+                }
+            }
+        },
+        "pairs.Leg": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "order": {
+                    "$ref": "#/definitions/models.CreateOrderRequest"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "size_matched": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "pairs.Pair": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "legs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pairs.Leg"
+                    }
+                },
+                "note": {
+                    "description": "why a rollback did not complete",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "polymarket.BalanceReport": {
+            "type": "object",
+            "properties": {
+                "allowances": {
+                    "description": "by exchange contract address",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "available": {
+                    "description": "balance not reserved by open BUY orders",
+                    "type": "number"
+                },
+                "balance": {
+                    "type": "number"
+                },
+                "can_trade": {
+                    "type": "boolean"
+                },
+                "open_buy_notional": {
+                    "description": "reserved by open BUY orders",
+                    "type": "number"
+                },
+                "tokens": {
+                    "description": "behind open SELL orders",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/polymarket.TokenBalance"
+                    }
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "polymarket.DriftReport": {
+            "type": "object",
+            "properties": {
+                "breaking": {
+                    "description": "payloads missing fields or with mismatched types",
+                    "type": "integer"
+                },
+                "checked": {
+                    "description": "payloads inspected",
+                    "type": "integer"
+                },
+                "endpoint": {
+                    "type": "string"
+                },
+                "last_drift_ms": {
+                    "description": "unix ms of the last breaking payload",
+                    "type": "integer"
+                },
+                "last_error": {
+                    "description": "most recent decode error",
+                    "type": "string"
+                },
+                "missing_fields": {
+                    "description": "expected fields absent, by payloads seen in",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "model": {
+                    "type": "string"
+                },
+                "unknown_fields": {
+                    "description": "fields the model does not have, by payloads seen in",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "polymarket.FailoverStatus": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "URL requests go to",
+                    "type": "string"
+                },
+                "failed_over": {
+                    "description": "active is a mirror",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "switched_at": {
+                    "type": "string"
+                },
+                "urls": {
+                    "description": "base URL first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/polymarket.MirrorStatus"
+                    }
+                }
+            }
+        },
+        "polymarket.MarketDetail": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "description": "partial failures by section",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "market": {
+                    "type": "object"
+                },
+                "recent_trades": {
+                    "type": "object"
+                },
+                "tokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/polymarket.OutcomeSnapshot"
+                    }
+                },
+                "volume_24hr": {
+                    "type": "number"
+                }
+            }
+        },
+        "polymarket.MirrorStatus": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "of the last failed check or request",
+                    "type": "string"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "polymarket.OrderPreview": {
+            "type": "object",
+            "properties": {
+                "avg_price": {
+                    "description": "nil when nothing fills",
+                    "type": "number"
+                },
+                "best_price": {
+                    "description": "best opposite price before the order; nil on an empty side",
+                    "type": "number"
+                },
+                "fee": {
+                    "description": "taker fee valued in USDC",
+                    "type": "number"
+                },
+                "fee_rate_bps": {
+                    "type": "number"
+                },
+                "fee_shares": {
+                    "description": "BUY: shares withheld as the fee",
+                    "type": "number"
+                },
+                "filled_size": {
+                    "type": "number"
+                },
+                "fills": {
+                    "description": "levels taken, best first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/polymarket.PreviewFill"
+                    }
+                },
+                "killed": {
+                    "description": "FOK: the book cannot fill it whole, so nothing fills",
+                    "type": "boolean"
+                },
+                "notional": {
+                    "description": "USDC paid (BUY) or received before the fee (SELL)",
+                    "type": "number"
+                },
+                "price": {
+                    "description": "limit",
+                    "type": "number"
+                },
+                "remaining_size": {
+                    "type": "number"
+                },
+                "resting": {
+                    "description": "GTC/GTD: shares left on the book as a maker order",
+                    "type": "number"
+                },
+                "side": {
+                    "$ref": "#/definitions/models.Side"
+                },
+                "size": {
+                    "type": "number"
+                },
+                "slippage": {
+                    "description": "how much worse AvgPrice is than BestPrice",
+                    "type": "number"
+                },
+                "token_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.OrderType"
+                },
+                "worst_price": {
+                    "description": "of the last level taken",
+                    "type": "number"
+                }
+            }
+        },
+        "polymarket.OutcomeSnapshot": {
+            "type": "object",
+            "properties": {
+                "ask": {
+                    "type": "number"
+                },
+                "ask_depth": {
+                    "description": "total size resting on the ask side",
+                    "type": "number"
+                },
+                "bid": {
+                    "type": "number"
+                },
+                "bid_depth": {
+                    "description": "total size resting on the bid side",
+                    "type": "number"
+                },
+                "change_24h": {
+                    "type": "number"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "last": {
+                    "type": "number"
+                },
+                "mid": {
+                    "type": "number"
+                },
+                "outcome": {
+                    "type": "string"
+                },
+                "spread": {
+                    "type": "number"
+                },
+                "token_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "polymarket.PayoutQuote": {
+            "type": "object",
+            "properties": {
+                "breakeven": {
+                    "description": "outcome probability at which the order has zero expected value",
+                    "type": "number"
+                },
+                "fee": {
+                    "description": "fee valued in USDC",
+                    "type": "number"
+                },
+                "fee_rate_bps": {
+                    "type": "number"
+                },
+                "fee_shares": {
+                    "description": "BUY: shares withheld as the fee",
+                    "type": "number"
+                },
+                "maker": {
+                    "type": "boolean"
+                },
+                "max_loss": {
+                    "description": "BUY: net cost; SELL: payout forgone less net proceeds",
+                    "type": "number"
+                },
+                "max_payout": {
+                    "description": "BUY: paid if the outcome wins; SELL: forgone if it wins",
+                    "type": "number"
+                },
+                "max_profit": {
+                    "description": "BUY: max payout less net cost; SELL: net proceeds, kept if it loses",
+                    "type": "number"
+                },
+                "neg_risk": {
+                    "type": "boolean"
+                },
+                "net_cost": {
+                    "description": "BUY: USDC paid",
+                    "type": "number"
+                },
+                "net_proceeds": {
+                    "description": "SELL: USDC received after the fee",
+                    "type": "number"
+                },
+                "notional": {
+                    "description": "price × size",
+                    "type": "number"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "shares": {
+                    "description": "shares received (BUY) or given up (SELL)",
+                    "type": "number"
+                },
+                "side": {
+                    "$ref": "#/definitions/models.Side"
+                },
+                "size": {
+                    "type": "number"
+                },
+                "tick_size": {
+                    "type": "number"
+                },
+                "token_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "polymarket.PoolStats": {
+            "type": "object",
+            "properties": {
+                "avg_lifetime_ms": {
+                    "description": "of closed connections",
+                    "type": "number"
+                },
+                "closed": {
+                    "type": "integer"
+                },
+                "dialed": {
+                    "description": "connections opened since startup",
+                    "type": "integer"
+                },
+                "host": {
+                    "description": "host:port",
+                    "type": "string"
+                },
+                "in_flight": {
+                    "description": "requests being served or waiting",
+                    "type": "integer"
+                },
+                "max_conns": {
+                    "type": "integer"
+                },
+                "max_lifetime_ms": {
+                    "type": "integer"
+                },
+                "open_conns": {
+                    "description": "busy or idle",
+                    "type": "integer"
+                },
+                "pending_acquires": {
+                    "description": "requests waiting for a free connection",
+                    "type": "integer"
+                }
+            }
+        },
+        "polymarket.PreviewFill": {
+            "type": "object",
+            "properties": {
+                "price": {
+                    "type": "number"
+                },
+                "size": {
+                    "type": "number"
+                }
+            }
+        },
+        "polymarket.ProbeResult": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
+                "last_success": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "polymarket.ShardStatus": {
+            "type": "object",
+            "properties": {
+                "connected": {
+                    "type": "boolean"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "lag_ms": {
+                    "description": "how far behind the upstream event time its latest message arrived",
+                    "type": "integer"
+                },
+                "last_message_ms": {
+                    "description": "unix ms, 0 if never",
+                    "type": "integer"
+                },
+                "last_pong_ms": {
+                    "description": "unix ms, 0 if never",
+                    "type": "integer"
+                },
+                "markets": {
+                    "description": "markets currently subscribed on this shard",
+                    "type": "integer"
+                }
+            }
+        },
+        "polymarket.TokenBalance": {
+            "type": "object",
+            "properties": {
+                "approved": {
+                    "description": "the exchange may transfer the shares",
+                    "type": "boolean"
+                },
+                "balance": {
+                    "description": "shares held",
+                    "type": "number"
+                },
+                "required": {
+                    "description": "shares offered by open SELL orders",
+                    "type": "number"
+                },
+                "token_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "polymarket.TokenSnapshot": {
+            "type": "object",
+            "properties": {
+                "ask": {
+                    "type": "number"
+                },
+                "ask_depth": {
+                    "description": "total size resting on the ask side",
+                    "type": "number"
+                },
+                "bid": {
+                    "type": "number"
+                },
+                "bid_depth": {
+                    "description": "total size resting on the bid side",
+                    "type": "number"
+                },
+                "change_24h": {
+                    "type": "number"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "last": {
+                    "type": "number"
+                },
+                "mid": {
+                    "type": "number"
+                },
+                "spread": {
+                    "type": "number"
+                },
+                "token_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "polymarket.TraderProfile": {
+            "type": "object",
+            "properties": {
+                "activity": {
+                    "type": "object"
+                },
+                "address": {
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "partial failures by section",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "positions": {
+                    "type": "object"
+                },
+                "recent_trades": {
+                    "type": "object"
+                },
+                "stats": {
+                    "$ref": "#/definitions/polymarket.TraderStats"
+                }
+            }
+        },
+        "polymarket.TraderStats": {
+            "type": "object",
+            "properties": {
+                "exposure": {
+                    "description": "current value of open positions",
+                    "type": "number"
+                },
+                "open_positions": {
+                    "type": "integer"
+                },
+                "realized_pnl": {
+                    "type": "number"
+                },
+                "resolved_positions": {
+                    "type": "integer"
+                },
+                "trades": {
+                    "type": "integer"
+                },
+                "unrealized_pnl": {
+                    "type": "number"
+                },
+                "volume": {
+                    "description": "USDC traded across recent_trades",
+                    "type": "number"
+                },
+                "win_rate": {
+                    "description": "wins / resolved positions; nil until one resolves",
+                    "type": "number"
+                },
+                "wins": {
+                    "type": "integer"
+                }
+            }
+        },
+        "posalert.Watch": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "last_polled": {
+                    "type": "string"
+                },
+                "min_size": {
+                    "description": "shares a position must change by; 0 for any change",
+                    "type": "number"
+                },
+                "min_value": {
+                    "description": "USDC, at the current price, a position must change by",
+                    "type": "number"
+                }
+            }
+        },
+        "recorder.Mover": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "type": "number"
+                },
+                "change_pct": {
+                    "type": "number"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "market_id": {
+                    "type": "string"
+                },
+                "price_end": {
+                    "type": "number"
+                },
+                "price_start": {
+                    "type": "number"
+                },
+                "question": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "volume": {
+                    "description": "volume traded within the window",
+                    "type": "number"
+                }
+            }
+        },
+        "response.ErrorInfo": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "string"
+                },
+                "fields": {
+                    "description": "Fields lists every invalid field of a rejected request body",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/validate.FieldError"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "retry_after_ms": {
+                    "type": "integer"
+                },
+                "retryable": {
+                    "description": "Retryable is set when the same request may succeed later (rate\nlimits, upstream outages), after RetryAfterMs when known",
+                    "type": "boolean"
+                }
+            }
+        },
+        "response.Meta": {
+            "type": "object",
+            "properties": {
+                "cache_hit": {
+                    "type": "boolean"
+                },
+                "cursor_refreshed": {
+                    "description": "CursorRefreshed is set when auto-pagination restarted after upstream expired a cursor",
+                    "type": "boolean"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.Response": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "error": {
+                    "$ref": "#/definitions/response.ErrorInfo"
+                },
+                "meta": {
+                    "$ref": "#/definitions/response.Meta"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "timestamp": {
+                    "type": "integer"
+                }
+            }
+        },
+        "rewards.Market": {
+            "type": "object",
+            "properties": {
+                "condition_id": {
+                    "type": "string"
+                },
+                "daily_rate": {
+                    "description": "USDC paid across the market's makers per day",
+                    "type": "number"
+                },
+                "eligible": {
+                    "type": "boolean"
+                },
+                "estimated_daily": {
+                    "description": "USDC per day if the book stayed as it is",
+                    "type": "number"
+                },
+                "market_id": {
+                    "type": "string"
+                },
+                "market_score": {
+                    "description": "the whole book's two-sided score",
+                    "type": "number"
+                },
+                "max_spread": {
+                    "description": "cents from the midpoint",
+                    "type": "number"
+                },
+                "midpoint": {
+                    "description": "of the first outcome",
+                    "type": "number"
+                },
+                "min_size": {
+                    "description": "shares per order",
+                    "type": "number"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rewards.Order"
+                    }
+                },
+                "question": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "score": {
+                    "description": "the maker's two-sided score",
+                    "type": "number"
+                },
+                "share": {
+                    "type": "number"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "rewards.Order": {
+            "type": "object",
+            "properties": {
+                "eligible": {
+                    "type": "boolean"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "side": {
+                    "$ref": "#/definitions/models.Side"
+                },
+                "size": {
+                    "description": "shares still resting",
+                    "type": "number"
+                },
+                "spread": {
+                    "description": "cents from the outcome's midpoint",
+                    "type": "number"
+                },
+                "token_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "rewards.Report": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "estimated_daily": {
+                    "type": "number"
+                },
+                "markets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rewards.Market"
+                    }
+                }
+            }
+        },
+        "risk.Limits": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/config.RiskLimits"
+                    }
+                },
+                "default": {
+                    "$ref": "#/definitions/config.RiskLimits"
+                }
+            }
+        },
+        "routing.AccountStatus": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "description": "of the last exposure lookup or placement",
+                    "type": "string"
+                },
+                "max_exposure": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "routed": {
+                    "description": "orders placed since start",
+                    "type": "integer"
+                },
+                "skipped": {
+                    "description": "times passed over at the exposure limit",
+                    "type": "integer"
+                }
+            }
+        },
+        "routing.Placement": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "type": "string"
+                },
+                "address": {
+                    "type": "string"
+                },
+                "order": {
+                    "description": "the CLOB's reply"
+                },
+                "route": {
+                    "description": "index of the route that matched",
+                    "type": "integer"
+                }
+            }
+        },
+        "routing.Status": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/routing.AccountStatus"
+                    }
+                },
+                "routes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.OrderRoute"
+                    }
+                }
+            }
+        },
+        "rules.ActionResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "rules.Rule": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.RuleAction"
+                    }
+                },
+                "condition": {
+                    "description": "e.g. volume_24h \u003e 1e6 AND price_change_5m \u003e 0.05",
+                    "type": "string"
+                },
+                "cooldown": {
+                    "description": "e.g. 10m (default: the configured cooldown)",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disabled": {
+                    "type": "boolean"
+                },
+                "dropped": {
+                    "description": "triggers lost to a full queue",
+                    "type": "integer"
+                },
+                "fields": {
+                    "description": "fields the condition reads",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "fired": {
+                    "type": "integer"
+                },
+                "last_fired": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "rules.Trigger": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rules.ActionResult"
+                    }
+                },
+                "asset_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "market": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "values": {
+                    "description": "fields the condition read",
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "strategies.Position": {
+            "type": "object",
+            "properties": {
+                "avg_price": {
+                    "type": "number"
+                },
+                "price": {
+                    "description": "current midpoint; nil if unavailable",
+                    "type": "number"
+                },
+                "size": {
+                    "description": "negative when the strategy sold more than it bought",
+                    "type": "number"
+                },
+                "token_id": {
+                    "type": "string"
+                },
+                "unrealized_pnl": {
+                    "type": "number"
+                }
+            }
+        },
+        "strategies.Stats": {
+            "type": "object",
+            "properties": {
+                "fill_rate": {
+                    "description": "FilledSize / OrderedSize",
+                    "type": "number"
+                },
+                "filled_orders": {
+                    "description": "with at least one fill",
+                    "type": "integer"
+                },
+                "filled_size": {
+                    "type": "number"
+                },
+                "fills": {
+                    "type": "integer"
+                },
+                "first_order": {
+                    "type": "string"
+                },
+                "last_fill": {
+                    "type": "string"
+                },
+                "ordered_size": {
+                    "description": "shares",
+                    "type": "number"
+                },
+                "orders": {
+                    "type": "integer"
+                },
+                "pnl": {
+                    "type": "number"
+                },
+                "positions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/strategies.Position"
+                    }
+                },
+                "realized_pnl": {
+                    "type": "number"
+                },
+                "strategy": {
+                    "type": "string"
+                },
+                "unrealized_pnl": {
+                    "description": "of positions with a current price",
+                    "type": "number"
+                },
+                "volume": {
+                    "description": "USDC filled",
+                    "type": "number"
+                }
+            }
+        },
+        "taxreport.Disposal": {
+            "type": "object",
+            "properties": {
+                "acquired": {
+                    "type": "string"
+                },
+                "asset": {
+                    "type": "string"
+                },
+                "condition_id": {
+                    "type": "string"
+                },
+                "cost_basis": {
+                    "type": "number"
+                },
+                "disposed": {
+                    "type": "string"
+                },
+                "gain": {
+                    "type": "number"
+                },
+                "kind": {
+                    "description": "sell or redeem",
+                    "type": "string"
+                },
+                "outcome": {
+                    "type": "string"
+                },
+                "proceeds": {
+                    "type": "number"
+                },
+                "size": {
+                    "type": "number"
+                },
+                "term": {
+                    "description": "short, long or unknown",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "transaction_hash": {
+                    "type": "string"
+                }
+            }
+        },
+        "taxreport.Lot": {
+            "type": "object",
+            "properties": {
+                "acquired": {
+                    "type": "string"
+                },
+                "asset": {
+                    "type": "string"
+                },
+                "condition_id": {
+                    "type": "string"
+                },
+                "cost_basis": {
+                    "description": "USDC paid for Size",
+                    "type": "number"
+                },
+                "outcome": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "number"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "taxreport.MarketGain": {
+            "type": "object",
+            "properties": {
+                "condition_id": {
+                    "type": "string"
+                },
+                "cost_basis": {
+                    "type": "number"
+                },
+                "disposals": {
+                    "type": "integer"
+                },
+                "gain": {
+                    "type": "number"
+                },
+                "proceeds": {
+                    "type": "number"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "taxreport.Report": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "disposals": {
+                    "description": "oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/taxreport.Disposal"
+                    }
+                },
+                "ignored": {
+                    "description": "activity of the year not counted, by type",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "markets": {
+                    "description": "largest gain first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/taxreport.MarketGain"
+                    }
+                },
+                "method": {
+                    "type": "string"
+                },
+                "open_lots": {
+                    "description": "still held at the end of the year",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/taxreport.Lot"
+                    }
+                },
+                "totals": {
+                    "$ref": "#/definitions/taxreport.Totals"
+                },
+                "truncated": {
+                    "description": "older activity than MaxActivity entries back was not fetched",
+                    "type": "boolean"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "taxreport.Totals": {
+            "type": "object",
+            "properties": {
+                "cost_basis": {
+                    "type": "number"
+                },
+                "gain": {
+                    "type": "number"
+                },
+                "long_term": {
+                    "type": "number"
+                },
+                "open_shares": {
+                    "type": "number"
+                },
+                "proceeds": {
+                    "type": "number"
+                },
+                "short_term": {
+                    "type": "number"
+                },
+                "unmatched": {
+                    "description": "shares disposed of with no recorded purchase",
+                    "type": "number"
+                }
+            }
+        },
+        "tenant.DayUsage": {
+            "type": "object",
+            "properties": {
+                "bytes_in": {
+                    "type": "integer"
+                },
+                "bytes_out": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "4xx and 5xx responses, besides rate limiting",
+                    "type": "integer"
+                },
+                "rate_limited": {
+                    "description": "requests rejected by the tenant's rate limit",
+                    "type": "integer"
+                },
+                "requests": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tenant.Report": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "description": "days with requests, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tenant.DayUsage"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "routes": {
+                    "description": "most requested first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tenant.RouteUsage"
+                    }
+                },
+                "tenant": {
+                    "$ref": "#/definitions/tenant.Tenant"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/tenant.Usage"
+                }
+            }
+        },
+        "tenant.RouteUsage": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "integer"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "route": {
+                    "description": "method and route pattern, e.g. GET /api/v1/markets/:id",
+                    "type": "string"
+                }
+            }
+        },
+        "tenant.Tenant": {
+            "type": "object",
+            "properties": {
+                "api_keys": {
+                    "description": "how many keys belong to the tenant",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rate_limit": {
+                    "description": "requests per 10s",
+                    "type": "integer"
+                }
+            }
+        },
+        "tenant.Usage": {
+            "type": "object",
+            "properties": {
+                "bytes_in": {
+                    "type": "integer"
+                },
+                "bytes_out": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "description": "4xx and 5xx responses, besides rate limiting",
+                    "type": "integer"
+                },
+                "rate_limited": {
+                    "description": "requests rejected by the tenant's rate limit",
+                    "type": "integer"
+                },
+                "requests": {
+                    "type": "integer"
+                }
+            }
+        },
+        "validate.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "JSON path, e.g. legs[1].price",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "param": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
+        "watchlist.Wallet": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "last_polled": {
+                    "type": "string"
+                }
+            }
+        },
+        "watchlist.WatchedMarket": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "market_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "webhooks.Delivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_status_code": {
+                    "type": "integer"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "webhooks.Subscription": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "description": "event types, \"*\" for all",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "tagged events (market.listed, rule.triggered) must carry one of these",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "wsbuffer.Stats": {
+            "type": "object",
+            "properties": {
+                "buffered_bytes": {
+                    "type": "integer"
+                },
+                "client_limit_bytes": {
+                    "description": "0 = unlimited",
+                    "type": "integer"
+                },
+                "clients": {
+                    "type": "integer"
+                },
+                "dropped_frames": {
+                    "description": "frames still queued for evicted clients",
+                    "type": "integer"
+                },
+                "evicted_client_limit": {
+                    "description": "clients over their own limit",
+                    "type": "integer"
+                },
+                "evicted_total_limit": {
+                    "description": "slowest clients once the total was reached",
+                    "type": "integer"
+                },
+                "limit_bytes": {
+                    "description": "0 = unlimited",
+                    "type": "integer"
+                },
+                "max_client_queue_bytes": {
+                    "description": "largest queue of a single client",
+                    "type": "integer"
+                }
+            }
         }
-      }
     },
-    "/api/v1/top-movers": {
-      "get": {
-        "tags": ["Markets"],
-        "summary": "Get top moving markets",
-        "produces": ["application/json"],
-        "parameters": [
-          {"name": "limit", "in": "query", "type": "integer", "default": 10}
-        ],
-        "responses": {
-          "200": {"description": "Top movers"}
+    "securityDefinitions": {
+        "AdminAuth": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        },
+        "ApiKeyAuth": {
+            "type": "apiKey",
+            "name": "POLY-API-KEY",
+            "in": "header"
         }
-      }
     },
-    "/api/v1/leaderboard": {
-      "get": {
-        "tags": ["User Data"],
-        "summary": "Get trading leaderboard",
-        "produces": ["application/json"],
-        "responses": {
-          "200": {"description": "Leaderboard"}
+    "tags": [
+        {
+            "description": "Health, stats and metrics endpoints",
+            "name": "Health"
+        },
+        {
+            "description": "Market operations",
+            "name": "Markets"
+        },
+        {
+            "description": "Event operations",
+            "name": "Events"
+        },
+        {
+            "description": "Price and order book operations",
+            "name": "Prices"
+        },
+        {
+            "description": "Order management (authenticated)",
+            "name": "Orders"
+        },
+        {
+            "description": "User data operations",
+            "name": "User Data"
         }
-      }
-    }
-  },
-  "tags": [
-    {"name": "Health", "description": "Health check endpoints"},
-    {"name": "Markets", "description": "Market operations"},
-    {"name": "Events", "description": "Event operations"},
-    {"name": "Prices", "description": "Price and order book operations"},
-    {"name": "Orders", "description": "Order management (authenticated)"},
-    {"name": "User Data", "description": "User data operations"}
-  ]
-}
-`
+    ]
+}`
 
-// SwaggerInfo holds exported Swagger Info
+// SwaggerInfo holds exported Swagger Info; Configure sets the host, base
+// path and schemes for the environment
 var SwaggerInfo = &swag.Spec{
 	Version:          "1.0",
 	Host:             "",
 	BasePath:         "/",
-	Schemes:          []string{"http", "https"},
+	Schemes:          []string{},
 	Title:            "PolyGo API",
 	Description:      "High-performance Polymarket API proxy with caching and WebSocket support",
 	InfoInstanceName: "swagger",
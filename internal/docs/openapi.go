@@ -0,0 +1,278 @@
+package docs
+
+//go:generate go run github.com/polygo/cmd/docgen -root ../..
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/polygo/internal/config"
+)
+
+// Configure points the generated spec at the host, base path and schemes
+// this environment is reached on. It is called before the docs are served.
+func Configure(cfg *config.DocsConfig) {
+	SwaggerInfo.Host = cfg.Host
+	SwaggerInfo.BasePath = cfg.BasePath
+	if SwaggerInfo.BasePath == "" {
+		SwaggerInfo.BasePath = "/"
+	}
+	SwaggerInfo.Schemes = cfg.Schemes
+}
+
+// OpenAPI converts the Swagger 2.0 spec, as configured, into an OpenAPI
+// 3.0 document for client generators
+func OpenAPI() ([]byte, error) {
+	var spec map[string]interface{}
+	if err := json.Unmarshal([]byte(SwaggerInfo.ReadDoc()), &spec); err != nil {
+		return nil, err
+	}
+
+	doc := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    spec["info"],
+		"servers": servers(),
+		"paths":   map[string]interface{}{},
+	}
+	for _, key := range []string{"tags", "security", "externalDocs"} {
+		if v, ok := spec[key]; ok {
+			doc[key] = v
+		}
+	}
+
+	components := map[string]interface{}{}
+	if defs, ok := spec["definitions"].(map[string]interface{}); ok {
+		components["schemas"] = defs
+	}
+	if defs, ok := spec["securityDefinitions"].(map[string]interface{}); ok {
+		schemes := make(map[string]interface{}, len(defs))
+		for name, def := range defs {
+			schemes[name] = securityScheme(asMap(def))
+		}
+		components["securitySchemes"] = schemes
+	}
+	if len(components) > 0 {
+		doc["components"] = components
+	}
+
+	consumes := stringList(spec["consumes"], "application/json")
+	produces := stringList(spec["produces"], "application/json")
+	paths := doc["paths"].(map[string]interface{})
+	for path, item := range asMap(spec["paths"]) {
+		out := map[string]interface{}{}
+		for method, op := range asMap(item) {
+			out[method] = operation(asMap(op), consumes, produces)
+		}
+		paths[path] = out
+	}
+
+	rewriteRefs(doc)
+	return json.Marshal(doc)
+}
+
+// servers lists where requests go. Without a configured host the URL is
+// relative, which clients resolve against the host serving the document.
+func servers() []interface{} {
+	base := SwaggerInfo.BasePath
+	if SwaggerInfo.Host == "" {
+		return []interface{}{map[string]string{"url": base}}
+	}
+	base = strings.TrimSuffix(base, "/")
+	schemes := SwaggerInfo.Schemes
+	if len(schemes) == 0 {
+		schemes = []string{"https"}
+	}
+	out := make([]interface{}, len(schemes))
+	for i, scheme := range schemes {
+		out[i] = map[string]string{"url": scheme + "://" + SwaggerInfo.Host + base}
+	}
+	return out
+}
+
+// operation converts a Swagger operation: body and form parameters become
+// a request body, and schemas move under each media type
+func operation(op map[string]interface{}, consumes, produces []string) map[string]interface{} {
+	consumes = stringList(op["consumes"], consumes...)
+	produces = stringList(op["produces"], produces...)
+
+	out := map[string]interface{}{}
+	for key, v := range op {
+		switch key {
+		case "parameters", "responses", "consumes", "produces", "schemes":
+		default:
+			out[key] = v
+		}
+	}
+
+	var params []interface{}
+	form := map[string]interface{}{}
+	var formRequired []interface{}
+	for _, p := range asList(op["parameters"]) {
+		param := asMap(p)
+		switch param["in"] {
+		case "body":
+			body := map[string]interface{}{"content": content(consumes, param["schema"])}
+			if param["description"] != nil {
+				body["description"] = param["description"]
+			}
+			if param["required"] == true {
+				body["required"] = true
+			}
+			out["requestBody"] = body
+		case "formData":
+			form[param["name"].(string)] = schema(param)
+			if param["required"] == true {
+				formRequired = append(formRequired, param["name"])
+			}
+		default:
+			params = append(params, parameter(param))
+		}
+	}
+	if len(params) > 0 {
+		out["parameters"] = params
+	}
+	if len(form) > 0 {
+		obj := map[string]interface{}{"type": "object", "properties": form}
+		if len(formRequired) > 0 {
+			obj["required"] = formRequired
+		}
+		types := []string{"application/x-www-form-urlencoded"}
+		for _, ct := range consumes {
+			if ct == "multipart/form-data" {
+				types = []string{ct}
+			}
+		}
+		out["requestBody"] = map[string]interface{}{"content": content(types, obj)}
+	}
+
+	responses := map[string]interface{}{}
+	for code, r := range asMap(op["responses"]) {
+		resp := asMap(r)
+		converted := map[string]interface{}{"description": resp["description"]}
+		if converted["description"] == nil {
+			converted["description"] = ""
+		}
+		if s, ok := resp["schema"]; ok {
+			converted["content"] = content(produces, s)
+		}
+		if headers := asMap(resp["headers"]); len(headers) > 0 {
+			converted["headers"] = responseHeaders(headers)
+		}
+		responses[code] = converted
+	}
+	out["responses"] = responses
+	return out
+}
+
+func responseHeaders(headers map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(headers))
+	for name, h := range headers {
+		header := asMap(h)
+		out[name] = map[string]interface{}{"description": header["description"], "schema": schema(header)}
+	}
+	return out
+}
+
+// parameter converts a path, query or header parameter, whose type moves
+// into a schema
+func parameter(p map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{"name": p["name"], "in": p["in"], "schema": schema(p)}
+	if p["description"] != nil {
+		out["description"] = p["description"]
+	}
+	if p["required"] == true || p["in"] == "path" {
+		out["required"] = true
+	}
+	if p["type"] == "array" {
+		switch p["collectionFormat"] {
+		case "multi":
+			out["explode"] = true
+		default:
+			out["explode"] = false
+		}
+	}
+	return out
+}
+
+// schemaKeys are the parameter fields that describe its value
+var schemaKeys = []string{
+	"type", "format", "items", "enum", "default", "minimum", "maximum",
+	"exclusiveMinimum", "exclusiveMaximum", "minLength", "maxLength",
+	"pattern", "minItems", "maxItems", "uniqueItems", "multipleOf",
+}
+
+func schema(p map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	for _, key := range schemaKeys {
+		if v, ok := p[key]; ok {
+			out[key] = v
+		}
+	}
+	if out["type"] == "file" {
+		out["type"], out["format"] = "string", "binary"
+	}
+	return out
+}
+
+func content(types []string, schema interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(types))
+	for _, t := range types {
+		out[t] = map[string]interface{}{"schema": schema}
+	}
+	return out
+}
+
+// securityScheme converts a Swagger security definition; API keys carry
+// over unchanged
+func securityScheme(def map[string]interface{}) map[string]interface{} {
+	if def["type"] == "basic" {
+		out := map[string]interface{}{"type": "http", "scheme": "basic"}
+		if def["description"] != nil {
+			out["description"] = def["description"]
+		}
+		return out
+	}
+	return def
+}
+
+// rewriteRefs points schema references at components
+func rewriteRefs(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				v[key] = strings.Replace(ref, "#/definitions/", "#/components/schemas/", 1)
+				continue
+			}
+			rewriteRefs(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			rewriteRefs(child)
+		}
+	}
+}
+
+func asMap(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func asList(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+// stringList reads a list of strings, falling back to def when empty
+func stringList(v interface{}, def ...string) []string {
+	var out []string
+	for _, s := range asList(v) {
+		if s, ok := s.(string); ok {
+			out = append(out, s)
+		}
+	}
+	if len(out) == 0 {
+		return def
+	}
+	return out
+}
@@ -0,0 +1,156 @@
+package equity
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Periods returns can be bucketed by
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// tradingDays annualizes the Sharpe ratio; prediction markets trade every day
+const tradingDays = 365
+
+// ErrInvalidPeriod is returned for a period other than day, week or month
+var ErrInvalidPeriod = errors.New("period must be day, week or month")
+
+// Point is a snapshot on an equity curve
+type Point struct {
+	Snapshot
+	Return float64 `json:"return"` // since the previous point
+	Equity float64 `json:"equity"` // growth of 1 invested at the first point
+}
+
+// PeriodReturn is the compounded return of the points within a period
+type PeriodReturn struct {
+	Period string  `json:"period"` // first date of the period
+	Start  string  `json:"start"`  // first snapshot date in it
+	End    string  `json:"end"`    // last snapshot date in it
+	Return float64 `json:"return"`
+}
+
+// Curve is a wallet's equity curve and the statistics drawn from it
+type Curve struct {
+	Address     string         `json:"address"`
+	Period      string         `json:"period"`
+	Points      []Point        `json:"points"`
+	Returns     []PeriodReturn `json:"returns"`
+	TotalReturn float64        `json:"total_return"`
+	MaxDrawdown float64        `json:"max_drawdown"` // largest fall of equity from a previous peak, as a fraction of the peak
+	Sharpe      *float64       `json:"sharpe"`       // annualized, zero risk-free rate; nil with fewer than two returns or no volatility
+}
+
+// Curve builds the equity curve of address from its snapshots since the
+// given date (all of them when since is empty), with returns bucketed by
+// period. False when there are no snapshots.
+func (t *Tracker) Curve(address, since, period string) (*Curve, bool, error) {
+	if period != PeriodDay && period != PeriodWeek && period != PeriodMonth {
+		return nil, false, ErrInvalidPeriod
+	}
+
+	snaps := t.History(address, since)
+	if len(snaps) == 0 {
+		return nil, false, nil
+	}
+	return Build(address, snaps, period), true, nil
+}
+
+// Build computes an equity curve from snapshots, oldest first. A day's
+// return is its change in PnL over the previous day's value, so money
+// moved into or out of positions is not counted as a gain or loss; days
+// following one with no value return 0.
+func Build(address string, snaps []Snapshot, period string) *Curve {
+	c := &Curve{
+		Address: address,
+		Period:  period,
+		Points:  make([]Point, len(snaps)),
+		Returns: []PeriodReturn{},
+	}
+
+	equity, peak := 1.0, 1.0
+	var returns []float64
+	for i, s := range snaps {
+		var ret float64
+		if i > 0 && snaps[i-1].Value > 0 {
+			ret = (s.PnL - snaps[i-1].PnL) / snaps[i-1].Value
+			returns = append(returns, ret)
+		}
+		equity *= 1 + ret
+		c.Points[i] = Point{Snapshot: s, Return: round(ret), Equity: round(equity)}
+
+		peak = math.Max(peak, equity)
+		if peak > 0 {
+			c.MaxDrawdown = math.Max(c.MaxDrawdown, (peak-equity)/peak)
+		}
+
+		key := bucket(s.Date, period)
+		n := len(c.Returns)
+		if n == 0 || c.Returns[n-1].Period != key {
+			// A period's return runs from the last point of the one before
+			c.Returns = append(c.Returns, PeriodReturn{Period: key, Start: s.Date, End: s.Date, Return: ret})
+			continue
+		}
+		r := &c.Returns[n-1]
+		r.End = s.Date
+		r.Return = (1+r.Return)*(1+ret) - 1
+	}
+	for i := range c.Returns {
+		c.Returns[i].Return = round(c.Returns[i].Return)
+	}
+
+	c.TotalReturn = round(equity - 1)
+	c.MaxDrawdown = round(c.MaxDrawdown)
+	c.Sharpe = sharpe(returns)
+	return c
+}
+
+// bucket returns the first date of the period date falls in
+func bucket(date, period string) string {
+	d, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return date
+	}
+	switch period {
+	case PeriodWeek:
+		// Weeks start on Monday
+		d = d.AddDate(0, 0, -(int(d.Weekday())+6)%7)
+	case PeriodMonth:
+		d = time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return d.Format(dateLayout)
+}
+
+// sharpe annualizes the mean daily return over its standard deviation
+func sharpe(returns []float64) *float64 {
+	if len(returns) < 2 {
+		return nil
+	}
+
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	std := math.Sqrt(variance / float64(len(returns)-1))
+	if std < 1e-12 {
+		return nil
+	}
+
+	s := round(mean / std * math.Sqrt(tradingDays))
+	return &s
+}
+
+// round rounds to 6 decimals
+func round(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
@@ -0,0 +1,242 @@
+// Package equity snapshots the portfolio of every watched wallet once a
+// day and turns the snapshots into equity curves with period returns, max
+// drawdown and a Sharpe ratio, so portfolio charts need no database.
+package equity
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+)
+
+// dateLayout is the layout of a snapshot's date
+const dateLayout = "2006-01-02"
+
+// Snapshot is a wallet's portfolio as taken on one day
+type Snapshot struct {
+	Date      string    `json:"date"` // UTC, YYYY-MM-DD
+	Time      time.Time `json:"time"`
+	Value     float64   `json:"value"` // current value of every position, USDC
+	PnL       float64   `json:"pnl"`   // unrealized plus realized PnL of those positions
+	Positions int       `json:"positions"`
+}
+
+// position holds the Data API position fields a snapshot uses
+type position struct {
+	Size         models.FlexString `json:"size"`
+	CurPrice     models.FlexString `json:"curPrice"`
+	CurrentValue models.FlexString `json:"currentValue"`
+	CashPnL      models.FlexString `json:"cashPnl"`
+	RealizedPnL  models.FlexString `json:"realizedPnl"`
+}
+
+// Tracker snapshots the wallets named by its address source every day at
+// At (UTC) and keeps Retention of daily history per wallet, saved to Path
+// so it survives restarts
+type Tracker struct {
+	data      *polymarket.DataClient
+	addresses func() []string
+	config    *config.EquityConfig
+
+	mu      sync.RWMutex
+	history map[string][]Snapshot // address -> snapshots, oldest first
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a tracker of the wallets addresses returns, restoring the
+// history saved at Path
+func New(data *polymarket.DataClient, addresses func() []string, cfg *config.EquityConfig) *Tracker {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	t := &Tracker{
+		data:      data,
+		addresses: addresses,
+		config:    cfg,
+		history:   make(map[string][]Snapshot),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	if err := t.load(); err != nil {
+		log.Printf("Failed to restore equity history from %s: %v", cfg.Path, err)
+	}
+	return t
+}
+
+// Start takes today's snapshots if At has passed and they are missing,
+// then snapshots every day at At
+func (t *Tracker) Start() {
+	if !t.config.Enabled {
+		return
+	}
+
+	at, err := parseAt(t.config.At)
+	if err != nil {
+		log.Printf("Invalid equity snapshot time %q, using 00:00: %v", t.config.At, err)
+	}
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+
+		now := time.Now().UTC()
+		if !now.Before(today(now, at)) {
+			t.Snapshot(now)
+		}
+
+		for {
+			timer := time.NewTimer(time.Until(next(time.Now().UTC(), at)))
+			select {
+			case <-t.ctx.Done():
+				timer.Stop()
+				return
+			case now := <-timer.C:
+				t.Snapshot(now.UTC())
+			}
+		}
+	}()
+}
+
+// Stop stops snapshotting
+func (t *Tracker) Stop() {
+	t.cancel()
+	t.wg.Wait()
+}
+
+// Snapshot records the portfolio of every watched wallet that has no
+// snapshot for now's date yet. Failures are logged; the wallet is tried
+// again on the next run.
+func (t *Tracker) Snapshot(now time.Time) {
+	date := now.UTC().Format(dateLayout)
+	for _, address := range t.addresses() {
+		if t.ctx.Err() != nil {
+			return
+		}
+		address = strings.ToLower(address)
+		if t.has(address, date) {
+			continue
+		}
+
+		s, err := t.take(address)
+		if err != nil {
+			log.Printf("Equity snapshot of %s failed: %v", address, err)
+			continue
+		}
+		s.Date, s.Time = date, now
+		t.Record(address, s)
+	}
+}
+
+// take computes a wallet's current portfolio from its positions
+func (t *Tracker) take(address string) (Snapshot, error) {
+	data, _, err := t.data.GetPositions(address, t.config.PositionsLimit, "", true)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	var positions []position
+	if err := sonic.Unmarshal(data, &positions); err != nil {
+		return Snapshot{}, err
+	}
+
+	var s Snapshot
+	for _, p := range positions {
+		value := p.CurrentValue.Float()
+		if p.CurrentValue == "" {
+			value = p.Size.Float() * p.CurPrice.Float()
+		}
+		s.Value += value
+		s.PnL += p.CashPnL.Float() + p.RealizedPnL.Float()
+		if p.Size.Float() > 0 {
+			s.Positions++
+		}
+	}
+	s.Value, s.PnL = round(s.Value), round(s.PnL)
+	return s, nil
+}
+
+// has reports whether address has a snapshot for date
+func (t *Tracker) has(address, date string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	snaps := t.history[address]
+	return len(snaps) > 0 && snaps[len(snaps)-1].Date >= date
+}
+
+// Record adds a snapshot of address, replacing one of the same date,
+// drops snapshots older than Retention and saves the history (used by
+// Snapshot and tests)
+func (t *Tracker) Record(address string, s Snapshot) {
+	address = strings.ToLower(address)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	snaps := t.history[address]
+	i := sort.Search(len(snaps), func(i int) bool { return snaps[i].Date >= s.Date })
+	if i < len(snaps) && snaps[i].Date == s.Date {
+		snaps[i] = s
+	} else {
+		snaps = append(snaps, Snapshot{})
+		copy(snaps[i+1:], snaps[i:])
+		snaps[i] = s
+	}
+
+	if t.config.Retention > 0 {
+		cutoff := s.Time.Add(-t.config.Retention).UTC().Format(dateLayout)
+		drop := 0
+		for drop < len(snaps) && snaps[drop].Date < cutoff {
+			drop++
+		}
+		snaps = snaps[drop:]
+	}
+	t.history[address] = snaps
+
+	t.saveLocked()
+}
+
+// History returns the snapshots of address since the given date (all of
+// them when since is empty), oldest first
+func (t *Tracker) History(address, since string) []Snapshot {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	snaps := t.history[strings.ToLower(address)]
+	i := sort.Search(len(snaps), func(i int) bool { return snaps[i].Date >= since })
+	return append([]Snapshot(nil), snaps[i:]...)
+}
+
+// parseAt parses a HH:MM time of day into the offset from midnight
+func parseAt(s string) (time.Duration, error) {
+	at, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute, nil
+}
+
+// today returns at on now's date
+func today(now time.Time, at time.Duration) time.Time {
+	y, m, d := now.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Add(at)
+}
+
+// next returns the first at after now
+func next(now time.Time, at time.Duration) time.Time {
+	run := today(now, at)
+	if !run.After(now) {
+		run = run.AddDate(0, 0, 1)
+	}
+	return run
+}
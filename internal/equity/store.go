@@ -0,0 +1,55 @@
+package equity
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/fsutil"
+)
+
+// load restores the history saved at Path; a missing file is not an error
+func (t *Tracker) load() error {
+	if t.config.Path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(t.config.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var history map[string][]Snapshot
+	if err := sonic.Unmarshal(data, &history); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for address, snaps := range history {
+		t.history[address] = snaps
+	}
+	return nil
+}
+
+// saveLocked writes the history to Path, replacing the file atomically.
+// Failures are logged; the in-memory history stays authoritative. Caller
+// holds t.mu.
+func (t *Tracker) saveLocked() {
+	if t.config.Path == "" {
+		return
+	}
+
+	data, err := sonic.Marshal(t.history)
+	if err == nil {
+		err = fsutil.WriteFileAtomic(t.config.Path, data, 0o644)
+	}
+	if err != nil {
+		log.Printf("Failed to save equity history to %s: %v", t.config.Path, err)
+	}
+}
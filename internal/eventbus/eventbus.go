@@ -0,0 +1,359 @@
+// Package eventbus publishes normalized market events from the upstream
+// WebSocket (trades, price changes, order book deltas and resolutions) to
+// Kafka topics or NATS subjects, so data platforms can consume them
+// without holding a WebSocket connection to PolyGo.
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/polymarket"
+)
+
+// Event types
+const (
+	TypeTrade       = "trade"
+	TypePriceChange = "price_change"
+	TypeBookDelta   = "book_delta"
+	TypeResolution  = "market_resolved"
+)
+
+// Event is the envelope published for every market event. Kafka messages
+// are keyed by Market so a market's events stay in order on one partition.
+type Event struct {
+	Type      string      `json:"type"`
+	Market    string      `json:"market"`             // condition ID
+	AssetID   string      `json:"asset_id,omitempty"` // CLOB token ID
+	Timestamp int64       `json:"timestamp"`          // unix ms, as sent upstream
+	Data      interface{} `json:"data"`
+}
+
+// Trade is a trade's data
+type Trade struct {
+	Price      float64 `json:"price"`
+	Size       float64 `json:"size"`
+	Side       string  `json:"side"`
+	FeeRateBps float64 `json:"fee_rate_bps"`
+}
+
+// PriceChange is a change of one order book level; size 0 removes it
+type PriceChange struct {
+	Price   float64  `json:"price"`
+	Size    float64  `json:"size"`
+	Side    string   `json:"side"`
+	BestBid *float64 `json:"best_bid,omitempty"`
+	BestAsk *float64 `json:"best_ask,omitempty"`
+}
+
+// BookDelta is the change of a token's order book since its previous
+// message, or the full book when Snapshot is set. Seq increases by one per
+// message for a token; after a gap, wait for the next snapshot.
+type BookDelta struct {
+	Seq      uint64  `json:"seq"`
+	Snapshot bool    `json:"snapshot"`
+	Bids     []Level `json:"bids"`
+	Asks     []Level `json:"asks"`
+	Hash     string  `json:"hash,omitempty"`
+}
+
+// Level is an order book price level; size 0 in a delta removes it
+type Level struct {
+	Price float64 `json:"price"`
+	Size  float64 `json:"size"`
+}
+
+// Resolution is a market's outcome
+type Resolution struct {
+	WinningAssetID string   `json:"winning_asset_id"`
+	WinningOutcome string   `json:"winning_outcome"`
+	AssetIDs       []string `json:"asset_ids,omitempty"`
+}
+
+// message is one encoded event bound for a topic
+type message struct {
+	topic string
+	key   string
+	value []byte
+}
+
+// queued is an event waiting to be published to topic
+type queued struct {
+	topic string
+	event Event
+}
+
+// sink delivers batches of messages to a bus. Sinks connect lazily and
+// reconnect on the next call after a failure; they are used by a single
+// goroutine.
+type sink interface {
+	Publish(msgs []message) error
+	Close() error
+}
+
+// Stats counts published and lost events
+type Stats struct {
+	Published int64 `json:"published"`
+	Dropped   int64 `json:"dropped"` // queue was full
+	Failed    int64 `json:"failed"`  // the bus rejected or was unreachable
+}
+
+// Publisher normalizes upstream WebSocket frames into events and publishes
+// them in batches from a bounded queue
+type Publisher struct {
+	config     *config.EventBusConfig
+	ws         *polymarket.WSManager
+	normalizer *Normalizer
+	sink       sink
+
+	queue    chan queued
+	dropping atomic.Bool
+	stats    struct{ published, dropped, failed atomic.Int64 }
+
+	mu   sync.Mutex
+	subs map[string]chan []byte // markets subscribed upstream for the bus
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a new event bus publisher. Book deltas carry a full snapshot
+// every bookSnapshotEvery updates per token.
+func New(ws *polymarket.WSManager, cfg *config.EventBusConfig, bookSnapshotEvery int) (*Publisher, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p := &Publisher{
+		config:     cfg,
+		ws:         ws,
+		normalizer: NewNormalizer(bookSnapshotEvery),
+		queue:      make(chan queued, cfg.QueueSize),
+		subs:       make(map[string]chan []byte),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	if !cfg.Enabled {
+		return p, nil
+	}
+
+	if len(cfg.Servers) == 0 {
+		cancel()
+		return nil, errors.New("event bus needs at least one server")
+	}
+	switch cfg.Backend {
+	case config.EventBusKafka:
+		p.sink = newKafkaSink(cfg)
+	case config.EventBusNATS:
+		p.sink = newNATSSink(cfg)
+	default:
+		cancel()
+		return nil, fmt.Errorf("unknown event bus backend %q", cfg.Backend)
+	}
+	return p, nil
+}
+
+// Start observes upstream frames, subscribes the configured markets and
+// launches the publishing worker
+func (p *Publisher) Start() {
+	if !p.config.Enabled {
+		return
+	}
+
+	p.ws.Observe(p.Handle)
+	p.mu.Lock()
+	for _, market := range p.config.Markets {
+		ch, err := p.ws.SubscribeMarket(market)
+		if err != nil {
+			log.Printf("Event bus failed to subscribe to market %s: %v", market, err)
+			continue
+		}
+		p.subs[market] = ch
+		go drain(ch)
+	}
+	p.mu.Unlock()
+
+	p.wg.Add(1)
+	go p.worker()
+}
+
+// Stop unsubscribes the configured markets and publishes what is queued
+func (p *Publisher) Stop() {
+	p.mu.Lock()
+	for market, ch := range p.subs {
+		p.ws.UnsubscribeMarket(market, ch)
+		delete(p.subs, market)
+	}
+	p.mu.Unlock()
+
+	p.cancel()
+	p.wg.Wait()
+	if p.sink != nil {
+		p.sink.Close()
+	}
+}
+
+// Enabled reports whether events are published
+func (p *Publisher) Enabled() bool {
+	return p.config.Enabled
+}
+
+// Stats returns the publisher's counters
+func (p *Publisher) Stats() Stats {
+	return Stats{
+		Published: p.stats.published.Load(),
+		Dropped:   p.stats.dropped.Load(),
+		Failed:    p.stats.failed.Load(),
+	}
+}
+
+// Handle normalizes an upstream frame and queues its events; when the
+// queue is full they are dropped
+func (p *Publisher) Handle(channel polymarket.WSChannel, data []byte) {
+	if channel != polymarket.WSChannelMarket {
+		return
+	}
+
+	for _, e := range p.normalizer.Events(data) {
+		if topic := p.topic(e.Type); topic != "" {
+			p.enqueue(topic, e)
+		}
+	}
+}
+
+// Emit queues an event of another source (e.g. a rule trigger) for topic.
+// It reports false when the bus is disabled or the queue is full.
+func (p *Publisher) Emit(topic string, e Event) bool {
+	if !p.config.Enabled {
+		return false
+	}
+	return p.enqueue(topic, e)
+}
+
+// enqueue queues an event without blocking; when the queue is full it is
+// dropped
+func (p *Publisher) enqueue(topic string, e Event) bool {
+	select {
+	case p.queue <- queued{topic: topic, event: e}:
+		p.dropping.Store(false)
+		return true
+	default:
+		p.stats.dropped.Add(1)
+		if !p.dropping.Swap(true) {
+			log.Printf("Event bus queue is full, dropping events")
+		}
+		return false
+	}
+}
+
+// topic returns the topic events of a type are published to
+func (p *Publisher) topic(eventType string) string {
+	switch eventType {
+	case TypeTrade:
+		return p.config.Topics.Trades
+	case TypePriceChange:
+		return p.config.Topics.PriceChanges
+	case TypeBookDelta:
+		return p.config.Topics.BookDeltas
+	case TypeResolution:
+		return p.config.Topics.Resolutions
+	}
+	return ""
+}
+
+// worker publishes queued events in batches of up to BatchSize, waiting at
+// most FlushInterval for a batch to fill
+func (p *Publisher) worker() {
+	defer p.wg.Done()
+
+	batch := make([]queued, 0, p.config.BatchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			p.publish(batch)
+			batch = batch[:0]
+		}
+	}
+
+	interval := p.config.FlushInterval
+	if interval <= 0 {
+		interval = 100 * time.Millisecond
+	}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			// Publish what is left before stopping
+			for {
+				select {
+				case e := <-p.queue:
+					batch = append(batch, e)
+					if len(batch) >= p.config.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		case q := <-p.queue:
+			batch = append(batch, q)
+			if len(batch) >= p.config.BatchSize {
+				flush()
+			}
+		case <-timer.C:
+			flush()
+			timer.Reset(interval)
+		}
+	}
+}
+
+// publish encodes and sends a batch, retrying once on a fresh connection
+func (p *Publisher) publish(batch []queued) {
+	msgs := make([]message, 0, len(batch))
+	for _, q := range batch {
+		value, err := sonic.Marshal(q.event)
+		if err != nil {
+			p.stats.failed.Add(1)
+			continue
+		}
+		msgs = append(msgs, message{topic: q.topic, key: q.event.Market, value: value})
+	}
+
+	err := p.sink.Publish(msgs)
+	if err != nil {
+		err = p.sink.Publish(msgs)
+	}
+	if err != nil {
+		p.stats.failed.Add(int64(len(msgs)))
+		log.Printf("Event bus failed to publish %d events: %v", len(msgs), err)
+		return
+	}
+	p.stats.published.Add(int64(len(msgs)))
+}
+
+// address strips a scheme from a configured server and adds the default
+// port when it has none
+func address(server, scheme, port string) string {
+	server = strings.TrimPrefix(server, scheme+"://")
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		return net.JoinHostPort(server, port)
+	}
+	return server
+}
+
+// drain discards frames routed to a subscription held only to keep the
+// market subscribed upstream
+func drain(ch chan []byte) {
+	for range ch {
+	}
+}
@@ -0,0 +1,66 @@
+package eventbus
+
+import (
+	"context"
+	"time"
+
+	"github.com/polygo/internal/config"
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaSink produces to Kafka with acks=1 through a kafka-go writer. Keyed
+// messages are partitioned like the Java client's default partitioner
+// (murmur2), so other producers keying by market agree on partitions.
+type kafkaSink struct {
+	config *config.EventBusConfig
+	writer *kafka.Writer
+}
+
+func newKafkaSink(cfg *config.EventBusConfig) *kafkaSink {
+	servers := make([]string, len(cfg.Servers))
+	for i, server := range cfg.Servers {
+		servers[i] = address(server, "kafka", "9092")
+	}
+	return &kafkaSink{
+		config: cfg,
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(servers...),
+			Balancer:     kafka.Murmur2Balancer{},
+			RequiredAcks: kafka.RequireOne,
+			// Publisher batches and retries; send what it gives at once
+			BatchSize:    max(cfg.BatchSize, 1),
+			BatchTimeout: time.Millisecond,
+			MaxAttempts:  1,
+			ReadTimeout:  cfg.Timeout,
+			WriteTimeout: cfg.Timeout,
+			Transport: &kafka.Transport{
+				ClientID:    cfg.ClientID,
+				DialTimeout: cfg.Timeout,
+			},
+		},
+	}
+}
+
+// Publish produces msgs and waits for each partition leader to take them
+func (s *kafkaSink) Publish(msgs []message) error {
+	ctx := context.Background()
+	if s.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.config.Timeout)
+		defer cancel()
+	}
+
+	out := make([]kafka.Message, len(msgs))
+	for i, m := range msgs {
+		out[i] = kafka.Message{Topic: m.topic, Value: m.value}
+		if m.key != "" {
+			out[i].Key = []byte(m.key)
+		}
+	}
+	return s.writer.WriteMessages(ctx, out...)
+}
+
+// Close flushes and closes the writer's connections
+func (s *kafkaSink) Close() error {
+	return s.writer.Close()
+}
@@ -0,0 +1,95 @@
+package eventbus
+
+import (
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	"github.com/polygo/internal/config"
+)
+
+// natsSink publishes with nats.go, flushing after each batch so the
+// server's PONG confirms it took the whole batch. The client does not
+// reconnect by itself; a failed batch drops the connection and the next
+// one dials again.
+type natsSink struct {
+	config *config.EventBusConfig
+	conn   *nats.Conn
+}
+
+func newNATSSink(cfg *config.EventBusConfig) *natsSink {
+	return &natsSink{config: cfg}
+}
+
+// Publish sends msgs and waits for the server to acknowledge them
+func (s *natsSink) Publish(msgs []message) error {
+	if err := s.publish(msgs); err != nil {
+		s.Close()
+		return err
+	}
+	return nil
+}
+
+func (s *natsSink) publish(msgs []message) error {
+	if s.conn == nil {
+		if err := s.connect(); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range msgs {
+		if err := s.conn.Publish(m.topic, m.value); err != nil {
+			return err
+		}
+	}
+	var err error
+	if s.config.Timeout > 0 {
+		err = s.conn.FlushTimeout(s.config.Timeout)
+	} else {
+		err = s.conn.Flush()
+	}
+	if err != nil {
+		return err
+	}
+	// -ERR (bad subject, permissions) arrives asynchronously
+	return s.conn.LastError()
+}
+
+// connect dials the configured servers in turn until one accepts
+func (s *natsSink) connect() error {
+	servers := make([]string, len(s.config.Servers))
+	for i, server := range s.config.Servers {
+		servers[i] = "nats://" + address(server, "nats", "4222")
+	}
+
+	opts := []nats.Option{
+		nats.Name(s.config.ClientID),
+		nats.NoReconnect(),
+		nats.DontRandomize(),
+	}
+	if s.config.Timeout > 0 {
+		opts = append(opts, nats.Timeout(s.config.Timeout))
+	}
+	if s.config.User != "" {
+		opts = append(opts, nats.UserInfo(s.config.User, s.config.Password))
+	}
+	if s.config.Token != "" {
+		opts = append(opts, nats.Token(s.config.Token))
+	}
+
+	conn, err := nats.Connect(strings.Join(servers, ","), opts...)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	return nil
+}
+
+// Close drops the connection
+func (s *natsSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	s.conn.Close()
+	s.conn = nil
+	return nil
+}
@@ -0,0 +1,167 @@
+package eventbus
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+)
+
+// upstream is the union of the market channel messages events are built from
+type upstream struct {
+	EventType  string            `json:"event_type"`
+	Market     string            `json:"market"`
+	AssetID    string            `json:"asset_id"`
+	Timestamp  models.FlexString `json:"timestamp"`
+	Price      models.FlexString `json:"price"`
+	Size       models.FlexString `json:"size"`
+	Side       string            `json:"side"`
+	FeeRateBps models.FlexString `json:"fee_rate_bps"`
+
+	// price_change: one entry per level, in either the batched or the
+	// older per-asset form
+	PriceChanges []upstreamChange `json:"price_changes"`
+	Changes      []upstreamChange `json:"changes"`
+
+	// market_resolved
+	AssetIDs       []string `json:"assets_ids"`
+	WinningAssetID string   `json:"winning_asset_id"`
+	WinningOutcome string   `json:"winning_outcome"`
+}
+
+type upstreamChange struct {
+	AssetID string            `json:"asset_id"`
+	Price   models.FlexString `json:"price"`
+	Size    models.FlexString `json:"size"`
+	Side    string            `json:"side"`
+	BestBid models.FlexString `json:"best_bid"`
+	BestAsk models.FlexString `json:"best_ask"`
+}
+
+// Normalizer turns upstream market channel frames into events. Full books
+// are diffed per token into deltas; the zero Normalizer skips books.
+type Normalizer struct {
+	books *polymarket.BookDiffer
+}
+
+// NewNormalizer creates a new normalizer whose book deltas carry a full
+// snapshot every snapshotEvery updates per token
+func NewNormalizer(snapshotEvery int) *Normalizer {
+	return &Normalizer{books: polymarket.NewBookDiffer(snapshotEvery)}
+}
+
+// Events returns the events in a frame, which holds one message or a batch
+// of them. Message types without an event (tick size changes, ...) and
+// books that did not change are skipped.
+func (n *Normalizer) Events(data []byte) []Event {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil
+	}
+	if data[0] != '[' {
+		return n.message(data)
+	}
+
+	var batch []json.RawMessage
+	if err := sonic.Unmarshal(data, &batch); err != nil {
+		return nil
+	}
+	var events []Event
+	for _, raw := range batch {
+		events = append(events, n.message(raw)...)
+	}
+	return events
+}
+
+// message returns the events of a single upstream message
+func (n *Normalizer) message(data []byte) []Event {
+	var msg upstream
+	if err := sonic.Unmarshal(data, &msg); err != nil {
+		return nil
+	}
+	ts, _ := strconv.ParseInt(string(msg.Timestamp), 10, 64)
+
+	switch msg.EventType {
+	case "last_trade_price":
+		return []Event{{
+			Type: TypeTrade, Market: msg.Market, AssetID: msg.AssetID, Timestamp: ts,
+			Data: Trade{Price: msg.Price.Float(), Size: msg.Size.Float(), Side: msg.Side, FeeRateBps: msg.FeeRateBps.Float()},
+		}}
+
+	case "price_change":
+		changes := msg.PriceChanges
+		if len(changes) == 0 {
+			changes = msg.Changes
+		}
+		if len(changes) == 0 {
+			changes = []upstreamChange{{Price: msg.Price, Size: msg.Size, Side: msg.Side}}
+		}
+		events := make([]Event, 0, len(changes))
+		for _, c := range changes {
+			assetID := c.AssetID
+			if assetID == "" {
+				assetID = msg.AssetID
+			}
+			events = append(events, Event{
+				Type: TypePriceChange, Market: msg.Market, AssetID: assetID, Timestamp: ts,
+				Data: PriceChange{
+					Price: c.Price.Float(), Size: c.Size.Float(), Side: c.Side,
+					BestBid: optional(c.BestBid), BestAsk: optional(c.BestAsk),
+				},
+			})
+		}
+		return events
+
+	case polymarket.BookEventSnapshot:
+		if n.books == nil {
+			return nil
+		}
+		update, _ := n.books.Apply(data)
+		if update == nil {
+			return nil
+		}
+		var book polymarket.BookUpdate
+		if err := sonic.Unmarshal(update, &book); err != nil {
+			return nil
+		}
+		return []Event{{
+			Type: TypeBookDelta, Market: book.Market, AssetID: book.AssetID, Timestamp: ts,
+			Data: BookDelta{
+				Seq:      book.Seq,
+				Snapshot: book.EventType == polymarket.BookEventSnapshot,
+				Bids:     levels(book.Bids),
+				Asks:     levels(book.Asks),
+				Hash:     book.Hash,
+			},
+		}}
+
+	case "market_resolved":
+		return []Event{{
+			Type: TypeResolution, Market: msg.Market, Timestamp: ts,
+			Data: Resolution{WinningAssetID: msg.WinningAssetID, WinningOutcome: msg.WinningOutcome, AssetIDs: msg.AssetIDs},
+		}}
+	}
+	return nil
+}
+
+// levels converts upstream levels to numbers
+func levels(in []models.PriceLevel) []Level {
+	out := make([]Level, len(in))
+	for i, l := range in {
+		out[i].Price, _ = strconv.ParseFloat(l.Price, 64)
+		out[i].Size, _ = strconv.ParseFloat(l.Size, 64)
+	}
+	return out
+}
+
+// optional returns a value's number, or nil when upstream left it out
+func optional(v models.FlexString) *float64 {
+	if v == "" {
+		return nil
+	}
+	f := v.Float()
+	return &f
+}
@@ -0,0 +1,253 @@
+package expiry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/webhooks"
+	"github.com/polygo/pkg/polygoclient"
+)
+
+// EventExpiring is the event published when a tracked GTD order reaches
+// its cancel buffer, whether or not it could be cancelled
+const EventExpiring = "order.expiring"
+
+// maxExpiration rejects millisecond timestamps, which would otherwise pass
+// as expirations tens of thousands of years away
+const maxExpiration = 1e11
+
+var (
+	// ErrExpirationRequired is returned for a GTD order without an expiration
+	ErrExpirationRequired = errors.New("expiration is required for GTD orders")
+	// ErrExpirationNotAllowed is returned for an expiration on a non-GTD order
+	ErrExpirationNotAllowed = errors.New("expiration is only allowed on GTD orders")
+	// ErrExpirationUnit is returned for an expiration that is not in unix seconds
+	ErrExpirationUnit = errors.New("expiration must be a unix timestamp in seconds")
+	// ErrExpirationPast is returned for an expiration that has already passed
+	ErrExpirationPast = errors.New("expiration is in the past")
+	// ErrExpirationTooNear is returned for an expiration within MinLifetime
+	ErrExpirationTooNear = errors.New("expiration is too near")
+)
+
+// Order is a GTD order whose expiration is being tracked
+type Order struct {
+	OrderID    string    `json:"order_id"`
+	Maker      string    `json:"maker,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CancelAt   time.Time `json:"cancel_at"`
+	AutoCancel bool      `json:"auto_cancel"` // false when no API secret was supplied to cancel with
+
+	owner string
+	creds *polygoclient.Credentials
+}
+
+// Notice is the payload of an EventExpiring event
+type Notice struct {
+	OrderID   string    `json:"order_id"`
+	Maker     string    `json:"maker,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Cancelled bool      `json:"cancelled"`
+	Reason    string    `json:"reason,omitempty"` // why the order was not cancelled
+}
+
+// Tracker validates GTD expirations at creation and, for orders placed
+// through PolyGo, cancels them CancelBuffer before they expire so they do
+// not linger until the CLOB expires them. Owners are notified through
+// webhooks and WebSocket listeners either way.
+type Tracker struct {
+	clob       *polymarket.ClobClient
+	dispatcher *webhooks.Dispatcher
+	auth       *config.AuthConfig
+	config     *config.OrderExpiryConfig
+
+	mu     sync.Mutex
+	orders map[string]*Order
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a new expiry tracker
+func New(clob *polymarket.ClobClient, dispatcher *webhooks.Dispatcher, auth *config.AuthConfig, cfg *config.OrderExpiryConfig) *Tracker {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Tracker{
+		clob:       clob,
+		dispatcher: dispatcher,
+		auth:       auth,
+		config:     cfg,
+		orders:     make(map[string]*Order),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start checks tracked expirations every Interval
+func (t *Tracker) Start() {
+	if !t.config.Enabled {
+		return
+	}
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+
+		ticker := time.NewTicker(t.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-t.ctx.Done():
+				return
+			case now := <-ticker.C:
+				t.Check(now)
+			}
+		}
+	}()
+}
+
+// Stop stops checking expirations
+func (t *Tracker) Stop() {
+	t.cancel()
+	t.wg.Wait()
+}
+
+// Validate checks an order's expiration against its type: GTD orders need
+// one at least MinLifetime away, other orders must not set one
+func (t *Tracker) Validate(req *models.CreateOrderRequest, now time.Time) error {
+	if req.Type != models.OrderTypeGTD {
+		if req.Expiration != 0 {
+			return ErrExpirationNotAllowed
+		}
+		return nil
+	}
+
+	switch {
+	case req.Expiration == 0:
+		return ErrExpirationRequired
+	case req.Expiration > maxExpiration:
+		return ErrExpirationUnit
+	case req.Expiration <= now.Unix():
+		return ErrExpirationPast
+	case time.Unix(req.Expiration, 0).Sub(now) < t.config.MinLifetime:
+		return fmt.Errorf("%w: it must be at least %s from now", ErrExpirationTooNear, t.config.MinLifetime)
+	}
+	return nil
+}
+
+// Track starts watching a placed GTD order. Without creds carrying a
+// secret the order cannot be cancelled; its owner is only notified.
+func (t *Tracker) Track(orderID, owner, maker string, expiresAt time.Time, creds *polygoclient.Credentials) {
+	if !t.config.Enabled || orderID == "" {
+		return
+	}
+	if creds != nil && creds.Secret == "" {
+		creds = nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.orders) >= t.config.MaxOrders {
+		return
+	}
+	t.orders[orderID] = &Order{
+		OrderID:    orderID,
+		Maker:      maker,
+		ExpiresAt:  expiresAt,
+		CancelAt:   expiresAt.Add(-t.config.CancelBuffer),
+		AutoCancel: creds != nil,
+		owner:      owner,
+		creds:      creds,
+	}
+}
+
+// Forget stops watching an order that was filled or cancelled
+func (t *Tracker) Forget(orderID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.orders, orderID)
+}
+
+// ForgetOwner stops watching all of an owner's orders
+func (t *Tracker) ForgetOwner(owner string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id, o := range t.orders {
+		if o.owner == owner {
+			delete(t.orders, id)
+		}
+	}
+}
+
+// Orders returns an owner's tracked orders, soonest expiry first
+func (t *Tracker) Orders(owner string) []Order {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := []Order{}
+	for _, o := range t.orders {
+		if o.owner == owner {
+			out = append(out, *o)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ExpiresAt.Before(out[j].ExpiresAt) })
+	return out
+}
+
+// Check cancels and notifies the owners of orders whose cancel time has
+// come, and stops tracking them (used by the ticker loop and tests)
+func (t *Tracker) Check(now time.Time) {
+	t.mu.Lock()
+	var due []*Order
+	for id, o := range t.orders {
+		if !o.CancelAt.After(now) {
+			due = append(due, o)
+			delete(t.orders, id)
+		}
+	}
+	t.mu.Unlock()
+
+	for _, o := range due {
+		notice := Notice{OrderID: o.OrderID, Maker: o.Maker, ExpiresAt: o.ExpiresAt}
+		if o.creds == nil {
+			notice.Reason = "no API secret was supplied with the order, so it was left to expire"
+		} else if err := t.cancelOrder(o); err != nil {
+			notice.Reason = err.Error()
+			log.Printf("Failed to cancel expiring order %s: %v", o.OrderID, err)
+		} else {
+			notice.Cancelled = true
+		}
+
+		if t.dispatcher != nil {
+			t.dispatcher.Publish(EventExpiring, "", o.owner, notice)
+		}
+	}
+}
+
+// cancelOrder cancels an order with its owner's credentials
+func (t *Tracker) cancelOrder(o *Order) error {
+	headers := polymarket.SignedHeaders(t.auth, o.creds, "DELETE", "/order/"+o.OrderID, nil)
+	data, err := t.clob.CancelOrder(o.OrderID, headers)
+	if err != nil {
+		return err
+	}
+
+	var result models.CancelResult
+	if err := sonic.Unmarshal(data, &result); err == nil {
+		if reason, ok := result.NotCanceled[o.OrderID]; ok {
+			return errors.New(reason)
+		}
+	}
+	return nil
+}
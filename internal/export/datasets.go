@@ -0,0 +1,147 @@
+package export
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/shape"
+)
+
+// tradesPageSize is how many trades are fetched per Data API request
+const tradesPageSize = 500
+
+// build collects a job's dataset as of now
+func (s *Scheduler) build(spec Spec, now time.Time) (*table, error) {
+	switch spec.Dataset {
+	case DatasetTrades:
+		return s.trades(spec.Address)
+	case DatasetCandles:
+		return s.candles(spec.TokenIDs, spec.Window, now)
+	}
+	return s.markets(), nil
+}
+
+// trades collects every trade by address, newest first, up to MaxRows
+func (s *Scheduler) trades(address string) (*table, error) {
+	// One extra trade tells the run its export was truncated
+	maxItems := s.config.MaxRows
+	if maxItems > 0 {
+		maxItems++
+	}
+	result, err := polymarket.Paginate(func(cursor string, offset int) ([]byte, error) {
+		if cursor == "" && offset > 0 {
+			cursor = strconv.Itoa(offset)
+		}
+		data, _, err := s.data.GetTrades(address, tradesPageSize, cursor, true)
+		return data, err
+	}, tradesPageSize, maxItems)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := sonic.Marshal(result.Items)
+	if err != nil {
+		return nil, err
+	}
+	shaped, err := shape.Trades(data)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &table{columns: []column{
+		{"time", kindTime},
+		{"transaction_hash", kindString},
+		{"condition_id", kindString},
+		{"token_id", kindString},
+		{"outcome", kindString},
+		{"title", kindString},
+		{"side", kindString},
+		{"price", kindFloat},
+		{"size", kindFloat},
+		{"address", kindString},
+	}}
+	for _, tr := range shaped.([]shape.Trade) {
+		var at time.Time
+		if tr.Time != nil {
+			at = *tr.Time
+		}
+		t.add(at, tr.TransactionHash, tr.ConditionID, tr.TokenID, tr.Outcome, tr.Title, tr.Side, tr.Price, tr.Size, tr.Address)
+	}
+	return t, nil
+}
+
+// candles collects the recorded candles of each token over window (the
+// recorder's whole retention when empty). Prices are the token's own, so
+// later outcomes are the complement of the recorded first-outcome price.
+func (s *Scheduler) candles(tokenIDs []string, window string, now time.Time) (*table, error) {
+	if s.recorder == nil || !s.recorder.Ready() {
+		return nil, errors.New("price recorder has no samples yet")
+	}
+	span := s.recorder.Retention()
+	if window != "" {
+		span, _ = time.ParseDuration(window)
+	}
+	since := now.Add(-span)
+
+	t := &table{columns: []column{
+		{"token_id", kindString},
+		{"market_id", kindString},
+		{"time", kindTime},
+		{"open", kindFloat},
+		{"high", kindFloat},
+		{"low", kindFloat},
+		{"close", kindFloat},
+		{"volume", kindFloat},
+	}}
+	for _, tokenID := range tokenIDs {
+		entry, outcome, ok := s.catalog.Token(tokenID)
+		if !ok {
+			continue
+		}
+		for _, c := range s.recorder.Candles(entry.ID, since) {
+			if outcome > 0 {
+				c.Open, c.High, c.Low, c.Close = 1-c.Open, 1-c.Low, 1-c.High, 1-c.Close
+			}
+			t.add(tokenID, entry.ID, c.Time, c.Open, c.High, c.Low, c.Close, c.Volume)
+		}
+	}
+	return t, nil
+}
+
+// markets collects the full market catalog
+func (s *Scheduler) markets() *table {
+	t := &table{columns: []column{
+		{"market_id", kindString},
+		{"condition_id", kindString},
+		{"question", kindString},
+		{"slug", kindString},
+		{"event_id", kindString},
+		{"event_slug", kindString},
+		{"category", kindString},
+		{"tags", kindString},
+		{"active", kindBool},
+		{"closed", kindBool},
+		{"end_date", kindTime},
+		{"volume", kindFloat},
+		{"volume_24hr", kindFloat},
+		{"liquidity", kindFloat},
+		{"outcomes", kindString},
+		{"outcome_prices", kindString},
+		{"token_ids", kindString},
+	}}
+	for _, e := range s.catalog.Markets() {
+		tags := make([]string, len(e.Tags))
+		for i, tag := range e.Tags {
+			tags[i] = tag.Slug
+		}
+		t.add(e.ID, e.ConditionID, e.Question, e.Slug, e.EventID, e.EventSlug, e.Category,
+			strings.Join(tags, ","), e.Active, e.Closed, e.EndDate,
+			e.Volume.Float(), e.Volume24hr.Float(), e.Liquidity.Float(),
+			strings.Join(e.Outcomes, ","), strings.Join(e.OutcomePrices, ","), strings.Join(e.ClobTokenIDs, ","))
+	}
+	return t
+}
@@ -0,0 +1,448 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/idgen"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/recorder"
+	"github.com/polygo/internal/storage"
+	"github.com/polygo/pkg/validate"
+)
+
+// Datasets a job can export
+const (
+	DatasetTrades  = "trades"  // every trade by Address
+	DatasetCandles = "candles" // recorded candles of TokenIDs
+	DatasetCatalog = "catalog" // the full market catalog
+)
+
+// File formats
+const (
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+)
+
+// Run statuses
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+var (
+	// ErrInvalidJob is wrapped by every job validation error
+	ErrInvalidJob = errors.New("invalid export job")
+	// ErrNotFound is returned for an unknown job ID
+	ErrNotFound = errors.New("export job not found")
+	// ErrTooManyJobs is returned when MaxJobs jobs already exist
+	ErrTooManyJobs = errors.New("too many export jobs")
+)
+
+var prefixPattern = regexp.MustCompile(`^[A-Za-z0-9._/-]*$`)
+
+// maxTokens bounds the tokens one candles job exports
+const maxTokens = 100
+
+// Spec is what a job exports, where to and how often
+type Spec struct {
+	Name     string   `json:"name"`
+	Dataset  string   `json:"dataset"`             // trades, candles or catalog
+	Address  string   `json:"address,omitempty"`   // trades: the wallet
+	TokenIDs []string `json:"token_ids,omitempty"` // candles: the tokens
+	Window   string   `json:"window,omitempty"`    // candles: how far back (default: recorder retention)
+	Format   string   `json:"format"`              // csv or parquet
+	Prefix   string   `json:"prefix,omitempty"`    // directory under the export directory (default: the job ID)
+	Interval string   `json:"interval"`            // e.g. 1h, 24h
+	Paused   bool     `json:"paused,omitempty"`
+}
+
+// Job is a scheduled export
+type Job struct {
+	ID string `json:"id"`
+	Spec
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	NextRun   *time.Time `json:"next_run,omitempty"` // unset while paused
+	LastRun   *Run       `json:"last_run,omitempty"`
+}
+
+// Run is one execution of a job
+type Run struct {
+	ID         string    `json:"id"`
+	JobID      string    `json:"job_id"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Status     string    `json:"status"`
+	Rows       int       `json:"rows"`
+	Truncated  bool      `json:"truncated,omitempty"` // more than MaxRows rows were available
+	Bytes      int       `json:"bytes"`
+	Location   string    `json:"location,omitempty"` // file path or bucket URL written
+	Error      string    `json:"error,omitempty"`
+}
+
+// job is a Job with its run history, newest first
+type job struct {
+	Job
+	interval time.Duration
+	runs     []Run
+}
+
+// Scheduler runs export jobs on their intervals and keeps their history
+type Scheduler struct {
+	data     *polymarket.DataClient
+	catalog  *catalog.Catalog
+	recorder *recorder.Recorder
+	store    storage.Store
+	config   *config.ExportConfig
+
+	mu   sync.Mutex
+	jobs map[string]*job
+
+	// runMu serializes runs so a job never overlaps itself
+	runMu sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a new export scheduler, restoring jobs saved at Path
+func New(data *polymarket.DataClient, cat *catalog.Catalog, rec *recorder.Recorder, store storage.Store, cfg *config.ExportConfig) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &Scheduler{
+		data:     data,
+		catalog:  cat,
+		recorder: rec,
+		store:    store,
+		config:   cfg,
+		jobs:     make(map[string]*job),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	if err := s.load(); err != nil {
+		log.Printf("Failed to restore export jobs from %s: %v", cfg.Path, err)
+	}
+	return s
+}
+
+// Start runs due jobs every Tick
+func (s *Scheduler) Start() {
+	if !s.config.Enabled {
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.config.Tick)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.RunDue(now)
+			}
+		}
+	}()
+}
+
+// Stop stops scheduling; a run in progress is finished first
+func (s *Scheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// List returns every job, oldest first
+func (s *Scheduler) List() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		out = append(out, j.Job)
+	}
+	sort.Slice(out, func(i, k int) bool {
+		if !out[i].CreatedAt.Equal(out[k].CreatedAt) {
+			return out[i].CreatedAt.Before(out[k].CreatedAt)
+		}
+		return out[i].ID < out[k].ID
+	})
+	return out
+}
+
+// Get returns a job
+func (s *Scheduler) Get(id string) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	return j.Job, nil
+}
+
+// Create adds a job; its first run is due immediately unless paused
+func (s *Scheduler) Create(spec Spec) (Job, error) {
+	interval, err := s.validate(&spec)
+	if err != nil {
+		return Job{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.config.MaxJobs > 0 && len(s.jobs) >= s.config.MaxJobs {
+		return Job{}, ErrTooManyJobs
+	}
+
+	now := time.Now()
+	j := &job{
+		Job:      Job{ID: idgen.WithPrefix("exp"), Spec: spec, CreatedAt: now, UpdatedAt: now},
+		interval: interval,
+	}
+	j.schedule(now)
+	s.jobs[j.ID] = j
+	s.saveLocked()
+	return j.Job, nil
+}
+
+// Update replaces a job's spec. The next run is rescheduled from the last
+// one, or due immediately if the job never ran.
+func (s *Scheduler) Update(id string, spec Spec) (Job, error) {
+	interval, err := s.validate(&spec)
+	if err != nil {
+		return Job{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	now := time.Now()
+	j.Spec = spec
+	j.interval = interval
+	j.UpdatedAt = now
+	if j.LastRun != nil {
+		j.schedule(j.LastRun.StartedAt.Add(interval))
+	} else {
+		j.schedule(now)
+	}
+	s.saveLocked()
+	return j.Job, nil
+}
+
+// Delete removes a job and its history. Files already exported are kept.
+func (s *Scheduler) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.jobs, id)
+	s.saveLocked()
+	return nil
+}
+
+// Runs returns a job's run history, newest first
+func (s *Scheduler) Runs(id string) ([]Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	out := make([]Run, len(j.runs))
+	copy(out, j.runs)
+	return out, nil
+}
+
+// RunNow runs a job immediately, paused or not, and returns the run
+func (s *Scheduler) RunNow(id string) (Run, error) {
+	s.mu.Lock()
+	j, ok := s.jobs[id]
+	var cur Job
+	if ok {
+		cur = j.Job
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		return Run{}, ErrNotFound
+	}
+	return s.run(cur, time.Now()), nil
+}
+
+// RunDue runs every unpaused job whose next run is at or before now
+func (s *Scheduler) RunDue(now time.Time) {
+	s.mu.Lock()
+	var due []Job
+	for _, j := range s.jobs {
+		if j.NextRun != nil && !j.NextRun.After(now) {
+			due = append(due, j.Job)
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Slice(due, func(i, k int) bool { return due[i].NextRun.Before(*due[k].NextRun) })
+	for _, j := range due {
+		if s.ctx.Err() != nil {
+			return
+		}
+		s.run(j, now)
+	}
+}
+
+// run exports a job's dataset, records the run and schedules the next one
+func (s *Scheduler) run(j Job, now time.Time) Run {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	r := Run{ID: idgen.WithPrefix("run"), JobID: j.ID, StartedAt: time.Now()}
+	if err := s.export(j, now, &r); err != nil {
+		r.Status = StatusFailed
+		r.Error = err.Error()
+		log.Printf("Export job %s failed: %v", j.ID, err)
+	} else {
+		r.Status = StatusSucceeded
+	}
+	r.FinishedAt = time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The job may have been deleted while it ran
+	if cur, ok := s.jobs[j.ID]; ok {
+		cur.LastRun = &r
+		cur.runs = append([]Run{r}, cur.runs...)
+		if s.config.History > 0 && len(cur.runs) > s.config.History {
+			cur.runs = cur.runs[:s.config.History]
+		}
+		cur.schedule(r.StartedAt.Add(cur.interval))
+		s.saveLocked()
+	}
+	return r
+}
+
+// export builds, encodes and writes one run's file
+func (s *Scheduler) export(j Job, now time.Time, r *Run) error {
+	t, err := s.build(j.Spec, now)
+	if err != nil {
+		return err
+	}
+	r.Truncated = t.truncate(s.config.MaxRows)
+	r.Rows = len(t.rows)
+
+	var buf bytes.Buffer
+	if j.Format == FormatParquet {
+		err = writeParquet(&buf, t)
+	} else {
+		err = writeCSV(&buf, t)
+	}
+	if err != nil {
+		return err
+	}
+	r.Bytes = buf.Len()
+
+	prefix := j.Prefix
+	if prefix == "" {
+		prefix = j.ID
+	}
+	key := path.Join(s.config.Dir, prefix, j.Dataset+"-"+now.UTC().Format("20060102T150405Z")+"."+j.Format)
+
+	if err := s.store.Put(key, buf.Bytes()); err != nil {
+		return err
+	}
+	r.Location = s.store.Location(key)
+	return nil
+}
+
+// validate normalizes a spec and returns its interval
+func (s *Scheduler) validate(spec *Spec) (time.Duration, error) {
+	spec.Name = strings.TrimSpace(spec.Name)
+	spec.Dataset = strings.ToLower(strings.TrimSpace(spec.Dataset))
+	spec.Format = strings.ToLower(strings.TrimSpace(spec.Format))
+	spec.Prefix = strings.Trim(strings.TrimSpace(spec.Prefix), "/")
+	if spec.Format == "" {
+		spec.Format = FormatCSV
+	}
+
+	switch spec.Dataset {
+	case DatasetTrades:
+		if !validate.IsAddress(spec.Address) {
+			return 0, fmt.Errorf("%w: trades exports need a 0x wallet address", ErrInvalidJob)
+		}
+		spec.Address = strings.ToLower(spec.Address)
+		spec.TokenIDs, spec.Window = nil, ""
+	case DatasetCandles:
+		if len(spec.TokenIDs) == 0 || len(spec.TokenIDs) > maxTokens {
+			return 0, fmt.Errorf("%w: candles exports need 1 to %d token_ids", ErrInvalidJob, maxTokens)
+		}
+		if spec.Window != "" {
+			if d, err := time.ParseDuration(spec.Window); err != nil || d <= 0 {
+				return 0, fmt.Errorf("%w: window must be a duration like 24h", ErrInvalidJob)
+			}
+		}
+		spec.Address = ""
+	case DatasetCatalog:
+		spec.Address, spec.TokenIDs, spec.Window = "", nil, ""
+	default:
+		return 0, fmt.Errorf("%w: dataset must be trades, candles or catalog", ErrInvalidJob)
+	}
+
+	if spec.Format != FormatCSV && spec.Format != FormatParquet {
+		return 0, fmt.Errorf("%w: format must be csv or parquet", ErrInvalidJob)
+	}
+	if !prefixPattern.MatchString(spec.Prefix) || hasDotSegment(spec.Prefix) {
+		return 0, fmt.Errorf("%w: prefix may only hold letters, digits, '.', '_', '-' and '/' and no '..' segments", ErrInvalidJob)
+	}
+
+	interval, err := time.ParseDuration(spec.Interval)
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("%w: interval must be a duration like 1h", ErrInvalidJob)
+	}
+	if interval < s.config.MinInterval {
+		return 0, fmt.Errorf("%w: interval must be at least %s", ErrInvalidJob, s.config.MinInterval)
+	}
+	return interval, nil
+}
+
+// hasDotSegment reports whether a prefix contains a "." or ".." segment
+func hasDotSegment(prefix string) bool {
+	for _, seg := range strings.Split(prefix, "/") {
+		if seg == "." || seg == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// schedule sets the next run to at, or clears it while paused
+func (j *job) schedule(at time.Time) {
+	if j.Paused {
+		j.NextRun = nil
+		return
+	}
+	j.NextRun = &at
+}
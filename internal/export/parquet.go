@@ -0,0 +1,76 @@
+package export
+
+import (
+	"io"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// parquetNode maps a column kind to its optional Parquet column
+func parquetNode(k kind) parquet.Node {
+	switch k {
+	case kindFloat:
+		return parquet.Optional(parquet.Leaf(parquet.DoubleType))
+	case kindInt:
+		return parquet.Optional(parquet.Int(64))
+	case kindBool:
+		return parquet.Optional(parquet.Leaf(parquet.BooleanType))
+	case kindTime:
+		return parquet.Optional(parquet.Timestamp(parquet.Millisecond))
+	}
+	return parquet.Optional(parquet.String())
+}
+
+// parquetValue converts a row value to its Parquet value, false for nulls
+func parquetValue(v interface{}) (parquet.Value, bool) {
+	if isNull(v) {
+		return parquet.Value{}, false
+	}
+	switch v := v.(type) {
+	case string:
+		return parquet.ByteArrayValue([]byte(v)), true
+	case float64:
+		return parquet.DoubleValue(v), true
+	case int64:
+		return parquet.Int64Value(v), true
+	case bool:
+		return parquet.BooleanValue(v), true
+	case time.Time:
+		return parquet.Int64Value(v.UnixMilli()), true
+	}
+	return parquet.Value{}, false
+}
+
+// writeParquet encodes t as a Parquet file of flat, optional columns.
+// Parquet groups order their columns by name, so they may come out in a
+// different order than in CSV.
+func writeParquet(w io.Writer, t *table) error {
+	group := make(parquet.Group, len(t.columns))
+	index := make(map[string]int, len(t.columns))
+	for i, col := range t.columns {
+		group[col.name] = parquetNode(col.kind)
+		index[col.name] = i
+	}
+	schema := parquet.NewSchema("export", group)
+	fields := schema.Fields()
+
+	rows := make([]parquet.Row, len(t.rows))
+	for r, values := range t.rows {
+		row := make(parquet.Row, len(fields))
+		for c, field := range fields {
+			if v, ok := parquetValue(values[index[field.Name()]]); ok {
+				row[c] = v.Level(0, 1, c)
+			} else {
+				row[c] = parquet.Value{}.Level(0, 0, c)
+			}
+		}
+		rows[r] = row
+	}
+
+	pw := parquet.NewWriter(w, schema)
+	if _, err := pw.WriteRows(rows); err != nil {
+		return err
+	}
+	return pw.Close()
+}
@@ -0,0 +1,85 @@
+package export
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/fsutil"
+)
+
+// stored is the on-disk form of every job and its run history
+type stored struct {
+	Jobs []storedJob `json:"jobs"`
+}
+
+type storedJob struct {
+	Job
+	Runs []Run `json:"runs"`
+}
+
+// load restores the jobs saved at Path; a missing file is not an error.
+// Jobs whose spec no longer validates (e.g. MinInterval was raised) are kept
+// but paused.
+func (s *Scheduler) load() error {
+	if s.config.Path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.config.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var st stored
+	if err := sonic.Unmarshal(data, &st); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sj := range st.Jobs {
+		j := &job{Job: sj.Job, runs: sj.Runs}
+		interval, err := s.validate(&j.Spec)
+		if err != nil {
+			log.Printf("Pausing export job %s: %v", j.ID, err)
+			j.Paused = true
+			j.NextRun = nil
+		} else {
+			j.interval = interval
+		}
+		if j.runs == nil {
+			j.runs = []Run{}
+		}
+		s.jobs[j.ID] = j
+	}
+	return nil
+}
+
+// saveLocked writes every job to Path, replacing the file atomically.
+// Failures are logged; the in-memory jobs stay authoritative. Caller holds
+// s.mu.
+func (s *Scheduler) saveLocked() {
+	if s.config.Path == "" {
+		return
+	}
+
+	st := stored{Jobs: make([]storedJob, 0, len(s.jobs))}
+	for _, j := range s.jobs {
+		st.Jobs = append(st.Jobs, storedJob{Job: j.Job, Runs: j.runs})
+	}
+
+	data, err := sonic.Marshal(st)
+	if err == nil {
+		err = fsutil.WriteFileAtomic(s.config.Path, data, 0o600)
+	}
+	if err != nil {
+		log.Printf("Failed to save export jobs to %s: %v", s.config.Path, err)
+	}
+}
@@ -0,0 +1,105 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// kind is the type of a table column's values
+type kind int
+
+const (
+	kindString kind = iota
+	kindFloat
+	kindInt
+	kindBool
+	kindTime
+)
+
+// column is a named, typed table column
+type column struct {
+	name string
+	kind kind
+}
+
+// table is a dataset ready to be encoded. Row values are string, float64,
+// int64, bool or time.Time matching their column's kind; nil and the zero
+// time are nulls.
+type table struct {
+	columns []column
+	rows    [][]interface{}
+}
+
+// add appends a row
+func (t *table) add(values ...interface{}) {
+	t.rows = append(t.rows, values)
+}
+
+// truncate keeps the first max rows (all of them when max <= 0) and
+// reports whether any were dropped
+func (t *table) truncate(max int) bool {
+	if max > 0 && len(t.rows) > max {
+		t.rows = t.rows[:max]
+		return true
+	}
+	return false
+}
+
+// isNull reports whether v is a null cell
+func isNull(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	if ts, ok := v.(time.Time); ok {
+		return ts.IsZero()
+	}
+	return false
+}
+
+// writeCSV encodes t as CSV with a header row. Times are RFC3339 and nulls
+// empty.
+func writeCSV(w io.Writer, t *table) error {
+	cw := csv.NewWriter(w)
+
+	record := make([]string, len(t.columns))
+	for i, col := range t.columns {
+		record[i] = col.name
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+
+	for _, row := range t.rows {
+		for i, v := range row {
+			record[i] = formatCell(v)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatCell renders one value for CSV
+func formatCell(v interface{}) string {
+	if isNull(v) {
+		return ""
+	}
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	}
+	return ""
+}
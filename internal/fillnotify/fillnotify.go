@@ -0,0 +1,433 @@
+// Package fillnotify watches the CLOB user channel for fills of resting
+// orders placed through PolyGo and announces each one to the caller that
+// placed the order, through its webhooks and /ws/events, so bots placing
+// orders over REST learn of fills without a WebSocket of their own.
+//
+// One user channel connection is kept per CLOB API key with watched
+// orders, opened with the caller's L2 credentials and closed IdleTimeout
+// after its last watched order is filled or cancelled.
+package fillnotify
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/webhooks"
+	"github.com/polygo/pkg/polygoclient"
+)
+
+// EventFilled is published for every fill of a watched order. Order
+// lookups that find a fill of an unwatched order publish it too, with only
+// order_id and maker.
+const EventFilled = "order.filled"
+
+// Order is a resting order placed through PolyGo
+type Order struct {
+	ID        string
+	RequestID string // of the request that placed it, carried by its fill events
+	Owner     string // whose webhooks and /ws/events listeners are notified
+	Maker     string
+	Size      string // original size, to tell when the order is done
+}
+
+// Fill is the payload of an EventFilled event: one trade of an order
+type Fill struct {
+	OrderID     string `json:"order_id"`
+	Maker       string `json:"maker,omitempty"`
+	TradeID     string `json:"trade_id"`
+	Role        string `json:"role"` // maker or taker
+	Market      string `json:"market"`
+	AssetID     string `json:"asset_id"`
+	Outcome     string `json:"outcome,omitempty"`
+	Side        string `json:"side"` // of the order
+	Price       string `json:"price"`
+	Size        string `json:"size"`         // filled by this trade
+	SizeMatched string `json:"size_matched"` // filled so far, across trades
+	Remaining   string `json:"remaining,omitempty"`
+	Status      string `json:"status"`    // trade status when first reported, usually MATCHED
+	Timestamp   int64  `json:"timestamp"` // unix ms
+}
+
+// userEvent is the union of the user channel's trade and order events
+type userEvent struct {
+	EventType    string          `json:"event_type"` // trade or order
+	ID           string          `json:"id"`         // trade ID, or order ID
+	Type         string          `json:"type"`       // order events: PLACEMENT, UPDATE or CANCELLATION
+	Market       string          `json:"market"`
+	AssetID      string          `json:"asset_id"`
+	Outcome      string          `json:"outcome"`
+	Side         string          `json:"side"`
+	Price        string          `json:"price"`
+	Size         string          `json:"size"`
+	SizeMatched  string          `json:"size_matched"`
+	Status       string          `json:"status"`
+	TakerOrderID string          `json:"taker_order_id"`
+	MakerOrders  []makerOrder    `json:"maker_orders"`
+	Timestamp    json.RawMessage `json:"timestamp"` // unix seconds or ms, as a string or a number
+}
+
+// makerOrder is a resting order matched by a trade
+type makerOrder struct {
+	OrderID       string `json:"order_id"`
+	AssetID       string `json:"asset_id"`
+	Outcome       string `json:"outcome"`
+	Side          string `json:"side"`
+	Price         string `json:"price"`
+	MatchedAmount string `json:"matched_amount"`
+}
+
+// watched is a tracked order
+type watched struct {
+	Order
+	apiKey  string
+	size    float64 // 0 if unknown
+	matched float64
+	trades  map[string]bool // trades already announced
+}
+
+// stream is the user channel of one API key
+type stream struct {
+	*polymarket.UserStream
+	orders    int       // watched orders
+	idleSince time.Time // when orders last dropped to 0
+}
+
+// published is an event to publish once the lock is released
+type published struct {
+	order *watched
+	fill  Fill
+}
+
+// Notifier watches orders and publishes their fills
+type Notifier struct {
+	polymarket *config.PolymarketConfig
+	data       *polymarket.DataClient
+	dispatcher *webhooks.Dispatcher
+	fills      *polymarket.FillTracker
+	config     *config.FillNotifyConfig
+
+	mu      sync.Mutex
+	orders  map[string]*watched
+	streams map[string]*stream
+	onFill  []func(Fill)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a new fill notifier. Makers' cached user data is dropped on
+// each fill, and fills is told of them so order lookups do not announce
+// them again.
+func New(pm *config.PolymarketConfig, data *polymarket.DataClient, dispatcher *webhooks.Dispatcher, fills *polymarket.FillTracker, cfg *config.FillNotifyConfig) *Notifier {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Notifier{
+		polymarket: pm,
+		data:       data,
+		dispatcher: dispatcher,
+		fills:      fills,
+		config:     cfg,
+		orders:     make(map[string]*watched),
+		streams:    make(map[string]*stream),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Enabled reports whether orders are watched
+func (n *Notifier) Enabled() bool {
+	return n.config.Enabled && n.polymarket.WsUserURL != ""
+}
+
+// Start closes idle user channels in the background
+func (n *Notifier) Start() {
+	if !n.Enabled() {
+		return
+	}
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-n.ctx.Done():
+				return
+			case now := <-ticker.C:
+				n.Sweep(now)
+			}
+		}
+	}()
+}
+
+// Stop closes every user channel; orders are no longer watched
+func (n *Notifier) Stop() {
+	n.cancel()
+	n.wg.Wait()
+
+	n.mu.Lock()
+	streams := n.streams
+	n.streams = make(map[string]*stream)
+	n.orders = make(map[string]*watched)
+	n.mu.Unlock()
+
+	for _, s := range streams {
+		s.Close()
+	}
+}
+
+// Track starts watching an order, opening the user channel of creds' API
+// key if needed. Orders placed without the API secret cannot be watched.
+func (n *Notifier) Track(o Order, creds *polygoclient.Credentials) {
+	if !n.Enabled() || o.ID == "" || creds == nil || creds.Secret == "" {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.ctx.Err() != nil || len(n.orders) >= n.config.MaxOrders {
+		return
+	}
+	if _, ok := n.orders[o.ID]; ok {
+		return
+	}
+	size, _ := strconv.ParseFloat(o.Size, 64)
+	n.orders[o.ID] = &watched{Order: o, apiKey: creds.APIKey, size: size, trades: make(map[string]bool)}
+
+	s, ok := n.streams[creds.APIKey]
+	if !ok {
+		key := creds.APIKey
+		auth := polymarket.WSAuth{APIKey: creds.APIKey, Secret: creds.Secret, Passphrase: creds.Passphrase}
+		s = &stream{UserStream: polymarket.NewUserStream(n.polymarket, auth, func(data []byte) {
+			n.handle(key, data)
+		})}
+		n.streams[key] = s
+	}
+	s.orders++
+}
+
+// OnFill registers fn to be called with every fill published
+func (n *Notifier) OnFill(fn func(Fill)) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.onFill = append(n.onFill, fn)
+}
+
+// Forget stops watching an order cancelled through PolyGo
+func (n *Notifier) Forget(orderID string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.doneLocked(orderID, time.Now())
+}
+
+// Watching returns the number of orders watched
+func (n *Notifier) Watching() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.orders)
+}
+
+// Streams returns the number of open user channels
+func (n *Notifier) Streams() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.streams)
+}
+
+// Sweep closes the user channels idle for IdleTimeout as of now
+func (n *Notifier) Sweep(now time.Time) {
+	n.mu.Lock()
+	var idle []*stream
+	for key, s := range n.streams {
+		if s.orders == 0 && now.Sub(s.idleSince) >= n.config.IdleTimeout {
+			delete(n.streams, key)
+			idle = append(idle, s)
+		}
+	}
+	n.mu.Unlock()
+
+	// Outside the lock: Close waits for the stream's handler, which takes it
+	for _, s := range idle {
+		s.Close()
+	}
+}
+
+// doneLocked stops watching an order; caller holds mu
+func (n *Notifier) doneLocked(orderID string, now time.Time) {
+	o, ok := n.orders[orderID]
+	if !ok {
+		return
+	}
+	delete(n.orders, orderID)
+	if s := n.streams[o.apiKey]; s != nil {
+		if s.orders--; s.orders == 0 {
+			s.idleSince = now
+		}
+	}
+}
+
+// handle publishes the fills of watched orders in a user channel frame of
+// apiKey, which holds one event or a JSON array of them
+func (n *Notifier) handle(apiKey string, data []byte) {
+	var events []userEvent
+	if err := sonic.Unmarshal(data, &events); err != nil {
+		var event userEvent
+		if err := sonic.Unmarshal(data, &event); err != nil {
+			return
+		}
+		events = []userEvent{event}
+	}
+
+	now := time.Now()
+	var out []published
+	n.mu.Lock()
+	for _, e := range events {
+		switch strings.ToLower(e.EventType) {
+		case "trade":
+			out = append(out, n.tradeLocked(apiKey, e, now)...)
+		case "order":
+			n.orderLocked(apiKey, e, now)
+		}
+	}
+	hooks := n.onFill
+	n.mu.Unlock()
+
+	for _, p := range out {
+		if n.data != nil && p.order.Maker != "" {
+			n.data.InvalidateUser(p.order.Maker)
+		}
+		n.dispatcher.Publish(EventFilled, p.order.RequestID, p.order.Owner, p.fill)
+		for _, fn := range hooks {
+			fn(p.fill)
+		}
+	}
+}
+
+// tradeLocked returns the fills of watched orders in a trade not
+// announced yet; caller holds mu. The CLOB reports a trade again as it
+// settles (MINED, CONFIRMED); only the first report is announced.
+func (n *Notifier) tradeLocked(apiKey string, e userEvent, now time.Time) []published {
+	if strings.EqualFold(e.Status, "FAILED") {
+		return nil
+	}
+
+	ts := timestamp(e.Timestamp, now)
+	var out []published
+	add := func(orderID string, fill Fill) {
+		o, ok := n.orders[orderID]
+		if !ok || o.apiKey != apiKey || o.trades[e.ID] {
+			return
+		}
+		o.trades[e.ID] = true
+
+		size, _ := strconv.ParseFloat(fill.Size, 64)
+		o.matched += size
+		fill.OrderID = orderID
+		fill.Maker = o.Maker
+		fill.TradeID = e.ID
+		fill.Market = e.Market
+		fill.Status = strings.ToUpper(e.Status)
+		fill.Timestamp = ts
+		fill.SizeMatched = format(o.matched)
+		if o.size > 0 {
+			fill.Remaining = format(max(o.size-o.matched, 0))
+		}
+		out = append(out, published{order: o, fill: fill})
+
+		// Lookups of the order then find nothing new to announce
+		if o.size > 0 && o.matched >= o.size-1e-9 {
+			n.fills.Observe(orderID, "MATCHED", "")
+			n.doneLocked(orderID, now)
+		}
+	}
+
+	add(e.TakerOrderID, Fill{
+		Role:    "taker",
+		AssetID: e.AssetID,
+		Outcome: e.Outcome,
+		Side:    strings.ToUpper(e.Side),
+		Price:   e.Price,
+		Size:    e.Size,
+	})
+	for _, m := range e.MakerOrders {
+		add(m.OrderID, Fill{
+			Role:    "maker",
+			AssetID: m.AssetID,
+			Outcome: m.Outcome,
+			Side:    makerSide(m, e),
+			Price:   m.Price,
+			Size:    m.MatchedAmount,
+		})
+	}
+	return out
+}
+
+// orderLocked follows the updates of a watched order; caller holds mu
+func (n *Notifier) orderLocked(apiKey string, e userEvent, now time.Time) {
+	o, ok := n.orders[e.ID]
+	if !ok || o.apiKey != apiKey {
+		return
+	}
+
+	switch strings.ToUpper(e.Type) {
+	case "CANCELLATION":
+		n.doneLocked(e.ID, now)
+	case "UPDATE":
+		// In the CLOB's format, so lookups see no change
+		if e.SizeMatched != "" {
+			n.fills.Observe(e.ID, "", e.SizeMatched)
+		}
+	}
+}
+
+// makerSide is the side of a matched resting order. Trades report the
+// taker's side; a maker of the same token took the other side, one of the
+// complementary token (matched by minting or merging) the same side.
+func makerSide(m makerOrder, e userEvent) string {
+	if m.Side != "" {
+		return strings.ToUpper(m.Side)
+	}
+	side := strings.ToUpper(e.Side)
+	if m.AssetID != "" && m.AssetID != e.AssetID {
+		return side
+	}
+	switch side {
+	case "BUY":
+		return "SELL"
+	case "SELL":
+		return "BUY"
+	}
+	return side
+}
+
+// timestamp decodes an event time in unix seconds or ms as unix ms,
+// falling back to now
+func timestamp(raw json.RawMessage, now time.Time) int64 {
+	ts, err := strconv.ParseInt(strings.Trim(string(raw), `"`), 10, 64)
+	switch {
+	case err != nil || ts <= 0:
+		return now.UnixMilli()
+	case ts < 1e12:
+		return ts * 1000
+	}
+	return ts
+}
+
+// format formats a size to the CLOB's 6 decimals, dropping the float
+// noise of summing fills
+func format(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1e6)/1e6, 'f', -1, 64)
+}
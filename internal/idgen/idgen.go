@@ -0,0 +1,23 @@
+// Package idgen generates random identifiers for requests, events and
+// other server-side records
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// New returns a random 128-bit identifier encoded as hex
+func New() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic("idgen: crypto/rand unavailable: " + err.Error())
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// WithPrefix returns a random identifier prefixed with prefix and an
+// underscore (e.g. "dlv_3f2a...")
+func WithPrefix(prefix string) string {
+	return prefix + "_" + New()
+}
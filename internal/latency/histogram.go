@@ -0,0 +1,114 @@
+// Package latency keeps per-route request latency histograms and measures
+// how much of each request was spent waiting on Polymarket, so slow
+// requests can be pinned on the upstream or on PolyGo itself
+package latency
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// Bounds are the histogram bucket upper bounds; slower observations fall
+// in a final overflow bucket
+var Bounds = []time.Duration{
+	time.Millisecond,
+	2500 * time.Microsecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// Histogram counts observations in fixed buckets. It is safe for
+// concurrent use without locking.
+type Histogram struct {
+	buckets [14]atomic.Uint64 // len(Bounds)+1
+	count   atomic.Uint64
+	sum     atomic.Int64 // nanoseconds
+	max     atomic.Int64 // nanoseconds
+}
+
+// Observe records one duration
+func (h *Histogram) Observe(d time.Duration) {
+	i := 0
+	for i < len(Bounds) && d > Bounds[i] {
+		i++
+	}
+	h.buckets[i].Add(1)
+	h.count.Add(1)
+	h.sum.Add(int64(d))
+	for {
+		m := h.max.Load()
+		if int64(d) <= m || h.max.CompareAndSwap(m, int64(d)) {
+			break
+		}
+	}
+}
+
+// Count returns the number of observations
+func (h *Histogram) Count() uint64 {
+	return h.count.Load()
+}
+
+// Sum returns the total of all observations
+func (h *Histogram) Sum() time.Duration {
+	return time.Duration(h.sum.Load())
+}
+
+// Max returns the slowest observation
+func (h *Histogram) Max() time.Duration {
+	return time.Duration(h.max.Load())
+}
+
+// Buckets returns the cumulative count at or below each of Bounds, then
+// the overall count
+func (h *Histogram) Buckets() []uint64 {
+	out := make([]uint64, len(h.buckets))
+	var total uint64
+	for i := range h.buckets {
+		total += h.buckets[i].Load()
+		out[i] = total
+	}
+	return out
+}
+
+// Quantile estimates the q-th quantile (0 < q <= 1), interpolating within
+// the bucket it falls in. Overflow observations are placed up to Max.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	buckets := h.Buckets()
+	total := buckets[len(buckets)-1]
+	if total == 0 {
+		return 0
+	}
+	rank := q * float64(total)
+
+	var below uint64
+	for i, cumulative := range buckets {
+		if float64(cumulative) < rank {
+			below = cumulative
+			continue
+		}
+		lower := time.Duration(0)
+		if i > 0 {
+			lower = Bounds[i-1]
+		}
+		upper := h.Max()
+		if i < len(Bounds) && Bounds[i] < upper {
+			upper = Bounds[i]
+		}
+		if upper <= lower {
+			return upper
+		}
+		fraction := (rank - float64(below)) / float64(cumulative-below)
+		return lower + time.Duration(math.Round(fraction*float64(upper-lower)))
+	}
+	return h.Max()
+}
@@ -0,0 +1,188 @@
+package latency
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// routeKey identifies a route by method and pattern (e.g.
+// "GET /api/v1/book/:token_id"), keeping the number of histograms bounded
+type routeKey struct {
+	method string
+	route  string
+}
+
+// route holds the histograms of one route
+type route struct {
+	total    Histogram
+	upstream Histogram
+	errors   atomic.Uint64
+}
+
+// Recorder keeps latency histograms per route
+type Recorder struct {
+	mu     sync.RWMutex
+	routes map[routeKey]*route
+}
+
+// NewRecorder creates a new latency recorder
+func NewRecorder() *Recorder {
+	return &Recorder{routes: make(map[routeKey]*route)}
+}
+
+// Observe records a finished request: its total latency, the part spent
+// waiting on upstream, and whether it failed with a 5xx
+func (r *Recorder) Observe(method, pattern string, total, upstream time.Duration, status int) {
+	key := routeKey{method: method, route: pattern}
+	r.mu.RLock()
+	rt, ok := r.routes[key]
+	r.mu.RUnlock()
+	if !ok {
+		r.mu.Lock()
+		if rt, ok = r.routes[key]; !ok {
+			// Keys outlive the request; fiber's strings may point into
+			// reused buffers
+			key = routeKey{method: strings.Clone(method), route: strings.Clone(pattern)}
+			rt = &route{}
+			r.routes[key] = rt
+		}
+		r.mu.Unlock()
+	}
+
+	rt.total.Observe(total)
+	rt.upstream.Observe(upstream)
+	if status >= 500 {
+		rt.errors.Add(1)
+	}
+}
+
+// Percentiles summarize a histogram in milliseconds
+type Percentiles struct {
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+// RouteStats summarizes one route's latencies
+type RouteStats struct {
+	Method     string      `json:"method"`
+	Route      string      `json:"route"`
+	Count      uint64      `json:"count"`
+	Errors     uint64      `json:"errors"` // 5xx responses
+	LatencyMs  Percentiles `json:"latency_ms"`
+	UpstreamMs Percentiles `json:"upstream_ms"` // time spent waiting on Polymarket
+}
+
+// Stats returns every route's latency summary, busiest first
+func (r *Recorder) Stats() []RouteStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]RouteStats, 0, len(r.routes))
+	for key, rt := range r.routes {
+		out = append(out, RouteStats{
+			Method:     key.method,
+			Route:      key.route,
+			Count:      rt.total.Count(),
+			Errors:     rt.errors.Load(),
+			LatencyMs:  percentiles(&rt.total),
+			UpstreamMs: percentiles(&rt.upstream),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Method+out[i].Route < out[j].Method+out[j].Route
+	})
+	return out
+}
+
+// WritePrometheus writes the histograms in the Prometheus text format:
+// request and upstream durations as histograms, plus their p50/p95/p99 as
+// summaries for dashboards without histogram_quantile
+func (r *Recorder) WritePrometheus(w io.Writer) {
+	type entry struct {
+		key routeKey
+		rt  *route
+	}
+	r.mu.RLock()
+	entries := make([]entry, 0, len(r.routes))
+	for key, rt := range r.routes {
+		entries = append(entries, entry{key, rt})
+	}
+	r.mu.RUnlock()
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i].key, entries[j].key
+		return a.route+a.method < b.route+b.method
+	})
+
+	families := []struct {
+		name, help string
+		get        func(*route) *Histogram
+	}{
+		{"polygo_http_request_duration_seconds", "Time to serve a request, by route", func(rt *route) *Histogram { return &rt.total }},
+		{"polygo_http_request_upstream_seconds", "Time a request spent waiting on Polymarket, by route", func(rt *route) *Histogram { return &rt.upstream }},
+	}
+	for _, f := range families {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", f.name, f.help, f.name)
+		for _, e := range entries {
+			h := f.get(e.rt)
+			labels := `method="` + e.key.method + `",route="` + escapeLabel(e.key.route) + `"`
+			for i, cumulative := range h.Buckets() {
+				le := "+Inf"
+				if i < len(Bounds) {
+					le = formatSeconds(Bounds[i])
+				}
+				fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n", f.name, labels, le, cumulative)
+			}
+			fmt.Fprintf(w, "%s_sum{%s} %s\n%s_count{%s} %d\n", f.name, labels, formatSeconds(h.Sum()), f.name, labels, h.Count())
+		}
+
+		quantiles := f.name + "_quantile"
+		fmt.Fprintf(w, "# HELP %s %s (estimated quantiles)\n# TYPE %s summary\n", quantiles, f.help, quantiles)
+		for _, e := range entries {
+			h := f.get(e.rt)
+			labels := `method="` + e.key.method + `",route="` + escapeLabel(e.key.route) + `"`
+			for _, q := range []string{"0.5", "0.95", "0.99"} {
+				qf, _ := strconv.ParseFloat(q, 64)
+				fmt.Fprintf(w, "%s{%s,quantile=\"%s\"} %s\n", quantiles, labels, q, formatSeconds(h.Quantile(qf)))
+			}
+			fmt.Fprintf(w, "%s_sum{%s} %s\n%s_count{%s} %d\n", quantiles, labels, formatSeconds(h.Sum()), quantiles, labels, h.Count())
+		}
+	}
+
+	fmt.Fprintf(w, "# HELP polygo_http_request_errors_total Requests answered with a 5xx, by route\n# TYPE polygo_http_request_errors_total counter\n")
+	for _, e := range entries {
+		fmt.Fprintf(w, "polygo_http_request_errors_total{method=\"%s\",route=\"%s\"} %d\n", e.key.method, escapeLabel(e.key.route), e.rt.errors.Load())
+	}
+}
+
+func percentiles(h *Histogram) Percentiles {
+	return Percentiles{
+		P50: milliseconds(h.Quantile(0.50)),
+		P95: milliseconds(h.Quantile(0.95)),
+		P99: milliseconds(h.Quantile(0.99)),
+		Max: milliseconds(h.Max()),
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
+
+// escapeLabel escapes a Prometheus label value
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,133 @@
+package latency
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Timing accumulates the upstream calls made for one request. Upstream
+// time is wall time with at least one call in flight, so calls fanned out
+// in parallel are not counted twice.
+type Timing struct {
+	mu       sync.Mutex
+	inFlight int
+	since    time.Time
+	upstream time.Duration
+	calls    int
+}
+
+// Upstream returns the time spent waiting on upstream calls so far
+func (t *Timing) Upstream() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	d := t.upstream
+	if t.inFlight > 0 {
+		d += time.Since(t.since)
+	}
+	return d
+}
+
+// Calls returns the number of upstream calls made so far
+func (t *Timing) Calls() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.calls
+}
+
+// begin marks the start of an upstream call
+func (t *Timing) begin() {
+	t.mu.Lock()
+	if t.inFlight == 0 {
+		t.since = time.Now()
+	}
+	t.inFlight++
+	t.calls++
+	t.mu.Unlock()
+}
+
+// end marks the end of an upstream call
+func (t *Timing) end() {
+	t.mu.Lock()
+	t.inFlight--
+	if t.inFlight == 0 {
+		t.upstream += time.Since(t.since)
+	}
+	t.mu.Unlock()
+}
+
+// Request handlers call the upstream clients without a context, so a
+// request's Timing is found through the goroutine serving it. bound counts
+// live bindings so upstream calls skip the lookup when nothing is measured.
+var (
+	timings sync.Map // goroutine ID -> *Timing
+	bound   atomic.Int64
+)
+
+// Bind attributes upstream calls made on the current goroutine to t until
+// the returned function is called
+func (t *Timing) Bind() func() {
+	id := goroutineID()
+	timings.Store(id, t)
+	bound.Add(1)
+	return func() {
+		timings.Delete(id)
+		bound.Add(-1)
+	}
+}
+
+// Current returns the Timing bound to the current goroutine, or nil
+func Current() *Timing {
+	if bound.Load() == 0 {
+		return nil
+	}
+	if t, ok := timings.Load(goroutineID()); ok {
+		return t.(*Timing)
+	}
+	return nil
+}
+
+// Inherit captures the current goroutine's Timing for a goroutine it is
+// about to start, which calls the result to bind it:
+//
+//	bind := latency.Inherit()
+//	go func() {
+//		defer bind()()
+//		...
+//	}()
+func Inherit() func() func() {
+	t := Current()
+	return func() func() {
+		if t == nil {
+			return func() {}
+		}
+		return t.Bind()
+	}
+}
+
+// StartUpstream marks the start of an upstream call for the current
+// goroutine's request, returning the function that marks its end
+func StartUpstream() func() {
+	t := Current()
+	if t == nil {
+		return func() {}
+	}
+	t.begin()
+	return t.end
+}
+
+// goroutineID reads the current goroutine's ID from its stack header
+// ("goroutine 123 [running]:")
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
@@ -0,0 +1,281 @@
+package leaderboard
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/polymarket"
+)
+
+// Entry is one trader's position on a leaderboard snapshot
+type Entry struct {
+	Rank    int     `json:"rank"`
+	Address string  `json:"address"`
+	Name    string  `json:"name,omitempty"`
+	PnL     float64 `json:"pnl"`
+	Volume  float64 `json:"volume"`
+}
+
+// Snapshot is the leaderboard as polled at one time
+type Snapshot struct {
+	Time    time.Time `json:"time"`
+	Entries []Entry   `json:"entries"`
+}
+
+// Point is a trader's standing in one snapshot
+type Point struct {
+	Time   time.Time `json:"time"`
+	Rank   int       `json:"rank"`
+	PnL    float64   `json:"pnl"`
+	Volume float64   `json:"volume"`
+}
+
+// TraderHistory is a trader's rank and PnL trajectory over a window
+type TraderHistory struct {
+	Address      string  `json:"address"`
+	Name         string  `json:"name,omitempty"`
+	Rank         int     `json:"rank"`          // in the latest snapshot; 0 if no longer ranked
+	PreviousRank int     `json:"previous_rank"` // in the first snapshot of the window; 0 if not yet ranked
+	RankChange   int     `json:"rank_change"`   // places climbed (negative = fell); 0 unless ranked at both ends
+	PnL          float64 `json:"pnl"`
+	PnLChange    float64 `json:"pnl_change"` // between the trader's first and last point in the window
+	Points       []Point `json:"points"`     // oldest first
+}
+
+// Tracker periodically snapshots the leaderboard and keeps a rolling,
+// persisted history of ranks
+type Tracker struct {
+	data   *polymarket.DataClient
+	config *config.LeaderboardConfig
+
+	mu        sync.RWMutex
+	snapshots []Snapshot // oldest first
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a new tracker, restoring the history saved at cfg.Path
+func New(data *polymarket.DataClient, cfg *config.LeaderboardConfig) *Tracker {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	t := &Tracker{
+		data:   data,
+		config: cfg,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	if err := t.load(); err != nil {
+		log.Printf("Failed to load leaderboard history from %s: %v", cfg.Path, err)
+	}
+	return t
+}
+
+// Start polls immediately and then every Interval
+func (t *Tracker) Start() {
+	if !t.config.Enabled {
+		return
+	}
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+
+		ticker := time.NewTicker(t.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			if err := t.Poll(); err != nil {
+				log.Printf("Leaderboard poll failed: %v", err)
+			}
+
+			select {
+			case <-t.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops polling
+func (t *Tracker) Stop() {
+	t.cancel()
+	t.wg.Wait()
+}
+
+// Poll fetches the current leaderboard and records it
+func (t *Tracker) Poll() error {
+	data, err := t.data.GetLeaderboard(t.config.Size)
+	if err != nil {
+		return err
+	}
+	entries, err := ParseEntries(data)
+	if err != nil {
+		return err
+	}
+	t.Record(time.Now(), entries)
+	return nil
+}
+
+// Record adds a snapshot, drops those older than Retention and saves the
+// history (used by Poll, warm starts and tests)
+func (t *Tracker) Record(at time.Time, entries []Entry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.snapshots = append(t.snapshots, Snapshot{Time: at, Entries: entries})
+	sort.SliceStable(t.snapshots, func(i, j int) bool { return t.snapshots[i].Time.Before(t.snapshots[j].Time) })
+
+	cutoff := at.Add(-t.config.Retention)
+	drop := 0
+	for drop < len(t.snapshots) && t.snapshots[drop].Time.Before(cutoff) {
+		drop++
+	}
+	t.snapshots = t.snapshots[drop:]
+
+	t.saveLocked()
+}
+
+// Retention is how far back history is kept
+func (t *Tracker) Retention() time.Duration {
+	return t.config.Retention
+}
+
+// History returns the traders on the latest snapshot, by current rank, with
+// their trajectory over the window ending at that snapshot
+func (t *Tracker) History(window time.Duration, limit int) []TraderHistory {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	snaps := t.windowLocked(window)
+	if len(snaps) == 0 {
+		return []TraderHistory{}
+	}
+
+	latest := snaps[len(snaps)-1].Entries
+	out := make([]TraderHistory, 0, len(latest))
+	for _, e := range latest {
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+		out = append(out, trajectory(snaps, e.Address))
+	}
+	return out
+}
+
+// Trader returns one trader's trajectory over the window, whether or not
+// they are still ranked; false if they were not ranked at any point in it
+func (t *Tracker) Trader(address string, window time.Duration) (TraderHistory, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	h := trajectory(t.windowLocked(window), strings.ToLower(address))
+	return h, len(h.Points) > 0
+}
+
+// windowLocked returns the snapshots within window of the latest one;
+// caller holds t.mu
+func (t *Tracker) windowLocked(window time.Duration) []Snapshot {
+	if len(t.snapshots) == 0 {
+		return nil
+	}
+	cutoff := t.snapshots[len(t.snapshots)-1].Time.Add(-window)
+	i := sort.Search(len(t.snapshots), func(i int) bool { return !t.snapshots[i].Time.Before(cutoff) })
+	return t.snapshots[i:]
+}
+
+// trajectory builds a trader's history from a window of snapshots
+func trajectory(snaps []Snapshot, address string) TraderHistory {
+	h := TraderHistory{Address: address, Points: []Point{}}
+	for i, s := range snaps {
+		for _, e := range s.Entries {
+			if e.Address != address {
+				continue
+			}
+			if e.Name != "" {
+				h.Name = e.Name
+			}
+			h.Points = append(h.Points, Point{Time: s.Time, Rank: e.Rank, PnL: e.PnL, Volume: e.Volume})
+			if i == 0 {
+				h.PreviousRank = e.Rank
+			}
+			if i == len(snaps)-1 {
+				h.Rank = e.Rank
+			}
+			break
+		}
+	}
+
+	if n := len(h.Points); n > 0 {
+		h.PnL = h.Points[n-1].PnL
+		h.PnLChange = h.Points[n-1].PnL - h.Points[0].PnL
+	}
+	if h.Rank > 0 && h.PreviousRank > 0 {
+		h.RankChange = h.PreviousRank - h.Rank
+	}
+	return h
+}
+
+// ParseEntries reads a Data API leaderboard, accepting the field names the
+// upstream has used for address, name, PnL and volume. Entries without a
+// rank are ranked by position.
+func ParseEntries(data []byte) ([]Entry, error) {
+	var raw []map[string]interface{}
+	if err := sonic.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(raw))
+	for i, r := range raw {
+		address := strings.ToLower(firstString(r, "proxyWallet", "address", "user"))
+		if address == "" {
+			continue
+		}
+		rank := int(firstFloat(r, "rank"))
+		if rank <= 0 {
+			rank = i + 1
+		}
+		entries = append(entries, Entry{
+			Rank:    rank,
+			Address: address,
+			Name:    firstString(r, "userName", "name", "pseudonym"),
+			PnL:     firstFloat(r, "pnl", "amount", "profit"),
+			Volume:  firstFloat(r, "vol", "volume"),
+		})
+	}
+	return entries, nil
+}
+
+// firstString returns the first non-empty string among keys
+func firstString(r map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		if s, ok := r[k].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// firstFloat returns the first number, or numeric string, among keys
+func firstFloat(r map[string]interface{}, keys ...string) float64 {
+	for _, k := range keys {
+		switch v := r[k].(type) {
+		case float64:
+			return v
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,53 @@
+package leaderboard
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/fsutil"
+)
+
+// load restores the history saved at Path; a missing file is not an error
+func (t *Tracker) load() error {
+	if t.config.Path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(t.config.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var snapshots []Snapshot
+	if err := sonic.Unmarshal(data, &snapshots); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.snapshots = snapshots
+	return nil
+}
+
+// saveLocked writes the history to Path, replacing the file atomically.
+// Failures are logged; the in-memory history stays authoritative. Caller
+// holds t.mu.
+func (t *Tracker) saveLocked() {
+	if t.config.Path == "" {
+		return
+	}
+
+	data, err := sonic.Marshal(t.snapshots)
+	if err == nil {
+		err = fsutil.WriteFileAtomic(t.config.Path, data, 0o644)
+	}
+	if err != nil {
+		log.Printf("Failed to save leaderboard history to %s: %v", t.config.Path, err)
+	}
+}
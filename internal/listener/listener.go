@@ -0,0 +1,178 @@
+// Package listener opens the sockets the server accepts connections on
+// when server.listen is set: unix sockets, sockets passed by systemd
+// socket activation and TCP addresses, served together as one listener.
+package listener
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polygo/internal/config"
+)
+
+var (
+	// ErrInvalidSpec is wrapped by every server.listen parsing error
+	ErrInvalidSpec = errors.New("invalid server.listen")
+	// ErrNotActivated is returned for "systemd" when the process was not
+	// started by systemd socket activation
+	ErrNotActivated = errors.New("no sockets passed by systemd")
+)
+
+// Open opens every listener in cfg.Listen, a comma-separated list of
+// unix:///path/to.sock, tcp://host:port and systemd. A single listener is
+// returned as is; several are merged into one. On error, the listeners
+// already opened are closed.
+func Open(cfg *config.ServerConfig) (net.Listener, error) {
+	mode, err := strconv.ParseUint(cfg.SocketMode, 8, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%w: socket_mode %q is not an octal file mode", ErrInvalidSpec, cfg.SocketMode)
+	}
+
+	var listeners []net.Listener
+	closeAll := func() {
+		for _, ln := range listeners {
+			ln.Close()
+		}
+	}
+
+	for _, spec := range strings.Split(cfg.Listen, ",") {
+		spec = strings.TrimSpace(spec)
+		var opened []net.Listener
+		switch {
+		case spec == "systemd":
+			opened, err = systemd()
+		case strings.HasPrefix(spec, "unix://"):
+			var ln net.Listener
+			ln, err = unix(strings.TrimPrefix(spec, "unix://"), fs.FileMode(mode))
+			opened = []net.Listener{ln}
+		case strings.HasPrefix(spec, "tcp://"):
+			var ln net.Listener
+			ln, err = net.Listen("tcp", strings.TrimPrefix(spec, "tcp://"))
+			opened = []net.Listener{ln}
+		default:
+			err = fmt.Errorf("%w: %q is not unix:///path, tcp://host:port or systemd", ErrInvalidSpec, spec)
+		}
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		listeners = append(listeners, opened...)
+	}
+
+	if len(listeners) == 1 {
+		return listeners[0], nil
+	}
+	return merge(listeners), nil
+}
+
+// unix listens on a unix socket at path, replacing a socket left behind
+// by a previous run unless a server still answers on it
+func unix(path string, mode fs.FileMode) (net.Listener, error) {
+	if path == "" {
+		return nil, fmt.Errorf("%w: unix:// needs a socket path", ErrInvalidSpec)
+	}
+
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("listen on %s: file exists and is not a socket", path)
+		}
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("listen on %s: socket is in use", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+// accepted is a connection, or the error, from one of the merged listeners
+type accepted struct {
+	conn net.Conn
+	err  error
+}
+
+// multi accepts connections from several listeners
+type multi struct {
+	listeners []net.Listener
+	conns     chan accepted
+	done      chan struct{}
+	once      sync.Once
+}
+
+// merge serves several listeners as one. Accept errors other than closing
+// are passed on, so the server decides whether to keep serving.
+func merge(listeners []net.Listener) net.Listener {
+	m := &multi{
+		listeners: listeners,
+		conns:     make(chan accepted),
+		done:      make(chan struct{}),
+	}
+	for _, ln := range listeners {
+		go m.accept(ln)
+	}
+	return m
+}
+
+func (m *multi) accept(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		select {
+		case m.conns <- accepted{conn, err}:
+		case <-m.done:
+			if conn != nil {
+				conn.Close()
+			}
+			return
+		}
+	}
+}
+
+// Accept returns the next connection from any of the listeners
+func (m *multi) Accept() (net.Conn, error) {
+	select {
+	case a := <-m.conns:
+		return a.conn, a.err
+	case <-m.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close closes every listener, removing unix socket files
+func (m *multi) Close() error {
+	var err error
+	m.once.Do(func() {
+		close(m.done)
+		for _, ln := range m.listeners {
+			if e := ln.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+	})
+	return err
+}
+
+// Addr returns the address of the first listener
+func (m *multi) Addr() net.Addr {
+	return m.listeners[0].Addr()
+}
@@ -0,0 +1,108 @@
+package listings
+
+import (
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/webhooks"
+	"github.com/polygo/internal/wsbuffer"
+	"github.com/polygo/internal/wsfanout"
+	"github.com/polygo/internal/wsframe"
+)
+
+// EventMarketListed is the webhook event type for a newly listed market
+const EventMarketListed = "market.listed"
+
+// Listing is pushed to WebSocket subscribers and webhooks for each market
+// a catalog sync discovers
+type Listing struct {
+	Type   string         `json:"type"` // always "market_listed"
+	Market *catalog.Entry `json:"market"`
+}
+
+// client is a WebSocket subscriber, optionally limited to markets carrying
+// one of its tags
+type client struct {
+	tags map[string]bool
+}
+
+// Feed fans markets the catalog lists for the first time out to WebSocket
+// subscribers and webhooks
+type Feed struct {
+	webhooks *webhooks.Dispatcher
+	clients  *wsfanout.Fanout[*client]
+}
+
+// New creates a new listings feed
+func New(dispatcher *webhooks.Dispatcher) *Feed {
+	return &Feed{
+		webhooks: dispatcher,
+		clients:  wsfanout.New[*client](),
+	}
+}
+
+// Subscribe registers a client for listings of markets with any of the
+// given tag slugs, or of every market when none are given
+func (f *Feed) Subscribe(out *wsbuffer.Client, enc wsframe.Encoding, tags []string) {
+	filter := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			filter[t] = true
+		}
+	}
+
+	f.clients.Add(out, enc, &client{tags: filter})
+}
+
+// Unsubscribe removes a client
+func (f *Feed) Unsubscribe(out *wsbuffer.Client) {
+	f.clients.Remove(out)
+}
+
+// Stop closes every subscriber
+func (f *Feed) Stop() {
+	f.clients.Close()
+}
+
+// Publish announces newly listed markets; it is registered with
+// Catalog.OnListed
+func (f *Feed) Publish(entries []*catalog.Entry) {
+	for _, e := range entries {
+		f.publish(e)
+	}
+}
+
+// publish fans one listing out to matching WebSocket clients and webhooks
+func (f *Feed) publish(e *catalog.Entry) {
+	listing := Listing{Type: "market_listed", Market: e}
+	tags := make([]string, 0, len(e.Tags))
+	for _, t := range e.Tags {
+		tags = append(tags, strings.ToLower(t.Slug))
+	}
+
+	if f.webhooks != nil {
+		f.webhooks.PublishTagged(EventMarketListed, tags, listing)
+	}
+
+	data, err := sonic.Marshal(listing)
+	if err != nil {
+		return
+	}
+	f.clients.Publish(data, func(cl *client) bool {
+		return cl.matches(tags)
+	})
+}
+
+// matches reports whether a client wants a market with the given tags
+func (cl *client) matches(tags []string) bool {
+	if len(cl.tags) == 0 {
+		return true
+	}
+	for _, t := range tags {
+		if cl.tags[t] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,220 @@
+package mockupstream
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/bytedance/sonic"
+)
+
+// IDs of the canned markets
+const (
+	MarketID      = "mock-market-1"
+	ConditionID   = "0xmockcondition1"
+	TokenYes      = "mock-token-yes-1"
+	TokenNo       = "mock-token-no-1"
+	OtherMarketID = "mock-market-2"
+	EventID       = "mock-event-1"
+	OrderID       = "0xmockorder1"
+)
+
+// markets are the Gamma fixtures, shaped like the real API (token IDs and
+// outcomes are JSON-encoded strings)
+var markets = []map[string]interface{}{
+	{
+		"id":               MarketID,
+		"question":         "Will the mock market resolve YES?",
+		"conditionId":      ConditionID,
+		"slug":             "mock-market-1",
+		"endDate":          "2030-01-01T00:00:00Z",
+		"liquidity":        "25000",
+		"volume":           "150000",
+		"volume24hr":       12000.5,
+		"bestBid":          0.49,
+		"bestAsk":          0.51,
+		"active":           true,
+		"closed":           false,
+		"outcomes":         `["Yes", "No"]`,
+		"outcomePrices":    `["0.5", "0.5"]`,
+		"clobTokenIds":     `["` + TokenYes + `", "` + TokenNo + `"]`,
+		"acceptingOrders":  true,
+		"enableOrderBook":  true,
+		"rewardsMinSize":   50,
+		"rewardsMaxSpread": 3.5,
+		"clobRewards":      []map[string]interface{}{{"id": "1", "conditionId": ConditionID, "rewardsDailyRate": 20}},
+	},
+	{
+		"id":              OtherMarketID,
+		"question":        "Will the other mock market resolve YES?",
+		"conditionId":     "0xmockcondition2",
+		"slug":            "mock-market-2",
+		"endDate":         "2030-01-01T00:00:00Z",
+		"liquidity":       "1000",
+		"volume":          "5000",
+		"volume24hr":      300,
+		"active":          true,
+		"closed":          false,
+		"outcomes":        `["Yes", "No"]`,
+		"outcomePrices":   `["0.2", "0.8"]`,
+		"clobTokenIds":    `["mock-token-yes-2", "mock-token-no-2"]`,
+		"acceptingOrders": true,
+		"enableOrderBook": true,
+	},
+}
+
+// fixture answers a request no scripted route matched
+func fixture(u Upstream, r *http.Request) (int, []byte) {
+	var v interface{}
+	switch u {
+	case CLOB:
+		v = clobFixture(r)
+	case Gamma:
+		v = gammaFixture(r)
+	case Data:
+		// Every Data API listing is empty by default
+		v = []interface{}{}
+	}
+	if v == nil {
+		return http.StatusNotFound, []byte(`{"error":"not found"}`)
+	}
+
+	data, err := sonic.Marshal(v)
+	if err != nil {
+		return http.StatusInternalServerError, []byte(`{"error":"` + err.Error() + `"}`)
+	}
+	return http.StatusOK, data
+}
+
+func clobFixture(r *http.Request) interface{} {
+	q := r.URL.Query()
+	path := r.URL.Path
+
+	switch {
+	case path == "/book":
+		return book(q.Get("token_id"))
+	case path == "/books":
+		var books []interface{}
+		for _, id := range strings.Split(q.Get("token_ids"), ",") {
+			books = append(books, book(id))
+		}
+		return books
+	case path == "/midpoint":
+		return map[string]string{"mid": "0.5"}
+	case path == "/midpoints":
+		mids := make(map[string]string)
+		for _, id := range strings.Split(q.Get("token_ids"), ",") {
+			mids[id] = "0.5"
+		}
+		return mids
+	case path == "/price":
+		return map[string]string{"price": "0.5"}
+	case path == "/spread":
+		return map[string]string{"spread": "0.02"}
+	case path == "/last-trade-price":
+		return map[string]string{"price": "0.5", "side": "BUY"}
+	case path == "/tick-size":
+		return map[string]float64{"minimum_tick_size": 0.01}
+	case path == "/neg-risk":
+		return map[string]bool{"neg_risk": false}
+	case path == "/fee-rate":
+		return map[string]int{"base_fee": 0}
+	case path == "/balance-allowance":
+		// 100 USDC (or shares), approved for the CTF exchange without limit
+		return map[string]interface{}{
+			"balance":    "100000000",
+			"allowances": map[string]string{"0x4bFb41d5B3570DeFd03C39a9A4D8dE6Bd8B8982E": "115792089237316195423570985008687907853269984665640564039457584007913129639935"},
+		}
+	case path == "/prices-history":
+		return map[string]interface{}{"history": []interface{}{}}
+	case path == "/order" && r.Method == http.MethodPost:
+		return map[string]interface{}{"success": true, "errorMsg": "", "orderID": OrderID, "status": "live"}
+	case strings.HasPrefix(path, "/order/") && r.Method == http.MethodDelete:
+		return map[string]interface{}{"canceled": []string{strings.TrimPrefix(path, "/order/")}, "not_canceled": map[string]string{}}
+	case strings.HasPrefix(path, "/order/"):
+		return order(strings.TrimPrefix(path, "/order/"))
+	case path == "/orders" && r.Method == http.MethodDelete, path == "/cancel-all":
+		return map[string]interface{}{"canceled": []string{}, "not_canceled": map[string]string{}}
+	case path == "/orders", path == "/orders/open", path == "/trades":
+		return []interface{}{}
+	}
+	return nil
+}
+
+func gammaFixture(r *http.Request) interface{} {
+	q := r.URL.Query()
+	path := r.URL.Path
+
+	switch {
+	case path == "/markets":
+		out := []map[string]interface{}{}
+		for _, m := range markets {
+			if matches(m, "slug", q.Get("slug")) && matches(m, "conditionId", q.Get("condition_id")) &&
+				(q.Get("clob_token_id") == "" || strings.Contains(m["clobTokenIds"].(string), `"`+q.Get("clob_token_id")+`"`)) {
+				out = append(out, m)
+			}
+		}
+		return out
+	case strings.HasPrefix(path, "/markets/"):
+		id := strings.TrimPrefix(path, "/markets/")
+		for _, m := range markets {
+			if m["id"] == id {
+				return m
+			}
+		}
+		// Gamma answers unknown IDs with null
+		return nullJSON{}
+	case path == "/events":
+		return []interface{}{event()}
+	case strings.HasPrefix(path, "/events/"):
+		if strings.TrimPrefix(path, "/events/") == EventID {
+			return event()
+		}
+		return nullJSON{}
+	}
+	return nil
+}
+
+// nullJSON marshals to null
+type nullJSON struct{}
+
+func (nullJSON) MarshalJSON() ([]byte, error) { return []byte("null"), nil }
+
+func matches(m map[string]interface{}, field, want string) bool {
+	return want == "" || m[field] == want
+}
+
+func book(tokenID string) map[string]interface{} {
+	return map[string]interface{}{
+		"market":    ConditionID,
+		"asset_id":  tokenID,
+		"hash":      "0xmockhash",
+		"timestamp": "1700000000000",
+		"bids":      []map[string]string{{"price": "0.48", "size": "200"}, {"price": "0.49", "size": "100"}},
+		"asks":      []map[string]string{{"price": "0.52", "size": "200"}, {"price": "0.51", "size": "100"}},
+	}
+}
+
+func order(id string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":            id,
+		"status":        "LIVE",
+		"market":        ConditionID,
+		"asset_id":      TokenYes,
+		"side":          "BUY",
+		"original_size": "10",
+		"size_matched":  "0",
+		"price":         "0.5",
+		"order_type":    "GTC",
+	}
+}
+
+func event() map[string]interface{} {
+	return map[string]interface{}{
+		"id":      EventID,
+		"slug":    "mock-event-1",
+		"title":   "Mock event",
+		"active":  true,
+		"closed":  false,
+		"markets": markets,
+	}
+}
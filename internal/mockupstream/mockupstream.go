@@ -0,0 +1,392 @@
+// Package mockupstream runs fake Polymarket CLOB, Gamma and Data APIs and a
+// CLOB market WebSocket for tests. Every endpoint PolyGo calls answers with
+// canned fixtures; tests override individual routes, inject failures and
+// push WebSocket messages. The same WebSocket serves the user channel,
+// whose clients are told apart by the API key they subscribe with.
+//
+//	mock := mockupstream.New()
+//	defer mock.Close()
+//	mock.Apply(&cfg.Polymarket)
+//	mock.On(mockupstream.CLOB, "GET", "/book", 503, `{"error":"down"}`).Times(2)
+package mockupstream
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gorilla/websocket"
+	"github.com/polygo/internal/config"
+)
+
+// Upstream identifies one of the fake APIs
+type Upstream string
+
+const (
+	CLOB  Upstream = "clob"
+	Gamma Upstream = "gamma"
+	Data  Upstream = "data"
+)
+
+// Request is a recorded upstream call
+type Request struct {
+	Upstream Upstream
+	Method   string
+	Path     string
+	Query    string
+	Header   http.Header
+	Body     []byte
+}
+
+// Route is a scripted response. Paths ending in "*" match by prefix.
+type Route struct {
+	upstream Upstream
+	method   string
+	path     string
+	status   int
+	body     []byte
+	header   map[string]string
+	delay    time.Duration
+	handler  http.HandlerFunc
+	times    int // remaining uses; 0 means unlimited
+	used     int
+}
+
+// Times limits the route to n uses, after which earlier routes and the
+// fixtures answer again
+func (r *Route) Times(n int) *Route {
+	r.times = n
+	return r
+}
+
+// Delay holds the response back, e.g. to trigger client timeouts
+func (r *Route) Delay(d time.Duration) *Route {
+	r.delay = d
+	return r
+}
+
+// Header adds a response header
+func (r *Route) Header(key, value string) *Route {
+	if r.header == nil {
+		r.header = make(map[string]string)
+	}
+	r.header[key] = value
+	return r
+}
+
+// matches reports whether the route answers method and path
+func (r *Route) matches(method, path string) bool {
+	if r.method != "" && r.method != method {
+		return false
+	}
+	if strings.HasSuffix(r.path, "*") {
+		return strings.HasPrefix(path, strings.TrimSuffix(r.path, "*"))
+	}
+	return r.path == path
+}
+
+// Server is a set of fake upstreams
+type Server struct {
+	servers map[Upstream]*httptest.Server
+	ws      *httptest.Server
+
+	mu       sync.Mutex
+	routes   []*Route
+	requests []Request
+
+	wsMu    sync.Mutex
+	wsConns map[*websocket.Conn]*sync.Mutex
+	subs    map[string]bool
+	users   map[*websocket.Conn]string // user channel clients and their API key
+}
+
+// New starts the fake upstreams
+func New() *Server {
+	s := &Server{
+		servers: make(map[Upstream]*httptest.Server),
+		wsConns: make(map[*websocket.Conn]*sync.Mutex),
+		subs:    make(map[string]bool),
+		users:   make(map[*websocket.Conn]string),
+	}
+	for _, u := range []Upstream{CLOB, Gamma, Data} {
+		u := u
+		s.servers[u] = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s.serve(u, w, r)
+		}))
+	}
+	s.ws = httptest.NewServer(http.HandlerFunc(s.serveWS))
+	return s
+}
+
+// Close shuts every fake upstream down
+func (s *Server) Close() {
+	s.wsMu.Lock()
+	for conn := range s.wsConns {
+		conn.Close()
+	}
+	s.wsMu.Unlock()
+
+	s.ws.Close()
+	for _, srv := range s.servers {
+		srv.Close()
+	}
+}
+
+// URL returns the base URL of an upstream
+func (s *Server) URL(u Upstream) string {
+	return s.servers[u].URL
+}
+
+// WSURL returns the CLOB WebSocket URL
+func (s *Server) WSURL() string {
+	return "ws" + strings.TrimPrefix(s.ws.URL, "http") + "/ws/"
+}
+
+// Apply points a Polymarket config at the fake upstreams and disables
+// retry backoff so failure tests stay fast
+func (s *Server) Apply(cfg *config.PolymarketConfig) {
+	cfg.ClobBaseURL = s.URL(CLOB)
+	cfg.GammaBaseURL = s.URL(Gamma)
+	cfg.DataBaseURL = s.URL(Data)
+	cfg.WsClobURL = s.WSURL()
+	cfg.WsLiveDataURL = s.WSURL()
+	cfg.WsUserURL = s.WSURL() + "user"
+	cfg.RetryWaitTime = time.Millisecond
+	cfg.RetryMaxWait = 10 * time.Millisecond
+}
+
+// On scripts a canned response. Later routes take precedence.
+func (s *Server) On(u Upstream, method, path string, status int, body string) *Route {
+	return s.add(&Route{upstream: u, method: method, path: path, status: status, body: []byte(body)})
+}
+
+// Handle scripts a route with a custom handler
+func (s *Server) Handle(u Upstream, method, path string, h http.HandlerFunc) *Route {
+	return s.add(&Route{upstream: u, method: method, path: path, handler: h})
+}
+
+func (s *Server) add(r *Route) *Route {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes = append(s.routes, r)
+	return r
+}
+
+// Reset drops scripted routes and recorded requests
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes = nil
+	s.requests = nil
+}
+
+// Requests returns the calls an upstream received, oldest first
+func (s *Server) Requests(u Upstream) []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []Request
+	for _, r := range s.requests {
+		if r.Upstream == u {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// serve answers an HTTP request from the scripted routes or the fixtures
+func (s *Server) serve(u Upstream, w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Upstream: u,
+		Method:   r.Method,
+		Path:     r.URL.Path,
+		Query:    r.URL.RawQuery,
+		Header:   r.Header.Clone(),
+		Body:     body,
+	})
+	var route *Route
+	for i := len(s.routes) - 1; i >= 0; i-- {
+		rt := s.routes[i]
+		if rt.upstream != u || !rt.matches(r.Method, r.URL.Path) || (rt.times > 0 && rt.used >= rt.times) {
+			continue
+		}
+		rt.used++
+		route = rt
+		break
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if route == nil {
+		status, data := fixture(u, r)
+		w.WriteHeader(status)
+		w.Write(data)
+		return
+	}
+
+	if route.delay > 0 {
+		select {
+		case <-time.After(route.delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	for k, v := range route.header {
+		w.Header().Set(k, v)
+	}
+	if route.handler != nil {
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+		route.handler(w, r)
+		return
+	}
+	status := route.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write(route.body)
+}
+
+// serveWS accepts a WebSocket client and records its subscriptions
+func (s *Server) serveWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	writeMu := &sync.Mutex{}
+	s.wsMu.Lock()
+	s.wsConns[conn] = writeMu
+	s.wsMu.Unlock()
+
+	defer func() {
+		s.wsMu.Lock()
+		delete(s.wsConns, conn)
+		delete(s.users, conn)
+		s.wsMu.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		s.handleWSMessage(conn, writeMu, data)
+	}
+}
+
+// Push sends a message to every connected WebSocket client
+func (s *Server) Push(data []byte) {
+	s.wsMu.Lock()
+	defer s.wsMu.Unlock()
+
+	for conn, writeMu := range s.wsConns {
+		writeMu.Lock()
+		conn.WriteMessage(websocket.TextMessage, data)
+		writeMu.Unlock()
+	}
+}
+
+// PushUser sends a message to the user channel clients subscribed with
+// apiKey
+func (s *Server) PushUser(apiKey string, data []byte) {
+	s.wsMu.Lock()
+	defer s.wsMu.Unlock()
+
+	for conn, key := range s.users {
+		if key != apiKey {
+			continue
+		}
+		writeMu := s.wsConns[conn]
+		writeMu.Lock()
+		conn.WriteMessage(websocket.TextMessage, data)
+		writeMu.Unlock()
+	}
+}
+
+// UserSubscriptions returns the API keys of connected user channel
+// clients, once per connection
+func (s *Server) UserSubscriptions() []string {
+	s.wsMu.Lock()
+	defer s.wsMu.Unlock()
+
+	out := make([]string, 0, len(s.users))
+	for _, key := range s.users {
+		out = append(out, key)
+	}
+	return out
+}
+
+// Subscriptions returns the markets clients are currently subscribed to
+func (s *Server) Subscriptions() []string {
+	s.wsMu.Lock()
+	defer s.wsMu.Unlock()
+
+	out := make([]string, 0, len(s.subs))
+	for m := range s.subs {
+		out = append(out, m)
+	}
+	return out
+}
+
+// WSConnections returns the number of connected WebSocket clients
+func (s *Server) WSConnections() int {
+	s.wsMu.Lock()
+	defer s.wsMu.Unlock()
+	return len(s.wsConns)
+}
+
+// DropWS closes every WebSocket connection, as an upstream restart would
+func (s *Server) DropWS() {
+	s.wsMu.Lock()
+	defer s.wsMu.Unlock()
+	for conn := range s.wsConns {
+		conn.Close()
+	}
+}
+
+// handleWSMessage tracks subscriptions and answers JSON pings
+func (s *Server) handleWSMessage(conn *websocket.Conn, writeMu *sync.Mutex, data []byte) {
+	var msg struct {
+		Type    string   `json:"type"`
+		Markets []string `json:"markets"`
+		Assets  []string `json:"assets_ids"`
+		Auth    struct {
+			APIKey string `json:"apiKey"`
+		} `json:"auth"`
+	}
+	if err := sonic.Unmarshal(data, &msg); err != nil {
+		return
+	}
+
+	switch strings.ToLower(msg.Type) {
+	case "subscribe", "market":
+		s.wsMu.Lock()
+		for _, m := range append(msg.Markets, msg.Assets...) {
+			s.subs[m] = true
+		}
+		s.wsMu.Unlock()
+	case "user":
+		s.wsMu.Lock()
+		s.users[conn] = msg.Auth.APIKey
+		s.wsMu.Unlock()
+	case "unsubscribe":
+		s.wsMu.Lock()
+		for _, m := range append(msg.Markets, msg.Assets...) {
+			delete(s.subs, m)
+		}
+		s.wsMu.Unlock()
+	case "ping":
+		writeMu.Lock()
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"pong"}`))
+		writeMu.Unlock()
+	}
+}
@@ -10,15 +10,20 @@ type Market struct {
 	ConditionID         string    `json:"conditionId"`
 	Slug                string    `json:"slug"`
 	EndDate             time.Time `json:"endDate"`
-	Liquidity           string    `json:"liquidity"`
-	Volume              string    `json:"volume"`
-	Volume24hr          string    `json:"volume24hr"`
+	CreatedAt           time.Time `json:"createdAt,omitempty"`
+	Liquidity           FlexString `json:"liquidity"`
+	Volume              FlexString `json:"volume"`
+	Volume24hr          FlexString `json:"volume24hr"`
+	Volume1wk           FlexString `json:"volume1wk,omitempty"`
+	Spread              FlexString `json:"spread,omitempty"`
+	BestBid             FlexString `json:"bestBid,omitempty"`
+	BestAsk             FlexString `json:"bestAsk,omitempty"`
 	Active              bool      `json:"active"`
 	Closed              bool      `json:"closed"`
 	MarketType          string    `json:"marketType"`
-	OutcomePrices       []string  `json:"outcomePrices"`
-	Outcomes            []string  `json:"outcomes"`
-	ClobTokenIDs        []string  `json:"clobTokenIds"`
+	OutcomePrices       StringList `json:"outcomePrices"`
+	Outcomes            StringList `json:"outcomes"`
+	ClobTokenIDs        StringList `json:"clobTokenIds"`
 	AcceptingOrders     bool      `json:"acceptingOrders"`
 	AcceptingOrdersTS   time.Time `json:"acceptingOrdersTimestamp"`
 	EnableOrderBook     bool      `json:"enableOrderBook"`
@@ -27,10 +32,24 @@ type Market struct {
 	NegRiskRequestID    string    `json:"negRiskRequestId,omitempty"`
 	Icon                string    `json:"icon,omitempty"`
 	Image               string    `json:"image,omitempty"`
+	OrderMinSize        float64   `json:"orderMinSize,omitempty"`
+	OrderPriceMinTickSize float64 `json:"orderPriceMinTickSize,omitempty"`
 	RewardsMinSize      float64   `json:"rewardsMinSize,omitempty"`
 	RewardsMaxSpread    float64   `json:"rewardsMaxSpread,omitempty"`
 	SpreadMultiplierMin float64   `json:"spreadMultiplierMin,omitempty"`
 	SpreadMultiplierMax float64   `json:"spreadMultiplierMax,omitempty"`
+	ClobRewards         []ClobReward `json:"clobRewards,omitempty"`
+}
+
+// ClobReward is a liquidity reward program running on a market
+type ClobReward struct {
+	ID               string  `json:"id"`
+	ConditionID      string  `json:"conditionId"`
+	AssetAddress     string  `json:"assetAddress"`
+	RewardsAmount    float64 `json:"rewardsAmount"`
+	RewardsDailyRate float64 `json:"rewardsDailyRate"` // paid across the market's makers per day
+	StartDate        string  `json:"startDate"`
+	EndDate          string  `json:"endDate"`
 }
 
 // MarketsResponse represents the API response for markets list
@@ -44,9 +63,22 @@ type MarketsResponse struct {
 type MarketQueryParams struct {
 	Limit      int    `query:"limit"`
 	Cursor     string `query:"cursor"`
+	Offset     int    `query:"offset"`
 	Active     *bool  `query:"active"`
 	Closed     *bool  `query:"closed"`
 	Slug       string `query:"slug"`
 	EventSlug  string `query:"event_slug"`
 	ClobTokenID string `query:"clob_token_id"`
+	Order      string `query:"order"`     // sort field, e.g. volume24hr
+	Ascending  *bool  `query:"ascending"`
+	MinVolume     float64   `query:"min_volume"`
+	MinLiquidity  float64   `query:"min_liquidity"`
+	EndDateBefore time.Time `query:"end_date_before"`
+	EndDateAfter  time.Time `query:"end_date_after"`
+}
+
+// Conditional reports whether params filter on volume, liquidity or end
+// date, which the local catalog applies better than Gamma
+func (p *MarketQueryParams) Conditional() bool {
+	return p.MinVolume > 0 || p.MinLiquidity > 0 || !p.EndDateBefore.IsZero() || !p.EndDateAfter.IsZero()
 }
@@ -79,12 +79,29 @@ type Spread struct {
 
 // CreateOrderRequest represents a request to create an order
 type CreateOrderRequest struct {
-	TokenID    string    `json:"tokenID" validate:"required"`
-	Side       Side      `json:"side" validate:"required"`
-	Price      string    `json:"price" validate:"required"`
-	Size       string    `json:"size" validate:"required"`
-	Type       OrderType `json:"type"`
-	Expiration int64     `json:"expiration,omitempty"`
+	TokenID    string    `json:"tokenID" validate:"required,max=100,market_id"`
+	Side       Side      `json:"side" validate:"required,oneof=BUY SELL"`
+	Price      string    `json:"price" validate:"required,numeric"` // may be omitted with price_mode, which sets it
+	Size       string    `json:"size" validate:"required,numeric"`
+	Type       OrderType `json:"type" validate:"omitempty,oneof=GTC FOK GTD"`
+	Expiration int64     `json:"expiration,omitempty" validate:"gte=0"`
+	Maker      string    `json:"maker,omitempty" validate:"omitempty,eth_addr"` // wallet address; enables cached user-data invalidation on fills
+	Strategy   string    `json:"strategy,omitempty" validate:"omitempty,max=64"` // attributes fills to a strategy in /analytics/strategies; not sent upstream
+	PriceMode  string    `json:"price_mode,omitempty" validate:"omitempty,max=32"` // price relative to the live book, e.g. best_bid+1tick; resolved before sending
+}
+
+// PlacedOrder is the CLOB's reply to an order, or to one order of a batch
+type PlacedOrder struct {
+	Success  *bool  `json:"success"` // not always sent
+	ErrorMsg string `json:"errorMsg"`
+	OrderID  string `json:"orderID"`
+	Status   string `json:"status"`
+}
+
+// CancelResult is the CLOB's reply to a cancellation
+type CancelResult struct {
+	Canceled    []string          `json:"canceled"`
+	NotCanceled map[string]string `json:"not_canceled"` // order ID -> reason
 }
 
 // OrdersResponse represents orders list response
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// StringList is a list of strings that also accepts the JSON-encoded string
+// form Gamma uses for outcomes, outcomePrices and clobTokenIds
+// (e.g. "[\"Yes\", \"No\"]"). It always marshals as a plain JSON array.
+type StringList []string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*l = nil
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var inner string
+		if err := json.Unmarshal(data, &inner); err != nil {
+			return err
+		}
+		if inner == "" {
+			*l = nil
+			return nil
+		}
+		data = []byte(inner)
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// Floats parses every element as a float, using 0 for unparsable entries
+func (l StringList) Floats() []float64 {
+	out := make([]float64, len(l))
+	for i, s := range l {
+		out[i], _ = strconv.ParseFloat(s, 64)
+	}
+	return out
+}
+
+// FlexString holds a value upstream sends either as a JSON string or a
+// JSON number (volume and liquidity fields vary between endpoints)
+type FlexString string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (f *FlexString) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*f = ""
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*f = FlexString(s)
+		return nil
+	}
+
+	*f = FlexString(data)
+	return nil
+}
+
+// Float parses the value as a float, returning 0 when empty or invalid
+func (f FlexString) Float() float64 {
+	v, _ := strconv.ParseFloat(string(f), 64)
+	return v
+}
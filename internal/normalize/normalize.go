@@ -0,0 +1,140 @@
+// Package normalize rewrites Gamma payloads into a consistent shape:
+// JSON-encoded lists stored in strings (outcomes, outcomePrices,
+// clobTokenIds) become arrays, numeric strings become numbers and
+// snake_case keys become camelCase. Markets nested in events are
+// normalized too.
+package normalize
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+)
+
+// api keeps numbers exact and output deterministic
+var api = sonic.Config{UseNumber: true, SortMapKeys: true}.Froze()
+
+// listFields hold a JSON array encoded as a string
+var listFields = map[string]bool{
+	"outcomes":              true,
+	"outcomePrices":         true,
+	"clobTokenIds":          true,
+	"umaResolutionStatuses": true,
+}
+
+// numericFields are sent as numbers, whether upstream used a string or not
+var numericFields = map[string]bool{
+	"outcomePrices":         true, // each element
+	"liquidity":             true,
+	"liquidityNum":          true,
+	"liquidityClob":         true,
+	"liquidityAmm":          true,
+	"volume":                true,
+	"volumeNum":             true,
+	"volumeClob":            true,
+	"volumeAmm":             true,
+	"volume24hr":            true,
+	"volume24hrClob":        true,
+	"volume1wk":             true,
+	"volume1mo":             true,
+	"volume1yr":             true,
+	"openInterest":          true,
+	"competitive":           true,
+	"spread":                true,
+	"bestBid":               true,
+	"bestAsk":               true,
+	"lastTradePrice":        true,
+	"oneDayPriceChange":     true,
+	"oneWeekPriceChange":    true,
+	"oneMonthPriceChange":   true,
+	"orderPriceMinTickSize": true,
+	"orderMinSize":          true,
+	"rewardsMinSize":        true,
+	"rewardsMaxSpread":      true,
+}
+
+// JSON normalizes an encoded market, event or list of either
+func JSON(data []byte) ([]byte, error) {
+	var v interface{}
+	if err := api.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return api.Marshal(Value(v))
+}
+
+// Value normalizes a decoded JSON value
+func Value(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for key, val := range t {
+			key = camelCase(key)
+			if s, ok := val.(string); ok && listFields[key] {
+				val = parseList(s)
+			}
+			val = Value(val)
+			if numericFields[key] {
+				val = toNumber(val)
+			}
+			out[key] = val
+		}
+		return out
+	case []interface{}:
+		for i := range t {
+			t[i] = Value(t[i])
+		}
+		return t
+	}
+	return v
+}
+
+// parseList decodes a stringified JSON array, leaving other strings as-is
+func parseList(s string) interface{} {
+	if !strings.HasPrefix(strings.TrimSpace(s), "[") {
+		return s
+	}
+	var list []interface{}
+	if err := api.UnmarshalFromString(s, &list); err != nil {
+		return s
+	}
+	return list
+}
+
+// toNumber converts numeric strings, including inside arrays
+func toNumber(v interface{}) interface{} {
+	switch t := v.(type) {
+	case string:
+		if _, err := strconv.ParseFloat(t, 64); err == nil {
+			return json.Number(t)
+		}
+	case []interface{}:
+		for i := range t {
+			t[i] = toNumber(t[i])
+		}
+	}
+	return v
+}
+
+// camelCase converts snake_case keys; camelCase keys are unchanged
+func camelCase(key string) string {
+	if !strings.Contains(key, "_") {
+		return key
+	}
+
+	var b strings.Builder
+	upper := false
+	for _, r := range key {
+		switch {
+		case r == '_':
+			upper = b.Len() > 0
+		case upper:
+			b.WriteString(strings.ToUpper(string(r)))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,101 @@
+// Package odds converts CLOB prices, which are probabilities between 0 and
+// 1, into other quoting conventions.
+package odds
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Format is a way of quoting a price
+type Format string
+
+const (
+	Probability Format = "probability" // 0 to 1, as quoted by the CLOB
+	Cents       Format = "cents"       // 0 to 100
+	American    Format = "american"    // moneyline odds, e.g. -150 or +200
+)
+
+// MaxDisplaySpread is the widest spread at which a book's implied
+// probability is its midpoint; wider books use the last trade price, as
+// Polymarket displays them
+const MaxDisplaySpread = 0.10
+
+// ParseFormat parses a format name; empty means Probability
+func ParseFormat(s string) (Format, bool) {
+	switch f := Format(strings.ToLower(s)); f {
+	case "", Probability:
+		return Probability, true
+	case Cents, American:
+		return f, true
+	}
+	return "", false
+}
+
+// Convert converts probability p to format f. American odds cannot express
+// a probability of 0 or 1.
+func Convert(p float64, f Format) (float64, bool) {
+	switch f {
+	case Cents:
+		return p * 100, true
+	case American:
+		if p <= 0 || p >= 1 {
+			return 0, false
+		}
+		if p >= 0.5 {
+			return -100 * p / (1 - p), true
+		}
+		return 100 * (1 - p) / p, true
+	}
+	return p, true
+}
+
+// Quote converts p to format f and renders it the way the format is
+// written: american odds are whole numbers with an explicit sign
+func Quote(p float64, f Format) (string, bool) {
+	v, ok := Convert(p, f)
+	if !ok {
+		return "", false
+	}
+	if f == American {
+		s := strconv.FormatFloat(math.Round(v), 'f', 0, 64)
+		if v > 0 {
+			s = "+" + s
+		}
+		return s, true
+	}
+	// Trim the float noise of the conversion (0.57*100 = 56.99999999999999)
+	return strconv.FormatFloat(round(v, 10), 'f', -1, 64), true
+}
+
+// RoundToTick rounds p to the nearest multiple of tick, keeping it within
+// [0, 1]. A tick of 0 leaves p unchanged.
+func RoundToTick(p, tick float64) float64 {
+	if tick <= 0 {
+		return p
+	}
+	decimals := int(math.Ceil(-math.Log10(tick)))
+	if decimals < 0 {
+		decimals = 0
+	}
+	return math.Min(1, math.Max(0, round(math.Round(p/tick)*tick, decimals)))
+}
+
+// Implied returns the implied probability of a book from its best bid, best
+// ask and last trade price, any of which may be 0 when unknown
+func Implied(bid, ask, last float64) (float64, bool) {
+	if bid > 0 && ask > 0 && (round(ask-bid, 10) <= MaxDisplaySpread || last <= 0) {
+		return round((bid+ask)/2, 10), true
+	}
+	if last > 0 {
+		return last, true
+	}
+	return 0, false
+}
+
+// round rounds v to the given number of decimals
+func round(v float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(v*scale) / scale
+}
@@ -0,0 +1,169 @@
+// Package orderrules checks orders against the CLOB's tick size, minimum
+// size and price bounds before they are sent, so mistakes are rejected
+// with an explanation instead of an opaque upstream 400
+package orderrules
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+)
+
+// Rejection codes
+const (
+	CodeTickSize      = "ORDER_TICK_SIZE"
+	CodePriceRange    = "ORDER_PRICE_OUT_OF_RANGE"
+	CodeMinSize       = "ORDER_MIN_SIZE"
+	CodeSizePrecision = "ORDER_SIZE_PRECISION"
+)
+
+// epsilon absorbs float noise when testing for multiples of the tick
+const epsilon = 1e-9
+
+// Error is an order that breaks a market rule
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Order   int    `json:"order"` // index of the offending order in a batch
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Rules are the constraints orders on one token must meet. Zero values are
+// unknown and not checked.
+type Rules struct {
+	TickSize float64 `json:"tick_size"`
+	MinSize  float64 `json:"min_size"` // shares
+}
+
+// Checker validates orders against each token's rules. Tick sizes come
+// from the CLOB (cached); minimum sizes from the token's market, or the
+// configured fallback. A rule that cannot be looked up is not enforced:
+// the CLOB still checks it.
+type Checker struct {
+	clob     *polymarket.ClobClient
+	resolver *catalog.Resolver
+	config   *config.OrderRulesConfig
+}
+
+// New creates a new order rules checker
+func New(clob *polymarket.ClobClient, resolver *catalog.Resolver, cfg *config.OrderRulesConfig) *Checker {
+	return &Checker{clob: clob, resolver: resolver, config: cfg}
+}
+
+// Enabled reports whether orders are checked at all
+func (k *Checker) Enabled() bool {
+	return k.config.Enabled
+}
+
+// Rules returns the rules that apply to a token
+func (k *Checker) Rules(tokenID string) Rules {
+	rules := Rules{TickSize: k.tickSize(tokenID), MinSize: k.config.MinSize}
+	if k.resolver != nil {
+		if info, err := k.resolver.Resolve(tokenID); err == nil {
+			if info.MinOrderSize > 0 {
+				rules.MinSize = info.MinOrderSize
+			}
+			if rules.TickSize == 0 {
+				rules.TickSize = info.TickSize
+			}
+		}
+	}
+	return rules
+}
+
+// Check returns an *Error for the first order that breaks its token's
+// rules. Orders must already carry numeric prices and sizes.
+func (k *Checker) Check(orders []models.CreateOrderRequest) error {
+	rules := make(map[string]Rules)
+	for i := range orders {
+		o := &orders[i]
+		r, ok := rules[o.TokenID]
+		if !ok {
+			r = k.Rules(o.TokenID)
+			rules[o.TokenID] = r
+		}
+		if err := k.check(o, r); err != nil {
+			err.Order = i
+			return err
+		}
+	}
+	return nil
+}
+
+// check applies one token's rules to an order
+func (k *Checker) check(o *models.CreateOrderRequest, r Rules) *Error {
+	price, err := strconv.ParseFloat(o.Price, 64)
+	if err != nil {
+		return nil
+	}
+	size, err := strconv.ParseFloat(o.Size, 64)
+	if err != nil {
+		return nil
+	}
+
+	if tick := r.TickSize; tick > 0 {
+		lowest, highest := tick, 1-tick
+		if price < lowest-epsilon || price > highest+epsilon {
+			return &Error{Code: CodePriceRange, Message: fmt.Sprintf(
+				"price %s is outside %s-%s for tick %s", o.Price, format(lowest), format(highest), format(tick))}
+		}
+		if steps := price / tick; math.Abs(steps-math.Round(steps)) > epsilon*math.Max(1, steps) {
+			below := math.Floor(steps) * tick
+			return &Error{Code: CodeTickSize, Message: fmt.Sprintf(
+				"price %s is not a multiple of tick %s; use %s or %s", o.Price, format(tick),
+				format(math.Max(below, lowest)), format(math.Min(below+tick, highest)))}
+		}
+	} else if price <= 0 || price >= 1 {
+		return &Error{Code: CodePriceRange, Message: fmt.Sprintf("price %s must be between 0 and 1", o.Price)}
+	}
+
+	if d := k.config.SizeDecimals; d >= 0 && decimals(o.Size) > d {
+		return &Error{Code: CodeSizePrecision, Message: fmt.Sprintf(
+			"size %s has more than %d decimals; use %s", o.Size, d, strconv.FormatFloat(math.Floor(size*math.Pow10(d))/math.Pow10(d), 'f', -1, 64))}
+	}
+	if r.MinSize > 0 && size < r.MinSize {
+		return &Error{Code: CodeMinSize, Message: fmt.Sprintf(
+			"size %s is below the market minimum of %s shares", o.Size, format(r.MinSize))}
+	}
+	return nil
+}
+
+// tickSize returns a token's tick size from the CLOB, or 0 if unknown
+func (k *Checker) tickSize(tokenID string) float64 {
+	data, _, err := k.clob.GetTickSize(tokenID)
+	if err != nil {
+		return 0
+	}
+	var resp struct {
+		MinimumTickSize models.FlexString `json:"minimum_tick_size"`
+	}
+	if err := sonic.Unmarshal(data, &resp); err != nil {
+		return 0
+	}
+	return resp.MinimumTickSize.Float()
+}
+
+// decimals counts the digits after a decimal string's point, ignoring
+// trailing zeros
+func decimals(s string) int {
+	_, frac, ok := strings.Cut(s, ".")
+	if !ok {
+		return 0
+	}
+	return len(strings.TrimRight(frac, "0"))
+}
+
+// format prints a price or size without float noise
+func format(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1e6)/1e6, 'f', -1, 64)
+}
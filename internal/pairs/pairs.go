@@ -0,0 +1,300 @@
+// Package pairs places two coordinated orders (e.g. a YES/NO straddle or
+// a cross-market spread) and tracks them as a single entity
+package pairs
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/idgen"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+)
+
+// maxPairsPerOwner bounds the pairs kept per owner; the oldest are dropped
+const maxPairsPerOwner = 200
+
+// Pair statuses
+const (
+	StatusOpen       = "open"        // both legs were accepted
+	StatusFilled     = "filled"      // both legs matched
+	StatusRolledBack = "rolled_back" // one leg was rejected and the other cancelled
+	StatusBroken     = "broken"      // one leg was rejected and the other could not be cancelled
+	StatusRejected   = "rejected"    // both legs were rejected
+	StatusCancelled  = "cancelled"   // cancelled by the owner
+)
+
+// Leg statuses besides the CLOB's own (live, matched, delayed, unmatched)
+const (
+	LegRejected  = "rejected"
+	LegCancelled = "cancelled"
+	legMatched   = "matched"
+)
+
+// ErrNotFound is returned for a pair that does not exist or belongs to
+// another owner
+var ErrNotFound = errors.New("pair not found")
+
+// Leg is one of a pair's orders and what became of it
+type Leg struct {
+	Order       models.CreateOrderRequest `json:"order"`
+	OrderID     string                    `json:"order_id,omitempty"`
+	Status      string                    `json:"status"`
+	SizeMatched string                    `json:"size_matched,omitempty"`
+	Error       string                    `json:"error,omitempty"`
+}
+
+// open reports whether the leg may still rest on the book
+func (l *Leg) open() bool {
+	switch l.Status {
+	case LegRejected, LegCancelled, legMatched:
+		return false
+	}
+	return l.OrderID != ""
+}
+
+// Pair is two orders placed together
+type Pair struct {
+	ID        string    `json:"id"`
+	Status    string    `json:"status"`
+	Legs      [2]Leg    `json:"legs"`
+	Note      string    `json:"note,omitempty"` // why a rollback did not complete
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	owner string
+}
+
+// orderState is the subset of a CLOB order needed to refresh a leg
+type orderState struct {
+	Status      string `json:"status"`
+	SizeMatched string `json:"size_matched"`
+}
+
+// Manager places pairs and keeps them in memory per owner. Both legs go
+// to the CLOB in one batch request so they reach the book together; if
+// only one is accepted it is cancelled again, unless it already filled.
+type Manager struct {
+	clob *polymarket.ClobClient
+
+	mu      sync.Mutex
+	pairs   map[string]*Pair
+	byOwner map[string][]string // pair IDs, oldest first
+}
+
+// New creates a new pair manager
+func New(clob *polymarket.ClobClient) *Manager {
+	return &Manager{
+		clob:    clob,
+		pairs:   make(map[string]*Pair),
+		byOwner: make(map[string][]string),
+	}
+}
+
+// Place submits both legs and rolls back a lone accepted leg. An error
+// means the batch request itself failed and no pair was recorded.
+func (m *Manager) Place(owner string, legs [2]models.CreateOrderRequest, authHeaders map[string]string) (Pair, error) {
+	data, err := m.clob.CreateOrders(legs[:], authHeaders)
+	if err != nil {
+		return Pair{}, err
+	}
+
+	var results []models.PlacedOrder
+	_ = sonic.Unmarshal(data, &results)
+
+	now := time.Now()
+	pair := &Pair{ID: idgen.WithPrefix("pair"), CreatedAt: now, UpdatedAt: now, owner: owner}
+	accepted := 0
+	for i := range legs {
+		leg := Leg{Order: legs[i], Status: LegRejected, Error: "no result was returned for this leg"}
+		if i < len(results) {
+			r := results[i]
+			switch {
+			case r.OrderID != "" && (r.Success == nil || *r.Success) && r.ErrorMsg == "":
+				leg.OrderID, leg.Status, leg.Error = r.OrderID, strings.ToLower(r.Status), ""
+				accepted++
+			case r.ErrorMsg != "":
+				leg.Error = r.ErrorMsg
+			default:
+				leg.Error = "rejected by the CLOB"
+			}
+		}
+		pair.Legs[i] = leg
+	}
+
+	switch accepted {
+	case 2:
+		pair.Status = StatusOpen
+		pair.settle()
+	case 0:
+		pair.Status = StatusRejected
+	default:
+		m.rollback(pair, authHeaders)
+	}
+
+	m.store(pair)
+	return *pair, nil
+}
+
+// rollback cancels the accepted leg of a half-placed pair
+func (m *Manager) rollback(pair *Pair, authHeaders map[string]string) {
+	i := 0
+	if pair.Legs[0].Status == LegRejected {
+		i = 1
+	}
+	leg := &pair.Legs[i]
+
+	if !leg.open() {
+		pair.Status = StatusBroken
+		pair.Note = fmt.Sprintf("legs[%d] filled before legs[%d] was rejected and cannot be rolled back", i, 1-i)
+		return
+	}
+	if err := m.cancel(leg, authHeaders); err != nil {
+		pair.Status = StatusBroken
+		pair.Note = fmt.Sprintf("legs[%d] could not be cancelled after legs[%d] was rejected: %v", i, 1-i, err)
+		return
+	}
+	pair.Status = StatusRolledBack
+}
+
+// cancel cancels one leg, recording the outcome on it
+func (m *Manager) cancel(leg *Leg, authHeaders map[string]string) error {
+	data, err := m.clob.CancelOrder(leg.OrderID, authHeaders)
+	if err != nil {
+		leg.Error = err.Error()
+		return err
+	}
+
+	var result models.CancelResult
+	if err := sonic.Unmarshal(data, &result); err == nil {
+		if reason, ok := result.NotCanceled[leg.OrderID]; ok {
+			leg.Error = reason
+			return errors.New(reason)
+		}
+	}
+	leg.Status = LegCancelled
+	leg.Error = ""
+	return nil
+}
+
+// settle marks an open pair filled once both legs matched
+func (p *Pair) settle() {
+	if p.Status == StatusOpen && p.Legs[0].Status == legMatched && p.Legs[1].Status == legMatched {
+		p.Status = StatusFilled
+	}
+}
+
+// Get returns one of the owner's pairs as last recorded
+func (m *Manager) Get(owner, id string) (Pair, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pair, ok := m.pairs[id]
+	if !ok || pair.owner != owner {
+		return Pair{}, ErrNotFound
+	}
+	return *pair, nil
+}
+
+// List returns the owner's pairs, newest first
+func (m *Manager) List(owner string) []Pair {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]Pair, 0, len(m.byOwner[owner]))
+	for _, id := range m.byOwner[owner] {
+		out = append(out, *m.pairs[id])
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out
+}
+
+// Refresh updates the pair's open legs from the CLOB
+func (m *Manager) Refresh(owner, id string, authHeaders map[string]string) (Pair, error) {
+	pair, err := m.Get(owner, id)
+	if err != nil {
+		return Pair{}, err
+	}
+
+	for i := range pair.Legs {
+		leg := &pair.Legs[i]
+		if !leg.open() {
+			continue
+		}
+		data, err := m.clob.GetOrder(leg.OrderID, authHeaders)
+		if err != nil {
+			return Pair{}, err
+		}
+		var state orderState
+		if err := sonic.Unmarshal(data, &state); err != nil {
+			return Pair{}, err
+		}
+		leg.Status = strings.ToLower(state.Status)
+		if leg.Status == "canceled" {
+			leg.Status = LegCancelled
+		}
+		leg.SizeMatched = state.SizeMatched
+	}
+	pair.settle()
+
+	pair.UpdatedAt = time.Now()
+	m.update(&pair)
+	return pair, nil
+}
+
+// Cancel cancels the pair's open legs. Legs that cannot be cancelled keep
+// their status and carry the reason in Error.
+func (m *Manager) Cancel(owner, id string, authHeaders map[string]string) (Pair, error) {
+	pair, err := m.Get(owner, id)
+	if err != nil {
+		return Pair{}, err
+	}
+
+	var failed error
+	for i := range pair.Legs {
+		leg := &pair.Legs[i]
+		if !leg.open() {
+			continue
+		}
+		if err := m.cancel(leg, authHeaders); err != nil && failed == nil {
+			failed = err
+		}
+	}
+	if pair.Status == StatusOpen {
+		pair.Status = StatusCancelled
+	}
+
+	pair.UpdatedAt = time.Now()
+	m.update(&pair)
+	return pair, failed
+}
+
+// store records a new pair, dropping the owner's oldest beyond the cap
+func (m *Manager) store(pair *Pair) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pairs[pair.ID] = pair
+	ids := append(m.byOwner[pair.owner], pair.ID)
+	for len(ids) > maxPairsPerOwner {
+		delete(m.pairs, ids[0])
+		ids = ids[1:]
+	}
+	m.byOwner[pair.owner] = ids
+}
+
+// update replaces a recorded pair unless it was dropped meanwhile
+func (m *Manager) update(pair *Pair) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.pairs[pair.ID]; ok {
+		updated := *pair
+		m.pairs[pair.ID] = &updated
+	}
+}
@@ -0,0 +1,80 @@
+package pb
+
+import (
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/shape"
+	"google.golang.org/protobuf/proto"
+)
+
+// From converts a shaped value to its message, false when it has none
+func From(v interface{}) (proto.Message, bool) {
+	switch v := v.(type) {
+	case shape.Price:
+		return fromPrice(v), true
+	case []shape.Price:
+		out := &PriceList{Prices: make([]*Price, len(v))}
+		for i, p := range v {
+			out.Prices[i] = fromPrice(p)
+		}
+		return out, true
+	case shape.OrderBook:
+		return fromBook(v), true
+	case []shape.OrderBook:
+		out := &OrderBookList{Books: make([]*OrderBook, len(v))}
+		for i, b := range v {
+			out.Books[i] = fromBook(b)
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// Snapshots converts token snapshots to a SnapshotList
+func Snapshots(snaps []polymarket.TokenSnapshot) *SnapshotList {
+	out := &SnapshotList{Tokens: make([]*TokenSnapshot, len(snaps))}
+	for i, s := range snaps {
+		out.Tokens[i] = &TokenSnapshot{
+			TokenId:    s.TokenID,
+			Bid:        s.Bid,
+			Ask:        s.Ask,
+			Mid:        s.Mid,
+			Last:       s.Last,
+			Spread:     s.Spread,
+			Change_24H: s.Change24h,
+			BidDepth:   s.BidDepth,
+			AskDepth:   s.AskDepth,
+			Error:      s.Error,
+		}
+	}
+	return out
+}
+
+func fromPrice(p shape.Price) *Price {
+	return &Price{TokenId: p.TokenID, Side: p.Side, Price: p.Price}
+}
+
+func fromBook(b shape.OrderBook) *OrderBook {
+	out := &OrderBook{
+		TokenId:            b.TokenID,
+		ConditionId:        b.ConditionID,
+		Bids:               fromLevels(b.Bids),
+		Asks:               fromLevels(b.Asks),
+		LastTradePrice:     b.LastTradePrice,
+		ImpliedProbability: b.ImpliedProbability,
+		TickSize:           b.TickSize,
+		MinOrderSize:       b.MinOrderSize,
+		Hash:               b.Hash,
+	}
+	if b.Timestamp != nil {
+		out.TimestampMs = b.Timestamp.UnixMilli()
+	}
+	return out
+}
+
+func fromLevels(levels []shape.Level) []*Level {
+	out := make([]*Level, len(levels))
+	for i, l := range levels {
+		out[i] = &Level{Price: l.Price, Size: l.Size}
+	}
+	return out
+}
@@ -0,0 +1,8 @@
+// Package pb holds the protobuf messages of polygo.proto, generated by
+// protoc-gen-go, and converts the shaped responses they replace.
+package pb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative polygo.proto
+
+// ContentType is the media type of protobuf responses
+const ContentType = "application/x-protobuf"
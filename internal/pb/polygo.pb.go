@@ -0,0 +1,622 @@
+// Protobuf responses of the high-frequency endpoints, sent instead of JSON
+// when a request has Accept: application/x-protobuf. Field numbers are
+// stable; new fields get new numbers.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
+// source: polygo.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// GET /price/{token_id}
+type Price struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TokenId       string                 `protobuf:"bytes,1,opt,name=token_id,json=tokenId,proto3" json:"token_id,omitempty"`
+	Side          string                 `protobuf:"bytes,2,opt,name=side,proto3" json:"side,omitempty"`
+	Price         *float64               `protobuf:"fixed64,3,opt,name=price,proto3,oneof" json:"price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Price) Reset() {
+	*x = Price{}
+	mi := &file_polygo_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Price) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Price) ProtoMessage() {}
+
+func (x *Price) ProtoReflect() protoreflect.Message {
+	mi := &file_polygo_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Price.ProtoReflect.Descriptor instead.
+func (*Price) Descriptor() ([]byte, []int) {
+	return file_polygo_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Price) GetTokenId() string {
+	if x != nil {
+		return x.TokenId
+	}
+	return ""
+}
+
+func (x *Price) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *Price) GetPrice() float64 {
+	if x != nil && x.Price != nil {
+		return *x.Price
+	}
+	return 0
+}
+
+// GET /prices
+type PriceList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prices        []*Price               `protobuf:"bytes,1,rep,name=prices,proto3" json:"prices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceList) Reset() {
+	*x = PriceList{}
+	mi := &file_polygo_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceList) ProtoMessage() {}
+
+func (x *PriceList) ProtoReflect() protoreflect.Message {
+	mi := &file_polygo_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceList.ProtoReflect.Descriptor instead.
+func (*PriceList) Descriptor() ([]byte, []int) {
+	return file_polygo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PriceList) GetPrices() []*Price {
+	if x != nil {
+		return x.Prices
+	}
+	return nil
+}
+
+type Level struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Price         float64                `protobuf:"fixed64,1,opt,name=price,proto3" json:"price,omitempty"`
+	Size          float64                `protobuf:"fixed64,2,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Level) Reset() {
+	*x = Level{}
+	mi := &file_polygo_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Level) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Level) ProtoMessage() {}
+
+func (x *Level) ProtoReflect() protoreflect.Message {
+	mi := &file_polygo_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Level.ProtoReflect.Descriptor instead.
+func (*Level) Descriptor() ([]byte, []int) {
+	return file_polygo_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Level) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Level) GetSize() float64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+// GET /book/{token_id}. Both sides are ordered best price first.
+type OrderBook struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	TokenId            string                 `protobuf:"bytes,1,opt,name=token_id,json=tokenId,proto3" json:"token_id,omitempty"`
+	ConditionId        string                 `protobuf:"bytes,2,opt,name=condition_id,json=conditionId,proto3" json:"condition_id,omitempty"`
+	Bids               []*Level               `protobuf:"bytes,3,rep,name=bids,proto3" json:"bids,omitempty"`
+	Asks               []*Level               `protobuf:"bytes,4,rep,name=asks,proto3" json:"asks,omitempty"`
+	LastTradePrice     *float64               `protobuf:"fixed64,5,opt,name=last_trade_price,json=lastTradePrice,proto3,oneof" json:"last_trade_price,omitempty"`
+	ImpliedProbability *float64               `protobuf:"fixed64,6,opt,name=implied_probability,json=impliedProbability,proto3,oneof" json:"implied_probability,omitempty"`
+	TickSize           float64                `protobuf:"fixed64,7,opt,name=tick_size,json=tickSize,proto3" json:"tick_size,omitempty"`
+	MinOrderSize       float64                `protobuf:"fixed64,8,opt,name=min_order_size,json=minOrderSize,proto3" json:"min_order_size,omitempty"`
+	Hash               string                 `protobuf:"bytes,9,opt,name=hash,proto3" json:"hash,omitempty"`
+	TimestampMs        int64                  `protobuf:"varint,10,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *OrderBook) Reset() {
+	*x = OrderBook{}
+	mi := &file_polygo_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderBook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderBook) ProtoMessage() {}
+
+func (x *OrderBook) ProtoReflect() protoreflect.Message {
+	mi := &file_polygo_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderBook.ProtoReflect.Descriptor instead.
+func (*OrderBook) Descriptor() ([]byte, []int) {
+	return file_polygo_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OrderBook) GetTokenId() string {
+	if x != nil {
+		return x.TokenId
+	}
+	return ""
+}
+
+func (x *OrderBook) GetConditionId() string {
+	if x != nil {
+		return x.ConditionId
+	}
+	return ""
+}
+
+func (x *OrderBook) GetBids() []*Level {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+func (x *OrderBook) GetAsks() []*Level {
+	if x != nil {
+		return x.Asks
+	}
+	return nil
+}
+
+func (x *OrderBook) GetLastTradePrice() float64 {
+	if x != nil && x.LastTradePrice != nil {
+		return *x.LastTradePrice
+	}
+	return 0
+}
+
+func (x *OrderBook) GetImpliedProbability() float64 {
+	if x != nil && x.ImpliedProbability != nil {
+		return *x.ImpliedProbability
+	}
+	return 0
+}
+
+func (x *OrderBook) GetTickSize() float64 {
+	if x != nil {
+		return x.TickSize
+	}
+	return 0
+}
+
+func (x *OrderBook) GetMinOrderSize() float64 {
+	if x != nil {
+		return x.MinOrderSize
+	}
+	return 0
+}
+
+func (x *OrderBook) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *OrderBook) GetTimestampMs() int64 {
+	if x != nil {
+		return x.TimestampMs
+	}
+	return 0
+}
+
+// GET /books
+type OrderBookList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Books         []*OrderBook           `protobuf:"bytes,1,rep,name=books,proto3" json:"books,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderBookList) Reset() {
+	*x = OrderBookList{}
+	mi := &file_polygo_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderBookList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderBookList) ProtoMessage() {}
+
+func (x *OrderBookList) ProtoReflect() protoreflect.Message {
+	mi := &file_polygo_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderBookList.ProtoReflect.Descriptor instead.
+func (*OrderBookList) Descriptor() ([]byte, []int) {
+	return file_polygo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OrderBookList) GetBooks() []*OrderBook {
+	if x != nil {
+		return x.Books
+	}
+	return nil
+}
+
+type TokenSnapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TokenId       string                 `protobuf:"bytes,1,opt,name=token_id,json=tokenId,proto3" json:"token_id,omitempty"`
+	Bid           *float64               `protobuf:"fixed64,2,opt,name=bid,proto3,oneof" json:"bid,omitempty"`
+	Ask           *float64               `protobuf:"fixed64,3,opt,name=ask,proto3,oneof" json:"ask,omitempty"`
+	Mid           *float64               `protobuf:"fixed64,4,opt,name=mid,proto3,oneof" json:"mid,omitempty"`
+	Last          *float64               `protobuf:"fixed64,5,opt,name=last,proto3,oneof" json:"last,omitempty"`
+	Spread        *float64               `protobuf:"fixed64,6,opt,name=spread,proto3,oneof" json:"spread,omitempty"`
+	Change_24H    *float64               `protobuf:"fixed64,7,opt,name=change_24h,json=change24h,proto3,oneof" json:"change_24h,omitempty"`
+	BidDepth      float64                `protobuf:"fixed64,8,opt,name=bid_depth,json=bidDepth,proto3" json:"bid_depth,omitempty"`
+	AskDepth      float64                `protobuf:"fixed64,9,opt,name=ask_depth,json=askDepth,proto3" json:"ask_depth,omitempty"`
+	Error         string                 `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenSnapshot) Reset() {
+	*x = TokenSnapshot{}
+	mi := &file_polygo_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenSnapshot) ProtoMessage() {}
+
+func (x *TokenSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_polygo_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenSnapshot.ProtoReflect.Descriptor instead.
+func (*TokenSnapshot) Descriptor() ([]byte, []int) {
+	return file_polygo_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TokenSnapshot) GetTokenId() string {
+	if x != nil {
+		return x.TokenId
+	}
+	return ""
+}
+
+func (x *TokenSnapshot) GetBid() float64 {
+	if x != nil && x.Bid != nil {
+		return *x.Bid
+	}
+	return 0
+}
+
+func (x *TokenSnapshot) GetAsk() float64 {
+	if x != nil && x.Ask != nil {
+		return *x.Ask
+	}
+	return 0
+}
+
+func (x *TokenSnapshot) GetMid() float64 {
+	if x != nil && x.Mid != nil {
+		return *x.Mid
+	}
+	return 0
+}
+
+func (x *TokenSnapshot) GetLast() float64 {
+	if x != nil && x.Last != nil {
+		return *x.Last
+	}
+	return 0
+}
+
+func (x *TokenSnapshot) GetSpread() float64 {
+	if x != nil && x.Spread != nil {
+		return *x.Spread
+	}
+	return 0
+}
+
+func (x *TokenSnapshot) GetChange_24H() float64 {
+	if x != nil && x.Change_24H != nil {
+		return *x.Change_24H
+	}
+	return 0
+}
+
+func (x *TokenSnapshot) GetBidDepth() float64 {
+	if x != nil {
+		return x.BidDepth
+	}
+	return 0
+}
+
+func (x *TokenSnapshot) GetAskDepth() float64 {
+	if x != nil {
+		return x.AskDepth
+	}
+	return 0
+}
+
+func (x *TokenSnapshot) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// GET /snapshot
+type SnapshotList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tokens        []*TokenSnapshot       `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotList) Reset() {
+	*x = SnapshotList{}
+	mi := &file_polygo_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotList) ProtoMessage() {}
+
+func (x *SnapshotList) ProtoReflect() protoreflect.Message {
+	mi := &file_polygo_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotList.ProtoReflect.Descriptor instead.
+func (*SnapshotList) Descriptor() ([]byte, []int) {
+	return file_polygo_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SnapshotList) GetTokens() []*TokenSnapshot {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+var File_polygo_proto protoreflect.FileDescriptor
+
+const file_polygo_proto_rawDesc = "" +
+	"\n" +
+	"\fpolygo.proto\x12\tpolygo.v1\"[\n" +
+	"\x05Price\x12\x19\n" +
+	"\btoken_id\x18\x01 \x01(\tR\atokenId\x12\x12\n" +
+	"\x04side\x18\x02 \x01(\tR\x04side\x12\x19\n" +
+	"\x05price\x18\x03 \x01(\x01H\x00R\x05price\x88\x01\x01B\b\n" +
+	"\x06_price\"5\n" +
+	"\tPriceList\x12(\n" +
+	"\x06prices\x18\x01 \x03(\v2\x10.polygo.v1.PriceR\x06prices\"1\n" +
+	"\x05Level\x12\x14\n" +
+	"\x05price\x18\x01 \x01(\x01R\x05price\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x01R\x04size\"\xa1\x03\n" +
+	"\tOrderBook\x12\x19\n" +
+	"\btoken_id\x18\x01 \x01(\tR\atokenId\x12!\n" +
+	"\fcondition_id\x18\x02 \x01(\tR\vconditionId\x12$\n" +
+	"\x04bids\x18\x03 \x03(\v2\x10.polygo.v1.LevelR\x04bids\x12$\n" +
+	"\x04asks\x18\x04 \x03(\v2\x10.polygo.v1.LevelR\x04asks\x12-\n" +
+	"\x10last_trade_price\x18\x05 \x01(\x01H\x00R\x0elastTradePrice\x88\x01\x01\x124\n" +
+	"\x13implied_probability\x18\x06 \x01(\x01H\x01R\x12impliedProbability\x88\x01\x01\x12\x1b\n" +
+	"\ttick_size\x18\a \x01(\x01R\btickSize\x12$\n" +
+	"\x0emin_order_size\x18\b \x01(\x01R\fminOrderSize\x12\x12\n" +
+	"\x04hash\x18\t \x01(\tR\x04hash\x12!\n" +
+	"\ftimestamp_ms\x18\n" +
+	" \x01(\x03R\vtimestampMsB\x13\n" +
+	"\x11_last_trade_priceB\x16\n" +
+	"\x14_implied_probability\";\n" +
+	"\rOrderBookList\x12*\n" +
+	"\x05books\x18\x01 \x03(\v2\x14.polygo.v1.OrderBookR\x05books\"\xd4\x02\n" +
+	"\rTokenSnapshot\x12\x19\n" +
+	"\btoken_id\x18\x01 \x01(\tR\atokenId\x12\x15\n" +
+	"\x03bid\x18\x02 \x01(\x01H\x00R\x03bid\x88\x01\x01\x12\x15\n" +
+	"\x03ask\x18\x03 \x01(\x01H\x01R\x03ask\x88\x01\x01\x12\x15\n" +
+	"\x03mid\x18\x04 \x01(\x01H\x02R\x03mid\x88\x01\x01\x12\x17\n" +
+	"\x04last\x18\x05 \x01(\x01H\x03R\x04last\x88\x01\x01\x12\x1b\n" +
+	"\x06spread\x18\x06 \x01(\x01H\x04R\x06spread\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"change_24h\x18\a \x01(\x01H\x05R\tchange24h\x88\x01\x01\x12\x1b\n" +
+	"\tbid_depth\x18\b \x01(\x01R\bbidDepth\x12\x1b\n" +
+	"\task_depth\x18\t \x01(\x01R\baskDepth\x12\x14\n" +
+	"\x05error\x18\n" +
+	" \x01(\tR\x05errorB\x06\n" +
+	"\x04_bidB\x06\n" +
+	"\x04_askB\x06\n" +
+	"\x04_midB\a\n" +
+	"\x05_lastB\t\n" +
+	"\a_spreadB\r\n" +
+	"\v_change_24h\"@\n" +
+	"\fSnapshotList\x120\n" +
+	"\x06tokens\x18\x01 \x03(\v2\x18.polygo.v1.TokenSnapshotR\x06tokensB\x1fZ\x1dgithub.com/polygo/internal/pbb\x06proto3"
+
+var (
+	file_polygo_proto_rawDescOnce sync.Once
+	file_polygo_proto_rawDescData []byte
+)
+
+func file_polygo_proto_rawDescGZIP() []byte {
+	file_polygo_proto_rawDescOnce.Do(func() {
+		file_polygo_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_polygo_proto_rawDesc), len(file_polygo_proto_rawDesc)))
+	})
+	return file_polygo_proto_rawDescData
+}
+
+var file_polygo_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_polygo_proto_goTypes = []any{
+	(*Price)(nil),         // 0: polygo.v1.Price
+	(*PriceList)(nil),     // 1: polygo.v1.PriceList
+	(*Level)(nil),         // 2: polygo.v1.Level
+	(*OrderBook)(nil),     // 3: polygo.v1.OrderBook
+	(*OrderBookList)(nil), // 4: polygo.v1.OrderBookList
+	(*TokenSnapshot)(nil), // 5: polygo.v1.TokenSnapshot
+	(*SnapshotList)(nil),  // 6: polygo.v1.SnapshotList
+}
+var file_polygo_proto_depIdxs = []int32{
+	0, // 0: polygo.v1.PriceList.prices:type_name -> polygo.v1.Price
+	2, // 1: polygo.v1.OrderBook.bids:type_name -> polygo.v1.Level
+	2, // 2: polygo.v1.OrderBook.asks:type_name -> polygo.v1.Level
+	3, // 3: polygo.v1.OrderBookList.books:type_name -> polygo.v1.OrderBook
+	5, // 4: polygo.v1.SnapshotList.tokens:type_name -> polygo.v1.TokenSnapshot
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_polygo_proto_init() }
+func file_polygo_proto_init() {
+	if File_polygo_proto != nil {
+		return
+	}
+	file_polygo_proto_msgTypes[0].OneofWrappers = []any{}
+	file_polygo_proto_msgTypes[3].OneofWrappers = []any{}
+	file_polygo_proto_msgTypes[5].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_polygo_proto_rawDesc), len(file_polygo_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_polygo_proto_goTypes,
+		DependencyIndexes: file_polygo_proto_depIdxs,
+		MessageInfos:      file_polygo_proto_msgTypes,
+	}.Build()
+	File_polygo_proto = out.File
+	file_polygo_proto_goTypes = nil
+	file_polygo_proto_depIdxs = nil
+}
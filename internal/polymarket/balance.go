@@ -0,0 +1,270 @@
+package polymarket
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/latency"
+	"github.com/polygo/internal/models"
+)
+
+// Asset types accepted by the CLOB's balance-allowance endpoint
+const (
+	AssetCollateral  = "COLLATERAL"
+	AssetConditional = "CONDITIONAL"
+)
+
+// assetDecimals scales CLOB balances and allowances, which are reported in
+// base units: USDC and conditional tokens both have 6 decimals
+const assetDecimals = 1e6
+
+// maxTokenChecks bounds the conditional token balances looked up for open
+// SELL orders in one report
+const maxTokenChecks = 20
+
+// TokenBalance is a conditional token held against open SELL orders
+type TokenBalance struct {
+	TokenID  string  `json:"token_id"`
+	Balance  float64 `json:"balance"`  // shares held
+	Required float64 `json:"required"` // shares offered by open SELL orders
+	Approved bool    `json:"approved"` // the exchange may transfer the shares
+}
+
+// BalanceReport is whether an account is funded and approved to trade.
+// Amounts are in USDC unless noted.
+type BalanceReport struct {
+	Balance         float64            `json:"balance"`
+	Allowances      map[string]float64 `json:"allowances"`        // by exchange contract address
+	OpenBuyNotional float64            `json:"open_buy_notional"` // reserved by open BUY orders
+	Available       float64            `json:"available"`         // balance not reserved by open BUY orders
+	Tokens          []TokenBalance     `json:"tokens,omitempty"`  // behind open SELL orders
+	CanTrade        bool               `json:"can_trade"`
+	Warnings        []string           `json:"warnings"`
+}
+
+// balanceAllowance is the CLOB's reply for one asset. Older deployments
+// report a single allowance instead of one per exchange contract.
+type balanceAllowance struct {
+	Balance    models.FlexString            `json:"balance"`
+	Allowance  models.FlexString            `json:"allowance"`
+	Allowances map[string]models.FlexString `json:"allowances"`
+}
+
+// allowances returns the reply's allowances in whole units
+func (b *balanceAllowance) allowances() map[string]float64 {
+	out := make(map[string]float64, len(b.Allowances))
+	for spender, amount := range b.Allowances {
+		out[spender] = amount.Float() / assetDecimals
+	}
+	if len(out) == 0 && b.Allowance != "" {
+		out["exchange"] = b.Allowance.Float() / assetDecimals
+	}
+	return out
+}
+
+// restingOrder is the subset of a CLOB open order needed for what it reserves
+type restingOrder struct {
+	AssetID      string            `json:"asset_id"`
+	Side         string            `json:"side"`
+	Price        models.FlexString `json:"price"`
+	OriginalSize models.FlexString `json:"original_size"`
+	SizeMatched  models.FlexString `json:"size_matched"`
+}
+
+// remaining is the size still resting on the book
+func (o *restingOrder) remaining() float64 {
+	return math.Max(o.OriginalSize.Float()-o.SizeMatched.Float(), 0)
+}
+
+// BalanceChecker reports an account's balances and allowances against what
+// its open orders need
+type BalanceChecker struct {
+	clob *ClobClient
+}
+
+// NewBalanceChecker creates a new balance checker
+func NewBalanceChecker(clob *ClobClient) *BalanceChecker {
+	return &BalanceChecker{clob: clob}
+}
+
+// Report fetches the account's USDC balance and allowances and its open
+// orders, then the balances of tokens offered by open SELL orders.
+// signatureType selects the account's wallet type as for order signing
+// and may be empty.
+func (b *BalanceChecker) Report(signatureType string, authHeaders map[string]string) (*BalanceReport, error) {
+	var (
+		collateral balanceAllowance
+		orders     []restingOrder
+		errs       [2]error
+		wg         sync.WaitGroup
+	)
+	bind := latency.Inherit()
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer bind()()
+		errs[0] = b.fetch(AssetCollateral, "", signatureType, authHeaders, &collateral)
+	}()
+	go func() {
+		defer wg.Done()
+		defer bind()()
+		orders, errs[1] = b.openOrders(authHeaders)
+	}()
+	wg.Wait()
+	if err := errors.Join(errs[:]...); err != nil {
+		return nil, err
+	}
+
+	report := &BalanceReport{
+		Balance:    collateral.Balance.Float() / assetDecimals,
+		Allowances: collateral.allowances(),
+		Warnings:   []string{},
+	}
+
+	selling := make(map[string]float64)
+	for i := range orders {
+		o := &orders[i]
+		if strings.EqualFold(o.Side, string(models.SideSell)) {
+			selling[o.AssetID] += o.remaining()
+		} else {
+			report.OpenBuyNotional += o.Price.Float() * o.remaining()
+		}
+	}
+	report.Available = math.Max(report.Balance-report.OpenBuyNotional, 0)
+
+	tokens, err := b.tokenBalances(selling, signatureType, authHeaders)
+	if err != nil {
+		return nil, err
+	}
+	report.Tokens = tokens
+
+	report.assess(len(selling) > len(tokens))
+	return report, nil
+}
+
+// assess sets CanTrade and explains anything that would make orders fail
+func (r *BalanceReport) assess(truncated bool) {
+	warn := func(format string, args ...interface{}) {
+		r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	approved := len(r.Allowances) > 0
+	spenders := make([]string, 0, len(r.Allowances))
+	for spender := range r.Allowances {
+		spenders = append(spenders, spender)
+	}
+	sort.Strings(spenders)
+	for _, spender := range spenders {
+		amount := r.Allowances[spender]
+		switch {
+		case amount <= 0:
+			approved = false
+			warn("No USDC allowance for %s; orders settled through it will fail", spender)
+		case amount < r.OpenBuyNotional:
+			warn("USDC allowance for %s (%s) is below the %s reserved by open BUY orders", spender, formatAmount(amount), formatAmount(r.OpenBuyNotional))
+		}
+	}
+	if len(r.Allowances) == 0 {
+		warn("No USDC allowances were reported; the exchange contracts may not be approved")
+	}
+
+	if r.Balance <= 0 {
+		warn("USDC balance is zero")
+	} else if r.Balance < r.OpenBuyNotional {
+		warn("USDC balance (%s) is below the %s reserved by open BUY orders; some will not fill", formatAmount(r.Balance), formatAmount(r.OpenBuyNotional))
+	}
+
+	for _, t := range r.Tokens {
+		if t.Balance < t.Required {
+			warn("Token %s balance (%s shares) is below the %s offered by open SELL orders", t.TokenID, formatAmount(t.Balance), formatAmount(t.Required))
+		}
+		if !t.Approved {
+			warn("Token %s is not approved for the exchange; open SELL orders will fail", t.TokenID)
+		}
+	}
+	if truncated {
+		warn("Only the first %d tokens with open SELL orders were checked", maxTokenChecks)
+	}
+
+	r.CanTrade = approved && r.Balance > 0
+}
+
+// tokenBalances looks up the tokens offered by open SELL orders, concurrently
+func (b *BalanceChecker) tokenBalances(selling map[string]float64, signatureType string, authHeaders map[string]string) ([]TokenBalance, error) {
+	ids := make([]string, 0, len(selling))
+	for id := range selling {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	if len(ids) > maxTokenChecks {
+		ids = ids[:maxTokenChecks]
+	}
+
+	tokens := make([]TokenBalance, len(ids))
+	errs := make([]error, len(ids))
+	var wg sync.WaitGroup
+	bind := latency.Inherit()
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			defer bind()()
+			var reply balanceAllowance
+			if errs[i] = b.fetch(AssetConditional, id, signatureType, authHeaders, &reply); errs[i] != nil {
+				return
+			}
+			tokens[i] = TokenBalance{TokenID: id, Balance: reply.Balance.Float() / assetDecimals, Required: selling[id], Approved: true}
+			for _, amount := range reply.allowances() {
+				if amount <= 0 {
+					tokens[i].Approved = false
+				}
+			}
+		}(i, id)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// fetch reads one asset's balance and allowances
+func (b *BalanceChecker) fetch(assetType, tokenID, signatureType string, authHeaders map[string]string, into *balanceAllowance) error {
+	data, err := b.clob.GetBalanceAllowance(assetType, tokenID, signatureType, authHeaders)
+	if err != nil {
+		return err
+	}
+	return sonic.Unmarshal(data, into)
+}
+
+// openOrders reads the account's open orders, a plain list or a {data: [...]} page
+func (b *BalanceChecker) openOrders(authHeaders map[string]string) ([]restingOrder, error) {
+	data, err := b.clob.GetOpenOrders("", authHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []restingOrder
+	if err := sonic.Unmarshal(data, &orders); err != nil {
+		var page struct {
+			Data []restingOrder `json:"data"`
+		}
+		if err := sonic.Unmarshal(data, &page); err != nil {
+			return nil, err
+		}
+		orders = page.Data
+	}
+	return orders, nil
+}
+
+// formatAmount prints an amount to the assets' 6 decimals, without float noise
+func formatAmount(f float64) string {
+	return strconv.FormatFloat(math.Round(f*assetDecimals)/assetDecimals, 'f', -1, 64)
+}
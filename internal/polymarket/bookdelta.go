@@ -0,0 +1,215 @@
+package polymarket
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/models"
+)
+
+// Book stream event types
+const (
+	BookEventSnapshot = "book"       // full book, the upstream format plus seq
+	BookEventDelta    = "book_delta" // changed levels only; size "0" removes a level
+)
+
+// BookUpdate is an order book message sent to delta-mode clients. Seq
+// increases by one per message for a token; a gap means the client must
+// wait for (or request) the next full snapshot.
+type BookUpdate struct {
+	EventType string              `json:"event_type"`
+	AssetID   string              `json:"asset_id"`
+	Market    string              `json:"market"`
+	Seq       uint64              `json:"seq"`
+	Bids      []models.PriceLevel `json:"bids"`
+	Asks      []models.PriceLevel `json:"asks"`
+	Hash      string              `json:"hash,omitempty"`
+	Timestamp json.RawMessage     `json:"timestamp,omitempty"`
+}
+
+// bookState is the last book seen for a token
+type bookState struct {
+	market        string
+	seq           uint64
+	bids          map[string]string // price -> size
+	asks          map[string]string
+	hash          string
+	timestamp     json.RawMessage
+	sinceSnapshot int
+}
+
+// BookDiffer converts full upstream book messages into per-token deltas,
+// sending a full snapshot every snapshotEvery updates so clients that
+// missed a message resync without reconnecting
+type BookDiffer struct {
+	snapshotEvery int
+
+	mu    sync.Mutex
+	books map[string]*bookState // asset ID -> last book
+}
+
+// NewBookDiffer creates a new book differ
+func NewBookDiffer(snapshotEvery int) *BookDiffer {
+	if snapshotEvery <= 0 {
+		snapshotEvery = 1
+	}
+	return &BookDiffer{
+		snapshotEvery: snapshotEvery,
+		books:         make(map[string]*bookState),
+	}
+}
+
+// Apply folds an upstream message into the tracked books. It reports
+// false when the message is not a full book. Otherwise it returns the
+// encoded update, which is nil when no level changed.
+func (d *BookDiffer) Apply(data []byte) ([]byte, bool) {
+	var msg struct {
+		EventType string              `json:"event_type"`
+		AssetID   string              `json:"asset_id"`
+		Market    string              `json:"market"`
+		Bids      []models.PriceLevel `json:"bids"`
+		Asks      []models.PriceLevel `json:"asks"`
+		Hash      string              `json:"hash"`
+		Timestamp json.RawMessage     `json:"timestamp"`
+	}
+	if err := sonic.Unmarshal(data, &msg); err != nil || msg.EventType != BookEventSnapshot || msg.AssetID == "" {
+		return nil, false
+	}
+
+	bids, asks := levelMap(msg.Bids), levelMap(msg.Asks)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	prev, ok := d.books[msg.AssetID]
+	state := &bookState{
+		market:    msg.Market,
+		bids:      bids,
+		asks:      asks,
+		hash:      msg.Hash,
+		timestamp: msg.Timestamp,
+	}
+	d.books[msg.AssetID] = state
+
+	if !ok {
+		state.seq = 1
+		return state.encode(msg.AssetID), true
+	}
+
+	state.seq = prev.seq
+	state.sinceSnapshot = prev.sinceSnapshot
+	bidDelta, askDelta := diffLevels(prev.bids, bids, true), diffLevels(prev.asks, asks, false)
+	if len(bidDelta) == 0 && len(askDelta) == 0 {
+		return nil, true
+	}
+
+	state.seq++
+	state.sinceSnapshot++
+	if state.sinceSnapshot >= d.snapshotEvery {
+		state.sinceSnapshot = 0
+		return state.encode(msg.AssetID), true
+	}
+
+	update, err := sonic.Marshal(BookUpdate{
+		EventType: BookEventDelta,
+		AssetID:   msg.AssetID,
+		Market:    state.market,
+		Seq:       state.seq,
+		Bids:      bidDelta,
+		Asks:      askDelta,
+		Hash:      state.hash,
+		Timestamp: state.timestamp,
+	})
+	if err != nil {
+		return nil, true
+	}
+	return update, true
+}
+
+// Snapshots returns the current full book of every token in a market, of
+// the token itself when market is a token ID, or of all tracked tokens when
+// market is "*", for clients joining mid-stream
+func (d *BookDiffer) Snapshots(market string) [][]byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	assets := make([]string, 0, len(d.books))
+	for id, state := range d.books {
+		if market == "*" || state.market == market || id == market {
+			assets = append(assets, id)
+		}
+	}
+	sort.Strings(assets)
+
+	out := make([][]byte, 0, len(assets))
+	for _, id := range assets {
+		if data := d.books[id].encode(id); data != nil {
+			out = append(out, data)
+		}
+	}
+	return out
+}
+
+// encode returns the full book as a snapshot message
+func (s *bookState) encode(assetID string) []byte {
+	data, err := sonic.Marshal(BookUpdate{
+		EventType: BookEventSnapshot,
+		AssetID:   assetID,
+		Market:    s.market,
+		Seq:       s.seq,
+		Bids:      sortedLevels(s.bids, true),
+		Asks:      sortedLevels(s.asks, false),
+		Hash:      s.hash,
+		Timestamp: s.timestamp,
+	})
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// levelMap indexes levels by price
+func levelMap(levels []models.PriceLevel) map[string]string {
+	m := make(map[string]string, len(levels))
+	for _, l := range levels {
+		m[l.Price] = l.Size
+	}
+	return m
+}
+
+// diffLevels returns the levels whose size changed, with removed levels
+// reported at size "0"
+func diffLevels(prev, next map[string]string, descending bool) []models.PriceLevel {
+	changed := make(map[string]string)
+	for price, size := range next {
+		if prev[price] != size {
+			changed[price] = size
+		}
+	}
+	for price := range prev {
+		if _, ok := next[price]; !ok {
+			changed[price] = "0"
+		}
+	}
+	return sortedLevels(changed, descending)
+}
+
+// sortedLevels returns levels ordered best price first
+func sortedLevels(levels map[string]string, descending bool) []models.PriceLevel {
+	out := make([]models.PriceLevel, 0, len(levels))
+	for price, size := range levels {
+		out = append(out, models.PriceLevel{Price: price, Size: size})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		pi, _ := strconv.ParseFloat(out[i].Price, 64)
+		pj, _ := strconv.ParseFloat(out[j].Price, 64)
+		if descending {
+			return pi > pj
+		}
+		return pi < pj
+	})
+	return out
+}
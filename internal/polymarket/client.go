@@ -1,65 +1,183 @@
 package polymarket
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/polygo/internal/cache"
 	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/latency"
+	"github.com/polygo/internal/tape"
 	"github.com/valyala/fasthttp"
 )
 
 // Client is the main Polymarket API client
 type Client struct {
 	httpClient *fasthttp.Client
+	// Streams bodies above StreamThreshold; nil when streaming is off
+	streamClient *fasthttp.Client
 	cache      *cache.Cache
 	config     *config.PolymarketConfig
+	retry      *retryPolicy
+
+	// Adaptive rate limiters per upstream host
+	limiters   map[string]*hostLimiter
+	limitersMu sync.Mutex
+
+	// Record/replay of upstream responses; nil when off
+	tape *tape.Tape
+
+	// Schema drift detection; nil when validation is off
+	drift *driftDetector
+
+	// Reports overload, when cache misses are refused; nil never sheds
+	shed func() bool
+
+	// Upstream requests shared by concurrent cache misses
+	flights flightGroup
+
+	// Routes connections through the configured HTTP or SOCKS5 proxies
+	proxies *ProxyDialer
+
+	// Connections per upstream host, and their limits
+	pool *connPool
+
+	// Moves requests to mirrors while a base URL is down; nil never does
+	failover *Failover
 
 	// Base URLs
 	clobURL  string
 	gammaURL string
 	dataURL  string
-
-	// Request/Response pools for zero-allocation
-	reqPool  sync.Pool
-	respPool sync.Pool
 }
 
 // NewClient creates a new Polymarket client with optimized settings
 func NewClient(cfg *config.PolymarketConfig, c *cache.Cache) *Client {
+	// The connection read timeout must allow the slowest overridden path
+	readTimeout := cfg.ReadTimeout
+	for _, t := range cfg.Timeouts {
+		if t > readTimeout {
+			readTimeout = t
+		}
+	}
+
+	// An invalid proxy fails every request; NewServer refuses it first
+	proxies, _ := NewProxyDialer(cfg)
+	pool := newConnPool(cfg.MaxConnsPerHost)
+
 	client := &Client{
-		httpClient: &fasthttp.Client{
-			Name:                     "PolyGo/1.0",
-			MaxConnsPerHost:          cfg.MaxConnsPerHost,
-			MaxIdleConnDuration:      cfg.MaxIdleConnDur,
-			ReadTimeout:              cfg.ReadTimeout,
-			WriteTimeout:             cfg.WriteTimeout,
-			NoDefaultUserAgentHeader: true,
-			DisableHeaderNamesNormalizing: true,
-			DisablePathNormalizing:   true,
-		},
+		httpClient: newHTTPClient(cfg, proxies, pool, readTimeout, false),
 		cache:    c,
 		config:   cfg,
+		retry:    newRetryPolicy(cfg),
+		limiters: make(map[string]*hostLimiter),
+		drift:    newDriftDetector(cfg),
 		clobURL:  cfg.ClobBaseURL,
 		gammaURL: cfg.GammaBaseURL,
 		dataURL:  cfg.DataBaseURL,
+		proxies:  proxies,
+		pool:     pool,
 	}
+	if cfg.StreamThreshold > 0 {
+		client.streamClient = newHTTPClient(cfg, proxies, pool, readTimeout, true)
+	}
+
+	return client
+}
 
-	// Initialize pools
-	client.reqPool = sync.Pool{
-		New: func() interface{} {
-			return fasthttp.AcquireRequest()
-		},
+// newHTTPClient creates the upstream HTTP client; stream leaves large
+// response bodies on the connection until read
+func newHTTPClient(cfg *config.PolymarketConfig, proxies *ProxyDialer, pool *connPool, readTimeout time.Duration, stream bool) *fasthttp.Client {
+	client := &fasthttp.Client{
+		Name:                     "PolyGo/1.0",
+		MaxConnsPerHost:          cfg.MaxConnsPerHost,
+		MaxConnWaitTimeout:       cfg.MaxConnWait,
+		MaxIdleConnDuration:      cfg.MaxIdleConnDur,
+		ReadTimeout:              readTimeout,
+		WriteTimeout:             cfg.WriteTimeout,
+		NoDefaultUserAgentHeader: true,
+		DisableHeaderNamesNormalizing: true,
+		DisablePathNormalizing:   true,
+		StreamResponseBody:       stream,
 	}
-	client.respPool = sync.Pool{
-		New: func() interface{} {
-			return fasthttp.AcquireResponse()
-		},
+	// Resolves through the DNS cache and races addresses, directly or
+	// through the proxies
+	client.Dial = pool.dial(proxies.Dial)
+	client.ConfigureClient = pool.configure(stream)
+	return client
+}
+
+// Proxies returns the dialer of the configured upstream proxies
+func (c *Client) Proxies() *ProxyDialer {
+	return c.proxies
+}
+
+// Warmup resolves each upstream API host and opens WarmupConns
+// connections to it, so the first requests skip DNS and TLS handshakes.
+// It returns the first failure.
+func (c *Client) Warmup() error {
+	n := c.config.WarmupConns
+	if n <= 0 || (c.tape != nil && c.tape.Replaying()) {
+		return nil
 	}
 
-	return client
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	for _, base := range []string{c.clobURL, c.gammaURL, c.dataURL} {
+		if base == "" || seen[base] {
+			continue
+		}
+		seen[base] = true
+		// Concurrent requests each take a connection of their own
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(base string) {
+				defer wg.Done()
+				req := c.acquireRequest()
+				resp := c.acquireResponse()
+				defer c.releaseRequest(req)
+				defer c.releaseResponse(resp)
+
+				req.SetRequestURI(base + "/")
+				req.Header.SetMethod(fasthttp.MethodHead)
+				resp.SkipBody = true
+				if err := c.httpClient.DoTimeout(req, resp, c.config.ReadTimeout); err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("warmup %s: %w", base, err)
+					}
+					mu.Unlock()
+				}
+			}(base)
+		}
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// SetTape records upstream responses to t, or serves them from it in
+// replay mode
+func (c *Client) SetTape(t *tape.Tape) {
+	c.tape = t
+}
+
+// ErrOverloaded is returned for a cached read that missed the cache while
+// the server sheds load
+var ErrOverloaded = errors.New("overloaded: read not in cache")
+
+// SetShedder makes cached reads that miss the cache fail with ErrOverloaded
+// while shed returns true, so requests the cache can answer keep being
+// served under load
+func (c *Client) SetShedder(shed func() bool) {
+	c.shed = shed
 }
 
 // acquireRequest gets a request from pool
@@ -82,24 +200,126 @@ func (c *Client) releaseResponse(resp *fasthttp.Response) {
 	fasthttp.ReleaseResponse(resp)
 }
 
+// StatusError is returned when upstream answers with a non-retryable status
+type StatusError struct {
+	StatusCode int
+	Body       []byte
+	RetryAfter time.Duration // upstream's Retry-After, 0 when absent
+}
+
+// Error implements the error interface
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
 // RequestOptions holds options for HTTP requests
 type RequestOptions struct {
 	Headers map[string]string
 	Timeout time.Duration
 }
 
+// TTLHeader is set by a primary PolyGo instance on replica responses to
+// tell replicas how long the payload stays fresh
+const TTLHeader = "X-PolyGo-TTL-Ms"
+
 // doRequest performs an HTTP request with retry logic
 func (c *Client) doRequest(method, url string, body []byte, opts *RequestOptions) ([]byte, error) {
-	req := c.acquireRequest()
+	data, _, err := c.doRequestWithTTL(method, url, body, opts)
+	return data, err
+}
+
+// doRequestWithTTL performs an HTTP request and also returns the TTL
+// advertised by the upstream through TTLHeader, or 0 if none was sent.
+// Responses go through the tape when record/replay is on.
+func (c *Client) doRequestWithTTL(method, url string, body []byte, opts *RequestOptions) ([]byte, time.Duration, error) {
+	if c.tape.Replaying() {
+		entry, err := c.tape.Load(method, url, body)
+		if err != nil {
+			return nil, 0, err
+		}
+		if entry.Status >= 300 {
+			return nil, 0, &StatusError{StatusCode: entry.Status, Body: []byte(entry.Body)}
+		}
+		return []byte(entry.Body), entry.TTL(), nil
+	}
+
+	done := latency.StartUpstream()
+	data, ttl, err := c.doUpstream(method, url, body, opts)
+	done()
+	if err == nil {
+		if err := c.validate(url, data); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if c.tape.Recording() {
+		var statusErr *StatusError
+		switch {
+		case err == nil:
+			if err := c.tape.Save(method, url, body, fasthttp.StatusOK, ttl, data); err != nil {
+				log.Printf("Failed to record %s %s: %v", method, url, err)
+			}
+		case errors.As(err, &statusErr):
+			if err := c.tape.Save(method, url, body, statusErr.StatusCode, 0, statusErr.Body); err != nil {
+				log.Printf("Failed to record %s %s: %v", method, url, err)
+			}
+		}
+	}
+	return data, ttl, err
+}
+
+// validate checks an upstream payload for drift from the models
+func (c *Client) validate(url string, data []byte) error {
+	if c.drift == nil {
+		return nil
+	}
+	upstream, path := c.upstreamPath(url)
+	return c.drift.check(upstream, path, data)
+}
+
+// doUpstream performs an HTTP request against the upstream. Network
+// errors, 5xx and 429 responses are retried for retryable methods while
+// the retry budget allows.
+func (c *Client) doUpstream(method, url string, body []byte, opts *RequestOptions) ([]byte, time.Duration, error) {
 	resp := c.acquireResponse()
-	defer c.releaseRequest(req)
 	defer c.releaseResponse(resp)
 
-	req.SetRequestURI(url)
+	if err := c.roundTrip(c.httpClient, method, url, body, opts, resp); err != nil {
+		return nil, 0, err
+	}
+
+	// Take the body over instead of copying it; resp gets a new buffer
+	// when reused
+	result := resp.SwapBody(nil)
+
+	var ttl time.Duration
+	if ms, err := strconv.ParseInt(string(resp.Header.Peek(TTLHeader)), 10, 64); err == nil && ms > 0 {
+		ttl = time.Duration(ms) * time.Millisecond
+	}
+	return result, ttl, nil
+}
+
+// roundTrip sends a request with hc, retrying as doUpstream describes,
+// and leaves the successful response in resp. Requests to an API with
+// mirrors go to the URL in use, and report to its health.
+func (c *Client) roundTrip(hc *fasthttp.Client, method, url string, body []byte, opts *RequestOptions, resp *fasthttp.Response) (err error) {
+	req := c.acquireRequest()
+	defer c.releaseRequest(req)
+
+	target, api, mirror := c.failover.route(url)
+	if api != nil {
+		defer func() { api.report(mirror, err, c.failover.threshold) }()
+	}
+
+	req.SetRequestURI(target)
 	req.Header.SetMethod(method)
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
 
+	for k, v := range c.config.ExtraHeaders {
+		req.Header.Set(k, v)
+	}
+
 	if opts != nil {
 		for k, v := range opts.Headers {
 			req.Header.Set(k, v)
@@ -110,41 +330,114 @@ func (c *Client) doRequest(method, url string, body []byte, opts *RequestOptions
 		req.SetBody(body)
 	}
 
-	timeout := c.config.ReadTimeout
+	timeout := c.timeoutFor(url)
 	if opts != nil && opts.Timeout > 0 {
 		timeout = opts.Timeout
 	}
 
+	retryable := c.retry.retryable(method)
+	c.retry.budget.deposit()
+	limiter := c.limiterFor(string(req.URI().Host()))
+
 	var lastErr error
-	for i := 0; i <= c.config.RetryCount; i++ {
-		if i > 0 {
-			time.Sleep(c.config.RetryWaitTime * time.Duration(i))
+	retries := 0
+	for {
+		if limiter != nil {
+			if err := limiter.wait(); err != nil {
+				return err
+			}
 		}
 
-		err := c.httpClient.DoTimeout(req, resp, timeout)
+		err := hc.DoTimeout(req, resp, timeout)
 		if err != nil {
 			lastErr = err
-			continue
+		} else {
+			statusCode := resp.StatusCode()
+			if statusCode >= 200 && statusCode < 300 {
+				if limiter != nil {
+					limiter.succeeded()
+				}
+				return nil
+			}
+
+			errBody := make([]byte, len(resp.Body()))
+			copy(errBody, resp.Body())
+			statusErr := &StatusError{StatusCode: statusCode, Body: errBody, RetryAfter: retryAfter(resp)}
+
+			switch {
+			case statusCode == fasthttp.StatusTooManyRequests:
+				// Rate limited: wait as long as asked, unless that is longer
+				// than we are willing to hold the caller
+				if limiter != nil {
+					limiter.throttled()
+				}
+				wait := statusErr.RetryAfter
+				if !retryable || wait > c.retry.maxWait || retries >= c.retry.maxRetries || !c.retry.budget.withdraw() {
+					return statusErr
+				}
+				retries++
+				if wait == 0 {
+					wait = c.retry.backoff(retries)
+				}
+				time.Sleep(wait)
+				continue
+			case statusCode >= 500:
+				lastErr = statusErr
+			default:
+				// Client error, don't retry
+				return statusErr
+			}
 		}
 
-		statusCode := resp.StatusCode()
-		if statusCode >= 200 && statusCode < 300 {
-			// Make a copy of the body
-			result := make([]byte, len(resp.Body()))
-			copy(result, resp.Body())
-			return result, nil
+		if !retryable || retries >= c.retry.maxRetries || !c.retry.budget.withdraw() {
+			break
 		}
+		retries++
+		time.Sleep(c.retry.backoff(retries))
+	}
 
-		if statusCode >= 500 {
-			lastErr = fmt.Errorf("server error: %d", statusCode)
-			continue
+	return fmt.Errorf("request failed after %d retries: %w", retries, lastErr)
+}
+
+// upstreamPath splits an upstream URL into the API it belongs to and the
+// path below that API's base URL, so matching also works when the base URL
+// has a path of its own (e.g. a replica's primary). URLs outside the
+// configured APIs return an empty upstream and their full path.
+func (c *Client) upstreamPath(url string) (string, string) {
+	for _, api := range [...]struct{ name, base string }{
+		{UpstreamClob, c.clobURL},
+		{UpstreamGamma, c.gammaURL},
+		{UpstreamData, c.dataURL},
+	} {
+		if api.base != "" && strings.HasPrefix(url, api.base) {
+			path := url[len(api.base):]
+			if i := strings.IndexAny(path, "?#"); i >= 0 {
+				path = path[:i]
+			}
+			return api.name, path
 		}
+	}
 
-		// Client error, don't retry
-		return nil, fmt.Errorf("request failed with status %d: %s", statusCode, resp.Body())
+	var uri fasthttp.URI
+	if err := uri.Parse(nil, []byte(url)); err == nil {
+		return "", string(uri.Path())
 	}
+	return "", ""
+}
 
-	return nil, fmt.Errorf("request failed after %d retries: %v", c.config.RetryCount, lastErr)
+// timeoutFor returns the request timeout for an upstream URL. Prefixes
+// match the path below the API base URL.
+func (c *Client) timeoutFor(url string) time.Duration {
+	_, path := c.upstreamPath(url)
+
+	timeout := c.config.ReadTimeout
+	longest := -1
+	for prefix, t := range c.config.Timeouts {
+		if len(prefix) > longest && strings.HasPrefix(path, prefix) {
+			timeout, longest = t, len(prefix)
+		}
+	}
+	return timeout
 }
 
 // Get performs a GET request
@@ -154,23 +447,58 @@ func (c *Client) Get(url string, opts *RequestOptions) ([]byte, error) {
 
 // GetWithCache performs a GET request with caching
 func (c *Client) GetWithCache(url, cacheKey string, ttl time.Duration) ([]byte, bool, error) {
-	// Check cache first
-	if data, found := c.cache.Get(cacheKey); found {
+	// Check cache first; a stale entry is served while one caller refreshes it
+	data, fresh, found := c.cache.GetStale(cacheKey)
+	if found && (fresh || !c.cache.BeginRefresh(cacheKey)) {
 		return data, true, nil
 	}
+	stale := data
+	if found {
+		defer c.cache.EndRefresh(cacheKey)
+	} else if c.shed != nil && c.shed() {
+		return nil, false, ErrOverloaded
+	}
 
-	// Fetch from API
-	data, err := c.Get(url, nil)
+	// Concurrent misses of a key share one upstream request
+	data, err := c.flights.do(cacheKey, func() ([]byte, error) {
+		data, upstreamTTL, err := c.doRequestWithTTL("GET", url, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		// Replicas follow the freshness advertised by their primary
+		if c.config.HonorCacheHeaders && upstreamTTL > 0 {
+			ttl = upstreamTTL
+		}
+
+		// Store in cache; data is ours and only ever read from here on
+		c.cache.SetNoCopy(cacheKey, data, ttl)
+		return data, nil
+	})
 	if err != nil {
+		// A failed refresh keeps serving what we had
+		if found {
+			return stale, true, nil
+		}
 		return nil, false, err
 	}
 
-	// Store in cache
-	c.cache.Set(cacheKey, data, ttl)
-
 	return data, false, nil
 }
 
+// RefreshCache fetches url unconditionally and stores the result under cacheKey
+func (c *Client) RefreshCache(url, cacheKey string, ttl time.Duration) ([]byte, error) {
+	data, err := c.Get(url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if ttl > 0 {
+		c.cache.SetNoCopy(cacheKey, data, ttl)
+	}
+	return data, nil
+}
+
 // Post performs a POST request
 func (c *Client) Post(url string, body []byte, opts *RequestOptions) ([]byte, error) {
 	return c.doRequest("POST", url, body, opts)
@@ -181,6 +509,11 @@ func (c *Client) Delete(url string, opts *RequestOptions) ([]byte, error) {
 	return c.doRequest("DELETE", url, nil, opts)
 }
 
+// Do performs a request with an arbitrary method
+func (c *Client) Do(method, url string, body []byte, opts *RequestOptions) ([]byte, error) {
+	return c.doRequest(method, url, body, opts)
+}
+
 // GetJSON performs a GET request and unmarshals the response
 func (c *Client) GetJSON(url string, dest interface{}, opts *RequestOptions) error {
 	data, err := c.Get(url, opts)
@@ -226,4 +559,7 @@ func (c *Client) Data(path string) string {
 // Close cleans up client resources
 func (c *Client) Close() {
 	c.httpClient.CloseIdleConnections()
+	if c.streamClient != nil {
+		c.streamClient.CloseIdleConnections()
+	}
 }
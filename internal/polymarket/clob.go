@@ -119,7 +119,7 @@ type OrderRequest struct {
 func (c *ClobClient) CreateOrder(order *models.CreateOrderRequest, authHeaders map[string]string) ([]byte, error) {
 	url := c.client.CLOB("/order")
 	
-	body, err := sonic.Marshal(order)
+	body, err := sonic.Marshal(upstreamOrder(*order))
 	if err != nil {
 		return nil, err
 	}
@@ -127,6 +127,29 @@ func (c *ClobClient) CreateOrder(order *models.CreateOrderRequest, authHeaders m
 	return c.client.Post(url, body, &RequestOptions{Headers: authHeaders})
 }
 
+// CreateOrders places a batch of orders in one request (requires authentication)
+func (c *ClobClient) CreateOrders(orders []models.CreateOrderRequest, authHeaders map[string]string) ([]byte, error) {
+	url := c.client.CLOB("/orders")
+	
+	upstream := make([]models.CreateOrderRequest, len(orders))
+	for i, o := range orders {
+		upstream[i] = upstreamOrder(o)
+	}
+	body, err := sonic.Marshal(upstream)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.client.Post(url, body, &RequestOptions{Headers: authHeaders})
+}
+
+// upstreamOrder drops the fields of an order that are PolyGo's own
+func upstreamOrder(o models.CreateOrderRequest) models.CreateOrderRequest {
+	o.Strategy = ""
+	o.PriceMode = ""
+	return o
+}
+
 // CancelOrder cancels an existing order (requires authentication)
 func (c *ClobClient) CancelOrder(orderID string, authHeaders map[string]string) ([]byte, error) {
 	url := c.client.CLOB("/order/" + orderID)
@@ -155,6 +178,13 @@ func (c *ClobClient) CancelAll(marketID string, authHeaders map[string]string) (
 	return c.client.Delete(url, &RequestOptions{Headers: authHeaders})
 }
 
+// CancelOpenOrders cancels every open order of the authenticated user
+// (requires authentication)
+func (c *ClobClient) CancelOpenOrders(authHeaders map[string]string) ([]byte, error) {
+	url := c.client.CLOB("/cancel-all")
+	return c.client.Delete(url, &RequestOptions{Headers: authHeaders})
+}
+
 // GetOrders retrieves orders for the authenticated user
 func (c *ClobClient) GetOrders(params map[string]string, authHeaders map[string]string) ([]byte, error) {
 	query := url.Values{}
@@ -185,13 +215,33 @@ func (c *ClobClient) GetOpenOrders(market string, authHeaders map[string]string)
 	return c.client.Get(url, &RequestOptions{Headers: authHeaders})
 }
 
+// GetBalanceAllowance retrieves the user's balance and exchange allowances
+// for collateral (USDC) or, with a token ID, a conditional token (requires
+// authentication)
+func (c *ClobClient) GetBalanceAllowance(assetType, tokenID, signatureType string, authHeaders map[string]string) ([]byte, error) {
+	query := url.Values{}
+	query.Set("asset_type", assetType)
+	if tokenID != "" {
+		query.Set("token_id", tokenID)
+	}
+	if signatureType != "" {
+		query.Set("signature_type", signatureType)
+	}
+	
+	u := c.client.CLOB("/balance-allowance?" + query.Encode())
+	return c.client.Get(u, &RequestOptions{Headers: authHeaders})
+}
+
 // GetTradesHistory retrieves trade history
-func (c *ClobClient) GetTradesHistory(tokenID string, limit int, before, after string) ([]byte, error) {
+func (c *ClobClient) GetTradesHistory(tokenID string, limit int, cursor, before, after string) ([]byte, error) {
 	query := url.Values{}
 	query.Set("token_id", tokenID)
 	if limit > 0 {
 		query.Set("limit", strconv.Itoa(limit))
 	}
+	if cursor != "" {
+		query.Set("next_cursor", cursor)
+	}
 	if before != "" {
 		query.Set("before", before)
 	}
@@ -216,13 +266,28 @@ func (c *ClobClient) GetMarketTradesHistory(conditionID string, limit int) ([]by
 }
 
 // GetTickSize retrieves tick size for a token
-func (c *ClobClient) GetTickSize(tokenID string) ([]byte, error) {
+func (c *ClobClient) GetTickSize(tokenID string) ([]byte, bool, error) {
+	cacheKey := cache.PriceKey("tick:" + tokenID)
 	url := c.client.CLOB("/tick-size?token_id=" + tokenID)
-	return c.client.Get(url, nil)
+
+	ttl := c.client.cache.GetConfig().DefaultTTL
+	return c.client.GetWithCache(url, cacheKey, ttl)
 }
 
 // GetNegRisk retrieves neg risk info for a token
-func (c *ClobClient) GetNegRisk(tokenID string) ([]byte, error) {
+func (c *ClobClient) GetNegRisk(tokenID string) ([]byte, bool, error) {
+	cacheKey := cache.PriceKey("negrisk:" + tokenID)
 	url := c.client.CLOB("/neg-risk?token_id=" + tokenID)
-	return c.client.Get(url, nil)
+
+	ttl := c.client.cache.GetConfig().DefaultTTL
+	return c.client.GetWithCache(url, cacheKey, ttl)
+}
+
+// GetFeeRate retrieves the base fee rate, in basis points, for a token
+func (c *ClobClient) GetFeeRate(tokenID string) ([]byte, bool, error) {
+	cacheKey := cache.PriceKey("fee:" + tokenID)
+	url := c.client.CLOB("/fee-rate?token_id=" + tokenID)
+
+	ttl := c.client.cache.GetConfig().DefaultTTL
+	return c.client.GetWithCache(url, cacheKey, ttl)
 }
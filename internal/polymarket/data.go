@@ -3,60 +3,121 @@ package polymarket
 import (
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/polygo/internal/cache"
 )
 
 // DataClient handles Data API requests (positions, trades, activity)
 type DataClient struct {
 	client *Client
+
+	// namespace keeps a tenant's cached user data apart from everyone
+	// else's (empty = the shared cache)
+	namespace string
+	gens      *generations
+}
+
+// generations are per-address cache generations; bumping one orphans every
+// cached user-data response for that address, in every namespace
+type generations struct {
+	mu   sync.Mutex
+	gens map[string]uint64
 }
 
 // NewDataClient creates a new Data client
 func NewDataClient(client *Client) *DataClient {
-	return &DataClient{client: client}
+	return &DataClient{
+		client: client,
+		gens:   &generations{gens: make(map[string]uint64)},
+	}
+}
+
+// Namespace returns a view of the client whose cached user data is only
+// shared with other views of the same namespace. Invalidations apply to
+// every namespace.
+func (d *DataClient) Namespace(namespace string) *DataClient {
+	return &DataClient{client: d.client, namespace: namespace, gens: d.gens}
+}
+
+// InvalidateUser drops cached positions, trades and activity for an address
+func (d *DataClient) InvalidateUser(address string) {
+	address = strings.ToLower(address)
+
+	d.gens.mu.Lock()
+	d.gens.gens[address]++
+	d.gens.mu.Unlock()
+}
+
+// generation returns the current cache generation of a lowercased address
+func (d *DataClient) generation(address string) uint64 {
+	d.gens.mu.Lock()
+	defer d.gens.mu.Unlock()
+	return d.gens.gens[address]
+}
+
+// userDataKey returns the cache key of an address's data in the client's
+// namespace
+func (d *DataClient) userDataKey(address, params string) string {
+	gen := d.generation(address)
+	if d.namespace != "" {
+		return cache.NamespacedUserDataKey(d.namespace, address, gen, params)
+	}
+	return cache.UserDataKey(address, gen, params)
+}
+
+// getUserData fetches per-user data with short-TTL caching keyed by
+// (address, params). fresh skips the cached copy but still refreshes it.
+func (d *DataClient) getUserData(address, path string, query url.Values, fresh bool) ([]byte, bool, error) {
+	address = strings.ToLower(address)
+
+	u := d.client.Data(path + "?" + query.Encode())
+	cacheKey := d.userDataKey(address, path+"?"+query.Encode())
+	ttl := d.client.cache.GetConfig().UserDataTTL
+
+	if fresh || ttl <= 0 {
+		data, err := d.client.RefreshCache(u, cacheKey, ttl)
+		return data, false, err
+	}
+	return d.client.GetWithCache(u, cacheKey, ttl)
 }
 
 // GetPositions retrieves user positions
-func (d *DataClient) GetPositions(address string, limit int, cursor string) ([]byte, error) {
+func (d *DataClient) GetPositions(address string, limit int, cursor string, fresh bool) ([]byte, bool, error) {
 	query := url.Values{}
 	query.Set("user", address)
 	if limit > 0 {
 		query.Set("limit", strconv.Itoa(limit))
 	}
-	if cursor != "" {
-		query.Set("next_cursor", cursor)
-	}
+	SetCursor(query, cursor)
 
-	u := d.client.Data("/positions?" + query.Encode())
-	return d.client.Get(u, nil)
+	return d.getUserData(address, "/positions", query, fresh)
 }
 
 // GetPositionsByMarket retrieves positions for a specific market
-func (d *DataClient) GetPositionsByMarket(address, marketID string) ([]byte, error) {
+func (d *DataClient) GetPositionsByMarket(address, marketID string, fresh bool) ([]byte, bool, error) {
 	query := url.Values{}
 	query.Set("user", address)
 	query.Set("market", marketID)
 
-	u := d.client.Data("/positions?" + query.Encode())
-	return d.client.Get(u, nil)
+	return d.getUserData(address, "/positions", query, fresh)
 }
 
 // GetTrades retrieves user trades
-func (d *DataClient) GetTrades(address string, limit int, cursor string) ([]byte, error) {
+func (d *DataClient) GetTrades(address string, limit int, cursor string, fresh bool) ([]byte, bool, error) {
 	query := url.Values{}
 	query.Set("user", address)
 	if limit > 0 {
 		query.Set("limit", strconv.Itoa(limit))
 	}
-	if cursor != "" {
-		query.Set("next_cursor", cursor)
-	}
+	SetCursor(query, cursor)
 
-	u := d.client.Data("/trades?" + query.Encode())
-	return d.client.Get(u, nil)
+	return d.getUserData(address, "/trades", query, fresh)
 }
 
 // GetTradesByMarket retrieves trades for a specific market
-func (d *DataClient) GetTradesByMarket(address, marketID string, limit int) ([]byte, error) {
+func (d *DataClient) GetTradesByMarket(address, marketID string, limit int, fresh bool) ([]byte, bool, error) {
 	query := url.Values{}
 	query.Set("user", address)
 	query.Set("market", marketID)
@@ -64,23 +125,19 @@ func (d *DataClient) GetTradesByMarket(address, marketID string, limit int) ([]b
 		query.Set("limit", strconv.Itoa(limit))
 	}
 
-	u := d.client.Data("/trades?" + query.Encode())
-	return d.client.Get(u, nil)
+	return d.getUserData(address, "/trades", query, fresh)
 }
 
 // GetActivity retrieves user activity
-func (d *DataClient) GetActivity(address string, limit int, cursor string) ([]byte, error) {
+func (d *DataClient) GetActivity(address string, limit int, cursor string, fresh bool) ([]byte, bool, error) {
 	query := url.Values{}
 	query.Set("user", address)
 	if limit > 0 {
 		query.Set("limit", strconv.Itoa(limit))
 	}
-	if cursor != "" {
-		query.Set("next_cursor", cursor)
-	}
+	SetCursor(query, cursor)
 
-	u := d.client.Data("/activity?" + query.Encode())
-	return d.client.Get(u, nil)
+	return d.getUserData(address, "/activity", query, fresh)
 }
 
 // GetMarketTrades retrieves public trades for a market (no auth required)
@@ -90,8 +147,20 @@ func (d *DataClient) GetMarketTrades(marketID string, limit int, cursor string)
 	if limit > 0 {
 		query.Set("limit", strconv.Itoa(limit))
 	}
-	if cursor != "" {
-		query.Set("next_cursor", cursor)
+	SetCursor(query, cursor)
+
+	u := d.client.Data("/trades?" + query.Encode())
+	return d.client.Get(u, nil)
+}
+
+// GetRecentTrades retrieves the most recent public trades across all markets
+func (d *DataClient) GetRecentTrades(limit, offset int) ([]byte, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if offset > 0 {
+		query.Set("offset", strconv.Itoa(offset))
 	}
 
 	u := d.client.Data("/trades?" + query.Encode())
@@ -100,6 +169,16 @@ func (d *DataClient) GetMarketTrades(marketID string, limit int, cursor string)
 
 // GetPriceHistory retrieves price history for a market
 func (d *DataClient) GetPriceHistory(tokenID string, interval string, fidelity int) ([]byte, error) {
+	return d.client.Get(d.priceHistoryURL(tokenID, interval, fidelity), nil)
+}
+
+// StreamPriceHistory is GetPriceHistory streaming histories larger than
+// StreamThreshold, such as interval=max at a fine fidelity
+func (d *DataClient) StreamPriceHistory(tokenID string, interval string, fidelity int) ([]byte, *Stream, error) {
+	return d.client.GetStream(d.priceHistoryURL(tokenID, interval, fidelity), nil)
+}
+
+func (d *DataClient) priceHistoryURL(tokenID string, interval string, fidelity int) string {
 	query := url.Values{}
 	query.Set("clob_token_id", tokenID)
 	if interval != "" {
@@ -108,9 +187,7 @@ func (d *DataClient) GetPriceHistory(tokenID string, interval string, fidelity i
 	if fidelity > 0 {
 		query.Set("fidelity", strconv.Itoa(fidelity))
 	}
-
-	u := d.client.Data("/prices-history?" + query.Encode())
-	return d.client.Get(u, nil)
+	return d.client.Data("/prices-history?" + query.Encode())
 }
 
 // GetTimeseriesData retrieves timeseries data for a market
@@ -0,0 +1,113 @@
+package polymarket
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/latency"
+	"github.com/polygo/internal/models"
+)
+
+// recentTradesLimit is how many trades a market detail includes
+const recentTradesLimit = 20
+
+// MarketDetail is everything a market page needs in one response
+type MarketDetail struct {
+	Market       json.RawMessage   `json:"market"`
+	Tokens       []OutcomeSnapshot `json:"tokens"`
+	Volume24hr   float64           `json:"volume_24hr"`
+	RecentTrades json.RawMessage   `json:"recent_trades"`
+	Errors       map[string]string `json:"errors,omitempty"` // partial failures by section
+}
+
+// OutcomeSnapshot is a token snapshot labelled with its outcome
+type OutcomeSnapshot struct {
+	Outcome string `json:"outcome"`
+	TokenSnapshot
+}
+
+// MarketDetailService composes the Gamma record, order book summaries and
+// recent trades of a market, caching the result for CompositeTTL
+type MarketDetailService struct {
+	gamma     *GammaClient
+	data      *DataClient
+	snapshots *SnapshotService
+}
+
+// NewMarketDetailService creates a new market detail service
+func NewMarketDetailService(gamma *GammaClient, data *DataClient, snapshots *SnapshotService) *MarketDetailService {
+	return &MarketDetailService{gamma: gamma, data: data, snapshots: snapshots}
+}
+
+// Get returns the composite detail for a market. The bool reports a cache
+// hit. A nil detail with nil error means the market does not exist.
+func (s *MarketDetailService) Get(id string) (*MarketDetail, bool, error) {
+	c := s.gamma.client.cache
+	key := cache.MarketDetailKey(id)
+
+	var cached MarketDetail
+	if c.GetJSON(key, &cached) {
+		return &cached, true, nil
+	}
+
+	raw, _, err := s.gamma.GetMarket(id)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, false, nil
+	}
+
+	var market models.Market
+	if err := sonic.Unmarshal(raw, &market); err != nil {
+		return nil, false, err
+	}
+
+	detail := &MarketDetail{
+		Market:     raw,
+		Volume24hr: market.Volume24hr.Float(),
+		Errors:     make(map[string]string),
+	}
+
+	// Book-derived data and trades are independent; fetch them together
+	var wg sync.WaitGroup
+	var snaps []TokenSnapshot
+	bind := latency.Inherit()
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer bind()()
+		snaps = s.snapshots.Get(market.ClobTokenIDs)
+	}()
+	go func() {
+		defer wg.Done()
+		defer bind()()
+		trades, err := s.data.GetMarketTrades(market.ConditionID, recentTradesLimit, "")
+		if err != nil {
+			detail.Errors["recent_trades"] = err.Error()
+			return
+		}
+		detail.RecentTrades = trades
+	}()
+	wg.Wait()
+
+	detail.Tokens = make([]OutcomeSnapshot, len(snaps))
+	for i, snap := range snaps {
+		detail.Tokens[i] = OutcomeSnapshot{TokenSnapshot: snap}
+		if i < len(market.Outcomes) {
+			detail.Tokens[i].Outcome = market.Outcomes[i]
+		}
+		if snap.Error != "" {
+			detail.Errors["token:"+snap.TokenID] = snap.Error
+		}
+	}
+
+	// Only complete compositions are cached, so a transient failure is not
+	// served for the whole TTL
+	if len(detail.Errors) == 0 {
+		c.SetJSON(key, detail, c.GetConfig().CompositeTTL)
+	}
+	return detail, false, nil
+}
@@ -0,0 +1,289 @@
+package polymarket
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/polygo/internal/config"
+)
+
+// defaultDialTimeout applies when DialTimeout is unset
+const defaultDialTimeout = 5 * time.Second
+
+// Dialer connects to upstream hosts directly. Host names are resolved
+// through a cache, so a slow or failing resolver costs a background
+// refresh rather than a request, and a host's addresses are raced (happy
+// eyeballs, RFC 8305) so one dead address or address family costs a
+// fraction of a second rather than a dial timeout.
+type Dialer struct {
+	timeout time.Duration
+	ttl     time.Duration
+	delay   time.Duration
+	lookup  func(ctx context.Context, host string) ([]net.IP, error)
+
+	mu    sync.Mutex
+	hosts map[string]*dnsEntry
+}
+
+// dnsEntry is a cached resolution of a host
+type dnsEntry struct {
+	ips        []net.IP // the address that last connected first
+	expires    time.Time
+	refreshing bool
+}
+
+// NewDialer creates the upstream dialer of the config
+func NewDialer(cfg *config.PolymarketConfig) *Dialer {
+	timeout := cfg.DialTimeout
+	if timeout <= 0 {
+		timeout = defaultDialTimeout
+	}
+	return &Dialer{
+		timeout: timeout,
+		ttl:     cfg.DNSCacheTTL,
+		delay:   cfg.HappyEyeballsDelay,
+		lookup: func(ctx context.Context, host string) ([]net.IP, error) {
+			return net.DefaultResolver.LookupIP(ctx, "ip", host)
+		},
+		hosts: make(map[string]*dnsEntry),
+	}
+}
+
+// SetLookup replaces the resolver, for tests
+func (d *Dialer) SetLookup(lookup func(ctx context.Context, host string) ([]net.IP, error)) {
+	d.lookup = lookup
+}
+
+// Dial connects to addr (host:port) within the dial timeout, for fasthttp
+func (d *Dialer) Dial(addr string) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+	return d.DialContext(ctx, "tcp", addr)
+}
+
+// DialContext connects to addr (host:port), trying the host's addresses
+// in turn, each with a head start of HappyEyeballsDelay over the next
+func (d *Dialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+
+	ips, err := d.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	ips = filterFamily(network, ips)
+	if len(ips) == 0 {
+		return nil, &net.AddrError{Err: "no suitable address", Addr: host}
+	}
+
+	conn, winner, err := d.race(ctx, ips, port)
+	if err != nil {
+		// The addresses may be stale; resolve again next time
+		d.forget(host)
+		return nil, err
+	}
+	d.prefer(host, winner)
+	return conn, nil
+}
+
+// resolve returns a host's addresses, from the cache when it has them.
+// Expired entries are still used while a background lookup refreshes
+// them, and kept should that lookup fail.
+func (d *Dialer) resolve(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	if d.ttl <= 0 {
+		return d.lookup(ctx, host)
+	}
+
+	d.mu.Lock()
+	if e, ok := d.hosts[host]; ok {
+		if time.Now().After(e.expires) && !e.refreshing {
+			e.refreshing = true
+			go d.refresh(host)
+		}
+		ips := e.ips
+		d.mu.Unlock()
+		return ips, nil
+	}
+	d.mu.Unlock()
+
+	ips, err := d.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	d.store(host, ips)
+	return ips, nil
+}
+
+// refresh looks a cached host up again in the background
+func (d *Dialer) refresh(host string) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+
+	ips, err := d.lookup(ctx, host)
+	if err != nil || len(ips) == 0 {
+		d.mu.Lock()
+		if e, ok := d.hosts[host]; ok {
+			e.refreshing = false
+		}
+		d.mu.Unlock()
+		return
+	}
+	d.store(host, ips)
+}
+
+// store caches a host's addresses, keeping the one that last connected first
+func (d *Dialer) store(host string, ips []net.IP) {
+	ips = interleave(ips)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if e, ok := d.hosts[host]; ok && len(e.ips) > 0 {
+		ips = moveFirst(ips, e.ips[0])
+	}
+	d.hosts[host] = &dnsEntry{ips: ips, expires: time.Now().Add(d.ttl)}
+}
+
+// prefer moves the address that connected to the front of the host's
+// entry, so its family is tried first from then on
+func (d *Dialer) prefer(host string, ip net.IP) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if e, ok := d.hosts[host]; ok && !e.ips[0].Equal(ip) {
+		e.ips = moveFirst(e.ips, ip)
+	}
+}
+
+// forget drops a host's cached addresses
+func (d *Dialer) forget(host string) {
+	d.mu.Lock()
+	delete(d.hosts, host)
+	d.mu.Unlock()
+}
+
+// race dials ips in order, starting the next one when the current one
+// fails or has had HappyEyeballsDelay to connect, and returns the first
+// connection made; the others are closed
+func (d *Dialer) race(ctx context.Context, ips []net.IP, port string) (net.Conn, net.IP, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type attempt struct {
+		conn net.Conn
+		ip   net.IP
+		err  error
+	}
+	results := make(chan attempt, len(ips))
+	var nd net.Dialer
+	next, pending := 0, 0
+	start := func() {
+		ip := ips[next]
+		next++
+		pending++
+		go func() {
+			conn, err := nd.DialContext(ctx, "tcp", net.JoinHostPort(ip.String(), port))
+			results <- attempt{conn: conn, ip: ip, err: err}
+		}()
+	}
+
+	var headStart <-chan time.Time
+	wait := func() {
+		headStart = nil
+		if d.delay > 0 && next < len(ips) {
+			headStart = time.After(d.delay)
+		}
+	}
+
+	start()
+	wait()
+	var firstErr error
+	for pending > 0 {
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				// Close the connections still being made
+				go func(n int) {
+					for ; n > 0; n-- {
+						if late := <-results; late.conn != nil {
+							late.conn.Close()
+						}
+					}
+				}(pending)
+				return r.conn, r.ip, nil
+			}
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			if next < len(ips) {
+				start()
+				wait()
+			}
+		case <-headStart:
+			start()
+			wait()
+		}
+	}
+	return nil, nil, firstErr
+}
+
+// interleave orders addresses alternating between families, starting with
+// the family of the first (RFC 8305, section 4)
+func interleave(ips []net.IP) []net.IP {
+	var first, second []net.IP
+	for _, ip := range ips {
+		if (ip.To4() != nil) == (ips[0].To4() != nil) {
+			first = append(first, ip)
+		} else {
+			second = append(second, ip)
+		}
+	}
+	out := make([]net.IP, 0, len(ips))
+	for i := 0; i < len(first) || i < len(second); i++ {
+		if i < len(first) {
+			out = append(out, first[i])
+		}
+		if i < len(second) {
+			out = append(out, second[i])
+		}
+	}
+	return out
+}
+
+// moveFirst returns ips with ip moved to the front, when present
+func moveFirst(ips []net.IP, ip net.IP) []net.IP {
+	out := make([]net.IP, 0, len(ips))
+	for _, candidate := range ips {
+		if candidate.Equal(ip) {
+			out = append([]net.IP{candidate}, out...)
+		} else {
+			out = append(out, candidate)
+		}
+	}
+	return out
+}
+
+// filterFamily keeps the addresses network allows
+func filterFamily(network string, ips []net.IP) []net.IP {
+	if network != "tcp4" && network != "tcp6" {
+		return ips
+	}
+	var out []net.IP
+	for _, ip := range ips {
+		if (ip.To4() != nil) == (network == "tcp4") {
+			out = append(out, ip)
+		}
+	}
+	return out
+}
@@ -0,0 +1,255 @@
+package polymarket
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/models"
+)
+
+// ErrSchemaDrift is returned in strict validation mode for upstream
+// payloads that no longer match the models
+var ErrSchemaDrift = errors.New("upstream payload does not match the expected schema")
+
+// driftSampleSize caps how many items of a list payload are inspected
+const driftSampleSize = 20
+
+// schemaRule maps upstream responses to the model they decode into
+type schemaRule struct {
+	name     string // endpoint label used in reports, e.g. "gamma /markets"
+	upstream string
+	prefix   string // path prefix below the upstream base URL
+	model    reflect.Type
+	known    map[string]bool // every JSON field of the model
+	required []string        // JSON fields without omitempty
+}
+
+// schemaRules lists the upstream endpoints whose payloads are validated
+var schemaRules = []*schemaRule{
+	newSchemaRule(UpstreamGamma, "/markets", models.Market{}),
+	newSchemaRule(UpstreamGamma, "/events", models.Event{}),
+	newSchemaRule(UpstreamClob, "/book", models.OrderBook{}),
+	newSchemaRule(UpstreamClob, "/order/", models.Order{}),
+	newSchemaRule(UpstreamData, "/positions", models.Position{}),
+}
+
+// newSchemaRule collects the JSON fields of model
+func newSchemaRule(upstream, prefix string, model interface{}) *schemaRule {
+	r := &schemaRule{
+		name:     upstream + " " + prefix,
+		upstream: upstream,
+		prefix:   prefix,
+		model:    reflect.TypeOf(model),
+		known:    make(map[string]bool),
+	}
+	for i := 0; i < r.model.NumField(); i++ {
+		tag := r.model.Field(i).Tag.Get("json")
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		r.known[name] = true
+		if !strings.Contains(opts, "omitempty") {
+			r.required = append(r.required, name)
+		}
+	}
+	return r
+}
+
+// DriftReport summarizes schema drift seen on one upstream endpoint
+type DriftReport struct {
+	Endpoint      string           `json:"endpoint"`
+	Model         string           `json:"model"`
+	Checked       int64            `json:"checked"`                  // payloads inspected
+	Breaking      int64            `json:"breaking"`                 // payloads missing fields or with mismatched types
+	UnknownFields map[string]int64 `json:"unknown_fields,omitempty"` // fields the model does not have, by payloads seen in
+	MissingFields map[string]int64 `json:"missing_fields,omitempty"` // expected fields absent, by payloads seen in
+	LastError     string           `json:"last_error,omitempty"`     // most recent decode error
+	LastDrift     int64            `json:"last_drift_ms,omitempty"`  // unix ms of the last breaking payload
+}
+
+// driftDetector validates upstream payloads against schemaRules
+type driftDetector struct {
+	strict bool
+
+	mu      sync.Mutex
+	reports map[string]*DriftReport
+	logged  map[string]bool // drift already logged, so each finding logs once
+}
+
+// newDriftDetector returns nil when validation is off
+func newDriftDetector(cfg *config.PolymarketConfig) *driftDetector {
+	switch cfg.Validation {
+	case config.ValidationWarn, config.ValidationStrict:
+		return &driftDetector{
+			strict:  cfg.Validation == config.ValidationStrict,
+			reports: make(map[string]*DriftReport),
+			logged:  make(map[string]bool),
+		}
+	}
+	return nil
+}
+
+// rule returns the longest matching rule for an upstream path
+func (d *driftDetector) rule(upstream, path string) *schemaRule {
+	var match *schemaRule
+	for _, r := range schemaRules {
+		if r.upstream == upstream && strings.HasPrefix(path, r.prefix) &&
+			(match == nil || len(r.prefix) > len(match.prefix)) {
+			match = r
+		}
+	}
+	return match
+}
+
+// check inspects a successful response. In strict mode it returns
+// ErrSchemaDrift for breaking drift; unknown fields are only reported.
+func (d *driftDetector) check(upstream, path string, data []byte) error {
+	r := d.rule(upstream, path)
+	if r == nil {
+		return nil
+	}
+
+	var payload interface{}
+	if err := sonic.Unmarshal(data, &payload); err != nil || payload == nil {
+		// Not JSON, or null for an unknown ID; nothing to compare
+		return nil
+	}
+
+	var items []map[string]interface{}
+	target := reflect.New(r.model)
+	switch v := payload.(type) {
+	case map[string]interface{}:
+		items = append(items, v)
+	case []interface{}:
+		target = reflect.New(reflect.SliceOf(r.model))
+		for i := 0; i < len(v) && i < driftSampleSize; i++ {
+			if item, ok := v[i].(map[string]interface{}); ok {
+				items = append(items, item)
+			}
+		}
+	default:
+		return nil
+	}
+	decodeErr := sonic.Unmarshal(data, target.Interface())
+
+	unknown := make(map[string]bool)
+	missing := make(map[string]bool)
+	for _, item := range items {
+		for field := range item {
+			if !r.known[field] {
+				unknown[field] = true
+			}
+		}
+		for _, field := range r.required {
+			if _, ok := item[field]; !ok {
+				missing[field] = true
+			}
+		}
+	}
+
+	breaking := len(missing) > 0 || decodeErr != nil
+	d.record(r, unknown, missing, decodeErr, breaking)
+
+	if breaking && d.strict {
+		if decodeErr != nil {
+			return fmt.Errorf("%w: %s: %v", ErrSchemaDrift, r.name, decodeErr)
+		}
+		return fmt.Errorf("%w: %s: missing %s", ErrSchemaDrift, r.name, strings.Join(sortedKeys(missing), ", "))
+	}
+	return nil
+}
+
+// record updates the report for r and logs findings not logged before
+func (d *driftDetector) record(r *schemaRule, unknown, missing map[string]bool, decodeErr error, breaking bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	report, ok := d.reports[r.name]
+	if !ok {
+		report = &DriftReport{
+			Endpoint:      r.name,
+			Model:         r.model.String(),
+			UnknownFields: make(map[string]int64),
+			MissingFields: make(map[string]int64),
+		}
+		d.reports[r.name] = report
+	}
+
+	report.Checked++
+	if breaking {
+		report.Breaking++
+		report.LastDrift = time.Now().UnixMilli()
+	}
+	for field := range unknown {
+		report.UnknownFields[field]++
+		d.logOnce(r.name+" unknown "+field, "Schema drift on %s: new field %q not in %s", r.name, field, report.Model)
+	}
+	for field := range missing {
+		report.MissingFields[field]++
+		d.logOnce(r.name+" missing "+field, "Schema drift on %s: expected field %q of %s is missing", r.name, field, report.Model)
+	}
+	if decodeErr != nil {
+		report.LastError = decodeErr.Error()
+		d.logOnce(r.name+" decode "+report.LastError, "Schema drift on %s: payload no longer decodes into %s: %v", r.name, report.Model, decodeErr)
+	}
+}
+
+// logOnce logs a finding the first time it is seen; caller holds d.mu
+func (d *driftDetector) logOnce(key, format string, args ...interface{}) {
+	if d.logged[key] {
+		return
+	}
+	d.logged[key] = true
+	log.Printf(format, args...)
+}
+
+// snapshot copies the reports, sorted by endpoint
+func (d *driftDetector) snapshot() []DriftReport {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	out := make([]DriftReport, 0, len(d.reports))
+	for _, report := range d.reports {
+		cp := *report
+		cp.UnknownFields = copyCounts(report.UnknownFields)
+		cp.MissingFields = copyCounts(report.MissingFields)
+		out = append(out, cp)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Endpoint < out[j].Endpoint })
+	return out
+}
+
+func copyCounts(m map[string]int64) map[string]int64 {
+	out := make(map[string]int64, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+func sortedKeys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// SchemaDrift returns what payload validation has found so far, or nil
+// when validation is off
+func (c *Client) SchemaDrift() []DriftReport {
+	if c.drift == nil {
+		return nil
+	}
+	return c.drift.snapshot()
+}
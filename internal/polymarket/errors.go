@@ -0,0 +1,163 @@
+package polymarket
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/valyala/fasthttp"
+)
+
+// Error codes for upstream failures. Order rejections carry the CLOB's
+// own message in details.
+const (
+	CodeUpstreamRateLimited  = "UPSTREAM_RATE_LIMITED"               // 429: Polymarket rate limited PolyGo
+	CodeUpstreamBusy         = "UPSTREAM_BUSY"                       // 503: PolyGo's own upstream queue is full
+	CodeOverloaded           = "OVERLOADED"                          // 503: PolyGo sheds reads missing the cache under load
+	CodeUpstreamUnavailable  = "UPSTREAM_UNAVAILABLE"                // 502: 5xx or unreachable after retries
+	CodeUpstreamTimeout      = "UPSTREAM_TIMEOUT"                    // 504
+	CodeUpstreamUnauthorized = "UPSTREAM_UNAUTHORIZED"               // 401/403: credentials rejected by Polymarket
+	CodeUpstreamNotFound     = "NOT_FOUND"                           // 404
+	CodeUpstreamBadRequest   = "UPSTREAM_BAD_REQUEST"                // 400: any other 4xx
+	CodeMarketClosed         = "MARKET_CLOSED"                       // 409: the market no longer (or does not yet) accept orders
+	CodeInsufficientBalance  = "ORDER_REJECTED_INSUFFICIENT_BALANCE" // 422
+	CodeOrderInvalidPrice    = "ORDER_REJECTED_INVALID_PRICE"        // 400: breaks the tick size
+	CodeOrderMinSize         = "ORDER_REJECTED_MIN_SIZE"             // 400
+	CodeOrderInvalidExpiry   = "ORDER_REJECTED_INVALID_EXPIRATION"   // 400
+	CodeOrderDuplicate       = "ORDER_REJECTED_DUPLICATE"            // 409
+	CodeOrderNotFilled       = "ORDER_REJECTED_NOT_FILLED"           // 422: FOK order could not fill
+	CodeOrderRejected        = "ORDER_REJECTED"                      // 422: any other rejection
+)
+
+// maxDetails bounds the upstream message relayed in details
+const maxDetails = 500
+
+// APIError is an upstream failure translated for PolyGo's clients
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+	Details string // upstream's own message, when it gave one
+	// Retryable marks failures the same request may get past later, after
+	// RetryAfter when known
+	Retryable  bool
+	RetryAfter time.Duration
+}
+
+// busyRetryAfter is suggested when PolyGo's own upstream queue is full or
+// Polymarket rate limits without saying for how long
+const busyRetryAfter = time.Second
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.Details != "" {
+		return e.Message + ": " + e.Details
+	}
+	return e.Message
+}
+
+// rejections maps fragments of CLOB order rejection messages to codes,
+// checked in order
+var rejections = []struct {
+	fragments []string
+	status    int
+	code      string
+	message   string
+}{
+	{[]string{"not enough balance", "allowance"}, http.StatusUnprocessableEntity, CodeInsufficientBalance, "Order rejected: insufficient balance or allowance"},
+	{[]string{"market is closed", "market closed", "not accepting orders", "not yet ready", "orderbook", "does not exist"}, http.StatusConflict, CodeMarketClosed, "Market is not accepting orders"},
+	{[]string{"tick size"}, http.StatusBadRequest, CodeOrderInvalidPrice, "Order rejected: price breaks the tick size"},
+	{[]string{"lower than the minimum", "min size", "minimum size"}, http.StatusBadRequest, CodeOrderMinSize, "Order rejected: size is below the market minimum"},
+	{[]string{"expiration"}, http.StatusBadRequest, CodeOrderInvalidExpiry, "Order rejected: invalid expiration"},
+	{[]string{"duplicated", "duplicate"}, http.StatusConflict, CodeOrderDuplicate, "Order rejected: duplicate order"},
+	{[]string{"fok", "fully filled"}, http.StatusUnprocessableEntity, CodeOrderNotFilled, "Order rejected: FOK order could not be fully filled"},
+}
+
+// ClassifyRejection translates a CLOB order rejection message (a 4xx
+// body, or the errorMsg of an unsuccessful order) into an API error
+func ClassifyRejection(msg string) *APIError {
+	lower := strings.ToLower(msg)
+	for _, r := range rejections {
+		for _, f := range r.fragments {
+			if strings.Contains(lower, f) {
+				return &APIError{Status: r.status, Code: r.code, Message: r.message, Details: msg}
+			}
+		}
+	}
+	return &APIError{Status: http.StatusUnprocessableEntity, Code: CodeOrderRejected, Message: "Order rejected", Details: msg}
+}
+
+// ClassifyError translates an upstream failure into an API error, or nil
+// when err did not come from upstream
+func ClassifyError(err error) *APIError {
+	var apiErr *APIError
+	var statusErr *StatusError
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &apiErr):
+		return apiErr
+	case errors.Is(err, ErrOverloaded):
+		return &APIError{Status: http.StatusServiceUnavailable, Code: CodeOverloaded, Message: "Server is overloaded", Details: "Only cached reads are served until load drops", Retryable: true, RetryAfter: busyRetryAfter}
+	case errors.Is(err, ErrUpstreamBusy):
+		return &APIError{Status: http.StatusServiceUnavailable, Code: CodeUpstreamBusy, Message: "Upstream rate limit reached", Details: err.Error(), Retryable: true, RetryAfter: busyRetryAfter}
+	case errors.Is(err, fasthttp.ErrTimeout), errors.As(err, &netErr) && netErr.Timeout():
+		return &APIError{Status: http.StatusGatewayTimeout, Code: CodeUpstreamTimeout, Message: "Upstream request timed out", Retryable: true}
+	case errors.As(err, &statusErr):
+		return classifyStatus(statusErr)
+	case errors.As(err, &netErr), errors.Is(err, fasthttp.ErrConnectionClosed), errors.Is(err, fasthttp.ErrNoFreeConns):
+		return &APIError{Status: http.StatusBadGateway, Code: CodeUpstreamUnavailable, Message: "Upstream is unreachable", Details: err.Error(), Retryable: true}
+	}
+	return nil
+}
+
+// classifyStatus maps an upstream status, and for client errors its body
+func classifyStatus(e *StatusError) *APIError {
+	msg := upstreamMessage(e.Body)
+	switch {
+	case e.StatusCode == http.StatusTooManyRequests:
+		wait := e.RetryAfter
+		if wait == 0 {
+			wait = busyRetryAfter
+		}
+		return &APIError{Status: http.StatusTooManyRequests, Code: CodeUpstreamRateLimited, Message: "Polymarket rate limit reached, retry later", Details: msg, Retryable: true, RetryAfter: wait}
+	case e.StatusCode >= 500:
+		return &APIError{Status: http.StatusBadGateway, Code: CodeUpstreamUnavailable, Message: "Upstream is unavailable", Details: msg, Retryable: true, RetryAfter: e.RetryAfter}
+	case e.StatusCode == http.StatusUnauthorized, e.StatusCode == http.StatusForbidden:
+		return &APIError{Status: e.StatusCode, Code: CodeUpstreamUnauthorized, Message: "Polymarket rejected the credentials", Details: msg}
+	case e.StatusCode == http.StatusNotFound:
+		return &APIError{Status: http.StatusNotFound, Code: CodeUpstreamNotFound, Message: "Not found upstream", Details: msg}
+	}
+
+	if rejected := ClassifyRejection(msg); rejected.Code != CodeOrderRejected {
+		return rejected
+	}
+	return &APIError{Status: http.StatusBadRequest, Code: CodeUpstreamBadRequest, Message: "Upstream rejected the request", Details: msg}
+}
+
+// upstreamMessage reads the message of an upstream error body: the
+// error, errorMsg or message field of a JSON object, or the raw text
+func upstreamMessage(body []byte) string {
+	var obj struct {
+		Error    string `json:"error"`
+		ErrorMsg string `json:"errorMsg"`
+		Message  string `json:"message"`
+	}
+	msg := strings.TrimSpace(string(body))
+	if err := sonic.Unmarshal(body, &obj); err == nil {
+		for _, m := range []string{obj.Error, obj.ErrorMsg, obj.Message} {
+			if m != "" {
+				msg = m
+				break
+			}
+		}
+	}
+	if len(msg) > maxDetails {
+		msg = msg[:maxDetails] + "..."
+	}
+	return msg
+}
@@ -0,0 +1,286 @@
+package polymarket
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polygo/internal/config"
+	"github.com/valyala/fasthttp"
+)
+
+// FailoverStatus is where requests to an upstream API with mirrors go
+type FailoverStatus struct {
+	Name       string         `json:"name"`
+	Active     string         `json:"active"`      // URL requests go to
+	FailedOver bool           `json:"failed_over"` // active is a mirror
+	SwitchedAt time.Time      `json:"switched_at,omitempty"`
+	URLs       []MirrorStatus `json:"urls"` // base URL first
+}
+
+// MirrorStatus is the health of a base or mirror URL
+type MirrorStatus struct {
+	URL     string `json:"url"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"` // of the last failed check or request
+}
+
+// Failover sends the requests of each upstream API to the first of its
+// base and mirror URLs that is healthy. URLs are checked every
+// FailoverCheckInterval, and requests failing with network errors or 5xx
+// count as failed checks of the URL they went to; FailoverThreshold
+// consecutive failures mark a URL down and as many successes up again, so
+// the base URL takes traffic back once it recovers.
+type Failover struct {
+	client    *Client
+	apis      []*failoverAPI
+	interval  time.Duration
+	timeout   time.Duration
+	threshold int
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// failoverAPI is the base and mirror URLs of one upstream API
+type failoverAPI struct {
+	name  string
+	probe string // path checked on each URL
+
+	mu         sync.Mutex
+	urls       []*mirror
+	active     int
+	switchedAt time.Time
+}
+
+// mirror is a base or mirror URL and its recent checks
+type mirror struct {
+	url     string
+	healthy bool
+	fails   int // consecutive
+	passes  int // consecutive
+	err     string
+}
+
+// NewFailover creates the failover of the APIs with mirrors configured,
+// checked with the health probe paths
+func NewFailover(client *Client, cfg *config.PolymarketConfig, health *config.HealthConfig) *Failover {
+	f := &Failover{
+		client:    client,
+		interval:  cfg.FailoverCheckInterval,
+		timeout:   health.ProbeTimeout,
+		threshold: cfg.FailoverThreshold,
+		stop:      make(chan struct{}),
+	}
+	if f.threshold < 1 {
+		f.threshold = 1
+	}
+	for _, api := range [...]struct {
+		name, base, probe string
+		mirrors           []string
+	}{
+		{UpstreamClob, cfg.ClobBaseURL, health.ClobProbePath, cfg.ClobMirrors},
+		{UpstreamGamma, cfg.GammaBaseURL, health.GammaProbePath, cfg.GammaMirrors},
+		{UpstreamData, cfg.DataBaseURL, health.DataProbePath, cfg.DataMirrors},
+	} {
+		if api.base == "" || len(api.mirrors) == 0 {
+			continue
+		}
+		a := &failoverAPI{name: api.name, probe: api.probe}
+		for _, u := range append([]string{api.base}, api.mirrors...) {
+			a.urls = append(a.urls, &mirror{url: strings.TrimRight(u, "/"), healthy: true})
+		}
+		f.apis = append(f.apis, a)
+	}
+	return f
+}
+
+// Enabled reports whether any API has mirrors
+func (f *Failover) Enabled() bool {
+	return f != nil && len(f.apis) > 0
+}
+
+// Start checks the URLs every FailoverCheckInterval in the background;
+// a replay never contacts Polymarket, so nothing is checked then
+func (f *Failover) Start() {
+	if !f.Enabled() || f.interval <= 0 || f.client.tape.Replaying() {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(f.interval)
+		defer ticker.Stop()
+		for {
+			f.Check()
+			select {
+			case <-f.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the checks
+func (f *Failover) Stop() {
+	if f == nil {
+		return
+	}
+	f.once.Do(func() { close(f.stop) })
+}
+
+// Check checks every base and mirror URL once, concurrently
+func (f *Failover) Check() {
+	if !f.Enabled() {
+		return
+	}
+	var wg sync.WaitGroup
+	for _, a := range f.apis {
+		a.mu.Lock()
+		urls := make([]string, len(a.urls))
+		for i, m := range a.urls {
+			urls[i] = m.url
+		}
+		a.mu.Unlock()
+
+		for i, u := range urls {
+			wg.Add(1)
+			go func(a *failoverAPI, i int, url string) {
+				defer wg.Done()
+				a.record(i, f.probe(url), f.threshold)
+			}(a, i, u+a.probe)
+		}
+	}
+	wg.Wait()
+}
+
+// probe requests url once, without retries; any response below 500 means
+// the URL is up
+func (f *Failover) probe(url string) error {
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(url)
+	req.Header.SetMethod("GET")
+	req.Header.Set("Accept", "application/json")
+	for k, v := range f.client.config.ExtraHeaders {
+		req.Header.Set(k, v)
+	}
+
+	if err := f.client.httpClient.DoTimeout(req, resp, f.timeout); err != nil {
+		return err
+	}
+	if resp.StatusCode() >= 500 {
+		return &StatusError{StatusCode: resp.StatusCode()}
+	}
+	return nil
+}
+
+// route returns url moved onto the URL in use of its API, with the API
+// and the index of that URL to report the outcome to; URLs of APIs
+// without mirrors are returned as they are, with a nil API
+func (f *Failover) route(url string) (string, *failoverAPI, int) {
+	if !f.Enabled() {
+		return url, nil, 0
+	}
+	for _, a := range f.apis {
+		base := a.urls[0].url
+		if !strings.HasPrefix(url, base) {
+			continue
+		}
+		a.mu.Lock()
+		i := a.active
+		active := a.urls[i].url
+		a.mu.Unlock()
+		return active + url[len(base):], a, i
+	}
+	return url, nil, 0
+}
+
+// report counts the outcome of a request to URL i. Client errors and our
+// own rate limit say nothing about the URL's health.
+func (a *failoverAPI) report(i int, err error, threshold int) {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode < 500 || errors.Is(err, ErrUpstreamBusy) {
+		return
+	}
+	a.record(i, err, threshold)
+}
+
+// record counts a check of URL i and moves traffic to the first healthy URL
+func (a *failoverAPI) record(i int, err error, threshold int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	m := a.urls[i]
+	if err != nil {
+		m.fails++
+		m.passes = 0
+		m.err = err.Error()
+		if m.fails >= threshold {
+			m.healthy = false
+		}
+	} else {
+		m.passes++
+		m.fails = 0
+		if m.passes >= threshold {
+			m.healthy = true
+			m.err = ""
+		}
+	}
+
+	// With every URL down, stay where we are
+	next := a.active
+	for j, candidate := range a.urls {
+		if candidate.healthy {
+			next = j
+			break
+		}
+	}
+	if next == a.active {
+		return
+	}
+	if next == 0 {
+		log.Printf("Upstream %s failed back to %s", a.name, a.urls[0].url)
+	} else {
+		log.Printf("Upstream %s failed over from %s to %s", a.name, a.urls[a.active].url, a.urls[next].url)
+	}
+	a.active = next
+	a.switchedAt = time.Now()
+}
+
+// Status returns the state of every API with mirrors
+func (f *Failover) Status() []FailoverStatus {
+	if !f.Enabled() {
+		return nil
+	}
+	out := make([]FailoverStatus, 0, len(f.apis))
+	for _, a := range f.apis {
+		a.mu.Lock()
+		s := FailoverStatus{
+			Name:       a.name,
+			Active:     a.urls[a.active].url,
+			FailedOver: a.active != 0,
+			SwitchedAt: a.switchedAt,
+		}
+		for _, m := range a.urls {
+			s.URLs = append(s.URLs, MirrorStatus{URL: m.url, Healthy: m.healthy, Error: m.err})
+		}
+		a.mu.Unlock()
+		out = append(out, s)
+	}
+	return out
+}
+
+// SetFailover sends requests to the mirrors of f while base URLs are down
+func (c *Client) SetFailover(f *Failover) {
+	c.failover = f
+}
+
+// Failover returns the state of the APIs with mirrors, nil without any
+func (c *Client) Failover() []FailoverStatus {
+	return c.failover.Status()
+}
@@ -0,0 +1,78 @@
+package polymarket
+
+import (
+	"strings"
+	"sync"
+)
+
+// maxTrackedOrders bounds the fill tracker's memory
+const maxTrackedOrders = 10000
+
+// trackedOrder is an order placed through PolyGo that has not fully filled
+type trackedOrder struct {
+	address     string
+	sizeMatched string
+}
+
+// FillTracker remembers which wallet placed each order sent through PolyGo
+// so that fills observed later (in order lookups) can be attributed to it
+type FillTracker struct {
+	mu     sync.Mutex
+	orders map[string]*trackedOrder
+}
+
+// NewFillTracker creates a new fill tracker
+func NewFillTracker() *FillTracker {
+	return &FillTracker{orders: make(map[string]*trackedOrder)}
+}
+
+// Track starts watching an order placed by address
+func (t *FillTracker) Track(orderID, address string) {
+	if orderID == "" || address == "" {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.orders) >= maxTrackedOrders {
+		return
+	}
+	t.orders[orderID] = &trackedOrder{address: strings.ToLower(address), sizeMatched: "0"}
+}
+
+// Observe records the latest state of an order and returns the placing
+// address when the order has (further) filled since it was last seen.
+// Orders that reach a terminal status stop being tracked.
+func (t *FillTracker) Observe(orderID, status, sizeMatched string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order, ok := t.orders[orderID]
+	if !ok {
+		return "", false
+	}
+
+	filled := false
+	if sizeMatched != "" && sizeMatched != order.sizeMatched && sizeMatched != "0" {
+		order.sizeMatched = sizeMatched
+		filled = true
+	}
+
+	switch strings.ToUpper(status) {
+	case "MATCHED":
+		filled = true
+		delete(t.orders, orderID)
+	case "CANCELLED", "CANCELED":
+		delete(t.orders, orderID)
+	}
+
+	return order.address, filled
+}
+
+// Len returns the number of orders being tracked
+func (t *FillTracker) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.orders)
+}
@@ -0,0 +1,42 @@
+package polymarket
+
+import "sync"
+
+// flightGroup lets concurrent callers for the same key share one call
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+// flight is a call in progress; done is closed once its result is set
+type flight struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// do calls fn for key, unless a call for key is in progress, in which case
+// it waits for that call and returns its result
+func (g *flightGroup) do(key string, fn func() ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	if f, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-f.done
+		return f.data, f.err
+	}
+	if g.calls == nil {
+		g.calls = make(map[string]*flight)
+	}
+	f := &flight{done: make(chan struct{})}
+	g.calls[key] = f
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(f.done)
+	}()
+	f.data, f.err = fn()
+	return f.data, f.err
+}
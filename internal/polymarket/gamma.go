@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/polygo/internal/cache"
 	"github.com/polygo/internal/models"
@@ -101,6 +102,36 @@ func (g *GammaClient) SearchEvents(query string, limit int) ([]byte, bool, error
 	return g.client.GetWithCache(u, cacheKey, ttl)
 }
 
+// GetAllMarkets walks every page of the markets listing (uncached),
+// restarting from the last stable offset if upstream expires the cursor
+func (g *GammaClient) GetAllMarkets(params *models.MarketQueryParams, maxItems int) (*PaginateResult, error) {
+	p := *params
+	base := startOffset(params.Cursor, params.Offset)
+	return Paginate(func(cursor string, offset int) ([]byte, error) {
+		p.Cursor = cursor
+		p.Offset = 0
+		if cursor == "" {
+			p.Offset = base + offset
+		}
+		return g.client.Get(g.client.Gamma("/markets"+buildMarketQuery(&p)), nil)
+	}, p.Limit, maxItems)
+}
+
+// GetAllEvents walks every page of the events listing (uncached),
+// restarting from the last stable offset if upstream expires the cursor
+func (g *GammaClient) GetAllEvents(params *models.EventQueryParams, maxItems int) (*PaginateResult, error) {
+	p := *params
+	base := startOffset(params.Cursor, params.Offset)
+	return Paginate(func(cursor string, offset int) ([]byte, error) {
+		p.Cursor = cursor
+		p.Offset = 0
+		if cursor == "" {
+			p.Offset = base + offset
+		}
+		return g.client.Get(g.client.Gamma("/events"+buildEventQuery(&p)), nil)
+	}, p.Limit, maxItems)
+}
+
 // buildEventQuery builds query string for events
 func buildEventQuery(params *models.EventQueryParams) string {
 	if params == nil {
@@ -112,8 +143,9 @@ func buildEventQuery(params *models.EventQueryParams) string {
 	if params.Limit > 0 {
 		v.Set("limit", strconv.Itoa(params.Limit))
 	}
-	if params.Cursor != "" {
-		v.Set("next_cursor", params.Cursor)
+	SetCursor(v, params.Cursor)
+	if params.Offset > 0 {
+		v.Set("offset", strconv.Itoa(params.Offset))
 	}
 	if params.Active != nil {
 		v.Set("active", strconv.FormatBool(*params.Active))
@@ -148,8 +180,9 @@ func buildMarketQuery(params *models.MarketQueryParams) string {
 	if params.Limit > 0 {
 		v.Set("limit", strconv.Itoa(params.Limit))
 	}
-	if params.Cursor != "" {
-		v.Set("next_cursor", params.Cursor)
+	SetCursor(v, params.Cursor)
+	if params.Offset > 0 {
+		v.Set("offset", strconv.Itoa(params.Offset))
 	}
 	if params.Active != nil {
 		v.Set("active", strconv.FormatBool(*params.Active))
@@ -166,6 +199,24 @@ func buildMarketQuery(params *models.MarketQueryParams) string {
 	if params.ClobTokenID != "" {
 		v.Set("clob_token_id", params.ClobTokenID)
 	}
+	if params.Order != "" {
+		v.Set("order", params.Order)
+	}
+	if params.Ascending != nil {
+		v.Set("ascending", strconv.FormatBool(*params.Ascending))
+	}
+	if params.MinVolume > 0 {
+		v.Set("volume_num_min", strconv.FormatFloat(params.MinVolume, 'f', -1, 64))
+	}
+	if params.MinLiquidity > 0 {
+		v.Set("liquidity_num_min", strconv.FormatFloat(params.MinLiquidity, 'f', -1, 64))
+	}
+	if !params.EndDateBefore.IsZero() {
+		v.Set("end_date_max", params.EndDateBefore.UTC().Format(time.RFC3339))
+	}
+	if !params.EndDateAfter.IsZero() {
+		v.Set("end_date_min", params.EndDateAfter.UTC().Format(time.RFC3339))
+	}
 
 	if len(v) == 0 {
 		return ""
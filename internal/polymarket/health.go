@@ -0,0 +1,215 @@
+package polymarket
+
+import (
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/polygo/internal/config"
+	"github.com/valyala/fasthttp"
+)
+
+// Upstream dependency names reported by the prober
+const (
+	UpstreamClob  = "clob"
+	UpstreamGamma = "gamma"
+	UpstreamData  = "data"
+)
+
+// ProbeResult is the latest reachability check for one upstream API
+type ProbeResult struct {
+	Name        string    `json:"name"`
+	URL         string    `json:"url"`
+	Healthy     bool      `json:"healthy"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	LatencyMs   int64     `json:"latency_ms"`
+	Error       string    `json:"error,omitempty"`
+	CheckedAt   time.Time `json:"checked_at"`
+	LastSuccess time.Time `json:"last_success,omitempty"`
+}
+
+// HealthProber actively probes the upstream APIs and caches the results
+type HealthProber struct {
+	client  *Client
+	config  *config.HealthConfig
+	targets map[string]string
+	proxies map[string]*url.URL // probed by connecting, so a dead proxy is told apart from dead upstreams
+
+	mu        sync.Mutex
+	results   map[string]*ProbeResult
+	checkedAt time.Time
+	startedAt time.Time
+	probing   chan struct{} // closed when the probe under way finishes; nil when none is
+}
+
+// NewHealthProber creates a new upstream health prober
+func NewHealthProber(client *Client, cfg *config.HealthConfig) *HealthProber {
+	return &HealthProber{
+		client: client,
+		config: cfg,
+		targets: map[string]string{
+			UpstreamClob:  client.CLOB(cfg.ClobProbePath),
+			UpstreamGamma: client.Gamma(cfg.GammaProbePath),
+			UpstreamData:  client.Data(cfg.DataProbePath),
+		},
+		proxies:   client.proxies.Proxies(),
+		results:   make(map[string]*ProbeResult),
+		startedAt: time.Now(),
+	}
+}
+
+// Check returns per-upstream probe results, re-probing when the cached
+// results are older than ProbeInterval. Probes run outside p.mu, so
+// callers finding a probe under way wait for its results without
+// blocking readers of fresh ones.
+func (p *HealthProber) Check() map[string]ProbeResult {
+	p.mu.Lock()
+	if running := p.probing; running != nil {
+		p.mu.Unlock()
+		<-running
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.snapshot()
+	}
+	if time.Since(p.checkedAt) < p.config.ProbeInterval {
+		defer p.mu.Unlock()
+		return p.snapshot()
+	}
+	done := make(chan struct{})
+	p.probing = done
+	p.mu.Unlock()
+
+	results := p.probeAll()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for name, result := range results {
+		if prev, ok := p.results[name]; ok && !result.Healthy {
+			result.LastSuccess = prev.LastSuccess
+		}
+		p.results[name] = result
+	}
+	p.checkedAt = time.Now()
+	p.probing = nil
+	close(done)
+	return p.snapshot()
+}
+
+// Unreachable returns the upstreams that have not answered successfully for
+// longer than UnhealthyThreshold
+func (p *HealthProber) Unreachable() []string {
+	results := p.Check()
+
+	var down []string
+	for name, r := range results {
+		since := r.LastSuccess
+		if since.IsZero() {
+			since = p.startedAt
+		}
+		if !r.Healthy && time.Since(since) > p.config.UnhealthyThreshold {
+			down = append(down, name)
+		}
+	}
+	return down
+}
+
+// probeAll probes every upstream concurrently and returns the results by
+// name
+func (p *HealthProber) probeAll() map[string]*ProbeResult {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	results := make(map[string]*ProbeResult, len(p.targets)+len(p.proxies))
+
+	for name, url := range p.targets {
+		// An API failed over is probed where its requests go
+		url, _, _ = p.client.failover.route(url)
+		wg.Add(1)
+		go func(name, url string) {
+			defer wg.Done()
+			result := p.probe(name, url)
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}(name, url)
+	}
+	for name, proxy := range p.proxies {
+		wg.Add(1)
+		go func(name string, proxy *url.URL) {
+			defer wg.Done()
+			result := p.probeProxy(name, proxy)
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}(name, proxy)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// probe performs a single reachability request without retries. Any
+// response below 500 means the upstream is reachable.
+func (p *HealthProber) probe(name, url string) *ProbeResult {
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(url)
+	req.Header.SetMethod("GET")
+	req.Header.Set("Accept", "application/json")
+
+	start := time.Now()
+	err := p.client.httpClient.DoTimeout(req, resp, p.config.ProbeTimeout)
+	result := &ProbeResult{
+		Name:      name,
+		URL:       url,
+		LatencyMs: time.Since(start).Milliseconds(),
+		CheckedAt: time.Now(),
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.StatusCode = resp.StatusCode()
+	result.Healthy = result.StatusCode < 500
+	if result.Healthy {
+		result.LastSuccess = result.CheckedAt
+	} else {
+		result.Error = "upstream returned server error"
+	}
+	return result
+}
+
+// probeProxy checks that a proxy accepts connections
+func (p *HealthProber) probeProxy(name string, proxy *url.URL) *ProbeResult {
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", proxy.Host, p.config.ProbeTimeout)
+	result := &ProbeResult{
+		Name:      name,
+		URL:       proxy.Scheme + "://" + proxy.Host,
+		LatencyMs: time.Since(start).Milliseconds(),
+		CheckedAt: time.Now(),
+	}
+
+	if err != nil {
+		result.Error = "proxy unreachable: " + err.Error()
+		return result
+	}
+	conn.Close()
+	result.Healthy = true
+	result.LastSuccess = result.CheckedAt
+	return result
+}
+
+// snapshot copies the cached results; caller holds p.mu
+func (p *HealthProber) snapshot() map[string]ProbeResult {
+	out := make(map[string]ProbeResult, len(p.results))
+	for name, r := range p.results {
+		out[name] = *r
+	}
+	return out
+}
@@ -0,0 +1,129 @@
+package polymarket
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/polygo/internal/config"
+)
+
+// ErrUpstreamBusy is returned when a request would have to queue longer
+// than allowed for the upstream rate limit, or the queue is full
+var ErrUpstreamBusy = errors.New("upstream rate limit: request queue full")
+
+// rateRecoveryFactor is how much of the configured rate is restored per
+// successful request after being throttled
+const rateRecoveryFactor = 0.01
+
+// hostLimiter is an adaptive token bucket for one upstream host. Requests
+// beyond the burst queue for a token instead of failing. A 429 halves the
+// rate; each success then creeps it back towards the configured maximum.
+type hostLimiter struct {
+	mu       sync.Mutex
+	rate     float64 // current tokens per second
+	maxRate  float64
+	minRate  float64
+	burst    float64
+	tokens   float64
+	last     time.Time
+	waiting  int
+	maxQueue int
+	maxWait  time.Duration
+}
+
+// newHostLimiter creates a limiter from the upstream rate limit settings
+func newHostLimiter(cfg *config.PolymarketConfig) *hostLimiter {
+	burst := float64(cfg.UpstreamBurst)
+	if burst < 1 {
+		burst = 1
+	}
+	minRate := cfg.UpstreamRPS / 10
+	return &hostLimiter{
+		rate:     cfg.UpstreamRPS,
+		maxRate:  cfg.UpstreamRPS,
+		minRate:  minRate,
+		burst:    burst,
+		tokens:   burst,
+		last:     time.Now(),
+		maxQueue: cfg.UpstreamQueueSize,
+		maxWait:  cfg.UpstreamQueueWait,
+	}
+}
+
+// wait blocks until the request may be sent, or returns ErrUpstreamBusy
+func (l *hostLimiter) wait() error {
+	l.mu.Lock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		l.mu.Unlock()
+		return nil
+	}
+
+	// Reserve the next token; the bucket goes negative by the queue depth
+	delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	if l.waiting >= l.maxQueue || delay > l.maxWait {
+		l.mu.Unlock()
+		return ErrUpstreamBusy
+	}
+	l.tokens--
+	l.waiting++
+	l.mu.Unlock()
+
+	time.Sleep(delay)
+
+	l.mu.Lock()
+	l.waiting--
+	l.mu.Unlock()
+	return nil
+}
+
+// throttled reacts to a 429 from the host
+func (l *hostLimiter) throttled() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rate /= 2
+	if l.rate < l.minRate {
+		l.rate = l.minRate
+	}
+}
+
+// succeeded slowly restores the rate after throttling
+func (l *hostLimiter) succeeded() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rate < l.maxRate {
+		l.rate += l.maxRate * rateRecoveryFactor
+		if l.rate > l.maxRate {
+			l.rate = l.maxRate
+		}
+	}
+}
+
+// limiterFor returns the limiter for a host, or nil when upstream rate
+// limiting is disabled
+func (c *Client) limiterFor(host string) *hostLimiter {
+	if c.config.UpstreamRPS <= 0 {
+		return nil
+	}
+
+	c.limitersMu.Lock()
+	defer c.limitersMu.Unlock()
+
+	l, ok := c.limiters[host]
+	if !ok {
+		l = newHostLimiter(c.config)
+		c.limiters[host] = l
+	}
+	return l
+}
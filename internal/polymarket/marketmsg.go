@@ -0,0 +1,116 @@
+package polymarket
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+)
+
+// Market channel event types, besides BookEventSnapshot
+const (
+	EventPriceChange    = "price_change"
+	EventLastTradePrice = "last_trade_price"
+	EventTickSizeChange = "tick_size_change"
+)
+
+// MarketMessage is one message of an upstream market channel frame. The
+// CLOB keys its messages by token (asset_id), with the condition ID of the
+// token's market in market.
+type MarketMessage struct {
+	EventType string
+	Market    string   // condition ID
+	AssetIDs  []string // tokens the message is about, in order of appearance
+	Markets   []string // markets named by frames PolyGo builds itself
+	Timestamp int64    // upstream event time, unix ms; 0 if absent
+	Data      []byte   // the message alone, as received
+}
+
+// marketMessage is the union of the routing fields of market channel messages
+type marketMessage struct {
+	EventType string          `json:"event_type"`
+	Market    string          `json:"market"`
+	AssetID   string          `json:"asset_id"`  // book, last_trade_price, tick_size_change and the older price_change
+	Timestamp json.RawMessage `json:"timestamp"` // unix ms, as a string or a number
+
+	// price_change: one entry per level, each for its own token
+	PriceChanges []struct {
+		AssetID string `json:"asset_id"`
+	} `json:"price_changes"`
+
+	// Frames PolyGo builds itself (tests, load runs) name markets directly
+	Markets []string `json:"markets"`
+}
+
+// ParseMarketFrame splits a market channel frame, which holds one message
+// or a JSON array of them (as the books sent on subscribe are), into its
+// messages. Elements that are not JSON objects are skipped.
+func ParseMarketFrame(data []byte) []MarketMessage {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil
+	}
+	if data[0] != '[' {
+		if m, ok := parseMarketMessage(data); ok {
+			return []MarketMessage{m}
+		}
+		return nil
+	}
+
+	var batch []json.RawMessage
+	if err := sonic.Unmarshal(data, &batch); err != nil {
+		return nil
+	}
+	out := make([]MarketMessage, 0, len(batch))
+	for _, raw := range batch {
+		if m, ok := parseMarketMessage(raw); ok {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func parseMarketMessage(data []byte) (MarketMessage, bool) {
+	var msg marketMessage
+	if err := sonic.Unmarshal(data, &msg); err != nil {
+		return MarketMessage{}, false
+	}
+
+	m := MarketMessage{EventType: msg.EventType, Market: msg.Market, Markets: msg.Markets, Data: data}
+	if ts, err := strconv.ParseInt(strings.Trim(string(msg.Timestamp), `"`), 10, 64); err == nil {
+		m.Timestamp = ts
+	}
+	if msg.AssetID != "" {
+		m.AssetIDs = append(m.AssetIDs, msg.AssetID)
+	}
+	for _, c := range msg.PriceChanges {
+		if c.AssetID != "" && !contains(m.AssetIDs, c.AssetID) {
+			m.AssetIDs = append(m.AssetIDs, c.AssetID)
+		}
+	}
+	return m, true
+}
+
+// Keys returns the subscription keys the message is routed to: its
+// tokens, then its market, each once
+func (m MarketMessage) Keys() []string {
+	keys := make([]string, 0, len(m.AssetIDs)+1+len(m.Markets))
+	keys = append(keys, m.AssetIDs...)
+	for _, market := range append([]string{m.Market}, m.Markets...) {
+		if market != "" && !contains(keys, market) {
+			keys = append(keys, market)
+		}
+	}
+	return keys
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
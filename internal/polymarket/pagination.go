@@ -0,0 +1,165 @@
+package polymarket
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+
+	"github.com/bytedance/sonic"
+)
+
+// endCursor is the sentinel Polymarket returns once the last page is reached
+const endCursor = "LTE="
+
+// maxCursorRefreshes bounds how many times a single iteration may restart
+const maxCursorRefreshes = 3
+
+// ErrCursorExpired is returned when upstream rejects a pagination cursor
+var ErrCursorExpired = errors.New("pagination cursor expired")
+
+// Page is one page of a paginated upstream listing
+type Page struct {
+	Items      []json.RawMessage
+	NextCursor string
+}
+
+// PageFetcher fetches the page identified by cursor, falling back to offset
+// when cursor is empty
+type PageFetcher func(cursor string, offset int) ([]byte, error)
+
+// PaginateResult holds the items collected by Paginate
+type PaginateResult struct {
+	Items           []json.RawMessage
+	Pages           int
+	CursorRefreshed bool
+}
+
+// Paginate walks all pages returned by fetch until the listing is exhausted
+// or maxItems have been collected. If upstream expires the cursor
+// mid-iteration, the walk restarts from the last stable offset (the number
+// of items already collected) instead of failing.
+func Paginate(fetch PageFetcher, pageSize, maxItems int) (*PaginateResult, error) {
+	result := &PaginateResult{}
+	cursor := ""
+	refreshes := 0
+
+	for maxItems <= 0 || len(result.Items) < maxItems {
+		data, err := fetch(cursor, len(result.Items))
+		if err != nil {
+			if IsCursorExpired(err) && cursor != "" && refreshes < maxCursorRefreshes {
+				refreshes++
+				result.CursorRefreshed = true
+				cursor = ""
+				continue
+			}
+			return nil, err
+		}
+
+		page, err := ParsePage(data)
+		if err != nil {
+			return nil, err
+		}
+		result.Pages++
+		result.Items = append(result.Items, page.Items...)
+
+		if len(page.Items) == 0 {
+			break
+		}
+		if page.NextCursor == "" || page.NextCursor == endCursor {
+			// Offset-paginated APIs don't return cursors; a short page is the end
+			if page.NextCursor == endCursor || len(page.Items) < pageSize {
+				break
+			}
+		}
+		cursor = page.NextCursor
+	}
+
+	if maxItems > 0 && len(result.Items) > maxItems {
+		result.Items = result.Items[:maxItems]
+	}
+	return result, nil
+}
+
+// ParsePage decodes an upstream page, which is either a bare JSON array or
+// an object wrapping the items in "data" alongside "next_cursor"
+func ParsePage(data []byte) (*Page, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var items []json.RawMessage
+		if err := sonic.Unmarshal(trimmed, &items); err != nil {
+			return nil, err
+		}
+		return &Page{Items: items}, nil
+	}
+
+	var wrapped struct {
+		Data       []json.RawMessage `json:"data"`
+		NextCursor string            `json:"next_cursor"`
+	}
+	if err := sonic.Unmarshal(trimmed, &wrapped); err != nil {
+		return nil, err
+	}
+	return &Page{Items: wrapped.Data, NextCursor: wrapped.NextCursor}, nil
+}
+
+// IsCursorExpired reports whether err is upstream rejecting a stale cursor
+func IsCursorExpired(err error) bool {
+	if errors.Is(err, ErrCursorExpired) {
+		return true
+	}
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	if statusErr.StatusCode != 400 && statusErr.StatusCode != 410 {
+		return false
+	}
+
+	body := bytes.ToLower(statusErr.Body)
+	return bytes.Contains(body, []byte("cursor")) &&
+		(bytes.Contains(body, []byte("expired")) || bytes.Contains(body, []byte("invalid")))
+}
+
+// SetCursor adds a unified pagination cursor to an upstream query. A
+// numeric cursor is an offset; anything else is passed on as next_cursor.
+func SetCursor(query url.Values, cursor string) {
+	if cursor == "" {
+		return
+	}
+	if _, err := strconv.Atoi(cursor); err == nil {
+		query.Set("offset", cursor)
+		return
+	}
+	query.Set("next_cursor", cursor)
+}
+
+// startOffset is where a walk begins: a numeric cursor overrides offset
+func startOffset(cursor string, offset int) int {
+	if n, err := strconv.Atoi(cursor); err == nil {
+		return n
+	}
+	return offset
+}
+
+// NextCursor returns the cursor for the page after data, or "" on the last
+// page. Wrapped responses carry it as next_cursor; for bare arrays from
+// offset-paginated APIs a full page yields the next offset.
+func NextCursor(data []byte, offset, limit int) string {
+	page, err := ParsePage(data)
+	if err != nil {
+		return ""
+	}
+	if page.NextCursor != "" {
+		if page.NextCursor == endCursor {
+			return ""
+		}
+		return page.NextCursor
+	}
+	if limit > 0 && len(page.Items) >= limit {
+		return strconv.Itoa(offset + len(page.Items))
+	}
+	return ""
+}
@@ -0,0 +1,184 @@
+package polymarket
+
+import (
+	"errors"
+	"math"
+	"sync"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/latency"
+	"github.com/polygo/internal/models"
+)
+
+// ErrPriceOffTick is returned for a price the CLOB would reject: not a
+// multiple of the token's tick size, or outside [tick, 1-tick]
+var ErrPriceOffTick = errors.New("price is not a valid tick")
+
+// PayoutOrder is a hypothetical order to price
+type PayoutOrder struct {
+	TokenID string
+	Side    models.Side
+	Price   float64
+	Size    float64 // shares
+	Maker   bool    // resting orders pay no fee
+}
+
+// MarketTerms are the per-token trading terms that affect an order's cost
+type MarketTerms struct {
+	TickSize   float64 `json:"tick_size"`
+	NegRisk    bool    `json:"neg_risk"`
+	FeeRateBps float64 `json:"fee_rate_bps"`
+}
+
+// PayoutQuote is what an order costs and can pay out, in USDC unless noted.
+// Buys pay their fee in shares, so fewer shares are received than ordered;
+// sells pay it from the proceeds.
+type PayoutQuote struct {
+	TokenID string      `json:"token_id"`
+	Side    models.Side `json:"side"`
+	Price   float64     `json:"price"`
+	Size    float64     `json:"size"`
+	Maker   bool        `json:"maker"`
+	MarketTerms
+
+	Notional  float64 `json:"notional"`             // price × size
+	Fee       float64 `json:"fee"`                  // fee valued in USDC
+	FeeShares float64 `json:"fee_shares,omitempty"` // BUY: shares withheld as the fee
+
+	NetCost     float64 `json:"net_cost,omitempty"`     // BUY: USDC paid
+	NetProceeds float64 `json:"net_proceeds,omitempty"` // SELL: USDC received after the fee
+	Shares      float64 `json:"shares"`                 // shares received (BUY) or given up (SELL)
+	MaxPayout   float64 `json:"max_payout"`             // BUY: paid if the outcome wins; SELL: forgone if it wins
+	MaxProfit   float64 `json:"max_profit"`             // BUY: max payout less net cost; SELL: net proceeds, kept if it loses
+	MaxLoss     float64 `json:"max_loss"`               // BUY: net cost; SELL: payout forgone less net proceeds
+	Breakeven   float64 `json:"breakeven"`              // outcome probability at which the order has zero expected value
+}
+
+// CalculatePayout prices an order under the given terms. The taker fee
+// follows the CLOB schedule: rate × min(price, 1-price) × size, charged in
+// shares on buys and in USDC on sells.
+func CalculatePayout(order PayoutOrder, terms MarketTerms) (*PayoutQuote, error) {
+	if !onTick(order.Price, terms.TickSize) {
+		return nil, ErrPriceOffTick
+	}
+
+	q := &PayoutQuote{
+		TokenID:     order.TokenID,
+		Side:        order.Side,
+		Price:       order.Price,
+		Size:        order.Size,
+		Maker:       order.Maker,
+		MarketTerms: terms,
+		Notional:    order.Price * order.Size,
+	}
+	if !order.Maker {
+		q.Fee = terms.FeeRateBps / 10000 * math.Min(order.Price, 1-order.Price) * order.Size
+	}
+
+	if order.Side == models.SideBuy {
+		q.FeeShares = q.Fee / order.Price
+		q.NetCost = q.Notional
+		q.Shares = order.Size - q.FeeShares
+		q.MaxPayout = q.Shares
+		q.MaxProfit = q.MaxPayout - q.NetCost
+		q.MaxLoss = q.NetCost
+		q.Breakeven = q.NetCost / q.Shares
+	} else {
+		q.NetProceeds = q.Notional - q.Fee
+		q.Shares = order.Size
+		q.MaxPayout = order.Size
+		q.MaxProfit = q.NetProceeds
+		q.MaxLoss = q.MaxPayout - q.NetProceeds
+		q.Breakeven = q.NetProceeds / order.Size
+	}
+
+	roundQuote(q)
+	return q, nil
+}
+
+// onTick reports whether the CLOB accepts price for a token with this tick
+func onTick(price, tick float64) bool {
+	if tick <= 0 {
+		return price > 0 && price < 1
+	}
+	if price < tick-1e-9 || price > 1-tick+1e-9 {
+		return false
+	}
+	steps := price / tick
+	return math.Abs(steps-math.Round(steps)) < 1e-6
+}
+
+// roundQuote trims float noise to USDC's six decimals
+func roundQuote(q *PayoutQuote) {
+	for _, v := range []*float64{&q.Notional, &q.Fee, &q.FeeShares, &q.NetCost, &q.NetProceeds,
+		&q.Shares, &q.MaxPayout, &q.MaxProfit, &q.MaxLoss, &q.Breakeven} {
+		*v = math.Round(*v*1e6) / 1e6
+	}
+}
+
+// PayoutCalculator prices hypothetical orders with a token's current
+// tick size, neg-risk flag and fee rate
+type PayoutCalculator struct {
+	clob *ClobClient
+}
+
+// NewPayoutCalculator creates a new payout calculator
+func NewPayoutCalculator(clob *ClobClient) *PayoutCalculator {
+	return &PayoutCalculator{clob: clob}
+}
+
+// Quote prices an order
+func (p *PayoutCalculator) Quote(order PayoutOrder) (*PayoutQuote, error) {
+	terms, err := p.Terms(order.TokenID)
+	if err != nil {
+		return nil, err
+	}
+	return CalculatePayout(order, *terms)
+}
+
+// Terms fetches a token's trading terms; each lookup goes through its own
+// cache, and the three are fetched concurrently
+func (p *PayoutCalculator) Terms(tokenID string) (*MarketTerms, error) {
+	var (
+		tick struct {
+			MinimumTickSize float64 `json:"minimum_tick_size"`
+		}
+		negRisk struct {
+			NegRisk bool `json:"neg_risk"`
+		}
+		fee struct {
+			BaseFee float64 `json:"base_fee"`
+		}
+	)
+
+	fetches := []struct {
+		get  func(string) ([]byte, bool, error)
+		into interface{}
+	}{
+		{p.clob.GetTickSize, &tick},
+		{p.clob.GetNegRisk, &negRisk},
+		{p.clob.GetFeeRate, &fee},
+	}
+
+	errs := make([]error, len(fetches))
+	var wg sync.WaitGroup
+	bind := latency.Inherit()
+	for i, f := range fetches {
+		wg.Add(1)
+		go func(i int, get func(string) ([]byte, bool, error), into interface{}) {
+			defer wg.Done()
+			defer bind()()
+			data, _, err := get(tokenID)
+			if err == nil {
+				err = sonic.Unmarshal(data, into)
+			}
+			errs[i] = err
+		}(i, f.get, f.into)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return &MarketTerms{TickSize: tick.MinimumTickSize, NegRisk: negRisk.NegRisk, FeeRateBps: fee.BaseFee}, nil
+}
@@ -0,0 +1,235 @@
+package polymarket
+
+import (
+	"errors"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// PoolStats describes the connections to an upstream host
+type PoolStats struct {
+	Host            string  `json:"host"` // host:port
+	MaxConns        int     `json:"max_conns"`
+	OpenConns       int     `json:"open_conns"`       // busy or idle
+	InFlight        int     `json:"in_flight"`        // requests being served or waiting
+	PendingAcquires int     `json:"pending_acquires"` // requests waiting for a free connection
+	Dialed          uint64  `json:"dialed"`           // connections opened since startup
+	Closed          uint64  `json:"closed"`
+	AvgLifetimeMs   float64 `json:"avg_lifetime_ms"` // of closed connections
+	MaxLifetimeMs   int64   `json:"max_lifetime_ms"`
+}
+
+// connPool follows the fasthttp host clients of the upstream HTTP clients
+// and the connections dialed for them, and applies connection limits set
+// at runtime
+type connPool struct {
+	mu        sync.Mutex
+	maxConns  int            // limit of hosts without their own
+	overrides map[string]int // by host or host:port
+	hosts     map[string]*hostPool
+}
+
+// hostPool is an upstream host:port's share of the pool
+type hostPool struct {
+	clients  [2]*fasthttp.HostClient // of the buffered and the streaming client
+	dialed   uint64
+	closed   uint64
+	lifetime time.Duration // total of closed connections
+	longest  time.Duration
+}
+
+// newConnPool creates a pool allowing maxConns connections per host, or
+// fasthttp's default when 0
+func newConnPool(maxConns int) *connPool {
+	if maxConns <= 0 {
+		maxConns = fasthttp.DefaultMaxConnsPerHost
+	}
+	return &connPool{
+		maxConns:  maxConns,
+		overrides: make(map[string]int),
+		hosts:     make(map[string]*hostPool),
+	}
+}
+
+// host returns the share of addr; p.mu must be held
+func (p *connPool) host(addr string) *hostPool {
+	h, ok := p.hosts[addr]
+	if !ok {
+		h = &hostPool{}
+		p.hosts[addr] = h
+	}
+	return h
+}
+
+// limit returns the connection limit of addr; p.mu must be held
+func (p *connPool) limit(addr string) int {
+	if n, ok := p.overrides[addr]; ok {
+		return n
+	}
+	hostname, _, _ := net.SplitHostPort(addr)
+	if n, ok := p.overrides[hostname]; ok {
+		return n
+	}
+	return p.maxConns
+}
+
+// configure returns the hook registering the host clients of the
+// buffered or the streaming client as fasthttp creates them. fasthttp
+// drops idle host clients and creates them anew, so the latest of a host
+// replaces the previous one.
+func (p *connPool) configure(stream bool) func(hc *fasthttp.HostClient) error {
+	i := 0
+	if stream {
+		i = 1
+	}
+	return func(hc *fasthttp.HostClient) error {
+		addr := strings.ToLower(hc.Addr)
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		hc.MaxConns = p.limit(addr)
+		p.host(addr).clients[i] = hc
+		return nil
+	}
+}
+
+// dial wraps a dial function to count the connections it opens per host
+// and how long they live
+func (p *connPool) dial(dial fasthttp.DialFunc) fasthttp.DialFunc {
+	return func(addr string) (net.Conn, error) {
+		conn, err := dial(addr)
+		if err != nil {
+			return nil, err
+		}
+		addr = strings.ToLower(addr)
+		p.mu.Lock()
+		p.host(addr).dialed++
+		p.mu.Unlock()
+		return &pooledConn{Conn: conn, pool: p, addr: addr, opened: time.Now()}, nil
+	}
+}
+
+// closed records the end of a connection to addr
+func (p *connPool) closed(addr string, lived time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	h := p.host(addr)
+	h.closed++
+	h.lifetime += lived
+	if lived > h.longest {
+		h.longest = lived
+	}
+}
+
+// ErrInvalidMaxConns is returned for a connection limit below 1
+var ErrInvalidMaxConns = errors.New("max_conns_per_host must be at least 1")
+
+// setMaxConns changes the connection limit of host (host or host:port),
+// or the default one when host is empty
+func (p *connPool) setMaxConns(host string, n int) error {
+	if n < 1 {
+		return ErrInvalidMaxConns
+	}
+	host = strings.ToLower(host)
+
+	p.mu.Lock()
+	if host == "" {
+		p.maxConns = n
+	} else {
+		p.overrides[host] = n
+	}
+	type resize struct {
+		clients [2]*fasthttp.HostClient
+		limit   int
+	}
+	var resizes []resize
+	for addr, h := range p.hosts {
+		resizes = append(resizes, resize{clients: h.clients, limit: p.limit(addr)})
+	}
+	p.mu.Unlock()
+
+	// Resized outside p.mu, so the pool never waits on a host client's lock
+	for _, r := range resizes {
+		for _, hc := range r.clients {
+			if hc != nil {
+				hc.SetMaxConns(r.limit)
+			}
+		}
+	}
+	return nil
+}
+
+// stats returns the state of every host, by address
+func (p *connPool) stats() []PoolStats {
+	p.mu.Lock()
+	type host struct {
+		clients [2]*fasthttp.HostClient
+		stats   PoolStats
+	}
+	hosts := make([]host, 0, len(p.hosts))
+	for addr, h := range p.hosts {
+		s := PoolStats{
+			Host:          addr,
+			MaxConns:      p.limit(addr),
+			Dialed:        h.dialed,
+			Closed:        h.closed,
+			MaxLifetimeMs: h.longest.Milliseconds(),
+		}
+		if h.closed > 0 {
+			s.AvgLifetimeMs = float64(h.lifetime.Milliseconds()) / float64(h.closed)
+		}
+		hosts = append(hosts, host{clients: h.clients, stats: s})
+	}
+	p.mu.Unlock()
+
+	out := make([]PoolStats, 0, len(hosts))
+	for _, h := range hosts {
+		s := h.stats
+		for _, hc := range h.clients {
+			if hc == nil {
+				continue
+			}
+			conns, inFlight := hc.ConnsCount(), hc.PendingRequests()
+			s.OpenConns += conns
+			s.InFlight += inFlight
+			// Requests beyond the connections are waiting for one
+			if inFlight > conns {
+				s.PendingAcquires += inFlight - conns
+			}
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Host < out[j].Host })
+	return out
+}
+
+// pooledConn reports its close to the pool
+type pooledConn struct {
+	net.Conn
+	pool   *connPool
+	addr   string
+	opened time.Time
+	once   sync.Once
+}
+
+// Close closes the connection
+func (c *pooledConn) Close() error {
+	c.once.Do(func() { c.pool.closed(c.addr, time.Since(c.opened)) })
+	return c.Conn.Close()
+}
+
+// PoolStats returns the upstream connections per host
+func (c *Client) PoolStats() []PoolStats {
+	return c.pool.stats()
+}
+
+// SetMaxConns changes at runtime how many connections are opened to an
+// upstream host (host or host:port), or to every host without a limit of
+// its own when host is empty
+func (c *Client) SetMaxConns(host string, n int) error {
+	return c.pool.setMaxConns(host, n)
+}
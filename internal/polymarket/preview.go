@@ -0,0 +1,181 @@
+package polymarket
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/models"
+)
+
+// PreviewOrder is a hypothetical order to match against a token's book
+type PreviewOrder struct {
+	TokenID string
+	Side    models.Side
+	Price   float64 // limit: levels priced worse are not taken
+	Size    float64 // shares
+	Type    models.OrderType
+}
+
+// PreviewFill is the shares an order takes at one price level
+type PreviewFill struct {
+	Price float64 `json:"price"`
+	Size  float64 `json:"size"`
+}
+
+// OrderPreview is how an order would match against the book as it stands,
+// in USDC unless noted. Later orders and the CLOB's own matching may fill
+// it differently.
+type OrderPreview struct {
+	TokenID    string           `json:"token_id"`
+	Side       models.Side      `json:"side"`
+	Type       models.OrderType `json:"type"`
+	Price      float64          `json:"price"` // limit
+	Size       float64          `json:"size"`
+	FeeRateBps float64          `json:"fee_rate_bps"`
+
+	FilledSize    float64  `json:"filled_size"`
+	RemainingSize float64  `json:"remaining_size"`
+	AvgPrice      *float64 `json:"avg_price"`            // nil when nothing fills
+	WorstPrice    *float64 `json:"worst_price"`          // of the last level taken
+	BestPrice     *float64 `json:"best_price"`           // best opposite price before the order; nil on an empty side
+	Slippage      *float64 `json:"slippage"`             // how much worse AvgPrice is than BestPrice
+	Notional      float64  `json:"notional"`             // USDC paid (BUY) or received before the fee (SELL)
+	Fee           float64  `json:"fee"`                  // taker fee valued in USDC
+	FeeShares     float64  `json:"fee_shares,omitempty"` // BUY: shares withheld as the fee
+	Resting       float64  `json:"resting"`              // GTC/GTD: shares left on the book as a maker order
+	Killed        bool     `json:"killed,omitempty"`     // FOK: the book cannot fill it whole, so nothing fills
+
+	Fills []PreviewFill `json:"fills"` // levels taken, best first
+}
+
+// PreviewMatch walks bids or asks, best price first, to fill order up to
+// its limit. Each level pays the taker fee rate × min(price, 1-price) ×
+// size, in shares on buys and in USDC on sells, as in CalculatePayout.
+func PreviewMatch(order PreviewOrder, terms MarketTerms, bids, asks []models.PriceLevel) (*OrderPreview, error) {
+	if !onTick(order.Price, terms.TickSize) {
+		return nil, ErrPriceOffTick
+	}
+	if order.Type == "" {
+		order.Type = models.OrderTypeGTC
+	}
+
+	p := &OrderPreview{
+		TokenID:    order.TokenID,
+		Side:       order.Side,
+		Type:       order.Type,
+		Price:      order.Price,
+		Size:       order.Size,
+		FeeRateBps: terms.FeeRateBps,
+		Fills:      []PreviewFill{},
+	}
+
+	// A buy takes the asks, cheapest first; a sell the bids, dearest first
+	buy := order.Side == models.SideBuy
+	levels := bookLevels(asks, false)
+	if !buy {
+		levels = bookLevels(bids, true)
+	}
+	if len(levels) > 0 {
+		best := levels[0].Price
+		p.BestPrice = &best
+	}
+
+	left := order.Size
+	var feeShares float64
+	for _, l := range levels {
+		if left < 1e-9 || (buy && l.Price > order.Price+1e-9) || (!buy && l.Price < order.Price-1e-9) {
+			break
+		}
+		size := math.Min(left, l.Size)
+		fee := terms.FeeRateBps / 10000 * math.Min(l.Price, 1-l.Price) * size
+		p.Fills = append(p.Fills, PreviewFill{Price: l.Price, Size: size})
+		p.FilledSize += size
+		p.Notional += l.Price * size
+		p.Fee += fee
+		feeShares += fee / l.Price
+		left -= size
+	}
+
+	if order.Type == models.OrderTypeFOK && left >= 1e-9 {
+		p.Fills, p.FilledSize, p.Notional, p.Fee, feeShares = []PreviewFill{}, 0, 0, 0, 0
+		p.Killed = true
+	}
+	p.RemainingSize = order.Size - p.FilledSize
+	if order.Type != models.OrderTypeFOK {
+		p.Resting = p.RemainingSize
+	}
+	if buy {
+		p.FeeShares = feeShares
+	}
+
+	if len(p.Fills) > 0 {
+		avg := round6(p.Notional / p.FilledSize)
+		worst := p.Fills[len(p.Fills)-1].Price
+		slippage := round6(avg - *p.BestPrice)
+		if !buy {
+			slippage = -slippage
+		}
+		p.AvgPrice, p.WorstPrice, p.Slippage = &avg, &worst, &slippage
+	}
+	for _, v := range []*float64{&p.FilledSize, &p.RemainingSize, &p.Notional, &p.Fee, &p.FeeShares, &p.Resting} {
+		*v = round6(*v)
+	}
+	for i := range p.Fills {
+		p.Fills[i].Size = round6(p.Fills[i].Size)
+	}
+	return p, nil
+}
+
+// bookLevels parses one side of a book, best price first, skipping
+// unparsable and empty levels
+func bookLevels(levels []models.PriceLevel, highest bool) []PreviewFill {
+	out := make([]PreviewFill, 0, len(levels))
+	for _, l := range levels {
+		price, err := strconv.ParseFloat(l.Price, 64)
+		if err != nil {
+			continue
+		}
+		size, err := strconv.ParseFloat(l.Size, 64)
+		if err != nil || size <= 0 {
+			continue
+		}
+		out = append(out, PreviewFill{Price: price, Size: size})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if highest {
+			return out[i].Price > out[j].Price
+		}
+		return out[i].Price < out[j].Price
+	})
+	return out
+}
+
+// round6 trims float noise to USDC's six decimals
+func round6(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
+
+// Preview matches order against the token's cached book under its current
+// terms; the bool reports whether the book came from the cache
+func (p *PayoutCalculator) Preview(order PreviewOrder) (*OrderPreview, bool, error) {
+	terms, err := p.Terms(order.TokenID)
+	if err != nil {
+		return nil, false, err
+	}
+	data, hit, err := p.clob.GetOrderBook(order.TokenID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var book struct {
+		Bids []models.PriceLevel `json:"bids"`
+		Asks []models.PriceLevel `json:"asks"`
+	}
+	if err := sonic.Unmarshal(data, &book); err != nil {
+		return nil, false, err
+	}
+	preview, err := PreviewMatch(order, *terms, book.Bids, book.Asks)
+	return preview, hit, err
+}
@@ -0,0 +1,215 @@
+package polymarket
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/models"
+)
+
+// Price mode anchors
+const (
+	AnchorMid     = "mid"
+	AnchorBestBid = "best_bid"
+	AnchorBestAsk = "best_ask"
+)
+
+var (
+	// ErrInvalidPriceMode is returned for a price mode that does not parse
+	ErrInvalidPriceMode = errors.New("price_mode must be mid, best_bid or best_ask, optionally followed by +N or -N ticks, e.g. best_bid+1tick")
+	// ErrNoAnchorPrice is returned when the book has no price to anchor on
+	ErrNoAnchorPrice = errors.New("the book has no price to anchor on")
+	// ErrPriceOutOfRange is returned when the offset takes the price off the
+	// token's price range
+	ErrPriceOutOfRange = errors.New("the resolved price is outside the token's price range")
+	// ErrPriceWithMode is returned for an order with both a price and a
+	// price mode
+	ErrPriceWithMode = errors.New("price must be omitted when price_mode is set")
+)
+
+var priceModePattern = regexp.MustCompile(`^(mid|best_bid|best_ask)(?:([+-])(\d{1,3})ticks?)?$`)
+
+// PriceMode prices an order relative to the book: an anchor moved by a
+// number of ticks
+type PriceMode struct {
+	Anchor string
+	Ticks  int // negative moves the price down
+}
+
+// ParsePriceMode parses a mode such as mid, best_bid+2ticks or
+// best_ask-1tick
+func ParsePriceMode(s string) (PriceMode, error) {
+	m := priceModePattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return PriceMode{}, ErrInvalidPriceMode
+	}
+	mode := PriceMode{Anchor: m[1]}
+	if m[3] != "" {
+		mode.Ticks, _ = strconv.Atoi(m[3])
+		if m[2] == "-" {
+			mode.Ticks = -mode.Ticks
+		}
+	}
+	return mode, nil
+}
+
+// Resolve prices an order on side against a book with the given tick size.
+// A midpoint between ticks is rounded away from the other side, down for
+// buys and up for sells, so it does not cross the spread by accident.
+func (m PriceMode) Resolve(side models.Side, bids, asks []models.PriceLevel, tick float64) (float64, error) {
+	if tick <= 0 {
+		tick = 0.01
+	}
+	bid, ask := bestLevel(bids, true), bestLevel(asks, false)
+
+	var anchor float64
+	switch m.Anchor {
+	case AnchorBestBid:
+		if bid == nil {
+			return 0, ErrNoAnchorPrice
+		}
+		anchor = *bid
+	case AnchorBestAsk:
+		if ask == nil {
+			return 0, ErrNoAnchorPrice
+		}
+		anchor = *ask
+	case AnchorMid:
+		if bid == nil || ask == nil {
+			return 0, ErrNoAnchorPrice
+		}
+		anchor = (*bid + *ask) / 2
+	default:
+		return 0, ErrInvalidPriceMode
+	}
+
+	steps := anchor / tick
+	if side == models.SideBuy {
+		steps = math.Floor(steps + 1e-6)
+	} else {
+		steps = math.Ceil(steps - 1e-6)
+	}
+	steps += float64(m.Ticks)
+
+	price := math.Round(steps*tick*1e6) / 1e6
+	if !onTick(price, tick) {
+		return 0, ErrPriceOutOfRange
+	}
+	return price, nil
+}
+
+// PriceModeError is a price mode that could not be resolved, with the
+// index of its order
+type PriceModeError struct {
+	Order int
+	Err   error
+}
+
+func (e *PriceModeError) Error() string {
+	return fmt.Sprintf("order %d: %v", e.Order, e.Err)
+}
+
+func (e *PriceModeError) Unwrap() error { return e.Err }
+
+// PriceResolver resolves the price modes of orders against the live book
+type PriceResolver struct {
+	clob *ClobClient
+}
+
+// NewPriceResolver creates a new price resolver
+func NewPriceResolver(clob *ClobClient) *PriceResolver {
+	return &PriceResolver{clob: clob}
+}
+
+// Resolve sets the price of every order with a price mode, fetching the
+// books of their tokens in one uncached request. Errors about a single
+// order are *PriceModeError; others are upstream failures.
+func (r *PriceResolver) Resolve(orders []models.CreateOrderRequest) error {
+	type pending struct {
+		order int
+		mode  PriceMode
+	}
+	var modes []pending
+	var tokens []string
+	seen := make(map[string]bool)
+	for i, o := range orders {
+		if o.PriceMode == "" {
+			continue
+		}
+		if o.Price != "" {
+			return &PriceModeError{Order: i, Err: ErrPriceWithMode}
+		}
+		mode, err := ParsePriceMode(o.PriceMode)
+		if err != nil {
+			return &PriceModeError{Order: i, Err: err}
+		}
+		modes = append(modes, pending{order: i, mode: mode})
+		if !seen[o.TokenID] {
+			seen[o.TokenID] = true
+			tokens = append(tokens, o.TokenID)
+		}
+	}
+	if len(modes) == 0 {
+		return nil
+	}
+
+	data, err := r.clob.GetOrderBooks(tokens)
+	if err != nil {
+		return err
+	}
+	var books []struct {
+		AssetID  string              `json:"asset_id"`
+		Bids     []models.PriceLevel `json:"bids"`
+		Asks     []models.PriceLevel `json:"asks"`
+		TickSize models.FlexString   `json:"tick_size"`
+	}
+	if err := sonic.Unmarshal(data, &books); err != nil {
+		return err
+	}
+
+	for _, p := range modes {
+		o := &orders[p.order]
+		found := false
+		for _, b := range books {
+			if b.AssetID != o.TokenID {
+				continue
+			}
+			found = true
+			tick := b.TickSize.Float()
+			if tick <= 0 {
+				tick = r.tickSize(o.TokenID)
+			}
+			price, err := p.mode.Resolve(o.Side, b.Bids, b.Asks, tick)
+			if err != nil {
+				return &PriceModeError{Order: p.order, Err: err}
+			}
+			o.Price = strconv.FormatFloat(price, 'f', -1, 64)
+			break
+		}
+		if !found {
+			return &PriceModeError{Order: p.order, Err: ErrNoAnchorPrice}
+		}
+	}
+	return nil
+}
+
+// tickSize looks up a token's tick size when its book does not carry it;
+// 0 falls back to a cent
+func (r *PriceResolver) tickSize(tokenID string) float64 {
+	data, _, err := r.clob.GetTickSize(tokenID)
+	if err != nil {
+		return 0
+	}
+	var tick struct {
+		MinimumTickSize float64 `json:"minimum_tick_size"`
+	}
+	if sonic.Unmarshal(data, &tick) != nil {
+		return 0
+	}
+	return tick.MinimumTickSize
+}
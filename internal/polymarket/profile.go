@@ -0,0 +1,177 @@
+package polymarket
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/latency"
+	"github.com/polygo/internal/models"
+)
+
+// Upstream page sizes a trader profile is computed from
+const (
+	profilePositionsLimit = 500
+	profileTradesLimit    = 100
+	profileActivityLimit  = 50
+)
+
+// TraderProfile is everything a trader page needs in one response
+type TraderProfile struct {
+	Address      string            `json:"address"`
+	Stats        TraderStats       `json:"stats"`
+	Positions    json.RawMessage   `json:"positions"`
+	RecentTrades json.RawMessage   `json:"recent_trades"`
+	Activity     json.RawMessage   `json:"activity"`
+	Errors       map[string]string `json:"errors,omitempty"` // partial failures by section
+}
+
+// TraderStats are computed from a trader's positions and recent trades
+type TraderStats struct {
+	Volume            float64  `json:"volume"` // USDC traded across recent_trades
+	Trades            int      `json:"trades"`
+	OpenPositions     int      `json:"open_positions"`
+	Exposure          float64  `json:"exposure"` // current value of open positions
+	UnrealizedPnL     float64  `json:"unrealized_pnl"`
+	RealizedPnL       float64  `json:"realized_pnl"`
+	ResolvedPositions int      `json:"resolved_positions"`
+	Wins              int      `json:"wins"`
+	WinRate           *float64 `json:"win_rate"` // wins / resolved positions; nil until one resolves
+}
+
+// profilePosition holds the Data API position fields the stats use
+type profilePosition struct {
+	CurPrice     models.FlexString `json:"curPrice"`
+	CurrentValue models.FlexString `json:"currentValue"`
+	CashPnL      models.FlexString `json:"cashPnl"`
+	RealizedPnL  models.FlexString `json:"realizedPnl"`
+	Redeemable   bool              `json:"redeemable"`
+}
+
+// profileTrade holds the Data API trade fields the stats use
+type profileTrade struct {
+	Size  models.FlexString `json:"size"`
+	Price models.FlexString `json:"price"`
+}
+
+// TraderProfileService composes a trader's positions, recent trades and
+// activity with stats computed from them, caching the result like other
+// user data
+type TraderProfileService struct {
+	data *DataClient
+}
+
+// NewTraderProfileService creates a new trader profile service
+func NewTraderProfileService(data *DataClient) *TraderProfileService {
+	return &TraderProfileService{data: data}
+}
+
+// Namespace returns a view of the service whose cached profiles and user
+// data are only shared within namespace, as with DataClient.Namespace
+func (s *TraderProfileService) Namespace(namespace string) *TraderProfileService {
+	return &TraderProfileService{data: s.data.Namespace(namespace)}
+}
+
+// Get returns the profile of an address. The bool reports a cache hit;
+// fresh bypasses cached copies.
+func (s *TraderProfileService) Get(address string, fresh bool) (*TraderProfile, bool, error) {
+	address = strings.ToLower(address)
+	c := s.data.client.cache
+	key := s.data.userDataKey(address, "profile")
+
+	var cached TraderProfile
+	if !fresh && c.GetJSON(key, &cached) {
+		return &cached, true, nil
+	}
+
+	profile := &TraderProfile{Address: address, Errors: make(map[string]string)}
+	errs := make(map[string]error)
+	var mu sync.Mutex
+	section := func(name string, dst *json.RawMessage, fetch func() ([]byte, bool, error)) func() {
+		return func() {
+			data, _, err := fetch()
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[name] = err
+				profile.Errors[name] = err.Error()
+				return
+			}
+			*dst = data
+		}
+	}
+
+	fetches := []func(){
+		section("positions", &profile.Positions, func() ([]byte, bool, error) {
+			return s.data.GetPositions(address, profilePositionsLimit, "", fresh)
+		}),
+		section("recent_trades", &profile.RecentTrades, func() ([]byte, bool, error) {
+			return s.data.GetTrades(address, profileTradesLimit, "", fresh)
+		}),
+		section("activity", &profile.Activity, func() ([]byte, bool, error) {
+			return s.data.GetActivity(address, profileActivityLimit, "", fresh)
+		}),
+	}
+	var wg sync.WaitGroup
+	bind := latency.Inherit()
+	for _, fetch := range fetches {
+		wg.Add(1)
+		go func(fetch func()) {
+			defer wg.Done()
+			defer bind()()
+			fetch()
+		}(fetch)
+	}
+	wg.Wait()
+
+	// Nothing to show: report the upstream failure rather than an empty profile
+	if len(errs) == len(fetches) {
+		return nil, false, errs["positions"]
+	}
+	profile.Stats = traderStats(profile.Positions, profile.RecentTrades)
+
+	// Only complete profiles are cached, so a transient failure is not
+	// served for the whole TTL
+	if len(profile.Errors) == 0 {
+		c.SetJSON(key, profile, c.GetConfig().UserDataTTL)
+	}
+	return profile, false, nil
+}
+
+// traderStats computes profile stats. A position counts as resolved once
+// it is redeemable or priced at 0 or 1, and as a win when priced at 1.
+func traderStats(positionsData, tradesData []byte) TraderStats {
+	var stats TraderStats
+
+	var positions []profilePosition
+	if sonic.Unmarshal(positionsData, &positions) == nil {
+		for _, p := range positions {
+			price := p.CurPrice.Float()
+			stats.RealizedPnL += p.RealizedPnL.Float()
+			if p.Redeemable || price <= 0 || price >= 1 {
+				stats.ResolvedPositions++
+				if price >= 1 {
+					stats.Wins++
+				}
+				continue
+			}
+			stats.OpenPositions++
+			stats.Exposure += p.CurrentValue.Float()
+			stats.UnrealizedPnL += p.CashPnL.Float()
+		}
+	}
+	if stats.ResolvedPositions > 0 {
+		rate := float64(stats.Wins) / float64(stats.ResolvedPositions)
+		stats.WinRate = &rate
+	}
+
+	var trades []profileTrade
+	if sonic.Unmarshal(tradesData, &trades) == nil {
+		stats.Trades = len(trades)
+		for _, t := range trades {
+			stats.Volume += t.Size.Float() * t.Price.Float()
+		}
+	}
+	return stats
+}
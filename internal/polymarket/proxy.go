@@ -0,0 +1,317 @@
+package polymarket
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/polygo/internal/config"
+)
+
+// ProxyDirect as a host's proxy bypasses the default proxy
+const ProxyDirect = "direct"
+
+// ProxyDialer opens upstream connections directly or through the HTTP
+// (CONNECT) or SOCKS5 proxy configured for the upstream's host
+type ProxyDialer struct {
+	direct   *Dialer             // reaches upstreams and proxies
+	fallback *url.URL            // nil dials directly
+	perHost  map[string]*url.URL // by lower-case host; nil dials directly
+	err      error               // invalid config: every dial fails rather than bypass the proxy
+}
+
+// NewProxyDialer creates the dialer of the configured proxies. With an
+// invalid proxy URL it returns the error, and a dialer that refuses to
+// connect anywhere.
+func NewProxyDialer(cfg *config.PolymarketConfig) (*ProxyDialer, error) {
+	d := &ProxyDialer{
+		direct:  NewDialer(cfg),
+		perHost: make(map[string]*url.URL, len(cfg.Proxies)),
+	}
+
+	var err error
+	if d.fallback, err = parseProxy(cfg.Proxy); err != nil {
+		d.err = fmt.Errorf("polymarket.proxy: %w", err)
+		return d, d.err
+	}
+	for host, raw := range cfg.Proxies {
+		p, err := parseProxy(raw)
+		if err != nil {
+			d.err = fmt.Errorf("polymarket.proxies.%s: %w", host, err)
+			return d, d.err
+		}
+		d.perHost[strings.ToLower(host)] = p
+	}
+	return d, nil
+}
+
+// parseProxy parses an http://, socks5:// or socks5h:// proxy URL; empty
+// and "direct" mean no proxy
+func parseProxy(raw string) (*url.URL, error) {
+	if raw == "" || raw == ProxyDirect {
+		return nil, nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, errors.New("invalid proxy URL")
+	}
+	switch u.Scheme {
+	case "http", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("proxy scheme must be http, socks5 or socks5h, not %q", u.Scheme)
+	}
+	if u.Hostname() == "" || u.Port() == "" {
+		return nil, errors.New("proxy URL needs a host and port")
+	}
+	return u, nil
+}
+
+// Enabled reports whether any upstream goes through a proxy
+func (d *ProxyDialer) Enabled() bool {
+	if d.fallback != nil || d.err != nil {
+		return true
+	}
+	for _, p := range d.perHost {
+		if p != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ProxyFor returns the proxy of an upstream host, nil for direct
+func (d *ProxyDialer) ProxyFor(host string) *url.URL {
+	if p, ok := d.perHost[strings.ToLower(host)]; ok {
+		return p
+	}
+	return d.fallback
+}
+
+// Proxies returns the proxies in use by health check name: "proxy" for
+// the default one, "proxy:<host>" for a host's own
+func (d *ProxyDialer) Proxies() map[string]*url.URL {
+	out := make(map[string]*url.URL)
+	if d.fallback != nil {
+		out["proxy"] = d.fallback
+	}
+	for host, p := range d.perHost {
+		if p != nil {
+			out["proxy:"+host] = p
+		}
+	}
+	return out
+}
+
+// Dial connects to addr (host:port) for fasthttp clients
+func (d *ProxyDialer) Dial(addr string) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.direct.timeout)
+	defer cancel()
+	return d.DialContext(ctx, "tcp", addr)
+}
+
+// DialContext connects to addr (host:port), through its host's proxy if any
+func (d *ProxyDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := d.ProxyFor(host)
+	if p == nil {
+		return d.direct.DialContext(ctx, network, addr)
+	}
+
+	conn, err := d.direct.DialContext(ctx, "tcp", p.Host)
+	if err != nil {
+		return nil, fmt.Errorf("proxy %s unreachable: %w", p.Host, err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(d.direct.timeout)
+	}
+	conn.SetDeadline(deadline)
+
+	if p.Scheme == "http" {
+		err = httpConnect(conn, p, addr)
+	} else {
+		err = socks5Connect(ctx, conn, p, addr, d.direct.resolve)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s: %w", p.Host, err)
+	}
+	conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+// httpConnect opens a tunnel to addr with CONNECT
+func httpConnect(conn net.Conn, p *url.URL, addr string) error {
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if p.User != nil {
+		password, _ := p.User.Password()
+		req.Header.Set("Proxy-Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(p.User.Username()+":"+password)))
+	}
+	if err := req.Write(conn); err != nil {
+		return err
+	}
+
+	// The tunnel carries nothing until the client speaks, so the reader
+	// can't have buffered any of it
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("CONNECT %s: %s", addr, resp.Status)
+	}
+	return nil
+}
+
+// socks5Replies explains SOCKS5 reply codes
+var socks5Replies = map[byte]string{
+	1: "general failure",
+	2: "connection not allowed by ruleset",
+	3: "network unreachable",
+	4: "host unreachable",
+	5: "connection refused",
+	6: "TTL expired",
+	7: "command not supported",
+	8: "address type not supported",
+}
+
+// socks5Connect asks a SOCKS5 proxy to connect to addr (RFC 1928), with
+// username/password authentication (RFC 1929) when the URL has a user.
+// socks5:// resolves the host with resolve, socks5h:// leaves it to the proxy.
+func socks5Connect(ctx context.Context, conn net.Conn, p *url.URL, addr string, resolve func(ctx context.Context, host string) ([]net.IP, error)) error {
+	host, portStr, _ := net.SplitHostPort(addr)
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return fmt.Errorf("invalid port in %s", addr)
+	}
+
+	methods := []byte{0x00}
+	if p.User != nil {
+		methods = []byte{0x02}
+	}
+	if _, err := conn.Write(append([]byte{5, byte(len(methods))}, methods...)); err != nil {
+		return err
+	}
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return err
+	}
+	if reply[0] != 5 {
+		return errors.New("not a SOCKS5 proxy")
+	}
+	switch reply[1] {
+	case 0x00:
+	case 0x02:
+		password, _ := p.User.Password()
+		user := p.User.Username()
+		if len(user) > 255 || len(password) > 255 {
+			return errors.New("SOCKS5 username and password are limited to 255 bytes")
+		}
+		auth := append([]byte{1, byte(len(user))}, user...)
+		auth = append(append(auth, byte(len(password))), password...)
+		if _, err := conn.Write(auth); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(conn, reply); err != nil {
+			return err
+		}
+		if reply[1] != 0 {
+			return errors.New("SOCKS5 authentication failed")
+		}
+	default:
+		return errors.New("SOCKS5 proxy accepts none of our authentication methods")
+	}
+
+	if p.Scheme == "socks5" && net.ParseIP(host) == nil {
+		ips, err := resolve(ctx, host)
+		if err != nil {
+			return err
+		}
+		host = ips[0].String()
+	}
+
+	req := []byte{5, 1, 0}
+	if ip := net.ParseIP(host); ip == nil {
+		if len(host) > 255 {
+			return errors.New("host name too long for SOCKS5")
+		}
+		req = append(append(req, 3, byte(len(host))), host...)
+	} else if ip4 := ip.To4(); ip4 != nil {
+		req = append(append(req, 1), ip4...)
+	} else {
+		req = append(append(req, 4), ip.To16()...)
+	}
+	req = binary.BigEndian.AppendUint16(req, uint16(port))
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	head := make([]byte, 4)
+	if _, err := io.ReadFull(conn, head); err != nil {
+		return err
+	}
+	if head[1] != 0 {
+		msg, ok := socks5Replies[head[1]]
+		if !ok {
+			msg = fmt.Sprintf("reply %d", head[1])
+		}
+		return fmt.Errorf("connect %s: %s", addr, msg)
+	}
+
+	// Skip the bound address
+	var skip int
+	switch head[3] {
+	case 1:
+		skip = net.IPv4len + 2
+	case 4:
+		skip = net.IPv6len + 2
+	case 3:
+		n := make([]byte, 1)
+		if _, err := io.ReadFull(conn, n); err != nil {
+			return err
+		}
+		skip = int(n[0]) + 2
+	default:
+		return errors.New("invalid SOCKS5 reply")
+	}
+	_, err = io.ReadFull(conn, make([]byte, skip))
+	return err
+}
+
+// newWSDialer returns the dialer of upstream WebSockets: through the
+// configured proxies, else through HTTPS_PROXY if set
+func newWSDialer(cfg *config.PolymarketConfig) *websocket.Dialer {
+	proxies, _ := NewProxyDialer(cfg)
+	dialer := &websocket.Dialer{
+		NetDialContext:   proxies.DialContext,
+		HandshakeTimeout: websocket.DefaultDialer.HandshakeTimeout,
+	}
+	if !proxies.Enabled() {
+		dialer.Proxy = http.ProxyFromEnvironment
+	}
+	return dialer
+}
@@ -0,0 +1,130 @@
+package polymarket
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polygo/internal/config"
+	"github.com/valyala/fasthttp"
+)
+
+// retryPolicy decides whether and when a failed upstream request is retried
+type retryPolicy struct {
+	maxRetries int
+	baseWait   time.Duration
+	maxWait    time.Duration
+	methods    map[string]bool
+
+	budget *retryBudget
+}
+
+// newRetryPolicy builds the retry policy from configuration
+func newRetryPolicy(cfg *config.PolymarketConfig) *retryPolicy {
+	methods := make(map[string]bool, len(cfg.RetryMethods))
+	for _, m := range cfg.RetryMethods {
+		methods[strings.ToUpper(strings.TrimSpace(m))] = true
+	}
+
+	maxWait := cfg.RetryMaxWait
+	if maxWait <= 0 {
+		maxWait = cfg.RetryWaitTime
+	}
+
+	return &retryPolicy{
+		maxRetries: cfg.RetryCount,
+		baseWait:   cfg.RetryWaitTime,
+		maxWait:    maxWait,
+		methods:    methods,
+		budget:     newRetryBudget(cfg.RetryBudgetRatio, cfg.RetryBudgetBurst),
+	}
+}
+
+// retryable reports whether requests with this method may be retried at
+// all. Non-idempotent methods such as POST /order are never retried so an
+// order is not placed twice.
+func (p *retryPolicy) retryable(method string) bool {
+	return p.methods[method]
+}
+
+// backoff returns the wait before retry n (1-based): exponential growth
+// capped at maxWait, with equal jitter so callers failing together do not
+// retry together
+func (p *retryPolicy) backoff(n int) time.Duration {
+	wait := p.baseWait
+	for i := 1; i < n && wait < p.maxWait; i++ {
+		wait *= 2
+	}
+	if wait > p.maxWait {
+		wait = p.maxWait
+	}
+	if wait <= 0 {
+		return 0
+	}
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(wait-half)+1))
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP
+// date, returning 0 when absent or invalid
+func retryAfter(resp *fasthttp.Response) time.Duration {
+	value := strings.TrimSpace(string(resp.Header.Peek(fasthttp.HeaderRetryAfter)))
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := time.Parse(time.RFC1123, value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// retryBudget caps retries to a fraction of overall request volume. Every
+// request earns ratio tokens and every retry spends one, so during an
+// upstream outage retries dry up instead of multiplying load.
+type retryBudget struct {
+	mu     sync.Mutex
+	ratio  float64
+	burst  float64
+	tokens float64
+}
+
+// newRetryBudget creates a retry budget that starts full. A non-positive
+// burst disables the budget.
+func newRetryBudget(ratio float64, burst int) *retryBudget {
+	return &retryBudget{ratio: ratio, burst: float64(burst), tokens: float64(burst)}
+}
+
+// deposit records a new request
+func (b *retryBudget) deposit() {
+	if b.burst <= 0 {
+		return
+	}
+	b.mu.Lock()
+	b.tokens += b.ratio
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.mu.Unlock()
+}
+
+// withdraw spends one token for a retry, reporting false when the budget
+// is exhausted
+func (b *retryBudget) withdraw() bool {
+	if b.burst <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
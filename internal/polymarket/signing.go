@@ -0,0 +1,22 @@
+package polymarket
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/polygo/internal/config"
+	"github.com/polygo/pkg/polygoclient"
+)
+
+// SignedHeaders builds the L2 auth headers for a request PolyGo makes on
+// an account's behalf (copy trading, expiry cancellation), under the same
+// header names it relays for callers. The secret itself is never sent.
+func SignedHeaders(cfg *config.AuthConfig, creds *polygoclient.Credentials, method, path string, body []byte) map[string]string {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	return map[string]string{
+		cfg.APIKeyHeader:     creds.APIKey,
+		cfg.PassphraseHeader: creds.Passphrase,
+		cfg.TimestampHeader:  ts,
+		cfg.SignatureHeader:  creds.Sign(ts, method, path, body),
+	}
+}
@@ -0,0 +1,165 @@
+package polymarket
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/latency"
+	"github.com/polygo/internal/models"
+)
+
+// TokenSnapshot is a one-shot view of a token's top of book and recent
+// price action. Fields are nil when the upstream had no value.
+type TokenSnapshot struct {
+	TokenID   string   `json:"token_id"`
+	Bid       *float64 `json:"bid"`
+	Ask       *float64 `json:"ask"`
+	Mid       *float64 `json:"mid"`
+	Last      *float64 `json:"last"`
+	Spread    *float64 `json:"spread"`
+	Change24h *float64 `json:"change_24h"`
+	BidDepth  float64  `json:"bid_depth"` // total size resting on the bid side
+	AskDepth  float64  `json:"ask_depth"` // total size resting on the ask side
+	Error     string   `json:"error,omitempty"`
+}
+
+// SnapshotService assembles token snapshots from the CLOB and Data APIs,
+// fanning requests out with bounded concurrency
+type SnapshotService struct {
+	clob        *ClobClient
+	data        *DataClient
+	concurrency int
+}
+
+// NewSnapshotService creates a new snapshot service
+func NewSnapshotService(clob *ClobClient, data *DataClient, concurrency int) *SnapshotService {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &SnapshotService{clob: clob, data: data, concurrency: concurrency}
+}
+
+// Get returns snapshots for the tokens, in the same order
+func (s *SnapshotService) Get(tokenIDs []string) []TokenSnapshot {
+	out := make([]TokenSnapshot, len(tokenIDs))
+	sem := make(chan struct{}, s.concurrency)
+
+	var wg sync.WaitGroup
+	bind := latency.Inherit()
+	for i, id := range tokenIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, id string) {
+			defer wg.Done()
+			defer bind()()
+			defer func() { <-sem }()
+			out[i] = s.snapshot(id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	return out
+}
+
+// snapshot builds a single token snapshot; each upstream call goes
+// through its own cache
+func (s *SnapshotService) snapshot(tokenID string) TokenSnapshot {
+	snap := TokenSnapshot{TokenID: tokenID}
+
+	data, _, err := s.clob.GetOrderBook(tokenID)
+	if err != nil {
+		snap.Error = err.Error()
+		return snap
+	}
+	var book models.OrderBook
+	if err := sonic.Unmarshal(data, &book); err == nil {
+		snap.Bid = bestLevel(book.Bids, true)
+		snap.Ask = bestLevel(book.Asks, false)
+		snap.BidDepth = totalSize(book.Bids)
+		snap.AskDepth = totalSize(book.Asks)
+		if snap.Bid != nil && snap.Ask != nil {
+			mid := (*snap.Bid + *snap.Ask) / 2
+			spread := *snap.Ask - *snap.Bid
+			snap.Mid, snap.Spread = &mid, &spread
+		}
+	}
+
+	if data, _, err := s.clob.GetLastTradePrice(tokenID); err == nil {
+		var last struct {
+			Price string `json:"price"`
+		}
+		if sonic.Unmarshal(data, &last) == nil {
+			snap.Last = parseFloatPtr(last.Price)
+		}
+	}
+
+	snap.Change24h = s.change24h(tokenID)
+	return snap
+}
+
+// change24h returns the price change over the last day from price history.
+// It moves slowly, so it is cached for MarketsTTL rather than PricesTTL.
+func (s *SnapshotService) change24h(tokenID string) *float64 {
+	c := s.data.client.cache
+	key := cache.PriceKey("change24h:" + tokenID)
+
+	var cached float64
+	if c.GetJSON(key, &cached) {
+		return &cached
+	}
+
+	data, err := s.data.GetPriceHistory(tokenID, "1d", 60)
+	if err != nil {
+		return nil
+	}
+	var history struct {
+		History []struct {
+			P float64 `json:"p"`
+		} `json:"history"`
+	}
+	if err := sonic.Unmarshal(data, &history); err != nil || len(history.History) < 2 {
+		return nil
+	}
+
+	change := history.History[len(history.History)-1].P - history.History[0].P
+	c.SetJSON(key, change, c.GetConfig().MarketsTTL)
+	return &change
+}
+
+// bestLevel returns the best price among levels regardless of the order
+// the upstream listed them in
+func bestLevel(levels []models.PriceLevel, highest bool) *float64 {
+	var best *float64
+	for _, l := range levels {
+		p := parseFloatPtr(l.Price)
+		if p == nil {
+			continue
+		}
+		if best == nil || (highest && *p > *best) || (!highest && *p < *best) {
+			best = p
+		}
+	}
+	return best
+}
+
+// totalSize sums the size of every level
+func totalSize(levels []models.PriceLevel) float64 {
+	var total float64
+	for _, l := range levels {
+		if p := parseFloatPtr(l.Size); p != nil {
+			total += *p
+		}
+	}
+	return total
+}
+
+// parseFloatPtr parses s, returning nil when it is empty or invalid
+func parseFloatPtr(s string) *float64 {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil
+	}
+	return &f
+}
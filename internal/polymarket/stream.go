@@ -0,0 +1,131 @@
+package polymarket
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/polygo/internal/latency"
+	"github.com/valyala/fasthttp"
+)
+
+// Stream is an upstream response body too large to buffer, read straight
+// from the upstream connection. Close releases the connection; handing the
+// stream to fiber's SendStream closes it once the response is written.
+type Stream struct {
+	Size int // body size in bytes, or -1 when the upstream did not say
+
+	r      io.Reader
+	eof    bool
+	client *Client
+	resp   *fasthttp.Response
+}
+
+func (s *Stream) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if err == io.EOF {
+		s.eof = true
+	}
+	return n, err
+}
+
+// Close releases the upstream response. The connection is reused only when
+// the body was read to the end, since the rest of it would otherwise be
+// taken for the next response.
+func (s *Stream) Close() error {
+	if s.resp == nil {
+		return nil
+	}
+	if !s.eof {
+		s.resp.SetConnectionClose()
+	}
+	s.client.releaseResponse(s.resp)
+	s.resp = nil
+	return nil
+}
+
+// GetStream performs a GET request, returning the body whole when it is at
+// most StreamThreshold bytes and as a Stream otherwise. Exactly one of the
+// two is set on success. Streamed bodies are neither validated nor
+// recorded, so a threshold of 0 and the tape always buffer.
+func (c *Client) GetStream(url string, opts *RequestOptions) ([]byte, *Stream, error) {
+	if c.streamClient == nil || c.tape.Recording() || c.tape.Replaying() {
+		data, err := c.Get(url, opts)
+		return data, nil, err
+	}
+
+	// Counts the time to the response headers, not how long the client
+	// takes to read a stream
+	done := latency.StartUpstream()
+	resp := c.acquireResponse()
+	err := c.roundTrip(c.streamClient, "GET", url, nil, opts, resp)
+	done()
+	if err != nil {
+		c.releaseResponse(resp)
+		return nil, nil, err
+	}
+
+	body := resp.BodyStream()
+	if body == nil {
+		// Small enough to have been read with the headers
+		defer c.releaseResponse(resp)
+		return c.validated(url, resp.SwapBody(nil))
+	}
+
+	threshold := c.config.StreamThreshold
+	size := resp.Header.ContentLength()
+	if size < 0 {
+		size = -1
+	}
+	stream := &Stream{Size: size, r: body, client: c, resp: resp}
+	if size > threshold {
+		return nil, stream, nil
+	}
+
+	// Read up to the threshold to find out whether a body of unknown size
+	// is small after all
+	head, err := io.ReadAll(io.LimitReader(body, int64(threshold)+1))
+	if err != nil {
+		stream.Close()
+		return nil, nil, err
+	}
+	if len(head) <= threshold {
+		stream.eof = true
+		stream.Close()
+		return c.validated(url, head)
+	}
+	stream.r = io.MultiReader(bytes.NewReader(head), body)
+	return nil, stream, nil
+}
+
+// validated returns a buffered body once it passes validation
+func (c *Client) validated(url string, data []byte) ([]byte, *Stream, error) {
+	if err := c.validate(url, data); err != nil {
+		return nil, nil, err
+	}
+	return data, nil, nil
+}
+
+// GetWithCacheOrStream is GetWithCache for responses that may be too large
+// to buffer: bodies above StreamThreshold come back as a Stream, which is
+// neither cached nor shared with concurrent callers
+func (c *Client) GetWithCacheOrStream(url, cacheKey string, ttl time.Duration) ([]byte, bool, *Stream, error) {
+	if c.streamClient == nil {
+		data, cached, err := c.GetWithCache(url, cacheKey, ttl)
+		return data, cached, nil, err
+	}
+
+	if data, found := c.cache.Get(cacheKey); found {
+		return data, true, nil, nil
+	}
+	if c.shed != nil && c.shed() {
+		return nil, false, nil, ErrOverloaded
+	}
+
+	data, stream, err := c.GetStream(url, nil)
+	if err != nil || stream != nil {
+		return nil, false, stream, err
+	}
+	c.cache.SetNoCopy(cacheKey, data, ttl)
+	return data, false, nil, nil
+}
@@ -0,0 +1,180 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/polygo/internal/config"
+)
+
+// UserStream is an authenticated connection to the CLOB user channel,
+// which reports the order updates and trades of one API key. It
+// reconnects with backoff until Close; events sent while it is down are
+// lost, as the channel has no replay.
+type UserStream struct {
+	config *config.PolymarketConfig
+	dialer *websocket.Dialer
+	auth   WSAuth
+	handle func(data []byte)
+
+	mu   sync.Mutex
+	conn *websocket.Conn // nil while disconnected
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewUserStream connects to the user channel with auth in the background
+// and calls handle with every frame received, from a single goroutine
+func NewUserStream(cfg *config.PolymarketConfig, auth WSAuth, handle func(data []byte)) *UserStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &UserStream{
+		config: cfg,
+		dialer: newWSDialer(cfg),
+		auth:   auth,
+		handle: handle,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	s.wg.Add(1)
+	go s.run()
+	return s
+}
+
+// Connected reports whether the stream is currently subscribed
+func (s *UserStream) Connected() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conn != nil
+}
+
+// Close disconnects and waits for the stream's goroutine to finish. It must
+// not be called from handle.
+func (s *UserStream) Close() {
+	s.cancel()
+	s.mu.Lock()
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// run dials, subscribes and reads until Close, backing off between attempts
+func (s *UserStream) run() {
+	defer s.wg.Done()
+
+	backoff := time.Second
+	maxBackoff := 30 * time.Second
+
+	for {
+		start := time.Now()
+		if conn, err := s.connect(); err == nil {
+			s.serve(conn)
+		}
+		if s.ctx.Err() != nil {
+			return
+		}
+
+		// A connection that held for a while resets the backoff
+		if time.Since(start) > maxBackoff {
+			backoff = time.Second
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// connect dials the user channel and subscribes with the stream's
+// credentials. The channel is subscribed to by its name; with no markets
+// listed it reports the key's events in every market.
+func (s *UserStream) connect() (*websocket.Conn, error) {
+	header := http.Header{}
+	for k, v := range s.config.ExtraHeaders {
+		header.Set(k, v)
+	}
+	conn, _, err := s.dialer.DialContext(s.ctx, s.config.WsUserURL, header)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.WriteJSON(WSMessage{Type: WSMessageType(WSChannelUser), Auth: &s.auth}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Close may have run while dialing
+	if s.ctx.Err() != nil {
+		conn.Close()
+		return nil, s.ctx.Err()
+	}
+	s.conn = conn
+	return conn, nil
+}
+
+// serve reads frames until the connection drops. With pings enabled, a
+// connection that answers neither pings nor with messages for
+// WsPingInterval plus WsPongTimeout is considered dead.
+func (s *UserStream) serve(conn *websocket.Conn) {
+	defer func() {
+		s.mu.Lock()
+		s.conn = nil
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	interval, timeout := s.config.WsPingInterval, s.config.WsPongTimeout
+	extend := func() {
+		if interval > 0 {
+			conn.SetReadDeadline(time.Now().Add(interval + timeout))
+		}
+	}
+	conn.SetPongHandler(func(string) error {
+		extend()
+		return nil
+	})
+	extend()
+
+	if interval > 0 {
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case now := <-ticker.C:
+					if conn.WriteControl(websocket.PingMessage, nil, now.Add(timeout)) != nil {
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		extend()
+		s.handle(message)
+	}
+}
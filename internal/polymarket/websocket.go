@@ -4,12 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/gorilla/websocket"
 	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/tape"
 )
 
 // WSMessageType represents WebSocket message types
@@ -52,38 +58,96 @@ type WSAuth struct {
 	Signature  string `json:"signature"`
 }
 
-// WSManager manages WebSocket connections to Polymarket
+// WSManager manages WebSocket connections to Polymarket. Market
+// subscriptions are spread over WsShards upstream connections by
+// rendezvous hashing; when a shard drops, its markets move to the
+// remaining shards and move back once it reconnects.
 type WSManager struct {
 	config     *config.PolymarketConfig
-	clobConn   *websocket.Conn
+	dialer     *websocket.Dialer // through the configured proxies
+	shards     []*wsShard
 	liveConn   *websocket.Conn
 	mu         sync.RWMutex
 	
 	// Subscriptions
 	marketSubs map[string][]chan []byte
 	userSubs   map[string]chan []byte
+	assigned   map[string]*wsShard // market -> shard it is subscribed on
+	
+	// Last message received per channel (unix ms), for /health
+	lastMessage map[WSChannel]*atomic.Int64
+	
+	// Messages not delivered to a subscriber whose channel was full, and
+	// shard drops that lost messages of subscribed markets
+	dropped atomic.Uint64
+	gaps    atomic.Uint64
+	
+	// Record/replay of upstream frames; nil when off
+	tape *tape.Tape
 	
 	// Callbacks
 	onMessage  func(channel WSChannel, data []byte)
 	onError    func(err error)
 	onConnect  func()
 	onDisconnect func()
+	observers  []func(channel WSChannel, data []byte)
+	gapObservers []func(markets []string)
 	
 	// State
-	connected  bool
+	connected  bool // at least one shard is connected
+	started    bool
 	ctx        context.Context
 	cancel     context.CancelFunc
 	wg         sync.WaitGroup
 }
 
+// wsShard is one upstream CLOB WebSocket connection
+type wsShard struct {
+	index     int
+	conn      *websocket.Conn // nil while disconnected
+	writeMu   sync.Mutex
+	
+	// Liveness, all unix ms
+	lastMessage atomic.Int64
+	lastPing    atomic.Int64
+	lastPong    atomic.Int64
+	lag         atomic.Int64 // receipt time minus event time of the latest timestamped message
+}
+
+// ShardStatus describes one upstream WebSocket connection
+type ShardStatus struct {
+	Index       int   `json:"index"`
+	Connected   bool  `json:"connected"`
+	Markets     int   `json:"markets"`         // markets currently subscribed on this shard
+	LastMessage int64 `json:"last_message_ms"` // unix ms, 0 if never
+	LastPong    int64 `json:"last_pong_ms"`    // unix ms, 0 if never
+	Lag         int64 `json:"lag_ms"`          // how far behind the upstream event time its latest message arrived
+}
+
 // NewWSManager creates a new WebSocket manager
 func NewWSManager(cfg *config.PolymarketConfig) *WSManager {
 	ctx, cancel := context.WithCancel(context.Background())
 	
+	n := cfg.WsShards
+	if n <= 0 {
+		n = 1
+	}
+	shards := make([]*wsShard, n)
+	for i := range shards {
+		shards[i] = &wsShard{index: i}
+	}
+	
 	return &WSManager{
 		config:     cfg,
+		dialer:     newWSDialer(cfg),
+		shards:     shards,
 		marketSubs: make(map[string][]chan []byte),
 		userSubs:   make(map[string]chan []byte),
+		assigned:   make(map[string]*wsShard),
+		lastMessage: map[WSChannel]*atomic.Int64{
+			WSChannelMarket: new(atomic.Int64),
+			WSChannelPrice:  new(atomic.Int64),
+		},
 		ctx:        ctx,
 		cancel:     cancel,
 	}
@@ -100,175 +164,459 @@ func (w *WSManager) SetCallbacks(onMessage func(WSChannel, []byte), onError func
 	w.onDisconnect = onDisconnect
 }
 
-// Connect establishes WebSocket connections
-func (w *WSManager) Connect() error {
+// Observe registers fn to be called with every upstream frame, besides the
+// onMessage callback
+func (w *WSManager) Observe(fn func(WSChannel, []byte)) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	
-	// Connect to CLOB WebSocket
-	clobConn, _, err := websocket.DefaultDialer.DialContext(w.ctx, w.config.WsClobURL, nil)
-	if err != nil {
-		return fmt.Errorf("failed to connect to CLOB WebSocket: %w", err)
-	}
-	w.clobConn = clobConn
+	w.observers = append(w.observers, fn)
+}
+
+// ObserveGaps registers fn to be called with the markets and tokens whose
+// shard dropped, as messages for them were lost until they are
+// resubscribed on another connection
+func (w *WSManager) ObserveGaps(fn func(markets []string)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
 	
-	// Connect to Live Data WebSocket
-	liveConn, _, err := websocket.DefaultDialer.DialContext(w.ctx, w.config.WsLiveDataURL, nil)
-	if err != nil {
-		w.clobConn.Close()
-		return fmt.Errorf("failed to connect to Live Data WebSocket: %w", err)
+	w.gapObservers = append(w.gapObservers, fn)
+}
+
+// SetTape records upstream frames to t, or replays them from it instead of
+// connecting upstream. Must be called before Connect.
+func (w *WSManager) SetTape(t *tape.Tape) {
+	w.tape = t
+}
+
+// Replaying reports whether frames come from a recording
+func (w *WSManager) Replaying() bool {
+	return w.tape.Replaying()
+}
+
+// Connect establishes WebSocket connections. Shards that fail to connect
+// keep retrying in the background; an error is returned only when none
+// could connect.
+func (w *WSManager) Connect() error {
+	w.mu.Lock()
+	if w.started {
+		w.mu.Unlock()
+		return nil
+	}
+	w.started = true
+	if w.tape.Replaying() {
+		w.connected = true
+		w.mu.Unlock()
+		
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			err := w.tape.ReplayFrames(w.ctx, func(channel string, data []byte) {
+				w.processMessage(WSChannel(channel), data)
+			})
+			if err != nil && w.onError != nil {
+				w.onError(fmt.Errorf("replay: %w", err))
+			}
+		}()
+		return nil
 	}
-	w.liveConn = liveConn
+	w.mu.Unlock()
 	
-	w.connected = true
+	var firstErr error
+	for _, shard := range w.shards {
+		conn, err := w.dial(w.config.WsClobURL)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to connect to CLOB WebSocket: %w", err)
+			}
+		} else if !w.shardUp(shard, conn) {
+			conn.Close()
+			conn = nil
+		}
+		
+		w.wg.Add(1)
+		go w.supervise(fmt.Sprintf("shard %d", shard.index), w.config.WsClobURL, conn,
+			func(conn *websocket.Conn) bool { return w.shardUp(shard, conn) },
+			func() { w.shardDown(shard) },
+			func(message []byte) {
+				now := time.Now().UnixMilli()
+				shard.lastMessage.Store(now)
+				if ts := w.processMessage(WSChannelMarket, message); ts > 0 {
+					shard.lag.Store(now - ts)
+				}
+			})
+	}
 	
-	// Start message handlers
-	w.wg.Add(2)
-	go w.handleClobMessages()
-	go w.handleLiveMessages()
+	// Connect to Live Data WebSocket (optional, e.g. disabled for replicas)
+	if w.config.WsLiveDataURL != "" {
+		liveConn, err := w.dial(w.config.WsLiveDataURL)
+		if err != nil {
+			if w.onError != nil {
+				w.onError(fmt.Errorf("failed to connect to Live Data WebSocket: %w", err))
+			}
+		} else if !w.liveUp(liveConn) {
+			liveConn.Close()
+			liveConn = nil
+		}
+		
+		w.wg.Add(1)
+		go w.supervise("live data", w.config.WsLiveDataURL, liveConn, w.liveUp, w.liveDown,
+			func(message []byte) {
+				w.processMessage(WSChannelPrice, message)
+			})
+	}
 	
 	// Start ping routine
 	w.wg.Add(1)
 	go w.pingRoutine()
 	
-	if w.onConnect != nil {
-		w.onConnect()
+	if !w.IsConnected() {
+		return firstErr
 	}
-	
 	return nil
 }
 
-// handleClobMessages handles messages from CLOB WebSocket
-func (w *WSManager) handleClobMessages() {
+// dial opens an upstream WebSocket with the configured extra headers
+func (w *WSManager) dial(url string) (*websocket.Conn, error) {
+	header := http.Header{}
+	for k, v := range w.config.ExtraHeaders {
+		header.Set(k, v)
+	}
+	
+	conn, _, err := w.dialer.DialContext(w.ctx, url, header)
+	return conn, err
+}
+
+// supervise reads from an upstream connection until Close, redialing url
+// with exponential backoff whenever it drops. It is the only goroutine
+// reading from or reconnecting that connection, so reconnects never
+// overlap. up attaches a new connection and reports false once Close has
+// begun; down detaches a dropped one. conn may be nil to start by dialing.
+func (w *WSManager) supervise(name, url string, conn *websocket.Conn, up func(*websocket.Conn) bool, down func(), handle func([]byte)) {
 	defer w.wg.Done()
 	
+	backoff := time.Second
+	maxBackoff := 30 * time.Second
+	
 	for {
-		select {
-		case <-w.ctx.Done():
-			return
-		default:
-			_, message, err := w.clobConn.ReadMessage()
+		if conn == nil {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-w.ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			
+			var err error
+			conn, err = w.dial(url)
 			if err != nil {
-				if w.onError != nil {
-					w.onError(err)
+				conn = nil
+				backoff *= 2
+				if backoff > maxBackoff {
+					backoff = maxBackoff
 				}
-				w.reconnect()
+				continue
+			}
+			backoff = time.Second
+			// A dial that completes as Close runs would otherwise leave a
+			// connection nobody closes, and this loop blocked reading it
+			if !up(conn) {
+				conn.Close()
 				return
 			}
-			
-			w.processMessage(WSChannelMarket, message)
 		}
+		
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if w.ctx.Err() != nil {
+				return
+			}
+			if w.onError != nil {
+				w.onError(fmt.Errorf("%s: %w", name, err))
+			}
+			down()
+			conn = nil
+			continue
+		}
+		
+		handle(message)
 	}
 }
 
-// handleLiveMessages handles messages from Live Data WebSocket
-func (w *WSManager) handleLiveMessages() {
-	defer w.wg.Done()
+// shardUp marks a shard connected and moves its preferred markets onto it.
+// It reports false, leaving the shard down, once Close has begun.
+func (w *WSManager) shardUp(shard *wsShard, conn *websocket.Conn) bool {
+	now := time.Now().UnixMilli()
+	shard.lastMessage.Store(now)
+	shard.lastPing.Store(now)
+	shard.lastPong.Store(now)
+	conn.SetPongHandler(func(string) error {
+		shard.lastPong.Store(time.Now().UnixMilli())
+		return nil
+	})
 	
-	for {
-		select {
-		case <-w.ctx.Done():
-			return
-		default:
-			_, message, err := w.liveConn.ReadMessage()
-			if err != nil {
-				if w.onError != nil {
-					w.onError(err)
-				}
-				return
-			}
-			
-			w.processMessage(WSChannelPrice, message)
+	w.mu.Lock()
+	if w.ctx.Err() != nil {
+		w.mu.Unlock()
+		return false
+	}
+	shard.conn = conn
+	wasConnected := w.connected
+	w.connected = true
+	w.rebalance()
+	onConnect := w.onConnect
+	w.mu.Unlock()
+	
+	if !wasConnected && onConnect != nil {
+		onConnect()
+	}
+	return true
+}
+
+// shardDown marks a shard disconnected and moves its markets elsewhere
+func (w *WSManager) shardDown(shard *wsShard) {
+	w.mu.Lock()
+	if shard.conn != nil {
+		shard.conn.Close()
+		shard.conn = nil
+	}
+	var lost []string
+	for market, s := range w.assigned {
+		if s == shard {
+			lost = append(lost, market)
+		}
+	}
+	sort.Strings(lost)
+	gapObservers := w.gapObservers
+	w.connected = false
+	for _, s := range w.shards {
+		if s.conn != nil {
+			w.connected = true
+		}
+	}
+	w.rebalance()
+	allDown := !w.connected
+	onDisconnect := w.onDisconnect
+	w.mu.Unlock()
+	
+	if allDown && onDisconnect != nil {
+		onDisconnect()
+	}
+	if len(lost) > 0 {
+		w.gaps.Add(1)
+		for _, fn := range gapObservers {
+			fn(lost)
+		}
+	}
+}
+
+// rebalance subscribes every wanted market on its preferred connected
+// shard, unsubscribing it from the shard it is leaving. Caller holds mu.
+func (w *WSManager) rebalance() {
+	for market := range w.marketSubs {
+		best := w.pickShard(market)
+		current := w.assigned[market]
+		if best == current && (current == nil || current.conn != nil) {
+			continue
 		}
+		
+		if current != nil && current.conn != nil {
+			current.send(WSMessage{Type: WSMessageTypeUnsubscribe, Channel: WSChannelMarket, Markets: []string{market}})
+		}
+		if best == nil {
+			delete(w.assigned, market)
+			continue
+		}
+		if err := best.send(WSMessage{Type: WSMessageTypeSubscribe, Channel: WSChannelMarket, Markets: []string{market}}); err != nil {
+			delete(w.assigned, market)
+			continue
+		}
+		w.assigned[market] = best
 	}
 }
 
-// processMessage processes incoming WebSocket messages
-func (w *WSManager) processMessage(channel WSChannel, data []byte) {
+// pickShard returns the connected shard with the highest rendezvous hash
+// for a market, so adding or losing a shard only moves that shard's
+// markets. Caller holds mu.
+func (w *WSManager) pickShard(market string) *wsShard {
+	var best *wsShard
+	var bestScore uint64
+	for _, s := range w.shards {
+		if s.conn == nil {
+			continue
+		}
+		h := fnv.New64a()
+		h.Write([]byte(market))
+		h.Write([]byte{'#', byte(s.index), byte(s.index >> 8)})
+		if score := h.Sum64(); best == nil || score > bestScore {
+			best, bestScore = s, score
+		}
+	}
+	return best
+}
+
+// send writes a message to the shard's connection
+func (s *wsShard) send(msg WSMessage) error {
+	data, err := sonic.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if s.conn == nil {
+		return fmt.Errorf("shard %d is not connected", s.index)
+	}
+	return s.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// liveUp attaches the Live Data connection. It reports false once Close
+// has begun.
+func (w *WSManager) liveUp(conn *websocket.Conn) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.ctx.Err() != nil {
+		return false
+	}
+	w.liveConn = conn
+	return true
+}
+
+// liveDown detaches a dropped Live Data connection
+func (w *WSManager) liveDown() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.liveConn != nil {
+		w.liveConn.Close()
+		w.liveConn = nil
+	}
+}
+
+// processMessage processes incoming WebSocket messages. It returns the
+// latest event time of the market messages in data, or 0.
+func (w *WSManager) processMessage(channel WSChannel, data []byte) int64 {
+	if w.tape.Recording() {
+		if err := w.tape.RecordFrame(string(channel), data); err != nil && w.onError != nil {
+			w.onError(fmt.Errorf("record frame: %w", err))
+		}
+	}
+	
+	if last, ok := w.lastMessage[channel]; ok {
+		last.Store(time.Now().UnixMilli())
+	}
+	
 	if w.onMessage != nil {
 		w.onMessage(channel, data)
 	}
 	
-	// Parse message to route to subscribers
-	var msg WSMessage
-	if err := sonic.Unmarshal(data, &msg); err != nil {
-		return
+	w.mu.RLock()
+	observers := w.observers
+	w.mu.RUnlock()
+	for _, fn := range observers {
+		fn(channel, data)
+	}
+	
+	// Route each message of the frame to the subscribers of its tokens
+	// and its market
+	messages := ParseMarketFrame(data)
+	if len(messages) == 0 {
+		return 0
 	}
 	
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 	
-	// Route to market subscribers
-	if len(msg.Markets) > 0 {
-		for _, market := range msg.Markets {
-			if subs, ok := w.marketSubs[market]; ok {
-				for _, ch := range subs {
-					select {
-					case ch <- data:
-					default:
-						// Channel full, skip
-					}
+	// Keys are distinct and every subscription has its own channel, so no
+	// subscriber gets a message twice
+	var latest int64
+	for _, m := range messages {
+		if m.Timestamp > latest {
+			latest = m.Timestamp
+		}
+		for _, key := range m.Keys() {
+			for _, ch := range w.marketSubs[key] {
+				select {
+				case ch <- m.Data:
+				default:
+					// Channel full, skip
+					w.dropped.Add(1)
 				}
 			}
 		}
 	}
+	return latest
 }
 
-// pingRoutine sends periodic pings to keep connection alive
+// pingRoutine sends periodic pings to keep connections alive and drops
+// shards that stop answering them or go quiet while subscribed
 func (w *WSManager) pingRoutine() {
 	defer w.wg.Done()
 	
-	ticker := time.NewTicker(30 * time.Second)
+	// Checks run more often than pings so dead shards are noticed within
+	// about a second of their deadline
+	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
 	
 	for {
 		select {
 		case <-w.ctx.Done():
 			return
-		case <-ticker.C:
-			w.mu.RLock()
-			if w.clobConn != nil {
-				ping := WSMessage{Type: WSMessageTypePing, Timestamp: time.Now().UnixMilli()}
-				data, _ := sonic.Marshal(ping)
-				w.clobConn.WriteMessage(websocket.TextMessage, data)
-			}
-			w.mu.RUnlock()
+		case now := <-ticker.C:
+			w.checkLiveness(now)
 		}
 	}
 }
 
-// reconnect attempts to reconnect WebSocket
-func (w *WSManager) reconnect() {
-	w.mu.Lock()
-	w.connected = false
-	w.mu.Unlock()
+// checkLiveness pings shards that are due and closes any whose pong is
+// overdue or whose feed is stale; their read loops then reconnect
+func (w *WSManager) checkLiveness(now time.Time) {
+	nowMs := now.UnixMilli()
 	
-	if w.onDisconnect != nil {
-		w.onDisconnect()
-	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
 	
-	// Attempt reconnection with exponential backoff
-	backoff := time.Second
-	maxBackoff := 30 * time.Second
+	active := make(map[*wsShard]bool)
+	for _, shard := range w.assigned {
+		active[shard] = true
+	}
 	
-	for {
-		select {
-		case <-w.ctx.Done():
-			return
-		case <-time.After(backoff):
-			if err := w.Connect(); err != nil {
-				backoff *= 2
-				if backoff > maxBackoff {
-					backoff = maxBackoff
-				}
-				continue
+	for _, shard := range w.shards {
+		if shard.conn == nil {
+			continue
+		}
+		
+		var reason error
+		lastPing, lastPong := shard.lastPing.Load(), shard.lastPong.Load()
+		switch {
+		case lastPing > lastPong && nowMs-lastPing > w.config.WsPongTimeout.Milliseconds():
+			reason = fmt.Errorf("shard %d: no pong within %s", shard.index, w.config.WsPongTimeout)
+		case active[shard] && w.config.WsStaleTimeout > 0 &&
+			nowMs-shard.lastMessage.Load() > w.config.WsStaleTimeout.Milliseconds():
+			reason = fmt.Errorf("shard %d: no messages for %s", shard.index, w.config.WsStaleTimeout)
+		}
+		if reason != nil {
+			if w.onError != nil {
+				w.onError(reason)
 			}
-			return
+			shard.conn.Close()
+			continue
+		}
+		
+		if w.config.WsPingInterval > 0 && lastPing <= lastPong &&
+			nowMs-lastPing >= w.config.WsPingInterval.Milliseconds() {
+			deadline := now.Add(w.config.WsPongTimeout)
+			if err := shard.conn.WriteControl(websocket.PingMessage, nil, deadline); err == nil {
+				shard.lastPing.Store(nowMs)
+			}
+			ping := WSMessage{Type: WSMessageTypePing, Timestamp: nowMs}
+			shard.send(ping)
 		}
 	}
 }
 
-// SubscribeMarket subscribes to market updates
+// SubscribeMarket subscribes to updates for a token or a market (condition
+// ID); messages are routed by their asset_id and market fields
 func (w *WSManager) SubscribeMarket(marketID string) (chan []byte, error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -276,22 +624,22 @@ func (w *WSManager) SubscribeMarket(marketID string) (chan []byte, error) {
 	ch := make(chan []byte, 100)
 	w.marketSubs[marketID] = append(w.marketSubs[marketID], ch)
 	
-	// Send subscribe message
-	msg := WSMessage{
-		Type:    WSMessageTypeSubscribe,
-		Channel: WSChannelMarket,
-		Markets: []string{marketID},
-	}
-	
-	data, err := sonic.Marshal(msg)
-	if err != nil {
-		return nil, err
+	if _, ok := w.assigned[marketID]; ok {
+		return ch, nil
 	}
 	
-	if w.clobConn != nil {
-		if err := w.clobConn.WriteMessage(websocket.TextMessage, data); err != nil {
+	// Not yet subscribed upstream; while no shard is connected the market
+	// is picked up by rebalance on the next connect
+	if shard := w.pickShard(marketID); shard != nil {
+		msg := WSMessage{
+			Type:    WSMessageTypeSubscribe,
+			Channel: WSChannelMarket,
+			Markets: []string{marketID},
+		}
+		if err := shard.send(msg); err != nil {
 			return nil, err
 		}
+		w.assigned[marketID] = shard
 	}
 	
 	return ch, nil
@@ -315,15 +663,15 @@ func (w *WSManager) UnsubscribeMarket(marketID string, ch chan []byte) {
 		if len(w.marketSubs[marketID]) == 0 {
 			delete(w.marketSubs, marketID)
 			
-			msg := WSMessage{
-				Type:    WSMessageTypeUnsubscribe,
-				Channel: WSChannelMarket,
-				Markets: []string{marketID},
-			}
-			
-			data, _ := sonic.Marshal(msg)
-			if w.clobConn != nil {
-				w.clobConn.WriteMessage(websocket.TextMessage, data)
+			if shard, ok := w.assigned[marketID]; ok {
+				delete(w.assigned, marketID)
+				if shard.conn != nil {
+					shard.send(WSMessage{
+						Type:    WSMessageTypeUnsubscribe,
+						Channel: WSChannelMarket,
+						Markets: []string{marketID},
+					})
+				}
 			}
 		}
 	}
@@ -343,13 +691,9 @@ func (w *WSManager) SubscribeUser(userID string, auth *WSAuth) (chan []byte, err
 		Auth:    auth,
 	}
 	
-	data, err := sonic.Marshal(msg)
-	if err != nil {
-		return nil, err
-	}
-	
-	if w.clobConn != nil {
-		if err := w.clobConn.WriteMessage(websocket.TextMessage, data); err != nil {
+	// User channels are not sharded; any connected shard will do
+	if shard := w.pickShard(userID); shard != nil {
+		if err := shard.send(msg); err != nil {
 			return nil, err
 		}
 	}
@@ -362,18 +706,25 @@ func (w *WSManager) Close() {
 	w.cancel()
 	
 	w.mu.Lock()
-	if w.clobConn != nil {
-		w.clobConn.Close()
+	for _, shard := range w.shards {
+		if shard.conn != nil {
+			shard.conn.Close()
+			shard.conn = nil
+		}
 	}
 	if w.liveConn != nil {
 		w.liveConn.Close()
+		w.liveConn = nil
 	}
 	w.connected = false
 	w.mu.Unlock()
 	
 	w.wg.Wait()
 	
-	// Close all subscriber channels
+	// Close all subscriber channels. They are dropped from the maps too, so
+	// handlers unsubscribing as their clients leave do not close them again.
+	w.mu.Lock()
+	defer w.mu.Unlock()
 	for _, subs := range w.marketSubs {
 		for _, ch := range subs {
 			close(ch)
@@ -382,11 +733,66 @@ func (w *WSManager) Close() {
 	for _, ch := range w.userSubs {
 		close(ch)
 	}
+	w.marketSubs = make(map[string][]chan []byte)
+	w.userSubs = make(map[string]chan []byte)
 }
 
-// IsConnected returns connection status
+// IsConnected reports whether at least one upstream shard is connected
 func (w *WSManager) IsConnected() bool {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 	return w.connected
 }
+
+// Shards returns the status of each upstream connection
+func (w *WSManager) Shards() []ShardStatus {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	
+	out := make([]ShardStatus, len(w.shards))
+	for i, shard := range w.shards {
+		out[i] = ShardStatus{
+			Index:       shard.index,
+			Connected:   shard.conn != nil,
+			LastMessage: shard.lastMessage.Load(),
+			LastPong:    shard.lastPong.Load(),
+			Lag:         shard.lag.Load(),
+		}
+	}
+	for _, shard := range w.assigned {
+		out[shard.index].Markets++
+	}
+	return out
+}
+
+// Dropped returns how many messages were not delivered to a subscriber
+// that fell behind, and how many shard drops lost messages of subscribed
+// markets
+func (w *WSManager) Dropped() (messages, gaps uint64) {
+	return w.dropped.Load(), w.gaps.Load()
+}
+
+// WritePrometheus writes the upstream lag per shard and the drop counters
+// in the Prometheus text format
+func (w *WSManager) WritePrometheus(out io.Writer) {
+	fmt.Fprintf(out, "# HELP polygo_ws_upstream_lag_ms How far behind its event time the latest upstream message arrived, per shard\n# TYPE polygo_ws_upstream_lag_ms gauge\n")
+	for _, shard := range w.Shards() {
+		fmt.Fprintf(out, "polygo_ws_upstream_lag_ms{shard=\"%d\"} %d\n", shard.Index, shard.Lag)
+	}
+	
+	messages, gaps := w.Dropped()
+	fmt.Fprintf(out, "# HELP polygo_ws_upstream_dropped_total Upstream messages not delivered to a subscriber that fell behind\n# TYPE polygo_ws_upstream_dropped_total counter\npolygo_ws_upstream_dropped_total %d\n", messages)
+	fmt.Fprintf(out, "# HELP polygo_ws_upstream_gaps_total Upstream shard drops that lost messages of subscribed markets\n# TYPE polygo_ws_upstream_gaps_total counter\npolygo_ws_upstream_gaps_total %d\n", gaps)
+}
+
+// LastMessages returns when a message was last received on each upstream
+// channel, omitting channels that have not received any
+func (w *WSManager) LastMessages() map[WSChannel]time.Time {
+	out := make(map[WSChannel]time.Time)
+	for channel, last := range w.lastMessage {
+		if ms := last.Load(); ms > 0 {
+			out[channel] = time.UnixMilli(ms)
+		}
+	}
+	return out
+}
@@ -0,0 +1,409 @@
+// Package posalert watches the positions of wallets and alerts each
+// watch's owner, through webhooks and /ws/events, when a position is
+// opened, grows, shrinks or is closed by at least the watch's thresholds.
+package posalert
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/idgen"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/webhooks"
+	"github.com/polygo/pkg/validate"
+)
+
+// Webhook event types, one per kind of change
+const (
+	EventOpened    = "position.opened"
+	EventIncreased = "position.increased"
+	EventDecreased = "position.decreased"
+	EventClosed    = "position.closed"
+)
+
+// events maps the type of a Change to its webhook event type
+var events = map[string]string{
+	"position_opened":    EventOpened,
+	"position_increased": EventIncreased,
+	"position_decreased": EventDecreased,
+	"position_closed":    EventClosed,
+}
+
+var (
+	// ErrInvalidThreshold is returned for a negative threshold
+	ErrInvalidThreshold = errors.New("thresholds must not be negative")
+	// ErrFull is returned once MaxWatches wallets are being watched
+	ErrFull = errors.New("position alerts are full")
+	// ErrNotFound is returned for a watch the caller does not own
+	ErrNotFound = errors.New("position alert not found")
+)
+
+// Watch is a wallet whose positions are watched for an owner
+type Watch struct {
+	ID         string    `json:"id"`
+	Address    string    `json:"address"`
+	Label      string    `json:"label,omitempty"`
+	MinSize    float64   `json:"min_size"`  // shares a position must change by; 0 for any change
+	MinValue   float64   `json:"min_value"` // USDC, at the current price, a position must change by
+	Owner      string    `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastPolled time.Time `json:"last_polled,omitempty"`
+}
+
+// Thresholds are the settings of a watch its owner can change
+type Thresholds struct {
+	Label    string
+	MinSize  float64
+	MinValue float64
+}
+
+// Change is the payload of a position event
+type Change struct {
+	Type         string  `json:"type"` // position_opened, position_increased, position_decreased or position_closed
+	WatchID      string  `json:"watch_id"`
+	Address      string  `json:"address"`
+	Label        string  `json:"label,omitempty"`
+	Asset        string  `json:"asset"`
+	ConditionID  string  `json:"condition_id"`
+	Title        string  `json:"title,omitempty"`
+	Outcome      string  `json:"outcome,omitempty"`
+	PreviousSize float64 `json:"previous_size"` // as last reported
+	Size         float64 `json:"size"`
+	Change       float64 `json:"change"` // Size - PreviousSize
+	Price        float64 `json:"price"`  // current price of the outcome
+	Value        float64 `json:"value"`  // the change at Price, USDC
+	AvgPrice     float64 `json:"avg_price"`
+}
+
+// position is the subset of a Data API position needed to diff them
+type position struct {
+	Asset       string  `json:"asset"`
+	ConditionID string  `json:"conditionId"`
+	Size        float64 `json:"size"`
+	AvgPrice    float64 `json:"avgPrice"`
+	CurPrice    float64 `json:"curPrice"`
+	Title       string  `json:"title"`
+	Outcome     string  `json:"outcome"`
+}
+
+// entry is a watch plus the positions last reported to its owner
+type entry struct {
+	watch    Watch
+	reported map[string]position // asset -> position
+	primed   bool                // the first poll only records what is already there
+}
+
+// Alerts polls the positions of watched wallets every Interval and
+// publishes the changes that meet each watch's thresholds. Changes are
+// measured from the size last reported, so a position growing slowly is
+// reported once its growth adds up. Watches are saved to Path so they
+// survive restarts; positions are not, so changes made while PolyGo was
+// down are not reported.
+type Alerts struct {
+	data     *polymarket.DataClient
+	webhooks *webhooks.Dispatcher
+	config   *config.PositionAlertsConfig
+
+	mu      sync.RWMutex
+	entries map[string]*entry // ID -> entry
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates position alerts, restoring the watches saved at Path
+func New(data *polymarket.DataClient, dispatcher *webhooks.Dispatcher, cfg *config.PositionAlertsConfig) *Alerts {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	a := &Alerts{
+		data:     data,
+		webhooks: dispatcher,
+		config:   cfg,
+		entries:  make(map[string]*entry),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	if err := a.load(); err != nil {
+		log.Printf("Failed to restore position alerts from %s: %v", cfg.Path, err)
+	}
+	return a
+}
+
+// Start polls watched wallets every Interval
+func (a *Alerts) Start() {
+	if !a.config.Enabled || a.config.Interval <= 0 {
+		return
+	}
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+
+		ticker := time.NewTicker(a.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-a.ctx.Done():
+				return
+			case <-ticker.C:
+				a.Poll()
+			}
+		}
+	}()
+}
+
+// Stop stops polling
+func (a *Alerts) Stop() {
+	a.cancel()
+	a.wg.Wait()
+}
+
+// Create starts watching an address for owner
+func (a *Alerts) Create(address string, t Thresholds, owner string) (Watch, error) {
+	if err := validate.Address(address); err != nil {
+		return Watch{}, err
+	}
+	if t.MinSize < 0 || t.MinValue < 0 {
+		return Watch{}, ErrInvalidThreshold
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.config.MaxWatches > 0 && len(a.entries) >= a.config.MaxWatches {
+		return Watch{}, ErrFull
+	}
+
+	e := &entry{
+		watch: Watch{
+			ID:        idgen.WithPrefix("pwa"),
+			Address:   strings.ToLower(address),
+			Label:     t.Label,
+			MinSize:   t.MinSize,
+			MinValue:  t.MinValue,
+			Owner:     owner,
+			CreatedAt: time.Now(),
+		},
+		reported: make(map[string]position),
+	}
+	a.entries[e.watch.ID] = e
+	a.saveLocked()
+	return e.watch, nil
+}
+
+// Update changes the label and thresholds of one of owner's watches
+func (a *Alerts) Update(id string, t Thresholds, owner string) (Watch, error) {
+	if t.MinSize < 0 || t.MinValue < 0 {
+		return Watch{}, ErrInvalidThreshold
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	e, ok := a.entries[id]
+	if !ok || e.watch.Owner != owner {
+		return Watch{}, ErrNotFound
+	}
+	e.watch.Label, e.watch.MinSize, e.watch.MinValue = t.Label, t.MinSize, t.MinValue
+	a.saveLocked()
+	return e.watch, nil
+}
+
+// Delete stops one of owner's watches
+func (a *Alerts) Delete(id, owner string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	e, ok := a.entries[id]
+	if !ok || e.watch.Owner != owner {
+		return false
+	}
+	delete(a.entries, id)
+	a.saveLocked()
+	return true
+}
+
+// Get returns one of owner's watches
+func (a *Alerts) Get(id, owner string) (Watch, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	e, ok := a.entries[id]
+	if !ok || e.watch.Owner != owner {
+		return Watch{}, false
+	}
+	return e.watch, true
+}
+
+// Watches returns owner's watches, oldest first
+func (a *Alerts) Watches(owner string) []Watch {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	out := make([]Watch, 0, len(a.entries))
+	for _, e := range a.entries {
+		if e.watch.Owner == owner {
+			out = append(out, e.watch)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// Addresses returns every watched wallet address across owners, sorted
+func (a *Alerts) Addresses() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	seen := make(map[string]bool, len(a.entries))
+	out := make([]string, 0, len(a.entries))
+	for _, e := range a.entries {
+		if !seen[e.watch.Address] {
+			seen[e.watch.Address] = true
+			out = append(out, e.watch.Address)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Poll fetches the positions of every watched wallet and publishes what
+// changed. Failures are logged and retried on the next poll.
+func (a *Alerts) Poll() {
+	a.mu.RLock()
+	entries := make([]*entry, 0, len(a.entries))
+	for _, e := range a.entries {
+		entries = append(entries, e)
+	}
+	a.mu.RUnlock()
+
+	for _, e := range entries {
+		if a.ctx.Err() != nil {
+			return
+		}
+		if err := a.poll(e); err != nil {
+			log.Printf("Position alert poll for %s failed: %v", e.watch.Address, err)
+		}
+	}
+}
+
+// poll diffs one wallet's positions against those last reported
+func (a *Alerts) poll(e *entry) error {
+	data, _, err := a.data.GetPositions(e.watch.Address, a.config.PositionsLimit, "", false)
+	if err != nil {
+		return err
+	}
+
+	var positions []position
+	if err := sonic.Unmarshal(data, &positions); err != nil {
+		return err
+	}
+	// A full page may leave positions out, which must not pass for closed
+	complete := a.config.PositionsLimit <= 0 || len(positions) < a.config.PositionsLimit
+
+	a.mu.Lock()
+	var changes []Change
+	if e.primed {
+		changes = diff(e, positions, complete)
+	} else {
+		for _, p := range positions {
+			if p.Size > 0 {
+				e.reported[p.Asset] = p
+			}
+		}
+		e.primed = true
+	}
+	e.watch.LastPolled = time.Now()
+	owner := e.watch.Owner
+	a.mu.Unlock()
+
+	if a.webhooks != nil {
+		for _, c := range changes {
+			a.webhooks.Publish(events[c.Type], "", owner, c)
+		}
+	}
+	return nil
+}
+
+// diff returns the changes from the positions last reported to the
+// current ones that meet the watch's thresholds, and records them as
+// reported. Closing a reported position is reported whatever its size.
+// Positions missing from an incomplete list are left alone. Caller holds mu.
+func diff(e *entry, positions []position, complete bool) []Change {
+	var changes []Change
+	current := make(map[string]bool, len(positions))
+	for _, p := range positions {
+		if p.Size <= 0 {
+			continue
+		}
+		current[p.Asset] = true
+		prev := e.reported[p.Asset]
+		if !meets(e.watch, p.Size-prev.Size, p.CurPrice) {
+			continue
+		}
+		changes = append(changes, change(e.watch, prev.Size, p))
+		e.reported[p.Asset] = p
+	}
+
+	if complete {
+		for asset, prev := range e.reported {
+			if current[asset] {
+				continue
+			}
+			closed := prev
+			closed.Size = 0
+			changes = append(changes, change(e.watch, prev.Size, closed))
+			delete(e.reported, asset)
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Asset < changes[j].Asset })
+	return changes
+}
+
+// meets reports whether a change in size at price meets w's thresholds
+func meets(w Watch, delta, price float64) bool {
+	delta = math.Abs(delta)
+	return delta > 1e-9 && delta >= w.MinSize && delta*price >= w.MinValue
+}
+
+// change describes a position going from prev shares to p
+func change(w Watch, prev float64, p position) Change {
+	kind := "position_decreased"
+	switch {
+	case prev == 0:
+		kind = "position_opened"
+	case p.Size == 0:
+		kind = "position_closed"
+	case p.Size > prev:
+		kind = "position_increased"
+	}
+
+	delta := p.Size - prev
+	return Change{
+		Type:         kind,
+		WatchID:      w.ID,
+		Address:      w.Address,
+		Label:        w.Label,
+		Asset:        p.Asset,
+		ConditionID:  p.ConditionID,
+		Title:        p.Title,
+		Outcome:      p.Outcome,
+		PreviousSize: prev,
+		Size:         p.Size,
+		Change:       delta,
+		Price:        p.CurPrice,
+		Value:        math.Abs(delta) * p.CurPrice,
+		AvgPrice:     p.AvgPrice,
+	}
+}
@@ -0,0 +1,98 @@
+package posalert
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/fsutil"
+)
+
+// stored is the on-disk form of every watch. Owners are API keys, so the
+// file is written readable by the server's user only.
+type stored struct {
+	Watches []storedWatch `json:"watches"`
+}
+
+type storedWatch struct {
+	ID        string    `json:"id"`
+	Address   string    `json:"address"`
+	Label     string    `json:"label,omitempty"`
+	MinSize   float64   `json:"min_size,omitempty"`
+	MinValue  float64   `json:"min_value,omitempty"`
+	Owner     string    `json:"owner,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// load restores the watches saved at Path; a missing file is not an error
+func (a *Alerts) load() error {
+	if a.config.Path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(a.config.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var s stored
+	if err := sonic.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, sw := range s.Watches {
+		a.entries[sw.ID] = &entry{
+			watch: Watch{
+				ID:        sw.ID,
+				Address:   sw.Address,
+				Label:     sw.Label,
+				MinSize:   sw.MinSize,
+				MinValue:  sw.MinValue,
+				Owner:     sw.Owner,
+				CreatedAt: sw.CreatedAt,
+			},
+			reported: make(map[string]position),
+		}
+	}
+	return nil
+}
+
+// saveLocked writes every watch to Path, replacing the file atomically.
+// Failures are logged; the in-memory watches stay authoritative. Caller
+// holds a.mu.
+func (a *Alerts) saveLocked() {
+	if a.config.Path == "" {
+		return
+	}
+
+	s := stored{Watches: make([]storedWatch, 0, len(a.entries))}
+	for _, e := range a.entries {
+		w := e.watch
+		s.Watches = append(s.Watches, storedWatch{
+			ID:        w.ID,
+			Address:   w.Address,
+			Label:     w.Label,
+			MinSize:   w.MinSize,
+			MinValue:  w.MinValue,
+			Owner:     w.Owner,
+			CreatedAt: w.CreatedAt,
+		})
+	}
+
+	data, err := sonic.Marshal(s)
+	if err == nil {
+		err = fsutil.WriteFileAtomic(a.config.Path, data, 0o600)
+	}
+	if err != nil {
+		log.Printf("Failed to save position alerts to %s: %v", a.config.Path, err)
+	}
+}
@@ -0,0 +1,70 @@
+// Package pretrade runs the checks every order goes through before it is
+// sent to the CLOB: its market's order rules, the duplicate and
+// per-market rate guard, then the account's risk limits. The /orders
+// routes run them as middleware; engines placing orders with the server's
+// own accounts run them through a Chain.
+package pretrade
+
+import (
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/orderrules"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/risk"
+	"github.com/polygo/internal/throttle"
+	"github.com/polygo/pkg/polygoclient"
+)
+
+// Chain runs the pre-trade checks for orders placed server-side
+type Chain struct {
+	rules *orderrules.Checker
+	guard *throttle.Guard
+	risk  *risk.Checker
+	auth  *config.AuthConfig
+}
+
+// New creates a chain of the given checkers
+func New(rules *orderrules.Checker, guard *throttle.Guard, checker *risk.Checker, auth *config.AuthConfig) *Chain {
+	return &Chain{rules: rules, guard: guard, risk: checker, auth: auth}
+}
+
+// Check runs the checks on orders an account is about to place together,
+// in the order the /orders routes run them. Accounts are keyed by API key
+// as callers of /orders are, so an account shares its limits whichever
+// way its orders come in. Accepted orders count towards the account's
+// duplicate window and rates until the returned reservation is Released.
+// Failures are *orderrules.Error, *throttle.Error or *risk.Error. A nil
+// chain accepts everything.
+func (p *Chain) Check(creds *polygoclient.Credentials, orders []models.CreateOrderRequest) (*throttle.Reservation, error) {
+	if p == nil {
+		return nil, nil
+	}
+	if p.rules.Enabled() {
+		if err := p.rules.Check(orders); err != nil {
+			return nil, err
+		}
+	}
+
+	reservation, err := p.guard.Reserve(creds.APIKey, orders)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.risk.Enabled() {
+		headers := polymarket.SignedHeaders(p.auth, creds, "GET", "/orders/open", nil)
+		if err := p.risk.Check(creds.APIKey, headers, orders); err != nil {
+			p.guard.Release(reservation)
+			return nil, err
+		}
+	}
+	return reservation, nil
+}
+
+// Release forgets the reservation of orders that were not placed after
+// all, e.g. because the CLOB rejected them
+func (p *Chain) Release(r *throttle.Reservation) {
+	if p == nil {
+		return
+	}
+	p.guard.Release(r)
+}
@@ -0,0 +1,93 @@
+package recorder
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Sort orders for top movers
+const (
+	SortAbsolute = "abs"
+	SortPercent  = "pct"
+	SortVolume   = "volume"
+)
+
+// Mover is a market's price change over a window
+type Mover struct {
+	MarketID   string    `json:"market_id"`
+	Question   string    `json:"question"`
+	Slug       string    `json:"slug,omitempty"`
+	From       time.Time `json:"from"`
+	PriceStart float64   `json:"price_start"`
+	PriceEnd   float64   `json:"price_end"`
+	Change     float64   `json:"change"`
+	ChangePct  float64   `json:"change_pct"`
+	Volume     float64   `json:"volume"` // volume traded within the window
+}
+
+// ValidSort reports whether sortBy is a supported mover ordering
+func ValidSort(sortBy string) bool {
+	return sortBy == SortAbsolute || sortBy == SortPercent || sortBy == SortVolume
+}
+
+// Movers computes price moves over the trailing window for every recorded
+// market and returns the top limit ordered by sortBy. Markets whose history
+// is shorter than the window are measured from their first candle.
+func (r *Recorder) Movers(window time.Duration, sortBy string, limit int) []Mover {
+	now := time.Now()
+	since := now.Add(-window)
+
+	r.mu.RLock()
+	movers := make([]Mover, 0, len(r.markets))
+	for id, s := range r.markets {
+		if len(s.candles) == 0 {
+			continue
+		}
+
+		// Start from the last candle at or before the window start
+		start := s.candles[0]
+		for _, c := range s.candles {
+			if c.Time.After(since) {
+				break
+			}
+			start = c
+		}
+		end := s.candles[len(s.candles)-1]
+		if !end.Time.After(start.Time) {
+			continue
+		}
+
+		m := Mover{
+			MarketID:   id,
+			Question:   s.question,
+			Slug:       s.slug,
+			From:       start.Time,
+			PriceStart: start.Close,
+			PriceEnd:   end.Close,
+			Change:     end.Close - start.Close,
+			Volume:     math.Max(0, end.Volume-start.Volume),
+		}
+		if start.Close > 0 {
+			m.ChangePct = m.Change / start.Close * 100
+		}
+		movers = append(movers, m)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(movers, func(i, j int) bool {
+		switch sortBy {
+		case SortPercent:
+			return math.Abs(movers[i].ChangePct) > math.Abs(movers[j].ChangePct)
+		case SortVolume:
+			return movers[i].Volume > movers[j].Volume
+		default:
+			return math.Abs(movers[i].Change) > math.Abs(movers[j].Change)
+		}
+	})
+
+	if limit > 0 && len(movers) > limit {
+		movers = movers[:limit]
+	}
+	return movers
+}
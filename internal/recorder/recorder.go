@@ -0,0 +1,240 @@
+package recorder
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/storage"
+)
+
+// Candle is one Resolution-wide bucket of a market's price (first outcome)
+// and cumulative traded volume
+type Candle struct {
+	Time   time.Time `json:"time"`
+	Open   float64   `json:"open"`
+	High   float64   `json:"high"`
+	Low    float64   `json:"low"`
+	Close  float64   `json:"close"`
+	Volume float64   `json:"volume"` // cumulative market volume at close
+}
+
+// series is the recorded history of one market
+type series struct {
+	question string
+	slug     string
+	candles  []Candle // oldest first
+}
+
+// Recorder periodically samples prices of the most active markets and keeps
+// a rolling window of candles in memory, optionally saved to storage so a
+// restart does not lose the history
+type Recorder struct {
+	gamma  *polymarket.GammaClient
+	store  storage.Store
+	config *config.RecorderConfig
+
+	mu      sync.RWMutex
+	markets map[string]*series
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a new recorder, restoring candles saved at Path in store. A
+// nil store keeps candles in memory only.
+func New(gamma *polymarket.GammaClient, store storage.Store, cfg *config.RecorderConfig) *Recorder {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	r := &Recorder{
+		gamma:   gamma,
+		store:   store,
+		config:  cfg,
+		markets: make(map[string]*series),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	if err := r.load(); err != nil {
+		log.Printf("Failed to restore recorded candles from %s: %v", cfg.Path, err)
+	}
+	return r
+}
+
+// Start samples immediately and then every SampleInterval, saving candles
+// every SaveInterval
+func (r *Recorder) Start() {
+	if !r.config.Enabled {
+		return
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.config.SampleInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := r.Sample(); err != nil {
+				log.Printf("Price recorder sample failed: %v", err)
+			}
+
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	if !r.persistent() || r.config.SaveInterval <= 0 {
+		return
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.config.SaveInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-ticker.C:
+				r.save()
+			}
+		}
+	}()
+}
+
+// Stop stops background sampling and saves the candles
+func (r *Recorder) Stop() {
+	r.cancel()
+	r.wg.Wait()
+	r.save()
+}
+
+// Sample fetches the top markets by 24h volume and records their prices
+func (r *Recorder) Sample() error {
+	active, closed, ascending := true, false, false
+	result, err := r.gamma.GetAllMarkets(&models.MarketQueryParams{
+		Limit:     r.config.MaxMarkets,
+		Active:    &active,
+		Closed:    &closed,
+		Order:     "volume24hr",
+		Ascending: &ascending,
+	}, r.config.MaxMarkets)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, raw := range result.Items {
+		var m models.Market
+		if err := sonic.Unmarshal(raw, &m); err != nil {
+			continue
+		}
+		prices := m.OutcomePrices.Floats()
+		if len(prices) == 0 {
+			continue
+		}
+		r.Record(m.ID, m.Question, m.Slug, prices[0], m.Volume.Float(), now)
+	}
+
+	// Forget markets that left the top list long enough ago to be stale
+	r.mu.Lock()
+	for id, s := range r.markets {
+		if n := len(s.candles); n == 0 || now.Sub(s.candles[n-1].Time) > r.config.Retention {
+			delete(r.markets, id)
+		}
+	}
+	r.mu.Unlock()
+	return nil
+}
+
+// Record adds a price observation for a market at the given time
+func (r *Recorder) Record(marketID, question, slug string, price, volume float64, at time.Time) {
+	bucket := at.Truncate(r.resolution())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.markets[marketID]
+	if !ok {
+		s = &series{}
+		r.markets[marketID] = s
+	}
+	s.question, s.slug = question, slug
+
+	if n := len(s.candles); n > 0 && s.candles[n-1].Time.Equal(bucket) {
+		c := &s.candles[n-1]
+		if price > c.High {
+			c.High = price
+		}
+		if price < c.Low {
+			c.Low = price
+		}
+		c.Close = price
+		c.Volume = volume
+	} else {
+		s.candles = append(s.candles, Candle{
+			Time: bucket, Open: price, High: price, Low: price, Close: price, Volume: volume,
+		})
+	}
+
+	// Drop candles that fell out of the retention window
+	cutoff := at.Add(-r.config.Retention)
+	i := 0
+	for i < len(s.candles) && s.candles[i].Time.Before(cutoff) {
+		i++
+	}
+	if i > 0 {
+		s.candles = append(s.candles[:0], s.candles[i:]...)
+	}
+}
+
+// Candles returns a copy of a market's candles since the given time
+func (r *Recorder) Candles(marketID string, since time.Time) []Candle {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.markets[marketID]
+	if !ok {
+		return nil
+	}
+
+	out := make([]Candle, 0, len(s.candles))
+	for _, c := range s.candles {
+		if !c.Time.Before(since) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Ready reports whether at least one sample has been recorded
+func (r *Recorder) Ready() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.markets) > 0
+}
+
+// Retention returns how much history the recorder keeps
+func (r *Recorder) Retention() time.Duration {
+	return r.config.Retention
+}
+
+// resolution returns the candle width, defaulting to one minute
+func (r *Recorder) resolution() time.Duration {
+	if r.config.Resolution <= 0 {
+		return time.Minute
+	}
+	return r.config.Resolution
+}
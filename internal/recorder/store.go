@@ -0,0 +1,82 @@
+package recorder
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/storage"
+)
+
+// storedSeries is the saved form of one market's candles
+type storedSeries struct {
+	Question string   `json:"question"`
+	Slug     string   `json:"slug"`
+	Candles  []Candle `json:"candles"`
+}
+
+// persistent reports whether candles are saved to storage
+func (r *Recorder) persistent() bool {
+	return r.store != nil && r.config.Path != ""
+}
+
+// load restores the candles saved at Path, dropping those past retention;
+// a missing object is not an error
+func (r *Recorder) load() error {
+	if !r.persistent() {
+		return nil
+	}
+
+	data, err := r.store.Get(r.config.Path)
+	if errors.Is(err, storage.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var stored map[string]storedSeries
+	if err := sonic.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+
+	cutoff := time.Now().Add(-r.config.Retention)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, ss := range stored {
+		i := 0
+		for i < len(ss.Candles) && ss.Candles[i].Time.Before(cutoff) {
+			i++
+		}
+		if i == len(ss.Candles) {
+			continue
+		}
+		r.markets[id] = &series{question: ss.Question, slug: ss.Slug, candles: ss.Candles[i:]}
+	}
+	return nil
+}
+
+// save writes every market's candles to Path. Failures are logged; the
+// in-memory candles stay authoritative.
+func (r *Recorder) save() {
+	if !r.persistent() {
+		return
+	}
+
+	r.mu.RLock()
+	stored := make(map[string]storedSeries, len(r.markets))
+	for id, s := range r.markets {
+		stored[id] = storedSeries{Question: s.question, Slug: s.slug, Candles: s.candles}
+	}
+	data, err := sonic.Marshal(stored)
+	r.mu.RUnlock()
+
+	if err == nil {
+		err = r.store.Put(r.config.Path, data)
+	}
+	if err != nil {
+		log.Printf("Failed to save recorded candles to %s: %v", r.store.Location(r.config.Path), err)
+	}
+}
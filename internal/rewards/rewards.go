@@ -0,0 +1,390 @@
+// Package rewards estimates a maker's liquidity reward eligibility and
+// daily accrual from their open orders and each market's reward terms
+package rewards
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/pkg/validate"
+)
+
+// twoSidedDivisor scales down single-sided liquidity in markets whose
+// midpoint is between minTwoSided and 1-minTwoSided; outside that range
+// only two-sided liquidity scores
+const (
+	twoSidedDivisor = 3.0
+	minTwoSided     = 0.10
+)
+
+// Reasons an order or market earns nothing
+const (
+	ReasonNoProgram    = "market has no liquidity rewards"
+	ReasonBelowMinSize = "below the minimum size"
+	ReasonTooWide      = "outside the max spread from the midpoint"
+	ReasonOneSided     = "one-sided liquidity does not score at this midpoint"
+	ReasonNoMidpoint   = "midpoint unavailable"
+)
+
+// Order is one open order's contribution
+type Order struct {
+	OrderID  string      `json:"order_id"`
+	TokenID  string      `json:"token_id"`
+	Outcome  string      `json:"outcome,omitempty"`
+	Side     models.Side `json:"side"`
+	Price    float64     `json:"price"`
+	Size     float64     `json:"size"`   // shares still resting
+	Spread   float64     `json:"spread"` // cents from the outcome's midpoint
+	Score    float64     `json:"score"`
+	Eligible bool        `json:"eligible"`
+	Reason   string      `json:"reason,omitempty"`
+}
+
+// Market is the estimate for one market the maker has orders in
+type Market struct {
+	MarketID       string  `json:"market_id"`
+	ConditionID    string  `json:"condition_id"`
+	Question       string  `json:"question"`
+	Slug           string  `json:"slug"`
+	MinSize        float64 `json:"min_size"`     // shares per order
+	MaxSpread      float64 `json:"max_spread"`   // cents from the midpoint
+	DailyRate      float64 `json:"daily_rate"`   // USDC paid across the market's makers per day
+	Midpoint       float64 `json:"midpoint"`     // of the first outcome
+	Score          float64 `json:"score"`        // the maker's two-sided score
+	MarketScore    float64 `json:"market_score"` // the whole book's two-sided score
+	Share          float64 `json:"share"`
+	EstimatedDaily float64 `json:"estimated_daily"` // USDC per day if the book stayed as it is
+	Eligible       bool    `json:"eligible"`
+	Reason         string  `json:"reason,omitempty"`
+	Orders         []Order `json:"orders"`
+}
+
+// Report is a maker's reward estimate across markets
+type Report struct {
+	Address        string   `json:"address"`
+	EstimatedDaily float64  `json:"estimated_daily"`
+	Markets        []Market `json:"markets"`
+}
+
+// openOrder is the subset of a CLOB open order needed to score it
+type openOrder struct {
+	ID           string            `json:"id"`
+	AssetID      string            `json:"asset_id"`
+	Side         string            `json:"side"`
+	Price        models.FlexString `json:"price"`
+	OriginalSize models.FlexString `json:"original_size"`
+	SizeMatched  models.FlexString `json:"size_matched"`
+	MakerAddress string            `json:"maker_address"`
+}
+
+// orderBook is the subset of a CLOB order book needed to score it
+type orderBook struct {
+	Bids []models.PriceLevel `json:"bids"`
+	Asks []models.PriceLevel `json:"asks"`
+}
+
+// Estimator scores open orders with Polymarket's liquidity reward formula:
+// each order within the max spread and at least the min size scores
+// ((max spread - spread) / max spread)² × size. Bids on the first outcome
+// and asks on the second form one side of the book, the rest the other;
+// the maker's score is the smaller side, or a third of the larger when
+// that is more and the midpoint allows single-sided liquidity. Their share
+// of the daily pool is that score over the same score for the whole book,
+// an estimate that assumes the book stays as it is.
+type Estimator struct {
+	clob    *polymarket.ClobClient
+	gamma   *polymarket.GammaClient
+	catalog *catalog.Catalog
+}
+
+// New creates a new rewards estimator
+func New(clob *polymarket.ClobClient, gamma *polymarket.GammaClient, cat *catalog.Catalog) *Estimator {
+	return &Estimator{clob: clob, gamma: gamma, catalog: cat}
+}
+
+// Estimate scores the open orders of the authenticated account that were
+// made by address (all of them when the CLOB omits maker addresses)
+func (e *Estimator) Estimate(address string, authHeaders map[string]string) (*Report, error) {
+	if err := validate.Address(address); err != nil {
+		return nil, err
+	}
+	orders, err := e.openOrders(authHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Address: address, Markets: []Market{}}
+	byMarket := make(map[string][]openOrder)
+	markets := make(map[string]*models.Market)
+	for _, o := range orders {
+		if o.MakerAddress != "" && !strings.EqualFold(o.MakerAddress, address) {
+			continue
+		}
+		m, err := e.market(o.AssetID)
+		if err != nil {
+			return nil, err
+		}
+		if m == nil {
+			continue
+		}
+		markets[m.ID] = m
+		byMarket[m.ID] = append(byMarket[m.ID], o)
+	}
+
+	for id, orders := range byMarket {
+		estimate, err := e.estimateMarket(markets[id], orders)
+		if err != nil {
+			return nil, err
+		}
+		report.Markets = append(report.Markets, *estimate)
+		report.EstimatedDaily += estimate.EstimatedDaily
+	}
+	report.EstimatedDaily = round(report.EstimatedDaily, 6)
+	sort.Slice(report.Markets, func(i, j int) bool {
+		return report.Markets[i].EstimatedDaily > report.Markets[j].EstimatedDaily
+	})
+	return report, nil
+}
+
+// estimateMarket scores the maker's orders and the book in one market
+func (e *Estimator) estimateMarket(m *models.Market, orders []openOrder) (*Market, error) {
+	out := &Market{
+		MarketID:    m.ID,
+		ConditionID: m.ConditionID,
+		Question:    m.Question,
+		Slug:        m.Slug,
+		MinSize:     m.RewardsMinSize,
+		MaxSpread:   m.RewardsMaxSpread,
+		Orders:      []Order{},
+	}
+	for _, r := range m.ClobRewards {
+		out.DailyRate += r.RewardsDailyRate
+	}
+
+	program := out.MaxSpread > 0 && len(m.ClobTokenIDs) == 2
+	var books [2]*orderBook
+	if program {
+		for i, tokenID := range m.ClobTokenIDs {
+			b, err := e.book(tokenID)
+			if err != nil {
+				return nil, err
+			}
+			books[i] = b
+		}
+		out.Midpoint = midpoint(books[0])
+	}
+
+	var sides [2]float64
+	for _, o := range orders {
+		order := Order{
+			OrderID: o.ID,
+			TokenID: o.AssetID,
+			Side:    models.Side(strings.ToUpper(o.Side)),
+			Price:   o.Price.Float(),
+			Size:    math.Max(o.OriginalSize.Float()-o.SizeMatched.Float(), 0),
+		}
+		outcome := outcomeIndex(m, o.AssetID)
+		if outcome >= 0 && outcome < len(m.Outcomes) {
+			order.Outcome = m.Outcomes[outcome]
+		}
+
+		switch {
+		case !program:
+			order.Reason = ReasonNoProgram
+		case out.Midpoint == 0:
+			order.Reason = ReasonNoMidpoint
+		default:
+			order.Spread = round(math.Abs(order.Price-outcomeMidpoint(out.Midpoint, outcome))*100, 4)
+			order.Score = score(out.MaxSpread, order.Spread, order.Size)
+			switch {
+			case order.Size < out.MinSize:
+				order.Score, order.Reason = 0, ReasonBelowMinSize
+			case order.Score == 0:
+				order.Reason = ReasonTooWide
+			default:
+				order.Eligible = true
+				sides[side(outcome, order.Side)] += order.Score
+			}
+		}
+		out.Orders = append(out.Orders, order)
+	}
+
+	switch {
+	case !program:
+		out.Reason = ReasonNoProgram
+		return out, nil
+	case out.Midpoint == 0:
+		out.Reason = ReasonNoMidpoint
+		return out, nil
+	}
+
+	out.Score = twoSided(sides, out.Midpoint)
+	out.MarketScore = twoSided(bookSides(books, out.Midpoint, out.MinSize, out.MaxSpread), out.Midpoint)
+	if out.Score > 0 {
+		out.Eligible = true
+		out.Share = 1
+		if out.MarketScore > out.Score {
+			out.Share = out.Score / out.MarketScore
+		}
+		out.EstimatedDaily = round(out.Share*out.DailyRate, 6)
+	} else if sides[0]+sides[1] > 0 {
+		out.Reason = ReasonOneSided
+	}
+	out.Score, out.MarketScore, out.Share = round(out.Score, 6), round(out.MarketScore, 6), round(out.Share, 6)
+	return out, nil
+}
+
+// bookSides scores every qualifying level of both outcomes' books
+func bookSides(books [2]*orderBook, mid, minSize, maxSpread float64) [2]float64 {
+	var sides [2]float64
+	for outcome, b := range books {
+		levels := map[models.Side][]models.PriceLevel{models.SideBuy: b.Bids, models.SideSell: b.Asks}
+		for s, list := range levels {
+			for _, l := range list {
+				price, _ := strconv.ParseFloat(l.Price, 64)
+				size, _ := strconv.ParseFloat(l.Size, 64)
+				if size < minSize {
+					continue
+				}
+				spread := math.Abs(price-outcomeMidpoint(mid, outcome)) * 100
+				sides[side(outcome, s)] += score(maxSpread, spread, size)
+			}
+		}
+	}
+	return sides
+}
+
+// score is an order's reward score: quadratic in how far inside the max
+// spread it rests, and linear in size
+func score(maxSpread, spread, size float64) float64 {
+	if spread >= maxSpread {
+		return 0
+	}
+	return math.Pow((maxSpread-spread)/maxSpread, 2) * size
+}
+
+// side folds complementary orders onto one book: bids on the first
+// outcome and asks on the second are side 0, the rest side 1
+func side(outcome int, s models.Side) int {
+	if (outcome == 0) == (s == models.SideBuy) {
+		return 0
+	}
+	return 1
+}
+
+// twoSided combines both sides' scores, rewarding balanced liquidity
+func twoSided(sides [2]float64, mid float64) float64 {
+	min := math.Min(sides[0], sides[1])
+	if mid < minTwoSided || mid > 1-minTwoSided {
+		return min
+	}
+	return math.Max(min, math.Max(sides[0], sides[1])/twoSidedDivisor)
+}
+
+// midpoint is halfway between a book's best bid and ask, or 0 when a side
+// is empty
+func midpoint(b *orderBook) float64 {
+	bid, ask := 0.0, 0.0
+	for _, l := range b.Bids {
+		if p, _ := strconv.ParseFloat(l.Price, 64); p > bid {
+			bid = p
+		}
+	}
+	for _, l := range b.Asks {
+		if p, _ := strconv.ParseFloat(l.Price, 64); p > 0 && (ask == 0 || p < ask) {
+			ask = p
+		}
+	}
+	if bid == 0 || ask == 0 {
+		return 0
+	}
+	return (bid + ask) / 2
+}
+
+// outcomeMidpoint is the midpoint of the given outcome of a binary market
+func outcomeMidpoint(mid float64, outcome int) float64 {
+	if outcome == 1 {
+		return 1 - mid
+	}
+	return mid
+}
+
+func outcomeIndex(m *models.Market, tokenID string) int {
+	for i, id := range m.ClobTokenIDs {
+		if id == tokenID {
+			return i
+		}
+	}
+	return -1
+}
+
+// market finds the market a token belongs to, from the catalog or Gamma;
+// nil when no market lists it
+func (e *Estimator) market(tokenID string) (*models.Market, error) {
+	if e.catalog != nil {
+		if entry, _, ok := e.catalog.Token(tokenID); ok {
+			return &entry.Market, nil
+		}
+	}
+
+	data, _, err := e.gamma.GetMarketByClobTokenID(tokenID)
+	if err != nil {
+		return nil, err
+	}
+	var markets []models.Market
+	if err := sonic.Unmarshal(data, &markets); err != nil {
+		return nil, err
+	}
+	for i := range markets {
+		if outcomeIndex(&markets[i], tokenID) >= 0 {
+			return &markets[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// book fetches a token's order book through the cache
+func (e *Estimator) book(tokenID string) (*orderBook, error) {
+	data, _, err := e.clob.GetOrderBook(tokenID)
+	if err != nil {
+		return nil, err
+	}
+	var b orderBook
+	if err := sonic.Unmarshal(data, &b); err != nil {
+		return nil, errors.Join(errors.New("invalid order book for token "+tokenID), err)
+	}
+	return &b, nil
+}
+
+// openOrders reads the account's open orders, a plain list or a {data: [...]} page
+func (e *Estimator) openOrders(authHeaders map[string]string) ([]openOrder, error) {
+	data, err := e.clob.GetOpenOrders("", authHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []openOrder
+	if err := sonic.Unmarshal(data, &orders); err != nil {
+		var page struct {
+			Data []openOrder `json:"data"`
+		}
+		if err := sonic.Unmarshal(data, &page); err != nil {
+			return nil, err
+		}
+		orders = page.Data
+	}
+	return orders, nil
+}
+
+// round rounds to the given number of decimals
+func round(f float64, decimals int) float64 {
+	p := math.Pow(10, float64(decimals))
+	return math.Round(f*p) / p
+}
@@ -0,0 +1,273 @@
+package risk
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+)
+
+// Rejection codes
+const (
+	CodeBannedMarket     = "RISK_BANNED_MARKET"
+	CodeMaxOrderSize     = "RISK_MAX_ORDER_SIZE"
+	CodeMaxOpenNotional  = "RISK_MAX_OPEN_NOTIONAL"
+	CodeOrderRate        = "RISK_ORDER_RATE"
+	CodeCheckUnavailable = "RISK_CHECK_UNAVAILABLE"
+)
+
+// rateWindow is the rolling window MaxOrdersPerMinute is counted over
+const rateWindow = time.Minute
+
+// Error is a failed pre-trade check
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Order   *int   `json:"order,omitempty"` // index of the offending order in a batch
+	// RetryAfter is when an order rate rejection clears, 0 if it never will
+	RetryAfter time.Duration `json:"-"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// reject builds an Error for the order at index i, or the whole request when i < 0
+func reject(code string, i int, format string, args ...interface{}) *Error {
+	e := &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+	if i >= 0 {
+		e.Order = &i
+	}
+	return e
+}
+
+// Limits are the default limits and per-account overrides in force
+type Limits struct {
+	Default  config.RiskLimits            `json:"default"`
+	Accounts map[string]config.RiskLimits `json:"accounts"`
+}
+
+// openOrder is the subset of a CLOB open order needed for its notional
+type openOrder struct {
+	Price        models.FlexString `json:"price"`
+	OriginalSize models.FlexString `json:"original_size"`
+	SizeMatched  models.FlexString `json:"size_matched"`
+}
+
+// Checker enforces per-account limits before orders are sent upstream.
+// Limits start from config and can be changed at runtime through the admin
+// API; changes are saved to Path.
+type Checker struct {
+	clob     *polymarket.ClobClient
+	resolver *catalog.Resolver
+	config   *config.RiskConfig
+
+	mu     sync.Mutex
+	limits Limits
+	recent map[string][]time.Time // accepted orders per account within rateWindow
+}
+
+// New creates a new checker, restoring limits saved at cfg.Path over the
+// configured ones
+func New(clob *polymarket.ClobClient, resolver *catalog.Resolver, cfg *config.RiskConfig) *Checker {
+	k := &Checker{
+		clob:     clob,
+		resolver: resolver,
+		config:   cfg,
+		limits:   Limits{Default: cfg.Default, Accounts: make(map[string]config.RiskLimits)},
+		recent:   make(map[string][]time.Time),
+	}
+	for account, l := range cfg.Accounts {
+		k.limits.Accounts[account] = l
+	}
+	if err := k.load(); err != nil {
+		log.Printf("Failed to load risk limits from %s: %v", cfg.Path, err)
+	}
+	return k
+}
+
+// Enabled reports whether orders are checked at all
+func (k *Checker) Enabled() bool {
+	return k.config.Enabled
+}
+
+// Limits returns the limits in force
+func (k *Checker) Limits() Limits {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	out := Limits{Default: k.limits.Default, Accounts: make(map[string]config.RiskLimits, len(k.limits.Accounts))}
+	for account, l := range k.limits.Accounts {
+		out.Accounts[account] = l
+	}
+	return out
+}
+
+// For returns the limits that apply to an account
+func (k *Checker) For(account string) config.RiskLimits {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.forLocked(account)
+}
+
+func (k *Checker) forLocked(account string) config.RiskLimits {
+	if l, ok := k.limits.Accounts[account]; ok {
+		return l
+	}
+	return k.limits.Default
+}
+
+// SetDefault replaces the limits of accounts without their own
+func (k *Checker) SetDefault(l config.RiskLimits) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.limits.Default = l
+	k.saveLocked()
+}
+
+// SetAccount replaces an account's limits
+func (k *Checker) SetAccount(account string, l config.RiskLimits) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.limits.Accounts[account] = l
+	k.saveLocked()
+}
+
+// DeleteAccount returns an account to the default limits; false if it had none of its own
+func (k *Checker) DeleteAccount(account string) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.limits.Accounts[account]; !ok {
+		return false
+	}
+	delete(k.limits.Accounts, account)
+	k.saveLocked()
+	return true
+}
+
+// Check runs the pre-trade checks for orders an account is about to place
+// together. authHeaders are the account's, used to look up its open orders
+// when an open notional limit applies. Accepted orders count towards the
+// account's order rate. Failures are *Error.
+func (k *Checker) Check(account string, authHeaders map[string]string, orders []models.CreateOrderRequest) error {
+	if !k.config.Enabled {
+		return nil
+	}
+	l := k.For(account)
+
+	var notional float64
+	for i, o := range orders {
+		if market, banned := k.banned(l.BannedMarkets, o.TokenID); banned {
+			return reject(CodeBannedMarket, i, "Trading in market %s is not allowed", market)
+		}
+		size, _ := strconv.ParseFloat(o.Size, 64)
+		if l.MaxOrderSize > 0 && size > l.MaxOrderSize {
+			return reject(CodeMaxOrderSize, i, "Order size %s exceeds the limit of %s", o.Size, formatFloat(l.MaxOrderSize))
+		}
+		price, _ := strconv.ParseFloat(o.Price, 64)
+		notional += price * size
+	}
+
+	if l.MaxOpenNotional > 0 {
+		open, err := OpenNotional(k.clob, authHeaders)
+		if err != nil {
+			return reject(CodeCheckUnavailable, -1, "Open orders could not be checked: %v", err)
+		}
+		if open+notional > l.MaxOpenNotional {
+			return reject(CodeMaxOpenNotional, -1, "Open notional would be %s USDC, over the limit of %s",
+				formatFloat(open+notional), formatFloat(l.MaxOpenNotional))
+		}
+	}
+
+	// Checked last and under the lock, so concurrent requests cannot both
+	// take the last slot
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := time.Now()
+	recent := k.recent[account]
+	drop := 0
+	for drop < len(recent) && now.Sub(recent[drop]) >= rateWindow {
+		drop++
+	}
+	recent = recent[drop:]
+	if l.MaxOrdersPerMinute > 0 && len(recent)+len(orders) > l.MaxOrdersPerMinute {
+		k.recent[account] = recent
+		e := reject(CodeOrderRate, -1, "Order rate limit of %d per minute reached", l.MaxOrdersPerMinute)
+		if len(orders) <= l.MaxOrdersPerMinute {
+			// Wait for enough of the recent orders to leave the window
+			e.RetryAfter = recent[len(recent)+len(orders)-l.MaxOrdersPerMinute-1].Add(rateWindow).Sub(now)
+		}
+		return e
+	}
+	for range orders {
+		recent = append(recent, now)
+	}
+	if len(recent) == 0 {
+		delete(k.recent, account)
+	} else {
+		k.recent[account] = recent
+	}
+	return nil
+}
+
+// banned reports whether a token, or the market it belongs to, is on the
+// banned list. Tokens that cannot be resolved are matched by ID only.
+func (k *Checker) banned(list []string, tokenID string) (string, bool) {
+	if len(list) == 0 {
+		return "", false
+	}
+	ids := []string{tokenID}
+	if k.resolver != nil {
+		if info, err := k.resolver.Resolve(tokenID); err == nil {
+			ids = append(ids, info.MarketID, info.ConditionID, info.Slug)
+		}
+	}
+	for _, b := range list {
+		for _, id := range ids {
+			if id != "" && strings.EqualFold(strings.TrimSpace(b), id) {
+				return b, true
+			}
+		}
+	}
+	return "", false
+}
+
+// OpenNotional sums price × remaining size, in USDC, over the open orders
+// of the account authHeaders sign for
+func OpenNotional(clob *polymarket.ClobClient, authHeaders map[string]string) (float64, error) {
+	data, err := clob.GetOpenOrders("", authHeaders)
+	if err != nil {
+		return 0, err
+	}
+
+	// A plain list or a {data: [...]} page
+	var orders []openOrder
+	if err := sonic.Unmarshal(data, &orders); err != nil {
+		var page struct {
+			Data []openOrder `json:"data"`
+		}
+		if err := sonic.Unmarshal(data, &page); err != nil {
+			return 0, err
+		}
+		orders = page.Data
+	}
+
+	var total float64
+	for _, o := range orders {
+		total += o.Price.Float() * (o.OriginalSize.Float() - o.SizeMatched.Float())
+	}
+	return total, nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
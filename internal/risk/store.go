@@ -0,0 +1,59 @@
+package risk
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/fsutil"
+)
+
+// load restores the limits saved at Path; a missing file is not an error.
+// Saved limits replace the configured ones, since they are the latest
+// changes made through the admin API.
+func (k *Checker) load() error {
+	if k.config.Path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(k.config.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var saved Limits
+	if err := sonic.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.limits.Default = saved.Default
+	if saved.Accounts != nil {
+		k.limits.Accounts = saved.Accounts
+	}
+	return nil
+}
+
+// saveLocked writes the limits to Path, replacing the file atomically.
+// Accounts are API keys, so the file is readable by the server's user
+// only. Failures are logged; the in-memory limits stay authoritative.
+// Caller holds k.mu.
+func (k *Checker) saveLocked() {
+	if k.config.Path == "" {
+		return
+	}
+
+	data, err := sonic.Marshal(k.limits)
+	if err == nil {
+		err = fsutil.WriteFileAtomic(k.config.Path, data, 0o600)
+	}
+	if err != nil {
+		log.Printf("Failed to save risk limits to %s: %v", k.config.Path, err)
+	}
+}
@@ -0,0 +1,327 @@
+// Package routing places orders for one of several local accounts, so a
+// desk can split its flow between accounts through a single PolyGo. Routes
+// pick the accounts by market or strategy tag and use them in turn,
+// skipping those whose open orders are at their exposure limit. Orders
+// pass the pre-trade checks of the account that takes them.
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/pretrade"
+	"github.com/polygo/internal/risk"
+	"github.com/polygo/pkg/polygoclient"
+)
+
+var (
+	// ErrNoRoute is returned for an order no route matches
+	ErrNoRoute = errors.New("no route matches the order")
+	// ErrExposure is returned when every account of the matching route
+	// would go over its exposure limit
+	ErrExposure = errors.New("every account of the route is at its exposure limit")
+)
+
+// Placement is an order placed for a routed account
+type Placement struct {
+	Account string      `json:"account"`
+	Address string      `json:"address"`
+	Route   int         `json:"route"` // index of the route that matched
+	Order   interface{} `json:"order"` // the CLOB's reply
+}
+
+// AccountStatus is a routing account as reported to the operator
+type AccountStatus struct {
+	Name        string  `json:"name"`
+	Address     string  `json:"address"`
+	MaxExposure float64 `json:"max_exposure,omitempty"`
+	Routed      int64   `json:"routed"`               // orders placed since start
+	Skipped     int64   `json:"skipped"`              // times passed over at the exposure limit
+	LastError   string  `json:"last_error,omitempty"` // of the last exposure lookup or placement
+}
+
+// Status is the routing table and its accounts
+type Status struct {
+	Accounts []AccountStatus     `json:"accounts"`
+	Routes   []config.OrderRoute `json:"routes"`
+}
+
+// account is a routing account and its counters
+type account struct {
+	name   string
+	config config.RoutingAccount
+	creds  *polygoclient.Credentials
+
+	// place serializes the exposure check and placement of orders for an
+	// account with a limit, so concurrent orders cannot both fit
+	place sync.Mutex
+
+	mu        sync.Mutex
+	routed    int64
+	skipped   int64
+	lastError string
+}
+
+// Router picks the account each order is placed for
+type Router struct {
+	clob     *polymarket.ClobClient
+	data     *polymarket.DataClient
+	fills    *polymarket.FillTracker
+	checks   *pretrade.Chain
+	resolver *catalog.Resolver
+	auth     *config.AuthConfig
+
+	accounts map[string]*account
+	routes   []config.OrderRoute
+
+	mu   sync.Mutex
+	next []int // per route, the account to try first
+}
+
+// New creates a new router. Routes naming unknown accounts lose those
+// names, and routes left without accounts are dropped.
+func New(clob *polymarket.ClobClient, data *polymarket.DataClient, fills *polymarket.FillTracker, checks *pretrade.Chain, resolver *catalog.Resolver, auth *config.AuthConfig, cfg *config.RoutingConfig) *Router {
+	r := &Router{
+		clob:     clob,
+		data:     data,
+		fills:    fills,
+		checks:   checks,
+		resolver: resolver,
+		auth:     auth,
+		accounts: make(map[string]*account, len(cfg.Accounts)),
+	}
+	for name, a := range cfg.Accounts {
+		r.accounts[name] = &account{
+			name:   name,
+			config: a,
+			creds:  &polygoclient.Credentials{APIKey: a.APIKey, Secret: a.Secret, Passphrase: a.Passphrase},
+		}
+	}
+
+	for i, route := range cfg.Routes {
+		var names []string
+		for _, name := range route.Accounts {
+			if _, ok := r.accounts[name]; ok {
+				names = append(names, name)
+			} else {
+				log.Printf("Order route %d names unknown account %q", i, name)
+			}
+		}
+		if len(names) == 0 {
+			log.Printf("Order route %d has no accounts and is ignored", i)
+			continue
+		}
+		route.Accounts = names
+		r.routes = append(r.routes, route)
+	}
+	r.next = make([]int, len(r.routes))
+	return r
+}
+
+// Status returns the routing table and its accounts' counters
+func (r *Router) Status() Status {
+	out := Status{Accounts: make([]AccountStatus, 0, len(r.accounts)), Routes: r.routes}
+	if out.Routes == nil {
+		out.Routes = []config.OrderRoute{}
+	}
+	for _, a := range r.accounts {
+		a.mu.Lock()
+		out.Accounts = append(out.Accounts, AccountStatus{
+			Name:        a.name,
+			Address:     a.config.Address,
+			MaxExposure: a.config.MaxExposure,
+			Routed:      a.routed,
+			Skipped:     a.skipped,
+			LastError:   a.lastError,
+		})
+		a.mu.Unlock()
+	}
+	sort.Slice(out.Accounts, func(i, j int) bool { return out.Accounts[i].Name < out.Accounts[j].Name })
+	return out
+}
+
+// Place routes an order and places it for the account picked: the first
+// route matching the order's market and strategy tries its accounts in
+// turn, each order starting one account further along, and the first with
+// room for the order under its exposure limit takes it. The order's maker
+// is set to the account's address. Pre-trade check failures are returned
+// as the checks report them.
+func (r *Router) Place(order models.CreateOrderRequest) (*Placement, error) {
+	i, ok := r.match(&order)
+	if !ok {
+		return nil, ErrNoRoute
+	}
+	route := r.routes[i]
+
+	r.mu.Lock()
+	start := r.next[i]
+	r.next[i] = (start + 1) % len(route.Accounts)
+	r.mu.Unlock()
+
+	price, _ := strconv.ParseFloat(order.Price, 64)
+	size, _ := strconv.ParseFloat(order.Size, 64)
+	notional := price * size
+
+	var lookupErr error
+	for n := 0; n < len(route.Accounts); n++ {
+		a := r.accounts[route.Accounts[(start+n)%len(route.Accounts)]]
+		placement, err := r.placeFor(a, order, notional)
+		var lookup *LookupError
+		switch {
+		case errors.Is(err, ErrExposure):
+			continue
+		case errors.As(err, &lookup):
+			// Try the next account; report this if none takes the order
+			lookupErr = err
+			continue
+		case err != nil:
+			return nil, err
+		}
+		placement.Route = i
+		return placement, nil
+	}
+	if lookupErr != nil {
+		return nil, lookupErr
+	}
+	return nil, ErrExposure
+}
+
+// LookupError is a failure to read an account's open orders for its
+// exposure
+type LookupError struct {
+	Account string
+	Err     error
+}
+
+func (e *LookupError) Error() string {
+	return fmt.Sprintf("open orders of account %s could not be read: %v", e.Account, e.Err)
+}
+
+func (e *LookupError) Unwrap() error { return e.Err }
+
+// placeFor places order for an account, if its exposure limit and the
+// account's pre-trade checks allow
+func (r *Router) placeFor(a *account, order models.CreateOrderRequest, notional float64) (*Placement, error) {
+	if limit := a.config.MaxExposure; limit > 0 {
+		a.place.Lock()
+		defer a.place.Unlock()
+
+		headers := polymarket.SignedHeaders(r.auth, a.creds, "GET", "/orders/open", nil)
+		open, err := risk.OpenNotional(r.clob, headers)
+		if err != nil {
+			a.fail(err)
+			return nil, &LookupError{Account: a.name, Err: err}
+		}
+		if open+notional > limit {
+			a.mu.Lock()
+			a.skipped++
+			a.mu.Unlock()
+			return nil, ErrExposure
+		}
+	}
+
+	order.Maker = a.config.Address
+	reservation, err := r.checks.Check(a.creds, []models.CreateOrderRequest{order})
+	if err != nil {
+		return nil, err
+	}
+
+	// Signed as sent: PolyGo's own fields stay behind
+	upstream := order
+	upstream.Strategy, upstream.PriceMode = "", ""
+	body, err := sonic.Marshal(upstream)
+	if err != nil {
+		r.checks.Release(reservation)
+		return nil, err
+	}
+	headers := polymarket.SignedHeaders(r.auth, a.creds, "POST", "/order", body)
+	data, err := r.clob.CreateOrder(&order, headers)
+	if err != nil {
+		r.checks.Release(reservation)
+		a.fail(err)
+		return nil, err
+	}
+	var placed models.PlacedOrder
+	if err := sonic.Unmarshal(data, &placed); err != nil {
+		return nil, fmt.Errorf("unreadable order response: %w", err)
+	}
+	if placed.Success != nil && !*placed.Success {
+		r.checks.Release(reservation)
+		err := polymarket.ClassifyRejection(placed.ErrorMsg)
+		a.fail(err)
+		return nil, err
+	}
+
+	// Immediate fills invalidate now; resting orders are tracked for later
+	if strings.EqualFold(placed.Status, "matched") {
+		r.data.InvalidateUser(strings.ToLower(a.config.Address))
+	} else if placed.OrderID != "" {
+		r.fills.Track(placed.OrderID, a.config.Address)
+	}
+
+	a.mu.Lock()
+	a.routed++
+	a.lastError = ""
+	a.mu.Unlock()
+
+	var reply interface{}
+	sonic.Unmarshal(data, &reply)
+	return &Placement{Account: a.name, Address: a.config.Address, Order: reply}, nil
+}
+
+func (a *account) fail(err error) {
+	a.mu.Lock()
+	a.lastError = err.Error()
+	a.mu.Unlock()
+}
+
+// match returns the index of the first route matching an order
+func (r *Router) match(order *models.CreateOrderRequest) (int, bool) {
+	var ids []string
+	for i, route := range r.routes {
+		if route.Strategy != "" && !strings.EqualFold(route.Strategy, order.Strategy) {
+			continue
+		}
+		if route.Market != "" {
+			if ids == nil {
+				ids = r.marketIDs(order.TokenID)
+			}
+			if !containsFold(ids, strings.TrimSpace(route.Market)) {
+				continue
+			}
+		}
+		return i, true
+	}
+	return 0, false
+}
+
+// marketIDs returns the IDs a route's market may name a token by. Tokens
+// that cannot be resolved are matched by ID only.
+func (r *Router) marketIDs(tokenID string) []string {
+	ids := []string{tokenID}
+	if r.resolver != nil {
+		if info, err := r.resolver.Resolve(tokenID); err == nil {
+			ids = append(ids, info.MarketID, info.ConditionID, info.Slug)
+		}
+	}
+	return ids
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if v != "" && strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
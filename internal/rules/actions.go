@@ -0,0 +1,260 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/eventbus"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/pkg/polygoclient"
+)
+
+// Action types
+const (
+	ActionWebhook = "webhook" // a rule.triggered webhook, tagged with the rule name
+	ActionPublish = "publish" // a rule.triggered event bus message on Topic
+	ActionLog     = "log"     // a log line
+	ActionOrder   = "order"   // an order for a configured account
+)
+
+// Action result statuses
+const (
+	StatusDone   = "done"
+	StatusFailed = "failed"
+)
+
+// ActionResult is what one action of a trigger did
+type ActionResult struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	OrderID string `json:"order_id,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// validateAction normalizes an action and checks it can run
+func (e *Engine) validateAction(a *config.RuleAction) error {
+	a.Type = strings.ToLower(strings.TrimSpace(a.Type))
+
+	switch a.Type {
+	case ActionWebhook:
+		if e.webhooks == nil {
+			return errors.New("webhook actions need webhooks")
+		}
+		*a = config.RuleAction{Type: a.Type}
+
+	case ActionLog:
+		*a = config.RuleAction{Type: a.Type}
+
+	case ActionPublish:
+		a.Topic = strings.TrimSpace(a.Topic)
+		if a.Topic == "" {
+			return errors.New("publish actions need a topic")
+		}
+		if e.bus == nil || !e.bus.Enabled() {
+			return errors.New("publish actions need the event bus enabled")
+		}
+		*a = config.RuleAction{Type: a.Type, Topic: a.Topic}
+
+	case ActionOrder:
+		a.Account = strings.ToLower(strings.TrimSpace(a.Account))
+		a.Side = strings.ToUpper(strings.TrimSpace(a.Side))
+		a.OrderType = strings.ToUpper(strings.TrimSpace(a.OrderType))
+		a.Topic = ""
+		if a.OrderType == "" {
+			a.OrderType = string(models.OrderTypeGTC)
+		}
+
+		if _, ok := e.account(a.Account); !ok {
+			return fmt.Errorf("order actions need one of the configured accounts (%s)", strings.Join(e.accountNames(), ", "))
+		}
+		if a.Side != string(models.SideBuy) && a.Side != string(models.SideSell) {
+			return errors.New("side must be BUY or SELL")
+		}
+		if a.Size <= 0 {
+			return errors.New("size must be positive")
+		}
+		if a.Price < 0 || a.Price >= 1 {
+			return errors.New("price must be between 0 and 1 (0 = the event's price)")
+		}
+		if a.OrderType != string(models.OrderTypeGTC) && a.OrderType != string(models.OrderTypeFOK) {
+			return errors.New("order_type must be GTC or FOK")
+		}
+
+	default:
+		return errors.New("type must be webhook, publish, log or order")
+	}
+	return nil
+}
+
+// account returns a configured account by name. Config keys are
+// lowercased when loaded, so names are matched case-insensitively.
+func (e *Engine) account(name string) (config.RuleAccount, bool) {
+	for k, acct := range e.config.Accounts {
+		if strings.EqualFold(k, name) {
+			return acct, true
+		}
+	}
+	return config.RuleAccount{}, false
+}
+
+func (e *Engine) accountNames() []string {
+	names := make([]string, 0, len(e.config.Accounts))
+	for k := range e.config.Accounts {
+		names = append(names, strings.ToLower(k))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// run performs a trigger's actions in order; a failed action does not stop
+// the ones after it
+func (e *Engine) run(f firing) []ActionResult {
+	results := make([]ActionResult, 0, len(f.rule.Actions))
+	for _, a := range f.rule.Actions {
+		res := ActionResult{Type: a.Type, Status: StatusDone}
+		var err error
+
+		switch a.Type {
+		case ActionWebhook:
+			e.webhooks.PublishTagged(EventRuleTriggered, []string{f.rule.Name}, f.trigger)
+		case ActionPublish:
+			if !e.bus.Emit(a.Topic, eventbus.Event{
+				Type:      EventRuleTriggered,
+				Market:    f.event.Market,
+				AssetID:   f.event.AssetID,
+				Timestamp: f.trigger.Time.UnixMilli(),
+				Data:      f.trigger,
+			}) {
+				err = errors.New("event bus queue is full")
+			}
+		case ActionLog:
+			log.Printf("Rule %s triggered by %s on market %s: %s", f.rule.Name, f.event.Type, f.event.Market, formatValues(f.trigger.Values))
+		case ActionOrder:
+			res.OrderID, err = e.order(a, &f.event)
+		}
+
+		if err != nil {
+			res.Status, res.Error = StatusFailed, err.Error()
+			log.Printf("Rule %s %s action failed: %v", f.rule.Name, a.Type, err)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// order places an action's order for its account, once it passes the
+// pre-trade checks, and returns the order ID
+func (e *Engine) order(a config.RuleAction, event *eventbus.Event) (string, error) {
+	acct, ok := e.account(a.Account)
+	if !ok {
+		return "", fmt.Errorf("account %s is no longer configured", a.Account)
+	}
+
+	tokenID := a.TokenID
+	if tokenID == "" {
+		tokenID = event.AssetID
+	}
+	if tokenID == "" {
+		return "", errors.New("the event has no token to trade")
+	}
+	price := a.Price
+	if price == 0 {
+		price = eventPrice(event, models.Side(a.Side))
+	}
+	if price <= 0 || price >= 1 {
+		return "", errors.New("the event has no usable price")
+	}
+
+	req := &models.CreateOrderRequest{
+		TokenID: tokenID,
+		Side:    models.Side(a.Side),
+		Price:   strconv.FormatFloat(price, 'f', -1, 64),
+		// The CLOB accepts sizes to two decimals
+		Size:  strconv.FormatFloat(math.Floor(a.Size*100)/100, 'f', 2, 64),
+		Type:  models.OrderType(a.OrderType),
+		Maker: acct.Address,
+	}
+	body, err := sonic.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	creds := &polygoclient.Credentials{APIKey: acct.APIKey, Secret: acct.Secret, Passphrase: acct.Passphrase}
+	reservation, err := e.checks.Check(creds, []models.CreateOrderRequest{*req})
+	if err != nil {
+		return "", err
+	}
+	headers := polymarket.SignedHeaders(e.auth, creds, "POST", "/order", body)
+
+	data, err := e.clob.CreateOrder(req, headers)
+	if err != nil {
+		e.checks.Release(reservation)
+		return "", err
+	}
+	var placed models.PlacedOrder
+	if err := sonic.Unmarshal(data, &placed); err != nil {
+		return "", errors.New("unreadable order response")
+	}
+	if placed.Success != nil && !*placed.Success {
+		e.checks.Release(reservation)
+		return "", errors.New(placed.ErrorMsg)
+	}
+
+	// Immediate fills invalidate now; resting orders are tracked for later
+	if strings.EqualFold(placed.Status, "matched") {
+		e.data.InvalidateUser(strings.ToLower(acct.Address))
+	} else {
+		e.fills.Track(placed.OrderID, acct.Address)
+	}
+	return placed.OrderID, nil
+}
+
+// eventPrice is the price to trade an event's token at: the best ask to
+// buy or best bid to sell when the event has them, else its price
+func eventPrice(event *eventbus.Event, side models.Side) float64 {
+	switch d := event.Data.(type) {
+	case eventbus.Trade:
+		return d.Price
+	case eventbus.PriceChange:
+		if side == models.SideBuy && d.BestAsk != nil {
+			return *d.BestAsk
+		}
+		if side == models.SideSell && d.BestBid != nil {
+			return *d.BestBid
+		}
+		return d.Price
+	}
+	return 0
+}
+
+// formatValues formats trigger values as name=value pairs, by name
+func formatValues(values map[string]interface{}) string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		switch v := values[name].(type) {
+		case float64:
+			parts[i] = name + "=" + formatNumber(v)
+		default:
+			parts[i] = fmt.Sprintf("%s=%v", name, v)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatNumber formats a number without trailing zeros
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,619 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// kind is the type of an expression's value
+type kind int
+
+const (
+	kindNumber kind = iota
+	kindString
+	kindBool
+)
+
+func (k kind) String() string {
+	switch k {
+	case kindNumber:
+		return "number"
+	case kindString:
+		return "string"
+	}
+	return "boolean"
+}
+
+// value is the result of evaluating an expression of some kind
+type value struct {
+	num float64
+	str string
+	b   bool
+}
+
+// node is a compiled expression. eval reports false when a field it reads
+// is missing from the event (e.g. best_bid of a trade).
+type node interface {
+	kind() kind
+	eval(env *env) (value, bool)
+}
+
+// Condition is a compiled rule condition: comparisons of event fields,
+// numbers and strings combined with AND, OR and NOT, e.g.
+//
+//	volume_24h > 1e6 AND abs(price_change_5m) > 0.05 AND side == "BUY"
+type Condition struct {
+	root   node
+	fields []*fieldRef   // fields read, in order of appearance
+	window time.Duration // longest price_change_<window> read
+}
+
+// Compile parses a condition. price_change_<window> fields may look back at
+// most maxWindow.
+func Compile(src string, maxWindow time.Duration) (*Condition, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{toks: toks, maxWindow: maxWindow, cond: &Condition{}}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %s at %d", t, t.pos)
+	}
+	if root.kind() != kindBool {
+		return nil, fmt.Errorf("condition is a %s, not a comparison", root.kind())
+	}
+	p.cond.root = root
+	return p.cond, nil
+}
+
+// match reports whether an event satisfies the condition. Comparisons with
+// a missing field are false.
+func (c *Condition) match(env *env) bool {
+	v, ok := c.root.eval(env)
+	return ok && v.b
+}
+
+// Fields returns the fields the condition reads
+func (c *Condition) Fields() []string {
+	names := make([]string, len(c.fields))
+	for i, f := range c.fields {
+		names[i] = f.name
+	}
+	return names
+}
+
+// values returns the fields the condition reads that an event has
+func (c *Condition) values(env *env) map[string]interface{} {
+	out := make(map[string]interface{}, len(c.fields))
+	for _, f := range c.fields {
+		v, ok := f.eval(env)
+		if !ok {
+			continue
+		}
+		if f.kind() == kindString {
+			out[f.name] = v.str
+		} else {
+			out[f.name] = v.num
+		}
+	}
+	return out
+}
+
+// Tokens
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokKind
+	text string
+	num  float64
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of condition"
+	case tokString:
+		return strconv.Quote(t.text)
+	}
+	return "'" + t.text + "'"
+}
+
+// lex splits a condition into tokens
+func lex(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			j := i
+			for j < len(src) && (isDigit(src[j]) || src[j] == '.' ||
+				(src[j] == 'e' || src[j] == 'E') ||
+				(src[j] == '+' || src[j] == '-') && (src[j-1] == 'e' || src[j-1] == 'E')) {
+				j++
+			}
+			n, err := strconv.ParseFloat(src[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at %d", src[i:j], i)
+			}
+			toks = append(toks, token{kind: tokNumber, text: src[i:j], num: n, pos: i})
+			i = j
+
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				j++
+			}
+			if j == len(src) {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			toks = append(toks, token{kind: tokString, text: src[i+1 : j], pos: i})
+			i = j + 1
+
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i
+			for j < len(src) && (src[j] == '_' || isDigit(src[j]) || unicode.IsLetter(rune(src[j]))) {
+				j++
+			}
+			toks = append(toks, token{kind: tokIdent, text: src[i:j], pos: i})
+			i = j
+
+		default:
+			op := ""
+			for _, candidate := range []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "+", "-", "*", "/", "(", ")"} {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected %q at %d", c, i)
+			}
+			toks = append(toks, token{kind: tokOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(toks, token{kind: tokEOF, pos: len(src)}), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// Parser
+//
+//	or      = and { (OR | "||") and }
+//	and     = not { (AND | "&&") not }
+//	not     = (NOT | "!") not | compare
+//	compare = sum [ ("==" | "!=" | "<" | "<=" | ">" | ">=") sum ]
+//	sum     = product { ("+" | "-") product }
+//	product = unary { ("*" | "/") unary }
+//	unary   = "-" unary | primary
+//	primary = number | string | TRUE | FALSE | field | abs "(" or ")" | "(" or ")"
+
+type parser struct {
+	toks      []token
+	at        int
+	maxWindow time.Duration
+	cond      *Condition
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.at]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.at]
+	if t.kind != tokEOF {
+		p.at++
+	}
+	return t
+}
+
+// accept consumes the next token if it is one of the given operators or
+// (case-insensitive) keywords
+func (p *parser) accept(texts ...string) (token, bool) {
+	t := p.peek()
+	if t.kind != tokOp && t.kind != tokIdent {
+		return t, false
+	}
+	for _, text := range texts {
+		if t.kind == tokOp && t.text == text || t.kind == tokIdent && strings.EqualFold(t.text, text) {
+			p.next()
+			return t, true
+		}
+	}
+	return t, false
+}
+
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("OR", "||")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		if err := wantKinds(op, kindBool, left, right); err != nil {
+			return nil, err
+		}
+		left = &logical{or: true, left: left, right: right}
+	}
+}
+
+func (p *parser) and() (node, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("AND", "&&")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		if err := wantKinds(op, kindBool, left, right); err != nil {
+			return nil, err
+		}
+		left = &logical{left: left, right: right}
+	}
+}
+
+func (p *parser) not() (node, error) {
+	if op, ok := p.accept("NOT", "!"); ok {
+		operand, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		if err := wantKinds(op, kindBool, operand); err != nil {
+			return nil, err
+		}
+		return &negation{operand: operand}, nil
+	}
+	return p.compare()
+}
+
+func (p *parser) compare() (node, error) {
+	left, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("==", "!=", "<=", ">=", "<", ">")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+
+	if left.kind() != right.kind() {
+		return nil, fmt.Errorf("%s at %d compares a %s with a %s", op, op.pos, left.kind(), right.kind())
+	}
+	if left.kind() != kindNumber && op.text != "==" && op.text != "!=" {
+		return nil, fmt.Errorf("%s at %d needs numbers", op, op.pos)
+	}
+	return &comparison{op: op.text, left: left, right: right}, nil
+}
+
+func (p *parser) sum() (node, error) {
+	left, err := p.product()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.product()
+		if err != nil {
+			return nil, err
+		}
+		if err := wantKinds(op, kindNumber, left, right); err != nil {
+			return nil, err
+		}
+		left = &arithmetic{op: op.text[0], left: left, right: right}
+	}
+}
+
+func (p *parser) product() (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*", "/")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		if err := wantKinds(op, kindNumber, left, right); err != nil {
+			return nil, err
+		}
+		left = &arithmetic{op: op.text[0], left: left, right: right}
+	}
+}
+
+func (p *parser) unary() (node, error) {
+	if op, ok := p.accept("-"); ok {
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		if err := wantKinds(op, kindNumber, operand); err != nil {
+			return nil, err
+		}
+		return &arithmetic{op: '-', left: &literal{k: kindNumber}, right: operand}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return &literal{k: kindNumber, v: value{num: t.num}}, nil
+	case tokString:
+		return &literal{k: kindString, v: value{str: t.text}}, nil
+
+	case tokOp:
+		if t.text != "(" {
+			break
+		}
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+
+	case tokIdent:
+		name := strings.ToLower(t.text)
+		switch name {
+		case "true", "false":
+			return &literal{k: kindBool, v: value{b: name == "true"}}, nil
+		case "abs":
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			arg, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			if err := wantKinds(t, kindNumber, arg); err != nil {
+				return nil, err
+			}
+			return &absolute{arg: arg}, nil
+		}
+		return p.field(t, name)
+	}
+	return nil, fmt.Errorf("unexpected %s at %d", t, t.pos)
+}
+
+// field resolves a field name
+func (p *parser) field(t token, name string) (node, error) {
+	if f, ok := fields[name]; ok {
+		return p.read(name, f), nil
+	}
+
+	if strings.HasPrefix(name, priceChangePrefix) {
+		window, err := time.ParseDuration(strings.TrimPrefix(name, priceChangePrefix))
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("%s at %d needs a window like price_change_5m", t, t.pos)
+		}
+		if window > p.maxWindow {
+			return nil, fmt.Errorf("%s at %d looks back more than %s", t, t.pos, p.maxWindow)
+		}
+		if window > p.cond.window {
+			p.cond.window = window
+		}
+		return p.read(name, priceChange(window)), nil
+	}
+	return nil, fmt.Errorf("unknown field %s at %d", t, t.pos)
+}
+
+// read returns a reference to a field, recording that the condition reads it
+func (p *parser) read(name string, f field) *fieldRef {
+	for _, ref := range p.cond.fields {
+		if ref.name == name {
+			return ref
+		}
+	}
+	ref := &fieldRef{name: name, field: f}
+	p.cond.fields = append(p.cond.fields, ref)
+	return ref
+}
+
+func (p *parser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		t := p.peek()
+		return fmt.Errorf("expected '%s' at %d, got %s", op, t.pos, t)
+	}
+	return nil
+}
+
+// wantKinds checks that an operator's operands are all of kind k
+func wantKinds(op token, k kind, operands ...node) error {
+	for _, n := range operands {
+		if n.kind() != k {
+			return fmt.Errorf("%s at %d needs a %s, got a %s", op, op.pos, k, n.kind())
+		}
+	}
+	return nil
+}
+
+// Nodes
+
+type literal struct {
+	k kind
+	v value
+}
+
+func (n *literal) kind() kind              { return n.k }
+func (n *literal) eval(*env) (value, bool) { return n.v, true }
+
+type fieldRef struct {
+	name  string
+	field field
+}
+
+func (n *fieldRef) kind() kind                { return n.field.kind }
+func (n *fieldRef) eval(e *env) (value, bool) { return n.field.get(e) }
+
+type logical struct {
+	or          bool
+	left, right node
+}
+
+func (n *logical) kind() kind { return kindBool }
+
+// eval treats a missing operand as false, so OR still matches on the
+// other side
+func (n *logical) eval(e *env) (value, bool) {
+	l, ok := n.left.eval(e)
+	l.b = l.b && ok
+	if l.b == n.or {
+		return value{b: l.b}, true
+	}
+	r, ok := n.right.eval(e)
+	return value{b: r.b && ok}, true
+}
+
+type negation struct {
+	operand node
+}
+
+func (n *negation) kind() kind { return kindBool }
+
+func (n *negation) eval(e *env) (value, bool) {
+	v, ok := n.operand.eval(e)
+	if !ok {
+		return value{}, false
+	}
+	return value{b: !v.b}, true
+}
+
+type comparison struct {
+	op          string
+	left, right node
+}
+
+func (n *comparison) kind() kind { return kindBool }
+
+func (n *comparison) eval(e *env) (value, bool) {
+	l, ok := n.left.eval(e)
+	if !ok {
+		return value{}, false
+	}
+	r, ok := n.right.eval(e)
+	if !ok {
+		return value{}, false
+	}
+
+	if n.left.kind() == kindString {
+		eq := strings.EqualFold(l.str, r.str)
+		return value{b: eq == (n.op == "==")}, true
+	}
+	if n.left.kind() == kindBool {
+		return value{b: (l.b == r.b) == (n.op == "==")}, true
+	}
+
+	var b bool
+	switch n.op {
+	case "==":
+		b = l.num == r.num
+	case "!=":
+		b = l.num != r.num
+	case "<":
+		b = l.num < r.num
+	case "<=":
+		b = l.num <= r.num
+	case ">":
+		b = l.num > r.num
+	case ">=":
+		b = l.num >= r.num
+	}
+	return value{b: b}, true
+}
+
+type arithmetic struct {
+	op          byte
+	left, right node
+}
+
+func (n *arithmetic) kind() kind { return kindNumber }
+
+func (n *arithmetic) eval(e *env) (value, bool) {
+	l, ok := n.left.eval(e)
+	if !ok {
+		return value{}, false
+	}
+	r, ok := n.right.eval(e)
+	if !ok {
+		return value{}, false
+	}
+
+	switch n.op {
+	case '+':
+		return value{num: l.num + r.num}, true
+	case '-':
+		return value{num: l.num - r.num}, true
+	case '*':
+		return value{num: l.num * r.num}, true
+	}
+	// Division by zero has no meaningful result; treat it as missing
+	if r.num == 0 {
+		return value{}, false
+	}
+	return value{num: l.num / r.num}, true
+}
+
+type absolute struct {
+	arg node
+}
+
+func (n *absolute) kind() kind { return kindNumber }
+
+func (n *absolute) eval(e *env) (value, bool) {
+	v, ok := n.arg.eval(e)
+	return value{num: math.Abs(v.num)}, ok
+}
@@ -0,0 +1,270 @@
+package rules
+
+import (
+	"sync"
+	"time"
+
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/eventbus"
+)
+
+// priceChangePrefix starts the price_change_<window> fields
+const priceChangePrefix = "price_change_"
+
+// priceSampleInterval is the resolution of the price history kept for
+// price_change_<window>; updates within it replace the latest sample
+const priceSampleInterval = 5 * time.Second
+
+// field is something a condition can read from an event
+type field struct {
+	kind kind
+	get  func(e *env) (value, bool)
+}
+
+// fields are the event fields conditions can read, besides
+// price_change_<window>
+var fields = map[string]field{
+	// The event
+	"type":     stringField(func(e *env) string { return e.event.Type }),
+	"market":   stringField(func(e *env) string { return e.event.Market }),
+	"asset_id": stringField(func(e *env) string { return e.event.AssetID }),
+	"side": stringField(func(e *env) string {
+		switch d := e.event.Data.(type) {
+		case eventbus.Trade:
+			return d.Side
+		case eventbus.PriceChange:
+			return d.Side
+		}
+		return ""
+	}),
+	"price": numberField(func(e *env) (float64, bool) {
+		switch d := e.event.Data.(type) {
+		case eventbus.Trade:
+			return d.Price, true
+		case eventbus.PriceChange:
+			return d.Price, true
+		}
+		return 0, false
+	}),
+	"size": numberField(func(e *env) (float64, bool) {
+		switch d := e.event.Data.(type) {
+		case eventbus.Trade:
+			return d.Size, true
+		case eventbus.PriceChange:
+			return d.Size, true
+		}
+		return 0, false
+	}),
+	"notional": numberField(func(e *env) (float64, bool) {
+		if d, ok := e.event.Data.(eventbus.Trade); ok {
+			return d.Price * d.Size, true
+		}
+		return 0, false
+	}),
+	"best_bid": numberField(func(e *env) (float64, bool) {
+		if d, ok := e.event.Data.(eventbus.PriceChange); ok && d.BestBid != nil {
+			return *d.BestBid, true
+		}
+		return 0, false
+	}),
+	"best_ask": numberField(func(e *env) (float64, bool) {
+		if d, ok := e.event.Data.(eventbus.PriceChange); ok && d.BestAsk != nil {
+			return *d.BestAsk, true
+		}
+		return 0, false
+	}),
+	"spread": numberField(func(e *env) (float64, bool) {
+		if d, ok := e.event.Data.(eventbus.PriceChange); ok && d.BestBid != nil && d.BestAsk != nil {
+			return *d.BestAsk - *d.BestBid, true
+		}
+		return 0, false
+	}),
+	"winning_outcome": stringField(func(e *env) string {
+		if d, ok := e.event.Data.(eventbus.Resolution); ok {
+			return d.WinningOutcome
+		}
+		return ""
+	}),
+
+	// The market, from the catalog
+	"slug":     stringField(func(e *env) string { return entryString(e, func(m *catalog.Entry) string { return m.Slug }) }),
+	"category": stringField(func(e *env) string { return entryString(e, func(m *catalog.Entry) string { return m.Category }) }),
+	"outcome": stringField(func(e *env) string {
+		m, outcome := e.market()
+		if m == nil || outcome >= len(m.Outcomes) {
+			return ""
+		}
+		return m.Outcomes[outcome]
+	}),
+	"volume":     entryNumber(func(m *catalog.Entry) float64 { return m.Volume.Float() }),
+	"volume_24h": entryNumber(func(m *catalog.Entry) float64 { return m.Volume24hr.Float() }),
+	"liquidity":  entryNumber(func(m *catalog.Entry) float64 { return m.Liquidity.Float() }),
+	"hours_to_close": numberField(func(e *env) (float64, bool) {
+		m, _ := e.market()
+		if m == nil || m.EndDate.IsZero() {
+			return 0, false
+		}
+		return m.EndDate.Sub(e.now).Hours(), true
+	}),
+}
+
+// stringField is a string field; empty means missing
+func stringField(get func(e *env) string) field {
+	return field{kind: kindString, get: func(e *env) (value, bool) {
+		s := get(e)
+		return value{str: s}, s != ""
+	}}
+}
+
+func numberField(get func(e *env) (float64, bool)) field {
+	return field{kind: kindNumber, get: func(e *env) (value, bool) {
+		n, ok := get(e)
+		return value{num: n}, ok
+	}}
+}
+
+// entryNumber is a number from the event's catalog market
+func entryNumber(get func(m *catalog.Entry) float64) field {
+	return numberField(func(e *env) (float64, bool) {
+		m, _ := e.market()
+		if m == nil {
+			return 0, false
+		}
+		return get(m), true
+	})
+}
+
+func entryString(e *env, get func(m *catalog.Entry) string) string {
+	m, _ := e.market()
+	if m == nil {
+		return ""
+	}
+	return get(m)
+}
+
+// priceChange is price_change_<window>: the token's price now minus its
+// price window ago, missing until the history reaches back that far
+func priceChange(window time.Duration) field {
+	return numberField(func(e *env) (float64, bool) {
+		if e.event.AssetID == "" {
+			return 0, false
+		}
+		return e.prices.change(e.event.AssetID, e.now, window)
+	})
+}
+
+// env is what a condition is evaluated against: an event and the state
+// around it
+type env struct {
+	event   *eventbus.Event
+	now     time.Time
+	catalog *catalog.Catalog
+	prices  *priceHistory
+
+	entry   *catalog.Entry
+	outcome int
+	looked  bool
+}
+
+// market returns the catalog market of the event's token, or of the first
+// token of a resolution
+func (e *env) market() (*catalog.Entry, int) {
+	if e.looked {
+		return e.entry, e.outcome
+	}
+	e.looked = true
+	if e.catalog == nil {
+		return nil, 0
+	}
+
+	token := e.event.AssetID
+	if r, ok := e.event.Data.(eventbus.Resolution); ok && token == "" && len(r.AssetIDs) > 0 {
+		token = r.AssetIDs[0]
+	}
+	if entry, outcome, ok := e.catalog.Token(token); ok {
+		e.entry, e.outcome = entry, outcome
+	}
+	return e.entry, e.outcome
+}
+
+// priceSample is a token's price at a time
+type priceSample struct {
+	at    time.Time
+	price float64
+}
+
+// priceHistory keeps recent prices per token: the last trade price, or the
+// best bid/ask midpoint of price changes
+type priceHistory struct {
+	mu     sync.Mutex
+	tokens map[string][]priceSample // oldest first
+}
+
+func newPriceHistory() *priceHistory {
+	return &priceHistory{tokens: make(map[string][]priceSample)}
+}
+
+// observe records the price an event carries, keeping window of history
+// (and the sample just before it)
+func (h *priceHistory) observe(event *eventbus.Event, now time.Time, window time.Duration) {
+	var price float64
+	switch d := event.Data.(type) {
+	case eventbus.Trade:
+		price = d.Price
+	case eventbus.PriceChange:
+		if d.BestBid == nil || d.BestAsk == nil {
+			return
+		}
+		price = (*d.BestBid + *d.BestAsk) / 2
+	default:
+		return
+	}
+	if event.AssetID == "" || price <= 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	samples := h.tokens[event.AssetID]
+	if n := len(samples); n > 0 && now.Sub(samples[n-1].at) < priceSampleInterval {
+		samples[n-1].price = price
+	} else {
+		samples = append(samples, priceSample{at: now, price: price})
+	}
+
+	cutoff := now.Add(-window)
+	drop := 0
+	for drop+1 < len(samples) && !samples[drop+1].at.After(cutoff) {
+		drop++
+	}
+	h.tokens[event.AssetID] = samples[drop:]
+}
+
+// change returns a token's latest price minus its price window before now
+func (h *priceHistory) change(token string, now time.Time, window time.Duration) (float64, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	samples := h.tokens[token]
+	cutoff := now.Add(-window)
+	for i := len(samples) - 1; i >= 0; i-- {
+		if !samples[i].at.After(cutoff) {
+			return samples[len(samples)-1].price - samples[i].price, true
+		}
+	}
+	return 0, false
+}
+
+// sweep forgets tokens with no price since window before now
+func (h *priceHistory) sweep(now time.Time, window time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cutoff := now.Add(-window)
+	for token, samples := range h.tokens {
+		if len(samples) == 0 || samples[len(samples)-1].at.Before(cutoff) {
+			delete(h.tokens, token)
+		}
+	}
+}
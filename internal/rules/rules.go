@@ -0,0 +1,496 @@
+// Package rules is an embedded rule engine: operators define conditions
+// over upstream market events ("volume_24h > 1e6 AND price_change_5m >
+// 0.05") and the actions run when an event matches (a webhook, an event
+// bus message, a log line or an order for a named account).
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/eventbus"
+	"github.com/polygo/internal/idgen"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/pretrade"
+	"github.com/polygo/internal/webhooks"
+)
+
+// EventRuleTriggered is the webhook and event bus event of a webhook or
+// publish action
+const EventRuleTriggered = "rule.triggered"
+
+var (
+	// ErrInvalidRule is wrapped by every rule validation error
+	ErrInvalidRule = errors.New("invalid rule")
+	// ErrNotFound is returned for an unknown rule name
+	ErrNotFound = errors.New("rule not found")
+	// ErrExists is returned when creating a rule whose name is taken
+	ErrExists = errors.New("rule already exists")
+	// ErrTooManyRules is returned when MaxRules rules already exist
+	ErrTooManyRules = errors.New("too many rules")
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// Rule is a rule and how often it fired
+type Rule struct {
+	config.RuleSpec
+	Fields    []string   `json:"fields"` // fields the condition reads
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Fired     int64      `json:"fired"`
+	Dropped   int64      `json:"dropped,omitempty"` // triggers lost to a full queue
+	LastFired *time.Time `json:"last_fired,omitempty"`
+}
+
+// Trigger is one firing of a rule and what its actions did
+type Trigger struct {
+	ID        string                 `json:"id"`
+	Rule      string                 `json:"rule"`
+	Time      time.Time              `json:"time"`
+	EventType string                 `json:"event_type"`
+	Market    string                 `json:"market"`
+	AssetID   string                 `json:"asset_id,omitempty"`
+	Values    map[string]interface{} `json:"values"` // fields the condition read
+	Actions   []ActionResult         `json:"actions,omitempty"`
+}
+
+// rule is a Rule with its compiled condition, cooldowns and triggers
+type rule struct {
+	Rule
+	cond     *Condition
+	cooldown time.Duration
+	triggers []Trigger // newest first
+
+	mu    sync.Mutex
+	fired map[string]time.Time // last trigger per market
+}
+
+// firing is a trigger waiting for its actions
+type firing struct {
+	rule    *rule
+	event   eventbus.Event
+	trigger Trigger
+}
+
+// Engine evaluates rules against upstream market channel events and runs
+// the actions of those that match from a bounded queue
+type Engine struct {
+	ws         *polymarket.WSManager
+	catalog    *catalog.Catalog
+	clob       *polymarket.ClobClient
+	data       *polymarket.DataClient
+	fills      *polymarket.FillTracker
+	checks     *pretrade.Chain
+	webhooks   *webhooks.Dispatcher
+	bus        *eventbus.Publisher
+	auth       *config.AuthConfig
+	config     *config.RulesConfig
+	normalizer *eventbus.Normalizer
+	prices     *priceHistory
+
+	mu     sync.RWMutex
+	rules  map[string]*rule
+	active []*rule       // enabled rules, evaluated without holding mu
+	window time.Duration // longest price window the active rules read
+	subs   map[string]chan []byte
+
+	queue chan firing
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a new rule engine with the configured rules, or those saved
+// at Path when there are any
+func New(ws *polymarket.WSManager, cat *catalog.Catalog, clob *polymarket.ClobClient, data *polymarket.DataClient, fills *polymarket.FillTracker, checks *pretrade.Chain, hooks *webhooks.Dispatcher, bus *eventbus.Publisher, auth *config.AuthConfig, cfg *config.RulesConfig) *Engine {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	e := &Engine{
+		ws:       ws,
+		catalog:  cat,
+		clob:     clob,
+		data:     data,
+		fills:    fills,
+		checks:   checks,
+		webhooks: hooks,
+		bus:      bus,
+		auth:     auth,
+		config:   cfg,
+		// Rules read no book fields, so books are not diffed
+		normalizer: &eventbus.Normalizer{},
+		prices:     newPriceHistory(),
+		rules:      make(map[string]*rule),
+		subs:       make(map[string]chan []byte),
+		queue:      make(chan firing, cfg.QueueSize),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+
+	specs, err := e.load()
+	if err != nil {
+		log.Printf("Failed to restore rules from %s: %v", cfg.Path, err)
+	}
+	if specs == nil {
+		specs = cfg.Rules
+	}
+	now := time.Now()
+	for _, spec := range specs {
+		r, err := e.compile(spec)
+		if err != nil {
+			log.Printf("Skipping rule %q: %v", spec.Name, err)
+			continue
+		}
+		r.CreatedAt, r.UpdatedAt = now, now
+		e.rules[r.Name] = r
+	}
+	e.activateLocked()
+	return e
+}
+
+// Start observes upstream frames, subscribes the configured markets and
+// launches the action worker
+func (e *Engine) Start() {
+	if !e.config.Enabled {
+		return
+	}
+
+	e.ws.Observe(e.Handle)
+	e.mu.Lock()
+	for _, market := range e.config.Markets {
+		ch, err := e.ws.SubscribeMarket(market)
+		if err != nil {
+			log.Printf("Rules failed to subscribe to market %s: %v", market, err)
+			continue
+		}
+		e.subs[market] = ch
+		go drain(ch)
+	}
+	e.mu.Unlock()
+
+	e.wg.Add(1)
+	go e.worker()
+}
+
+// Stop unsubscribes the configured markets and stops the worker; queued
+// triggers are abandoned
+func (e *Engine) Stop() {
+	e.mu.Lock()
+	for market, ch := range e.subs {
+		e.ws.UnsubscribeMarket(market, ch)
+		delete(e.subs, market)
+	}
+	e.mu.Unlock()
+
+	e.cancel()
+	e.wg.Wait()
+}
+
+// List returns every rule, by name
+func (e *Engine) List() []Rule {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	out := make([]Rule, 0, len(e.rules))
+	for _, r := range e.rules {
+		out = append(out, r.Rule)
+	}
+	sort.Slice(out, func(i, k int) bool { return out[i].Name < out[k].Name })
+	return out
+}
+
+// Get returns a rule
+func (e *Engine) Get(name string) (Rule, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	r, ok := e.rules[name]
+	if !ok {
+		return Rule{}, ErrNotFound
+	}
+	return r.Rule, nil
+}
+
+// Create adds a rule
+func (e *Engine) Create(spec config.RuleSpec) (Rule, error) {
+	r, err := e.compile(spec)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.rules[r.Name]; ok {
+		return Rule{}, ErrExists
+	}
+	if e.config.MaxRules > 0 && len(e.rules) >= e.config.MaxRules {
+		return Rule{}, ErrTooManyRules
+	}
+
+	now := time.Now()
+	r.CreatedAt, r.UpdatedAt = now, now
+	e.rules[r.Name] = r
+	e.activateLocked()
+	e.saveLocked()
+	return r.Rule, nil
+}
+
+// Update replaces a rule's spec. Its cooldowns restart; its counts and
+// triggers are kept.
+func (e *Engine) Update(name string, spec config.RuleSpec) (Rule, error) {
+	if spec.Name == "" {
+		spec.Name = name
+	}
+	if spec.Name != name {
+		return Rule{}, fmt.Errorf("%w: a rule cannot be renamed", ErrInvalidRule)
+	}
+	r, err := e.compile(spec)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	cur, ok := e.rules[name]
+	if !ok {
+		return Rule{}, ErrNotFound
+	}
+	r.CreatedAt, r.UpdatedAt = cur.CreatedAt, time.Now()
+	r.Fired, r.Dropped, r.LastFired = cur.Fired, cur.Dropped, cur.LastFired
+	r.triggers = cur.triggers
+	e.rules[name] = r
+	e.activateLocked()
+	e.saveLocked()
+	return r.Rule, nil
+}
+
+// Delete removes a rule and its triggers
+func (e *Engine) Delete(name string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.rules[name]; !ok {
+		return ErrNotFound
+	}
+	delete(e.rules, name)
+	e.activateLocked()
+	e.saveLocked()
+	return nil
+}
+
+// Triggers returns a rule's recent triggers, newest first
+func (e *Engine) Triggers(name string) ([]Trigger, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	r, ok := e.rules[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	out := make([]Trigger, len(r.triggers))
+	copy(out, r.triggers)
+	return out, nil
+}
+
+// Handle evaluates the active rules against the events of an upstream
+// frame
+func (e *Engine) Handle(channel polymarket.WSChannel, data []byte) {
+	if channel != polymarket.WSChannelMarket {
+		return
+	}
+	e.mu.RLock()
+	idle := len(e.active) == 0
+	e.mu.RUnlock()
+	if idle {
+		return
+	}
+	e.Evaluate(e.normalizer.Events(data), time.Now())
+}
+
+// Evaluate evaluates the active rules against events observed at now and
+// queues the triggers of those that match. When the queue is full,
+// triggers are dropped.
+func (e *Engine) Evaluate(events []eventbus.Event, now time.Time) {
+	e.mu.RLock()
+	active, window := e.active, e.window
+	e.mu.RUnlock()
+
+	for i := range events {
+		event := &events[i]
+		if window > 0 {
+			e.prices.observe(event, now, window)
+		}
+
+		env := &env{event: event, now: now, catalog: e.catalog, prices: e.prices}
+		for _, r := range active {
+			if !r.cond.match(env) || !r.cool(event.Market, now) {
+				continue
+			}
+
+			f := firing{rule: r, event: *event, trigger: Trigger{
+				ID:        idgen.WithPrefix("trg"),
+				Rule:      r.Name,
+				Time:      now,
+				EventType: event.Type,
+				Market:    event.Market,
+				AssetID:   event.AssetID,
+				Values:    r.cond.values(env),
+			}}
+			select {
+			case e.queue <- f:
+			default:
+				e.mu.Lock()
+				r.Dropped++
+				e.mu.Unlock()
+			}
+		}
+	}
+}
+
+// cool reports whether the rule may fire for a market at now, and if so
+// starts its cooldown
+func (r *rule) cool(market string, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if last, ok := r.fired[market]; ok && now.Sub(last) < r.cooldown {
+		return false
+	}
+	r.fired[market] = now
+	return true
+}
+
+// worker runs the actions of queued triggers and sweeps stale state
+func (e *Engine) worker() {
+	defer e.wg.Done()
+
+	sweep := time.NewTicker(time.Minute)
+	defer sweep.Stop()
+
+	for {
+		select {
+		case <-e.ctx.Done():
+			return
+		case f := <-e.queue:
+			f.trigger.Actions = e.run(f)
+			e.record(f.rule, f.trigger)
+		case now := <-sweep.C:
+			e.sweep(now)
+		}
+	}
+}
+
+// record counts a trigger and adds it to its rule's history. The rule may
+// have been updated or deleted since it fired.
+func (e *Engine) record(fired *rule, t Trigger) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	r, ok := e.rules[fired.Name]
+	if !ok {
+		return
+	}
+	r.Fired++
+	at := t.Time
+	r.LastFired = &at
+	r.triggers = append([]Trigger{t}, r.triggers...)
+	if e.config.History > 0 && len(r.triggers) > e.config.History {
+		r.triggers = r.triggers[:e.config.History]
+	}
+}
+
+// sweep forgets prices and cooldowns that no longer matter
+func (e *Engine) sweep(now time.Time) {
+	e.mu.RLock()
+	active, window := e.active, e.window
+	e.mu.RUnlock()
+
+	e.prices.sweep(now, window)
+	for _, r := range active {
+		r.mu.Lock()
+		for market, last := range r.fired {
+			if now.Sub(last) >= r.cooldown {
+				delete(r.fired, market)
+			}
+		}
+		r.mu.Unlock()
+	}
+}
+
+// activateLocked rebuilds the active rule list; caller holds e.mu
+func (e *Engine) activateLocked() {
+	active := make([]*rule, 0, len(e.rules))
+	var window time.Duration
+	for _, r := range e.rules {
+		if r.Disabled {
+			continue
+		}
+		active = append(active, r)
+		if r.cond.window > window {
+			window = r.cond.window
+		}
+	}
+	sort.Slice(active, func(i, k int) bool { return active[i].Name < active[k].Name })
+	e.active, e.window = active, window
+}
+
+// compile validates and normalizes a spec and compiles its condition
+func (e *Engine) compile(spec config.RuleSpec) (*rule, error) {
+	spec.Name = strings.TrimSpace(spec.Name)
+	spec.Condition = strings.TrimSpace(spec.Condition)
+	spec.Cooldown = strings.TrimSpace(spec.Cooldown)
+
+	if !namePattern.MatchString(spec.Name) {
+		return nil, fmt.Errorf("%w: name must be 1 to 64 letters, digits, '.', '_' or '-'", ErrInvalidRule)
+	}
+	if spec.Condition == "" {
+		return nil, fmt.Errorf("%w: condition is required", ErrInvalidRule)
+	}
+	cond, err := Compile(spec.Condition, e.config.MaxPriceWindow)
+	if err != nil {
+		return nil, fmt.Errorf("%w: condition: %v", ErrInvalidRule, err)
+	}
+
+	cooldown := e.config.Cooldown
+	if spec.Cooldown != "" {
+		cooldown, err = time.ParseDuration(spec.Cooldown)
+		if err != nil || cooldown < 0 {
+			return nil, fmt.Errorf("%w: cooldown must be a duration like 10m", ErrInvalidRule)
+		}
+	}
+
+	if len(spec.Actions) == 0 {
+		return nil, fmt.Errorf("%w: at least one action is required", ErrInvalidRule)
+	}
+	for i := range spec.Actions {
+		if err := e.validateAction(&spec.Actions[i]); err != nil {
+			return nil, fmt.Errorf("%w: action %d: %v", ErrInvalidRule, i+1, err)
+		}
+	}
+
+	return &rule{
+		Rule:     Rule{RuleSpec: spec, Fields: cond.Fields()},
+		cond:     cond,
+		cooldown: cooldown,
+		fired:    make(map[string]time.Time),
+	}, nil
+}
+
+// drain discards frames routed to a subscription held only to keep the
+// market subscribed upstream
+func drain(ch chan []byte) {
+	for range ch {
+	}
+}
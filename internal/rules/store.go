@@ -0,0 +1,67 @@
+package rules
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/fsutil"
+)
+
+// stored is the on-disk form of the rules
+type stored struct {
+	Rules []config.RuleSpec `json:"rules"`
+}
+
+// load returns the rules saved at Path, or nil when there is no file.
+// Saved rules replace the configured ones, since they are the latest
+// changes made through the admin API.
+func (e *Engine) load() ([]config.RuleSpec, error) {
+	if e.config.Path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(e.config.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var st stored
+	if err := sonic.Unmarshal(data, &st); err != nil {
+		return nil, err
+	}
+	if st.Rules == nil {
+		st.Rules = []config.RuleSpec{}
+	}
+	return st.Rules, nil
+}
+
+// saveLocked writes every rule to Path, replacing the file atomically.
+// Failures are logged; the in-memory rules stay authoritative. Caller holds
+// e.mu.
+func (e *Engine) saveLocked() {
+	if e.config.Path == "" {
+		return
+	}
+
+	st := stored{Rules: make([]config.RuleSpec, 0, len(e.rules))}
+	for _, r := range e.rules {
+		st.Rules = append(st.Rules, r.RuleSpec)
+	}
+	sort.Slice(st.Rules, func(i, k int) bool { return st.Rules[i].Name < st.Rules[k].Name })
+
+	data, err := sonic.Marshal(st)
+	if err == nil {
+		err = fsutil.WriteFileAtomic(e.config.Path, data, 0o600)
+	}
+	if err != nil {
+		log.Printf("Failed to save rules to %s: %v", e.config.Path, err)
+	}
+}
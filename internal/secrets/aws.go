@@ -0,0 +1,157 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// aws reads <secret-id>[#<key>] from Secrets Manager. The secret id may be
+// a name or an ARN; with a key, the secret must hold a JSON object.
+func (r *Resolver) aws(ctx context.Context, ref string) (string, error) {
+	id, key := ref, ""
+	if i := strings.LastIndex(ref, "#"); i >= 0 {
+		id, key = ref[:i], ref[i+1:]
+	}
+	if id == "" {
+		return "", fmt.Errorf("%w: use aws-sm://<secret-id>[#<key>]", ErrInvalidReference)
+	}
+	region := r.config.AWSRegion
+	if strings.HasPrefix(id, "arn:") {
+		// arn:aws:secretsmanager:<region>:<account>:secret:<name>
+		if parts := strings.Split(id, ":"); len(parts) > 3 && parts[3] != "" {
+			region = parts[3]
+		}
+	}
+	if region == "" {
+		return "", fmt.Errorf("aws: secrets.aws_region is not set")
+	}
+	if r.config.AWSAccessKeyID == "" || r.config.AWSSecretAccessKey == "" {
+		return "", fmt.Errorf("aws: secrets.aws_access_key_id and aws_secret_access_key are required")
+	}
+
+	endpoint := r.config.AWSEndpoint
+	if endpoint == "" {
+		endpoint = "https://secretsmanager." + region + ".amazonaws.com"
+	}
+	body, _ := sonic.Marshal(map[string]string{"SecretId": id})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(endpoint, "/")+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("aws: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	r.signV4(req, body, region, time.Now().UTC())
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("aws: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("aws: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Type string `json:"__type"`
+		}
+		_ = sonic.Unmarshal(respBody, &e)
+		return "", fmt.Errorf("aws: %s returned %d %s", id, resp.StatusCode, e.Type)
+	}
+
+	var secret struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := sonic.Unmarshal(respBody, &secret); err != nil {
+		return "", fmt.Errorf("aws: %s: %w", id, err)
+	}
+	if key == "" {
+		return secret.SecretString, nil
+	}
+
+	var fields map[string]interface{}
+	if err := sonic.UnmarshalString(secret.SecretString, &fields); err != nil {
+		return "", fmt.Errorf("aws: %s does not hold a JSON object", id)
+	}
+	value, ok := fields[key].(string)
+	if !ok {
+		return "", fmt.Errorf("aws: %s has no string key %s", id, key)
+	}
+	return value, nil
+}
+
+// signV4 signs a Secrets Manager request with AWS Signature Version 4
+func (r *Resolver) signV4(req *http.Request, body []byte, region string, now time.Time) {
+	const service = "secretsmanager"
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if r.config.AWSSessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", r.config.AWSSessionToken)
+	}
+
+	headers := map[string]string{
+		"content-type": req.Header.Get("Content-Type"),
+		"host":         req.URL.Host,
+		"x-amz-date":   amzDate,
+		"x-amz-target": req.Header.Get("X-Amz-Target"),
+	}
+	names := []string{"content-type", "host", "x-amz-date"}
+	if r.config.AWSSessionToken != "" {
+		headers["x-amz-security-token"] = r.config.AWSSessionToken
+		names = append(names, "x-amz-security-token")
+	}
+	names = append(names, "x-amz-target")
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+r.config.AWSSecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		r.config.AWSAccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
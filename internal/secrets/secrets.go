@@ -0,0 +1,227 @@
+// Package secrets resolves settings given as references to a secret store
+// instead of plaintext: vault://<path>#<key> reads a key of a HashiCorp
+// Vault secret, aws-sm://<secret-id>[#<key>] an AWS Secrets Manager secret
+// (or a key of it, when it holds JSON). References are resolved once at
+// startup and may be watched to notice rotations.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polygo/internal/config"
+)
+
+// Reference schemes
+const (
+	SchemeVault = "vault://"
+	SchemeAWS   = "aws-sm://"
+)
+
+// ErrInvalidReference is returned for a reference that names no secret
+var ErrInvalidReference = errors.New("invalid secret reference")
+
+// IsReference reports whether a setting is a secret reference
+func IsReference(s string) bool {
+	return strings.HasPrefix(s, SchemeVault) || strings.HasPrefix(s, SchemeAWS)
+}
+
+// Ref is a setting that was given as a reference
+type Ref struct {
+	Key       string // the setting, e.g. admin.token or rules.accounts.desk-a.secret
+	Reference string
+	value     string // as last resolved
+}
+
+// Resolver reads secrets from Vault and Secrets Manager
+type Resolver struct {
+	config *config.SecretsConfig
+	client *http.Client
+}
+
+// NewResolver creates a resolver
+func NewResolver(cfg *config.SecretsConfig) *Resolver {
+	return &Resolver{config: cfg, client: &http.Client{Timeout: cfg.Timeout}}
+}
+
+// Resolve reads the secret a reference names
+func (r *Resolver) Resolve(ctx context.Context, reference string) (string, error) {
+	switch {
+	case strings.HasPrefix(reference, SchemeVault):
+		return r.vault(ctx, strings.TrimPrefix(reference, SchemeVault))
+	case strings.HasPrefix(reference, SchemeAWS):
+		return r.aws(ctx, strings.TrimPrefix(reference, SchemeAWS))
+	}
+	return "", ErrInvalidReference
+}
+
+// Resolve replaces every setting of cfg given as a reference with the
+// secret it names, and returns those settings for Watch. The secrets
+// section itself is left alone. An error names the setting, never a value.
+func Resolve(cfg *config.Config) ([]*Ref, error) {
+	var refs []*Ref
+	walk(reflect.ValueOf(cfg).Elem(), "", func(key string, v reflect.Value) {
+		if key != "secrets" && !strings.HasPrefix(key, "secrets.") && IsReference(v.String()) {
+			refs = append(refs, &Ref{Key: key, Reference: v.String()})
+		}
+	})
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	r := NewResolver(&cfg.Secrets)
+	resolved := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		value, ok := resolved[ref.Reference]
+		if !ok {
+			var err error
+			if value, err = r.Resolve(context.Background(), ref.Reference); err != nil {
+				return nil, fmt.Errorf("%s: %w", ref.Key, err)
+			}
+			resolved[ref.Reference] = value
+		}
+		ref.value = value
+	}
+
+	walk(reflect.ValueOf(cfg).Elem(), "", func(key string, v reflect.Value) {
+		if value, ok := resolved[v.String()]; ok && key != "secrets" && !strings.HasPrefix(key, "secrets.") {
+			v.SetString(value)
+		}
+	})
+	return refs, nil
+}
+
+// walk calls fn with every settable string in v and its dotted key, by
+// mapstructure tag. Map values are copied out, walked and stored back.
+func walk(v reflect.Value, key string, fn func(key string, v reflect.Value)) {
+	join := func(name string) string {
+		if key == "" {
+			return name
+		}
+		return key + "." + name
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if v.CanSet() {
+			fn(key, v)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("mapstructure"), ",")
+			if name == "" {
+				name = strings.ToLower(t.Field(i).Name)
+			}
+			walk(v.Field(i), join(name), fn)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walk(v.Index(i), join(strconv.Itoa(i)), fn)
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(k))
+			walk(elem, join(fmt.Sprint(k.Interface())), fn)
+			v.SetMapIndex(k, elem)
+		}
+	case reflect.Pointer:
+		if !v.IsNil() {
+			walk(v.Elem(), key, fn)
+		}
+	}
+}
+
+// Watcher reads references again every RefreshInterval and reports the
+// settings whose secret changed
+type Watcher struct {
+	resolver *Resolver
+	interval time.Duration
+	refs     []*Ref
+	onRotate func(keys []string)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Watch starts watching refs, as returned by Resolve. onRotate is called
+// once with the keys of the settings whose secret changed; the settings
+// themselves are not updated, since they were read at startup. Nothing is
+// watched without references or a refresh interval.
+func Watch(cfg *config.SecretsConfig, refs []*Ref, onRotate func(keys []string)) *Watcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &Watcher{
+		resolver: NewResolver(cfg),
+		interval: cfg.RefreshInterval,
+		refs:     refs,
+		onRotate: onRotate,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	if len(refs) == 0 || cfg.RefreshInterval <= 0 {
+		return w
+	}
+
+	w.wg.Add(1)
+	go w.run()
+	return w
+}
+
+// Stop stops watching
+func (w *Watcher) Stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+func (w *Watcher) run() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			if rotated := w.check(); len(rotated) > 0 {
+				w.onRotate(rotated)
+				return
+			}
+		}
+	}
+}
+
+// check reads every reference again and returns the keys whose secret
+// changed. Failed reads are logged and count as unchanged.
+func (w *Watcher) check() []string {
+	var rotated []string
+	for _, ref := range w.refs {
+		value, err := w.resolver.Resolve(w.ctx, ref.Reference)
+		if err != nil {
+			if w.ctx.Err() == nil {
+				log.Printf("Failed to read secret for %s: %v", ref.Key, err)
+			}
+			continue
+		}
+		if value != ref.value {
+			rotated = append(rotated, ref.Key)
+		}
+	}
+	sort.Strings(rotated)
+	return rotated
+}
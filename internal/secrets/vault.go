@@ -0,0 +1,67 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/bytedance/sonic"
+)
+
+// vault reads <path>#<key> from Vault: path is the API path after /v1/,
+// e.g. secret/data/polygo for the KV v2 engine mounted at secret/
+func (r *Resolver) vault(ctx context.Context, ref string) (string, error) {
+	path, key, _ := strings.Cut(ref, "#")
+	path = strings.Trim(path, "/")
+	if path == "" || key == "" {
+		return "", fmt.Errorf("%w: use vault://<path>#<key>", ErrInvalidReference)
+	}
+	if r.config.VaultAddr == "" {
+		return "", fmt.Errorf("vault: secrets.vault_addr is not set")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(r.config.VaultAddr, "/")+"/v1/"+path, nil)
+	if err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", r.config.VaultToken)
+	if r.config.VaultNamespace != "" {
+		req.Header.Set("X-Vault-Namespace", r.config.VaultNamespace)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault: %s returned %d", path, resp.StatusCode)
+	}
+
+	// KV v2 nests the secret in data.data, KV v1 and other engines in data
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := sonic.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("vault: %s: %w", path, err)
+	}
+	data := secret.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, isMeta := data["metadata"]; isMeta {
+			data = nested
+		}
+	}
+
+	value, ok := data[key].(string)
+	if !ok {
+		return "", fmt.Errorf("vault: %s has no string key %s", path, key)
+	}
+	return value, nil
+}
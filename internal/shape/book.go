@@ -0,0 +1,173 @@
+package shape
+
+import "time"
+
+// OrderBook is a token's book with each side best price first
+type OrderBook struct {
+	TokenID            string     `json:"token_id"`
+	ConditionID        string     `json:"condition_id"`
+	Bids               []Level    `json:"bids"`
+	Asks               []Level    `json:"asks"`
+	LastTradePrice     *float64   `json:"last_trade_price"`
+	ImpliedProbability *float64   `json:"implied_probability"`
+	TickSize           float64    `json:"tick_size,omitempty"`
+	MinOrderSize       float64    `json:"min_order_size,omitempty"`
+	Hash               string     `json:"hash,omitempty"`
+	Timestamp          *time.Time `json:"timestamp"`
+}
+
+// Level is a price level of a book
+type Level struct {
+	Price float64 `json:"price"`
+	Size  float64 `json:"size"`
+}
+
+// Price is the price to trade a token on one side
+type Price struct {
+	TokenID string   `json:"token_id"`
+	Side    string   `json:"side"`
+	Price   *float64 `json:"price"`
+}
+
+// Midpoint is the middle of a token's best bid and ask
+type Midpoint struct {
+	TokenID            string   `json:"token_id"`
+	Mid                *float64 `json:"mid"`
+	ImpliedProbability *float64 `json:"implied_probability,omitempty"`
+}
+
+// Spread is the gap between a token's best bid and ask
+type Spread struct {
+	TokenID string   `json:"token_id"`
+	Spread  *float64 `json:"spread"`
+}
+
+// LastTrade is the price and side of a token's last trade
+type LastTrade struct {
+	TokenID string   `json:"token_id"`
+	Price   *float64 `json:"price"`
+	Side    string   `json:"side,omitempty"`
+}
+
+// PricePoint is a sample of a token's price history
+type PricePoint struct {
+	Time  time.Time `json:"time"`
+	Price float64   `json:"price"`
+}
+
+var (
+	// Book shapes an order book
+	Book = one(parseBook)
+	// Books shapes a list of order books
+	Books = many(parseBook)
+)
+
+func parseBook(o object) OrderBook {
+	return OrderBook{
+		TokenID:            o.str("asset_id", "token_id"),
+		ConditionID:        o.str("market", "condition_id"),
+		Bids:               parseLevels(o.list("bids")),
+		Asks:               parseLevels(o.list("asks")),
+		LastTradePrice:     o.optional("last_trade_price"),
+		ImpliedProbability: o.optional("implied_probability"),
+		TickSize:           o.float("tick_size"),
+		MinOrderSize:       o.float("min_order_size"),
+		Hash:               o.str("hash"),
+		Timestamp:          o.time("timestamp"),
+	}
+}
+
+// parseLevels reads a side of a book. The CLOB lists levels from the worst
+// price to the best, so they are reversed.
+func parseLevels(list []interface{}) []Level {
+	levels := parseAll(list, func(o object) Level {
+		return Level{Price: o.float("price"), Size: o.float("size")}
+	})
+	for i, j := 0, len(levels)-1; i < j; i, j = i+1, j-1 {
+		levels[i], levels[j] = levels[j], levels[i]
+	}
+	return levels
+}
+
+// TokenPrice shapes the price of a token
+func TokenPrice(tokenID, side string) Shaper {
+	return one(func(o object) Price {
+		return Price{TokenID: tokenID, Side: side, Price: o.optional("price")}
+	})
+}
+
+// TokenMidpoint shapes the midpoint of a token
+func TokenMidpoint(tokenID string) Shaper {
+	return one(func(o object) Midpoint {
+		return Midpoint{TokenID: tokenID, Mid: o.optional("mid"), ImpliedProbability: o.optional("implied_probability")}
+	})
+}
+
+// TokenSpread shapes the spread of a token
+func TokenSpread(tokenID string) Shaper {
+	return one(func(o object) Spread {
+		return Spread{TokenID: tokenID, Spread: o.optional("spread")}
+	})
+}
+
+// TokenLastTrade shapes the last trade of a token
+func TokenLastTrade(tokenID string) Shaper {
+	return one(func(o object) LastTrade {
+		return LastTrade{TokenID: tokenID, Price: o.optional("price"), Side: o.str("side")}
+	})
+}
+
+// Prices shapes the token -> side -> price map of /prices into a list
+// ordered by token and side
+func Prices(data []byte) (interface{}, error) {
+	v, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	byToken := asObject(v)
+	out := make([]Price, 0, len(byToken))
+	for _, tokenID := range sortedKeys(byToken) {
+		bySide := byToken.obj(tokenID)
+		for _, side := range sortedKeys(bySide) {
+			out = append(out, Price{TokenID: tokenID, Side: side, Price: bySide.optional(side)})
+		}
+	}
+	return out, nil
+}
+
+// Midpoints shapes the token -> midpoint map of /midpoints into a list
+// ordered by token
+func Midpoints(data []byte) (interface{}, error) {
+	v, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	byToken := asObject(v)
+	out := make([]Midpoint, 0, len(byToken))
+	for _, tokenID := range sortedKeys(byToken) {
+		out = append(out, Midpoint{TokenID: tokenID, Mid: byToken.optional(tokenID)})
+	}
+	return out, nil
+}
+
+// PriceHistory shapes the {history: [{t, p}]} samples of a token
+func PriceHistory(data []byte) (interface{}, error) {
+	v, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	list := asObject(v).list("history")
+	if list == nil {
+		list = items(v)
+	}
+	out := make([]PricePoint, 0, len(list))
+	for _, item := range list {
+		o := asObject(item)
+		t, ok := parseTime(o["t"])
+		if !ok {
+			continue
+		}
+		out = append(out, PricePoint{Time: t, Price: o.float("p", "price")})
+	}
+	return out, nil
+}
@@ -0,0 +1,220 @@
+package shape
+
+import (
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/polymarket"
+)
+
+// Market is a Gamma market with its outcomes, token IDs and prices zipped
+// together
+type Market struct {
+	ID              string     `json:"id"`
+	ConditionID     string     `json:"condition_id"`
+	Slug            string     `json:"slug"`
+	Question        string     `json:"question"`
+	Description     string     `json:"description,omitempty"`
+	Outcomes        []Outcome  `json:"outcomes"`
+	Active          bool       `json:"active"`
+	Closed          bool       `json:"closed"`
+	AcceptingOrders bool       `json:"accepting_orders"`
+	NegRisk         bool       `json:"neg_risk"`
+	Volume          float64    `json:"volume"`
+	Volume24h       float64    `json:"volume_24h"`
+	Liquidity       float64    `json:"liquidity"`
+	BestBid         *float64   `json:"best_bid"`
+	BestAsk         *float64   `json:"best_ask"`
+	Spread          *float64   `json:"spread"`
+	LastTradePrice  *float64   `json:"last_trade_price"`
+	TickSize        float64    `json:"tick_size,omitempty"`
+	MinOrderSize    float64    `json:"min_order_size,omitempty"`
+	EndDate         *time.Time `json:"end_date"`
+	// Set for markets served from the local catalog
+	EventID    string     `json:"event_id,omitempty"`
+	EventSlug  string     `json:"event_slug,omitempty"`
+	EventTitle string     `json:"event_title,omitempty"`
+	Category   string     `json:"category,omitempty"`
+	Tags       []Tag      `json:"tags,omitempty"`
+	FirstSeen  *time.Time `json:"first_seen,omitempty"`
+}
+
+// Outcome is one side of a market and the CLOB token trading it
+type Outcome struct {
+	Name    string   `json:"name"`
+	TokenID string   `json:"token_id"`
+	Price   *float64 `json:"price"`
+}
+
+// Tag labels an event
+type Tag struct {
+	ID    string `json:"id"`
+	Slug  string `json:"slug"`
+	Label string `json:"label"`
+}
+
+// Event groups related markets
+type Event struct {
+	ID          string     `json:"id"`
+	Slug        string     `json:"slug"`
+	Ticker      string     `json:"ticker,omitempty"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Active      bool       `json:"active"`
+	Closed      bool       `json:"closed"`
+	Archived    bool       `json:"archived"`
+	Volume      float64    `json:"volume"`
+	Volume24h   float64    `json:"volume_24h"`
+	Liquidity   float64    `json:"liquidity"`
+	StartDate   *time.Time `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
+	Tags        []Tag      `json:"tags"`
+	Markets     []Market   `json:"markets"`
+}
+
+// RankedMarket is a catalog market with its 24h trade count
+type RankedMarket struct {
+	Market
+	Trades24h int `json:"trades_24h"`
+}
+
+// ClosingMarket is a catalog market with the time left before it ends
+type ClosingMarket struct {
+	Market
+	SecondsRemaining int64 `json:"seconds_remaining"`
+}
+
+// MarketDetail is the composite market view of /markets/:id/full
+type MarketDetail struct {
+	Market       Market                       `json:"market"`
+	Tokens       []polymarket.OutcomeSnapshot `json:"tokens"`
+	Volume24h    float64                      `json:"volume_24h"`
+	RecentTrades []Trade                      `json:"recent_trades"`
+	Errors       map[string]string            `json:"errors,omitempty"`
+}
+
+var (
+	// MarketOne shapes a market, or the first of a lookup's results
+	MarketOne = one(parseMarket)
+	// Markets shapes a list of markets
+	Markets = many(parseMarket)
+	// RankedMarkets shapes analytics rankings
+	RankedMarkets = many(parseRankedMarket)
+	// ClosingMarkets shapes markets approaching their end date
+	ClosingMarkets = many(parseClosingMarket)
+	// EventOne shapes an event, or the first of a lookup's results
+	EventOne = one(parseEvent)
+	// Events shapes a list of events
+	Events = many(parseEvent)
+)
+
+func parseMarket(o object) Market {
+	m := Market{
+		ID:              o.str("id"),
+		ConditionID:     o.str("conditionId", "condition_id"),
+		Slug:            o.str("slug", "market_slug"),
+		Question:        o.str("question"),
+		Description:     o.str("description"),
+		Active:          o.boolean("active"),
+		Closed:          o.boolean("closed"),
+		AcceptingOrders: o.boolean("acceptingOrders", "accepting_orders"),
+		NegRisk:         o.boolean("negRisk", "neg_risk"),
+		Volume:          o.float("volumeNum", "volume"),
+		Volume24h:       o.float("volume24hr", "volume_24hr"),
+		Liquidity:       o.float("liquidityNum", "liquidity"),
+		BestBid:         o.optional("bestBid", "best_bid"),
+		BestAsk:         o.optional("bestAsk", "best_ask"),
+		Spread:          o.optional("spread"),
+		LastTradePrice:  o.optional("lastTradePrice", "last_trade_price"),
+		TickSize:        o.float("orderPriceMinTickSize", "minimum_tick_size"),
+		MinOrderSize:    o.float("orderMinSize", "minimum_order_size"),
+		EndDate:         o.time("endDate", "end_date_iso", "endDateIso"),
+		EventID:         o.str("eventId", "event_id"),
+		EventSlug:       o.str("eventSlug", "event_slug"),
+		EventTitle:      o.str("eventTitle", "event_title"),
+		Category:        o.str("category"),
+		Tags:            parseTags(o.list("tags")),
+		FirstSeen:       o.time("firstSeen", "first_seen"),
+	}
+
+	names := o.list("outcomes")
+	tokens := o.list("clobTokenIds", "clob_token_ids")
+	prices := o.list("outcomePrices", "outcome_prices")
+	for i, name := range names {
+		out := Outcome{Name: text(name)}
+		if i < len(tokens) {
+			out.TokenID = text(tokens[i])
+		}
+		if i < len(prices) {
+			if p, ok := number(prices[i]); ok {
+				out.Price = &p
+			}
+		}
+		m.Outcomes = append(m.Outcomes, out)
+	}
+	// CLOB markets carry a tokens list instead
+	if len(names) == 0 {
+		m.Outcomes = parseAll(o.list("tokens"), func(t object) Outcome {
+			return Outcome{Name: t.str("outcome"), TokenID: t.str("token_id"), Price: t.optional("price")}
+		})
+	}
+	if m.Outcomes == nil {
+		m.Outcomes = []Outcome{}
+	}
+	return m
+}
+
+func parseRankedMarket(o object) RankedMarket {
+	return RankedMarket{Market: parseMarket(o), Trades24h: int(o.float("trades24h"))}
+}
+
+func parseClosingMarket(o object) ClosingMarket {
+	return ClosingMarket{Market: parseMarket(o), SecondsRemaining: int64(o.float("secondsRemaining"))}
+}
+
+func parseTags(list []interface{}) []Tag {
+	return parseAll(list, func(o object) Tag {
+		return Tag{ID: o.str("id"), Slug: o.str("slug"), Label: o.str("label", "name")}
+	})
+}
+
+func parseEvent(o object) Event {
+	return Event{
+		ID:          o.str("id"),
+		Slug:        o.str("slug"),
+		Ticker:      o.str("ticker"),
+		Title:       o.str("title"),
+		Description: o.str("description"),
+		Active:      o.boolean("active"),
+		Closed:      o.boolean("closed"),
+		Archived:    o.boolean("archived"),
+		Volume:      o.float("volume"),
+		Volume24h:   o.float("volume24hr", "volume_24hr"),
+		Liquidity:   o.float("liquidity"),
+		StartDate:   o.time("startDate", "start_date"),
+		EndDate:     o.time("endDate", "end_date"),
+		Tags:        parseTags(o.list("tags")),
+		Markets:     parseAll(o.list("markets"), parseMarket),
+	}
+}
+
+// Detail shapes a polymarket.MarketDetail
+func Detail(data []byte) (interface{}, error) {
+	var detail polymarket.MarketDetail
+	if err := sonic.Unmarshal(data, &detail); err != nil {
+		return nil, err
+	}
+	out := MarketDetail{
+		Tokens:    detail.Tokens,
+		Volume24h: detail.Volume24hr,
+		Errors:    detail.Errors,
+	}
+	if v, err := decode(detail.Market); err == nil {
+		out.Market = parseMarket(asObject(v))
+	}
+	out.RecentTrades = []Trade{}
+	if v, err := decode(detail.RecentTrades); err == nil {
+		out.RecentTrades = parseAll(items(v), parseTrade)
+	}
+	return out, nil
+}
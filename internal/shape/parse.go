@@ -0,0 +1,183 @@
+package shape
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// object is a decoded upstream JSON object. Its accessors take several
+// keys, as the APIs name the same field differently (conditionId,
+// condition_id, market), and return the first one present.
+type object map[string]interface{}
+
+// decode parses an upstream payload
+func decode(data []byte) (interface{}, error) {
+	var v interface{}
+	if err := sonic.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// items returns the elements of a list payload: a bare array or a page
+// wrapping one in data
+func items(v interface{}) []interface{} {
+	switch t := v.(type) {
+	case []interface{}:
+		return t
+	case map[string]interface{}:
+		list, _ := t["data"].([]interface{})
+		return list
+	}
+	return nil
+}
+
+func asObject(v interface{}) object {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func (o object) obj(key string) object {
+	return asObject(o[key])
+}
+
+// str returns the first non-empty string among keys; numbers are formatted
+func (o object) str(keys ...string) string {
+	for _, k := range keys {
+		if s := text(o[k]); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// num returns the first number, or numeric string, among keys
+func (o object) num(keys ...string) (float64, bool) {
+	for _, k := range keys {
+		if f, ok := number(o[k]); ok {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+// float is num with 0 for a missing value
+func (o object) float(keys ...string) float64 {
+	f, _ := o.num(keys...)
+	return f
+}
+
+// optional is num with nil for a missing value
+func (o object) optional(keys ...string) *float64 {
+	if f, ok := o.num(keys...); ok {
+		return &f
+	}
+	return nil
+}
+
+// boolean returns the first boolean among keys, accepting "true"/"false"
+func (o object) boolean(keys ...string) bool {
+	for _, k := range keys {
+		switch v := o[k].(type) {
+		case bool:
+			return v
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b
+			}
+		}
+	}
+	return false
+}
+
+// time returns the first parseable time among keys
+func (o object) time(keys ...string) *time.Time {
+	for _, k := range keys {
+		if t, ok := parseTime(o[k]); ok {
+			return &t
+		}
+	}
+	return nil
+}
+
+// list returns an array field, decoding arrays Gamma sends as JSON strings
+func (o object) list(keys ...string) []interface{} {
+	for _, k := range keys {
+		switch v := o[k].(type) {
+		case []interface{}:
+			return v
+		case string:
+			if !strings.HasPrefix(strings.TrimSpace(v), "[") {
+				continue
+			}
+			var list []interface{}
+			if err := sonic.UnmarshalString(v, &list); err == nil {
+				return list
+			}
+		}
+	}
+	return nil
+}
+
+// text returns a string value, formatting numbers
+func text(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	}
+	return ""
+}
+
+// number parses a number sent as a JSON number or a numeric string
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, false
+		}
+		return f, true
+	}
+	return 0, false
+}
+
+// timeLayouts are the string time formats the upstream APIs use
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02 15:04:05Z07",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// parseTime reads a timestamp sent as a date string or as unix seconds or
+// milliseconds, in a number or a string
+func parseTime(v interface{}) (time.Time, bool) {
+	if s, ok := v.(string); ok {
+		s = strings.TrimSpace(s)
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				// Go values marshal an unset time as year 1
+				return t.UTC(), !t.IsZero()
+			}
+		}
+	}
+	n, ok := number(v)
+	if !ok || n <= 0 {
+		return time.Time{}, false
+	}
+	// Seconds stay below 1e12 until the year 33658
+	if n >= 1e12 {
+		return time.UnixMilli(int64(n)).UTC(), true
+	}
+	return time.Unix(int64(n), 0).UTC(), true
+}
@@ -0,0 +1,79 @@
+// Package shape parses upstream payloads into the typed models served on
+// /api/v2. Every model uses snake_case keys, numbers for prices, sizes and
+// amounts, RFC 3339 times and the same name for the same field whichever
+// API it came from: token_id, condition_id, address.
+//
+// Parsing is lenient. Missing fields are left at their zero value (or nil
+// for optional prices and times) rather than failing the response.
+package shape
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrNotFound is returned for a lookup that matched nothing, such as a
+// slug query answered with an empty list
+var ErrNotFound = errors.New("not found")
+
+// Shaper turns an upstream payload into its /api/v2 model
+type Shaper func(data []byte) (interface{}, error)
+
+// one shapes a single object. A list holding it, as slug and token
+// lookups return, is unwrapped.
+func one[T any](parse func(object) T) Shaper {
+	return func(data []byte) (interface{}, error) {
+		v, err := decode(data)
+		if err != nil {
+			return nil, err
+		}
+		if list, ok := v.([]interface{}); ok {
+			if len(list) == 0 {
+				return nil, ErrNotFound
+			}
+			v = list[0]
+		}
+		o := asObject(v)
+		if o == nil {
+			return nil, ErrNotFound
+		}
+		return parse(o), nil
+	}
+}
+
+// many shapes a list, skipping elements that are not objects
+func many[T any](parse func(object) T) Shaper {
+	return func(data []byte) (interface{}, error) {
+		v, err := decode(data)
+		if err != nil {
+			return nil, err
+		}
+		return parseAll(items(v), parse), nil
+	}
+}
+
+func parseAll[T any](list []interface{}, parse func(object) T) []T {
+	out := make([]T, 0, len(list))
+	for _, item := range list {
+		if o := asObject(item); o != nil {
+			out = append(out, parse(o))
+		}
+	}
+	return out
+}
+
+// Any passes a payload through decoded, for endpoints with no fixed schema
+func Any(data []byte) (interface{}, error) {
+	return decode(data)
+}
+
+// sortedKeys returns a map's keys in order, so lists built from maps are
+// stable
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,270 @@
+package shape
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/leaderboard"
+	"github.com/polygo/internal/polymarket"
+)
+
+// Order is a resting or finished CLOB order
+type Order struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	ConditionID string     `json:"condition_id"`
+	TokenID     string     `json:"token_id"`
+	Outcome     string     `json:"outcome,omitempty"`
+	Side        string     `json:"side"`
+	Type        string     `json:"type"`
+	Price       float64    `json:"price"`
+	Size        float64    `json:"size"`
+	SizeMatched float64    `json:"size_matched"`
+	Address     string     `json:"address,omitempty"`
+	CreatedAt   *time.Time `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+// OrderResult is the outcome of placing an order
+type OrderResult struct {
+	OrderID string `json:"order_id"`
+	Status  string `json:"status"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+	// Set for orders priced with a price_mode
+	PriceMode     string   `json:"price_mode,omitempty"`
+	ResolvedPrice *float64 `json:"resolved_price,omitempty"`
+}
+
+// CancelResult lists the orders a cancel request removed and those it could
+// not
+type CancelResult struct {
+	Cancelled    []string        `json:"cancelled"`
+	NotCancelled []CancelFailure `json:"not_cancelled"`
+}
+
+// CancelFailure is an order a cancel request left in place
+type CancelFailure struct {
+	OrderID string `json:"order_id"`
+	Reason  string `json:"reason"`
+}
+
+// Trade is a fill, from the CLOB or the Data API
+type Trade struct {
+	ID              string     `json:"id,omitempty"`
+	TokenID         string     `json:"token_id"`
+	ConditionID     string     `json:"condition_id"`
+	Outcome         string     `json:"outcome,omitempty"`
+	Side            string     `json:"side"`
+	Price           float64    `json:"price"`
+	Size            float64    `json:"size"`
+	Status          string     `json:"status,omitempty"`
+	Address         string     `json:"address,omitempty"`
+	Title           string     `json:"title,omitempty"`
+	TransactionHash string     `json:"transaction_hash,omitempty"`
+	Time            *time.Time `json:"time"`
+	// Confirmed is set when on-chain verification was asked for
+	Confirmed *bool `json:"confirmed,omitempty"`
+}
+
+// Position is a wallet's holding of one outcome token
+type Position struct {
+	Address      string     `json:"address"`
+	TokenID      string     `json:"token_id"`
+	ConditionID  string     `json:"condition_id"`
+	Outcome      string     `json:"outcome"`
+	Title        string     `json:"title,omitempty"`
+	Slug         string     `json:"slug,omitempty"`
+	Size         float64    `json:"size"`
+	AvgPrice     float64    `json:"avg_price"`
+	CurrentPrice *float64   `json:"current_price"`
+	InitialValue float64    `json:"initial_value"`
+	CurrentValue float64    `json:"current_value"`
+	CashPnL      float64    `json:"cash_pnl"`
+	PercentPnL   float64    `json:"percent_pnl"`
+	RealizedPnL  float64    `json:"realized_pnl"`
+	Redeemable   bool       `json:"redeemable"`
+	EndDate      *time.Time `json:"end_date"`
+}
+
+// Activity is an entry of a wallet's on-chain activity: trades, splits,
+// merges, redemptions and rewards
+type Activity struct {
+	Type            string     `json:"type"`
+	Address         string     `json:"address"`
+	TokenID         string     `json:"token_id,omitempty"`
+	ConditionID     string     `json:"condition_id,omitempty"`
+	Outcome         string     `json:"outcome,omitempty"`
+	Title           string     `json:"title,omitempty"`
+	Side            string     `json:"side,omitempty"`
+	Price           *float64   `json:"price"`
+	Size            float64    `json:"size"`
+	USDCSize        float64    `json:"usdc_size"`
+	TransactionHash string     `json:"transaction_hash,omitempty"`
+	Time            *time.Time `json:"time"`
+}
+
+// TraderProfile is the composite wallet view of /trader/:address/profile
+type TraderProfile struct {
+	Address      string                 `json:"address"`
+	Stats        polymarket.TraderStats `json:"stats"`
+	Positions    []Position             `json:"positions"`
+	RecentTrades []Trade                `json:"recent_trades"`
+	Activity     []Activity             `json:"activity"`
+	Errors       map[string]string      `json:"errors,omitempty"`
+}
+
+var (
+	// OrderOne shapes an order
+	OrderOne = one(parseOrder)
+	// Orders shapes a list or page of orders
+	Orders = many(parseOrder)
+	// Placed shapes the result of placing an order
+	Placed = one(parseOrderResult)
+	// PlacedAll shapes the results of a batch of orders
+	PlacedAll = many(parseOrderResult)
+	// Trades shapes a list or page of trades
+	Trades = many(parseTrade)
+	// Positions shapes a list or page of positions
+	Positions = many(parsePosition)
+	// Activities shapes a list or page of activity
+	Activities = many(parseActivity)
+)
+
+func parseOrder(o object) Order {
+	return Order{
+		ID:          o.str("id", "orderID", "order_id"),
+		Status:      strings.ToUpper(o.str("status")),
+		ConditionID: o.str("market", "condition_id"),
+		TokenID:     o.str("asset_id", "token_id"),
+		Outcome:     o.str("outcome"),
+		Side:        strings.ToUpper(o.str("side")),
+		Type:        strings.ToUpper(o.str("order_type", "type")),
+		Price:       o.float("price"),
+		Size:        o.float("original_size", "size"),
+		SizeMatched: o.float("size_matched"),
+		Address:     strings.ToLower(o.str("maker_address", "owner")),
+		CreatedAt:   o.time("created_at"),
+		ExpiresAt:   o.time("expiration"),
+	}
+}
+
+func parseOrderResult(o object) OrderResult {
+	return OrderResult{
+		OrderID:       o.str("orderID", "orderId", "order_id"),
+		Status:        strings.ToUpper(o.str("status")),
+		Success:       o.boolean("success"),
+		Error:         o.str("errorMsg", "error"),
+		PriceMode:     o.str("price_mode"),
+		ResolvedPrice: o.optional("resolved_price"),
+	}
+}
+
+// Cancelled shapes the result of a cancel request
+func Cancelled(data []byte) (interface{}, error) {
+	v, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	o := asObject(v)
+	out := CancelResult{Cancelled: []string{}, NotCancelled: []CancelFailure{}}
+	for _, id := range o.list("canceled", "cancelled") {
+		out.Cancelled = append(out.Cancelled, text(id))
+	}
+	failed := o.obj("not_canceled")
+	for _, id := range sortedKeys(failed) {
+		out.NotCancelled = append(out.NotCancelled, CancelFailure{OrderID: id, Reason: failed.str(id)})
+	}
+	return out, nil
+}
+
+func parseTrade(o object) Trade {
+	t := Trade{
+		ID:              o.str("id"),
+		TokenID:         o.str("asset_id", "asset", "token_id"),
+		ConditionID:     o.str("conditionId", "market", "condition_id"),
+		Outcome:         o.str("outcome"),
+		Side:            strings.ToUpper(o.str("side")),
+		Price:           o.float("price"),
+		Size:            o.float("size"),
+		Status:          strings.ToUpper(o.str("status")),
+		Address:         strings.ToLower(o.str("proxyWallet", "maker_address", "owner")),
+		Title:           o.str("title"),
+		TransactionHash: o.str("transactionHash", "transaction_hash"),
+		Time:            o.time("match_time", "timestamp", "created_at"),
+	}
+	if c, ok := o["confirmed"].(bool); ok {
+		t.Confirmed = &c
+	}
+	return t
+}
+
+func parsePosition(o object) Position {
+	return Position{
+		Address:      strings.ToLower(o.str("proxyWallet", "user")),
+		TokenID:      o.str("asset", "asset_id"),
+		ConditionID:  o.str("conditionId", "condition_id"),
+		Outcome:      o.str("outcome"),
+		Title:        o.str("title"),
+		Slug:         o.str("slug"),
+		Size:         o.float("size"),
+		AvgPrice:     o.float("avgPrice", "avgCost"),
+		CurrentPrice: o.optional("curPrice", "currentPrice"),
+		InitialValue: o.float("initialValue"),
+		CurrentValue: o.float("currentValue", "curVal"),
+		CashPnL:      o.float("cashPnl", "unrealizedPnl"),
+		PercentPnL:   o.float("percentPnl"),
+		RealizedPnL:  o.float("realizedPnl"),
+		Redeemable:   o.boolean("redeemable"),
+		EndDate:      o.time("endDate"),
+	}
+}
+
+func parseActivity(o object) Activity {
+	return Activity{
+		Type:            strings.ToUpper(o.str("type")),
+		Address:         strings.ToLower(o.str("proxyWallet", "user")),
+		TokenID:         o.str("asset", "asset_id"),
+		ConditionID:     o.str("conditionId", "market"),
+		Outcome:         o.str("outcome"),
+		Title:           o.str("title"),
+		Side:            strings.ToUpper(o.str("side")),
+		Price:           o.optional("price"),
+		Size:            o.float("size", "amount"),
+		USDCSize:        o.float("usdcSize"),
+		TransactionHash: o.str("transactionHash", "transaction_hash"),
+		Time:            o.time("timestamp"),
+	}
+}
+
+// Profile shapes a polymarket.TraderProfile
+func Profile(data []byte) (interface{}, error) {
+	var profile polymarket.TraderProfile
+	if err := sonic.Unmarshal(data, &profile); err != nil {
+		return nil, err
+	}
+	out := TraderProfile{
+		Address:      profile.Address,
+		Stats:        profile.Stats,
+		Positions:    []Position{},
+		RecentTrades: []Trade{},
+		Activity:     []Activity{},
+		Errors:       profile.Errors,
+	}
+	if v, err := decode(profile.Positions); err == nil {
+		out.Positions = parseAll(items(v), parsePosition)
+	}
+	if v, err := decode(profile.RecentTrades); err == nil {
+		out.RecentTrades = parseAll(items(v), parseTrade)
+	}
+	if v, err := decode(profile.Activity); err == nil {
+		out.Activity = parseAll(items(v), parseActivity)
+	}
+	return out, nil
+}
+
+// Leaderboard shapes the Data API leaderboard into ranked entries
+func Leaderboard(data []byte) (interface{}, error) {
+	return leaderboard.ParseEntries(data)
+}
@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/polygo/internal/config"
+)
+
+// Default endpoints; AWS picks the regional one from the region.
+// gcsEndpoint is Cloud Storage's S3-interoperable XML API, which accepts
+// HMAC keys.
+const (
+	s3Endpoint  = "https://s3.amazonaws.com"
+	gcsEndpoint = "https://storage.googleapis.com"
+)
+
+// Bucket keeps objects in an S3-compatible bucket (AWS, MinIO, R2, ... or
+// GCS through its interoperable API) with minio-go, addressed path-style
+type Bucket struct {
+	config *config.StorageConfig
+	client *minio.Client
+	scheme string // s3 or gs, for locations
+}
+
+// newBucket creates a bucket store; the backend decides the defaults
+func newBucket(cfg *config.StorageConfig) (*Bucket, error) {
+	b := &Bucket{config: cfg, scheme: "s3"}
+	region, raw := cfg.Region, cfg.Endpoint
+	if cfg.Backend == BackendGCS {
+		b.scheme = "gs"
+		if raw == "" {
+			raw = gcsEndpoint
+		}
+		if region == "" {
+			region = "auto"
+		}
+	}
+	if raw == "" {
+		raw = s3Endpoint
+	}
+
+	endpoint, err := url.Parse(raw)
+	if err != nil || endpoint.Host == "" || strings.Trim(endpoint.Path, "/") != "" {
+		return nil, fmt.Errorf("invalid storage endpoint %q", raw)
+	}
+	b.client, err = minio.New(endpoint.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure:       endpoint.Scheme != "http",
+		Region:       region,
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Put uploads data as key
+func (b *Bucket) Put(key string, data []byte) error {
+	ctx, cancel := b.context()
+	defer cancel()
+
+	_, err := b.client.PutObject(ctx, b.config.Bucket, objectName(b.config.Prefix, key),
+		bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{ContentType: contentType(key)})
+	return err
+}
+
+// Get downloads key
+func (b *Bucket) Get(key string) ([]byte, error) {
+	ctx, cancel := b.context()
+	defer cancel()
+
+	obj, err := b.client.GetObject(ctx, b.config.Bucket, objectName(b.config.Prefix, key), minio.GetObjectOptions{})
+	if err == nil {
+		defer obj.Close()
+		var data []byte
+		if data, err = io.ReadAll(obj); err == nil {
+			return data, nil
+		}
+	}
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return nil, fmt.Errorf("%w: %s", ErrNotExist, key)
+	}
+	return nil, err
+}
+
+// Exists reports whether any object is stored under dir, listing at most
+// one of them
+func (b *Bucket) Exists(dir string) (bool, error) {
+	ctx, cancel := b.context()
+	defer cancel() // stops the listing after the first object
+
+	objects := b.client.ListObjects(ctx, b.config.Bucket, minio.ListObjectsOptions{
+		Prefix:    objectName(b.config.Prefix, dir) + "/",
+		Recursive: true,
+		MaxKeys:   1,
+	})
+	for obj := range objects {
+		if obj.Err != nil {
+			return false, obj.Err
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// Location returns key's bucket URL
+func (b *Bucket) Location(key string) string {
+	return b.scheme + "://" + b.config.Bucket + "/" + objectName(b.config.Prefix, key)
+}
+
+// context bounds a request by the configured timeout
+func (b *Bucket) context() (context.Context, context.CancelFunc) {
+	if b.config.Timeout > 0 {
+		return context.WithTimeout(context.Background(), b.config.Timeout)
+	}
+	return context.WithCancel(context.Background())
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/polygo/internal/fsutil"
+)
+
+// Local keeps objects as files, keys being paths relative to the working
+// directory (or absolute)
+type Local struct{}
+
+// NewLocal creates a local disk store
+func NewLocal() *Local {
+	return &Local{}
+}
+
+// Put writes data to a temporary file next to key and renames it over key,
+// so concurrent readers never see a partial file
+func (l *Local) Put(key string, data []byte) error {
+	return fsutil.WriteFileAtomic(filepath.FromSlash(cleanKey(key)), data, 0o644)
+}
+
+// Get reads the file at key
+func (l *Local) Get(key string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.FromSlash(cleanKey(key)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotExist, key)
+	}
+	return data, err
+}
+
+// Exists reports whether the directory dir exists
+func (l *Local) Exists(dir string) (bool, error) {
+	_, err := os.Stat(filepath.FromSlash(cleanKey(dir)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Location returns the file path of key
+func (l *Local) Location(key string) string {
+	return filepath.FromSlash(cleanKey(key))
+}
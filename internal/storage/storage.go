@@ -0,0 +1,87 @@
+// Package storage keeps artifacts (recorder snapshots, export files, tape
+// recordings) on local disk or in an S3-compatible or GCS bucket.
+//
+// Keys are slash-separated paths. On local disk a key is a file path
+// relative to the working directory, so a component configured with
+// ./data/exports keeps writing there; in a bucket the same key, without
+// the leading ./ or /, names the object under the configured prefix.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/polygo/internal/config"
+)
+
+// Backends
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+	BackendGCS   = "gcs"
+)
+
+// ErrNotExist is returned by Get for a missing key
+var ErrNotExist = errors.New("object does not exist")
+
+// Store reads and writes whole objects by key
+type Store interface {
+	// Put writes data as key, replacing any existing object
+	Put(key string, data []byte) error
+	// Get reads key, wrapping ErrNotExist when it is missing
+	Get(key string) ([]byte, error)
+	// Exists reports whether anything is stored under the directory dir
+	Exists(dir string) (bool, error)
+	// Location describes where key is kept (a file path or a bucket URL)
+	Location(key string) string
+}
+
+// New creates the store for the configured backend
+func New(cfg *config.StorageConfig) (Store, error) {
+	switch cfg.Backend {
+	case "", BackendLocal:
+		return NewLocal(), nil
+	case BackendS3, BackendGCS:
+		if cfg.Bucket == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+			return nil, fmt.Errorf("%s storage needs a bucket and access keys", cfg.Backend)
+		}
+		b, err := newBucket(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+}
+
+// cleanKey normalizes a key to slash-separated form without ./ segments
+func cleanKey(key string) string {
+	return path.Clean(filepath.ToSlash(key))
+}
+
+// objectName turns a key into a bucket object name under prefix
+func objectName(prefix, key string) string {
+	key = strings.TrimLeft(strings.TrimPrefix(cleanKey(key), "./"), "/")
+	if prefix = strings.Trim(prefix, "/"); prefix != "" {
+		return prefix + "/" + key
+	}
+	return key
+}
+
+// contentType guesses an object's media type from its extension
+func contentType(key string) string {
+	switch path.Ext(key) {
+	case ".json":
+		return "application/json"
+	case ".jsonl":
+		return "application/x-ndjson"
+	case ".csv":
+		return "text/csv"
+	case ".parquet":
+		return "application/vnd.apache.parquet"
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,80 @@
+package strategies
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/fsutil"
+)
+
+// stored is the on-disk form of every order and its fills. Owners are API
+// keys, so the file is written readable by the server's user only.
+type stored struct {
+	Orders []storedOrder `json:"orders"` // oldest first
+}
+
+type storedOrder struct {
+	Order
+	Owner string `json:"owner,omitempty"`
+	Fills []Fill `json:"fills,omitempty"`
+}
+
+// load restores the orders saved at Path; a missing file is not an error
+func (t *Tracker) load() error {
+	if t.config.Path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(t.config.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var s stored
+	if err := sonic.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, so := range s.Orders {
+		if _, ok := t.entries[so.ID]; ok {
+			continue
+		}
+		o := so.Order
+		o.Owner = so.Owner
+		t.entries[o.ID] = &entry{order: o, fills: so.Fills}
+		t.order = append(t.order, o.ID)
+	}
+	return nil
+}
+
+// saveLocked writes every order to Path, replacing the file atomically.
+// Failures are logged; the in-memory orders stay authoritative. Caller
+// holds t.mu.
+func (t *Tracker) saveLocked() {
+	if t.config.Path == "" {
+		return
+	}
+
+	s := stored{Orders: make([]storedOrder, 0, len(t.order))}
+	for _, id := range t.order {
+		e := t.entries[id]
+		s.Orders = append(s.Orders, storedOrder{Order: e.order, Owner: e.order.Owner, Fills: e.fills})
+	}
+
+	data, err := sonic.Marshal(s)
+	if err == nil {
+		err = fsutil.WriteFileAtomic(t.config.Path, data, 0o600)
+	}
+	if err != nil {
+		log.Printf("Failed to save strategy orders to %s: %v", t.config.Path, err)
+	}
+}
@@ -0,0 +1,320 @@
+// Package strategies attributes the orders placed through PolyGo to the
+// strategy their caller tagged them with, follows their fills, and reports
+// fill and PnL statistics per strategy.
+package strategies
+
+import (
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/fillnotify"
+	"github.com/polygo/internal/polymarket"
+)
+
+// placementTrade stands in for the trade ID of a fill known only from the
+// order being matched as it was placed
+const placementTrade = "placement"
+
+// Order is a tagged order placed through PolyGo
+type Order struct {
+	ID       string    `json:"id"`
+	Owner    string    `json:"-"`
+	Strategy string    `json:"strategy"`
+	TokenID  string    `json:"token_id"`
+	Side     string    `json:"side"` // BUY or SELL
+	Price    float64   `json:"price"`
+	Size     float64   `json:"size"`
+	PlacedAt time.Time `json:"placed_at"`
+}
+
+// Fill is one trade of a tagged order
+type Fill struct {
+	TradeID   string    `json:"trade_id"`
+	Price     float64   `json:"price"`
+	Size      float64   `json:"size"`
+	Time      time.Time `json:"time"`
+	Estimated bool      `json:"estimated,omitempty"` // matched as placed: the order's size at its limit price
+}
+
+// Position is a strategy's net holding of one token, at average cost
+type Position struct {
+	TokenID       string   `json:"token_id"`
+	Size          float64  `json:"size"` // negative when the strategy sold more than it bought
+	AvgPrice      float64  `json:"avg_price"`
+	Price         *float64 `json:"price"` // current midpoint; nil if unavailable
+	UnrealizedPnL float64  `json:"unrealized_pnl"`
+}
+
+// Stats are the fills and PnL of one strategy
+type Stats struct {
+	Strategy      string     `json:"strategy"`
+	Orders        int        `json:"orders"`
+	FilledOrders  int        `json:"filled_orders"` // with at least one fill
+	Fills         int        `json:"fills"`
+	OrderedSize   float64    `json:"ordered_size"` // shares
+	FilledSize    float64    `json:"filled_size"`
+	FillRate      float64    `json:"fill_rate"` // FilledSize / OrderedSize
+	Volume        float64    `json:"volume"`    // USDC filled
+	RealizedPnL   float64    `json:"realized_pnl"`
+	UnrealizedPnL float64    `json:"unrealized_pnl"` // of positions with a current price
+	PnL           float64    `json:"pnl"`
+	Positions     []Position `json:"positions"`
+	FirstOrder    time.Time  `json:"first_order"`
+	LastFill      *time.Time `json:"last_fill"`
+}
+
+// entry is an order and its fills
+type entry struct {
+	order Order
+	fills []Fill
+}
+
+// Tracker keeps the tagged orders placed through PolyGo and their fills,
+// saved to Path so statistics survive restarts. Past MaxOrders the oldest
+// orders and their fills are dropped.
+type Tracker struct {
+	clob   *polymarket.ClobClient
+	config *config.StrategiesConfig
+
+	mu      sync.RWMutex
+	entries map[string]*entry // order ID -> entry
+	order   []string          // order IDs, oldest first
+}
+
+// New creates a tracker, restoring the orders saved at Path. Current
+// midpoints from clob price open positions.
+func New(clob *polymarket.ClobClient, cfg *config.StrategiesConfig) *Tracker {
+	t := &Tracker{
+		clob:    clob,
+		config:  cfg,
+		entries: make(map[string]*entry),
+	}
+	if err := t.load(); err != nil {
+		log.Printf("Failed to restore strategy orders from %s: %v", cfg.Path, err)
+	}
+	return t
+}
+
+// Enabled reports whether orders are attributed
+func (t *Tracker) Enabled() bool {
+	return t.config.Enabled
+}
+
+// Record starts following a tagged order. An order matched as it was
+// placed is filled at once with its size at its limit price, which the
+// match was at least as good as.
+func (t *Tracker) Record(o Order, matched bool) {
+	if !t.config.Enabled || o.ID == "" || o.Strategy == "" {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.entries[o.ID]; ok {
+		return
+	}
+	e := &entry{order: o}
+	if matched {
+		e.fills = append(e.fills, Fill{TradeID: placementTrade, Price: o.Price, Size: o.Size, Time: o.PlacedAt, Estimated: true})
+	}
+	t.entries[o.ID] = e
+	t.order = append(t.order, o.ID)
+
+	for t.config.MaxOrders > 0 && len(t.order) > t.config.MaxOrders {
+		delete(t.entries, t.order[0])
+		t.order = t.order[1:]
+	}
+	t.saveLocked()
+}
+
+// ObserveFill records a fill published by the fill notifier, if its order
+// is tagged
+func (t *Tracker) ObserveFill(f fillnotify.Fill) {
+	price, _ := strconv.ParseFloat(f.Price, 64)
+	size, _ := strconv.ParseFloat(f.Size, 64)
+	t.AddFill(f.OrderID, Fill{TradeID: f.TradeID, Price: price, Size: size, Time: time.UnixMilli(f.Timestamp)})
+}
+
+// AddFill records a fill of a tagged order; fills of a trade already
+// recorded, and of untagged orders, are ignored
+func (t *Tracker) AddFill(orderID string, f Fill) {
+	if f.Size <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.entries[orderID]
+	if !ok {
+		return
+	}
+	for _, seen := range e.fills {
+		if seen.TradeID == f.TradeID || seen.Estimated {
+			return
+		}
+	}
+	e.fills = append(e.fills, f)
+	t.saveLocked()
+}
+
+// Stats returns the statistics of owner's strategies, by name, pricing
+// open positions at their current midpoints
+func (t *Tracker) Stats(owner string) []Stats {
+	byStrategy := make(map[string]*Stats)
+	books := make(map[string]map[string]*book) // strategy -> token -> book
+
+	t.mu.RLock()
+	for _, id := range t.order {
+		e := t.entries[id]
+		o := e.order
+		if o.Owner != owner {
+			continue
+		}
+
+		s := byStrategy[o.Strategy]
+		if s == nil {
+			s = &Stats{Strategy: o.Strategy, FirstOrder: o.PlacedAt, Positions: []Position{}}
+			byStrategy[o.Strategy] = s
+			books[o.Strategy] = make(map[string]*book)
+		}
+		s.Orders++
+		s.OrderedSize += o.Size
+		if len(e.fills) > 0 {
+			s.FilledOrders++
+		}
+
+		b := books[o.Strategy][o.TokenID]
+		if b == nil {
+			b = &book{}
+			books[o.Strategy][o.TokenID] = b
+		}
+		for _, f := range e.fills {
+			s.Fills++
+			s.FilledSize += f.Size
+			s.Volume += f.Price * f.Size
+			s.RealizedPnL += b.apply(o.Side, f.Price, f.Size)
+			if s.LastFill == nil || f.Time.After(*s.LastFill) {
+				at := f.Time
+				s.LastFill = &at
+			}
+		}
+	}
+	t.mu.RUnlock()
+
+	mids := t.midpoints(books)
+	out := make([]Stats, 0, len(byStrategy))
+	for name, s := range byStrategy {
+		for tokenID, b := range books[name] {
+			if math.Abs(b.size) < 1e-9 {
+				continue
+			}
+			p := Position{TokenID: tokenID, Size: round(b.size), AvgPrice: round(b.avg)}
+			if mid, ok := mids[tokenID]; ok {
+				p.Price = &mid
+				p.UnrealizedPnL = round(b.size * (mid - b.avg))
+				s.UnrealizedPnL += p.UnrealizedPnL
+			}
+			s.Positions = append(s.Positions, p)
+		}
+		sort.Slice(s.Positions, func(i, j int) bool { return s.Positions[i].TokenID < s.Positions[j].TokenID })
+
+		if s.OrderedSize > 0 {
+			s.FillRate = round(s.FilledSize / s.OrderedSize)
+		}
+		s.OrderedSize, s.FilledSize, s.Volume = round(s.OrderedSize), round(s.FilledSize), round(s.Volume)
+		s.RealizedPnL, s.UnrealizedPnL = round(s.RealizedPnL), round(s.UnrealizedPnL)
+		s.PnL = round(s.RealizedPnL + s.UnrealizedPnL)
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Strategy < out[j].Strategy })
+	return out
+}
+
+// midpoints fetches the current midpoints of the tokens held, in one
+// request. On failure positions are left unpriced.
+func (t *Tracker) midpoints(books map[string]map[string]*book) map[string]float64 {
+	held := make(map[string]bool)
+	for _, tokens := range books {
+		for tokenID, b := range tokens {
+			if math.Abs(b.size) >= 1e-9 {
+				held[tokenID] = true
+			}
+		}
+	}
+	if len(held) == 0 || t.clob == nil {
+		return nil
+	}
+	tokens := make([]string, 0, len(held))
+	for tokenID := range held {
+		tokens = append(tokens, tokenID)
+	}
+	sort.Strings(tokens)
+
+	data, err := t.clob.GetMidpoints(tokens)
+	if err != nil {
+		log.Printf("Strategy midpoints failed: %v", err)
+		return nil
+	}
+	var raw map[string]string
+	if err := sonic.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+	mids := make(map[string]float64, len(raw))
+	for tokenID, v := range raw {
+		if mid, err := strconv.ParseFloat(v, 64); err == nil {
+			mids[tokenID] = mid
+		}
+	}
+	return mids
+}
+
+// book is a strategy's position in one token at average cost
+type book struct {
+	size float64 // signed: bought minus sold
+	avg  float64
+}
+
+// apply adds a fill to the position and returns the PnL it realized by
+// reducing it
+func (b *book) apply(side string, price, size float64) float64 {
+	q := size
+	if side == "SELL" {
+		q = -size
+	}
+
+	// Adding to the position, or opening it
+	if b.size == 0 || (b.size > 0) == (q > 0) {
+		total := math.Abs(b.size) + size
+		b.avg = (b.avg*math.Abs(b.size) + price*size) / total
+		b.size += q
+		return 0
+	}
+
+	// Reducing it, and opening the other way with what is left
+	closed := math.Min(size, math.Abs(b.size))
+	realized := closed * (price - b.avg)
+	if b.size < 0 {
+		realized = -realized
+	}
+	b.size += q
+	switch {
+	case math.Abs(b.size) < 1e-9:
+		b.size, b.avg = 0, 0
+	case (b.size > 0) == (q > 0):
+		b.avg = price
+	}
+	return realized
+}
+
+// round rounds to 6 decimals
+func round(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
@@ -0,0 +1,275 @@
+// Package tape records upstream HTTP responses and WebSocket frames to the
+// configured storage and replays them, so PolyGo can run offline against
+// captured traffic.
+//
+// A recording directory holds one JSON file per distinct request under
+// http/, keyed by a hash of method, URL and body, and every WebSocket frame
+// in arrival order in ws.jsonl.
+package tape
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/storage"
+)
+
+// flushInterval is how often recorded WebSocket frames are written out;
+// storage has no appends, so each flush rewrites ws.jsonl
+const flushInterval = 10 * time.Second
+
+// ErrNotRecorded is returned in replay mode for requests missing from the
+// recording
+var ErrNotRecorded = errors.New("request not in recording")
+
+// Entry is one recorded upstream response
+type Entry struct {
+	Method     string `json:"method"`
+	URL        string `json:"url"`
+	Request    string `json:"request,omitempty"` // request body
+	Status     int    `json:"status"`
+	TTLMs      int64  `json:"ttl_ms,omitempty"`
+	Body       string `json:"body"`
+	RecordedAt int64  `json:"recorded_at"`
+}
+
+// TTL returns the freshness the upstream advertised
+func (e *Entry) TTL() time.Duration {
+	return time.Duration(e.TTLMs) * time.Millisecond
+}
+
+// Frame is one recorded WebSocket message
+type Frame struct {
+	Offset  int64  `json:"t"` // milliseconds since recording started
+	Channel string `json:"c"`
+	Data    string `json:"d"`
+}
+
+// Tape records or replays upstream traffic
+type Tape struct {
+	config *config.TapeConfig
+	store  storage.Store
+
+	mu        sync.Mutex
+	ws        bytes.Buffer // recorded frames, as ws.jsonl
+	wsStart   time.Time
+	wsDirty   bool
+	wsFlushed time.Time
+}
+
+// New opens the recording directory in store for the configured mode. It
+// returns nil when record/replay is off.
+func New(cfg *config.TapeConfig, store storage.Store) (*Tape, error) {
+	switch cfg.Mode {
+	case "", config.TapeModeOff:
+		return nil, nil
+	case config.TapeModeRecord:
+	case config.TapeModeReplay:
+		ok, err := store.Exists(cfg.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open tape directory: %w", err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("failed to open tape directory: %s is empty or missing", store.Location(cfg.Dir))
+		}
+	default:
+		return nil, fmt.Errorf("unknown tape mode %q", cfg.Mode)
+	}
+
+	return &Tape{config: cfg, store: store}, nil
+}
+
+// Recording reports whether traffic is being recorded
+func (t *Tape) Recording() bool {
+	return t != nil && t.config.Mode == config.TapeModeRecord
+}
+
+// Replaying reports whether traffic is served from the recording
+func (t *Tape) Replaying() bool {
+	return t != nil && t.config.Mode == config.TapeModeReplay
+}
+
+// Key identifies a request independently of query parameter order
+func Key(method, rawURL string, body []byte) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		u.RawQuery = u.Query().Encode()
+		rawURL = u.String()
+	}
+
+	h := sha256.New()
+	h.Write([]byte(method + " " + rawURL + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// key returns the storage key an entry is stored at
+func (t *Tape) key(method, rawURL string, reqBody []byte) string {
+	return path.Join(t.config.Dir, "http", Key(method, rawURL, reqBody)+".json")
+}
+
+// wsKey returns the storage key of the WebSocket recording
+func (t *Tape) wsKey() string {
+	return path.Join(t.config.Dir, "ws.jsonl")
+}
+
+// Save records a response. Auth headers are never written; only the
+// request line, body and the response are.
+func (t *Tape) Save(method, rawURL string, reqBody []byte, status int, ttl time.Duration, body []byte) error {
+	entry := Entry{
+		Method:     method,
+		URL:        rawURL,
+		Request:    string(reqBody),
+		Status:     status,
+		TTLMs:      ttl.Milliseconds(),
+		Body:       string(body),
+		RecordedAt: time.Now().UnixMilli(),
+	}
+	data, err := sonic.ConfigStd.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return t.store.Put(t.key(method, rawURL, reqBody), data)
+}
+
+// Load returns the recorded response for a request
+func (t *Tape) Load(method, rawURL string, reqBody []byte) (*Entry, error) {
+	data, err := t.store.Get(t.key(method, rawURL, reqBody))
+	if errors.Is(err, storage.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, method, rawURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entry Entry
+	if err := sonic.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// RecordFrame appends a WebSocket frame to the recording, writing it out
+// every flushInterval
+func (t *Tape) RecordFrame(channel string, data []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.wsStart.IsZero() {
+		t.wsStart = time.Now()
+		t.wsFlushed = t.wsStart
+	}
+
+	line, err := sonic.Marshal(Frame{
+		Offset:  time.Since(t.wsStart).Milliseconds(),
+		Channel: channel,
+		Data:    string(data),
+	})
+	if err != nil {
+		return err
+	}
+	t.ws.Write(append(line, '\n'))
+	t.wsDirty = true
+
+	if time.Since(t.wsFlushed) < flushInterval {
+		return nil
+	}
+	return t.flushLocked()
+}
+
+// flushLocked writes the recorded frames out if any were added
+func (t *Tape) flushLocked() error {
+	if !t.wsDirty {
+		return nil
+	}
+	t.wsFlushed = time.Now()
+	if err := t.store.Put(t.wsKey(), t.ws.Bytes()); err != nil {
+		return err
+	}
+	t.wsDirty = false
+	return nil
+}
+
+// Frames reads the recorded WebSocket frames
+func (t *Tape) Frames() ([]Frame, error) {
+	data, err := t.store.Get(t.wsKey())
+	if errors.Is(err, storage.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var frames []Frame
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var frame Frame
+		if err := sonic.Unmarshal(scanner.Bytes(), &frame); err != nil {
+			return nil, fmt.Errorf("corrupt frame in ws.jsonl: %w", err)
+		}
+		frames = append(frames, frame)
+	}
+	return frames, scanner.Err()
+}
+
+// ReplayFrames emits the recorded frames with their original spacing,
+// scaled by WSSpeed, until ctx is done. With WSLoop the recording restarts
+// at the end; otherwise ReplayFrames returns once every frame was sent.
+func (t *Tape) ReplayFrames(ctx context.Context, emit func(channel string, data []byte)) error {
+	frames, err := t.Frames()
+	if err != nil || len(frames) == 0 {
+		return err
+	}
+
+	for {
+		start := time.Now()
+		for _, frame := range frames {
+			if t.config.WSSpeed > 0 {
+				due := start.Add(time.Duration(float64(frame.Offset)/t.config.WSSpeed) * time.Millisecond)
+				if wait := time.Until(due); wait > 0 {
+					select {
+					case <-ctx.Done():
+						return nil
+					case <-time.After(wait):
+					}
+				}
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+			emit(frame.Channel, []byte(frame.Data))
+		}
+
+		if !t.config.WSLoop {
+			return nil
+		}
+		// Avoid spinning on a recording with no spacing
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// Close flushes the WebSocket recording
+func (t *Tape) Close() error {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.flushLocked()
+}
@@ -0,0 +1,47 @@
+package taxreport
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// csvHeader names the columns of a report's CSV, one row per disposal
+var csvHeader = []string{
+	"disposed", "acquired", "kind", "term", "condition_id", "asset", "title", "outcome",
+	"size", "proceeds", "cost_basis", "gain", "transaction_hash",
+}
+
+// WriteCSV encodes the report's disposals as CSV with a header row. Times
+// are RFC3339; a disposal with no recorded purchase has an empty acquired.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, d := range r.Disposals {
+		acquired := ""
+		if d.Acquired != nil {
+			acquired = d.Acquired.UTC().Format(time.RFC3339)
+		}
+		record := []string{
+			d.Disposed.UTC().Format(time.RFC3339), acquired, d.Kind, d.Term,
+			d.ConditionID, d.Asset, d.Title, d.Outcome,
+			formatFloat(d.Size), formatFloat(d.Proceeds), formatFloat(d.CostBasis), formatFloat(d.Gain),
+			d.TransactionHash,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatFloat renders a number without exponent or trailing zeros
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,197 @@
+package taxreport
+
+import (
+	"sort"
+	"time"
+
+	"github.com/polygo/internal/shape"
+)
+
+// dust is the share count below which a lot is considered used up
+const dust = 1e-9
+
+// book holds the open lots of a wallet by asset, oldest first
+type book struct {
+	method string
+	lots   map[string][]Lot
+}
+
+// amount is the USDC value of an activity entry, from its price when the
+// Data API left usdcSize out
+func amount(a shape.Activity) float64 {
+	if a.USDCSize > 0 || a.Price == nil {
+		return a.USDCSize
+	}
+	return a.Size * *a.Price
+}
+
+// buy opens a lot
+func (b *book) buy(a shape.Activity) {
+	if a.Size <= 0 {
+		return
+	}
+	b.lots[a.TokenID] = append(b.lots[a.TokenID], Lot{
+		ConditionID: a.ConditionID,
+		Asset:       a.TokenID,
+		Title:       a.Title,
+		Outcome:     a.Outcome,
+		Acquired:    *a.Time,
+		Size:        a.Size,
+		CostBasis:   amount(a),
+	})
+}
+
+// sell consumes a.Size shares of a's outcome from its lots in method order
+func (b *book) sell(a shape.Activity) []Disposal {
+	if a.Size <= 0 {
+		return nil
+	}
+	proceeds := amount(a)
+	base := Disposal{
+		ConditionID:     a.ConditionID,
+		Asset:           a.TokenID,
+		Title:           a.Title,
+		Outcome:         a.Outcome,
+		Kind:            "sell",
+		Disposed:        *a.Time,
+		TransactionHash: a.TransactionHash,
+	}
+
+	var out []Disposal
+	remaining := a.Size
+	lots := b.lots[a.TokenID]
+	for remaining > dust && len(lots) > 0 {
+		i := 0
+		if b.method == MethodLIFO {
+			i = len(lots) - 1
+		}
+		l := &lots[i]
+
+		size := remaining
+		if l.Size < size {
+			size = l.Size
+		}
+		cost := l.CostBasis * size / l.Size
+		d := base
+		d.Size = size
+		d.Proceeds = proceeds * size / a.Size
+		d.CostBasis = cost
+		acquired := l.Acquired
+		d.Acquired = &acquired
+		d.Term = term(l.Acquired, d.Disposed)
+		out = append(out, d)
+
+		l.Size -= size
+		l.CostBasis -= cost
+		remaining -= size
+		if l.Size <= dust {
+			lots = append(lots[:i], lots[i+1:]...)
+		}
+	}
+	b.set(a.TokenID, lots)
+
+	if remaining > dust {
+		d := base
+		d.Size = remaining
+		d.Proceeds = proceeds * remaining / a.Size
+		d.Term = TermUnknown
+		out = append(out, d)
+	}
+	return out
+}
+
+// redeem closes every open lot of a's market, sharing its payout between
+// them by shares: a redemption pays out the winning outcome and closes the
+// losing one at nothing
+func (b *book) redeem(a shape.Activity) []Disposal {
+	var held []string
+	var shares float64
+	for asset, lots := range b.lots {
+		if len(lots) == 0 || lots[0].ConditionID != a.ConditionID {
+			continue
+		}
+		held = append(held, asset)
+		for _, l := range lots {
+			shares += l.Size
+		}
+	}
+	sort.Strings(held)
+
+	payout := amount(a)
+	if len(held) == 0 {
+		if a.Size <= 0 && payout <= 0 {
+			return nil
+		}
+		return []Disposal{{
+			ConditionID:     a.ConditionID,
+			Asset:           a.TokenID,
+			Title:           a.Title,
+			Outcome:         a.Outcome,
+			Kind:            "redeem",
+			Disposed:        *a.Time,
+			Size:            a.Size,
+			Proceeds:        payout,
+			Term:            TermUnknown,
+			TransactionHash: a.TransactionHash,
+		}}
+	}
+
+	var out []Disposal
+	for _, asset := range held {
+		for _, l := range b.lots[asset] {
+			acquired := l.Acquired
+			out = append(out, Disposal{
+				ConditionID:     l.ConditionID,
+				Asset:           l.Asset,
+				Title:           l.Title,
+				Outcome:         l.Outcome,
+				Kind:            "redeem",
+				Acquired:        &acquired,
+				Disposed:        *a.Time,
+				Size:            l.Size,
+				Proceeds:        payout * l.Size / shares,
+				CostBasis:       l.CostBasis,
+				Term:            term(l.Acquired, *a.Time),
+				TransactionHash: a.TransactionHash,
+			})
+		}
+		delete(b.lots, asset)
+	}
+	return out
+}
+
+// set replaces the lots of asset, forgetting it once none are left
+func (b *book) set(asset string, lots []Lot) {
+	if len(lots) == 0 {
+		delete(b.lots, asset)
+		return
+	}
+	b.lots[asset] = lots
+}
+
+// open returns the lots still held, oldest first
+func (b *book) open() []Lot {
+	out := []Lot{}
+	for _, lots := range b.lots {
+		for _, l := range lots {
+			l.Size, l.CostBasis = round(l.Size), round(l.CostBasis)
+			out = append(out, l)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if !out[i].Acquired.Equal(out[j].Acquired) {
+			return out[i].Acquired.Before(out[j].Acquired)
+		}
+		return out[i].Asset < out[j].Asset
+	})
+	return out
+}
+
+// term is the holding term of shares acquired and disposed of at the given
+// times: long once held for more than a year
+func term(acquired, disposed time.Time) string {
+	if disposed.After(acquired.AddDate(1, 0, 0)) {
+		return TermLong
+	}
+	return TermShort
+}
@@ -0,0 +1,268 @@
+// Package taxreport matches a wallet's sales and redemptions against the
+// shares it bought, first in first out or last in first out, and reports
+// the realized gains of a calendar year per disposal and per market.
+package taxreport
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/shape"
+	"github.com/polygo/pkg/validate"
+)
+
+// Lot matching methods
+const (
+	MethodFIFO = "fifo"
+	MethodLIFO = "lifo"
+)
+
+// Holding terms of a disposal
+const (
+	TermShort   = "short"   // held a year or less
+	TermLong    = "long"    // held more than a year
+	TermUnknown = "unknown" // no recorded purchase to match
+)
+
+// activityPageSize is how many activity entries are fetched per Data API request
+const activityPageSize = 500
+
+var (
+	// ErrInvalidMethod is returned for a method other than fifo or lifo
+	ErrInvalidMethod = errors.New("method must be fifo or lifo")
+)
+
+// Lot is shares of one outcome bought together
+type Lot struct {
+	ConditionID string    `json:"condition_id"`
+	Asset       string    `json:"asset"`
+	Title       string    `json:"title,omitempty"`
+	Outcome     string    `json:"outcome,omitempty"`
+	Acquired    time.Time `json:"acquired"`
+	Size        float64   `json:"size"`
+	CostBasis   float64   `json:"cost_basis"` // USDC paid for Size
+}
+
+// Disposal is shares of one lot sold or redeemed. Shares disposed of
+// beyond the recorded lots have no acquisition date and a cost basis of 0.
+type Disposal struct {
+	ConditionID     string     `json:"condition_id"`
+	Asset           string     `json:"asset,omitempty"`
+	Title           string     `json:"title,omitempty"`
+	Outcome         string     `json:"outcome,omitempty"`
+	Kind            string     `json:"kind"` // sell or redeem
+	Acquired        *time.Time `json:"acquired"`
+	Disposed        time.Time  `json:"disposed"`
+	Size            float64    `json:"size"`
+	Proceeds        float64    `json:"proceeds"`
+	CostBasis       float64    `json:"cost_basis"`
+	Gain            float64    `json:"gain"`
+	Term            string     `json:"term"` // short, long or unknown
+	TransactionHash string     `json:"transaction_hash,omitempty"`
+}
+
+// MarketGain sums a year's disposals in one market
+type MarketGain struct {
+	ConditionID string  `json:"condition_id"`
+	Title       string  `json:"title,omitempty"`
+	Disposals   int     `json:"disposals"`
+	Proceeds    float64 `json:"proceeds"`
+	CostBasis   float64 `json:"cost_basis"`
+	Gain        float64 `json:"gain"`
+}
+
+// Totals sums a year's disposals
+type Totals struct {
+	Proceeds   float64 `json:"proceeds"`
+	CostBasis  float64 `json:"cost_basis"`
+	Gain       float64 `json:"gain"`
+	ShortTerm  float64 `json:"short_term"`
+	LongTerm   float64 `json:"long_term"`
+	Unmatched  float64 `json:"unmatched"` // shares disposed of with no recorded purchase
+	OpenShares float64 `json:"open_shares"`
+}
+
+// Report is a wallet's realized gains in a calendar year
+type Report struct {
+	Address   string         `json:"address"`
+	Year      int            `json:"year"`
+	Method    string         `json:"method"`
+	Disposals []Disposal     `json:"disposals"` // oldest first
+	Markets   []MarketGain   `json:"markets"`   // largest gain first
+	Totals    Totals         `json:"totals"`
+	OpenLots  []Lot          `json:"open_lots"` // still held at the end of the year
+	Ignored   map[string]int `json:"ignored"`   // activity of the year not counted, by type
+	Truncated bool           `json:"truncated"` // older activity than MaxActivity entries back was not fetched
+}
+
+// Reporter builds tax reports from the Data API activity of wallets
+type Reporter struct {
+	data   *polymarket.DataClient
+	config *config.TaxReportConfig
+}
+
+// New creates a new reporter
+func New(data *polymarket.DataClient, cfg *config.TaxReportConfig) *Reporter {
+	return &Reporter{data: data, config: cfg}
+}
+
+// Namespace returns a view of the reporter whose fetched user data is only
+// shared within namespace, as with DataClient.Namespace
+func (r *Reporter) Namespace(namespace string) *Reporter {
+	return &Reporter{data: r.data.Namespace(namespace), config: r.config}
+}
+
+// Generate fetches the activity of address, newest first up to
+// MaxActivity entries, and reports its gains realized in year
+func (r *Reporter) Generate(address string, year int, method string) (*Report, error) {
+	if err := validate.Address(address); err != nil {
+		return nil, err
+	}
+	if method != MethodFIFO && method != MethodLIFO {
+		return nil, ErrInvalidMethod
+	}
+
+	// One extra entry tells the report its history was cut short
+	maxItems := r.config.MaxActivity
+	if maxItems > 0 {
+		maxItems++
+	}
+	result, err := polymarket.Paginate(func(cursor string, offset int) ([]byte, error) {
+		if cursor == "" && offset > 0 {
+			cursor = strconv.Itoa(offset)
+		}
+		data, _, err := r.data.GetActivity(address, activityPageSize, cursor, true)
+		return data, err
+	}, activityPageSize, maxItems)
+	if err != nil {
+		return nil, err
+	}
+
+	items := result.Items
+	truncated := r.config.MaxActivity > 0 && len(items) > r.config.MaxActivity
+	if truncated {
+		items = items[:r.config.MaxActivity]
+	}
+	data, err := sonic.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	shaped, err := shape.Activities(data)
+	if err != nil {
+		return nil, err
+	}
+
+	report := Build(strings.ToLower(address), shaped.([]shape.Activity), year, method)
+	report.Truncated = truncated
+	return report, nil
+}
+
+// Build matches activity, in any order, into a report of the disposals
+// made in year. Buys open lots; sells consume lots of their outcome and
+// redemptions every open lot of their market, their proceeds shared out
+// by shares. Splits, merges and other activity are counted in Ignored.
+func Build(address string, activity []shape.Activity, year int, method string) *Report {
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+
+	sorted := make([]shape.Activity, 0, len(activity))
+	for _, a := range activity {
+		if a.Time != nil && a.Time.Before(end) {
+			sorted = append(sorted, a)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(*sorted[j].Time) })
+
+	report := &Report{
+		Address:   address,
+		Year:      year,
+		Method:    method,
+		Disposals: []Disposal{},
+		Markets:   []MarketGain{},
+		OpenLots:  []Lot{},
+		Ignored:   map[string]int{},
+	}
+	b := &book{method: method, lots: make(map[string][]Lot)}
+	for _, a := range sorted {
+		var disposals []Disposal
+		switch {
+		case a.Type == "TRADE" && a.Side == "BUY":
+			b.buy(a)
+		case a.Type == "TRADE" && a.Side == "SELL":
+			disposals = b.sell(a)
+		case a.Type == "REDEEM":
+			disposals = b.redeem(a)
+		default:
+			if !a.Time.Before(start) {
+				report.Ignored[a.Type]++
+			}
+		}
+		if !a.Time.Before(start) {
+			report.Disposals = append(report.Disposals, disposals...)
+		}
+	}
+
+	markets := make(map[string]*MarketGain)
+	for i := range report.Disposals {
+		d := &report.Disposals[i]
+		d.Size, d.Proceeds, d.CostBasis = round(d.Size), round(d.Proceeds), round(d.CostBasis)
+		d.Gain = round(d.Proceeds - d.CostBasis)
+
+		m := markets[d.ConditionID]
+		if m == nil {
+			m = &MarketGain{ConditionID: d.ConditionID, Title: d.Title}
+			markets[d.ConditionID] = m
+		}
+		m.Disposals++
+		m.Proceeds += d.Proceeds
+		m.CostBasis += d.CostBasis
+
+		t := &report.Totals
+		t.Proceeds += d.Proceeds
+		t.CostBasis += d.CostBasis
+		switch d.Term {
+		case TermLong:
+			t.LongTerm += d.Gain
+		case TermUnknown:
+			t.Unmatched += d.Size
+			t.ShortTerm += d.Gain
+		default:
+			t.ShortTerm += d.Gain
+		}
+	}
+	for _, m := range markets {
+		m.Proceeds, m.CostBasis = round(m.Proceeds), round(m.CostBasis)
+		m.Gain = round(m.Proceeds - m.CostBasis)
+		report.Markets = append(report.Markets, *m)
+	}
+	sort.Slice(report.Markets, func(i, j int) bool {
+		if report.Markets[i].Gain != report.Markets[j].Gain {
+			return report.Markets[i].Gain > report.Markets[j].Gain
+		}
+		return report.Markets[i].ConditionID < report.Markets[j].ConditionID
+	})
+
+	t := &report.Totals
+	t.Proceeds, t.CostBasis = round(t.Proceeds), round(t.CostBasis)
+	t.Gain = round(t.Proceeds - t.CostBasis)
+	t.ShortTerm, t.LongTerm, t.Unmatched = round(t.ShortTerm), round(t.LongTerm), round(t.Unmatched)
+
+	report.OpenLots = b.open()
+	for _, l := range report.OpenLots {
+		t.OpenShares += l.Size
+	}
+	t.OpenShares = round(t.OpenShares)
+	return report
+}
+
+// round rounds to 6 decimals
+func round(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
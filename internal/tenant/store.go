@@ -0,0 +1,69 @@
+package tenant
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/fsutil"
+)
+
+// load restores the usage saved at UsagePath, dropping days past retention
+// and tenants no longer configured; a missing file is not an error
+func (r *Registry) load() error {
+	if r.config.UsagePath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(r.config.UsagePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var stored map[string]map[string]*day
+	if err := sonic.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	for id, days := range stored {
+		if _, ok := r.tenants[id]; !ok {
+			continue
+		}
+		for _, d := range days {
+			if d.Routes == nil {
+				d.Routes = make(map[string]*RouteUsage)
+			}
+		}
+		r.usage[id] = days
+	}
+	r.mu.Unlock()
+
+	r.prune(time.Now())
+	return nil
+}
+
+// save writes usage to UsagePath, replacing the file atomically. Failures
+// are logged; the in-memory usage stays authoritative.
+func (r *Registry) save() {
+	if r.config.UsagePath == "" {
+		return
+	}
+
+	r.mu.Lock()
+	data, err := sonic.Marshal(r.usage)
+	r.mu.Unlock()
+
+	if err == nil {
+		err = fsutil.WriteFileAtomic(r.config.UsagePath, data, 0o600)
+	}
+	if err != nil {
+		log.Printf("Failed to save tenant usage to %s: %v", r.config.UsagePath, err)
+	}
+}
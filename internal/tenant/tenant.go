@@ -0,0 +1,172 @@
+// Package tenant groups API keys into tenants so PolyGo can be shared by
+// several teams: each tenant has its own rate limit, watchlists, webhooks
+// and cached user data, and its requests are accounted for per day.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polygo/internal/config"
+)
+
+// ownerPrefix starts the owner of a tenant's webhooks, watchlists and
+// orders, so it cannot collide with a bare API key
+const ownerPrefix = "tenant:"
+
+var (
+	// ErrInvalidConfig is wrapped by every tenants config error
+	ErrInvalidConfig = errors.New("invalid tenants config")
+	// ErrNotFound is returned for an unknown tenant ID
+	ErrNotFound = errors.New("tenant not found")
+)
+
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// Tenant is a group of API keys
+type Tenant struct {
+	ID        string `json:"id"`
+	Name      string `json:"name,omitempty"`
+	RateLimit int    `json:"rate_limit"` // requests per 10s
+	APIKeys   int    `json:"api_keys"`   // how many keys belong to the tenant
+}
+
+// Owner is the owner of the tenant's webhooks, watchlists and orders,
+// shared by all of its API keys
+func (t *Tenant) Owner() string {
+	return ownerPrefix + t.ID
+}
+
+// Registry resolves API keys to tenants and accounts for their usage
+type Registry struct {
+	config  *config.TenantsConfig
+	tenants map[string]*Tenant // by ID
+	keys    map[string]*Tenant // by API key
+
+	mu    sync.Mutex
+	usage map[string]map[string]*day // tenant ID -> UTC date -> usage
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a registry of the configured tenants and restores their
+// usage from UsagePath. Tenant IDs must be unique and an API key may
+// belong to only one tenant.
+func New(cfg *config.TenantsConfig) (*Registry, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Registry{
+		config:  cfg,
+		tenants: make(map[string]*Tenant),
+		keys:    make(map[string]*Tenant),
+		usage:   make(map[string]map[string]*day),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	if !cfg.Enabled {
+		return r, nil
+	}
+
+	for i, spec := range cfg.Tenants {
+		id := strings.TrimSpace(spec.ID)
+		if !idPattern.MatchString(id) {
+			return nil, fmt.Errorf("%w: tenant %d: id must be 1 to 64 letters, digits, '.', '_' or '-'", ErrInvalidConfig, i+1)
+		}
+		if _, ok := r.tenants[id]; ok {
+			return nil, fmt.Errorf("%w: tenant %s is configured twice", ErrInvalidConfig, id)
+		}
+
+		t := &Tenant{ID: id, Name: spec.Name, RateLimit: spec.RateLimit}
+		if t.RateLimit <= 0 {
+			t.RateLimit = cfg.RateLimit
+		}
+		for _, key := range spec.APIKeys {
+			if key == "" {
+				continue
+			}
+			if other, ok := r.keys[key]; ok {
+				return nil, fmt.Errorf("%w: an API key of tenant %s also belongs to tenant %s", ErrInvalidConfig, id, other.ID)
+			}
+			r.keys[key] = t
+			t.APIKeys++
+		}
+		r.tenants[id] = t
+	}
+
+	if err := r.load(); err != nil {
+		log.Printf("Failed to restore tenant usage from %s: %v", cfg.UsagePath, err)
+	}
+	return r, nil
+}
+
+// Enabled reports whether tenants are enabled
+func (r *Registry) Enabled() bool {
+	return r.config.Enabled
+}
+
+// Start periodically saves usage and forgets days past retention
+func (r *Registry) Start() {
+	if !r.config.Enabled || r.config.UsagePath == "" || r.config.SaveInterval <= 0 {
+		return
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.config.SaveInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.ctx.Done():
+				return
+			case now := <-ticker.C:
+				r.prune(now)
+				r.save()
+			}
+		}
+	}()
+}
+
+// Stop stops periodic saving and saves usage
+func (r *Registry) Stop() {
+	r.cancel()
+	r.wg.Wait()
+	if r.config.Enabled {
+		r.save()
+	}
+}
+
+// ForKey returns the tenant an API key belongs to
+func (r *Registry) ForKey(key string) (*Tenant, bool) {
+	if key == "" {
+		return nil, false
+	}
+	t, ok := r.keys[key]
+	return t, ok
+}
+
+// Get returns a tenant by ID
+func (r *Registry) Get(id string) (*Tenant, bool) {
+	t, ok := r.tenants[id]
+	return t, ok
+}
+
+// List returns every tenant, by ID
+func (r *Registry) List() []Tenant {
+	out := make([]Tenant, 0, len(r.tenants))
+	for _, t := range r.tenants {
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, k int) bool { return out[i].ID < out[k].ID })
+	return out
+}
@@ -0,0 +1,173 @@
+package tenant
+
+import (
+	"net/http"
+	"sort"
+	"time"
+)
+
+// dateLayout is the layout of usage dates (UTC days)
+const dateLayout = "2006-01-02"
+
+// Usage counts a tenant's requests
+type Usage struct {
+	Requests    int64 `json:"requests"`
+	Errors      int64 `json:"errors"`       // 4xx and 5xx responses, besides rate limiting
+	RateLimited int64 `json:"rate_limited"` // requests rejected by the tenant's rate limit
+	BytesIn     int64 `json:"bytes_in"`
+	BytesOut    int64 `json:"bytes_out"`
+}
+
+// DayUsage is a tenant's usage on one UTC day
+type DayUsage struct {
+	Date string `json:"date"`
+	Usage
+}
+
+// RouteUsage is a tenant's requests to one route
+type RouteUsage struct {
+	Route    string `json:"route"` // method and route pattern, e.g. GET /api/v1/markets/:id
+	Requests int64  `json:"requests"`
+	Errors   int64  `json:"errors"`
+}
+
+// Report is a tenant's usage over a range of days
+type Report struct {
+	Tenant Tenant       `json:"tenant"`
+	From   string       `json:"from"`
+	To     string       `json:"to"`
+	Total  Usage        `json:"total"`
+	Days   []DayUsage   `json:"days"`   // days with requests, oldest first
+	Routes []RouteUsage `json:"routes"` // most requested first
+}
+
+// day is a tenant's usage on one day, with its routes
+type day struct {
+	Usage
+	Routes map[string]*RouteUsage `json:"routes"`
+}
+
+func (u *Usage) add(o Usage) {
+	u.Requests += o.Requests
+	u.Errors += o.Errors
+	u.RateLimited += o.RateLimited
+	u.BytesIn += o.BytesIn
+	u.BytesOut += o.BytesOut
+}
+
+// Record counts a tenant's request to route (its pattern, not its path)
+// that was answered with status
+func (r *Registry) Record(t *Tenant, method, route string, status int, bytesIn, bytesOut int64, now time.Time) {
+	date := now.UTC().Format(dateLayout)
+	limited := status == http.StatusTooManyRequests
+	failed := status >= 400 && !limited
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	days, ok := r.usage[t.ID]
+	if !ok {
+		days = make(map[string]*day)
+		r.usage[t.ID] = days
+	}
+	d, ok := days[date]
+	if !ok {
+		d = &day{Routes: make(map[string]*RouteUsage)}
+		days[date] = d
+	}
+
+	d.Requests++
+	d.BytesIn += bytesIn
+	d.BytesOut += bytesOut
+	if limited {
+		d.RateLimited++
+	}
+	if failed {
+		d.Errors++
+	}
+
+	// Rate-limited requests never reached their route
+	if limited {
+		return
+	}
+	key := method + " " + route
+	ru, ok := d.Routes[key]
+	if !ok {
+		ru = &RouteUsage{Route: key}
+		d.Routes[key] = ru
+	}
+	ru.Requests++
+	if failed {
+		ru.Errors++
+	}
+}
+
+// Usage reports a tenant's usage from one UTC day to another, inclusive
+func (r *Registry) Usage(id string, from, to time.Time) (*Report, error) {
+	t, ok := r.tenants[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	report := &Report{
+		Tenant: *t,
+		From:   from.UTC().Format(dateLayout),
+		To:     to.UTC().Format(dateLayout),
+		Days:   []DayUsage{},
+		Routes: []RouteUsage{},
+	}
+	routes := make(map[string]*RouteUsage)
+
+	r.mu.Lock()
+	for date, d := range r.usage[id] {
+		if date < report.From || date > report.To {
+			continue
+		}
+		report.Days = append(report.Days, DayUsage{Date: date, Usage: d.Usage})
+		report.Total.add(d.Usage)
+		for key, ru := range d.Routes {
+			sum, ok := routes[key]
+			if !ok {
+				sum = &RouteUsage{Route: key}
+				routes[key] = sum
+			}
+			sum.Requests += ru.Requests
+			sum.Errors += ru.Errors
+		}
+	}
+	r.mu.Unlock()
+
+	sort.Slice(report.Days, func(i, k int) bool { return report.Days[i].Date < report.Days[k].Date })
+	for _, ru := range routes {
+		report.Routes = append(report.Routes, *ru)
+	}
+	sort.Slice(report.Routes, func(i, k int) bool {
+		a, b := report.Routes[i], report.Routes[k]
+		if a.Requests != b.Requests {
+			return a.Requests > b.Requests
+		}
+		return a.Route < b.Route
+	})
+	return report, nil
+}
+
+// prune forgets usage older than UsageRetention days before now
+func (r *Registry) prune(now time.Time) {
+	if r.config.UsageRetention <= 0 {
+		return
+	}
+	cutoff := now.UTC().AddDate(0, 0, 1-r.config.UsageRetention).Format(dateLayout)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, days := range r.usage {
+		for date := range days {
+			if date < cutoff {
+				delete(days, date)
+			}
+		}
+		if len(days) == 0 {
+			delete(r.usage, id)
+		}
+	}
+}
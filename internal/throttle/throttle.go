@@ -0,0 +1,248 @@
+// Package throttle guards the CLOB against runaway clients, such as a bot
+// stuck in a loop: it rejects an order identical to one the same API key
+// sent moments ago, and caps how many orders a key sends to one market
+// within a rolling window
+package throttle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/models"
+)
+
+// Rejection codes
+const (
+	CodeDuplicate  = "DUPLICATE_ORDER"
+	CodeMarketRate = "ORDER_MARKET_RATE"
+)
+
+// Error is an order the guard rejected
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Order   int    `json:"order"` // index of the offending order in a batch
+	// RetryAfter is when the rejection clears, 0 if it never will
+	RetryAfter time.Duration `json:"-"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Guard remembers the orders each API key sent recently
+type Guard struct {
+	resolver *catalog.Resolver
+	config   *config.ThrottleConfig
+
+	mu     sync.Mutex
+	sent   map[string]time.Time   // when each key last sent each distinct order
+	recent map[string][]time.Time // orders per key and market within MarketWindow
+	swept  time.Time
+}
+
+// New creates a new guard
+func New(resolver *catalog.Resolver, cfg *config.ThrottleConfig) *Guard {
+	return &Guard{
+		resolver: resolver,
+		config:   cfg,
+		sent:     make(map[string]time.Time),
+		recent:   make(map[string][]time.Time),
+	}
+}
+
+// Enabled reports whether orders are checked at all
+func (g *Guard) Enabled() bool {
+	return g.config.Enabled
+}
+
+// Reservation is the record of orders a guard accepted, until released
+type Reservation struct {
+	at      time.Time
+	keys    []string             // fingerprints recorded
+	prev    map[string]time.Time // what they replaced
+	markets []string             // market counts added to, one per order
+}
+
+// Reserve returns an *Error for the first of the orders an account is about
+// to place together that repeats an order it sent within DuplicateWindow,
+// or repeats another order of the batch, or that takes its market over
+// MarketRate. Accepted orders are remembered until Released; rejected ones
+// are not. The reservation is nil when the guard is disabled.
+func (g *Guard) Reserve(account string, orders []models.CreateOrderRequest) (*Reservation, error) {
+	if !g.config.Enabled {
+		return nil, nil
+	}
+
+	// Markets are resolved before taking the lock, as they may need Gamma
+	markets := make([]string, len(orders))
+	for i, o := range orders {
+		markets[i] = g.market(o.TokenID)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	g.sweepLocked(now)
+
+	window := g.config.DuplicateWindow
+	keys := make([]string, len(orders))
+	batch := make(map[string]bool, len(orders))
+	for i := range orders {
+		o := &orders[i]
+		keys[i] = account + "|" + fingerprint(o)
+		if window <= 0 {
+			continue
+		}
+		if batch[keys[i]] {
+			return nil, &Error{Code: CodeDuplicate, Order: i, Message: fmt.Sprintf(
+				"Order %s %s @ %s on token %s appears twice in the request", o.Side, o.Size, o.Price, o.TokenID)}
+		}
+		batch[keys[i]] = true
+		if last, ok := g.sent[keys[i]]; ok && now.Sub(last) < window {
+			return nil, &Error{Code: CodeDuplicate, Order: i, RetryAfter: last.Add(window).Sub(now), Message: fmt.Sprintf(
+				"Order %s %s @ %s on token %s repeats one sent %s ago", o.Side, o.Size, o.Price, o.TokenID, now.Sub(last).Round(time.Millisecond))}
+		}
+	}
+
+	limit := g.config.MarketRate
+	counts := make(map[string]int)
+	first := make(map[string]int)
+	for i, m := range markets {
+		if _, ok := first[m]; !ok {
+			first[m] = i
+		}
+		counts[m]++
+	}
+	for i, m := range markets {
+		if limit <= 0 || first[m] != i {
+			continue
+		}
+		recent := g.pruneLocked(account+"|"+m, now)
+		if len(recent)+counts[m] <= limit {
+			continue
+		}
+		e := &Error{Code: CodeMarketRate, Order: i, Message: fmt.Sprintf(
+			"Order rate limit of %d per %s reached for market %s", limit, g.config.MarketWindow, m)}
+		if counts[m] <= limit {
+			// Wait for enough of the recent orders to leave the window
+			e.RetryAfter = recent[len(recent)+counts[m]-limit-1].Add(g.config.MarketWindow).Sub(now)
+		}
+		return nil, e
+	}
+
+	r := &Reservation{at: now, prev: make(map[string]time.Time)}
+	for i := range orders {
+		if window > 0 {
+			r.prev[keys[i]] = g.sent[keys[i]]
+			r.keys = append(r.keys, keys[i])
+			g.sent[keys[i]] = now
+		}
+		if limit > 0 {
+			key := account + "|" + markets[i]
+			r.markets = append(r.markets, key)
+			g.recent[key] = append(g.recent[key], now)
+		}
+	}
+	return r, nil
+}
+
+// Release forgets a reservation whose orders were not placed after all,
+// e.g. because a later check or the CLOB rejected the request
+func (g *Guard) Release(r *Reservation) {
+	if r == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, key := range r.keys {
+		if !g.sent[key].Equal(r.at) {
+			continue // sent again since
+		}
+		if prev := r.prev[key]; prev.IsZero() {
+			delete(g.sent, key)
+		} else {
+			g.sent[key] = prev
+		}
+	}
+	for _, key := range r.markets {
+		recent := g.recent[key]
+		for i := len(recent) - 1; i >= 0; i-- {
+			if recent[i].Equal(r.at) {
+				recent = append(recent[:i:i], recent[i+1:]...)
+				break
+			}
+		}
+		if len(recent) == 0 {
+			delete(g.recent, key)
+		} else {
+			g.recent[key] = recent
+		}
+	}
+}
+
+// market returns the condition ID of a token's market, so both outcomes
+// count together; tokens that cannot be resolved count on their own
+func (g *Guard) market(tokenID string) string {
+	if g.resolver != nil {
+		if info, err := g.resolver.Resolve(tokenID); err == nil && info.ConditionID != "" {
+			return info.ConditionID
+		}
+	}
+	return tokenID
+}
+
+// pruneLocked drops a key's orders that left the market window. Caller
+// holds g.mu.
+func (g *Guard) pruneLocked(key string, now time.Time) []time.Time {
+	recent := g.recent[key]
+	drop := 0
+	for drop < len(recent) && now.Sub(recent[drop]) >= g.config.MarketWindow {
+		drop++
+	}
+	recent = recent[drop:]
+	if len(recent) == 0 {
+		delete(g.recent, key)
+	} else {
+		g.recent[key] = recent
+	}
+	return recent
+}
+
+// sweepLocked forgets orders past both windows, at most once per minute.
+// Caller holds g.mu.
+func (g *Guard) sweepLocked(now time.Time) {
+	if now.Sub(g.swept) < time.Minute {
+		return
+	}
+	g.swept = now
+	for key, last := range g.sent {
+		if now.Sub(last) >= g.config.DuplicateWindow {
+			delete(g.sent, key)
+		}
+	}
+	for key := range g.recent {
+		g.pruneLocked(key, now)
+	}
+}
+
+// fingerprint identifies an order by token, side, price and size, with
+// numbers normalized so 0.5 and 0.50 match
+func fingerprint(o *models.CreateOrderRequest) string {
+	return strings.Join([]string{o.TokenID, strings.ToUpper(string(o.Side)), number(o.Price), number(o.Size)}, "|")
+}
+
+func number(s string) string {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return s
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,204 @@
+package ticker
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/wsbuffer"
+	"github.com/polygo/internal/wsfanout"
+	"github.com/polygo/internal/wsframe"
+)
+
+// Tick is a compact midpoint update for one token
+type Tick struct {
+	MarketID string  `json:"m"`
+	TokenID  string  `json:"t"`
+	Mid      float64 `json:"p"`
+}
+
+// Frame is one throttled batch of ticks sent to clients
+type Frame struct {
+	Type      string `json:"type"` // "snapshot" on connect, then "ticker"
+	Timestamp int64  `json:"ts"`
+	Ticks     []Tick `json:"d"`
+}
+
+// Ticker polls midpoints of the top markets by volume once per Interval
+// and fans out only the tokens that changed, so each token updates at most
+// once per Interval regardless of upstream activity
+type Ticker struct {
+	clob    *polymarket.ClobClient
+	catalog *catalog.Catalog
+	config  *config.TickerConfig
+
+	mu      sync.RWMutex
+	last    map[string]Tick // token ID -> last sent tick
+	clients *wsfanout.Fanout[struct{}]
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a new ticker
+func New(clob *polymarket.ClobClient, cat *catalog.Catalog, cfg *config.TickerConfig) *Ticker {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Ticker{
+		clob:    clob,
+		catalog: cat,
+		config:  cfg,
+		last:    make(map[string]Tick),
+		clients: wsfanout.New[struct{}](),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Start begins polling; polls are skipped while nobody is subscribed
+func (t *Ticker) Start() {
+	if !t.config.Enabled {
+		return
+	}
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+
+		ticker := time.NewTicker(t.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-t.ctx.Done():
+				return
+			case <-ticker.C:
+				if t.clients.Len() == 0 {
+					continue
+				}
+				if err := t.poll(); err != nil {
+					log.Printf("Ticker poll failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops polling and closes every subscriber
+func (t *Ticker) Stop() {
+	t.cancel()
+	t.wg.Wait()
+	t.clients.Close()
+}
+
+// Subscribe registers a client. It is first sent a snapshot frame of the
+// current midpoints, then throttled updates, all in the requested encoding.
+func (t *Ticker) Subscribe(out *wsbuffer.Client, enc wsframe.Encoding) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ticks := make([]Tick, 0, len(t.last))
+	for _, tick := range t.last {
+		ticks = append(ticks, tick)
+	}
+	sort.Slice(ticks, func(i, j int) bool { return ticks[i].TokenID < ticks[j].TokenID })
+	if data, err := sonic.Marshal(Frame{Type: "snapshot", Timestamp: time.Now().UnixMilli(), Ticks: ticks}); err == nil {
+		wsfanout.Send(out, enc, data)
+	}
+
+	t.clients.Add(out, enc, struct{}{})
+}
+
+// Unsubscribe removes a client
+func (t *Ticker) Unsubscribe(out *wsbuffer.Client) {
+	t.clients.Remove(out)
+}
+
+// poll fetches midpoints for the top markets and broadcasts changes
+func (t *Ticker) poll() error {
+	tokens := t.topTokens()
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(tokens))
+	for id := range tokens {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	data, err := t.clob.GetMidpoints(ids)
+	if err != nil {
+		return err
+	}
+
+	var mids map[string]string
+	if err := sonic.Unmarshal(data, &mids); err != nil {
+		return err
+	}
+
+	t.Publish(tokens, mids)
+	return nil
+}
+
+// Publish diffs fresh midpoints (token ID -> price) against the last sent
+// values and broadcasts one frame with the tokens that changed. tokens maps
+// token IDs to their market IDs.
+func (t *Ticker) Publish(tokens map[string]string, mids map[string]string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var changed []Tick
+	for tokenID, raw := range mids {
+		mid, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			continue
+		}
+		if prev, ok := t.last[tokenID]; ok && prev.Mid == mid {
+			continue
+		}
+		tick := Tick{MarketID: tokens[tokenID], TokenID: tokenID, Mid: mid}
+		t.last[tokenID] = tick
+		changed = append(changed, tick)
+	}
+	if len(changed) == 0 {
+		return
+	}
+	sort.Slice(changed, func(i, j int) bool { return changed[i].TokenID < changed[j].TokenID })
+
+	data, err := sonic.Marshal(Frame{Type: "ticker", Timestamp: time.Now().UnixMilli(), Ticks: changed})
+	if err != nil {
+		return
+	}
+
+	// Encoded at most once per encoding, not per client
+	t.clients.Publish(data, nil)
+}
+
+// topTokens returns the first outcome token of the top markets by 24h
+// volume, mapped to their market IDs
+func (t *Ticker) topTokens() map[string]string {
+	markets := t.catalog.Markets()
+	sort.SliceStable(markets, func(i, j int) bool {
+		return markets[i].Volume24hr.Float() > markets[j].Volume24hr.Float()
+	})
+
+	tokens := make(map[string]string, t.config.TopMarkets)
+	for _, m := range markets {
+		if len(tokens) >= t.config.TopMarkets {
+			break
+		}
+		if len(m.ClobTokenIDs) > 0 {
+			tokens[m.ClobTokenIDs[0]] = m.ID
+		}
+	}
+	return tokens
+}
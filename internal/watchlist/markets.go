@@ -0,0 +1,299 @@
+package watchlist
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/models"
+)
+
+// Market frame types
+const (
+	MarketSnapshot = "market_snapshot" // current state, on connect or once a new watch is first polled
+	MarketUpdate   = "market_update"   // state after a change
+)
+
+// Fields reported in MarketFrame.Changed
+const (
+	ChangedPrice      = "price"
+	ChangedVolume     = "volume"
+	ChangedResolution = "resolution"
+)
+
+// ErrUnknownMarket is returned when watching a market Gamma does not know
+var ErrUnknownMarket = errors.New("unknown market")
+
+// WatchedMarket is a market on a caller's watchlist
+type WatchedMarket struct {
+	MarketID  string    `json:"market_id"`
+	Owner     string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MarketState is the polled state of a watched market
+type MarketState struct {
+	MarketID   string             `json:"market_id"`
+	Question   string             `json:"question,omitempty"`
+	Prices     map[string]float64 `json:"prices"` // outcome -> midpoint (final price once closed)
+	Volume     float64            `json:"volume"`
+	Volume24hr float64            `json:"volume_24hr"`
+	Closed     bool               `json:"closed"`
+	Winner     string             `json:"winner,omitempty"` // winning outcome once resolved
+}
+
+// MarketFrame is pushed to WebSocket subscribers whose watchlist holds the market
+type MarketFrame struct {
+	Type      string   `json:"type"`
+	Changed   []string `json:"changed,omitempty"`
+	Timestamp int64    `json:"timestamp"`
+	*MarketState
+}
+
+// marketFrame encodes a market frame as JSON
+func marketFrame(frameType string, state *MarketState, changed []string) ([]byte, error) {
+	return sonic.Marshal(MarketFrame{Type: frameType, Changed: changed, Timestamp: time.Now().UnixMilli(), MarketState: state})
+}
+
+// AddMarket puts a market on owner's watchlist
+func (w *Watchlist) AddMarket(marketID, owner string) (WatchedMarket, error) {
+	if _, err := w.fetchMarket(marketID); err != nil {
+		return WatchedMarket{}, err
+	}
+	key := owner + "|" + marketID
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if m, ok := w.markets[key]; ok {
+		return *m, nil
+	}
+	if w.config.MaxMarkets > 0 && len(w.markets) >= w.config.MaxMarkets {
+		return WatchedMarket{}, ErrFull
+	}
+
+	m := &WatchedMarket{MarketID: marketID, Owner: owner, CreatedAt: time.Now()}
+	w.markets[key] = m
+	w.saveLocked()
+	return *m, nil
+}
+
+// RemoveMarket takes a market off owner's watchlist
+func (w *Watchlist) RemoveMarket(marketID, owner string) bool {
+	key := owner + "|" + marketID
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.markets[key]; !ok {
+		return false
+	}
+	delete(w.markets, key)
+	if len(w.ownersLocked(marketID)) == 0 {
+		delete(w.states, marketID)
+	}
+	w.saveLocked()
+	return true
+}
+
+// Markets returns the markets on owner's watchlist, oldest first
+func (w *Watchlist) Markets(owner string) []WatchedMarket {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	markets := w.marketsLocked(owner)
+	out := make([]WatchedMarket, len(markets))
+	for i, m := range markets {
+		out[i] = *m
+	}
+	return out
+}
+
+// marketsLocked returns owner's watches, oldest first; caller holds w.mu
+func (w *Watchlist) marketsLocked(owner string) []*WatchedMarket {
+	var out []*WatchedMarket
+	for _, m := range w.markets {
+		if m.Owner == owner {
+			out = append(out, m)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// ownersLocked returns who watches a market; caller holds w.mu
+func (w *Watchlist) ownersLocked(marketID string) map[string]bool {
+	owners := make(map[string]bool)
+	for _, m := range w.markets {
+		if m.MarketID == marketID {
+			owners[m.Owner] = true
+		}
+	}
+	return owners
+}
+
+// PollMarkets refreshes every watched market once, whoever watches it,
+// and pushes the ones whose price, volume or resolution changed
+func (w *Watchlist) PollMarkets() {
+	w.mu.RLock()
+	ids := make(map[string]bool)
+	for _, m := range w.markets {
+		ids[m.MarketID] = true
+	}
+	w.mu.RUnlock()
+	if len(ids) == 0 {
+		return
+	}
+
+	markets := make([]*models.Market, 0, len(ids))
+	for id := range ids {
+		if w.ctx.Err() != nil {
+			return
+		}
+		m, err := w.fetchMarket(id)
+		if err != nil {
+			log.Printf("Watchlist poll for market %s failed: %v", id, err)
+			continue
+		}
+		markets = append(markets, m)
+	}
+
+	mids := w.midpoints(markets)
+	for _, m := range markets {
+		w.updateMarket(marketState(m, mids))
+	}
+}
+
+// fetchMarket loads a market from Gamma (cached for MarketsTTL)
+func (w *Watchlist) fetchMarket(id string) (*models.Market, error) {
+	data, _, err := w.gamma.GetMarket(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 || string(data) == "null" {
+		return nil, ErrUnknownMarket
+	}
+
+	var m models.Market
+	if err := sonic.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	if m.ID == "" {
+		return nil, ErrUnknownMarket
+	}
+	return &m, nil
+}
+
+// midpoints fetches current midpoints for the outcome tokens of open
+// markets in one request. On failure, prices fall back to Gamma's.
+func (w *Watchlist) midpoints(markets []*models.Market) map[string]string {
+	var tokens []string
+	for _, m := range markets {
+		if !m.Closed {
+			tokens = append(tokens, m.ClobTokenIDs...)
+		}
+	}
+	if len(tokens) == 0 || w.clob == nil {
+		return nil
+	}
+	sort.Strings(tokens)
+
+	data, err := w.clob.GetMidpoints(tokens)
+	if err != nil {
+		log.Printf("Watchlist midpoints failed: %v", err)
+		return nil
+	}
+	var mids map[string]string
+	if err := sonic.Unmarshal(data, &mids); err != nil {
+		return nil
+	}
+	return mids
+}
+
+// marketState builds the state of a market, preferring live midpoints
+func marketState(m *models.Market, mids map[string]string) *MarketState {
+	state := &MarketState{
+		MarketID:   m.ID,
+		Question:   m.Question,
+		Prices:     make(map[string]float64, len(m.Outcomes)),
+		Volume:     m.Volume.Float(),
+		Volume24hr: m.Volume24hr.Float(),
+		Closed:     m.Closed,
+	}
+
+	final := m.OutcomePrices.Floats()
+	for i, outcome := range m.Outcomes {
+		var price float64
+		if i < len(final) {
+			price = final[i]
+		}
+		if i < len(m.ClobTokenIDs) {
+			if mid, err := strconv.ParseFloat(mids[m.ClobTokenIDs[i]], 64); err == nil {
+				price = mid
+			}
+		}
+		state.Prices[outcome] = price
+
+		if m.Closed && i < len(final) && final[i] == 1 {
+			state.Winner = outcome
+		}
+	}
+	return state
+}
+
+// updateMarket stores a polled state and pushes it to the market's
+// watchers: as a snapshot the first time, then only when something changed
+func (w *Watchlist) updateMarket(state *MarketState) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	owners := w.ownersLocked(state.MarketID)
+	if len(owners) == 0 {
+		return // removed while polling
+	}
+
+	frameType := MarketSnapshot
+	var changed []string
+	if prev, ok := w.states[state.MarketID]; ok {
+		frameType = MarketUpdate
+		changed = diffStates(prev, state)
+		if len(changed) == 0 {
+			return
+		}
+	}
+	w.states[state.MarketID] = state
+
+	// Encoded at most once per encoding, not per client
+	data, err := marketFrame(frameType, state, changed)
+	if err != nil {
+		return
+	}
+	w.clients.Publish(data, func(cl *client) bool {
+		return owners[cl.owner]
+	})
+}
+
+// diffStates lists what changed between two polls of a market
+func diffStates(prev, next *MarketState) []string {
+	var changed []string
+	if len(prev.Prices) != len(next.Prices) {
+		changed = append(changed, ChangedPrice)
+	} else {
+		for outcome, price := range next.Prices {
+			if prev.Prices[outcome] != price {
+				changed = append(changed, ChangedPrice)
+				break
+			}
+		}
+	}
+	if prev.Volume != next.Volume || prev.Volume24hr != next.Volume24hr {
+		changed = append(changed, ChangedVolume)
+	}
+	if prev.Closed != next.Closed || prev.Winner != next.Winner {
+		changed = append(changed, ChangedResolution)
+	}
+	return changed
+}
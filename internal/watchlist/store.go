@@ -0,0 +1,94 @@
+package watchlist
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/fsutil"
+)
+
+// stored is the on-disk form of every watchlist. Owners are API keys, so
+// the file is written readable by the server's user only.
+type stored struct {
+	Wallets []storedWallet `json:"wallets"`
+	Markets []storedMarket `json:"markets"`
+}
+
+type storedWallet struct {
+	Address   string    `json:"address"`
+	Label     string    `json:"label,omitempty"`
+	Owner     string    `json:"owner,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type storedMarket struct {
+	MarketID  string    `json:"market_id"`
+	Owner     string    `json:"owner,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// load restores the watchlists saved at Path; a missing file is not an error
+func (w *Watchlist) load() error {
+	if w.config.Path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(w.config.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var s stored
+	if err := sonic.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, sw := range s.Wallets {
+		w.entries[sw.Owner+"|"+sw.Address] = &entry{
+			wallet: Wallet{Address: sw.Address, Label: sw.Label, Owner: sw.Owner, CreatedAt: sw.CreatedAt},
+			seen:   make(map[string]struct{}),
+		}
+	}
+	for _, sm := range s.Markets {
+		w.markets[sm.Owner+"|"+sm.MarketID] = &WatchedMarket{MarketID: sm.MarketID, Owner: sm.Owner, CreatedAt: sm.CreatedAt}
+	}
+	return nil
+}
+
+// saveLocked writes every watchlist to Path, replacing the file atomically.
+// Failures are logged; the in-memory watchlists stay authoritative. Caller
+// holds w.mu.
+func (w *Watchlist) saveLocked() {
+	if w.config.Path == "" {
+		return
+	}
+
+	s := stored{
+		Wallets: make([]storedWallet, 0, len(w.entries)),
+		Markets: make([]storedMarket, 0, len(w.markets)),
+	}
+	for _, e := range w.entries {
+		s.Wallets = append(s.Wallets, storedWallet{Address: e.wallet.Address, Label: e.wallet.Label, Owner: e.wallet.Owner, CreatedAt: e.wallet.CreatedAt})
+	}
+	for _, m := range w.markets {
+		s.Markets = append(s.Markets, storedMarket{MarketID: m.MarketID, Owner: m.Owner, CreatedAt: m.CreatedAt})
+	}
+
+	data, err := sonic.Marshal(s)
+	if err == nil {
+		err = fsutil.WriteFileAtomic(w.config.Path, data, 0o600)
+	}
+	if err != nil {
+		log.Printf("Failed to save watchlists to %s: %v", w.config.Path, err)
+	}
+}
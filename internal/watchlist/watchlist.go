@@ -0,0 +1,354 @@
+package watchlist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/webhooks"
+	"github.com/polygo/internal/wsbuffer"
+	"github.com/polygo/internal/wsfanout"
+	"github.com/polygo/internal/wsframe"
+	"github.com/polygo/pkg/validate"
+)
+
+// EventWalletTrade is the webhook event type for a new trade by a watched wallet
+const EventWalletTrade = "wallet.trade"
+
+var (
+	// ErrFull is returned once MaxWallets or MaxMarkets are being watched
+	ErrFull = errors.New("watchlist is full")
+)
+
+// Wallet is a watched wallet address
+type Wallet struct {
+	Address    string    `json:"address"`
+	Label      string    `json:"label,omitempty"`
+	Owner      string    `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastPolled time.Time `json:"last_polled,omitempty"`
+}
+
+// TradeEvent is pushed to WebSocket subscribers and webhooks for each new
+// trade by a watched wallet
+type TradeEvent struct {
+	Type    string          `json:"type"` // always "wallet_trade"
+	Address string          `json:"address"`
+	Label   string          `json:"label,omitempty"`
+	Trade   json.RawMessage `json:"trade"`
+}
+
+// walletTrade is the subset of a Data API trade needed to tell trades apart
+type walletTrade struct {
+	Asset           string  `json:"asset"`
+	Side            string  `json:"side"`
+	Size            float64 `json:"size"`
+	Price           float64 `json:"price"`
+	TransactionHash string  `json:"transactionHash"`
+}
+
+// key identifies a trade across overlapping polls
+func (t *walletTrade) key() string {
+	return t.TransactionHash + ":" + t.Asset + ":" + t.Side + ":" +
+		strconv.FormatFloat(t.Size, 'f', -1, 64) + ":" + strconv.FormatFloat(t.Price, 'f', -1, 64)
+}
+
+// entry is a wallet plus the trades seen on its last poll
+type entry struct {
+	wallet Wallet
+	seen   map[string]struct{}
+	primed bool // the first poll only records what is already there
+}
+
+// client is a WebSocket subscriber. It receives updates for its owner's
+// watched markets and trades of its owner's wallets, or of the given
+// wallets instead when any are given.
+type client struct {
+	owner   string
+	wallets map[string]bool
+}
+
+// Watchlist tracks wallets and markets per API key. It polls the recent
+// trades of watched wallets and the price, volume and resolution of watched
+// markets, and pushes what changed to WebSocket subscribers (and new wallet
+// trades to webhooks). Watchlists are saved to Path so they survive restarts.
+type Watchlist struct {
+	data     *polymarket.DataClient
+	gamma    *polymarket.GammaClient
+	clob     *polymarket.ClobClient
+	webhooks *webhooks.Dispatcher
+	config   *config.WatchlistConfig
+
+	mu      sync.RWMutex
+	entries map[string]*entry         // owner + "|" + address -> entry
+	markets map[string]*WatchedMarket // owner + "|" + market ID -> watch
+	states  map[string]*MarketState   // market ID -> last polled state
+	clients *wsfanout.Fanout[*client]
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a new watchlist, restoring the watchlists saved at Path
+func New(data *polymarket.DataClient, gamma *polymarket.GammaClient, clob *polymarket.ClobClient, dispatcher *webhooks.Dispatcher, cfg *config.WatchlistConfig) *Watchlist {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	w := &Watchlist{
+		data:     data,
+		gamma:    gamma,
+		clob:     clob,
+		webhooks: dispatcher,
+		config:   cfg,
+		entries:  make(map[string]*entry),
+		markets:  make(map[string]*WatchedMarket),
+		states:   make(map[string]*MarketState),
+		clients:  wsfanout.New[*client](),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	if err := w.load(); err != nil {
+		log.Printf("Failed to restore watchlists from %s: %v", cfg.Path, err)
+	}
+	return w
+}
+
+// Start polls watched wallets every Interval and watched markets every
+// MarketInterval
+func (w *Watchlist) Start() {
+	if !w.config.Enabled {
+		return
+	}
+
+	w.loop(w.config.Interval, w.Poll)
+	w.loop(w.config.MarketInterval, w.PollMarkets)
+}
+
+// loop runs poll every interval until Stop
+func (w *Watchlist) loop(interval time.Duration, poll func()) {
+	if interval <= 0 {
+		return
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-ticker.C:
+				poll()
+			}
+		}
+	}()
+}
+
+// Stop stops polling and closes every subscriber
+func (w *Watchlist) Stop() {
+	w.cancel()
+	w.wg.Wait()
+	w.clients.Close()
+}
+
+// Add starts watching an address for owner. Adding an address that is
+// already watched updates its label.
+func (w *Watchlist) Add(address, label, owner string) (Wallet, error) {
+	if err := validate.Address(address); err != nil {
+		return Wallet{}, err
+	}
+	address = strings.ToLower(address)
+	key := owner + "|" + address
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if e, ok := w.entries[key]; ok {
+		if e.wallet.Label != label {
+			e.wallet.Label = label
+			w.saveLocked()
+		}
+		return e.wallet, nil
+	}
+	if w.config.MaxWallets > 0 && len(w.entries) >= w.config.MaxWallets {
+		return Wallet{}, ErrFull
+	}
+
+	e := &entry{
+		wallet: Wallet{Address: address, Label: label, Owner: owner, CreatedAt: time.Now()},
+		seen:   make(map[string]struct{}),
+	}
+	w.entries[key] = e
+	w.saveLocked()
+	return e.wallet, nil
+}
+
+// Remove stops watching an address for owner
+func (w *Watchlist) Remove(address, owner string) bool {
+	key := owner + "|" + strings.ToLower(address)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.entries[key]; !ok {
+		return false
+	}
+	delete(w.entries, key)
+	w.saveLocked()
+	return true
+}
+
+// Wallets returns the wallets watched for owner, oldest first
+func (w *Watchlist) Wallets(owner string) []Wallet {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	out := make([]Wallet, 0, len(w.entries))
+	for _, e := range w.entries {
+		if e.wallet.Owner == owner {
+			out = append(out, e.wallet)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// Addresses returns every watched wallet address across owners, sorted
+func (w *Watchlist) Addresses() []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	seen := make(map[string]bool, len(w.entries))
+	out := make([]string, 0, len(w.entries))
+	for _, e := range w.entries {
+		if !seen[e.wallet.Address] {
+			seen[e.wallet.Address] = true
+			out = append(out, e.wallet.Address)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Subscribe registers a WebSocket client for owner's watchlist. It is
+// first sent a snapshot of each watched market already polled, then
+// updates. Wallet trades are those of owner's wallets, or of the given
+// addresses when any are given.
+func (w *Watchlist) Subscribe(out *wsbuffer.Client, enc wsframe.Encoding, owner string, addresses []string) {
+	filter := make(map[string]bool, len(addresses))
+	for _, a := range addresses {
+		filter[strings.ToLower(a)] = true
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, m := range w.marketsLocked(owner) {
+		state, ok := w.states[m.MarketID]
+		if !ok {
+			continue
+		}
+		if data, err := marketFrame(MarketSnapshot, state, nil); err == nil {
+			wsfanout.Send(out, enc, data)
+		}
+	}
+
+	w.clients.Add(out, enc, &client{owner: owner, wallets: filter})
+}
+
+// Unsubscribe removes a client
+func (w *Watchlist) Unsubscribe(out *wsbuffer.Client) {
+	w.clients.Remove(out)
+}
+
+// Poll fetches the recent trades of every watched wallet and publishes the
+// new ones. Failures are logged and retried on the next poll.
+func (w *Watchlist) Poll() {
+	w.mu.RLock()
+	entries := make([]*entry, 0, len(w.entries))
+	for _, e := range w.entries {
+		entries = append(entries, e)
+	}
+	w.mu.RUnlock()
+
+	for _, e := range entries {
+		if w.ctx.Err() != nil {
+			return
+		}
+		if err := w.poll(e); err != nil {
+			log.Printf("Watchlist poll for %s failed: %v", e.wallet.Address, err)
+		}
+	}
+}
+
+// poll diffs one wallet's recent trades against those seen last time
+func (w *Watchlist) poll(e *entry) error {
+	data, _, err := w.data.GetTrades(e.wallet.Address, w.config.TradesLimit, "", false)
+	if err != nil {
+		return err
+	}
+
+	var raw []json.RawMessage
+	if err := sonic.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	seen := make(map[string]struct{}, len(raw))
+	var fresh []json.RawMessage
+	// Upstream lists newest first; publish oldest first
+	for i := len(raw) - 1; i >= 0; i-- {
+		var t walletTrade
+		if err := sonic.Unmarshal(raw[i], &t); err != nil {
+			continue
+		}
+		key := t.key()
+		seen[key] = struct{}{}
+		if _, ok := e.seen[key]; !ok && e.primed {
+			fresh = append(fresh, raw[i])
+		}
+	}
+	e.seen = seen
+	e.primed = true
+	e.wallet.LastPolled = time.Now()
+	wallet := e.wallet
+	w.mu.Unlock()
+
+	for _, trade := range fresh {
+		w.publish(wallet, trade)
+	}
+	return nil
+}
+
+// publish fans one trade out to matching WebSocket clients and webhooks
+func (w *Watchlist) publish(wallet Wallet, trade json.RawMessage) {
+	event := TradeEvent{Type: "wallet_trade", Address: wallet.Address, Label: wallet.Label, Trade: trade}
+
+	if w.webhooks != nil {
+		w.webhooks.Publish(EventWalletTrade, "", wallet.Owner, event)
+	}
+
+	data, err := sonic.Marshal(event)
+	if err != nil {
+		return
+	}
+	w.clients.Publish(data, func(cl *client) bool {
+		if len(cl.wallets) > 0 {
+			return cl.wallets[wallet.Address]
+		}
+		return cl.owner == wallet.Owner
+	})
+}
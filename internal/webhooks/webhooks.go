@@ -0,0 +1,568 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/idgen"
+	"github.com/polygo/pkg/polygoclient"
+	"github.com/valyala/fasthttp"
+)
+
+// Delivery statuses
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// Subscription is a registered webhook endpoint
+type Subscription struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`         // event types, "*" for all
+	Tags      []string  `json:"tags,omitempty"` // tagged events (market.listed, rule.triggered) must carry one of these
+	Owner     string    `json:"owner,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Deliveries are signed with Secret, and with the one it replaced until
+	// previousUntil; never listed
+	Secret         string `json:"-"`
+	previousSecret string
+	previousUntil  time.Time
+}
+
+// RotationGrace is how long deliveries stay signed with a subscription's
+// previous secret too after it is rotated, for receivers to switch over
+const RotationGrace = 24 * time.Hour
+
+// MinSecretLength is the shortest secret a subscription may be given
+const MinSecretLength = 16
+
+// NewSecret returns a random subscription secret
+func NewSecret() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return "whsec_" + hex.EncodeToString(b)
+}
+
+// secretsLocked returns the secrets a delivery is signed with now; caller
+// holds d.mu
+func (s *Subscription) secretsLocked(now time.Time) []string {
+	if s.previousSecret != "" && now.Before(s.previousUntil) {
+		return []string{s.Secret, s.previousSecret}
+	}
+	return []string{s.Secret}
+}
+
+// Matches reports whether the subscription wants events of the given type.
+// A trailing ".*" matches a whole family (e.g. "order.*").
+func (s *Subscription) Matches(eventType string) bool {
+	for _, e := range s.Events {
+		if e == "*" || e == eventType {
+			return true
+		}
+		if strings.HasSuffix(e, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(e, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesTags reports whether the subscription wants an event carrying the
+// given tags. Events published without tags always match.
+func (s *Subscription) MatchesTags(tags []string) bool {
+	if len(s.Tags) == 0 || tags == nil {
+		return true
+	}
+	for _, want := range s.Tags {
+		for _, tag := range tags {
+			if strings.EqualFold(want, tag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Event is the envelope delivered to webhook endpoints. RequestID carries
+// the correlation ID of the API request that caused the event, if any.
+type Event struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	RequestID string      `json:"request_id,omitempty"`
+	Owner     string      `json:"-"`
+	Tags      []string    `json:"-"` // tag slugs subscriptions can filter on
+	Timestamp int64       `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// Delivery tracks one attempt to deliver an event to a subscription
+type Delivery struct {
+	ID             string    `json:"id"`
+	SubscriptionID string    `json:"subscription_id"`
+	EventID        string    `json:"event_id"`
+	EventType      string    `json:"event_type"`
+	RequestID      string    `json:"request_id,omitempty"`
+	URL            string    `json:"url"`
+	Status         string    `json:"status"`
+	Attempts       int       `json:"attempts"`
+	LastStatusCode int       `json:"last_status_code,omitempty"`
+	LastError      string    `json:"last_error,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	DeliveredAt    time.Time `json:"delivered_at,omitempty"`
+
+	payload []byte
+	secrets []string // of the subscription when queued
+}
+
+// Dispatcher fans events out to matching subscriptions and delivers them
+// asynchronously with retries, keeping a bounded delivery history
+type Dispatcher struct {
+	config     *config.WebhooksConfig
+	httpClient *fasthttp.Client
+
+	mu            sync.RWMutex
+	subs          map[string]*Subscription
+	deliveries    map[string]*Delivery
+	deliveryOrder []string
+	listeners     map[chan *Event]string // in-process listeners by owner
+
+	queue  chan *Delivery
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher creates a new webhook dispatcher. It fails when the
+// configured client certificate or CA bundle can't be loaded.
+func NewDispatcher(cfg *config.WebhooksConfig) (*Dispatcher, error) {
+	tlsConfig, err := clientTLS(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Dispatcher{
+		config: cfg,
+		httpClient: &fasthttp.Client{
+			Name:                     "PolyGo-Webhooks/1.0",
+			ReadTimeout:              cfg.Timeout,
+			WriteTimeout:             cfg.Timeout,
+			NoDefaultUserAgentHeader: true,
+			TLSConfig:                tlsConfig,
+		},
+		subs:       make(map[string]*Subscription),
+		deliveries: make(map[string]*Delivery),
+		listeners:  make(map[chan *Event]string),
+		queue:      make(chan *Delivery, cfg.QueueSize),
+		ctx:        ctx,
+		cancel:     cancel,
+	}, nil
+}
+
+// clientTLS returns the TLS config of deliveries: the client certificate
+// presented to endpoints that require mTLS, and the CAs their certificates
+// are checked against besides the system ones. Nil when neither is set.
+func clientTLS(cfg *config.WebhooksConfig) (*tls.Config, error) {
+	if cfg.ClientCert == "" && cfg.ClientKey == "" && cfg.CAFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("webhook client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("webhook CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("webhook CA file: no certificates in %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// Start launches the delivery workers
+func (d *Dispatcher) Start() {
+	if !d.config.Enabled {
+		return
+	}
+
+	for i := 0; i < d.config.Workers; i++ {
+		d.wg.Add(1)
+		go d.worker()
+	}
+}
+
+// Stop stops the delivery workers; queued deliveries are abandoned
+func (d *Dispatcher) Stop() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+// Subscribe registers a new subscription whose deliveries are signed with
+// secret, or a random one when empty
+func (d *Dispatcher) Subscribe(url string, events, tags []string, owner, secret string) *Subscription {
+	if len(events) == 0 {
+		events = []string{"*"}
+	}
+	if secret == "" {
+		secret = NewSecret()
+	}
+
+	sub := &Subscription{
+		ID:        idgen.WithPrefix("whk"),
+		URL:       url,
+		Events:    events,
+		Tags:      tags,
+		Owner:     owner,
+		CreatedAt: time.Now(),
+		Secret:    secret,
+	}
+
+	d.mu.Lock()
+	d.subs[sub.ID] = sub
+	d.mu.Unlock()
+
+	return sub
+}
+
+// RotateSecret replaces a subscription's secret with secret, or a random
+// one when empty, and returns a copy of the subscription with it.
+// Deliveries are signed with the old secret too for RotationGrace.
+func (d *Dispatcher) RotateSecret(id, secret string) (Subscription, bool) {
+	if secret == "" {
+		secret = NewSecret()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	sub, ok := d.subs[id]
+	if !ok {
+		return Subscription{}, false
+	}
+	sub.previousSecret, sub.previousUntil = sub.Secret, time.Now().Add(RotationGrace)
+	sub.Secret = secret
+	return *sub, true
+}
+
+// Unsubscribe removes a subscription
+func (d *Dispatcher) Unsubscribe(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.subs[id]; !ok {
+		return false
+	}
+	delete(d.subs, id)
+	return true
+}
+
+// Subscription returns a subscription by ID
+func (d *Dispatcher) Subscription(id string) (*Subscription, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	sub, ok := d.subs[id]
+	return sub, ok
+}
+
+// Subscriptions returns all subscriptions, optionally filtered by owner
+func (d *Dispatcher) Subscriptions(owner string) []*Subscription {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	out := make([]*Subscription, 0, len(d.subs))
+	for _, sub := range d.subs {
+		if owner == "" || sub.Owner == owner {
+			out = append(out, sub)
+		}
+	}
+	return out
+}
+
+// Publish creates an event and queues a delivery to every matching
+// subscription. When owner is set, only that owner's subscriptions match.
+func (d *Dispatcher) Publish(eventType, requestID, owner string, data interface{}) *Event {
+	return d.publish(&Event{
+		ID:        idgen.WithPrefix("evt"),
+		Type:      eventType,
+		RequestID: requestID,
+		Owner:     owner,
+		Timestamp: time.Now().UnixMilli(),
+		Data:      data,
+	})
+}
+
+// PublishTagged publishes an event of no particular owner to every
+// matching subscription whose tag filter, if any, shares one of tags
+func (d *Dispatcher) PublishTagged(eventType string, tags []string, data interface{}) *Event {
+	if tags == nil {
+		tags = []string{}
+	}
+	return d.publish(&Event{
+		ID:        idgen.WithPrefix("evt"),
+		Type:      eventType,
+		Tags:      tags,
+		Timestamp: time.Now().UnixMilli(),
+		Data:      data,
+	})
+}
+
+// publish notifies listeners and queues deliveries of an event
+func (d *Dispatcher) publish(event *Event) *Event {
+	eventType, requestID, owner := event.Type, event.RequestID, event.Owner
+
+	d.notify(event)
+
+	if !d.config.Enabled {
+		return event
+	}
+
+	payload, err := sonic.Marshal(event)
+	if err != nil {
+		return event
+	}
+
+	now := time.Now()
+	d.mu.Lock()
+	var queued []*Delivery
+	for _, sub := range d.subs {
+		if !sub.Matches(eventType) || !sub.MatchesTags(event.Tags) || (owner != "" && sub.Owner != "" && sub.Owner != owner) {
+			continue
+		}
+		delivery := &Delivery{
+			ID:             idgen.WithPrefix("dlv"),
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      eventType,
+			RequestID:      requestID,
+			URL:            sub.URL,
+			Status:         StatusPending,
+			CreatedAt:      now,
+			payload:        payload,
+			secrets:        sub.secretsLocked(now),
+		}
+		d.recordLocked(delivery)
+		queued = append(queued, delivery)
+	}
+	d.mu.Unlock()
+
+	for _, delivery := range queued {
+		select {
+		case d.queue <- delivery:
+		default:
+			d.finish(delivery, StatusFailed, 0, "delivery queue full")
+		}
+	}
+
+	return event
+}
+
+// listenerBuffer is how many events a slow listener may fall behind
+// before events are dropped for it
+const listenerBuffer = 64
+
+// Listen streams an owner's events in process (e.g. to a WebSocket),
+// whether or not webhooks are enabled. Call the returned func to stop;
+// it closes the channel.
+func (d *Dispatcher) Listen(owner string) (<-chan *Event, func()) {
+	ch := make(chan *Event, listenerBuffer)
+
+	d.mu.Lock()
+	d.listeners[ch] = owner
+	d.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			d.mu.Lock()
+			delete(d.listeners, ch)
+			d.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// notify hands an event to its owner's listeners without blocking
+func (d *Dispatcher) notify(event *Event) {
+	if event.Owner == "" {
+		return
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for ch, owner := range d.listeners {
+		if owner != event.Owner {
+			continue
+		}
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Delivery returns a snapshot of a delivery record by ID
+func (d *Dispatcher) Delivery(id string) (Delivery, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	delivery, ok := d.deliveries[id]
+	if !ok {
+		return Delivery{}, false
+	}
+	return *delivery, true
+}
+
+// DeliveriesForEvent returns snapshots of every delivery of an event
+func (d *Dispatcher) DeliveriesForEvent(eventID string) []Delivery {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var out []Delivery
+	for _, id := range d.deliveryOrder {
+		if delivery := d.deliveries[id]; delivery != nil && delivery.EventID == eventID {
+			out = append(out, *delivery)
+		}
+	}
+	return out
+}
+
+// recordLocked stores a delivery, evicting the oldest beyond MaxDeliveries;
+// caller holds d.mu
+func (d *Dispatcher) recordLocked(delivery *Delivery) {
+	d.deliveries[delivery.ID] = delivery
+	d.deliveryOrder = append(d.deliveryOrder, delivery.ID)
+
+	for len(d.deliveryOrder) > d.config.MaxDeliveries {
+		delete(d.deliveries, d.deliveryOrder[0])
+		d.deliveryOrder = d.deliveryOrder[1:]
+	}
+}
+
+// worker delivers queued events
+func (d *Dispatcher) worker() {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case delivery := <-d.queue:
+			d.deliver(delivery)
+		}
+	}
+}
+
+// deliver POSTs the event, retrying with linear backoff
+func (d *Dispatcher) deliver(delivery *Delivery) {
+	var lastStatus int
+	var lastErr string
+
+	for attempt := 1; attempt <= d.config.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-d.ctx.Done():
+				return
+			case <-time.After(d.config.RetryBackoff * time.Duration(attempt-1)):
+			}
+		}
+
+		status, err := d.post(delivery)
+		lastStatus, lastErr = status, ""
+		if err != nil {
+			lastErr = err.Error()
+		}
+
+		d.mu.Lock()
+		delivery.Attempts = attempt
+		delivery.LastStatusCode = lastStatus
+		delivery.LastError = lastErr
+		d.mu.Unlock()
+
+		if err == nil {
+			d.finish(delivery, StatusDelivered, status, "")
+			return
+		}
+	}
+
+	d.finish(delivery, StatusFailed, lastStatus, lastErr)
+}
+
+// post performs a single delivery attempt, signed afresh so the
+// timestamp receivers check is the attempt's
+func (d *Dispatcher) post(delivery *Delivery) (int, error) {
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(delivery.URL)
+	req.Header.SetMethod("POST")
+	req.Header.SetContentType("application/json")
+	req.Header.Set("X-PolyGo-Event-ID", delivery.EventID)
+	req.Header.Set("X-PolyGo-Event-Type", delivery.EventType)
+	req.Header.Set("X-PolyGo-Delivery-ID", delivery.ID)
+	if delivery.RequestID != "" {
+		req.Header.Set("X-Request-ID", delivery.RequestID)
+	}
+	if len(delivery.secrets) > 0 {
+		ts := time.Now().Unix()
+		sigs := make([]string, len(delivery.secrets))
+		for i, secret := range delivery.secrets {
+			sigs[i] = polygoclient.SignWebhook(secret, ts, delivery.payload)
+		}
+		req.Header.Set(polygoclient.HeaderWebhookTimestamp, strconv.FormatInt(ts, 10))
+		req.Header.Set(polygoclient.HeaderWebhookSignature, strings.Join(sigs, ","))
+	}
+	req.SetBody(delivery.payload)
+
+	if err := d.httpClient.DoTimeout(req, resp, d.config.Timeout); err != nil {
+		return 0, err
+	}
+
+	status := resp.StatusCode()
+	if status < 200 || status >= 300 {
+		return status, fmt.Errorf("endpoint responded with status %d", status)
+	}
+	return status, nil
+}
+
+// finish marks a delivery as completed
+func (d *Dispatcher) finish(delivery *Delivery, status string, code int, errMsg string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delivery.Status = status
+	delivery.LastStatusCode = code
+	delivery.LastError = errMsg
+	if status == StatusDelivered {
+		delivery.DeliveredAt = time.Now()
+	}
+}
@@ -0,0 +1,373 @@
+// Package wsbuffer bounds the memory held by frames queued for WebSocket
+// clients. Each client's frames are written in order by one goroutine; a
+// client whose queue outgrows the per-client limit is disconnected, and
+// when all queues together outgrow the global limit the clients with the
+// most queued are disconnected until the rest fit.
+package wsbuffer
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Conn is the part of a WebSocket connection a Client writes to
+type Conn interface {
+	WriteMessage(messageType int, data []byte) error
+	WriteControl(messageType int, data []byte, deadline time.Time) error
+	Close() error
+}
+
+// Eviction reasons, as reported on /metrics
+const (
+	ReasonClientLimit = "client_limit"
+	ReasonTotalLimit  = "total_limit"
+)
+
+// closeTimeout bounds the close frame sent to an evicted client
+const closeTimeout = time.Second
+
+// Pool accounts for the frames queued for every client
+type Pool struct {
+	maxBytes       int // 0 = unlimited
+	maxClientBytes int // 0 = unlimited
+
+	mu      sync.Mutex
+	bytes   int
+	clients map[*Client]struct{}
+
+	evictedClient atomic.Uint64
+	evictedTotal  atomic.Uint64
+	droppedFrames atomic.Uint64 // still queued for evicted clients
+}
+
+// New creates a pool holding at most maxBytes across clients and
+// maxClientBytes per client; 0 leaves either unlimited
+func New(maxBytes, maxClientBytes int) *Pool {
+	return &Pool{
+		maxBytes:       maxBytes,
+		maxClientBytes: maxClientBytes,
+		clients:        make(map[*Client]struct{}),
+	}
+}
+
+// frame is a message queued for a client: head, if any, then data. Data
+// may be shared with other clients; head is the client's own.
+type frame struct {
+	messageType int
+	head        []byte
+	data        []byte
+}
+
+func (f frame) size() int {
+	return len(f.head) + len(f.data)
+}
+
+// nextWriter is implemented by connections that can write a message in
+// parts, so a head and shared data go out without being joined first
+type nextWriter interface {
+	NextWriter(messageType int) (io.WriteCloser, error)
+}
+
+// Client queues frames for one connection
+type Client struct {
+	pool *Pool
+	conn Conn
+
+	// Guarded by pool.mu
+	queue  []frame
+	bytes  int // queued, including the frame being written
+	closed bool
+
+	wake chan struct{}
+	done chan struct{}
+
+	sentFrames atomic.Uint64
+	sentBytes  atomic.Uint64
+}
+
+// Add registers conn and starts writing the frames sent to it. Close the
+// client once the connection ends.
+func (p *Pool) Add(conn Conn) *Client {
+	c := &Client{
+		pool: p,
+		conn: conn,
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	p.mu.Lock()
+	p.clients[c] = struct{}{}
+	p.mu.Unlock()
+
+	go c.run()
+	return c
+}
+
+// Send queues a frame. It reports false once the client is closed, which
+// includes being evicted for this frame.
+func (c *Client) Send(messageType int, data []byte) bool {
+	return c.queueFrame(frame{messageType: messageType, data: data})
+}
+
+// SendParts queues a frame whose payload is head followed by data, so data
+// can be shared between clients and only head is the client's own
+func (c *Client) SendParts(messageType int, head, data []byte) bool {
+	return c.queueFrame(frame{messageType: messageType, head: head, data: data})
+}
+
+func (c *Client) queueFrame(f frame) bool {
+	p := c.pool
+	n := f.size()
+
+	p.mu.Lock()
+	if c.closed {
+		p.mu.Unlock()
+		return false
+	}
+	// A frame larger than the limit still goes to a client that is caught up
+	if p.maxClientBytes > 0 && c.bytes > 0 && c.bytes+n > p.maxClientBytes {
+		p.evict(c, ReasonClientLimit)
+		p.mu.Unlock()
+		return false
+	}
+	for p.maxBytes > 0 && p.bytes+n > p.maxBytes {
+		slowest := p.slowest()
+		if slowest == nil {
+			break
+		}
+		p.evict(slowest, ReasonTotalLimit)
+		if slowest == c {
+			p.mu.Unlock()
+			return false
+		}
+	}
+	c.queue = append(c.queue, f)
+	c.bytes += n
+	p.bytes += n
+	p.mu.Unlock()
+
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// Close drops the client's queued frames and stops its writer. It does
+// not close the connection.
+func (c *Client) Close() {
+	c.pool.mu.Lock()
+	defer c.pool.mu.Unlock()
+	c.pool.drop(c)
+}
+
+// Done is closed once the client is closed, evicted or fails to write
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// run writes queued frames until the client is closed
+func (c *Client) run() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-c.wake:
+		}
+
+		for {
+			f, ok := c.next()
+			if !ok {
+				break
+			}
+			err := c.write(f)
+			c.written(f.size())
+			if err != nil {
+				c.Close()
+				return
+			}
+			c.sentFrames.Add(1)
+			c.sentBytes.Add(uint64(f.size()))
+		}
+	}
+}
+
+// write sends a frame as one message
+func (c *Client) write(f frame) error {
+	if len(f.head) == 0 {
+		return c.conn.WriteMessage(f.messageType, f.data)
+	}
+	nw, ok := c.conn.(nextWriter)
+	if !ok {
+		return c.conn.WriteMessage(f.messageType, append(f.head[:len(f.head):len(f.head)], f.data...))
+	}
+	w, err := nw.NextWriter(f.messageType)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(f.head); err != nil {
+		w.Close()
+		return err
+	}
+	if _, err := w.Write(f.data); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// next returns the oldest queued frame, which stays accounted for until
+// it is written
+func (c *Client) next() (frame, bool) {
+	c.pool.mu.Lock()
+	defer c.pool.mu.Unlock()
+	if c.closed || len(c.queue) == 0 {
+		return frame{}, false
+	}
+	return c.queue[0], true
+}
+
+// written releases the frame next returned
+func (c *Client) written(n int) {
+	p := c.pool
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if c.closed {
+		// Evicted or closed while writing; the queue is already released
+		return
+	}
+	c.queue[0] = frame{}
+	c.queue = c.queue[1:]
+	if len(c.queue) == 0 {
+		c.queue = nil
+	}
+	c.bytes -= n
+	p.bytes -= n
+}
+
+// slowest returns the client with the most bytes queued. p.mu is held.
+func (p *Pool) slowest() *Client {
+	var slowest *Client
+	for c := range p.clients {
+		if c.bytes > 0 && (slowest == nil || c.bytes > slowest.bytes) {
+			slowest = c
+		}
+	}
+	return slowest
+}
+
+// drop releases a client's queue and stops its writer. p.mu is held.
+func (p *Pool) drop(c *Client) {
+	if c.closed {
+		return
+	}
+	c.closed = true
+	p.bytes -= c.bytes
+	c.bytes = 0
+	c.queue = nil
+	delete(p.clients, c)
+	close(c.done)
+}
+
+// evict drops a client and closes its connection, which ends the handler
+// reading from it. p.mu is held.
+func (p *Pool) evict(c *Client, reason string) {
+	p.droppedFrames.Add(uint64(len(c.queue)))
+	p.drop(c)
+	if reason == ReasonClientLimit {
+		p.evictedClient.Add(1)
+	} else {
+		p.evictedTotal.Add(1)
+	}
+
+	// The writer may be blocked on this connection; closing it frees the write
+	go func() {
+		msg := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too slow: send buffer limit exceeded")
+		c.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(closeTimeout))
+		c.conn.Close()
+	}()
+}
+
+// Stats describes the queued frames and evictions so far
+type Stats struct {
+	Clients        int    `json:"clients"`
+	BufferedBytes  int    `json:"buffered_bytes"`
+	MaxClientQueue int    `json:"max_client_queue_bytes"` // largest queue of a single client
+	LimitBytes     int    `json:"limit_bytes"`            // 0 = unlimited
+	ClientLimit    int    `json:"client_limit_bytes"`     // 0 = unlimited
+	EvictedClient  uint64 `json:"evicted_client_limit"`   // clients over their own limit
+	EvictedTotal   uint64 `json:"evicted_total_limit"`    // slowest clients once the total was reached
+	DroppedFrames  uint64 `json:"dropped_frames"`         // frames still queued for evicted clients
+}
+
+// Stats returns the current usage
+func (p *Pool) Stats() Stats {
+	p.mu.Lock()
+	s := Stats{
+		Clients:       len(p.clients),
+		BufferedBytes: p.bytes,
+		LimitBytes:    p.maxBytes,
+		ClientLimit:   p.maxClientBytes,
+	}
+	for c := range p.clients {
+		if c.bytes > s.MaxClientQueue {
+			s.MaxClientQueue = c.bytes
+		}
+	}
+	p.mu.Unlock()
+
+	s.EvictedClient = p.evictedClient.Load()
+	s.EvictedTotal = p.evictedTotal.Load()
+	s.DroppedFrames = p.droppedFrames.Load()
+	return s
+}
+
+// ClientStats describes what was sent to one client
+type ClientStats struct {
+	SentFrames  uint64 `json:"sent_frames"`
+	SentBytes   uint64 `json:"sent_bytes"`
+	QueuedBytes int    `json:"queued_bytes"`
+}
+
+// Stats returns what was written to the client and what is still queued
+func (c *Client) Stats() ClientStats {
+	c.pool.mu.Lock()
+	queued := c.bytes
+	c.pool.mu.Unlock()
+
+	return ClientStats{
+		SentFrames:  c.sentFrames.Load(),
+		SentBytes:   c.sentBytes.Load(),
+		QueuedBytes: queued,
+	}
+}
+
+// WritePrometheus writes the usage gauges and eviction counters in the
+// Prometheus text format
+func (p *Pool) WritePrometheus(w io.Writer) {
+	s := p.Stats()
+	gauges := []struct {
+		name, help string
+		value      int
+	}{
+		{"polygo_ws_clients", "WebSocket clients with a send buffer", s.Clients},
+		{"polygo_ws_buffered_bytes", "Bytes of frames queued for WebSocket clients", s.BufferedBytes},
+		{"polygo_ws_client_buffered_bytes_max", "Bytes queued for the WebSocket client furthest behind", s.MaxClientQueue},
+		{"polygo_ws_buffer_limit_bytes", "Limit on bytes queued for all WebSocket clients (0 = unlimited)", s.LimitBytes},
+		{"polygo_ws_client_buffer_limit_bytes", "Limit on bytes queued for one WebSocket client (0 = unlimited)", s.ClientLimit},
+	}
+	for _, g := range gauges {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %d\n", g.name, g.help, g.name, g.name, g.value)
+	}
+
+	fmt.Fprintf(w, "# HELP polygo_ws_evictions_total WebSocket clients disconnected for exceeding a send buffer limit, by limit\n# TYPE polygo_ws_evictions_total counter\n")
+	fmt.Fprintf(w, "polygo_ws_evictions_total{reason=%q} %d\n", ReasonClientLimit, s.EvictedClient)
+	fmt.Fprintf(w, "polygo_ws_evictions_total{reason=%q} %d\n", ReasonTotalLimit, s.EvictedTotal)
+
+	fmt.Fprintf(w, "# HELP polygo_ws_dropped_frames_total Frames discarded with the queues of evicted WebSocket clients\n# TYPE polygo_ws_dropped_frames_total counter\n")
+	fmt.Fprintf(w, "polygo_ws_dropped_frames_total %d\n", s.DroppedFrames)
+}
@@ -0,0 +1,90 @@
+// Package wsfanout sends the frames of a feed to its WebSocket
+// subscribers. Each frame is encoded at most once per encoding and queued
+// on the subscribers' wsbuffer clients, so slow subscribers are held to the
+// same memory limits as the market streams.
+package wsfanout
+
+import (
+	"sync"
+
+	"github.com/polygo/internal/wsbuffer"
+	"github.com/polygo/internal/wsframe"
+)
+
+// Fanout is the subscribers of a feed, each with the feed's own state T,
+// such as a filter
+type Fanout[T any] struct {
+	mu          sync.RWMutex
+	subscribers map[*wsbuffer.Client]subscriber[T]
+}
+
+type subscriber[T any] struct {
+	enc   wsframe.Encoding
+	state T
+}
+
+// New creates a fan-out without subscribers
+func New[T any]() *Fanout[T] {
+	return &Fanout[T]{subscribers: make(map[*wsbuffer.Client]subscriber[T])}
+}
+
+// Add registers a subscriber receiving frames in enc
+func (f *Fanout[T]) Add(out *wsbuffer.Client, enc wsframe.Encoding, state T) {
+	f.mu.Lock()
+	f.subscribers[out] = subscriber[T]{enc: enc, state: state}
+	f.mu.Unlock()
+}
+
+// Remove forgets a subscriber
+func (f *Fanout[T]) Remove(out *wsbuffer.Client) {
+	f.mu.Lock()
+	delete(f.subscribers, out)
+	f.mu.Unlock()
+}
+
+// Len returns the number of subscribers
+func (f *Fanout[T]) Len() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return len(f.subscribers)
+}
+
+// Publish queues a JSON message for the subscribers whose state match
+// accepts, or for every subscriber when match is nil. A subscriber that
+// falls too far behind is evicted by its buffer pool rather than holding
+// up the feed.
+func (f *Fanout[T]) Publish(data []byte, match func(T) bool) {
+	frame := wsframe.NewMessage(data)
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for out, s := range f.subscribers {
+		if match != nil && !match(s.state) {
+			continue
+		}
+		if messageType, payload, err := frame.Frame(s.enc); err == nil {
+			out.Send(messageType, payload)
+		}
+	}
+}
+
+// Close closes every subscriber's client, which tells their handlers the
+// feed is gone, and forgets them
+func (f *Fanout[T]) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for out := range f.subscribers {
+		out.Close()
+		delete(f.subscribers, out)
+	}
+}
+
+// Send queues a JSON message for one client in enc. It reports false once
+// the client is closed.
+func Send(out *wsbuffer.Client, enc wsframe.Encoding, data []byte) bool {
+	messageType, payload, err := wsframe.NewMessage(data).Frame(enc)
+	if err != nil {
+		return true
+	}
+	return out.Send(messageType, payload)
+}
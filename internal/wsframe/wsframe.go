@@ -0,0 +1,176 @@
+package wsframe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"strconv"
+	"sync"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/websocket/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Encoding is a downstream WebSocket message encoding
+type Encoding string
+
+const (
+	// JSON sends text frames, the default
+	JSON Encoding = "json"
+	// Msgpack sends MessagePack binary frames with the same keys as JSON
+	Msgpack Encoding = "msgpack"
+)
+
+// ParseEncoding parses the ?encoding= query value; empty means JSON
+func ParseEncoding(s string) (Encoding, bool) {
+	switch Encoding(s) {
+	case "", JSON:
+		return JSON, true
+	case Msgpack:
+		return Msgpack, true
+	}
+	return "", false
+}
+
+// ToMsgpack re-encodes a JSON document as MessagePack
+func ToMsgpack(data []byte) ([]byte, error) {
+	var v interface{}
+	if err := sonic.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return msgpack.Marshal(v)
+}
+
+// Message is a JSON message that is converted to MessagePack at most once,
+// however many clients it is sent to
+type Message struct {
+	data []byte
+
+	once   sync.Once
+	packed []byte
+	err    error
+}
+
+// NewMessage wraps a JSON message
+func NewMessage(data []byte) *Message {
+	return &Message{data: data}
+}
+
+// JSON returns the message as wrapped
+func (m *Message) JSON() []byte {
+	return m.data
+}
+
+// Frame returns the WebSocket message type and payload for an encoding
+func (m *Message) Frame(enc Encoding) (int, []byte, error) {
+	if enc != Msgpack {
+		return websocket.TextMessage, m.data, nil
+	}
+	m.once.Do(func() {
+		m.packed, m.err = ToMsgpack(m.data)
+	})
+	return websocket.BinaryMessage, m.packed, m.err
+}
+
+// Write sends a JSON message to a connection in the given encoding
+func Write(c *websocket.Conn, enc Encoding, data []byte) error {
+	messageType, payload, err := NewMessage(data).Frame(enc)
+	if err != nil {
+		return err
+	}
+	return c.WriteMessage(messageType, payload)
+}
+
+// Stamp adds a subscription and its sequence number as the first fields of
+// a message encoded as by Frame, without decoding or copying it: the
+// stamped message is head followed by body, where body is the rest of data
+// after its JSON object or MessagePack map header. ok is false for other
+// payloads, which are not stamped.
+func Stamp(messageType int, data []byte, sub, seqKey string, seq uint64) (head, body []byte, ok bool) {
+	if messageType == websocket.BinaryMessage {
+		return stampMsgpack(data, sub, seqKey, seq)
+	}
+	return stampJSON(data, sub, seqKey, seq)
+}
+
+func stampJSON(data []byte, sub, seqKey string, seq uint64) ([]byte, []byte, bool) {
+	data = bytes.TrimSpace(data)
+	if len(data) < 2 || data[0] != '{' {
+		return nil, data, false
+	}
+	body := bytes.TrimLeft(data[1:], " \t\r\n")
+	quoted, err := sonic.Marshal(sub)
+	if err != nil {
+		return nil, data, false
+	}
+
+	head := make([]byte, 0, len(quoted)+len(seqKey)+32)
+	head = append(head, `{"sub":`...)
+	head = append(head, quoted...)
+	head = append(head, `,"`...)
+	head = append(head, seqKey...)
+	head = append(head, `":`...)
+	head = strconv.AppendUint(head, seq, 10)
+	if body[0] != '}' {
+		head = append(head, ',')
+	}
+	return head, body, true
+}
+
+// stampMsgpack writes a map header with two more entries and the new
+// entries into head
+func stampMsgpack(data []byte, sub, seqKey string, seq uint64) ([]byte, []byte, bool) {
+	if len(data) == 0 {
+		return nil, data, false
+	}
+
+	var count uint32
+	var body []byte
+	switch b := data[0]; {
+	case b&0xf0 == 0x80: // fixmap
+		count, body = uint32(b&0x0f), data[1:]
+	case b == 0xde && len(data) >= 3: // map 16
+		count, body = uint32(binary.BigEndian.Uint16(data[1:])), data[3:]
+	case b == 0xdf && len(data) >= 5: // map 32
+		count, body = binary.BigEndian.Uint32(data[1:]), data[5:]
+	default:
+		return nil, data, false
+	}
+	if count > math.MaxUint32-2 {
+		return nil, data, false
+	}
+
+	var head bytes.Buffer
+	enc := msgpack.NewEncoder(&head)
+	if err := errors.Join(
+		enc.EncodeMapLen(int(count+2)),
+		enc.EncodeString("sub"),
+		enc.EncodeString(sub),
+		enc.EncodeString(seqKey),
+		enc.EncodeUint(seq),
+	); err != nil {
+		return nil, data, false
+	}
+	return head.Bytes(), body, true
+}
+
+// StampJSON adds key as the first field of a JSON object, on a copy
+func StampJSON(data []byte, key string, n uint64) []byte {
+	data = bytes.TrimSpace(data)
+	if len(data) < 2 || data[0] != '{' {
+		return data
+	}
+	rest := bytes.TrimSpace(data[1:])
+
+	out := make([]byte, 0, len(data)+len(key)+24)
+	out = append(out, `{"`...)
+	out = append(out, key...)
+	out = append(out, `":`...)
+	out = strconv.AppendUint(out, n, 10)
+	if rest[0] != '}' {
+		out = append(out, ',')
+	}
+	return append(out, rest...)
+}
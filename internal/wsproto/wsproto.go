@@ -0,0 +1,150 @@
+// Package wsproto defines the downstream WebSocket protocol versions of the
+// market streams. Each connection picks one with ?version= when it
+// connects, so the message format can evolve without breaking clients
+// written against an older one.
+package wsproto
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+)
+
+// Version is a downstream protocol version
+type Version int
+
+const (
+	// V1 passes upstream messages through as received, with PolyGo's own
+	// messages (pong, gap, ...) alongside. The default.
+	V1 Version = 1
+	// V2 puts every message in an Envelope
+	V2 Version = 2
+)
+
+// ParseVersion parses the ?version= query value; empty means V1
+func ParseVersion(s string) (Version, bool) {
+	switch s {
+	case "", "1":
+		return V1, true
+	case "2":
+		return V2, true
+	}
+	return 0, false
+}
+
+// Envelope is a v2 message. Seq, the connection's sequence number, is
+// added as the first field when the envelope is sent.
+type Envelope struct {
+	Type      string          `json:"type"`                 // event_type of upstream messages, type of PolyGo's own
+	Token     string          `json:"token,omitempty"`      // the one token the message is about
+	Market    string          `json:"market,omitempty"`     // condition ID
+	StreamSeq uint64          `json:"stream_seq,omitempty"` // see ?since=
+	Timestamp int64           `json:"timestamp,omitempty"`  // unix ms, as sent upstream
+	Payload   json.RawMessage `json:"payload"`              // the v1 message, without stream_seq
+}
+
+// Wrapped is an encoded envelope with what it is routed by
+type Wrapped struct {
+	Token  string
+	Market string
+	Data   []byte
+}
+
+// Wrap returns the v2 envelopes of a v1 message: one per token for a price
+// change listing several tokens, with only that token's entries in its
+// payload, and one otherwise. Messages that are not JSON objects have none.
+func Wrap(data []byte) []Wrapped {
+	var fields map[string]json.RawMessage
+	if err := sonic.Unmarshal(data, &fields); err != nil || fields == nil {
+		return nil
+	}
+
+	env := Envelope{
+		Type:      str(fields["event_type"]),
+		Token:     str(fields["asset_id"]),
+		Market:    str(fields["market"]),
+		Timestamp: number(fields["timestamp"]),
+		Payload:   data,
+	}
+	if env.Type == "" {
+		env.Type = str(fields["type"])
+	}
+	if raw, ok := fields["stream_seq"]; ok {
+		env.StreamSeq = uint64(number(raw))
+		delete(fields, "stream_seq")
+		env.Payload = marshal(fields)
+	}
+
+	if changes := byToken(fields["price_changes"]); len(changes) > 0 {
+		out := make([]Wrapped, 0, len(changes))
+		for _, c := range changes {
+			fields["price_changes"] = marshal(c.entries)
+			env.Token = c.token
+			env.Payload = marshal(fields)
+			out = append(out, wrapped(env))
+		}
+		return out
+	}
+	return []Wrapped{wrapped(env)}
+}
+
+// tokenChanges are the price_changes entries of one token
+type tokenChanges struct {
+	token   string
+	entries []json.RawMessage
+}
+
+// byToken groups price_changes entries by asset_id, in order of first
+// appearance
+func byToken(raw json.RawMessage) []tokenChanges {
+	if len(raw) == 0 {
+		return nil
+	}
+	var entries []json.RawMessage
+	if err := sonic.Unmarshal(raw, &entries); err != nil {
+		return nil
+	}
+
+	var out []tokenChanges
+	index := make(map[string]int)
+	for _, e := range entries {
+		var entry struct {
+			AssetID string `json:"asset_id"`
+		}
+		sonic.Unmarshal(e, &entry)
+		i, ok := index[entry.AssetID]
+		if !ok {
+			i = len(out)
+			index[entry.AssetID] = i
+			out = append(out, tokenChanges{token: entry.AssetID})
+		}
+		out[i].entries = append(out[i].entries, e)
+	}
+	return out
+}
+
+func wrapped(env Envelope) Wrapped {
+	return Wrapped{Token: env.Token, Market: env.Market, Data: marshal(env)}
+}
+
+func marshal(v interface{}) []byte {
+	data, _ := sonic.Marshal(v)
+	return data
+}
+
+// str decodes a JSON string, or returns ""
+func str(raw json.RawMessage) string {
+	var s string
+	if len(raw) > 0 {
+		sonic.Unmarshal(raw, &s)
+	}
+	return s
+}
+
+// number decodes an integer sent as a JSON number or string, or returns 0
+func number(raw json.RawMessage) int64 {
+	n, _ := strconv.ParseInt(strings.Trim(string(raw), `"`), 10, 64)
+	return n
+}
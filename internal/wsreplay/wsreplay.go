@@ -0,0 +1,140 @@
+// Package wsreplay keeps the market channel messages of the last few
+// seconds, so a WebSocket client reconnecting after a short network blip
+// can be sent what it missed instead of resyncing over REST.
+//
+// Every message gets a stream-wide sequence number, added to its JSON as
+// stream_seq; clients reconnect with the last one they saw.
+package wsreplay
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/polygo/internal/wsframe"
+)
+
+// maxPerKey bounds the messages kept for one token or market, whatever the
+// window, so a burst cannot hold unbounded memory
+const maxPerKey = 1000
+
+// Entry is a buffered message
+type Entry struct {
+	Seq  uint64
+	Keys []string // tokens and market of the message
+	Data []byte   // the message with its stream_seq
+	Book bool     // a full order book, which delta clients get as snapshots instead
+}
+
+type entry struct {
+	Entry
+	at time.Time
+}
+
+// Buffer holds the recent messages of each token and market
+type Buffer struct {
+	window time.Duration
+
+	mu        sync.Mutex
+	seq       uint64              // last assigned
+	keys      map[string][]*entry // token or market -> messages, oldest first
+	dropped   uint64              // highest seq evicted
+	lastSweep time.Time
+}
+
+// New creates a buffer keeping messages for window; 0 disables it
+func New(window time.Duration) *Buffer {
+	return &Buffer{
+		window:    window,
+		keys:      make(map[string][]*entry),
+		lastSweep: time.Now(),
+	}
+}
+
+// Enabled reports whether messages are buffered
+func (b *Buffer) Enabled() bool {
+	return b.window > 0
+}
+
+// Add numbers a message, keeps it under each of its keys and returns it
+// with its stream_seq. Disabled buffers return data as is.
+func (b *Buffer) Add(keys []string, data []byte, book bool) []byte {
+	if !b.Enabled() {
+		return data
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	e := &entry{Entry: Entry{Seq: b.seq, Keys: keys, Data: wsframe.StampJSON(data, "stream_seq", b.seq), Book: book}, at: time.Now()}
+	cutoff := e.at.Add(-b.window)
+	for _, key := range keys {
+		b.keys[key] = b.prune(append(b.keys[key], e), cutoff)
+	}
+
+	// Keys that stopped receiving messages are only pruned here
+	if e.at.Sub(b.lastSweep) >= b.window {
+		b.lastSweep = e.at
+		for key, entries := range b.keys {
+			if entries = b.prune(entries, cutoff); len(entries) == 0 {
+				delete(b.keys, key)
+			} else {
+				b.keys[key] = entries
+			}
+		}
+	}
+	return e.Data
+}
+
+// prune drops entries older than cutoff or over maxPerKey
+func (b *Buffer) prune(entries []*entry, cutoff time.Time) []*entry {
+	n := 0
+	for n < len(entries) && (entries[n].at.Before(cutoff) || len(entries)-n > maxPerKey) {
+		if entries[n].Seq > b.dropped {
+			b.dropped = entries[n].Seq
+		}
+		n++
+	}
+	if n == 0 {
+		return entries
+	}
+	return append(entries[:0:0], entries[n:]...)
+}
+
+// Since returns the messages after seq for any of keys ("*" for all), in
+// order. It reports false when some messages after seq may be gone: they
+// were evicted, or seq is from before a restart.
+func (b *Buffer) Since(seq uint64, keys map[string]bool) ([]Entry, bool) {
+	if !b.Enabled() {
+		return nil, false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	seen := make(map[uint64]bool)
+	var out []Entry
+	collect := func(entries []*entry) {
+		// Entries are in order, so only the tail can be newer than seq
+		i := sort.Search(len(entries), func(i int) bool { return entries[i].Seq > seq })
+		for _, e := range entries[i:] {
+			if !seen[e.Seq] {
+				seen[e.Seq] = true
+				out = append(out, e.Entry)
+			}
+		}
+	}
+	if keys["*"] {
+		for _, entries := range b.keys {
+			collect(entries)
+		}
+	} else {
+		for key := range keys {
+			collect(b.keys[key])
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Seq < out[j].Seq })
+
+	return out, seq >= b.dropped && seq <= b.seq
+}
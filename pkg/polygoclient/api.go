@@ -0,0 +1,151 @@
+package polygoclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// MarketsQuery filters GetMarkets
+type MarketsQuery struct {
+	Limit       int
+	Offset      int
+	Active      *bool
+	Closed      *bool
+	Slug        string
+	EventSlug   string
+	ClobTokenID string
+}
+
+// values encodes the query for /api/v1/markets
+func (q *MarketsQuery) values() url.Values {
+	v := url.Values{}
+	if q == nil {
+		return v
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if q.Offset > 0 {
+		v.Set("offset", strconv.Itoa(q.Offset))
+	}
+	if q.Active != nil {
+		v.Set("active", strconv.FormatBool(*q.Active))
+	}
+	if q.Closed != nil {
+		v.Set("closed", strconv.FormatBool(*q.Closed))
+	}
+	if q.Slug != "" {
+		v.Set("slug", q.Slug)
+	}
+	if q.EventSlug != "" {
+		v.Set("event_slug", q.EventSlug)
+	}
+	if q.ClobTokenID != "" {
+		v.Set("clob_token_id", q.ClobTokenID)
+	}
+	return v
+}
+
+// PlaceOrderResponse is the CLOB's answer to an order placement
+type PlaceOrderResponse struct {
+	Success  bool   `json:"success"`
+	ErrorMsg string `json:"errorMsg"`
+	OrderID  string `json:"orderID"`
+	Status   string `json:"status"`
+}
+
+// CancelResponse lists the orders the CLOB cancelled or refused to cancel
+type CancelResponse struct {
+	Canceled    []string          `json:"canceled"`
+	NotCanceled map[string]string `json:"not_canceled"`
+}
+
+// GetMarkets lists markets
+func (c *Client) GetMarkets(ctx context.Context, q *MarketsQuery) ([]Market, error) {
+	path := "/api/v1/markets"
+	if v := q.values(); len(v) > 0 {
+		path += "?" + v.Encode()
+	}
+
+	var markets []Market
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &markets); err != nil {
+		return nil, err
+	}
+	return markets, nil
+}
+
+// GetMarket returns a market by ID
+func (c *Client) GetMarket(ctx context.Context, id string) (*Market, error) {
+	var market Market
+	if err := c.do(ctx, http.MethodGet, "/api/v1/markets/"+url.PathEscape(id), nil, nil, &market); err != nil {
+		return nil, err
+	}
+	return &market, nil
+}
+
+// GetOrderBook returns the order book for a token
+func (c *Client) GetOrderBook(ctx context.Context, tokenID string) (*OrderBook, error) {
+	var book OrderBook
+	if err := c.do(ctx, http.MethodGet, "/api/v1/book/"+url.PathEscape(tokenID), nil, nil, &book); err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+// GetMidpoint returns the midpoint price for a token
+func (c *Client) GetMidpoint(ctx context.Context, tokenID string) (float64, error) {
+	var mid struct {
+		Mid string `json:"mid"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/midpoint/"+url.PathEscape(tokenID), nil, nil, &mid); err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(mid.Mid, 64)
+}
+
+// GetOpenOrders returns the caller's open orders, optionally for one market
+func (c *Client) GetOpenOrders(ctx context.Context, market string) ([]Order, error) {
+	path := "/api/v1/orders/open"
+	upstream := "/orders/open"
+	if market != "" {
+		path += "?market=" + url.QueryEscape(market)
+	}
+
+	var orders []Order
+	if err := c.do(ctx, http.MethodGet, path, nil, &signedRequest{http.MethodGet, upstream}, &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// PlaceOrder submits an order. It is never retried, so a timeout leaves
+// the outcome unknown; check GetOpenOrders before resubmitting.
+func (c *Client) PlaceOrder(ctx context.Context, order *CreateOrderRequest) (*PlaceOrderResponse, error) {
+	var resp PlaceOrderResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/orders", order, &signedRequest{http.MethodPost, "/order"}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CancelOrder cancels an order by ID
+func (c *Client) CancelOrder(ctx context.Context, orderID string) (*CancelResponse, error) {
+	var resp CancelResponse
+	upstream := "/order/" + orderID
+	if err := c.do(ctx, http.MethodDelete, "/api/v1/orders/"+url.PathEscape(orderID), nil, &signedRequest{http.MethodDelete, upstream}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PurgeCache drops one response cache entry, or the whole cache when key
+// is empty. Requires WithAdminToken when the server has one configured.
+func (c *Client) PurgeCache(ctx context.Context, key string) error {
+	path := "/admin/cache"
+	if key != "" {
+		path += "?key=" + url.QueryEscape(key)
+	}
+	return c.do(ctx, http.MethodDelete, path, nil, nil, nil)
+}
@@ -0,0 +1,52 @@
+package polygoclient
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Header names PolyGo forwards to the CLOB for authenticated requests
+const (
+	HeaderAPIKey     = "POLY-API-KEY"
+	HeaderAPISecret  = "POLY-API-SECRET"
+	HeaderPassphrase = "POLY-PASSPHRASE"
+	HeaderSignature  = "POLY-SIGNATURE"
+	HeaderTimestamp  = "POLY-TIMESTAMP"
+)
+
+// Credentials are Polymarket L2 API credentials. Requests are signed
+// client-side; the secret itself is never sent.
+type Credentials struct {
+	APIKey     string
+	Secret     string // base64url encoded, as issued by Polymarket
+	Passphrase string
+}
+
+// Sign returns the L2 signature for an upstream request: an HMAC-SHA256
+// over timestamp, method, path and body, keyed by the decoded secret
+func (c *Credentials) Sign(timestamp, method, path string, body []byte) string {
+	key, err := base64.URLEncoding.DecodeString(c.Secret)
+	if err != nil {
+		key = []byte(c.Secret)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(timestamp + method + path))
+	mac.Write(body)
+	return base64.URLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// apply sets the auth headers for a request that PolyGo relays as
+// method path upstream. PolyGo matches header names case-sensitively, so
+// they bypass net/http's canonicalization.
+func (c *Credentials) apply(h http.Header, method, path string, body []byte) {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	h[HeaderAPIKey] = []string{c.APIKey}
+	h[HeaderPassphrase] = []string{c.Passphrase}
+	h[HeaderTimestamp] = []string{ts}
+	h[HeaderSignature] = []string{c.Sign(ts, method, path, body)}
+}
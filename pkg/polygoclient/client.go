@@ -0,0 +1,292 @@
+// Package polygoclient is a typed Go client for a PolyGo deployment.
+//
+//	c := polygoclient.New("http://localhost:8080",
+//		polygoclient.WithCredentials(polygoclient.Credentials{APIKey: key, Secret: secret, Passphrase: pass}))
+//	book, err := c.GetOrderBook(ctx, tokenID)
+package polygoclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/polygo/internal/models"
+)
+
+// Types shared with the server
+type (
+	Market             = models.Market
+	OrderBook          = models.OrderBook
+	PriceLevel         = models.PriceLevel
+	Order              = models.Order
+	CreateOrderRequest = models.CreateOrderRequest
+	Side               = models.Side
+	OrderType          = models.OrderType
+)
+
+// Order sides and types
+const (
+	Buy  = models.SideBuy
+	Sell = models.SideSell
+
+	GTC = models.OrderTypeGTC
+	FOK = models.OrderTypeFOK
+	GTD = models.OrderTypeGTD
+)
+
+// Client talks to a PolyGo instance over HTTP and WebSocket
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	creds       *Credentials
+	adminToken  string
+	accessToken string
+	userAgent   string
+	retries     int
+	retryWait   time.Duration
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithCredentials sets the L2 API credentials used for order endpoints
+func WithCredentials(creds Credentials) Option {
+	return func(c *Client) { c.creds = &creds }
+}
+
+// WithAdminToken sets the bearer token for /admin endpoints
+func WithAdminToken(token string) Option {
+	return func(c *Client) { c.adminToken = token }
+}
+
+// WithAccessToken sets the access key or JWT sent as the bearer token when
+// the server enforces roles. The admin token, if set, is sent to /admin
+// endpoints instead.
+func WithAccessToken(token string) Option {
+	return func(c *Client) { c.accessToken = token }
+}
+
+// WithRetries sets how many times idempotent requests are retried and the
+// first backoff, which doubles per attempt
+func WithRetries(n int, wait time.Duration) Option {
+	return func(c *Client) { c.retries, c.retryWait = n, wait }
+}
+
+// WithUserAgent sets the User-Agent header
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua }
+}
+
+// New creates a client for the PolyGo instance at baseURL
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		userAgent:  "polygoclient/1.0",
+		retries:    3,
+		retryWait:  200 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError is an error response from PolyGo or the upstream behind it
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Details    string
+	// Retryable reports whether the request may succeed if sent again,
+	// after RetryAfter when the server said how long to wait
+	Retryable  bool
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("polygo: %d", e.StatusCode)
+	if e.Code != "" {
+		msg += " " + e.Code
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.Details != "" {
+		msg += " (" + e.Details + ")"
+	}
+	return msg
+}
+
+// envelope is PolyGo's standard response wrapper. Endpoints that relay
+// upstream payloads return them unwrapped; those may carry a "success"
+// field of their own, so the timestamp is what identifies an envelope.
+type envelope struct {
+	Success   *bool           `json:"success"`
+	Timestamp *int64          `json:"timestamp"`
+	Data      json.RawMessage `json:"data"`
+	Error     *struct {
+		Code         string `json:"code"`
+		Message      string `json:"message"`
+		Details      string `json:"details"`
+		Retryable    *bool  `json:"retryable"`
+		RetryAfterMs int64  `json:"retry_after_ms"`
+	} `json:"error"`
+}
+
+// isEnvelope reports whether a decoded body is a PolyGo envelope
+func (e *envelope) isEnvelope() bool {
+	return e.Success != nil && e.Timestamp != nil && (e.Data != nil || e.Error != nil)
+}
+
+// signedRequest describes the upstream call a request maps to, so
+// credentials can sign it the way Polymarket verifies it
+type signedRequest struct {
+	method string
+	path   string
+}
+
+// do sends a request and decodes the (possibly wrapped) response into out
+func (c *Client) do(ctx context.Context, method, path string, body interface{}, sign *signedRequest, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	retryable := method == http.MethodGet || method == http.MethodDelete
+	for attempt := 0; ; attempt++ {
+		data, status, retryAfter, err := c.send(ctx, method, path, payload, sign)
+		if err == nil && status < 300 {
+			return decode(data, out)
+		}
+		transient := status == 0
+		if err == nil {
+			apiErr := apiError(status, data, retryAfter)
+			transient, retryAfter, err = apiErr.Retryable, apiErr.RetryAfter, apiErr
+		}
+		if !retryable || !transient || attempt >= c.retries {
+			return err
+		}
+
+		wait := retryAfter
+		if wait == 0 {
+			wait = c.retryWait << attempt
+			wait = wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// send performs a single HTTP round trip
+func (c *Client) send(ctx context.Context, method, path string, payload []byte, sign *signedRequest) ([]byte, int, time.Duration, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.adminToken != "" && strings.HasPrefix(path, "/admin/") {
+		req.Header.Set("Authorization", "Bearer "+c.adminToken)
+	} else if c.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	}
+	if sign != nil {
+		if c.creds == nil {
+			return nil, 0, 0, fmt.Errorf("polygo: %s %s requires credentials", method, path)
+		}
+		c.creds.apply(req.Header, sign.method, sign.path, payload)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	var retryAfter time.Duration
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		retryAfter = time.Duration(secs) * time.Second
+	}
+	return data, resp.StatusCode, retryAfter, nil
+}
+
+// decode unwraps PolyGo's envelope when present and decodes into out
+func decode(data []byte, out interface{}) error {
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+
+	var env envelope
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) && json.Unmarshal(data, &env) == nil && env.isEnvelope() {
+		if !*env.Success {
+			return apiError(http.StatusOK, data, 0)
+		}
+		data = env.Data
+	}
+	return json.Unmarshal(data, out)
+}
+
+// apiError builds an APIError from an error response body and its Retry-After
+func apiError(status int, data []byte, retryAfter time.Duration) *APIError {
+	e := &APIError{
+		StatusCode: status,
+		Retryable:  status == http.StatusTooManyRequests || status >= 500,
+		RetryAfter: retryAfter,
+	}
+
+	var env envelope
+	if json.Unmarshal(data, &env) == nil && env.isEnvelope() && env.Error != nil {
+		e.Code, e.Message, e.Details = env.Error.Code, env.Error.Message, env.Error.Details
+		// Servers that send retry hints know better than the status
+		if env.Error.Retryable != nil {
+			e.Retryable = *env.Error.Retryable
+		}
+		if env.Error.RetryAfterMs > 0 {
+			e.RetryAfter = time.Duration(env.Error.RetryAfterMs) * time.Millisecond
+		}
+		return e
+	}
+
+	// Relayed upstream errors are usually {"error": "..."}
+	var upstream struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(data, &upstream) == nil && upstream.Error != "" {
+		e.Message = upstream.Error
+	} else {
+		e.Message = strings.TrimSpace(string(data))
+	}
+	return e
+}
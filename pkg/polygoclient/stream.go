@@ -0,0 +1,198 @@
+package polygoclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// MarketEvent is one update from a market stream. Fields that do not apply
+// to the event type are left empty; Raw holds the original message.
+type MarketEvent struct {
+	EventType string       `json:"event_type"` // book, book_delta, price_change, last_trade_price, tick_size_change
+	AssetID   string       `json:"asset_id"`
+	Market    string       `json:"market"`
+	Seq       uint64       `json:"seq,omitempty"`        // per token, book and book_delta only
+	StreamSeq uint64       `json:"stream_seq,omitempty"` // across the stream, used to resume after a reconnect
+	Bids      []PriceLevel `json:"bids,omitempty"`
+	Asks      []PriceLevel `json:"asks,omitempty"`
+	Price     string       `json:"price,omitempty"`
+	Side      Side         `json:"side,omitempty"`
+	Size      string       `json:"size,omitempty"`
+	Hash      string       `json:"hash,omitempty"`
+	Timestamp json.Number  `json:"timestamp,omitempty"`
+
+	Raw json.RawMessage `json:"-"`
+}
+
+// streamBuffer is how many events a slow reader may fall behind before the
+// stream blocks
+const streamBuffer = 64
+
+// SubscribeMarket streams updates for a market over /ws/market/{id}. The
+// connection is re-established with backoff when it drops, asking for the
+// updates missed meanwhile; the channel is closed once ctx is cancelled.
+func (c *Client) SubscribeMarket(ctx context.Context, marketID string) (<-chan MarketEvent, error) {
+	return c.subscribe(ctx, "/ws/market/"+url.PathEscape(marketID))
+}
+
+// SubscribeToken streams updates for one outcome token over
+// /ws/token/{id}, like SubscribeMarket. Price changes still list every
+// token of the market that changed.
+func (c *Client) SubscribeToken(ctx context.Context, tokenID string) (<-chan MarketEvent, error) {
+	return c.subscribe(ctx, "/ws/token/"+url.PathEscape(tokenID))
+}
+
+// subscribe streams the events of a WebSocket endpoint, reconnecting
+func (c *Client) subscribe(ctx context.Context, path string) (<-chan MarketEvent, error) {
+	wsURL, err := c.wsURL(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dial once up front so bad URLs and refused connections surface here
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, wsURL, c.wsHeader())
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan MarketEvent, streamBuffer)
+	go func() {
+		defer close(events)
+
+		wait := c.retryWait
+		var last uint64 // stream_seq of the last event
+		for {
+			if conn != nil {
+				wait = c.retryWait
+				if seq := c.readEvents(ctx, conn, events); seq != 0 {
+					last = seq
+				}
+				conn = nil
+			}
+			if ctx.Err() != nil {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+			if wait < 30*time.Second {
+				wait *= 2
+			}
+
+			conn, _, _ = websocket.DefaultDialer.DialContext(ctx, resumeURL(wsURL, last), c.wsHeader())
+		}
+	}()
+
+	return events, nil
+}
+
+// readEvents decodes messages from conn into events until the connection
+// fails or ctx is cancelled. It returns the last stream_seq seen, or 0.
+func (c *Client) readEvents(ctx context.Context, conn *websocket.Conn, events chan<- MarketEvent) uint64 {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+			conn.Close()
+		case <-done:
+			conn.Close()
+		}
+	}()
+
+	var last uint64
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return last
+		}
+		for _, ev := range decodeEvents(data) {
+			select {
+			case events <- ev:
+				if ev.StreamSeq != 0 {
+					last = ev.StreamSeq
+				}
+			case <-ctx.Done():
+				return last
+			}
+		}
+	}
+}
+
+// resumeURL asks the server to replay the events after seq, if any
+func resumeURL(wsURL string, seq uint64) string {
+	if seq == 0 {
+		return wsURL
+	}
+	u, err := url.Parse(wsURL)
+	if err != nil {
+		return wsURL
+	}
+	q := u.Query()
+	q.Set("since", strconv.FormatUint(seq, 10))
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// decodeEvents parses a message that holds a single event or an array of them
+func decodeEvents(data []byte) []MarketEvent {
+	data = bytes.TrimSpace(data)
+
+	var raws []json.RawMessage
+	if bytes.HasPrefix(data, []byte("[")) {
+		if json.Unmarshal(data, &raws) != nil {
+			return nil
+		}
+	} else {
+		raws = []json.RawMessage{data}
+	}
+
+	events := make([]MarketEvent, 0, len(raws))
+	for _, raw := range raws {
+		var ev MarketEvent
+		if json.Unmarshal(raw, &ev) != nil || ev.EventType == "" {
+			// pongs and control messages
+			continue
+		}
+		ev.Raw = raw
+		events = append(events, ev)
+	}
+	return events
+}
+
+// wsURL maps the HTTP base URL onto the ws/wss scheme
+func (c *Client) wsURL(path string) (string, error) {
+	u, err := url.Parse(c.baseURL + path)
+	if err != nil {
+		return "", err
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "https":
+		u.Scheme = "wss"
+	case "http":
+		u.Scheme = "ws"
+	}
+	return u.String(), nil
+}
+
+// wsHeader returns the handshake headers
+func (c *Client) wsHeader() http.Header {
+	h := http.Header{"User-Agent": {c.userAgent}}
+	if c.accessToken != "" {
+		h.Set("Authorization", "Bearer "+c.accessToken)
+	}
+	return h
+}
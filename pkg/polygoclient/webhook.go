@@ -0,0 +1,72 @@
+package polygoclient
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header names of signed webhook deliveries
+const (
+	HeaderWebhookSignature = "X-PolyGo-Signature"
+	HeaderWebhookTimestamp = "X-PolyGo-Timestamp"
+)
+
+// DefaultWebhookTolerance is how old a delivery VerifyWebhook accepts by
+// default; older ones may be replays
+const DefaultWebhookTolerance = 5 * time.Minute
+
+var (
+	// ErrWebhookSignature is returned for a delivery without a valid
+	// signature for the secret
+	ErrWebhookSignature = errors.New("invalid webhook signature")
+	// ErrWebhookTimestamp is returned for a delivery signed too long ago,
+	// or with no timestamp
+	ErrWebhookTimestamp = errors.New("webhook timestamp outside tolerance")
+)
+
+// SignWebhook returns the signature PolyGo sends in X-PolyGo-Signature:
+// v1= and the hex HMAC-SHA256 of "<timestamp>.<body>" keyed by the
+// subscription's secret, timestamp in Unix seconds
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook checks that a webhook delivery was signed with secret no
+// longer than tolerance ago (DefaultWebhookTolerance when 0). body must be
+// the raw request body, read before any decoding.
+//
+//	body, _ := io.ReadAll(r.Body)
+//	if err := polygoclient.VerifyWebhook(secret, r.Header, body, 0); err != nil {
+//		http.Error(w, err.Error(), http.StatusUnauthorized)
+//		return
+//	}
+func VerifyWebhook(secret string, header http.Header, body []byte, tolerance time.Duration) error {
+	if tolerance <= 0 {
+		tolerance = DefaultWebhookTolerance
+	}
+	ts, err := strconv.ParseInt(header.Get(HeaderWebhookTimestamp), 10, 64)
+	if err != nil {
+		return ErrWebhookTimestamp
+	}
+	if age := time.Since(time.Unix(ts, 0)); age > tolerance || age < -tolerance {
+		return ErrWebhookTimestamp
+	}
+
+	want := SignWebhook(secret, ts, body)
+	// Several signatures may be sent, comma-separated
+	for _, sig := range strings.Split(header.Get(HeaderWebhookSignature), ",") {
+		if hmac.Equal([]byte(strings.TrimSpace(sig)), []byte(want)) {
+			return nil
+		}
+	}
+	return ErrWebhookSignature
+}
@@ -1,10 +1,14 @@
 package response
 
 import (
+	"io"
+	"strconv"
 	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/gofiber/fiber/v2"
+	"github.com/polygo/pkg/validate"
+	"github.com/valyala/fasthttp"
 )
 
 // Response represents a standardized API response
@@ -16,11 +20,19 @@ type Response struct {
 	Timestamp int64       `json:"timestamp"`
 }
 
-// ErrorInfo contains error details
+// ErrorInfo contains error details. Upstream failures use the codes in
+// polymarket (UPSTREAM_*, MARKET_CLOSED, ORDER_REJECTED_*), with
+// Polymarket's own message in Details.
 type ErrorInfo struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 	Details string `json:"details,omitempty"`
+	// Fields lists every invalid field of a rejected request body
+	Fields []validate.FieldError `json:"fields,omitempty"`
+	// Retryable is set when the same request may succeed later (rate
+	// limits, upstream outages), after RetryAfterMs when known
+	Retryable    bool  `json:"retryable"`
+	RetryAfterMs int64 `json:"retry_after_ms,omitempty"`
 }
 
 // Meta contains metadata for paginated responses
@@ -30,6 +42,8 @@ type Meta struct {
 	Total      int    `json:"total,omitempty"`
 	CacheHit   bool   `json:"cache_hit,omitempty"`
 	LatencyMs  int64  `json:"latency_ms,omitempty"`
+	// CursorRefreshed is set when auto-pagination restarted after upstream expired a cursor
+	CursorRefreshed bool `json:"cursor_refreshed,omitempty"`
 }
 
 // Pre-allocated byte slices for common responses
@@ -81,6 +95,30 @@ func Error(c *fiber.Ctx, status int, code, message, details string) error {
 	return c.Status(status).Send(body)
 }
 
+// Retry sends an error response the client may retry. A known retryAfter
+// is reported in retry_after_ms and the Retry-After header.
+func Retry(c *fiber.Ctx, status int, code, message, details string, retryAfter time.Duration) error {
+	resp := Response{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:      code,
+			Message:   message,
+			Details:   details,
+			Retryable: true,
+		},
+		Timestamp: time.Now().UnixMilli(),
+	}
+	if retryAfter > 0 {
+		resp.Error.RetryAfterMs = retryAfter.Milliseconds()
+		// Retry-After counts whole seconds: round up so clients never retry early
+		c.Set(fiber.HeaderRetryAfter, strconv.FormatInt(int64((retryAfter+time.Second-1)/time.Second), 10))
+	}
+	
+	body, _ := sonic.Marshal(resp)
+	c.Set("Content-Type", "application/json")
+	return c.Status(status).Send(body)
+}
+
 // BadRequest sends a 400 error response
 func BadRequest(c *fiber.Ctx, message string) error {
 	return Error(c, fiber.StatusBadRequest, "BAD_REQUEST", message, "")
@@ -101,9 +139,52 @@ func InternalError(c *fiber.Ctx, err error) error {
 	return Error(c, fiber.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error", err.Error())
 }
 
-// TooManyRequests sends a 429 error response
-func TooManyRequests(c *fiber.Ctx) error {
-	return Error(c, fiber.StatusTooManyRequests, "RATE_LIMITED", "Too many requests", "Please slow down")
+// ValidationFailed sends a 400 error response listing every invalid field;
+// details describes the first
+func ValidationFailed(c *fiber.Ctx, errs validate.Errors) error {
+	resp := Response{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    "VALIDATION_FAILED",
+			Message: "Request validation failed",
+			Fields:  errs,
+		},
+		Timestamp: time.Now().UnixMilli(),
+	}
+	if len(errs) > 0 {
+		resp.Error.Details = errs[0].Message
+	}
+	
+	body, _ := sonic.Marshal(resp)
+	c.Set("Content-Type", "application/json")
+	return c.Status(fiber.StatusBadRequest).Send(body)
+}
+
+// PayloadTooLarge sends a 413 error response
+func PayloadTooLarge(c *fiber.Ctx, limit int) error {
+	return Error(c, fiber.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE", "Request body is too large", "limit is "+strconv.Itoa(limit)+" bytes")
+}
+
+// TooManyRequests sends a 429 error response, retryable after retryAfter
+func TooManyRequests(c *fiber.Ctx, retryAfter time.Duration) error {
+	return Retry(c, fiber.StatusTooManyRequests, "RATE_LIMITED", "Too many requests", "Please slow down", retryAfter)
+}
+
+// NextLink sets an RFC 5988 Link header pointing at the next page: the
+// current request with its cursor replaced. Nothing is set for an empty cursor.
+func NextLink(c *fiber.Ctx, cursor string) {
+	if cursor == "" {
+		return
+	}
+	
+	args := fasthttp.AcquireArgs()
+	defer fasthttp.ReleaseArgs(args)
+	c.Request().URI().QueryArgs().CopyTo(args)
+	args.Del("next_cursor")
+	args.Del("offset")
+	args.Set("cursor", cursor)
+	
+	c.Append("Link", "<"+c.BaseURL()+c.Path()+"?"+args.String()+`>; rel="next"`)
 }
 
 // Raw sends raw JSON bytes directly (zero-copy for cached responses)
@@ -112,6 +193,19 @@ func Raw(c *fiber.Ctx, body []byte) error {
 	return c.Send(body)
 }
 
+// Stream sends a raw JSON body read from body as it is written, chunked
+// when size is -1. body is closed afterwards when it is an io.Closer.
+func Stream(c *fiber.Ctx, body io.Reader, size int) error {
+	c.Set("Content-Type", "application/json")
+	return c.SendStream(body, size)
+}
+
+// Protobuf sends a protobuf-encoded message, without the JSON envelope
+func Protobuf(c *fiber.Ctx, body []byte) error {
+	c.Set("Content-Type", "application/x-protobuf")
+	return c.Send(body)
+}
+
 // RawWithCacheHeader sends raw JSON with cache indicator
 func RawWithCacheHeader(c *fiber.Ctx, body []byte, cacheHit bool) error {
 	c.Set("Content-Type", "application/json")
@@ -122,3 +216,4 @@ func RawWithCacheHeader(c *fiber.Ctx, body []byte, cacheHit bool) error {
 	}
 	return c.Send(body)
 }
+
@@ -0,0 +1,190 @@
+// Package validate checks values against their `validate` struct tags with
+// go-playground/validator, and reports every invalid field by its JSON
+// path, e.g. legs[1].price, with a message a caller can show as is.
+//
+// Besides validator's built-in rules it registers PolyGo's own: eth_addr
+// (a 0x-prefixed 20-byte hex wallet address, the check behind Address)
+// and market_id (a market, condition or token ID safe to put in an
+// upstream URL).
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError is one rule a field failed
+type FieldError struct {
+	Field   string `json:"field"` // JSON path, e.g. legs[1].price
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Errors lists every rule that failed, in field order
+type Errors []FieldError
+
+// Error implements error
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, f := range e {
+		msgs[i] = f.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+var (
+	addressPattern  = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	marketIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,100}$`)
+)
+
+// ErrInvalidAddress is returned for anything but a 0x-prefixed 20-byte hex address
+var ErrInvalidAddress = errors.New("invalid wallet address")
+
+// IsAddress reports whether s is a 0x-prefixed 20-byte hex wallet address
+func IsAddress(s string) bool {
+	return addressPattern.MatchString(s)
+}
+
+// Address returns ErrInvalidAddress unless s is a wallet address
+func Address(s string) error {
+	if !IsAddress(s) {
+		return ErrInvalidAddress
+	}
+	return nil
+}
+
+// IsMarketID reports whether s can be a Polymarket market, condition or
+// token ID: 1 to 100 letters, digits, dots, dashes or underscores
+func IsMarketID(s string) bool {
+	return marketIDPattern.MatchString(s)
+}
+
+// validate is shared; validator caches each struct type's parsed tags
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	// Paths use the JSON names callers sent
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			return f.Name
+		}
+		return name
+	})
+	v.RegisterValidation("eth_addr", func(fl validator.FieldLevel) bool {
+		return IsAddress(fl.Field().String())
+	})
+	v.RegisterValidation("market_id", func(fl validator.FieldLevel) bool {
+		return IsMarketID(fl.Field().String())
+	})
+	return v
+}
+
+// Struct validates v, a struct or pointer to one, returning nil when it is valid
+func Struct(v interface{}) Errors {
+	return Var(v, "")
+}
+
+// Var validates v under the JSON path name. Slices and arrays are
+// validated element by element as name[i].
+func Var(v interface{}, name string) Errors {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+
+	var errs Errors
+	if val.Kind() == reflect.Slice || val.Kind() == reflect.Array {
+		for i := 0; i < val.Len(); i++ {
+			errs = append(errs, structErrors(val.Index(i), index(name, i))...)
+		}
+		return errs
+	}
+	return structErrors(val, name)
+}
+
+// structErrors validates a struct, naming its fields under path
+func structErrors(val reflect.Value, path string) Errors {
+	for val.Kind() == reflect.Pointer || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var failed validator.ValidationErrors
+	if !errors.As(validate.Struct(val.Interface()), &failed) {
+		return nil
+	}
+	errs := make(Errors, len(failed))
+	for i, fe := range failed {
+		// The namespace starts with the struct's type name
+		_, field, _ := strings.Cut(fe.Namespace(), ".")
+		field = join(path, field)
+		errs[i] = FieldError{Field: field, Rule: fe.Tag(), Param: fe.Param(), Message: field + " " + message(fe)}
+	}
+	return errs
+}
+
+// message says what is wrong with a field in words
+func message(fe validator.FieldError) string {
+	param := fe.Param()
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(param), ", ")
+	case "numeric":
+		return "must be a number"
+	case "eth_addr":
+		return "must be a 0x-prefixed 20-byte hex address"
+	case "market_id":
+		return "must be a market or token ID"
+	case "len":
+		return size(fe, "exactly", param)
+	case "min", "gte":
+		return size(fe, "at least", param)
+	case "max", "lte":
+		return size(fe, "at most", param)
+	case "gt":
+		return size(fe, "more than", param)
+	case "lt":
+		return size(fe, "less than", param)
+	}
+	return "failed " + fe.Tag()
+}
+
+// size words a size rule: a length for strings and collections, else a value
+func size(fe validator.FieldError, what, param string) string {
+	switch fe.Kind() {
+	case reflect.String:
+		return "must have " + what + " " + param + " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "must have " + what + " " + param + " items"
+	}
+	return "must be " + what + " " + param
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func index(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
@@ -1,8 +1,13 @@
 package integration
 
 import (
+	"fmt"
 	"io"
+	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -14,11 +19,34 @@ import (
 	"github.com/polygo/internal/api"
 	"github.com/polygo/internal/cache"
 	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/docs"
+	"github.com/polygo/internal/mockupstream"
 )
 
 func setupTestServer(t *testing.T) *fiber.App {
+	app, _ := setupMockedServer(t, nil)
+	return app
+}
+
+// setupMockedServer builds a server whose upstreams are a fresh mock
+func setupMockedServer(t *testing.T, mutate func(*config.Config)) (*fiber.App, *mockupstream.Server) {
+	app, mock, _ := setupMockedServerWithCache(t, mutate)
+	return app, mock
+}
+
+// setupMockedServerWithCache is setupMockedServer for tests that wait on
+// the cache, whose sets land asynchronously
+func setupMockedServerWithCache(t *testing.T, mutate func(*config.Config)) (*fiber.App, *mockupstream.Server, *cache.Cache) {
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+
 	cfg := config.DefaultConfig()
 	cfg.Server.Debug = true
+	mock.Apply(&cfg.Polymarket)
+	scratchState(t, cfg)
+	if mutate != nil {
+		mutate(cfg)
+	}
 
 	c, err := cache.New(&cfg.Cache)
 	require.NoError(t, err)
@@ -26,7 +54,36 @@ func setupTestServer(t *testing.T) *fiber.App {
 	server, err := api.NewServer(cfg, c)
 	require.NoError(t, err)
 
-	return server.GetApp()
+	return server.GetApp(), mock, c
+}
+
+// scratchState points the files a server persists its state to at a
+// directory removed after the test, rather than ./data under the tests
+func scratchState(tb testing.TB, cfg *config.Config) {
+	dir := tb.TempDir()
+	cfg.Leaderboard.Path = filepath.Join(dir, "leaderboard.json")
+	cfg.Risk.Path = filepath.Join(dir, "risk.json")
+	cfg.Export.Path = filepath.Join(dir, "exports.json")
+	cfg.Export.Dir = filepath.Join(dir, "exports")
+	cfg.Rules.Path = filepath.Join(dir, "rules.json")
+	cfg.Tenants.UsagePath = filepath.Join(dir, "tenant_usage.json")
+	cfg.Watchlist.Path = filepath.Join(dir, "watchlists.json")
+	cfg.PositionAlerts.Path = filepath.Join(dir, "position_alerts.json")
+	cfg.Equity.Path = filepath.Join(dir, "equity.json")
+	cfg.Strategies.Path = filepath.Join(dir, "strategies.json")
+	cfg.Tape.Dir = filepath.Join(dir, "tape")
+	cfg.Audit.Path = filepath.Join(dir, "audit.jsonl")
+}
+
+// clobWrites returns the requests sent to the CLOB other than lookups
+func clobWrites(mock *mockupstream.Server) []mockupstream.Request {
+	var writes []mockupstream.Request
+	for _, r := range mock.Requests(mockupstream.CLOB) {
+		if r.Method != "GET" {
+			writes = append(writes, r)
+		}
+	}
+	return writes
 }
 
 func TestHealthEndpoint(t *testing.T) {
@@ -56,6 +113,46 @@ func TestReadyEndpoint(t *testing.T) {
 	assert.Equal(t, 200, resp.StatusCode)
 }
 
+func TestReadyEndpoint_FailsWhileUpstreamUnreachableBeyondThreshold(t *testing.T) {
+	var clobStatus atomic.Int64
+	clobStatus.Store(http.StatusOK)
+	app, mock := setupMockedServer(t, func(cfg *config.Config) {
+		cfg.Health.ProbeInterval = 0 // every /ready probes
+		cfg.Health.UnhealthyThreshold = 150 * time.Millisecond
+		cfg.Health.ClobProbePath = "/time"
+	})
+	mock.Handle(mockupstream.CLOB, "GET", "/time", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(clobStatus.Load()))
+	})
+
+	ready := func() (int, string) {
+		resp, err := app.Test(httptest.NewRequest("GET", "/ready", nil), -1)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	status, _ := ready()
+	assert.Equal(t, 200, status)
+
+	// Failed probes within the threshold keep the server ready
+	clobStatus.Store(http.StatusBadGateway)
+	for i := 0; i < 3; i++ {
+		status, _ = ready()
+		assert.Equal(t, 200, status)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	status, body := ready()
+	assert.Equal(t, 503, status)
+	assert.Contains(t, body, "Upstream unreachable: clob")
+
+	// The first successful probe makes it ready again
+	clobStatus.Store(http.StatusOK)
+	status, _ = ready()
+	assert.Equal(t, 200, status)
+}
+
 func TestStatsEndpoint(t *testing.T) {
 	app := setupTestServer(t)
 
@@ -73,6 +170,25 @@ func TestStatsEndpoint(t *testing.T) {
 	data := result["data"].(map[string]interface{})
 	assert.NotEmpty(t, data["go_version"])
 	assert.NotZero(t, data["num_cpu"])
+	assert.Equal(t, float64(10000), data["goroutine_budget"])
+	assert.Equal(t, false, data["over_goroutine_budget"])
+}
+
+func TestStatsEndpoint_OverGoroutineBudget(t *testing.T) {
+	app, _ := setupMockedServer(t, func(cfg *config.Config) {
+		cfg.Server.GoroutineBudget = 1
+	})
+
+	req := httptest.NewRequest("GET", "/stats", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+
+	body, _ := io.ReadAll(resp.Body)
+	var result map[string]interface{}
+	require.NoError(t, sonic.Unmarshal(body, &result))
+
+	data := result["data"].(map[string]interface{})
+	assert.Equal(t, true, data["over_goroutine_budget"])
 }
 
 func TestMarketsEndpoint_RequiresNoAuth(t *testing.T) {
@@ -169,6 +285,101 @@ func TestPositions_RequiresAddress(t *testing.T) {
 	assert.False(t, result["success"].(bool))
 }
 
+func TestSnapshot_ValidatesTokenIDs(t *testing.T) {
+	app := setupTestServer(t)
+
+	req := httptest.NewRequest("GET", "/api/v1/snapshot", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+
+	ids := make([]string, 51)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("%d", i)
+	}
+	req = httptest.NewRequest("GET", "/api/v1/snapshot?token_ids="+strings.Join(ids, ","), nil)
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestRawProxy_EnforcesAllowlist(t *testing.T) {
+	app := setupTestServer(t)
+
+	// The default allowlist is empty
+	req := httptest.NewRequest("GET", "/api/v1/raw/clob/book?token_id=1", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, 403, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/api/v1/raw/unknown/book", nil)
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+}
+
+func TestRawProxy_RejectsPathTraversal(t *testing.T) {
+	app, mock := setupMockedServer(t, func(cfg *config.Config) {
+		cfg.RawProxy.Enabled = true
+		cfg.RawProxy.Allowlist = []string{"clob:/prices-history", "clob:/order"}
+	})
+
+	for _, path := range []string{
+		"/api/v1/raw/clob/prices-history/../order",
+		"/api/v1/raw/clob/prices-history/%2e%2e/order",
+		"/api/v1/raw/clob/prices-history/%2E%2E%2Forder",
+		"/api/v1/raw/clob/prices-history/./x",
+		"/api/v1/raw/clob/prices-history//x",
+	} {
+		resp, err := app.Test(httptest.NewRequest("POST", path, strings.NewReader(`{}`)), -1)
+		require.NoError(t, err)
+		assert.Equal(t, 403, resp.StatusCode, path)
+	}
+
+	// Allowlisted, but orders must go through the pre-trade checks
+	resp, err := app.Test(httptest.NewRequest("POST", "/api/v1/raw/clob/order", strings.NewReader(`{}`)), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 403, resp.StatusCode)
+	assert.Empty(t, clobWrites(mock))
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/api/v1/raw/clob/prices-history?market=1", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func TestAdminCachePurge(t *testing.T) {
+	mock := mockupstream.New()
+	defer mock.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.Admin.Token = "secret"
+	mock.Apply(&cfg.Polymarket)
+	scratchState(t, cfg)
+
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	server, err := api.NewServer(cfg, c)
+	require.NoError(t, err)
+	app := server.GetApp()
+
+	c.Set("markets:1", []byte(`{}`), time.Minute)
+	c.Wait()
+
+	req := httptest.NewRequest("DELETE", "/admin/cache?key=markets:1", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, 401, resp.StatusCode)
+
+	req = httptest.NewRequest("DELETE", "/admin/cache?key=markets:1", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	_, found := c.Get("markets:1")
+	assert.False(t, found)
+}
+
 func TestTopMovers_Endpoint(t *testing.T) {
 	app := setupTestServer(t)
 
@@ -218,3 +429,32 @@ func BenchmarkMarketsEndpoint(b *testing.B) {
 		app.Test(req, 5*int(time.Second))
 	}
 }
+
+func TestDocs_ServedForConfiguredHost(t *testing.T) {
+	app, _ := setupMockedServer(t, func(cfg *config.Config) {
+		cfg.Docs = config.DocsConfig{Host: "api.example.com", BasePath: "/", Schemes: []string{"https"}}
+	})
+	// The spec is process-wide
+	t.Cleanup(func() { docs.Configure(&config.DefaultConfig().Docs) })
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/openapi.json", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "application/json")
+
+	var doc map[string]interface{}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, sonic.Unmarshal(body, &doc))
+	assert.Equal(t, "3.0.3", doc["openapi"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"url": "https://api.example.com"}}, doc["servers"])
+	assert.Contains(t, doc["paths"], "/api/v1/markets")
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/swagger/doc.json", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	var spec map[string]interface{}
+	body, _ = io.ReadAll(resp.Body)
+	require.NoError(t, sonic.Unmarshal(body, &spec))
+	assert.Equal(t, "api.example.com", spec["host"])
+	assert.Equal(t, []interface{}{"https"}, spec["schemes"])
+}
@@ -0,0 +1,2035 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	fiberws "github.com/gofiber/websocket/v2"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/polygo/internal/api"
+	"github.com/polygo/internal/api/handlers"
+	"github.com/polygo/internal/audit"
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/copytrade"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/pb"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/wsbuffer"
+	"github.com/polygo/internal/wsreplay"
+)
+
+func TestMarkets_ServedFromUpstream(t *testing.T) {
+	app, mock := setupMockedServer(t, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/markets?limit=10", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+
+	body, _ := io.ReadAll(resp.Body)
+	var markets []map[string]interface{}
+	require.NoError(t, sonic.Unmarshal(body, &markets))
+	assert.Len(t, markets, 2)
+	assert.Equal(t, mockupstream.MarketID, markets[0]["id"])
+
+	requests := mock.Requests(mockupstream.Gamma)
+	require.Len(t, requests, 1)
+	assert.Equal(t, "/markets", requests[0].Path)
+
+	req = httptest.NewRequest("GET", "/api/v1/markets/unknown", nil)
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+}
+
+func TestMarkets_Normalized(t *testing.T) {
+	app, _ := setupMockedServer(t, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/markets/"+mockupstream.MarketID+"?normalize=true", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+
+	body, _ := io.ReadAll(resp.Body)
+	var market map[string]interface{}
+	require.NoError(t, sonic.Unmarshal(body, &market))
+	assert.Equal(t, []interface{}{mockupstream.TokenYes, mockupstream.TokenNo}, market["clobTokenIds"])
+	assert.Equal(t, []interface{}{0.5, 0.5}, market["outcomePrices"])
+}
+
+func TestMarkets_LinkToNextPage(t *testing.T) {
+	app, mock := setupMockedServer(t, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/markets?limit=2&active=true", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, `<http://example.com/api/v1/markets?limit=2&active=true&cursor=2>; rel="next"`, resp.Header.Get("Link"))
+
+	// The unified cursor reaches Gamma as an offset
+	req = httptest.NewRequest("GET", "/api/v1/markets?limit=2&cursor=2", nil)
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+
+	requests := mock.Requests(mockupstream.Gamma)
+	require.Len(t, requests, 2)
+	assert.Contains(t, requests[1].Query, "offset=2")
+}
+
+// getMarketFull requests /markets/:id/full and returns the detail and its X-Cache
+func getMarketFull(t *testing.T, app *fiber.App, id string) (int, *polymarket.MarketDetail, string) {
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/markets/"+id+"/full", nil), -1)
+	require.NoError(t, err)
+	if resp.StatusCode != 200 {
+		return resp.StatusCode, nil, resp.Header.Get("X-Cache")
+	}
+	var result struct {
+		Data polymarket.MarketDetail `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	return resp.StatusCode, &result.Data, resp.Header.Get("X-Cache")
+}
+
+func TestMarketFull_ComposesAndCaches(t *testing.T) {
+	app, mock := setupMockedServer(t, nil)
+
+	status, detail, xcache := getMarketFull(t, app, mockupstream.MarketID)
+	require.Equal(t, 200, status)
+	assert.Equal(t, "MISS", xcache)
+	assert.Empty(t, detail.Errors)
+	assert.InDelta(t, 12000.5, detail.Volume24hr, 1e-9)
+	assert.Contains(t, string(detail.Market), mockupstream.ConditionID)
+	require.Len(t, detail.Tokens, 2)
+	assert.Equal(t, "Yes", detail.Tokens[0].Outcome)
+	assert.Equal(t, mockupstream.TokenYes, detail.Tokens[0].TokenID)
+	assert.Equal(t, "No", detail.Tokens[1].Outcome)
+	assert.NotEmpty(t, detail.RecentTrades)
+
+	// The composition is served whole from the cache once it applies the write
+	require.Eventually(t, func() bool {
+		_, _, xcache := getMarketFull(t, app, mockupstream.MarketID)
+		return xcache == "HIT"
+	}, time.Second, 10*time.Millisecond)
+	upstream := func() int {
+		return len(mock.Requests(mockupstream.Gamma)) + len(mock.Requests(mockupstream.CLOB)) + len(mock.Requests(mockupstream.Data))
+	}
+	before := upstream()
+
+	status, cached, xcache := getMarketFull(t, app, mockupstream.MarketID)
+	require.Equal(t, 200, status)
+	assert.Equal(t, "HIT", xcache)
+	assert.Equal(t, detail.Tokens, cached.Tokens)
+	assert.JSONEq(t, string(detail.Market), string(cached.Market))
+	assert.JSONEq(t, string(detail.RecentTrades), string(cached.RecentTrades))
+	assert.Equal(t, before, upstream(), "a hit contacts no upstream")
+
+	status, _, _ = getMarketFull(t, app, "unknown")
+	assert.Equal(t, 404, status)
+}
+
+func TestMarketFull_ReportsPartialFailuresUncached(t *testing.T) {
+	app, mock := setupMockedServer(t, nil)
+	mock.On(mockupstream.Data, "GET", "/trades", 500, `{"error":"down"}`)
+	mock.Handle(mockupstream.CLOB, "GET", "/book", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("token_id") == mockupstream.TokenNo {
+			w.WriteHeader(500)
+			return
+		}
+		w.Write([]byte(`{"asset_id":"` + mockupstream.TokenYes + `","bids":[{"price":"0.48","size":"100"}],"asks":[{"price":"0.52","size":"100"}]}`))
+	})
+
+	status, detail, xcache := getMarketFull(t, app, mockupstream.MarketID)
+	require.Equal(t, 200, status, "failed sections do not fail the market")
+	assert.Equal(t, "MISS", xcache)
+	assert.Contains(t, string(detail.Market), mockupstream.ConditionID)
+	assert.Contains(t, detail.Errors, "recent_trades")
+	assert.Contains(t, detail.Errors, "token:"+mockupstream.TokenNo)
+	assert.NotContains(t, detail.Errors, "token:"+mockupstream.TokenYes)
+	assert.JSONEq(t, "null", string(detail.RecentTrades))
+	require.Len(t, detail.Tokens, 2)
+	assert.Empty(t, detail.Tokens[0].Error)
+	assert.NotEmpty(t, detail.Tokens[1].Error)
+
+	// Incomplete compositions are not cached; the next request tries again
+	trades := len(mock.Requests(mockupstream.Data))
+	mock.Reset()
+	status, detail, xcache = getMarketFull(t, app, mockupstream.MarketID)
+	require.Equal(t, 200, status)
+	assert.Equal(t, "MISS", xcache)
+	assert.Positive(t, trades)
+	assert.NotEmpty(t, mock.Requests(mockupstream.Data))
+	assert.NotContains(t, detail.Errors, "recent_trades")
+	assert.NotEmpty(t, detail.RecentTrades)
+}
+
+func TestResolve_TokenFromUpstream(t *testing.T) {
+	app, mock := setupMockedServer(t, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/resolve/"+mockupstream.TokenNo, nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+
+	body, _ := io.ReadAll(resp.Body)
+	var result struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, sonic.Unmarshal(body, &result))
+	assert.Equal(t, mockupstream.MarketID, result.Data["market_id"])
+	assert.Equal(t, "No", result.Data["outcome"])
+
+	// Resolved tokens are remembered
+	req = httptest.NewRequest("GET", "/api/v1/resolve/"+mockupstream.TokenNo, nil)
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+	assert.Len(t, mock.Requests(mockupstream.Gamma), 1)
+
+	req = httptest.NewRequest("GET", "/api/v1/resolve/unknown-token", nil)
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+}
+
+func TestPrices_ConvertedAndImplied(t *testing.T) {
+	app, mock := setupMockedServer(t, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/book/"+mockupstream.TokenYes+"?as=cents&round=tick", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+
+	body, _ := io.ReadAll(resp.Body)
+	var book map[string]interface{}
+	require.NoError(t, sonic.Unmarshal(body, &book))
+	assert.Equal(t, 0.5, book["implied_probability"])
+	bids := book["bids"].([]interface{})
+	assert.Equal(t, "48", bids[0].(map[string]interface{})["price"])
+	assert.Equal(t, "200", bids[0].(map[string]interface{})["size"])
+
+	var tickRequests int
+	for _, r := range mock.Requests(mockupstream.CLOB) {
+		if r.Path == "/tick-size" {
+			tickRequests++
+		}
+	}
+	assert.Equal(t, 1, tickRequests)
+
+	req = httptest.NewRequest("GET", "/api/v1/midpoint/"+mockupstream.TokenYes+"?as=american", nil)
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+
+	body, _ = io.ReadAll(resp.Body)
+	var mid map[string]interface{}
+	require.NoError(t, sonic.Unmarshal(body, &mid))
+	assert.Equal(t, "-100", mid["mid"])
+	assert.Equal(t, 0.5, mid["implied_probability"])
+
+	req = httptest.NewRequest("GET", "/api/v1/price/"+mockupstream.TokenYes+"?as=decimal", nil)
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestCalcPayout_UsesTokenTerms(t *testing.T) {
+	app, mock := setupMockedServer(t, nil)
+	mock.On(mockupstream.CLOB, "GET", "/fee-rate", 200, `{"base_fee": 200}`)
+
+	req := httptest.NewRequest("GET", "/api/v1/calc/payout?token_id="+mockupstream.TokenYes+"&side=buy&price=0.40&size=100", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+
+	body, _ := io.ReadAll(resp.Body)
+	var result struct {
+		Data polymarket.PayoutQuote `json:"data"`
+	}
+	require.NoError(t, sonic.Unmarshal(body, &result))
+	assert.Equal(t, 0.01, result.Data.TickSize)
+	assert.Equal(t, 200.0, result.Data.FeeRateBps)
+	assert.Equal(t, 98.0, result.Data.Shares)
+	assert.Equal(t, 40.0, result.Data.NetCost)
+
+	req = httptest.NewRequest("GET", "/api/v1/calc/payout?token_id="+mockupstream.TokenYes+"&side=BUY&price=0.405&size=100", nil)
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestTraderProfile_ComputesStats(t *testing.T) {
+	app, mock := setupMockedServer(t, nil)
+	mock.On(mockupstream.Data, "GET", "/positions", 200, `[
+		{"asset":"a","curPrice":0.6,"currentValue":60,"cashPnl":10,"realizedPnl":0},
+		{"asset":"b","curPrice":1,"currentValue":0,"realizedPnl":25,"redeemable":true},
+		{"asset":"c","curPrice":0,"currentValue":0,"realizedPnl":-5}
+	]`)
+	mock.On(mockupstream.Data, "GET", "/trades", 200, `[{"size":100,"price":"0.5"},{"size":"10","price":0.2}]`)
+
+	req := httptest.NewRequest("GET", "/api/v1/trader/0xAbC/profile", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "MISS", resp.Header.Get("X-Cache"))
+
+	body, _ := io.ReadAll(resp.Body)
+	var result struct {
+		Data polymarket.TraderProfile `json:"data"`
+	}
+	require.NoError(t, sonic.Unmarshal(body, &result))
+	stats := result.Data.Stats
+	assert.Equal(t, "0xabc", result.Data.Address)
+	assert.Equal(t, 1, stats.OpenPositions)
+	assert.Equal(t, 60.0, stats.Exposure)
+	assert.Equal(t, 2, stats.ResolvedPositions)
+	require.NotNil(t, stats.WinRate)
+	assert.Equal(t, 0.5, *stats.WinRate)
+	assert.Equal(t, 20.0, stats.RealizedPnL)
+	assert.Equal(t, 2, stats.Trades)
+	assert.Equal(t, 52.0, stats.Volume)
+	assert.Empty(t, result.Data.Errors)
+
+	// Cached under the lowercased address once the cache applies the write
+	require.Eventually(t, func() bool {
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/trader/0xabc/profile", nil), -1)
+		return err == nil && resp.Header.Get("X-Cache") == "HIT"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCopyTrade_AdminOnlyLifecycle(t *testing.T) {
+	app, _ := setupMockedServer(t, func(cfg *config.Config) {
+		cfg.Admin.Token = "secret"
+		cfg.CopyTrade.Enabled = true
+		cfg.CopyTrade.Interval = time.Hour
+		cfg.CopyTrade.Address = "0x2222222222222222222222222222222222222222"
+		cfg.CopyTrade.APIKey = "key"
+		cfg.CopyTrade.Secret = "c2VjcmV0"
+		cfg.CopyTrade.Passphrase = "pass"
+	})
+	call := func(method, path, body, token string) *http.Response {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp
+	}
+
+	assert.Equal(t, 401, call("GET", "/api/v1/copytrade/status", "", "").StatusCode)
+	assert.Equal(t, 400, call("POST", "/api/v1/copytrade/start", `{"target":"nope"}`, "secret").StatusCode)
+
+	start := `{"target":"0x1111111111111111111111111111111111111111","scale":0.5}`
+	assert.Equal(t, 200, call("POST", "/api/v1/copytrade/start", start, "secret").StatusCode)
+	assert.Equal(t, 409, call("POST", "/api/v1/copytrade/start", start, "secret").StatusCode)
+
+	resp := call("POST", "/api/v1/copytrade/stop", "", "secret")
+	assert.Equal(t, 200, resp.StatusCode)
+	var body struct {
+		Data copytrade.Status `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.False(t, body.Data.Running)
+	assert.Equal(t, 0.5, body.Data.Options.Scale)
+
+	assert.Equal(t, 409, call("POST", "/api/v1/copytrade/stop", "", "secret").StatusCode)
+}
+
+func TestOrderBook_RetriesUpstreamFailures(t *testing.T) {
+	app, mock := setupMockedServer(t, nil)
+	mock.On(mockupstream.CLOB, "GET", "/book", 503, `{"error":"unavailable"}`).Times(2)
+
+	req := httptest.NewRequest("GET", "/api/v1/book/"+mockupstream.TokenYes, nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Len(t, mock.Requests(mockupstream.CLOB), 3)
+}
+
+func TestOrderBook_ClientErrorNotRetried(t *testing.T) {
+	app, mock := setupMockedServer(t, nil)
+	mock.On(mockupstream.CLOB, "GET", "/book", 400, `{"error":"invalid token id"}`)
+
+	req := httptest.NewRequest("GET", "/api/v1/book/bad", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, 400, resp.StatusCode)
+	assert.Contains(t, string(data), `"code":"UPSTREAM_BAD_REQUEST"`)
+	assert.Contains(t, string(data), "invalid token id")
+	assert.Len(t, mock.Requests(mockupstream.CLOB), 1)
+}
+
+func TestCreateOrder_ForwardsAuthToUpstream(t *testing.T) {
+	app, mock := setupMockedServer(t, nil)
+
+	body := `{"tokenID":"` + mockupstream.TokenYes + `","side":"BUY","price":"0.5","size":"10"}`
+	req := httptest.NewRequest("POST", "/api/v1/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	// PolyGo matches auth header names case-sensitively
+	req.Header["POLY-API-KEY"] = []string{"key"}
+	req.Header["POLY-TIMESTAMP"] = []string{"1700000000"}
+	req.Header["POLY-SIGNATURE"] = []string{"sig"}
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(resp.Body)
+	require.Equal(t, 200, resp.StatusCode, string(data))
+
+	assert.Contains(t, string(data), mockupstream.OrderID)
+
+	requests := clobWrites(mock)
+	require.Len(t, requests, 1)
+	assert.Equal(t, "/order", requests[0].Path)
+	assert.Equal(t, "key", requests[0].Header.Get("POLY-API-KEY"))
+	assert.Contains(t, string(requests[0].Body), `"type":"GTC"`)
+}
+
+func TestCreateOrders_PreTradeRiskChecks(t *testing.T) {
+	app, mock := setupMockedServer(t, func(cfg *config.Config) {
+		cfg.Risk.Path = ""
+		cfg.Risk.Default = config.RiskLimits{MaxOrderSize: 50, BannedMarkets: []string{mockupstream.ConditionID}}
+	})
+	mock.On(mockupstream.CLOB, "POST", "/orders", 200,
+		`[{"success":true,"orderID":"0xa","status":"live"},{"success":true,"orderID":"0xb","status":"live"}]`)
+	post := func(path, body string) (int, string) {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header["POLY-API-KEY"] = []string{"key"}
+		req.Header["POLY-TIMESTAMP"] = []string{"1700000000"}
+		req.Header["POLY-SIGNATURE"] = []string{"sig"}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+	order := func(tokenID, size string) string {
+		return `{"tokenID":"` + tokenID + `","side":"BUY","price":"0.5","size":"` + size + `"}`
+	}
+
+	status, body := post("/api/v1/orders", order("mock-token-yes-2", "51"))
+	assert.Equal(t, 422, status)
+	assert.Contains(t, body, `"code":"RISK_MAX_ORDER_SIZE"`)
+
+	status, body = post("/api/v1/orders/batch", "["+order("mock-token-yes-2", "10")+","+order(mockupstream.TokenYes, "10")+"]")
+	assert.Equal(t, 422, status)
+	assert.Contains(t, body, `"code":"RISK_BANNED_MARKET"`)
+	assert.Contains(t, body, `"details":"orders[1]"`)
+	assert.Empty(t, clobWrites(mock), "rejected orders never reach the CLOB")
+
+	status, body = post("/api/v1/orders/batch", "["+order("mock-token-yes-2", "10")+","+order("mock-token-no-2", "10")+"]")
+	require.Equal(t, 200, status, body)
+	assert.Contains(t, body, "0xb")
+	requests := clobWrites(mock)
+	require.Len(t, requests, 1)
+	assert.Equal(t, "/orders", requests[0].Path)
+	assert.Contains(t, string(requests[0].Body), `"type":"GTC"`)
+}
+
+func TestCreateOrder_GTDExpirationsAreValidatedAndTracked(t *testing.T) {
+	app, _ := setupMockedServer(t, nil)
+	call := func(method, path, body string) (int, string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header["POLY-API-KEY"] = []string{"key"}
+		req.Header["POLY-API-SECRET"] = []string{"c2VjcmV0"}
+		req.Header["POLY-TIMESTAMP"] = []string{"1700000000"}
+		req.Header["POLY-SIGNATURE"] = []string{"sig"}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+	gtd := func(expiration int64) string {
+		return `{"tokenID":"` + mockupstream.TokenYes + `","side":"BUY","price":"0.5","size":"10","type":"GTD","expiration":` + strconv.FormatInt(expiration, 10) + `}`
+	}
+
+	status, body := call("POST", "/api/v1/orders", gtd(time.Now().Add(-time.Minute).Unix()))
+	assert.Equal(t, 400, status)
+	assert.Contains(t, body, "Expiration is in the past")
+
+	status, body = call("POST", "/api/v1/orders", gtd(time.Now().Add(10*time.Second).Unix()))
+	assert.Equal(t, 400, status)
+	assert.Contains(t, body, "Expiration is too near")
+
+	status, body = call("POST", "/api/v1/orders", gtd(time.Now().Add(time.Hour).Unix()))
+	require.Equal(t, 200, status, body)
+
+	status, body = call("GET", "/api/v1/orders/expiring", "")
+	require.Equal(t, 200, status, body)
+	assert.Contains(t, body, mockupstream.OrderID)
+	assert.Contains(t, body, `"auto_cancel":true`)
+
+	status, _ = call("DELETE", "/api/v1/orders/"+mockupstream.OrderID, "")
+	require.Equal(t, 200, status)
+	_, body = call("GET", "/api/v1/orders/expiring", "")
+	assert.NotContains(t, body, mockupstream.OrderID, "cancelled orders stop being tracked")
+}
+
+func TestOrderPair_PlacesRollsBackAndCancels(t *testing.T) {
+	// The same pair is placed again after rolling back
+	app, mock := setupMockedServer(t, func(cfg *config.Config) { cfg.Throttle.DuplicateWindow = 0 })
+	call := func(method, path, body string) (int, string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header["POLY-API-KEY"] = []string{"key"}
+		req.Header["POLY-TIMESTAMP"] = []string{"1700000000"}
+		req.Header["POLY-SIGNATURE"] = []string{"sig"}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+	leg := func(token, price string) string {
+		return `{"tokenID":"` + token + `","side":"BUY","price":"` + price + `","size":"10"}`
+	}
+	body := `{"legs":[` + leg(mockupstream.TokenYes, "0.40") + `,` + leg(mockupstream.TokenNo, "0.55") + `]}`
+
+	status, resp := call("POST", "/api/v1/orders/pair", `{"legs":[`+leg(mockupstream.TokenYes, "0.40")+`]}`)
+	assert.Equal(t, 400, status)
+	assert.Contains(t, resp, "legs must have exactly 2 items")
+
+	mock.On(mockupstream.CLOB, "POST", "/orders", 200,
+		`[{"success":true,"orderID":"0xyes","status":"live"},{"success":false,"errorMsg":"not enough balance"}]`)
+	status, resp = call("POST", "/api/v1/orders/pair", body)
+	require.Equal(t, 200, status, resp)
+	assert.Contains(t, resp, `"status":"rolled_back"`)
+
+	mock.On(mockupstream.CLOB, "POST", "/orders", 200,
+		`[{"success":true,"orderID":"0xyes","status":"live"},{"success":true,"orderID":"0xno","status":"live"}]`)
+	status, resp = call("POST", "/api/v1/orders/pair", body)
+	require.Equal(t, 200, status, resp)
+	var created struct {
+		Data struct {
+			ID     string `json:"id"`
+			Status string `json:"status"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(resp), &created))
+	assert.Equal(t, "open", created.Data.Status)
+
+	status, resp = call("GET", "/api/v1/orders/pair", "")
+	require.Equal(t, 200, status)
+	assert.Equal(t, 2, strings.Count(resp, `"id":"pair_`))
+
+	status, _ = call("GET", "/api/v1/orders/pair/"+created.Data.ID, "")
+	assert.Equal(t, 200, status)
+	status, _ = call("GET", "/api/v1/orders/pair/pair_unknown", "")
+	assert.Equal(t, 404, status)
+
+	status, resp = call("DELETE", "/api/v1/orders/pair/"+created.Data.ID, "")
+	require.Equal(t, 200, status, resp)
+	assert.Contains(t, resp, `"status":"cancelled"`)
+}
+
+func TestUserBalance_RequiresAuthAndReportsTradeability(t *testing.T) {
+	app, _ := setupMockedServer(t, nil)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/user/balance", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 401, resp.StatusCode)
+
+	req := httptest.NewRequest("GET", "/api/v1/user/balance", nil)
+	req.Header["POLY-API-KEY"] = []string{"key"}
+	req.Header["POLY-TIMESTAMP"] = []string{"1700000000"}
+	req.Header["POLY-SIGNATURE"] = []string{"sig"}
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+
+	var body struct {
+		Data struct {
+			Balance  float64  `json:"balance"`
+			CanTrade bool     `json:"can_trade"`
+			Warnings []string `json:"warnings"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, 100.0, body.Data.Balance)
+	assert.True(t, body.Data.CanTrade)
+	assert.Empty(t, body.Data.Warnings)
+}
+
+func TestTxVerify_ChecksSettlementAndAnnotatesTrades(t *testing.T) {
+	const hash = "0x1111111111111111111111111111111111111111111111111111111111111111"
+	rpc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int    `json:"id"`
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		result := interface{}("0x64")
+		if req.Method == "eth_getTransactionReceipt" {
+			result = map[string]interface{}{"status": "0x1", "blockNumber": "0x10", "logs": []interface{}{}}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	defer rpc.Close()
+
+	app, mock := setupMockedServer(t, func(cfg *config.Config) {
+		cfg.Chain.RPCURL = rpc.URL
+	})
+	mock.On(mockupstream.Data, "GET", "/trades", 200, `[{"transactionHash":"`+hash+`","size":10}]`)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/tx/0x1234/verify", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/api/v1/tx/"+hash+"/verify", nil), -1)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), `"status":"confirmed"`)
+	assert.Contains(t, string(body), `"block_number":16`)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/api/v1/user/trades?address=0xabc&verify=true", nil), -1)
+	require.NoError(t, err)
+	body, _ = io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), `"confirmed":true`)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/api/v1/user/trades?address=0xabc", nil), -1)
+	require.NoError(t, err)
+	body, _ = io.ReadAll(resp.Body)
+	assert.NotContains(t, string(body), `"confirmed"`, "only annotated on request")
+}
+
+func TestRewards_EstimatesFromOpenOrdersAndMarketTerms(t *testing.T) {
+	const address = "0x00000000000000000000000000000000000000aa"
+	app, mock := setupMockedServer(t, nil)
+	mock.On(mockupstream.CLOB, "GET", "/orders/open", 200, `[
+		{"id":"yes-bid","asset_id":"`+mockupstream.TokenYes+`","side":"BUY","price":"0.49","original_size":"100","size_matched":"0"},
+		{"id":"no-bid","asset_id":"`+mockupstream.TokenNo+`","side":"BUY","price":"0.49","original_size":"100","size_matched":"0"}
+	]`)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/rewards/"+address, nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 401, resp.StatusCode)
+
+	get := func(path string) *http.Response {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header["POLY-API-KEY"] = []string{"key"}
+		req.Header["POLY-TIMESTAMP"] = []string{"1700000000"}
+		req.Header["POLY-SIGNATURE"] = []string{"sig"}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp
+	}
+
+	assert.Equal(t, 400, get("/api/v1/rewards/not-an-address").StatusCode)
+
+	resp = get("/api/v1/rewards/" + address)
+	require.Equal(t, 200, resp.StatusCode)
+	var body struct {
+		Data struct {
+			EstimatedDaily float64 `json:"estimated_daily"`
+			Markets        []struct {
+				MarketID string  `json:"market_id"`
+				MinSize  float64 `json:"min_size"`
+				Eligible bool    `json:"eligible"`
+				Orders   []struct {
+					Eligible bool `json:"eligible"`
+				} `json:"orders"`
+			} `json:"markets"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Data.Markets, 1)
+	assert.Equal(t, mockupstream.MarketID, body.Data.Markets[0].MarketID)
+	assert.Equal(t, 50.0, body.Data.Markets[0].MinSize)
+	assert.True(t, body.Data.Markets[0].Eligible)
+	assert.Len(t, body.Data.Markets[0].Orders, 2)
+	assert.InDelta(t, 20*2500.0/8600, body.Data.EstimatedDaily, 1e-5)
+}
+
+func TestOrders_ValidateBodiesAndLimitSize(t *testing.T) {
+	app, mock := setupMockedServer(t, func(cfg *config.Config) {
+		cfg.Server.OrderBodyLimit = 512
+	})
+	call := func(path, body string) (int, string) {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header["POLY-API-KEY"] = []string{"key"}
+		req.Header["POLY-TIMESTAMP"] = []string{"1700000000"}
+		req.Header["POLY-SIGNATURE"] = []string{"sig"}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+
+	status, body := call("/api/v1/orders/batch", `[
+		{"tokenID":"`+mockupstream.TokenYes+`","side":"BUY","price":"0.5","size":"10"},
+		{"tokenID":"`+mockupstream.TokenYes+`","side":"buy","price":"half","size":"10"}
+	]`)
+	require.Equal(t, 400, status)
+	var envelope struct {
+		Error struct {
+			Code    string `json:"code"`
+			Details string `json:"details"`
+			Fields  []struct {
+				Field string `json:"field"`
+				Rule  string `json:"rule"`
+			} `json:"fields"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &envelope))
+	assert.Equal(t, "VALIDATION_FAILED", envelope.Error.Code)
+	require.Len(t, envelope.Error.Fields, 2)
+	assert.Equal(t, "orders[1].side", envelope.Error.Fields[0].Field)
+	assert.Equal(t, "orders[1].price", envelope.Error.Fields[1].Field)
+	assert.Equal(t, "orders[1].side must be one of BUY, SELL", envelope.Error.Details)
+
+	status, body = call("/api/v1/orders/batch-cancel", `{"orderIds":[]}`)
+	assert.Equal(t, 400, status)
+	assert.Contains(t, body, `"field":"orderIds"`)
+
+	status, body = call("/api/v1/orders", `{"tokenID":"`+strings.Repeat("1", 600)+`"}`)
+	assert.Equal(t, 413, status)
+	assert.Contains(t, body, "PAYLOAD_TOO_LARGE")
+
+	// Nothing invalid reached the CLOB
+	for _, r := range mock.Requests(mockupstream.CLOB) {
+		assert.NotEqual(t, "POST", r.Method, r.Path)
+	}
+}
+
+func TestOrders_RejectsOffTickPricesLocally(t *testing.T) {
+	app, mock := setupMockedServer(t, nil)
+
+	body := `{"tokenID":"` + mockupstream.TokenYes + `","side":"BUY","price":"0.123","size":"10"}`
+	req := httptest.NewRequest("POST", "/api/v1/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header["POLY-API-KEY"] = []string{"key"}
+	req.Header["POLY-TIMESTAMP"] = []string{"1700000000"}
+	req.Header["POLY-SIGNATURE"] = []string{"sig"}
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, 400, resp.StatusCode)
+	assert.Contains(t, string(data), `"code":"ORDER_TICK_SIZE"`)
+	assert.Contains(t, string(data), "not a multiple of tick 0.01; use 0.12 or 0.13")
+	assert.Contains(t, string(data), `"details":"orders[0]"`)
+	assert.Empty(t, clobWrites(mock))
+}
+
+func TestUpstreamErrors_MappedToTypedCodes(t *testing.T) {
+	app, mock := setupMockedServer(t, nil)
+	mock.On(mockupstream.CLOB, "GET", "/book", 429, `{"error":"Too Many Requests"}`)
+	mock.On(mockupstream.CLOB, "POST", "/order", 200,
+		`{"success":false,"errorMsg":"not enough balance / allowance","orderID":""}`)
+
+	req := httptest.NewRequest("GET", "/api/v1/book/"+mockupstream.TokenYes, nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, 429, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+	assert.Contains(t, string(data), `"code":"UPSTREAM_RATE_LIMITED"`)
+	assert.Contains(t, string(data), `"retryable":true,"retry_after_ms":1000`)
+
+	body := `{"tokenID":"` + mockupstream.TokenYes + `","side":"BUY","price":"0.5","size":"10"}`
+	req = httptest.NewRequest("POST", "/api/v1/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header["POLY-API-KEY"] = []string{"key"}
+	req.Header["POLY-TIMESTAMP"] = []string{"1700000000"}
+	req.Header["POLY-SIGNATURE"] = []string{"sig"}
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	var envelope struct {
+		Error struct {
+			Code      string `json:"code"`
+			Details   string `json:"details"`
+			Retryable bool   `json:"retryable"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&envelope))
+	assert.Equal(t, 422, resp.StatusCode)
+	assert.Equal(t, "ORDER_REJECTED_INSUFFICIENT_BALANCE", envelope.Error.Code)
+	assert.Equal(t, "not enough balance / allowance", envelope.Error.Details)
+	assert.False(t, envelope.Error.Retryable)
+}
+
+func TestLatency_RouteHistogramsSplitUpstreamTime(t *testing.T) {
+	app, mock := setupMockedServer(t, nil)
+	mock.On(mockupstream.CLOB, "GET", "/book", 200, `{"bids":[],"asks":[]}`).Delay(60 * time.Millisecond)
+
+	for _, token := range []string{mockupstream.TokenYes, mockupstream.TokenNo} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/book/"+token, nil), -1)
+		require.NoError(t, err)
+		require.Equal(t, 200, resp.StatusCode)
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/stats", nil), -1)
+	require.NoError(t, err)
+	var stats struct {
+		Data struct {
+			Routes []struct {
+				Method     string                     `json:"method"`
+				Route      string                     `json:"route"`
+				Count      uint64                     `json:"count"`
+				LatencyMs  struct{ P50, P99 float64 } `json:"latency_ms"`
+				UpstreamMs struct{ P50, Max float64 } `json:"upstream_ms"`
+			} `json:"routes"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&stats))
+
+	var found bool
+	for _, r := range stats.Data.Routes {
+		if r.Method != "GET" || r.Route != "/api/v1/book/:token_id" {
+			continue
+		}
+		found = true
+		assert.Equal(t, uint64(2), r.Count)
+		assert.GreaterOrEqual(t, r.LatencyMs.P50, 25.0, "p50 falls in the 25-50ms or 50-100ms bucket")
+		assert.Greater(t, r.UpstreamMs.Max, 55.0, "the mock's delay is upstream time")
+	}
+	require.True(t, found, "book route is tracked by its pattern: %+v", stats.Data.Routes)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/metrics", nil), -1)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/plain")
+	assert.Contains(t, string(body), `polygo_http_request_duration_seconds_count{method="GET",route="/api/v1/book/:token_id"} 2`)
+	assert.Contains(t, string(body), `polygo_http_request_upstream_seconds_bucket{method="GET",route="/api/v1/book/:token_id",le="0.05"} 0`)
+	assert.Contains(t, string(body), `polygo_http_request_duration_seconds_quantile{method="GET",route="/api/v1/book/:token_id",quantile="0.99"}`)
+}
+
+func TestWSManager_ReceivesUpstreamPushes(t *testing.T) {
+	mock := mockupstream.New()
+	defer mock.Close()
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	cfg.Polymarket.WsShards = 1
+
+	m := polymarket.NewWSManager(&cfg.Polymarket)
+	defer m.Close()
+	require.NoError(t, m.Connect())
+
+	ch, err := m.SubscribeMarket(mockupstream.MarketID)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return len(mock.Subscriptions()) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	msg := `{"type":"price_change","markets":["` + mockupstream.MarketID + `"]}`
+	mock.Push([]byte(msg))
+
+	select {
+	case data := <-ch:
+		assert.JSONEq(t, msg, string(data))
+	case <-time.After(2 * time.Second):
+		t.Fatal("no message from upstream")
+	}
+}
+
+func TestMarketWS_AcceptsStandardClients(t *testing.T) {
+	app, _ := setupMockedServer(t, nil)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go app.Listener(ln)
+	t.Cleanup(func() { app.Shutdown() })
+
+	// gorilla sends Sec-WebSocket-Version, as browsers do; the upgrader
+	// looks it up as Sec-Websocket-Version
+	conn, resp, err := websocket.DefaultDialer.Dial("ws://"+ln.Addr().String()+"/ws/market/"+mockupstream.MarketID, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+}
+
+func TestMarketWS_RoutesByAssetID(t *testing.T) {
+	mock := mockupstream.New()
+	defer mock.Close()
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	cfg.Polymarket.WsShards = 1
+	cfg.Polymarket.WsLiveDataURL = ""
+
+	m := polymarket.NewWSManager(&cfg.Polymarket)
+	h := handlers.NewWebSocketHandler(m, nil, 100, wsbuffer.New(0, 0), wsreplay.New(0))
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use("/ws", handlers.WSMiddleware())
+	app.Get("/ws/market/:market_id", fiberws.New(h.HandleMarketWS))
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go app.Listener(ln)
+	t.Cleanup(func() {
+		app.Shutdown()
+		m.Close()
+		h.Close()
+	})
+	require.NoError(t, m.Connect())
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+ln.Addr().String()+"/ws/market/"+mockupstream.TokenYes, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Eventually(t, func() bool {
+		return len(mock.Subscriptions()) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Upstream messages name their token in asset_id and the condition ID in
+	// market; the mock pushes every frame to every connection
+	book := func(token string) string {
+		return `{"event_type":"book","asset_id":"` + token + `","market":"` + mockupstream.ConditionID + `","bids":[{"price":"0.5","size":"10"}],"asks":[]}`
+	}
+	trade := func(token string) string {
+		return `{"event_type":"last_trade_price","asset_id":"` + token + `","market":"` + mockupstream.ConditionID + `","price":"0.5","size":"1","side":"BUY"}`
+	}
+	mock.Push([]byte(`[` + book(mockupstream.TokenYes) + `,` + book(mockupstream.TokenNo) + `]`))
+	mock.Push([]byte(trade(mockupstream.TokenNo)))
+	mock.Push([]byte(trade(mockupstream.TokenYes)))
+
+	var got []string
+	for {
+		conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		var msg struct {
+			EventType string `json:"event_type"`
+			AssetID   string `json:"asset_id"`
+		}
+		require.NoError(t, sonic.Unmarshal(data, &msg))
+		got = append(got, msg.EventType+" "+msg.AssetID)
+	}
+	// Once each: the book split out of the batch, then the token's trade
+	assert.Equal(t, []string{
+		"book " + mockupstream.TokenYes,
+		"last_trade_price " + mockupstream.TokenYes,
+	}, got)
+}
+
+func TestTokenWS_FollowsSubscribedAssets(t *testing.T) {
+	mock := mockupstream.New()
+	defer mock.Close()
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	cfg.Polymarket.WsShards = 1
+	cfg.Polymarket.WsLiveDataURL = ""
+
+	m := polymarket.NewWSManager(&cfg.Polymarket)
+	h := handlers.NewWebSocketHandler(m, nil, 100, wsbuffer.New(0, 0), wsreplay.New(0))
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use("/ws", handlers.WSMiddleware())
+	app.Get("/ws/token/:token_id", fiberws.New(h.HandleTokenWS))
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go app.Listener(ln)
+	t.Cleanup(func() {
+		app.Shutdown()
+		m.Close()
+		h.Close()
+	})
+	require.NoError(t, m.Connect())
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+ln.Addr().String()+"/ws/token/"+mockupstream.TokenYes, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"subscribe","assets":["`+mockupstream.TokenNo+`"]}`)))
+	require.Eventually(t, func() bool {
+		return len(mock.Subscriptions()) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+
+	trade := func(token string) []byte {
+		return []byte(`{"event_type":"last_trade_price","asset_id":"` + token + `","market":"` + mockupstream.ConditionID + `","price":"0.5","size":"1","side":"BUY"}`)
+	}
+	received := func(c *websocket.Conn) []string {
+		var got []string
+		for {
+			c.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+			_, data, err := c.ReadMessage()
+			if err != nil {
+				return got
+			}
+			var msg struct {
+				AssetID string `json:"asset_id"`
+			}
+			require.NoError(t, sonic.Unmarshal(data, &msg))
+			got = append(got, msg.AssetID)
+		}
+	}
+
+	mock.Push(trade(mockupstream.TokenNo))
+	mock.Push(trade(mockupstream.TokenYes))
+	assert.Equal(t, []string{mockupstream.TokenNo, mockupstream.TokenYes}, received(conn))
+
+	// A timed-out read leaves the connection unusable, so check
+	// unsubscribing on a fresh one
+	other, _, err := websocket.DefaultDialer.Dial("ws://"+ln.Addr().String()+"/ws/token/"+mockupstream.TokenYes, nil)
+	require.NoError(t, err)
+	defer other.Close()
+	require.NoError(t, other.WriteMessage(websocket.TextMessage, []byte(`{"type":"subscribe","assets":["`+mockupstream.TokenNo+`"]}`)))
+	require.NoError(t, other.WriteMessage(websocket.TextMessage, []byte(`{"type":"unsubscribe","assets":["`+mockupstream.TokenYes+`"]}`)))
+	time.Sleep(100 * time.Millisecond)
+
+	mock.Push(trade(mockupstream.TokenYes))
+	mock.Push(trade(mockupstream.TokenNo))
+	assert.Equal(t, []string{mockupstream.TokenNo}, received(other))
+}
+
+func TestMarketWS_ReplaysMissedMessagesSince(t *testing.T) {
+	mock := mockupstream.New()
+	defer mock.Close()
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	cfg.Polymarket.WsShards = 1
+	cfg.Polymarket.WsLiveDataURL = ""
+
+	m := polymarket.NewWSManager(&cfg.Polymarket)
+	h := handlers.NewWebSocketHandler(m, nil, 100, wsbuffer.New(0, 0), wsreplay.New(time.Minute))
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use("/ws", handlers.WSMiddleware())
+	app.Get("/ws/market/:market_id", fiberws.New(h.HandleMarketWS))
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go app.Listener(ln)
+	t.Cleanup(func() {
+		app.Shutdown()
+		m.Close()
+		h.Close()
+	})
+	require.NoError(t, m.Connect())
+
+	type message struct {
+		Type      string `json:"type"`
+		StreamSeq uint64 `json:"stream_seq"`
+		Price     string `json:"price"`
+		Count     int    `json:"count"`
+		Complete  bool   `json:"complete"`
+	}
+	read := func(conn *websocket.Conn) message {
+		t.Helper()
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		var msg message
+		require.NoError(t, sonic.Unmarshal(data, &msg))
+		return msg
+	}
+	trade := func(price string) []byte {
+		return []byte(`{"event_type":"last_trade_price","asset_id":"` + mockupstream.TokenYes + `","market":"` + mockupstream.ConditionID + `","price":"` + price + `","size":"1","side":"BUY"}`)
+	}
+	url := "ws://" + ln.Addr().String() + "/ws/market/" + mockupstream.TokenYes
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return len(mock.Subscriptions()) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	mock.Push(trade("0.50"))
+	first := read(conn)
+	assert.Equal(t, "0.50", first.Price)
+	require.NotZero(t, first.StreamSeq)
+	conn.Close()
+
+	// Missed while disconnected
+	mock.Push(trade("0.51"))
+	mock.Push(trade("0.52"))
+	time.Sleep(100 * time.Millisecond)
+
+	conn, _, err = websocket.DefaultDialer.Dial(url+"?since="+strconv.FormatUint(first.StreamSeq, 10), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	assert.Equal(t, "0.51", read(conn).Price)
+	assert.Equal(t, "0.52", read(conn).Price)
+	notice := read(conn)
+	assert.Equal(t, "replayed", notice.Type)
+	assert.Equal(t, 2, notice.Count)
+	assert.True(t, notice.Complete)
+
+	mock.Push(trade("0.53"))
+	live := read(conn)
+	assert.Equal(t, "0.53", live.Price)
+	assert.Equal(t, first.StreamSeq+3, live.StreamSeq)
+
+	_, resp, err := websocket.DefaultDialer.Dial(url+"?since=abc", nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestMarketWS_NumbersMessagesAndReportsGaps(t *testing.T) {
+	mock := mockupstream.New()
+	defer mock.Close()
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	cfg.Polymarket.WsShards = 1
+	cfg.Polymarket.WsLiveDataURL = ""
+
+	m := polymarket.NewWSManager(&cfg.Polymarket)
+	h := handlers.NewWebSocketHandler(m, nil, 100, wsbuffer.New(0, 0), wsreplay.New(0))
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use("/ws", handlers.WSMiddleware())
+	app.Get("/ws/market/:market_id", fiberws.New(h.HandleMarketWS))
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go app.Listener(ln)
+	t.Cleanup(func() {
+		app.Shutdown()
+		m.Close()
+		h.Close()
+	})
+	require.NoError(t, m.Connect())
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+ln.Addr().String()+"/ws/market/"+mockupstream.TokenYes, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Eventually(t, func() bool {
+		return len(mock.Subscriptions()) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	type message struct {
+		Sub       string   `json:"sub"`
+		SubSeq    uint64   `json:"sub_seq"`
+		Type      string   `json:"type"`
+		EventType string   `json:"event_type"`
+		Reason    string   `json:"reason"`
+		Markets   []string `json:"markets"`
+	}
+	read := func() message {
+		t.Helper()
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		var msg message
+		require.NoError(t, sonic.Unmarshal(data, &msg))
+		return msg
+	}
+	trade := []byte(`{"event_type":"last_trade_price","asset_id":"` + mockupstream.TokenYes + `","market":"` + mockupstream.ConditionID + `","price":"0.5","size":"1","side":"BUY"}`)
+
+	mock.Push(trade)
+	mock.Push(trade)
+	assert.Equal(t, message{Sub: mockupstream.TokenYes, SubSeq: 1, EventType: "last_trade_price"}, read())
+	assert.Equal(t, message{Sub: mockupstream.TokenYes, SubSeq: 2, EventType: "last_trade_price"}, read())
+
+	// Messages sent while the upstream connection is down are lost
+	mock.DropWS()
+	assert.Equal(t, message{Sub: mockupstream.TokenYes, SubSeq: 3, Type: "gap", Reason: "upstream_reconnect", Markets: []string{mockupstream.TokenYes}}, read())
+
+	require.Eventually(t, func() bool {
+		return m.IsConnected() && mock.WSConnections() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	mock.Push(trade)
+	assert.Equal(t, message{Sub: mockupstream.TokenYes, SubSeq: 4, EventType: "last_trade_price"}, read())
+
+	// Each subscription is numbered on its own, and replies are not numbered
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{"type": "subscribe", "assets": []string{mockupstream.TokenNo}}))
+	require.Eventually(t, func() bool {
+		return len(mock.Subscriptions()) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{"type": "ping"}))
+	assert.Equal(t, message{Type: "pong"}, read())
+	mock.Push([]byte(`{"event_type":"last_trade_price","asset_id":"` + mockupstream.TokenNo + `","market":"` + mockupstream.ConditionID + `","price":"0.5","size":"1","side":"SELL"}`))
+	assert.Equal(t, message{Sub: mockupstream.TokenNo, SubSeq: 1, EventType: "last_trade_price"}, read())
+	mock.Push(trade)
+	assert.Equal(t, message{Sub: mockupstream.TokenYes, SubSeq: 5, EventType: "last_trade_price"}, read())
+}
+
+func TestAdminWSStats_ReportsSubscriptionsAndClients(t *testing.T) {
+	mock := mockupstream.New()
+	defer mock.Close()
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	cfg.Polymarket.WsShards = 1
+	cfg.Polymarket.WsLiveDataURL = ""
+
+	m := polymarket.NewWSManager(&cfg.Polymarket)
+	h := handlers.NewWebSocketHandler(m, nil, 100, wsbuffer.New(0, 0), wsreplay.New(0))
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use("/ws", handlers.WSMiddleware())
+	app.Get("/ws/market/:market_id", fiberws.New(h.HandleMarketWS))
+	app.Get("/ws/token/:token_id", fiberws.New(h.HandleTokenWS))
+	app.Get("/admin/ws/stats", h.GetStats)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go app.Listener(ln)
+	t.Cleanup(func() {
+		app.Shutdown()
+		m.Close()
+		h.Close()
+	})
+	require.NoError(t, m.Connect())
+
+	base := "ws://" + ln.Addr().String()
+	byMarket, _, err := websocket.DefaultDialer.Dial(base+"/ws/market/"+mockupstream.ConditionID, nil)
+	require.NoError(t, err)
+	defer byMarket.Close()
+	byToken, _, err := websocket.DefaultDialer.Dial(base+"/ws/token/"+mockupstream.TokenNo+"?encoding=msgpack", nil)
+	require.NoError(t, err)
+	defer byToken.Close()
+	require.Eventually(t, func() bool {
+		return len(mock.Subscriptions()) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Event time a second ago, so the upstream lag is about that
+	ts := strconv.FormatInt(time.Now().Add(-time.Second).UnixMilli(), 10)
+	trade := func(token string) []byte {
+		return []byte(`{"event_type":"last_trade_price","asset_id":"` + token + `","market":"` + mockupstream.ConditionID + `","price":"0.5","size":"1","side":"BUY","timestamp":"` + ts + `"}`)
+	}
+	mock.Push(trade(mockupstream.TokenYes))
+	mock.Push(trade(mockupstream.TokenNo))
+	for i := 0; i < 2; i++ {
+		byMarket.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, _, err := byMarket.ReadMessage()
+		require.NoError(t, err)
+	}
+	byToken.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err = byToken.ReadMessage()
+	require.NoError(t, err)
+
+	var stats handlers.WSStatsResponse
+	require.Eventually(t, func() bool {
+		resp, err := app.Test(httptest.NewRequest("GET", "/admin/ws/stats", nil), -1)
+		require.NoError(t, err)
+		var body struct {
+			Data handlers.WSStatsResponse `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		stats = body.Data
+		// Frames are counted once written
+		sent := 0
+		for _, c := range stats.Clients {
+			sent += int(c.SentFrames)
+		}
+		return sent == 3
+	}, 2*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, 2, stats.Connections)
+	require.Len(t, stats.Subscriptions, 2)
+	assert.Equal(t, mockupstream.ConditionID, stats.Subscriptions[0].Key, "busiest first")
+	assert.Equal(t, 1, stats.Subscriptions[0].Clients)
+	assert.Equal(t, uint64(2), stats.Subscriptions[0].Messages)
+	assert.Equal(t, mockupstream.TokenNo, stats.Subscriptions[1].Key)
+	assert.Equal(t, uint64(1), stats.Subscriptions[1].Messages)
+
+	require.Len(t, stats.Clients, 2)
+	assert.Equal(t, "/ws/market/"+mockupstream.ConditionID, stats.Clients[0].Path)
+	assert.Equal(t, []string{mockupstream.ConditionID}, stats.Clients[0].Subscriptions)
+	assert.Equal(t, map[string]uint64{mockupstream.ConditionID: 2}, stats.Clients[0].SubSeq)
+	assert.Equal(t, "msgpack", stats.Clients[1].Encoding)
+	assert.NotEmpty(t, stats.Clients[1].ClientIP)
+
+	assert.True(t, stats.Upstream.Connected)
+	assert.GreaterOrEqual(t, stats.Upstream.MaxLag, int64(1000))
+	assert.Less(t, stats.Upstream.MaxLag, int64(5000))
+}
+
+func TestTokenWS_V2EnvelopesPerToken(t *testing.T) {
+	mock := mockupstream.New()
+	defer mock.Close()
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	cfg.Polymarket.WsShards = 1
+	cfg.Polymarket.WsLiveDataURL = ""
+
+	m := polymarket.NewWSManager(&cfg.Polymarket)
+	h := handlers.NewWebSocketHandler(m, nil, 100, wsbuffer.New(0, 0), wsreplay.New(time.Minute))
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use("/ws", handlers.WSMiddleware())
+	app.Get("/ws/token/:token_id", fiberws.New(h.HandleTokenWS))
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go app.Listener(ln)
+	t.Cleanup(func() {
+		app.Shutdown()
+		m.Close()
+		h.Close()
+	})
+	require.NoError(t, m.Connect())
+
+	url := "ws://" + ln.Addr().String() + "/ws/token/" + mockupstream.TokenYes
+	v1, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer v1.Close()
+	v2, _, err := websocket.DefaultDialer.Dial(url+"?version=2", nil)
+	require.NoError(t, err)
+	defer v2.Close()
+	require.Eventually(t, func() bool {
+		return len(mock.Subscriptions()) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+
+	// One price change for both tokens of the market
+	mock.Push([]byte(`{"event_type":"price_change","market":"` + mockupstream.ConditionID + `","timestamp":"1757908892351","price_changes":[` +
+		`{"asset_id":"` + mockupstream.TokenYes + `","price":"0.5","size":"10","side":"BUY"},` +
+		`{"asset_id":"` + mockupstream.TokenNo + `","price":"0.5","size":"10","side":"SELL"}]}`))
+
+	type envelope struct {
+		Seq       uint64 `json:"seq"`
+		Type      string `json:"type"`
+		Token     string `json:"token"`
+		Market    string `json:"market"`
+		StreamSeq uint64 `json:"stream_seq"`
+		Timestamp int64  `json:"timestamp"`
+		Payload   struct {
+			StreamSeq    uint64 `json:"stream_seq"`
+			PriceChanges []struct {
+				AssetID string `json:"asset_id"`
+			} `json:"price_changes"`
+		} `json:"payload"`
+	}
+	v2.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var env envelope
+	require.NoError(t, v2.ReadJSON(&env))
+	assert.Equal(t, uint64(1), env.Seq)
+	assert.Equal(t, "price_change", env.Type)
+	assert.Equal(t, mockupstream.TokenYes, env.Token)
+	assert.Equal(t, mockupstream.ConditionID, env.Market)
+	assert.Equal(t, int64(1757908892351), env.Timestamp)
+	assert.NotZero(t, env.StreamSeq)
+	assert.Zero(t, env.Payload.StreamSeq, "stream_seq is lifted into the envelope")
+	require.Len(t, env.Payload.PriceChanges, 1, "only the followed token's entries")
+	assert.Equal(t, mockupstream.TokenYes, env.Payload.PriceChanges[0].AssetID)
+
+	// v1 clients still get the message as received
+	v1.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var raw struct {
+		Sub          string `json:"sub"`
+		SubSeq       uint64 `json:"sub_seq"`
+		EventType    string `json:"event_type"`
+		PriceChanges []struct {
+			AssetID string `json:"asset_id"`
+		} `json:"price_changes"`
+	}
+	require.NoError(t, v1.ReadJSON(&raw))
+	assert.Equal(t, mockupstream.TokenYes, raw.Sub)
+	assert.Equal(t, uint64(1), raw.SubSeq)
+	assert.Equal(t, "price_change", raw.EventType)
+	assert.Len(t, raw.PriceChanges, 2)
+
+	// PolyGo's own messages are wrapped too
+	require.NoError(t, v2.WriteMessage(websocket.TextMessage, []byte(`{"type":"ping"}`)))
+	var pong envelope
+	require.NoError(t, v2.ReadJSON(&pong))
+	assert.Zero(t, pong.Seq, "replies belong to no subscription")
+	assert.Equal(t, "pong", pong.Type)
+	assert.Empty(t, pong.Token)
+
+	_, resp, err := websocket.DefaultDialer.Dial(url+"?version=3", nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestV2_TypedResponsesShareV1Handlers(t *testing.T) {
+	app, mock, c := setupMockedServerWithCache(t, nil)
+
+	get := func(path string, out interface{}) *http.Response {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil), -1)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		require.Equal(t, 200, resp.StatusCode, string(body))
+		require.NoError(t, sonic.Unmarshal(body, out))
+		return resp
+	}
+
+	// v1 still relays Gamma's payload as is
+	var raw []map[string]interface{}
+	get("/api/v1/markets?limit=2", &raw)
+	require.Len(t, raw, 2)
+	assert.IsType(t, "", raw[0]["clobTokenIds"])
+	c.Wait()
+
+	var markets struct {
+		Data []struct {
+			ConditionID string  `json:"condition_id"`
+			Volume      float64 `json:"volume"`
+			Outcomes    []struct {
+				Name    string  `json:"name"`
+				TokenID string  `json:"token_id"`
+				Price   float64 `json:"price"`
+			} `json:"outcomes"`
+		} `json:"data"`
+		Meta struct {
+			NextCursor string `json:"next_cursor"`
+			Limit      int    `json:"limit"`
+		} `json:"meta"`
+	}
+	resp := get("/api/v2/markets?limit=2", &markets)
+	require.Len(t, markets.Data, 2)
+	assert.Equal(t, mockupstream.ConditionID, markets.Data[0].ConditionID)
+	assert.Equal(t, 150000.0, markets.Data[0].Volume)
+	require.Len(t, markets.Data[0].Outcomes, 2)
+	assert.Equal(t, "Yes", markets.Data[0].Outcomes[0].Name)
+	assert.Equal(t, mockupstream.TokenYes, markets.Data[0].Outcomes[0].TokenID)
+	assert.Equal(t, 0.5, markets.Data[0].Outcomes[0].Price)
+	assert.Equal(t, "2", markets.Meta.NextCursor)
+	assert.Equal(t, 2, markets.Meta.Limit)
+	assert.Equal(t, `<http://example.com/api/v2/markets?limit=2&cursor=2>; rel="next"`, resp.Header.Get("Link"))
+	assert.Len(t, mock.Requests(mockupstream.Gamma), 1, "both versions share the upstream call")
+
+	// Slug lookups return the market rather than a list of one
+	var market struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	get("/api/v2/markets/slug/mock-market-2", &market)
+	assert.Equal(t, mockupstream.OtherMarketID, market.Data.ID)
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v2/markets/slug/unknown", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+
+	// Book sides come best price first, as numbers
+	var book struct {
+		Data struct {
+			TokenID string `json:"token_id"`
+			Asks    []struct {
+				Price float64 `json:"price"`
+				Size  float64 `json:"size"`
+			} `json:"asks"`
+			Timestamp time.Time `json:"timestamp"`
+		} `json:"data"`
+		Meta struct {
+			CacheHit bool `json:"cache_hit"`
+		} `json:"meta"`
+	}
+	get("/api/v2/book/"+mockupstream.TokenYes, &book)
+	assert.Equal(t, mockupstream.TokenYes, book.Data.TokenID)
+	require.Len(t, book.Data.Asks, 2)
+	assert.Equal(t, 0.51, book.Data.Asks[0].Price)
+	assert.Equal(t, 100.0, book.Data.Asks[0].Size)
+	assert.Equal(t, time.UnixMilli(1700000000000).UTC(), book.Data.Timestamp)
+	assert.False(t, book.Meta.CacheHit)
+	// The cache stores entries asynchronously
+	require.Eventually(t, func() bool {
+		get("/api/v2/book/"+mockupstream.TokenYes, &book)
+		return book.Meta.CacheHit
+	}, time.Second, 10*time.Millisecond, "v2 reports X-Cache in meta")
+
+	// Token maps become lists
+	var mids struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	get("/api/v2/midpoints?token_ids="+mockupstream.TokenNo+","+mockupstream.TokenYes, &mids)
+	assert.Equal(t, []map[string]interface{}{
+		{"token_id": mockupstream.TokenNo, "mid": 0.5},
+		{"token_id": mockupstream.TokenYes, "mid": 0.5},
+	}, mids.Data)
+
+	// Writes answer with the same typed results
+	body := `{"tokenID":"` + mockupstream.TokenYes + `","side":"BUY","price":"0.5","size":"10"}`
+	req := httptest.NewRequest("POST", "/api/v2/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header["POLY-API-KEY"] = []string{"key"}
+	req.Header["POLY-TIMESTAMP"] = []string{"1700000000"}
+	req.Header["POLY-SIGNATURE"] = []string{"sig"}
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(resp.Body)
+	require.Equal(t, 200, resp.StatusCode, string(data))
+	var placed struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, sonic.Unmarshal(data, &placed))
+	assert.Equal(t, map[string]interface{}{"order_id": mockupstream.OrderID, "status": "LIVE", "success": true}, placed.Data)
+
+	// The raw proxy is a v1 passthrough only
+	app, _ = setupMockedServer(t, func(cfg *config.Config) { cfg.RawProxy.Enabled = true })
+	resp, err = app.Test(httptest.NewRequest("GET", "/api/v2/raw/clob/midpoint?token_id="+mockupstream.TokenYes, nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+}
+
+func TestProtobuf_NegotiatedOnQuoteEndpoints(t *testing.T) {
+	app, _ := setupMockedServer(t, nil)
+
+	get := func(path, accept string) (*http.Response, []byte) {
+		t.Helper()
+		req := httptest.NewRequest("GET", path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		require.Equal(t, 200, resp.StatusCode, string(body))
+		return resp, body
+	}
+
+	resp, body := get("/api/v1/book/"+mockupstream.TokenYes, "application/x-protobuf")
+	assert.Equal(t, pb.ContentType, resp.Header.Get("Content-Type"))
+	assert.Contains(t, resp.Header.Get("Vary"), "Accept")
+	var book pb.OrderBook
+	require.NoError(t, proto.Unmarshal(body, &book))
+	assert.Equal(t, mockupstream.TokenYes, book.TokenId)
+	require.NotEmpty(t, book.Bids)
+	assert.Greater(t, book.Bids[0].Price, book.Bids[len(book.Bids)-1].Price, "best bid first")
+
+	// Same schema on both versions, and smaller than the JSON it replaces
+	_, v2 := get("/api/v2/book/"+mockupstream.TokenYes, "application/x-protobuf")
+	assert.Equal(t, body, v2)
+	_, jsonBody := get("/api/v1/book/"+mockupstream.TokenYes, "")
+	assert.Less(t, len(body), len(jsonBody))
+
+	_, body = get("/api/v1/price/"+mockupstream.TokenYes+"?side=SELL", "application/x-protobuf")
+	var price pb.Price
+	require.NoError(t, proto.Unmarshal(body, &price))
+	assert.Equal(t, mockupstream.TokenYes, price.TokenId)
+	assert.Equal(t, "SELL", price.Side)
+	require.NotNil(t, price.Price)
+
+	_, body = get("/api/v1/snapshot?token_ids="+mockupstream.TokenYes, "application/x-protobuf")
+	var snaps pb.SnapshotList
+	require.NoError(t, proto.Unmarshal(body, &snaps))
+	require.Len(t, snaps.Tokens, 1)
+	assert.Equal(t, mockupstream.TokenYes, snaps.Tokens[0].TokenId)
+
+	// JSON stays the default, including when both are acceptable
+	resp, _ = get("/api/v1/snapshot?token_ids="+mockupstream.TokenYes, "application/json, application/x-protobuf;q=0.5")
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+}
+
+func TestCreateOrder_ResolvesPriceMode(t *testing.T) {
+	app, mock := setupMockedServer(t, nil)
+	post := func(body string) (int, string) {
+		req := httptest.NewRequest("POST", "/api/v1/orders", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header["POLY-API-KEY"] = []string{"key"}
+		req.Header["POLY-TIMESTAMP"] = []string{"1700000000"}
+		req.Header["POLY-SIGNATURE"] = []string{"sig"}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+
+	// The mock book's best bid is 0.49
+	status, body := post(`{"tokenID":"` + mockupstream.TokenYes + `","side":"BUY","price_mode":"best_bid+1tick","size":"10"}`)
+	require.Equal(t, 200, status, body)
+	assert.Contains(t, body, `"resolved_price":"0.5"`)
+	assert.Contains(t, body, `"price_mode":"best_bid+1tick"`)
+
+	requests := clobWrites(mock)
+	require.Len(t, requests, 1)
+	assert.Contains(t, string(requests[0].Body), `"price":"0.5"`)
+	assert.NotContains(t, string(requests[0].Body), "price_mode")
+
+	status, body = post(`{"tokenID":"` + mockupstream.TokenYes + `","side":"BUY","price_mode":"last+1tick","size":"10"}`)
+	assert.Equal(t, 400, status)
+	assert.Contains(t, body, `"field":"price_mode"`)
+
+	status, body = post(`{"tokenID":"` + mockupstream.TokenYes + `","side":"SELL","price_mode":"best_ask+60ticks","size":"10"}`)
+	assert.Equal(t, 422, status)
+	assert.Contains(t, body, `"code":"PRICE_UNRESOLVABLE"`)
+	assert.Len(t, clobWrites(mock), 1, "unresolved orders never reach the CLOB")
+}
+
+func TestCreateOrder_RejectsDuplicates(t *testing.T) {
+	app, mock := setupMockedServer(t, nil)
+	post := func(body string) (*http.Response, string) {
+		req := httptest.NewRequest("POST", "/api/v1/orders", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header["POLY-API-KEY"] = []string{"key"}
+		req.Header["POLY-TIMESTAMP"] = []string{"1700000000"}
+		req.Header["POLY-SIGNATURE"] = []string{"sig"}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		data, _ := io.ReadAll(resp.Body)
+		return resp, string(data)
+	}
+	order := `{"tokenID":"` + mockupstream.TokenYes + `","side":"BUY","price":"0.5","size":"10"}`
+
+	// An order the CLOB rejects can be sent again at once
+	mock.On(mockupstream.CLOB, "POST", "/order", 500, `{"error":"internal"}`).Times(1)
+	resp, body := post(order)
+	require.GreaterOrEqual(t, resp.StatusCode, 500, body)
+
+	resp, body = post(order)
+	require.Equal(t, 200, resp.StatusCode, body)
+
+	resp, body = post(order)
+	assert.Equal(t, 409, resp.StatusCode)
+	assert.Contains(t, body, `"code":"DUPLICATE_ORDER"`)
+	assert.Equal(t, "2", resp.Header.Get("Retry-After"))
+	assert.Len(t, clobWrites(mock), 2, "duplicates never reach the CLOB")
+}
+
+func TestAuditLog_RecordsOrdersAndAdminChanges(t *testing.T) {
+	app, _ := setupMockedServer(t, func(cfg *config.Config) {
+		cfg.Audit = config.AuditConfig{Enabled: true, Keep: 100}
+		cfg.Risk.Path = ""
+	})
+	order := `{"tokenID":"` + mockupstream.TokenYes + `","side":"BUY","price":"0.5","size":"10"}`
+	req := httptest.NewRequest("POST", "/api/v1/orders", strings.NewReader(order))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header["POLY-API-KEY"] = []string{"key"}
+	req.Header["POLY-TIMESTAMP"] = []string{"1700000000"}
+	req.Header["POLY-SIGNATURE"] = []string{"sig"}
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+
+	req = httptest.NewRequest("PUT", "/admin/risk/limits/key", strings.NewReader(`{"max_order_size":1000}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+
+	// Reads and unauthenticated requests are not recorded
+	app.Test(httptest.NewRequest("GET", "/api/v1/orders/open", nil), -1)
+	app.Test(httptest.NewRequest("POST", "/api/v1/orders", strings.NewReader(order)), -1)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/admin/audit", nil), -1)
+	require.NoError(t, err)
+	var result struct {
+		Data []audit.Entry `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Data, 2)
+	assert.Equal(t, "risk_limits.set", result.Data[0].Action)
+	assert.Equal(t, "admin", result.Data[0].Actor)
+	assert.Equal(t, "order.create", result.Data[1].Action)
+	assert.Equal(t, "key", result.Data[1].Actor)
+	assert.Equal(t, 200, result.Data[1].Status)
+	assert.Equal(t, audit.HashPayload([]byte(order)), result.Data[1].PayloadHash)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/admin/audit/export?format=csv&action=order.*", nil), -1)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(resp.Body)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[1], ",key,")
+	assert.Contains(t, resp.Header.Get("Content-Disposition"), ".csv")
+}
+
+func TestAccessControl_EnforcesRoles(t *testing.T) {
+	app, mock := setupMockedServer(t, func(cfg *config.Config) {
+		cfg.Admin.Token = "admin-token"
+		cfg.Access = config.AccessConfig{
+			Enabled: true,
+			Keys: []config.AccessKey{
+				{Name: "dashboard", Key: "view-key", Role: "viewer"},
+				{Name: "bot", Key: "trade-key", Role: "trader"},
+				{Name: "ops", Key: "ops-key", Role: "admin"},
+			},
+			DefaultRole: "viewer",
+		}
+	})
+	call := func(method, path, token, body string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header["POLY-API-KEY"] = []string{"key"}
+		req.Header["POLY-TIMESTAMP"] = []string{"1700000000"}
+		req.Header["POLY-SIGNATURE"] = []string{"sig"}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+	order := `{"tokenID":"` + mockupstream.TokenYes + `","side":"BUY","price":"0.5","size":"10"}`
+
+	assert.Equal(t, 200, call("GET", "/health", "", ""))
+	assert.Equal(t, 200, call("GET", "/api/v1/markets", "", ""), "callers without a token are viewers")
+	assert.Equal(t, 200, call("POST", "/api/v1/orders/preview", "view-key", order))
+	assert.Equal(t, 403, call("POST", "/api/v1/orders", "view-key", order))
+	assert.Equal(t, 403, call("POST", "/api/v1/orders", "", order))
+	assert.Equal(t, 401, call("GET", "/api/v1/markets", "bogus", ""))
+	assert.Empty(t, clobWrites(mock))
+
+	assert.Equal(t, 200, call("POST", "/api/v1/orders", "trade-key", order))
+	assert.Len(t, clobWrites(mock), 1)
+	assert.Equal(t, 403, call("GET", "/admin/config/effective", "trade-key", ""))
+
+	assert.Equal(t, 200, call("GET", "/admin/config/effective", "ops-key", ""))
+	assert.Equal(t, 200, call("GET", "/admin/config/effective", "admin-token", ""), "the admin token still works")
+}
+
+func TestAccessControl_OperatorRoutesIgnorePathCase(t *testing.T) {
+	// Without an admin token the operator routes rely on the admin role alone
+	app, _ := setupMockedServer(t, func(cfg *config.Config) {
+		cfg.CopyTrade.Enabled = true
+		cfg.Access = config.AccessConfig{
+			Enabled: true,
+			Keys: []config.AccessKey{
+				{Name: "dashboard", Key: "view-key", Role: "viewer"},
+				{Name: "ops", Key: "ops-key", Role: "admin"},
+			},
+			DefaultRole: "viewer",
+		}
+	})
+	call := func(path, token string) int {
+		req := httptest.NewRequest("GET", path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	for _, path := range []string{"/admin/config/effective", "/ADMIN/config/effective", "/Admin/Config/Effective", "/api/v1/CopyTrade/status", "/API/V2/COPYTRADE/STATUS"} {
+		assert.Equal(t, 403, call(path, "view-key"), path)
+		assert.Equal(t, 403, call(path, ""), path)
+		assert.Equal(t, 200, call(path, "ops-key"), path)
+	}
+}
+
+func TestWebhooks_SecretShownOnCreateAndRotate(t *testing.T) {
+	app, _ := setupMockedServer(t, nil)
+
+	post := func(path, body string) (int, map[string]interface{}) {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header["POLY-API-KEY"] = []string{"key"}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		var result struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		return resp.StatusCode, result.Data
+	}
+
+	status, _ := post("/api/v1/webhooks", `{"url":"https://example.com/hook","secret":"short"}`)
+	assert.Equal(t, 400, status)
+
+	status, created := post("/api/v1/webhooks", `{"url":"https://example.com/hook"}`)
+	require.Equal(t, 200, status)
+	id, _ := created["id"].(string)
+	secret, _ := created["secret"].(string)
+	assert.True(t, strings.HasPrefix(secret, "whsec_"))
+
+	req := httptest.NewRequest("GET", "/api/v1/webhooks", nil)
+	req.Header["POLY-API-KEY"] = []string{"key"}
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), id)
+	assert.NotContains(t, string(body), secret, "secrets are not listed")
+
+	status, rotated := post("/api/v1/webhooks/"+id+"/secret", `{"secret":"my-own-long-secret"}`)
+	require.Equal(t, 200, status)
+	assert.Equal(t, "my-own-long-secret", rotated["secret"])
+	assert.Equal(t, id, rotated["id"])
+
+	status, _ = post("/api/v1/webhooks/whk_missing/secret", "")
+	assert.Equal(t, 404, status)
+}
+
+func TestRequestID_EchoedOnOrdersAndErrors(t *testing.T) {
+	app, _ := setupMockedServer(t, nil)
+
+	send := func(method, path, key, requestID, body string) *http.Response {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header["POLY-API-KEY"] = []string{key}
+		req.Header["POLY-TIMESTAMP"] = []string{"1700000000"}
+		req.Header["POLY-SIGNATURE"] = []string{"sig"}
+		if requestID != "" {
+			req.Header.Set("X-Request-ID", requestID)
+		}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp
+	}
+	order := `{"tokenID":"` + mockupstream.TokenYes + `","side":"BUY","price":"0.5","size":"10"}`
+
+	resp := send("POST", "/api/v1/orders", "key", "trace-123", order)
+	require.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "trace-123", resp.Header.Get("X-Request-ID"))
+
+	// Another order, so the duplicate guard lets it through
+	resp = send("POST", "/api/v1/orders", "key", "", strings.Replace(order, `"10"`, `"11"`, 1))
+	require.Equal(t, 200, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get("X-Request-ID"))
+
+	// Errors carry the ID too, so failed calls can be reported
+	resp = send("GET", "/api/v1/webhooks/deliveries/dlv_missing", "key", "trace-404", "")
+	assert.Equal(t, 404, resp.StatusCode)
+	assert.Equal(t, "trace-404", resp.Header.Get("X-Request-ID"))
+}
+
+// startConnectProxy runs a minimal HTTP CONNECT proxy and returns its
+// address and how many tunnels it opened
+func startConnectProxy(t *testing.T) (string, *atomic.Int64) {
+	var tunnels atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			upstream.Close()
+			return
+		}
+		conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		tunnels.Add(1)
+		go func() {
+			defer upstream.Close()
+			defer conn.Close()
+			go io.Copy(upstream, conn)
+			io.Copy(conn, upstream)
+		}()
+	}))
+	t.Cleanup(srv.Close)
+	return srv.Listener.Addr().String(), &tunnels
+}
+
+func TestUpstreamProxy_CarriesRequestsAndReportsHealth(t *testing.T) {
+	addr, tunnels := startConnectProxy(t)
+	app, _ := setupMockedServer(t, func(cfg *config.Config) {
+		cfg.Polymarket.Proxy = "http://" + addr
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/markets", nil), -1)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+	assert.Positive(t, tunnels.Load(), "upstream requests go through the proxy")
+
+	health := func(app *fiber.App) map[string]string {
+		resp, err := app.Test(httptest.NewRequest("GET", "/health", nil), -1)
+		require.NoError(t, err)
+		var result struct {
+			Data struct {
+				Status   string            `json:"status"`
+				Services map[string]string `json:"services"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		result.Data.Services["status"] = result.Data.Status
+		return result.Data.Services
+	}
+	services := health(app)
+	assert.Equal(t, "reachable", services["proxy"])
+
+	// A dead proxy for the upstreams' host is reported as such
+	down, _ := setupMockedServer(t, func(cfg *config.Config) {
+		cfg.Polymarket.Proxies = map[string]string{"127.0.0.1": "socks5://127.0.0.1:1"}
+	})
+	services = health(down)
+	assert.Equal(t, "unreachable", services["proxy:127.0.0.1"])
+	assert.Equal(t, "unreachable", services["clob"])
+	assert.Equal(t, "degraded", services["status"])
+}
+
+func TestReplica_ServesReadsToTokenHolders(t *testing.T) {
+	app, mock := setupMockedServer(t, func(cfg *config.Config) {
+		cfg.Replication.ServeReplicas = true
+		cfg.Replication.Token = "s3cret"
+	})
+	replica := func(method, token string) *http.Response {
+		req := httptest.NewRequest(method, "/replica/gamma/markets", nil)
+		if token != "" {
+			req.Header.Set(config.ReplicaTokenHeader, token)
+		}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp
+	}
+
+	assert.Equal(t, 401, replica("GET", "").StatusCode)
+	assert.Equal(t, 401, replica("GET", "wrong").StatusCode)
+	assert.Empty(t, mock.Requests(mockupstream.Gamma))
+
+	resp := replica("GET", "s3cret")
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get(polymarket.TTLHeader))
+	assert.Equal(t, 200, replica("HEAD", "s3cret").StatusCode)
+
+	// Writes never go upstream through /replica, token or not
+	before := len(clobWrites(mock))
+	for _, method := range []string{"POST", "PUT", "PATCH", "DELETE"} {
+		req := httptest.NewRequest(method, "/replica/clob/order", strings.NewReader(`{}`))
+		req.Header.Set(config.ReplicaTokenHeader, "s3cret")
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		assert.Equal(t, 405, resp.StatusCode, method)
+	}
+	assert.Len(t, clobWrites(mock), before)
+}
+
+func TestReplica_RefusedWithoutToken(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Replication.ServeReplicas = true
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+
+	_, err = api.NewServer(cfg, c)
+	assert.ErrorContains(t, err, "replication token")
+}
+
+func TestUpstreamPool_StatsAndRuntimeTuning(t *testing.T) {
+	app, _ := setupMockedServer(t, func(cfg *config.Config) {
+		cfg.Admin.Token = "secret"
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/markets", nil), -1)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+
+	type pool struct {
+		Host     string `json:"host"`
+		MaxConns int    `json:"max_conns"`
+		Dialed   uint64 `json:"dialed"`
+	}
+	resp, err = app.Test(httptest.NewRequest("GET", "/stats", nil), -1)
+	require.NoError(t, err)
+	var stats struct {
+		Data struct {
+			UpstreamPools []pool `json:"upstream_pools"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&stats))
+	require.NotEmpty(t, stats.Data.UpstreamPools)
+	assert.Equal(t, 1000, stats.Data.UpstreamPools[0].MaxConns)
+	assert.Positive(t, stats.Data.UpstreamPools[0].Dialed)
+
+	tune := func(body string) *http.Response {
+		req := httptest.NewRequest("PUT", "/admin/upstream/pool", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp
+	}
+
+	host := stats.Data.UpstreamPools[0].Host
+	resp = tune(`{"host":"` + host + `","max_conns_per_host":8}`)
+	require.Equal(t, 200, resp.StatusCode)
+	var tuned struct {
+		Data []pool `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&tuned))
+	for _, p := range tuned.Data {
+		if p.Host == host {
+			assert.Equal(t, 8, p.MaxConns)
+		} else {
+			assert.Equal(t, 1000, p.MaxConns)
+		}
+	}
+
+	assert.Equal(t, 400, tune(`{"max_conns_per_host":0}`).StatusCode)
+}
+
+func TestUpstreamFailover_ServesFromMirrorAndReportsIt(t *testing.T) {
+	var gammaMirror string
+	app, mock := setupMockedServer(t, func(cfg *config.Config) {
+		// The mock's Gamma API stands in for a mirror of a base URL that is down
+		gammaMirror = cfg.Polymarket.GammaBaseURL
+		cfg.Polymarket.GammaBaseURL = "http://127.0.0.1:1"
+		cfg.Polymarket.GammaMirrors = []string{gammaMirror}
+		cfg.Polymarket.FailoverThreshold = 1
+		cfg.Polymarket.RetryCount = 0
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/markets", nil), -1)
+	require.NoError(t, err)
+	assert.NotEqual(t, 200, resp.StatusCode, "the first request finds the base URL down")
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/api/v1/markets", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.NotEmpty(t, mock.Requests(mockupstream.Gamma))
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/health", nil), -1)
+	require.NoError(t, err)
+	var health struct {
+		Data struct {
+			Status   string                      `json:"status"`
+			Services map[string]string           `json:"services"`
+			Failover []polymarket.FailoverStatus `json:"failover"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&health))
+	assert.Equal(t, "degraded", health.Data.Status)
+	assert.Equal(t, "reachable", health.Data.Services["gamma"], "the mirror in use is probed")
+	require.Len(t, health.Data.Failover, 1)
+	assert.True(t, health.Data.Failover[0].FailedOver)
+	assert.Equal(t, gammaMirror, health.Data.Failover[0].Active)
+}
+
+func TestRouting_AdminOrdersPassPreTradeChecks(t *testing.T) {
+	app, mock := setupMockedServer(t, func(cfg *config.Config) {
+		cfg.Admin.Token = "secret"
+		cfg.Routing = config.RoutingConfig{
+			Enabled: true,
+			Accounts: map[string]config.RoutingAccount{
+				"desk": {Address: "0x2222222222222222222222222222222222222222", APIKey: "desk-key", Secret: "c2VjcmV0", Passphrase: "pass"},
+			},
+			Routes: []config.OrderRoute{{Accounts: []string{"desk"}}},
+		}
+		cfg.Risk.Enabled = true
+		cfg.Risk.Default = config.RiskLimits{MaxOrderSize: 50}
+	})
+	post := func(body string) (int, string) {
+		req := httptest.NewRequest("POST", "/admin/orders", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		var result struct {
+			Error struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		return resp.StatusCode, result.Error.Code
+	}
+	order := `{"tokenID":"` + mockupstream.TokenYes + `","side":"BUY","price":"0.5","size":"10"}`
+
+	status, _ := post(order)
+	assert.Equal(t, 200, status)
+	status, code := post(order)
+	assert.Equal(t, 409, status, "the duplicate guard applies")
+	assert.Equal(t, "DUPLICATE_ORDER", code)
+	status, code = post(`{"tokenID":"` + mockupstream.TokenYes + `","side":"BUY","price":"0.5","size":"100"}`)
+	assert.Equal(t, 422, status, "risk limits apply")
+	assert.Equal(t, "RISK_MAX_ORDER_SIZE", code)
+	assert.Len(t, clobWrites(mock), 1)
+}
+
+func TestWatchlist_ScopedToAPIKey(t *testing.T) {
+	app, _ := setupMockedServer(t, nil)
+	const wallet = "0x1111111111111111111111111111111111111111"
+
+	// Keys of the same length reuse the same bytes of the request buffer
+	send := func(key, method, path, body string) string {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header["POLY-API-KEY"] = []string{key}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		data, _ := io.ReadAll(resp.Body)
+		return string(data)
+	}
+
+	send("key-AAAAAAAA", "POST", "/api/v1/watchlist/wallets", `{"address":"`+wallet+`"}`)
+	assert.NotContains(t, send("key-BBBBBBBB", "GET", "/api/v1/watchlist/wallets", ""), wallet)
+	send("key-BBBBBBBB", "DELETE", "/api/v1/watchlist/wallets/"+wallet, "")
+	assert.Contains(t, send("key-AAAAAAAA", "GET", "/api/v1/watchlist/wallets", ""), wallet)
+}
@@ -0,0 +1,99 @@
+package unit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/access"
+	"github.com/polygo/internal/config"
+)
+
+// signJWT builds an HS256 JWT of claims
+func signJWT(secret, claims string) string {
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + enc.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + enc.EncodeToString(mac.Sum(nil))
+}
+
+func newAccessControl(t *testing.T, defaultRole string) *access.Control {
+	acl, err := access.New(&config.AccessConfig{
+		Enabled: true,
+		Keys: []config.AccessKey{
+			{Name: "dashboard", Key: "view-key", Role: "viewer"},
+			{Name: "bot", Key: "trade-key", Role: "trader"},
+		},
+		JWTSecret:   "jwt-secret",
+		DefaultRole: defaultRole,
+	}, "admin-token")
+	require.NoError(t, err)
+	return acl
+}
+
+func TestAccess_ResolvesKeysAndAdminToken(t *testing.T) {
+	acl := newAccessControl(t, "viewer")
+
+	id, err := acl.Resolve("trade-key")
+	require.NoError(t, err)
+	assert.Equal(t, access.Identity{Name: "bot", Role: access.RoleTrader}, id)
+
+	id, err = acl.Resolve("admin-token")
+	require.NoError(t, err)
+	assert.Equal(t, access.RoleAdmin, id.Role)
+
+	id, err = acl.Resolve("")
+	require.NoError(t, err)
+	assert.Equal(t, access.Identity{Role: access.RoleViewer}, id, "callers without a token get the default role")
+
+	_, err = acl.Resolve("unknown")
+	assert.ErrorIs(t, err, access.ErrInvalidToken)
+
+	_, err = newAccessControl(t, "").Resolve("")
+	assert.ErrorIs(t, err, access.ErrTokenRequired)
+}
+
+func TestAccess_VerifiesJWTs(t *testing.T) {
+	acl := newAccessControl(t, "")
+	future := time.Now().Add(time.Hour).Unix()
+
+	id, err := acl.Resolve(signJWT("jwt-secret", `{"sub":"alice","role":"admin","exp":`+strconv.FormatInt(future, 10)+`}`))
+	require.NoError(t, err)
+	assert.Equal(t, access.Identity{Name: "jwt:alice", Role: access.RoleAdmin}, id)
+
+	for name, token := range map[string]string{
+		"wrong secret": signJWT("other", `{"sub":"alice","role":"admin"}`),
+		"expired":      signJWT("jwt-secret", `{"sub":"alice","role":"trader","exp":1}`),
+		"not yet":      signJWT("jwt-secret", `{"sub":"alice","role":"trader","nbf":`+strconv.FormatInt(future, 10)+`}`),
+		"no role":      signJWT("jwt-secret", `{"sub":"alice"}`),
+		"bad role":     signJWT("jwt-secret", `{"sub":"alice","role":"root"}`),
+		"malformed":    "a.b.c",
+	} {
+		_, err := acl.Resolve(token)
+		assert.ErrorIs(t, err, access.ErrInvalidToken, name)
+	}
+}
+
+func TestAccess_RolesAndConfig(t *testing.T) {
+	assert.True(t, access.RoleAdmin.Allows(access.RoleTrader))
+	assert.True(t, access.RoleTrader.Allows(access.RoleViewer))
+	assert.False(t, access.RoleViewer.Allows(access.RoleTrader))
+	assert.False(t, access.Role("").Allows(access.RoleViewer))
+
+	for name, cfg := range map[string]config.AccessConfig{
+		"bad role":     {Enabled: true, Keys: []config.AccessKey{{Key: "k", Role: "root"}}},
+		"empty key":    {Enabled: true, Keys: []config.AccessKey{{Name: "x", Role: "viewer"}}},
+		"duplicate":    {Enabled: true, Keys: []config.AccessKey{{Key: "k", Role: "viewer"}, {Key: "k", Role: "admin"}}},
+		"default role": {Enabled: true, DefaultRole: "guest"},
+	} {
+		_, err := access.New(&cfg, "")
+		assert.ErrorIs(t, err, access.ErrInvalidConfig, name)
+	}
+}
@@ -0,0 +1,102 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/analytics"
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/models"
+)
+
+func rankEntries() []*catalog.Entry {
+	return []*catalog.Entry{
+		{Market: models.Market{ID: "a", ConditionID: "0xa", Volume24hr: "100", Liquidity: "5000", Spread: "0.02"}},
+		{Market: models.Market{ID: "b", ConditionID: "0xb", Volume24hr: "900", Liquidity: "1000", Spread: "0.01"}},
+		{Market: models.Market{ID: "c", ConditionID: "0xc", Volume24hr: "500", Liquidity: "3000"}},
+	}
+}
+
+func ids(ranked []analytics.RankedMarket) []string {
+	out := make([]string, len(ranked))
+	for i, r := range ranked {
+		out[i] = r.ID
+	}
+	return out
+}
+
+func TestRank_ByVolumeAndLiquidity(t *testing.T) {
+	assert.Equal(t, []string{"b", "c", "a"}, ids(analytics.Rank(rankEntries(), nil, analytics.ByVolume)))
+	assert.Equal(t, []string{"a", "c", "b"}, ids(analytics.Rank(rankEntries(), nil, analytics.ByLiquidity)))
+}
+
+func TestRank_BySpreadSkipsUnquotedMarkets(t *testing.T) {
+	assert.Equal(t, []string{"b", "a"}, ids(analytics.Rank(rankEntries(), nil, analytics.BySpread)))
+}
+
+func TestRank_ByTradeCount(t *testing.T) {
+	counter := analytics.NewTradeCounter(nil, &config.AnalyticsConfig{TradeWindow: 24 * time.Hour})
+	now := time.Now()
+
+	counter.Add("0xc", "t1", now)
+	counter.Add("0xc", "t2", now)
+	counter.Add("0xc", "t2", now) // duplicate from an overlapping poll
+	counter.Add("0xa", "t3", now)
+	counter.Add("0xb", "t4", now.Add(-48*time.Hour)) // outside the window
+
+	ranked := analytics.Rank(rankEntries(), counter, analytics.ByTrades)
+	require.Len(t, ranked, 3)
+	assert.Equal(t, []string{"c", "a", "b"}, ids(ranked))
+	assert.Equal(t, 2, ranked[0].Trades)
+	assert.Equal(t, 0, ranked[2].Trades)
+}
+
+func TestCorrelate_PairsPriceChanges(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := func(prices ...float64) []analytics.PricePoint {
+		out := make([]analytics.PricePoint, len(prices))
+		for i, p := range prices {
+			out[i] = analytics.PricePoint{Time: start.Add(time.Duration(i) * time.Minute), Price: p}
+		}
+		return out
+	}
+
+	series := map[string][]analytics.PricePoint{
+		"yes":  points(0.40, 0.45, 0.42, 0.50, 0.48),
+		"no":   points(0.60, 0.55, 0.58, 0.50, 0.52),
+		"flat": points(0.30, 0.30, 0.30, 0.30, 0.30),
+	}
+	m := analytics.Correlate([]string{"yes", "no", "flat", "unknown"}, series)
+
+	require.NotNil(t, m.Matrix[0][0])
+	assert.InDelta(t, 1.0, *m.Matrix[0][0], 1e-9)
+	require.NotNil(t, m.Matrix[0][1])
+	assert.InDelta(t, -1.0, *m.Matrix[0][1], 1e-9)
+	assert.Equal(t, m.Matrix[0][1], m.Matrix[1][0])
+	assert.Equal(t, 4, m.Samples[0][1])
+
+	assert.Nil(t, m.Matrix[0][2], "a token that never moved has no correlation")
+	assert.Nil(t, m.Matrix[0][3])
+	assert.Equal(t, 0, m.Samples[0][3])
+	assert.Equal(t, []string{"unknown"}, m.Missing)
+}
+
+func TestCorrelate_AlignsOnSharedTimestamps(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(min int, price float64) analytics.PricePoint {
+		return analytics.PricePoint{Time: start.Add(time.Duration(min) * time.Minute), Price: price}
+	}
+
+	series := map[string][]analytics.PricePoint{
+		"a": {at(0, 0.1), at(1, 0.2), at(2, 0.4), at(3, 0.3), at(4, 0.5)},
+		"b": {at(1, 0.6), at(3, 0.7), at(4, 0.9)},
+	}
+	m := analytics.Correlate([]string{"a", "b"}, series)
+
+	assert.Equal(t, 2, m.Samples[0][1], "only minutes 1, 3 and 4 are shared")
+	assert.Nil(t, m.Matrix[0][1])
+}
@@ -0,0 +1,83 @@
+package unit
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/audit"
+	"github.com/polygo/internal/config"
+)
+
+func TestAudit_QueriesNewestFirstWithFilters(t *testing.T) {
+	l, err := audit.New(&config.AuditConfig{Enabled: true, Keep: 3})
+	require.NoError(t, err)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, action := range []string{"order.create", "webhook.create", "order.cancel", "order.create"} {
+		l.Record(audit.Entry{Actor: "key", Action: action, Time: start.Add(time.Duration(i) * time.Hour)})
+	}
+	l.Record(audit.Entry{Actor: "admin", Action: "rule.create", Time: start.Add(4 * time.Hour)})
+
+	all := l.Query(audit.Filter{})
+	require.Len(t, all, 3, "only the latest Keep are searched")
+	assert.Equal(t, int64(5), all[0].Seq)
+	assert.Equal(t, int64(3), all[2].Seq)
+
+	orders := l.Query(audit.Filter{Action: "order.*", Actor: "key"})
+	require.Len(t, orders, 2)
+	assert.Equal(t, "order.create", orders[0].Action)
+
+	window := l.Query(audit.Filter{Since: start.Add(2 * time.Hour), Until: start.Add(4 * time.Hour)})
+	require.Len(t, window, 2, "until is exclusive")
+	assert.Empty(t, l.Query(audit.Filter{Action: "order"}))
+}
+
+func TestAudit_AppendsToFileAndExportsAll(t *testing.T) {
+	cfg := &config.AuditConfig{Enabled: true, Path: filepath.Join(t.TempDir(), "audit", "log.jsonl"), Keep: 2}
+	l, err := audit.New(cfg)
+	require.NoError(t, err)
+	l.Record(audit.Entry{Actor: "key", Action: "order.create", PayloadHash: audit.HashPayload([]byte(`{}`))})
+	l.Record(audit.Entry{Actor: "key", Action: "order.cancel"})
+	l.Record(audit.Entry{Actor: "admin", Action: "cache.purge"})
+	require.NoError(t, l.Close())
+
+	// Reopened, numbering carries on
+	l, err = audit.New(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	l.Record(audit.Entry{Actor: "key", Action: "webhook.delete"})
+	latest := l.Query(audit.Filter{})
+	require.Len(t, latest, 2)
+	assert.Equal(t, int64(4), latest[0].Seq)
+
+	// Exports read the whole file, oldest first
+	var buf bytes.Buffer
+	require.NoError(t, l.Export(&buf, audit.Filter{Actor: "key"}, audit.FormatJSONL))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], `"seq":1`)
+	assert.Contains(t, lines[0], audit.HashPayload([]byte(`{}`)))
+
+	buf.Reset()
+	require.NoError(t, l.Export(&buf, audit.Filter{Action: "cache.purge"}, audit.FormatCSV))
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "seq,time,actor"))
+	assert.True(t, strings.HasPrefix(lines[1], "3,"))
+
+	assert.ErrorIs(t, l.Export(&buf, audit.Filter{}, "xml"), audit.ErrInvalidFormat)
+}
+
+func TestAudit_DisabledRecordsNothing(t *testing.T) {
+	l, err := audit.New(&config.AuditConfig{Path: filepath.Join(t.TempDir(), "log.jsonl")})
+	require.NoError(t, err)
+	l.Record(audit.Entry{Actor: "key", Action: "order.create"})
+	assert.Empty(t, l.Query(audit.Filter{}))
+	assert.Empty(t, audit.HashPayload(nil))
+}
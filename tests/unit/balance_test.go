@@ -0,0 +1,83 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/polymarket"
+)
+
+func newBalanceChecker(t *testing.T) (*polymarket.BalanceChecker, *mockupstream.Server) {
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	return polymarket.NewBalanceChecker(polymarket.NewClobClient(polymarket.NewClient(&cfg.Polymarket, c))), mock
+}
+
+func TestBalance_FundedAccountCanTrade(t *testing.T) {
+	checker, mock := newBalanceChecker(t)
+
+	report, err := checker.Report("1", map[string]string{"POLY-API-KEY": "key"})
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, report.Balance)
+	assert.Equal(t, 100.0, report.Available)
+	assert.Len(t, report.Allowances, 1)
+	assert.True(t, report.CanTrade)
+	assert.Empty(t, report.Warnings)
+
+	var balanceReq *mockupstream.Request
+	for _, r := range mock.Requests(mockupstream.CLOB) {
+		if r.Path == "/balance-allowance" {
+			balanceReq = &r
+		}
+	}
+	require.NotNil(t, balanceReq)
+	assert.Contains(t, balanceReq.Query, "asset_type=COLLATERAL")
+	assert.Contains(t, balanceReq.Query, "signature_type=1")
+	assert.Equal(t, "key", balanceReq.Header.Get("POLY-API-KEY"))
+}
+
+func TestBalance_WarnsWhenOpenOrdersAreNotCovered(t *testing.T) {
+	checker, mock := newBalanceChecker(t)
+	mock.On(mockupstream.CLOB, "GET", "/balance-allowance", 200, `{"balance":"30000000","allowances":{"0xexchange":"20000000","0xnegrisk":"0"}}`)
+	mock.On(mockupstream.CLOB, "GET", "/orders/open", 200, `[
+		{"asset_id":"a","side":"BUY","price":"0.5","original_size":"100","size_matched":"20"},
+		{"asset_id":"b","side":"SELL","price":"0.6","original_size":"50","size_matched":"0"}
+	]`)
+
+	report, err := checker.Report("", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 40.0, report.OpenBuyNotional)
+	assert.Equal(t, 0.0, report.Available)
+	assert.False(t, report.CanTrade, "an exchange has no allowance")
+
+	require.Len(t, report.Tokens, 1)
+	assert.Equal(t, "b", report.Tokens[0].TokenID)
+	assert.Equal(t, 50.0, report.Tokens[0].Required)
+
+	joined := ""
+	for _, w := range report.Warnings {
+		joined += w + "\n"
+	}
+	assert.Contains(t, joined, "No USDC allowance for 0xnegrisk")
+	assert.Contains(t, joined, "USDC allowance for 0xexchange (20) is below the 40")
+	assert.Contains(t, joined, "USDC balance (30) is below the 40")
+	// The token lookup gets the same reply: 30 shares, one zero allowance
+	assert.Contains(t, joined, "Token b balance (30 shares) is below the 50")
+	assert.Contains(t, joined, "Token b is not approved")
+
+	mock.On(mockupstream.CLOB, "GET", "/balance-allowance", 500, `{}`)
+	_, err = checker.Report("", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,72 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+)
+
+func bookMessage(bids, asks string) []byte {
+	return []byte(`{"event_type":"book","asset_id":"tok","market":"0xm","bids":` + bids + `,"asks":` + asks + `,"timestamp":"1700000000000"}`)
+}
+
+func decodeUpdate(t *testing.T, data []byte) polymarket.BookUpdate {
+	t.Helper()
+	var u polymarket.BookUpdate
+	require.NoError(t, json.Unmarshal(data, &u))
+	return u
+}
+
+func TestBookDiffer_SnapshotThenDeltas(t *testing.T) {
+	d := polymarket.NewBookDiffer(100)
+
+	out, ok := d.Apply(bookMessage(`[{"price":"0.48","size":"10"},{"price":"0.49","size":"5"}]`, `[{"price":"0.51","size":"7"}]`))
+	require.True(t, ok)
+	first := decodeUpdate(t, out)
+	assert.Equal(t, polymarket.BookEventSnapshot, first.EventType)
+	assert.Equal(t, uint64(1), first.Seq)
+	assert.Equal(t, "0.49", first.Bids[0].Price, "best bid first")
+
+	// 0.49 resized, 0.48 removed, ask unchanged
+	out, ok = d.Apply(bookMessage(`[{"price":"0.49","size":"8"}]`, `[{"price":"0.51","size":"7"}]`))
+	require.True(t, ok)
+	delta := decodeUpdate(t, out)
+	assert.Equal(t, polymarket.BookEventDelta, delta.EventType)
+	assert.Equal(t, uint64(2), delta.Seq)
+	assert.Equal(t, []models.PriceLevel{{Price: "0.49", Size: "8"}, {Price: "0.48", Size: "0"}}, delta.Bids)
+	assert.Empty(t, delta.Asks)
+
+	// Identical book: nothing to send, sequence unchanged
+	out, ok = d.Apply(bookMessage(`[{"price":"0.49","size":"8"}]`, `[{"price":"0.51","size":"7"}]`))
+	assert.True(t, ok)
+	assert.Nil(t, out)
+
+	snaps := d.Snapshots("0xm")
+	require.Len(t, snaps, 1)
+	assert.Equal(t, uint64(2), decodeUpdate(t, snaps[0]).Seq)
+	assert.Empty(t, d.Snapshots("0xother"))
+}
+
+func TestBookDiffer_PeriodicSnapshot(t *testing.T) {
+	d := polymarket.NewBookDiffer(2)
+
+	d.Apply(bookMessage(`[{"price":"0.4","size":"1"}]`, `[]`))
+	out, _ := d.Apply(bookMessage(`[{"price":"0.4","size":"2"}]`, `[]`))
+	assert.Equal(t, polymarket.BookEventDelta, decodeUpdate(t, out).EventType)
+
+	out, _ = d.Apply(bookMessage(`[{"price":"0.4","size":"3"}]`, `[]`))
+	resync := decodeUpdate(t, out)
+	assert.Equal(t, polymarket.BookEventSnapshot, resync.EventType)
+	assert.Equal(t, uint64(3), resync.Seq)
+}
+
+func TestBookDiffer_IgnoresOtherEvents(t *testing.T) {
+	d := polymarket.NewBookDiffer(10)
+	_, ok := d.Apply([]byte(`{"event_type":"price_change","asset_id":"tok","market":"0xm"}`))
+	assert.False(t, ok)
+}
@@ -1,6 +1,8 @@
 package unit
 
 import (
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -191,3 +193,160 @@ func BenchmarkCache_Get(b *testing.B) {
 		c.Get("bench-key")
 	}
 }
+
+// benchPayload is a busy order book, about 32KB
+var benchPayload = func() []byte {
+	levels := strings.TrimSuffix(strings.Repeat(`{"price":"0.51","size":"1200"},`, 512), ",")
+	return []byte(`{"token_id":"1","bids":[` + levels + `],"asks":[` + levels + `],"hash":"0x","timestamp":1}`)
+}()
+
+func BenchmarkCache_SetPayload(b *testing.B) {
+	c, _ := cache.New(&config.CacheConfig{MaxCost: 1 << 30, NumCounters: 1e7, BufferItems: 64})
+	defer c.Close()
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchPayload)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Set("bench-key", benchPayload, time.Minute)
+	}
+}
+
+func BenchmarkCache_SetNoCopyPayload(b *testing.B) {
+	c, _ := cache.New(&config.CacheConfig{MaxCost: 1 << 30, NumCounters: 1e7, BufferItems: 64})
+	defer c.Close()
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchPayload)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.SetNoCopy("bench-key", benchPayload, time.Minute)
+	}
+}
+
+func TestCache_SetCopiesAndSetNoCopyShares(t *testing.T) {
+	c, err := cache.New(&config.DefaultConfig().Cache)
+	require.NoError(t, err)
+	defer c.Close()
+
+	value := []byte("value")
+	c.Set("copied", value, time.Minute)
+	c.SetNoCopy("shared", value, time.Minute)
+	c.Wait()
+	value[0] = 'V'
+
+	data, _ := c.Get("copied")
+	assert.Equal(t, []byte("value"), data)
+	data, _ = c.Get("shared")
+	assert.Equal(t, []byte("Value"), data)
+}
+
+func TestCache_RejectsOversizeEntries(t *testing.T) {
+	cfg := &config.CacheConfig{
+		MaxCost:      1 << 20,
+		NumCounters:  1e6,
+		BufferItems:  64,
+		DefaultTTL:   time.Second,
+		MaxEntrySize: 16,
+	}
+
+	c, err := cache.New(cfg)
+	require.NoError(t, err)
+	defer c.Close()
+
+	ok := c.Set("big", make([]byte, 17), time.Minute)
+	assert.False(t, ok)
+	c.Wait()
+
+	_, found := c.Get("big")
+	assert.False(t, found)
+	assert.Equal(t, uint64(1), c.SizeStats().RejectedOversize)
+}
+
+func TestCache_CompressesLargeEntries(t *testing.T) {
+	for _, codec := range []string{cache.CompressionSnappy, cache.CompressionZstd} {
+		t.Run(codec, func(t *testing.T) {
+			cfg := &config.CacheConfig{
+				MaxCost:           1 << 20,
+				NumCounters:       1e6,
+				BufferItems:       64,
+				DefaultTTL:        time.Second,
+				Compression:       codec,
+				CompressThreshold: 1024,
+			}
+
+			c, err := cache.New(cfg)
+			require.NoError(t, err)
+			defer c.Close()
+
+			value := []byte(strings.Repeat(`{"id":"123","question":"Will it happen?"},`, 200))
+			require.True(t, c.Set("markets:list", value, time.Minute))
+			c.Wait()
+
+			result, found := c.Get("markets:list")
+			require.True(t, found)
+			assert.Equal(t, value, result)
+
+			stats := c.SizeStats()
+			assert.Equal(t, codec, stats.Compression)
+			assert.Equal(t, uint64(1), stats.Compressed)
+			assert.NotZero(t, stats.BytesSaved)
+		})
+	}
+}
+
+func TestCache_UnknownCompressionFails(t *testing.T) {
+	_, err := cache.New(&config.CacheConfig{
+		MaxCost:     1 << 20,
+		NumCounters: 1e6,
+		BufferItems: 64,
+		Compression: "lz4",
+	})
+	assert.Error(t, err)
+}
+
+func TestCache_PersistsPrefixedEntriesAcrossRestarts(t *testing.T) {
+	cfg := config.DefaultConfig().Cache
+	cfg.PersistPath = filepath.Join(t.TempDir(), "cache", "snapshot.json")
+	cfg.PersistPrefixes = []string{cache.PrefixMarkets, cache.PrefixEvents}
+	cfg.TTLJitter = 0
+
+	c, err := cache.New(&cfg)
+	require.NoError(t, err)
+	c.Set(cache.MarketKey("1"), []byte("market"), time.Minute)
+	c.Set(cache.EventKey("1"), []byte("event"), 10*time.Millisecond)
+	c.Set(cache.OrderBookKey("1"), []byte("book"), time.Minute)
+	c.Wait()
+	time.Sleep(20 * time.Millisecond)
+
+	n, err := c.Save()
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "only fresh entries under the prefixes are saved")
+	c.Close()
+
+	restarted, err := cache.New(&cfg)
+	require.NoError(t, err)
+	defer restarted.Close()
+	n, err = restarted.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	data, found := restarted.Get(cache.MarketKey("1"))
+	assert.True(t, found)
+	assert.Equal(t, []byte("market"), data)
+	_, found = restarted.Get(cache.OrderBookKey("1"))
+	assert.False(t, found)
+}
+
+func TestCache_LoadWithoutSnapshot(t *testing.T) {
+	cfg := config.DefaultConfig().Cache
+	cfg.PersistPath = filepath.Join(t.TempDir(), "missing.json")
+
+	c, err := cache.New(&cfg)
+	require.NoError(t, err)
+	defer c.Close()
+
+	n, err := c.Load()
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+}
@@ -0,0 +1,206 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/models"
+)
+
+func TestMarket_DecodesGammaEncodedFields(t *testing.T) {
+	raw := `{
+		"id": "123",
+		"question": "Will BTC hit 100k?",
+		"outcomes": "[\"Yes\", \"No\"]",
+		"outcomePrices": "[\"0.65\", \"0.35\"]",
+		"clobTokenIds": ["111", "222"],
+		"volume": "1500.5",
+		"volume24hr": 250.25
+	}`
+
+	var m models.Market
+	require.NoError(t, sonic.Unmarshal([]byte(raw), &m))
+
+	assert.Equal(t, models.StringList{"Yes", "No"}, m.Outcomes)
+	assert.Equal(t, []float64{0.65, 0.35}, m.OutcomePrices.Floats())
+	assert.Equal(t, models.StringList{"111", "222"}, m.ClobTokenIDs)
+	assert.Equal(t, 1500.5, m.Volume.Float())
+	assert.Equal(t, 250.25, m.Volume24hr.Float())
+
+	out, err := sonic.Marshal(m.Outcomes)
+	require.NoError(t, err)
+	assert.JSONEq(t, `["Yes","No"]`, string(out))
+}
+
+func TestClassifier_PrefersTags(t *testing.T) {
+	c := catalog.NewClassifier(nil)
+
+	entry := &catalog.Entry{
+		Market: models.Market{Question: "Who will win the match?"},
+		Tags:   []models.Tag{{Slug: "crypto"}},
+	}
+	assert.Equal(t, catalog.CategoryCrypto, c.Classify(entry))
+}
+
+func TestClassifier_UsesQuestionKeywords(t *testing.T) {
+	c := catalog.NewClassifier(nil)
+
+	cases := map[string]string{
+		"Will Bitcoin reach $100k by June?":            catalog.CategoryCrypto,
+		"Will the Fed cut interest rates in March?":    catalog.CategoryEconomics,
+		"Who will win the 2028 presidential election?": catalog.CategoryPolitics,
+		"Will the Lakers make the NBA playoffs?":       catalog.CategorySports,
+		"Will it snow in Paris on Christmas?":          catalog.CategoryOther,
+	}
+	for question, want := range cases {
+		entry := &catalog.Entry{Market: models.Market{Question: question}}
+		assert.Equal(t, want, c.Classify(entry), question)
+	}
+}
+
+func TestClassifier_ExtraKeywordsFromConfig(t *testing.T) {
+	c := catalog.NewClassifier(map[string][]string{"weather": {"snow", "hurricane"}})
+
+	entry := &catalog.Entry{Market: models.Market{Question: "Will it snow in Paris on Christmas?"}}
+	assert.Equal(t, "weather", c.Classify(entry))
+}
+
+func TestCatalog_LoadAssignsCategories(t *testing.T) {
+	cat := catalog.New(nil, &config.CatalogConfig{})
+	cat.Load([]*models.Event{{
+		ID:    "e1",
+		Title: "Super Bowl",
+		Tags:  []models.Tag{{Slug: "sports"}},
+		Markets: []models.Market{
+			{ID: "m1", Question: "Will the Chiefs win?"},
+			{ID: "m2", Question: "Will the Eagles win?"},
+		},
+	}})
+
+	markets := cat.Markets()
+	require.Len(t, markets, 2)
+	for _, m := range markets {
+		assert.Equal(t, catalog.CategorySports, m.Category)
+		assert.Equal(t, "e1", m.EventID)
+	}
+
+	entry, ok := cat.Market("m1")
+	require.True(t, ok)
+	assert.Equal(t, "Super Bowl", entry.EventTitle)
+}
+
+func TestCatalog_TagsCountsEventsAndMarkets(t *testing.T) {
+	cat := catalog.New(nil, &config.CatalogConfig{})
+	cat.Load([]*models.Event{
+		{
+			ID:      "e1",
+			Tags:    []models.Tag{{Slug: "sports", Label: "Sports"}, {Slug: "nfl", Label: "NFL"}},
+			Markets: []models.Market{{ID: "m1"}, {ID: "m2"}},
+		},
+		{
+			ID:      "e2",
+			Tags:    []models.Tag{{Slug: "sports", Label: "Sports"}},
+			Markets: []models.Market{{ID: "m3"}},
+		},
+	})
+
+	tags := cat.Tags()
+	require.Len(t, tags, 2)
+	assert.Equal(t, catalog.TagCount{Slug: "sports", Label: "Sports", Events: 2, Markets: 3}, tags[0])
+	assert.Equal(t, catalog.TagCount{Slug: "nfl", Label: "NFL", Events: 1, Markets: 2}, tags[1])
+
+	assert.Len(t, cat.MarketsByTag("NFL"), 2)
+	assert.Empty(t, cat.MarketsByTag("politics"))
+}
+
+func TestCatalog_FilterAppliesThresholdsDatesAndOrder(t *testing.T) {
+	cat := catalog.New(nil, &config.CatalogConfig{})
+	cat.Load([]*models.Event{{
+		ID: "e1",
+		Markets: []models.Market{
+			{ID: "m1", Volume: "500", Liquidity: "100", EndDate: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+			{ID: "m2", Volume: "5000", Liquidity: "50", EndDate: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)},
+			{ID: "m3", Volume: "2000", Liquidity: "800", EndDate: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)},
+			{ID: "m4", Volume: "9000", Liquidity: "900"},
+		},
+	}})
+
+	ids := func(entries []*catalog.Entry) []string {
+		out := make([]string, len(entries))
+		for i, e := range entries {
+			out[i] = e.ID
+		}
+		return out
+	}
+
+	got := cat.Filter(&models.MarketQueryParams{MinVolume: 1000, Order: "volume"})
+	assert.Equal(t, []string{"m4", "m2", "m3"}, ids(got))
+
+	got = cat.Filter(&models.MarketQueryParams{MinLiquidity: 100, EndDateBefore: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)})
+	assert.Equal(t, []string{"m1"}, ids(got), "markets without an end date never end before a date")
+
+	ascending := true
+	got = cat.Filter(&models.MarketQueryParams{
+		EndDateAfter: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		Order:        "end_date",
+		Ascending:    &ascending,
+	})
+	assert.Equal(t, []string{"m1", "m2", "m3"}, ids(got))
+
+	assert.True(t, catalog.ValidOrder("endDate"))
+	assert.False(t, catalog.ValidOrder("question"))
+}
+
+func TestCatalog_EventsByTagBucketsActiveEvents(t *testing.T) {
+	cat := catalog.New(nil, &config.CatalogConfig{})
+	sports := models.Tag{Slug: "sports", Label: "Sports"}
+	nfl := models.Tag{Slug: "nfl", Label: "NFL"}
+	cat.Load([]*models.Event{
+		{ID: "e1", Active: true, Volume: "100", Tags: []models.Tag{sports, nfl}, Markets: []models.Market{{ID: "m1"}}},
+		{ID: "e2", Active: true, Volume: "300", Tags: []models.Tag{sports}},
+		{ID: "e3", Active: true, Volume: "200", Tags: []models.Tag{sports}},
+		{ID: "e4", Active: true, Closed: true, Volume: "900", Tags: []models.Tag{nfl}},
+	})
+
+	groups := cat.EventsByTag(2)
+	require.Len(t, groups, 2)
+
+	assert.Equal(t, "sports", groups[0].Slug)
+	assert.Equal(t, 3, groups[0].Events)
+	assert.Equal(t, 600.0, groups[0].Volume)
+	require.Len(t, groups[0].Top, 2)
+	assert.Equal(t, "e2", groups[0].Top[0].ID)
+	assert.Equal(t, "e3", groups[0].Top[1].ID)
+
+	assert.Equal(t, "nfl", groups[1].Slug)
+	assert.Equal(t, 1, groups[1].Events, "closed events are left out")
+	assert.Equal(t, 1, groups[1].Top[0].Markets)
+}
+
+func TestCatalog_ClosingSortsByTimeRemaining(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	cat := catalog.New(nil, &config.CatalogConfig{})
+	cat.Load([]*models.Event{{
+		ID: "e1",
+		Markets: []models.Market{
+			{ID: "later", EndDate: now.Add(20 * time.Hour)},
+			{ID: "soon", EndDate: now.Add(90 * time.Minute)},
+			{ID: "past", EndDate: now.Add(-time.Hour)},
+			{ID: "closed", EndDate: now.Add(time.Hour), Closed: true},
+			{ID: "far", EndDate: now.Add(72 * time.Hour)},
+			{ID: "undated"},
+		},
+	}})
+
+	closing := cat.Closing(now, 24*time.Hour)
+	require.Len(t, closing, 2)
+	assert.Equal(t, "soon", closing[0].ID)
+	assert.Equal(t, int64(5400), closing[0].SecondsRemaining)
+	assert.Equal(t, "later", closing[1].ID)
+}
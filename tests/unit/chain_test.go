@@ -0,0 +1,167 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/chain"
+	"github.com/polygo/internal/config"
+)
+
+const (
+	settledTx  = "0x1111111111111111111111111111111111111111111111111111111111111111"
+	revertedTx = "0x2222222222222222222222222222222222222222222222222222222222222222"
+	pendingTx  = "0x3333333333333333333333333333333333333333333333333333333333333333"
+	unknownTx  = "0x4444444444444444444444444444444444444444444444444444444444444444"
+
+	usdcContract = "0x2791bca1f2de4661ed88a30c99a7a9449aa84174"
+	ctfContract  = "0x4d97dcd97ec945f40cf65f87097ace5ea0476045"
+	maker        = "0x000000000000000000000000aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	taker        = "0x000000000000000000000000bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+// fakeRPC serves receipts from a Polygon node whose head is block 0x64
+// (100), counting receipt lookups
+func fakeRPC(t *testing.T, receiptCalls *atomic.Int32) *httptest.Server {
+	receipts := map[string]interface{}{
+		settledTx: map[string]interface{}{
+			"status":      "0x1",
+			"blockNumber": "0x50", // 80: 21 confirmations
+			"logs": []interface{}{
+				map[string]interface{}{
+					"address": usdcContract,
+					"topics":  []string{"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", taker, maker},
+					"data":    "0x0000000000000000000000000000000000000000000000000000000000bebc20", // 12.5 USDC
+				},
+				map[string]interface{}{
+					"address": ctfContract,
+					"topics":  []string{"0xc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f62", taker, maker, taker},
+					"data":    "0x000000000000000000000000000000000000000000000000000000000000002a0000000000000000000000000000000000000000000000000000000001c9c380", // id 42, 30 shares
+				},
+			},
+		},
+		revertedTx: map[string]interface{}{"status": "0x0", "blockNumber": "0x60", "logs": []interface{}{}},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int           `json:"id"`
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		var result interface{}
+		switch req.Method {
+		case "eth_blockNumber":
+			result = "0x64"
+		case "eth_getTransactionReceipt":
+			receiptCalls.Add(1)
+			result = receipts[req.Params[0].(string)]
+		case "eth_getTransactionByHash":
+			if req.Params[0] == pendingTx {
+				result = map[string]interface{}{"hash": pendingTx, "blockNumber": nil}
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newVerifier(t *testing.T, confirmations uint64) (*chain.Verifier, *atomic.Int32) {
+	var receiptCalls atomic.Int32
+	server := fakeRPC(t, &receiptCalls)
+
+	cfg := config.DefaultConfig()
+	cfg.Chain.RPCURL = server.URL
+	cfg.Chain.Confirmations = confirmations
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	return chain.NewVerifier(chain.NewClient(&cfg.Chain), c, &cfg.Chain), &receiptCalls
+}
+
+func TestChain_VerifiesSettlementAndDecodesTransfers(t *testing.T) {
+	verifier, receiptCalls := newVerifier(t, 20)
+
+	s, err := verifier.Verify(settledTx)
+	require.NoError(t, err)
+	assert.Equal(t, chain.StatusConfirmed, s.Status)
+	assert.True(t, s.Confirmed)
+	assert.Equal(t, uint64(80), s.BlockNumber)
+	assert.Equal(t, uint64(21), s.Confirmations)
+
+	require.Len(t, s.Transfers, 2)
+	assert.Equal(t, chain.Transfer{
+		Standard: "erc20", Contract: usdcContract,
+		From: "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", To: "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		Amount: "12500000", Value: 12.5,
+	}, s.Transfers[0])
+	assert.Equal(t, "erc1155", s.Transfers[1].Standard)
+	assert.Equal(t, "42", s.Transfers[1].TokenID)
+	assert.Equal(t, 30.0, s.Transfers[1].Value)
+
+	// Confirmed results are final and served from the cache
+	require.Eventually(t, func() bool {
+		before := receiptCalls.Load()
+		_, err := verifier.Verify(settledTx)
+		return err == nil && receiptCalls.Load() == before
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestChain_ReportsUnsettledTransactions(t *testing.T) {
+	verifier, _ := newVerifier(t, 30)
+
+	s, err := verifier.Verify(settledTx)
+	require.NoError(t, err)
+	assert.Equal(t, chain.StatusUnconfirmed, s.Status, "21 of 30 confirmations")
+	assert.False(t, s.Confirmed)
+
+	s, err = verifier.Verify(revertedTx)
+	require.NoError(t, err)
+	assert.Equal(t, chain.StatusFailed, s.Status)
+	assert.False(t, s.Confirmed)
+
+	s, err = verifier.Verify(pendingTx)
+	require.NoError(t, err)
+	assert.Equal(t, chain.StatusPending, s.Status)
+
+	s, err = verifier.Verify(unknownTx)
+	require.NoError(t, err)
+	assert.Equal(t, chain.StatusNotFound, s.Status)
+
+	_, err = verifier.Verify("0x1234")
+	assert.ErrorIs(t, err, chain.ErrInvalidHash)
+}
+
+func TestChain_AnnotatesTrades(t *testing.T) {
+	verifier, _ := newVerifier(t, 20)
+
+	out, err := verifier.Annotate([]byte(`[
+		{"transactionHash":"` + settledTx + `","size":12345678901234567890},
+		{"transactionHash":"` + revertedTx + `"},
+		{"asset":"no-hash"}
+	]`))
+	require.NoError(t, err)
+
+	var trades []map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(out, &trades))
+	assert.Equal(t, "true", string(trades[0]["confirmed"]))
+	assert.Equal(t, "12345678901234567890", string(trades[0]["size"]), "numbers stay exact")
+	assert.Equal(t, "false", string(trades[1]["confirmed"]))
+	assert.NotContains(t, trades[2], "confirmed")
+
+	out, err = verifier.Annotate([]byte(`{"data":[{"transaction_hash":"` + settledTx + `"}],"next_cursor":"LTE="}`))
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"confirmed":true`)
+}
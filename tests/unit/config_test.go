@@ -0,0 +1,73 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polygo/internal/config"
+)
+
+func TestEffective_RedactsSecrets(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Replication.Token = "s3cret"
+	cfg.Admin.Token = "admin-s3cret"
+	cfg.Polymarket.ExtraHeaders = map[string]string{"X-Upstream-Key": "abc"}
+	cfg.ErrorReporting.SentryDSN = "https://publickey@o1.ingest.sentry.io/42"
+	cfg.ErrorReporting.BugsnagAPIKey = "bugsnag-key"
+	cfg.Storage.SecretAccessKey = "s3-secret-key"
+
+	eff := cfg.Effective()
+
+	assert.NotContains(t, eff.Config.Replication.Token, "s3cret")
+	assert.NotContains(t, eff.Config.Admin.Token, "s3cret")
+	assert.NotEqual(t, "abc", eff.Config.Polymarket.ExtraHeaders["X-Upstream-Key"])
+	assert.NotContains(t, eff.Config.ErrorReporting.SentryDSN, "publickey")
+	assert.NotContains(t, eff.Config.ErrorReporting.BugsnagAPIKey, "bugsnag-key")
+	assert.NotContains(t, eff.Config.Storage.SecretAccessKey, "s3-secret-key")
+
+	// The live configuration is untouched
+	assert.Equal(t, "s3cret", cfg.Replication.Token)
+	assert.Equal(t, "abc", cfg.Polymarket.ExtraHeaders["X-Upstream-Key"])
+}
+
+func TestWarnings_FlagDangerousCombinations(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Admin.Token = "set"
+	assert.Empty(t, cfg.Warnings())
+
+	cfg.Server.Prefork = true
+	cfg.Server.CORSAllowCredentials = true
+	cfg.Cache.MaxCost = 1 << 20
+	cfg.Cache.MaxEntrySize = 512 << 10
+
+	warnings := cfg.Warnings()
+	assert.Len(t, warnings, 3)
+	assert.Contains(t, warnings[0], "prefork")
+	assert.Contains(t, warnings[1], "CORS")
+	assert.Contains(t, warnings[2], "max_cost")
+}
+
+func TestValidate_RefusesOpenAdminWithAccounts(t *testing.T) {
+	cfg := config.DefaultConfig()
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Routing.Enabled = true
+	cfg.Routing.Accounts = map[string]config.RoutingAccount{"desk": {APIKey: "desk-key"}}
+	assert.ErrorContains(t, cfg.Validate(), "order routing")
+
+	cfg.Admin.Token = "set"
+	assert.NoError(t, cfg.Validate())
+
+	cfg = config.DefaultConfig()
+	cfg.CopyTrade.Enabled = true
+	assert.ErrorContains(t, cfg.Validate(), "copy trading")
+	cfg.Access.Enabled = true
+	assert.NoError(t, cfg.Validate(), "access control keeps /admin for admin keys")
+
+	cfg = config.DefaultConfig()
+	cfg.Rules.Enabled = true
+	assert.NoError(t, cfg.Validate(), "rules without accounts only notify")
+	cfg.Rules.Accounts = map[string]config.RuleAccount{"main": {APIKey: "key"}}
+	assert.ErrorContains(t, cfg.Validate(), "rules")
+}
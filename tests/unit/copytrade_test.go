@@ -0,0 +1,174 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/copytrade"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/orderrules"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/pretrade"
+	"github.com/polygo/internal/risk"
+	"github.com/polygo/internal/throttle"
+	"github.com/polygo/pkg/polygoclient"
+	"github.com/polygo/pkg/validate"
+)
+
+const (
+	copyTarget  = "0x1111111111111111111111111111111111111111"
+	copyAccount = "0x2222222222222222222222222222222222222222"
+)
+
+func newCopyTradeEngine(t *testing.T, mutate func(*config.Config)) (*copytrade.Engine, *polymarket.FillTracker, *mockupstream.Server, *config.Config) {
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	cfg.CopyTrade.Enabled = true
+	cfg.CopyTrade.Interval = time.Hour
+	cfg.CopyTrade.Address = copyAccount
+	cfg.CopyTrade.APIKey = "key"
+	cfg.CopyTrade.Secret = "c2VjcmV0"
+	cfg.CopyTrade.Passphrase = "pass"
+	if mutate != nil {
+		mutate(cfg)
+	}
+
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	client := polymarket.NewClient(&cfg.Polymarket, c)
+
+	fills := polymarket.NewFillTracker()
+	clob := polymarket.NewClobClient(client)
+	checks := pretrade.New(orderrules.New(clob, nil, &cfg.OrderRules), throttle.New(nil, &cfg.Throttle), risk.New(clob, nil, &cfg.Risk), &cfg.Auth)
+	engine := copytrade.New(polymarket.NewDataClient(client), clob, fills, checks, &cfg.Auth, &cfg.CopyTrade)
+	return engine, fills, mock, cfg
+}
+
+func TestCopyTrade_MirrorsNewTradesWithinCaps(t *testing.T) {
+	engine, fills, mock, cfg := newCopyTradeEngine(t, nil)
+
+	mock.On(mockupstream.Data, "GET", "/trades", 200,
+		`[{"asset":"old","side":"BUY","size":10,"price":0.5,"transactionHash":"0x0"}]`)
+	require.NoError(t, engine.Start(copytrade.Options{Target: copyTarget, Scale: 0.5, MaxOrderNotional: 5, MaxExposure: 8}))
+	t.Cleanup(func() { engine.Stop() })
+	require.Eventually(t, func() bool { return engine.Status().LastPolled != nil }, time.Second, 10*time.Millisecond)
+
+	// Newest first, as the Data API lists them
+	mock.On(mockupstream.Data, "GET", "/trades", 200, `[
+		{"asset":"d","side":"SELL","size":4,"price":0.5,"transactionHash":"0x4"},
+		{"asset":"c","side":"BUY","size":20,"price":0.5,"transactionHash":"0x3"},
+		{"asset":"b","side":"BUY","size":100,"price":0.5,"transactionHash":"0x2"},
+		{"asset":"a","side":"BUY","size":10,"price":0.4,"transactionHash":"0x1"},
+		{"asset":"old","side":"BUY","size":10,"price":0.5,"transactionHash":"0x0"}
+	]`)
+	require.NoError(t, engine.Poll())
+
+	status := engine.Status()
+	assert.True(t, status.Running)
+	assert.Equal(t, 3, status.Placed)
+	assert.Equal(t, 1, status.Skipped)
+	assert.InDelta(t, 6.0, status.Exposure, 1e-9, "2 + 5 bought, 1 sold")
+	require.Len(t, status.Recent, 4)
+	assert.Equal(t, "d", status.Recent[0].TokenID, "newest first")
+	assert.Equal(t, copytrade.StatusSkipped, status.Recent[1].Status)
+	assert.Equal(t, "max exposure reached", status.Recent[1].Reason)
+	assert.Equal(t, 10.0, status.Recent[2].Size, "scaled down to the per-order cap")
+	assert.Equal(t, mockupstream.OrderID, status.Recent[3].OrderID)
+
+	orders := mock.Requests(mockupstream.CLOB)
+	var posted []mockupstream.Request
+	for _, r := range orders {
+		if r.Method == "POST" && r.Path == "/order" {
+			posted = append(posted, r)
+		}
+	}
+	require.Len(t, posted, 3)
+
+	var first models.CreateOrderRequest
+	require.NoError(t, sonic.Unmarshal(posted[0].Body, &first))
+	assert.Equal(t, "a", first.TokenID)
+	assert.Equal(t, "5.00", first.Size)
+	assert.Equal(t, "0.4", first.Price)
+	assert.Equal(t, copyAccount, first.Maker)
+
+	h := posted[0].Header
+	creds := polygoclient.Credentials{APIKey: "key", Secret: "c2VjcmV0", Passphrase: "pass"}
+	assert.Equal(t, "key", h.Get(cfg.Auth.APIKeyHeader))
+	assert.Equal(t, creds.Sign(h.Get(cfg.Auth.TimestampHeader), "POST", "/order", posted[0].Body), h.Get(cfg.Auth.SignatureHeader))
+	assert.Empty(t, h.Get(cfg.Auth.APISecretHeader), "the secret is never sent")
+
+	// Resting mirrored orders are tracked, so later fills invalidate the account
+	address, filled := fills.Observe(mockupstream.OrderID, "MATCHED", "10")
+	assert.True(t, filled)
+	assert.Equal(t, copyAccount, address)
+}
+
+func TestCopyTrade_MirrorsPassPreTradeChecks(t *testing.T) {
+	engine, _, mock, _ := newCopyTradeEngine(t, func(c *config.Config) {
+		c.Risk.Enabled = true
+		c.Risk.Default = config.RiskLimits{BannedMarkets: []string{"banned"}}
+		c.Throttle.Enabled = true
+		c.Throttle.DuplicateWindow = time.Minute
+	})
+
+	mock.On(mockupstream.Data, "GET", "/trades", 200, `[]`)
+	require.NoError(t, engine.Start(copytrade.Options{Target: copyTarget}))
+	t.Cleanup(func() { engine.Stop() })
+	require.Eventually(t, func() bool { return engine.Status().LastPolled != nil }, time.Second, 10*time.Millisecond)
+
+	mock.On(mockupstream.Data, "GET", "/trades", 200, `[
+		{"asset":"a","side":"BUY","size":10,"price":0.4,"transactionHash":"0x3"},
+		{"asset":"banned","side":"BUY","size":10,"price":0.4,"transactionHash":"0x2"},
+		{"asset":"a","side":"BUY","size":10,"price":0.4,"transactionHash":"0x1"}
+	]`)
+	require.NoError(t, engine.Poll())
+
+	status := engine.Status()
+	assert.Equal(t, 1, status.Placed)
+	assert.Equal(t, 2, status.Skipped)
+	require.Len(t, status.Recent, 3)
+	assert.Contains(t, status.Recent[1].Reason, "not allowed", "risk limits apply")
+	assert.Contains(t, status.Recent[0].Reason, "repeats one sent", "the duplicate guard applies")
+
+	var posted int
+	for _, r := range mock.Requests(mockupstream.CLOB) {
+		if r.Method == "POST" && r.Path == "/order" {
+			posted++
+		}
+	}
+	assert.Equal(t, 1, posted)
+}
+
+func TestCopyTrade_StartAndStop(t *testing.T) {
+	engine, _, _, _ := newCopyTradeEngine(t, func(c *config.Config) { c.CopyTrade.Enabled = false })
+	assert.ErrorIs(t, engine.Start(copytrade.Options{Target: copyTarget}), copytrade.ErrDisabled)
+
+	engine, _, _, _ = newCopyTradeEngine(t, func(c *config.Config) { c.CopyTrade.Secret = "" })
+	assert.ErrorIs(t, engine.Start(copytrade.Options{Target: copyTarget}), copytrade.ErrNoAccount)
+
+	engine, _, _, _ = newCopyTradeEngine(t, nil)
+	assert.ErrorIs(t, engine.Start(copytrade.Options{Target: "0xnope"}), validate.ErrInvalidAddress)
+	assert.ErrorIs(t, engine.Stop(), copytrade.ErrNotRunning)
+
+	require.NoError(t, engine.Start(copytrade.Options{Target: copyTarget}))
+	assert.ErrorIs(t, engine.Start(copytrade.Options{Target: copyTarget}), copytrade.ErrRunning)
+
+	status := engine.Status()
+	assert.Equal(t, 1.0, status.Options.Scale, "configured defaults fill in")
+	assert.Equal(t, copyAccount, status.Account)
+
+	require.NoError(t, engine.Stop())
+	assert.False(t, engine.Status().Running)
+	assert.ErrorIs(t, engine.Poll(), copytrade.ErrNotRunning)
+}
@@ -0,0 +1,156 @@
+package unit
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/api/middleware"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/crashreport"
+)
+
+// trackerServer records the requests posted to it, with their path in
+// X-Test-Path
+func trackerServer(t *testing.T) (*httptest.Server, func() []http.Header, func() [][]byte) {
+	var mu sync.Mutex
+	var headers []http.Header
+	var bodies [][]byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		h := r.Header.Clone()
+		h.Set("X-Test-Path", r.URL.Path)
+		headers = append(headers, h)
+		bodies = append(bodies, body)
+		mu.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+	return srv,
+		func() []http.Header { mu.Lock(); defer mu.Unlock(); return append([]http.Header(nil), headers...) },
+		func() [][]byte { mu.Lock(); defer mu.Unlock(); return append([][]byte(nil), bodies...) }
+}
+
+func crashReportConfig() *config.ErrorReportingConfig {
+	cfg := config.DefaultConfig().ErrorReporting
+	return &cfg
+}
+
+func TestCrashReport_RecoveryReportsToSentry(t *testing.T) {
+	srv, headers, bodies := trackerServer(t)
+	cfg := crashReportConfig()
+	cfg.SentryDSN = strings.Replace(srv.URL, "http://", "http://publickey@", 1) + "/42"
+	cfg.Release = "v1.2.3"
+
+	reporter, err := crashreport.New(cfg)
+	require.NoError(t, err)
+	reporter.Start()
+
+	app := fiber.New()
+	app.Use(middleware.Recovery(reporter), middleware.RequestID())
+	app.Get("/boom/:id", func(c *fiber.Ctx) error {
+		var orders []string
+		_ = orders[len(c.Params("id"))] // index out of range
+		return nil
+	})
+
+	for _, id := range []string{"a", "bb"} {
+		req := httptest.NewRequest("GET", "/boom/"+id, nil)
+		req.Header.Set("X-Request-ID", "req-"+id)
+		req.Header["POLY-API-KEY"] = []string{"my-key"}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		assert.Equal(t, 500, resp.StatusCode)
+	}
+	reporter.Stop()
+
+	sent := bodies()
+	require.Len(t, sent, 1, "the second panic differs only in its index and is deduplicated")
+	assert.Equal(t, "/api/42/envelope/", headers()[0].Get("X-Test-Path"))
+	assert.Contains(t, headers()[0].Get("X-Sentry-Auth"), "sentry_key=publickey")
+
+	lines := bytes.Split(bytes.TrimSpace(sent[0]), []byte("\n"))
+	require.Len(t, lines, 3, "envelope header, item header, event")
+	event := string(lines[2])
+	assert.Contains(t, event, `"request_id":"req-a"`)
+	assert.Contains(t, event, `"route":"/boom/:id"`)
+	assert.Contains(t, event, `"release":"v1.2.3"`)
+	assert.Contains(t, event, "index out of range")
+	assert.Contains(t, event, `"in_app":true`)
+	assert.NotContains(t, event, "my-key", "credentials are not reported")
+}
+
+func TestCrashReport_DedupWindowCountsOccurrences(t *testing.T) {
+	cfg := crashReportConfig()
+	cfg.DedupWindow = 50 * time.Millisecond
+	reporter, err := crashreport.New(cfg)
+	require.NoError(t, err)
+	assert.False(t, reporter.Enabled())
+
+	first := reporter.Capture("order 17 missing", debug.Stack(), nil)
+	require.NotNil(t, first)
+	assert.Equal(t, 1, first.Occurrences)
+	assert.Nil(t, reporter.Capture("order 18 missing", debug.Stack(), nil))
+	assert.Nil(t, reporter.Capture("order 19 missing", debug.Stack(), nil))
+	require.NotNil(t, reporter.Capture("another failure", debug.Stack(), nil), "a different panic is reported")
+
+	time.Sleep(60 * time.Millisecond)
+	again := reporter.Capture("order 20 missing", debug.Stack(), nil)
+	require.NotNil(t, again)
+	assert.Equal(t, 3, again.Occurrences, "includes the two suppressed in the last window")
+}
+
+func TestCrashReport_Bugsnag(t *testing.T) {
+	srv, headers, bodies := trackerServer(t)
+	cfg := crashReportConfig()
+	cfg.BugsnagAPIKey = "0123456789abcdef0123456789abcdef"
+	cfg.BugsnagURL = srv.URL
+
+	reporter, err := crashreport.New(cfg)
+	require.NoError(t, err)
+	reporter.Start()
+	reporter.Capture("nil map", debug.Stack(), &crashreport.Request{ID: "req-1", Method: "POST", Route: "/api/v1/orders"})
+	reporter.Stop()
+
+	require.Len(t, bodies(), 1)
+	assert.Equal(t, "0123456789abcdef0123456789abcdef", headers()[0].Get("Bugsnag-Api-Key"))
+	body := string(bodies()[0])
+	assert.Contains(t, body, `"context":"POST /api/v1/orders"`)
+	assert.Contains(t, body, `"releaseStage":"production"`)
+	assert.Contains(t, body, `"errorClass":"string"`)
+	assert.Contains(t, body, `"unhandled":true`)
+	assert.Contains(t, body, `"inProject":true`)
+}
+
+func TestCrashReport_InvalidDSN(t *testing.T) {
+	cfg := crashReportConfig()
+	cfg.SentryDSN = "https://sentry.io/42"
+	_, err := crashreport.New(cfg)
+	assert.Error(t, err)
+}
+
+func TestCrashReport_ParseStackStartsAtPanic(t *testing.T) {
+	var frames []crashreport.Frame
+	func() {
+		defer func() {
+			recover()
+			frames = crashreport.ParseStack(debug.Stack())
+		}()
+		panic("boom")
+	}()
+
+	require.NotEmpty(t, frames)
+	assert.Contains(t, frames[0].Function, "TestCrashReport_ParseStackStartsAtPanic")
+	assert.True(t, strings.HasSuffix(frames[0].File, "crashreport_test.go"))
+	assert.Positive(t, frames[0].Line)
+}
@@ -0,0 +1,152 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/deadman"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/webhooks"
+	"github.com/polygo/pkg/polygoclient"
+)
+
+var deadManCreds = &polygoclient.Credentials{APIKey: "key", Secret: "c2VjcmV0", Passphrase: "pass"}
+
+func newDeadMan(t *testing.T) (*deadman.Switches, *webhooks.Dispatcher, *mockupstream.Server, *config.Config) {
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	cfg.DeadMan.MinTimeout = 50 * time.Millisecond
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	clob := polymarket.NewClobClient(polymarket.NewClient(&cfg.Polymarket, c))
+	dispatcher, err := webhooks.NewDispatcher(&cfg.Webhooks)
+	require.NoError(t, err)
+	return deadman.New(clob, dispatcher, &cfg.Auth, &cfg.DeadMan), dispatcher, mock, cfg
+}
+
+func nextDeadManNotice(t *testing.T, events <-chan *webhooks.Event) deadman.Notice {
+	t.Helper()
+	select {
+	case event := <-events:
+		require.Equal(t, deadman.EventTriggered, event.Type)
+		return event.Data.(deadman.Notice)
+	case <-time.After(2 * time.Second):
+		t.Fatal("missing order.dead_man event")
+	}
+	return deadman.Notice{}
+}
+
+func TestDeadMan_ValidatesArming(t *testing.T) {
+	switches, _, _, cfg := newDeadMan(t)
+
+	_, err := switches.Session("owner", nil).Arm(time.Second)
+	assert.ErrorIs(t, err, deadman.ErrNoSecret)
+	_, err = switches.Session("owner", &polygoclient.Credentials{APIKey: "key"}).Arm(time.Second)
+	assert.ErrorIs(t, err, deadman.ErrNoSecret)
+
+	session := switches.Session("owner", deadManCreds)
+	defer session.Disarm()
+	_, err = session.Arm(10 * time.Millisecond)
+	assert.ErrorIs(t, err, deadman.ErrTimeout)
+	_, err = session.Arm(time.Hour)
+	assert.ErrorIs(t, err, deadman.ErrTimeout)
+
+	status, err := session.Arm(10 * time.Second)
+	require.NoError(t, err)
+	assert.True(t, status.Armed)
+	assert.Equal(t, 10.0, status.Timeout)
+	require.NotNil(t, status.Deadline)
+	assert.WithinDuration(t, time.Now().Add(10*time.Second), *status.Deadline, time.Second)
+
+	cfg.DeadMan.Enabled = false
+	_, err = switches.Session("owner", deadManCreds).Arm(time.Second)
+	assert.ErrorIs(t, err, deadman.ErrDisabled)
+}
+
+func TestDeadMan_CancelsOnMissedHeartbeat(t *testing.T) {
+	switches, dispatcher, mock, cfg := newDeadMan(t)
+	events, stop := dispatcher.Listen("owner")
+	defer stop()
+
+	session := switches.Session("owner", deadManCreds)
+	_, ok := session.Heartbeat()
+	assert.False(t, ok, "not armed yet")
+
+	_, err := session.Arm(200 * time.Millisecond)
+	require.NoError(t, err)
+	// Heartbeats keep the switch from firing
+	for i := 0; i < 4; i++ {
+		time.Sleep(100 * time.Millisecond)
+		_, ok := session.Heartbeat()
+		require.True(t, ok)
+	}
+	assert.Empty(t, mock.Requests(mockupstream.CLOB))
+
+	notice := nextDeadManNotice(t, events)
+	assert.Equal(t, deadman.ReasonHeartbeat, notice.Reason)
+	assert.Empty(t, notice.Error)
+	assert.NotNil(t, notice.Cancelled)
+	assert.False(t, session.Status().Armed, "a triggered switch is spent")
+
+	requests := mock.Requests(mockupstream.CLOB)
+	require.Len(t, requests, 1)
+	assert.Equal(t, "DELETE", requests[0].Method)
+	assert.Equal(t, "/cancel-all", requests[0].Path)
+	h := requests[0].Header
+	assert.Equal(t, deadManCreds.Sign(h.Get(cfg.Auth.TimestampHeader), "DELETE", "/cancel-all", nil), h.Get(cfg.Auth.SignatureHeader))
+
+	// Closing after it fired cancels nothing more
+	session.Close()
+	assert.Len(t, mock.Requests(mockupstream.CLOB), 1)
+}
+
+func TestDeadMan_CancelsOnDisconnect(t *testing.T) {
+	switches, dispatcher, mock, _ := newDeadMan(t)
+	mock.On(mockupstream.CLOB, "DELETE", "/cancel-all", 200, `{"canceled":["0xa","0xb"],"not_canceled":{"0xc":"order already filled"}}`)
+	events, stop := dispatcher.Listen("owner")
+	defer stop()
+
+	session := switches.Session("owner", deadManCreds)
+	_, err := session.Arm(time.Minute)
+	require.NoError(t, err)
+	session.Close()
+
+	notice := nextDeadManNotice(t, events)
+	assert.Equal(t, deadman.ReasonDisconnect, notice.Reason)
+	assert.Equal(t, []string{"0xa", "0xb"}, notice.Cancelled)
+	assert.Equal(t, map[string]string{"0xc": "order already filled"}, notice.NotCancelled)
+	assert.False(t, notice.ArmedAt.IsZero())
+}
+
+func TestDeadMan_DisarmAndStopDoNotCancel(t *testing.T) {
+	switches, _, mock, _ := newDeadMan(t)
+
+	disarmed := switches.Session("owner", deadManCreds)
+	_, err := disarmed.Arm(100 * time.Millisecond)
+	require.NoError(t, err)
+	assert.False(t, disarmed.Disarm().Armed)
+
+	armed := switches.Session("owner", deadManCreds)
+	_, err = armed.Arm(100 * time.Millisecond)
+	require.NoError(t, err)
+	switches.Stop()
+
+	time.Sleep(250 * time.Millisecond)
+	disarmed.Close()
+	armed.Close()
+	assert.Empty(t, mock.Requests(mockupstream.CLOB))
+
+	_, err = switches.Session("owner", deadManCreds).Arm(time.Second)
+	assert.ErrorIs(t, err, deadman.ErrDisabled, "no arming once stopped")
+}
@@ -0,0 +1,180 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/polymarket"
+)
+
+// startAcceptor listens on loopback and returns its port
+func startAcceptor(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	return port
+}
+
+// deadPort returns a loopback port nothing listens on
+func deadPort(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	ln.Close()
+	return port
+}
+
+func TestDialer_CachesResolutions(t *testing.T) {
+	port := startAcceptor(t)
+	d := polymarket.NewDialer(&config.PolymarketConfig{DNSCacheTTL: time.Minute})
+	var lookups atomic.Int64
+	d.SetLookup(func(ctx context.Context, host string) ([]net.IP, error) {
+		lookups.Add(1)
+		return []net.IP{net.ParseIP("127.0.0.1")}, nil
+	})
+
+	for i := 0; i < 3; i++ {
+		conn, err := d.Dial("upstream.test:" + port)
+		require.NoError(t, err)
+		conn.Close()
+	}
+	assert.Equal(t, int64(1), lookups.Load())
+}
+
+func TestDialer_ServesStaleWhenResolverFails(t *testing.T) {
+	port := startAcceptor(t)
+	d := polymarket.NewDialer(&config.PolymarketConfig{DNSCacheTTL: time.Millisecond})
+	var lookups atomic.Int64
+	d.SetLookup(func(ctx context.Context, host string) ([]net.IP, error) {
+		if lookups.Add(1) > 1 {
+			return nil, errors.New("resolver down")
+		}
+		return []net.IP{net.ParseIP("127.0.0.1")}, nil
+	})
+
+	conn, err := d.Dial("upstream.test:" + port)
+	require.NoError(t, err)
+	conn.Close()
+
+	time.Sleep(5 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		conn, err := d.Dial("upstream.test:" + port)
+		require.NoError(t, err)
+		conn.Close()
+	}
+	assert.Eventually(t, func() bool { return lookups.Load() >= 2 }, time.Second, 5*time.Millisecond)
+}
+
+func TestDialer_WithoutCacheResolvesEveryTime(t *testing.T) {
+	port := startAcceptor(t)
+	d := polymarket.NewDialer(&config.PolymarketConfig{})
+	var lookups atomic.Int64
+	d.SetLookup(func(ctx context.Context, host string) ([]net.IP, error) {
+		lookups.Add(1)
+		return []net.IP{net.ParseIP("127.0.0.1")}, nil
+	})
+
+	for i := 0; i < 2; i++ {
+		conn, err := d.Dial("upstream.test:" + port)
+		require.NoError(t, err)
+		conn.Close()
+	}
+	assert.Equal(t, int64(2), lookups.Load())
+}
+
+func TestDialer_FallsBackToNextAddress(t *testing.T) {
+	port := startAcceptor(t)
+	d := polymarket.NewDialer(&config.PolymarketConfig{
+		DNSCacheTTL:        time.Minute,
+		HappyEyeballsDelay: 50 * time.Millisecond,
+	})
+	// 192.0.2.1 (TEST-NET-1) never answers, or fails at once
+	d.SetLookup(func(ctx context.Context, host string) ([]net.IP, error) {
+		return []net.IP{net.ParseIP("192.0.2.1"), net.ParseIP("127.0.0.1")}, nil
+	})
+
+	start := time.Now()
+	conn, err := d.Dial("upstream.test:" + port)
+	require.NoError(t, err)
+	conn.Close()
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, "127.0.0.1:"+port, conn.RemoteAddr().String())
+
+	// The address that connected goes first from then on
+	start = time.Now()
+	conn, err = d.Dial("upstream.test:" + port)
+	require.NoError(t, err)
+	conn.Close()
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestDialer_ResolvesAgainAfterFailure(t *testing.T) {
+	dead := deadPort(t)
+	d := polymarket.NewDialer(&config.PolymarketConfig{DNSCacheTTL: time.Minute})
+	var lookups atomic.Int64
+	d.SetLookup(func(ctx context.Context, host string) ([]net.IP, error) {
+		lookups.Add(1)
+		return []net.IP{net.ParseIP("127.0.0.1")}, nil
+	})
+
+	_, err := d.Dial("upstream.test:" + dead)
+	require.Error(t, err)
+	_, err = d.Dial("upstream.test:" + dead)
+	require.Error(t, err)
+	assert.Equal(t, int64(2), lookups.Load())
+}
+
+func TestDialer_LookupError(t *testing.T) {
+	d := polymarket.NewDialer(&config.PolymarketConfig{DNSCacheTTL: time.Minute})
+	d.SetLookup(func(ctx context.Context, host string) ([]net.IP, error) {
+		return nil, errors.New("no such host")
+	})
+
+	_, err := d.Dial("upstream.test:443")
+	assert.ErrorContains(t, err, "no such host")
+}
+
+func TestClient_WarmupOpensConnections(t *testing.T) {
+	var conns atomic.Int64
+	// Slow enough that no request finds another's connection idle
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	cfg := config.DefaultConfig().Polymarket
+	cfg.ClobBaseURL = srv.URL
+	cfg.GammaBaseURL = srv.URL
+	cfg.DataBaseURL = srv.URL
+	cfg.WarmupConns = 3
+	client := polymarket.NewClient(&cfg, nil)
+
+	require.NoError(t, client.Warmup())
+	assert.Equal(t, int64(3), conns.Load())
+}
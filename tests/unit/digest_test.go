@@ -0,0 +1,71 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/digest"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/recorder"
+)
+
+func TestDigest_BuildSections(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	cat := catalog.New(nil, &config.CatalogConfig{})
+	cat.Load([]*models.Event{{
+		ID:   "e1",
+		Slug: "event",
+		Markets: []models.Market{
+			{ID: "new", CreatedAt: now.Add(-2 * time.Hour), Volume24hr: "500", OutcomePrices: models.StringList{"0.3", "0.7"}},
+			{ID: "old", CreatedAt: now.Add(-72 * time.Hour), Volume24hr: "100"},
+			{ID: "soon", EndDate: now.Add(3 * time.Hour)},
+			{ID: "sooner", EndDate: now.Add(time.Hour)},
+			{ID: "ended", EndDate: now.Add(-time.Hour)},
+			{ID: "later", EndDate: now.Add(48 * time.Hour)},
+			{ID: "spike", Volume24hr: "6000", Volume1wk: "7000"},
+			{ID: "steady", Volume24hr: "1500", Volume1wk: "10500"},
+			{ID: "thin", Volume24hr: "50", Volume1wk: "70"},
+		},
+	}})
+
+	b := digest.NewBuilder(cat, nil, nil, &config.DigestConfig{Limit: 10, SpikeRatio: 3})
+	d := b.Build(now)
+
+	assert.Empty(t, d.Swings, "no swings without recorded prices")
+
+	require.Len(t, d.NewMarkets, 1)
+	assert.Equal(t, "new", d.NewMarkets[0].MarketID)
+	assert.Equal(t, 0.3, d.NewMarkets[0].Price)
+	assert.Equal(t, "event", d.NewMarkets[0].EventSlug)
+
+	require.Len(t, d.Resolving, 2)
+	assert.Equal(t, "sooner", d.Resolving[0].MarketID)
+	assert.Equal(t, "soon", d.Resolving[1].MarketID)
+
+	require.Len(t, d.VolumeSpikes, 1)
+	assert.Equal(t, "spike", d.VolumeSpikes[0].MarketID)
+	assert.Equal(t, 1000.0, d.VolumeSpikes[0].AvgDailyVolume)
+	assert.Equal(t, 6.0, d.VolumeSpikes[0].Ratio)
+}
+
+func TestDigest_SwingsFromRecorder(t *testing.T) {
+	now := time.Now()
+	rec := recorder.New(nil, nil, &config.RecorderConfig{Retention: 48 * time.Hour})
+	rec.Record("a", "A?", "a", 0.20, 0, now.Add(-2*time.Hour))
+	rec.Record("a", "A?", "a", 0.60, 0, now)
+	rec.Record("b", "B?", "b", 0.50, 0, now.Add(-2*time.Hour))
+	rec.Record("b", "B?", "b", 0.45, 0, now)
+
+	cat := catalog.New(nil, &config.CatalogConfig{})
+	b := digest.NewBuilder(cat, rec, nil, &config.DigestConfig{Limit: 1, SpikeRatio: 3})
+	d := b.Build(now)
+
+	require.Len(t, d.Swings, 1)
+	assert.Equal(t, "a", d.Swings[0].MarketID)
+}
@@ -0,0 +1,117 @@
+package unit
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/docs"
+)
+
+func openAPIDoc(t *testing.T, cfg config.DocsConfig) map[string]interface{} {
+	t.Helper()
+	docs.Configure(&cfg)
+	t.Cleanup(func() { docs.Configure(&config.DefaultConfig().Docs) })
+
+	raw, err := docs.OpenAPI()
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "#/definitions/", "refs point at components")
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(raw, &doc))
+	return doc
+}
+
+func TestDocs_SwaggerUsesConfiguredHost(t *testing.T) {
+	docs.Configure(&config.DocsConfig{Host: "api.example.com", BasePath: "/v", Schemes: []string{"https"}})
+	t.Cleanup(func() { docs.Configure(&config.DefaultConfig().Docs) })
+
+	var spec map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(docs.SwaggerInfo.ReadDoc()), &spec))
+	assert.Equal(t, "api.example.com", spec["host"])
+	assert.Equal(t, "/v", spec["basePath"])
+	assert.Equal(t, []interface{}{"https"}, spec["schemes"])
+
+	// Without schemes the field is left out, so the UI uses its own
+	docs.Configure(&config.DefaultConfig().Docs)
+	spec = nil
+	require.NoError(t, json.Unmarshal([]byte(docs.SwaggerInfo.ReadDoc()), &spec))
+	assert.Equal(t, "", spec["host"])
+	assert.Equal(t, "/", spec["basePath"])
+	assert.NotContains(t, spec, "schemes")
+}
+
+func TestDocs_SwaggerCoversRoutes(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]interface{} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(docs.SwaggerInfo.ReadDoc()), &spec))
+
+	for path, method := range map[string]string{
+		"/health":                 "get",
+		"/openapi.json":           "get",
+		"/api/v1/orders":          "post",
+		"/api/v1/book/{token_id}": "get",
+		"/admin/config/effective": "get",
+		"/api/v1/webhooks/{id}":   "delete",
+		"/ws/market/{market_id}":  "get",
+	} {
+		assert.Contains(t, spec.Paths[path], method, "%s %s", method, path)
+	}
+}
+
+func TestDocs_OpenAPIConversion(t *testing.T) {
+	doc := openAPIDoc(t, config.DocsConfig{BasePath: "/"})
+
+	assert.Equal(t, "3.0.3", doc["openapi"])
+	assert.Equal(t, "PolyGo API", doc["info"].(map[string]interface{})["title"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"url": "/"}}, doc["servers"], "relative without a host")
+
+	components := doc["components"].(map[string]interface{})
+	assert.Contains(t, components["schemas"], "models.CreateOrderRequest")
+	assert.Equal(t, map[string]interface{}{"type": "apiKey", "in": "header", "name": "POLY-API-KEY"},
+		components["securitySchemes"].(map[string]interface{})["ApiKeyAuth"])
+
+	paths := doc["paths"].(map[string]interface{})
+
+	// Body parameters become the request body
+	create := paths["/api/v1/orders"].(map[string]interface{})["post"].(map[string]interface{})
+	assert.NotContains(t, create, "parameters")
+	assert.NotContains(t, create, "consumes")
+	body := create["requestBody"].(map[string]interface{})
+	assert.Equal(t, true, body["required"])
+	schema := body["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"]
+	assert.Equal(t, map[string]interface{}{"$ref": "#/components/schemas/models.CreateOrderRequest"}, schema)
+
+	// Response schemas move under the media type
+	resp := create["responses"].(map[string]interface{})["400"].(map[string]interface{})
+	assert.Equal(t, "Bad Request", resp["description"])
+	assert.Contains(t, resp["content"], "application/json")
+
+	// Other parameters keep their place with the type in a schema
+	book := paths["/api/v1/book/{token_id}"].(map[string]interface{})["get"].(map[string]interface{})
+	param := book["parameters"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "token_id", param["name"])
+	assert.Equal(t, "path", param["in"])
+	assert.Equal(t, true, param["required"])
+	assert.Equal(t, map[string]interface{}{"type": "string"}, param["schema"])
+	assert.NotContains(t, param, "type")
+}
+
+func TestDocs_OpenAPIServersFromConfig(t *testing.T) {
+	doc := openAPIDoc(t, config.DocsConfig{Host: "api.example.com", BasePath: "/polygo/", Schemes: []string{"https", "http"}})
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"url": "https://api.example.com/polygo"},
+		map[string]interface{}{"url": "http://api.example.com/polygo"},
+	}, doc["servers"])
+
+	doc = openAPIDoc(t, config.DocsConfig{Host: "api.example.com"})
+	servers := doc["servers"].([]interface{})
+	require.Len(t, servers, 1)
+	assert.True(t, strings.HasPrefix(servers[0].(map[string]interface{})["url"].(string), "https://api.example.com"),
+		"https by default once a host is set")
+}
@@ -0,0 +1,117 @@
+package unit
+
+import (
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/api/middleware"
+	"github.com/polygo/pkg/response"
+)
+
+// newDrainApp serves /health, exempt from draining, and /orders, tracked
+// and held until release is closed
+func newDrainApp(drainer *middleware.Drainer) (*fiber.App, chan struct{}) {
+	release := make(chan struct{})
+
+	app := fiber.New()
+	app.Use(drainer.Reject(func(c *fiber.Ctx) bool { return c.Path() == "/health" }))
+	app.Get("/health", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	app.Post("/orders", drainer.Track(), func(c *fiber.Ctx) error {
+		<-release
+		return c.SendString("placed")
+	})
+	return app, release
+}
+
+func TestDrainer_RejectsNewRequestsAfterStart(t *testing.T) {
+	drainer := middleware.NewDrainer(3 * time.Second)
+	app, release := newDrainApp(drainer)
+	close(release)
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/orders", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	drainer.Start()
+	assert.True(t, drainer.IsDraining())
+
+	resp, err = app.Test(httptest.NewRequest("POST", "/orders", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 503, resp.StatusCode)
+	assert.Equal(t, "3", resp.Header.Get("Retry-After"))
+	assert.True(t, resp.Close, "Connection: close")
+	var body response.Response
+	require.NoError(t, sonic.ConfigDefault.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "SHUTTING_DOWN", body.Error.Code)
+
+	// Skipped routes keep being served
+	resp, err = app.Test(httptest.NewRequest("GET", "/health", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func TestDrainer_WaitBlocksUntilTrackedRequestsFinish(t *testing.T) {
+	drainer := middleware.NewDrainer(time.Second)
+	app, release := newDrainApp(drainer)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			app.Test(httptest.NewRequest("POST", "/orders", nil), -1)
+		}()
+	}
+	require.Eventually(t, func() bool { return drainer.InFlight() == 2 }, time.Second, time.Millisecond)
+	drainer.Start()
+
+	done := make(chan bool)
+	go func() { done <- drainer.Wait(5 * time.Second) }()
+	select {
+	case <-done:
+		t.Fatal("Wait returned with orders in flight")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case ok := <-done:
+		assert.True(t, ok)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Wait did not return after the orders finished")
+	}
+	wg.Wait()
+	assert.Equal(t, int64(0), drainer.InFlight())
+}
+
+func TestDrainer_WaitGivesUpAtTimeout(t *testing.T) {
+	drainer := middleware.NewDrainer(time.Second)
+	app, release := newDrainApp(drainer)
+	defer close(release)
+
+	go app.Test(httptest.NewRequest("POST", "/orders", nil), -1)
+	require.Eventually(t, func() bool { return drainer.InFlight() == 1 }, time.Second, time.Millisecond)
+	drainer.Start()
+
+	start := time.Now()
+	assert.False(t, drainer.Wait(150*time.Millisecond))
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, int64(1), drainer.InFlight())
+}
+
+func TestDrainer_WaitReturnsAtOnceWhenIdle(t *testing.T) {
+	drainer := middleware.NewDrainer(time.Second)
+	drainer.Start()
+
+	start := time.Now()
+	assert.True(t, drainer.Wait(time.Second))
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+}
@@ -0,0 +1,80 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/polymarket"
+)
+
+func newDriftClient(t *testing.T, mode string) (*polymarket.Client, *mockupstream.Server) {
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	cfg.Polymarket.Validation = mode
+
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	return polymarket.NewClient(&cfg.Polymarket, c), mock
+}
+
+func TestDrift_WarnReportsUnknownAndMissingFields(t *testing.T) {
+	client, mock := newDriftClient(t, config.ValidationWarn)
+	mock.On(mockupstream.Gamma, "GET", "/markets", 200, `[{"id":"1","question":"q","brandNew":true}]`)
+
+	_, err := client.Get(mock.URL(mockupstream.Gamma)+"/markets?limit=1", nil)
+	require.NoError(t, err)
+
+	// Unvalidated endpoints are not reported
+	_, err = client.Get(mock.URL(mockupstream.CLOB)+"/midpoint?token_id=1", nil)
+	require.NoError(t, err)
+
+	reports := client.SchemaDrift()
+	require.Len(t, reports, 1)
+	assert.Equal(t, "gamma /markets", reports[0].Endpoint)
+	assert.EqualValues(t, 1, reports[0].Checked)
+	assert.EqualValues(t, 1, reports[0].Breaking)
+	assert.EqualValues(t, 1, reports[0].UnknownFields["brandNew"])
+	assert.Contains(t, reports[0].MissingFields, "slug")
+	assert.NotContains(t, reports[0].MissingFields, "id")
+}
+
+func TestDrift_StrictRejectsBreakingPayloads(t *testing.T) {
+	client, mock := newDriftClient(t, config.ValidationStrict)
+
+	// Fields added upstream are not breaking
+	mock.On(mockupstream.CLOB, "GET", "/book", 200,
+		`{"token_id":"t","bids":[],"asks":[],"hash":"h","timestamp":1,"extra":1}`)
+	_, err := client.Get(mock.URL(mockupstream.CLOB)+"/book?token_id=t", nil)
+	require.NoError(t, err)
+
+	// A type change is
+	mock.On(mockupstream.CLOB, "GET", "/book", 200,
+		`{"token_id":"t","bids":"none","asks":[],"hash":"h","timestamp":1}`)
+	_, err = client.Get(mock.URL(mockupstream.CLOB)+"/book?token_id=t", nil)
+	assert.True(t, errors.Is(err, polymarket.ErrSchemaDrift))
+
+	// And so is a removed field
+	mock.On(mockupstream.CLOB, "GET", "/book", 200, `{"bids":[],"asks":[],"hash":"h","timestamp":1}`)
+	_, err = client.Get(mock.URL(mockupstream.CLOB)+"/book?token_id=t", nil)
+	assert.True(t, errors.Is(err, polymarket.ErrSchemaDrift))
+	assert.Contains(t, err.Error(), "token_id")
+}
+
+func TestDrift_OffByDefault(t *testing.T) {
+	client, mock := newDriftClient(t, config.ValidationOff)
+	mock.On(mockupstream.Gamma, "GET", "/markets", 200, `[{"unexpected":1}]`)
+
+	_, err := client.Get(mock.URL(mockupstream.Gamma)+"/markets", nil)
+	require.NoError(t, err)
+	assert.Nil(t, client.SchemaDrift())
+}
@@ -0,0 +1,90 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/equity"
+	"github.com/polygo/internal/polymarket"
+)
+
+func newTestEquityTracker(t *testing.T, positions string, addresses []string, cfg *config.EquityConfig) *equity.Tracker {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(positions))
+	}))
+	t.Cleanup(srv.Close)
+
+	full := config.DefaultConfig()
+	full.Polymarket.DataBaseURL = srv.URL
+	c, err := cache.New(&full.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	data := polymarket.NewDataClient(polymarket.NewClient(&full.Polymarket, c))
+	return equity.New(data, func() []string { return addresses }, cfg)
+}
+
+func TestEquity_SnapshotsWatchedWalletsOncePerDay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "equity.json")
+	cfg := &config.EquityConfig{PositionsLimit: 100, Retention: 3 * 24 * time.Hour, Path: path}
+	tracker := newTestEquityTracker(t,
+		`[{"size":"100","currentValue":"60","cashPnl":"10","realizedPnl":"2.5"},{"size":10,"curPrice":0.5,"cashPnl":-1}]`,
+		[]string{"0xABC"}, cfg)
+
+	day := time.Date(2026, 3, 2, 0, 0, 5, 0, time.UTC)
+	tracker.Snapshot(day)
+	tracker.Snapshot(day.Add(time.Hour))
+	history := tracker.History("0xabc", "")
+	require.Len(t, history, 1, "one snapshot per day")
+	assert.Equal(t, equity.Snapshot{Date: "2026-03-02", Time: day, Value: 65, PnL: 11.5, Positions: 2}, history[0])
+
+	// Old snapshots are dropped past Retention; history survives a restart
+	tracker.Record("0xabc", equity.Snapshot{Date: "2026-02-20", Time: day.AddDate(0, 0, -10), Value: 1})
+	tracker.Record("0xabc", equity.Snapshot{Date: "2026-03-03", Time: day.AddDate(0, 0, 1), Value: 70})
+	restored := newTestEquityTracker(t, `[]`, nil, cfg)
+	history = restored.History("0xABC", "")
+	require.Len(t, history, 2)
+	assert.Equal(t, "2026-03-02", history[0].Date)
+	assert.Equal(t, "2026-03-03", history[1].Date)
+	assert.Len(t, restored.History("0xabc", "2026-03-03"), 1)
+}
+
+func TestEquity_BuildsCurveStatistics(t *testing.T) {
+	snaps := []equity.Snapshot{
+		{Date: "2026-03-06", Value: 100, PnL: 0},  // Friday
+		{Date: "2026-03-07", Value: 110, PnL: 10}, // +10%
+		{Date: "2026-03-08", Value: 300, PnL: 21}, // +10%, with 189 more put into positions
+		{Date: "2026-03-09", Value: 150, PnL: -9}, // -10% on Monday
+		{Date: "2026-03-10", Value: 150, PnL: 6},  // +10%
+	}
+
+	curve := equity.Build("0xabc", snaps, equity.PeriodWeek)
+	require.Len(t, curve.Points, 5)
+	assert.Equal(t, []float64{0, 0.1, 0.1, -0.1, 0.1}, []float64{
+		curve.Points[0].Return, curve.Points[1].Return, curve.Points[2].Return, curve.Points[3].Return, curve.Points[4].Return,
+	})
+	assert.Equal(t, 1.21, curve.Points[2].Equity)
+	assert.Equal(t, 0.1979, curve.TotalReturn)
+	assert.Equal(t, 0.1, curve.MaxDrawdown)
+	require.NotNil(t, curve.Sharpe)
+	assert.InDelta(t, 0.5*19.1049, *curve.Sharpe, 0.01)
+
+	assert.Equal(t, []equity.PeriodReturn{
+		{Period: "2026-03-02", Start: "2026-03-06", End: "2026-03-08", Return: 0.21},
+		{Period: "2026-03-09", Start: "2026-03-09", End: "2026-03-10", Return: -0.01},
+	}, curve.Returns)
+
+	monthly := equity.Build("0xabc", snaps, equity.PeriodMonth)
+	require.Len(t, monthly.Returns, 1)
+	assert.Equal(t, 0.1979, monthly.Returns[0].Return)
+
+	assert.Nil(t, equity.Build("0xabc", snaps[:2], equity.PeriodDay).Sharpe, "one return has no volatility")
+}
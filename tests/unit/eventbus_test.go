@@ -0,0 +1,269 @@
+package unit
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/eventbus"
+	"github.com/polygo/internal/polymarket"
+)
+
+func TestEventBus_NormalizesUpstreamMessages(t *testing.T) {
+	n := eventbus.NewNormalizer(100)
+
+	events := n.Events([]byte(`{"event_type":"last_trade_price","market":"0xm","asset_id":"tok","price":"0.42","size":"10","side":"BUY","fee_rate_bps":"0","timestamp":"1700000000000"}`))
+	require.Len(t, events, 1)
+	assert.Equal(t, eventbus.Event{
+		Type: eventbus.TypeTrade, Market: "0xm", AssetID: "tok", Timestamp: 1700000000000,
+		Data: eventbus.Trade{Price: 0.42, Size: 10, Side: "BUY"},
+	}, events[0])
+
+	events = n.Events([]byte(`{"event_type":"price_change","market":"0xm","timestamp":"1700000000001","price_changes":[{"asset_id":"yes","price":"0.4","size":"0","side":"BUY","best_bid":"0.39","best_ask":"0.41"},{"asset_id":"no","price":"0.6","size":"5","side":"SELL"}]}`))
+	require.Len(t, events, 2)
+	assert.Equal(t, "yes", events[0].AssetID)
+	change := events[0].Data.(eventbus.PriceChange)
+	assert.Equal(t, 0.0, change.Size, "size 0 removes the level")
+	require.NotNil(t, change.BestBid)
+	assert.Equal(t, 0.39, *change.BestBid)
+	assert.Nil(t, events[1].Data.(eventbus.PriceChange).BestAsk)
+
+	events = n.Events([]byte(`{"event_type":"market_resolved","market":"0xm","assets_ids":["yes","no"],"winning_asset_id":"yes","winning_outcome":"Yes","timestamp":"1700000000002"}`))
+	require.Len(t, events, 1)
+	assert.Equal(t, eventbus.Resolution{WinningAssetID: "yes", WinningOutcome: "Yes", AssetIDs: []string{"yes", "no"}}, events[0].Data)
+
+	assert.Empty(t, n.Events([]byte(`{"event_type":"tick_size_change","market":"0xm"}`)))
+}
+
+func TestEventBus_DiffsBooksIntoDeltas(t *testing.T) {
+	n := eventbus.NewNormalizer(100)
+
+	events := n.Events([]byte(`[{"event_type":"book","market":"0xm","asset_id":"tok","bids":[{"price":"0.5","size":"3"}],"asks":[{"price":"0.6","size":"2"}]}]`))
+	require.Len(t, events, 1)
+	book := events[0].Data.(eventbus.BookDelta)
+	assert.True(t, book.Snapshot, "the first book is sent whole")
+	assert.Equal(t, uint64(1), book.Seq)
+
+	assert.Empty(t, n.Events([]byte(`{"event_type":"book","market":"0xm","asset_id":"tok","bids":[{"price":"0.5","size":"3"}],"asks":[{"price":"0.6","size":"2"}]}`)))
+
+	events = n.Events([]byte(`{"event_type":"book","market":"0xm","asset_id":"tok","bids":[{"price":"0.5","size":"4"}],"asks":[]}`))
+	require.Len(t, events, 1)
+	delta := events[0].Data.(eventbus.BookDelta)
+	assert.False(t, delta.Snapshot)
+	assert.Equal(t, uint64(2), delta.Seq)
+	assert.Equal(t, []eventbus.Level{{Price: 0.5, Size: 4}}, delta.Bids)
+	assert.Equal(t, []eventbus.Level{{Price: 0.6, Size: 0}}, delta.Asks)
+}
+
+func TestEventBus_NewValidatesConfig(t *testing.T) {
+	ws := polymarket.NewWSManager(&config.DefaultConfig().Polymarket)
+
+	cfg := config.DefaultConfig().EventBus
+	p, err := eventbus.New(ws, &cfg, 100)
+	require.NoError(t, err)
+	assert.False(t, p.Enabled())
+
+	cfg.Enabled = true
+	_, err = eventbus.New(ws, &cfg, 100)
+	assert.Error(t, err, "no servers")
+
+	cfg.Servers = []string{"localhost:9092"}
+	cfg.Backend = "rabbitmq"
+	_, err = eventbus.New(ws, &cfg, 100)
+	assert.Error(t, err)
+}
+
+func TestEventBus_PublishesToNATS(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	pubs := make(chan string, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte("INFO {\"server_id\":\"test\",\"max_payload\":1048576}\r\n"))
+
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			switch {
+			case strings.HasPrefix(line, "PUB "):
+				payload, _ := r.ReadString('\n')
+				pubs <- line + " " + strings.TrimRight(payload, "\r\n")
+			case line == "PING":
+				conn.Write([]byte("PONG\r\n"))
+			}
+		}
+	}()
+
+	cfg := config.DefaultConfig().EventBus
+	cfg.Enabled = true
+	cfg.Backend = config.EventBusNATS
+	cfg.Servers = []string{"nats://" + ln.Addr().String()}
+	cfg.Topics.PriceChanges = "" // not published
+
+	p, err := eventbus.New(polymarket.NewWSManager(&config.DefaultConfig().Polymarket), &cfg, 100)
+	require.NoError(t, err)
+	p.Start()
+	p.Handle(polymarket.WSChannelMarket, []byte(`{"event_type":"price_change","market":"0xm","asset_id":"tok","price":"0.5","size":"1","side":"BUY"}`))
+	p.Handle(polymarket.WSChannelMarket, []byte(`{"event_type":"last_trade_price","market":"0xm","asset_id":"tok","price":"0.5","size":"1","side":"BUY","timestamp":"1"}`))
+	p.Stop()
+
+	select {
+	case pub := <-pubs:
+		assert.True(t, strings.HasPrefix(pub, "PUB polygo.trades "), pub)
+		assert.Contains(t, pub, `"type":"trade"`)
+	case <-time.After(2 * time.Second):
+		t.Fatal("nothing published")
+	}
+	assert.Equal(t, eventbus.Stats{Published: 1}, p.Stats())
+}
+
+// fakeKafka offers ApiVersions v0, Metadata v1 and Produce v3 only, answers
+// Metadata with itself leading one partition of every topic asked for (or
+// of topic when all are) and acknowledges Produce requests, sending their
+// record batches to batches
+func fakeKafka(ln net.Listener, topic string, batches chan<- []byte) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go fakeKafkaConn(conn, ln.Addr().String(), topic, batches)
+	}
+}
+
+func fakeKafkaConn(conn net.Conn, addr, defaultTopic string, batches chan<- []byte) {
+	host, portStr, _ := net.SplitHostPort(addr)
+	port, _ := strconv.Atoi(portStr)
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			return
+		}
+		req := make([]byte, binary.BigEndian.Uint32(size[:]))
+		io.ReadFull(r, req)
+
+		apiKey := binary.BigEndian.Uint16(req)
+		correlation := req[4:8]
+		body := req[10+int(binary.BigEndian.Uint16(req[8:])):] // after the client ID
+
+		resp := append([]byte{}, correlation...)
+		str := func(s string) { resp = binary.BigEndian.AppendUint16(resp, uint16(len(s))); resp = append(resp, s...) }
+		i16 := func(v uint16) { resp = binary.BigEndian.AppendUint16(resp, v) }
+		i32 := func(v uint32) { resp = binary.BigEndian.AppendUint32(resp, v) }
+
+		switch apiKey {
+		case 18: // ApiVersions
+			i16(0)
+			i32(3)
+			for _, api := range [][3]uint16{{0, 3, 3}, {3, 1, 1}, {18, 0, 0}} {
+				i16(api[0])
+				i16(api[1])
+				i16(api[2])
+			}
+		case 3: // Metadata
+			topics := []string{defaultTopic}
+			if n := int32(binary.BigEndian.Uint32(body)); n >= 0 {
+				topics = topics[:0]
+				at := 4
+				for ; n > 0; n-- {
+					l := int(binary.BigEndian.Uint16(body[at:]))
+					topics = append(topics, string(body[at+2:at+2+l]))
+					at += 2 + l
+				}
+			}
+			i32(1) // brokers
+			i32(1)
+			str(host)
+			i32(uint32(port))
+			i16(0xffff) // no rack
+			i32(1)      // controller
+			i32(uint32(len(topics)))
+			for _, topic := range topics {
+				i16(0)
+				str(topic)
+				resp = append(resp, 0) // not internal
+				i32(1)                 // partitions
+				i16(0)
+				i32(0) // partition
+				i32(1) // leader
+				i32(1) // replicas
+				i32(1)
+				i32(1) // isr
+				i32(1)
+			}
+		default: // Produce
+			// transactional ID, acks, timeout, one topic, one partition, then the batch
+			at := 2 + 2 + 4 + 4
+			topicLen := int(binary.BigEndian.Uint16(body[at:]))
+			topic := string(body[at+2 : at+2+topicLen])
+			at += 2 + topicLen + 4 + 4
+			batchLen := int(binary.BigEndian.Uint32(body[at:]))
+			batches <- body[at+4 : at+4+batchLen]
+
+			i32(1)
+			str(topic)
+			i32(1)
+			i32(0) // partition
+			i16(0) // no error
+			resp = binary.BigEndian.AppendUint64(resp, 0)
+			resp = binary.BigEndian.AppendUint64(resp, ^uint64(0))
+			i32(0) // throttle
+		}
+
+		out := binary.BigEndian.AppendUint32(nil, uint32(len(resp)))
+		conn.Write(append(out, resp...))
+	}
+}
+
+func TestEventBus_PublishesToKafka(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	batches := make(chan []byte, 10)
+	go fakeKafka(ln, config.DefaultConfig().EventBus.Topics.Resolutions, batches)
+
+	cfg := config.DefaultConfig().EventBus
+	cfg.Enabled = true
+	cfg.Backend = config.EventBusKafka
+	cfg.Servers = []string{ln.Addr().String()}
+
+	p, err := eventbus.New(polymarket.NewWSManager(&config.DefaultConfig().Polymarket), &cfg, 100)
+	require.NoError(t, err)
+	p.Start()
+	p.Handle(polymarket.WSChannelMarket, []byte(`{"event_type":"market_resolved","market":"0xm","winning_asset_id":"yes","winning_outcome":"Yes","timestamp":"1"}`))
+	p.Stop()
+
+	select {
+	case batch := <-batches:
+		assert.Equal(t, byte(2), batch[16], "record batch magic")
+		assert.Equal(t, uint32(1), binary.BigEndian.Uint32(batch[57:]), "one record")
+		assert.Contains(t, string(batch), "0xm", "keyed by market")
+		assert.Contains(t, string(batch), `"winning_outcome":"Yes"`)
+	case <-time.After(2 * time.Second):
+		t.Fatal("nothing produced")
+	}
+	assert.Equal(t, eventbus.Stats{Published: 1}, p.Stats())
+}
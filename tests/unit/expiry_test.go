@@ -0,0 +1,118 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/expiry"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/webhooks"
+	"github.com/polygo/pkg/polygoclient"
+)
+
+func newExpiryTracker(t *testing.T) (*expiry.Tracker, *webhooks.Dispatcher, *mockupstream.Server, *config.Config) {
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	clob := polymarket.NewClobClient(polymarket.NewClient(&cfg.Polymarket, c))
+	dispatcher, err := webhooks.NewDispatcher(&cfg.Webhooks)
+	require.NoError(t, err)
+	return expiry.New(clob, dispatcher, &cfg.Auth, &cfg.OrderExpiry), dispatcher, mock, cfg
+}
+
+func TestExpiry_ValidatesExpirations(t *testing.T) {
+	tracker, _, _, _ := newExpiryTracker(t)
+	now := time.Unix(1_800_000_000, 0)
+	gtd := func(expiration int64) *models.CreateOrderRequest {
+		return &models.CreateOrderRequest{Type: models.OrderTypeGTD, Expiration: expiration}
+	}
+
+	assert.NoError(t, tracker.Validate(&models.CreateOrderRequest{Type: models.OrderTypeGTC}, now))
+	assert.ErrorIs(t, tracker.Validate(&models.CreateOrderRequest{Type: models.OrderTypeGTC, Expiration: now.Unix() + 3600}, now), expiry.ErrExpirationNotAllowed)
+	assert.ErrorIs(t, tracker.Validate(gtd(0), now), expiry.ErrExpirationRequired)
+	assert.ErrorIs(t, tracker.Validate(gtd(now.Add(time.Hour).UnixMilli()), now), expiry.ErrExpirationUnit)
+	assert.ErrorIs(t, tracker.Validate(gtd(now.Unix()-1), now), expiry.ErrExpirationPast)
+
+	err := tracker.Validate(gtd(now.Unix()+60), now)
+	assert.ErrorIs(t, err, expiry.ErrExpirationTooNear)
+	assert.Contains(t, err.Error(), "at least 1m30s from now")
+
+	assert.NoError(t, tracker.Validate(gtd(now.Unix()+90), now))
+}
+
+func TestExpiry_CancelsBeforeExpiryAndNotifies(t *testing.T) {
+	tracker, dispatcher, mock, cfg := newExpiryTracker(t)
+	events, stop := dispatcher.Listen("owner")
+	defer stop()
+	others, stopOthers := dispatcher.Listen("someone-else")
+	defer stopOthers()
+
+	creds := &polygoclient.Credentials{APIKey: "key", Secret: "c2VjcmV0", Passphrase: "pass"}
+	now := time.Now()
+	tracker.Track("0xsoon", "owner", "0xmaker", now.Add(20*time.Second), creds)
+	tracker.Track("0xnosecret", "owner", "", now.Add(25*time.Second), &polygoclient.Credentials{APIKey: "key"})
+	tracker.Track("0xlater", "owner", "", now.Add(time.Hour), creds)
+	tracker.Track("0xforgotten", "owner", "", now.Add(10*time.Second), creds)
+	tracker.Forget("0xforgotten")
+
+	orders := tracker.Orders("owner")
+	require.Len(t, orders, 3)
+	assert.Equal(t, "0xsoon", orders[0].OrderID, "soonest expiry first")
+	assert.True(t, orders[0].AutoCancel)
+	assert.False(t, orders[1].AutoCancel, "no secret to cancel with")
+	assert.Empty(t, tracker.Orders("someone-else"))
+
+	tracker.Check(now)
+
+	deletes := mock.Requests(mockupstream.CLOB)
+	require.Len(t, deletes, 1, "only the order with a secret is cancelled")
+	assert.Equal(t, "/order/0xsoon", deletes[0].Path)
+	h := deletes[0].Header
+	assert.Equal(t, creds.Sign(h.Get(cfg.Auth.TimestampHeader), "DELETE", "/order/0xsoon", nil), h.Get(cfg.Auth.SignatureHeader))
+
+	notices := map[string]expiry.Notice{}
+	for i := 0; i < 2; i++ {
+		select {
+		case event := <-events:
+			assert.Equal(t, expiry.EventExpiring, event.Type)
+			notice := event.Data.(expiry.Notice)
+			notices[notice.OrderID] = notice
+		case <-time.After(time.Second):
+			t.Fatal("missing order.expiring event")
+		}
+	}
+	assert.True(t, notices["0xsoon"].Cancelled)
+	assert.False(t, notices["0xnosecret"].Cancelled)
+	assert.NotEmpty(t, notices["0xnosecret"].Reason)
+	assert.Empty(t, others, "events go to their owner only")
+
+	require.Len(t, tracker.Orders("owner"), 1, "handled orders stop being tracked")
+}
+
+func TestExpiry_ReportsOrdersTheCLOBDidNotCancel(t *testing.T) {
+	tracker, dispatcher, mock, _ := newExpiryTracker(t)
+	mock.On(mockupstream.CLOB, "DELETE", "/order/0xfilled", 200, `{"canceled":[],"not_canceled":{"0xfilled":"order already filled"}}`)
+	events, stop := dispatcher.Listen("owner")
+	defer stop()
+
+	creds := &polygoclient.Credentials{APIKey: "key", Secret: "c2VjcmV0", Passphrase: "pass"}
+	tracker.Track("0xfilled", "owner", "", time.Now(), creds)
+	tracker.Check(time.Now())
+
+	notice := (<-events).Data.(expiry.Notice)
+	assert.False(t, notice.Cancelled)
+	assert.Equal(t, "order already filled", notice.Reason)
+}
@@ -0,0 +1,128 @@
+package unit
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/export"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/storage"
+)
+
+func newExportScheduler(t *testing.T) *export.Scheduler {
+	cat := catalog.New(nil, &config.CatalogConfig{})
+	cat.Load([]*models.Event{{
+		ID:   "e1",
+		Slug: "super-bowl",
+		Tags: []models.Tag{{Slug: "sports"}},
+		Markets: []models.Market{
+			{ID: "m1", Question: "Will the Chiefs win?", Active: true, Volume: "1200.5"},
+			{ID: "m2", Question: "Will the Eagles win?", Active: true, Closed: true},
+		},
+	}})
+
+	return export.New(nil, cat, nil, storage.NewLocal(), &config.ExportConfig{
+		Dir:         t.TempDir(),
+		MinInterval: time.Minute,
+		MaxJobs:     2,
+		History:     5,
+	})
+}
+
+func TestExport_CatalogToLocalCSV(t *testing.T) {
+	s := newExportScheduler(t)
+
+	job, err := s.Create(export.Spec{Name: "catalog", Dataset: "Catalog", Interval: "1h", Prefix: "/daily/"})
+	require.NoError(t, err)
+	assert.Equal(t, export.FormatCSV, job.Format)
+	assert.Equal(t, "daily", job.Prefix)
+	require.NotNil(t, job.NextRun, "a new job is due immediately")
+
+	run, err := s.RunNow(job.ID)
+	require.NoError(t, err)
+	require.Equal(t, export.StatusSucceeded, run.Status, run.Error)
+	assert.Equal(t, 2, run.Rows)
+	assert.True(t, strings.HasSuffix(run.Location, ".csv"))
+
+	data, err := os.ReadFile(run.Location)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "market_id,condition_id,question,"))
+	assert.Contains(t, lines[1], "m1,,Will the Chiefs win?,,e1,super-bowl,sports,sports,true,false,,1200.5,")
+
+	runs, err := s.Runs(job.ID)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, run.ID, runs[0].ID)
+
+	job, err = s.Get(job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, job.NextRun)
+	assert.Equal(t, run.StartedAt.Add(time.Hour), *job.NextRun)
+}
+
+func TestExport_Parquet(t *testing.T) {
+	s := newExportScheduler(t)
+
+	job, err := s.Create(export.Spec{Dataset: "catalog", Format: "parquet", Interval: "24h", Paused: true})
+	require.NoError(t, err)
+	assert.Nil(t, job.NextRun, "paused jobs are not scheduled")
+
+	run, err := s.RunNow(job.ID)
+	require.NoError(t, err)
+	require.Equal(t, export.StatusSucceeded, run.Status, run.Error)
+
+	data, err := os.ReadFile(run.Location)
+	require.NoError(t, err)
+	assert.Equal(t, "PAR1", string(data[:4]))
+	assert.Equal(t, "PAR1", string(data[len(data)-4:]))
+
+	f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), f.NumRows())
+	question, ok := f.Schema().Lookup("question")
+	require.True(t, ok)
+
+	rows := make([]parquet.Row, 2)
+	n, err := f.RowGroups()[0].Rows().ReadRows(rows)
+	require.Equal(t, 2, n, err)
+	assert.Equal(t, "Will the Chiefs win?", rows[0][question.ColumnIndex].String())
+}
+
+func TestExport_ValidatesJobs(t *testing.T) {
+	s := newExportScheduler(t)
+
+	for name, spec := range map[string]export.Spec{
+		"unknown dataset":  {Dataset: "orders", Interval: "1h"},
+		"missing address":  {Dataset: "trades", Interval: "1h"},
+		"missing tokens":   {Dataset: "candles", Interval: "1h"},
+		"unknown format":   {Dataset: "catalog", Format: "xlsx", Interval: "1h"},
+		"escaping prefix":  {Dataset: "catalog", Prefix: "../etc", Interval: "1h"},
+		"too frequent":     {Dataset: "catalog", Interval: "10s"},
+		"missing interval": {Dataset: "catalog"},
+	} {
+		_, err := s.Create(spec)
+		assert.True(t, errors.Is(err, export.ErrInvalidJob), name)
+	}
+
+	_, err := s.Get("exp_missing")
+	assert.ErrorIs(t, err, export.ErrNotFound)
+
+	_, err = s.Create(export.Spec{Dataset: "catalog", Interval: "1h"})
+	require.NoError(t, err)
+	_, err = s.Create(export.Spec{Dataset: "trades", Address: "0x1111111111111111111111111111111111111111", Interval: "1h"})
+	require.NoError(t, err)
+	_, err = s.Create(export.Spec{Dataset: "catalog", Interval: "1h"})
+	assert.ErrorIs(t, err, export.ErrTooManyJobs)
+}
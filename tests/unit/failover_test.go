@@ -0,0 +1,120 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/polymarket"
+)
+
+// newFailoverClient returns a client whose Gamma API has a base URL
+// answering 503 while down is set, and one mirror
+func newFailoverClient(t *testing.T, down *atomic.Bool) (*polymarket.Client, *polymarket.Failover) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case down.Load():
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`"primary"`))
+		}
+	}))
+	t.Cleanup(primary.Close)
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`"mirror"`))
+	}))
+	t.Cleanup(mirror.Close)
+
+	cfg := config.DefaultConfig()
+	cfg.Polymarket.GammaBaseURL = primary.URL
+	cfg.Polymarket.GammaMirrors = []string{mirror.URL + "/"}
+	cfg.Polymarket.FailoverThreshold = 2
+	cfg.Polymarket.RetryCount = 0
+	cfg.Polymarket.UpstreamRPS = 0
+
+	client := polymarket.NewClient(&cfg.Polymarket, nil)
+	failover := polymarket.NewFailover(client, &cfg.Polymarket, &cfg.Health)
+	client.SetFailover(failover)
+	return client, failover
+}
+
+func TestFailover_MovesToMirrorAndBack(t *testing.T) {
+	var down atomic.Bool
+	client, failover := newFailoverClient(t, &down)
+
+	body, err := client.Get(client.Gamma("/markets"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, `"primary"`, string(body))
+
+	// Failed requests count against the base URL
+	down.Store(true)
+	for i := 0; i < 2; i++ {
+		_, err := client.Get(client.Gamma("/markets"), nil)
+		require.Error(t, err)
+	}
+	body, err = client.Get(client.Gamma("/markets"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, `"mirror"`, string(body))
+
+	status := failover.Status()
+	require.Len(t, status, 1)
+	assert.Equal(t, polymarket.UpstreamGamma, status[0].Name)
+	assert.True(t, status[0].FailedOver)
+	assert.False(t, status[0].URLs[0].Healthy)
+	assert.NotEmpty(t, status[0].URLs[0].Error)
+
+	// The base URL takes traffic back after passing enough checks
+	down.Store(false)
+	failover.Check()
+	body, err = client.Get(client.Gamma("/markets"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, `"mirror"`, string(body))
+
+	failover.Check()
+	body, err = client.Get(client.Gamma("/markets"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, `"primary"`, string(body))
+	assert.False(t, failover.Status()[0].FailedOver)
+}
+
+func TestFailover_ChecksMarkBaseURLDown(t *testing.T) {
+	var down atomic.Bool
+	client, failover := newFailoverClient(t, &down)
+
+	down.Store(true)
+	failover.Check()
+	assert.False(t, failover.Status()[0].FailedOver)
+	failover.Check()
+	assert.True(t, failover.Status()[0].FailedOver)
+
+	body, err := client.Get(client.Gamma("/markets"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, `"mirror"`, string(body))
+}
+
+func TestFailover_ClientErrorsDoNotCount(t *testing.T) {
+	var down atomic.Bool
+	client, failover := newFailoverClient(t, &down)
+
+	for i := 0; i < 3; i++ {
+		_, err := client.Get(client.Gamma("/missing"), nil)
+		require.Error(t, err)
+	}
+	assert.False(t, failover.Status()[0].FailedOver)
+}
+
+func TestFailover_DisabledWithoutMirrors(t *testing.T) {
+	cfg := config.DefaultConfig()
+	client := polymarket.NewClient(&cfg.Polymarket, nil)
+	failover := polymarket.NewFailover(client, &cfg.Polymarket, &cfg.Health)
+
+	assert.False(t, failover.Enabled())
+	assert.Nil(t, failover.Status())
+}
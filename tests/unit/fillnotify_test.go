@@ -0,0 +1,124 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/fillnotify"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/webhooks"
+	"github.com/polygo/pkg/polygoclient"
+)
+
+func newFillNotifier(t *testing.T) (*fillnotify.Notifier, *webhooks.Dispatcher, *polymarket.FillTracker, *mockupstream.Server) {
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	dispatcher, err := webhooks.NewDispatcher(&cfg.Webhooks)
+	require.NoError(t, err)
+	fills := polymarket.NewFillTracker()
+	n := fillnotify.New(&cfg.Polymarket, nil, dispatcher, fills, &cfg.FillNotify)
+	t.Cleanup(n.Stop)
+	return n, dispatcher, fills, mock
+}
+
+func nextFill(t *testing.T, events <-chan *webhooks.Event) fillnotify.Fill {
+	select {
+	case e := <-events:
+		assert.Equal(t, fillnotify.EventFilled, e.Type)
+		fill, ok := e.Data.(fillnotify.Fill)
+		require.True(t, ok)
+		return fill
+	case <-time.After(2 * time.Second):
+		t.Fatal("no fill event")
+	}
+	return fillnotify.Fill{}
+}
+
+func TestFillNotify_PublishesFillsToOwner(t *testing.T) {
+	n, dispatcher, fills, mock := newFillNotifier(t)
+	events, stop := dispatcher.Listen("owner")
+	defer stop()
+	others, stopOthers := dispatcher.Listen("someone-else")
+	defer stopOthers()
+
+	creds := &polygoclient.Credentials{APIKey: "key", Secret: "c2VjcmV0", Passphrase: "pass"}
+	fills.Track("0xmaker-order", "0xmaker")
+	n.Track(fillnotify.Order{ID: "0xmaker-order", RequestID: "req-1", Owner: "owner", Maker: "0xmaker", Size: "10"}, creds)
+	n.Track(fillnotify.Order{ID: "0xtaker-order", Owner: "owner", Size: "5"}, creds)
+	n.Track(fillnotify.Order{ID: "0xnosecret", Owner: "owner", Size: "5"}, &polygoclient.Credentials{APIKey: "key"})
+	assert.Equal(t, 2, n.Watching(), "orders placed without the secret cannot be watched")
+	require.Eventually(t, func() bool {
+		return len(mock.UserSubscriptions()) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"key"}, mock.UserSubscriptions(), "one user channel per API key")
+
+	// The resting order is matched by someone else's taker order
+	trade := func(id, status, amount string) []byte {
+		return []byte(`{"event_type":"trade","id":"` + id + `","status":"` + status + `","market":"0xmarket","asset_id":"yes","outcome":"Yes",` +
+			`"side":"BUY","price":"0.41","size":"` + amount + `","taker_order_id":"0xsomeone","timestamp":"1757908892",` +
+			`"maker_orders":[{"order_id":"0xmaker-order","asset_id":"yes","outcome":"Yes","price":"0.40","matched_amount":"` + amount + `"}]}`)
+	}
+	mock.PushUser("key", trade("trade-1", "MATCHED", "4"))
+	fill := nextFill(t, events)
+	assert.Equal(t, fillnotify.Fill{
+		OrderID:     "0xmaker-order",
+		Maker:       "0xmaker",
+		TradeID:     "trade-1",
+		Role:        "maker",
+		Market:      "0xmarket",
+		AssetID:     "yes",
+		Outcome:     "Yes",
+		Side:        "SELL",
+		Price:       "0.40",
+		Size:        "4",
+		SizeMatched: "4",
+		Remaining:   "6",
+		Status:      "MATCHED",
+		Timestamp:   1757908892000,
+	}, fill)
+
+	// Settlement updates of the same trade are not announced again
+	mock.PushUser("key", trade("trade-1", "MINED", "4"))
+	mock.PushUser("key", trade("trade-2", "MATCHED", "6"))
+	fill = nextFill(t, events)
+	assert.Equal(t, "trade-2", fill.TradeID)
+	assert.Equal(t, "10", fill.SizeMatched)
+	assert.Equal(t, "0", fill.Remaining)
+	assert.Equal(t, 1, n.Watching(), "filled orders are no longer watched")
+	_, filled := fills.Observe("0xmaker-order", "MATCHED", "10")
+	assert.False(t, filled, "lookups do not announce the fill again")
+
+	// Taker fills, in a batch with an event of another account's order
+	mock.PushUser("key", []byte(`[{"event_type":"trade","id":"trade-3","status":"MATCHED","market":"0xmarket","asset_id":"no","side":"SELL","price":"0.55","size":"2","taker_order_id":"0xtaker-order","maker_orders":[]},`+
+		`{"event_type":"trade","id":"trade-4","status":"MATCHED","taker_order_id":"0xunknown","size":"1"}]`))
+	fill = nextFill(t, events)
+	assert.Equal(t, "taker", fill.Role)
+	assert.Equal(t, "SELL", fill.Side)
+	assert.Equal(t, "3", fill.Remaining)
+
+	assert.Empty(t, others)
+	select {
+	case e := <-events:
+		t.Fatalf("unexpected event %+v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Cancelled orders stop being watched, and the idle channel is closed
+	mock.PushUser("key", []byte(`{"event_type":"order","id":"0xtaker-order","type":"CANCELLATION"}`))
+	require.Eventually(t, func() bool { return n.Watching() == 0 }, 2*time.Second, 10*time.Millisecond)
+	n.Sweep(time.Now())
+	assert.Equal(t, 1, n.Streams(), "kept for IdleTimeout")
+	n.Sweep(time.Now().Add(time.Hour))
+	assert.Zero(t, n.Streams())
+	require.Eventually(t, func() bool {
+		return len(mock.UserSubscriptions()) == 0
+	}, 2*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,50 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/polymarket"
+)
+
+func TestFillTracker_ReportsPartialAndFullFills(t *testing.T) {
+	tracker := polymarket.NewFillTracker()
+	tracker.Track("order-1", "0xABC")
+
+	_, filled := tracker.Observe("order-1", "LIVE", "0")
+	assert.False(t, filled)
+
+	address, filled := tracker.Observe("order-1", "LIVE", "5")
+	assert.True(t, filled)
+	assert.Equal(t, "0xabc", address)
+
+	// Same matched size again is not a new fill
+	_, filled = tracker.Observe("order-1", "LIVE", "5")
+	assert.False(t, filled)
+
+	_, filled = tracker.Observe("order-1", "MATCHED", "10")
+	assert.True(t, filled)
+	assert.Equal(t, 0, tracker.Len())
+}
+
+func TestFillTracker_IgnoresUntrackedAndCancelled(t *testing.T) {
+	tracker := polymarket.NewFillTracker()
+
+	_, filled := tracker.Observe("unknown", "MATCHED", "10")
+	assert.False(t, filled)
+
+	tracker.Track("order-2", "0xdef")
+	_, filled = tracker.Observe("order-2", "CANCELLED", "0")
+	assert.False(t, filled)
+	assert.Equal(t, 0, tracker.Len())
+}
+
+func TestUserDataKey_ChangesWithGeneration(t *testing.T) {
+	k0 := cache.UserDataKey("0xabc", 0, "/positions?user=0xabc")
+	k1 := cache.UserDataKey("0xabc", 1, "/positions?user=0xabc")
+
+	assert.NotEqual(t, k0, k1)
+	assert.Contains(t, k0, cache.PrefixUserData)
+}
@@ -0,0 +1,53 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/fsutil"
+)
+
+func TestWriteFileAtomic_CreatesAndReplaces(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+
+	require.NoError(t, fsutil.WriteFileAtomic(path, []byte(`{"a":1}`), 0o600))
+	require.NoError(t, fsutil.WriteFileAtomic(path, []byte(`{"a":2}`), 0o600))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":2}`, string(data))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	dir, err := os.Stat(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o700), dir.Mode().Perm())
+
+	// No temporary file is left behind
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestWriteFileAtomic_KeepsOldFileOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+	require.NoError(t, fsutil.WriteFileAtomic(path, []byte("old"), 0o644))
+
+	// A directory in the way of the rename
+	target := filepath.Join(dir, "taken")
+	require.NoError(t, os.MkdirAll(filepath.Join(target, "child"), 0o755))
+	assert.Error(t, fsutil.WriteFileAtomic(target, []byte("new"), 0o644))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(data))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
@@ -0,0 +1,85 @@
+package unit
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/polygo/internal/api/handlers"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/wsbuffer"
+	"github.com/polygo/internal/wsreplay"
+)
+
+// dropAll closes every upstream connection accepted so far
+func (f *fakeUpstream) dropAll() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, conn := range f.conns {
+		conn.Close()
+	}
+}
+
+func TestWSManager_ReconnectsWithoutLeaking(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	upstream := &fakeUpstream{}
+	srv := httptest.NewServer(upstream)
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	m := polymarket.NewWSManager(&config.PolymarketConfig{
+		WsClobURL:     url,
+		WsLiveDataURL: url,
+		WsShards:      2,
+	})
+	require.NoError(t, m.Connect())
+	require.Eventually(t, func() bool { return upstream.count() == 3 }, time.Second, 10*time.Millisecond)
+
+	// Both shards and the live data connection come back, one each
+	upstream.dropAll()
+	require.Eventually(t, func() bool { return upstream.count() == 6 }, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 6, upstream.count())
+
+	m.Close()
+}
+
+func TestWSManager_CloseWhileRedialing(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	upstream := &fakeUpstream{}
+	srv := httptest.NewServer(upstream)
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	m := polymarket.NewWSManager(&config.PolymarketConfig{
+		WsClobURL:     url,
+		WsLiveDataURL: url,
+		WsShards:      2,
+	})
+	require.NoError(t, m.Connect())
+	require.Eventually(t, func() bool { return upstream.count() == 3 }, time.Second, 10*time.Millisecond)
+
+	// With the upstream gone every connection is stuck redialing
+	upstream.dropAll()
+	srv.Close()
+	require.Eventually(t, func() bool { return !m.IsConnected() }, time.Second, 10*time.Millisecond)
+
+	m.Close()
+}
+
+func TestWebSocketHandler_CloseStopsBroadcasts(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	m := polymarket.NewWSManager(&config.PolymarketConfig{})
+	h := handlers.NewWebSocketHandler(m, nil, 100, wsbuffer.New(0, 0), wsreplay.New(0))
+	m.Close()
+	h.Close()
+	h.Close()
+}
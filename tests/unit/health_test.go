@@ -0,0 +1,125 @@
+package unit
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/polymarket"
+)
+
+// newProber probes fake upstreams whose CLOB answers with the status held
+// in clobStatus, after delay
+func newProber(t *testing.T, cfg config.HealthConfig, delay time.Duration) (*polymarket.HealthProber, *mockupstream.Server, *atomic.Int64) {
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+
+	clobStatus := &atomic.Int64{}
+	clobStatus.Store(http.StatusOK)
+	mock.Handle(mockupstream.CLOB, "GET", "/time", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.WriteHeader(int(clobStatus.Load()))
+	})
+
+	pm := config.DefaultConfig().Polymarket
+	mock.Apply(&pm)
+	cfg.ClobProbePath = "/time"
+	cfg.GammaProbePath = "/markets"
+	cfg.DataProbePath = "/"
+	if cfg.ProbeTimeout == 0 {
+		cfg.ProbeTimeout = time.Second
+	}
+	return polymarket.NewHealthProber(polymarket.NewClient(&pm, nil), &cfg), mock, clobStatus
+}
+
+func TestHealthProber_UnreachableOnlyBeyondThreshold(t *testing.T) {
+	prober, _, clobStatus := newProber(t, config.HealthConfig{UnhealthyThreshold: 150 * time.Millisecond}, 0)
+
+	results := prober.Check()
+	require.Contains(t, results, polymarket.UpstreamClob)
+	assert.True(t, results[polymarket.UpstreamClob].Healthy)
+	assert.Empty(t, prober.Unreachable())
+
+	// Failing, but not for long enough yet
+	clobStatus.Store(http.StatusBadGateway)
+	assert.Empty(t, prober.Unreachable())
+	results = prober.Check()
+	assert.False(t, results[polymarket.UpstreamClob].Healthy)
+	assert.Equal(t, http.StatusBadGateway, results[polymarket.UpstreamClob].StatusCode)
+	assert.False(t, results[polymarket.UpstreamClob].LastSuccess.IsZero(), "the last success is kept")
+
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, []string{polymarket.UpstreamClob}, prober.Unreachable())
+
+	// One successful probe makes it reachable again
+	clobStatus.Store(http.StatusOK)
+	assert.Empty(t, prober.Unreachable())
+}
+
+func TestHealthProber_NeverReachableCountsFromStartup(t *testing.T) {
+	prober, _, clobStatus := newProber(t, config.HealthConfig{UnhealthyThreshold: 100 * time.Millisecond}, 0)
+	clobStatus.Store(http.StatusServiceUnavailable)
+
+	assert.Empty(t, prober.Unreachable())
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, []string{polymarket.UpstreamClob}, prober.Unreachable())
+}
+
+func TestHealthProber_CachesResultsForProbeInterval(t *testing.T) {
+	prober, mock, clobStatus := newProber(t, config.HealthConfig{ProbeInterval: time.Hour, UnhealthyThreshold: time.Hour}, 0)
+
+	prober.Check()
+	clobStatus.Store(http.StatusBadGateway)
+	results := prober.Check()
+	assert.True(t, results[polymarket.UpstreamClob].Healthy)
+	assert.Len(t, mock.Requests(mockupstream.CLOB), 1)
+}
+
+func TestHealthProber_ConcurrentChecksShareOneProbe(t *testing.T) {
+	prober, mock, _ := newProber(t, config.HealthConfig{ProbeInterval: time.Hour, UnhealthyThreshold: time.Hour}, 100*time.Millisecond)
+
+	var wg sync.WaitGroup
+	results := make([]map[string]polymarket.ProbeResult, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = prober.Check()
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Len(t, mock.Requests(mockupstream.CLOB), 1)
+	for _, r := range results {
+		require.Contains(t, r, polymarket.UpstreamClob)
+		assert.True(t, r[polymarket.UpstreamClob].Healthy)
+	}
+}
+
+func TestHealthProber_ChecksWaitForTheProbeUnderWay(t *testing.T) {
+	// Results are always stale, so a caller probing under the lock would
+	// make every caller after it probe again in turn
+	prober, mock, _ := newProber(t, config.HealthConfig{UnhealthyThreshold: time.Hour}, 300*time.Millisecond)
+	go prober.Check()
+	require.Eventually(t, func() bool { return len(mock.Requests(mockupstream.CLOB)) == 1 }, time.Second, time.Millisecond)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Empty(t, prober.Unreachable())
+		}()
+	}
+	wg.Wait()
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Len(t, mock.Requests(mockupstream.CLOB), 1)
+}
@@ -0,0 +1,70 @@
+package unit
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/polymarket"
+)
+
+func newHotPathClient(tb testing.TB) (*polymarket.Client, *cache.Cache) {
+	mock := mockupstream.New()
+	tb.Cleanup(mock.Close)
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	mock.On(mockupstream.CLOB, "GET", "/book", 200, string(benchPayload))
+
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(tb, err)
+	tb.Cleanup(c.Close)
+	return polymarket.NewClient(&cfg.Polymarket, c), c
+}
+
+func TestGetWithCache_HitSharesTheFetchedBytes(t *testing.T) {
+	client, c := newHotPathClient(t)
+	url := client.CLOB("/book?token_id=1")
+
+	fetched, cached, err := client.GetWithCache(url, "book:1", time.Minute)
+	require.NoError(t, err)
+	require.False(t, cached)
+	assert.Equal(t, benchPayload, fetched)
+	c.Wait()
+
+	hit, cached, err := client.GetWithCache(url, "book:1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, cached)
+	assert.Same(t, &fetched[0], &hit[0], "the body is cached without a copy")
+}
+
+func BenchmarkGetWithCache_Hit(b *testing.B) {
+	client, c := newHotPathClient(b)
+	url := client.CLOB("/book?token_id=1")
+	client.GetWithCache(url, "book:1", time.Minute)
+	c.Wait()
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchPayload)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		client.GetWithCache(url, "book:1", time.Minute)
+	}
+}
+
+func BenchmarkGetWithCache_Miss(b *testing.B) {
+	client, _ := newHotPathClient(b)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchPayload)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		id := strconv.Itoa(i)
+		client.GetWithCache(client.CLOB("/book?token_id="+id), "book:"+id, time.Minute)
+	}
+}
@@ -0,0 +1,108 @@
+package unit
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/latency"
+)
+
+func TestHistogram_Quantiles(t *testing.T) {
+	var h latency.Histogram
+	assert.Zero(t, h.Quantile(0.5))
+
+	for i := 0; i < 90; i++ {
+		h.Observe(3 * time.Millisecond) // 2.5-5ms bucket
+	}
+	for i := 0; i < 10; i++ {
+		h.Observe(400 * time.Millisecond) // 250-500ms bucket
+	}
+
+	assert.Equal(t, uint64(100), h.Count())
+	assert.Equal(t, 400*time.Millisecond, h.Max())
+	p50 := h.Quantile(0.5)
+	assert.True(t, p50 > 2500*time.Microsecond && p50 <= 5*time.Millisecond, "p50 %v", p50)
+	p99 := h.Quantile(0.99)
+	assert.True(t, p99 > 250*time.Millisecond && p99 <= 400*time.Millisecond, "p99 %v capped at the max", p99)
+
+	buckets := h.Buckets()
+	assert.Equal(t, uint64(90), buckets[2])
+	assert.Equal(t, uint64(100), buckets[len(buckets)-1])
+}
+
+func TestHistogram_OverflowUpToMax(t *testing.T) {
+	var h latency.Histogram
+	h.Observe(30 * time.Second)
+	p99 := h.Quantile(0.99)
+	assert.True(t, p99 > 10*time.Second && p99 <= 30*time.Second, "p99 %v between the last bound and the max", p99)
+}
+
+func TestTiming_FanOutCountsWallTimeOnce(t *testing.T) {
+	timing := &latency.Timing{}
+	unbind := timing.Bind()
+	defer unbind()
+
+	// Three parallel upstream calls of 30ms on child goroutines
+	bind := latency.Inherit()
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer bind()()
+			done := latency.StartUpstream()
+			time.Sleep(30 * time.Millisecond)
+			done()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 3, timing.Calls())
+	assert.GreaterOrEqual(t, timing.Upstream(), 30*time.Millisecond)
+	assert.Less(t, timing.Upstream(), 80*time.Millisecond, "overlapping calls are not summed")
+}
+
+func TestTiming_UnboundGoroutinesAreNotMeasured(t *testing.T) {
+	timing := &latency.Timing{}
+	unbind := timing.Bind()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		latency.StartUpstream()()
+	}()
+	<-done
+	unbind()
+	latency.StartUpstream()()
+
+	assert.Zero(t, timing.Calls())
+	assert.Nil(t, latency.Current())
+}
+
+func TestRecorder_StatsAndPrometheus(t *testing.T) {
+	rec := latency.NewRecorder()
+	rec.Observe("GET", "/api/v1/markets", 120*time.Millisecond, 100*time.Millisecond, 200)
+	rec.Observe("GET", "/api/v1/markets", 80*time.Millisecond, 70*time.Millisecond, 502)
+	rec.Observe("POST", "/api/v1/orders", 20*time.Millisecond, 15*time.Millisecond, 200)
+
+	stats := rec.Stats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, "/api/v1/markets", stats[0].Route, "busiest first")
+	assert.Equal(t, uint64(2), stats[0].Count)
+	assert.Equal(t, uint64(1), stats[0].Errors)
+	assert.Equal(t, 120.0, stats[0].LatencyMs.Max)
+
+	var buf bytes.Buffer
+	rec.WritePrometheus(&buf)
+	out := buf.String()
+	assert.Contains(t, out, "# TYPE polygo_http_request_duration_seconds histogram")
+	assert.Contains(t, out, `polygo_http_request_duration_seconds_bucket{method="GET",route="/api/v1/markets",le="0.1"} 1`)
+	assert.Contains(t, out, `polygo_http_request_duration_seconds_bucket{method="GET",route="/api/v1/markets",le="+Inf"} 2`)
+	assert.Contains(t, out, `polygo_http_request_errors_total{method="GET",route="/api/v1/markets"} 1`)
+	assert.Contains(t, out, "# TYPE polygo_http_request_upstream_seconds_quantile summary")
+}
@@ -0,0 +1,77 @@
+package unit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/leaderboard"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/polymarket"
+)
+
+func TestLeaderboard_HistoryTracksRankChanges(t *testing.T) {
+	tr := leaderboard.New(nil, &config.LeaderboardConfig{Retention: 30 * 24 * time.Hour})
+	now := time.Now()
+
+	tr.Record(now.Add(-10*24*time.Hour), []leaderboard.Entry{{Rank: 1, Address: "0xold", PnL: 1}})
+	tr.Record(now.Add(-6*24*time.Hour), []leaderboard.Entry{
+		{Rank: 1, Address: "0xa", PnL: 100},
+		{Rank: 2, Address: "0xb", PnL: 90},
+	})
+	tr.Record(now, []leaderboard.Entry{
+		{Rank: 1, Address: "0xb", PnL: 150},
+		{Rank: 2, Address: "0xa", PnL: 110},
+		{Rank: 3, Address: "0xc", PnL: 80},
+	})
+
+	history := tr.History(7*24*time.Hour, 0)
+	require.Len(t, history, 3)
+
+	assert.Equal(t, "0xb", history[0].Address)
+	assert.Equal(t, 2, history[0].PreviousRank)
+	assert.Equal(t, 1, history[0].RankChange)
+	assert.Equal(t, 60.0, history[0].PnLChange)
+	assert.Len(t, history[0].Points, 2)
+
+	assert.Equal(t, -1, history[1].RankChange)
+	assert.Equal(t, 0, history[2].PreviousRank, "new entrants have no previous rank")
+	assert.Equal(t, 0, history[2].RankChange)
+
+	_, ok := tr.Trader("0xOLD", 7*24*time.Hour)
+	assert.False(t, ok, "outside the window")
+	old, ok := tr.Trader("0xOLD", 14*24*time.Hour)
+	require.True(t, ok)
+	assert.Equal(t, 0, old.Rank, "no longer ranked")
+	assert.Equal(t, 1, old.PreviousRank)
+}
+
+func TestLeaderboard_PollAndPersist(t *testing.T) {
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+	mock.On(mockupstream.Data, "GET", "/leaderboard", 200,
+		`[{"proxyWallet":"0xAbC","userName":"whale","pnl":"1200.5","vol":50000},{"proxyWallet":"0xdef","pnl":300}]`)
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	data := polymarket.NewDataClient(polymarket.NewClient(&cfg.Polymarket, c))
+
+	lbCfg := &config.LeaderboardConfig{Size: 10, Retention: time.Hour, Path: filepath.Join(t.TempDir(), "leaderboard.json")}
+	require.NoError(t, leaderboard.New(data, lbCfg).Poll())
+
+	// A new tracker picks the snapshot up from disk
+	trader, ok := leaderboard.New(nil, lbCfg).Trader("0xabc", time.Hour)
+	require.True(t, ok)
+	assert.Equal(t, 1, trader.Rank)
+	assert.Equal(t, "whale", trader.Name)
+	assert.Equal(t, 1200.5, trader.PnL)
+	assert.Equal(t, 50000.0, trader.Points[0].Volume)
+}
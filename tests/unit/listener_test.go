@@ -0,0 +1,77 @@
+package unit
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/listener"
+)
+
+func TestListener_ServesUnixSocketAndTCPTogether(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "polygo.sock")
+	cfg := config.DefaultConfig().Server
+	cfg.Listen = "unix://" + sock + ", tcp://127.0.0.1:0"
+
+	ln, err := listener.Open(&cfg)
+	require.NoError(t, err)
+
+	info, err := os.Stat(sock)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o660), info.Mode().Perm())
+
+	_, err = listener.Open(&cfg)
+	assert.Error(t, err, "the socket is in use")
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/health", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	go app.Listener(ln)
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", sock)
+		},
+	}}
+	resp, err := client.Get("http://polygo/health")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "ok", string(body))
+
+	require.NoError(t, app.Shutdown())
+	_, err = os.Stat(sock)
+	assert.True(t, os.IsNotExist(err), "the socket file is removed on shutdown")
+}
+
+func TestListener_RejectsBadSpecs(t *testing.T) {
+	cfg := config.DefaultConfig().Server
+
+	cfg.Listen = "http://localhost:8080"
+	_, err := listener.Open(&cfg)
+	assert.ErrorIs(t, err, listener.ErrInvalidSpec)
+
+	cfg.Listen = "unix://"
+	_, err = listener.Open(&cfg)
+	assert.ErrorIs(t, err, listener.ErrInvalidSpec)
+
+	cfg.Listen = "unix://" + filepath.Join(t.TempDir(), "polygo.sock")
+	cfg.SocketMode = "rw"
+	_, err = listener.Open(&cfg)
+	assert.ErrorIs(t, err, listener.ErrInvalidSpec)
+
+	t.Setenv("LISTEN_PID", "1")
+	t.Setenv("LISTEN_FDS", "1")
+	cfg.Listen = "systemd"
+	cfg.SocketMode = "0660"
+	_, err = listener.Open(&cfg)
+	assert.ErrorIs(t, err, listener.ErrNotActivated, "the sockets were passed to another process")
+}
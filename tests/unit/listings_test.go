@@ -0,0 +1,68 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/listings"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/webhooks"
+	"github.com/polygo/internal/wsframe"
+)
+
+func TestListings_PublishesMarketsAddedAfterFirstSync(t *testing.T) {
+	cat := catalog.New(nil, &config.CatalogConfig{})
+	feed := listings.New(nil)
+	cat.OnListed(feed.Publish)
+
+	allOut, all := feedClient(t)
+	cryptoOut, crypto := feedClient(t)
+	feed.Subscribe(allOut, wsframe.JSON, nil)
+	feed.Subscribe(cryptoOut, wsframe.JSON, []string{"Crypto"})
+
+	sports := models.Tag{Slug: "sports"}
+	cat.Load([]*models.Event{{ID: "e1", Tags: []models.Tag{sports}, Markets: []models.Market{{ID: "m1"}}}})
+	assertNoFrame(t, all, "the first sync only records existing markets")
+
+	cat.Load([]*models.Event{
+		{ID: "e1", Tags: []models.Tag{sports}, Markets: []models.Market{{ID: "m1"}, {ID: "m2"}}},
+		{ID: "e2", Tags: []models.Tag{{Slug: "crypto"}}, Markets: []models.Market{{ID: "m3", Question: "BTC?"}}},
+	})
+
+	var listing struct {
+		Type   string `json:"type"`
+		Market struct {
+			ID string `json:"id"`
+		} `json:"market"`
+	}
+	require.NoError(t, json.Unmarshal(nextFrame(t, all), &listing))
+	assert.Equal(t, "market_listed", listing.Type)
+	assert.Equal(t, "m2", listing.Market.ID)
+	require.NoError(t, json.Unmarshal(nextFrame(t, all), &listing))
+	assert.Equal(t, "m3", listing.Market.ID)
+	assertNoFrame(t, all)
+
+	require.NoError(t, json.Unmarshal(nextFrame(t, crypto), &listing))
+	assert.Equal(t, "m3", listing.Market.ID)
+	assertNoFrame(t, crypto)
+
+	feed.Unsubscribe(allOut)
+	feed.Unsubscribe(cryptoOut)
+}
+
+func TestWebhookSubscription_MatchesTags(t *testing.T) {
+	sub := &webhooks.Subscription{Events: []string{"*"}, Tags: []string{"crypto"}}
+	assert.True(t, sub.MatchesTags([]string{"politics", "crypto"}))
+	assert.False(t, sub.MatchesTags([]string{"sports"}))
+	assert.False(t, sub.MatchesTags([]string{}), "an untagged market does not match a tag filter")
+	assert.True(t, sub.MatchesTags(nil), "events published without tags are not tag filtered")
+
+	all := &webhooks.Subscription{Events: []string{"market.*"}}
+	assert.True(t, all.MatchesTags([]string{"sports"}))
+	assert.True(t, all.Matches(listings.EventMarketListed))
+}
@@ -0,0 +1,132 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/storage"
+	"github.com/polygo/internal/tape"
+)
+
+// IDs in testdata/clob_market, a tape of CLOB market channel frames: the
+// books sent on subscribe as one batch, a price change for both tokens of
+// a market, a trade, and a tick size change and book for another market
+const (
+	recordedMarket = "0x5f65177b394277fd294cd75650044e32ba009a95022d88a0c1d565897d72f8f1"
+	recordedYes    = "71321045679252212594626385532706912750332728571942532289631379312455583992563"
+	recordedNo     = "52114319501245915516055106046884209969926127482827954674443846427813813222426"
+	recordedOther  = "21742633143463906290569050155826241533067272736897614950488156847949938836455"
+)
+
+func recordedFrames(t *testing.T) *tape.Tape {
+	tp, err := tape.New(&config.TapeConfig{Mode: config.TapeModeReplay, Dir: "testdata/clob_market"}, storage.NewLocal())
+	require.NoError(t, err)
+	return tp
+}
+
+func TestParseMarketFrame_RecordedFrames(t *testing.T) {
+	frames, err := recordedFrames(t).Frames()
+	require.NoError(t, err)
+	require.Len(t, frames, 5)
+
+	type parsed struct {
+		eventType string
+		keys      []string
+	}
+	var got []parsed
+	for _, frame := range frames {
+		for _, m := range polymarket.ParseMarketFrame([]byte(frame.Data)) {
+			got = append(got, parsed{m.EventType, m.Keys()})
+		}
+	}
+
+	otherMarket := "0xbd31dc8a20211944f6b70f31557f1001557b59905b7738480ca09bd4532f84af"
+	assert.Equal(t, []parsed{
+		{"book", []string{recordedYes, recordedMarket}},
+		{"book", []string{recordedNo, recordedMarket}},
+		{"price_change", []string{recordedYes, recordedNo, recordedMarket}},
+		{"last_trade_price", []string{recordedYes, recordedMarket}},
+		{"tick_size_change", []string{recordedOther, otherMarket}},
+		{"book", []string{recordedOther, otherMarket}},
+	}, got)
+}
+
+func TestParseMarketFrame_SplitsBatchIntoMessages(t *testing.T) {
+	frames, err := recordedFrames(t).Frames()
+	require.NoError(t, err)
+
+	messages := polymarket.ParseMarketFrame([]byte(frames[0].Data))
+	require.Len(t, messages, 2)
+	for _, m := range messages {
+		var book struct {
+			AssetID string `json:"asset_id"`
+		}
+		require.NoError(t, sonic.Unmarshal(m.Data, &book))
+		assert.Equal(t, []string{book.AssetID}, m.AssetIDs, "each message carries only its own JSON")
+	}
+}
+
+func TestParseMarketFrame_OlderAndInvalidFrames(t *testing.T) {
+	// Frames PolyGo builds itself name markets directly
+	messages := polymarket.ParseMarketFrame([]byte(`{"type":"price_change","markets":["m1","m2"]}`))
+	require.Len(t, messages, 1)
+	assert.Equal(t, []string{"m1", "m2"}, messages[0].Keys())
+
+	// Upstream timestamps are strings of unix ms; numbers are accepted too
+	messages = polymarket.ParseMarketFrame([]byte(`[{"event_type":"book","asset_id":"a","timestamp":"1757908892351"},{"event_type":"book","asset_id":"b","timestamp":1757908892352}]`))
+	require.Len(t, messages, 2)
+	assert.Equal(t, int64(1757908892351), messages[0].Timestamp)
+	assert.Equal(t, int64(1757908892352), messages[1].Timestamp)
+
+	assert.Empty(t, polymarket.ParseMarketFrame([]byte(`not json`)))
+	assert.Empty(t, polymarket.ParseMarketFrame([]byte(`  `)))
+	assert.Len(t, polymarket.ParseMarketFrame([]byte(`[{"event_type":"book","asset_id":"a"}, 1]`)), 1)
+}
+
+func TestWSManager_RoutesRecordedFramesByAsset(t *testing.T) {
+	m := polymarket.NewWSManager(&config.PolymarketConfig{})
+	m.SetTape(recordedFrames(t))
+	defer m.Close()
+
+	subs := map[string]chan []byte{}
+	for _, key := range []string{recordedYes, recordedNo, recordedMarket, recordedOther, "unrelated"} {
+		ch, err := m.SubscribeMarket(key)
+		require.NoError(t, err)
+		subs[key] = ch
+	}
+	require.NoError(t, m.Connect())
+
+	received := func(key string, n int) []string {
+		var types []string
+		for len(types) < n {
+			select {
+			case data := <-subs[key]:
+				var msg struct {
+					EventType string `json:"event_type"`
+				}
+				require.NoError(t, sonic.Unmarshal(data, &msg))
+				types = append(types, msg.EventType)
+			case <-time.After(2 * time.Second):
+				t.Fatalf("%s got %v, want %d messages", key, types, n)
+			}
+		}
+		return types
+	}
+
+	assert.Equal(t, []string{"book", "price_change", "last_trade_price"}, received(recordedYes, 3))
+	assert.Equal(t, []string{"book", "price_change"}, received(recordedNo, 2))
+	assert.Equal(t, []string{"book", "book", "price_change", "last_trade_price"}, received(recordedMarket, 4))
+	assert.Equal(t, []string{"tick_size_change", "book"}, received(recordedOther, 2))
+
+	// Nothing further, and nothing for markets absent from the recording
+	time.Sleep(50 * time.Millisecond)
+	for key, ch := range subs {
+		assert.Empty(t, ch, key)
+	}
+}
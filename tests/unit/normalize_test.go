@@ -0,0 +1,37 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/normalize"
+)
+
+func TestNormalize_Market(t *testing.T) {
+	in := `{"id":"1","outcomes":"[\"Yes\",\"No\"]","outcomePrices":"[\"0.52\",\"0.48\"]",` +
+		`"clobTokenIds":"[\"111\",\"222\"]","volume":"1234.5","liquidityNum":99.5,"end_date_iso":"2025-01-01","question":"Q?"}`
+
+	out, err := normalize.JSON([]byte(in))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"1","outcomes":["Yes","No"],"outcomePrices":[0.52,0.48],`+
+		`"clobTokenIds":["111","222"],"volume":1234.5,"liquidityNum":99.5,"endDateIso":"2025-01-01","question":"Q?"}`, string(out))
+}
+
+func TestNormalize_EventWithNestedMarkets(t *testing.T) {
+	in := `[{"id":"e1","volume":"10","markets":[{"id":"m1","outcomePrices":"[\"1\",\"0\"]"}]}]`
+
+	out, err := normalize.JSON([]byte(in))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"id":"e1","volume":10,"markets":[{"id":"m1","outcomePrices":[1,0]}]}]`, string(out))
+}
+
+func TestNormalize_LeavesUnparseableValues(t *testing.T) {
+	out, err := normalize.JSON([]byte(`{"outcomes":"Yes/No","volume":"n/a","id":"0x1"}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"outcomes":"Yes/No","volume":"n/a","id":"0x1"}`, string(out))
+
+	_, err = normalize.JSON([]byte(`{`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,71 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polygo/internal/odds"
+)
+
+func TestOdds_Quote(t *testing.T) {
+	cases := []struct {
+		p      float64
+		format odds.Format
+		want   string
+	}{
+		{0.57, odds.Probability, "0.57"},
+		{0.57, odds.Cents, "57"},
+		{0.125, odds.Cents, "12.5"},
+		{0.6, odds.American, "-150"},
+		{0.5, odds.American, "-100"},
+		{0.25, odds.American, "+300"},
+	}
+	for _, tc := range cases {
+		got, ok := odds.Quote(tc.p, tc.format)
+		assert.True(t, ok)
+		assert.Equal(t, tc.want, got, "%v as %s", tc.p, tc.format)
+	}
+
+	_, ok := odds.Quote(1, odds.American)
+	assert.False(t, ok, "certainty has no american odds")
+	_, ok = odds.Quote(0, odds.American)
+	assert.False(t, ok)
+}
+
+func TestOdds_ParseFormat(t *testing.T) {
+	f, ok := odds.ParseFormat("")
+	assert.True(t, ok)
+	assert.Equal(t, odds.Probability, f)
+
+	f, ok = odds.ParseFormat("American")
+	assert.True(t, ok)
+	assert.Equal(t, odds.American, f)
+
+	_, ok = odds.ParseFormat("decimal")
+	assert.False(t, ok)
+}
+
+func TestOdds_RoundToTick(t *testing.T) {
+	assert.Equal(t, 0.57, odds.RoundToTick(0.5712, 0.01))
+	assert.Equal(t, 0.571, odds.RoundToTick(0.5712, 0.001))
+	assert.Equal(t, 0.5, odds.RoundToTick(0.5, 0))
+	assert.Equal(t, 1.0, odds.RoundToTick(0.999, 0.01))
+}
+
+func TestOdds_Implied(t *testing.T) {
+	p, ok := odds.Implied(0.49, 0.51, 0.3)
+	assert.True(t, ok)
+	assert.Equal(t, 0.5, p, "tight books quote the midpoint")
+
+	p, ok = odds.Implied(0.2, 0.6, 0.3)
+	assert.True(t, ok)
+	assert.Equal(t, 0.3, p, "wide books quote the last trade")
+
+	p, ok = odds.Implied(0.2, 0.6, 0)
+	assert.True(t, ok)
+	assert.Equal(t, 0.4, p)
+
+	_, ok = odds.Implied(0, 0, 0)
+	assert.False(t, ok)
+}
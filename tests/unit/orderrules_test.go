@@ -0,0 +1,85 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/orderrules"
+	"github.com/polygo/internal/polymarket"
+)
+
+func newRulesChecker(t *testing.T) (*orderrules.Checker, *mockupstream.Server) {
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	client := polymarket.NewClient(&cfg.Polymarket, c)
+	gamma := polymarket.NewGammaClient(client)
+	resolver := catalog.NewResolver(catalog.New(gamma, &cfg.Catalog), gamma)
+	return orderrules.New(polymarket.NewClobClient(client), resolver, &cfg.OrderRules), mock
+}
+
+func rulesError(t *testing.T, err error) *orderrules.Error {
+	t.Helper()
+	var rejected *orderrules.Error
+	require.True(t, errors.As(err, &rejected), "expected a rule rejection, got %v", err)
+	return rejected
+}
+
+func TestOrderRules_TickSizeAndPriceBounds(t *testing.T) {
+	checker, _ := newRulesChecker(t)
+	order := func(price, size string) models.CreateOrderRequest {
+		return models.CreateOrderRequest{TokenID: mockupstream.TokenYes, Side: models.SideBuy, Price: price, Size: size}
+	}
+
+	require.NoError(t, checker.Check([]models.CreateOrderRequest{order("0.57", "10"), order("0.01", "1.5"), order("0.99", "3")}))
+
+	rejected := rulesError(t, checker.Check([]models.CreateOrderRequest{order("0.5", "10"), order("0.123", "10")}))
+	assert.Equal(t, orderrules.CodeTickSize, rejected.Code)
+	assert.Equal(t, 1, rejected.Order)
+	assert.Equal(t, "price 0.123 is not a multiple of tick 0.01; use 0.12 or 0.13", rejected.Message)
+
+	rejected = rulesError(t, checker.Check([]models.CreateOrderRequest{order("0.995", "10")}))
+	assert.Equal(t, orderrules.CodePriceRange, rejected.Code)
+	assert.Equal(t, "price 0.995 is outside 0.01-0.99 for tick 0.01", rejected.Message)
+
+	rejected = rulesError(t, checker.Check([]models.CreateOrderRequest{order("0", "10")}))
+	assert.Equal(t, orderrules.CodePriceRange, rejected.Code)
+
+	rejected = rulesError(t, checker.Check([]models.CreateOrderRequest{order("0.5", "10.125")}))
+	assert.Equal(t, orderrules.CodeSizePrecision, rejected.Code)
+	assert.Equal(t, "size 10.125 has more than 2 decimals; use 10.12", rejected.Message)
+}
+
+func TestOrderRules_MarketMinimumAndFallbackTick(t *testing.T) {
+	checker, mock := newRulesChecker(t)
+	mock.On(mockupstream.Gamma, "GET", "/markets", 200,
+		`[{"id":"9","conditionId":"0xc9","clobTokenIds":"[\"tok-a\",\"tok-b\"]","orderMinSize":5,"orderPriceMinTickSize":0.001}]`)
+	mock.On(mockupstream.CLOB, "GET", "/tick-size", 500, `{"error":"unavailable"}`)
+	order := func(price, size string) []models.CreateOrderRequest {
+		return []models.CreateOrderRequest{{TokenID: "tok-a", Side: models.SideSell, Price: price, Size: size}}
+	}
+
+	assert.Equal(t, orderrules.Rules{TickSize: 0.001, MinSize: 5}, checker.Rules("tok-a"))
+	require.NoError(t, checker.Check(order("0.123", "5")))
+
+	rejected := rulesError(t, checker.Check(order("0.123", "4")))
+	assert.Equal(t, orderrules.CodeMinSize, rejected.Code)
+	assert.Equal(t, "size 4 is below the market minimum of 5 shares", rejected.Message)
+
+	rejected = rulesError(t, checker.Check(order("0.1234", "5")))
+	assert.Equal(t, orderrules.CodeTickSize, rejected.Code)
+}
@@ -0,0 +1,105 @@
+package unit
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/polymarket"
+)
+
+func TestPaginate_FollowsCursorUntilEnd(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"data":[{"id":1},{"id":2}],"next_cursor":"c1"}`,
+		"c1": `{"data":[{"id":3},{"id":4}],"next_cursor":"LTE="}`,
+	}
+
+	result, err := polymarket.Paginate(func(cursor string, offset int) ([]byte, error) {
+		return []byte(pages[cursor]), nil
+	}, 2, 0)
+	require.NoError(t, err)
+
+	assert.Len(t, result.Items, 4)
+	assert.Equal(t, 2, result.Pages)
+	assert.False(t, result.CursorRefreshed)
+}
+
+func TestPaginate_OffsetListingStopsOnShortPage(t *testing.T) {
+	result, err := polymarket.Paginate(func(cursor string, offset int) ([]byte, error) {
+		if offset >= 4 {
+			return []byte(`[{"id":5}]`), nil
+		}
+		return []byte(fmt.Sprintf(`[{"id":%d},{"id":%d}]`, offset+1, offset+2)), nil
+	}, 2, 0)
+	require.NoError(t, err)
+
+	assert.Len(t, result.Items, 5)
+	assert.Equal(t, 3, result.Pages)
+}
+
+func TestPaginate_RestartsFromOffsetOnCursorExpiry(t *testing.T) {
+	expired := false
+	var offsets []int
+
+	result, err := polymarket.Paginate(func(cursor string, offset int) ([]byte, error) {
+		if cursor == "c1" && !expired {
+			expired = true
+			return nil, &polymarket.StatusError{StatusCode: 400, Body: []byte(`{"error":"cursor expired"}`)}
+		}
+		if cursor == "" {
+			offsets = append(offsets, offset)
+		}
+		switch {
+		case cursor == "" && offset == 0:
+			return []byte(`{"data":[{"id":1},{"id":2}],"next_cursor":"c1"}`), nil
+		case cursor == "" && offset == 2:
+			return []byte(`{"data":[{"id":3}],"next_cursor":"LTE="}`), nil
+		}
+		return nil, fmt.Errorf("unexpected page cursor=%q offset=%d", cursor, offset)
+	}, 2, 0)
+	require.NoError(t, err)
+
+	assert.Len(t, result.Items, 3)
+	assert.True(t, result.CursorRefreshed)
+	assert.Equal(t, []int{0, 2}, offsets)
+}
+
+func TestPaginate_RespectsMaxItems(t *testing.T) {
+	result, err := polymarket.Paginate(func(cursor string, offset int) ([]byte, error) {
+		return []byte(`[{"id":1},{"id":2},{"id":3}]`), nil
+	}, 3, 5)
+	require.NoError(t, err)
+
+	assert.Len(t, result.Items, 5)
+}
+
+func TestIsCursorExpired(t *testing.T) {
+	assert.True(t, polymarket.IsCursorExpired(polymarket.ErrCursorExpired))
+	assert.True(t, polymarket.IsCursorExpired(&polymarket.StatusError{StatusCode: 410, Body: []byte("Invalid cursor")}))
+	assert.False(t, polymarket.IsCursorExpired(&polymarket.StatusError{StatusCode: 404, Body: []byte("cursor expired")}))
+	assert.False(t, polymarket.IsCursorExpired(&polymarket.StatusError{StatusCode: 400, Body: []byte("bad token id")}))
+	assert.False(t, polymarket.IsCursorExpired(fmt.Errorf("timeout")))
+}
+
+func TestNextCursor(t *testing.T) {
+	assert.Equal(t, "c1", polymarket.NextCursor([]byte(`{"data":[{"id":1}],"next_cursor":"c1"}`), 0, 10))
+	assert.Empty(t, polymarket.NextCursor([]byte(`{"data":[{"id":1}],"next_cursor":"LTE="}`), 0, 1))
+
+	// Bare arrays from offset APIs: a full page points at the next offset
+	assert.Equal(t, "22", polymarket.NextCursor([]byte(`[{"id":1},{"id":2}]`), 20, 2))
+	assert.Empty(t, polymarket.NextCursor([]byte(`[{"id":1}]`), 20, 2))
+	assert.Empty(t, polymarket.NextCursor([]byte(`[{"id":1}]`), 0, 0))
+}
+
+func TestSetCursor(t *testing.T) {
+	q := url.Values{}
+	polymarket.SetCursor(q, "40")
+	assert.Equal(t, "offset=40", q.Encode())
+
+	q = url.Values{}
+	polymarket.SetCursor(q, "MTAw")
+	assert.Equal(t, "next_cursor=MTAw", q.Encode())
+}
@@ -0,0 +1,132 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/pairs"
+	"github.com/polygo/internal/polymarket"
+)
+
+func newPairManager(t *testing.T) (*pairs.Manager, *mockupstream.Server) {
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	return pairs.New(polymarket.NewClobClient(polymarket.NewClient(&cfg.Polymarket, c))), mock
+}
+
+var straddle = [2]models.CreateOrderRequest{
+	{TokenID: mockupstream.TokenYes, Side: models.SideBuy, Price: "0.40", Size: "10", Type: models.OrderTypeGTC},
+	{TokenID: mockupstream.TokenNo, Side: models.SideBuy, Price: "0.55", Size: "10", Type: models.OrderTypeGTC},
+}
+
+func deleted(mock *mockupstream.Server) []string {
+	var paths []string
+	for _, r := range mock.Requests(mockupstream.CLOB) {
+		if r.Method == "DELETE" {
+			paths = append(paths, r.Path)
+		}
+	}
+	return paths
+}
+
+func TestPairs_PlacesBothLegsInOneBatch(t *testing.T) {
+	manager, mock := newPairManager(t)
+	mock.On(mockupstream.CLOB, "POST", "/orders", 200,
+		`[{"success":true,"orderID":"0xyes","status":"live"},{"success":true,"orderID":"0xno","status":"matched"}]`)
+
+	pair, err := manager.Place("owner", straddle, nil)
+	require.NoError(t, err)
+	assert.Equal(t, pairs.StatusOpen, pair.Status)
+	assert.Equal(t, "0xyes", pair.Legs[0].OrderID)
+	assert.Equal(t, "matched", pair.Legs[1].Status)
+	require.Len(t, mock.Requests(mockupstream.CLOB), 1, "one upstream request for both legs")
+
+	mock.On(mockupstream.CLOB, "GET", "/order/0xyes", 200, `{"id":"0xyes","status":"MATCHED","size_matched":"10"}`)
+	pair, err = manager.Refresh("owner", pair.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, pairs.StatusFilled, pair.Status)
+	assert.Equal(t, "10", pair.Legs[0].SizeMatched)
+
+	_, err = manager.Get("someone-else", pair.ID)
+	assert.ErrorIs(t, err, pairs.ErrNotFound)
+	assert.Len(t, manager.List("owner"), 1)
+	assert.Empty(t, manager.List("someone-else"))
+}
+
+func TestPairs_RollsBackWhenOneLegIsRejected(t *testing.T) {
+	manager, mock := newPairManager(t)
+	mock.On(mockupstream.CLOB, "POST", "/orders", 200,
+		`[{"success":true,"orderID":"0xyes","status":"live"},{"success":false,"errorMsg":"not enough balance"}]`)
+
+	pair, err := manager.Place("owner", straddle, nil)
+	require.NoError(t, err)
+	assert.Equal(t, pairs.StatusRolledBack, pair.Status)
+	assert.Equal(t, pairs.LegCancelled, pair.Legs[0].Status)
+	assert.Equal(t, pairs.LegRejected, pair.Legs[1].Status)
+	assert.Equal(t, "not enough balance", pair.Legs[1].Error)
+	assert.Equal(t, []string{"/order/0xyes"}, deleted(mock))
+}
+
+func TestPairs_ReportsLegsThatCannotBeRolledBack(t *testing.T) {
+	manager, mock := newPairManager(t)
+	mock.On(mockupstream.CLOB, "POST", "/orders", 200,
+		`[{"success":false,"errorMsg":"invalid price"},{"success":true,"orderID":"0xno","status":"matched"}]`)
+
+	pair, err := manager.Place("owner", straddle, nil)
+	require.NoError(t, err)
+	assert.Equal(t, pairs.StatusBroken, pair.Status)
+	assert.Contains(t, pair.Note, "legs[1] filled")
+	assert.Empty(t, deleted(mock), "a filled leg is not cancelled")
+
+	mock.On(mockupstream.CLOB, "POST", "/orders", 200,
+		`[{"success":true,"orderID":"0xyes","status":"live"},{"success":false,"errorMsg":"invalid price"}]`)
+	mock.On(mockupstream.CLOB, "DELETE", "/order/0xyes", 200, `{"canceled":[],"not_canceled":{"0xyes":"order already matched"}}`)
+	pair, err = manager.Place("owner", straddle, nil)
+	require.NoError(t, err)
+	assert.Equal(t, pairs.StatusBroken, pair.Status)
+	assert.Contains(t, pair.Note, "order already matched")
+
+	mock.On(mockupstream.CLOB, "POST", "/orders", 200,
+		`[{"success":false,"errorMsg":"invalid price"},{"success":false,"errorMsg":"invalid price"}]`)
+	pair, err = manager.Place("owner", straddle, nil)
+	require.NoError(t, err)
+	assert.Equal(t, pairs.StatusRejected, pair.Status)
+
+	mock.On(mockupstream.CLOB, "POST", "/orders", 500, `{}`)
+	_, err = manager.Place("owner", straddle, nil)
+	assert.Error(t, err)
+	assert.Len(t, manager.List("owner"), 3, "failed requests record no pair")
+}
+
+func TestPairs_CancelsOpenLegs(t *testing.T) {
+	manager, mock := newPairManager(t)
+	mock.On(mockupstream.CLOB, "POST", "/orders", 200,
+		`[{"success":true,"orderID":"0xyes","status":"live"},{"success":true,"orderID":"0xno","status":"matched"}]`)
+
+	pair, err := manager.Place("owner", straddle, nil)
+	require.NoError(t, err)
+
+	pair, err = manager.Cancel("owner", pair.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, pairs.StatusCancelled, pair.Status)
+	assert.Equal(t, pairs.LegCancelled, pair.Legs[0].Status)
+	assert.Equal(t, "matched", pair.Legs[1].Status)
+	assert.Equal(t, []string{"/order/0xyes"}, deleted(mock))
+
+	stored, err := manager.Get("owner", pair.ID)
+	require.NoError(t, err)
+	assert.Equal(t, pairs.StatusCancelled, stored.Status)
+}
@@ -0,0 +1,63 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+)
+
+func TestCalculatePayout_BuyPaysFeeInShares(t *testing.T) {
+	q, err := polymarket.CalculatePayout(
+		polymarket.PayoutOrder{Side: models.SideBuy, Price: 0.4, Size: 100},
+		polymarket.MarketTerms{TickSize: 0.01, FeeRateBps: 200},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, 40.0, q.NetCost)
+	assert.Equal(t, 0.8, q.Fee)
+	assert.Equal(t, 2.0, q.FeeShares)
+	assert.Equal(t, 98.0, q.Shares)
+	assert.Equal(t, 98.0, q.MaxPayout)
+	assert.Equal(t, 58.0, q.MaxProfit)
+	assert.Equal(t, 40.0, q.MaxLoss)
+	assert.Equal(t, 0.408163, q.Breakeven)
+}
+
+func TestCalculatePayout_SellPaysFeeFromProceeds(t *testing.T) {
+	q, err := polymarket.CalculatePayout(
+		polymarket.PayoutOrder{Side: models.SideSell, Price: 0.7, Size: 100},
+		polymarket.MarketTerms{TickSize: 0.01, FeeRateBps: 200},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, 0.6, q.Fee, "fee is charged on min(price, 1-price)")
+	assert.Equal(t, 69.4, q.NetProceeds)
+	assert.Equal(t, 30.6, q.MaxLoss)
+	assert.Equal(t, 0.694, q.Breakeven)
+}
+
+func TestCalculatePayout_MakersPayNoFee(t *testing.T) {
+	q, err := polymarket.CalculatePayout(
+		polymarket.PayoutOrder{Side: models.SideBuy, Price: 0.25, Size: 10, Maker: true},
+		polymarket.MarketTerms{TickSize: 0.01, FeeRateBps: 200},
+	)
+	require.NoError(t, err)
+	assert.Zero(t, q.Fee)
+	assert.Equal(t, 10.0, q.Shares)
+	assert.Equal(t, 0.25, q.Breakeven)
+}
+
+func TestCalculatePayout_RejectsOffTickPrices(t *testing.T) {
+	terms := polymarket.MarketTerms{TickSize: 0.01}
+	for _, price := range []float64{0.555, 0.001, 0.995} {
+		_, err := polymarket.CalculatePayout(polymarket.PayoutOrder{Side: models.SideBuy, Price: price, Size: 1}, terms)
+		assert.ErrorIs(t, err, polymarket.ErrPriceOffTick, "%v", price)
+	}
+
+	_, err := polymarket.CalculatePayout(polymarket.PayoutOrder{Side: models.SideBuy, Price: 0.555, Size: 1}, polymarket.MarketTerms{TickSize: 0.001})
+	assert.NoError(t, err)
+}
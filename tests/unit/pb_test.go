@@ -0,0 +1,87 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/polygo/internal/pb"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/shape"
+)
+
+func TestPB_PriceWireFormat(t *testing.T) {
+	half := 0.5
+	data, err := proto.Marshal(&pb.Price{TokenId: "t", Side: "BUY", Price: &half})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{
+		0x0a, 0x01, 't', // field 1, length 1
+		0x12, 0x03, 'B', 'U', 'Y', // field 2, length 3
+		0x19, 0, 0, 0, 0, 0, 0, 0xe0, 0x3f, // field 3, fixed64 0.5
+	}, data)
+
+	// Optional fields are sent when set, even at zero; other zeros are left out
+	zero := 0.0
+	data, err = proto.Marshal(&pb.Price{Price: &zero})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x19, 0, 0, 0, 0, 0, 0, 0, 0}, data)
+	data, err = proto.Marshal(&pb.Price{})
+	require.NoError(t, err)
+	assert.Empty(t, data)
+}
+
+func TestPB_BookRoundTrip(t *testing.T) {
+	v, err := shape.Book([]byte(`{"market":"0xc","asset_id":"t1","timestamp":"1700000000000","hash":"h",
+		"bids":[{"price":"0.48","size":"200"},{"price":"0.49","size":"100"}],"asks":[{"price":"0.51","size":"100"}]}`))
+	require.NoError(t, err)
+	msg, ok := pb.From(v)
+	require.True(t, ok)
+
+	data, err := proto.Marshal(msg)
+	require.NoError(t, err)
+	var book pb.OrderBook
+	require.NoError(t, proto.Unmarshal(data, &book))
+	assert.Equal(t, "t1", book.TokenId)
+	assert.Equal(t, "0xc", book.ConditionId)
+	require.Len(t, book.Bids, 2)
+	assert.True(t, proto.Equal(&pb.Level{Price: 0.49, Size: 100}, book.Bids[0]))
+	assert.True(t, proto.Equal(&pb.Level{Price: 0.48, Size: 200}, book.Bids[1]))
+	require.Len(t, book.Asks, 1)
+	assert.True(t, proto.Equal(&pb.Level{Price: 0.51, Size: 100}, book.Asks[0]))
+	assert.Nil(t, book.LastTradePrice)
+	assert.Equal(t, "h", book.Hash)
+	assert.Equal(t, int64(1700000000000), book.TimestampMs)
+
+	_, ok = pb.From(shape.Midpoint{})
+	assert.False(t, ok, "only the high-frequency responses have messages")
+}
+
+func TestPB_SnapshotsRoundTrip(t *testing.T) {
+	mid := 0.55
+	list := pb.Snapshots([]polymarket.TokenSnapshot{
+		{TokenID: "t1", Mid: &mid, BidDepth: 120},
+		{TokenID: "t2", Error: "no orderbook"},
+	})
+
+	data, err := proto.Marshal(list)
+	require.NoError(t, err)
+	var out pb.SnapshotList
+	require.NoError(t, proto.Unmarshal(data, &out))
+	require.Len(t, out.Tokens, 2)
+	assert.Equal(t, 0.55, *out.Tokens[0].Mid)
+	assert.Nil(t, out.Tokens[0].Bid)
+	assert.Equal(t, 120.0, out.Tokens[0].BidDepth)
+	assert.Equal(t, "no orderbook", out.Tokens[1].Error)
+}
+
+func TestPB_DecodeSkipsUnknownAndRejectsMalformed(t *testing.T) {
+	// field 1 "t", then an unknown varint field 15
+	var p pb.Price
+	require.NoError(t, proto.Unmarshal([]byte{0x0a, 0x01, 't', 0x78, 0x05}, &p))
+	assert.Equal(t, "t", p.TokenId)
+
+	assert.Error(t, proto.Unmarshal([]byte{0x0a, 0x05, 't'}, &p), "truncated string")
+	assert.Error(t, proto.Unmarshal([]byte{0x0f}, &p), "wire type 7")
+}
@@ -0,0 +1,234 @@
+package unit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/pkg/polygoclient"
+)
+
+func TestPolygoClient_DecodesRawAndEnvelope(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/book/tok":
+			w.Write([]byte(`{"token_id":"tok","bids":[{"price":"0.4","size":"10"}],"asks":[]}`))
+		case "/api/v1/markets":
+			assert.Equal(t, "5", r.URL.Query().Get("limit"))
+			w.Write([]byte(`{"success":true,"data":[{"id":"m1"},{"id":"m2"}],"timestamp":1700000000000}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"success":false,"error":{"code":"NOT_FOUND","message":"Market not found"},"timestamp":1700000000000}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := polygoclient.New(srv.URL)
+	ctx := context.Background()
+
+	book, err := c.GetOrderBook(ctx, "tok")
+	require.NoError(t, err)
+	assert.Equal(t, "tok", book.TokenID)
+	require.Len(t, book.Bids, 1)
+	assert.Equal(t, "0.4", book.Bids[0].Price)
+
+	markets, err := c.GetMarkets(ctx, &polygoclient.MarketsQuery{Limit: 5})
+	require.NoError(t, err)
+	assert.Len(t, markets, 2)
+
+	_, err = c.GetMarket(ctx, "missing")
+	var apiErr *polygoclient.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "NOT_FOUND", apiErr.Code)
+}
+
+func TestPolygoClient_RetriesReadsButNotOrders(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"mid":"0.55"}`))
+	}))
+	defer srv.Close()
+
+	c := polygoclient.New(srv.URL,
+		polygoclient.WithRetries(3, time.Millisecond),
+		polygoclient.WithCredentials(polygoclient.Credentials{APIKey: "k", Secret: "c2VjcmV0", Passphrase: "p"}))
+
+	mid, err := c.GetMidpoint(context.Background(), "tok")
+	require.NoError(t, err)
+	assert.Equal(t, 0.55, mid)
+	assert.Equal(t, int32(3), hits.Load())
+
+	hits.Store(0)
+	_, err = c.PlaceOrder(context.Background(), &polygoclient.CreateOrderRequest{TokenID: "tok", Side: polygoclient.Buy, Price: "0.5", Size: "1"})
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), hits.Load())
+}
+
+func TestPolygoClient_FollowsRetryHints(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		switch r.URL.Path {
+		case "/api/v1/midpoint/tok":
+			if hits.Load() == 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(`{"success":false,"error":{"code":"UPSTREAM_RATE_LIMITED","message":"slow down","retryable":true,"retry_after_ms":20},"timestamp":1700000000000}`))
+				return
+			}
+			w.Write([]byte(`{"mid":"0.55"}`))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"success":false,"error":{"code":"WATCHLIST_FULL","message":"Watchlist is full","retryable":false},"timestamp":1700000000000}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := polygoclient.New(srv.URL, polygoclient.WithRetries(3, time.Hour))
+
+	mid, err := c.GetMidpoint(context.Background(), "tok")
+	require.NoError(t, err, "retry_after_ms is waited instead of the backoff")
+	assert.Equal(t, 0.55, mid)
+	assert.Equal(t, int32(2), hits.Load())
+
+	hits.Store(0)
+	_, err = c.GetMarket(context.Background(), "m1")
+	var apiErr *polygoclient.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.False(t, apiErr.Retryable)
+	assert.Equal(t, int32(1), hits.Load(), "retryable false overrides the 5xx status")
+}
+
+func TestPolygoClient_SignsOrderRequests(t *testing.T) {
+	creds := polygoclient.Credentials{APIKey: "k", Secret: "c2VjcmV0", Passphrase: "p"}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts := r.Header.Get(polygoclient.HeaderTimestamp)
+		assert.Equal(t, "k", r.Header.Get(polygoclient.HeaderAPIKey))
+		assert.Empty(t, r.Header.Get(polygoclient.HeaderAPISecret))
+		assert.Equal(t, creds.Sign(ts, "POST", "/order", body), r.Header.Get(polygoclient.HeaderSignature))
+		w.Write([]byte(`{"success":true,"orderID":"o1","status":"live"}`))
+	}))
+	defer srv.Close()
+
+	c := polygoclient.New(srv.URL, polygoclient.WithCredentials(creds))
+	resp, err := c.PlaceOrder(context.Background(), &polygoclient.CreateOrderRequest{TokenID: "tok", Side: polygoclient.Buy, Price: "0.5", Size: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, "o1", resp.OrderID)
+
+	_, err = polygoclient.New(srv.URL).PlaceOrder(context.Background(), &polygoclient.CreateOrderRequest{})
+	assert.Error(t, err)
+}
+
+func TestPolygoClient_SubscribeMarket(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/ws/market/m1", r.URL.Path)
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.WriteMessage(websocket.TextMessage, []byte(`[{"event_type":"book","asset_id":"a1","market":"m1","bids":[{"price":"0.5","size":"3"}],"timestamp":"1700000000000"}]`))
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"event_type":"price_change","asset_id":"a1","price":"0.51","side":"BUY"}`))
+		conn.ReadMessage()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := polygoclient.New(srv.URL).SubscribeMarket(ctx, "m1")
+	require.NoError(t, err)
+
+	ev := <-events
+	assert.Equal(t, "book", ev.EventType)
+	require.Len(t, ev.Bids, 1)
+	assert.Equal(t, "1700000000000", ev.Timestamp.String())
+
+	ev = <-events
+	assert.Equal(t, "price_change", ev.EventType)
+	assert.Equal(t, polygoclient.Buy, ev.Side)
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok)
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream not closed after cancel")
+	}
+}
+
+func TestPolygoClient_SubscribeToken(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/ws/token/a1", r.URL.Path)
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"event_type":"last_trade_price","asset_id":"a1","market":"m1","price":"0.52","side":"SELL","size":"4"}`))
+		conn.ReadMessage()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := polygoclient.New(srv.URL).SubscribeToken(ctx, "a1")
+	require.NoError(t, err)
+
+	ev := <-events
+	assert.Equal(t, "last_trade_price", ev.EventType)
+	assert.Equal(t, "a1", ev.AssetID)
+	assert.Equal(t, polygoclient.Sell, ev.Side)
+}
+
+func TestPolygoClient_SubscribeResumesFromStreamSeq(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	var mu sync.Mutex
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		first := len(queries) == 1
+		mu.Unlock()
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if first {
+			// Drop the connection after one event
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"stream_seq":7,"event_type":"last_trade_price","asset_id":"a1","price":"0.5"}`))
+			return
+		}
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"stream_seq":9,"event_type":"last_trade_price","asset_id":"a1","price":"0.6"}`))
+		conn.ReadMessage()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := polygoclient.New(srv.URL, polygoclient.WithRetries(1, 10*time.Millisecond)).SubscribeMarket(ctx, "m1")
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(7), (<-events).StreamSeq)
+	assert.Equal(t, uint64(9), (<-events).StreamSeq)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"", "since=7"}, queries)
+}
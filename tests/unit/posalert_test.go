@@ -0,0 +1,158 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/posalert"
+	"github.com/polygo/internal/webhooks"
+	"github.com/polygo/pkg/validate"
+)
+
+func newTestPositionAlerts(t *testing.T, positions *atomic.Value, cfg *config.PositionAlertsConfig) (*posalert.Alerts, *webhooks.Dispatcher) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(positions.Load().(string)))
+	}))
+	t.Cleanup(srv.Close)
+
+	full := config.DefaultConfig()
+	full.Polymarket.DataBaseURL = srv.URL
+	full.Cache.UserDataTTL = 0
+	c, err := cache.New(&full.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	data := polymarket.NewDataClient(polymarket.NewClient(&full.Polymarket, c))
+	dispatcher, err := webhooks.NewDispatcher(&full.Webhooks)
+	require.NoError(t, err)
+	return posalert.New(data, dispatcher, cfg), dispatcher
+}
+
+func drainChanges(events <-chan *webhooks.Event) map[string]posalert.Change {
+	out := make(map[string]posalert.Change)
+	for {
+		select {
+		case e := <-events:
+			c := e.Data.(posalert.Change)
+			out[e.Type+" "+c.Asset] = c
+		case <-time.After(50 * time.Millisecond):
+			return out
+		}
+	}
+}
+
+func TestPositionAlerts_ReportsChangesOverThresholds(t *testing.T) {
+	var positions atomic.Value
+	positions.Store(`[{"asset":"a","conditionId":"m1","size":100,"curPrice":0.5},{"asset":"b","conditionId":"m2","size":40,"curPrice":0.2}]`)
+	alerts, dispatcher := newTestPositionAlerts(t, &positions, &config.PositionAlertsConfig{MaxWatches: 5, PositionsLimit: 10})
+	events, stop := dispatcher.Listen("owner")
+	defer stop()
+
+	watch, err := alerts.Create(whale, posalert.Thresholds{Label: "whale", MinSize: 10, MinValue: 5}, "owner")
+	require.NoError(t, err)
+
+	// The first poll only records existing positions
+	alerts.Poll()
+	assert.Empty(t, drainChanges(events))
+
+	// a grows by 5 shares, below min_size; c opens with 8 shares at 0.9, below min_size
+	positions.Store(`[{"asset":"a","conditionId":"m1","size":105,"curPrice":0.5},{"asset":"b","conditionId":"m2","size":40,"curPrice":0.2},` +
+		`{"asset":"c","conditionId":"m3","size":8,"curPrice":0.9}]`)
+	alerts.Poll()
+	assert.Empty(t, drainChanges(events))
+
+	// a has now grown by 12 since last reported; c by 20 shares but only 2 USDC; b is gone
+	positions.Store(`[{"asset":"a","conditionId":"m1","title":"Rain?","outcome":"Yes","size":112,"curPrice":0.5,"avgPrice":0.45},` +
+		`{"asset":"c","conditionId":"m3","size":20,"curPrice":0.1}]`)
+	alerts.Poll()
+	changes := drainChanges(events)
+	require.Len(t, changes, 2)
+	assert.Equal(t, posalert.Change{
+		Type:         "position_increased",
+		WatchID:      watch.ID,
+		Address:      whale,
+		Label:        "whale",
+		Asset:        "a",
+		ConditionID:  "m1",
+		Title:        "Rain?",
+		Outcome:      "Yes",
+		PreviousSize: 100,
+		Size:         112,
+		Change:       12,
+		Price:        0.5,
+		Value:        6,
+		AvgPrice:     0.45,
+	}, changes[posalert.EventIncreased+" a"])
+	closed := changes[posalert.EventClosed+" b"]
+	assert.Equal(t, 40.0, closed.PreviousSize)
+	assert.Zero(t, closed.Size)
+	assert.Equal(t, -40.0, closed.Change)
+
+	// Lowered thresholds let c through as opened, and a's drop as decreased
+	_, err = alerts.Update(watch.ID, posalert.Thresholds{Label: "whale"}, "owner")
+	require.NoError(t, err)
+	positions.Store(`[{"asset":"a","conditionId":"m1","size":50,"curPrice":0.5},{"asset":"c","conditionId":"m3","size":20,"curPrice":0.1}]`)
+	alerts.Poll()
+	changes = drainChanges(events)
+	require.Len(t, changes, 2)
+	assert.Equal(t, 20.0, changes[posalert.EventOpened+" c"].Size)
+	assert.Equal(t, -62.0, changes[posalert.EventDecreased+" a"].Change)
+
+	// A full page may leave positions out, so missing ones are not closed
+	positions.Store(`[{"asset":"x1","size":1,"curPrice":0.5},{"asset":"x2","size":1,"curPrice":0.5},{"asset":"x3","size":1,"curPrice":0.5},` +
+		`{"asset":"x4","size":1,"curPrice":0.5},{"asset":"x5","size":1,"curPrice":0.5},{"asset":"x6","size":1,"curPrice":0.5},` +
+		`{"asset":"x7","size":1,"curPrice":0.5},{"asset":"x8","size":1,"curPrice":0.5},{"asset":"x9","size":1,"curPrice":0.5},{"asset":"x10","size":1,"curPrice":0.5}]`)
+	alerts.Poll()
+	changes = drainChanges(events)
+	assert.Len(t, changes, 10)
+	for key := range changes {
+		assert.Contains(t, key, posalert.EventOpened)
+	}
+}
+
+func TestPositionAlerts_ManagesWatchesPerOwner(t *testing.T) {
+	var positions atomic.Value
+	positions.Store(`[]`)
+	path := filepath.Join(t.TempDir(), "position_alerts.json")
+	cfg := &config.PositionAlertsConfig{MaxWatches: 2, PositionsLimit: 10, Path: path}
+	alerts, _ := newTestPositionAlerts(t, &positions, cfg)
+
+	_, err := alerts.Create("not-an-address", posalert.Thresholds{}, "owner")
+	assert.ErrorIs(t, err, validate.ErrInvalidAddress)
+	_, err = alerts.Create(whale, posalert.Thresholds{MinSize: -1}, "owner")
+	assert.ErrorIs(t, err, posalert.ErrInvalidThreshold)
+
+	watch, err := alerts.Create(whale, posalert.Thresholds{MinValue: 25}, "owner")
+	require.NoError(t, err)
+	_, err = alerts.Create("0x2222222222222222222222222222222222222222", posalert.Thresholds{}, "other")
+	require.NoError(t, err)
+	_, err = alerts.Create(whale, posalert.Thresholds{}, "owner")
+	assert.ErrorIs(t, err, posalert.ErrFull)
+
+	_, ok := alerts.Get(watch.ID, "other")
+	assert.False(t, ok, "watches are scoped to their owner")
+	_, err = alerts.Update(watch.ID, posalert.Thresholds{}, "other")
+	assert.ErrorIs(t, err, posalert.ErrNotFound)
+	assert.False(t, alerts.Delete(watch.ID, "other"))
+	require.Len(t, alerts.Watches("owner"), 1)
+
+	// Watches survive a restart
+	restored, _ := newTestPositionAlerts(t, &positions, cfg)
+	got, ok := restored.Get(watch.ID, "owner")
+	require.True(t, ok)
+	assert.Equal(t, 25.0, got.MinValue)
+	assert.Len(t, restored.Watches("other"), 1)
+
+	assert.True(t, restored.Delete(watch.ID, "owner"))
+	assert.Empty(t, restored.Watches("owner"))
+}
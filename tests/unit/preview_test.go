@@ -0,0 +1,75 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+)
+
+var (
+	previewTerms = polymarket.MarketTerms{TickSize: 0.01, FeeRateBps: 200}
+	// Worst price first, as the CLOB lists them
+	previewBids = []models.PriceLevel{{Price: "0.30", Size: "10"}, {Price: "0.35", Size: "40"}}
+	previewAsks = []models.PriceLevel{{Price: "0.45", Size: "100"}, {Price: "0.42", Size: "50"}, {Price: "0.40", Size: "30"}}
+)
+
+func TestPreviewMatch_BuyWalksAsks(t *testing.T) {
+	p, err := polymarket.PreviewMatch(polymarket.PreviewOrder{Side: models.SideBuy, Price: 0.45, Size: 100}, previewTerms, previewBids, previewAsks)
+	require.NoError(t, err)
+
+	assert.Equal(t, []polymarket.PreviewFill{{Price: 0.40, Size: 30}, {Price: 0.42, Size: 50}, {Price: 0.45, Size: 20}}, p.Fills)
+	assert.Equal(t, models.OrderTypeGTC, p.Type)
+	assert.Equal(t, 100.0, p.FilledSize)
+	assert.Zero(t, p.RemainingSize)
+	assert.Equal(t, 42.0, p.Notional)
+	assert.Equal(t, 0.42, *p.AvgPrice)
+	assert.Equal(t, 0.45, *p.WorstPrice)
+	assert.Equal(t, 0.40, *p.BestPrice)
+	assert.Equal(t, 0.02, *p.Slippage)
+	assert.Equal(t, 0.84, p.Fee)
+	assert.Equal(t, 2.0, p.FeeShares)
+}
+
+func TestPreviewMatch_StopsAtLimit(t *testing.T) {
+	p, err := polymarket.PreviewMatch(polymarket.PreviewOrder{Side: models.SideBuy, Price: 0.42, Size: 100}, previewTerms, previewBids, previewAsks)
+	require.NoError(t, err)
+	assert.Equal(t, 80.0, p.FilledSize)
+	assert.Equal(t, 20.0, p.RemainingSize)
+	assert.Equal(t, 20.0, p.Resting)
+	assert.Equal(t, 0.4125, *p.AvgPrice)
+
+	// FOK orders fill whole or not at all
+	p, err = polymarket.PreviewMatch(polymarket.PreviewOrder{Side: models.SideBuy, Price: 0.42, Size: 100, Type: models.OrderTypeFOK}, previewTerms, previewBids, previewAsks)
+	require.NoError(t, err)
+	assert.True(t, p.Killed)
+	assert.Zero(t, p.FilledSize)
+	assert.Equal(t, 100.0, p.RemainingSize)
+	assert.Zero(t, p.Resting)
+	assert.Nil(t, p.AvgPrice)
+	assert.Empty(t, p.Fills)
+}
+
+func TestPreviewMatch_SellWalksBids(t *testing.T) {
+	p, err := polymarket.PreviewMatch(polymarket.PreviewOrder{Side: models.SideSell, Price: 0.30, Size: 50}, previewTerms, previewBids, previewAsks)
+	require.NoError(t, err)
+
+	assert.Equal(t, []polymarket.PreviewFill{{Price: 0.35, Size: 40}, {Price: 0.30, Size: 10}}, p.Fills)
+	assert.Equal(t, 17.0, p.Notional)
+	assert.Equal(t, 0.34, *p.AvgPrice)
+	assert.Equal(t, 0.01, *p.Slippage)
+	assert.Equal(t, 0.34, p.Fee)
+	assert.Zero(t, p.FeeShares, "sells pay the fee in USDC")
+
+	// An empty side fills nothing
+	p, err = polymarket.PreviewMatch(polymarket.PreviewOrder{Side: models.SideSell, Price: 0.30, Size: 60}, previewTerms, nil, previewAsks)
+	require.NoError(t, err)
+	assert.Nil(t, p.BestPrice)
+	assert.Equal(t, 60.0, p.Resting)
+
+	_, err = polymarket.PreviewMatch(polymarket.PreviewOrder{Side: models.SideSell, Price: 0.305, Size: 60}, previewTerms, previewBids, previewAsks)
+	assert.ErrorIs(t, err, polymarket.ErrPriceOffTick)
+}
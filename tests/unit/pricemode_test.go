@@ -0,0 +1,95 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+)
+
+func TestParsePriceMode(t *testing.T) {
+	for s, want := range map[string]polymarket.PriceMode{
+		"mid":             {Anchor: polymarket.AnchorMid},
+		"best_bid+2ticks": {Anchor: polymarket.AnchorBestBid, Ticks: 2},
+		"best_ask-1tick":  {Anchor: polymarket.AnchorBestAsk, Ticks: -1},
+		" Best_Bid+1Tick": {Anchor: polymarket.AnchorBestBid, Ticks: 1},
+	} {
+		mode, err := polymarket.ParsePriceMode(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, mode, s)
+	}
+
+	for _, s := range []string{"", "last", "mid+", "mid+1", "best_bid+1.5ticks", "best_bid + 1tick"} {
+		_, err := polymarket.ParsePriceMode(s)
+		assert.ErrorIs(t, err, polymarket.ErrInvalidPriceMode, s)
+	}
+}
+
+func TestPriceMode_Resolve(t *testing.T) {
+	bids := []models.PriceLevel{{Price: "0.48", Size: "10"}, {Price: "0.49", Size: "10"}}
+	asks := []models.PriceLevel{{Price: "0.53", Size: "10"}, {Price: "0.52", Size: "10"}}
+	resolve := func(mode string, side models.Side) (float64, error) {
+		m, err := polymarket.ParsePriceMode(mode)
+		require.NoError(t, err)
+		return m.Resolve(side, bids, asks, 0.01)
+	}
+
+	// The midpoint 0.505 is rounded away from the other side
+	price, err := resolve("mid", models.SideBuy)
+	require.NoError(t, err)
+	assert.Equal(t, 0.50, price)
+	price, _ = resolve("mid", models.SideSell)
+	assert.Equal(t, 0.51, price)
+	price, _ = resolve("mid-1tick", models.SideSell)
+	assert.Equal(t, 0.50, price)
+
+	price, _ = resolve("best_bid+2ticks", models.SideBuy)
+	assert.Equal(t, 0.51, price)
+	price, _ = resolve("best_ask-1tick", models.SideSell)
+	assert.Equal(t, 0.51, price)
+
+	_, err = resolve("best_bid-49ticks", models.SideBuy)
+	assert.ErrorIs(t, err, polymarket.ErrPriceOutOfRange)
+
+	m, _ := polymarket.ParsePriceMode("mid")
+	_, err = m.Resolve(models.SideBuy, bids, nil, 0.01)
+	assert.ErrorIs(t, err, polymarket.ErrNoAnchorPrice)
+}
+
+func TestPriceResolver_ResolvesOrders(t *testing.T) {
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	resolver := polymarket.NewPriceResolver(polymarket.NewClobClient(polymarket.NewClient(&cfg.Polymarket, c)))
+
+	// The mock book is 0.49 bid, 0.51 ask for every token
+	orders := []models.CreateOrderRequest{
+		{TokenID: mockupstream.TokenYes, Side: models.SideBuy, Price: "0.3", Size: "5"},
+		{TokenID: mockupstream.TokenYes, Side: models.SideBuy, PriceMode: "mid", Size: "5"},
+		{TokenID: mockupstream.TokenNo, Side: models.SideSell, PriceMode: "best_ask+2ticks", Size: "5"},
+	}
+	require.NoError(t, resolver.Resolve(orders))
+	assert.Equal(t, "0.3", orders[0].Price)
+	assert.Equal(t, "0.5", orders[1].Price)
+	assert.Equal(t, "0.53", orders[2].Price)
+	assert.Len(t, mock.Requests(mockupstream.CLOB), 3, "one /books request and a tick size lookup per token")
+
+	orders = []models.CreateOrderRequest{
+		{TokenID: mockupstream.TokenYes, Side: models.SideBuy, PriceMode: "mid", Size: "5"},
+		{TokenID: mockupstream.TokenYes, Side: models.SideBuy, Price: "0.4", PriceMode: "mid", Size: "5"},
+	}
+	var modeErr *polymarket.PriceModeError
+	require.ErrorAs(t, resolver.Resolve(orders), &modeErr)
+	assert.Equal(t, 1, modeErr.Order)
+	assert.ErrorIs(t, modeErr, polymarket.ErrPriceWithMode)
+}
@@ -0,0 +1,160 @@
+package unit
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/polymarket"
+)
+
+// startSOCKS5 runs a minimal SOCKS5 proxy requiring user/pass and returns
+// its address and how many connections it tunneled
+func startSOCKS5(t *testing.T, user, pass string) (string, *atomic.Int64) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	var tunneled atomic.Int64
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				head := make([]byte, 2)
+				io.ReadFull(conn, head)
+				io.ReadFull(conn, make([]byte, head[1]))
+				conn.Write([]byte{5, 2})
+
+				// RFC 1929 username/password
+				io.ReadFull(conn, head)
+				u := make([]byte, head[1])
+				io.ReadFull(conn, u)
+				io.ReadFull(conn, head[:1])
+				p := make([]byte, head[0])
+				io.ReadFull(conn, p)
+				if string(u) != user || string(p) != pass {
+					conn.Write([]byte{1, 1})
+					return
+				}
+				conn.Write([]byte{1, 0})
+
+				req := make([]byte, 4)
+				io.ReadFull(conn, req)
+				var host string
+				switch req[3] {
+				case 1:
+					ip := make([]byte, 4)
+					io.ReadFull(conn, ip)
+					host = net.IP(ip).String()
+				case 3:
+					io.ReadFull(conn, head[:1])
+					name := make([]byte, head[0])
+					io.ReadFull(conn, name)
+					host = string(name)
+				}
+				port := make([]byte, 2)
+				io.ReadFull(conn, port)
+
+				upstream, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))))
+				if err != nil {
+					conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+					return
+				}
+				defer upstream.Close()
+				tunneled.Add(1)
+				conn.Write([]byte{5, 0, 0, 1, 127, 0, 0, 1, 0, 0})
+				go io.Copy(upstream, conn)
+				io.Copy(conn, upstream)
+			}(conn)
+		}
+	}()
+	return ln.Addr().String(), &tunneled
+}
+
+func proxiedGet(t *testing.T, d *polymarket.ProxyDialer, url string) (*http.Response, error) {
+	client := &http.Client{Transport: &http.Transport{DialContext: d.DialContext}}
+	t.Cleanup(client.CloseIdleConnections)
+	return client.Get(url)
+}
+
+func TestProxyDialer_SOCKS5(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, "ok") }))
+	defer srv.Close()
+	addr, tunneled := startSOCKS5(t, "polygo", "s3cret")
+
+	for _, scheme := range []string{"socks5", "socks5h"} {
+		d, err := polymarket.NewProxyDialer(&config.PolymarketConfig{Proxy: scheme + "://polygo:s3cret@" + addr})
+		require.NoError(t, err)
+		assert.True(t, d.Enabled())
+
+		resp, err := proxiedGet(t, d, srv.URL)
+		require.NoError(t, err, scheme)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, "ok", string(body))
+	}
+	assert.EqualValues(t, 2, tunneled.Load())
+
+	d, err := polymarket.NewProxyDialer(&config.PolymarketConfig{Proxy: "socks5://polygo:wrong@" + addr})
+	require.NoError(t, err)
+	_, err = d.DialContext(context.Background(), "tcp", srv.Listener.Addr().String())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "authentication failed")
+}
+
+func TestProxyDialer_PerHost(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, "ok") }))
+	defer srv.Close()
+	addr, tunneled := startSOCKS5(t, "u", "p")
+
+	// The default proxy is down, but 127.0.0.1 goes through its own
+	d, err := polymarket.NewProxyDialer(&config.PolymarketConfig{
+		Proxy:   "http://127.0.0.1:1",
+		Proxies: map[string]string{"127.0.0.1": "socks5://u:p@" + addr, "localhost": "direct"},
+	})
+	require.NoError(t, err)
+	resp, err := proxiedGet(t, d, srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.EqualValues(t, 1, tunneled.Load())
+
+	_, err = d.DialContext(context.Background(), "tcp", "example.com:443")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "proxy 127.0.0.1:1 unreachable")
+
+	names := d.Proxies()
+	assert.Contains(t, names, "proxy")
+	assert.Contains(t, names, "proxy:127.0.0.1")
+	assert.NotContains(t, names, "proxy:localhost")
+}
+
+func TestProxyDialer_InvalidConfig(t *testing.T) {
+	for _, raw := range []string{"ftp://proxy:21", "http://proxy", "socks5://:1080"} {
+		d, err := polymarket.NewProxyDialer(&config.PolymarketConfig{Proxy: raw})
+		assert.Error(t, err, raw)
+		// Never falls back to connecting directly
+		_, err = d.DialContext(context.Background(), "tcp", "example.com:443")
+		assert.Error(t, err, raw)
+	}
+
+	_, err := polymarket.NewProxyDialer(&config.PolymarketConfig{Proxies: map[string]string{"clob.polymarket.com": "nope"}})
+	assert.ErrorContains(t, err, "polymarket.proxies.clob.polymarket.com")
+
+	d, err := polymarket.NewProxyDialer(&config.PolymarketConfig{})
+	require.NoError(t, err)
+	assert.False(t, d.Enabled())
+}
@@ -0,0 +1,142 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/api/middleware"
+)
+
+func TestRateLimit_HeadersAreNumeric(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.RateLimit(middleware.RateLimitConfig{Max: 120, Window: 10 * time.Second}))
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	for i := 1; i <= 2; i++ {
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil), -1)
+		require.NoError(t, err)
+		require.Equal(t, 200, resp.StatusCode)
+
+		remaining := strconv.Itoa(120 - i)
+		assert.Equal(t, "120", resp.Header.Get("X-RateLimit-Limit"))
+		assert.Equal(t, remaining, resp.Header.Get("X-RateLimit-Remaining"))
+		assert.Equal(t, "120", resp.Header.Get("RateLimit-Limit"))
+		assert.Equal(t, remaining, resp.Header.Get("RateLimit-Remaining"))
+		assert.Equal(t, "120;w=10", resp.Header.Get("RateLimit-Policy"))
+
+		reset, err := strconv.Atoi(resp.Header.Get("RateLimit-Reset"))
+		require.NoError(t, err, "seconds until the window resets")
+		assert.InDelta(t, 10, reset, 1)
+		_, err = time.Parse(time.RFC3339, resp.Header.Get("X-RateLimit-Reset"))
+		assert.NoError(t, err)
+	}
+}
+
+func TestRateLimit_ExhaustedLimitReportsZeroRemaining(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.RateLimit(middleware.RateLimitConfig{Max: 1, Window: time.Minute}))
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, "0", resp.Header.Get("RateLimit-Remaining"))
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 429, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", resp.Header.Get("X-RateLimit-Remaining"))
+	assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+}
+
+func rateLimitApp(t *testing.T, config middleware.RateLimitConfig) *fiber.App {
+	handler, err := middleware.NewRateLimit(config)
+	require.NoError(t, err)
+	app := fiber.New()
+	app.Use(handler)
+	app.Get("/*", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	return app
+}
+
+func TestRateLimit_SlidingWindowFreesSlotsAsRequestsAge(t *testing.T) {
+	app := rateLimitApp(t, middleware.RateLimitConfig{Max: 2, Window: 300 * time.Millisecond, Algorithm: middleware.SlidingWindow})
+	get := func() *http.Response {
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil), -1)
+		require.NoError(t, err)
+		return resp
+	}
+
+	assert.Equal(t, 200, get().StatusCode)
+	assert.Equal(t, 200, get().StatusCode)
+	resp := get()
+	assert.Equal(t, 429, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"), "the oldest request leaves the window within a second")
+
+	time.Sleep(350 * time.Millisecond)
+	assert.Equal(t, 200, get().StatusCode)
+}
+
+func TestRateLimit_TokenBucketAllowsBurstThenRefillRate(t *testing.T) {
+	app := rateLimitApp(t, middleware.RateLimitConfig{Max: 1, Window: time.Minute, Algorithm: middleware.TokenBucket, Burst: 3})
+
+	for i := 0; i < 3; i++ {
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil), -1)
+		require.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode, "request %d is within the burst", i)
+		assert.Equal(t, "1;w=60;burst=3", resp.Header.Get("RateLimit-Policy"))
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 429, resp.StatusCode)
+	retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	require.NoError(t, err)
+	assert.InDelta(t, 60, retryAfter, 1, "one token refills per minute")
+}
+
+func TestRateLimit_RoutesHaveTheirOwnLimits(t *testing.T) {
+	app := rateLimitApp(t, middleware.RateLimitConfig{
+		Max:    100,
+		Window: time.Minute,
+		Routes: []middleware.RouteRateLimit{
+			{Prefix: "/api/v1/orders", Max: 1, Algorithm: middleware.SlidingWindow},
+			{Prefix: "/api/v1/orders/batch", Max: 5},
+		},
+	})
+	get := func(path string) *http.Response {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil), -1)
+		require.NoError(t, err)
+		return resp
+	}
+
+	assert.Equal(t, 200, get("/api/v1/orders").StatusCode)
+	assert.Equal(t, 429, get("/api/v1/orders/123").StatusCode)
+
+	resp := get("/api/v1/orders/batch")
+	assert.Equal(t, 200, resp.StatusCode, "the longest prefix applies")
+	assert.Equal(t, "5", resp.Header.Get("RateLimit-Limit"))
+
+	resp = get("/api/v1/markets")
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "100", resp.Header.Get("RateLimit-Limit"))
+	assert.Equal(t, "99", resp.Header.Get("RateLimit-Remaining"), "routes are counted apart")
+
+	assert.Equal(t, 429, get("/API/V1/Orders").StatusCode, "Fiber routes ignore case, so does the route limit")
+}
+
+func TestNewRateLimit_RejectsUnknownAlgorithms(t *testing.T) {
+	_, err := middleware.NewRateLimit(middleware.RateLimitConfig{Algorithm: "leaky_bucket"})
+	assert.Error(t, err)
+
+	_, err = middleware.NewRateLimit(middleware.RateLimitConfig{
+		Routes: []middleware.RouteRateLimit{{Prefix: "/api/v1/orders", Algorithm: "leaky_bucket"}},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package unit
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/api/middleware"
+	"github.com/polygo/internal/config"
+)
+
+// newRealIPApp echoes the client IP; app.Test connects from 0.0.0.0
+func newRealIPApp(t *testing.T, trusted ...string) *fiber.App {
+	cfg := config.DefaultConfig().Server
+	cfg.TrustedProxies = trusted
+	proxies, err := middleware.NewProxies(&cfg)
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Use(proxies.RealIP())
+	app.Use(middleware.RateLimit(middleware.RateLimitConfig{Max: 1, Window: time.Minute}))
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendString(middleware.ClientIP(c)) })
+	return app
+}
+
+func getFrom(t *testing.T, app *fiber.App, forwardedFor string) (int, string) {
+	req := httptest.NewRequest("GET", "/", nil)
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	buf := make([]byte, 64)
+	n, _ := resp.Body.Read(buf)
+	return resp.StatusCode, string(buf[:n])
+}
+
+func TestRealIP_TrustedProxyRateLimitsEachClient(t *testing.T) {
+	app := newRealIPApp(t, "0.0.0.0", "10.0.0.0/8")
+
+	status, ip := getFrom(t, app, "203.0.113.7, 10.1.2.3")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "203.0.113.7", ip, "the last hop before the trusted proxies")
+
+	status, ip = getFrom(t, app, "198.51.100.1:4711")
+	assert.Equal(t, 200, status, "another client behind the same balancer has its own limit")
+	assert.Equal(t, "198.51.100.1", ip)
+
+	status, _ = getFrom(t, app, "1.1.1.1, 203.0.113.7")
+	assert.Equal(t, 429, status, "a spoofed leftmost address does not escape the limit")
+}
+
+func TestRealIP_IgnoresHeadersFromUntrustedPeers(t *testing.T) {
+	app := newRealIPApp(t)
+
+	status, ip := getFrom(t, app, "203.0.113.7")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, "0.0.0.0", ip)
+
+	status, _ = getFrom(t, app, "198.51.100.1")
+	assert.Equal(t, 429, status)
+}
+
+func TestNewProxies_RejectsBadConfig(t *testing.T) {
+	cfg := config.DefaultConfig().Server
+	cfg.TrustedProxies = []string{"10.0.0.0/33"}
+	_, err := middleware.NewProxies(&cfg)
+	assert.Error(t, err)
+
+	cfg.TrustedProxies = []string{"unix", "::1"}
+	cfg.ProxyHeader = "Forwarded"
+	_, err = middleware.NewProxies(&cfg)
+	assert.Error(t, err)
+
+	cfg.ProxyHeader = "X-Real-IP"
+	_, err = middleware.NewProxies(&cfg)
+	assert.NoError(t, err)
+}
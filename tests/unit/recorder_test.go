@@ -0,0 +1,76 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/recorder"
+)
+
+func newTestRecorder() *recorder.Recorder {
+	return recorder.New(nil, nil, &config.RecorderConfig{
+		Resolution: time.Minute,
+		Retention:  25 * time.Hour,
+	})
+}
+
+func TestRecorder_AggregatesSamplesIntoCandles(t *testing.T) {
+	rec := newTestRecorder()
+	base := time.Now().Truncate(time.Minute)
+
+	rec.Record("m1", "Q?", "q", 0.50, 100, base)
+	rec.Record("m1", "Q?", "q", 0.60, 110, base.Add(10*time.Second))
+	rec.Record("m1", "Q?", "q", 0.40, 120, base.Add(20*time.Second))
+	rec.Record("m1", "Q?", "q", 0.45, 130, base.Add(time.Minute))
+
+	candles := rec.Candles("m1", base)
+	require.Len(t, candles, 2)
+	assert.Equal(t, 0.50, candles[0].Open)
+	assert.Equal(t, 0.60, candles[0].High)
+	assert.Equal(t, 0.40, candles[0].Low)
+	assert.Equal(t, 0.40, candles[0].Close)
+	assert.Equal(t, 120.0, candles[0].Volume)
+	assert.Equal(t, 0.45, candles[1].Close)
+}
+
+func TestRecorder_MoversSortsByWindowAndOrder(t *testing.T) {
+	rec := newTestRecorder()
+	now := time.Now()
+
+	// m1: small absolute move on a cheap market (large percent)
+	rec.Record("m1", "Cheap", "", 0.02, 1000, now.Add(-2*time.Hour))
+	rec.Record("m1", "Cheap", "", 0.06, 1100, now)
+	// m2: big absolute move, lots of volume
+	rec.Record("m2", "Big", "", 0.30, 1000, now.Add(-2*time.Hour))
+	rec.Record("m2", "Big", "", 0.55, 9000, now)
+
+	byAbs := rec.Movers(time.Hour*3, recorder.SortAbsolute, 10)
+	require.Len(t, byAbs, 2)
+	assert.Equal(t, "m2", byAbs[0].MarketID)
+	assert.InDelta(t, 0.25, byAbs[0].Change, 1e-9)
+
+	byPct := rec.Movers(time.Hour*3, recorder.SortPercent, 10)
+	assert.Equal(t, "m1", byPct[0].MarketID)
+	assert.InDelta(t, 200.0, byPct[0].ChangePct, 1e-9)
+
+	byVol := rec.Movers(time.Hour*3, recorder.SortVolume, 1)
+	require.Len(t, byVol, 1)
+	assert.Equal(t, 8000.0, byVol[0].Volume)
+}
+
+func TestRecorder_ShortWindowIgnoresOlderHistory(t *testing.T) {
+	rec := newTestRecorder()
+	now := time.Now()
+
+	rec.Record("m1", "Q", "", 0.10, 0, now.Add(-time.Hour))
+	rec.Record("m1", "Q", "", 0.50, 0, now.Add(-10*time.Minute))
+	rec.Record("m1", "Q", "", 0.55, 0, now)
+
+	movers := rec.Movers(5*time.Minute, recorder.SortAbsolute, 10)
+	require.Len(t, movers, 1)
+	assert.InDelta(t, 0.05, movers[0].Change, 1e-9)
+}
@@ -0,0 +1,65 @@
+package unit
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/api/middleware"
+)
+
+// requestIDOf returns the ID the handler saw and the one echoed back
+func requestIDOf(t *testing.T, app *fiber.App, header string) (string, string) {
+	req := httptest.NewRequest("GET", "/", nil)
+	if header != "" {
+		req.Header.Set(middleware.RequestIDHeader, header)
+	}
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body), resp.Header.Get(middleware.RequestIDHeader)
+}
+
+func TestRequestID_PropagatesAndGenerates(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.RequestID())
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(middleware.GetRequestID(c))
+	})
+
+	// The caller's ID is kept
+	seen, echoed := requestIDOf(t, app, "trace-123")
+	assert.Equal(t, "trace-123", seen)
+	assert.Equal(t, "trace-123", echoed)
+
+	// Without one, each request gets its own
+	first, echoed := requestIDOf(t, app, "")
+	assert.NotEmpty(t, first)
+	assert.Equal(t, first, echoed)
+	second, _ := requestIDOf(t, app, "")
+	assert.NotEqual(t, first, second)
+
+	// Oversized IDs are replaced rather than echoed into logs and events
+	long := strings.Repeat("x", 129)
+	seen, echoed = requestIDOf(t, app, long)
+	assert.NotEqual(t, long, seen)
+	assert.NotEmpty(t, seen)
+	assert.Equal(t, seen, echoed)
+}
+
+func TestRequestID_EmptyOutsideMiddleware(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(middleware.GetRequestID(c))
+	})
+
+	seen, echoed := requestIDOf(t, app, "trace-123")
+	assert.Empty(t, seen)
+	assert.Empty(t, echoed)
+}
@@ -0,0 +1,74 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/models"
+)
+
+func newTestResolver() *catalog.Resolver {
+	cat := catalog.New(nil, &config.CatalogConfig{})
+	cat.Load([]*models.Event{{
+		ID:   "e1",
+		Slug: "fed-march",
+		Markets: []models.Market{{
+			ID:           "m1",
+			ConditionID:  "0xc1",
+			Question:     "Will the Fed cut rates in March?",
+			Slug:         "fed-cut-march",
+			Outcomes:     models.StringList{"Yes", "No"},
+			ClobTokenIDs: models.StringList{"tok-yes", "tok-no"},
+		}},
+	}})
+	return catalog.NewResolver(cat, nil)
+}
+
+func TestResolver_LookupFromCatalog(t *testing.T) {
+	r := newTestResolver()
+
+	info, ok := r.Lookup("tok-no")
+	require.True(t, ok)
+	assert.Equal(t, "No", info.Outcome)
+	assert.Equal(t, 1, info.OutcomeIndex)
+	assert.Equal(t, "m1", info.MarketID)
+	assert.Equal(t, "fed-cut-march", info.Slug)
+	assert.Equal(t, "fed-march", info.EventSlug)
+}
+
+func TestResolver_EnrichesTradeAndPriceMessages(t *testing.T) {
+	r := newTestResolver()
+
+	trade := r.Enrich([]byte(`{"event_type":"last_trade_price","asset_id":"tok-yes","price":"0.42"}`))
+	var msg map[string]interface{}
+	require.NoError(t, sonic.Unmarshal(trade, &msg))
+	info := msg["market_info"].(map[string]interface{})
+	assert.Equal(t, "Yes", info["outcome"])
+	assert.Equal(t, "Will the Fed cut rates in March?", info["question"])
+	assert.Equal(t, "0.42", msg["price"])
+
+	prices := r.Enrich([]byte(`[{"event_type":"price_change","market":"0xc1","price_changes":[{"asset_id":"tok-yes","price":"0.4"},{"asset_id":"tok-no","price":"0.6"}]}]`))
+	var batch []map[string]interface{}
+	require.NoError(t, sonic.Unmarshal(prices, &batch))
+	require.Len(t, batch, 1)
+	changes := batch[0]["price_changes"].([]interface{})
+	assert.Equal(t, "Yes", changes[0].(map[string]interface{})["outcome"])
+	assert.Equal(t, "No", changes[1].(map[string]interface{})["outcome"])
+	assert.Equal(t, "m1", batch[0]["market_info"].(map[string]interface{})["market_id"])
+}
+
+func TestResolver_LeavesOtherMessagesUnchanged(t *testing.T) {
+	r := newTestResolver()
+
+	for _, data := range []string{
+		`{"type":"pong"}`,
+		`not json`,
+	} {
+		assert.Equal(t, data, string(r.Enrich([]byte(data))))
+	}
+}
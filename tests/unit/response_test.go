@@ -4,6 +4,7 @@ import (
 	"io"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/gofiber/fiber/v2"
@@ -57,6 +58,8 @@ func TestResponse_Error(t *testing.T) {
 	assert.Equal(t, "BAD_REQUEST", result.Error.Code)
 	assert.Equal(t, "Invalid input", result.Error.Message)
 	assert.Equal(t, "Details here", result.Error.Details)
+	assert.False(t, result.Error.Retryable)
+	assert.Contains(t, string(body), `"retryable":false`)
 }
 
 func TestResponse_BadRequest(t *testing.T) {
@@ -102,13 +105,44 @@ func TestResponse_TooManyRequests(t *testing.T) {
 	app := fiber.New()
 
 	app.Get("/test", func(c *fiber.Ctx) error {
-		return response.TooManyRequests(c)
+		return response.TooManyRequests(c, 30*time.Second)
 	})
 
 	req := httptest.NewRequest("GET", "/test", nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, 429, resp.StatusCode)
+	assert.Equal(t, "30", resp.Header.Get("Retry-After"))
+
+	body, _ := io.ReadAll(resp.Body)
+	var result response.Response
+	require.NoError(t, sonic.Unmarshal(body, &result))
+	assert.True(t, result.Error.Retryable)
+	assert.Equal(t, int64(30000), result.Error.RetryAfterMs)
+}
+
+func TestResponse_RetryRoundsRetryAfterUp(t *testing.T) {
+	app := fiber.New()
+
+	app.Get("/known", func(c *fiber.Ctx) error {
+		return response.Retry(c, 503, "UPSTREAM_BUSY", "Busy", "", 1500*time.Millisecond)
+	})
+	app.Get("/unknown", func(c *fiber.Ctx) error {
+		return response.Retry(c, 502, "UPSTREAM_UNAVAILABLE", "Down", "", 0)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/known", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "2", resp.Header.Get("Retry-After"))
+	body, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), `"retry_after_ms":1500`)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/unknown", nil))
+	require.NoError(t, err)
+	assert.Empty(t, resp.Header.Get("Retry-After"))
+	body, _ = io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), `"retryable":true`)
+	assert.NotContains(t, string(body), "retry_after_ms")
 }
 
 func TestResponse_Raw(t *testing.T) {
@@ -0,0 +1,153 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/polymarket"
+)
+
+// countingServer answers the first failures requests with status, then 200
+func countingServer(status, failures int, header map[string]string) (*httptest.Server, *atomic.Int32) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(hits.Add(1)) <= failures {
+			for k, v := range header {
+				w.Header().Set(k, v)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	return srv, &hits
+}
+
+func newRetryClient(t *testing.T, mutate func(*config.PolymarketConfig)) *polymarket.Client {
+	cfg := config.DefaultConfig()
+	cfg.Polymarket.RetryWaitTime = time.Millisecond
+	cfg.Polymarket.RetryMaxWait = 5 * time.Millisecond
+	if mutate != nil {
+		mutate(&cfg.Polymarket)
+	}
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	return polymarket.NewClient(&cfg.Polymarket, c)
+}
+
+func TestRetry_GetRetriesServerErrors(t *testing.T) {
+	srv, hits := countingServer(http.StatusBadGateway, 2, nil)
+	defer srv.Close()
+
+	_, err := newRetryClient(t, nil).Get(srv.URL, nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, hits.Load())
+}
+
+func TestRetry_PostIsNeverRetried(t *testing.T) {
+	srv, hits := countingServer(http.StatusBadGateway, 1, nil)
+	defer srv.Close()
+
+	_, err := newRetryClient(t, nil).Post(srv.URL+"/order", []byte(`{}`), nil)
+	require.Error(t, err)
+	assert.EqualValues(t, 1, hits.Load())
+}
+
+func TestRetry_HonorsRetryAfter(t *testing.T) {
+	srv, hits := countingServer(http.StatusTooManyRequests, 1, map[string]string{"Retry-After": "0"})
+	defer srv.Close()
+
+	_, err := newRetryClient(t, nil).Get(srv.URL, nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, hits.Load())
+
+	// A Retry-After beyond RetryMaxWait is surfaced as a 429 right away
+	slow, slowHits := countingServer(http.StatusTooManyRequests, 1, map[string]string{"Retry-After": "60"})
+	defer slow.Close()
+
+	_, err = newRetryClient(t, nil).Get(slow.URL, nil)
+	var statusErr *polymarket.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusTooManyRequests, statusErr.StatusCode)
+	assert.EqualValues(t, 1, slowHits.Load())
+}
+
+func TestRetry_BudgetStopsRetryStorms(t *testing.T) {
+	srv, hits := countingServer(http.StatusServiceUnavailable, 1000, nil)
+	defer srv.Close()
+
+	client := newRetryClient(t, func(cfg *config.PolymarketConfig) {
+		cfg.RetryCount = 3
+		cfg.RetryBudgetRatio = 0
+		cfg.RetryBudgetBurst = 2
+	})
+
+	for i := 0; i < 5; i++ {
+		client.Get(srv.URL, nil)
+	}
+	// 5 initial attempts plus the 2 retries the budget allowed
+	assert.EqualValues(t, 7, hits.Load())
+}
+
+func TestUpstreamLimiter_QueuesThenRejects(t *testing.T) {
+	srv, hits := countingServer(http.StatusOK, 0, nil)
+	defer srv.Close()
+
+	client := newRetryClient(t, func(cfg *config.PolymarketConfig) {
+		cfg.UpstreamRPS = 20
+		cfg.UpstreamBurst = 1
+		cfg.UpstreamQueueSize = 1
+		cfg.UpstreamQueueWait = time.Second
+	})
+
+	// The burst goes straight through and the next request queues for a
+	// token (~50ms at 20 rps)
+	start := time.Now()
+	_, err := client.Get(srv.URL, nil)
+	require.NoError(t, err)
+	_, err = client.Get(srv.URL, nil)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+
+	// With one request already queued, the next is rejected
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Get(srv.URL, nil)
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	_, err = client.Get(srv.URL, nil)
+	assert.ErrorIs(t, err, polymarket.ErrUpstreamBusy)
+	require.NoError(t, <-done)
+	assert.EqualValues(t, 3, hits.Load())
+}
+
+func TestUpstreamTimeouts_PerPathOverride(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := newRetryClient(t, func(cfg *config.PolymarketConfig) {
+		cfg.DataBaseURL = srv.URL
+		cfg.ReadTimeout = 20 * time.Millisecond
+		cfg.RetryCount = 0
+		cfg.Timeouts = map[string]time.Duration{"/prices-history": time.Second}
+	})
+
+	_, err := client.Get(client.Data("/positions?user=0x1"), nil)
+	assert.Error(t, err)
+
+	_, err = client.Get(client.Data("/prices-history?market=1"), nil)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,115 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/rewards"
+	"github.com/polygo/pkg/validate"
+)
+
+const rewardsMaker = "0x00000000000000000000000000000000000000aa"
+
+func newRewardsEstimator(t *testing.T) (*rewards.Estimator, *mockupstream.Server) {
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	client := polymarket.NewClient(&cfg.Polymarket, c)
+	return rewards.New(polymarket.NewClobClient(client), polymarket.NewGammaClient(client), nil), mock
+}
+
+func TestRewards_EstimatesShareOfDailyRewards(t *testing.T) {
+	estimator, mock := newRewardsEstimator(t)
+	// Mock market 1 pays 20 USDC a day for orders of 50+ shares within 3.5c
+	// of a 0.50 midpoint; its books rest 100 shares 1c and 200 shares 2c out
+	// on each side of both outcomes
+	mock.On(mockupstream.CLOB, "GET", "/orders/open", 200, `{"data":[
+		{"id":"yes-bid","asset_id":"`+mockupstream.TokenYes+`","side":"BUY","price":"0.49","original_size":"120","size_matched":"20","maker_address":"`+rewardsMaker+`"},
+		{"id":"no-bid","asset_id":"`+mockupstream.TokenNo+`","side":"BUY","price":"0.49","original_size":"100","size_matched":"0"},
+		{"id":"small","asset_id":"`+mockupstream.TokenYes+`","side":"SELL","price":"0.51","original_size":"10","size_matched":"0"},
+		{"id":"wide","asset_id":"`+mockupstream.TokenYes+`","side":"SELL","price":"0.55","original_size":"100","size_matched":"0"},
+		{"id":"other-maker","asset_id":"`+mockupstream.TokenYes+`","side":"BUY","price":"0.49","original_size":"100","size_matched":"0","maker_address":"0x00000000000000000000000000000000000000bb"},
+		{"id":"no-program","asset_id":"mock-token-yes-2","side":"BUY","price":"0.49","original_size":"100","size_matched":"0"}
+	]}`)
+
+	report, err := estimator.Estimate(rewardsMaker, nil)
+	require.NoError(t, err)
+	require.Len(t, report.Markets, 2)
+
+	m := report.Markets[0]
+	assert.Equal(t, mockupstream.MarketID, m.MarketID)
+	assert.Equal(t, 50.0, m.MinSize)
+	assert.Equal(t, 3.5, m.MaxSpread)
+	assert.Equal(t, 20.0, m.DailyRate)
+	assert.Equal(t, 0.5, m.Midpoint)
+	assert.True(t, m.Eligible)
+
+	// Each 100-share order 1c out scores (2.5/3.5)² × 100 = 2500/49; the
+	// book scores 8600/49 on each side
+	assert.InDelta(t, 2500.0/49, m.Score, 1e-4)
+	assert.InDelta(t, 8600.0/49, m.MarketScore, 1e-4)
+	assert.InDelta(t, 2500.0/8600, m.Share, 1e-5)
+	assert.InDelta(t, 20*2500.0/8600, m.EstimatedDaily, 1e-5)
+	assert.Equal(t, m.EstimatedDaily, report.EstimatedDaily)
+
+	require.Len(t, m.Orders, 4)
+	reasons := make(map[string]string)
+	for _, o := range m.Orders {
+		reasons[o.OrderID] = o.Reason
+		assert.Equal(t, o.Reason == "", o.Eligible, o.OrderID)
+	}
+	assert.Equal(t, map[string]string{
+		"yes-bid": "",
+		"no-bid":  "",
+		"small":   rewards.ReasonBelowMinSize,
+		"wide":    rewards.ReasonTooWide,
+	}, reasons)
+
+	assert.Equal(t, rewards.ReasonNoProgram, report.Markets[1].Reason)
+	assert.False(t, report.Markets[1].Eligible)
+}
+
+func TestRewards_OneSidedLiquidity(t *testing.T) {
+	estimator, mock := newRewardsEstimator(t)
+	mock.On(mockupstream.CLOB, "GET", "/orders/open", 200, `[
+		{"id":"yes-bid","asset_id":"`+mockupstream.TokenYes+`","side":"BUY","price":"0.49","original_size":"100","size_matched":"0"}
+	]`)
+
+	// Near 0.50 a lone side still scores a third
+	report, err := estimator.Estimate(rewardsMaker, nil)
+	require.NoError(t, err)
+	require.Len(t, report.Markets, 1)
+	assert.InDelta(t, 2500.0/49/3, report.Markets[0].Score, 1e-4)
+
+	_, err = estimator.Estimate("0x123", nil)
+	assert.ErrorIs(t, err, validate.ErrInvalidAddress)
+}
+
+func TestRewards_NearCertaintyRequiresTwoSidedLiquidity(t *testing.T) {
+	estimator, mock := newRewardsEstimator(t)
+	mock.On(mockupstream.CLOB, "GET", "/book", 200, `{"bids":[{"price":"0.94","size":"100"}],"asks":[{"price":"0.96","size":"100"}]}`)
+	mock.On(mockupstream.CLOB, "GET", "/orders/open", 200, `[
+		{"id":"yes-bid","asset_id":"`+mockupstream.TokenYes+`","side":"BUY","price":"0.94","original_size":"100","size_matched":"0"}
+	]`)
+
+	report, err := estimator.Estimate(rewardsMaker, nil)
+	require.NoError(t, err)
+	require.Len(t, report.Markets, 1)
+	assert.Equal(t, 0.95, report.Markets[0].Midpoint)
+	assert.True(t, report.Markets[0].Orders[0].Eligible)
+	assert.Zero(t, report.Markets[0].Score)
+	assert.Zero(t, report.Markets[0].EstimatedDaily)
+	assert.Equal(t, rewards.ReasonOneSided, report.Markets[0].Reason)
+}
@@ -0,0 +1,126 @@
+package unit
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/risk"
+)
+
+func newRiskChecker(t *testing.T, riskCfg *config.RiskConfig) (*risk.Checker, *mockupstream.Server) {
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	client := polymarket.NewClient(&cfg.Polymarket, c)
+	gamma := polymarket.NewGammaClient(client)
+	resolver := catalog.NewResolver(catalog.New(gamma, &cfg.Catalog), gamma)
+	return risk.New(polymarket.NewClobClient(client), resolver, riskCfg), mock
+}
+
+// otherToken belongs to the second canned market
+const otherToken = "mock-token-yes-2"
+
+func riskOrder(tokenID, price, size string) models.CreateOrderRequest {
+	return models.CreateOrderRequest{TokenID: tokenID, Side: models.SideBuy, Price: price, Size: size}
+}
+
+func riskCode(t *testing.T, err error) *risk.Error {
+	t.Helper()
+	var rejected *risk.Error
+	require.True(t, errors.As(err, &rejected), "expected a risk rejection, got %v", err)
+	return rejected
+}
+
+func TestRisk_OrderSizeAndBannedMarkets(t *testing.T) {
+	checker, _ := newRiskChecker(t, &config.RiskConfig{
+		Enabled: true,
+		Default: config.RiskLimits{MaxOrderSize: 100, BannedMarkets: []string{mockupstream.MarketID}},
+	})
+
+	require.NoError(t, checker.Check("key", nil, []models.CreateOrderRequest{riskOrder(otherToken, "0.5", "100")}))
+
+	rejected := riskCode(t, checker.Check("key", nil, []models.CreateOrderRequest{
+		riskOrder(otherToken, "0.5", "10"),
+		riskOrder(mockupstream.TokenYes, "0.5", "10"),
+	}))
+	assert.Equal(t, risk.CodeBannedMarket, rejected.Code, "banned by the market the token resolves to")
+	require.NotNil(t, rejected.Order)
+	assert.Equal(t, 1, *rejected.Order)
+
+	rejected = riskCode(t, checker.Check("key", nil, []models.CreateOrderRequest{riskOrder(otherToken, "0.5", "101")}))
+	assert.Equal(t, risk.CodeMaxOrderSize, rejected.Code)
+
+	// Account limits replace the default ones
+	checker.SetAccount("vip", config.RiskLimits{MaxOrderSize: 1000})
+	assert.NoError(t, checker.Check("vip", nil, []models.CreateOrderRequest{riskOrder(mockupstream.TokenYes, "0.5", "500")}))
+}
+
+func TestRisk_OrderRate(t *testing.T) {
+	checker, _ := newRiskChecker(t, &config.RiskConfig{Enabled: true, Default: config.RiskLimits{MaxOrdersPerMinute: 3}})
+	batch := []models.CreateOrderRequest{riskOrder("a", "0.5", "1"), riskOrder("b", "0.5", "1")}
+
+	require.NoError(t, checker.Check("key", nil, batch))
+	rejected := riskCode(t, checker.Check("key", nil, batch))
+	assert.Equal(t, risk.CodeOrderRate, rejected.Code)
+	assert.InDelta(t, time.Minute.Seconds(), rejected.RetryAfter.Seconds(), 1, "clears when the first order leaves the window")
+	tooBig := append(batch, riskOrder("c", "0.5", "1"), riskOrder("d", "0.5", "1"))
+	assert.Zero(t, riskCode(t, checker.Check("key", nil, tooBig)).RetryAfter, "a batch over the limit never clears")
+	assert.NoError(t, checker.Check("key", nil, batch[:1]), "rejected orders do not use up the rate")
+	assert.NoError(t, checker.Check("other", nil, batch), "counted per account")
+}
+
+func TestRisk_OpenNotional(t *testing.T) {
+	checker, mock := newRiskChecker(t, &config.RiskConfig{Enabled: true, Default: config.RiskLimits{MaxOpenNotional: 50}})
+	mock.On(mockupstream.CLOB, "GET", "/orders/open", 200, `[{"price":"0.5","original_size":"100","size_matched":"20"}]`)
+	headers := map[string]string{"POLY-API-KEY": "key"}
+
+	rejected := riskCode(t, checker.Check("key", headers, []models.CreateOrderRequest{riskOrder("a", "0.5", "30")}))
+	assert.Equal(t, risk.CodeMaxOpenNotional, rejected.Code, "40 open + 15 new")
+	assert.Nil(t, rejected.Order)
+
+	require.NoError(t, checker.Check("key", headers, []models.CreateOrderRequest{riskOrder("a", "0.5", "20")}))
+	assert.Equal(t, "key", mock.Requests(mockupstream.CLOB)[0].Header.Get("POLY-API-KEY"), "open orders are the caller's")
+
+	mock.On(mockupstream.CLOB, "GET", "/orders/open", 500, `{}`)
+	assert.Equal(t, risk.CodeCheckUnavailable, riskCode(t, checker.Check("key", headers, []models.CreateOrderRequest{riskOrder("a", "0.5", "1")})).Code,
+		"fails closed when open orders cannot be read")
+}
+
+func TestRisk_DisabledAndPersisted(t *testing.T) {
+	checker, _ := newRiskChecker(t, &config.RiskConfig{Default: config.RiskLimits{MaxOrderSize: 1}})
+	assert.NoError(t, checker.Check("key", nil, []models.CreateOrderRequest{riskOrder("a", "0.5", "100")}))
+
+	cfg := &config.RiskConfig{
+		Enabled:  true,
+		Accounts: map[string]config.RiskLimits{"configured": {MaxOrderSize: 5}},
+		Path:     filepath.Join(t.TempDir(), "risk.json"),
+	}
+	checker, _ = newRiskChecker(t, cfg)
+	checker.SetDefault(config.RiskLimits{MaxOrderSize: 10})
+	checker.SetAccount("vip", config.RiskLimits{MaxOrderSize: 100})
+	assert.True(t, checker.DeleteAccount("configured"))
+	assert.False(t, checker.DeleteAccount("configured"))
+
+	restored, _ := newRiskChecker(t, cfg)
+	limits := restored.Limits()
+	assert.Equal(t, 10.0, limits.Default.MaxOrderSize)
+	assert.Equal(t, 100.0, limits.Accounts["vip"].MaxOrderSize)
+	assert.NotContains(t, limits.Accounts, "configured", "deletions survive a restart")
+}
@@ -0,0 +1,182 @@
+package unit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/orderrules"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/pretrade"
+	"github.com/polygo/internal/risk"
+	"github.com/polygo/internal/routing"
+	"github.com/polygo/internal/throttle"
+)
+
+func newRouter(t *testing.T, routingCfg *config.RoutingConfig) (*routing.Router, *mockupstream.Server, *config.Config) {
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	// Routing tests send the same order repeatedly
+	cfg.Throttle.Enabled = false
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	client := polymarket.NewClient(&cfg.Polymarket, c)
+	gamma := polymarket.NewGammaClient(client)
+	resolver := catalog.NewResolver(catalog.New(gamma, &cfg.Catalog), gamma)
+	clob := polymarket.NewClobClient(client)
+	checks := pretrade.New(orderrules.New(clob, resolver, &cfg.OrderRules), throttle.New(resolver, &cfg.Throttle), risk.New(clob, resolver, &cfg.Risk), &cfg.Auth)
+	router := routing.New(clob, polymarket.NewDataClient(client), polymarket.NewFillTracker(), checks, resolver, &cfg.Auth, routingCfg)
+	return router, mock, cfg
+}
+
+func routingAccounts() map[string]config.RoutingAccount {
+	return map[string]config.RoutingAccount{
+		"desk-a": {Address: "0xaaa", APIKey: "key-a", Secret: "c2VjcmV0", Passphrase: "pass"},
+		"desk-b": {Address: "0xbbb", APIKey: "key-b", Secret: "c2VjcmV0", Passphrase: "pass"},
+	}
+}
+
+func routedOrder(tokenID, strategy string) models.CreateOrderRequest {
+	return models.CreateOrderRequest{TokenID: tokenID, Side: models.SideBuy, Price: "0.5", Size: "10", Type: models.OrderTypeGTC, Strategy: strategy}
+}
+
+// placedFor returns the API key and maker of each order the CLOB received
+func placedFor(mock *mockupstream.Server, cfg *config.Config) [][2]string {
+	var out [][2]string
+	for _, r := range mock.Requests(mockupstream.CLOB) {
+		if r.Method == http.MethodPost && r.Path == "/order" {
+			var body struct {
+				Maker string `json:"maker"`
+			}
+			sonic.Unmarshal(r.Body, &body)
+			out = append(out, [2]string{r.Header.Get(cfg.Auth.APIKeyHeader), body.Maker})
+		}
+	}
+	return out
+}
+
+func TestRouting_RoutesByStrategyMarketAndRoundRobin(t *testing.T) {
+	router, mock, cfg := newRouter(t, &config.RoutingConfig{
+		Enabled:  true,
+		Accounts: routingAccounts(),
+		Routes: []config.OrderRoute{
+			{Strategy: "mm", Accounts: []string{"desk-b", "missing"}},
+			{Market: mockupstream.ConditionID, Accounts: []string{"desk-a"}},
+			{Accounts: []string{"desk-a", "desk-b"}},
+		},
+	})
+
+	p, err := router.Place(routedOrder(otherToken, "MM"))
+	require.NoError(t, err)
+	assert.Equal(t, "desk-b", p.Account)
+	assert.Equal(t, 0, p.Route)
+
+	p, err = router.Place(routedOrder(mockupstream.TokenNo, ""))
+	require.NoError(t, err)
+	assert.Equal(t, "desk-a", p.Account, "the token's market matches by condition ID")
+	assert.Equal(t, 1, p.Route)
+
+	var accounts []string
+	for i := 0; i < 3; i++ {
+		p, err := router.Place(routedOrder(otherToken, ""))
+		require.NoError(t, err)
+		assert.Equal(t, 2, p.Route)
+		accounts = append(accounts, p.Account)
+	}
+	assert.Equal(t, []string{"desk-a", "desk-b", "desk-a"}, accounts)
+
+	placed := placedFor(mock, cfg)
+	require.Len(t, placed, 5)
+	assert.Equal(t, [2]string{"key-b", "0xbbb"}, placed[0], "signed by and made for the routed account")
+	assert.Equal(t, [2]string{"key-a", "0xaaa"}, placed[1])
+
+	status := router.Status()
+	require.Len(t, status.Accounts, 2)
+	assert.Equal(t, "desk-a", status.Accounts[0].Name)
+	assert.Equal(t, int64(3), status.Accounts[0].Routed)
+	assert.Equal(t, []string{"desk-b"}, status.Routes[0].Accounts, "unknown accounts are dropped")
+}
+
+func TestRouting_SkipsAccountsAtTheirExposureLimit(t *testing.T) {
+	accounts := routingAccounts()
+	a := accounts["desk-a"]
+	a.MaxExposure = 100
+	accounts["desk-a"] = a
+	router, mock, cfg := newRouter(t, &config.RoutingConfig{
+		Enabled:  true,
+		Accounts: accounts,
+		Routes:   []config.OrderRoute{{Strategy: "mm", Accounts: []string{"desk-a"}}, {Accounts: []string{"desk-a", "desk-b"}}},
+	})
+	// desk-a has 97.5 USDC of open orders: 0.5 × (200 - 5)
+	mock.Handle(mockupstream.CLOB, "GET", "/orders/open", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(cfg.Auth.APIKeyHeader) == "key-a" {
+			w.Write([]byte(`[{"price":"0.5","original_size":"200","size_matched":"5"}]`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	})
+
+	small := routedOrder(otherToken, "")
+	small.Size = "5"
+	p, err := router.Place(small)
+	require.NoError(t, err)
+	assert.Equal(t, "desk-a", p.Account, "2.5 USDC still fits")
+
+	// desk-a has no room for the next orders: desk-b takes them
+	for i := 0; i < 2; i++ {
+		p, err = router.Place(routedOrder(otherToken, ""))
+		require.NoError(t, err)
+		assert.Equal(t, "desk-b", p.Account)
+	}
+
+	_, err = router.Place(routedOrder(otherToken, "mm"))
+	assert.ErrorIs(t, err, routing.ErrExposure)
+	assert.Equal(t, int64(2), router.Status().Accounts[0].Skipped)
+}
+
+func TestRouting_NoRoute(t *testing.T) {
+	router, mock, _ := newRouter(t, &config.RoutingConfig{
+		Enabled:  true,
+		Accounts: routingAccounts(),
+		Routes:   []config.OrderRoute{{Strategy: "mm", Accounts: []string{"desk-a"}}},
+	})
+
+	_, err := router.Place(routedOrder(otherToken, "arb"))
+	assert.ErrorIs(t, err, routing.ErrNoRoute)
+	assert.Empty(t, mock.Requests(mockupstream.CLOB))
+}
+
+func TestRouting_OrdersPassPreTradeChecksOfTheirAccount(t *testing.T) {
+	router, mock, cfg := newRouter(t, &config.RoutingConfig{
+		Enabled:  true,
+		Accounts: routingAccounts(),
+		Routes:   []config.OrderRoute{{Accounts: []string{"desk-a", "desk-b"}}},
+	})
+	cfg.Throttle.Enabled = true
+	cfg.Throttle.DuplicateWindow = time.Minute
+
+	// Round-robin: desk-a, desk-b, then desk-a sees its first order again
+	for i := 0; i < 2; i++ {
+		_, err := router.Place(routedOrder(otherToken, ""))
+		require.NoError(t, err)
+	}
+	_, err := router.Place(routedOrder(otherToken, ""))
+	var rejected *throttle.Error
+	require.ErrorAs(t, err, &rejected)
+	assert.Equal(t, throttle.CodeDuplicate, rejected.Code)
+	assert.Len(t, placedFor(mock, cfg), 2)
+}
@@ -0,0 +1,274 @@
+package unit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/eventbus"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/orderrules"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/pretrade"
+	"github.com/polygo/internal/risk"
+	"github.com/polygo/internal/rules"
+	"github.com/polygo/internal/throttle"
+	"github.com/polygo/internal/webhooks"
+	"github.com/polygo/pkg/polygoclient"
+)
+
+const ruleAccount = "0x3333333333333333333333333333333333333333"
+
+func newRuleEngine(t *testing.T, mutate func(*config.Config)) (*rules.Engine, *mockupstream.Server, *config.Config) {
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	cfg.Rules.Enabled = true
+	cfg.Rules.Path = filepath.Join(t.TempDir(), "rules.json")
+	cfg.Rules.Cooldown = time.Minute
+	cfg.Rules.Accounts = map[string]config.RuleAccount{
+		"main": {Address: ruleAccount, APIKey: "key", Secret: "c2VjcmV0", Passphrase: "pass"},
+	}
+	if mutate != nil {
+		mutate(cfg)
+	}
+
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	client := polymarket.NewClient(&cfg.Polymarket, c)
+
+	cat := catalog.New(nil, &config.CatalogConfig{})
+	cat.Load([]*models.Event{{
+		ID: "e1",
+		Markets: []models.Market{{
+			ID:           "m1",
+			ConditionID:  "0xcond",
+			Slug:         "will-it-rain",
+			Outcomes:     models.StringList{"Yes", "No"},
+			ClobTokenIDs: models.StringList{"yes-token", "no-token"},
+			Volume24hr:   "2500000",
+		}},
+	}})
+
+	bus, err := eventbus.New(polymarket.NewWSManager(&cfg.Polymarket), &cfg.EventBus, 100)
+	require.NoError(t, err)
+
+	dispatcher, err := webhooks.NewDispatcher(&cfg.Webhooks)
+	require.NoError(t, err)
+
+	clob := polymarket.NewClobClient(client)
+	checks := pretrade.New(orderrules.New(clob, nil, &cfg.OrderRules), throttle.New(nil, &cfg.Throttle), risk.New(clob, nil, &cfg.Risk), &cfg.Auth)
+	engine := rules.New(polymarket.NewWSManager(&cfg.Polymarket), cat, clob,
+		polymarket.NewDataClient(client), polymarket.NewFillTracker(), checks, dispatcher,
+		bus, &cfg.Auth, &cfg.Rules)
+	return engine, mock, cfg
+}
+
+// quote is a price change of a token with its new best bid and ask
+func quote(token string, bid, ask float64) eventbus.Event {
+	return eventbus.Event{
+		Type:    eventbus.TypePriceChange,
+		Market:  "0xcond",
+		AssetID: token,
+		Data:    eventbus.PriceChange{Price: bid, Size: 10, Side: "BUY", BestBid: &bid, BestAsk: &ask},
+	}
+}
+
+func TestRules_CompileRejectsInvalidConditions(t *testing.T) {
+	cases := map[string]string{
+		"volume_24h >":              "unexpected end",
+		"votes > 1":                 "unknown field",
+		"volume_24h > 'big'":        "compares a number with a string",
+		"volume_24h":                "not a comparison",
+		"price_change_2h > 0.1":     "looks back more than",
+		"slug == 'will-it-rain":     "unterminated string",
+		"price > 0.5 AND (side":     "expected ')'",
+		"price_change_fast > 0.1":   "needs a window",
+		"NOT side == 'BUY' OR 1 > ": "unexpected end",
+	}
+	for src, want := range cases {
+		_, err := rules.Compile(src, time.Hour)
+		if assert.Error(t, err, src) {
+			assert.Contains(t, err.Error(), want, src)
+		}
+	}
+
+	cond, err := rules.Compile(`volume_24h > 1e6 and abs(price_change_5m) >= 0.05 && !(side == "sell")`, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"volume_24h", "price_change_5m", "side"}, cond.Fields())
+}
+
+func TestRules_FiresOncePerCooldownAndPlacesOrders(t *testing.T) {
+	engine, mock, cfg := newRuleEngine(t, nil)
+
+	_, err := engine.Create(config.RuleSpec{
+		Name:      "rain-jump",
+		Condition: "volume_24h > 1e6 AND outcome == 'yes' AND price_change_5m > 0.05",
+		Actions: []config.RuleAction{
+			{Type: "log"},
+			{Type: "order", Account: "MAIN", Side: "buy", Size: 12.345},
+		},
+	})
+	require.NoError(t, err)
+	engine.Start()
+	t.Cleanup(engine.Stop)
+
+	base := time.Now()
+	engine.Evaluate([]eventbus.Event{quote("yes-token", 0.40, 0.42)}, base)
+	engine.Evaluate([]eventbus.Event{quote("no-token", 0.40, 0.42)}, base)
+	// Not 5 minutes of history yet
+	engine.Evaluate([]eventbus.Event{quote("yes-token", 0.60, 0.62)}, base.Add(time.Minute))
+	engine.Evaluate([]eventbus.Event{quote("yes-token", 0.50, 0.52)}, base.Add(5*time.Minute))
+	// Within the cooldown
+	engine.Evaluate([]eventbus.Event{quote("yes-token", 0.55, 0.57)}, base.Add(5*time.Minute+30*time.Second))
+
+	require.Eventually(t, func() bool {
+		r, err := engine.Get("rain-jump")
+		return err == nil && r.Fired == 1
+	}, time.Second, 10*time.Millisecond)
+
+	triggers, err := engine.Triggers("rain-jump")
+	require.NoError(t, err)
+	require.Len(t, triggers, 1)
+	trg := triggers[0]
+	assert.Equal(t, "0xcond", trg.Market)
+	assert.Equal(t, "yes-token", trg.AssetID)
+	assert.Equal(t, "Yes", trg.Values["outcome"])
+	assert.InDelta(t, 0.10, trg.Values["price_change_5m"], 1e-9, "midpoint 0.51 - 0.41")
+	require.Len(t, trg.Actions, 2)
+	assert.Equal(t, rules.StatusDone, trg.Actions[0].Status)
+	assert.Equal(t, rules.StatusDone, trg.Actions[1].Status)
+	assert.Equal(t, mockupstream.OrderID, trg.Actions[1].OrderID)
+
+	var posted []mockupstream.Request
+	for _, r := range mock.Requests(mockupstream.CLOB) {
+		if r.Method == "POST" && r.Path == "/order" {
+			posted = append(posted, r)
+		}
+	}
+	require.Len(t, posted, 1)
+
+	var order models.CreateOrderRequest
+	require.NoError(t, sonic.Unmarshal(posted[0].Body, &order))
+	assert.Equal(t, "yes-token", order.TokenID)
+	assert.Equal(t, models.SideBuy, order.Side)
+	assert.Equal(t, "0.52", order.Price, "bought at the best ask")
+	assert.Equal(t, "12.34", order.Size)
+	assert.Equal(t, ruleAccount, order.Maker)
+
+	h := posted[0].Header
+	creds := polygoclient.Credentials{APIKey: "key", Secret: "c2VjcmV0", Passphrase: "pass"}
+	assert.Equal(t, creds.Sign(h.Get(cfg.Auth.TimestampHeader), "POST", "/order", posted[0].Body), h.Get(cfg.Auth.SignatureHeader))
+}
+
+func TestRules_OrdersPassPreTradeChecks(t *testing.T) {
+	engine, mock, _ := newRuleEngine(t, func(c *config.Config) {
+		c.Risk.Enabled = true
+		c.Risk.Accounts = map[string]config.RiskLimits{"key": {MaxOrderSize: 10}}
+	})
+
+	_, err := engine.Create(config.RuleSpec{
+		Name:      "too-big",
+		Condition: "price > 0.5",
+		Actions:   []config.RuleAction{{Type: "order", Account: "main", Side: "BUY", Size: 12}},
+	})
+	require.NoError(t, err)
+	engine.Start()
+	t.Cleanup(engine.Stop)
+
+	engine.Evaluate([]eventbus.Event{quote("yes-token", 0.60, 0.62)}, time.Now())
+	require.Eventually(t, func() bool {
+		triggers, err := engine.Triggers("too-big")
+		return err == nil && len(triggers) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	triggers, _ := engine.Triggers("too-big")
+	require.Len(t, triggers[0].Actions, 1)
+	assert.Equal(t, rules.StatusFailed, triggers[0].Actions[0].Status)
+	assert.Contains(t, triggers[0].Actions[0].Error, "exceeds the limit of 10")
+	for _, r := range mock.Requests(mockupstream.CLOB) {
+		assert.False(t, r.Method == "POST" && r.Path == "/order", "the order is never sent")
+	}
+}
+
+func TestRules_ValidatesRules(t *testing.T) {
+	engine, _, _ := newRuleEngine(t, func(c *config.Config) { c.Rules.MaxRules = 2 })
+
+	invalid := []config.RuleSpec{
+		{Name: "bad name!", Condition: "price > 0.5", Actions: []config.RuleAction{{Type: "log"}}},
+		{Name: "no-actions", Condition: "price > 0.5"},
+		{Name: "bad-cooldown", Condition: "price > 0.5", Cooldown: "soon", Actions: []config.RuleAction{{Type: "log"}}},
+		{Name: "bad-type", Condition: "price > 0.5", Actions: []config.RuleAction{{Type: "email"}}},
+		{Name: "no-bus", Condition: "price > 0.5", Actions: []config.RuleAction{{Type: "publish", Topic: "alerts"}}},
+		{Name: "no-account", Condition: "price > 0.5", Actions: []config.RuleAction{{Type: "order", Account: "other", Side: "BUY", Size: 1}}},
+		{Name: "bad-price", Condition: "price > 0.5", Actions: []config.RuleAction{{Type: "order", Account: "main", Side: "BUY", Size: 1, Price: 1.5}}},
+	}
+	for _, spec := range invalid {
+		_, err := engine.Create(spec)
+		assert.ErrorIs(t, err, rules.ErrInvalidRule, spec.Name)
+	}
+
+	spec := config.RuleSpec{Name: "a", Condition: "price > 0.5", Actions: []config.RuleAction{{Type: "log"}}}
+	_, err := engine.Create(spec)
+	require.NoError(t, err)
+	_, err = engine.Create(spec)
+	assert.ErrorIs(t, err, rules.ErrExists)
+
+	spec.Name = "b"
+	_, err = engine.Create(spec)
+	require.NoError(t, err)
+	spec.Name = "c"
+	_, err = engine.Create(spec)
+	assert.ErrorIs(t, err, rules.ErrTooManyRules)
+
+	_, err = engine.Update("a", config.RuleSpec{Name: "z", Condition: "price > 0.5", Actions: []config.RuleAction{{Type: "log"}}})
+	assert.ErrorIs(t, err, rules.ErrInvalidRule, "renames are rejected")
+	assert.ErrorIs(t, engine.Delete("c"), rules.ErrNotFound)
+}
+
+func TestRules_SavedRulesReplaceConfiguredOnes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	configured := []config.RuleSpec{
+		{Name: "from-config", Condition: "price > 0.9", Actions: []config.RuleAction{{Type: "log"}}},
+		{Name: "broken", Condition: "votes > 1", Actions: []config.RuleAction{{Type: "log"}}},
+	}
+	setup := func(c *config.Config) {
+		c.Rules.Path = path
+		c.Rules.Rules = configured
+	}
+
+	engine, _, _ := newRuleEngine(t, setup)
+	list := engine.List()
+	require.Len(t, list, 1, "invalid configured rules are skipped")
+	assert.Equal(t, "from-config", list[0].Name)
+
+	_, err := engine.Create(config.RuleSpec{Name: "added", Condition: "spread > 0.1", Cooldown: "30s", Actions: []config.RuleAction{{Type: "LOG"}}})
+	require.NoError(t, err)
+	updated, err := engine.Update("added", config.RuleSpec{Condition: "spread > 0.2", Disabled: true, Actions: []config.RuleAction{{Type: "log"}}})
+	require.NoError(t, err)
+	assert.Equal(t, "added", updated.Name)
+	assert.True(t, updated.Disabled)
+	require.NoError(t, engine.Delete("from-config"))
+
+	reloaded, _, _ := newRuleEngine(t, setup)
+	list = reloaded.List()
+	require.Len(t, list, 1)
+	assert.Equal(t, "added", list[0].Name)
+	assert.Equal(t, "spread > 0.2", list[0].Condition)
+	assert.Equal(t, []string{"spread"}, list[0].Fields)
+	assert.True(t, list[0].Disabled)
+
+	_, err = reloaded.Get("from-config")
+	assert.ErrorIs(t, err, rules.ErrNotFound)
+}
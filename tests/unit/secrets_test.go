@@ -0,0 +1,175 @@
+package unit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/secrets"
+)
+
+// newVaultServer serves a KV v2 secret at secret/data/polygo and a KV v1
+// one at kv/polygo, for the token "vault-token"
+func newVaultServer(t *testing.T, adminToken *atomic.Value) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/polygo":
+			io.WriteString(w, `{"data":{"data":{"admin_token":"`+adminToken.Load().(string)+`"},"metadata":{"version":1}}}`)
+		case "/v1/kv/polygo":
+			io.WriteString(w, `{"data":{"jwt_secret":"kv1-secret"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSecrets_ResolveVault(t *testing.T) {
+	token := &atomic.Value{}
+	token.Store("from-vault")
+	srv := newVaultServer(t, token)
+
+	cfg := config.DefaultConfig()
+	cfg.Secrets.VaultAddr = srv.URL
+	cfg.Secrets.VaultToken = "vault-token"
+	cfg.Admin.Token = "vault://secret/data/polygo#admin_token"
+	cfg.Access.JWTSecret = "vault://kv/polygo#jwt_secret"
+	cfg.Routing.Accounts = map[string]config.RoutingAccount{
+		"desk-a": {Address: "0xabc", Secret: "vault://secret/data/polygo#admin_token"},
+	}
+
+	refs, err := secrets.Resolve(cfg)
+	require.NoError(t, err)
+	assert.Len(t, refs, 3)
+	assert.Equal(t, "from-vault", cfg.Admin.Token)
+	assert.Equal(t, "kv1-secret", cfg.Access.JWTSecret)
+	assert.Equal(t, "from-vault", cfg.Routing.Accounts["desk-a"].Secret)
+	assert.Equal(t, "0xabc", cfg.Routing.Accounts["desk-a"].Address)
+}
+
+func TestSecrets_ResolveErrorNamesSetting(t *testing.T) {
+	token := &atomic.Value{}
+	token.Store("from-vault")
+	srv := newVaultServer(t, token)
+
+	cfg := config.DefaultConfig()
+	cfg.Secrets.VaultAddr = srv.URL
+	cfg.Secrets.VaultToken = "vault-token"
+	cfg.Admin.Token = "vault://secret/data/polygo#missing"
+	_, err := secrets.Resolve(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "admin.token")
+
+	cfg.Admin.Token = "vault://secret/data/polygo"
+	_, err = secrets.Resolve(cfg)
+	assert.ErrorIs(t, err, secrets.ErrInvalidReference)
+
+	cfg.Admin.Token = "vault://secret/data/polygo#admin_token"
+	cfg.Secrets.VaultToken = "wrong"
+	_, err = secrets.Resolve(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+}
+
+func TestSecrets_ResolveAWS(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") ||
+			!strings.Contains(auth, "/us-east-1/secretsmanager/aws4_request") ||
+			!strings.Contains(auth, "SignedHeaders=content-type;host;x-amz-date;x-amz-target") ||
+			r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		switch string(body) {
+		case `{"SecretId":"polygo/plain"}`:
+			io.WriteString(w, `{"Name":"polygo/plain","SecretString":"plain-value"}`)
+		case `{"SecretId":"polygo/desk-a"}`:
+			io.WriteString(w, `{"Name":"polygo/desk-a","SecretString":"{\"api_key\":\"k\",\"secret\":\"s\"}"}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"__type":"ResourceNotFoundException"}`)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.Secrets.AWSRegion = "us-east-1"
+	cfg.Secrets.AWSEndpoint = srv.URL
+	cfg.Secrets.AWSAccessKeyID = "AKID"
+	cfg.Secrets.AWSSecretAccessKey = "SECRET"
+	cfg.Replication.Token = "aws-sm://polygo/plain"
+	cfg.Routing.Accounts = map[string]config.RoutingAccount{
+		"desk-a": {APIKey: "aws-sm://polygo/desk-a#api_key", Secret: "aws-sm://polygo/desk-a#secret"},
+	}
+
+	_, err := secrets.Resolve(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "plain-value", cfg.Replication.Token)
+	assert.Equal(t, "k", cfg.Routing.Accounts["desk-a"].APIKey)
+	assert.Equal(t, "s", cfg.Routing.Accounts["desk-a"].Secret)
+
+	r := secrets.NewResolver(&cfg.Secrets)
+	_, err = r.Resolve(context.Background(), "aws-sm://polygo/missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ResourceNotFoundException")
+	_, err = r.Resolve(context.Background(), "aws-sm://polygo/plain#key")
+	assert.Error(t, err, "a key needs a JSON secret")
+}
+
+func TestSecrets_NoReferences(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Admin.Token = "plain"
+	refs, err := secrets.Resolve(cfg)
+	require.NoError(t, err)
+	assert.Empty(t, refs)
+	assert.Equal(t, "plain", cfg.Admin.Token)
+}
+
+func TestSecrets_WatchReportsRotation(t *testing.T) {
+	token := &atomic.Value{}
+	token.Store("v1")
+	srv := newVaultServer(t, token)
+
+	cfg := config.DefaultConfig()
+	cfg.Secrets.VaultAddr = srv.URL
+	cfg.Secrets.VaultToken = "vault-token"
+	cfg.Secrets.RefreshInterval = 20 * time.Millisecond
+	cfg.Admin.Token = "vault://secret/data/polygo#admin_token"
+	refs, err := secrets.Resolve(cfg)
+	require.NoError(t, err)
+
+	rotated := make(chan []string, 1)
+	w := secrets.Watch(&cfg.Secrets, refs, func(keys []string) { rotated <- keys })
+	defer w.Stop()
+
+	select {
+	case <-rotated:
+		t.Fatal("reported a rotation before the secret changed")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	token.Store("v2")
+	select {
+	case keys := <-rotated:
+		assert.Equal(t, []string{"admin.token"}, keys)
+	case <-time.After(2 * time.Second):
+		t.Fatal("rotation not reported")
+	}
+	assert.Equal(t, "v1", cfg.Admin.Token, "settings are not changed by a rotation")
+}
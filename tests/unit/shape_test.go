@@ -0,0 +1,204 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/catalog"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/shape"
+)
+
+func TestShape_MarketZipsOutcomes(t *testing.T) {
+	data := []byte(`{"id":"1","conditionId":"0xc","question":"Q?","volume":"1500.5","volume24hr":12,
+		"liquidity":"300","bestBid":0.49,"endDate":"2030-01-01T00:00:00Z","acceptingOrders":true,
+		"outcomes":"[\"Yes\", \"No\"]","outcomePrices":"[\"0.6\", \"0.4\"]","clobTokenIds":"[\"t1\", \"t2\"]"}`)
+
+	v, err := shape.MarketOne(data)
+	require.NoError(t, err)
+	m := v.(shape.Market)
+
+	assert.Equal(t, "0xc", m.ConditionID)
+	assert.Equal(t, 1500.5, m.Volume)
+	assert.Equal(t, 12.0, m.Volume24h)
+	assert.Equal(t, 300.0, m.Liquidity)
+	require.NotNil(t, m.BestBid)
+	assert.Equal(t, 0.49, *m.BestBid)
+	assert.Nil(t, m.BestAsk, "missing prices are null, not 0")
+	assert.True(t, m.AcceptingOrders)
+	assert.Equal(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), *m.EndDate)
+
+	require.Len(t, m.Outcomes, 2)
+	assert.Equal(t, "No", m.Outcomes[1].Name)
+	assert.Equal(t, "t2", m.Outcomes[1].TokenID)
+	assert.Equal(t, 0.4, *m.Outcomes[1].Price)
+
+	// Already-normalized payloads shape the same way
+	v, err = shape.MarketOne([]byte(`{"id":"1","outcomes":["Yes","No"],"outcomePrices":[0.6,0.4],"clobTokenIds":["t1","t2"]}`))
+	require.NoError(t, err)
+	assert.Equal(t, m.Outcomes, v.(shape.Market).Outcomes)
+}
+
+func TestShape_LookupsUnwrapOrNotFound(t *testing.T) {
+	v, err := shape.MarketOne([]byte(`[{"id":"7"}]`))
+	require.NoError(t, err)
+	assert.Equal(t, "7", v.(shape.Market).ID)
+
+	_, err = shape.MarketOne([]byte(`[]`))
+	assert.ErrorIs(t, err, shape.ErrNotFound)
+	_, err = shape.EventOne([]byte(`null`))
+	assert.ErrorIs(t, err, shape.ErrNotFound)
+
+	_, err = shape.Markets([]byte(`{not json`))
+	assert.Error(t, err)
+}
+
+func TestShape_CatalogEntry(t *testing.T) {
+	entry := &catalog.Entry{
+		Market:     models.Market{ID: "1", Volume24hr: "42", Outcomes: models.StringList{"Yes"}},
+		EventID:    "e1",
+		EventTitle: "Event",
+		Tags:       []models.Tag{{ID: "3", Slug: "politics", Label: "Politics"}},
+		Category:   "politics",
+	}
+	data, err := sonic.Marshal([]*catalog.Entry{entry})
+	require.NoError(t, err)
+
+	v, err := shape.Markets(data)
+	require.NoError(t, err)
+	markets := v.([]shape.Market)
+	require.Len(t, markets, 1)
+	assert.Equal(t, "e1", markets[0].EventID)
+	assert.Equal(t, "Event", markets[0].EventTitle)
+	assert.Equal(t, 42.0, markets[0].Volume24h)
+	assert.Equal(t, []shape.Tag{{ID: "3", Slug: "politics", Label: "Politics"}}, markets[0].Tags)
+	assert.Nil(t, markets[0].EndDate, "an unset Go time is not year 1")
+	assert.Nil(t, markets[0].FirstSeen)
+}
+
+func TestShape_BookBestFirst(t *testing.T) {
+	data := []byte(`{"market":"0xc","asset_id":"t1","timestamp":"1700000000000","implied_probability":0.5,
+		"bids":[{"price":"0.48","size":"200"},{"price":"0.49","size":"100"}],
+		"asks":[{"price":"0.52","size":"200"},{"price":"0.51","size":"100"}]}`)
+
+	v, err := shape.Book(data)
+	require.NoError(t, err)
+	book := v.(shape.OrderBook)
+
+	assert.Equal(t, "t1", book.TokenID)
+	assert.Equal(t, "0xc", book.ConditionID)
+	assert.Equal(t, []shape.Level{{Price: 0.49, Size: 100}, {Price: 0.48, Size: 200}}, book.Bids)
+	assert.Equal(t, []shape.Level{{Price: 0.51, Size: 100}, {Price: 0.52, Size: 200}}, book.Asks)
+	assert.Equal(t, 0.5, *book.ImpliedProbability)
+	assert.Nil(t, book.LastTradePrice)
+	assert.Equal(t, time.UnixMilli(1700000000000).UTC(), *book.Timestamp)
+}
+
+func TestShape_TokenMapsBecomeSortedLists(t *testing.T) {
+	v, err := shape.Prices([]byte(`{"b":{"SELL":"0.4","BUY":"0.6"},"a":{"BUY":0.1}}`))
+	require.NoError(t, err)
+	prices := v.([]shape.Price)
+	require.Len(t, prices, 3)
+	assert.Equal(t, []string{"a/BUY", "b/BUY", "b/SELL"}, []string{
+		prices[0].TokenID + "/" + prices[0].Side,
+		prices[1].TokenID + "/" + prices[1].Side,
+		prices[2].TokenID + "/" + prices[2].Side,
+	})
+	assert.Equal(t, 0.4, *prices[2].Price)
+
+	// Prices a format cannot express stay null
+	v, err = shape.Midpoints([]byte(`{"t2":"0.5","t1":null}`))
+	require.NoError(t, err)
+	mids := v.([]shape.Midpoint)
+	require.Len(t, mids, 2)
+	assert.Equal(t, "t1", mids[0].TokenID)
+	assert.Nil(t, mids[0].Mid)
+	assert.Equal(t, 0.5, *mids[1].Mid)
+
+	v, err = shape.TokenMidpoint("t1")([]byte(`{"mid":"0.55","implied_probability":0.55}`))
+	require.NoError(t, err)
+	assert.Equal(t, "t1", v.(shape.Midpoint).TokenID)
+	assert.Equal(t, 0.55, *v.(shape.Midpoint).ImpliedProbability)
+}
+
+func TestShape_PriceHistory(t *testing.T) {
+	v, err := shape.PriceHistory([]byte(`{"history":[{"t":1700000000,"p":0.5},{"t":"bad","p":1},{"t":1700000060,"p":"0.52"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []shape.PricePoint{
+		{Time: time.Unix(1700000000, 0).UTC(), Price: 0.5},
+		{Time: time.Unix(1700000060, 0).UTC(), Price: 0.52},
+	}, v)
+}
+
+func TestShape_OrdersAndResults(t *testing.T) {
+	v, err := shape.Orders([]byte(`{"data":[{"id":"o1","status":"live","market":"0xc","asset_id":"t1","side":"buy",
+		"original_size":"10","size_matched":"2.5","price":"0.5","order_type":"GTC","created_at":1700000000,"expiration":"0"}],
+		"next_cursor":"LTE="}`))
+	require.NoError(t, err)
+	orders := v.([]shape.Order)
+	require.Len(t, orders, 1)
+	o := orders[0]
+	assert.Equal(t, "LIVE", o.Status)
+	assert.Equal(t, "BUY", o.Side)
+	assert.Equal(t, "t1", o.TokenID)
+	assert.Equal(t, 10.0, o.Size)
+	assert.Equal(t, 2.5, o.SizeMatched)
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), *o.CreatedAt)
+	assert.Nil(t, o.ExpiresAt, "expiration 0 means none")
+
+	v, err = shape.Placed([]byte(`{"success":false,"errorMsg":"not enough balance","orderID":"","status":""}`))
+	require.NoError(t, err)
+	assert.Equal(t, shape.OrderResult{Error: "not enough balance"}, v)
+
+	v, err = shape.Cancelled([]byte(`{"canceled":["o1"],"not_canceled":{"o3":"already matched","o2":"not found"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, shape.CancelResult{
+		Cancelled:    []string{"o1"},
+		NotCancelled: []shape.CancelFailure{{OrderID: "o2", Reason: "not found"}, {OrderID: "o3", Reason: "already matched"}},
+	}, v)
+}
+
+func TestShape_TradesFromEitherAPI(t *testing.T) {
+	// The CLOB's trade history and the Data API's trades use different names
+	v, err := shape.Trades([]byte(`[
+		{"id":"x","asset_id":"t1","market":"0xc","side":"SELL","price":"0.4","size":"5","status":"CONFIRMED","match_time":"1700000000","transaction_hash":"0xh"},
+		{"proxyWallet":"0xABC","asset":"t1","conditionId":"0xc","side":"BUY","price":0.6,"size":3,"timestamp":1700000060,"transactionHash":"0xi","confirmed":true}
+	]`))
+	require.NoError(t, err)
+	trades := v.([]shape.Trade)
+	require.Len(t, trades, 2)
+
+	for _, tr := range trades {
+		assert.Equal(t, "t1", tr.TokenID)
+		assert.Equal(t, "0xc", tr.ConditionID)
+		assert.NotNil(t, tr.Time)
+	}
+	assert.Equal(t, "0xh", trades[0].TransactionHash)
+	assert.Nil(t, trades[0].Confirmed)
+	assert.Equal(t, "0xabc", trades[1].Address)
+	assert.Equal(t, 0.6, trades[1].Price)
+	require.NotNil(t, trades[1].Confirmed)
+	assert.True(t, *trades[1].Confirmed)
+}
+
+func TestShape_PositionsAndLeaderboard(t *testing.T) {
+	v, err := shape.Positions([]byte(`[{"proxyWallet":"0xA","asset":"t1","conditionId":"0xc","size":"10","avgPrice":0.4,
+		"curPrice":0.5,"currentValue":5,"cashPnl":1,"redeemable":false,"outcome":"Yes"}]`))
+	require.NoError(t, err)
+	positions := v.([]shape.Position)
+	require.Len(t, positions, 1)
+	assert.Equal(t, "0xa", positions[0].Address)
+	assert.Equal(t, 10.0, positions[0].Size)
+	assert.Equal(t, 0.5, *positions[0].CurrentPrice)
+	assert.Equal(t, 1.0, positions[0].CashPnL)
+
+	v, err = shape.Leaderboard([]byte(`[{"proxyWallet":"0xB","userName":"b","pnl":"12.5","vol":100}]`))
+	require.NoError(t, err)
+	out, err := sonic.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"rank":1,"address":"0xb","name":"b","pnl":12.5,"volume":100}]`, string(out))
+}
@@ -0,0 +1,123 @@
+package unit
+
+import (
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/api/middleware"
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/pkg/response"
+)
+
+// newShedApp serves /hold/:class until release is closed
+func newShedApp(t *testing.T, cfg config.ConcurrencyConfig) (*fiber.App, *middleware.Shedder, chan struct{}) {
+	shedder := middleware.NewShedder(&cfg, func(c *fiber.Ctx) string { return c.Params("class") })
+	release := make(chan struct{})
+
+	app := fiber.New()
+	app.Get("/hold/:class", shedder.Limit(nil), func(c *fiber.Ctx) error {
+		<-release
+		return c.SendString("ok")
+	})
+	return app, shedder, release
+}
+
+// holdRequests starts n requests that stay in flight until release
+func holdRequests(t *testing.T, app *fiber.App, shedder *middleware.Shedder, path string, n int) *sync.WaitGroup {
+	var wg sync.WaitGroup
+	before := shedder.Stats().InFlight
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			app.Test(httptest.NewRequest("GET", path, nil), -1)
+		}()
+	}
+	require.Eventually(t, func() bool { return shedder.Stats().InFlight == before+int64(n) }, time.Second, time.Millisecond)
+	return &wg
+}
+
+func TestShedder_RejectsBeyondMaxInFlight(t *testing.T) {
+	app, shedder, release := newShedApp(t, config.ConcurrencyConfig{MaxInFlight: 2, RetryAfter: 2 * time.Second})
+	wg := holdRequests(t, app, shedder, "/hold/reads", 2)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/hold/reads", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 503, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("Retry-After"))
+	var body response.Response
+	require.NoError(t, sonic.ConfigDefault.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "OVERLOADED", body.Error.Code)
+
+	close(release)
+	wg.Wait()
+	stats := shedder.Stats()
+	assert.Equal(t, int64(0), stats.InFlight)
+	assert.Equal(t, int64(1), stats.Rejected)
+}
+
+func TestShedder_LimitsEachRouteClass(t *testing.T) {
+	app, shedder, release := newShedApp(t, config.ConcurrencyConfig{Classes: map[string]int{"trading": 1}})
+	wg := holdRequests(t, app, shedder, "/hold/trading", 1)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/hold/trading", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 503, resp.StatusCode)
+
+	wg2 := holdRequests(t, app, shedder, "/hold/reads", 1)
+	assert.Equal(t, int64(2), shedder.Stats().InFlight, "other classes are not limited")
+
+	close(release)
+	wg.Wait()
+	wg2.Wait()
+	assert.Equal(t, int64(1), shedder.Stats().Classes["trading"].Rejected)
+}
+
+func TestShedder_ServesCachedReadsUnderLoad(t *testing.T) {
+	app, shedder, release := newShedApp(t, config.ConcurrencyConfig{MaxInFlight: 10, ShedAt: 0.2})
+	assert.False(t, shedder.Shed())
+
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	client := polymarket.NewClient(&cfg.Polymarket, c)
+	client.SetShedder(shedder.Shed)
+
+	url := client.CLOB("/price?token_id=1&side=BUY")
+	_, cached, err := client.GetWithCache(url, "price:cached", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, cached)
+	c.Wait()
+
+	wg := holdRequests(t, app, shedder, "/hold/reads", 2)
+
+	_, cached, err = client.GetWithCache(url, "price:cached", time.Minute)
+	require.NoError(t, err, "cached reads are still served")
+	assert.True(t, cached)
+
+	_, _, err = client.GetWithCache(url, "price:missing", time.Minute)
+	assert.ErrorIs(t, err, polymarket.ErrOverloaded)
+	apiErr := polymarket.ClassifyError(err)
+	require.NotNil(t, apiErr)
+	assert.Equal(t, 503, apiErr.Status)
+	assert.True(t, apiErr.Retryable)
+
+	close(release)
+	wg.Wait()
+	assert.False(t, shedder.Shed())
+	assert.Equal(t, int64(1), shedder.Stats().Shed)
+}
@@ -0,0 +1,148 @@
+package unit
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/polymarket"
+)
+
+func newStampedeClient(t *testing.T, staleFor time.Duration) (*polymarket.Client, *cache.Cache, *mockupstream.Server) {
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+	cfg := config.DefaultConfig()
+	cfg.Cache.TTLJitter = 0
+	cfg.Cache.StaleFor = staleFor
+	mock.Apply(&cfg.Polymarket)
+
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	return polymarket.NewClient(&cfg.Polymarket, c), c, mock
+}
+
+func TestCache_JitterSpreadsExpiry(t *testing.T) {
+	cfg := config.DefaultConfig().Cache
+	cfg.TTLJitter = 0.5
+	cfg.StaleFor = time.Minute
+	c, err := cache.New(&cfg)
+	require.NoError(t, err)
+	defer c.Close()
+
+	for i := 0; i < 50; i++ {
+		c.Set(fmt.Sprintf("key-%d", i), []byte("v"), 200*time.Millisecond)
+	}
+	c.Wait()
+
+	// TTLs fall between 100ms and 300ms
+	time.Sleep(200 * time.Millisecond)
+	fresh := 0
+	for i := 0; i < 50; i++ {
+		_, ok, found := c.GetStale(fmt.Sprintf("key-%d", i))
+		require.True(t, found, "stale entries are kept")
+		if ok {
+			fresh++
+		}
+	}
+	assert.Greater(t, fresh, 0)
+	assert.Less(t, fresh, 50)
+}
+
+func TestCache_StaleEntriesAreKeptForStaleFor(t *testing.T) {
+	cfg := config.DefaultConfig().Cache
+	cfg.TTLJitter = 0
+	cfg.StaleFor = time.Minute
+	c, err := cache.New(&cfg)
+	require.NoError(t, err)
+	defer c.Close()
+
+	c.Set("key", []byte("value"), 20*time.Millisecond)
+	c.Wait()
+	time.Sleep(30 * time.Millisecond)
+
+	_, found := c.Get("key")
+	assert.False(t, found, "Get does not return stale entries")
+	data, fresh, found := c.GetStale("key")
+	assert.True(t, found)
+	assert.False(t, fresh)
+	assert.Equal(t, []byte("value"), data)
+}
+
+func TestCache_OneRefresherPerKey(t *testing.T) {
+	c, err := cache.New(&config.DefaultConfig().Cache)
+	require.NoError(t, err)
+	defer c.Close()
+
+	assert.True(t, c.BeginRefresh("key"))
+	assert.False(t, c.BeginRefresh("key"))
+	assert.True(t, c.BeginRefresh("other"))
+
+	c.EndRefresh("key")
+	assert.True(t, c.BeginRefresh("key"))
+}
+
+func TestGetWithCache_ConcurrentMissesShareOneRequest(t *testing.T) {
+	client, _, mock := newStampedeClient(t, time.Second)
+	mock.On(mockupstream.CLOB, "GET", "/book", 200, `{"bids":[],"asks":[]}`).Delay(50 * time.Millisecond)
+	url := client.CLOB("/book?token_id=1")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := client.GetWithCache(url, "book:1", time.Minute)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, mock.Requests(mockupstream.CLOB), 1)
+}
+
+func TestGetWithCache_ServesStaleWhileRefreshing(t *testing.T) {
+	client, c, mock := newStampedeClient(t, time.Minute)
+	url := client.CLOB("/book?token_id=1")
+
+	_, _, err := client.GetWithCache(url, "book:1", 20*time.Millisecond)
+	require.NoError(t, err)
+	c.Wait()
+	time.Sleep(30 * time.Millisecond)
+
+	// Another caller is refreshing the key
+	require.True(t, c.BeginRefresh("book:1"))
+	_, cached, err := client.GetWithCache(url, "book:1", 20*time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, cached, "stale data is served")
+	assert.Len(t, mock.Requests(mockupstream.CLOB), 1)
+
+	c.EndRefresh("book:1")
+	_, cached, err = client.GetWithCache(url, "book:1", 20*time.Millisecond)
+	require.NoError(t, err)
+	assert.False(t, cached, "the lock holder refreshes")
+	assert.Len(t, mock.Requests(mockupstream.CLOB), 2)
+}
+
+func TestGetWithCache_FailedRefreshServesStale(t *testing.T) {
+	client, c, mock := newStampedeClient(t, time.Minute)
+	url := client.CLOB("/book?token_id=1")
+
+	want, _, err := client.GetWithCache(url, "book:1", 20*time.Millisecond)
+	require.NoError(t, err)
+	c.Wait()
+	time.Sleep(30 * time.Millisecond)
+
+	mock.On(mockupstream.CLOB, "GET", "/book", 404, `{"error":"not found"}`)
+	data, cached, err := client.GetWithCache(url, "book:1", 20*time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, cached)
+	assert.Equal(t, want, data)
+}
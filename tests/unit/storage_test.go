@@ -0,0 +1,173 @@
+package unit
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/recorder"
+	"github.com/polygo/internal/storage"
+)
+
+func TestStorage_LocalRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	store := storage.NewLocal()
+	key := filepath.ToSlash(dir) + "/nested/a.json"
+
+	require.NoError(t, store.Put(key, []byte(`{"a":1}`)))
+	data, err := store.Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(data))
+	assert.Equal(t, filepath.Join(dir, "nested", "a.json"), store.Location(key))
+
+	ok, err := store.Exists(filepath.ToSlash(dir) + "/nested")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = store.Exists(filepath.ToSlash(dir) + "/missing")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = store.Get(filepath.ToSlash(dir) + "/missing.json")
+	assert.True(t, errors.Is(err, storage.ErrNotExist))
+}
+
+// fakeBucket is a minimal S3-compatible server keeping objects by path
+type fakeBucket struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	auth    []string
+}
+
+func (f *fakeBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+
+	switch {
+	case r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		if strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+			body = unchunk(body)
+		}
+		f.objects[r.URL.Path] = body
+	case r.URL.Query().Get("list-type") == "2":
+		prefix := strings.TrimSuffix(r.URL.Path, "/") + "/" + r.URL.Query().Get("prefix")
+		io.WriteString(w, "<ListBucketResult>")
+		for name := range f.objects {
+			if strings.HasPrefix(name, prefix) {
+				io.WriteString(w, "<Contents><Key>"+name+"</Key></Contents>")
+			}
+		}
+		io.WriteString(w, "</ListBucketResult>")
+	default:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Write(body)
+	}
+}
+
+// unchunk decodes an aws-chunked body, sent by clients signing each chunk
+// over plain HTTP: "<hex size>;chunk-signature=...\r\n<data>\r\n" until a
+// chunk of size 0
+func unchunk(body []byte) []byte {
+	var out []byte
+	for {
+		header, rest, _ := strings.Cut(string(body), "\r\n")
+		size, _ := strconv.ParseInt(strings.SplitN(header, ";", 2)[0], 16, 64)
+		if size == 0 {
+			return out
+		}
+		out = append(out, rest[:size]...)
+		body = []byte(rest[size+2:])
+	}
+}
+
+func TestStorage_BucketRoundTrip(t *testing.T) {
+	fake := &fakeBucket{objects: map[string][]byte{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	store, err := storage.New(&config.StorageConfig{
+		Backend:         storage.BackendS3,
+		Endpoint:        srv.URL,
+		Region:          "us-east-1",
+		Bucket:          "artifacts",
+		Prefix:          "/polygo/",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		Timeout:         5 * time.Second,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put("./data/exports/job 1/a.csv", []byte("x,y\n")))
+	assert.Contains(t, fake.objects, "/artifacts/polygo/data/exports/job 1/a.csv")
+	assert.Equal(t, "s3://artifacts/polygo/data/exports/job 1/a.csv", store.Location("./data/exports/job 1/a.csv"))
+	require.NotEmpty(t, fake.auth)
+	assert.True(t, strings.HasPrefix(fake.auth[0], "AWS4-HMAC-SHA256 Credential=AKID/"))
+
+	data, err := store.Get("./data/exports/job 1/a.csv")
+	require.NoError(t, err)
+	assert.Equal(t, "x,y\n", string(data))
+
+	_, err = store.Get("data/missing.csv")
+	assert.True(t, errors.Is(err, storage.ErrNotExist))
+
+	ok, err := store.Exists("./data/exports")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = store.Exists("./data/export")
+	require.NoError(t, err)
+	assert.False(t, ok, "a directory is not matched by a longer name")
+}
+
+func TestStorage_NewValidatesBackends(t *testing.T) {
+	store, err := storage.New(&config.StorageConfig{})
+	require.NoError(t, err)
+	assert.IsType(t, &storage.Local{}, store)
+
+	_, err = storage.New(&config.StorageConfig{Backend: "ftp"})
+	assert.Error(t, err)
+	_, err = storage.New(&config.StorageConfig{Backend: storage.BackendGCS, Bucket: "b"})
+	assert.Error(t, err, "bucket backends need access keys")
+	_, err = storage.New(&config.StorageConfig{Backend: storage.BackendS3, Endpoint: "https://minio.internal/s3", Bucket: "b", AccessKeyID: "k", SecretAccessKey: "s"})
+	assert.Error(t, err, "endpoints are a scheme and host")
+
+	store, err = storage.New(&config.StorageConfig{Backend: storage.BackendGCS, Bucket: "b", AccessKeyID: "k", SecretAccessKey: "s"})
+	require.NoError(t, err)
+	assert.Equal(t, "gs://b/tape/ws.jsonl", store.Location("./tape/ws.jsonl"))
+}
+
+func TestRecorder_SnapshotsCandlesToStorage(t *testing.T) {
+	cfg := &config.RecorderConfig{
+		Resolution: time.Minute,
+		Retention:  time.Hour,
+		Path:       filepath.ToSlash(t.TempDir()) + "/candles.json",
+	}
+	now := time.Now().Truncate(time.Minute)
+
+	rec := recorder.New(nil, storage.NewLocal(), cfg)
+	rec.Record("m1", "Q?", "q", 0.5, 100, now.Add(-2*time.Minute))
+	rec.Record("m1", "Q?", "q", 0.6, 110, now)
+	rec.Stop()
+
+	restored := recorder.New(nil, storage.NewLocal(), cfg)
+	candles := restored.Candles("m1", now.Add(-time.Hour))
+	require.Len(t, candles, 2)
+	assert.Equal(t, 0.6, candles[1].Close)
+	assert.True(t, restored.Ready())
+}
@@ -0,0 +1,106 @@
+package unit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/fillnotify"
+	"github.com/polygo/internal/strategies"
+)
+
+func strategyOrder(id, owner, strategy, side string, price, size float64) strategies.Order {
+	return strategies.Order{ID: id, Owner: owner, Strategy: strategy, TokenID: "tok", Side: side, Price: price, Size: size, PlacedAt: time.Unix(1700000000, 0)}
+}
+
+func TestStrategies_AttributesFillsAndPnL(t *testing.T) {
+	tracker := strategies.New(nil, &config.StrategiesConfig{Enabled: true, MaxOrders: 10})
+
+	tracker.Record(strategyOrder("o1", "owner", "grid", "BUY", 0.40, 100), false)
+	tracker.Record(strategyOrder("o2", "owner", "grid", "SELL", 0.60, 50), true)
+	tracker.Record(strategyOrder("o3", "owner", "arb", "BUY", 0.30, 20), false)
+	tracker.Record(strategyOrder("o4", "other", "grid", "BUY", 0.50, 10), true)
+	tracker.Record(strategyOrder("o5", "owner", "", "BUY", 0.50, 10), true) // untagged
+
+	tracker.ObserveFill(fillnotify.Fill{OrderID: "o1", TradeID: "t1", Price: "0.4", Size: "60", Timestamp: 1700000100000})
+	tracker.ObserveFill(fillnotify.Fill{OrderID: "o1", TradeID: "t1", Price: "0.4", Size: "60", Timestamp: 1700000100000})
+	// o2 was matched as placed; a later report of the same match is not counted twice
+	tracker.AddFill("o2", strategies.Fill{TradeID: "t2", Price: 0.6, Size: 50, Time: time.Unix(1700000200, 0)})
+	tracker.AddFill("o5", strategies.Fill{TradeID: "t3", Price: 0.5, Size: 10})
+
+	stats := tracker.Stats("owner")
+	require.Len(t, stats, 2)
+
+	arb := stats[0]
+	assert.Equal(t, "arb", arb.Strategy)
+	assert.Equal(t, 1, arb.Orders)
+	assert.Equal(t, 0, arb.FilledOrders)
+	assert.Equal(t, 0.0, arb.FillRate)
+	assert.Nil(t, arb.LastFill)
+	assert.Empty(t, arb.Positions)
+
+	grid := stats[1]
+	assert.Equal(t, "grid", grid.Strategy)
+	assert.Equal(t, 2, grid.Orders)
+	assert.Equal(t, 2, grid.FilledOrders)
+	assert.Equal(t, 2, grid.Fills)
+	assert.Equal(t, 150.0, grid.OrderedSize)
+	assert.Equal(t, 110.0, grid.FilledSize)
+	assert.InDelta(t, 110.0/150, grid.FillRate, 1e-6)
+	assert.Equal(t, 54.0, grid.Volume)
+	// 50 of the 60 bought at 0.40 sold at 0.60
+	assert.Equal(t, 10.0, grid.RealizedPnL)
+	assert.Equal(t, 10.0, grid.PnL)
+	require.Len(t, grid.Positions, 1)
+	assert.Equal(t, strategies.Position{TokenID: "tok", Size: 10, AvgPrice: 0.4}, grid.Positions[0])
+	require.NotNil(t, grid.LastFill)
+	assert.Equal(t, time.UnixMilli(1700000100000), *grid.LastFill)
+
+	assert.Len(t, tracker.Stats("other"), 1)
+	assert.Empty(t, tracker.Stats("nobody"))
+}
+
+func TestStrategies_ShortPositionsAndLimits(t *testing.T) {
+	tracker := strategies.New(nil, &config.StrategiesConfig{Enabled: true, MaxOrders: 2})
+
+	tracker.Record(strategyOrder("o1", "owner", "mm", "SELL", 0.70, 10), true)
+	tracker.Record(strategyOrder("o2", "owner", "mm", "BUY", 0.50, 15), true)
+
+	stats := tracker.Stats("owner")
+	require.Len(t, stats, 1)
+	// Covering the short at 0.50 realizes 10 * 0.20 and leaves 5 bought at 0.50
+	assert.Equal(t, 2.0, stats[0].RealizedPnL)
+	require.Len(t, stats[0].Positions, 1)
+	assert.Equal(t, 5.0, stats[0].Positions[0].Size)
+	assert.Equal(t, 0.5, stats[0].Positions[0].AvgPrice)
+
+	// Past MaxOrders the oldest order is dropped
+	tracker.Record(strategyOrder("o3", "owner", "mm", "BUY", 0.50, 1), false)
+	assert.Equal(t, 2, tracker.Stats("owner")[0].Orders)
+
+	disabled := strategies.New(nil, &config.StrategiesConfig{Enabled: false})
+	disabled.Record(strategyOrder("o1", "owner", "mm", "BUY", 0.50, 1), true)
+	assert.Empty(t, disabled.Stats("owner"))
+}
+
+func TestStrategies_Persists(t *testing.T) {
+	cfg := &config.StrategiesConfig{Enabled: true, MaxOrders: 10, Path: filepath.Join(t.TempDir(), "strategies.json")}
+
+	tracker := strategies.New(nil, cfg)
+	tracker.Record(strategyOrder("o1", "owner", "grid", "BUY", 0.40, 100), false)
+	tracker.AddFill("o1", strategies.Fill{TradeID: "t1", Price: 0.4, Size: 25, Time: time.Unix(1700000100, 0)})
+
+	restored := strategies.New(nil, cfg)
+	stats := restored.Stats("owner")
+	require.Len(t, stats, 1)
+	assert.Equal(t, 25.0, stats[0].FilledSize)
+	assert.Equal(t, 0.25, stats[0].FillRate)
+
+	// Restored orders keep following fills
+	restored.AddFill("o1", strategies.Fill{TradeID: "t2", Price: 0.4, Size: 25, Time: time.Unix(1700000200, 0)})
+	assert.Equal(t, 50.0, restored.Stats("owner")[0].FilledSize)
+}
@@ -0,0 +1,120 @@
+package unit
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/pkg/response"
+)
+
+func newStreamClient(t *testing.T, threshold int) (*polymarket.Client, *mockupstream.Server) {
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+	cfg := config.DefaultConfig()
+	cfg.Polymarket.StreamThreshold = threshold
+	mock.Apply(&cfg.Polymarket)
+
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+	client := polymarket.NewClient(&cfg.Polymarket, c)
+	t.Cleanup(client.Close)
+	return client, mock
+}
+
+// largeHistory is a price history of about 64KB
+var largeHistory = `{"history":[` + strings.TrimSuffix(strings.Repeat(`{"t":1700000000,"p":0.5},`, 2600), ",") + `]}`
+
+func TestGetStream_BuffersSmallBodies(t *testing.T) {
+	client, _ := newStreamClient(t, 1<<20)
+
+	data, stream, err := client.GetStream(client.Data("/prices-history?clob_token_id=1"), nil)
+	require.NoError(t, err)
+	assert.Nil(t, stream)
+	assert.Equal(t, "[]", string(data))
+}
+
+func TestGetStream_StreamsBodiesAboveTheThreshold(t *testing.T) {
+	client, mock := newStreamClient(t, 1024)
+
+	for name, withLength := range map[string]bool{"chunked": false, "content-length": true} {
+		t.Run(name, func(t *testing.T) {
+			mock.Handle(mockupstream.Data, "GET", "/prices-history", func(w http.ResponseWriter, r *http.Request) {
+				if withLength {
+					w.Header().Set("Content-Length", strconv.Itoa(len(largeHistory)))
+				}
+				w.Write([]byte(largeHistory))
+			})
+
+			data, stream, err := client.GetStream(client.Data("/prices-history?clob_token_id=1"), nil)
+			require.NoError(t, err)
+			require.NotNil(t, stream)
+			assert.Nil(t, data)
+			if withLength {
+				assert.Equal(t, len(largeHistory), stream.Size)
+			}
+
+			body, err := io.ReadAll(stream)
+			require.NoError(t, err)
+			assert.Equal(t, largeHistory, string(body))
+			assert.NoError(t, stream.Close())
+		})
+	}
+}
+
+func TestGetWithCacheOrStream_CachesOnlyBufferedBodies(t *testing.T) {
+	client, mock := newStreamClient(t, 1024)
+	mock.On(mockupstream.Data, "GET", "/prices-history", 200, largeHistory)
+	small := client.Data("/trades?user=0x1")
+	large := client.Data("/prices-history?clob_token_id=1")
+
+	for i := 0; i < 2; i++ {
+		data, cached, stream, err := client.GetWithCacheOrStream(small, "raw:small", time.Minute)
+		require.NoError(t, err)
+		assert.Nil(t, stream)
+		assert.NotEmpty(t, data)
+		assert.Equal(t, i == 1, cached)
+
+		_, cached, stream, err = client.GetWithCacheOrStream(large, "raw:large", time.Minute)
+		require.NoError(t, err)
+		require.NotNil(t, stream)
+		assert.False(t, cached)
+		stream.Close()
+	}
+	assert.Len(t, mock.Requests(mockupstream.Data), 3, "streamed bodies are fetched every time")
+}
+
+func TestResponseStream_RelaysTheWholeBody(t *testing.T) {
+	client, mock := newStreamClient(t, 1024)
+	mock.On(mockupstream.Data, "GET", "/prices-history", 200, largeHistory)
+
+	app := fiber.New()
+	app.Get("/history", func(c *fiber.Ctx) error {
+		_, stream, err := client.GetStream(client.Data("/prices-history?clob_token_id=1"), nil)
+		if err != nil {
+			return err
+		}
+		return response.Stream(c, stream, stream.Size)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/history", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, largeHistory, string(body))
+}
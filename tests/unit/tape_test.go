@@ -0,0 +1,97 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/storage"
+	"github.com/polygo/internal/tape"
+)
+
+func newTapeClient(t *testing.T, pm config.PolymarketConfig, tc *config.TapeConfig) *polymarket.Client {
+	tp, err := tape.New(tc, storage.NewLocal())
+	require.NoError(t, err)
+
+	c, err := cache.New(&config.DefaultConfig().Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	client := polymarket.NewClient(&pm, c)
+	client.SetTape(tp)
+	return client
+}
+
+func TestTape_RecordsAndReplaysHTTP(t *testing.T) {
+	dir := t.TempDir()
+	mock := mockupstream.New()
+	mock.On(mockupstream.CLOB, "GET", "/spread", 400, `{"error":"bad token"}`)
+
+	pm := config.DefaultConfig().Polymarket
+	mock.Apply(&pm)
+	base := pm.ClobBaseURL
+
+	recorder := newTapeClient(t, pm, &config.TapeConfig{Mode: config.TapeModeRecord, Dir: dir})
+	recorded, err := recorder.Get(base+"/midpoints?token_ids=a,b&x=1", nil)
+	require.NoError(t, err)
+	_, err = recorder.Get(base+"/spread?token_id=bad", nil)
+	require.Error(t, err)
+	mock.Close()
+
+	replayer := newTapeClient(t, pm, &config.TapeConfig{Mode: config.TapeModeReplay, Dir: dir})
+
+	// Query parameter order does not matter
+	replayed, err := replayer.Get(base+"/midpoints?x=1&token_ids=a,b", nil)
+	require.NoError(t, err)
+	assert.Equal(t, recorded, replayed)
+
+	_, err = replayer.Get(base+"/spread?token_id=bad", nil)
+	var statusErr *polymarket.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, 400, statusErr.StatusCode)
+
+	_, err = replayer.Get(base+"/book?token_id=never", nil)
+	assert.True(t, errors.Is(err, tape.ErrNotRecorded))
+}
+
+func TestTape_ReplaysFramesInOrder(t *testing.T) {
+	dir := t.TempDir()
+
+	rec, err := tape.New(&config.TapeConfig{Mode: config.TapeModeRecord, Dir: dir}, storage.NewLocal())
+	require.NoError(t, err)
+	require.NoError(t, rec.RecordFrame("market", []byte(`{"n":1}`)))
+	require.NoError(t, rec.RecordFrame("price", []byte(`{"n":2}`)))
+	require.NoError(t, rec.Close())
+
+	play, err := tape.New(&config.TapeConfig{Mode: config.TapeModeReplay, Dir: dir}, storage.NewLocal())
+	require.NoError(t, err)
+
+	var got []string
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, play.ReplayFrames(ctx, func(channel string, data []byte) {
+		got = append(got, channel+" "+string(data))
+	}))
+	assert.Equal(t, []string{`market {"n":1}`, `price {"n":2}`}, got)
+}
+
+func TestTape_OffAndInvalidModes(t *testing.T) {
+	tp, err := tape.New(&config.TapeConfig{Mode: config.TapeModeOff}, storage.NewLocal())
+	require.NoError(t, err)
+	assert.False(t, tp.Recording())
+	assert.False(t, tp.Replaying())
+
+	_, err = tape.New(&config.TapeConfig{Mode: "rewind"}, storage.NewLocal())
+	assert.Error(t, err)
+
+	_, err = tape.New(&config.TapeConfig{Mode: config.TapeModeReplay, Dir: t.TempDir() + "/missing"}, storage.NewLocal())
+	assert.Error(t, err)
+}
@@ -0,0 +1,116 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/shape"
+	"github.com/polygo/internal/taxreport"
+)
+
+func taxActivity(kind, side, asset, market string, at time.Time, size, usdc float64) shape.Activity {
+	return shape.Activity{Type: kind, Side: side, TokenID: asset, ConditionID: market, Title: market + "?", Size: size, USDCSize: usdc, Time: &at}
+}
+
+func taxDay(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 12, 0, 0, 0, time.UTC)
+}
+
+func taxHistory() []shape.Activity {
+	// Newest first, as the Data API returns it
+	return []shape.Activity{
+		taxActivity("REDEEM", "", "", "m2", taxDay(2024, 11, 5), 50, 50),
+		taxActivity("SPLIT", "", "", "m3", taxDay(2024, 9, 1), 10, 10),
+		taxActivity("TRADE", "SELL", "yes1", "m1", taxDay(2024, 6, 1), 150, 120),
+		taxActivity("TRADE", "BUY", "no2", "m2", taxDay(2024, 3, 1), 20, 8),
+		taxActivity("TRADE", "BUY", "yes2", "m2", taxDay(2024, 2, 1), 50, 30),
+		taxActivity("TRADE", "BUY", "yes1", "m1", taxDay(2024, 1, 10), 100, 60),
+		taxActivity("TRADE", "SELL", "yes1", "m1", taxDay(2023, 8, 1), 20, 15),
+		taxActivity("TRADE", "BUY", "yes1", "m1", taxDay(2023, 1, 5), 100, 40),
+	}
+}
+
+func TestTaxReport_MatchesLotsFIFO(t *testing.T) {
+	report := taxreport.Build("0xabc", taxHistory(), 2024, taxreport.MethodFIFO)
+
+	require.Len(t, report.Disposals, 4)
+	// The sale of 150 takes the 80 left of the 2023 lot, then 70 of the 2024 one
+	sale := report.Disposals[0]
+	assert.Equal(t, "sell", sale.Kind)
+	assert.Equal(t, 80.0, sale.Size)
+	assert.Equal(t, 64.0, sale.Proceeds)
+	assert.Equal(t, 32.0, sale.CostBasis)
+	assert.Equal(t, 32.0, sale.Gain)
+	assert.Equal(t, taxreport.TermLong, sale.Term)
+	assert.Equal(t, taxDay(2023, 1, 5), *sale.Acquired)
+	sale = report.Disposals[1]
+	assert.Equal(t, 70.0, sale.Size)
+	assert.Equal(t, 42.0, sale.CostBasis)
+	assert.Equal(t, taxreport.TermShort, sale.Term)
+
+	// The redemption pays the winning outcome and closes the losing one at nothing
+	assert.Equal(t, "redeem", report.Disposals[2].Kind)
+	assert.Equal(t, "no2", report.Disposals[2].Asset)
+	assert.Equal(t, "yes2", report.Disposals[3].Asset)
+	assert.InDelta(t, 50*50.0/70, report.Disposals[3].Proceeds, 1e-6)
+
+	assert.Equal(t, taxreport.Totals{
+		Proceeds:   170,
+		CostBasis:  112,
+		Gain:       58,
+		ShortTerm:  26,
+		LongTerm:   32,
+		OpenShares: 30,
+	}, report.Totals)
+	require.Len(t, report.Markets, 2)
+	assert.Equal(t, taxreport.MarketGain{ConditionID: "m1", Title: "m1?", Disposals: 2, Proceeds: 120, CostBasis: 74, Gain: 46}, report.Markets[0])
+	assert.Equal(t, 12.0, report.Markets[1].Gain)
+
+	require.Len(t, report.OpenLots, 1)
+	assert.Equal(t, 30.0, report.OpenLots[0].Size)
+	assert.Equal(t, 18.0, report.OpenLots[0].CostBasis)
+	assert.Equal(t, map[string]int{"SPLIT": 1}, report.Ignored)
+}
+
+func TestTaxReport_MatchesLotsLIFO(t *testing.T) {
+	report := taxreport.Build("0xabc", taxHistory(), 2024, taxreport.MethodLIFO)
+
+	// The sale takes the 2024 lot first, then 50 of the 2023 one
+	assert.Equal(t, 100.0, report.Disposals[0].Size)
+	assert.Equal(t, 60.0, report.Disposals[0].CostBasis)
+	assert.Equal(t, 50.0, report.Disposals[1].Size)
+	assert.Equal(t, 20.0, report.Disposals[1].CostBasis)
+	assert.Equal(t, 30.0, report.OpenLots[0].Size)
+	assert.Equal(t, taxDay(2023, 1, 5), report.OpenLots[0].Acquired)
+
+	// 2023 only sees its own disposals
+	earlier := taxreport.Build("0xabc", taxHistory(), 2023, taxreport.MethodLIFO)
+	require.Len(t, earlier.Disposals, 1)
+	assert.Equal(t, 7.0, earlier.Totals.Gain)
+}
+
+func TestTaxReport_UnmatchedSalesAndCSV(t *testing.T) {
+	activity := []shape.Activity{
+		taxActivity("TRADE", "SELL", "yes1", "m1", taxDay(2024, 6, 1), 10, 9),
+	}
+	report := taxreport.Build("0xabc", activity, 2024, taxreport.MethodFIFO)
+	require.Len(t, report.Disposals, 1)
+	assert.Nil(t, report.Disposals[0].Acquired)
+	assert.Equal(t, taxreport.TermUnknown, report.Disposals[0].Term)
+	assert.Equal(t, 9.0, report.Totals.Gain)
+	assert.Equal(t, 10.0, report.Totals.Unmatched)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteCSV(&buf))
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{"disposed", "acquired", "kind", "term", "condition_id", "asset", "title", "outcome",
+		"size", "proceeds", "cost_basis", "gain", "transaction_hash"}, rows[0])
+	assert.Equal(t, []string{"2024-06-01T12:00:00Z", "", "sell", "unknown", "m1", "yes1", "m1?", "", "10", "9", "0", "9", ""}, rows[1])
+}
@@ -0,0 +1,166 @@
+package unit
+
+import (
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/api/middleware"
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/tenant"
+)
+
+func newTenantRegistry(t *testing.T) (*tenant.Registry, *config.TenantsConfig) {
+	cfg := config.DefaultConfig().Tenants
+	cfg.Enabled = true
+	cfg.UsagePath = filepath.Join(t.TempDir(), "usage.json")
+	cfg.Tenants = []config.TenantSpec{
+		{ID: "desk-a", Name: "Desk A", APIKeys: []string{"key-a1", "key-a2"}},
+		{ID: "desk-b", APIKeys: []string{"key-b"}, RateLimit: 2},
+	}
+	registry, err := tenant.New(&cfg)
+	require.NoError(t, err)
+	return registry, &cfg
+}
+
+func TestTenants_ResolveKeysAndValidateConfig(t *testing.T) {
+	registry, _ := newTenantRegistry(t)
+
+	a1, ok := registry.ForKey("key-a1")
+	require.True(t, ok)
+	a2, _ := registry.ForKey("key-a2")
+	assert.Same(t, a1, a2, "keys of a tenant share it")
+	assert.Equal(t, "tenant:desk-a", a1.Owner())
+	assert.Equal(t, 1000, a1.RateLimit, "the default limit")
+	_, ok = registry.ForKey("unknown")
+	assert.False(t, ok)
+
+	list := registry.List()
+	require.Len(t, list, 2)
+	assert.Equal(t, tenant.Tenant{ID: "desk-b", RateLimit: 2, APIKeys: 1}, list[1])
+
+	cfg := config.DefaultConfig().Tenants
+	cfg.Enabled = true
+	cfg.Tenants = []config.TenantSpec{{ID: "a", APIKeys: []string{"k"}}, {ID: "b", APIKeys: []string{"k"}}}
+	_, err := tenant.New(&cfg)
+	assert.ErrorIs(t, err, tenant.ErrInvalidConfig, "a key in two tenants")
+
+	cfg.Tenants = []config.TenantSpec{{ID: "has space"}}
+	_, err = tenant.New(&cfg)
+	assert.ErrorIs(t, err, tenant.ErrInvalidConfig)
+}
+
+func TestTenants_UsageIsReportedPerDayAndSurvivesRestarts(t *testing.T) {
+	registry, cfg := newTenantRegistry(t)
+	a, _ := registry.ForKey("key-a1")
+	b, _ := registry.ForKey("key-b")
+
+	today := time.Now().UTC()
+	yesterday := today.AddDate(0, 0, -1)
+	registry.Record(a, "GET", "/api/v1/markets/:id", 200, 0, 300, today)
+	registry.Record(a, "GET", "/api/v1/markets/:id", 502, 0, 50, today)
+	registry.Record(a, "GET", "/", 429, 0, 60, today)
+	registry.Record(a, "POST", "/api/v1/orders", 200, 120, 80, yesterday)
+	registry.Record(b, "GET", "/api/v1/markets", 200, 0, 10, today)
+
+	report, err := registry.Usage("desk-a", yesterday, today)
+	require.NoError(t, err)
+	assert.Equal(t, tenant.Usage{Requests: 4, Errors: 1, RateLimited: 1, BytesIn: 120, BytesOut: 490}, report.Total)
+	require.Len(t, report.Days, 2)
+	assert.Equal(t, yesterday.Format("2006-01-02"), report.Days[0].Date)
+	assert.Equal(t, int64(3), report.Days[1].Requests)
+	assert.Equal(t, []tenant.RouteUsage{
+		{Route: "GET /api/v1/markets/:id", Requests: 2, Errors: 1},
+		{Route: "POST /api/v1/orders", Requests: 1},
+	}, report.Routes, "rate-limited requests never reached a route")
+
+	report, err = registry.Usage("desk-a", today, today)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), report.Total.Requests)
+
+	_, err = registry.Usage("desk-c", yesterday, today)
+	assert.ErrorIs(t, err, tenant.ErrNotFound)
+
+	registry.Stop()
+	restored, err := tenant.New(cfg)
+	require.NoError(t, err)
+	report, err = restored.Usage("desk-a", yesterday, today)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), report.Total.Requests)
+	report, err = restored.Usage("desk-b", yesterday, today)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), report.Total.Requests)
+}
+
+func TestTenants_MiddlewareRateLimitsAndRecordsPerTenant(t *testing.T) {
+	registry, _ := newTenantRegistry(t)
+	auth := config.DefaultConfig().Auth
+
+	app := fiber.New()
+	app.Use(middleware.Tenant(registry, &auth))
+	app.Use(middleware.RateLimit(middleware.RateLimitConfig{
+		Max:    100,
+		Window: time.Minute,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			if t := middleware.GetTenant(c); t != nil {
+				return t.Owner()
+			}
+			return c.IP()
+		},
+		MaxFor: func(c *fiber.Ctx) int {
+			if t := middleware.GetTenant(c); t != nil {
+				return t.RateLimit
+			}
+			return 0
+		},
+	}))
+	app.Get("/public", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	app.Get("/mine", middleware.RequireTenant(registry), func(c *fiber.Ctx) error {
+		return c.SendString(middleware.GetTenant(c).ID)
+	})
+
+	get := func(path, key string) int {
+		req := httptest.NewRequest("GET", path, nil)
+		if key != "" {
+			req.Header.Set(auth.APIKeyHeader, key)
+		}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, 200, get("/public", ""))
+	assert.Equal(t, 401, get("/mine", ""), "tenant data needs a tenant's key")
+	assert.Equal(t, 401, get("/mine", "unknown"))
+	assert.Equal(t, 200, get("/mine", "key-a2"))
+
+	// desk-b allows 2 requests per window across its keys; desk-a is unaffected
+	assert.Equal(t, 200, get("/public", "key-b"))
+	assert.Equal(t, 200, get("/mine", "key-b"))
+	assert.Equal(t, 429, get("/public", "key-b"))
+	assert.Equal(t, 200, get("/public", "key-a1"))
+
+	report, err := registry.Usage("desk-b", time.Now(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), report.Total.Requests)
+	assert.Equal(t, int64(1), report.Total.RateLimited)
+	assert.Greater(t, report.Total.BytesOut, int64(len("ok")+len("desk-b")), "both answers and the rejection")
+
+	report, err = registry.Usage("desk-a", time.Now(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), report.Total.Requests)
+}
+
+func TestNamespacedUserDataKey_IsolatesTenants(t *testing.T) {
+	shared := cache.UserDataKey("0xabc", 3, "/positions?user=0xabc")
+	a := cache.NamespacedUserDataKey("desk-a", "0xabc", 3, "/positions?user=0xabc")
+	b := cache.NamespacedUserDataKey("desk-b", "0xabc", 3, "/positions?user=0xabc")
+	assert.NotEqual(t, shared, a)
+	assert.NotEqual(t, a, b)
+}
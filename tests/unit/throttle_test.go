@@ -0,0 +1,98 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/models"
+	"github.com/polygo/internal/throttle"
+)
+
+func throttleOrder(token, price, size string) models.CreateOrderRequest {
+	return models.CreateOrderRequest{TokenID: token, Side: models.SideBuy, Price: price, Size: size}
+}
+
+func newThrottleGuard(cfg *config.ThrottleConfig) (*throttle.Guard, func(string, ...models.CreateOrderRequest) error) {
+	guard := throttle.New(nil, cfg)
+	return guard, func(account string, orders ...models.CreateOrderRequest) error {
+		_, err := guard.Reserve(account, orders)
+		return err
+	}
+}
+
+func TestThrottle_RejectsDuplicates(t *testing.T) {
+	_, reserve := newThrottleGuard(&config.ThrottleConfig{Enabled: true, DuplicateWindow: 100 * time.Millisecond})
+
+	require.NoError(t, reserve("key", throttleOrder("t1", "0.5", "10")))
+
+	var rejected *throttle.Error
+	err := reserve("key", throttleOrder("t1", "0.4", "10"), throttleOrder("t1", "0.50", "10.0"))
+	require.ErrorAs(t, err, &rejected)
+	assert.Equal(t, throttle.CodeDuplicate, rejected.Code)
+	assert.Equal(t, 1, rejected.Order)
+	assert.Greater(t, rejected.RetryAfter, time.Duration(0))
+	assert.LessOrEqual(t, rejected.RetryAfter, 100*time.Millisecond)
+
+	// Other keys, sides and sizes are distinct orders
+	assert.NoError(t, reserve("other", throttleOrder("t1", "0.5", "10")))
+	sell := throttleOrder("t1", "0.5", "10")
+	sell.Side = models.SideSell
+	assert.NoError(t, reserve("key", sell, throttleOrder("t1", "0.5", "11")))
+
+	// The rejected batch was not remembered
+	assert.NoError(t, reserve("key", throttleOrder("t1", "0.4", "10")))
+
+	err = reserve("key", throttleOrder("t2", "0.3", "5"), throttleOrder("t2", "0.3", "5"))
+	require.ErrorAs(t, err, &rejected)
+	assert.Equal(t, 1, rejected.Order, "repeated within the batch")
+	assert.Zero(t, rejected.RetryAfter)
+
+	time.Sleep(120 * time.Millisecond)
+	assert.NoError(t, reserve("key", throttleOrder("t1", "0.5", "10")))
+}
+
+func TestThrottle_CapsOrdersPerMarket(t *testing.T) {
+	cfg := &config.ThrottleConfig{Enabled: true, MarketRate: 3, MarketWindow: time.Minute}
+	_, reserve := newThrottleGuard(cfg)
+
+	require.NoError(t, reserve("key", throttleOrder("t1", "0.5", "10"), throttleOrder("t1", "0.5", "10")))
+	require.NoError(t, reserve("key", throttleOrder("t1", "0.5", "10")))
+
+	var rejected *throttle.Error
+	err := reserve("key", throttleOrder("t2", "0.5", "10"), throttleOrder("t1", "0.5", "10"))
+	require.ErrorAs(t, err, &rejected)
+	assert.Equal(t, throttle.CodeMarketRate, rejected.Code)
+	assert.Equal(t, 1, rejected.Order)
+	assert.InDelta(t, time.Minute.Seconds(), rejected.RetryAfter.Seconds(), 1)
+
+	// Other markets and keys have their own budgets
+	assert.NoError(t, reserve("key", throttleOrder("t2", "0.5", "10")))
+	assert.NoError(t, reserve("other", throttleOrder("t1", "0.5", "10")))
+
+	// A batch larger than the cap never clears
+	err = reserve("fresh", throttleOrder("t3", "0.1", "1"), throttleOrder("t3", "0.2", "1"), throttleOrder("t3", "0.3", "1"), throttleOrder("t3", "0.4", "1"))
+	require.ErrorAs(t, err, &rejected)
+	assert.Zero(t, rejected.RetryAfter)
+
+	cfg.Enabled = false
+	assert.NoError(t, reserve("key", throttleOrder("t1", "0.5", "10")))
+}
+
+func TestThrottle_ReleaseForgetsOrders(t *testing.T) {
+	guard, reserve := newThrottleGuard(&config.ThrottleConfig{Enabled: true, DuplicateWindow: time.Minute, MarketRate: 1, MarketWindow: time.Minute})
+
+	r, err := guard.Reserve("key", []models.CreateOrderRequest{throttleOrder("t1", "0.5", "10")})
+	require.NoError(t, err)
+	assert.Error(t, reserve("key", throttleOrder("t1", "0.5", "10")))
+
+	// e.g. the CLOB rejected the order: it may be sent again at once
+	guard.Release(r)
+	require.NoError(t, reserve("key", throttleOrder("t1", "0.5", "10")))
+	assert.Error(t, reserve("key", throttleOrder("t1", "0.5", "10")))
+
+	guard.Release(nil)
+}
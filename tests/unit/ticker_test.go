@@ -0,0 +1,65 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/ticker"
+	"github.com/polygo/internal/wsframe"
+)
+
+func readFrame(t *testing.T, ch chan []byte) ticker.Frame {
+	t.Helper()
+	var f ticker.Frame
+	require.NoError(t, json.Unmarshal(nextFrame(t, ch), &f))
+	return f
+}
+
+func TestTicker_BroadcastsOnlyChangedTokens(t *testing.T) {
+	tk := ticker.New(nil, nil, &config.TickerConfig{Interval: time.Second, TopMarkets: 10})
+	tokens := map[string]string{"t1": "m1", "t2": "m2"}
+
+	out, ch := feedClient(t)
+	tk.Subscribe(out, wsframe.JSON)
+	snap := readFrame(t, ch)
+	assert.Equal(t, "snapshot", snap.Type)
+	assert.Empty(t, snap.Ticks)
+
+	tk.Publish(tokens, map[string]string{"t1": "0.5", "t2": "0.25"})
+	f := readFrame(t, ch)
+	assert.Equal(t, "ticker", f.Type)
+	require.Len(t, f.Ticks, 2)
+
+	// Unchanged midpoints produce no frame at all
+	tk.Publish(tokens, map[string]string{"t1": "0.5", "t2": "0.25"})
+	tk.Publish(tokens, map[string]string{"t1": "0.5", "t2": "0.3"})
+	f = readFrame(t, ch)
+	require.Len(t, f.Ticks, 1)
+	assert.Equal(t, ticker.Tick{MarketID: "m2", TokenID: "t2", Mid: 0.3}, f.Ticks[0])
+
+	tk.Unsubscribe(out)
+}
+
+func TestTicker_SnapshotOnSubscribe(t *testing.T) {
+	tk := ticker.New(nil, nil, &config.TickerConfig{Interval: time.Second, TopMarkets: 10})
+	tk.Publish(map[string]string{"t1": "m1"}, map[string]string{"t1": "0.7"})
+
+	out, ch := feedClient(t)
+	tk.Subscribe(out, wsframe.JSON)
+	snap := readFrame(t, ch)
+	assert.Equal(t, "snapshot", snap.Type)
+	require.Len(t, snap.Ticks, 1)
+	assert.Equal(t, 0.7, snap.Ticks[0].Mid)
+
+	tk.Stop()
+	select {
+	case <-out.Done():
+	case <-time.After(time.Second):
+		t.Fatal("subscriber not closed on Stop")
+	}
+}
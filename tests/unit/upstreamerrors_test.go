@@ -0,0 +1,97 @@
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/polygo/internal/polymarket"
+)
+
+func TestClassifyError_StatusCodes(t *testing.T) {
+	cases := []struct {
+		status int
+		body   string
+		want   int
+		code   string
+	}{
+		{429, `{"error":"Too Many Requests"}`, 429, polymarket.CodeUpstreamRateLimited},
+		{503, `{"error":"maintenance"}`, 502, polymarket.CodeUpstreamUnavailable},
+		{401, `{"error":"Unauthorized/Invalid api key"}`, 401, polymarket.CodeUpstreamUnauthorized},
+		{404, `{"error":"market not found"}`, 404, polymarket.CodeUpstreamNotFound},
+		{400, `{"error":"invalid token id"}`, 400, polymarket.CodeUpstreamBadRequest},
+		{400, `{"error":"order 0x1 is invalid. Price (0.555), breaks minimum tick size rule: 0.01"}`, 400, polymarket.CodeOrderInvalidPrice},
+		{400, `{"errorMsg":"the market is not yet ready to process new orders"}`, 409, polymarket.CodeMarketClosed},
+	}
+	for _, tc := range cases {
+		err := fmt.Errorf("request failed after 3 retries: %w",
+			&polymarket.StatusError{StatusCode: tc.status, Body: []byte(tc.body)})
+		got := polymarket.ClassifyError(err)
+		require.NotNil(t, got, tc.body)
+		assert.Equal(t, tc.want, got.Status, tc.body)
+		assert.Equal(t, tc.code, got.Code, tc.body)
+	}
+}
+
+func TestClassifyError_TransportFailures(t *testing.T) {
+	assert.Nil(t, polymarket.ClassifyError(nil))
+	assert.Nil(t, polymarket.ClassifyError(errors.New("failed to parse response")))
+
+	busy := polymarket.ClassifyError(polymarket.ErrUpstreamBusy)
+	require.NotNil(t, busy)
+	assert.Equal(t, 503, busy.Status)
+	assert.Equal(t, polymarket.CodeUpstreamBusy, busy.Code)
+	assert.True(t, busy.Retryable)
+	assert.Equal(t, time.Second, busy.RetryAfter)
+
+	timeout := polymarket.ClassifyError(fmt.Errorf("request failed: %w", fasthttp.ErrTimeout))
+	require.NotNil(t, timeout)
+	assert.Equal(t, 504, timeout.Status)
+	assert.Equal(t, polymarket.CodeUpstreamTimeout, timeout.Code)
+
+	closed := polymarket.ClassifyError(fasthttp.ErrConnectionClosed)
+	require.NotNil(t, closed)
+	assert.Equal(t, 502, closed.Status)
+}
+
+func TestClassifyError_RetryHints(t *testing.T) {
+	limited := polymarket.ClassifyError(&polymarket.StatusError{StatusCode: 429, RetryAfter: 5 * time.Second})
+	assert.True(t, limited.Retryable)
+	assert.Equal(t, 5*time.Second, limited.RetryAfter, "upstream's Retry-After is relayed")
+
+	down := polymarket.ClassifyError(&polymarket.StatusError{StatusCode: 502})
+	assert.True(t, down.Retryable)
+	assert.Zero(t, down.RetryAfter)
+
+	rejected := polymarket.ClassifyError(&polymarket.StatusError{StatusCode: 400, Body: []byte(`{"error":"not enough balance / allowance"}`)})
+	assert.False(t, rejected.Retryable)
+}
+
+func TestClassifyError_TruncatesDetails(t *testing.T) {
+	body := `{"error":"` + strings.Repeat("x", 2000) + `"}`
+	got := polymarket.ClassifyError(&polymarket.StatusError{StatusCode: 400, Body: []byte(body)})
+	require.NotNil(t, got)
+	assert.LessOrEqual(t, len(got.Details), 503)
+}
+
+func TestClassifyRejection(t *testing.T) {
+	cases := map[string]string{
+		"not enough balance / allowance":                 polymarket.CodeInsufficientBalance,
+		"Size (1) lower than the minimum: 5":             polymarket.CodeOrderMinSize,
+		"invalid expiration":                             polymarket.CodeOrderInvalidExpiry,
+		"order 0xabc is duplicated":                      polymarket.CodeOrderDuplicate,
+		"order couldn't be fully filled. FOK orders are": polymarket.CodeOrderNotFilled,
+		"something unexpected":                           polymarket.CodeOrderRejected,
+	}
+	for msg, code := range cases {
+		got := polymarket.ClassifyRejection(msg)
+		assert.Equal(t, code, got.Code, msg)
+		assert.Equal(t, msg, got.Details)
+	}
+}
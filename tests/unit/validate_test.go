@@ -0,0 +1,76 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polygo/internal/models"
+	"github.com/polygo/pkg/validate"
+)
+
+func TestValidate_ReportsEveryInvalidFieldByJSONPath(t *testing.T) {
+	errs := validate.Struct(&models.CreateOrderRequest{
+		Side:  "HOLD",
+		Price: "0.5",
+		Size:  "ten",
+		Type:  "IOC",
+		Maker: "0x123",
+	})
+
+	fields := make(map[string]string)
+	for _, e := range errs {
+		fields[e.Field] = e.Rule
+	}
+	assert.Equal(t, map[string]string{
+		"tokenID": "required",
+		"side":    "oneof",
+		"size":    "numeric",
+		"type":    "oneof",
+		"maker":   "eth_addr",
+	}, fields)
+	assert.Contains(t, errs.Error(), "side must be one of BUY, SELL")
+
+	valid := models.CreateOrderRequest{TokenID: "123", Side: models.SideBuy, Price: "0.5", Size: "10"}
+	assert.Nil(t, validate.Struct(&valid), "optional fields may be omitted")
+}
+
+func TestValidate_DivesIntoSlices(t *testing.T) {
+	type batch struct {
+		IDs    []string                    `json:"ids" validate:"min=1,max=2,dive,required"`
+		Orders []models.CreateOrderRequest `json:"orders" validate:"len=1,dive"`
+	}
+
+	errs := validate.Struct(batch{
+		IDs:    []string{"a", ""},
+		Orders: []models.CreateOrderRequest{{TokenID: "1", Side: models.SideSell, Price: "-", Size: "1"}},
+	})
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "ids[1]", errs[0].Field)
+	assert.Equal(t, "orders[0].price", errs[1].Field)
+
+	errs = validate.Struct(batch{IDs: []string{}, Orders: nil})
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "ids must have at least 1 items", errs[0].Message)
+
+	// Top-level slices are indexed under the given name
+	errs = validate.Var([]models.CreateOrderRequest{{TokenID: "1", Side: models.SideBuy, Price: "1", Size: "1"}, {}}, "orders")
+	assert.NotEmpty(t, errs)
+	for _, e := range errs {
+		assert.Contains(t, e.Field, "orders[1].")
+	}
+}
+
+func TestValidate_MarketIDs(t *testing.T) {
+	assert.True(t, validate.IsMarketID("71321045679252212594626385532706912750332728571942532289631379312455583992563"))
+	assert.True(t, validate.IsMarketID("will-it-rain_2025.v2"))
+	assert.False(t, validate.IsMarketID(""))
+	assert.False(t, validate.IsMarketID("../orders"))
+	assert.False(t, validate.IsMarketID("123?side=SELL"))
+
+	errs := validate.Struct(&models.CreateOrderRequest{TokenID: "1/2", Side: models.SideBuy, Price: "0.5", Size: "1"})
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, "market_id", errs[0].Rule)
+		assert.Equal(t, "tokenID must be a market or token ID", errs[0].Message)
+	}
+}
@@ -0,0 +1,205 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/cache"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/mockupstream"
+	"github.com/polygo/internal/polymarket"
+	"github.com/polygo/internal/watchlist"
+	"github.com/polygo/internal/wsframe"
+	"github.com/polygo/pkg/validate"
+)
+
+const whale = "0x1111111111111111111111111111111111111111"
+
+func newTestWatchlist(t *testing.T, trades *atomic.Value) *watchlist.Watchlist {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(trades.Load().(string)))
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg := config.DefaultConfig()
+	cfg.Polymarket.DataBaseURL = srv.URL
+	cfg.Cache.UserDataTTL = 0
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	data := polymarket.NewDataClient(polymarket.NewClient(&cfg.Polymarket, c))
+	return watchlist.New(data, nil, nil, nil, &config.WatchlistConfig{MaxWallets: 2, TradesLimit: 50})
+}
+
+func TestWatchlist_PushesOnlyNewTrades(t *testing.T) {
+	var trades atomic.Value
+	trades.Store(`[{"transactionHash":"0xa","asset":"t1","side":"BUY","size":10,"price":0.5}]`)
+	wl := newTestWatchlist(t, &trades)
+
+	_, err := wl.Add(whale, "whale", "")
+	require.NoError(t, err)
+	out, ch := feedClient(t)
+	otherOut, other := feedClient(t)
+	wl.Subscribe(out, wsframe.JSON, "", nil)
+	wl.Subscribe(otherOut, wsframe.JSON, "", []string{"0x2222222222222222222222222222222222222222"})
+
+	// The first poll only records existing trades
+	wl.Poll()
+	assertNoFrame(t, ch)
+
+	trades.Store(`[{"transactionHash":"0xc","asset":"t1","side":"SELL","size":5,"price":0.6},` +
+		`{"transactionHash":"0xb","asset":"t1","side":"BUY","size":1,"price":0.55},` +
+		`{"transactionHash":"0xa","asset":"t1","side":"BUY","size":10,"price":0.5}]`)
+	wl.Poll()
+
+	// New trades arrive oldest first
+	for _, hash := range []string{"0xb", "0xc"} {
+		var ev struct {
+			Type    string                 `json:"type"`
+			Address string                 `json:"address"`
+			Label   string                 `json:"label"`
+			Trade   map[string]interface{} `json:"trade"`
+		}
+		require.NoError(t, json.Unmarshal(nextFrame(t, ch), &ev))
+		assert.Equal(t, "wallet_trade", ev.Type)
+		assert.Equal(t, whale, ev.Address)
+		assert.Equal(t, "whale", ev.Label)
+		assert.Equal(t, hash, ev.Trade["transactionHash"])
+	}
+	assertNoFrame(t, other)
+
+	// Nothing new, nothing pushed
+	wl.Poll()
+	assertNoFrame(t, ch)
+
+	wl.Unsubscribe(out)
+	wl.Unsubscribe(otherOut)
+}
+
+func TestWatchlist_AddValidatesAndLimits(t *testing.T) {
+	var trades atomic.Value
+	trades.Store(`[]`)
+	wl := newTestWatchlist(t, &trades)
+
+	_, err := wl.Add("not-an-address", "", "")
+	assert.ErrorIs(t, err, validate.ErrInvalidAddress)
+
+	_, err = wl.Add(whale, "", "k1")
+	require.NoError(t, err)
+	_, err = wl.Add("0x2222222222222222222222222222222222222222", "", "k2")
+	require.NoError(t, err)
+	_, err = wl.Add("0x3333333333333333333333333333333333333333", "", "k1")
+	assert.ErrorIs(t, err, watchlist.ErrFull)
+
+	// Wallets are scoped to their owner
+	assert.Len(t, wl.Wallets("k1"), 1)
+	assert.False(t, wl.Remove(whale, "k2"))
+	assert.True(t, wl.Remove(whale, "k1"))
+	assert.Empty(t, wl.Wallets("k1"))
+}
+
+func newMarketWatchlist(t *testing.T, path string) (*watchlist.Watchlist, *mockupstream.Server, *cache.Cache) {
+	mock := mockupstream.New()
+	t.Cleanup(mock.Close)
+
+	cfg := config.DefaultConfig()
+	mock.Apply(&cfg.Polymarket)
+	c, err := cache.New(&cfg.Cache)
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	client := polymarket.NewClient(&cfg.Polymarket, c)
+	wl := watchlist.New(polymarket.NewDataClient(client), polymarket.NewGammaClient(client), polymarket.NewClobClient(client), nil,
+		&config.WatchlistConfig{MaxMarkets: 10, Path: path})
+	return wl, mock, c
+}
+
+func readMarketFrame(t *testing.T, ch chan []byte) watchlist.MarketFrame {
+	t.Helper()
+	var f watchlist.MarketFrame
+	require.NoError(t, json.Unmarshal(nextFrame(t, ch), &f))
+	return f
+}
+
+func TestWatchlist_MarketUpdatesForWatchers(t *testing.T) {
+	wl, mock, c := newMarketWatchlist(t, "")
+
+	_, err := wl.AddMarket("missing", "k1")
+	assert.ErrorIs(t, err, watchlist.ErrUnknownMarket)
+	_, err = wl.AddMarket(mockupstream.MarketID, "k1")
+	require.NoError(t, err)
+
+	mineOut, mine := feedClient(t)
+	theirsOut, theirs := feedClient(t)
+	wl.Subscribe(mineOut, wsframe.JSON, "k1", nil)
+	wl.Subscribe(theirsOut, wsframe.JSON, "k2", nil)
+
+	// First poll: a snapshot of the new watch
+	wl.PollMarkets()
+	f := readMarketFrame(t, mine)
+	assert.Equal(t, watchlist.MarketSnapshot, f.Type)
+	assert.Equal(t, map[string]float64{"Yes": 0.5, "No": 0.5}, f.Prices)
+	assert.Equal(t, 150000.0, f.Volume)
+
+	// Unchanged: nothing sent
+	wl.PollMarkets()
+	assertNoFrame(t, mine)
+
+	mock.On(mockupstream.CLOB, "GET", "/midpoints", 200,
+		`{"`+mockupstream.TokenYes+`":"0.6","`+mockupstream.TokenNo+`":"0.4"}`)
+	wl.PollMarkets()
+	f = readMarketFrame(t, mine)
+	assert.Equal(t, watchlist.MarketUpdate, f.Type)
+	assert.Equal(t, []string{watchlist.ChangedPrice}, f.Changed)
+	assert.Equal(t, 0.6, f.Prices["Yes"])
+
+	// Resolution comes from Gamma
+	mock.On(mockupstream.Gamma, "GET", "/markets/"+mockupstream.MarketID, 200,
+		`{"id":"`+mockupstream.MarketID+`","closed":true,"volume":"150000","volume24hr":12000.5,`+
+			`"outcomes":"[\"Yes\",\"No\"]","outcomePrices":"[\"1\",\"0\"]","clobTokenIds":"[\"`+mockupstream.TokenYes+`\",\"`+mockupstream.TokenNo+`\"]"}`)
+	c.Delete(cache.MarketKey(mockupstream.MarketID))
+	wl.PollMarkets()
+	f = readMarketFrame(t, mine)
+	assert.Equal(t, []string{watchlist.ChangedPrice, watchlist.ChangedResolution}, f.Changed)
+	assert.True(t, f.Closed)
+	assert.Equal(t, "Yes", f.Winner)
+
+	assertNoFrame(t, theirs)
+	wl.Unsubscribe(mineOut)
+	wl.Unsubscribe(theirsOut)
+
+	// A reconnect starts from the latest state
+	againOut, again := feedClient(t)
+	wl.Subscribe(againOut, wsframe.JSON, "k1", nil)
+	f = readMarketFrame(t, again)
+	assert.Equal(t, watchlist.MarketSnapshot, f.Type)
+	assert.Equal(t, "Yes", f.Winner)
+	wl.Unsubscribe(againOut)
+}
+
+func TestWatchlist_PersistsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watchlists.json")
+
+	wl, _, _ := newMarketWatchlist(t, path)
+	_, err := wl.AddMarket(mockupstream.MarketID, "k1")
+	require.NoError(t, err)
+	_, err = wl.Add(whale, "whale", "k1")
+	require.NoError(t, err)
+
+	restored, _, _ := newMarketWatchlist(t, path)
+	markets := restored.Markets("k1")
+	require.Len(t, markets, 1)
+	assert.Equal(t, mockupstream.MarketID, markets[0].MarketID)
+	wallets := restored.Wallets("k1")
+	require.Len(t, wallets, 1)
+	assert.Equal(t, "whale", wallets[0].Label)
+	assert.Empty(t, restored.Markets("k2"))
+}
@@ -0,0 +1,284 @@
+package unit
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/api/handlers"
+	"github.com/polygo/internal/api/middleware"
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/webhooks"
+	"github.com/polygo/pkg/polygoclient"
+)
+
+// received is a webhook delivery as an endpoint saw it
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+func receive(ch chan received) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ch <- received{header: r.Header.Clone(), body: body}
+	}
+}
+
+func newTestDispatcher(t *testing.T, mutate func(*config.WebhooksConfig)) *webhooks.Dispatcher {
+	cfg := config.DefaultConfig().Webhooks
+	cfg.RetryBackoff = 10 * time.Millisecond
+	if mutate != nil {
+		mutate(&cfg)
+	}
+	d, err := webhooks.NewDispatcher(&cfg)
+	require.NoError(t, err)
+	d.Start()
+	t.Cleanup(d.Stop)
+	return d
+}
+
+func awaitDelivery(t *testing.T, ch chan received) received {
+	select {
+	case r := <-ch:
+		return r
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not delivered")
+		return received{}
+	}
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	body := []byte(`{"id":"evt_1"}`)
+	now := time.Now().Unix()
+	header := http.Header{}
+	header.Set(polygoclient.HeaderWebhookTimestamp, strconv.FormatInt(now, 10))
+	header.Set(polygoclient.HeaderWebhookSignature, polygoclient.SignWebhook("secret-secret-16", now, body))
+
+	assert.NoError(t, polygoclient.VerifyWebhook("secret-secret-16", header, body, 0))
+	assert.ErrorIs(t, polygoclient.VerifyWebhook("other-secret-123", header, body, 0), polygoclient.ErrWebhookSignature)
+	assert.ErrorIs(t, polygoclient.VerifyWebhook("secret-secret-16", header, []byte(`{"id":"evt_2"}`), 0), polygoclient.ErrWebhookSignature)
+
+	// Any of several signatures will do
+	header.Set(polygoclient.HeaderWebhookSignature, "v1=00,"+polygoclient.SignWebhook("secret-secret-16", now, body))
+	assert.NoError(t, polygoclient.VerifyWebhook("secret-secret-16", header, body, 0))
+
+	old := now - 600
+	header.Set(polygoclient.HeaderWebhookTimestamp, strconv.FormatInt(old, 10))
+	header.Set(polygoclient.HeaderWebhookSignature, polygoclient.SignWebhook("secret-secret-16", old, body))
+	assert.ErrorIs(t, polygoclient.VerifyWebhook("secret-secret-16", header, body, 0), polygoclient.ErrWebhookTimestamp)
+	assert.NoError(t, polygoclient.VerifyWebhook("secret-secret-16", header, body, time.Hour))
+
+	header.Del(polygoclient.HeaderWebhookTimestamp)
+	assert.ErrorIs(t, polygoclient.VerifyWebhook("secret-secret-16", header, body, 0), polygoclient.ErrWebhookTimestamp)
+}
+
+func TestWebhooks_DeliveriesAreSigned(t *testing.T) {
+	ch := make(chan received, 4)
+	srv := httptest.NewServer(receive(ch))
+	defer srv.Close()
+
+	d := newTestDispatcher(t, nil)
+	sub := d.Subscribe(srv.URL, nil, nil, "", "")
+	require.True(t, strings.HasPrefix(sub.Secret, "whsec_"))
+
+	d.Publish("order.created", "", "", map[string]string{"id": "1"})
+	got := awaitDelivery(t, ch)
+	assert.NoError(t, polygoclient.VerifyWebhook(sub.Secret, got.header, got.body, 0))
+
+	// After a rotation both secrets verify during the grace period
+	oldSecret := sub.Secret
+	rotated, ok := d.RotateSecret(sub.ID, "a-brand-new-secret")
+	require.True(t, ok)
+	assert.Equal(t, "a-brand-new-secret", rotated.Secret)
+
+	d.Publish("order.created", "", "", map[string]string{"id": "2"})
+	got = awaitDelivery(t, ch)
+	assert.NoError(t, polygoclient.VerifyWebhook("a-brand-new-secret", got.header, got.body, 0))
+	assert.NoError(t, polygoclient.VerifyWebhook(oldSecret, got.header, got.body, 0))
+
+	_, ok = d.RotateSecret("whk_missing", "")
+	assert.False(t, ok)
+}
+
+func TestWebhooks_DeliveriesTracedByRequestID(t *testing.T) {
+	ch := make(chan received, 4)
+	srv := httptest.NewServer(receive(ch))
+	defer srv.Close()
+
+	d := newTestDispatcher(t, nil)
+	sub := d.Subscribe(srv.URL, []string{"order.created"}, nil, "key", "")
+	authCfg := config.DefaultConfig().Auth
+	handler := handlers.NewWebhooksHandler(d, nil, nil, nil)
+
+	app := fiber.New()
+	app.Use(middleware.RequestID())
+	app.Post("/orders", func(c *fiber.Ctx) error {
+		event := d.Publish("order.created", middleware.GetRequestID(c), "key", map[string]string{"id": "1"})
+		return c.SendString(event.ID)
+	})
+	app.Get("/deliveries/:id", middleware.Auth(&authCfg), handler.GetDelivery)
+
+	req := httptest.NewRequest("POST", "/orders", nil)
+	req.Header.Set(middleware.RequestIDHeader, "trace-123")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	eventID, _ := io.ReadAll(resp.Body)
+
+	got := awaitDelivery(t, ch)
+	assert.Equal(t, "trace-123", got.header.Get("X-Request-ID"))
+	assert.Equal(t, string(eventID), got.header.Get("X-PolyGo-Event-ID"))
+	assert.Contains(t, string(got.body), `"request_id":"trace-123"`)
+	deliveryID := got.header.Get("X-PolyGo-Delivery-ID")
+	require.NotEmpty(t, deliveryID)
+
+	trace := func(key, id string) (int, webhooks.Delivery) {
+		req := httptest.NewRequest("GET", "/deliveries/"+id, nil)
+		req.Header[authCfg.APIKeyHeader] = []string{key}
+		req.Header[authCfg.TimestampHeader] = []string{"1700000000"}
+		req.Header[authCfg.SignatureHeader] = []string{"sig"}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		var result struct {
+			Data webhooks.Delivery `json:"data"`
+		}
+		sonic.ConfigDefault.NewDecoder(resp.Body).Decode(&result)
+		return resp.StatusCode, result.Data
+	}
+	var delivery webhooks.Delivery
+	require.Eventually(t, func() bool {
+		status, traced := trace("key", deliveryID)
+		delivery = traced
+		return status == 200 && traced.Status == webhooks.StatusDelivered
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, sub.ID, delivery.SubscriptionID)
+	assert.Equal(t, string(eventID), delivery.EventID)
+	assert.Equal(t, "order.created", delivery.EventType)
+	assert.Equal(t, "trace-123", delivery.RequestID)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, 200, delivery.LastStatusCode)
+
+	// Without an ID of their own, deliveries carry the generated one
+	resp, err = app.Test(httptest.NewRequest("POST", "/orders", nil), -1)
+	require.NoError(t, err)
+	generated := resp.Header.Get(middleware.RequestIDHeader)
+	require.NotEmpty(t, generated)
+	assert.Equal(t, generated, awaitDelivery(t, ch).header.Get("X-Request-ID"))
+
+	// Other callers and unknown IDs find nothing
+	status, _ := trace("other-key", deliveryID)
+	assert.Equal(t, 404, status)
+	status, _ = trace("key", "dlv_missing")
+	assert.Equal(t, 404, status)
+}
+
+// writePEM writes a PEM block to a file in dir and returns its path
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	return path
+}
+
+// issue creates a certificate signed by parent (self-signed when nil)
+func issue(t *testing.T, tmpl *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key, der
+}
+
+func TestWebhooks_MTLSDelivery(t *testing.T) {
+	dir := t.TempDir()
+	notAfter := time.Now().Add(time.Hour)
+
+	ca, caKey, caDER := issue(t, &x509.Certificate{
+		SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "test CA"}, NotBefore: time.Now().Add(-time.Minute), NotAfter: notAfter,
+		IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign,
+	}, nil, nil)
+	_, serverKey, serverDER := issue(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2), Subject: pkix.Name{CommonName: "receiver"}, NotBefore: time.Now().Add(-time.Minute), NotAfter: notAfter,
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}, KeyUsage: x509.KeyUsageDigitalSignature,
+	}, ca, caKey)
+	_, clientKey, clientDER := issue(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3), Subject: pkix.Name{CommonName: "polygo"}, NotBefore: time.Now().Add(-time.Minute), NotAfter: notAfter,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, KeyUsage: x509.KeyUsageDigitalSignature,
+	}, ca, caKey)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	serverKeyDER, err := x509.MarshalECPrivateKey(serverKey)
+	require.NoError(t, err)
+	serverCert, err := tls.X509KeyPair(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: serverDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: serverKeyDER}))
+	require.NoError(t, err)
+
+	ch := make(chan received, 4)
+	srv := httptest.NewUnstartedServer(receive(ch))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{serverCert}, ClientCAs: pool, ClientAuth: tls.RequireAndVerifyClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+
+	clientKeyDER, err := x509.MarshalECPrivateKey(clientKey)
+	require.NoError(t, err)
+	caFile := writePEM(t, dir, "ca.pem", "CERTIFICATE", caDER)
+	certFile := writePEM(t, dir, "client.pem", "CERTIFICATE", clientDER)
+	keyFile := writePEM(t, dir, "client-key.pem", "EC PRIVATE KEY", clientKeyDER)
+
+	// Without a client certificate the receiver refuses the connection
+	plain := newTestDispatcher(t, func(c *config.WebhooksConfig) { c.CAFile = caFile; c.MaxAttempts = 1 })
+	plain.Subscribe(srv.URL, nil, nil, "", "")
+	event := plain.Publish("order.created", "", "", nil)
+	require.Eventually(t, func() bool {
+		deliveries := plain.DeliveriesForEvent(event.ID)
+		return len(deliveries) == 1 && deliveries[0].Status == webhooks.StatusFailed
+	}, 5*time.Second, 10*time.Millisecond)
+
+	d := newTestDispatcher(t, func(c *config.WebhooksConfig) {
+		c.CAFile, c.ClientCert, c.ClientKey = caFile, certFile, keyFile
+	})
+	sub := d.Subscribe(srv.URL, nil, nil, "", "")
+	d.Publish("order.created", "", "", nil)
+	got := awaitDelivery(t, ch)
+	assert.NoError(t, polygoclient.VerifyWebhook(sub.Secret, got.header, got.body, 0))
+}
+
+func TestWebhooks_BadTLSConfig(t *testing.T) {
+	cfg := config.DefaultConfig().Webhooks
+	cfg.ClientCert = "/nonexistent/cert.pem"
+	cfg.ClientKey = "/nonexistent/key.pem"
+	_, err := webhooks.NewDispatcher(&cfg)
+	assert.Error(t, err)
+
+	cfg = config.DefaultConfig().Webhooks
+	cfg.CAFile = filepath.Join(t.TempDir(), "empty.pem")
+	require.NoError(t, os.WriteFile(cfg.CAFile, []byte("not a certificate"), 0o600))
+	_, err = webhooks.NewDispatcher(&cfg)
+	assert.Error(t, err)
+}
@@ -0,0 +1,306 @@
+package unit
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/wsbuffer"
+)
+
+// slowConn blocks every write until the test opens its gate
+type slowConn struct {
+	gate chan struct{}
+
+	mu      sync.Mutex
+	written []string
+	control []int // close codes sent
+	closed  bool
+}
+
+func newSlowConn() *slowConn {
+	return &slowConn{gate: make(chan struct{})}
+}
+
+func (c *slowConn) WriteMessage(messageType int, data []byte) error {
+	<-c.gate
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return websocket.ErrCloseSent
+	}
+	c.written = append(c.written, string(data))
+	return nil
+}
+
+func (c *slowConn) WriteControl(messageType int, data []byte, deadline time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(data) >= 2 {
+		c.control = append(c.control, int(data[0])<<8|int(data[1]))
+	}
+	return nil
+}
+
+func (c *slowConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		c.closed = true
+		close(c.gate)
+	}
+	return nil
+}
+
+func (c *slowConn) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+// frameConn hands the frames written to it to the test
+type frameConn struct {
+	frames chan []byte
+}
+
+func (c *frameConn) WriteMessage(messageType int, data []byte) error {
+	c.frames <- append([]byte(nil), data...)
+	return nil
+}
+
+func (c *frameConn) WriteControl(messageType int, data []byte, deadline time.Time) error {
+	return nil
+}
+
+func (c *frameConn) Close() error {
+	return nil
+}
+
+// feedClient returns a client of a fresh pool, as a WebSocket handler
+// subscribes to a feed, and the frames written to it
+func feedClient(t *testing.T) (*wsbuffer.Client, chan []byte) {
+	conn := &frameConn{frames: make(chan []byte, 64)}
+	out := wsbuffer.New(0, 0).Add(conn)
+	t.Cleanup(out.Close)
+	return out, conn.frames
+}
+
+// nextFrame returns the next frame written to a feed client
+func nextFrame(t *testing.T, frames chan []byte) []byte {
+	t.Helper()
+	select {
+	case data := <-frames:
+		return data
+	case <-time.After(time.Second):
+		t.Fatal("no frame received")
+		return nil
+	}
+}
+
+// assertNoFrame asserts nothing more is written to a feed client
+func assertNoFrame(t *testing.T, frames chan []byte, msgAndArgs ...interface{}) {
+	t.Helper()
+	select {
+	case data := <-frames:
+		assert.Fail(t, "unexpected frame "+string(data), msgAndArgs...)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestWSBuffer_WritesInOrderAndReleases(t *testing.T) {
+	pool := wsbuffer.New(0, 0)
+	conn := newSlowConn()
+	client := pool.Add(conn)
+	defer client.Close()
+
+	for _, msg := range []string{"one", "two", "three"} {
+		require.True(t, client.Send(websocket.TextMessage, []byte(msg)))
+	}
+	assert.Equal(t, 11, pool.Stats().BufferedBytes)
+
+	close(conn.gate)
+	require.Eventually(t, func() bool {
+		return pool.Stats().BufferedBytes == 0
+	}, time.Second, 5*time.Millisecond)
+
+	conn.mu.Lock()
+	assert.Equal(t, []string{"one", "two", "three"}, conn.written)
+	conn.mu.Unlock()
+	assert.Equal(t, 1, pool.Stats().Clients)
+}
+
+// partsConn is a slowConn that can also write a message in parts
+type partsConn struct {
+	*slowConn
+	parts [][]string
+}
+
+type partsWriter struct {
+	conn  *partsConn
+	parts []string
+}
+
+func (w *partsWriter) Write(p []byte) (int, error) {
+	w.parts = append(w.parts, string(p))
+	return len(p), nil
+}
+
+func (w *partsWriter) Close() error {
+	w.conn.mu.Lock()
+	defer w.conn.mu.Unlock()
+	w.conn.parts = append(w.conn.parts, w.parts)
+	return nil
+}
+
+func (c *partsConn) NextWriter(messageType int) (io.WriteCloser, error) {
+	<-c.gate
+	return &partsWriter{conn: c}, nil
+}
+
+func TestWSBuffer_SendPartsWritesHeadThenSharedData(t *testing.T) {
+	pool := wsbuffer.New(0, 0)
+	plain := newSlowConn()
+	parts := &partsConn{slowConn: newSlowConn()}
+	a, b := pool.Add(plain), pool.Add(parts)
+	defer a.Close()
+	defer b.Close()
+
+	data := make([]byte, 0, 64)
+	data = append(data, `"m":1}`...)
+	require.True(t, a.SendParts(websocket.TextMessage, []byte(`{"n":1,`), data[:6]))
+	require.True(t, b.SendParts(websocket.TextMessage, []byte(`{"n":2,`), data[:6]))
+	assert.Equal(t, 26, pool.Stats().BufferedBytes)
+
+	close(plain.gate)
+	close(parts.gate)
+	require.Eventually(t, func() bool {
+		return pool.Stats().BufferedBytes == 0
+	}, time.Second, 5*time.Millisecond)
+
+	plain.mu.Lock()
+	assert.Equal(t, []string{`{"n":1,"m":1}`}, plain.written)
+	plain.mu.Unlock()
+	parts.mu.Lock()
+	assert.Equal(t, [][]string{{`{"n":2,`, `"m":1}`}}, parts.parts, "written without joining")
+	parts.mu.Unlock()
+	assert.Equal(t, `"m":1}`, string(data[:6]), "shared data is left as is")
+}
+
+func TestWSBuffer_EvictsClientOverItsLimit(t *testing.T) {
+	pool := wsbuffer.New(0, 10)
+	conn := newSlowConn()
+	client := pool.Add(conn)
+
+	// A frame larger than the limit still goes to a client with nothing queued
+	require.True(t, client.Send(websocket.TextMessage, []byte("0123456789abc")))
+	assert.False(t, client.Send(websocket.TextMessage, []byte("x")))
+
+	require.Eventually(t, conn.isClosed, time.Second, 5*time.Millisecond)
+	conn.mu.Lock()
+	assert.Equal(t, []int{websocket.CloseTryAgainLater}, conn.control)
+	conn.mu.Unlock()
+
+	stats := pool.Stats()
+	assert.Equal(t, 0, stats.Clients)
+	assert.Equal(t, 0, stats.BufferedBytes)
+	assert.Equal(t, uint64(1), stats.EvictedClient)
+	assert.False(t, client.Send(websocket.TextMessage, []byte("x")))
+}
+
+func TestWSBuffer_EvictsSlowestAtTotalLimit(t *testing.T) {
+	pool := wsbuffer.New(20, 0)
+	slow, other := newSlowConn(), newSlowConn()
+	slowClient := pool.Add(slow)
+	otherClient := pool.Add(other)
+	defer other.Close()
+	defer otherClient.Close()
+
+	require.True(t, slowClient.Send(websocket.TextMessage, []byte("0123456789")))
+	require.True(t, slowClient.Send(websocket.TextMessage, []byte("01234")))
+	require.True(t, otherClient.Send(websocket.TextMessage, []byte("0123")))
+
+	// 19 bytes queued; the next frame for the other client makes room by
+	// disconnecting the client with the most queued
+	require.True(t, otherClient.Send(websocket.TextMessage, []byte("abc")))
+	require.Eventually(t, slow.isClosed, time.Second, 5*time.Millisecond)
+	assert.False(t, other.isClosed())
+
+	stats := pool.Stats()
+	assert.Equal(t, 1, stats.Clients)
+	assert.Equal(t, 7, stats.BufferedBytes)
+	assert.Equal(t, 7, stats.MaxClientQueue)
+	assert.Equal(t, uint64(1), stats.EvictedTotal)
+	assert.False(t, slowClient.Send(websocket.TextMessage, []byte("x")))
+}
+
+func TestWSBuffer_ClosedClientStopsQueueing(t *testing.T) {
+	pool := wsbuffer.New(0, 0)
+	conn := newSlowConn()
+	client := pool.Add(conn)
+
+	require.True(t, client.Send(websocket.TextMessage, []byte("queued")))
+	client.Close()
+
+	assert.False(t, client.Send(websocket.TextMessage, []byte("late")))
+	assert.Equal(t, wsbuffer.Stats{}, pool.Stats())
+	// Closing the client leaves the connection to its handler
+	assert.False(t, conn.isClosed())
+	conn.Close()
+}
+
+func TestWSBuffer_WritePrometheus(t *testing.T) {
+	pool := wsbuffer.New(1000, 100)
+	conn := newSlowConn()
+	client := pool.Add(conn)
+	defer conn.Close()
+	defer client.Close()
+	require.True(t, client.Send(websocket.TextMessage, []byte("hello")))
+
+	var buf bytes.Buffer
+	pool.WritePrometheus(&buf)
+	out := buf.String()
+
+	for _, line := range []string{
+		"# TYPE polygo_ws_buffered_bytes gauge",
+		"polygo_ws_clients 1",
+		"polygo_ws_buffered_bytes 5",
+		"polygo_ws_client_buffered_bytes_max 5",
+		"polygo_ws_buffer_limit_bytes 1000",
+		"polygo_ws_client_buffer_limit_bytes 100",
+		"# TYPE polygo_ws_evictions_total counter",
+		`polygo_ws_evictions_total{reason="client_limit"} 0`,
+		`polygo_ws_evictions_total{reason="total_limit"} 0`,
+	} {
+		assert.True(t, strings.Contains(out, line+"\n"), "missing %q in:\n%s", line, out)
+	}
+}
+
+func TestWSBuffer_CountsSentAndDroppedFrames(t *testing.T) {
+	pool := wsbuffer.New(0, 10)
+	conn := newSlowConn()
+	client := pool.Add(conn)
+
+	require.True(t, client.Send(websocket.TextMessage, []byte("hello")))
+	close(conn.gate)
+	require.Eventually(t, func() bool {
+		return client.Stats().SentFrames == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, wsbuffer.ClientStats{SentFrames: 1, SentBytes: 5}, client.Stats())
+	client.Close()
+
+	// Frames still queued when a client is evicted are dropped
+	slow := newSlowConn()
+	slowClient := pool.Add(slow)
+	require.True(t, slowClient.Send(websocket.TextMessage, []byte("0123456")))
+	require.True(t, slowClient.Send(websocket.TextMessage, []byte("789")))
+	assert.Equal(t, 10, slowClient.Stats().QueuedBytes)
+	assert.False(t, slowClient.Send(websocket.TextMessage, []byte("x")))
+	assert.Equal(t, uint64(2), pool.Stats().DroppedFrames)
+}
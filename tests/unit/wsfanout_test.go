@@ -0,0 +1,42 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/polygo/internal/wsfanout"
+	"github.com/polygo/internal/wsframe"
+)
+
+func TestWSFanout_PublishesToMatchingSubscribersInTheirEncoding(t *testing.T) {
+	f := wsfanout.New[string]()
+	jsonOut, jsonFrames := feedClient(t)
+	packOut, packFrames := feedClient(t)
+	otherOut, otherFrames := feedClient(t)
+	f.Add(jsonOut, wsframe.JSON, "k1")
+	f.Add(packOut, wsframe.Msgpack, "k1")
+	f.Add(otherOut, wsframe.JSON, "k2")
+
+	f.Publish([]byte(`{"type":"x"}`), func(owner string) bool { return owner == "k1" })
+	assert.JSONEq(t, `{"type":"x"}`, string(nextFrame(t, jsonFrames)))
+	var decoded map[string]interface{}
+	assert.NoError(t, msgpack.Unmarshal(nextFrame(t, packFrames), &decoded))
+	assert.Equal(t, "x", decoded["type"])
+	assertNoFrame(t, otherFrames)
+
+	f.Remove(jsonOut)
+	f.Publish([]byte(`{"type":"y"}`), nil)
+	assertNoFrame(t, jsonFrames, "removed subscribers get nothing")
+	assert.Equal(t, 2, f.Len())
+
+	f.Close()
+	assert.Zero(t, f.Len())
+	select {
+	case <-otherOut.Done():
+	case <-time.After(time.Second):
+		t.Fatal("subscriber not closed")
+	}
+}
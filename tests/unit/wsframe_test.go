@@ -0,0 +1,103 @@
+package unit
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gofiber/websocket/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/polygo/internal/wsframe"
+)
+
+func TestParseEncoding(t *testing.T) {
+	enc, ok := wsframe.ParseEncoding("")
+	assert.True(t, ok)
+	assert.Equal(t, wsframe.JSON, enc)
+
+	enc, ok = wsframe.ParseEncoding("msgpack")
+	assert.True(t, ok)
+	assert.Equal(t, wsframe.Msgpack, enc)
+
+	_, ok = wsframe.ParseEncoding("protobuf")
+	assert.False(t, ok)
+}
+
+func TestMessage_FramesPerEncoding(t *testing.T) {
+	msg := wsframe.NewMessage([]byte(`{"market":"0xabc","price":"0.5","size":12}`))
+
+	messageType, data, err := msg.Frame(wsframe.JSON)
+	require.NoError(t, err)
+	assert.Equal(t, websocket.TextMessage, messageType)
+	assert.JSONEq(t, `{"market":"0xabc","price":"0.5","size":12}`, string(data))
+
+	messageType, packed, err := msg.Frame(wsframe.Msgpack)
+	require.NoError(t, err)
+	assert.Equal(t, websocket.BinaryMessage, messageType)
+
+	var decoded map[string]interface{}
+	require.NoError(t, msgpack.Unmarshal(packed, &decoded))
+	assert.Equal(t, "0xabc", decoded["market"])
+	assert.Equal(t, "0.5", decoded["price"])
+	assert.EqualValues(t, 12, decoded["size"])
+
+	// Encoded once: later clients get the same buffer
+	_, again, _ := msg.Frame(wsframe.Msgpack)
+	assert.Same(t, &packed[0], &again[0])
+}
+
+func TestStamp_JSON(t *testing.T) {
+	stamp := func(data string) (string, bool) {
+		head, body, ok := wsframe.Stamp(websocket.TextMessage, []byte(data), "0xabc", "sub_seq", 3)
+		return string(head) + string(body), ok
+	}
+
+	got, ok := stamp(` {"market":"0xabc"}`)
+	assert.True(t, ok)
+	assert.Equal(t, `{"sub":"0xabc","sub_seq":3,"market":"0xabc"}`, got)
+
+	got, ok = stamp(`{ }`)
+	assert.True(t, ok)
+	assert.Equal(t, `{"sub":"0xabc","sub_seq":3}`, got)
+
+	_, ok = stamp(`[1]`)
+	assert.False(t, ok, "arrays are left alone")
+}
+
+func TestStamp_SharesBody(t *testing.T) {
+	data := []byte(`{"market":"0xabc"}`)
+	_, body, ok := wsframe.Stamp(websocket.TextMessage, data, "0xabc", "sub_seq", 1)
+	require.True(t, ok)
+	assert.Equal(t, &data[1], &body[0], "the frame is not copied")
+}
+
+func TestStamp_Msgpack(t *testing.T) {
+	stamped := func(fields int) map[string]interface{} {
+		msg := make(map[string]interface{}, fields)
+		for i := 0; i < fields; i++ {
+			msg[fmt.Sprintf("f%d", i)] = i
+		}
+		packed, err := msgpack.Marshal(msg)
+		require.NoError(t, err)
+
+		head, body, ok := wsframe.Stamp(websocket.BinaryMessage, packed, "0xabc", "sub_seq", 300)
+		require.True(t, ok)
+		var decoded map[string]interface{}
+		require.NoError(t, msgpack.Unmarshal(append(head, body...), &decoded))
+		require.Len(t, decoded, fields+2)
+		assert.Equal(t, "0xabc", decoded["sub"])
+		assert.EqualValues(t, 300, decoded["sub_seq"])
+		assert.EqualValues(t, fields-1, decoded[fmt.Sprintf("f%d", fields-1)])
+		return decoded
+	}
+
+	// Within a fixmap, growing out of one, and already a map 16
+	stamped(2)
+	stamped(15)
+	stamped(40)
+
+	_, _, ok := wsframe.Stamp(websocket.BinaryMessage, []byte{0x91, 0x01}, "0xabc", "sub_seq", 1)
+	assert.False(t, ok, "arrays are left alone")
+}
@@ -0,0 +1,82 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/wsproto"
+)
+
+func TestParseVersion(t *testing.T) {
+	for s, want := range map[string]wsproto.Version{"": wsproto.V1, "1": wsproto.V1, "2": wsproto.V2} {
+		v, ok := wsproto.ParseVersion(s)
+		assert.True(t, ok, s)
+		assert.Equal(t, want, v, s)
+	}
+	for _, s := range []string{"0", "3", "v2", "two"} {
+		_, ok := wsproto.ParseVersion(s)
+		assert.False(t, ok, s)
+	}
+}
+
+func TestWrap_SplitsPriceChangeByToken(t *testing.T) {
+	wrapped := wsproto.Wrap([]byte(`{"stream_seq":7,"event_type":"price_change","market":"m","timestamp":"1757908892351","price_changes":[` +
+		`{"asset_id":"a","price":"0.4"},{"asset_id":"b","price":"0.6"},{"asset_id":"a","price":"0.41"}]}`))
+	require.Len(t, wrapped, 2)
+
+	type entry struct {
+		AssetID string `json:"asset_id"`
+		Price   string `json:"price"`
+	}
+	var prices [][]string
+	for i, w := range wrapped {
+		var env struct {
+			wsproto.Envelope
+			Payload struct {
+				StreamSeq    *uint64 `json:"stream_seq"`
+				Market       string  `json:"market"`
+				PriceChanges []entry `json:"price_changes"`
+			} `json:"payload"`
+		}
+		require.NoError(t, sonic.Unmarshal(w.Data, &env))
+		assert.Equal(t, []string{"a", "b"}[i], w.Token)
+		assert.Equal(t, w.Token, env.Token)
+		assert.Equal(t, "m", w.Market)
+		assert.Equal(t, "price_change", env.Type)
+		assert.Equal(t, uint64(7), env.StreamSeq)
+		assert.Equal(t, int64(1757908892351), env.Timestamp)
+		assert.Nil(t, env.Payload.StreamSeq)
+		assert.Equal(t, "m", env.Payload.Market)
+
+		var p []string
+		for _, c := range env.Payload.PriceChanges {
+			assert.Equal(t, w.Token, c.AssetID)
+			p = append(p, c.Price)
+		}
+		prices = append(prices, p)
+	}
+	assert.Equal(t, [][]string{{"0.4", "0.41"}, {"0.6"}}, prices)
+}
+
+func TestWrap_SingleMessages(t *testing.T) {
+	wrapped := wsproto.Wrap([]byte(`{"event_type":"last_trade_price","asset_id":"a","market":"m","price":"0.5"}`))
+	require.Len(t, wrapped, 1)
+	var env wsproto.Envelope
+	require.NoError(t, sonic.Unmarshal(wrapped[0].Data, &env))
+	assert.Equal(t, "last_trade_price", env.Type)
+	assert.Equal(t, "a", env.Token)
+	assert.JSONEq(t, `{"event_type":"last_trade_price","asset_id":"a","market":"m","price":"0.5"}`, string(env.Payload))
+
+	// PolyGo's own messages are typed by type and name no token
+	wrapped = wsproto.Wrap([]byte(`{"type":"gap","reason":"upstream_reconnect","markets":["m"]}`))
+	require.Len(t, wrapped, 1)
+	require.NoError(t, sonic.Unmarshal(wrapped[0].Data, &env))
+	assert.Equal(t, "gap", env.Type)
+	assert.Empty(t, wrapped[0].Token)
+
+	assert.Empty(t, wsproto.Wrap([]byte(`not json`)))
+	assert.Empty(t, wsproto.Wrap([]byte(`[1,2]`)))
+}
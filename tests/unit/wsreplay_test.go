@@ -0,0 +1,74 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/wsreplay"
+)
+
+func replayedData(entries []wsreplay.Entry) []string {
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, string(e.Data))
+	}
+	return out
+}
+
+func TestWSReplay_StampsAndReplaysByKey(t *testing.T) {
+	b := wsreplay.New(time.Minute)
+
+	assert.Equal(t, `{"stream_seq":1,"asset_id":"a1"}`, string(b.Add([]string{"a1", "m1"}, []byte(`{"asset_id":"a1"}`), false)))
+	b.Add([]string{"b1", "m2"}, []byte(`{"asset_id":"b1"}`), false)
+	b.Add([]string{"a1", "a2", "m1"}, []byte(` { "asset_id":"a2"} `), false)
+	assert.Equal(t, `{"stream_seq":4}`, string(b.Add([]string{"a2"}, []byte(`{}`), true)))
+
+	entries, complete := b.Since(1, map[string]bool{"a1": true})
+	assert.True(t, complete)
+	assert.Equal(t, []string{`{"stream_seq":3,"asset_id":"a2"}`}, replayedData(entries))
+
+	// A message under several subscribed keys is replayed once
+	entries, _ = b.Since(0, map[string]bool{"m1": true, "a2": true})
+	assert.Equal(t, []uint64{1, 3, 4}, []uint64{entries[0].Seq, entries[1].Seq, entries[2].Seq})
+	assert.True(t, entries[2].Book)
+
+	entries, _ = b.Since(0, map[string]bool{"*": true})
+	assert.Len(t, entries, 4)
+
+	entries, complete = b.Since(4, map[string]bool{"*": true})
+	assert.Empty(t, entries)
+	assert.True(t, complete)
+}
+
+func TestWSReplay_ReportsMissingMessages(t *testing.T) {
+	b := wsreplay.New(50 * time.Millisecond)
+	b.Add([]string{"a1"}, []byte(`{"n":1}`), false)
+	b.Add([]string{"a1"}, []byte(`{"n":2}`), false)
+
+	time.Sleep(60 * time.Millisecond)
+	b.Add([]string{"a1"}, []byte(`{"n":3}`), false)
+
+	entries, complete := b.Since(0, map[string]bool{"a1": true})
+	require.Len(t, entries, 1)
+	assert.Equal(t, uint64(3), entries[0].Seq)
+	assert.False(t, complete, "messages 1 and 2 were evicted")
+
+	_, complete = b.Since(2, map[string]bool{"a1": true})
+	assert.True(t, complete)
+
+	// A seq from before a restart is ahead of the buffer
+	_, complete = b.Since(100, map[string]bool{"a1": true})
+	assert.False(t, complete)
+}
+
+func TestWSReplay_Disabled(t *testing.T) {
+	b := wsreplay.New(0)
+	assert.Equal(t, `{"asset_id":"a1"}`, string(b.Add([]string{"a1"}, []byte(`{"asset_id":"a1"}`), false)))
+
+	entries, complete := b.Since(0, map[string]bool{"a1": true})
+	assert.Empty(t, entries)
+	assert.False(t, complete)
+}
@@ -0,0 +1,166 @@
+package unit
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/polygo/internal/config"
+	"github.com/polygo/internal/polymarket"
+)
+
+// fakeUpstream accepts WebSocket connections and keeps them so a test can
+// drop one
+type fakeUpstream struct {
+	mu    sync.Mutex
+	conns []*websocket.Conn
+	deaf  bool // never read, so pings go unanswered
+}
+
+func (f *fakeUpstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	f.mu.Lock()
+	f.conns = append(f.conns, conn)
+	f.mu.Unlock()
+
+	if f.deaf {
+		<-r.Context().Done()
+		return
+	}
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeUpstream) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.conns)
+}
+
+func shardMarkets(m *polymarket.WSManager) (connected, markets []int) {
+	for _, s := range m.Shards() {
+		if s.Connected {
+			connected = append(connected, s.Index)
+		}
+		markets = append(markets, s.Markets)
+	}
+	return connected, markets
+}
+
+func TestWSManager_ShardsAndRebalances(t *testing.T) {
+	upstream := &fakeUpstream{}
+	srv := httptest.NewServer(upstream)
+	defer srv.Close()
+
+	m := polymarket.NewWSManager(&config.PolymarketConfig{
+		WsClobURL: "ws" + strings.TrimPrefix(srv.URL, "http"),
+		WsShards:  2,
+	})
+	defer m.Close()
+
+	require.NoError(t, m.Connect())
+	require.Eventually(t, func() bool { return upstream.count() == 2 }, time.Second, 10*time.Millisecond)
+
+	for i := 0; i < 20; i++ {
+		_, err := m.SubscribeMarket(fmt.Sprintf("market-%d", i))
+		require.NoError(t, err)
+	}
+	_, markets := shardMarkets(m)
+	assert.Equal(t, 20, markets[0]+markets[1])
+	assert.NotZero(t, markets[0])
+	assert.NotZero(t, markets[1])
+	before := markets
+
+	// Drop one upstream connection: its markets move to the other shard
+	upstream.mu.Lock()
+	upstream.conns[0].Close()
+	upstream.mu.Unlock()
+
+	require.Eventually(t, func() bool {
+		connected, markets := shardMarkets(m)
+		return len(connected) == 1 && markets[connected[0]] == 20
+	}, time.Second, 10*time.Millisecond)
+	assert.True(t, m.IsConnected())
+
+	// After reconnecting, the same markets return to the recovered shard
+	require.Eventually(t, func() bool {
+		connected, markets := shardMarkets(m)
+		return len(connected) == 2 && markets[0] == before[0] && markets[1] == before[1]
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestWSManager_ReconnectsOnMissingPong(t *testing.T) {
+	upstream := &fakeUpstream{deaf: true}
+	srv := httptest.NewServer(upstream)
+	defer srv.Close()
+
+	m := polymarket.NewWSManager(&config.PolymarketConfig{
+		WsClobURL:      "ws" + strings.TrimPrefix(srv.URL, "http"),
+		WsShards:       1,
+		WsPingInterval: 100 * time.Millisecond,
+		WsPongTimeout:  100 * time.Millisecond,
+	})
+	defer m.Close()
+
+	require.NoError(t, m.Connect())
+	require.Eventually(t, func() bool { return upstream.count() >= 2 }, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestWSManager_ReconnectsStaleFeed(t *testing.T) {
+	upstream := &fakeUpstream{}
+	srv := httptest.NewServer(upstream)
+	defer srv.Close()
+
+	m := polymarket.NewWSManager(&config.PolymarketConfig{
+		WsClobURL:      "ws" + strings.TrimPrefix(srv.URL, "http"),
+		WsShards:       1,
+		WsStaleTimeout: 300 * time.Millisecond,
+	})
+	defer m.Close()
+
+	require.NoError(t, m.Connect())
+
+	// An idle connection without subscriptions is not stale
+	time.Sleep(1500 * time.Millisecond)
+	assert.Equal(t, 1, upstream.count())
+
+	_, err := m.SubscribeMarket("quiet-market")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return upstream.count() >= 2 }, 5*time.Second, 50*time.Millisecond)
+	assert.Empty(t, m.LastMessages())
+}
+
+func TestWSManager_UnsubscribeAfterClose(t *testing.T) {
+	upstream := &fakeUpstream{}
+	srv := httptest.NewServer(upstream)
+	defer srv.Close()
+
+	m := polymarket.NewWSManager(&config.PolymarketConfig{
+		WsClobURL: "ws" + strings.TrimPrefix(srv.URL, "http"),
+		WsShards:  1,
+	})
+	require.NoError(t, m.Connect())
+
+	ch, err := m.SubscribeMarket("market-1")
+	require.NoError(t, err)
+	m.Close()
+
+	_, open := <-ch
+	assert.False(t, open, "Close ends subscriptions")
+	// Handlers unsubscribe as their clients leave, which may be after Close
+	assert.NotPanics(t, func() { m.UnsubscribeMarket("market-1", ch) })
+}